@@ -0,0 +1,74 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package activitycmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/trustbloc/orb/pkg/activitypub/activitytemplate"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+)
+
+const (
+	offerFlagName  = "offer"
+	offerFlagUsage = "The path of the file that contains the 'Offer' activity (JSON) to accept." +
+		" Alternatively, this can be set with the following environment variable: " + offerEnvKey
+	offerEnvKey = "ORB_CLI_OFFER"
+
+	proofFlagName  = "proof"
+	proofFlagUsage = "The path of the file that contains the proof (JSON) of the anchor event in the offer." +
+		" Alternatively, this can be set with the following environment variable: " + proofEnvKey
+	proofEnvKey = "ORB_CLI_PROOF"
+)
+
+func newAcceptCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "accept",
+		Short: "Generates an 'Accept' activity which replies to a witness 'Offer' with a proof.",
+		Long: "Generates an 'Accept' activity which replies to a witness 'Offer' with a proof. The activity" +
+			" is addressed to the actor of the offer and to the public collection.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return executeAccept(cmd)
+		},
+	}
+
+	addActivityFlags(cmd)
+
+	cmd.Flags().StringP(offerFlagName, "", "", offerFlagUsage)
+	cmd.Flags().StringP(proofFlagName, "", "", proofFlagUsage)
+	cmd.Flags().StringP(maxWitnessDelayFlagName, "", "", maxWitnessDelayFlagUsage)
+
+	return cmd
+}
+
+func executeAccept(cmd *cobra.Command) error {
+	offer := &vocab.ActivityType{}
+
+	err := readJSONFile(cmd, offerFlagName, offerEnvKey, offer)
+	if err != nil {
+		return err
+	}
+
+	proof := &vocab.ObjectType{}
+
+	err = readJSONFile(cmd, proofFlagName, proofEnvKey, proof)
+	if err != nil {
+		return err
+	}
+
+	maxWitnessDelay, err := getMaxWitnessDelay(cmd)
+	if err != nil {
+		return err
+	}
+
+	accept, err := activitytemplate.NewProofAccept(offer, proof, maxWitnessDelay)
+	if err != nil {
+		return err
+	}
+
+	return output(cmd, accept)
+}
@@ -0,0 +1,129 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package activitycmd
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/activitytemplate"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+)
+
+func TestAcceptCmd(t *testing.T) {
+	offer := activitytemplate.NewWitnessOffer(newAnchorEvent(t), []*url.URL{mustParseURL(t, service2)}, time.Minute)
+	offer.SetID(mustParseURL(t, service1+"/activities/1234"))
+	offer.SetActor(mustParseURL(t, service1))
+
+	offerFile := writeJSONFile(t, "offer.json", offer)
+	proofFile := writeJSONFile(t, "proof.json", vocab.NewObject(vocab.WithType(vocab.TypeVerifiableCredential)))
+
+	t.Run("test missing offer arg", func(t *testing.T) {
+		cmd := GetCmd()
+		cmd.SetArgs([]string{"accept"})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Equal(t,
+			"Neither offer (command line flag) nor ORB_CLI_OFFER (environment variable) have been set.",
+			err.Error())
+	})
+
+	t.Run("test missing proof arg", func(t *testing.T) {
+		cmd := GetCmd()
+
+		args := []string{"accept"}
+		args = append(args, offerArg(offerFile)...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Equal(t,
+			"Neither proof (command line flag) nor ORB_CLI_PROOF (environment variable) have been set.",
+			err.Error())
+	})
+
+	t.Run("test invalid proof file", func(t *testing.T) {
+		cmd := GetCmd()
+
+		args := []string{"accept"}
+		args = append(args, offerArg(offerFile)...)
+		args = append(args, proofArg(writeJSONFile(t, "invalid.json", "invalid"))...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal proof file")
+	})
+
+	t.Run("test invalid max witness delay arg", func(t *testing.T) {
+		cmd := GetCmd()
+
+		args := []string{"accept"}
+		args = append(args, offerArg(offerFile)...)
+		args = append(args, proofArg(proofFile)...)
+		args = append(args, maxWitnessDelayArg("xxx")...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for max-witness-delay")
+	})
+
+	t.Run("test offer without anchor event", func(t *testing.T) {
+		cmd := GetCmd()
+
+		args := []string{"accept"}
+		args = append(args, offerArg(writeJSONFile(t, "offer.json",
+			vocab.NewOfferActivity(vocab.NewObjectProperty(vocab.WithIRI(mustParseURL(t, service2)))),
+		))...)
+		args = append(args, proofArg(proofFile)...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), activitytemplate.ErrNoAnchorEvent.Error())
+	})
+
+	t.Run("test post to outbox", func(t *testing.T) {
+		outbox := newMockOutbox()
+		defer outbox.Close()
+
+		cmd := GetCmd()
+
+		args := []string{"accept"}
+		args = append(args, offerArg(offerFile)...)
+		args = append(args, proofArg(proofFile)...)
+		args = append(args, outboxURLArg(outbox.URL)...)
+		args = append(args, actorArg(service2)...)
+		cmd.SetArgs(args)
+
+		require.NoError(t, cmd.Execute())
+
+		accept := outbox.activity(t)
+		require.True(t, accept.Type().Is(vocab.TypeAccept))
+		require.Equal(t, service2, accept.Actor().String())
+		require.True(t, accept.To().Contains(mustParseURL(t, service1), vocab.PublicIRI))
+		require.Equal(t, offer.ID().String(), accept.Object().Activity().ID().String())
+
+		receipt := accept.Result().Object()
+		require.True(t, receipt.Type().Is(vocab.TypeAnchorReceipt))
+		require.Equal(t, anchorIndexURL, receipt.InReplyTo().String())
+		require.Equal(t, defaultMaxWitnessDelay, receipt.EndTime().Sub(*receipt.StartTime()))
+	})
+}
+
+func offerArg(value string) []string {
+	return []string{flag + offerFlagName, value}
+}
+
+func proofArg(value string) []string {
+	return []string{flag + proofFlagName, value}
+}
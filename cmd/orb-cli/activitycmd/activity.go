@@ -0,0 +1,188 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package activitycmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+
+	"github.com/trustbloc/orb/cmd/orb-cli/common"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+)
+
+const (
+	outboxURLFlagName  = "outbox-url"
+	outboxURLFlagUsage = "The URL of the outbox to which the activity is posted. If not set then the activity" +
+		" is written to stdout." +
+		" Alternatively, this can be set with the following environment variable: " + outboxURLEnvKey
+	outboxURLEnvKey = "ORB_CLI_OUTBOX_URL"
+
+	actorFlagName  = "actor"
+	actorFlagUsage = "Actor IRI. If not set then the actor is set by the outbox." +
+		" Alternatively, this can be set with the following environment variable: " + actorEnvKey
+	actorEnvKey = "ORB_CLI_ACTOR"
+
+	anchorEventFlagName  = "anchor-event"
+	anchorEventFlagUsage = "The path of the file that contains the anchor event (JSON)." +
+		" Alternatively, this can be set with the following environment variable: " + anchorEventEnvKey
+	anchorEventEnvKey = "ORB_CLI_ANCHOR_EVENT"
+
+	maxWitnessDelayFlagName  = "max-witness-delay"
+	maxWitnessDelayFlagUsage = "The period of time in which the witnesses must provide a proof, for example 10m." +
+		" Defaults to 10m if not set." +
+		" Alternatively, this can be set with the following environment variable: " + maxWitnessDelayEnvKey
+	maxWitnessDelayEnvKey = "ORB_CLI_MAX_WITNESS_DELAY"
+)
+
+const defaultMaxWitnessDelay = 10 * time.Minute
+
+// GetCmd returns the Cobra activity command.
+func GetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "activity",
+		Short: "Generates Orb activities.",
+		Long: "Generates correctly-formed 'Offer' (witness request), 'Accept' (proof) and 'Announce' (anchor)" +
+			" activities and either posts them to an outbox or writes them to stdout.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return errors.New("expecting subcommand offer, accept, or announce")
+		},
+	}
+
+	cmd.AddCommand(
+		newOfferCmd(),
+		newAcceptCmd(),
+		newAnnounceCmd(),
+	)
+
+	return cmd
+}
+
+func addActivityFlags(cmd *cobra.Command) {
+	common.AddCommonFlags(cmd)
+
+	cmd.Flags().StringP(outboxURLFlagName, "", "", outboxURLFlagUsage)
+	cmd.Flags().StringP(actorFlagName, "", "", actorFlagUsage)
+}
+
+// output posts the activity to the outbox if the outbox URL is specified, otherwise the activity
+// is written to stdout.
+func output(cmd *cobra.Command, activity *vocab.ActivityType) error {
+	outboxURL := cmdutils.GetUserSetOptionalVarFromString(cmd, outboxURLFlagName, outboxURLEnvKey)
+
+	actor, err := getOptionalURL(cmd, actorFlagName, actorEnvKey)
+	if err != nil {
+		return err
+	}
+
+	if actor != nil {
+		activity.SetActor(actor)
+	}
+
+	if outboxURL == "" {
+		activityBytes, e := json.MarshalIndent(activity, "", "  ")
+		if e != nil {
+			return fmt.Errorf("marshal activity: %w", e)
+		}
+
+		fmt.Println(string(activityBytes))
+
+		return nil
+	}
+
+	activityBytes, err := json.Marshal(activity)
+	if err != nil {
+		return fmt.Errorf("marshal activity: %w", err)
+	}
+
+	resp, err := common.SendHTTPRequest(cmd, activityBytes, http.MethodPost, outboxURL)
+	if err != nil {
+		return fmt.Errorf("failed to send http request: %w", err)
+	}
+
+	fmt.Printf("success %s id: %s\n", activity.Type(), resp)
+
+	return nil
+}
+
+func getMaxWitnessDelay(cmd *cobra.Command) (time.Duration, error) {
+	maxWitnessDelayStr := cmdutils.GetUserSetOptionalVarFromString(cmd, maxWitnessDelayFlagName,
+		maxWitnessDelayEnvKey)
+	if maxWitnessDelayStr == "" {
+		return defaultMaxWitnessDelay, nil
+	}
+
+	maxWitnessDelay, err := time.ParseDuration(maxWitnessDelayStr)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value for %s [%s]: %w", maxWitnessDelayFlagName, maxWitnessDelayStr, err)
+	}
+
+	if maxWitnessDelay <= 0 {
+		return 0, fmt.Errorf("%s must be greater than 0", maxWitnessDelayFlagName)
+	}
+
+	return maxWitnessDelay, nil
+}
+
+func getURL(cmd *cobra.Command, flagName, envKey string) (*url.URL, error) {
+	value, err := cmdutils.GetUserSetVarFromString(cmd, flagName, envKey, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseURL(flagName, value)
+}
+
+func getOptionalURL(cmd *cobra.Command, flagName, envKey string) (*url.URL, error) {
+	value := cmdutils.GetUserSetOptionalVarFromString(cmd, flagName, envKey)
+	if value == "" {
+		return nil, nil
+	}
+
+	return parseURL(flagName, value)
+}
+
+func parseURL(flagName, value string) (*url.URL, error) {
+	u, err := url.Parse(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s URL %s: %w", flagName, value, err)
+	}
+
+	return u, nil
+}
+
+// readJSONFile reads the JSON document from the file specified by the given flag and unmarshals
+// it into the given object.
+func readJSONFile(cmd *cobra.Command, flagName, envKey string, obj interface{}) error {
+	path, err := cmdutils.GetUserSetVarFromString(cmd, flagName, envKey, false)
+	if err != nil {
+		return err
+	}
+
+	return unmarshalFile(flagName, path, obj)
+}
+
+func unmarshalFile(flagName, path string, obj interface{}) error {
+	docBytes, err := ioutil.ReadFile(path) //nolint:gosec
+	if err != nil {
+		return fmt.Errorf("read %s file [%s]: %w", flagName, path, err)
+	}
+
+	err = json.Unmarshal(docBytes, obj)
+	if err != nil {
+		return fmt.Errorf("unmarshal %s file [%s]: %w", flagName, path, err)
+	}
+
+	return nil
+}
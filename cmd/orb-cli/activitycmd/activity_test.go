@@ -0,0 +1,184 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package activitycmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+)
+
+const (
+	flag = "--"
+
+	anchorEventURL = "hl:uEiCsFp-ft8tI1DFGbXs78tw-HS561mMPa3Z6GsGAHElrNQ"
+	anchorIndexURL = "hl:uEiDpzs3ld5DWgK3ZMrTPVbGS9ruGd8JvlgXxljwX3v3BDQ"
+	service1       = "https://orb.domain1.com/services/orb"
+	service2       = "https://orb.domain2.com/services/orb"
+)
+
+func TestActivityCmd(t *testing.T) {
+	t.Run("test missing subcommand", func(t *testing.T) {
+		err := GetCmd().Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "expecting subcommand offer, accept, or announce")
+	})
+
+	t.Run("test invalid actor arg", func(t *testing.T) {
+		cmd := GetCmd()
+
+		args := []string{"announce"}
+		args = append(args, followersArg(service1+"/followers")...)
+		args = append(args, anchorEventURLArg(anchorEventURL)...)
+		args = append(args, actorArg(string([]byte{0x0}))...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid actor URL")
+	})
+
+	t.Run("test post to outbox", func(t *testing.T) {
+		outbox := newMockOutbox()
+		defer outbox.Close()
+
+		cmd := GetCmd()
+
+		args := []string{"announce"}
+		args = append(args, followersArg(service1+"/followers")...)
+		args = append(args, anchorEventURLArg(anchorEventURL)...)
+		args = append(args, actorArg(service1)...)
+		args = append(args, outboxURLArg(outbox.URL)...)
+		cmd.SetArgs(args)
+
+		require.NoError(t, cmd.Execute())
+
+		activity := outbox.activity(t)
+		require.True(t, activity.Type().Is(vocab.TypeAnnounce))
+		require.Equal(t, service1, activity.Actor().String())
+	})
+
+	t.Run("test outbox error", func(t *testing.T) {
+		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer serv.Close()
+
+		cmd := GetCmd()
+
+		args := []string{"announce"}
+		args = append(args, followersArg(service1+"/followers")...)
+		args = append(args, anchorEventURLArg(anchorEventURL)...)
+		args = append(args, outboxURLArg(serv.URL)...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to send http request")
+	})
+}
+
+type mockOutbox struct {
+	*httptest.Server
+
+	mutex sync.Mutex
+	body  []byte
+}
+
+func newMockOutbox() *mockOutbox {
+	m := &mockOutbox{}
+
+	m.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		m.mutex.Lock()
+		m.body = body
+		m.mutex.Unlock()
+
+		_, err = fmt.Fprint(w, service1+"/activities/1234")
+		if err != nil {
+			panic(err)
+		}
+	}))
+
+	return m
+}
+
+func (m *mockOutbox) activity(t *testing.T) *vocab.ActivityType {
+	t.Helper()
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	activity := &vocab.ActivityType{}
+	require.NoError(t, json.Unmarshal(m.body, activity))
+
+	return activity
+}
+
+func newAnchorEvent(t *testing.T) *vocab.AnchorEventType {
+	t.Helper()
+
+	return vocab.NewAnchorEvent(
+		vocab.WithURL(mustParseURL(t, anchorEventURL)),
+		vocab.WithIndex(mustParseURL(t, anchorIndexURL)),
+		vocab.WithAttributedTo(mustParseURL(t, service1)),
+	)
+}
+
+func writeJSONFile(t *testing.T, name string, obj interface{}) string {
+	t.Helper()
+
+	docBytes, err := json.Marshal(obj)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), name)
+
+	require.NoError(t, ioutil.WriteFile(path, docBytes, 0600))
+
+	return path
+}
+
+func mustParseURL(t *testing.T, raw string) *url.URL {
+	t.Helper()
+
+	u, err := url.Parse(raw)
+	require.NoError(t, err)
+
+	return u
+}
+
+func outboxURLArg(value string) []string {
+	return []string{flag + outboxURLFlagName, value}
+}
+
+func actorArg(value string) []string {
+	return []string{flag + actorFlagName, value}
+}
+
+func anchorEventArg(value string) []string {
+	return []string{flag + anchorEventFlagName, value}
+}
+
+func maxWitnessDelayArg(value string) []string {
+	return []string{flag + maxWitnessDelayFlagName, value}
+}
@@ -0,0 +1,82 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package activitycmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+
+	"github.com/trustbloc/orb/pkg/activitypub/activitytemplate"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+)
+
+const (
+	followersFlagName  = "followers"
+	followersFlagUsage = "The IRI of the followers collection of the actor." +
+		" Alternatively, this can be set with the following environment variable: " + followersEnvKey
+	followersEnvKey = "ORB_CLI_FOLLOWERS"
+
+	anchorEventURLFlagName  = "anchor-event-url"
+	anchorEventURLFlagUsage = "The URL (hashlink) of the anchor event to announce by reference. Either this flag or " +
+		anchorEventFlagName + " must be set." +
+		" Alternatively, this can be set with the following environment variable: " + anchorEventURLEnvKey
+	anchorEventURLEnvKey = "ORB_CLI_ANCHOR_EVENT_URL"
+)
+
+func newAnnounceCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "announce",
+		Short: "Generates an 'Announce' activity which announces an anchor event to the followers.",
+		Long: "Generates an 'Announce' activity which announces an anchor event (either embedded or by reference)" +
+			" to the followers. The activity is addressed to the followers and to the public collection.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return executeAnnounce(cmd)
+		},
+	}
+
+	addActivityFlags(cmd)
+
+	cmd.Flags().StringP(followersFlagName, "", "", followersFlagUsage)
+	cmd.Flags().StringP(anchorEventFlagName, "", "", anchorEventFlagUsage)
+	cmd.Flags().StringP(anchorEventURLFlagName, "", "", anchorEventURLFlagUsage)
+
+	return cmd
+}
+
+func executeAnnounce(cmd *cobra.Command) error {
+	followers, err := getURL(cmd, followersFlagName, followersEnvKey)
+	if err != nil {
+		return err
+	}
+
+	anchorEventURL, err := getOptionalURL(cmd, anchorEventURLFlagName, anchorEventURLEnvKey)
+	if err != nil {
+		return err
+	}
+
+	anchorEventPath := cmdutils.GetUserSetOptionalVarFromString(cmd, anchorEventFlagName, anchorEventEnvKey)
+
+	switch {
+	case anchorEventPath != "" && anchorEventURL != nil:
+		return fmt.Errorf("only one of %s or %s may be set", anchorEventFlagName, anchorEventURLFlagName)
+	case anchorEventURL != nil:
+		return output(cmd, activitytemplate.NewAnchorRefAnnounce(followers, anchorEventURL))
+	case anchorEventPath != "":
+		anchorEvent := &vocab.AnchorEventType{}
+
+		err = unmarshalFile(anchorEventFlagName, anchorEventPath, anchorEvent)
+		if err != nil {
+			return err
+		}
+
+		return output(cmd, activitytemplate.NewAnchorAnnounce(followers, anchorEvent))
+	default:
+		return fmt.Errorf("either %s or %s must be set", anchorEventFlagName, anchorEventURLFlagName)
+	}
+}
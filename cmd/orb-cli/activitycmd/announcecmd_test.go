@@ -0,0 +1,135 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package activitycmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+)
+
+func TestAnnounceCmd(t *testing.T) {
+	const followers = service1 + "/followers"
+
+	anchorEventFile := writeJSONFile(t, "anchorevent.json", newAnchorEvent(t))
+
+	t.Run("test missing followers arg", func(t *testing.T) {
+		cmd := GetCmd()
+		cmd.SetArgs([]string{"announce"})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Equal(t,
+			"Neither followers (command line flag) nor ORB_CLI_FOLLOWERS (environment variable) have been set.",
+			err.Error())
+	})
+
+	t.Run("test invalid anchor event URL arg", func(t *testing.T) {
+		cmd := GetCmd()
+
+		args := []string{"announce"}
+		args = append(args, followersArg(followers)...)
+		args = append(args, anchorEventURLArg(":invalid")...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid anchor-event-url URL")
+	})
+
+	t.Run("test missing anchor event", func(t *testing.T) {
+		cmd := GetCmd()
+
+		args := []string{"announce"}
+		args = append(args, followersArg(followers)...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "either anchor-event or anchor-event-url must be set")
+	})
+
+	t.Run("test anchor event and anchor event URL", func(t *testing.T) {
+		cmd := GetCmd()
+
+		args := []string{"announce"}
+		args = append(args, followersArg(followers)...)
+		args = append(args, anchorEventArg(anchorEventFile)...)
+		args = append(args, anchorEventURLArg(anchorEventURL)...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "only one of anchor-event or anchor-event-url may be set")
+	})
+
+	t.Run("test anchor event file not found", func(t *testing.T) {
+		cmd := GetCmd()
+
+		args := []string{"announce"}
+		args = append(args, followersArg(followers)...)
+		args = append(args, anchorEventArg("./invalid.json")...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "read anchor-event file")
+	})
+
+	t.Run("test announce anchor event", func(t *testing.T) {
+		outbox := newMockOutbox()
+		defer outbox.Close()
+
+		cmd := GetCmd()
+
+		args := []string{"announce"}
+		args = append(args, followersArg(followers)...)
+		args = append(args, anchorEventArg(anchorEventFile)...)
+		args = append(args, outboxURLArg(outbox.URL)...)
+		cmd.SetArgs(args)
+
+		require.NoError(t, cmd.Execute())
+
+		announce := outbox.activity(t)
+		require.True(t, announce.Type().Is(vocab.TypeAnnounce))
+		require.True(t, announce.To().Contains(mustParseURL(t, followers), vocab.PublicIRI))
+
+		items := announce.Object().Collection().Items()
+		require.Len(t, items, 1)
+		require.Equal(t, anchorIndexURL, items[0].AnchorEvent().Index().String())
+	})
+
+	t.Run("test announce anchor event reference", func(t *testing.T) {
+		outbox := newMockOutbox()
+		defer outbox.Close()
+
+		cmd := GetCmd()
+
+		args := []string{"announce"}
+		args = append(args, followersArg(followers)...)
+		args = append(args, anchorEventURLArg(anchorEventURL)...)
+		args = append(args, outboxURLArg(outbox.URL)...)
+		cmd.SetArgs(args)
+
+		require.NoError(t, cmd.Execute())
+
+		items := outbox.activity(t).Object().Collection().Items()
+		require.Len(t, items, 1)
+		require.Nil(t, items[0].AnchorEvent().Index())
+		require.Equal(t, anchorEventURL, items[0].AnchorEvent().URL()[0].String())
+	})
+}
+
+func followersArg(value string) []string {
+	return []string{flag + followersFlagName, value}
+}
+
+func anchorEventURLArg(value string) []string {
+	return []string{flag + anchorEventURLFlagName, value}
+}
@@ -0,0 +1,83 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package activitycmd
+
+import (
+	"net/url"
+
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+
+	"github.com/trustbloc/orb/pkg/activitypub/activitytemplate"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+)
+
+const (
+	witnessFlagName  = "witness"
+	witnessFlagUsage = "A comma-separated list of the service IRIs of the witnesses." +
+		" Alternatively, this can be set with the following environment variable: " + witnessEnvKey
+	witnessEnvKey = "ORB_CLI_WITNESS"
+)
+
+func newOfferCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "offer",
+		Short: "Generates an 'Offer' activity which requests witnesses to witness an anchor event.",
+		Long: "Generates an 'Offer' activity which requests witnesses to witness an anchor event. The activity" +
+			" is addressed to the witnesses and to the public collection.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return executeOffer(cmd)
+		},
+	}
+
+	addActivityFlags(cmd)
+
+	cmd.Flags().StringP(anchorEventFlagName, "", "", anchorEventFlagUsage)
+	cmd.Flags().StringArrayP(witnessFlagName, "", nil, witnessFlagUsage)
+	cmd.Flags().StringP(maxWitnessDelayFlagName, "", "", maxWitnessDelayFlagUsage)
+
+	return cmd
+}
+
+func executeOffer(cmd *cobra.Command) error {
+	anchorEvent := &vocab.AnchorEventType{}
+
+	err := readJSONFile(cmd, anchorEventFlagName, anchorEventEnvKey, anchorEvent)
+	if err != nil {
+		return err
+	}
+
+	witnesses, err := getWitnesses(cmd)
+	if err != nil {
+		return err
+	}
+
+	maxWitnessDelay, err := getMaxWitnessDelay(cmd)
+	if err != nil {
+		return err
+	}
+
+	return output(cmd, activitytemplate.NewWitnessOffer(anchorEvent, witnesses, maxWitnessDelay))
+}
+
+func getWitnesses(cmd *cobra.Command) ([]*url.URL, error) {
+	values, err := cmdutils.GetUserSetVarFromArrayString(cmd, witnessFlagName, witnessEnvKey, false)
+	if err != nil {
+		return nil, err
+	}
+
+	witnesses := make([]*url.URL, len(values))
+
+	for i, value := range values {
+		witnesses[i], err = parseURL(witnessFlagName, value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return witnesses, nil
+}
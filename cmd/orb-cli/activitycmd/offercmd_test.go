@@ -0,0 +1,134 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package activitycmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+)
+
+func TestOfferCmd(t *testing.T) {
+	anchorEventFile := writeJSONFile(t, "anchorevent.json", newAnchorEvent(t))
+
+	t.Run("test missing anchor event arg", func(t *testing.T) {
+		cmd := GetCmd()
+		cmd.SetArgs([]string{"offer"})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Equal(t,
+			"Neither anchor-event (command line flag) nor ORB_CLI_ANCHOR_EVENT (environment variable) have been set.",
+			err.Error())
+	})
+
+	t.Run("test anchor event file not found", func(t *testing.T) {
+		cmd := GetCmd()
+
+		args := []string{"offer"}
+		args = append(args, anchorEventArg("./invalid.json")...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "read anchor-event file")
+	})
+
+	t.Run("test missing witness arg", func(t *testing.T) {
+		cmd := GetCmd()
+
+		args := []string{"offer"}
+		args = append(args, anchorEventArg(anchorEventFile)...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Equal(t,
+			"Neither witness (command line flag) nor ORB_CLI_WITNESS (environment variable) have been set.",
+			err.Error())
+	})
+
+	t.Run("test invalid witness arg", func(t *testing.T) {
+		cmd := GetCmd()
+
+		args := []string{"offer"}
+		args = append(args, anchorEventArg(anchorEventFile)...)
+		args = append(args, witnessArg(":invalid")...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid witness URL")
+	})
+
+	t.Run("test invalid max witness delay arg", func(t *testing.T) {
+		cmd := GetCmd()
+
+		args := []string{"offer"}
+		args = append(args, anchorEventArg(anchorEventFile)...)
+		args = append(args, witnessArg(service2)...)
+		args = append(args, maxWitnessDelayArg("xxx")...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for max-witness-delay")
+
+		cmd = GetCmd()
+
+		args = []string{"offer"}
+		args = append(args, anchorEventArg(anchorEventFile)...)
+		args = append(args, witnessArg(service2)...)
+		args = append(args, maxWitnessDelayArg("-1m")...)
+		cmd.SetArgs(args)
+
+		err = cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "max-witness-delay must be greater than 0")
+	})
+
+	t.Run("test write to stdout", func(t *testing.T) {
+		cmd := GetCmd()
+
+		args := []string{"offer"}
+		args = append(args, anchorEventArg(anchorEventFile)...)
+		args = append(args, witnessArg(service2)...)
+		cmd.SetArgs(args)
+
+		require.NoError(t, cmd.Execute())
+	})
+
+	t.Run("test post to outbox", func(t *testing.T) {
+		outbox := newMockOutbox()
+		defer outbox.Close()
+
+		cmd := GetCmd()
+
+		args := []string{"offer"}
+		args = append(args, anchorEventArg(anchorEventFile)...)
+		args = append(args, witnessArg(service2)...)
+		args = append(args, maxWitnessDelayArg("5m")...)
+		args = append(args, outboxURLArg(outbox.URL)...)
+		cmd.SetArgs(args)
+
+		require.NoError(t, cmd.Execute())
+
+		offer := outbox.activity(t)
+		require.True(t, offer.Type().Is(vocab.TypeOffer))
+		require.Equal(t, anchorEventURL, offer.Object().AnchorEvent().URL()[0].String())
+		require.True(t, offer.To().Contains(mustParseURL(t, service2), vocab.PublicIRI))
+		require.Equal(t, vocab.AnchorWitnessTargetIRI.String(), offer.Target().IRI().String())
+		require.Equal(t, 5*time.Minute, offer.EndTime().Sub(*offer.StartTime()))
+	})
+}
+
+func witnessArg(value string) []string {
+	return []string{flag + witnessFlagName, value}
+}
@@ -0,0 +1,165 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package backupcmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+)
+
+const (
+	uploadURLFlagName  = "upload-url"
+	uploadURLFlagUsage = "A URL to which the archive is uploaded with an HTTP PUT request, for example a pre-signed" +
+		" S3 URL. If --file isn't set then the archive is only uploaded." +
+		" Alternatively, this can be set with the following environment variable: " + uploadURLEnvKey
+	uploadURLEnvKey = "ORB_CLI_BACKUP_UPLOAD_URL"
+)
+
+// GetBackupCmd returns the Cobra backup command.
+func GetBackupCmd() *cobra.Command {
+	return newBackupCmd(newMongoDBArchiver)
+}
+
+func newBackupCmd(provider archiverProvider) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Backs up the state of the Orb servers.",
+		Long: "Writes the activity, anchor, operation and configuration stores (all Orb databases) to a portable " +
+			"archive which may be saved to a file and/or uploaded (for example to S3). Maintenance mode is enabled " +
+			"on the given servers while the backup is taken so that the archive is consistent.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return executeBackup(cmd, provider)
+		},
+	}
+
+	addDatabaseFlags(cmd)
+
+	cmd.Flags().StringP(uploadURLFlagName, "", "", uploadURLFlagUsage)
+
+	return cmd
+}
+
+func executeBackup(cmd *cobra.Command, provider archiverProvider) error {
+	fileName := cmdutils.GetUserSetOptionalVarFromString(cmd, fileFlagName, fileEnvKey)
+	uploadURL := cmdutils.GetUserSetOptionalVarFromString(cmd, uploadURLFlagName, uploadURLEnvKey)
+
+	if fileName == "" && uploadURL == "" {
+		return errors.New("either file or upload-url must be specified")
+	}
+
+	if uploadURL != "" {
+		if _, err := url.ParseRequestURI(uploadURL); err != nil {
+			return fmt.Errorf("invalid upload URL: %w", err)
+		}
+	}
+
+	maintenanceURLs, err := getMaintenanceURLs(cmd)
+	if err != nil {
+		return err
+	}
+
+	a, closeArchiver, err := newArchiver(cmd, provider)
+	if err != nil {
+		return err
+	}
+
+	defer closeArchiver()
+
+	if fileName == "" {
+		f, e := ioutil.TempFile("", "orb-backup-*.tar.gz")
+		if e != nil {
+			return fmt.Errorf("create temp file: %w", e)
+		}
+
+		fileName = f.Name()
+
+		closeFile(f)
+
+		defer removeFile(fileName)
+	}
+
+	err = withMaintenanceMode(cmd, maintenanceURLs, func() error {
+		return writeArchive(cmd, a, fileName)
+	})
+	if err != nil {
+		return err
+	}
+
+	if uploadURL != "" {
+		return upload(cmd, fileName, uploadURL)
+	}
+
+	return nil
+}
+
+func writeArchive(cmd *cobra.Command, a archiver, fileName string) error {
+	f, err := os.Create(filepath.Clean(fileName))
+	if err != nil {
+		return fmt.Errorf("create archive file: %w", err)
+	}
+
+	defer closeFile(f)
+
+	manifest, err := a.Backup(context.Background(), f)
+	if err != nil {
+		return fmt.Errorf("backup: %w", err)
+	}
+
+	printManifest(cmd, "Backed up", manifest)
+
+	return nil
+}
+
+// upload uploads the archive with a PUT request. The file is sent with a Content-Length header since pre-signed
+// S3 URLs don't support chunked uploads.
+func upload(cmd *cobra.Command, fileName, uploadURL string) error {
+	f, err := os.Open(filepath.Clean(fileName))
+	if err != nil {
+		return fmt.Errorf("open archive file: %w", err)
+	}
+
+	defer closeFile(f)
+
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("stat archive file: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPut, uploadURL, f)
+	if err != nil {
+		return fmt.Errorf("create upload request: %w", err)
+	}
+
+	req.ContentLength = info.Size()
+	req.Header.Set("Content-Type", "application/gzip")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("upload archive: %w", err)
+	}
+
+	defer closeBody(resp)
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		body, _ := ioutil.ReadAll(resp.Body) //nolint:errcheck
+
+		return fmt.Errorf("upload archive: unexpected status %d: %s", resp.StatusCode, body)
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Uploaded archive (%d bytes)\n", info.Size())
+
+	return nil
+}
@@ -0,0 +1,183 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package backupcmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/trustbloc/edge-core/pkg/log"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/trustbloc/orb/cmd/orb-cli/common"
+	"github.com/trustbloc/orb/pkg/backup"
+)
+
+var logger = log.New("orb-cli-backup")
+
+const (
+	databaseURLFlagName  = "database-url"
+	databaseURLFlagUsage = "The URL (connection string) of the MongoDB database used by the Orb servers." +
+		" Alternatively, this can be set with the following environment variable: " + databaseURLEnvKey
+	databaseURLEnvKey = "ORB_CLI_DATABASE_URL"
+
+	databasePrefixFlagName  = "database-prefix"
+	databasePrefixFlagUsage = "The prefix of the Orb databases (as configured on the Orb servers)." +
+		" Alternatively, this can be set with the following environment variable: " + databasePrefixEnvKey
+	databasePrefixEnvKey = "ORB_CLI_DATABASE_PREFIX"
+
+	fileFlagName  = "file"
+	fileFlagUsage = "The path of the backup archive." +
+		" Alternatively, this can be set with the following environment variable: " + fileEnvKey
+	fileEnvKey = "ORB_CLI_BACKUP_FILE"
+
+	maintenanceURLFlagName  = "maintenance-url"
+	maintenanceURLFlagUsage = "The URL of the maintenance mode endpoint of an Orb server, for example " +
+		"https://orb.domain1.com/maintenance. Maintenance mode is enabled on each of the given servers before " +
+		"the operation and disabled when it completes so that no data is written in the meantime. This flag " +
+		"may be repeated for each server in the cluster. If not set then the servers should be stopped first." +
+		" Alternatively, this can be set with the following environment variable (comma separated): " +
+		maintenanceURLEnvKey
+	maintenanceURLEnvKey = "ORB_CLI_MAINTENANCE_URL"
+)
+
+const mongoDBScheme = "mongodb"
+
+// archiver creates and restores backup archives.
+type archiver interface {
+	Backup(ctx context.Context, w io.Writer) (*backup.Manifest, error)
+	Restore(ctx context.Context, r io.Reader, overwrite bool) (*backup.Manifest, error)
+}
+
+// archiverProvider returns the archiver for the given database along with a function that releases its resources.
+type archiverProvider func(databaseURL, databasePrefix string) (archiver, func(), error)
+
+type maintenanceRequest struct {
+	Enabled bool `json:"enabled"`
+}
+
+func addDatabaseFlags(cmd *cobra.Command) {
+	common.AddCommonFlags(cmd)
+
+	cmd.Flags().StringP(databaseURLFlagName, "", "", databaseURLFlagUsage)
+	cmd.Flags().StringP(databasePrefixFlagName, "", "", databasePrefixFlagUsage)
+	cmd.Flags().StringP(fileFlagName, "", "", fileFlagUsage)
+	cmd.Flags().StringArrayP(maintenanceURLFlagName, "", nil, maintenanceURLFlagUsage)
+}
+
+func newArchiver(cmd *cobra.Command, provider archiverProvider) (archiver, func(), error) {
+	databaseURL, err := cmdutils.GetUserSetVarFromString(cmd, databaseURLFlagName, databaseURLEnvKey, false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !strings.HasPrefix(databaseURL, mongoDBScheme) {
+		return nil, nil, fmt.Errorf("unsupported database URL [%s]: only MongoDB is supported", databaseURL)
+	}
+
+	databasePrefix := cmdutils.GetUserSetOptionalVarFromString(cmd, databasePrefixFlagName, databasePrefixEnvKey)
+
+	return provider(databaseURL, databasePrefix)
+}
+
+func newMongoDBArchiver(databaseURL, databasePrefix string) (archiver, func(), error) {
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(databaseURL))
+	if err != nil {
+		return nil, nil, fmt.Errorf("connect to MongoDB: %w", err)
+	}
+
+	return backup.NewMongoDB(client, databasePrefix), func() {
+		if e := client.Disconnect(context.Background()); e != nil {
+			logger.Warnf("Error disconnecting from MongoDB: %s", e)
+		}
+	}, nil
+}
+
+func getMaintenanceURLs(cmd *cobra.Command) ([]string, error) {
+	urls := cmdutils.GetUserSetOptionalVarFromArrayString(cmd, maintenanceURLFlagName, maintenanceURLEnvKey)
+
+	for _, u := range urls {
+		if _, err := url.ParseRequestURI(u); err != nil {
+			return nil, fmt.Errorf("invalid maintenance URL [%s]: %w", u, err)
+		}
+	}
+
+	return urls, nil
+}
+
+// withMaintenanceMode enables maintenance mode on all of the given servers, invokes the given function and then
+// disables maintenance mode on the servers. Maintenance mode is disabled even if the function returns an error.
+func withMaintenanceMode(cmd *cobra.Command, urls []string, fn func() error) error {
+	var enabled []string
+
+	defer func() {
+		for _, u := range enabled {
+			if err := setMaintenanceMode(cmd, u, false); err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Error disabling maintenance mode on %s: %s\n", u, err)
+			}
+		}
+	}()
+
+	for _, u := range urls {
+		if err := setMaintenanceMode(cmd, u, true); err != nil {
+			return fmt.Errorf("enable maintenance mode on %s: %w", u, err)
+		}
+
+		enabled = append(enabled, u)
+	}
+
+	return fn()
+}
+
+func setMaintenanceMode(cmd *cobra.Command, maintenanceURL string, enabled bool) error {
+	reqBytes, err := json.Marshal(&maintenanceRequest{Enabled: enabled})
+	if err != nil {
+		return err
+	}
+
+	_, err = common.SendHTTPRequest(cmd, reqBytes, http.MethodPost, maintenanceURL)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Maintenance mode enabled on %s: %t\n", maintenanceURL, enabled)
+
+	return nil
+}
+
+func printManifest(cmd *cobra.Command, action string, manifest *backup.Manifest) {
+	var docs int64
+
+	for _, c := range manifest.Collections {
+		docs += c.Documents
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "%s %d collections (%d documents) - archive created %s\n",
+		action, len(manifest.Collections), docs, manifest.Created)
+}
+
+func closeFile(f *os.File) {
+	if err := f.Close(); err != nil {
+		logger.Warnf("Error closing file [%s]: %s", f.Name(), err)
+	}
+}
+
+func removeFile(name string) {
+	if err := os.Remove(filepath.Clean(name)); err != nil {
+		logger.Warnf("Error removing file [%s]: %s", name, err)
+	}
+}
@@ -0,0 +1,370 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package backupcmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/backup"
+)
+
+const (
+	flag        = "--"
+	databaseURL = "mongodb://localhost:27017"
+	archiveData = "archive-data"
+)
+
+func TestBackupCmd(t *testing.T) {
+	t.Run("Success - file", func(t *testing.T) {
+		m := newMockMaintenance()
+		srv := httptest.NewServer(m)
+		defer srv.Close()
+
+		a := &mockArchiver{maintenance: m}
+		fileName := filepath.Join(t.TempDir(), "backup.tar.gz")
+
+		out, err := execute(newBackupCmd(a.provider),
+			flag+databaseURLFlagName, databaseURL,
+			flag+fileFlagName, fileName,
+			flag+maintenanceURLFlagName, srv.URL+"/maintenance",
+		)
+		require.NoError(t, err)
+		require.Contains(t, out, "Backed up 1 collections (3 documents)")
+		require.Equal(t, []bool{true, false}, m.requests())
+		require.True(t, a.maintenanceEnabled)
+
+		data, err := ioutil.ReadFile(filepath.Clean(fileName))
+		require.NoError(t, err)
+		require.Equal(t, archiveData, string(data))
+	})
+
+	t.Run("Success - upload", func(t *testing.T) {
+		var uploaded []byte
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodPut, r.Method)
+			require.Equal(t, int64(len(archiveData)), r.ContentLength)
+
+			var err error
+
+			uploaded, err = ioutil.ReadAll(r.Body)
+			require.NoError(t, err)
+		}))
+		defer srv.Close()
+
+		out, err := execute(newBackupCmd((&mockArchiver{}).provider),
+			flag+databaseURLFlagName, databaseURL,
+			flag+uploadURLFlagName, srv.URL+"/bucket/backup.tar.gz",
+		)
+		require.NoError(t, err)
+		require.Contains(t, out, "Uploaded archive")
+		require.Equal(t, archiveData, string(uploaded))
+	})
+
+	t.Run("Upload error", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		}))
+		defer srv.Close()
+
+		_, err := execute(newBackupCmd((&mockArchiver{}).provider),
+			flag+databaseURLFlagName, databaseURL,
+			flag+uploadURLFlagName, srv.URL,
+		)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unexpected status 403")
+	})
+
+	t.Run("Missing file and upload URL", func(t *testing.T) {
+		_, err := execute(newBackupCmd((&mockArchiver{}).provider), flag+databaseURLFlagName, databaseURL)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "either file or upload-url must be specified")
+	})
+
+	t.Run("Missing database URL", func(t *testing.T) {
+		_, err := execute(newBackupCmd((&mockArchiver{}).provider), flag+fileFlagName, "backup.tar.gz")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Neither database-url (command line flag) nor ORB_CLI_DATABASE_URL")
+	})
+
+	t.Run("Unsupported database", func(t *testing.T) {
+		_, err := execute(newBackupCmd((&mockArchiver{}).provider),
+			flag+databaseURLFlagName, "couchdb://localhost:5984",
+			flag+fileFlagName, "backup.tar.gz",
+		)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "only MongoDB is supported")
+	})
+
+	t.Run("Invalid maintenance URL", func(t *testing.T) {
+		_, err := execute(newBackupCmd((&mockArchiver{}).provider),
+			flag+databaseURLFlagName, databaseURL,
+			flag+fileFlagName, "backup.tar.gz",
+			flag+maintenanceURLFlagName, ":invalid",
+		)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid maintenance URL")
+	})
+
+	t.Run("Backup error - maintenance mode is disabled", func(t *testing.T) {
+		m := newMockMaintenance()
+		srv := httptest.NewServer(m)
+		defer srv.Close()
+
+		_, err := execute(newBackupCmd((&mockArchiver{err: errors.New("injected backup error")}).provider),
+			flag+databaseURLFlagName, databaseURL,
+			flag+fileFlagName, filepath.Join(t.TempDir(), "backup.tar.gz"),
+			flag+maintenanceURLFlagName, srv.URL+"/maintenance",
+		)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected backup error")
+		require.Equal(t, []bool{true, false}, m.requests())
+	})
+
+	t.Run("Enable maintenance mode error", func(t *testing.T) {
+		m := newMockMaintenance()
+		srv := httptest.NewServer(m)
+		defer srv.Close()
+
+		failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer failing.Close()
+
+		a := &mockArchiver{}
+
+		_, err := execute(newBackupCmd(a.provider),
+			flag+databaseURLFlagName, databaseURL,
+			flag+fileFlagName, filepath.Join(t.TempDir(), "backup.tar.gz"),
+			flag+maintenanceURLFlagName, srv.URL+"/maintenance",
+			flag+maintenanceURLFlagName, failing.URL+"/maintenance",
+		)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "enable maintenance mode")
+		require.Equal(t, []bool{true, false}, m.requests())
+		require.False(t, a.invoked)
+	})
+}
+
+func TestRestoreCmd(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "backup.tar.gz")
+	require.NoError(t, ioutil.WriteFile(fileName, []byte(archiveData), 0o600))
+
+	t.Run("Success - file", func(t *testing.T) {
+		m := newMockMaintenance()
+		srv := httptest.NewServer(m)
+		defer srv.Close()
+
+		a := &mockArchiver{maintenance: m}
+
+		out, err := execute(newRestoreCmd(a.provider),
+			flag+databaseURLFlagName, databaseURL,
+			flag+fileFlagName, fileName,
+			flag+overwriteFlagName, "true",
+			flag+maintenanceURLFlagName, srv.URL+"/maintenance",
+		)
+		require.NoError(t, err)
+		require.Contains(t, out, "Restored 1 collections (3 documents)")
+		require.Equal(t, archiveData, string(a.restored))
+		require.True(t, a.overwrite)
+		require.True(t, a.maintenanceEnabled)
+		require.Equal(t, []bool{true, false}, m.requests())
+	})
+
+	t.Run("Success - download", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, err := w.Write([]byte(archiveData))
+			require.NoError(t, err)
+		}))
+		defer srv.Close()
+
+		a := &mockArchiver{}
+
+		_, err := execute(newRestoreCmd(a.provider),
+			flag+databaseURLFlagName, databaseURL,
+			flag+downloadURLFlagName, srv.URL+"/bucket/backup.tar.gz",
+		)
+		require.NoError(t, err)
+		require.Equal(t, archiveData, string(a.restored))
+		require.False(t, a.overwrite)
+	})
+
+	t.Run("Download error", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer srv.Close()
+
+		_, err := execute(newRestoreCmd((&mockArchiver{}).provider),
+			flag+databaseURLFlagName, databaseURL,
+			flag+downloadURLFlagName, srv.URL,
+		)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unexpected status 404")
+	})
+
+	t.Run("File and download URL", func(t *testing.T) {
+		_, err := execute(newRestoreCmd((&mockArchiver{}).provider),
+			flag+databaseURLFlagName, databaseURL,
+			flag+fileFlagName, fileName,
+			flag+downloadURLFlagName, "https://example.com/backup.tar.gz",
+		)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "exactly one of file or download-url must be specified")
+	})
+
+	t.Run("Invalid overwrite", func(t *testing.T) {
+		_, err := execute(newRestoreCmd((&mockArchiver{}).provider),
+			flag+databaseURLFlagName, databaseURL,
+			flag+fileFlagName, fileName,
+			flag+overwriteFlagName, "xxx",
+		)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for overwrite")
+	})
+
+	t.Run("File not found", func(t *testing.T) {
+		_, err := execute(newRestoreCmd((&mockArchiver{}).provider),
+			flag+databaseURLFlagName, databaseURL,
+			flag+fileFlagName, filepath.Join(t.TempDir(), "missing.tar.gz"),
+		)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "open archive file")
+	})
+
+	t.Run("Restore error", func(t *testing.T) {
+		_, err := execute(newRestoreCmd((&mockArchiver{err: errors.New("injected restore error")}).provider),
+			flag+databaseURLFlagName, databaseURL,
+			flag+fileFlagName, fileName,
+		)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected restore error")
+	})
+}
+
+func TestGetCmds(t *testing.T) {
+	require.Equal(t, "backup", GetBackupCmd().Use)
+	require.Equal(t, "restore", GetRestoreCmd().Use)
+}
+
+func execute(cmd *cobra.Command, args ...string) (string, error) {
+	out := &bytes.Buffer{}
+
+	cmd.SetArgs(args)
+	cmd.SetOut(out)
+	cmd.SetErr(out)
+
+	err := cmd.Execute()
+
+	return out.String(), err
+}
+
+type mockMaintenance struct {
+	mutex   sync.Mutex
+	enabled []bool
+}
+
+func newMockMaintenance() *mockMaintenance {
+	return &mockMaintenance{}
+}
+
+func (m *mockMaintenance) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	req := &maintenanceRequest{}
+
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	m.mutex.Lock()
+	m.enabled = append(m.enabled, req.Enabled)
+	m.mutex.Unlock()
+}
+
+func (m *mockMaintenance) isEnabled() bool {
+	reqs := m.requests()
+
+	return len(reqs) > 0 && reqs[len(reqs)-1]
+}
+
+func (m *mockMaintenance) requests() []bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.enabled
+}
+
+type mockArchiver struct {
+	maintenance        *mockMaintenance
+	err                error
+	invoked            bool
+	maintenanceEnabled bool
+	restored           []byte
+	overwrite          bool
+}
+
+func (m *mockArchiver) provider(string, string) (archiver, func(), error) {
+	return m, func() {}, nil
+}
+
+func (m *mockArchiver) Backup(_ context.Context, w io.Writer) (*backup.Manifest, error) {
+	m.invoked = true
+	m.maintenanceEnabled = m.maintenance != nil && m.maintenance.isEnabled()
+
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	if _, err := w.Write([]byte(archiveData)); err != nil {
+		return nil, err
+	}
+
+	return newManifest(), nil
+}
+
+func (m *mockArchiver) Restore(_ context.Context, r io.Reader, overwrite bool) (*backup.Manifest, error) {
+	m.invoked = true
+	m.maintenanceEnabled = m.maintenance != nil && m.maintenance.isEnabled()
+	m.overwrite = overwrite
+
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	var err error
+
+	m.restored, err = ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return newManifest(), nil
+}
+
+func newManifest() *backup.Manifest {
+	return &backup.Manifest{
+		FormatVersion: backup.FormatVersion,
+		Created:       time.Now(),
+		DatabaseType:  backup.DatabaseTypeMongoDB,
+		Collections:   []*backup.Collection{{Database: "orb_activity", Name: "activity", Documents: 3}},
+	}
+}
@@ -0,0 +1,157 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package backupcmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+)
+
+const (
+	downloadURLFlagName  = "download-url"
+	downloadURLFlagUsage = "A URL from which the archive is downloaded with an HTTP GET request, for example a" +
+		" pre-signed S3 URL. Either this flag or --file must be set." +
+		" Alternatively, this can be set with the following environment variable: " + downloadURLEnvKey
+	downloadURLEnvKey = "ORB_CLI_RESTORE_DOWNLOAD_URL"
+
+	overwriteFlagName  = "overwrite"
+	overwriteFlagUsage = "Set to true to replace the data in collections that aren't empty. Defaults to false," +
+		" in which case the restore fails if any of the collections in the archive already contains data." +
+		" Alternatively, this can be set with the following environment variable: " + overwriteEnvKey
+	overwriteEnvKey = "ORB_CLI_RESTORE_OVERWRITE"
+)
+
+// GetRestoreCmd returns the Cobra restore command.
+func GetRestoreCmd() *cobra.Command {
+	return newRestoreCmd(newMongoDBArchiver)
+}
+
+func newRestoreCmd(provider archiverProvider) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore",
+		Short: "Restores the state of the Orb servers from a backup.",
+		Long: "Restores the Orb databases from an archive created by the backup command. The database prefix of " +
+			"the archive is replaced by the given database prefix. Maintenance mode is enabled on the given " +
+			"servers while the data is restored.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return executeRestore(cmd, provider)
+		},
+	}
+
+	addDatabaseFlags(cmd)
+
+	cmd.Flags().StringP(downloadURLFlagName, "", "", downloadURLFlagUsage)
+	cmd.Flags().StringP(overwriteFlagName, "", "", overwriteFlagUsage)
+
+	return cmd
+}
+
+func executeRestore(cmd *cobra.Command, provider archiverProvider) error {
+	fileName := cmdutils.GetUserSetOptionalVarFromString(cmd, fileFlagName, fileEnvKey)
+	downloadURL := cmdutils.GetUserSetOptionalVarFromString(cmd, downloadURLFlagName, downloadURLEnvKey)
+
+	if (fileName == "") == (downloadURL == "") {
+		return errors.New("exactly one of file or download-url must be specified")
+	}
+
+	if downloadURL != "" {
+		if _, err := url.ParseRequestURI(downloadURL); err != nil {
+			return fmt.Errorf("invalid download URL: %w", err)
+		}
+	}
+
+	overwrite := false
+
+	if overwriteStr := cmdutils.GetUserSetOptionalVarFromString(cmd, overwriteFlagName,
+		overwriteEnvKey); overwriteStr != "" {
+		var err error
+
+		overwrite, err = strconv.ParseBool(overwriteStr)
+		if err != nil {
+			return fmt.Errorf("invalid value for %s: %w", overwriteFlagName, err)
+		}
+	}
+
+	maintenanceURLs, err := getMaintenanceURLs(cmd)
+	if err != nil {
+		return err
+	}
+
+	a, closeArchiver, err := newArchiver(cmd, provider)
+	if err != nil {
+		return err
+	}
+
+	defer closeArchiver()
+
+	return withMaintenanceMode(cmd, maintenanceURLs, func() error {
+		r, closeReader, e := openArchive(fileName, downloadURL)
+		if e != nil {
+			return e
+		}
+
+		defer closeReader()
+
+		manifest, e := a.Restore(context.Background(), r, overwrite)
+		if e != nil {
+			return fmt.Errorf("restore: %w", e)
+		}
+
+		printManifest(cmd, "Restored", manifest)
+
+		return nil
+	})
+}
+
+// openArchive opens the archive file or, if a download URL is given, streams the archive from the URL.
+func openArchive(fileName, downloadURL string) (io.Reader, func(), error) {
+	if fileName != "" {
+		f, err := os.Open(filepath.Clean(fileName))
+		if err != nil {
+			return nil, nil, fmt.Errorf("open archive file: %w", err)
+		}
+
+		return f, func() { closeFile(f) }, nil
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, downloadURL, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("create download request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("download archive: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body) //nolint:errcheck
+
+		closeBody(resp)
+
+		return nil, nil, fmt.Errorf("download archive: unexpected status %d: %s", resp.StatusCode, body)
+	}
+
+	return resp.Body, func() { closeBody(resp) }, nil
+}
+
+func closeBody(resp *http.Response) {
+	if err := resp.Body.Close(); err != nil {
+		logger.Warnf("Error closing response body: %s", err)
+	}
+}
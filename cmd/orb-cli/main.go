@@ -11,6 +11,8 @@ import (
 	"github.com/trustbloc/edge-core/pkg/log"
 
 	"github.com/trustbloc/orb/cmd/orb-cli/acceptlistcmd"
+	"github.com/trustbloc/orb/cmd/orb-cli/activitycmd"
+	"github.com/trustbloc/orb/cmd/orb-cli/backupcmd"
 	"github.com/trustbloc/orb/cmd/orb-cli/createdidcmd"
 	"github.com/trustbloc/orb/cmd/orb-cli/deactivatedidcmd"
 	"github.com/trustbloc/orb/cmd/orb-cli/followcmd"
@@ -18,6 +20,7 @@ import (
 	"github.com/trustbloc/orb/cmd/orb-cli/ipnshostmetagencmd"
 	"github.com/trustbloc/orb/cmd/orb-cli/ipnshostmetauploadcmd"
 	"github.com/trustbloc/orb/cmd/orb-cli/recoverdidcmd"
+	"github.com/trustbloc/orb/cmd/orb-cli/statuscmd"
 	"github.com/trustbloc/orb/cmd/orb-cli/updatedidcmd"
 	"github.com/trustbloc/orb/cmd/orb-cli/witnesscmd"
 )
@@ -60,6 +63,10 @@ func main() {
 	rootCmd.AddCommand(followcmd.GetCmd())
 	rootCmd.AddCommand(witnesscmd.GetCmd())
 	rootCmd.AddCommand(acceptlistcmd.GetCmd())
+	rootCmd.AddCommand(activitycmd.GetCmd())
+	rootCmd.AddCommand(statuscmd.GetCmd())
+	rootCmd.AddCommand(backupcmd.GetBackupCmd())
+	rootCmd.AddCommand(backupcmd.GetRestoreCmd())
 
 	if err := rootCmd.Execute(); err != nil {
 		logger.Fatalf("Failed to run orb-cli: %s", err.Error())
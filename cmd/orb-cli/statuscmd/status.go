@@ -0,0 +1,418 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statuscmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+
+	"github.com/trustbloc/orb/cmd/orb-cli/common"
+)
+
+const (
+	urlFlagName  = "url"
+	urlFlagUsage = "The URL of the ActivityPub service, for example https://orb.domain1.com/services/orb." +
+		" Alternatively, this can be set with the following environment variable: " + urlEnvKey
+	urlEnvKey = "ORB_CLI_URL"
+
+	metricsURLFlagName  = "metrics-url"
+	metricsURLFlagUsage = "The URL of the node's Prometheus metrics endpoint, for example http://orb.domain1.com:48327/metrics." +
+		" If not set then metrics are not included in the summary." +
+		" Alternatively, this can be set with the following environment variable: " + metricsURLEnvKey
+	metricsURLEnvKey = "ORB_CLI_METRICS_URL"
+
+	vctURLFlagName  = "vct-url"
+	vctURLFlagUsage = "The URL of the VCT log used by the node. If not set then the log health is not checked." +
+		" Alternatively, this can be set with the following environment variable: " + vctURLEnvKey
+	vctURLEnvKey = "ORB_CLI_VCT_URL"
+
+	outputFlagName  = "output"
+	outputFlagUsage = "The output format (text or json). Defaults to text." +
+		" Alternatively, this can be set with the following environment variable: " + outputEnvKey
+	outputEnvKey = "ORB_CLI_OUTPUT"
+)
+
+const (
+	outputText = "text"
+	outputJSON = "json"
+
+	vctSTHPath = "/v1/get-sth"
+)
+
+// Names of the Prometheus metrics that are included in the summary.
+const (
+	metricOpQueueBatchSize          = "orb_opqueue_batch_size"
+	metricOpQueueQueueSize          = "orb_opqueue_queue_size"
+	metricObserverAnchorLag         = "orb_observer_anchor_lag_seconds"
+	metricObserverProcessAnchorSum  = "orb_observer_process_anchor_seconds_sum"
+	metricObserverProcessAnchorCnt  = "orb_observer_process_anchor_seconds_count"
+	metricAnchorWriteCount          = "orb_anchor_write_seconds_count"
+	metricOutboxActivityCount       = "orb_activitypub_outbox_count"
+	metricInboxHandlerCount         = "orb_activitypub_inbox_handler_seconds_count"
+	metricOpQueueAddOperationCount  = "orb_opqueue_add_operation_seconds_count"
+	metricOpQueueBatchRollbackCount = "orb_opqueue_batch_rollback_seconds_count"
+)
+
+var collections = []string{"followers", "following", "witnesses", "witnessing"}
+
+// Status contains the aggregated status of an Orb node.
+type Status struct {
+	ServiceURL  string            `json:"serviceUrl"`
+	Collections map[string]int    `json:"collections,omitempty"`
+	Metrics     *MetricsSummary   `json:"metrics,omitempty"`
+	VCT         *VCTStatus        `json:"vct,omitempty"`
+	Errors      map[string]string `json:"errors,omitempty"`
+}
+
+// MetricsSummary contains a summary of selected metrics retrieved from the node's metrics endpoint.
+type MetricsSummary struct {
+	QueueSize                    float64 `json:"queueSize"`
+	OperationsAdded              float64 `json:"operationsAdded"`
+	LastBatchSize                float64 `json:"lastBatchSize"`
+	BatchRollbacks               float64 `json:"batchRollbacks"`
+	AnchorsWritten               float64 `json:"anchorsWritten"`
+	AnchorsObserved              float64 `json:"anchorsObserved"`
+	AvgObserverProcessingSeconds float64 `json:"avgObserverProcessingSeconds"`
+	ObserverLagSeconds           float64 `json:"observerLagSeconds"`
+	OutboxActivities             float64 `json:"outboxActivities"`
+	InboxActivities              float64 `json:"inboxActivities"`
+}
+
+// VCTStatus contains the health of the VCT log.
+type VCTStatus struct {
+	URL       string `json:"url"`
+	Healthy   bool   `json:"healthy"`
+	TreeSize  uint64 `json:"treeSize"`
+	Timestamp uint64 `json:"timestamp"`
+}
+
+type collectionResponse struct {
+	TotalItems int `json:"totalItems"`
+}
+
+type sthResponse struct {
+	TreeSize  uint64 `json:"tree_size"`
+	Timestamp uint64 `json:"timestamp"`
+}
+
+// GetCmd returns the Cobra status command.
+func GetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Displays a summary of the status of an Orb node.",
+		Long: "Aggregates metrics, queue statistics, observer processing time and lag, follower/witness counts and " +
+			"VCT log health from a node's endpoints into a single summary.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return executeStatus(cmd)
+		},
+	}
+
+	common.AddCommonFlags(cmd)
+
+	cmd.Flags().StringP(urlFlagName, "", "", urlFlagUsage)
+	cmd.Flags().StringP(metricsURLFlagName, "", "", metricsURLFlagUsage)
+	cmd.Flags().StringP(vctURLFlagName, "", "", vctURLFlagUsage)
+	cmd.Flags().StringP(outputFlagName, "", "", outputFlagUsage)
+
+	return cmd
+}
+
+func executeStatus(cmd *cobra.Command) error {
+	serviceURL, err := getURL(cmd, urlFlagName, urlEnvKey, false)
+	if err != nil {
+		return err
+	}
+
+	metricsURL, err := getURL(cmd, metricsURLFlagName, metricsURLEnvKey, true)
+	if err != nil {
+		return err
+	}
+
+	vctURL, err := getURL(cmd, vctURLFlagName, vctURLEnvKey, true)
+	if err != nil {
+		return err
+	}
+
+	output := cmdutils.GetUserSetOptionalVarFromString(cmd, outputFlagName, outputEnvKey)
+	if output == "" {
+		output = outputText
+	}
+
+	if output != outputText && output != outputJSON {
+		return fmt.Errorf("invalid output format [%s]: must be either %s or %s", output, outputText, outputJSON)
+	}
+
+	status := collectStatus(cmd, serviceURL, metricsURL, vctURL)
+
+	if output == outputJSON {
+		return writeJSON(os.Stdout, status)
+	}
+
+	return writeText(os.Stdout, status)
+}
+
+func getURL(cmd *cobra.Command, flagName, envKey string, optional bool) (string, error) {
+	u, err := cmdutils.GetUserSetVarFromString(cmd, flagName, envKey, optional)
+	if err != nil {
+		return "", err
+	}
+
+	if u == "" {
+		return "", nil
+	}
+
+	if _, err := url.Parse(u); err != nil {
+		return "", fmt.Errorf("invalid URL %s: %w", u, err)
+	}
+
+	return strings.TrimSuffix(u, "/"), nil
+}
+
+func collectStatus(cmd *cobra.Command, serviceURL, metricsURL, vctURL string) *Status {
+	status := &Status{
+		ServiceURL:  serviceURL,
+		Collections: make(map[string]int),
+		Errors:      make(map[string]string),
+	}
+
+	for _, name := range collections {
+		total, err := getCollectionTotal(cmd, fmt.Sprintf("%s/%s", serviceURL, name))
+		if err != nil {
+			status.Errors[name] = err.Error()
+
+			continue
+		}
+
+		status.Collections[name] = total
+	}
+
+	if metricsURL != "" {
+		m, err := getMetricsSummary(cmd, metricsURL)
+		if err != nil {
+			status.Errors["metrics"] = err.Error()
+		} else {
+			status.Metrics = m
+		}
+	}
+
+	if vctURL != "" {
+		status.VCT = getVCTStatus(cmd, vctURL, status.Errors)
+	}
+
+	return status
+}
+
+func getCollectionTotal(cmd *cobra.Command, collURL string) (int, error) {
+	respBytes, err := common.SendHTTPRequest(cmd, nil, http.MethodGet, collURL)
+	if err != nil {
+		return 0, err
+	}
+
+	coll := &collectionResponse{}
+
+	if err := json.Unmarshal(respBytes, coll); err != nil {
+		return 0, fmt.Errorf("unmarshal collection from %s: %w", collURL, err)
+	}
+
+	return coll.TotalItems, nil
+}
+
+func getMetricsSummary(cmd *cobra.Command, metricsURL string) (*MetricsSummary, error) {
+	respBytes, err := common.SendHTTPRequest(cmd, nil, http.MethodGet, metricsURL)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := parseMetrics(bytes.NewReader(respBytes))
+	if err != nil {
+		return nil, fmt.Errorf("parse metrics from %s: %w", metricsURL, err)
+	}
+
+	summary := &MetricsSummary{
+		QueueSize:          values[metricOpQueueQueueSize],
+		OperationsAdded:    values[metricOpQueueAddOperationCount],
+		LastBatchSize:      values[metricOpQueueBatchSize],
+		BatchRollbacks:     values[metricOpQueueBatchRollbackCount],
+		AnchorsWritten:     values[metricAnchorWriteCount],
+		AnchorsObserved:    values[metricObserverProcessAnchorCnt],
+		ObserverLagSeconds: values[metricObserverAnchorLag],
+		OutboxActivities:   values[metricOutboxActivityCount],
+		InboxActivities:    values[metricInboxHandlerCount],
+	}
+
+	if summary.AnchorsObserved > 0 {
+		summary.AvgObserverProcessingSeconds = values[metricObserverProcessAnchorSum] / summary.AnchorsObserved
+	}
+
+	return summary, nil
+}
+
+// parseMetrics parses the Prometheus text exposition format and returns the value of each metric. The values
+// of metrics with the same name but different labels are summed.
+func parseMetrics(r io.Reader) (map[string]float64, error) {
+	values := make(map[string]float64)
+
+	scanner := bufio.NewScanner(r)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name, value, ok := parseMetricLine(line)
+		if !ok {
+			continue
+		}
+
+		values[name] += value
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+func parseMetricLine(line string) (string, float64, bool) {
+	var name, rest string
+
+	if i := strings.Index(line, "{"); i >= 0 {
+		j := strings.LastIndex(line, "}")
+		if j < i {
+			return "", 0, false
+		}
+
+		name, rest = line[:i], line[j+1:]
+	} else {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			return "", 0, false
+		}
+
+		name, rest = fields[0], strings.Join(fields[1:], " ")
+	}
+
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return "", 0, false
+	}
+
+	value, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return "", 0, false
+	}
+
+	return name, value, true
+}
+
+func getVCTStatus(cmd *cobra.Command, vctURL string, errs map[string]string) *VCTStatus {
+	status := &VCTStatus{URL: vctURL}
+
+	respBytes, err := common.SendHTTPRequest(cmd, nil, http.MethodGet, vctURL+vctSTHPath)
+	if err != nil {
+		errs["vct"] = err.Error()
+
+		return status
+	}
+
+	sth := &sthResponse{}
+
+	if err := json.Unmarshal(respBytes, sth); err != nil {
+		errs["vct"] = fmt.Sprintf("unmarshal signed tree head: %s", err)
+
+		return status
+	}
+
+	status.Healthy = true
+	status.TreeSize = sth.TreeSize
+	status.Timestamp = sth.Timestamp
+
+	return status
+}
+
+func writeJSON(w io.Writer, status *Status) error {
+	statusBytes, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal status: %w", err)
+	}
+
+	_, err = fmt.Fprintln(w, string(statusBytes))
+
+	return err
+}
+
+func writeText(w io.Writer, status *Status) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Service:\t%s\n", status.ServiceURL)
+
+	fmt.Fprintln(tw, "\nCollections")
+
+	for _, name := range collections {
+		if total, ok := status.Collections[name]; ok {
+			fmt.Fprintf(tw, "  %s:\t%d\n", name, total)
+		} else {
+			fmt.Fprintf(tw, "  %s:\tunavailable\n", name)
+		}
+	}
+
+	if m := status.Metrics; m != nil {
+		fmt.Fprintln(tw, "\nMetrics")
+		fmt.Fprintf(tw, "  Operations in queue:\t%.0f\n", m.QueueSize)
+		fmt.Fprintf(tw, "  Operations added (total):\t%.0f\n", m.OperationsAdded)
+		fmt.Fprintf(tw, "  Last batch size:\t%.0f\n", m.LastBatchSize)
+		fmt.Fprintf(tw, "  Batch rollbacks:\t%.0f\n", m.BatchRollbacks)
+		fmt.Fprintf(tw, "  Anchors written:\t%.0f\n", m.AnchorsWritten)
+		fmt.Fprintf(tw, "  Anchors observed:\t%.0f\n", m.AnchorsObserved)
+		fmt.Fprintf(tw, "  Avg observer processing time:\t%.3fs\n", m.AvgObserverProcessingSeconds)
+		fmt.Fprintf(tw, "  Observer lag:\t%.3fs\n", m.ObserverLagSeconds)
+		fmt.Fprintf(tw, "  Outbox activities:\t%.0f\n", m.OutboxActivities)
+		fmt.Fprintf(tw, "  Inbox activities:\t%.0f\n", m.InboxActivities)
+	}
+
+	if v := status.VCT; v != nil {
+		fmt.Fprintln(tw, "\nVCT log")
+		fmt.Fprintf(tw, "  URL:\t%s\n", v.URL)
+		fmt.Fprintf(tw, "  Healthy:\t%t\n", v.Healthy)
+
+		if v.Healthy {
+			fmt.Fprintf(tw, "  Tree size:\t%d\n", v.TreeSize)
+		}
+	}
+
+	if len(status.Errors) > 0 {
+		fmt.Fprintln(tw, "\nErrors")
+
+		keys := make([]string, 0, len(status.Errors))
+		for k := range status.Errors {
+			keys = append(keys, k)
+		}
+
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			fmt.Fprintf(tw, "  %s:\t%s\n", k, status.Errors[k])
+		}
+	}
+
+	return tw.Flush()
+}
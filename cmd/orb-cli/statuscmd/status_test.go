@@ -0,0 +1,177 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statuscmd
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	flag = "--"
+
+	sampleMetrics = `# HELP orb_opqueue_batch_size The size of a cut batch.
+# TYPE orb_opqueue_batch_size gauge
+orb_opqueue_batch_size 12
+orb_opqueue_queue_size 7
+orb_opqueue_add_operation_seconds_count 150
+orb_observer_anchor_lag_seconds 2.5
+orb_observer_process_anchor_seconds_sum 4.5
+orb_observer_process_anchor_seconds_count 3
+orb_activitypub_outbox_count{type="Create"} 10
+orb_activitypub_outbox_count{type="Like"} 5
+orb_anchor_write_seconds_count 7 1633024800000
+invalid_line
+`
+)
+
+func TestStatusCmd(t *testing.T) {
+	t.Run("test missing url arg", func(t *testing.T) {
+		cmd := GetCmd()
+		cmd.SetArgs(nil)
+
+		err := cmd.Execute()
+
+		require.Error(t, err)
+		require.Equal(t,
+			"Neither url (command line flag) nor ORB_CLI_URL (environment variable) have been set.",
+			err.Error())
+	})
+
+	t.Run("test invalid url arg", func(t *testing.T) {
+		cmd := GetCmd()
+		cmd.SetArgs(urlArg(":invalid"))
+
+		err := cmd.Execute()
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid URL")
+	})
+
+	t.Run("test invalid output arg", func(t *testing.T) {
+		cmd := GetCmd()
+
+		args := urlArg("https://orb.domain1.com/services/orb")
+		args = append(args, outputArg("xml")...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid output format")
+	})
+
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/metrics":
+			_, err := fmt.Fprint(w, sampleMetrics)
+			require.NoError(t, err)
+		case r.URL.Path == vctSTHPath:
+			_, err := fmt.Fprint(w, `{"tree_size":25,"timestamp":1633024800000}`)
+			require.NoError(t, err)
+		case strings.HasSuffix(r.URL.Path, "/witnessing"):
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			_, err := fmt.Fprint(w, `{"type":"OrderedCollection","totalItems":3}`)
+			require.NoError(t, err)
+		}
+	}))
+	defer serv.Close()
+
+	for _, output := range []string{outputText, outputJSON} {
+		t.Run("success - "+output, func(t *testing.T) {
+			cmd := GetCmd()
+
+			args := urlArg(serv.URL + "/services/orb")
+			args = append(args, metricsURLArg(serv.URL+"/metrics")...)
+			args = append(args, vctURLArg(serv.URL)...)
+			args = append(args, outputArg(output)...)
+			cmd.SetArgs(args)
+
+			require.NoError(t, cmd.Execute())
+		})
+	}
+}
+
+func TestCollectStatus(t *testing.T) {
+	serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/metrics":
+			_, err := fmt.Fprint(w, sampleMetrics)
+			require.NoError(t, err)
+		case vctSTHPath:
+			_, err := fmt.Fprint(w, `{`)
+			require.NoError(t, err)
+		case "/services/orb/followers":
+			_, err := fmt.Fprint(w, `{"totalItems":2}`)
+			require.NoError(t, err)
+		case "/services/orb/following":
+			_, err := fmt.Fprint(w, `{"totalItems":1}`)
+			require.NoError(t, err)
+		case "/services/orb/witnesses":
+			_, err := fmt.Fprint(w, `invalid`)
+			require.NoError(t, err)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer serv.Close()
+
+	status := collectStatus(GetCmd(), serv.URL+"/services/orb", serv.URL+"/metrics", serv.URL)
+
+	require.Equal(t, 2, status.Collections["followers"])
+	require.Equal(t, 1, status.Collections["following"])
+	require.Contains(t, status.Errors["witnesses"], "unmarshal collection")
+	require.Contains(t, status.Errors["witnessing"], "status '404'")
+
+	require.NotNil(t, status.Metrics)
+	require.Equal(t, float64(12), status.Metrics.LastBatchSize)
+	require.Equal(t, float64(7), status.Metrics.QueueSize)
+	require.Equal(t, float64(150), status.Metrics.OperationsAdded)
+	require.Equal(t, float64(3), status.Metrics.AnchorsObserved)
+	require.Equal(t, 1.5, status.Metrics.AvgObserverProcessingSeconds)
+	require.Equal(t, 2.5, status.Metrics.ObserverLagSeconds)
+	require.Equal(t, float64(15), status.Metrics.OutboxActivities)
+	require.Equal(t, float64(7), status.Metrics.AnchorsWritten)
+
+	require.NotNil(t, status.VCT)
+	require.False(t, status.VCT.Healthy)
+	require.Contains(t, status.Errors["vct"], "unmarshal signed tree head")
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, writeText(buf, status))
+	require.Contains(t, buf.String(), "unavailable")
+	require.Contains(t, buf.String(), "Avg observer processing time:  1.500s")
+	require.Contains(t, buf.String(), "Operations in queue:")
+	require.Contains(t, buf.String(), "Observer lag:")
+
+	buf.Reset()
+	require.NoError(t, writeJSON(buf, status))
+	require.Contains(t, buf.String(), `"followers": 2`)
+}
+
+func urlArg(value string) []string {
+	return []string{flag + urlFlagName, value}
+}
+
+func metricsURLArg(value string) []string {
+	return []string{flag + metricsURLFlagName, value}
+}
+
+func vctURLArg(value string) []string {
+	return []string{flag + vctURLFlagName, value}
+}
+
+func outputArg(value string) []string {
+	return []string{flag + outputFlagName, value}
+}
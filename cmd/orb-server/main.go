@@ -24,6 +24,7 @@ func main() {
 	}
 
 	rootCmd.AddCommand(startcmd.GetStartCmd())
+	rootCmd.AddCommand(startcmd.GetMigrateCmd())
 
 	if err := rootCmd.Execute(); err != nil {
 		logger.Fatalf("Failed to run orb-rest: %s", err.Error())
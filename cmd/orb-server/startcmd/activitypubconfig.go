@@ -0,0 +1,53 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package startcmd
+
+import (
+	"github.com/trustbloc/orb/pkg/activitypub/service/inbox/fairqueue"
+	"github.com/trustbloc/orb/pkg/config/dynamic"
+	"github.com/trustbloc/orb/pkg/pubsub/redelivery"
+)
+
+const (
+	activityPubMaxRetriesParam     = "activitypub-max-retries"
+	activityPubInitialBackoffParam = "activitypub-initial-backoff"
+	activityPubMaxBackoffParam     = "activitypub-max-backoff"
+)
+
+// registerActivityPubParameters registers the retry policy of the ActivityPub outbox and the per-sender limit of
+// the inbox with the dynamic configuration and updates the components when a value changes.
+func registerActivityPubParameters(dynamicConfig *dynamic.Manager, retryCfg *redelivery.Config,
+	senderPolicy *fairqueue.StaticPolicy) error {
+	err := registerUintParameter(dynamicConfig, activityPubMaxRetriesParam,
+		"The maximum number of times that the delivery of an activity is retried.",
+		uint(retryCfg.MaxRetries), func(value uint) { retryCfg.SetMaxRetries(int(value)) })
+	if err != nil {
+		return err
+	}
+
+	err = registerDurationParameter(dynamicConfig, activityPubInitialBackoffParam,
+		"The first interval between the delivery retries of an activity.",
+		retryCfg.InitialBackoff, retryCfg.SetInitialBackoff)
+	if err != nil {
+		return err
+	}
+
+	err = registerDurationParameter(dynamicConfig, activityPubMaxBackoffParam,
+		"The maximum interval between the delivery retries of an activity.",
+		retryCfg.MaxBackoff, retryCfg.SetMaxBackoff)
+	if err != nil {
+		return err
+	}
+
+	if senderPolicy == nil {
+		return nil
+	}
+
+	return registerUintParameter(dynamicConfig, inboxSenderMaxInFlightFlagName,
+		"The maximum number of activities from a single sender that are processed concurrently by the inbox.",
+		uint(senderPolicy.DefaultLimit()), func(value uint) { senderPolicy.SetDefaultLimit(int(value)) })
+}
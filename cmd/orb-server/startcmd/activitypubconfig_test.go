@@ -0,0 +1,58 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package startcmd
+
+import (
+	"testing"
+	"time"
+
+	ariesmemstorage "github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/service/inbox/fairqueue"
+	"github.com/trustbloc/orb/pkg/config/dynamic"
+	"github.com/trustbloc/orb/pkg/pubsub/redelivery"
+)
+
+func TestRegisterActivityPubParameters(t *testing.T) {
+	configStore, err := ariesmemstorage.NewProvider().OpenStore("orb-config")
+	require.NoError(t, err)
+
+	dynamicConfig := dynamic.New(configStore)
+
+	retryCfg := redelivery.DefaultConfig()
+	senderPolicy := fairqueue.NewStaticPolicy(2, nil)
+
+	require.NoError(t, registerActivityPubParameters(dynamicConfig, retryCfg, senderPolicy))
+
+	value, err := dynamicConfig.Get(inboxSenderMaxInFlightFlagName)
+	require.NoError(t, err)
+	require.Equal(t, "2", value)
+
+	require.NoError(t, dynamicConfig.Update(map[string]string{
+		activityPubMaxRetriesParam:     "10",
+		activityPubInitialBackoffParam: "1s",
+		activityPubMaxBackoffParam:     "1m",
+		inboxSenderMaxInFlightFlagName: "5",
+	}))
+
+	require.Equal(t, 10, retryCfg.MaxRetries)
+	require.Equal(t, time.Second, retryCfg.InitialBackoff)
+	require.Equal(t, time.Minute, retryCfg.MaxBackoff)
+	require.Equal(t, 5, senderPolicy.MaxInFlight("https://domain1.com/services/orb"))
+
+	require.Error(t, dynamicConfig.Update(map[string]string{activityPubMaxRetriesParam: "-1"}))
+	require.Error(t, dynamicConfig.Update(map[string]string{activityPubMaxBackoffParam: "0s"}))
+
+	t.Run("No sender policy", func(t *testing.T) {
+		require.NoError(t, registerActivityPubParameters(dynamic.New(configStore), redelivery.DefaultConfig(), nil))
+	})
+
+	t.Run("Already registered", func(t *testing.T) {
+		require.Error(t, registerActivityPubParameters(dynamicConfig, retryCfg, senderPolicy))
+	})
+}
@@ -0,0 +1,175 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package startcmd
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+
+	"github.com/trustbloc/orb/pkg/config/dynamic"
+	"github.com/trustbloc/orb/pkg/context/cutoff"
+)
+
+const (
+	batchMaxOperationsFlagName  = "batch-max-operations"
+	batchMaxOperationsEnvKey    = "ORB_BATCH_MAX_OPERATIONS"
+	batchMaxOperationsFlagUsage = "The maximum number of operations in a batch. A batch is cut as soon as this " +
+		"number of operations is pending. The value may not exceed the maximum operation count of the " +
+		"protocol version. Defaults to 0, which means that the maximum operation count of the protocol version is used. " +
+		"This parameter may be updated at runtime. " + commonEnvVarUsageText + batchMaxOperationsEnvKey
+
+	batchMaxSizeFlagName  = "batch-max-size"
+	batchMaxSizeEnvKey    = "ORB_BATCH_MAX_SIZE"
+	batchMaxSizeFlagUsage = "The maximum total size (in bytes) of the operations in a batch. A batch is cut as soon " +
+		"as the pending operations reach this size. Defaults to 0, which means that the size of a batch is not limited. " +
+		"This parameter may be updated at runtime. " + commonEnvVarUsageText + batchMaxSizeEnvKey
+
+	batchMaxLatencyFlagName  = "batch-max-latency"
+	batchMaxLatencyEnvKey    = "ORB_BATCH_MAX_LATENCY"
+	batchMaxLatencyFlagUsage = "The maximum time that an operation waits to be included in a batch. For example, '5s' " +
+		"for a 5 second latency. The value should not exceed the batch writer timeout. Defaults to the batch writer " +
+		"timeout. This parameter may be updated at runtime. " + commonEnvVarUsageText + batchMaxLatencyEnvKey
+)
+
+// batchCutoffParameters contains the initial batch cut-off limits.
+type batchCutoffParameters struct {
+	maxOperations uint
+	maxSize       uint
+	maxLatency    time.Duration
+}
+
+func getBatchCutoffParameters(cmd *cobra.Command, batchWriterTimeout time.Duration) (*batchCutoffParameters, error) {
+	maxOperations, err := getUint(cmd, batchMaxOperationsFlagName, batchMaxOperationsEnvKey)
+	if err != nil {
+		return nil, err
+	}
+
+	maxSize, err := getUint(cmd, batchMaxSizeFlagName, batchMaxSizeEnvKey)
+	if err != nil {
+		return nil, err
+	}
+
+	maxLatency, err := getDuration(cmd, batchMaxLatencyFlagName, batchMaxLatencyEnvKey, batchWriterTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for parameter [%s]: %w", batchMaxLatencyFlagName, err)
+	}
+
+	if maxLatency <= 0 {
+		return nil, fmt.Errorf("value for parameter [%s] must be greater than 0", batchMaxLatencyFlagName)
+	}
+
+	return &batchCutoffParameters{
+		maxOperations: maxOperations,
+		maxSize:       maxSize,
+		maxLatency:    maxLatency,
+	}, nil
+}
+
+func getUint(cmd *cobra.Command, flagName, envKey string) (uint, error) {
+	valueStr, err := cmdutils.GetUserSetVarFromString(cmd, flagName, envKey, true)
+	if err != nil {
+		return 0, err
+	}
+
+	if valueStr == "" {
+		return 0, nil
+	}
+
+	value, err := strconv.ParseUint(valueStr, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value [%s] for parameter [%s]: %w", valueStr, flagName, err)
+	}
+
+	return uint(value), nil
+}
+
+func newBatchCutoffController(parameters *batchCutoffParameters) *cutoff.Controller {
+	return cutoff.New(cutoff.Limits{
+		MaxOperations: parameters.maxOperations,
+		MaxBatchSize:  parameters.maxSize,
+		MaxLatency:    parameters.maxLatency,
+	})
+}
+
+// registerBatchCutoffParameters registers the batch cut-off limits with the dynamic configuration
+// and updates the controller when a limit changes.
+func registerBatchCutoffParameters(dynamicConfig *dynamic.Manager, controller *cutoff.Controller) error {
+	limits := controller.Limits()
+
+	err := registerUintParameter(dynamicConfig, batchMaxOperationsFlagName,
+		"The maximum number of operations in a batch (0 for the protocol maximum).",
+		limits.MaxOperations, controller.SetMaxOperations)
+	if err != nil {
+		return err
+	}
+
+	err = registerUintParameter(dynamicConfig, batchMaxSizeFlagName,
+		"The maximum total size (in bytes) of the operations in a batch (0 for no limit).",
+		limits.MaxBatchSize, controller.SetMaxBatchSize)
+	if err != nil {
+		return err
+	}
+
+	err = dynamicConfig.Register(&dynamic.Parameter{
+		Name:         batchMaxLatencyFlagName,
+		Description:  "The maximum time that an operation waits to be included in a batch.",
+		DefaultValue: limits.MaxLatency.String(),
+		Validate:     dynamic.PositiveDuration,
+	})
+	if err != nil {
+		return fmt.Errorf("register parameter [%s]: %w", batchMaxLatencyFlagName, err)
+	}
+
+	err = dynamicConfig.Subscribe(batchMaxLatencyFlagName, func(value string) {
+		latency, e := time.ParseDuration(value)
+		if e != nil {
+			logger.Warnf("Invalid value for [%s]: %s", batchMaxLatencyFlagName, e)
+
+			return
+		}
+
+		controller.SetMaxLatency(latency)
+	})
+	if err != nil {
+		return fmt.Errorf("subscribe to parameter [%s]: %w", batchMaxLatencyFlagName, err)
+	}
+
+	return nil
+}
+
+func registerUintParameter(dynamicConfig *dynamic.Manager, name, description string, defaultValue uint,
+	set func(value uint)) error {
+	err := dynamicConfig.Register(&dynamic.Parameter{
+		Name:         name,
+		Description:  description,
+		DefaultValue: strconv.FormatUint(uint64(defaultValue), 10),
+		Validate:     dynamic.NonNegativeInt,
+	})
+	if err != nil {
+		return fmt.Errorf("register parameter [%s]: %w", name, err)
+	}
+
+	err = dynamicConfig.Subscribe(name, func(value string) {
+		n, e := strconv.ParseUint(value, 10, 32)
+		if e != nil {
+			logger.Warnf("Invalid value for [%s]: %s", name, e)
+
+			return
+		}
+
+		set(uint(n))
+	})
+	if err != nil {
+		return fmt.Errorf("subscribe to parameter [%s]: %w", name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package startcmd
+
+import (
+	"testing"
+	"time"
+
+	ariesmemstorage "github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/config/dynamic"
+	"github.com/trustbloc/orb/pkg/context/cutoff"
+)
+
+func TestGetBatchCutoffParameters(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		startCmd := GetStartCmd()
+		require.NoError(t, startCmd.ParseFlags(nil))
+
+		p, err := getBatchCutoffParameters(startCmd, time.Second)
+		require.NoError(t, err)
+		require.Zero(t, p.maxOperations)
+		require.Zero(t, p.maxSize)
+		require.Equal(t, time.Second, p.maxLatency)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		startCmd := GetStartCmd()
+		require.NoError(t, startCmd.ParseFlags([]string{
+			"--" + batchMaxOperationsFlagName, "100",
+			"--" + batchMaxSizeFlagName, "500000",
+			"--" + batchMaxLatencyFlagName, "500ms",
+		}))
+
+		p, err := getBatchCutoffParameters(startCmd, time.Second)
+		require.NoError(t, err)
+		require.Equal(t, uint(100), p.maxOperations)
+		require.Equal(t, uint(500000), p.maxSize)
+		require.Equal(t, 500*time.Millisecond, p.maxLatency)
+	})
+
+	t.Run("invalid max operations", func(t *testing.T) {
+		startCmd := GetStartCmd()
+		require.NoError(t, startCmd.ParseFlags([]string{"--" + batchMaxOperationsFlagName, "-1"}))
+
+		_, err := getBatchCutoffParameters(startCmd, time.Second)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value [-1] for parameter [batch-max-operations]")
+	})
+
+	t.Run("invalid max size", func(t *testing.T) {
+		startCmd := GetStartCmd()
+		require.NoError(t, startCmd.ParseFlags([]string{"--" + batchMaxSizeFlagName, "xxx"}))
+
+		_, err := getBatchCutoffParameters(startCmd, time.Second)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value [xxx] for parameter [batch-max-size]")
+	})
+
+	t.Run("invalid max latency", func(t *testing.T) {
+		startCmd := GetStartCmd()
+		require.NoError(t, startCmd.ParseFlags([]string{"--" + batchMaxLatencyFlagName, "xxx"}))
+
+		_, err := getBatchCutoffParameters(startCmd, time.Second)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for parameter [batch-max-latency]")
+
+		startCmd = GetStartCmd()
+		require.NoError(t, startCmd.ParseFlags([]string{"--" + batchMaxLatencyFlagName, "0s"}))
+
+		_, err = getBatchCutoffParameters(startCmd, time.Second)
+		require.EqualError(t, err, "value for parameter [batch-max-latency] must be greater than 0")
+	})
+}
+
+func TestRegisterBatchCutoffParameters(t *testing.T) {
+	configStore, err := ariesmemstorage.NewProvider().OpenStore("orb-config")
+	require.NoError(t, err)
+
+	dynamicConfig := dynamic.New(configStore)
+
+	controller := newBatchCutoffController(&batchCutoffParameters{
+		maxOperations: 100,
+		maxLatency:    time.Second,
+	})
+
+	require.NoError(t, registerBatchCutoffParameters(dynamicConfig, controller))
+
+	value, err := dynamicConfig.Get(batchMaxOperationsFlagName)
+	require.NoError(t, err)
+	require.Equal(t, "100", value)
+
+	require.NoError(t, dynamicConfig.Update(map[string]string{
+		batchMaxOperationsFlagName: "50",
+		batchMaxSizeFlagName:       "1000",
+		batchMaxLatencyFlagName:    "2s",
+	}))
+
+	require.Equal(t, cutoff.Limits{
+		MaxOperations: 50,
+		MaxBatchSize:  1000,
+		MaxLatency:    2 * time.Second,
+	}, controller.Limits())
+
+	require.Error(t, dynamicConfig.Update(map[string]string{batchMaxSizeFlagName: "-1"}))
+	require.Error(t, dynamicConfig.Update(map[string]string{batchMaxLatencyFlagName: "0s"}))
+
+	t.Run("already registered", func(t *testing.T) {
+		require.Error(t, registerBatchCutoffParameters(dynamicConfig, controller))
+	})
+}
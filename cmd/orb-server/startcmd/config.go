@@ -0,0 +1,179 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package startcmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	configFileFlagName  = "config"
+	configFileEnvKey    = "ORB_CONFIG_FILE"
+	configFileFlagUsage = "The path to a YAML configuration file. The keys in the file are the names of the command-line " +
+		"flags (for example, host-url or database-type) and the values are either strings, numbers, booleans " +
+		"or lists (for flags that accept multiple values). A value in the file is only used if neither the " +
+		"command-line flag nor the corresponding environment variable is set. " +
+		commonEnvVarUsageText + configFileEnvKey
+
+	validateConfigFlagName  = "validate-config"
+	validateConfigEnvKey    = "ORB_VALIDATE_CONFIG"
+	validateConfigFlagUsage = `Set to "true" to validate the configuration (flags, environment variables and ` +
+		`configuration file) and exit without starting the server. ` + commonEnvVarUsageText + validateConfigEnvKey
+
+	envVarUsageMarker = "environment variable: "
+)
+
+// loadConfigFile loads the YAML configuration file (if specified) and sets the value of each flag that
+// was not set on the command-line or with an environment variable. The precedence is therefore:
+// command-line flag, environment variable, configuration file and finally the flag's default value.
+func loadConfigFile(cmd *cobra.Command) error {
+	configFile := cmdutils.GetUserSetOptionalVarFromString(cmd, configFileFlagName, configFileEnvKey)
+	if configFile == "" {
+		return nil
+	}
+
+	configBytes, err := ioutil.ReadFile(filepath.Clean(configFile))
+	if err != nil {
+		return fmt.Errorf("read configuration file [%s]: %w", configFile, err)
+	}
+
+	values := make(map[string]interface{})
+
+	if err := yaml.Unmarshal(configBytes, &values); err != nil {
+		return fmt.Errorf("parse configuration file [%s]: %w", configFile, err)
+	}
+
+	return applyConfig(cmd, values)
+}
+
+func getValidateConfig(cmd *cobra.Command) (bool, error) {
+	validateConfigStr := cmdutils.GetUserSetOptionalVarFromString(cmd, validateConfigFlagName, validateConfigEnvKey)
+	if validateConfigStr == "" {
+		return false, nil
+	}
+
+	validateConfig, err := strconv.ParseBool(validateConfigStr)
+	if err != nil {
+		return false, fmt.Errorf("invalid value for %s: %w", validateConfigFlagName, err)
+	}
+
+	return validateConfig, nil
+}
+
+func applyConfig(cmd *cobra.Command, values map[string]interface{}) error {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	var errs []string
+
+	for _, key := range keys {
+		if err := applyConfigValue(cmd, key, values[key]); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration file: %s", strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+func applyConfigValue(cmd *cobra.Command, key string, value interface{}) error {
+	if key == configFileFlagName || key == validateConfigFlagName {
+		return fmt.Errorf("[%s] may not be set in the configuration file", key)
+	}
+
+	flag := cmd.Flags().Lookup(key)
+	if flag == nil {
+		return fmt.Errorf("unknown parameter [%s]", key)
+	}
+
+	strValues, err := toStrings(value, flag.Value.Type() == "stringArray")
+	if err != nil {
+		return fmt.Errorf("invalid value for [%s]: %w", key, err)
+	}
+
+	if flag.Changed {
+		logger.Debugf("Ignoring [%s] in configuration file since the flag was set on the command-line.", key)
+
+		return nil
+	}
+
+	if envKey := envKeyFromUsage(flag); envKey != "" {
+		if _, ok := os.LookupEnv(envKey); ok {
+			logger.Debugf("Ignoring [%s] in configuration file since environment variable [%s] is set.", key, envKey)
+
+			return nil
+		}
+	}
+
+	for _, v := range strValues {
+		if err := cmd.Flags().Set(key, v); err != nil {
+			return fmt.Errorf("set [%s]: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+func toStrings(value interface{}, multiValued bool) ([]string, error) {
+	switch v := value.(type) {
+	case string, bool, int, int64, uint64, float64:
+		return []string{fmt.Sprint(v)}, nil
+	case []interface{}:
+		if !multiValued {
+			return nil, fmt.Errorf("a list is not supported")
+		}
+
+		values := make([]string, len(v))
+
+		for i, item := range v {
+			itemValues, err := toStrings(item, false)
+			if err != nil {
+				return nil, err
+			}
+
+			values[i] = itemValues[0]
+		}
+
+		return values, nil
+	default:
+		return nil, fmt.Errorf("unsupported type %T", value)
+	}
+}
+
+// envKeyFromUsage returns the environment variable for the given flag. All flags document the
+// environment variable at the end of the usage text.
+func envKeyFromUsage(flag *pflag.Flag) string {
+	i := strings.LastIndex(flag.Usage, envVarUsageMarker)
+	if i < 0 {
+		return ""
+	}
+
+	fields := strings.Fields(flag.Usage[i+len(envVarUsageMarker):])
+	if len(fields) == 0 {
+		return ""
+	}
+
+	return fields[0]
+}
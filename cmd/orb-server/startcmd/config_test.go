@@ -0,0 +1,180 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package startcmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfigFile(t *testing.T) {
+	t.Run("no config file", func(t *testing.T) {
+		cmd := getTestCmd(t)
+
+		require.NoError(t, loadConfigFile(cmd))
+	})
+
+	t.Run("success", func(t *testing.T) {
+		configFile := writeConfigFile(t, `
+host-url: localhost:8080
+did-namespace: did:orb
+batch-writer-timeout: 700
+allowed-origins:
+  - https://orb.domain1.com
+  - https://orb.domain2.com
+`)
+
+		cmd := getTestCmd(t, "--"+configFileFlagName, configFile)
+
+		require.NoError(t, loadConfigFile(cmd))
+
+		requireFlagValue(t, cmd, hostURLFlagName, "localhost:8080")
+		requireFlagValue(t, cmd, didNamespaceFlagName, "did:orb")
+		requireFlagValue(t, cmd, batchWriterTimeoutFlagName, "700")
+
+		allowedOrigins, err := cmd.Flags().GetStringArray(allowedOriginsFlagName)
+		require.NoError(t, err)
+		require.Equal(t, []string{"https://orb.domain1.com", "https://orb.domain2.com"}, allowedOrigins)
+	})
+
+	t.Run("flag and environment variable take precedence", func(t *testing.T) {
+		configFile := writeConfigFile(t, `
+host-url: localhost:8080
+did-namespace: did:orb
+`)
+
+		restore := setEnv(t, didNamespaceEnvKey, "did:env")
+		defer restore()
+
+		cmd := getTestCmd(t, "--"+configFileFlagName, configFile, "--"+hostURLFlagName, "localhost:9090")
+
+		require.NoError(t, loadConfigFile(cmd))
+
+		requireFlagValue(t, cmd, hostURLFlagName, "localhost:9090")
+
+		// The flag is left unset so that the environment variable is used.
+		requireFlagValue(t, cmd, didNamespaceFlagName, "")
+	})
+
+	t.Run("config file from environment variable", func(t *testing.T) {
+		configFile := writeConfigFile(t, `host-url: localhost:8080`)
+
+		restore := setEnv(t, configFileEnvKey, configFile)
+		defer restore()
+
+		cmd := getTestCmd(t)
+
+		require.NoError(t, loadConfigFile(cmd))
+
+		requireFlagValue(t, cmd, hostURLFlagName, "localhost:8080")
+	})
+
+	t.Run("file not found", func(t *testing.T) {
+		cmd := getTestCmd(t, "--"+configFileFlagName, filepath.Join(t.TempDir(), "missing.yaml"))
+
+		err := loadConfigFile(cmd)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "read configuration file")
+	})
+
+	t.Run("invalid YAML", func(t *testing.T) {
+		cmd := getTestCmd(t, "--"+configFileFlagName, writeConfigFile(t, `host-url: [`))
+
+		err := loadConfigFile(cmd)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "parse configuration file")
+	})
+
+	t.Run("invalid configuration", func(t *testing.T) {
+		configFile := writeConfigFile(t, `
+unknown-param: value
+host-url:
+  - localhost:8080
+config: other.yaml
+enable-dev-mode:
+  key: value
+`)
+
+		cmd := getTestCmd(t, "--"+configFileFlagName, configFile)
+
+		err := loadConfigFile(cmd)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid configuration file")
+		require.Contains(t, err.Error(), "[config] may not be set in the configuration file")
+		require.Contains(t, err.Error(), "invalid value for [enable-dev-mode]: unsupported type")
+		require.Contains(t, err.Error(), "invalid value for [host-url]: a list is not supported")
+		require.Contains(t, err.Error(), "unknown parameter [unknown-param]")
+	})
+}
+
+func TestGetValidateConfig(t *testing.T) {
+	t.Run("not set", func(t *testing.T) {
+		validate, err := getValidateConfig(getTestCmd(t))
+		require.NoError(t, err)
+		require.False(t, validate)
+	})
+
+	t.Run("set", func(t *testing.T) {
+		validate, err := getValidateConfig(getTestCmd(t, "--"+validateConfigFlagName, "true"))
+		require.NoError(t, err)
+		require.True(t, validate)
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		_, err := getValidateConfig(getTestCmd(t, "--"+validateConfigFlagName, "xxx"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for validate-config")
+	})
+}
+
+func TestStartCmdValidateConfig(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		startCmd := GetStartCmd()
+
+		startCmd.SetArgs(append(getTestArgs("localhost:8081", "local", "false", databaseTypeMemOption, ""),
+			"--"+validateConfigFlagName, "true"))
+
+		require.NoError(t, startCmd.Execute())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		configFile := writeConfigFile(t, `cas-type: xxx`)
+
+		startCmd := GetStartCmd()
+
+		startCmd.SetArgs([]string{
+			"--" + hostURLFlagName, "localhost:8247",
+			"--" + configFileFlagName, configFile,
+			"--" + validateConfigFlagName, "true",
+		})
+
+		require.Error(t, startCmd.Execute())
+	})
+}
+
+func writeConfigFile(t *testing.T, contents string) string {
+	t.Helper()
+
+	configFile := filepath.Join(t.TempDir(), "orb-config.yaml")
+
+	require.NoError(t, ioutil.WriteFile(configFile, []byte(contents), os.ModePerm))
+
+	return configFile
+}
+
+func requireFlagValue(t *testing.T, cmd *cobra.Command, name, expected string) {
+	t.Helper()
+
+	flag := cmd.Flags().Lookup(name)
+	require.NotNil(t, flag)
+	require.Equal(t, expected, flag.Value.String())
+}
@@ -0,0 +1,195 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package startcmd
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+
+	"github.com/trustbloc/orb/pkg/httpserver"
+)
+
+const (
+	corsAllowedOriginsFlagUsage = "The origins from which browsers may make cross-origin " +
+		"requests to the %s endpoints. '*' allows all origins and 'none' disallows all cross-origin requests. " +
+		"Defaults to '*'. This flag can be repeated, allowing multiple origins. " + commonEnvVarUsageText + "%s"
+	corsAllowedHeadersFlagUsage = "The headers that may be used in cross-origin requests " +
+		"to the %s endpoints. Defaults to '*' (all headers). " + commonEnvVarUsageText + "%s"
+	corsMaxAgeFlagUsage = "The amount of time for which browsers may cache the results of a CORS preflight " +
+		"request to the %s endpoints, for example 10m. If not set then the Access-Control-Max-Age header isn't " +
+		"sent. " + commonEnvVarUsageText + "%s"
+
+	corsResolutionAllowedOriginsFlagName = "cors-resolution-allowed-origins"
+	corsResolutionAllowedOriginsEnvKey   = "CORS_RESOLUTION_ALLOWED_ORIGINS"
+	corsResolutionAllowedHeadersFlagName = "cors-resolution-allowed-headers"
+	corsResolutionAllowedHeadersEnvKey   = "CORS_RESOLUTION_ALLOWED_HEADERS"
+	corsResolutionMaxAgeFlagName         = "cors-resolution-max-age"
+	corsResolutionMaxAgeEnvKey           = "CORS_RESOLUTION_MAX_AGE"
+
+	corsFederationAllowedOriginsFlagName = "cors-federation-allowed-origins"
+	corsFederationAllowedOriginsEnvKey   = "CORS_FEDERATION_ALLOWED_ORIGINS"
+	corsFederationAllowedHeadersFlagName = "cors-federation-allowed-headers"
+	corsFederationAllowedHeadersEnvKey   = "CORS_FEDERATION_ALLOWED_HEADERS"
+	corsFederationMaxAgeFlagName         = "cors-federation-max-age"
+	corsFederationMaxAgeEnvKey           = "CORS_FEDERATION_MAX_AGE"
+
+	corsAdminAllowedOriginsFlagName = "cors-admin-allowed-origins"
+	corsAdminAllowedOriginsEnvKey   = "CORS_ADMIN_ALLOWED_ORIGINS"
+	corsAdminAllowedHeadersFlagName = "cors-admin-allowed-headers"
+	corsAdminAllowedHeadersEnvKey   = "CORS_ADMIN_ALLOWED_HEADERS"
+	corsAdminMaxAgeFlagName         = "cors-admin-max-age"
+	corsAdminMaxAgeEnvKey           = "CORS_ADMIN_MAX_AGE"
+
+	// corsNoOrigins disallows all cross-origin requests to an endpoint group.
+	corsNoOrigins  = "none"
+	corsAllHeaders = "*"
+)
+
+// corsGroup defines an endpoint group which has its own CORS policy.
+type corsGroup struct {
+	name                 string
+	description          string
+	allowedOriginsFlag   string
+	allowedOriginsEnvKey string
+	allowedHeadersFlag   string
+	allowedHeadersEnvKey string
+	maxAgeFlag           string
+	maxAgeEnvKey         string
+	pathExpressions      []string
+}
+
+// corsGroups returns the endpoint groups in the order in which they're matched against the path of a request.
+// The admin group matches all endpoints that aren't in the resolution or federation groups.
+func corsGroups() []*corsGroup {
+	return []*corsGroup{
+		{
+			name:                 "resolution",
+			description:          "DID resolution and operation (/sidetree/v1, /1.0/identifiers) and explorer",
+			allowedOriginsFlag:   corsResolutionAllowedOriginsFlagName,
+			allowedOriginsEnvKey: corsResolutionAllowedOriginsEnvKey,
+			allowedHeadersFlag:   corsResolutionAllowedHeadersFlagName,
+			allowedHeadersEnvKey: corsResolutionAllowedHeadersEnvKey,
+			maxAgeFlag:           corsResolutionMaxAgeFlagName,
+			maxAgeEnvKey:         corsResolutionMaxAgeEnvKey,
+			pathExpressions:      []string{"^" + basePath + "/", `^/1\.0/identifiers(/|$)`, "^/explorer/"},
+		},
+		{
+			name:                 "federation",
+			description:          "ActivityPub (/services), CAS (/cas), /.well-known and NodeInfo",
+			allowedOriginsFlag:   corsFederationAllowedOriginsFlagName,
+			allowedOriginsEnvKey: corsFederationAllowedOriginsEnvKey,
+			allowedHeadersFlag:   corsFederationAllowedHeadersFlagName,
+			allowedHeadersEnvKey: corsFederationAllowedHeadersEnvKey,
+			maxAgeFlag:           corsFederationMaxAgeFlagName,
+			maxAgeEnvKey:         corsFederationMaxAgeEnvKey,
+			pathExpressions:      []string{"^/services/", "^" + casPath + "(/|$)", `^/\.well-known/`, "^/nodeinfo"},
+		},
+		{
+			name:                 "admin",
+			description:          "administrative (all other)",
+			allowedOriginsFlag:   corsAdminAllowedOriginsFlagName,
+			allowedOriginsEnvKey: corsAdminAllowedOriginsEnvKey,
+			allowedHeadersFlag:   corsAdminAllowedHeadersFlagName,
+			allowedHeadersEnvKey: corsAdminAllowedHeadersEnvKey,
+			maxAgeFlag:           corsAdminMaxAgeFlagName,
+			maxAgeEnvKey:         corsAdminMaxAgeEnvKey,
+			pathExpressions:      []string{"^/"},
+		},
+	}
+}
+
+func getCORSPolicies(cmd *cobra.Command) ([]*httpserver.CORSPolicy, error) {
+	var policies []*httpserver.CORSPolicy
+
+	for _, group := range corsGroups() {
+		policy, err := getCORSPolicy(cmd, group)
+		if err != nil {
+			return nil, err
+		}
+
+		policies = append(policies, policy)
+	}
+
+	return policies, nil
+}
+
+func getCORSPolicy(cmd *cobra.Command, group *corsGroup) (*httpserver.CORSPolicy, error) {
+	allowedOrigins, err := cmdutils.GetUserSetVarFromArrayString(cmd, group.allowedOriginsFlag,
+		group.allowedOriginsEnvKey, true)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", group.allowedOriginsFlag, err)
+	}
+
+	switch {
+	case len(allowedOrigins) == 0:
+		allowedOrigins = []string{httpserver.AllowAllOrigins}
+	case contains(allowedOrigins, corsNoOrigins):
+		if len(allowedOrigins) > 1 {
+			return nil, fmt.Errorf("%s: '%s' may not be combined with other origins",
+				group.allowedOriginsFlag, corsNoOrigins)
+		}
+
+		allowedOrigins = nil
+	}
+
+	allowedHeaders, err := cmdutils.GetUserSetVarFromArrayString(cmd, group.allowedHeadersFlag,
+		group.allowedHeadersEnvKey, true)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", group.allowedHeadersFlag, err)
+	}
+
+	if len(allowedHeaders) == 0 {
+		allowedHeaders = []string{corsAllHeaders}
+	}
+
+	maxAge, err := getDuration(cmd, group.maxAgeFlag, group.maxAgeEnvKey, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", group.maxAgeFlag, err)
+	}
+
+	if maxAge < 0 {
+		return nil, fmt.Errorf("%s: value must not be negative", group.maxAgeFlag)
+	}
+
+	pathExpressions := make([]*regexp.Regexp, len(group.pathExpressions))
+
+	for i, expr := range group.pathExpressions {
+		pathExpressions[i] = regexp.MustCompile(expr)
+	}
+
+	return &httpserver.CORSPolicy{
+		Name:            group.name,
+		PathExpressions: pathExpressions,
+		AllowedOrigins:  allowedOrigins,
+		AllowedHeaders:  allowedHeaders,
+		MaxAge:          maxAge,
+	}, nil
+}
+
+func createCORSFlags(startCmd *cobra.Command) {
+	for _, group := range corsGroups() {
+		startCmd.Flags().StringArrayP(group.allowedOriginsFlag, "", []string{},
+			fmt.Sprintf(corsAllowedOriginsFlagUsage, group.description, group.allowedOriginsEnvKey))
+		startCmd.Flags().StringArrayP(group.allowedHeadersFlag, "", []string{},
+			fmt.Sprintf(corsAllowedHeadersFlagUsage, group.description, group.allowedHeadersEnvKey))
+		startCmd.Flags().StringP(group.maxAgeFlag, "", "",
+			fmt.Sprintf(corsMaxAgeFlagUsage, group.description, group.maxAgeEnvKey))
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,120 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package startcmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/httpserver"
+)
+
+func TestGetCORSPolicies(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		startCmd := GetStartCmd()
+		require.NoError(t, startCmd.ParseFlags(nil))
+
+		policies, err := getCORSPolicies(startCmd)
+		require.NoError(t, err)
+		require.Len(t, policies, 3)
+
+		for _, policy := range policies {
+			require.Equal(t, []string{httpserver.AllowAllOrigins}, policy.AllowedOrigins)
+			require.Equal(t, []string{"*"}, policy.AllowedHeaders)
+			require.Zero(t, policy.MaxAge)
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		startCmd := GetStartCmd()
+		require.NoError(t, startCmd.ParseFlags([]string{
+			"--" + corsResolutionAllowedOriginsFlagName, "https://explorer.example.com",
+			"--" + corsResolutionAllowedHeadersFlagName, "Accept",
+			"--" + corsResolutionAllowedHeadersFlagName, "Content-Type",
+			"--" + corsResolutionMaxAgeFlagName, "10m",
+			"--" + corsAdminAllowedOriginsFlagName, "none",
+		}))
+
+		policies, err := getCORSPolicies(startCmd)
+		require.NoError(t, err)
+		require.Len(t, policies, 3)
+
+		resolution := policies[0]
+		require.Equal(t, "resolution", resolution.Name)
+		require.Equal(t, []string{"https://explorer.example.com"}, resolution.AllowedOrigins)
+		require.Equal(t, []string{"Accept", "Content-Type"}, resolution.AllowedHeaders)
+		require.Equal(t, 10*time.Minute, resolution.MaxAge)
+
+		federation := policies[1]
+		require.Equal(t, "federation", federation.Name)
+		require.Equal(t, []string{httpserver.AllowAllOrigins}, federation.AllowedOrigins)
+
+		admin := policies[2]
+		require.Equal(t, "admin", admin.Name)
+		require.Empty(t, admin.AllowedOrigins)
+	})
+
+	t.Run("endpoint groups", func(t *testing.T) {
+		startCmd := GetStartCmd()
+		require.NoError(t, startCmd.ParseFlags(nil))
+
+		policies, err := getCORSPolicies(startCmd)
+		require.NoError(t, err)
+
+		groupOf := func(path string) string {
+			for _, policy := range policies {
+				for _, expr := range policy.PathExpressions {
+					if expr.MatchString(path) {
+						return policy.Name
+					}
+				}
+			}
+
+			return ""
+		}
+
+		require.Equal(t, "resolution", groupOf("/sidetree/v1/identifiers/did:orb:uAAA:123"))
+		require.Equal(t, "resolution", groupOf("/1.0/identifiers/did:orb:uAAA:123"))
+		require.Equal(t, "federation", groupOf("/services/orb/outbox"))
+		require.Equal(t, "federation", groupOf("/cas/uEiABC"))
+		require.Equal(t, "federation", groupOf("/.well-known/webfinger"))
+		require.Equal(t, "admin", groupOf("/policy"))
+		require.Equal(t, "admin", groupOf("/castle"))
+	})
+
+	t.Run("none combined with other origins", func(t *testing.T) {
+		startCmd := GetStartCmd()
+		require.NoError(t, startCmd.ParseFlags([]string{
+			"--" + corsAdminAllowedOriginsFlagName, "none",
+			"--" + corsAdminAllowedOriginsFlagName, "https://explorer.example.com",
+		}))
+
+		_, err := getCORSPolicies(startCmd)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "'none' may not be combined with other origins")
+	})
+
+	t.Run("invalid max age", func(t *testing.T) {
+		startCmd := GetStartCmd()
+		require.NoError(t, startCmd.ParseFlags([]string{"--" + corsFederationMaxAgeFlagName, "xxx"}))
+
+		_, err := getCORSPolicies(startCmd)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "cors-federation-max-age")
+	})
+
+	t.Run("negative max age", func(t *testing.T) {
+		startCmd := GetStartCmd()
+		require.NoError(t, startCmd.ParseFlags([]string{"--" + corsFederationMaxAgeFlagName, "-1m"}))
+
+		_, err := getCORSPolicies(startCmd)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "value must not be negative")
+	})
+}
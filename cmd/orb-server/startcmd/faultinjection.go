@@ -0,0 +1,76 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package startcmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+
+	"github.com/trustbloc/orb/pkg/faultinjection"
+)
+
+const (
+	faultInjectionFlagName  = "fault-injection"
+	faultInjectionEnvKey    = "FAULT_INJECTION"
+	faultInjectionFlagUsage = "The initial fault-injection configuration, which injects latency, errors and " +
+		`partial failures into the store, CAS and ActivityPub transport layers, for example ` +
+		`{"store":{"latency":"50ms","errorRate":0.1},"transport":{"partialFailureRate":0.2}}. ` +
+		"The configuration may be changed at runtime using the /faultinjection endpoint. " +
+		"Only supported by servers built with the 'faultinjection' build tag, which must not be used in production. " +
+		commonEnvVarUsageText + faultInjectionEnvKey
+)
+
+func getFaultInjectionConfig(cmd *cobra.Command) (faultinjection.Config, error) {
+	cfgStr := cmdutils.GetUserSetOptionalVarFromString(cmd, faultInjectionFlagName, faultInjectionEnvKey)
+	if cfgStr == "" {
+		return nil, nil
+	}
+
+	if !faultInjectionSupported {
+		return nil, fmt.Errorf("%s is not supported by this build of the server", faultInjectionFlagName)
+	}
+
+	cfg, err := faultinjection.ParseConfig([]byte(cfgStr))
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for %s: %w", faultInjectionFlagName, err)
+	}
+
+	return cfg, nil
+}
+
+// newFaultInjector returns a fault injector if the server was built with fault-injection support, otherwise nil.
+// The injector is always created in such a build (even if no faults are configured) so that faults may be
+// enabled using the admin endpoint.
+func newFaultInjector(parameters *orbParameters) (*faultinjection.Injector, error) {
+	if !faultInjectionSupported {
+		return nil, nil
+	}
+
+	logger.Warnf("Fault injection is supported by this build of the server, which must not be used in production.")
+
+	injector, err := faultinjection.New(parameters.faultInjection)
+	if err != nil {
+		return nil, fmt.Errorf("create fault injector: %w", err)
+	}
+
+	return injector, nil
+}
+
+// withFaultInjector returns a copy of the storage providers whose main provider injects faults into
+// store operations.
+func (s *storageProviders) withFaultInjector(injector *faultinjection.Injector) *storageProviders {
+	providers := *s
+
+	providers.provider = &storageProvider{
+		Provider: faultinjection.NewProvider(s.provider.Provider, injector),
+		dbType:   s.provider.dbType,
+	}
+
+	return &providers
+}
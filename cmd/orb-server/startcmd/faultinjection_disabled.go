@@ -0,0 +1,14 @@
+//go:build !faultinjection
+// +build !faultinjection
+
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package startcmd
+
+// faultInjectionSupported is false since fault injection is only supported by servers built with
+// the 'faultinjection' tag.
+const faultInjectionSupported = false
@@ -0,0 +1,13 @@
+//go:build faultinjection
+// +build faultinjection
+
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package startcmd
+
+// faultInjectionSupported is true since the server was built with the 'faultinjection' tag.
+const faultInjectionSupported = true
@@ -0,0 +1,85 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package startcmd
+
+import (
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/faultinjection"
+)
+
+func TestGetFaultInjectionConfig(t *testing.T) {
+	t.Run("Not specified -> nil", func(t *testing.T) {
+		cfg, err := getFaultInjectionConfig(getTestCmd(t))
+		require.NoError(t, err)
+		require.Nil(t, cfg)
+	})
+
+	t.Run("Valid env value", func(t *testing.T) {
+		restoreEnv := setEnv(t, faultInjectionEnvKey, `{"store":{"errorRate":0.1}}`)
+		defer restoreEnv()
+
+		cfg, err := getFaultInjectionConfig(getTestCmd(t))
+		if !faultInjectionSupported {
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "fault-injection is not supported by this build of the server")
+
+			return
+		}
+
+		require.NoError(t, err)
+		require.Equal(t, 0.1, cfg[faultinjection.TargetStore].ErrorRate)
+	})
+
+	t.Run("Invalid value -> error", func(t *testing.T) {
+		if !faultInjectionSupported {
+			t.Skip("fault injection isn't supported by this build")
+		}
+
+		restoreEnv := setEnv(t, faultInjectionEnvKey, `{"store":{"errorRate":2}}`)
+		defer restoreEnv()
+
+		_, err := getFaultInjectionConfig(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for fault-injection")
+	})
+}
+
+func TestNewFaultInjector(t *testing.T) {
+	injector, err := newFaultInjector(&orbParameters{})
+	require.NoError(t, err)
+
+	if !faultInjectionSupported {
+		require.Nil(t, injector)
+
+		return
+	}
+
+	require.NotNil(t, injector)
+	require.Empty(t, injector.Config())
+}
+
+func TestStorageProviders_WithFaultInjector(t *testing.T) {
+	injector, err := faultinjection.New(nil)
+	require.NoError(t, err)
+
+	providers := &storageProviders{
+		provider: &storageProvider{Provider: mem.NewProvider(), dbType: databaseTypeMemOption},
+	}
+
+	p := providers.withFaultInjector(injector)
+	require.Equal(t, databaseTypeMemOption, p.provider.dbType)
+
+	_, ok := p.provider.Provider.(*faultinjection.Provider)
+	require.True(t, ok)
+
+	_, ok = providers.provider.Provider.(*faultinjection.Provider)
+	require.False(t, ok)
+}
@@ -0,0 +1,316 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package startcmd
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+
+	"github.com/trustbloc/orb/pkg/activitypub/client/transport"
+	"github.com/trustbloc/orb/pkg/circuitbreaker"
+	"github.com/trustbloc/orb/pkg/faultinjection"
+	"github.com/trustbloc/orb/pkg/metrics"
+)
+
+const (
+	httpMaxIdleConnsFlagName  = "http-max-idle-conns"
+	httpMaxIdleConnsEnvKey    = "HTTP_MAX_IDLE_CONNS"
+	httpMaxIdleConnsFlagUsage = "The maximum number of idle (keep-alive) connections across all hosts for " +
+		"outbound HTTP requests. Defaults to 2000. " + commonEnvVarUsageText + httpMaxIdleConnsEnvKey
+
+	httpMaxIdleConnsPerHostFlagName  = "http-max-idle-conns-per-host"
+	httpMaxIdleConnsPerHostEnvKey    = "HTTP_MAX_IDLE_CONNS_PER_HOST"
+	httpMaxIdleConnsPerHostFlagUsage = "The maximum number of idle (keep-alive) connections per host for " +
+		"outbound HTTP requests. Defaults to 100. " + commonEnvVarUsageText + httpMaxIdleConnsPerHostEnvKey
+
+	httpMaxConnsPerHostFlagName  = "http-max-conns-per-host"
+	httpMaxConnsPerHostEnvKey    = "HTTP_MAX_CONNS_PER_HOST"
+	httpMaxConnsPerHostFlagUsage = "The maximum number of connections (dialing, active and idle) per host for " +
+		"outbound HTTP requests. Defaults to 100. " + commonEnvVarUsageText + httpMaxConnsPerHostEnvKey
+
+	httpIdleConnTimeoutFlagName  = "http-idle-conn-timeout"
+	httpIdleConnTimeoutEnvKey    = "HTTP_IDLE_CONN_TIMEOUT"
+	httpIdleConnTimeoutFlagUsage = "The amount of time that an idle (keep-alive) connection remains open. " +
+		"For example, '90s' for a 90 second timeout. Defaults to 90s. " +
+		commonEnvVarUsageText + httpIdleConnTimeoutEnvKey
+
+	httpHostTimeoutsFlagName  = "http-host-timeouts"
+	httpHostTimeoutsEnvKey    = "HTTP_HOST_TIMEOUTS"
+	httpHostTimeoutsFlagUsage = "The timeouts of outbound HTTP requests to specific hosts, which override " +
+		"the value of http-timeout. Each value is in the format host=timeout, for example, orb.domain1.com=5s. " +
+		commonEnvVarUsageText + httpHostTimeoutsEnvKey
+
+	circuitBreakerFailureThresholdFlagName  = "circuit-breaker-failure-threshold"
+	circuitBreakerFailureThresholdEnvKey    = "CIRCUIT_BREAKER_FAILURE_THRESHOLD"
+	circuitBreakerFailureThresholdFlagUsage = "The number of consecutive failed requests to a host (ActivityPub, " +
+		"WebCAS and VCT) after which subsequent requests to the host fail immediately. Defaults to 5. " +
+		commonEnvVarUsageText + circuitBreakerFailureThresholdEnvKey
+
+	circuitBreakerOpenTimeoutFlagName  = "circuit-breaker-open-timeout"
+	circuitBreakerOpenTimeoutEnvKey    = "CIRCUIT_BREAKER_OPEN_TIMEOUT"
+	circuitBreakerOpenTimeoutFlagUsage = "The amount of time that requests to a failed host are rejected before " +
+		"a trial request is allowed. For example, '1m' for a one minute timeout. Defaults to 30s. " +
+		commonEnvVarUsageText + circuitBreakerOpenTimeoutEnvKey
+
+	httpProxyURLFlagName  = "http-proxy-url"
+	httpProxyURLEnvKey    = "HTTP_PROXY_URL"
+	httpProxyURLFlagUsage = "The URL of the proxy for outbound HTTP requests (federation, CAS, etc.). " +
+		"Supported schemes are http, https and socks5, for example, socks5://proxy.example.com:1080. If none of " +
+		"the proxy parameters are set then the proxy is taken from the HTTP_PROXY, HTTPS_PROXY and NO_PROXY " +
+		"environment variables. " +
+		commonEnvVarUsageText + httpProxyURLEnvKey
+
+	httpProxyOverridesFlagName  = "http-proxy-overrides"
+	httpProxyOverridesEnvKey    = "HTTP_PROXY_OVERRIDES"
+	httpProxyOverridesFlagUsage = "The proxies for specific destination domains, which override the value of " +
+		"http-proxy-url. Each value is in the format domain=proxy, for example, orb.domain1.com=http://proxy2:3128. " +
+		"A domain also matches its subdomains. The proxy 'direct' means that requests to the domain are not proxied. " +
+		commonEnvVarUsageText + httpProxyOverridesEnvKey
+
+	httpNoProxyFlagName  = "http-no-proxy"
+	httpNoProxyEnvKey    = "HTTP_NO_PROXY"
+	httpNoProxyFlagUsage = "A list of domains, IP addresses and CIDRs of the destinations to which outbound HTTP " +
+		"requests are never proxied, for example, internal.example.com,10.0.0.0/8. A domain also matches its " +
+		"subdomains and '*' matches all destinations. " + commonEnvVarUsageText + httpNoProxyEnvKey
+
+	directProxy = "direct"
+)
+
+// httpClientParameters contains the connection pool limits and per-host timeouts of the shared HTTP client.
+type httpClientParameters struct {
+	maxIdleConns        uint
+	maxIdleConnsPerHost uint
+	maxConnsPerHost     uint
+	idleConnTimeout     time.Duration
+	hostTimeouts        map[string]time.Duration
+
+	circuitBreakerFailureThreshold uint
+	circuitBreakerOpenTimeout      time.Duration
+
+	proxy *transport.ProxyConfig
+}
+
+func getHTTPClientParameters(cmd *cobra.Command) (*httpClientParameters, error) {
+	maxIdleConns, err := getUint(cmd, httpMaxIdleConnsFlagName, httpMaxIdleConnsEnvKey)
+	if err != nil {
+		return nil, err
+	}
+
+	maxIdleConnsPerHost, err := getUint(cmd, httpMaxIdleConnsPerHostFlagName, httpMaxIdleConnsPerHostEnvKey)
+	if err != nil {
+		return nil, err
+	}
+
+	maxConnsPerHost, err := getUint(cmd, httpMaxConnsPerHostFlagName, httpMaxConnsPerHostEnvKey)
+	if err != nil {
+		return nil, err
+	}
+
+	idleConnTimeout, err := getDuration(cmd, httpIdleConnTimeoutFlagName, httpIdleConnTimeoutEnvKey, 0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for parameter [%s]: %w", httpIdleConnTimeoutFlagName, err)
+	}
+
+	hostTimeouts, err := getHostTimeouts(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	cbFailureThreshold, err := getUint(cmd, circuitBreakerFailureThresholdFlagName,
+		circuitBreakerFailureThresholdEnvKey)
+	if err != nil {
+		return nil, err
+	}
+
+	cbOpenTimeout, err := getDuration(cmd, circuitBreakerOpenTimeoutFlagName, circuitBreakerOpenTimeoutEnvKey, 0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for parameter [%s]: %w", circuitBreakerOpenTimeoutFlagName, err)
+	}
+
+	proxy, err := getProxyConfig(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	return &httpClientParameters{
+		maxIdleConns:                   maxIdleConns,
+		maxIdleConnsPerHost:            maxIdleConnsPerHost,
+		maxConnsPerHost:                maxConnsPerHost,
+		idleConnTimeout:                idleConnTimeout,
+		hostTimeouts:                   hostTimeouts,
+		circuitBreakerFailureThreshold: cbFailureThreshold,
+		circuitBreakerOpenTimeout:      cbOpenTimeout,
+		proxy:                          proxy,
+	}, nil
+}
+
+func getHostTimeouts(cmd *cobra.Command) (map[string]time.Duration, error) {
+	values := cmdutils.GetUserSetOptionalVarFromArrayString(cmd, httpHostTimeoutsFlagName, httpHostTimeoutsEnvKey)
+
+	hostTimeouts := make(map[string]time.Duration)
+
+	for _, value := range values {
+		parts := strings.Split(value, "=")
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" { //nolint:gomnd
+			return nil, fmt.Errorf("invalid value [%s] for parameter [%s]: expecting host=timeout",
+				value, httpHostTimeoutsFlagName)
+		}
+
+		timeout, err := time.ParseDuration(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid timeout in value [%s] for parameter [%s]: %w",
+				value, httpHostTimeoutsFlagName, err)
+		}
+
+		hostTimeouts[strings.TrimSpace(parts[0])] = timeout
+	}
+
+	return hostTimeouts, nil
+}
+
+// getProxyConfig returns the proxy configuration or nil if none of the proxy parameters are set, in which case the
+// proxy is taken from the environment.
+func getProxyConfig(cmd *cobra.Command) (*transport.ProxyConfig, error) {
+	proxyURLStr := cmdutils.GetUserSetOptionalVarFromString(cmd, httpProxyURLFlagName, httpProxyURLEnvKey)
+	overrideValues := cmdutils.GetUserSetOptionalVarFromArrayString(cmd, httpProxyOverridesFlagName,
+		httpProxyOverridesEnvKey)
+	noProxyValues := cmdutils.GetUserSetOptionalVarFromArrayString(cmd, httpNoProxyFlagName, httpNoProxyEnvKey)
+
+	if proxyURLStr == "" && len(overrideValues) == 0 && len(noProxyValues) == 0 {
+		return nil, nil
+	}
+
+	cfg := &transport.ProxyConfig{}
+
+	if proxyURLStr != "" {
+		proxyURL, err := parseProxyURL(proxyURLStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for parameter [%s]: %w", httpProxyURLFlagName, err)
+		}
+
+		cfg.URL = proxyURL
+	}
+
+	overrides, err := getProxyOverrides(overrideValues)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.Overrides = overrides
+
+	for _, value := range noProxyValues {
+		value = strings.TrimSpace(value)
+
+		if strings.Contains(value, "/") {
+			_, ipNet, e := net.ParseCIDR(value)
+			if e != nil {
+				return nil, fmt.Errorf("invalid CIDR [%s] for parameter [%s]: %w", value, httpNoProxyFlagName, e)
+			}
+
+			cfg.NoProxyNets = append(cfg.NoProxyNets, ipNet)
+		} else if value != "" {
+			// IP addresses are matched as hosts.
+			cfg.NoProxyDomains = append(cfg.NoProxyDomains, value)
+		}
+	}
+
+	return cfg, nil
+}
+
+func getProxyOverrides(values []string) (map[string]*url.URL, error) {
+	overrides := make(map[string]*url.URL)
+
+	for _, value := range values {
+		i := strings.Index(value, "=")
+		if i < 0 || strings.TrimSpace(value[:i]) == "" {
+			return nil, fmt.Errorf("invalid value [%s] for parameter [%s]: expecting domain=proxy",
+				value, httpProxyOverridesFlagName)
+		}
+
+		var proxyURL *url.URL
+
+		if v := strings.TrimSpace(value[i+1:]); v != directProxy {
+			u, err := parseProxyURL(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid proxy in value [%s] for parameter [%s]: %w",
+					value, httpProxyOverridesFlagName, err)
+			}
+
+			proxyURL = u
+		}
+
+		overrides[strings.TrimSpace(value[:i])] = proxyURL
+	}
+
+	return overrides, nil
+}
+
+func parseProxyURL(value string) (*url.URL, error) {
+	u, err := url.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme [%s]", u.Scheme)
+	}
+
+	if u.Host == "" {
+		return nil, fmt.Errorf("missing host in proxy URL [%s]", value)
+	}
+
+	return u, nil
+}
+
+// newHTTPClient returns the HTTP client that is shared by all outbound requests.
+func newHTTPClient(parameters *orbParameters, tlsConfig *tls.Config) *http.Client {
+	return transport.NewHTTPClient(&transport.HTTPClientConfig{
+		TLSConfig:           tlsConfig,
+		Timeout:             parameters.httpTimeout,
+		DialTimeout:         parameters.httpDialTimeout,
+		HostTimeouts:        parameters.httpClient.hostTimeouts,
+		MaxIdleConns:        int(parameters.httpClient.maxIdleConns),
+		MaxIdleConnsPerHost: int(parameters.httpClient.maxIdleConnsPerHost),
+		MaxConnsPerHost:     int(parameters.httpClient.maxConnsPerHost),
+		IdleConnTimeout:     parameters.httpClient.idleConnTimeout,
+		Proxy:               parameters.httpClient.proxy,
+	})
+}
+
+// newFederationHTTPClient returns an HTTP client for requests to other Orb domains (ActivityPub, WebCAS and VCT).
+// The client maintains a circuit breaker for each destination host so that requests to an unresponsive domain
+// fail immediately. If a fault injector is provided then faults are injected into the requests (behind the
+// circuit breakers, so that injected failures open them).
+func newFederationHTTPClient(parameters *orbParameters, httpClient *http.Client,
+	faultInjector *faultinjection.Injector) *http.Client {
+	next := httpClient.Transport
+
+	if faultInjector != nil {
+		next = faultinjection.NewRoundTripper(next, faultInjector)
+	}
+
+	return &http.Client{
+		Timeout: httpClient.Timeout,
+		Transport: circuitbreaker.NewRoundTripper(next,
+			circuitbreaker.Config{
+				FailureThreshold: parameters.httpClient.circuitBreakerFailureThreshold,
+				OpenTimeout:      parameters.httpClient.circuitBreakerOpenTimeout,
+			},
+			metrics.Get(),
+		),
+	}
+}
@@ -0,0 +1,213 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package startcmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/trustbloc/orb/pkg/config/dynamic"
+	"github.com/trustbloc/orb/pkg/httpclient"
+)
+
+const (
+	activityPubDestination = "activitypub"
+	casDestination         = "cas"
+	vctDestination         = "vct"
+	kmsDestination         = "kms"
+
+	httpTimeoutFlagSuffix      = "-http-timeout"
+	httpMaxRetriesFlagSuffix   = "-http-max-retries"
+	httpRetryBackoffFlagSuffix = "-http-retry-backoff"
+
+	defaultHTTPRetryBackoff = time.Second
+)
+
+// httpDestinationClasses contains the classes of outbound HTTP destinations that have their own timeout and
+// retry settings. For each class, the flags <class>-http-timeout, <class>-http-max-retries and
+// <class>-http-retry-backoff are defined and the same parameters may be updated at runtime.
+var httpDestinationClasses = []struct {
+	name        string
+	description string
+}{
+	{activityPubDestination, "ActivityPub requests to other Orb domains"},
+	{casDestination, "CAS requests (IPFS and WebCAS)"},
+	{vctDestination, "requests to VCT logs"},
+	{kmsDestination, "requests to the remote KMS"},
+}
+
+func destinationFlagName(class, suffix string) string {
+	return class + suffix
+}
+
+func destinationEnvKey(flagName string) string {
+	return strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+func createHTTPDestinationFlags(startCmd *cobra.Command) {
+	for _, class := range httpDestinationClasses {
+		timeoutFlagName := destinationFlagName(class.name, httpTimeoutFlagSuffix)
+		maxRetriesFlagName := destinationFlagName(class.name, httpMaxRetriesFlagSuffix)
+		retryBackoffFlagName := destinationFlagName(class.name, httpRetryBackoffFlagSuffix)
+
+		startCmd.Flags().String(timeoutFlagName, "", fmt.Sprintf(
+			"The timeout of a single attempt of %s. For example, '30s' for a 30 second timeout. "+
+				"Defaults to the value of %s. This parameter may be updated at runtime. "+
+				commonEnvVarUsageText+"%s", class.description, defaultTimeoutFlagName(class.name),
+			destinationEnvKey(timeoutFlagName)))
+
+		startCmd.Flags().String(maxRetriesFlagName, "", fmt.Sprintf(
+			"The maximum number of times that a failed attempt of %s is retried. An attempt is retried if it "+
+				"fails with a connection error or with status 429, 502, 503 or 504. Defaults to 0 (no retries). "+
+				"This parameter may be updated at runtime. "+commonEnvVarUsageText+"%s",
+			class.description, destinationEnvKey(maxRetriesFlagName)))
+
+		startCmd.Flags().String(retryBackoffFlagName, "", fmt.Sprintf(
+			"The time to wait before the first retry of %s. The wait time is doubled for each subsequent retry. "+
+				"Defaults to 1s. This parameter may be updated at runtime. "+commonEnvVarUsageText+"%s",
+			class.description, destinationEnvKey(retryBackoffFlagName)))
+	}
+}
+
+func defaultTimeoutFlagName(class string) string {
+	if class == casDestination {
+		return ipfsTimeoutFlagName
+	}
+
+	return httpTimeoutFlagName
+}
+
+// getHTTPDestinationParameters returns the timeout and retry settings of each class of outbound HTTP destinations.
+// The timeout of CAS requests defaults to the IPFS timeout and the timeout of the other classes defaults to the
+// HTTP timeout.
+func getHTTPDestinationParameters(cmd *cobra.Command, httpTimeout,
+	ipfsTimeout time.Duration) (map[string]httpclient.Settings, error) {
+	settings := make(map[string]httpclient.Settings)
+
+	for _, class := range httpDestinationClasses {
+		defaultTimeout := httpTimeout
+		if defaultTimeoutFlagName(class.name) == ipfsTimeoutFlagName {
+			defaultTimeout = ipfsTimeout
+		}
+
+		timeoutFlagName := destinationFlagName(class.name, httpTimeoutFlagSuffix)
+
+		timeout, err := getDuration(cmd, timeoutFlagName, destinationEnvKey(timeoutFlagName), defaultTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for parameter [%s]: %w", timeoutFlagName, err)
+		}
+
+		maxRetriesFlagName := destinationFlagName(class.name, httpMaxRetriesFlagSuffix)
+
+		maxRetries, err := getUint(cmd, maxRetriesFlagName, destinationEnvKey(maxRetriesFlagName))
+		if err != nil {
+			return nil, err
+		}
+
+		retryBackoffFlagName := destinationFlagName(class.name, httpRetryBackoffFlagSuffix)
+
+		retryBackoff, err := getDuration(cmd, retryBackoffFlagName, destinationEnvKey(retryBackoffFlagName),
+			defaultHTTPRetryBackoff)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for parameter [%s]: %w", retryBackoffFlagName, err)
+		}
+
+		settings[class.name] = httpclient.Settings{
+			Timeout:      timeout,
+			MaxRetries:   maxRetries,
+			RetryBackoff: retryBackoff,
+		}
+	}
+
+	return settings, nil
+}
+
+// httpDestinations contains the classes of outbound HTTP destinations. Each class may have any number of
+// HTTP clients (for example, CAS requests are sent to IPFS and to WebCAS using different clients) which
+// share the settings of the class.
+type httpDestinations struct {
+	activityPub *httpclient.Destination
+	cas         *httpclient.Destination
+	vct         *httpclient.Destination
+	kms         *httpclient.Destination
+}
+
+func newHTTPDestinations(settings map[string]httpclient.Settings) *httpDestinations {
+	return &httpDestinations{
+		activityPub: httpclient.NewDestination(activityPubDestination, settings[activityPubDestination]),
+		cas:         httpclient.NewDestination(casDestination, settings[casDestination]),
+		vct:         httpclient.NewDestination(vctDestination, settings[vctDestination]),
+		kms:         httpclient.NewDestination(kmsDestination, settings[kmsDestination]),
+	}
+}
+
+func (d *httpDestinations) all() []*httpclient.Destination {
+	return []*httpclient.Destination{d.activityPub, d.cas, d.vct, d.kms}
+}
+
+// registerHTTPDestinations registers the timeout and retry settings of each class of outbound HTTP destinations
+// with the dynamic configuration and updates the class when a setting changes.
+func registerHTTPDestinations(dynamicConfig *dynamic.Manager, destinations *httpDestinations) error {
+	for _, d := range destinations.all() {
+		settings := d.Settings()
+
+		err := registerDurationParameter(dynamicConfig, destinationFlagName(d.Name(), httpTimeoutFlagSuffix),
+			fmt.Sprintf("The timeout of a single attempt of [%s] HTTP requests.", d.Name()),
+			settings.Timeout, d.SetTimeout)
+		if err != nil {
+			return err
+		}
+
+		err = registerUintParameter(dynamicConfig, destinationFlagName(d.Name(), httpMaxRetriesFlagSuffix),
+			fmt.Sprintf("The maximum number of times that a failed [%s] HTTP request is retried.", d.Name()),
+			settings.MaxRetries, d.SetMaxRetries)
+		if err != nil {
+			return err
+		}
+
+		err = registerDurationParameter(dynamicConfig, destinationFlagName(d.Name(), httpRetryBackoffFlagSuffix),
+			fmt.Sprintf("The time to wait before the first retry of a failed [%s] HTTP request.", d.Name()),
+			settings.RetryBackoff, d.SetRetryBackoff)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func registerDurationParameter(dynamicConfig *dynamic.Manager, name, description string,
+	defaultValue time.Duration, set func(value time.Duration)) error {
+	err := dynamicConfig.Register(&dynamic.Parameter{
+		Name:         name,
+		Description:  description,
+		DefaultValue: defaultValue.String(),
+		Validate:     dynamic.PositiveDuration,
+	})
+	if err != nil {
+		return fmt.Errorf("register parameter [%s]: %w", name, err)
+	}
+
+	err = dynamicConfig.Subscribe(name, func(value string) {
+		d, e := time.ParseDuration(value)
+		if e != nil {
+			logger.Warnf("Invalid value for [%s]: %s", name, e)
+
+			return
+		}
+
+		set(d)
+	})
+	if err != nil {
+		return fmt.Errorf("subscribe to parameter [%s]: %w", name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package startcmd
+
+import (
+	"testing"
+	"time"
+
+	ariesmemstorage "github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/config/dynamic"
+	"github.com/trustbloc/orb/pkg/httpclient"
+)
+
+func TestGetHTTPDestinationParameters(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		startCmd := GetStartCmd()
+		require.NoError(t, startCmd.ParseFlags(nil))
+
+		p, err := getHTTPDestinationParameters(startCmd, time.Second, 2*time.Second)
+		require.NoError(t, err)
+		require.Len(t, p, 4)
+
+		require.Equal(t, httpclient.Settings{
+			Timeout:      time.Second,
+			RetryBackoff: defaultHTTPRetryBackoff,
+		}, p[activityPubDestination])
+
+		require.Equal(t, httpclient.Settings{
+			Timeout:      2 * time.Second,
+			RetryBackoff: defaultHTTPRetryBackoff,
+		}, p[casDestination])
+
+		require.Equal(t, time.Second, p[vctDestination].Timeout)
+		require.Equal(t, time.Second, p[kmsDestination].Timeout)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		startCmd := GetStartCmd()
+		require.NoError(t, startCmd.ParseFlags([]string{
+			"--" + activityPubDestination + httpTimeoutFlagSuffix, "5s",
+			"--" + activityPubDestination + httpMaxRetriesFlagSuffix, "2",
+			"--" + activityPubDestination + httpRetryBackoffFlagSuffix, "500ms",
+			"--" + kmsDestination + httpTimeoutFlagSuffix, "3s",
+		}))
+
+		restoreEnv := setEnv(t, "VCT_HTTP_MAX_RETRIES", "4")
+		defer restoreEnv()
+
+		p, err := getHTTPDestinationParameters(startCmd, time.Second, 2*time.Second)
+		require.NoError(t, err)
+
+		require.Equal(t, httpclient.Settings{
+			Timeout:      5 * time.Second,
+			MaxRetries:   2,
+			RetryBackoff: 500 * time.Millisecond,
+		}, p[activityPubDestination])
+
+		require.Equal(t, uint(4), p[vctDestination].MaxRetries)
+		require.Equal(t, 3*time.Second, p[kmsDestination].Timeout)
+		require.Zero(t, p[kmsDestination].MaxRetries)
+	})
+
+	t.Run("invalid timeout", func(t *testing.T) {
+		startCmd := GetStartCmd()
+		require.NoError(t, startCmd.ParseFlags([]string{"--" + casDestination + httpTimeoutFlagSuffix, "xxx"}))
+
+		_, err := getHTTPDestinationParameters(startCmd, time.Second, 2*time.Second)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for parameter [cas-http-timeout]")
+	})
+
+	t.Run("invalid max retries", func(t *testing.T) {
+		startCmd := GetStartCmd()
+		require.NoError(t, startCmd.ParseFlags([]string{"--" + vctDestination + httpMaxRetriesFlagSuffix, "-1"}))
+
+		_, err := getHTTPDestinationParameters(startCmd, time.Second, 2*time.Second)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value [-1] for parameter [vct-http-max-retries]")
+	})
+
+	t.Run("invalid retry backoff", func(t *testing.T) {
+		startCmd := GetStartCmd()
+		require.NoError(t, startCmd.ParseFlags([]string{"--" + kmsDestination + httpRetryBackoffFlagSuffix, "xxx"}))
+
+		_, err := getHTTPDestinationParameters(startCmd, time.Second, 2*time.Second)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for parameter [kms-http-retry-backoff]")
+	})
+}
+
+func TestRegisterHTTPDestinations(t *testing.T) {
+	configStore, err := ariesmemstorage.NewProvider().OpenStore("orb-config")
+	require.NoError(t, err)
+
+	dynamicConfig := dynamic.New(configStore)
+
+	destinations := newHTTPDestinations(map[string]httpclient.Settings{
+		activityPubDestination: {Timeout: time.Second, RetryBackoff: time.Second},
+		casDestination:         {Timeout: 2 * time.Second, RetryBackoff: time.Second},
+		vctDestination:         {Timeout: time.Second, RetryBackoff: time.Second},
+		kmsDestination:         {Timeout: time.Second, RetryBackoff: time.Second},
+	})
+
+	require.NoError(t, registerHTTPDestinations(dynamicConfig, destinations))
+
+	value, err := dynamicConfig.Get("cas-http-timeout")
+	require.NoError(t, err)
+	require.Equal(t, "2s", value)
+
+	require.NoError(t, dynamicConfig.Update(map[string]string{
+		"activitypub-http-timeout":       "10s",
+		"activitypub-http-max-retries":   "3",
+		"activitypub-http-retry-backoff": "250ms",
+		"kms-http-max-retries":           "1",
+	}))
+
+	require.Equal(t, httpclient.Settings{
+		Timeout:      10 * time.Second,
+		MaxRetries:   3,
+		RetryBackoff: 250 * time.Millisecond,
+	}, destinations.activityPub.Settings())
+
+	require.Equal(t, uint(1), destinations.kms.Settings().MaxRetries)
+	require.Equal(t, 2*time.Second, destinations.cas.Settings().Timeout)
+
+	require.Error(t, dynamicConfig.Update(map[string]string{"vct-http-timeout": "0s"}))
+	require.Error(t, dynamicConfig.Update(map[string]string{"vct-http-max-retries": "-1"}))
+
+	t.Run("already registered", func(t *testing.T) {
+		require.Error(t, registerHTTPDestinations(dynamicConfig, destinations))
+	})
+}
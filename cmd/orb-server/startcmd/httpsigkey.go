@@ -0,0 +1,146 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package startcmd
+
+import (
+	"fmt"
+	"net/url"
+	"sync"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/config/dynamic"
+)
+
+const httpSignatureKeyIDParam = "http-signature-key-id"
+
+type pubKeyExporter interface {
+	ExportPubKeyBytes(keyID string) ([]byte, error)
+}
+
+type keyIDSetter interface {
+	SetKeyID(keyID string)
+}
+
+type publicKeySetter interface {
+	SetPublicKey(publicKey *vocab.PublicKeyType)
+}
+
+type linksetPublicKeyProvider interface {
+	PublicKey(keyIRI *url.URL) ([]byte, error)
+}
+
+// httpSignatureKeyRotator switches the KMS key that is used to sign outbound ActivityPub requests (and anchor
+// linksets, if enabled) and updates the public key that is published by the ActivityPub service.
+type httpSignatureKeyRotator struct {
+	km                    pubKeyExporter
+	apServiceIRI          *url.URL
+	apServicePublicKeyIRI *url.URL
+	signers               []keyIDSetter
+	publicKeyHandlers     []publicKeySetter
+
+	mutex sync.Mutex
+	keyID string
+}
+
+func newHTTPSignatureKeyRotator(keyID string, km pubKeyExporter, apServiceIRI, apServicePublicKeyIRI *url.URL,
+	signers []keyIDSetter, publicKeyHandlers ...publicKeySetter) *httpSignatureKeyRotator {
+	return &httpSignatureKeyRotator{
+		km:                    km,
+		apServiceIRI:          apServiceIRI,
+		apServicePublicKeyIRI: apServicePublicKeyIRI,
+		signers:               signers,
+		publicKeyHandlers:     publicKeyHandlers,
+		keyID:                 keyID,
+	}
+}
+
+// register registers the HTTP signature key ID as a dynamic configuration parameter so that the key
+// may be rotated without restarting the server.
+func (r *httpSignatureKeyRotator) register(dynamicConfig *dynamic.Manager) error {
+	err := dynamicConfig.Register(&dynamic.Parameter{
+		Name: httpSignatureKeyIDParam,
+		Description: "The ID of the KMS key used to sign ActivityPub HTTP requests. The key must exist in the KMS. " +
+			"Note that other servers may cache the previous public key until their cache expires.",
+		DefaultValue: r.keyID,
+		Validate:     r.validate,
+	})
+	if err != nil {
+		return fmt.Errorf("register parameter [%s]: %w", httpSignatureKeyIDParam, err)
+	}
+
+	err = dynamicConfig.Subscribe(httpSignatureKeyIDParam, r.rotate)
+	if err != nil {
+		return fmt.Errorf("subscribe to parameter [%s]: %w", httpSignatureKeyIDParam, err)
+	}
+
+	return nil
+}
+
+func (r *httpSignatureKeyRotator) validate(keyID string) error {
+	if keyID == "" {
+		return fmt.Errorf("key ID is required")
+	}
+
+	if _, err := r.km.ExportPubKeyBytes(keyID); err != nil {
+		return fmt.Errorf("export public key for key ID [%s]: %w", keyID, err)
+	}
+
+	return nil
+}
+
+func (r *httpSignatureKeyRotator) rotate(keyID string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if keyID == r.keyID {
+		return
+	}
+
+	pubKeyBytes, err := r.km.ExportPubKeyBytes(keyID)
+	if err != nil {
+		logger.Errorf("Unable to rotate HTTP signature key to [%s]. The previous key [%s] will continue to be used: %s",
+			keyID, r.keyID, err)
+
+		return
+	}
+
+	publicKey, err := getActivityPubPublicKey(pubKeyBytes, r.apServiceIRI, r.apServicePublicKeyIRI)
+	if err != nil {
+		logger.Errorf("Unable to rotate HTTP signature key to [%s]. The previous key [%s] will continue to be used: %s",
+			keyID, r.keyID, err)
+
+		return
+	}
+
+	for _, h := range r.publicKeyHandlers {
+		h.SetPublicKey(publicKey)
+	}
+
+	for _, s := range r.signers {
+		s.SetKeyID(keyID)
+	}
+
+	logger.Infof("Rotated HTTP signature key from [%s] to [%s]", r.keyID, keyID)
+
+	r.keyID = keyID
+}
+
+// linksetPublicKeyResolver resolves the public keys that were used to sign anchor linksets so that the
+// signatures created before the HTTP signature key was rotated may still be verified by other servers.
+type linksetPublicKeyResolver struct {
+	signer       linksetPublicKeyProvider
+	apServiceIRI *url.URL
+}
+
+func (r *linksetPublicKeyResolver) ResolvePublicKey(keyIRI *url.URL) (*vocab.PublicKeyType, error) {
+	pubKeyBytes, err := r.signer.PublicKey(keyIRI)
+	if err != nil {
+		return nil, err
+	}
+
+	return getActivityPubPublicKey(pubKeyBytes, r.apServiceIRI, keyIRI)
+}
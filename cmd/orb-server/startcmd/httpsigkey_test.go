@@ -0,0 +1,169 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package startcmd
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+
+	ariesmemstorage "github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/config/dynamic"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+func TestHTTPSignatureKeyRotator(t *testing.T) {
+	const (
+		key1 = "key1"
+		key2 = "key2"
+	)
+
+	km := &mockPubKeyExporter{keys: map[string][]byte{
+		key1: newPublicKey(t),
+		key2: newPublicKey(t),
+	}}
+
+	apServiceIRI, err := url.Parse("https://orb.domain1.com/services/orb")
+	require.NoError(t, err)
+
+	apServicePublicKeyIRI, err := url.Parse("https://orb.domain1.com/services/orb/keys/main-key")
+	require.NoError(t, err)
+
+	configStore, err := ariesmemstorage.NewProvider().OpenStore("orb-config")
+	require.NoError(t, err)
+
+	s := &mockKeyIDSetter{}
+	h := &mockPublicKeySetter{}
+
+	r := newHTTPSignatureKeyRotator(key1, km, apServiceIRI, apServicePublicKeyIRI, []keyIDSetter{s}, h)
+
+	dynamicConfig := dynamic.New(configStore)
+
+	require.NoError(t, r.register(dynamicConfig))
+
+	// The current key should not result in a rotation.
+	require.Empty(t, s.keyID)
+	require.Nil(t, h.publicKey)
+
+	t.Run("success", func(t *testing.T) {
+		require.NoError(t, dynamicConfig.Update(map[string]string{httpSignatureKeyIDParam: key2}))
+
+		require.Equal(t, key2, s.keyID)
+		require.NotNil(t, h.publicKey)
+		require.Equal(t, apServicePublicKeyIRI.String(), h.publicKey.ID.String())
+		require.NotEmpty(t, h.publicKey.PublicKeyPem)
+		require.True(t, strings.HasPrefix(h.publicKey.PublicKeyMultibase, "z6Mk"))
+	})
+
+	t.Run("invalid key ID", func(t *testing.T) {
+		err := dynamicConfig.Update(map[string]string{httpSignatureKeyIDParam: "unknown"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "export public key for key ID [unknown]")
+
+		err = dynamicConfig.Update(map[string]string{httpSignatureKeyIDParam: ""})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "key ID is required")
+
+		require.Equal(t, key2, s.keyID)
+	})
+
+	t.Run("export error -> previous key is used", func(t *testing.T) {
+		r.rotate("unknown")
+
+		require.Equal(t, key2, s.keyID)
+	})
+
+	t.Run("register error", func(t *testing.T) {
+		require.Error(t, r.register(dynamicConfig))
+	})
+}
+
+func TestLinksetPublicKeyResolver(t *testing.T) {
+	apServiceIRI, err := url.Parse("https://orb.domain1.com/services/orb")
+	require.NoError(t, err)
+
+	keyIRI, err := url.Parse("https://orb.domain1.com/services/orb/keys/main-key-ref1")
+	require.NoError(t, err)
+
+	r := &linksetPublicKeyResolver{
+		signer:       &mockLinksetPublicKeyProvider{keys: map[string][]byte{keyIRI.String(): newPublicKey(t)}},
+		apServiceIRI: apServiceIRI,
+	}
+
+	t.Run("success", func(t *testing.T) {
+		publicKey, e := r.ResolvePublicKey(keyIRI)
+		require.NoError(t, e)
+		require.Equal(t, keyIRI.String(), publicKey.ID.String())
+		require.Equal(t, apServiceIRI.String(), publicKey.Owner.String())
+		require.NotEmpty(t, publicKey.PublicKeyPem)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		unknownKeyIRI, e := url.Parse("https://orb.domain1.com/services/orb/keys/main-key-ref2")
+		require.NoError(t, e)
+
+		_, e = r.ResolvePublicKey(unknownKeyIRI)
+		require.ErrorIs(t, e, orberrors.ErrContentNotFound)
+	})
+}
+
+type mockLinksetPublicKeyProvider struct {
+	keys map[string][]byte
+}
+
+func (m *mockLinksetPublicKeyProvider) PublicKey(keyIRI *url.URL) ([]byte, error) {
+	key, ok := m.keys[keyIRI.String()]
+	if !ok {
+		return nil, orberrors.ErrContentNotFound
+	}
+
+	return key, nil
+}
+
+type mockPubKeyExporter struct {
+	keys map[string][]byte
+}
+
+func (m *mockPubKeyExporter) ExportPubKeyBytes(keyID string) ([]byte, error) {
+	key, ok := m.keys[keyID]
+	if !ok {
+		return nil, errors.New("key not found")
+	}
+
+	return key, nil
+}
+
+type mockKeyIDSetter struct {
+	keyID string
+}
+
+func (m *mockKeyIDSetter) SetKeyID(keyID string) {
+	m.keyID = keyID
+}
+
+type mockPublicKeySetter struct {
+	publicKey *vocab.PublicKeyType
+}
+
+func (m *mockPublicKeySetter) SetPublicKey(publicKey *vocab.PublicKeyType) {
+	m.publicKey = publicKey
+}
+
+func newPublicKey(t *testing.T) []byte {
+	t.Helper()
+
+	pubKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	return pubKey
+}
@@ -0,0 +1,129 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package startcmd
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+	restcommon "github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+
+	"github.com/trustbloc/orb/pkg/activitypub/archive"
+	aphandler "github.com/trustbloc/orb/pkg/activitypub/resthandler"
+	"github.com/trustbloc/orb/pkg/httpserver/debug"
+	"github.com/trustbloc/orb/pkg/httpserver/ipfilter"
+	leaderhandler "github.com/trustbloc/orb/pkg/leaderelection/resthandler"
+	maintenancehandler "github.com/trustbloc/orb/pkg/maintenance/resthandler"
+	taskhandler "github.com/trustbloc/orb/pkg/taskmgr/resthandler"
+)
+
+const (
+	adminAllowedCIDRsFlagName  = "admin-allowed-cidrs"
+	adminAllowedCIDRsEnvKey    = "ADMIN_ALLOWED_CIDRS"
+	adminAllowedCIDRsFlagUsage = "A list of CIDRs (or IP addresses) of the clients that are allowed to access the " +
+		"admin endpoints, i.e. the endpoints that require the admin authorization token. If not specified then " +
+		"clients from any network (that isn't denied) are allowed. For example, 10.0.0.0/8. " +
+		commonEnvVarUsageText + adminAllowedCIDRsEnvKey
+
+	adminDeniedCIDRsFlagName  = "admin-denied-cidrs"
+	adminDeniedCIDRsEnvKey    = "ADMIN_DENIED_CIDRS"
+	adminDeniedCIDRsFlagUsage = "A list of CIDRs (or IP addresses) of the clients that are denied access to the " +
+		"admin endpoints. The deny list takes precedence over the allow list. " +
+		commonEnvVarUsageText + adminDeniedCIDRsEnvKey
+
+	trustedProxyCIDRsFlagName  = "trusted-proxy-cidrs"
+	trustedProxyCIDRsEnvKey    = "TRUSTED_PROXY_CIDRS"
+	trustedProxyCIDRsFlagUsage = "A list of CIDRs (or IP addresses) of trusted proxies (e.g. load balancers). " +
+		"If a request is received from a trusted proxy then the client IP address is taken from the Forwarded " +
+		"(or X-Forwarded-For) header when applying the admin allow/deny lists. " +
+		commonEnvVarUsageText + trustedProxyCIDRsEnvKey
+)
+
+func getAdminIPFilter(cmd *cobra.Command) (ipfilter.Config, error) {
+	allow, err := getCIDRs(cmd, adminAllowedCIDRsFlagName, adminAllowedCIDRsEnvKey)
+	if err != nil {
+		return ipfilter.Config{}, err
+	}
+
+	deny, err := getCIDRs(cmd, adminDeniedCIDRsFlagName, adminDeniedCIDRsEnvKey)
+	if err != nil {
+		return ipfilter.Config{}, err
+	}
+
+	trustedProxies, err := getCIDRs(cmd, trustedProxyCIDRsFlagName, trustedProxyCIDRsEnvKey)
+	if err != nil {
+		return ipfilter.Config{}, err
+	}
+
+	return ipfilter.Config{
+		Allow:          allow,
+		Deny:           deny,
+		TrustedProxies: trustedProxies,
+	}, nil
+}
+
+func getCIDRs(cmd *cobra.Command, flagName, envKey string) ([]*net.IPNet, error) {
+	networks, err := ipfilter.ParseCIDRs(cmdutils.GetUserSetOptionalVarFromArrayString(cmd, flagName, envKey))
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for parameter [%s]: %w", flagName, err)
+	}
+
+	return networks, nil
+}
+
+type requiredAuthTokensProvider interface {
+	RequiredAuthTokens(endpoint, method string) ([]string, error)
+}
+
+// applyAdminIPFilter wraps the admin handlers with a handler that applies the IP allow/deny lists. A handler is
+// considered to be an admin handler if it requires the admin authorization token.
+func applyAdminIPFilter(handlers []restcommon.HTTPHandler, cfg ipfilter.Config, tm requiredAuthTokensProvider,
+	adminToken string) []restcommon.HTTPHandler {
+	if !cfg.Enabled() {
+		return handlers
+	}
+
+	for i, handler := range handlers {
+		if isAdminEndpoint(handler, tm, adminToken) {
+			logger.Debugf("Applying IP filter to admin endpoint %s %s", handler.Method(), handler.Path())
+
+			handlers[i] = ipfilter.NewHandlerWrapper(handler, cfg)
+		}
+	}
+
+	return handlers
+}
+
+func isAdminEndpoint(handler restcommon.HTTPHandler, tm requiredAuthTokensProvider, adminToken string) bool {
+	// The debug, archive, task, maintenance, leader and pending follows endpoints always require the admin token.
+	if strings.HasPrefix(handler.Path(), debug.BasePath+"/") || handler.Path() == archive.Path ||
+		handler.Path() == taskhandler.Path || handler.Path() == maintenancehandler.Path ||
+		handler.Path() == leaderhandler.Path ||
+		handler.Path() == activityPubServicesPath+aphandler.PendingFollowsPath {
+		return true
+	}
+
+	if adminToken == "" {
+		return false
+	}
+
+	tokens, err := tm.RequiredAuthTokens(handler.Path(), handler.Method())
+	if err != nil {
+		return false
+	}
+
+	for _, token := range tokens {
+		if token == adminToken {
+			return true
+		}
+	}
+
+	return false
+}
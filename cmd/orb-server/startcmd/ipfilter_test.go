@@ -0,0 +1,144 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package startcmd
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	restcommon "github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+
+	"github.com/trustbloc/orb/pkg/activitypub/archive"
+	"github.com/trustbloc/orb/pkg/httpserver/ipfilter"
+)
+
+func TestGetAdminIPFilter(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		startCmd := GetStartCmd()
+		require.NoError(t, startCmd.ParseFlags(nil))
+
+		cfg, err := getAdminIPFilter(startCmd)
+		require.NoError(t, err)
+		require.False(t, cfg.Enabled())
+		require.Empty(t, cfg.TrustedProxies)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		startCmd := GetStartCmd()
+		require.NoError(t, startCmd.ParseFlags([]string{
+			"--" + adminAllowedCIDRsFlagName, "10.0.0.0/8",
+			"--" + adminAllowedCIDRsFlagName, "192.168.1.10",
+			"--" + adminDeniedCIDRsFlagName, "10.0.0.5",
+			"--" + trustedProxyCIDRsFlagName, "172.16.0.0/12",
+		}))
+
+		cfg, err := getAdminIPFilter(startCmd)
+		require.NoError(t, err)
+		require.True(t, cfg.Enabled())
+		require.Len(t, cfg.Allow, 2)
+		require.Len(t, cfg.Deny, 1)
+		require.Len(t, cfg.TrustedProxies, 1)
+		require.Equal(t, "172.16.0.0/12", cfg.TrustedProxies[0].String())
+	})
+
+	t.Run("invalid allowed CIDR", func(t *testing.T) {
+		startCmd := GetStartCmd()
+		require.NoError(t, startCmd.ParseFlags([]string{"--" + adminAllowedCIDRsFlagName, "10.0.0.0/99"}))
+
+		_, err := getAdminIPFilter(startCmd)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for parameter [admin-allowed-cidrs]")
+	})
+
+	t.Run("invalid denied CIDR", func(t *testing.T) {
+		startCmd := GetStartCmd()
+		require.NoError(t, startCmd.ParseFlags([]string{"--" + adminDeniedCIDRsFlagName, "xxx"}))
+
+		_, err := getAdminIPFilter(startCmd)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for parameter [admin-denied-cidrs]")
+	})
+
+	t.Run("invalid trusted proxy CIDR", func(t *testing.T) {
+		startCmd := GetStartCmd()
+		require.NoError(t, startCmd.ParseFlags([]string{"--" + trustedProxyCIDRsFlagName, "xxx"}))
+
+		_, err := getAdminIPFilter(startCmd)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for parameter [trusted-proxy-cidrs]")
+	})
+}
+
+func TestApplyAdminIPFilter(t *testing.T) {
+	tm := &mockRequiredAuthTokens{tokens: map[string][]string{
+		"/path:" + http.MethodPost: {"ADMIN_TOKEN"},
+		"/path:" + http.MethodGet:  {"READ_TOKEN"},
+	}}
+
+	newHandlers := func() []restcommon.HTTPHandler {
+		return []restcommon.HTTPHandler{
+			&mockHandler{method: http.MethodGet},
+			&mockHandler{method: http.MethodPost},
+			archive.NewHandler(nil),
+		}
+	}
+
+	t.Run("not enabled", func(t *testing.T) {
+		handlers := applyAdminIPFilter(newHandlers(), ipfilter.Config{}, tm, "ADMIN_TOKEN")
+
+		for _, h := range handlers {
+			_, ok := h.(*ipfilter.HandlerWrapper)
+			require.False(t, ok)
+		}
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		allow, err := ipfilter.ParseCIDRs([]string{"10.0.0.0/8"})
+		require.NoError(t, err)
+
+		handlers := applyAdminIPFilter(newHandlers(), ipfilter.Config{Allow: allow}, tm, "ADMIN_TOKEN")
+
+		_, ok := handlers[0].(*ipfilter.HandlerWrapper)
+		require.False(t, ok)
+
+		_, ok = handlers[1].(*ipfilter.HandlerWrapper)
+		require.True(t, ok)
+
+		_, ok = handlers[2].(*ipfilter.HandlerWrapper)
+		require.True(t, ok)
+	})
+
+	t.Run("no admin token", func(t *testing.T) {
+		allow, err := ipfilter.ParseCIDRs([]string{"10.0.0.0/8"})
+		require.NoError(t, err)
+
+		handlers := applyAdminIPFilter(newHandlers(), ipfilter.Config{Allow: allow}, tm, "")
+
+		_, ok := handlers[1].(*ipfilter.HandlerWrapper)
+		require.False(t, ok)
+	})
+
+	t.Run("token manager error", func(t *testing.T) {
+		require.False(t, isAdminEndpoint(&mockHandler{method: http.MethodPost},
+			&mockRequiredAuthTokens{err: errors.New("injected error")}, "ADMIN_TOKEN"))
+	})
+}
+
+type mockRequiredAuthTokens struct {
+	tokens map[string][]string
+	err    error
+}
+
+func (m *mockRequiredAuthTokens) RequiredAuthTokens(endpoint, method string) ([]string, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	return m.tokens[endpoint+":"+method], nil
+}
@@ -0,0 +1,145 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package startcmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+
+	"github.com/trustbloc/orb/pkg/store/migrations"
+)
+
+const (
+	dryRunFlagName  = "dry-run"
+	dryRunEnvKey    = "MIGRATE_DRY_RUN"
+	dryRunFlagUsage = "Set to true to log the pending store migrations without applying them. Defaults to false. " +
+		commonEnvVarUsageText + dryRunEnvKey
+)
+
+// GetMigrateCmd returns the Cobra migrate command which applies the pending store schema migrations. The command
+// may be run before upgrading the servers (when migrations aren't applied at startup).
+func GetMigrateCmd() *cobra.Command {
+	migrateCmd := createMigrateCmd()
+
+	createMigrateFlags(migrateCmd)
+
+	return migrateCmd
+}
+
+func createMigrateCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "migrate",
+		Short: "Apply pending store migrations",
+		Long:  "Apply the pending store schema migrations of orb-server",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			parameters, dryRun, err := getMigrateParameters(cmd)
+			if err != nil {
+				return err
+			}
+
+			return migrateStores(parameters, dryRun)
+		},
+	}
+}
+
+func getMigrateParameters(cmd *cobra.Command) (*orbParameters, bool, error) {
+	dbParams, err := getDBParameters(cmd, true)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// The key database isn't migrated.
+	dbParams.kmsSecretsDatabaseType = databaseTypeMemOption
+
+	// Migrations read their own writes so they always use the primary database.
+	dbParams.readDatabaseURL = ""
+
+	databaseTimeout, err := getDuration(cmd, databaseTimeoutFlagName, databaseTimeoutEnvKey, defaultDatabaseTimeout)
+	if err != nil {
+		return nil, false, fmt.Errorf("%s: %w", databaseTimeoutFlagName, err)
+	}
+
+	tenants, err := getTenants(cmd)
+	if err != nil {
+		return nil, false, err
+	}
+
+	dryRun := false
+
+	if dryRunStr := cmdutils.GetUserSetOptionalVarFromString(cmd, dryRunFlagName, dryRunEnvKey); dryRunStr != "" {
+		dryRun, err = strconv.ParseBool(dryRunStr)
+		if err != nil {
+			return nil, false, fmt.Errorf("invalid value for %s: %w", dryRunFlagName, err)
+		}
+	}
+
+	return &orbParameters{
+		dbParameters:    dbParams,
+		databaseTimeout: databaseTimeout,
+		tenants:         tenants,
+	}, dryRun, nil
+}
+
+// migrateStores applies the pending store migrations to the stores of the server or, if tenants are configured,
+// to the stores of each tenant.
+func migrateStores(parameters *orbParameters, dryRun bool) error {
+	storeProviders, err := createStoreProviders(parameters)
+	if err != nil {
+		return err
+	}
+
+	if len(parameters.tenants) == 0 {
+		return applyStoreMigrations(storeProviders.provider, dryRun)
+	}
+
+	for _, t := range parameters.tenants {
+		logger.Infof("Applying store migrations for tenant [%s]", t.ID)
+
+		err = applyStoreMigrations(storeProviders.forTenant(t.ID).provider, dryRun)
+		if err != nil {
+			return fmt.Errorf("tenant [%s]: %w", t.ID, err)
+		}
+	}
+
+	return nil
+}
+
+func applyStoreMigrations(provider storage.Provider, dryRun bool) error {
+	mgr, err := migrations.New(provider, migrations.Orb()...)
+	if err != nil {
+		return fmt.Errorf("create store migration manager: %w", err)
+	}
+
+	result, err := mgr.Migrate(dryRun)
+	if err != nil {
+		return fmt.Errorf("apply store migrations: %w", err)
+	}
+
+	for _, m := range result.Migrations {
+		logger.Infof("Store migration %d (%s) - dry run: %t", m.Version, m.Description, dryRun)
+	}
+
+	logger.Infof("Store schema version: %d (was %d) - dry run: %t", result.ToVersion, result.FromVersion, dryRun)
+
+	return nil
+}
+
+func createMigrateFlags(migrateCmd *cobra.Command) {
+	migrateCmd.Flags().StringP(databaseTypeFlagName, databaseTypeFlagShorthand, "", databaseTypeFlagUsage)
+	migrateCmd.Flags().StringP(databaseURLFlagName, databaseURLFlagShorthand, "", databaseURLFlagUsage)
+	migrateCmd.Flags().StringP(databasePrefixFlagName, "", "", databasePrefixFlagUsage)
+	migrateCmd.Flags().StringP(databaseTimeoutFlagName, "", "", databaseTimeoutFlagUsage)
+	migrateCmd.Flags().String(legacyDatabaseTypeFlagName, "", legacyDatabaseTypeFlagUsage)
+	migrateCmd.Flags().String(legacyDatabaseURLFlagName, "", legacyDatabaseURLFlagUsage)
+	migrateCmd.Flags().String(legacyDatabasePrefixFlagName, "", legacyDatabasePrefixFlagUsage)
+	migrateCmd.Flags().String(tenantsFileFlagName, "", tenantsFileFlagUsage)
+	migrateCmd.Flags().String(dryRunFlagName, "", dryRunFlagUsage)
+}
@@ -0,0 +1,74 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package startcmd
+
+import (
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/store/migrations"
+)
+
+func TestMigrateCmd(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		migrateCmd := GetMigrateCmd()
+		migrateCmd.SetArgs([]string{"--" + databaseTypeFlagName, databaseTypeMemOption})
+
+		require.NoError(t, migrateCmd.Execute())
+	})
+
+	t.Run("Dry run with tenants", func(t *testing.T) {
+		tenantsFile := writeConfigFile(t, `
+tenants:
+  - id: tenant1
+    host: tenant1.example.com
+    externalEndpoint: https://tenant1.example.com
+`)
+
+		migrateCmd := GetMigrateCmd()
+		migrateCmd.SetArgs([]string{
+			"--" + databaseTypeFlagName, databaseTypeMemOption,
+			"--" + tenantsFileFlagName, tenantsFile,
+			"--" + dryRunFlagName, "true",
+		})
+
+		require.NoError(t, migrateCmd.Execute())
+	})
+
+	t.Run("Invalid dry-run value", func(t *testing.T) {
+		migrateCmd := GetMigrateCmd()
+		migrateCmd.SetArgs([]string{"--" + databaseTypeFlagName, databaseTypeMemOption, "--" + dryRunFlagName, "xxx"})
+
+		err := migrateCmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for dry-run")
+	})
+
+	t.Run("Invalid database type", func(t *testing.T) {
+		migrateCmd := GetMigrateCmd()
+		migrateCmd.SetArgs([]string{"--" + databaseTypeFlagName, "xxx"})
+
+		err := migrateCmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "database type not set to a valid type")
+	})
+}
+
+func TestApplyStoreMigrations(t *testing.T) {
+	provider := mem.NewProvider()
+
+	require.NoError(t, applyStoreMigrations(provider, false))
+
+	mgr, err := migrations.New(provider, migrations.Orb()...)
+	require.NoError(t, err)
+
+	pending, err := mgr.Pending()
+	require.NoError(t, err)
+	require.Empty(t, pending)
+}
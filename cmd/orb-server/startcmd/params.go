@@ -7,9 +7,12 @@ SPDX-License-Identifier: Apache-2.0
 package startcmd
 
 import (
+	"crypto/ed25519"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"net/url"
+	"os"
 	"strconv"
 	"strings"
 	"time"
@@ -18,7 +21,20 @@ import (
 	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
 	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
 
+	aphandler "github.com/trustbloc/orb/pkg/activitypub/resthandler"
+	"github.com/trustbloc/orb/pkg/activitypub/service/inbox/fairqueue"
+	"github.com/trustbloc/orb/pkg/compression"
+	"github.com/trustbloc/orb/pkg/faultinjection"
+	"github.com/trustbloc/orb/pkg/httpclient"
+	"github.com/trustbloc/orb/pkg/httpserver"
 	"github.com/trustbloc/orb/pkg/httpserver/auth"
+	"github.com/trustbloc/orb/pkg/httpserver/ipfilter"
+	"github.com/trustbloc/orb/pkg/httpserver/limits"
+	"github.com/trustbloc/orb/pkg/httpserver/quota"
+	"github.com/trustbloc/orb/pkg/leaderelection"
+	"github.com/trustbloc/orb/pkg/observer/shard"
+	"github.com/trustbloc/orb/pkg/taskmgr"
+	"github.com/trustbloc/orb/pkg/tenant"
 )
 
 const (
@@ -31,9 +47,37 @@ const (
 	defaultHTTPDialTimeout                  = 2 * time.Second
 	defaultHTTPTimeout                      = 20 * time.Second
 	defaultUnpublishedOperationLifespan     = time.Minute * 5
+	defaultDIDSuffixIndexSyncInterval       = 10 * time.Second
 	defaultTaskMgrCheckInterval             = 10 * time.Second
 	defaultDataExpiryCheckInterval          = time.Minute
 	defaultAnchorSyncInterval               = time.Minute
+	defaultAnchorReconcileInterval          = time.Hour
+	defaultAnchorReconcileDays              = 7
+	defaultMaintenanceRetryAfter            = time.Minute
+	defaultLeaderElectionEnabled            = false
+	defaultObserverShardingEnabled          = false
+	defaultActivitySinkInterval             = 10 * time.Second
+	defaultActivitySinkBatchSize            = 100
+	defaultAcceptListRegistryInterval       = time.Hour
+	defaultGraphQLEnabled                   = false
+	defaultUniversalResolverDriverEnabled   = false
+	defaultWebhooksEnabled                  = false
+	defaultDIDWebhooksEnabled               = false
+	defaultOperationStatusEnabled           = false
+	defaultAnchorExplorerEnabled            = false
+	defaultMigrationEnabled                 = false
+	defaultStoreMigrationsEnabled           = true
+	defaultDeliveryAnalyticsEnabled         = false
+	defaultInboxQuarantineEnabled           = false
+	defaultAccessTokensEnabled              = false
+	defaultActivitySearchEnabled            = false
+	defaultJSONLDRemoteContextFetchEnabled  = false
+	defaultVCTLogAllowListEnabled           = false
+	defaultPersistentMetricsEnabled         = true
+	defaultLinksetSigningEnabled            = false
+	defaultLinksetSignatureRequired         = false
+	defaultLegacyDatabaseVerifyInterval     = time.Hour
+	defaultReadDatabaseMaxStaleness         = 30 * time.Second
 	defaultVCTMonitoringInterval            = 10 * time.Second
 	defaultAnchorStatusMonitoringInterval   = 5 * time.Second
 	defaultAnchorStatusInProcessGracePeriod = 10 * time.Second
@@ -44,19 +88,37 @@ const (
 	defaultActivityPubIRICacheExpiration    = time.Hour
 	defaultFollowAuthType                   = acceptAllPolicy
 	defaultInviteWitnessAuthType            = acceptAllPolicy
+	defaultInviteWitnessReciprocation       = noReciprocation
+	defaultWitnessProofBatchSize            = 20
+	defaultWitnessProofCacheSize            = 1000
 	defaultMQOpPoolSize                     = 5
+	defaultShutdownTimeout                  = 20 * time.Second
+	defaultPendingDeliveryRetention         = 7 * 24 * time.Hour
+	defaultInboxWorkers                     = 1
+	defaultInboxSenderMaxInFlight           = 1
+	defaultInboxRejectionHistorySize        = 100
+	defaultSidetreeProtocolVersion          = "1.0"
 
 	commonEnvVarUsageText = "Alternatively, this can be set with the following environment variable: "
 
 	hostURLFlagName      = "host-url"
 	hostURLFlagShorthand = "u"
-	hostURLFlagUsage     = "URL to run the orb-server instance on. Format: HostName:Port."
-	hostURLEnvKey        = "ORB_HOST_URL"
+	hostURLFlagUsage     = "URL to run the orb-server instance on. Format: HostName:Port. " +
+		commonEnvVarUsageText + hostURLEnvKey
+	hostURLEnvKey = "ORB_HOST_URL"
 
 	hostMetricsURLFlagName      = "host-metrics-url"
 	hostMetricsURLFlagShorthand = "M"
-	hostMetricsURLFlagUsage     = "URL that exposes the metrics endpoint. Format: HostName:Port."
-	hostMetricsURLEnvKey        = "ORB_HOST_METRICS_URL"
+	hostMetricsURLFlagUsage     = "URL that exposes the metrics endpoint. Format: HostName:Port. " +
+		commonEnvVarUsageText + hostMetricsURLEnvKey
+	hostMetricsURLEnvKey = "ORB_HOST_METRICS_URL"
+
+	grpcHostURLFlagName  = "grpc-host-url"
+	grpcHostURLEnvKey    = "ORB_GRPC_HOST_URL"
+	grpcHostURLFlagUsage = "URL on which the gRPC API for operation submission and DID resolution is exposed. " +
+		"Format: HostName:Port. The same TLS certificate and bearer tokens are used as for the REST API. " +
+		"If not set then the gRPC API is disabled. The gRPC API isn't supported in multi-tenant mode. " +
+		commonEnvVarUsageText + grpcHostURLEnvKey
 
 	syncTimeoutFlagName  = "sync-timeout"
 	syncTimeoutEnvKey    = "ORB_SYNC_TIMEOUT"
@@ -64,7 +126,7 @@ const (
 		" Alternatively, this can be set with the following environment variable: " + syncTimeoutEnvKey
 
 	vctURLFlagName  = "vct-url"
-	vctURLFlagUsage = "Verifiable credential transparency URL."
+	vctURLFlagUsage = "Verifiable credential transparency URL. " + commonEnvVarUsageText + vctURLEnvKey
 	vctURLEnvKey    = "ORB_VCT_URL"
 
 	vctMonitoringIntervalFlagName  = "vct-monitoring-interval"
@@ -114,12 +176,14 @@ const (
 	externalEndpointFlagShorthand = "e"
 	externalEndpointFlagUsage     = "External endpoint that clients use to invoke services." +
 		" This endpoint is used to generate IDs of anchor credentials and ActivityPub objects and" +
-		" should be resolvable by external clients. Format: HostName[:Port]."
+		" should be resolvable by external clients. Format: HostName[:Port]. " +
+		commonEnvVarUsageText + externalEndpointEnvKey
 	externalEndpointEnvKey = "ORB_EXTERNAL_ENDPOINT"
 
 	discoveryDomainFlagName  = "discovery-domain"
-	discoveryDomainFlagUsage = "Discovery domain for this domain." + " Format: HostName"
-	discoveryDomainEnvKey    = "ORB_DISCOVERY_DOMAIN"
+	discoveryDomainFlagUsage = "Discovery domain for this domain." + " Format: HostName. " +
+		commonEnvVarUsageText + discoveryDomainEnvKey
+	discoveryDomainEnvKey = "ORB_DISCOVERY_DOMAIN"
 
 	tlsSystemCertPoolFlagName  = "tls-systemcertpool"
 	tlsSystemCertPoolFlagUsage = "Use system certificate pool." +
@@ -134,8 +198,9 @@ const (
 
 	tlsCertificateFlagName      = "tls-certificate"
 	tlsCertificateFlagShorthand = "y"
-	tlsCertificateFlagUsage     = "TLS certificate for ORB server. " + commonEnvVarUsageText + tlsCertificateLEnvKey
-	tlsCertificateLEnvKey       = "ORB_TLS_CERTIFICATE"
+	tlsCertificateFlagUsage     = "TLS certificate for ORB server. The certificate and key files are reloaded " +
+		"when they are modified. " + commonEnvVarUsageText + tlsCertificateLEnvKey
+	tlsCertificateLEnvKey = "ORB_TLS_CERTIFICATE"
 
 	tlsKeyFlagName      = "tls-key"
 	tlsKeyFlagShorthand = "x"
@@ -152,6 +217,14 @@ const (
 	didAliasesFlagShorthand = "a"
 	didAliasesFlagUsage     = "Aliases for this did method. " + commonEnvVarUsageText + didAliasesEnvKey
 
+	didNetworkFlagName  = "did-network"
+	didNetworkEnvKey    = "DID_NETWORK"
+	didNetworkFlagUsage = "The network identifier (for example, testnet) which is appended to the DID namespace and " +
+		"aliases, so that a test network or a fork (e.g. did:orb:testnet) may coexist with the main network. " +
+		"The network is included in the anchors, the discovery (.well-known/did-orb and WebFinger) responses, " +
+		"and the method metadata of resolved documents. Only lowercase letters and digits are allowed. " +
+		"Defaults to '' (no network). " + commonEnvVarUsageText + didNetworkEnvKey
+
 	casTypeFlagName      = "cas-type"
 	casTypeFlagShorthand = "c"
 	casTypeEnvKey        = "CAS_TYPE"
@@ -198,6 +271,26 @@ const (
 	mqMaxConnectionSubscriptionsFlagUsage     = "The maximum number of subscriptions per connection. " +
 		commonEnvVarUsageText + mqMaxConnectionSubscriptionsEnvKey
 
+	mqFailoverURLsFlagName  = "mq-failover-url"
+	mqFailoverURLsEnvKey    = "MQ_FAILOVER_URLS"
+	mqFailoverURLsFlagUsage = "The URL of another node of the message broker cluster. If the node at mq-url (or the " +
+		"node that is currently connected) becomes unavailable then a connection is made to the next available " +
+		"node. Only the host and port are used; the credentials and virtual host of mq-url apply to all nodes. " +
+		"This flag can be repeated, allowing multiple nodes. " + commonEnvVarUsageText + mqFailoverURLsEnvKey
+
+	mqPublisherConfirmsEnabledFlagName  = "mq-publisher-confirms-enabled"
+	mqPublisherConfirmsEnabledEnvKey    = "MQ_PUBLISHER_CONFIRMS_ENABLED"
+	mqPublisherConfirmsEnabledFlagUsage = "Set to true to wait for the message broker to confirm each published " +
+		"message. Messages are published with the mandatory flag so that a message that can't be routed to a queue " +
+		"results in an error instead of being silently dropped. Defaults to false. " +
+		commonEnvVarUsageText + mqPublisherConfirmsEnabledEnvKey
+
+	mqPublishConfirmTimeoutFlagName  = "mq-publish-confirm-timeout"
+	mqPublishConfirmTimeoutEnvKey    = "MQ_PUBLISH_CONFIRM_TIMEOUT"
+	mqPublishConfirmTimeoutFlagUsage = "The maximum amount of time to wait for the message broker to confirm a " +
+		"published message (if publisher confirms are enabled). Defaults to 10s. " +
+		commonEnvVarUsageText + mqPublishConfirmTimeoutEnvKey
+
 	cidVersionFlagName  = "cid-version"
 	cidVersionEnvKey    = "CID_VERSION"
 	cidVersionFlagUsage = "The version of the CID format to use for generating CIDs. " +
@@ -226,6 +319,46 @@ const (
 	databasePrefixFlagUsage = "An optional prefix to be used when creating and retrieving underlying databases. " +
 		commonEnvVarUsageText + databasePrefixEnvKey
 
+	legacyDatabaseTypeFlagName  = "legacy-database-type"
+	legacyDatabaseTypeEnvKey    = "LEGACY_DATABASE_TYPE"
+	legacyDatabaseTypeFlagUsage = "The type of the legacy database when migrating to the database specified by " +
+		databaseTypeFlagName + " (for example, from CouchDB to MongoDB) without downtime. If set then all writes " +
+		"go to both databases, reads by key prefer the new database and queries are served by the legacy " +
+		"database. A verification task periodically copies missing data to the new database and reports any " +
+		"differences. Supported options: couchdb, mongodb. " + commonEnvVarUsageText + legacyDatabaseTypeEnvKey
+
+	legacyDatabaseURLFlagName  = "legacy-database-url"
+	legacyDatabaseURLEnvKey    = "LEGACY_DATABASE_URL"
+	legacyDatabaseURLFlagUsage = "The URL (or connection string) of the legacy database. " +
+		commonEnvVarUsageText + legacyDatabaseURLEnvKey
+
+	legacyDatabasePrefixFlagName  = "legacy-database-prefix"
+	legacyDatabasePrefixEnvKey    = "LEGACY_DATABASE_PREFIX"
+	legacyDatabasePrefixFlagUsage = "An optional prefix of the databases in the legacy database. " +
+		commonEnvVarUsageText + legacyDatabasePrefixEnvKey
+
+	legacyDatabaseVerifyIntervalFlagName  = "legacy-database-verify-interval"
+	legacyDatabaseVerifyIntervalEnvKey    = "LEGACY_DATABASE_VERIFY_INTERVAL"
+	legacyDatabaseVerifyIntervalFlagUsage = "The interval in which the new database is verified against (and " +
+		"missing data is copied from) the legacy database. Defaults to 1h. " +
+		commonEnvVarUsageText + legacyDatabaseVerifyIntervalEnvKey
+
+	readDatabaseURLFlagName  = "database-read-url"
+	readDatabaseURLEnvKey    = "DATABASE_READ_URL"
+	readDatabaseURLFlagUsage = "The URL (or connection string) of a read endpoint of the database (for example, " +
+		"MongoDB secondaries or a replicated CouchDB cluster). If set then queries are served by the read " +
+		"endpoint in order to increase read throughput, while writes and reads by key go to " + databaseURLFlagName +
+		". For MongoDB, the read preference (e.g. readPreference=secondaryPreferred&maxStalenessSeconds=90) " +
+		"should be set in the connection string. Not supported for memstore or with " + legacyDatabaseTypeFlagName +
+		". " + commonEnvVarUsageText + readDatabaseURLEnvKey
+
+	readDatabaseMaxStalenessFlagName  = "database-read-max-staleness"
+	readDatabaseMaxStalenessEnvKey    = "DATABASE_READ_MAX_STALENESS"
+	readDatabaseMaxStalenessFlagUsage = "The maximum time that the read endpoint may lag behind the database. " +
+		"Queries on a store that was written to (by this server) within this time are served by the primary " +
+		"database so that recent writes are always visible. If 0 then all queries go to the read endpoint. " +
+		"Defaults to 30s. " + commonEnvVarUsageText + readDatabaseMaxStalenessEnvKey
+
 	// Linter gosec flags these as "potential hardcoded credentials". They are not, hence the nolint annotations.
 	kmsSecretsDatabaseTypeFlagName      = "kms-secrets-database-type" //nolint: gosec
 	kmsSecretsDatabaseTypeEnvKey        = "KMSSECRETS_DATABASE_TYPE"  //nolint: gosec
@@ -330,6 +463,12 @@ const (
 		`Used for resolving unpublished updates for documents.` +
 		commonEnvVarUsageText + enableUpdateDocumentStoreEnvKey
 
+	updateDocumentStoreTypesFlagName = "update-document-store-types"
+	updateDocumentStoreTypesEnvKey   = "UPDATE_DOCUMENT_STORE_TYPES"
+	updateDocumentStoreTypesUsage    = "The types of operations (update, recover or deactivate) that are stored in " +
+		"the update document store until they are anchored, so that they are included when resolving the document. " +
+		"Defaults to update. " + commonEnvVarUsageText + updateDocumentStoreTypesEnvKey
+
 	includeUnpublishedOperationsFlagName = "include-unpublished-operations-in-metadata"
 	includeUnpublishedOperationsEnvKey   = "INCLUDE_UNPUBLISHED_OPERATIONS_IN_METADATA"
 	includeUnpublishedOperationsUsage    = `Set to "true" to include unpublished operations in metadata. ` +
@@ -352,20 +491,21 @@ const (
 
 	authTokensDefFlagName      = "auth-tokens-def"
 	authTokensDefFlagShorthand = "D"
-	authTokensDefFlagUsage     = "Authorization token definitions."
+	authTokensDefFlagUsage     = "Authorization token definitions. " + commonEnvVarUsageText + authTokensDefEnvKey
 	authTokensDefEnvKey        = "ORB_AUTH_TOKENS_DEF"
 
 	authTokensFlagName      = "auth-tokens"
 	authTokensFlagShorthand = "A"
-	authTokensFlagUsage     = "Authorization tokens."
+	authTokensFlagUsage     = "Authorization tokens. " + commonEnvVarUsageText + authTokensEnvKey
 	authTokensEnvKey        = "ORB_AUTH_TOKENS"
 
 	clientAuthTokensDefFlagName  = "client-auth-tokens-def"
-	clientAuthTokensDefFlagUsage = "Client authorization token definitions."
-	clientAuthTokensDefEnvKey    = "ORB_CLIENT_AUTH_TOKENS_DEF"
+	clientAuthTokensDefFlagUsage = "Client authorization token definitions. " +
+		commonEnvVarUsageText + clientAuthTokensDefEnvKey
+	clientAuthTokensDefEnvKey = "ORB_CLIENT_AUTH_TOKENS_DEF"
 
 	clientAuthTokensFlagName  = "client-auth-tokens"
-	clientAuthTokensFlagUsage = "Client authorization tokens."
+	clientAuthTokensFlagUsage = "Client authorization tokens. " + commonEnvVarUsageText + clientAuthTokensEnvKey
 	clientAuthTokensEnvKey    = "ORB_CLIENT_AUTH_TOKENS"
 
 	activityPubPageSizeFlagName      = "activitypub-page-size"
@@ -374,11 +514,27 @@ const (
 	activityPubPageSizeFlagUsage     = "The maximum page size for an ActivityPub collection or ordered collection. " +
 		commonEnvVarUsageText + activityPubPageSizeEnvKey
 
+	activityPubCollectionVisibilityFlagName  = "activitypub-collection-visibility"
+	activityPubCollectionVisibilityEnvKey    = "ACTIVITYPUB_COLLECTION_VISIBILITY"
+	activityPubCollectionVisibilityFlagUsage = "The visibility of an ActivityPub collection in the format " +
+		"collection=visibility, for example, followers=authenticated. Supported collections are: followers, " +
+		"following, witnesses, witnessing, liked, likes, shares, inbox, outbox and activities. Supported " +
+		"visibilities are: 'public' - access is determined by the authorization token definitions (default), " +
+		"'authenticated' - the request must have a valid bearer token or HTTP signature, and " +
+		"'private' - the request must have a valid bearer token or be signed by a follower or witness. " +
+		commonEnvVarUsageText + activityPubCollectionVisibilityEnvKey
+
 	devModeEnabledFlagName = "enable-dev-mode"
 	devModeEnabledEnvKey   = "DEV_MODE_ENABLED"
 	devModeEnabledUsage    = `Set to "true" to enable dev mode. ` +
 		commonEnvVarUsageText + devModeEnabledEnvKey
 
+	profilingEnabledFlagName = "enable-profiling"
+	profilingEnabledEnvKey   = "PROFILING_ENABLED"
+	profilingEnabledUsage    = `Set to "true" to expose the runtime profiling (pprof) and execution trace endpoints ` +
+		`under /debug. The endpoints require the 'admin' token (see auth-tokens). Defaults to false. ` +
+		commonEnvVarUsageText + profilingEnabledEnvKey
+
 	nodeInfoRefreshIntervalFlagName      = "nodeinfo-refresh-interval"
 	nodeInfoRefreshIntervalFlagShorthand = "R"
 	nodeInfoRefreshIntervalEnvKey        = "NODEINFO_REFRESH_INTERVAL"
@@ -400,7 +556,49 @@ const (
 	unpublishedOperationLifespanEnvKey    = "UNPUBLISHED_OPERATION_LIFETIME"
 	unpublishedOperationLifespanFlagUsage = "How long unpublished operations remain stored before expiring " +
 		"(and thus, being deleted some time later). For example, '1m' for a 1 minute lifespan. " +
-		"Defaults to 1 minute if not set. " + commonEnvVarUsageText + unpublishedOperationLifespanEnvKey
+		"Defaults to 5 minutes if not set. " + commonEnvVarUsageText + unpublishedOperationLifespanEnvKey
+
+	opQueueBloomFilterSizeFlagName  = "op-queue-bloom-filter-size"
+	opQueueBloomFilterSizeEnvKey    = "OP_QUEUE_BLOOM_FILTER_SIZE"
+	opQueueBloomFilterSizeFlagUsage = "The expected number of operations that are added to the operation queue " +
+		"within the operation expiration period. If set, an operation that's already queued isn't added again. " +
+		"An in-memory bloom filter of the DID suffixes with queued operations is consulted before the database is " +
+		"queried for a duplicate, which avoids most of the database queries during a burst of creates. " +
+		"Defaults to 0 (disabled). " +
+		commonEnvVarUsageText + opQueueBloomFilterSizeEnvKey
+
+	didSnapshotIntervalFlagName  = "did-snapshot-interval"
+	didSnapshotIntervalEnvKey    = "DID_SNAPSHOT_INTERVAL"
+	didSnapshotIntervalFlagUsage = "The number of operations that must be anchored for a DID since its last " +
+		"snapshot before a new snapshot of the DID's state is taken. When a DID is resolved, only the operations " +
+		"that were anchored after the latest snapshot are applied. A snapshot is only used if the operations " +
+		"that it includes (identified by their anchor hash links) match the operations in the operation store. " +
+		"Defaults to 0 (snapshots disabled). " +
+		commonEnvVarUsageText + didSnapshotIntervalEnvKey
+
+	didSuffixIndexSizeFlagName  = "did-suffix-index-size"
+	didSuffixIndexSizeEnvKey    = "DID_SUFFIX_INDEX_SIZE"
+	didSuffixIndexSizeFlagUsage = "The expected number of DIDs in the operation store. If set, a persisted bloom " +
+		"filter of the DID suffixes in the operation store is maintained and is consulted before the operation " +
+		"store is queried, so that unknown DIDs are rejected without querying the database. The index is built " +
+		"from the operation store on first use (or if the size changes) and is updated incrementally as " +
+		"operations are stored. Defaults to 0 (disabled). " +
+		commonEnvVarUsageText + didSuffixIndexSizeEnvKey
+
+	didSuffixIndexSyncIntervalFlagName  = "did-suffix-index-sync-interval"
+	didSuffixIndexSyncIntervalEnvKey    = "DID_SUFFIX_INDEX_SYNC_INTERVAL"
+	didSuffixIndexSyncIntervalFlagUsage = "The interval at which the DID suffix index is persisted and merged " +
+		"with the suffixes that were added by other instances. A DID that was stored by another instance may not " +
+		"be found by this instance until the next synchronization. Defaults to 10s. " +
+		commonEnvVarUsageText + didSuffixIndexSyncIntervalEnvKey
+
+	operationIdempotencyWindowFlagName  = "operation-idempotency-window"
+	operationIdempotencyWindowEnvKey    = "OPERATION_IDEMPOTENCY_WINDOW"
+	operationIdempotencyWindowFlagUsage = "The period of time for which the response to a Sidetree operation " +
+		"request with an Idempotency-Key header is kept. A request with the same key (and body) within this " +
+		"period returns the original response instead of submitting the operation again. " +
+		"Defaults to 0 (Idempotency-Key header is ignored). " +
+		commonEnvVarUsageText + operationIdempotencyWindowEnvKey
 
 	taskMgrCheckIntervalFlagName  = "task-manager-check-interval"
 	taskMgrCheckIntervalEnvKey    = "TASK_MANAGER_CHECK_INTERVAL"
@@ -408,6 +606,15 @@ const (
 		"For example, a setting of '10s' will cause the task manager to check for outstanding tasks every 10s. " +
 		"Defaults to 10 seconds if not set. " + commonEnvVarUsageText + taskMgrCheckIntervalEnvKey
 
+	taskScheduleFlagName  = "task-schedule"
+	taskScheduleEnvKey    = "TASK_SCHEDULE"
+	taskScheduleFlagUsage = "The schedule of a background task in the format taskID=schedule, which overrides " +
+		"the interval at which the task is run. The schedule is either a cron expression with five fields " +
+		"(minute, hour, day of month, month and day of week, in UTC), one of the descriptors @yearly, @monthly, " +
+		"@weekly, @daily or @hourly, or @every <duration>. For example, " +
+		"--task-schedule \"data-expiry=30 2 * * *\" --task-schedule \"vct-monitor=@every 5m\". " +
+		"The IDs of the tasks are returned by the /tasks endpoint. " + commonEnvVarUsageText + taskScheduleEnvKey
+
 	dataExpiryCheckIntervalFlagName  = "data-expiry-check-interval"
 	dataExpiryCheckIntervalEnvKey    = "DATA_EXPIRY_CHECK_INTERVAL"
 	dataExpiryCheckIntervalFlagUsage = "How frequently to check for (and delete) any expired data. " +
@@ -418,9 +625,11 @@ const (
 	followAuthPolicyFlagShorthand = "F"
 	followAuthPolicyEnvKey        = "FOLLOW_AUTH_POLICY"
 	followAuthPolicyFlagUsage     = "The type of authorization to use when a 'Follow' ActivityPub request is received. " +
-		"Possible values are: 'accept-all' and 'accept-list'. The value, 'accept-all', indicates that this " +
-		"server will accept any 'Follow' request. The value, 'accept-list', indicates that the service sending the " +
-		"'Follow' request must be included in an 'accept list'. " +
+		"Possible values are: 'accept-all', 'accept-list' and 'accept-list-hold'. The value, 'accept-all', " +
+		"indicates that this server will accept any 'Follow' request. The value, 'accept-list', indicates that the " +
+		"service sending the 'Follow' request must be included in an 'accept list'. The value, 'accept-list-hold', " +
+		"indicates that a 'Follow' request from a service in the 'accept list' is accepted automatically and a " +
+		"request from any other service is held for approval by an administrator (using the admin token). " +
 		"Defaults to 'accept-all' if not set. " + commonEnvVarUsageText + followAuthPolicyEnvKey
 
 	inviteWitnessAuthPolicyFlagName      = "invite-witness-auth-policy"
@@ -432,16 +641,106 @@ const (
 		"'Invite' witness request must be included in an 'accept list'. " +
 		"Defaults to 'accept-all' if not set. " + commonEnvVarUsageText + inviteWitnessAuthPolicyEnvKey
 
+	inviteWitnessReciprocationFlagName  = "invite-witness-reciprocation"
+	inviteWitnessReciprocationEnvKey    = "INVITE_WITNESS_RECIPROCATION"
+	inviteWitnessReciprocationFlagUsage = "The action to take after this server accepts an 'Invite' witness request " +
+		"from another service. Possible values are: 'none', 'invite-witness' and 'invite-witness-and-follow'. " +
+		"The value, 'invite-witness', indicates that this server sends a reciprocal 'Invite' witness request to the " +
+		"inviting service. The value, 'invite-witness-and-follow', indicates that this server also sends a 'Follow' " +
+		"request to the inviting service. A reciprocal 'Invite' is never itself reciprocated. " +
+		"Defaults to 'none' if not set. " + commonEnvVarUsageText + inviteWitnessReciprocationEnvKey
+
+	actorKeyPinningFlagName  = "actor-key-pinning"
+	actorKeyPinningEnvKey    = "ACTOR_KEY_PINNING"
+	actorKeyPinningFlagUsage = "The policy for pinning the public keys of followed and witness actors on first use. " +
+		"Possible values are: 'none', 'alert' and 'approve'. With 'alert', a key change is accepted and an alert is " +
+		"raised (a metric is incremented and the change is added to the key change alerts collection). With " +
+		"'approve', a changed key is also rejected until the alert is approved by an administrator. " +
+		"Defaults to 'none' if not set. " + commonEnvVarUsageText + actorKeyPinningEnvKey
+
+	deliveryReceiptRetentionFlagName  = "outbox-delivery-receipt-retention"
+	deliveryReceiptRetentionEnvKey    = "OUTBOX_DELIVERY_RECEIPT_RETENTION"
+	deliveryReceiptRetentionFlagUsage = "The period for which the delivery receipts (delivered, failed or pending) " +
+		"of each activity posted to the outbox are retained. If set, the receipts of an activity may be retrieved " +
+		"from the /services/orb/outbox/{id}/deliveries endpoint. " +
+		"Defaults to 0 (delivery receipts are not collected). " + commonEnvVarUsageText + deliveryReceiptRetentionEnvKey
+
+	pendingDeliveryRetentionFlagName  = "outbox-pending-delivery-retention"
+	pendingDeliveryRetentionEnvKey    = "OUTBOX_PENDING_DELIVERY_RETENTION"
+	pendingDeliveryRetentionFlagUsage = "The period for which the pending deliveries of each activity posted to the " +
+		"outbox are persisted. Deliveries that are still pending when the server is restarted are resumed at " +
+		"startup. Set to 0 to disable persistent delivery. " +
+		"Defaults to 168h. " + commonEnvVarUsageText + pendingDeliveryRetentionEnvKey
+
+	witnessExpirationFlagName  = "witness-expiration"
+	witnessExpirationEnvKey    = "WITNESS_EXPIRATION"
+	witnessExpirationFlagUsage = "The period within which a witness must re-confirm the witness relationship " +
+		"(by accepting a renewal 'Invite' witness request), otherwise the witness is removed from the witnesses " +
+		"collection. Defaults to 0 (witness relationships don't expire). " +
+		commonEnvVarUsageText + witnessExpirationEnvKey
+
+	witnessRenewalWindowFlagName  = "witness-renewal-window"
+	witnessRenewalWindowEnvKey    = "WITNESS_RENEWAL_WINDOW"
+	witnessRenewalWindowFlagUsage = "The period before a witness relationship expires in which a renewal " +
+		"'Invite' witness request is sent to the witness. This value must be less than the witness expiration. " +
+		"Defaults to a tenth of the witness expiration. " +
+		commonEnvVarUsageText + witnessRenewalWindowEnvKey
+
+	witnessProofBatchWindowFlagName  = "witness-proof-batch-window"
+	witnessProofBatchWindowEnvKey    = "WITNESS_PROOF_BATCH_WINDOW"
+	witnessProofBatchWindowFlagUsage = "The maximum amount of time that a witness proof is held so that it may be " +
+		"delivered to the anchor origin along with other proofs to the same origin, " +
+		"reducing the number of deliveries. " +
+		"For example, '500ms'. Note that all anchor origins must support batched proofs. " +
+		"Defaults to 0 (proofs are not batched) if not set. " + commonEnvVarUsageText + witnessProofBatchWindowEnvKey
+
+	inboxWorkersFlagName  = "inbox-workers"
+	inboxWorkersEnvKey    = "INBOX_WORKERS"
+	inboxWorkersFlagUsage = "The number of inbox activities that are processed concurrently. " +
+		"Defaults to 1 if not set. " + commonEnvVarUsageText + inboxWorkersEnvKey
+
+	inboxQueueSizeFlagName  = "inbox-queue-size"
+	inboxQueueSizeEnvKey    = "INBOX_QUEUE_SIZE"
+	inboxQueueSizeFlagUsage = "The maximum number of inbox activities that are received ahead of the workers. " +
+		"Queued activities are processed in round-robin order by sender so that a flood of activities from one " +
+		"sender doesn't prevent the activities of other senders from being processed. If greater than 1 then " +
+		"the message queue is consumed by a pool of this size. Defaults to the number of inbox workers if not set. " +
+		commonEnvVarUsageText + inboxQueueSizeEnvKey
+
+	inboxSenderMaxInFlightFlagName  = "inbox-sender-max-in-flight"
+	inboxSenderMaxInFlightEnvKey    = "INBOX_SENDER_MAX_IN_FLIGHT"
+	inboxSenderMaxInFlightFlagUsage = "The maximum number of activities from a single sender that are processed " +
+		"concurrently by the inbox workers. Defaults to 1 if not set. This parameter may be updated at runtime. " +
+		commonEnvVarUsageText + inboxSenderMaxInFlightEnvKey
+
+	inboxSenderMaxInFlightOverridesFlagName  = "inbox-sender-max-in-flight-overrides"
+	inboxSenderMaxInFlightOverridesEnvKey    = "INBOX_SENDER_MAX_IN_FLIGHT_OVERRIDES"
+	inboxSenderMaxInFlightOverridesFlagUsage = "The maximum number of concurrently processed activities for " +
+		"specific senders in the format actorIRI=limit, for example, https://orb.domain2.com/services/orb=5. " +
+		"Senders that aren't listed use the value of " + inboxSenderMaxInFlightFlagName + ". " +
+		commonEnvVarUsageText + inboxSenderMaxInFlightOverridesEnvKey
+
+	witnessProofBatchSizeFlagName  = "witness-proof-batch-size"
+	witnessProofBatchSizeEnvKey    = "WITNESS_PROOF_BATCH_SIZE"
+	witnessProofBatchSizeFlagUsage = "The maximum number of witness proofs that are delivered to an anchor origin " +
+		"in a single batch. Defaults to 20 if not set. " + commonEnvVarUsageText + witnessProofBatchSizeEnvKey
+
+	witnessProofCacheSizeFlagName  = "witness-proof-cache-size"
+	witnessProofCacheSizeEnvKey    = "WITNESS_PROOF_CACHE_SIZE"
+	witnessProofCacheSizeFlagUsage = "The maximum number of witness proofs that are cached so that a proof may be " +
+		"reused when the same anchor credential is offered again (for example, when an 'Offer' is redelivered). " +
+		"Set to 0 to disable caching. Defaults to 1000 if not set. " +
+		commonEnvVarUsageText + witnessProofCacheSizeEnvKey
+
 	httpTimeoutFlagName  = "http-timeout"
 	httpTimeoutEnvKey    = "HTTP_TIMEOUT"
 	httpTimeoutFlagUsage = "The timeout for http requests. For example, '30s' for a 30 second timeout. " +
-		"Currently this setting only applies if you're using MongoDB. " +
+		"The timeout may be overridden for specific hosts using http-host-timeouts. " +
 		commonEnvVarUsageText + httpTimeoutEnvKey
 
 	httpDialTimeoutFlagName  = "http-dial-timeout"
 	httpDialTimeoutEnvKey    = "HTTP_DIAL_TIMEOUT"
 	httpDialTimeoutFlagUsage = "The timeout for http dial. For example, '30s' for a 30 second timeout. " +
-		"Currently this setting only applies if you're using MongoDB. " +
 		commonEnvVarUsageText + httpDialTimeoutEnvKey
 
 	anchorSyncIntervalFlagName      = "sync-interval"
@@ -451,6 +750,266 @@ const (
 		"this service is following. Defaults to 1m if not set. " +
 		commonEnvVarUsageText + anchorSyncIntervalEnvKey
 
+	anchorReconcileIntervalFlagName  = "anchor-reconcile-interval"
+	anchorReconcileIntervalEnvKey    = "ANCHOR_RECONCILE_INTERVAL"
+	anchorReconcileIntervalFlagUsage = "The interval in which the anchor digests of the services that this service " +
+		"is following are compared with the anchors that this service has processed, in order to detect and " +
+		"repair gaps in federation delivery. Defaults to 1h if not set. " +
+		commonEnvVarUsageText + anchorReconcileIntervalEnvKey
+
+	anchorReconcileDaysFlagName  = "anchor-reconcile-days"
+	anchorReconcileDaysEnvKey    = "ANCHOR_RECONCILE_DAYS"
+	anchorReconcileDaysFlagUsage = "The number of days (including the current day) of anchors that are reconciled " +
+		"with the services that this service is following. Defaults to 7 if not set. " +
+		commonEnvVarUsageText + anchorReconcileDaysEnvKey
+
+	maintenanceRetryAfterFlagName  = "maintenance-retry-after"
+	maintenanceRetryAfterEnvKey    = "MAINTENANCE_RETRY_AFTER"
+	maintenanceRetryAfterFlagUsage = "The duration returned to clients in the Retry-After header when an operation " +
+		"or inbox request is rejected since the node is in maintenance mode. Defaults to 1m if not set. " +
+		commonEnvVarUsageText + maintenanceRetryAfterEnvKey
+
+	leaderElectionEnabledFlagName  = "leader-election-enabled"
+	leaderElectionEnabledEnvKey    = "LEADER_ELECTION_ENABLED"
+	leaderElectionEnabledFlagUsage = "Set to true to elect a leader among the Orb instances that share the same " +
+		"database. If enabled then scheduled background tasks (e.g. the VCT proof monitor) are only run by the " +
+		"leader and another instance takes over automatically if the leader goes down. The batch writer " +
+		"runs on every instance regardless since each instance anchors the operations in its own queue. " +
+		"Defaults to false. " + commonEnvVarUsageText + leaderElectionEnabledEnvKey
+
+	leaderLeaseDurationFlagName  = "leader-lease-duration"
+	leaderLeaseDurationEnvKey    = "LEADER_LEASE_DURATION"
+	leaderLeaseDurationFlagUsage = "The duration of the leader's lease. If the leader doesn't renew its lease " +
+		"within this duration then another instance becomes the leader. Defaults to 30s if not set. " +
+		commonEnvVarUsageText + leaderLeaseDurationEnvKey
+
+	observerShardingEnabledFlagName  = "observer-sharding-enabled"
+	observerShardingEnabledEnvKey    = "OBSERVER_SHARDING_ENABLED"
+	observerShardingEnabledFlagUsage = "Set to true to distribute the processing of anchors among the Orb instances " +
+		"that share the same database and message queue. Each instance processes the anchors of the origins that " +
+		"are assigned to its shard (using consistent hashing) and the shards are rebalanced when an instance joins " +
+		"or leaves. Defaults to false. " +
+		commonEnvVarUsageText + observerShardingEnabledEnvKey
+
+	observerShardIDFlagName  = "observer-shard-id"
+	observerShardIDEnvKey    = "OBSERVER_SHARD_ID"
+	observerShardIDFlagUsage = "The ID of this instance within the observer shard group. The ID must be unique " +
+		"and should be stable across restarts so that anchors that were forwarded to this instance's shard are " +
+		"processed after a restart. Defaults to the host name if not set. " +
+		commonEnvVarUsageText + observerShardIDEnvKey
+
+	observerShardHeartbeatIntervalFlagName  = "observer-shard-heartbeat-interval"
+	observerShardHeartbeatIntervalEnvKey    = "OBSERVER_SHARD_HEARTBEAT_INTERVAL"
+	observerShardHeartbeatIntervalFlagUsage = "The interval at which an instance announces its membership in the " +
+		"observer shard group. An instance is removed from the group if it misses three heartbeats. " +
+		"Defaults to 10s if not set. " +
+		commonEnvVarUsageText + observerShardHeartbeatIntervalEnvKey
+
+	activitySinkWebhookURLFlagName  = "activity-sink-webhook-url"
+	activitySinkWebhookURLEnvKey    = "ACTIVITY_SINK_WEBHOOK_URL"
+	activitySinkWebhookURLFlagUsage = "The URL of a webhook to which the activities in this service's inbox and " +
+		"outbox (including the hashlinks of processed anchors) are streamed as JSON events, e.g. for ingestion " +
+		"into a data warehouse. Events are delivered at least once. If not set then activities aren't streamed. " +
+		commonEnvVarUsageText + activitySinkWebhookURLEnvKey
+
+	activitySinkIntervalFlagName  = "activity-sink-interval"
+	activitySinkIntervalEnvKey    = "ACTIVITY_SINK_INTERVAL"
+	activitySinkIntervalFlagUsage = "The interval in which new activities are streamed to the activity sink. " +
+		"Defaults to 10s if not set. " +
+		commonEnvVarUsageText + activitySinkIntervalEnvKey
+
+	activitySinkBatchSizeFlagName  = "activity-sink-batch-size"
+	activitySinkBatchSizeEnvKey    = "ACTIVITY_SINK_BATCH_SIZE"
+	activitySinkBatchSizeFlagUsage = "The maximum number of events that are posted to the activity sink in a " +
+		"single request. Defaults to 100 if not set. " +
+		commonEnvVarUsageText + activitySinkBatchSizeEnvKey
+
+	acceptListRegistryURLFlagName  = "acceptlist-registry-url"
+	acceptListRegistryURLEnvKey    = "ACCEPTLIST_REGISTRY_URL"
+	acceptListRegistryURLFlagUsage = "The URL of a registry of trusted Orb services (for example, the members of " +
+		"a consortium) which is periodically fetched and merged into the accept lists. The registry is a compact " +
+		"JWS, signed with EdDSA, whose payload contains the accept lists by type, for example: " +
+		`{"issued":"2022-06-01T00:00:00Z","acceptLists":[{"type":"follow",` +
+		`"url":["https://orb.domain2.com/services/orb"]}]}. ` +
+		"Services that are removed from the registry are removed from the accept lists. If not set then no registry " +
+		"is imported. " + commonEnvVarUsageText + acceptListRegistryURLEnvKey
+
+	acceptListRegistryPublicKeyFlagName  = "acceptlist-registry-public-key"
+	acceptListRegistryPublicKeyEnvKey    = "ACCEPTLIST_REGISTRY_PUBLIC_KEY"
+	acceptListRegistryPublicKeyFlagUsage = "The base64-encoded Ed25519 public key that's used to verify the " +
+		"signature of the accept list registry. Required if " + acceptListRegistryURLFlagName + " is set. " +
+		commonEnvVarUsageText + acceptListRegistryPublicKeyEnvKey
+
+	acceptListRegistryIntervalFlagName  = "acceptlist-registry-refresh-interval"
+	acceptListRegistryIntervalEnvKey    = "ACCEPTLIST_REGISTRY_REFRESH_INTERVAL"
+	acceptListRegistryIntervalFlagUsage = "The interval at which the accept list registry is fetched. " +
+		"Defaults to 1h. " + commonEnvVarUsageText + acceptListRegistryIntervalEnvKey
+
+	graphQLEnabledFlagName  = "graphql-enabled"
+	graphQLEnabledEnvKey    = "GRAPHQL_ENABLED"
+	graphQLEnabledFlagUsage = "Set to true to expose a read-only GraphQL endpoint (/graphql) for explorer front-ends " +
+		"which allows activities, anchors and DIDs to be queried, e.g. the Announce activities of a given actor " +
+		"since a given date together with their anchors. Defaults to false. " +
+		commonEnvVarUsageText + graphQLEnabledEnvKey
+
+	universalResolverDriverEnabledFlagName  = "universal-resolver-driver-enabled"
+	universalResolverDriverEnabledEnvKey    = "UNIVERSAL_RESOLVER_DRIVER_ENABLED"
+	universalResolverDriverEnabledFlagUsage = "Set to true to expose a DIF Universal Resolver driver endpoint " +
+		"(/1.0/identifiers/{did}) which returns the DID resolution result (including driver metadata) so that " +
+		"did:orb may be added to a Universal Resolver deployment without a separate driver. Defaults to false. " +
+		commonEnvVarUsageText + universalResolverDriverEnabledEnvKey
+
+	webhooksEnabledFlagName  = "webhooks-enabled"
+	webhooksEnabledEnvKey    = "WEBHOOKS_ENABLED"
+	webhooksEnabledFlagUsage = "Set to true to expose the webhook subscription endpoints (/webhooks) which allow " +
+		"callback URLs to be registered for anchor-processed, did-updated, follow-accepted and operation-rejected " +
+		"events. Defaults to false. " +
+		commonEnvVarUsageText + webhooksEnabledEnvKey
+
+	didWebhooksEnabledFlagName  = "did-webhooks-enabled"
+	didWebhooksEnabledEnvKey    = "DID_WEBHOOKS_ENABLED"
+	didWebhooksEnabledFlagUsage = "Set to true to expose the DID webhook registration endpoint (/webhooks/did) " +
+		"which allows the controller of a DID to register (with a request that's signed by one of the " +
+		"authentication keys of the DID) a callback URL that's notified when operations on the DID are anchored " +
+		"or rejected. Webhooks must also be enabled. Defaults to false. " +
+		commonEnvVarUsageText + didWebhooksEnabledEnvKey
+
+	operationStatusEnabledFlagName  = "operation-status-enabled"
+	operationStatusEnabledEnvKey    = "OPERATION_STATUS_ENABLED"
+	operationStatusEnabledFlagUsage = "Set to true to track the anchoring status (queued, batched, anchored, " +
+		"observed or rejected) of submitted operations. The ID of a submitted operation is returned in the " +
+		"X-Orb-Operation-Id response header and the status is exposed at /sidetree/v1/operations/{id}/status. " +
+		"Defaults to false. " +
+		commonEnvVarUsageText + operationStatusEnabledEnvKey
+
+	anchorExplorerEnabledFlagName  = "anchor-explorer-enabled"
+	anchorExplorerEnabledEnvKey    = "ANCHOR_EXPLORER_ENABLED"
+	anchorExplorerEnabledFlagUsage = "Set to true to index processed anchors and expose the anchor explorer " +
+		"endpoints (/explorer/anchors) which allow anchors to be searched by time range, origin domain, witness " +
+		"or DID suffix. Defaults to false. " +
+		commonEnvVarUsageText + anchorExplorerEnabledEnvKey
+
+	migrationEnabledFlagName  = "migration-enabled"
+	migrationEnabledEnvKey    = "MIGRATION_ENABLED"
+	migrationEnabledFlagUsage = "Set to true to expose the migration endpoints (/migration) which import DIDs " +
+		"from another Sidetree network (e.g. did:ion). The batch files of the source transactions are read from " +
+		"IPFS, so ipfs-url must also be specified. Defaults to false. " +
+		commonEnvVarUsageText + migrationEnabledEnvKey
+
+	storeMigrationsEnabledFlagName  = "store-migrations-enabled"
+	storeMigrationsEnabledEnvKey    = "STORE_MIGRATIONS_ENABLED"
+	storeMigrationsEnabledFlagUsage = "Set to false to disable applying pending store schema migrations at " +
+		"startup. The migrations may then be applied using the 'migrate' command. Defaults to true. " +
+		commonEnvVarUsageText + storeMigrationsEnabledEnvKey
+
+	deliveryAnalyticsEnabledFlagName  = "delivery-analytics-enabled"
+	deliveryAnalyticsEnabledEnvKey    = "DELIVERY_ANALYTICS_ENABLED"
+	deliveryAnalyticsEnabledFlagUsage = "Set to true to collect the latency and failure counts of the deliveries " +
+		"to each follower's (and witness's) inbox. The hourly counts are exposed by the /delivery-stats endpoint. " +
+		"Defaults to false. " + commonEnvVarUsageText + deliveryAnalyticsEnabledEnvKey
+
+	inboxQuarantineEnabledFlagName  = "inbox-quarantine-enabled"
+	inboxQuarantineEnabledEnvKey    = "INBOX_QUARANTINE_ENABLED"
+	inboxQuarantineEnabledFlagUsage = "Set to true to keep the inbox requests whose HTTP signature could not be " +
+		"verified (for example, because of an invalid signature or an unknown actor) in a quarantine store, " +
+		"where they may be reviewed and processed again using the /quarantine endpoint, which requires the admin " +
+		"token. Defaults to false. " + commonEnvVarUsageText + inboxQuarantineEnabledEnvKey
+
+	accessTokensEnabledFlagName  = "access-tokens-enabled"
+	accessTokensEnabledEnvKey    = "ACCESS_TOKENS_ENABLED"
+	accessTokensEnabledFlagUsage = "Set to true to allow scoped, expiring access tokens to be issued to partner " +
+		"systems using the /access-tokens endpoint (which requires the admin token). An access token grants read " +
+		"access to specific ActivityPub collections (for example, witnesses) so that the global authorization " +
+		"tokens don't need to be shared. Defaults to false. " + commonEnvVarUsageText + accessTokensEnabledEnvKey
+
+	inboxEvidenceRetentionFlagName  = "inbox-evidence-retention"
+	inboxEvidenceRetentionEnvKey    = "INBOX_EVIDENCE_RETENTION"
+	inboxEvidenceRetentionFlagUsage = "The period for which the raw requests (headers and body) of the HTTP-signed " +
+		"activities accepted by the inbox are retained, so that disputes about the signature of an activity may " +
+		"be resolved. If set, the request of an activity may be retrieved from the /inbox-evidence?id={activity ID} " +
+		"endpoint, which requires the admin token. Defaults to 0 (requests are not retained). " +
+		commonEnvVarUsageText + inboxEvidenceRetentionEnvKey
+
+	inboxRejectionHistorySizeFlagName  = "inbox-rejection-history-size"
+	inboxRejectionHistorySizeEnvKey    = "INBOX_REJECTION_HISTORY_SIZE"
+	inboxRejectionHistorySizeFlagUsage = "The number of recent inbox rejections (for example, because of an invalid " +
+		"HTTP signature or because the actor isn't in the accept list) that are retained in memory. The recent " +
+		"rejections may be retrieved from the /inbox-rejections endpoint, which requires the admin token. " +
+		"Defaults to 100. " + commonEnvVarUsageText + inboxRejectionHistorySizeEnvKey
+
+	ipfsPubSubAnchorTopicFlagName  = "ipfs-pubsub-anchor-topic"
+	ipfsPubSubAnchorTopicEnvKey    = "IPFS_PUBSUB_ANCHOR_TOPIC"
+	ipfsPubSubAnchorTopicFlagUsage = "The IPFS pubsub topic on which anchors are announced. If set, the anchors " +
+		"written by this server are announced on the topic and the anchors announced by the services that this " +
+		"server is following are observed from the topic (in addition to ActivityPub), so that a server behind " +
+		"restrictive HTTP ingress can still observe the network. The pubsub experiment must be enabled on the " +
+		"IPFS node and ipfs-url must also be specified. Defaults to '' (disabled). " +
+		commonEnvVarUsageText + ipfsPubSubAnchorTopicEnvKey
+
+	activitySearchEnabledFlagName  = "activity-search-enabled"
+	activitySearchEnabledEnvKey    = "ACTIVITY_SEARCH_ENABLED"
+	activitySearchEnabledFlagUsage = "Set to true to index the IDs and content of the activities that are added " +
+		"to the ActivityPub store, so that activities containing a given term (for example, a CID or a DID suffix) " +
+		"may be found using the /services/orb/search?q=... endpoint. Only activities that are stored after the " +
+		"index is enabled are indexed. Defaults to false. " + commonEnvVarUsageText + activitySearchEnabledEnvKey
+
+	jsonldRemoteContextFetchEnabledFlagName  = "jsonld-remote-context-fetch-enabled"
+	jsonldRemoteContextFetchEnabledEnvKey    = "JSONLD_REMOTE_CONTEXT_FETCH_ENABLED"
+	jsonldRemoteContextFetchEnabledFlagUsage = "Set to true to retrieve a JSON-LD context that isn't in the " +
+		"context cache from its URL. The retrieved context is added to the cache so that it's retrieved only once. " +
+		"If false then an unknown context results in an error and the context must be pinned using the " +
+		"/contextpins endpoint, which requires the admin token. Defaults to false. " + commonEnvVarUsageText +
+		jsonldRemoteContextFetchEnabledEnvKey
+
+	vctLogAllowListEnabledFlagName  = "vct-log-allow-list-enabled"
+	vctLogAllowListEnabledEnvKey    = "VCT_LOG_ALLOW_LIST_ENABLED"
+	vctLogAllowListEnabledFlagUsage = "Set to true to only trust the VCT logs in the log allow list when " +
+		"processing the proofs in anchors. The allow list is managed using the /logallowlist endpoint, which " +
+		"requires the admin token, and the log specified by the vct-url parameter is added to the list on " +
+		"startup. Defaults to false. " + commonEnvVarUsageText + vctLogAllowListEnabledEnvKey
+
+	tenantsFileFlagName  = "tenants-file"
+	tenantsFileEnvKey    = "TENANTS_FILE"
+	tenantsFileFlagUsage = "The path to a YAML file that defines the tenants (logical Orb services) that are hosted " +
+		"by this server. Each tenant has its own ID, host and/or base path (which select the tenant for a request), " +
+		"external endpoint, keys and accept lists, and the tenant's stores and message queue topics are " +
+		"namespaced by the tenant ID. If not set then the server hosts a single Orb service. " +
+		commonEnvVarUsageText + tenantsFileEnvKey
+
+	operationQuotasFileFlagName  = "operation-quotas-file"
+	operationQuotasFileEnvKey    = "OPERATION_QUOTAS_FILE"
+	operationQuotasFileFlagUsage = "The path to a YAML file that defines the quotas (operations per hour and " +
+		"anchors per day) of the API keys that are provided in the X-API-Key header of requests to the operations " +
+		"endpoint, and optionally the default quotas of requests without an API key. A request that exceeds a " +
+		"quota is rejected with a 429 (Too Many Requests) response. The usage of the API keys may be retrieved " +
+		"from the /quota/usage endpoint, which requires the admin token. If not set then quotas aren't enforced. " +
+		commonEnvVarUsageText + operationQuotasFileEnvKey
+
+	persistentMetricsEnabledFlagName  = "persistent-metrics-enabled"
+	persistentMetricsEnabledEnvKey    = "PERSISTENT_METRICS_ENABLED"
+	persistentMetricsEnabledFlagUsage = "Set to false to disable persisting the business metrics counters (for " +
+		"example, the total number of anchors written and operations processed) so that the counters are reset " +
+		"when the server restarts. Defaults to true. " + commonEnvVarUsageText + persistentMetricsEnabledEnvKey
+
+	persistentMetricsInstanceFlagName  = "persistent-metrics-instance"
+	persistentMetricsInstanceEnvKey    = "PERSISTENT_METRICS_INSTANCE"
+	persistentMetricsInstanceFlagUsage = "The name under which this instance persists its metrics counters. The " +
+		"name must be unique within the cluster and it must not change across restarts (for example, the name " +
+		"of a stateful set pod). Defaults to the host name. " + commonEnvVarUsageText + persistentMetricsInstanceEnvKey
+
+	linksetSigningEnabledFlagName  = "linkset-signing-enabled"
+	linksetSigningEnabledEnvKey    = "LINKSET_SIGNING_ENABLED"
+	linksetSigningEnabledFlagUsage = "Set to true to sign the anchor linksets that are written to the CAS with a " +
+		"detached JWS using the HTTP signature key. The signature is returned by the WebCAS endpoint so that " +
+		"other servers may detect tampering with the anchor linkset in a CAS replica. Signatures returned by other " +
+		"servers are always verified. Defaults to false. " + commonEnvVarUsageText + linksetSigningEnabledEnvKey
+
+	linksetSignatureRequiredFlagName  = "linkset-signature-required"
+	linksetSignatureRequiredEnvKey    = "LINKSET_SIGNATURE_REQUIRED"
+	linksetSignatureRequiredFlagUsage = "Set to true to reject anchor linksets retrieved from other servers' WebCAS " +
+		"endpoints that aren't signed. If false then unsigned linksets are only rejected from servers that " +
+		"previously returned a signature. Defaults to false. " + commonEnvVarUsageText + linksetSignatureRequiredEnvKey
+
 	activityPubClientCacheSizeFlagName  = "apclient-cache-size"
 	activityPubClientCacheSizeEnvKey    = "ACTIVITYPUB_CLIENT_CACHE_SIZE"
 	activityPubClientCacheSizeFlagUsage = "The maximum size of an ActivityPub service and public key cache. " +
@@ -471,14 +1030,52 @@ const (
 	activityPubIRICacheExpirationFlagUsage = "The expiration time of an ActivityPub actor IRI cache. " +
 		commonEnvVarUsageText + activityPubIRICacheExpirationEnvKey
 
+	shutdownTimeoutFlagName  = "shutdown-timeout"
+	shutdownTimeoutEnvKey    = "ORB_SHUTDOWN_TIMEOUT"
+	shutdownTimeoutFlagUsage = "The maximum time to wait for in-flight requests and messages to be processed " +
+		"when the server is shutting down. For example, '30s' for a 30 second timeout. Defaults to 20s. " +
+		commonEnvVarUsageText + shutdownTimeoutEnvKey
+
+	sidetreeProtocolVersionsFlagName  = "sidetree-protocol-versions"
+	sidetreeProtocolVersionsEnvKey    = "ORB_SIDETREE_PROTOCOL_VERSIONS"
+	sidetreeProtocolVersionsFlagUsage = "The Sidetree protocol versions that are enabled " +
+		"(for example, 1.0 and 1.1). New anchors are created with the latest enabled version, while existing anchors are " +
+		"processed with the version that was in effect when they were created. Defaults to 1.0. " +
+		commonEnvVarUsageText + sidetreeProtocolVersionsEnvKey
+
+	batchCompressionAlgorithmFlagName  = "batch-compression-algorithm"
+	batchCompressionAlgorithmEnvKey    = "ORB_BATCH_COMPRESSION_ALGORITHM"
+	batchCompressionAlgorithmFlagUsage = "The algorithm (GZIP or ZSTD) that is used to compress the batch files " +
+		"that are stored in CAS. Files are always decompressed using the algorithm in the file header, although " +
+		"other nodes need to be running a version that supports the algorithm in order to read the files. " +
+		"Defaults to the compression algorithm of the protocol version. " +
+		commonEnvVarUsageText + batchCompressionAlgorithmEnvKey
+
 	// TODO: Update verification method
 )
 
 type acceptRejectPolicy string
 
 const (
-	acceptAllPolicy  acceptRejectPolicy = "accept-all"
-	acceptListPolicy acceptRejectPolicy = "accept-list"
+	acceptAllPolicy      acceptRejectPolicy = "accept-all"
+	acceptListPolicy     acceptRejectPolicy = "accept-list"
+	acceptListHoldPolicy acceptRejectPolicy = "accept-list-hold"
+)
+
+type reciprocationPolicy string
+
+const (
+	noReciprocation                     reciprocationPolicy = "none"
+	inviteWitnessReciprocation          reciprocationPolicy = "invite-witness"
+	inviteWitnessAndFollowReciprocation reciprocationPolicy = "invite-witness-and-follow"
+)
+
+type keyPinningPolicy string
+
+const (
+	noKeyPinning      keyPinningPolicy = "none"
+	alertKeyPinning   keyPinningPolicy = "alert"
+	approveKeyPinning keyPinningPolicy = "approve"
 )
 
 type tlsParameters struct {
@@ -491,6 +1088,7 @@ type tlsParameters struct {
 type orbParameters struct {
 	hostURL                          string
 	hostMetricsURL                   string
+	grpcHostURL                      string
 	vctURL                           string
 	keyID                            string
 	privateKeyBase64                 string
@@ -501,6 +1099,7 @@ type orbParameters struct {
 	discoveryDomain                  string
 	didNamespace                     string
 	didAliases                       []string
+	didNetwork                       string
 	batchWriterTimeout               time.Duration
 	casType                          string
 	ipfsURL                          string
@@ -508,6 +1107,9 @@ type orbParameters struct {
 	cidVersion                       int
 	mqURL                            string
 	mqMaxConnectionSubscriptions     int
+	mqFailoverURLs                   []string
+	mqPublisherConfirmsEnabled       bool
+	mqPublishConfirmTimeout          time.Duration
 	dbParameters                     *dbParameters
 	logLevel                         string
 	methodContext                    []string
@@ -537,7 +1139,9 @@ type orbParameters struct {
 	opQueuePoolSize                  uint
 	observerQueuePoolSize            uint
 	activityPubPageSize              int
+	apCollectionVisibility           map[string]aphandler.Visibility
 	enableDevMode                    bool
+	enableProfiling                  bool
 	nodeInfoRefreshInterval          time.Duration
 	ipfsTimeout                      time.Duration
 	databaseTimeout                  time.Duration
@@ -545,18 +1149,86 @@ type orbParameters struct {
 	httpDialTimeout                  time.Duration
 	contextProviderURLs              []string
 	unpublishedOperationLifespan     time.Duration
+	opQueueBloomFilterSize           uint
+	didSnapshotInterval              uint
+	didSuffixIndexSize               uint
+	didSuffixIndexSyncInterval       time.Duration
+	operationIdempotencyWindow       time.Duration
 	dataExpiryCheckInterval          time.Duration
 	inviteWitnessAuthPolicy          acceptRejectPolicy
+	inviteWitnessReciprocation       reciprocationPolicy
+	actorKeyPinning                  keyPinningPolicy
+	deliveryReceiptRetention         time.Duration
+	pendingDeliveryRetention         time.Duration
+	inboxEvidenceRetention           time.Duration
+	inboxRejectionHistorySize        int
+	ipfsPubSubAnchorTopic            string
+	inboxWorkers                     int
+	inboxQueueSize                   int
+	inboxSenderPolicy                *fairqueue.StaticPolicy
+	witnessExpiration                time.Duration
+	witnessRenewalWindow             time.Duration
+	witnessProofBatchWindow          time.Duration
+	witnessProofBatchSize            int
+	witnessProofCacheSize            int
 	followAuthPolicy                 acceptRejectPolicy
 	taskMgrCheckInterval             time.Duration
+	taskSchedules                    map[string]taskmgr.Schedule
 	syncPeriod                       time.Duration
+	anchorReconcileInterval          time.Duration
+	anchorReconcileDays              int
+	maintenanceRetryAfter            time.Duration
+	leaderElectionEnabled            bool
+	leaderLeaseDuration              time.Duration
+	observerShardingEnabled          bool
+	observerShardID                  string
+	observerShardHeartbeatInterval   time.Duration
+	activitySink                     *activitySinkParameters
+	acceptListRegistry               *acceptListRegistryParameters
+	graphQLEnabled                   bool
+	universalResolverDriverEnabled   bool
+	webhooksEnabled                  bool
+	didWebhooksEnabled               bool
+	operationStatusEnabled           bool
+	anchorExplorerEnabled            bool
+	migrationEnabled                 bool
+	storeMigrationsEnabled           bool
+	deliveryAnalyticsEnabled         bool
+	inboxQuarantineEnabled           bool
+	accessTokensEnabled              bool
+	activitySearchEnabled            bool
+	jsonldRemoteContextFetchEnabled  bool
+	vctLogAllowListEnabled           bool
+	faultInjection                   faultinjection.Config
+	tenants                          []*tenant.Config
+	operationQuotas                  *quota.Config
+	persistentMetricsEnabled         bool
+	persistentMetricsInstance        string
+	linksetSigningEnabled            bool
+	linksetSignatureRequired         bool
+	followAcceptList                 []*url.URL
+	inviteWitnessAcceptList          []*url.URL
 	vctMonitoringInterval            time.Duration
 	anchorStatusMonitoringInterval   time.Duration
+	legacyDatabaseVerifyInterval     time.Duration
 	anchorStatusInProcessGracePeriod time.Duration
 	apClientCacheSize                int
 	apClientCacheExpiration          time.Duration
 	apIRICacheSize                   int
 	apIRICacheExpiration             time.Duration
+	shutdownTimeout                  time.Duration
+	sidetreeProtocolVersions         []string
+	batchCompressionAlgorithm        string
+	batchCutoff                      *batchCutoffParameters
+	httpClient                       *httpClientParameters
+	httpDestinations                 map[string]httpclient.Settings
+	requestLimits                    limits.Config
+	responseCompression              *responseCompressionParams
+	corsPolicies                     []*httpserver.CORSPolicy
+	adminIPFilter                    ipfilter.Config
+	httpSignatureKey                 *signingKeyParameters
+	anchorCredentialKey              *signingKeyParameters
+	externalKMS                      *externalKMSParameters
 }
 
 type anchorCredentialParams struct {
@@ -574,6 +1246,11 @@ type dbParameters struct {
 	kmsSecretsDatabaseType   string
 	kmsSecretsDatabaseURL    string
 	kmsSecretsDatabasePrefix string
+	legacyDatabaseType       string
+	legacyDatabaseURL        string
+	legacyDatabasePrefix     string
+	readDatabaseURL          string
+	readDatabaseMaxStaleness time.Duration
 }
 
 // nolint: gocyclo,funlen
@@ -588,6 +1265,8 @@ func getOrbParameters(cmd *cobra.Command) (*orbParameters, error) {
 		return nil, err
 	}
 
+	grpcHostURL := cmdutils.GetUserSetOptionalVarFromString(cmd, grpcHostURLFlagName, grpcHostURLEnvKey)
+
 	// no need to check errors for optional flags
 	vctURL, _ := cmdutils.GetUserSetVarFromString(cmd, vctURLFlagName, vctURLEnvKey, true)
 	kmsStoreEndpoint, _ := cmdutils.GetUserSetVarFromString(cmd, kmsStoreEndpointFlagName, kmsStoreEndpointEnvKey, true) // nolint: errcheck,lll
@@ -657,6 +1336,11 @@ func getOrbParameters(cmd *cobra.Command) (*orbParameters, error) {
 		return nil, err
 	}
 
+	mqFailoverURLs, mqPublisherConfirmsEnabled, mqPublishConfirmTimeout, err := getMQFailoverParameters(cmd)
+	if err != nil {
+		return nil, err
+	}
+
 	cidVersionString, err := cmdutils.GetUserSetVarFromString(cmd, cidVersionFlagName, cidVersionEnvKey, true)
 	if err != nil {
 		return nil, err
@@ -803,6 +1487,11 @@ func getOrbParameters(cmd *cobra.Command) (*orbParameters, error) {
 		updateDocumentStoreEnabled = enable
 	}
 
+	updateDocumentStoreTypes, err := getUpdateDocumentStoreTypes(cmd)
+	if err != nil {
+		return nil, err
+	}
+
 	includeUnpublishedOperationsStr, err := cmdutils.GetUserSetVarFromString(cmd, includeUnpublishedOperationsFlagName, includeUnpublishedOperationsEnvKey, true)
 	if err != nil {
 		return nil, err
@@ -870,6 +1559,19 @@ func getOrbParameters(cmd *cobra.Command) (*orbParameters, error) {
 
 	didAliases := cmdutils.GetUserSetOptionalVarFromArrayString(cmd, didAliasesFlagName, didAliasesEnvKey)
 
+	didNetwork, err := getDIDNetwork(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	if didNetwork != "" {
+		didNamespace += ":" + didNetwork
+
+		for i, alias := range didAliases {
+			didAliases[i] = alias + ":" + didNetwork
+		}
+	}
+
 	dbParams, err := getDBParameters(cmd, kmsStoreEndpoint != "" || kmsEndpoint != "")
 	if err != nil {
 		return nil, err
@@ -925,11 +1627,21 @@ func getOrbParameters(cmd *cobra.Command) (*orbParameters, error) {
 		return nil, fmt.Errorf("client authorization tokens: %w", err)
 	}
 
+	enableProfiling, err := getProfilingEnabled(cmd, authTokens)
+	if err != nil {
+		return nil, err
+	}
+
 	activityPubPageSize, err := getActivityPubPageSize(cmd)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", activityPubPageSizeFlagName, err)
 	}
 
+	apCollectionVisibility, err := getActivityPubCollectionVisibility(cmd)
+	if err != nil {
+		return nil, err
+	}
+
 	nodeInfoRefreshInterval, err := getDuration(cmd, nodeInfoRefreshIntervalFlagName,
 		nodeInfoRefreshIntervalEnvKey, defaultNodeInfoRefreshInterval)
 	if err != nil {
@@ -967,6 +1679,34 @@ func getOrbParameters(cmd *cobra.Command) (*orbParameters, error) {
 		return nil, fmt.Errorf("%s: %w", unpublishedOperationLifespanFlagName, err)
 	}
 
+	opQueueBloomFilterSize, err := getUint(cmd, opQueueBloomFilterSizeFlagName,
+		opQueueBloomFilterSizeEnvKey)
+	if err != nil {
+		return nil, err
+	}
+
+	didSnapshotInterval, err := getUint(cmd, didSnapshotIntervalFlagName, didSnapshotIntervalEnvKey)
+	if err != nil {
+		return nil, err
+	}
+
+	didSuffixIndexSize, err := getUint(cmd, didSuffixIndexSizeFlagName, didSuffixIndexSizeEnvKey)
+	if err != nil {
+		return nil, err
+	}
+
+	didSuffixIndexSyncInterval, err := getDuration(cmd, didSuffixIndexSyncIntervalFlagName,
+		didSuffixIndexSyncIntervalEnvKey, defaultDIDSuffixIndexSyncInterval)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", didSuffixIndexSyncIntervalFlagName, err)
+	}
+
+	operationIdempotencyWindow, err := getDuration(cmd, operationIdempotencyWindowFlagName,
+		operationIdempotencyWindowEnvKey, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", operationIdempotencyWindowFlagName, err)
+	}
+
 	dataExpiryCheckInterval, err := getDuration(cmd, dataExpiryCheckIntervalFlagName,
 		dataExpiryCheckIntervalEnvKey, defaultDataExpiryCheckInterval)
 	if err != nil {
@@ -979,6 +1719,11 @@ func getOrbParameters(cmd *cobra.Command) (*orbParameters, error) {
 		return nil, fmt.Errorf("%s: %w", taskMgrCheckIntervalFlagName, err)
 	}
 
+	taskSchedules, err := getTaskSchedules(cmd)
+	if err != nil {
+		return nil, err
+	}
+
 	followAuthPolicy, err := getFollowAuthPolicy(cmd)
 	if err != nil {
 		return nil, err
@@ -989,11 +1734,66 @@ func getOrbParameters(cmd *cobra.Command) (*orbParameters, error) {
 		return nil, err
 	}
 
+	inviteWitnessReciprocation, err := getInviteWitnessReciprocation(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	actorKeyPinning, err := getActorKeyPinningPolicy(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveryReceiptRetention, err := getDuration(cmd, deliveryReceiptRetentionFlagName,
+		deliveryReceiptRetentionEnvKey, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", deliveryReceiptRetentionFlagName, err)
+	}
+
+	if deliveryReceiptRetention < 0 {
+		return nil, fmt.Errorf("%s: value must not be negative", deliveryReceiptRetentionFlagName)
+	}
+
+	pendingDeliveryRetention, err := getDuration(cmd, pendingDeliveryRetentionFlagName,
+		pendingDeliveryRetentionEnvKey, defaultPendingDeliveryRetention)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", pendingDeliveryRetentionFlagName, err)
+	}
+
+	if pendingDeliveryRetention < 0 {
+		return nil, fmt.Errorf("%s: value must not be negative", pendingDeliveryRetentionFlagName)
+	}
+
+	inboxWorkers, inboxQueueSize, inboxSenderPolicy, err := getInboxQueueParameters(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	witnessExpiration, witnessRenewalWindow, err := getWitnessExpiryParameters(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	witnessProofBatchWindow, witnessProofBatchSize, err := getWitnessProofBatchParameters(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	witnessProofCacheSize, err := getWitnessProofCacheSize(cmd)
+	if err != nil {
+		return nil, err
+	}
+
 	syncPeriod, err := getDuration(cmd, anchorSyncIntervalFlagName, anchorSyncIntervalEnvKey, defaultAnchorSyncInterval)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", anchorSyncIntervalFlagName, err)
 	}
 
+	anchorReconcileInterval, anchorReconcileDays, err := getAnchorReconcileParameters(cmd)
+	if err != nil {
+		return nil, err
+	}
+
 	vctMonitoringInterval, err := getDuration(cmd, vctMonitoringIntervalFlagName, vctMonitoringIntervalEnvKey,
 		defaultVCTMonitoringInterval)
 	if err != nil {
@@ -1006,6 +1806,12 @@ func getOrbParameters(cmd *cobra.Command) (*orbParameters, error) {
 		return nil, fmt.Errorf("%s: %w", anchorStatusMonitoringIntervalFlagName, err)
 	}
 
+	legacyDatabaseVerifyInterval, err := getDuration(cmd, legacyDatabaseVerifyIntervalFlagName,
+		legacyDatabaseVerifyIntervalEnvKey, defaultLegacyDatabaseVerifyInterval)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", legacyDatabaseVerifyIntervalFlagName, err)
+	}
+
 	anchorStatusInProcessGracePeriod, err := getDuration(cmd, anchorStatusInProcessGracePeriodFlagName, anchorStatusInProcessGracePeriodEnvKey,
 		defaultAnchorStatusInProcessGracePeriod)
 	if err != nil {
@@ -1022,423 +1828,1630 @@ func getOrbParameters(cmd *cobra.Command) (*orbParameters, error) {
 		return nil, err
 	}
 
-	return &orbParameters{
-		hostURL:                          hostURL,
-		hostMetricsURL:                   hostMetricsURL,
-		vctURL:                           vctURL,
-		kmsEndpoint:                      kmsEndpoint,
-		keyID:                            keyID,
-		privateKeyBase64:                 privateKeyBase64,
-		secretLockKeyPath:                secretLockKeyPath,
-		kmsStoreEndpoint:                 kmsStoreEndpoint,
-		discoveryDomain:                  discoveryDomain,
-		externalEndpoint:                 externalEndpoint,
-		tlsParams:                        tlsParams,
-		didNamespace:                     didNamespace,
-		didAliases:                       didAliases,
-		allowedOrigins:                   allowedOrigins,
-		casType:                          casType,
-		ipfsURL:                          ipfsURL,
-		localCASReplicateInIPFSEnabled:   localCASReplicateInIPFSEnabled,
-		cidVersion:                       cidVersion,
-		mqURL:                            mqURL,
-		mqMaxConnectionSubscriptions:     mqMaxSubscriptionsPerConnection,
-		opQueuePoolSize:                  uint(mqOpPoolSize),
-		observerQueuePoolSize:            uint(mqObserverPoolSize),
-		batchWriterTimeout:               batchWriterTimeout,
-		anchorCredentialParams:           anchorCredentialParams,
-		logLevel:                         loggingLevel,
-		dbParameters:                     dbParams,
-		discoveryDomains:                 discoveryDomains,
-		discoveryVctDomains:              discoveryVctDomains,
-		discoveryMinimumResolvers:        discoveryMinimumResolvers,
-		maxWitnessDelay:                  maxWitnessDelay,
-		syncTimeout:                      syncTimeout,
-		signWithLocalWitness:             signWithLocalWitness,
-		httpSignaturesEnabled:            httpSignaturesEnabled,
-		didDiscoveryEnabled:              didDiscoveryEnabled,
-		createDocumentStoreEnabled:       createDocumentStoreEnabled,
-		updateDocumentStoreEnabled:       updateDocumentStoreEnabled,
-		includePublishedOperations:       includePublishedOperations,
-		includeUnpublishedOperations:     includeUnpublishedOperations,
-		resolveFromAnchorOrigin:          resolveFromAnchorOrigin,
-		verifyLatestFromAnchorOrigin:     verifyLatestFromAnchorOrigin,
-		authTokenDefinitions:             authTokenDefs,
-		authTokens:                       authTokens,
-		clientAuthTokenDefinitions:       clientAuthTokenDefs,
-		clientAuthTokens:                 clientAuthTokens,
-		activityPubPageSize:              activityPubPageSize,
-		enableDevMode:                    enableDevMode,
-		nodeInfoRefreshInterval:          nodeInfoRefreshInterval,
-		ipfsTimeout:                      ipfsTimeout,
-		databaseTimeout:                  databaseTimeout,
-		contextProviderURLs:              contextProviderURLs,
-		unpublishedOperationLifespan:     unpublishedOperationLifespan,
-		dataExpiryCheckInterval:          dataExpiryCheckInterval,
-		followAuthPolicy:                 followAuthPolicy,
-		inviteWitnessAuthPolicy:          inviteWitnessAuthPolicy,
-		taskMgrCheckInterval:             taskMgrCheckInterval,
-		httpDialTimeout:                  httpDialTimeout,
-		httpTimeout:                      httpTimeout,
-		syncPeriod:                       syncPeriod,
-		vctMonitoringInterval:            vctMonitoringInterval,
-		anchorStatusMonitoringInterval:   anchorStatusMonitoringInterval,
-		anchorStatusInProcessGracePeriod: anchorStatusInProcessGracePeriod,
-		apClientCacheSize:                apClientCacheSize,
-		apClientCacheExpiration:          apClientCacheExpiration,
-		apIRICacheSize:                   apIRICacheSize,
-		apIRICacheExpiration:             apIRICacheExpiration,
-	}, nil
-}
+	shutdownTimeout, err := getDuration(cmd, shutdownTimeoutFlagName, shutdownTimeoutEnvKey, defaultShutdownTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", shutdownTimeoutFlagName, err)
+	}
 
-func getAnchorCredentialParameters(cmd *cobra.Command, externalEndpoint string) (*anchorCredentialParams, error) {
-	domain := cmdutils.GetUserSetOptionalVarFromString(cmd, anchorCredentialDomainFlagName, anchorCredentialDomainEnvKey)
-	if domain == "" {
-		domain = externalEndpoint
+	sidetreeProtocolVersions := cmdutils.GetUserSetOptionalVarFromArrayString(cmd,
+		sidetreeProtocolVersionsFlagName, sidetreeProtocolVersionsEnvKey)
+	if len(sidetreeProtocolVersions) == 0 {
+		sidetreeProtocolVersions = []string{defaultSidetreeProtocolVersion}
 	}
 
-	issuer := cmdutils.GetUserSetOptionalVarFromString(cmd, anchorCredentialIssuerFlagName, anchorCredentialIssuerEnvKey)
-	if issuer == "" {
-		issuer = externalEndpoint
+	batchCompressionAlgorithm, err := cmdutils.GetUserSetVarFromString(cmd, batchCompressionAlgorithmFlagName,
+		batchCompressionAlgorithmEnvKey, true)
+	if err != nil {
+		return nil, err
 	}
 
-	url := cmdutils.GetUserSetOptionalVarFromString(cmd, anchorCredentialURLFlagName, anchorCredentialURLEnvKey)
-	if url == "" {
-		url = fmt.Sprintf("%s/vc", externalEndpoint)
+	if batchCompressionAlgorithm != "" {
+		if !compression.IsSupported(batchCompressionAlgorithm) {
+			return nil, fmt.Errorf("unsupported value [%s] for parameter [%s]",
+				batchCompressionAlgorithm, batchCompressionAlgorithmFlagName)
+		}
+
+		batchCompressionAlgorithm = strings.ToUpper(batchCompressionAlgorithm)
 	}
 
-	signatureSuite, err := cmdutils.GetUserSetVarFromString(cmd, anchorCredentialSignatureSuiteFlagName, anchorCredentialSignatureSuiteEnvKey, false)
+	batchCutoff, err := getBatchCutoffParameters(cmd, batchWriterTimeout)
 	if err != nil {
 		return nil, err
 	}
 
-	// TODO: Add verification method here
+	httpClientParams, err := getHTTPClientParameters(cmd)
+	if err != nil {
+		return nil, err
+	}
 
-	return &anchorCredentialParams{
-		issuer:         issuer,
-		url:            url,
-		domain:         domain,
-		signatureSuite: signatureSuite,
-	}, nil
-}
+	httpDestinationParams, err := getHTTPDestinationParameters(cmd, httpTimeout, ipfsTimeout)
+	if err != nil {
+		return nil, err
+	}
 
-func getDBParameters(cmd *cobra.Command, kmOptional bool) (*dbParameters, error) {
-	databaseType, err := cmdutils.GetUserSetVarFromString(cmd, databaseTypeFlagName,
-		databaseTypeEnvKey, false)
+	requestLimits, err := getRequestLimits(cmd)
 	if err != nil {
 		return nil, err
 	}
 
-	databaseURL, err := cmdutils.GetUserSetVarFromString(cmd, databaseURLFlagName,
-		databaseURLEnvKey, true)
+	responseCompression, err := getResponseCompression(cmd)
 	if err != nil {
 		return nil, err
 	}
 
-	databasePrefix, err := cmdutils.GetUserSetVarFromString(cmd, databasePrefixFlagName,
-		databasePrefixEnvKey, true)
+	corsPolicies, err := getCORSPolicies(cmd)
 	if err != nil {
 		return nil, err
 	}
 
-	keyDatabaseType, err := cmdutils.GetUserSetVarFromString(cmd, kmsSecretsDatabaseTypeFlagName,
-		kmsSecretsDatabaseTypeEnvKey, kmOptional)
+	adminIPFilter, err := getAdminIPFilter(cmd)
 	if err != nil {
 		return nil, err
 	}
 
-	keyDatabaseURL, err := cmdutils.GetUserSetVarFromString(cmd, kmsSecretsDatabaseURLFlagName,
-		kmsSecretsDatabaseURLEnvKey, true)
+	maintenanceRetryAfter, err := getDuration(cmd, maintenanceRetryAfterFlagName, maintenanceRetryAfterEnvKey,
+		defaultMaintenanceRetryAfter)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", maintenanceRetryAfterFlagName, err)
+	}
+
+	leaderElectionEnabled, leaderLeaseDuration, err := getLeaderElectionParameters(cmd)
 	if err != nil {
 		return nil, err
 	}
 
-	keyDatabasePrefix, err := cmdutils.GetUserSetVarFromString(cmd, kmsSecretsDatabasePrefixFlagName,
-		kmsSecretsDatabasePrefixEnvKey, true)
+	observerShardingEnabled, observerShardID, observerShardHeartbeatInterval, err :=
+		getObserverShardingParameters(cmd)
 	if err != nil {
 		return nil, err
 	}
 
-	return &dbParameters{
-		databaseType:             databaseType,
-		databaseURL:              databaseURL,
-		databasePrefix:           databasePrefix,
-		kmsSecretsDatabaseType:   keyDatabaseType,
-		kmsSecretsDatabaseURL:    keyDatabaseURL,
-		kmsSecretsDatabasePrefix: keyDatabasePrefix,
-	}, nil
-}
+	activitySink, err := getActivitySinkParameters(cmd)
+	if err != nil {
+		return nil, err
+	}
 
-func getAuthTokenDefinitions(cmd *cobra.Command, flagName, envKey string, defaultDefs []*auth.TokenDef) ([]*auth.TokenDef, error) {
-	authTokenDefsStr, err := cmdutils.GetUserSetVarFromArrayString(cmd, flagName, envKey, true)
+	acceptListRegistry, err := getAcceptListRegistryParameters(cmd)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(authTokenDefsStr) == 0 {
-		return defaultDefs, nil
+	graphQLEnabled, err := getGraphQLEnabled(cmd)
+	if err != nil {
+		return nil, err
 	}
 
-	logger.Debugf("Auth tokens definition: %s", authTokenDefsStr)
+	universalResolverDriverEnabled, err := getUniversalResolverDriverEnabled(cmd)
+	if err != nil {
+		return nil, err
+	}
 
-	var authTokenDefs []*auth.TokenDef
+	webhooksEnabled, err := getWebhooksEnabled(cmd)
+	if err != nil {
+		return nil, err
+	}
 
-	for _, defStr := range authTokenDefsStr {
-		parts := strings.Split(defStr, "|")
-		if len(parts) < 1 || len(parts) > 3 {
+	didWebhooksEnabled, err := getDIDWebhooksEnabled(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	operationStatusEnabled, err := getOperationStatusEnabled(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	anchorExplorerEnabled, err := getAnchorExplorerEnabled(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	migrationEnabled, err := getMigrationEnabled(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	storeMigrationsEnabled, err := getStoreMigrationsEnabled(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveryAnalyticsEnabled, err := getDeliveryAnalyticsEnabled(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	inboxQuarantineEnabled, err := getInboxQuarantineEnabled(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	accessTokensEnabled, err := getAccessTokensEnabled(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	inboxEvidenceRetention, err := getDuration(cmd, inboxEvidenceRetentionFlagName,
+		inboxEvidenceRetentionEnvKey, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", inboxEvidenceRetentionFlagName, err)
+	}
+
+	if inboxEvidenceRetention < 0 {
+		return nil, fmt.Errorf("%s: value must not be negative", inboxEvidenceRetentionFlagName)
+	}
+
+	inboxRejectionHistorySize, err := getPositiveInt(cmd, inboxRejectionHistorySizeFlagName,
+		inboxRejectionHistorySizeEnvKey, defaultInboxRejectionHistorySize)
+	if err != nil {
+		return nil, err
+	}
+
+	ipfsPubSubAnchorTopic, err := getIPFSPubSubAnchorTopic(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	activitySearchEnabled, err := getActivitySearchEnabled(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonldRemoteContextFetchEnabled, err := getJSONLDRemoteContextFetchEnabled(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	vctLogAllowListEnabled, err := getVCTLogAllowListEnabled(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	faultInjection, err := getFaultInjectionConfig(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	tenants, err := getTenants(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	operationQuotas, err := getOperationQuotas(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	persistentMetricsEnabled, persistentMetricsInstance, err := getPersistentMetricsParameters(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	linksetSigningEnabled, linksetSignatureRequired, err := getLinksetSigningParameters(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	if grpcHostURL != "" && len(tenants) > 0 {
+		return nil, fmt.Errorf("%s is not supported in multi-tenant mode", grpcHostURLFlagName)
+	}
+
+	httpSignatureKey, anchorCredentialKey, externalKMS, err := getSigningKeyParameters(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	return &orbParameters{
+		hostURL:                          hostURL,
+		hostMetricsURL:                   hostMetricsURL,
+		grpcHostURL:                      grpcHostURL,
+		vctURL:                           vctURL,
+		kmsEndpoint:                      kmsEndpoint,
+		keyID:                            keyID,
+		privateKeyBase64:                 privateKeyBase64,
+		secretLockKeyPath:                secretLockKeyPath,
+		kmsStoreEndpoint:                 kmsStoreEndpoint,
+		discoveryDomain:                  discoveryDomain,
+		externalEndpoint:                 externalEndpoint,
+		tlsParams:                        tlsParams,
+		didNamespace:                     didNamespace,
+		didAliases:                       didAliases,
+		didNetwork:                       didNetwork,
+		allowedOrigins:                   allowedOrigins,
+		casType:                          casType,
+		ipfsURL:                          ipfsURL,
+		localCASReplicateInIPFSEnabled:   localCASReplicateInIPFSEnabled,
+		cidVersion:                       cidVersion,
+		mqURL:                            mqURL,
+		mqMaxConnectionSubscriptions:     mqMaxSubscriptionsPerConnection,
+		mqFailoverURLs:                   mqFailoverURLs,
+		mqPublisherConfirmsEnabled:       mqPublisherConfirmsEnabled,
+		mqPublishConfirmTimeout:          mqPublishConfirmTimeout,
+		opQueuePoolSize:                  uint(mqOpPoolSize),
+		observerQueuePoolSize:            uint(mqObserverPoolSize),
+		batchWriterTimeout:               batchWriterTimeout,
+		anchorCredentialParams:           anchorCredentialParams,
+		logLevel:                         loggingLevel,
+		dbParameters:                     dbParams,
+		legacyDatabaseVerifyInterval:     legacyDatabaseVerifyInterval,
+		discoveryDomains:                 discoveryDomains,
+		discoveryVctDomains:              discoveryVctDomains,
+		discoveryMinimumResolvers:        discoveryMinimumResolvers,
+		maxWitnessDelay:                  maxWitnessDelay,
+		syncTimeout:                      syncTimeout,
+		signWithLocalWitness:             signWithLocalWitness,
+		httpSignaturesEnabled:            httpSignaturesEnabled,
+		didDiscoveryEnabled:              didDiscoveryEnabled,
+		createDocumentStoreEnabled:       createDocumentStoreEnabled,
+		updateDocumentStoreEnabled:       updateDocumentStoreEnabled,
+		updateDocumentStoreTypes:         updateDocumentStoreTypes,
+		includePublishedOperations:       includePublishedOperations,
+		includeUnpublishedOperations:     includeUnpublishedOperations,
+		resolveFromAnchorOrigin:          resolveFromAnchorOrigin,
+		verifyLatestFromAnchorOrigin:     verifyLatestFromAnchorOrigin,
+		authTokenDefinitions:             authTokenDefs,
+		authTokens:                       authTokens,
+		clientAuthTokenDefinitions:       clientAuthTokenDefs,
+		clientAuthTokens:                 clientAuthTokens,
+		activityPubPageSize:              activityPubPageSize,
+		apCollectionVisibility:           apCollectionVisibility,
+		enableDevMode:                    enableDevMode,
+		enableProfiling:                  enableProfiling,
+		nodeInfoRefreshInterval:          nodeInfoRefreshInterval,
+		ipfsTimeout:                      ipfsTimeout,
+		databaseTimeout:                  databaseTimeout,
+		contextProviderURLs:              contextProviderURLs,
+		unpublishedOperationLifespan:     unpublishedOperationLifespan,
+		opQueueBloomFilterSize:           opQueueBloomFilterSize,
+		didSnapshotInterval:              didSnapshotInterval,
+		didSuffixIndexSize:               didSuffixIndexSize,
+		didSuffixIndexSyncInterval:       didSuffixIndexSyncInterval,
+		operationIdempotencyWindow:       operationIdempotencyWindow,
+		dataExpiryCheckInterval:          dataExpiryCheckInterval,
+		followAuthPolicy:                 followAuthPolicy,
+		inviteWitnessAuthPolicy:          inviteWitnessAuthPolicy,
+		inviteWitnessReciprocation:       inviteWitnessReciprocation,
+		actorKeyPinning:                  actorKeyPinning,
+		deliveryReceiptRetention:         deliveryReceiptRetention,
+		pendingDeliveryRetention:         pendingDeliveryRetention,
+		inboxEvidenceRetention:           inboxEvidenceRetention,
+		inboxRejectionHistorySize:        inboxRejectionHistorySize,
+		ipfsPubSubAnchorTopic:            ipfsPubSubAnchorTopic,
+		inboxWorkers:                     inboxWorkers,
+		inboxQueueSize:                   inboxQueueSize,
+		inboxSenderPolicy:                inboxSenderPolicy,
+		witnessExpiration:                witnessExpiration,
+		witnessRenewalWindow:             witnessRenewalWindow,
+		witnessProofBatchWindow:          witnessProofBatchWindow,
+		witnessProofBatchSize:            witnessProofBatchSize,
+		witnessProofCacheSize:            witnessProofCacheSize,
+		taskMgrCheckInterval:             taskMgrCheckInterval,
+		taskSchedules:                    taskSchedules,
+		httpDialTimeout:                  httpDialTimeout,
+		httpTimeout:                      httpTimeout,
+		syncPeriod:                       syncPeriod,
+		anchorReconcileInterval:          anchorReconcileInterval,
+		anchorReconcileDays:              anchorReconcileDays,
+		maintenanceRetryAfter:            maintenanceRetryAfter,
+		leaderElectionEnabled:            leaderElectionEnabled,
+		leaderLeaseDuration:              leaderLeaseDuration,
+		observerShardingEnabled:          observerShardingEnabled,
+		observerShardID:                  observerShardID,
+		observerShardHeartbeatInterval:   observerShardHeartbeatInterval,
+		activitySink:                     activitySink,
+		acceptListRegistry:               acceptListRegistry,
+		graphQLEnabled:                   graphQLEnabled,
+		universalResolverDriverEnabled:   universalResolverDriverEnabled,
+		webhooksEnabled:                  webhooksEnabled,
+		didWebhooksEnabled:               didWebhooksEnabled,
+		operationStatusEnabled:           operationStatusEnabled,
+		anchorExplorerEnabled:            anchorExplorerEnabled,
+		migrationEnabled:                 migrationEnabled,
+		storeMigrationsEnabled:           storeMigrationsEnabled,
+		deliveryAnalyticsEnabled:         deliveryAnalyticsEnabled,
+		inboxQuarantineEnabled:           inboxQuarantineEnabled,
+		accessTokensEnabled:              accessTokensEnabled,
+		activitySearchEnabled:            activitySearchEnabled,
+		jsonldRemoteContextFetchEnabled:  jsonldRemoteContextFetchEnabled,
+		vctLogAllowListEnabled:           vctLogAllowListEnabled,
+		faultInjection:                   faultInjection,
+		tenants:                          tenants,
+		operationQuotas:                  operationQuotas,
+		persistentMetricsEnabled:         persistentMetricsEnabled,
+		persistentMetricsInstance:        persistentMetricsInstance,
+		linksetSigningEnabled:            linksetSigningEnabled,
+		linksetSignatureRequired:         linksetSignatureRequired,
+		vctMonitoringInterval:            vctMonitoringInterval,
+		anchorStatusMonitoringInterval:   anchorStatusMonitoringInterval,
+		anchorStatusInProcessGracePeriod: anchorStatusInProcessGracePeriod,
+		apClientCacheSize:                apClientCacheSize,
+		apClientCacheExpiration:          apClientCacheExpiration,
+		apIRICacheSize:                   apIRICacheSize,
+		apIRICacheExpiration:             apIRICacheExpiration,
+		shutdownTimeout:                  shutdownTimeout,
+		sidetreeProtocolVersions:         sidetreeProtocolVersions,
+		batchCompressionAlgorithm:        batchCompressionAlgorithm,
+		batchCutoff:                      batchCutoff,
+		httpClient:                       httpClientParams,
+		httpDestinations:                 httpDestinationParams,
+		requestLimits:                    requestLimits,
+		responseCompression:              responseCompression,
+		corsPolicies:                     corsPolicies,
+		adminIPFilter:                    adminIPFilter,
+		httpSignatureKey:                 httpSignatureKey,
+		anchorCredentialKey:              anchorCredentialKey,
+		externalKMS:                      externalKMS,
+	}, nil
+}
+
+func getAnchorCredentialParameters(cmd *cobra.Command, externalEndpoint string) (*anchorCredentialParams, error) {
+	domain := cmdutils.GetUserSetOptionalVarFromString(cmd, anchorCredentialDomainFlagName, anchorCredentialDomainEnvKey)
+	if domain == "" {
+		domain = externalEndpoint
+	}
+
+	issuer := cmdutils.GetUserSetOptionalVarFromString(cmd, anchorCredentialIssuerFlagName, anchorCredentialIssuerEnvKey)
+	if issuer == "" {
+		issuer = externalEndpoint
+	}
+
+	url := cmdutils.GetUserSetOptionalVarFromString(cmd, anchorCredentialURLFlagName, anchorCredentialURLEnvKey)
+	if url == "" {
+		url = fmt.Sprintf("%s/vc", externalEndpoint)
+	}
+
+	signatureSuite, err := cmdutils.GetUserSetVarFromString(cmd, anchorCredentialSignatureSuiteFlagName, anchorCredentialSignatureSuiteEnvKey, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// TODO: Add verification method here
+
+	return &anchorCredentialParams{
+		issuer:         issuer,
+		url:            url,
+		domain:         domain,
+		signatureSuite: signatureSuite,
+	}, nil
+}
+
+func getDBParameters(cmd *cobra.Command, kmOptional bool) (*dbParameters, error) {
+	databaseType, err := cmdutils.GetUserSetVarFromString(cmd, databaseTypeFlagName,
+		databaseTypeEnvKey, false)
+	if err != nil {
+		return nil, err
+	}
+
+	databaseURL, err := cmdutils.GetUserSetVarFromString(cmd, databaseURLFlagName,
+		databaseURLEnvKey, true)
+	if err != nil {
+		return nil, err
+	}
+
+	databasePrefix, err := cmdutils.GetUserSetVarFromString(cmd, databasePrefixFlagName,
+		databasePrefixEnvKey, true)
+	if err != nil {
+		return nil, err
+	}
+
+	keyDatabaseType, err := cmdutils.GetUserSetVarFromString(cmd, kmsSecretsDatabaseTypeFlagName,
+		kmsSecretsDatabaseTypeEnvKey, kmOptional)
+	if err != nil {
+		return nil, err
+	}
+
+	keyDatabaseURL, err := cmdutils.GetUserSetVarFromString(cmd, kmsSecretsDatabaseURLFlagName,
+		kmsSecretsDatabaseURLEnvKey, true)
+	if err != nil {
+		return nil, err
+	}
+
+	keyDatabasePrefix, err := cmdutils.GetUserSetVarFromString(cmd, kmsSecretsDatabasePrefixFlagName,
+		kmsSecretsDatabasePrefixEnvKey, true)
+	if err != nil {
+		return nil, err
+	}
+
+	legacyDatabaseType := cmdutils.GetUserSetOptionalVarFromString(cmd, legacyDatabaseTypeFlagName,
+		legacyDatabaseTypeEnvKey)
+
+	if legacyDatabaseType != "" && !strings.EqualFold(legacyDatabaseType, databaseTypeCouchDBOption) &&
+		!strings.EqualFold(legacyDatabaseType, databaseTypeMongoDBOption) {
+		return nil, fmt.Errorf("unsupported legacy database type: %s", legacyDatabaseType)
+	}
+
+	readDatabaseURL, readDatabaseMaxStaleness, err := getReadDatabaseParameters(cmd, databaseType, legacyDatabaseType)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dbParameters{
+		databaseType:             databaseType,
+		databaseURL:              databaseURL,
+		databasePrefix:           databasePrefix,
+		kmsSecretsDatabaseType:   keyDatabaseType,
+		kmsSecretsDatabaseURL:    keyDatabaseURL,
+		kmsSecretsDatabasePrefix: keyDatabasePrefix,
+		legacyDatabaseType:       legacyDatabaseType,
+		legacyDatabaseURL: cmdutils.GetUserSetOptionalVarFromString(cmd, legacyDatabaseURLFlagName,
+			legacyDatabaseURLEnvKey),
+		legacyDatabasePrefix: cmdutils.GetUserSetOptionalVarFromString(cmd, legacyDatabasePrefixFlagName,
+			legacyDatabasePrefixEnvKey),
+		readDatabaseURL:          readDatabaseURL,
+		readDatabaseMaxStaleness: readDatabaseMaxStaleness,
+	}, nil
+}
+
+func getReadDatabaseParameters(cmd *cobra.Command, databaseType, legacyDatabaseType string) (string, time.Duration,
+	error) {
+	readDatabaseURL := cmdutils.GetUserSetOptionalVarFromString(cmd, readDatabaseURLFlagName, readDatabaseURLEnvKey)
+
+	if readDatabaseURL != "" {
+		if strings.EqualFold(databaseType, databaseTypeMemOption) {
+			return "", 0, fmt.Errorf("%s is not supported for database type %s", readDatabaseURLFlagName,
+				databaseType)
+		}
+
+		if legacyDatabaseType != "" {
+			return "", 0, fmt.Errorf("%s may not be used with %s", readDatabaseURLFlagName,
+				legacyDatabaseTypeFlagName)
+		}
+	}
+
+	maxStaleness, err := getDuration(cmd, readDatabaseMaxStalenessFlagName, readDatabaseMaxStalenessEnvKey,
+		defaultReadDatabaseMaxStaleness)
+	if err != nil {
+		return "", 0, fmt.Errorf("%s: %w", readDatabaseMaxStalenessFlagName, err)
+	}
+
+	return readDatabaseURL, maxStaleness, nil
+}
+
+func getAuthTokenDefinitions(cmd *cobra.Command, flagName, envKey string, defaultDefs []*auth.TokenDef) ([]*auth.TokenDef, error) {
+	authTokenDefsStr, err := cmdutils.GetUserSetVarFromArrayString(cmd, flagName, envKey, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(authTokenDefsStr) == 0 {
+		return defaultDefs, nil
+	}
+
+	logger.Debugf("Auth tokens definition: %s", authTokenDefsStr)
+
+	var authTokenDefs []*auth.TokenDef
+
+	for _, defStr := range authTokenDefsStr {
+		parts := strings.Split(defStr, "|")
+		if len(parts) < 1 || len(parts) > 3 {
 			return nil, fmt.Errorf("invalid auth token definition %s: %w", defStr, err)
 		}
 
-		var readTokens []string
-		var writeTokens []string
+		var readTokens []string
+		var writeTokens []string
+
+		if len(parts) > 1 {
+			readTokens = filterEmptyTokens(strings.Split(parts[1], "&"))
+		}
+
+		if len(parts) > 2 {
+			writeTokens = filterEmptyTokens(strings.Split(parts[2], "&"))
+		}
+
+		def := &auth.TokenDef{
+			EndpointExpression: parts[0],
+			ReadTokens:         readTokens,
+			WriteTokens:        writeTokens,
+		}
+
+		logger.Debugf("Adding auth token definition for endpoint %s - Read Tokens: %s, Write Tokens: %s",
+			def.EndpointExpression, def.ReadTokens, def.WriteTokens)
+
+		authTokenDefs = append(authTokenDefs, def)
+	}
+
+	return authTokenDefs, nil
+}
+
+func filterEmptyTokens(tokens []string) []string {
+	var nonEmptyTokens []string
+
+	for _, token := range tokens {
+		if token != "" {
+			nonEmptyTokens = append(nonEmptyTokens, token)
+		}
+	}
+
+	return nonEmptyTokens
+}
+
+func getAuthTokens(cmd *cobra.Command, flagName, envKey string, defaultTokens map[string]string) (map[string]string, error) {
+	authTokensStr, err := cmdutils.GetUserSetVarFromArrayString(cmd, flagName, envKey, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(authTokensStr) == 0 {
+		return defaultTokens, nil
+	}
+
+	authTokens := make(map[string]string)
+
+	for _, keyValStr := range authTokensStr {
+		keyVal := strings.Split(keyValStr, "=")
+
+		if len(keyVal) != 2 {
+			return nil, fmt.Errorf("invalid auth token string [%s]: %w", authTokensStr, err)
+		}
+
+		logger.Debugf("Adding token %s=%s", keyVal[0], keyVal[1])
+
+		authTokens[keyVal[0]] = keyVal[1]
+	}
+
+	return authTokens, nil
+}
+
+func getProfilingEnabled(cmd *cobra.Command, authTokens map[string]string) (bool, error) {
+	enableProfilingStr := cmdutils.GetUserSetOptionalVarFromString(cmd, profilingEnabledFlagName, profilingEnabledEnvKey)
+
+	if enableProfilingStr == "" {
+		return defaultProfilingEnabled, nil
+	}
+
+	enable, err := strconv.ParseBool(enableProfilingStr)
+	if err != nil {
+		return false, fmt.Errorf("invalid value for %s: %w", profilingEnabledFlagName, err)
+	}
+
+	if enable && authTokens[adminTokenID] == "" {
+		// The profiling endpoints expose sensitive information about the server so they must be protected.
+		return false, fmt.Errorf("an '%s' token must be configured in %s in order to enable profiling",
+			adminTokenID, authTokensFlagName)
+	}
+
+	return enable, nil
+}
+
+// activityPubCollectionPaths returns the endpoints of the ActivityPub collections, keyed by collection name.
+func activityPubCollectionPaths() map[string]string {
+	return map[string]string{
+		"followers":  aphandler.FollowersPath,
+		"following":  aphandler.FollowingPath,
+		"witnesses":  aphandler.WitnessesPath,
+		"witnessing": aphandler.WitnessingPath,
+		"liked":      aphandler.LikedPath,
+		"likes":      aphandler.LikesPath,
+		"shares":     aphandler.SharesPath,
+		"inbox":      aphandler.InboxPath,
+		"outbox":     aphandler.OutboxPath,
+		"activities": aphandler.ActivitiesPath,
+	}
+}
+
+func getActivityPubCollectionVisibility(cmd *cobra.Command) (map[string]aphandler.Visibility, error) {
+	collectionPaths := activityPubCollectionPaths()
+
+	values := cmdutils.GetUserSetOptionalVarFromArrayString(cmd, activityPubCollectionVisibilityFlagName,
+		activityPubCollectionVisibilityEnvKey)
+
+	visibility := make(map[string]aphandler.Visibility)
+
+	for _, value := range values {
+		parts := strings.Split(value, "=")
+		if len(parts) != 2 { //nolint:gomnd
+			return nil, fmt.Errorf("invalid value [%s] for parameter [%s]: expecting collection=visibility",
+				value, activityPubCollectionVisibilityFlagName)
+		}
+
+		path, ok := collectionPaths[strings.ToLower(strings.TrimSpace(parts[0]))]
+		if !ok {
+			return nil, fmt.Errorf("invalid value [%s] for parameter [%s]: unsupported collection [%s]",
+				value, activityPubCollectionVisibilityFlagName, parts[0])
+		}
+
+		v, err := aphandler.ParseVisibility(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value [%s] for parameter [%s]: %w",
+				value, activityPubCollectionVisibilityFlagName, err)
+		}
+
+		visibility[path] = v
+	}
+
+	return visibility, nil
+}
+
+func getTaskSchedules(cmd *cobra.Command) (map[string]taskmgr.Schedule, error) {
+	values := cmdutils.GetUserSetOptionalVarFromArrayString(cmd, taskScheduleFlagName, taskScheduleEnvKey)
+
+	schedules := make(map[string]taskmgr.Schedule)
+
+	for _, value := range joinTaskScheduleValues(values) {
+		i := strings.Index(value, "=")
+		if i <= 0 {
+			return nil, fmt.Errorf("invalid value [%s] for parameter [%s]: expecting taskID=schedule",
+				value, taskScheduleFlagName)
+		}
+
+		schedule, err := taskmgr.ParseSchedule(value[i+1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid value [%s] for parameter [%s]: %w", value, taskScheduleFlagName, err)
+		}
+
+		schedules[strings.TrimSpace(value[:i])] = schedule
+	}
+
+	return schedules, nil
+}
+
+// joinTaskScheduleValues joins the values that were split at the commas of a cron expression
+// (e.g. "0,30 * * * *") when the schedules are provided in the environment variable.
+func joinTaskScheduleValues(values []string) []string {
+	var joined []string
+
+	for _, v := range values {
+		if len(joined) > 0 && !strings.Contains(v, "=") {
+			joined[len(joined)-1] += "," + v
+
+			continue
+		}
+
+		joined = append(joined, v)
+	}
+
+	return joined
+}
+
+func getActivityPubPageSize(cmd *cobra.Command) (int, error) {
+	activityPubPageSizeStr, err := cmdutils.GetUserSetVarFromString(cmd, activityPubPageSizeFlagName, activityPubPageSizeEnvKey, true)
+	if err != nil {
+		return 0, err
+	}
+
+	if activityPubPageSizeStr == "" {
+		return defaultActivityPubPageSize, nil
+	}
+
+	activityPubPageSize, err := strconv.Atoi(activityPubPageSizeStr)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value [%s]: %w", activityPubPageSizeStr, err)
+	}
+
+	if activityPubPageSize <= 0 {
+		return 0, errors.New("value must be greater than 0")
+	}
+
+	return activityPubPageSize, nil
+}
+
+func getUpdateDocumentStoreTypes(cmd *cobra.Command) ([]operation.Type, error) {
+	typesStr := cmdutils.GetUserSetOptionalVarFromArrayString(cmd,
+		updateDocumentStoreTypesFlagName, updateDocumentStoreTypesEnvKey)
+	if len(typesStr) == 0 {
+		return []operation.Type{operation.TypeUpdate}, nil
+	}
+
+	var types []operation.Type
+
+	for _, t := range typesStr {
+		opType := operation.Type(strings.ToLower(strings.TrimSpace(t)))
+
+		switch opType {
+		case operation.TypeUpdate, operation.TypeRecover, operation.TypeDeactivate:
+			types = append(types, opType)
+		default:
+			return nil, fmt.Errorf("invalid value [%s] for parameter [%s]: supported operation types are %s, %s and %s",
+				t, updateDocumentStoreTypesFlagName, operation.TypeUpdate, operation.TypeRecover, operation.TypeDeactivate)
+		}
+	}
+
+	return types, nil
+}
+
+func getDuration(cmd *cobra.Command, flagName, envKey string,
+	defaultDuration time.Duration) (time.Duration, error) {
+	timeoutStr, err := cmdutils.GetUserSetVarFromString(cmd, flagName, envKey, true)
+	if err != nil {
+		return -1, err
+	}
+
+	if timeoutStr == "" {
+		return defaultDuration, nil
+	}
+
+	timeout, err := time.ParseDuration(timeoutStr)
+	if err != nil {
+		return -1, fmt.Errorf("invalid value [%s]: %w", timeoutStr, err)
+	}
+
+	return timeout, nil
+}
+
+func getMQParameters(cmd *cobra.Command) (mqURL string, mqOpPoolSize int, mqObserverPoolSize int,
+	mqMaxConnectionSubscriptions int, err error) {
+	mqURL, err = cmdutils.GetUserSetVarFromString(cmd, mqURLFlagName, mqURLEnvKey, true)
+	if err != nil {
+		return "", 0, 0, 0, fmt.Errorf("%s: %w", mqURLFlagName, err)
+	}
+
+	mqOpPoolStr, err := cmdutils.GetUserSetVarFromString(cmd, mqOpPoolFlagName, mqOpPoolEnvKey, true)
+	if err != nil {
+		return "", 0, 0, 0, fmt.Errorf("%s: %w", mqOpPoolFlagName, err)
+	}
+
+	if mqOpPoolStr != "" {
+		mqOpPoolSize, err = strconv.Atoi(mqOpPoolStr)
+		if err != nil {
+			return "", 0, 0, 0, fmt.Errorf("invalid value for %s [%s]: %w", mqOpPoolFlagName, mqOpPoolStr, err)
+		}
+	} else {
+		mqOpPoolSize = defaultMQOpPoolSize
+	}
+
+	mqObserverPoolStr, err := cmdutils.GetUserSetVarFromString(cmd, mqObserverPoolFlagName, mqObserverPoolEnvKey, true)
+	if err != nil {
+		return "", 0, 0, 0, fmt.Errorf("%s: %w", mqObserverPoolFlagName, err)
+	}
+
+	if mqObserverPoolStr != "" {
+		mqObserverPoolSize, err = strconv.Atoi(mqObserverPoolStr)
+		if err != nil {
+			return "", 0, 0, 0, fmt.Errorf("invalid value for %s [%s]: %w", mqObserverPoolFlagName, mqObserverPoolStr, err)
+		}
+	}
+
+	mqMaxConnectionSubscriptionsStr, err := cmdutils.GetUserSetVarFromString(cmd, mqMaxConnectionSubscriptionsFlagName,
+		mqMaxConnectionSubscriptionsEnvKey, true)
+	if err != nil {
+		return "", 0, 0, 0, fmt.Errorf("%s: %w", mqMaxConnectionSubscriptionsFlagName, err)
+	}
+
+	if mqMaxConnectionSubscriptionsStr != "" {
+		mqMaxConnectionSubscriptions, err = strconv.Atoi(mqMaxConnectionSubscriptionsStr)
+		if err != nil {
+			return "", 0, 0, 0, fmt.Errorf("invalid value for %s [%s]: %w", mqMaxConnectionSubscriptionsFlagName, mqMaxConnectionSubscriptionsStr, err)
+		}
+	} else {
+		mqMaxConnectionSubscriptions = mqDefaultMaxConnectionSubscriptions
+	}
+
+	return mqURL, mqOpPoolSize, mqObserverPoolSize, mqMaxConnectionSubscriptions, nil
+}
+
+func getMQFailoverParameters(cmd *cobra.Command) (failoverURLs []string, confirmsEnabled bool,
+	confirmTimeout time.Duration, err error) {
+	failoverURLs, err = cmdutils.GetUserSetVarFromArrayString(cmd, mqFailoverURLsFlagName, mqFailoverURLsEnvKey, true)
+	if err != nil {
+		return nil, false, 0, fmt.Errorf("%s: %w", mqFailoverURLsFlagName, err)
+	}
+
+	for _, u := range failoverURLs {
+		parsedURL, e := url.Parse(u)
+		if e != nil || (parsedURL.Scheme != "amqp" && parsedURL.Scheme != "amqps") || parsedURL.Host == "" {
+			return nil, false, 0, fmt.Errorf("%s: invalid AMQP URL [%s]", mqFailoverURLsFlagName, u)
+		}
+	}
+
+	confirmsEnabledStr := cmdutils.GetUserSetOptionalVarFromString(cmd, mqPublisherConfirmsEnabledFlagName,
+		mqPublisherConfirmsEnabledEnvKey)
+	if confirmsEnabledStr != "" {
+		confirmsEnabled, err = strconv.ParseBool(confirmsEnabledStr)
+		if err != nil {
+			return nil, false, 0, fmt.Errorf("invalid value for %s: %w", mqPublisherConfirmsEnabledFlagName, err)
+		}
+	}
+
+	confirmTimeout, err = getDuration(cmd, mqPublishConfirmTimeoutFlagName, mqPublishConfirmTimeoutEnvKey, 0)
+	if err != nil {
+		return nil, false, 0, fmt.Errorf("%s: %w", mqPublishConfirmTimeoutFlagName, err)
+	}
+
+	return failoverURLs, confirmsEnabled, confirmTimeout, nil
+}
+
+func getTLS(cmd *cobra.Command) (*tlsParameters, error) {
+	tlsSystemCertPoolString := cmdutils.GetUserSetOptionalVarFromString(cmd, tlsSystemCertPoolFlagName,
+		tlsSystemCertPoolEnvKey)
+
+	tlsSystemCertPool := false
+
+	if tlsSystemCertPoolString != "" {
+		var err error
+
+		tlsSystemCertPool, err = strconv.ParseBool(tlsSystemCertPoolString)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tlsCACerts := cmdutils.GetUserSetOptionalVarFromArrayString(cmd, tlsCACertsFlagName, tlsCACertsEnvKey)
+
+	tlsServeCertPath := cmdutils.GetUserSetOptionalVarFromString(cmd, tlsCertificateFlagName, tlsCertificateLEnvKey)
+
+	tlsServeKeyPath := cmdutils.GetUserSetOptionalVarFromString(cmd, tlsKeyFlagName, tlsKeyEnvKey)
+
+	return &tlsParameters{
+		systemCertPool: tlsSystemCertPool,
+		caCerts:        tlsCACerts,
+		serveCertPath:  tlsServeCertPath,
+		serveKeyPath:   tlsServeKeyPath,
+	}, nil
+}
+
+func getFollowAuthPolicy(cmd *cobra.Command) (acceptRejectPolicy, error) {
+	authType, err := cmdutils.GetUserSetVarFromString(cmd, followAuthPolicyFlagName, followAuthPolicyEnvKey, true)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", followAuthPolicyFlagName, err)
+	}
+
+	followAuthType := acceptRejectPolicy(authType)
+
+	if followAuthType == "" {
+		followAuthType = defaultFollowAuthType
+	} else if followAuthType != acceptAllPolicy && followAuthType != acceptListPolicy &&
+		followAuthType != acceptListHoldPolicy {
+		return "", fmt.Errorf("unsupported accept/reject authorization type: %s",
+			followAuthType)
+	}
+
+	return followAuthType, nil
+}
+
+func getInviteWitnessAuthPolicy(cmd *cobra.Command) (acceptRejectPolicy, error) {
+	authType, err := cmdutils.GetUserSetVarFromString(cmd, inviteWitnessAuthPolicyFlagName, inviteWitnessAuthPolicyEnvKey, true)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", inviteWitnessAuthPolicyFlagName, err)
+	}
+
+	inviteWitnessAuthType := acceptRejectPolicy(authType)
+
+	if inviteWitnessAuthType == "" {
+		inviteWitnessAuthType = defaultInviteWitnessAuthType
+	} else if inviteWitnessAuthType != acceptAllPolicy && inviteWitnessAuthType != acceptListPolicy {
+		return "", fmt.Errorf("unsupported accept/reject authorization type: %s",
+			inviteWitnessAuthType)
+	}
+
+	return inviteWitnessAuthType, nil
+}
+
+func getInviteWitnessReciprocation(cmd *cobra.Command) (reciprocationPolicy, error) {
+	value, err := cmdutils.GetUserSetVarFromString(cmd, inviteWitnessReciprocationFlagName,
+		inviteWitnessReciprocationEnvKey, true)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", inviteWitnessReciprocationFlagName, err)
+	}
+
+	policy := reciprocationPolicy(value)
+
+	switch policy {
+	case "":
+		return defaultInviteWitnessReciprocation, nil
+	case noReciprocation, inviteWitnessReciprocation, inviteWitnessAndFollowReciprocation:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("%s: unsupported reciprocation policy: %s", inviteWitnessReciprocationFlagName, policy)
+	}
+}
+
+func getActorKeyPinningPolicy(cmd *cobra.Command) (keyPinningPolicy, error) {
+	value, err := cmdutils.GetUserSetVarFromString(cmd, actorKeyPinningFlagName, actorKeyPinningEnvKey, true)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", actorKeyPinningFlagName, err)
+	}
+
+	policy := keyPinningPolicy(value)
+
+	switch policy {
+	case "":
+		return noKeyPinning, nil
+	case noKeyPinning, alertKeyPinning, approveKeyPinning:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("%s: unsupported key pinning policy: %s", actorKeyPinningFlagName, policy)
+	}
+}
+
+func getWitnessExpiryParameters(cmd *cobra.Command) (time.Duration, time.Duration, error) {
+	expiration, err := getDuration(cmd, witnessExpirationFlagName, witnessExpirationEnvKey, 0)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%s: %w", witnessExpirationFlagName, err)
+	}
+
+	if expiration < 0 {
+		return 0, 0, fmt.Errorf("%s: value must not be negative", witnessExpirationFlagName)
+	}
+
+	renewalWindow, err := getDuration(cmd, witnessRenewalWindowFlagName, witnessRenewalWindowEnvKey, 0)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%s: %w", witnessRenewalWindowFlagName, err)
+	}
+
+	if renewalWindow < 0 || (expiration > 0 && renewalWindow >= expiration) {
+		return 0, 0, fmt.Errorf("%s: value must not be negative and must be less than %s",
+			witnessRenewalWindowFlagName, witnessExpirationFlagName)
+	}
+
+	return expiration, renewalWindow, nil
+}
+
+func getWitnessProofBatchParameters(cmd *cobra.Command) (time.Duration, int, error) {
+	window, err := getDuration(cmd, witnessProofBatchWindowFlagName, witnessProofBatchWindowEnvKey, 0)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%s: %w", witnessProofBatchWindowFlagName, err)
+	}
+
+	if window < 0 {
+		return 0, 0, fmt.Errorf("%s: value must not be negative", witnessProofBatchWindowFlagName)
+	}
+
+	sizeStr, err := cmdutils.GetUserSetVarFromString(cmd, witnessProofBatchSizeFlagName,
+		witnessProofBatchSizeEnvKey, true)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%s: %w", witnessProofBatchSizeFlagName, err)
+	}
+
+	size := defaultWitnessProofBatchSize
+
+	if sizeStr != "" {
+		size, err = strconv.Atoi(sizeStr)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid value for %s [%s]: %w", witnessProofBatchSizeFlagName, sizeStr, err)
+		}
+
+		if size <= 0 {
+			return 0, 0, fmt.Errorf("%s: value must be greater than 0", witnessProofBatchSizeFlagName)
+		}
+	}
+
+	return window, size, nil
+}
+
+func getInboxQueueParameters(cmd *cobra.Command) (int, int, *fairqueue.StaticPolicy, error) {
+	workers, err := getPositiveInt(cmd, inboxWorkersFlagName, inboxWorkersEnvKey, defaultInboxWorkers)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+
+	queueSize, err := getPositiveInt(cmd, inboxQueueSizeFlagName, inboxQueueSizeEnvKey, workers)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+
+	if queueSize < workers {
+		return 0, 0, nil, fmt.Errorf("%s: value must not be less than the value of %s",
+			inboxQueueSizeFlagName, inboxWorkersFlagName)
+	}
+
+	maxInFlight, err := getPositiveInt(cmd, inboxSenderMaxInFlightFlagName, inboxSenderMaxInFlightEnvKey,
+		defaultInboxSenderMaxInFlight)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+
+	overrides, err := getInboxSenderMaxInFlightOverrides(cmd)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+
+	return workers, queueSize, fairqueue.NewStaticPolicy(maxInFlight, overrides), nil
+}
+
+func getInboxSenderMaxInFlightOverrides(cmd *cobra.Command) (map[string]int, error) {
+	values := cmdutils.GetUserSetOptionalVarFromArrayString(cmd, inboxSenderMaxInFlightOverridesFlagName,
+		inboxSenderMaxInFlightOverridesEnvKey)
+
+	overrides := make(map[string]int)
+
+	for _, value := range values {
+		i := strings.LastIndex(value, "=")
+		if i <= 0 {
+			return nil, fmt.Errorf("invalid value [%s] for parameter [%s]: expecting actorIRI=limit",
+				value, inboxSenderMaxInFlightOverridesFlagName)
+		}
+
+		actorIRI := strings.TrimSpace(value[:i])
+
+		if _, err := url.Parse(actorIRI); err != nil {
+			return nil, fmt.Errorf("invalid value [%s] for parameter [%s]: invalid actor IRI: %w",
+				value, inboxSenderMaxInFlightOverridesFlagName, err)
+		}
+
+		limit, err := strconv.Atoi(strings.TrimSpace(value[i+1:]))
+		if err != nil || limit <= 0 {
+			return nil, fmt.Errorf("invalid value [%s] for parameter [%s]: limit must be a positive integer",
+				value, inboxSenderMaxInFlightOverridesFlagName)
+		}
+
+		overrides[actorIRI] = limit
+	}
+
+	return overrides, nil
+}
+
+func getPositiveInt(cmd *cobra.Command, flagName, envKey string, defaultValue int) (int, error) {
+	valueStr, err := cmdutils.GetUserSetVarFromString(cmd, flagName, envKey, true)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", flagName, err)
+	}
+
+	if valueStr == "" {
+		return defaultValue, nil
+	}
+
+	value, err := strconv.Atoi(valueStr)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value for %s [%s]: %w", flagName, valueStr, err)
+	}
+
+	if value <= 0 {
+		return 0, fmt.Errorf("%s: value must be greater than 0", flagName)
+	}
+
+	return value, nil
+}
+
+func getWitnessProofCacheSize(cmd *cobra.Command) (int, error) {
+	sizeStr, err := cmdutils.GetUserSetVarFromString(cmd, witnessProofCacheSizeFlagName,
+		witnessProofCacheSizeEnvKey, true)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", witnessProofCacheSizeFlagName, err)
+	}
+
+	if sizeStr == "" {
+		return defaultWitnessProofCacheSize, nil
+	}
+
+	size, err := strconv.Atoi(sizeStr)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value for %s [%s]: %w", witnessProofCacheSizeFlagName, sizeStr, err)
+	}
+
+	if size < 0 {
+		return 0, fmt.Errorf("%s: value must not be negative", witnessProofCacheSizeFlagName)
+	}
+
+	return size, nil
+}
+
+func getActivityPubClientParameters(cmd *cobra.Command) (int, time.Duration, error) {
+	cacheSize := defaultActivityPubClientCacheSize
+
+	cacheSizeStr, err := cmdutils.GetUserSetVarFromString(cmd, activityPubClientCacheSizeFlagName, activityPubClientCacheSizeEnvKey, true)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if cacheSizeStr != "" {
+		cacheSize, err = strconv.Atoi(cacheSizeStr)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid value [%s] for parameter [%s]: %w",
+				cacheSizeStr, activityPubClientCacheSizeFlagName, err)
+		}
+
+		if cacheSize <= 0 {
+			return 0, 0, fmt.Errorf("value for parameter [%s] must be grater than 0", activityPubClientCacheSizeFlagName)
+		}
+	}
+
+	cacheExpiration, err := getDuration(cmd, activityPubClientCacheExpirationFlagName,
+		activityPubClientCacheExpirationEnvKey, defaultActivityPubClientCacheExpiration)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid value for parameter [%s]: %w",
+			activityPubClientCacheExpirationFlagName, err)
+	}
+
+	return cacheSize, cacheExpiration, nil
+}
+
+func getAnchorReconcileParameters(cmd *cobra.Command) (time.Duration, int, error) {
+	interval, err := getDuration(cmd, anchorReconcileIntervalFlagName, anchorReconcileIntervalEnvKey,
+		defaultAnchorReconcileInterval)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%s: %w", anchorReconcileIntervalFlagName, err)
+	}
+
+	days := defaultAnchorReconcileDays
+
+	daysStr, err := cmdutils.GetUserSetVarFromString(cmd, anchorReconcileDaysFlagName, anchorReconcileDaysEnvKey, true)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if daysStr != "" {
+		days, err = strconv.Atoi(daysStr)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid value [%s] for parameter [%s]: %w",
+				daysStr, anchorReconcileDaysFlagName, err)
+		}
+
+		if days <= 0 {
+			return 0, 0, fmt.Errorf("value for parameter [%s] must be greater than 0", anchorReconcileDaysFlagName)
+		}
+	}
 
-		if len(parts) > 1 {
-			readTokens = filterEmptyTokens(strings.Split(parts[1], "&"))
+	return interval, days, nil
+}
+
+func getLeaderElectionParameters(cmd *cobra.Command) (bool, time.Duration, error) {
+	enabled := defaultLeaderElectionEnabled
+
+	enabledStr := cmdutils.GetUserSetOptionalVarFromString(cmd, leaderElectionEnabledFlagName,
+		leaderElectionEnabledEnvKey)
+	if enabledStr != "" {
+		enable, err := strconv.ParseBool(enabledStr)
+		if err != nil {
+			return false, 0, fmt.Errorf("invalid value for %s: %w", leaderElectionEnabledFlagName, err)
 		}
 
-		if len(parts) > 2 {
-			writeTokens = filterEmptyTokens(strings.Split(parts[2], "&"))
+		enabled = enable
+	}
+
+	leaseDuration, err := getDuration(cmd, leaderLeaseDurationFlagName, leaderLeaseDurationEnvKey,
+		leaderelection.DefaultLeaseDuration)
+	if err != nil {
+		return false, 0, fmt.Errorf("%s: %w", leaderLeaseDurationFlagName, err)
+	}
+
+	return enabled, leaseDuration, nil
+}
+
+func getObserverShardingParameters(cmd *cobra.Command) (bool, string, time.Duration, error) {
+	enabled := defaultObserverShardingEnabled
+
+	enabledStr := cmdutils.GetUserSetOptionalVarFromString(cmd, observerShardingEnabledFlagName,
+		observerShardingEnabledEnvKey)
+	if enabledStr != "" {
+		enable, err := strconv.ParseBool(enabledStr)
+		if err != nil {
+			return false, "", 0, fmt.Errorf("invalid value for %s: %w", observerShardingEnabledFlagName, err)
 		}
 
-		def := &auth.TokenDef{
-			EndpointExpression: parts[0],
-			ReadTokens:         readTokens,
-			WriteTokens:        writeTokens,
+		enabled = enable
+	}
+
+	shardID := cmdutils.GetUserSetOptionalVarFromString(cmd, observerShardIDFlagName, observerShardIDEnvKey)
+	if shardID == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return false, "", 0, fmt.Errorf("%s: get host name: %w", observerShardIDFlagName, err)
 		}
 
-		logger.Debugf("Adding auth token definition for endpoint %s - Read Tokens: %s, Write Tokens: %s",
-			def.EndpointExpression, def.ReadTokens, def.WriteTokens)
+		shardID = hostname
+	}
 
-		authTokenDefs = append(authTokenDefs, def)
+	heartbeatInterval, err := getDuration(cmd, observerShardHeartbeatIntervalFlagName,
+		observerShardHeartbeatIntervalEnvKey, shard.DefaultHeartbeatInterval)
+	if err != nil {
+		return false, "", 0, fmt.Errorf("%s: %w", observerShardHeartbeatIntervalFlagName, err)
 	}
 
-	return authTokenDefs, nil
+	return enabled, shardID, heartbeatInterval, nil
 }
 
-func filterEmptyTokens(tokens []string) []string {
-	var nonEmptyTokens []string
+type activitySinkParameters struct {
+	webhookURL string
+	interval   time.Duration
+	batchSize  int
+}
 
-	for _, token := range tokens {
-		if token != "" {
-			nonEmptyTokens = append(nonEmptyTokens, token)
+func getActivitySinkParameters(cmd *cobra.Command) (*activitySinkParameters, error) {
+	webhookURL := cmdutils.GetUserSetOptionalVarFromString(cmd, activitySinkWebhookURLFlagName,
+		activitySinkWebhookURLEnvKey)
+	if webhookURL != "" {
+		u, err := url.Parse(webhookURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", activitySinkWebhookURLFlagName, err)
+		}
+
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return nil, fmt.Errorf("invalid value for %s: scheme must be http or https",
+				activitySinkWebhookURLFlagName)
 		}
 	}
 
-	return nonEmptyTokens
+	interval, err := getDuration(cmd, activitySinkIntervalFlagName, activitySinkIntervalEnvKey,
+		defaultActivitySinkInterval)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", activitySinkIntervalFlagName, err)
+	}
+
+	batchSize := defaultActivitySinkBatchSize
+
+	batchSizeStr := cmdutils.GetUserSetOptionalVarFromString(cmd, activitySinkBatchSizeFlagName,
+		activitySinkBatchSizeEnvKey)
+	if batchSizeStr != "" {
+		batchSize, err = strconv.Atoi(batchSizeStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value [%s] for parameter [%s]: %w",
+				batchSizeStr, activitySinkBatchSizeFlagName, err)
+		}
+
+		if batchSize <= 0 {
+			return nil, fmt.Errorf("value for parameter [%s] must be greater than 0", activitySinkBatchSizeFlagName)
+		}
+	}
+
+	return &activitySinkParameters{
+		webhookURL: webhookURL,
+		interval:   interval,
+		batchSize:  batchSize,
+	}, nil
 }
 
-func getAuthTokens(cmd *cobra.Command, flagName, envKey string, defaultTokens map[string]string) (map[string]string, error) {
-	authTokensStr, err := cmdutils.GetUserSetVarFromArrayString(cmd, flagName, envKey, true)
+type acceptListRegistryParameters struct {
+	url       string
+	publicKey []byte
+	interval  time.Duration
+}
+
+func getAcceptListRegistryParameters(cmd *cobra.Command) (*acceptListRegistryParameters, error) {
+	registryURL := cmdutils.GetUserSetOptionalVarFromString(cmd, acceptListRegistryURLFlagName,
+		acceptListRegistryURLEnvKey)
+	if registryURL == "" {
+		return &acceptListRegistryParameters{}, nil
+	}
+
+	u, err := url.Parse(registryURL)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("invalid value for %s: %w", acceptListRegistryURLFlagName, err)
 	}
 
-	if len(authTokensStr) == 0 {
-		return defaultTokens, nil
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid value for %s: scheme must be http or https", acceptListRegistryURLFlagName)
 	}
 
-	authTokens := make(map[string]string)
+	publicKeyBase64 := cmdutils.GetUserSetOptionalVarFromString(cmd, acceptListRegistryPublicKeyFlagName,
+		acceptListRegistryPublicKeyEnvKey)
+	if publicKeyBase64 == "" {
+		return nil, fmt.Errorf("%s is required when %s is set", acceptListRegistryPublicKeyFlagName,
+			acceptListRegistryURLFlagName)
+	}
 
-	for _, keyValStr := range authTokensStr {
-		keyVal := strings.Split(keyValStr, "=")
+	publicKey, err := base64.RawStdEncoding.DecodeString(strings.TrimRight(publicKeyBase64, "="))
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for %s: %w", acceptListRegistryPublicKeyFlagName, err)
+	}
 
-		if len(keyVal) != 2 {
-			return nil, fmt.Errorf("invalid auth token string [%s]: %w", authTokensStr, err)
-		}
+	if len(publicKey) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid value for %s: expecting an Ed25519 public key of %d bytes",
+			acceptListRegistryPublicKeyFlagName, ed25519.PublicKeySize)
+	}
 
-		logger.Debugf("Adding token %s=%s", keyVal[0], keyVal[1])
+	interval, err := getDuration(cmd, acceptListRegistryIntervalFlagName, acceptListRegistryIntervalEnvKey,
+		defaultAcceptListRegistryInterval)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", acceptListRegistryIntervalFlagName, err)
+	}
 
-		authTokens[keyVal[0]] = keyVal[1]
+	if interval <= 0 {
+		return nil, fmt.Errorf("%s: value must be greater than 0", acceptListRegistryIntervalFlagName)
 	}
 
-	return authTokens, nil
+	return &acceptListRegistryParameters{
+		url:       registryURL,
+		publicKey: publicKey,
+		interval:  interval,
+	}, nil
 }
 
-func getActivityPubPageSize(cmd *cobra.Command) (int, error) {
-	activityPubPageSizeStr, err := cmdutils.GetUserSetVarFromString(cmd, activityPubPageSizeFlagName, activityPubPageSizeEnvKey, true)
+func getGraphQLEnabled(cmd *cobra.Command) (bool, error) {
+	enabledStr := cmdutils.GetUserSetOptionalVarFromString(cmd, graphQLEnabledFlagName, graphQLEnabledEnvKey)
+	if enabledStr == "" {
+		return defaultGraphQLEnabled, nil
+	}
+
+	enabled, err := strconv.ParseBool(enabledStr)
 	if err != nil {
-		return 0, err
+		return false, fmt.Errorf("invalid value for %s: %w", graphQLEnabledFlagName, err)
 	}
 
-	if activityPubPageSizeStr == "" {
-		return defaultActivityPubPageSize, nil
+	return enabled, nil
+}
+
+func getUniversalResolverDriverEnabled(cmd *cobra.Command) (bool, error) {
+	enabledStr := cmdutils.GetUserSetOptionalVarFromString(cmd, universalResolverDriverEnabledFlagName,
+		universalResolverDriverEnabledEnvKey)
+	if enabledStr == "" {
+		return defaultUniversalResolverDriverEnabled, nil
 	}
 
-	activityPubPageSize, err := strconv.Atoi(activityPubPageSizeStr)
+	enabled, err := strconv.ParseBool(enabledStr)
 	if err != nil {
-		return 0, fmt.Errorf("invalid value [%s]: %w", activityPubPageSizeStr, err)
+		return false, fmt.Errorf("invalid value for %s: %w", universalResolverDriverEnabledFlagName, err)
 	}
 
-	if activityPubPageSize <= 0 {
-		return 0, errors.New("value must be greater than 0")
+	return enabled, nil
+}
+
+func getWebhooksEnabled(cmd *cobra.Command) (bool, error) {
+	enabledStr := cmdutils.GetUserSetOptionalVarFromString(cmd, webhooksEnabledFlagName, webhooksEnabledEnvKey)
+	if enabledStr == "" {
+		return defaultWebhooksEnabled, nil
 	}
 
-	return activityPubPageSize, nil
+	enabled, err := strconv.ParseBool(enabledStr)
+	if err != nil {
+		return false, fmt.Errorf("invalid value for %s: %w", webhooksEnabledFlagName, err)
+	}
+
+	return enabled, nil
 }
 
-func getDuration(cmd *cobra.Command, flagName, envKey string,
-	defaultDuration time.Duration) (time.Duration, error) {
-	timeoutStr, err := cmdutils.GetUserSetVarFromString(cmd, flagName, envKey, true)
+func getDIDWebhooksEnabled(cmd *cobra.Command) (bool, error) {
+	enabledStr := cmdutils.GetUserSetOptionalVarFromString(cmd, didWebhooksEnabledFlagName, didWebhooksEnabledEnvKey)
+	if enabledStr == "" {
+		return defaultDIDWebhooksEnabled, nil
+	}
+
+	enabled, err := strconv.ParseBool(enabledStr)
 	if err != nil {
-		return -1, err
+		return false, fmt.Errorf("invalid value for %s: %w", didWebhooksEnabledFlagName, err)
 	}
 
-	if timeoutStr == "" {
-		return defaultDuration, nil
+	return enabled, nil
+}
+
+func getOperationStatusEnabled(cmd *cobra.Command) (bool, error) {
+	enabledStr := cmdutils.GetUserSetOptionalVarFromString(cmd, operationStatusEnabledFlagName,
+		operationStatusEnabledEnvKey)
+	if enabledStr == "" {
+		return defaultOperationStatusEnabled, nil
 	}
 
-	timeout, err := time.ParseDuration(timeoutStr)
+	enabled, err := strconv.ParseBool(enabledStr)
 	if err != nil {
-		return -1, fmt.Errorf("invalid value [%s]: %w", timeoutStr, err)
+		return false, fmt.Errorf("invalid value for %s: %w", operationStatusEnabledFlagName, err)
 	}
 
-	return timeout, nil
+	return enabled, nil
 }
 
-func getMQParameters(cmd *cobra.Command) (mqURL string, mqOpPoolSize int, mqObserverPoolSize int,
-	mqMaxConnectionSubscriptions int, err error) {
-	mqURL, err = cmdutils.GetUserSetVarFromString(cmd, mqURLFlagName, mqURLEnvKey, true)
-	if err != nil {
-		return "", 0, 0, 0, fmt.Errorf("%s: %w", mqURLFlagName, err)
+func getAnchorExplorerEnabled(cmd *cobra.Command) (bool, error) {
+	enabledStr := cmdutils.GetUserSetOptionalVarFromString(cmd, anchorExplorerEnabledFlagName,
+		anchorExplorerEnabledEnvKey)
+	if enabledStr == "" {
+		return defaultAnchorExplorerEnabled, nil
 	}
 
-	mqOpPoolStr, err := cmdutils.GetUserSetVarFromString(cmd, mqOpPoolFlagName, mqOpPoolEnvKey, true)
+	enabled, err := strconv.ParseBool(enabledStr)
 	if err != nil {
-		return "", 0, 0, 0, fmt.Errorf("%s: %w", mqOpPoolFlagName, err)
+		return false, fmt.Errorf("invalid value for %s: %w", anchorExplorerEnabledFlagName, err)
 	}
 
-	if mqOpPoolStr != "" {
-		mqOpPoolSize, err = strconv.Atoi(mqOpPoolStr)
-		if err != nil {
-			return "", 0, 0, 0, fmt.Errorf("invalid value for %s [%s]: %w", mqOpPoolFlagName, mqOpPoolStr, err)
-		}
-	} else {
-		mqOpPoolSize = defaultMQOpPoolSize
+	return enabled, nil
+}
+
+func getMigrationEnabled(cmd *cobra.Command) (bool, error) {
+	enabledStr := cmdutils.GetUserSetOptionalVarFromString(cmd, migrationEnabledFlagName, migrationEnabledEnvKey)
+	if enabledStr == "" {
+		return defaultMigrationEnabled, nil
 	}
 
-	mqObserverPoolStr, err := cmdutils.GetUserSetVarFromString(cmd, mqObserverPoolFlagName, mqObserverPoolEnvKey, true)
+	enabled, err := strconv.ParseBool(enabledStr)
 	if err != nil {
-		return "", 0, 0, 0, fmt.Errorf("%s: %w", mqObserverPoolFlagName, err)
+		return false, fmt.Errorf("invalid value for %s: %w", migrationEnabledFlagName, err)
 	}
 
-	if mqObserverPoolStr != "" {
-		mqObserverPoolSize, err = strconv.Atoi(mqObserverPoolStr)
-		if err != nil {
-			return "", 0, 0, 0, fmt.Errorf("invalid value for %s [%s]: %w", mqObserverPoolFlagName, mqObserverPoolStr, err)
+	if enabled && cmdutils.GetUserSetOptionalVarFromString(cmd, ipfsURLFlagName, ipfsURLEnvKey) == "" {
+		return false, fmt.Errorf("%s must be specified when %s is true", ipfsURLFlagName, migrationEnabledFlagName)
+	}
+
+	return enabled, nil
+}
+
+func getDIDNetwork(cmd *cobra.Command) (string, error) {
+	network := cmdutils.GetUserSetOptionalVarFromString(cmd, didNetworkFlagName, didNetworkEnvKey)
+
+	for _, c := range network {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') {
+			return "", fmt.Errorf("%s: invalid value [%s] - only lowercase letters and digits are allowed",
+				didNetworkFlagName, network)
 		}
 	}
 
-	mqMaxConnectionSubscriptionsStr, err := cmdutils.GetUserSetVarFromString(cmd, mqMaxConnectionSubscriptionsFlagName,
-		mqMaxConnectionSubscriptionsEnvKey, true)
-	if err != nil {
-		return "", 0, 0, 0, fmt.Errorf("%s: %w", mqMaxConnectionSubscriptionsFlagName, err)
+	return network, nil
+}
+
+func getIPFSPubSubAnchorTopic(cmd *cobra.Command) (string, error) {
+	topic := cmdutils.GetUserSetOptionalVarFromString(cmd, ipfsPubSubAnchorTopicFlagName, ipfsPubSubAnchorTopicEnvKey)
+	if topic == "" {
+		return "", nil
 	}
 
-	if mqMaxConnectionSubscriptionsStr != "" {
-		mqMaxConnectionSubscriptions, err = strconv.Atoi(mqMaxConnectionSubscriptionsStr)
-		if err != nil {
-			return "", 0, 0, 0, fmt.Errorf("invalid value for %s [%s]: %w", mqMaxConnectionSubscriptionsFlagName, mqMaxConnectionSubscriptionsStr, err)
-		}
-	} else {
-		mqMaxConnectionSubscriptions = mqDefaultMaxConnectionSubscriptions
+	if cmdutils.GetUserSetOptionalVarFromString(cmd, ipfsURLFlagName, ipfsURLEnvKey) == "" {
+		return "", fmt.Errorf("%s must be specified when %s is set", ipfsURLFlagName, ipfsPubSubAnchorTopicFlagName)
 	}
 
-	return mqURL, mqOpPoolSize, mqObserverPoolSize, mqMaxConnectionSubscriptions, nil
+	return topic, nil
 }
 
-func getTLS(cmd *cobra.Command) (*tlsParameters, error) {
-	tlsSystemCertPoolString := cmdutils.GetUserSetOptionalVarFromString(cmd, tlsSystemCertPoolFlagName,
-		tlsSystemCertPoolEnvKey)
+func getStoreMigrationsEnabled(cmd *cobra.Command) (bool, error) {
+	enabledStr := cmdutils.GetUserSetOptionalVarFromString(cmd, storeMigrationsEnabledFlagName,
+		storeMigrationsEnabledEnvKey)
+	if enabledStr == "" {
+		return defaultStoreMigrationsEnabled, nil
+	}
 
-	tlsSystemCertPool := false
+	enabled, err := strconv.ParseBool(enabledStr)
+	if err != nil {
+		return false, fmt.Errorf("invalid value for %s: %w", storeMigrationsEnabledFlagName, err)
+	}
 
-	if tlsSystemCertPoolString != "" {
-		var err error
+	return enabled, nil
+}
 
-		tlsSystemCertPool, err = strconv.ParseBool(tlsSystemCertPoolString)
-		if err != nil {
-			return nil, err
-		}
+func getDeliveryAnalyticsEnabled(cmd *cobra.Command) (bool, error) {
+	enabledStr := cmdutils.GetUserSetOptionalVarFromString(cmd, deliveryAnalyticsEnabledFlagName,
+		deliveryAnalyticsEnabledEnvKey)
+	if enabledStr == "" {
+		return defaultDeliveryAnalyticsEnabled, nil
 	}
 
-	tlsCACerts := cmdutils.GetUserSetOptionalVarFromArrayString(cmd, tlsCACertsFlagName, tlsCACertsEnvKey)
+	enabled, err := strconv.ParseBool(enabledStr)
+	if err != nil {
+		return false, fmt.Errorf("invalid value for %s: %w", deliveryAnalyticsEnabledFlagName, err)
+	}
 
-	tlsServeCertPath := cmdutils.GetUserSetOptionalVarFromString(cmd, tlsCertificateFlagName, tlsCertificateLEnvKey)
+	return enabled, nil
+}
 
-	tlsServeKeyPath := cmdutils.GetUserSetOptionalVarFromString(cmd, tlsKeyFlagName, tlsKeyEnvKey)
+func getInboxQuarantineEnabled(cmd *cobra.Command) (bool, error) {
+	enabledStr := cmdutils.GetUserSetOptionalVarFromString(cmd, inboxQuarantineEnabledFlagName,
+		inboxQuarantineEnabledEnvKey)
+	if enabledStr == "" {
+		return defaultInboxQuarantineEnabled, nil
+	}
 
-	return &tlsParameters{
-		systemCertPool: tlsSystemCertPool,
-		caCerts:        tlsCACerts,
-		serveCertPath:  tlsServeCertPath,
-		serveKeyPath:   tlsServeKeyPath,
-	}, nil
+	enabled, err := strconv.ParseBool(enabledStr)
+	if err != nil {
+		return false, fmt.Errorf("invalid value for %s: %w", inboxQuarantineEnabledFlagName, err)
+	}
+
+	return enabled, nil
 }
 
-func getFollowAuthPolicy(cmd *cobra.Command) (acceptRejectPolicy, error) {
-	authType, err := cmdutils.GetUserSetVarFromString(cmd, followAuthPolicyFlagName, followAuthPolicyEnvKey, true)
+func getAccessTokensEnabled(cmd *cobra.Command) (bool, error) {
+	enabledStr := cmdutils.GetUserSetOptionalVarFromString(cmd, accessTokensEnabledFlagName,
+		accessTokensEnabledEnvKey)
+	if enabledStr == "" {
+		return defaultAccessTokensEnabled, nil
+	}
+
+	enabled, err := strconv.ParseBool(enabledStr)
 	if err != nil {
-		return "", fmt.Errorf("%s: %w", followAuthPolicyFlagName, err)
+		return false, fmt.Errorf("invalid value for %s: %w", accessTokensEnabledFlagName, err)
 	}
 
-	followAuthType := acceptRejectPolicy(authType)
+	return enabled, nil
+}
 
-	if followAuthType == "" {
-		followAuthType = defaultFollowAuthType
-	} else if followAuthType != acceptAllPolicy && followAuthType != acceptListPolicy {
-		return "", fmt.Errorf("unsupported accept/reject authorization type: %s",
-			followAuthType)
+func getActivitySearchEnabled(cmd *cobra.Command) (bool, error) {
+	enabledStr := cmdutils.GetUserSetOptionalVarFromString(cmd, activitySearchEnabledFlagName,
+		activitySearchEnabledEnvKey)
+	if enabledStr == "" {
+		return defaultActivitySearchEnabled, nil
 	}
 
-	return followAuthType, nil
+	enabled, err := strconv.ParseBool(enabledStr)
+	if err != nil {
+		return false, fmt.Errorf("invalid value for %s: %w", activitySearchEnabledFlagName, err)
+	}
+
+	return enabled, nil
 }
 
-func getInviteWitnessAuthPolicy(cmd *cobra.Command) (acceptRejectPolicy, error) {
-	authType, err := cmdutils.GetUserSetVarFromString(cmd, inviteWitnessAuthPolicyFlagName, inviteWitnessAuthPolicyEnvKey, true)
+func getJSONLDRemoteContextFetchEnabled(cmd *cobra.Command) (bool, error) {
+	enabledStr := cmdutils.GetUserSetOptionalVarFromString(cmd, jsonldRemoteContextFetchEnabledFlagName,
+		jsonldRemoteContextFetchEnabledEnvKey)
+	if enabledStr == "" {
+		return defaultJSONLDRemoteContextFetchEnabled, nil
+	}
+
+	enabled, err := strconv.ParseBool(enabledStr)
 	if err != nil {
-		return "", fmt.Errorf("%s: %w", inviteWitnessAuthPolicyFlagName, err)
+		return false, fmt.Errorf("invalid value for %s: %w", jsonldRemoteContextFetchEnabledFlagName, err)
 	}
 
-	inviteWitnessAuthType := acceptRejectPolicy(authType)
+	return enabled, nil
+}
 
-	if inviteWitnessAuthType == "" {
-		inviteWitnessAuthType = defaultInviteWitnessAuthType
-	} else if inviteWitnessAuthType != acceptAllPolicy && inviteWitnessAuthType != acceptListPolicy {
-		return "", fmt.Errorf("unsupported accept/reject authorization type: %s",
-			inviteWitnessAuthType)
+func getVCTLogAllowListEnabled(cmd *cobra.Command) (bool, error) {
+	enabledStr := cmdutils.GetUserSetOptionalVarFromString(cmd, vctLogAllowListEnabledFlagName,
+		vctLogAllowListEnabledEnvKey)
+	if enabledStr == "" {
+		return defaultVCTLogAllowListEnabled, nil
 	}
 
-	return inviteWitnessAuthType, nil
+	enabled, err := strconv.ParseBool(enabledStr)
+	if err != nil {
+		return false, fmt.Errorf("invalid value for %s: %w", vctLogAllowListEnabledFlagName, err)
+	}
+
+	return enabled, nil
 }
 
-func getActivityPubClientParameters(cmd *cobra.Command) (int, time.Duration, error) {
-	cacheSize := defaultActivityPubClientCacheSize
+func getTenants(cmd *cobra.Command) ([]*tenant.Config, error) {
+	tenantsFile := cmdutils.GetUserSetOptionalVarFromString(cmd, tenantsFileFlagName, tenantsFileEnvKey)
+	if tenantsFile == "" {
+		return nil, nil
+	}
 
-	cacheSizeStr, err := cmdutils.GetUserSetVarFromString(cmd, activityPubClientCacheSizeFlagName, activityPubClientCacheSizeEnvKey, true)
+	tenants, err := tenant.LoadConfig(tenantsFile)
 	if err != nil {
-		return 0, 0, err
+		return nil, fmt.Errorf("%s: %w", tenantsFileFlagName, err)
 	}
 
-	if cacheSizeStr != "" {
-		cacheSize, err = strconv.Atoi(cacheSizeStr)
+	return tenants, nil
+}
+
+func getOperationQuotas(cmd *cobra.Command) (*quota.Config, error) {
+	quotasFile := cmdutils.GetUserSetOptionalVarFromString(cmd, operationQuotasFileFlagName, operationQuotasFileEnvKey)
+	if quotasFile == "" {
+		return nil, nil
+	}
+
+	cfg, err := quota.LoadConfig(quotasFile)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", operationQuotasFileFlagName, err)
+	}
+
+	return cfg, nil
+}
+
+func getPersistentMetricsParameters(cmd *cobra.Command) (bool, string, error) {
+	enabled := defaultPersistentMetricsEnabled
+
+	enabledStr := cmdutils.GetUserSetOptionalVarFromString(cmd, persistentMetricsEnabledFlagName,
+		persistentMetricsEnabledEnvKey)
+	if enabledStr != "" {
+		enable, err := strconv.ParseBool(enabledStr)
 		if err != nil {
-			return 0, 0, fmt.Errorf("invalid value [%s] for parameter [%s]: %w",
-				cacheSizeStr, activityPubClientCacheSizeFlagName, err)
+			return false, "", fmt.Errorf("invalid value for %s: %w", persistentMetricsEnabledFlagName, err)
 		}
 
-		if cacheSize <= 0 {
-			return 0, 0, fmt.Errorf("value for parameter [%s] must be grater than 0", activityPubClientCacheSizeFlagName)
+		enabled = enable
+	}
+
+	instance := cmdutils.GetUserSetOptionalVarFromString(cmd, persistentMetricsInstanceFlagName,
+		persistentMetricsInstanceEnvKey)
+	if instance == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return false, "", fmt.Errorf("%s: get host name: %w", persistentMetricsInstanceFlagName, err)
+		}
+
+		instance = hostname
+	}
+
+	return enabled, instance, nil
+}
+
+func getLinksetSigningParameters(cmd *cobra.Command) (enabled, signatureRequired bool, err error) {
+	enabled = defaultLinksetSigningEnabled
+
+	enabledStr := cmdutils.GetUserSetOptionalVarFromString(cmd, linksetSigningEnabledFlagName,
+		linksetSigningEnabledEnvKey)
+	if enabledStr != "" {
+		enabled, err = strconv.ParseBool(enabledStr)
+		if err != nil {
+			return false, false, fmt.Errorf("invalid value for %s: %w", linksetSigningEnabledFlagName, err)
 		}
 	}
 
-	cacheExpiration, err := getDuration(cmd, activityPubClientCacheExpirationFlagName,
-		activityPubClientCacheExpirationEnvKey, defaultActivityPubClientCacheExpiration)
-	if err != nil {
-		return 0, 0, fmt.Errorf("invalid value for parameter [%s]: %w",
-			activityPubClientCacheExpirationFlagName, err)
+	signatureRequired = defaultLinksetSignatureRequired
+
+	requiredStr := cmdutils.GetUserSetOptionalVarFromString(cmd, linksetSignatureRequiredFlagName,
+		linksetSignatureRequiredEnvKey)
+	if requiredStr != "" {
+		signatureRequired, err = strconv.ParseBool(requiredStr)
+		if err != nil {
+			return false, false, fmt.Errorf("invalid value for %s: %w", linksetSignatureRequiredFlagName, err)
+		}
 	}
 
-	return cacheSize, cacheExpiration, nil
+	return enabled, signatureRequired, nil
 }
 
 func getActivityPubIRICacheParameters(cmd *cobra.Command) (int, time.Duration, error) {
@@ -1472,7 +3485,10 @@ func getActivityPubIRICacheParameters(cmd *cobra.Command) (int, time.Duration, e
 }
 
 func createFlags(startCmd *cobra.Command) {
+	startCmd.Flags().String(configFileFlagName, "", configFileFlagUsage)
+	startCmd.Flags().String(validateConfigFlagName, "false", validateConfigFlagUsage)
 	startCmd.Flags().StringP(hostURLFlagName, hostURLFlagShorthand, "", hostURLFlagUsage)
+	startCmd.Flags().String(grpcHostURLFlagName, "", grpcHostURLFlagUsage)
 	startCmd.Flags().StringP(hostMetricsURLFlagName, hostMetricsURLFlagShorthand, "", hostMetricsURLFlagUsage)
 	startCmd.Flags().String(syncTimeoutFlagName, "1", syncTimeoutFlagUsage)
 	startCmd.Flags().String(vctURLFlagName, "", vctURLFlagUsage)
@@ -1494,6 +3510,7 @@ func createFlags(startCmd *cobra.Command) {
 	startCmd.Flags().String(enableDidDiscoveryFlagName, "", enableDidDiscoveryUsage)
 	startCmd.Flags().String(enableCreateDocumentStoreFlagName, "", enableCreateDocumentStoreUsage)
 	startCmd.Flags().String(enableUpdateDocumentStoreFlagName, "", enableUpdateDocumentStoreUsage)
+	startCmd.Flags().StringArray(updateDocumentStoreTypesFlagName, []string{}, updateDocumentStoreTypesUsage)
 	startCmd.Flags().String(includeUnpublishedOperationsFlagName, "", includeUnpublishedOperationsUsage)
 	startCmd.Flags().String(includePublishedOperationsFlagName, "", includePublishedOperationsUsage)
 	startCmd.Flags().String(resolveFromAnchorOriginFlagName, "", resolveFromAnchorOriginUsage)
@@ -1505,9 +3522,13 @@ func createFlags(startCmd *cobra.Command) {
 	startCmd.Flags().StringP(mqOpPoolFlagName, mqOpPoolFlagShorthand, "", mqOpPoolFlagUsage)
 	startCmd.Flags().StringP(mqObserverPoolFlagName, mqObserverPoolFlagShorthand, "", mqObserverPoolFlagUsage)
 	startCmd.Flags().StringP(mqMaxConnectionSubscriptionsFlagName, mqMaxConnectionSubscriptionsFlagShorthand, "", mqMaxConnectionSubscriptionsFlagUsage)
+	startCmd.Flags().StringArray(mqFailoverURLsFlagName, []string{}, mqFailoverURLsFlagUsage)
+	startCmd.Flags().String(mqPublisherConfirmsEnabledFlagName, "", mqPublisherConfirmsEnabledFlagUsage)
+	startCmd.Flags().String(mqPublishConfirmTimeoutFlagName, "", mqPublishConfirmTimeoutFlagUsage)
 	startCmd.Flags().String(cidVersionFlagName, "1", cidVersionFlagUsage)
 	startCmd.Flags().StringP(didNamespaceFlagName, didNamespaceFlagShorthand, "", didNamespaceFlagUsage)
 	startCmd.Flags().StringArrayP(didAliasesFlagName, didAliasesFlagShorthand, []string{}, didAliasesFlagUsage)
+	startCmd.Flags().String(didNetworkFlagName, "", didNetworkFlagUsage)
 	startCmd.Flags().StringArrayP(allowedOriginsFlagName, allowedOriginsFlagShorthand, []string{}, allowedOriginsFlagUsage)
 	startCmd.Flags().StringP(anchorCredentialDomainFlagName, anchorCredentialDomainFlagShorthand, "", anchorCredentialDomainFlagUsage)
 	startCmd.Flags().StringP(anchorCredentialIssuerFlagName, anchorCredentialIssuerFlagShorthand, "", anchorCredentialIssuerFlagUsage)
@@ -1516,6 +3537,12 @@ func createFlags(startCmd *cobra.Command) {
 	startCmd.Flags().StringP(databaseTypeFlagName, databaseTypeFlagShorthand, "", databaseTypeFlagUsage)
 	startCmd.Flags().StringP(databaseURLFlagName, databaseURLFlagShorthand, "", databaseURLFlagUsage)
 	startCmd.Flags().StringP(databasePrefixFlagName, "", "", databasePrefixFlagUsage)
+	startCmd.Flags().String(legacyDatabaseTypeFlagName, "", legacyDatabaseTypeFlagUsage)
+	startCmd.Flags().String(legacyDatabaseURLFlagName, "", legacyDatabaseURLFlagUsage)
+	startCmd.Flags().String(legacyDatabasePrefixFlagName, "", legacyDatabasePrefixFlagUsage)
+	startCmd.Flags().String(legacyDatabaseVerifyIntervalFlagName, "", legacyDatabaseVerifyIntervalFlagUsage)
+	startCmd.Flags().String(readDatabaseURLFlagName, "", readDatabaseURLFlagUsage)
+	startCmd.Flags().String(readDatabaseMaxStalenessFlagName, "", readDatabaseMaxStalenessFlagUsage)
 	startCmd.Flags().StringP(kmsSecretsDatabaseTypeFlagName, kmsSecretsDatabaseTypeFlagShorthand, "",
 		kmsSecretsDatabaseTypeFlagUsage)
 	startCmd.Flags().StringP(kmsSecretsDatabaseURLFlagName, kmsSecretsDatabaseURLFlagShorthand, "",
@@ -1530,22 +3557,118 @@ func createFlags(startCmd *cobra.Command) {
 	startCmd.Flags().StringArrayP(clientAuthTokensDefFlagName, "", nil, clientAuthTokensDefFlagUsage)
 	startCmd.Flags().StringArrayP(clientAuthTokensFlagName, "", nil, clientAuthTokensFlagUsage)
 	startCmd.Flags().StringP(activityPubPageSizeFlagName, activityPubPageSizeFlagShorthand, "", activityPubPageSizeFlagUsage)
+	startCmd.Flags().StringArray(activityPubCollectionVisibilityFlagName, []string{},
+		activityPubCollectionVisibilityFlagUsage)
 	startCmd.Flags().String(devModeEnabledFlagName, "false", devModeEnabledUsage)
+	startCmd.Flags().String(profilingEnabledFlagName, "false", profilingEnabledUsage)
 	startCmd.Flags().StringP(nodeInfoRefreshIntervalFlagName, nodeInfoRefreshIntervalFlagShorthand, "", nodeInfoRefreshIntervalFlagUsage)
 	startCmd.Flags().StringP(ipfsTimeoutFlagName, ipfsTimeoutFlagShorthand, "", ipfsTimeoutFlagUsage)
 	startCmd.Flags().StringArrayP(contextProviderFlagName, "", []string{}, contextProviderFlagUsage)
 	startCmd.Flags().StringP(databaseTimeoutFlagName, "", "", databaseTimeoutFlagUsage)
 	startCmd.Flags().StringP(unpublishedOperationLifespanFlagName, "", "", unpublishedOperationLifespanFlagUsage)
+	startCmd.Flags().StringP(opQueueBloomFilterSizeFlagName, "", "",
+		opQueueBloomFilterSizeFlagUsage)
+	startCmd.Flags().StringP(didSnapshotIntervalFlagName, "", "", didSnapshotIntervalFlagUsage)
+	startCmd.Flags().StringP(didSuffixIndexSizeFlagName, "", "", didSuffixIndexSizeFlagUsage)
+	startCmd.Flags().StringP(didSuffixIndexSyncIntervalFlagName, "", "", didSuffixIndexSyncIntervalFlagUsage)
+	startCmd.Flags().StringP(operationIdempotencyWindowFlagName, "", "", operationIdempotencyWindowFlagUsage)
 	startCmd.Flags().StringP(taskMgrCheckIntervalFlagName, "", "", taskMgrCheckIntervalFlagUsage)
+	startCmd.Flags().StringArrayP(taskScheduleFlagName, "", []string{}, taskScheduleFlagUsage)
 	startCmd.Flags().StringP(dataExpiryCheckIntervalFlagName, "", "", dataExpiryCheckIntervalFlagUsage)
 	startCmd.Flags().StringP(followAuthPolicyFlagName, followAuthPolicyFlagShorthand, "", followAuthPolicyFlagUsage)
 	startCmd.Flags().StringP(inviteWitnessAuthPolicyFlagName, inviteWitnessAuthPolicyFlagShorthand, "", inviteWitnessAuthPolicyFlagUsage)
+	startCmd.Flags().StringP(inviteWitnessReciprocationFlagName, "", "", inviteWitnessReciprocationFlagUsage)
+	startCmd.Flags().StringP(actorKeyPinningFlagName, "", "", actorKeyPinningFlagUsage)
+	startCmd.Flags().StringP(deliveryReceiptRetentionFlagName, "", "", deliveryReceiptRetentionFlagUsage)
+	startCmd.Flags().StringP(pendingDeliveryRetentionFlagName, "", "", pendingDeliveryRetentionFlagUsage)
+	startCmd.Flags().StringP(witnessExpirationFlagName, "", "", witnessExpirationFlagUsage)
+	startCmd.Flags().StringP(witnessRenewalWindowFlagName, "", "", witnessRenewalWindowFlagUsage)
+	startCmd.Flags().StringP(witnessProofBatchWindowFlagName, "", "", witnessProofBatchWindowFlagUsage)
+	startCmd.Flags().StringP(witnessProofBatchSizeFlagName, "", "", witnessProofBatchSizeFlagUsage)
+	startCmd.Flags().StringP(inboxWorkersFlagName, "", "", inboxWorkersFlagUsage)
+	startCmd.Flags().StringP(inboxQueueSizeFlagName, "", "", inboxQueueSizeFlagUsage)
+	startCmd.Flags().StringP(inboxSenderMaxInFlightFlagName, "", "", inboxSenderMaxInFlightFlagUsage)
+	startCmd.Flags().StringArray(inboxSenderMaxInFlightOverridesFlagName, []string{},
+		inboxSenderMaxInFlightOverridesFlagUsage)
+	startCmd.Flags().StringP(witnessProofCacheSizeFlagName, "", "", witnessProofCacheSizeFlagUsage)
 	startCmd.Flags().StringP(httpTimeoutFlagName, "", "", httpTimeoutFlagUsage)
 	startCmd.Flags().StringP(httpDialTimeoutFlagName, "", "", httpDialTimeoutFlagUsage)
 	startCmd.Flags().StringP(anchorSyncIntervalFlagName, anchorSyncIntervalFlagShorthand, "", anchorSyncIntervalFlagUsage)
+	startCmd.Flags().String(anchorReconcileIntervalFlagName, "", anchorReconcileIntervalFlagUsage)
+	startCmd.Flags().String(anchorReconcileDaysFlagName, "", anchorReconcileDaysFlagUsage)
+	startCmd.Flags().String(maintenanceRetryAfterFlagName, "", maintenanceRetryAfterFlagUsage)
+	startCmd.Flags().String(leaderElectionEnabledFlagName, "", leaderElectionEnabledFlagUsage)
+	startCmd.Flags().String(leaderLeaseDurationFlagName, "", leaderLeaseDurationFlagUsage)
+	startCmd.Flags().String(observerShardingEnabledFlagName, "", observerShardingEnabledFlagUsage)
+	startCmd.Flags().String(observerShardIDFlagName, "", observerShardIDFlagUsage)
+	startCmd.Flags().String(observerShardHeartbeatIntervalFlagName, "", observerShardHeartbeatIntervalFlagUsage)
+	startCmd.Flags().String(activitySinkWebhookURLFlagName, "", activitySinkWebhookURLFlagUsage)
+	startCmd.Flags().String(activitySinkIntervalFlagName, "", activitySinkIntervalFlagUsage)
+	startCmd.Flags().String(activitySinkBatchSizeFlagName, "", activitySinkBatchSizeFlagUsage)
+	startCmd.Flags().String(graphQLEnabledFlagName, "", graphQLEnabledFlagUsage)
+	startCmd.Flags().String(universalResolverDriverEnabledFlagName, "", universalResolverDriverEnabledFlagUsage)
+	startCmd.Flags().String(webhooksEnabledFlagName, "", webhooksEnabledFlagUsage)
+	startCmd.Flags().String(didWebhooksEnabledFlagName, "", didWebhooksEnabledFlagUsage)
+	startCmd.Flags().String(operationStatusEnabledFlagName, "", operationStatusEnabledFlagUsage)
+	startCmd.Flags().String(anchorExplorerEnabledFlagName, "", anchorExplorerEnabledFlagUsage)
+	startCmd.Flags().String(migrationEnabledFlagName, "", migrationEnabledFlagUsage)
+	startCmd.Flags().String(storeMigrationsEnabledFlagName, "", storeMigrationsEnabledFlagUsage)
+	startCmd.Flags().String(deliveryAnalyticsEnabledFlagName, "", deliveryAnalyticsEnabledFlagUsage)
+	startCmd.Flags().String(inboxQuarantineEnabledFlagName, "", inboxQuarantineEnabledFlagUsage)
+	startCmd.Flags().String(accessTokensEnabledFlagName, "", accessTokensEnabledFlagUsage)
+	startCmd.Flags().StringP(inboxEvidenceRetentionFlagName, "", "", inboxEvidenceRetentionFlagUsage)
+	startCmd.Flags().String(inboxRejectionHistorySizeFlagName, "", inboxRejectionHistorySizeFlagUsage)
+	startCmd.Flags().String(ipfsPubSubAnchorTopicFlagName, "", ipfsPubSubAnchorTopicFlagUsage)
+	startCmd.Flags().String(acceptListRegistryURLFlagName, "", acceptListRegistryURLFlagUsage)
+	startCmd.Flags().String(acceptListRegistryPublicKeyFlagName, "", acceptListRegistryPublicKeyFlagUsage)
+	startCmd.Flags().StringP(acceptListRegistryIntervalFlagName, "", "", acceptListRegistryIntervalFlagUsage)
+	startCmd.Flags().String(activitySearchEnabledFlagName, "", activitySearchEnabledFlagUsage)
+	startCmd.Flags().String(jsonldRemoteContextFetchEnabledFlagName, "", jsonldRemoteContextFetchEnabledFlagUsage)
+	startCmd.Flags().String(vctLogAllowListEnabledFlagName, "", vctLogAllowListEnabledFlagUsage)
+	startCmd.Flags().String(faultInjectionFlagName, "", faultInjectionFlagUsage)
+	startCmd.Flags().String(tenantsFileFlagName, "", tenantsFileFlagUsage)
+	startCmd.Flags().String(operationQuotasFileFlagName, "", operationQuotasFileFlagUsage)
+	startCmd.Flags().String(persistentMetricsEnabledFlagName, "", persistentMetricsEnabledFlagUsage)
+	startCmd.Flags().String(persistentMetricsInstanceFlagName, "", persistentMetricsInstanceFlagUsage)
+	startCmd.Flags().String(linksetSigningEnabledFlagName, "", linksetSigningEnabledFlagUsage)
+	startCmd.Flags().String(linksetSignatureRequiredFlagName, "", linksetSignatureRequiredFlagUsage)
 	startCmd.Flags().StringP(vctMonitoringIntervalFlagName, "", "", vctMonitoringIntervalFlagUsage)
 	startCmd.Flags().StringP(anchorStatusMonitoringIntervalFlagName, "", "", anchorStatusMonitoringIntervalFlagUsage)
 	startCmd.Flags().StringP(anchorStatusInProcessGracePeriodFlagName, "", "", anchorStatusInProcessGracePeriodFlagUsage)
 	startCmd.Flags().StringP(activityPubClientCacheSizeFlagName, "", "", activityPubClientCacheSizeFlagUsage)
 	startCmd.Flags().StringP(activityPubIRICacheSizeFlagName, "", "", activityPubIRICacheSizeFlagUsage)
+	startCmd.Flags().String(shutdownTimeoutFlagName, "", shutdownTimeoutFlagUsage)
+	startCmd.Flags().StringArray(sidetreeProtocolVersionsFlagName, []string{}, sidetreeProtocolVersionsFlagUsage)
+	startCmd.Flags().String(batchCompressionAlgorithmFlagName, "", batchCompressionAlgorithmFlagUsage)
+	startCmd.Flags().String(batchMaxOperationsFlagName, "", batchMaxOperationsFlagUsage)
+	startCmd.Flags().String(batchMaxSizeFlagName, "", batchMaxSizeFlagUsage)
+	startCmd.Flags().String(batchMaxLatencyFlagName, "", batchMaxLatencyFlagUsage)
+	startCmd.Flags().String(httpMaxIdleConnsFlagName, "", httpMaxIdleConnsFlagUsage)
+	startCmd.Flags().String(httpMaxIdleConnsPerHostFlagName, "", httpMaxIdleConnsPerHostFlagUsage)
+	startCmd.Flags().String(httpMaxConnsPerHostFlagName, "", httpMaxConnsPerHostFlagUsage)
+	startCmd.Flags().String(httpIdleConnTimeoutFlagName, "", httpIdleConnTimeoutFlagUsage)
+	startCmd.Flags().StringArray(httpHostTimeoutsFlagName, []string{}, httpHostTimeoutsFlagUsage)
+	startCmd.Flags().String(circuitBreakerFailureThresholdFlagName, "", circuitBreakerFailureThresholdFlagUsage)
+	startCmd.Flags().String(circuitBreakerOpenTimeoutFlagName, "", circuitBreakerOpenTimeoutFlagUsage)
+	startCmd.Flags().String(httpProxyURLFlagName, "", httpProxyURLFlagUsage)
+	startCmd.Flags().StringArray(httpProxyOverridesFlagName, []string{}, httpProxyOverridesFlagUsage)
+	startCmd.Flags().StringArray(httpNoProxyFlagName, []string{}, httpNoProxyFlagUsage)
+	createHTTPDestinationFlags(startCmd)
+	createResponseCompressionFlags(startCmd)
+	createCORSFlags(startCmd)
+	startCmd.Flags().String(httpMaxBodySizeFlagName, "", httpMaxBodySizeFlagUsage)
+	startCmd.Flags().String(httpMaxJSONDepthFlagName, "", httpMaxJSONDepthFlagUsage)
+	startCmd.Flags().String(httpMaxJSONElementsFlagName, "", httpMaxJSONElementsFlagUsage)
+	startCmd.Flags().StringArray(adminAllowedCIDRsFlagName, []string{}, adminAllowedCIDRsFlagUsage)
+	startCmd.Flags().StringArray(adminDeniedCIDRsFlagName, []string{}, adminDeniedCIDRsFlagUsage)
+	startCmd.Flags().StringArray(trustedProxyCIDRsFlagName, []string{}, trustedProxyCIDRsFlagUsage)
+	startCmd.Flags().String(httpSignatureKMSTypeFlagName, "", httpSignatureKMSTypeFlagUsage)
+	startCmd.Flags().String(httpSignatureKMSKeyIDFlagName, "", httpSignatureKMSKeyIDFlagUsage)
+	startCmd.Flags().String(anchorCredentialKMSTypeFlagName, "", anchorCredentialKMSTypeFlagUsage)
+	startCmd.Flags().String(anchorCredentialKMSKeyIDFlagName, "", anchorCredentialKMSKeyIDFlagUsage)
+	startCmd.Flags().String(awsKMSRegionFlagName, "", awsKMSRegionFlagUsage)
+	startCmd.Flags().String(awsKMSEndpointFlagName, "", awsKMSEndpointFlagUsage)
+	startCmd.Flags().String(vaultURLFlagName, "", vaultURLFlagUsage)
+	startCmd.Flags().String(vaultTokenFlagName, "", vaultTokenFlagUsage)
+	startCmd.Flags().String(vaultTransitMountPathFlagName, "", vaultTransitMountPathFlagUsage)
 }
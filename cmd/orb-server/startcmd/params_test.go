@@ -19,6 +19,11 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/require"
 	"github.com/trustbloc/edge-core/pkg/log"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+
+	aphandler "github.com/trustbloc/orb/pkg/activitypub/resthandler"
+	"github.com/trustbloc/orb/pkg/leaderelection"
+	"github.com/trustbloc/orb/pkg/observer/shard"
 )
 
 func TestStartCmdContents(t *testing.T) {
@@ -554,6 +559,30 @@ func TestStartCmdWithMissingArg(t *testing.T) {
 		require.EqualError(t, err, "activitypub-page-size: value must be greater than 0")
 	})
 
+	t.Run("gRPC API in multi-tenant mode", func(t *testing.T) {
+		tenantsFile := writeConfigFile(t, `
+tenants:
+  - id: tenant1
+    host: tenant1.example.com
+    externalEndpoint: https://tenant1.example.com
+`)
+
+		restoreTenantsEnv := setEnv(t, tenantsFileEnvKey, tenantsFile)
+		restoreGRPCEnv := setEnv(t, grpcHostURLEnvKey, "localhost:8249")
+
+		defer func() {
+			restoreTenantsEnv()
+			restoreGRPCEnv()
+		}()
+
+		startCmd := GetStartCmd()
+
+		startCmd.SetArgs(getTestArgs("localhost:8081", "local", "false", databaseTypeMemOption, ""))
+
+		err := startCmd.Execute()
+		require.EqualError(t, err, "grpc-host-url is not supported in multi-tenant mode")
+	})
+
 	t.Run("Invalid NodeInfo refresh interval", func(t *testing.T) {
 		restoreEnv := setEnv(t, nodeInfoRefreshIntervalEnvKey, "5")
 		defer restoreEnv()
@@ -606,6 +635,162 @@ func TestStartCmdWithMissingArg(t *testing.T) {
 		require.Contains(t, err.Error(), "missing unit in duration")
 	})
 
+	t.Run("Invalid operation queue bloom filter size", func(t *testing.T) {
+		restoreEnv := setEnv(t, opQueueBloomFilterSizeEnvKey, "-1")
+		defer restoreEnv()
+
+		startCmd := GetStartCmd()
+
+		startCmd.SetArgs(getTestArgs("localhost:8081", "local", "false", databaseTypeMemOption, ""))
+
+		err := startCmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value [-1] for parameter [op-queue-bloom-filter-size]")
+	})
+
+	t.Run("Invalid DID snapshot interval", func(t *testing.T) {
+		restoreEnv := setEnv(t, didSnapshotIntervalEnvKey, "-1")
+		defer restoreEnv()
+
+		startCmd := GetStartCmd()
+
+		startCmd.SetArgs(getTestArgs("localhost:8081", "local", "false", databaseTypeMemOption, ""))
+
+		err := startCmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value [-1] for parameter [did-snapshot-interval]")
+	})
+
+	t.Run("Invalid DID suffix index size", func(t *testing.T) {
+		restoreEnv := setEnv(t, didSuffixIndexSizeEnvKey, "-1")
+		defer restoreEnv()
+
+		startCmd := GetStartCmd()
+
+		startCmd.SetArgs(getTestArgs("localhost:8081", "local", "false", databaseTypeMemOption, ""))
+
+		err := startCmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value [-1] for parameter [did-suffix-index-size]")
+	})
+
+	t.Run("Invalid DID suffix index sync interval", func(t *testing.T) {
+		restoreEnv := setEnv(t, didSuffixIndexSyncIntervalEnvKey, "5")
+		defer restoreEnv()
+
+		startCmd := GetStartCmd()
+
+		startCmd.SetArgs(getTestArgs("localhost:8081", "local", "false", databaseTypeMemOption, ""))
+
+		err := startCmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "missing unit in duration")
+	})
+
+	t.Run("Invalid operation idempotency window", func(t *testing.T) {
+		restoreEnv := setEnv(t, operationIdempotencyWindowEnvKey, "5")
+		defer restoreEnv()
+
+		startCmd := GetStartCmd()
+
+		startCmd.SetArgs(getTestArgs("localhost:8081", "local", "false", databaseTypeMemOption, ""))
+
+		err := startCmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "missing unit in duration")
+	})
+
+	t.Run("Invalid outbox delivery receipt retention", func(t *testing.T) {
+		restoreEnv := setEnv(t, deliveryReceiptRetentionEnvKey, "5")
+		defer restoreEnv()
+
+		startCmd := GetStartCmd()
+
+		startCmd.SetArgs(getTestArgs("localhost:8081", "local", "false", databaseTypeMemOption, ""))
+
+		err := startCmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "missing unit in duration")
+	})
+
+	t.Run("Negative outbox delivery receipt retention", func(t *testing.T) {
+		restoreEnv := setEnv(t, deliveryReceiptRetentionEnvKey, "-1h")
+		defer restoreEnv()
+
+		startCmd := GetStartCmd()
+
+		startCmd.SetArgs(getTestArgs("localhost:8081", "local", "false", databaseTypeMemOption, ""))
+
+		err := startCmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "value must not be negative")
+	})
+
+	t.Run("Invalid outbox pending delivery retention", func(t *testing.T) {
+		restoreEnv := setEnv(t, pendingDeliveryRetentionEnvKey, "5")
+		defer restoreEnv()
+
+		startCmd := GetStartCmd()
+
+		startCmd.SetArgs(getTestArgs("localhost:8081", "local", "false", databaseTypeMemOption, ""))
+
+		err := startCmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "missing unit in duration")
+	})
+
+	t.Run("Negative outbox pending delivery retention", func(t *testing.T) {
+		restoreEnv := setEnv(t, pendingDeliveryRetentionEnvKey, "-1h")
+		defer restoreEnv()
+
+		startCmd := GetStartCmd()
+
+		startCmd.SetArgs(getTestArgs("localhost:8081", "local", "false", databaseTypeMemOption, ""))
+
+		err := startCmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "value must not be negative")
+	})
+
+	t.Run("Invalid inbox evidence retention", func(t *testing.T) {
+		restoreEnv := setEnv(t, inboxEvidenceRetentionEnvKey, "5")
+		defer restoreEnv()
+
+		startCmd := GetStartCmd()
+
+		startCmd.SetArgs(getTestArgs("localhost:8081", "local", "false", databaseTypeMemOption, ""))
+
+		err := startCmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "missing unit in duration")
+	})
+
+	t.Run("Negative inbox evidence retention", func(t *testing.T) {
+		restoreEnv := setEnv(t, inboxEvidenceRetentionEnvKey, "-1h")
+		defer restoreEnv()
+
+		startCmd := GetStartCmd()
+
+		startCmd.SetArgs(getTestArgs("localhost:8081", "local", "false", databaseTypeMemOption, ""))
+
+		err := startCmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "value must not be negative")
+	})
+
+	t.Run("Invalid inbox rejection history size", func(t *testing.T) {
+		restoreEnv := setEnv(t, inboxRejectionHistorySizeEnvKey, "0")
+		defer restoreEnv()
+
+		startCmd := GetStartCmd()
+
+		startCmd.SetArgs(getTestArgs("localhost:8081", "local", "false", databaseTypeMemOption, ""))
+
+		err := startCmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), inboxRejectionHistorySizeFlagName+": value must be greater than 0")
+	})
+
 	t.Run("Invalid expiry check interval", func(t *testing.T) {
 		restoreEnv := setEnv(t, dataExpiryCheckIntervalEnvKey, "5")
 		defer restoreEnv()
@@ -671,6 +856,19 @@ func TestStartCmdWithMissingArg(t *testing.T) {
 		require.Contains(t, err.Error(), "sync-interval: invalid value [xxx]")
 	})
 
+	t.Run("Invalid maintenance retry-after", func(t *testing.T) {
+		restoreEnv := setEnv(t, maintenanceRetryAfterEnvKey, "xxx")
+		defer restoreEnv()
+
+		startCmd := GetStartCmd()
+
+		startCmd.SetArgs(getTestArgs("localhost:8081", "local", "false", databaseTypeMemOption, ""))
+
+		err := startCmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "maintenance-retry-after: invalid value [xxx]")
+	})
+
 	t.Run("VCT monitoring interval", func(t *testing.T) {
 		restoreEnv := setEnv(t, vctMonitoringIntervalEnvKey, "xxx")
 		defer restoreEnv()
@@ -735,6 +933,64 @@ func TestStartCmdWithMissingArg(t *testing.T) {
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "invalid value [xxx] for parameter [apiri-cache-size]")
 	})
+
+	t.Run("unsupported Sidetree protocol version", func(t *testing.T) {
+		startCmd := GetStartCmd()
+
+		startCmd.SetArgs(append(getTestArgs("localhost:8081", "local", "false", databaseTypeMemOption, ""),
+			"--"+sidetreeProtocolVersionsFlagName, "1.0",
+			"--"+sidetreeProtocolVersionsFlagName, "9.9",
+		))
+
+		err := startCmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "error creating protocol version [9.9]")
+	})
+
+	t.Run("unsupported batch compression algorithm", func(t *testing.T) {
+		startCmd := GetStartCmd()
+
+		startCmd.SetArgs(append(getTestArgs("localhost:8081", "local", "false", databaseTypeMemOption, ""),
+			"--"+batchCompressionAlgorithmFlagName, "xxx",
+		))
+
+		err := startCmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unsupported value [xxx] for parameter [batch-compression-algorithm]")
+	})
+}
+
+func TestGetUpdateDocumentStoreTypes(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		startCmd := GetStartCmd()
+		require.NoError(t, startCmd.ParseFlags(nil))
+
+		types, err := getUpdateDocumentStoreTypes(startCmd)
+		require.NoError(t, err)
+		require.Equal(t, []operation.Type{operation.TypeUpdate}, types)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		startCmd := GetStartCmd()
+		require.NoError(t, startCmd.ParseFlags([]string{
+			"--" + updateDocumentStoreTypesFlagName, "update",
+			"--" + updateDocumentStoreTypesFlagName, "Recover",
+			"--" + updateDocumentStoreTypesFlagName, "deactivate",
+		}))
+
+		types, err := getUpdateDocumentStoreTypes(startCmd)
+		require.NoError(t, err)
+		require.Equal(t, []operation.Type{operation.TypeUpdate, operation.TypeRecover, operation.TypeDeactivate}, types)
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		startCmd := GetStartCmd()
+		require.NoError(t, startCmd.ParseFlags([]string{"--" + updateDocumentStoreTypesFlagName, "create"}))
+
+		_, err := getUpdateDocumentStoreTypes(startCmd)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value [create] for parameter [update-document-store-types]")
+	})
 }
 
 func TestStartCmdWithBlankEnvVar(t *testing.T) {
@@ -885,7 +1141,11 @@ func TestStartCmdValidArgsEnvVar(t *testing.T) {
 
 		defer unsetEnvVars(t)
 
+		done := make(chan struct{})
+
 		go func() {
+			defer close(done)
+
 			err := startCmd.Execute()
 			require.Nil(t, err)
 			require.Equal(t, log.ERROR, log.GetLevel(""))
@@ -897,6 +1157,9 @@ func TestStartCmdValidArgsEnvVar(t *testing.T) {
 			return err
 		}, backoff.WithMaxRetries(backoff.NewConstantBackOff(time.Second), 5)))
 		require.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGINT))
+
+		// Wait for the servers to stop so that the next test can listen on the same ports.
+		<-done
 	})
 	t.Run("CAS Type: Local (without IPFS replication)", func(t *testing.T) {
 		startCmd := GetStartCmd()
@@ -905,7 +1168,11 @@ func TestStartCmdValidArgsEnvVar(t *testing.T) {
 
 		defer unsetEnvVars(t)
 
+		done := make(chan struct{})
+
 		go func() {
+			defer close(done)
+
 			err := startCmd.Execute()
 			require.Nil(t, err)
 			require.Equal(t, log.ERROR, log.GetLevel(""))
@@ -917,6 +1184,9 @@ func TestStartCmdValidArgsEnvVar(t *testing.T) {
 			return err
 		}, backoff.WithMaxRetries(backoff.NewConstantBackOff(time.Second), 5)))
 		require.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGINT))
+
+		// Wait for the servers to stop so that the next test can listen on the same ports.
+		<-done
 	})
 	t.Run("CAS Type: Local (with IPFS replication)", func(t *testing.T) {
 		startCmd := GetStartCmd()
@@ -925,7 +1195,11 @@ func TestStartCmdValidArgsEnvVar(t *testing.T) {
 
 		defer unsetEnvVars(t)
 
+		done := make(chan struct{})
+
 		go func() {
+			defer close(done)
+
 			err := startCmd.Execute()
 			require.Nil(t, err)
 			require.Equal(t, log.ERROR, log.GetLevel(""))
@@ -937,6 +1211,9 @@ func TestStartCmdValidArgsEnvVar(t *testing.T) {
 			return err
 		}, backoff.WithMaxRetries(backoff.NewConstantBackOff(time.Second), 5)))
 		require.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGINT))
+
+		// Wait for the servers to stop so that the next test can listen on the same ports.
+		<-done
 	})
 }
 
@@ -1063,6 +1340,147 @@ func TestGetActivityPubPageSize(t *testing.T) {
 	})
 }
 
+func TestGetActivityPubCollectionVisibility(t *testing.T) {
+	t.Run("Not specified -> empty", func(t *testing.T) {
+		cmd := getTestCmd(t)
+
+		visibility, err := getActivityPubCollectionVisibility(cmd)
+		require.NoError(t, err)
+		require.Empty(t, visibility)
+	})
+
+	t.Run("Valid values -> success", func(t *testing.T) {
+		cmd := getTestCmd(t,
+			"--"+activityPubCollectionVisibilityFlagName, "followers=authenticated",
+			"--"+activityPubCollectionVisibilityFlagName, "Witnesses=Private",
+			"--"+activityPubCollectionVisibilityFlagName, "outbox=public",
+		)
+
+		visibility, err := getActivityPubCollectionVisibility(cmd)
+		require.NoError(t, err)
+		require.Equal(t, map[string]aphandler.Visibility{
+			aphandler.FollowersPath: aphandler.VisibilityAuthenticated,
+			aphandler.WitnessesPath: aphandler.VisibilityPrivate,
+			aphandler.OutboxPath:    aphandler.VisibilityPublic,
+		}, visibility)
+	})
+
+	t.Run("Invalid format -> error", func(t *testing.T) {
+		cmd := getTestCmd(t, "--"+activityPubCollectionVisibilityFlagName, "followers")
+
+		_, err := getActivityPubCollectionVisibility(cmd)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "expecting collection=visibility")
+	})
+
+	t.Run("Unsupported collection -> error", func(t *testing.T) {
+		cmd := getTestCmd(t, "--"+activityPubCollectionVisibilityFlagName, "keys=private")
+
+		_, err := getActivityPubCollectionVisibility(cmd)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unsupported collection [keys]")
+	})
+
+	t.Run("Unsupported visibility -> error", func(t *testing.T) {
+		cmd := getTestCmd(t, "--"+activityPubCollectionVisibilityFlagName, "followers=secret")
+
+		_, err := getActivityPubCollectionVisibility(cmd)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unsupported visibility [secret]")
+	})
+}
+
+func TestGetTaskSchedules(t *testing.T) {
+	t.Run("Not specified -> empty", func(t *testing.T) {
+		schedules, err := getTaskSchedules(getTestCmd(t))
+		require.NoError(t, err)
+		require.Empty(t, schedules)
+	})
+
+	t.Run("Valid values -> success", func(t *testing.T) {
+		cmd := getTestCmd(t,
+			"--"+taskScheduleFlagName, "data-expiry=0,30 2 * * *",
+			"--"+taskScheduleFlagName, "vct-monitor=@every 5m",
+		)
+
+		schedules, err := getTaskSchedules(cmd)
+		require.NoError(t, err)
+		require.Len(t, schedules, 2)
+		require.Equal(t, "0,30 2 * * *", schedules["data-expiry"].String())
+		require.Equal(t, "@every 5m0s", schedules["vct-monitor"].String())
+	})
+
+	t.Run("Environment variable -> success", func(t *testing.T) {
+		restore := setEnv(t, taskScheduleEnvKey, "data-expiry=0,30 2 * * *,vct-monitor=@hourly")
+		defer restore()
+
+		schedules, err := getTaskSchedules(getTestCmd(t))
+		require.NoError(t, err)
+		require.Len(t, schedules, 2)
+		require.Equal(t, "0,30 2 * * *", schedules["data-expiry"].String())
+		require.Equal(t, "@hourly", schedules["vct-monitor"].String())
+	})
+
+	t.Run("Invalid format -> error", func(t *testing.T) {
+		_, err := getTaskSchedules(getTestCmd(t, "--"+taskScheduleFlagName, "@daily"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "expecting taskID=schedule")
+	})
+
+	t.Run("Invalid schedule -> error", func(t *testing.T) {
+		_, err := getTaskSchedules(getTestCmd(t, "--"+taskScheduleFlagName, "data-expiry=* * *"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid schedule")
+	})
+}
+
+func TestGetProfilingEnabled(t *testing.T) {
+	adminToken := map[string]string{adminTokenID: "ADMIN_TOKEN"}
+
+	t.Run("Not specified -> default value", func(t *testing.T) {
+		cmd := getTestCmd(t)
+
+		enabled, err := getProfilingEnabled(cmd, adminToken)
+		require.NoError(t, err)
+		require.False(t, enabled)
+	})
+
+	t.Run("Invalid value -> error", func(t *testing.T) {
+		cmd := getTestCmd(t, "--"+profilingEnabledFlagName, "xxx")
+
+		_, err := getProfilingEnabled(cmd, adminToken)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for enable-profiling")
+	})
+
+	t.Run("No admin token -> error", func(t *testing.T) {
+		cmd := getTestCmd(t, "--"+profilingEnabledFlagName, "true")
+
+		_, err := getProfilingEnabled(cmd, map[string]string{"read": "READ_TOKEN"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "an 'admin' token must be configured")
+	})
+
+	t.Run("Valid value -> success", func(t *testing.T) {
+		cmd := getTestCmd(t, "--"+profilingEnabledFlagName, "true")
+
+		enabled, err := getProfilingEnabled(cmd, adminToken)
+		require.NoError(t, err)
+		require.True(t, enabled)
+	})
+
+	t.Run("Valid env value -> success", func(t *testing.T) {
+		restoreEnv := setEnv(t, profilingEnabledEnvKey, "true")
+		defer restoreEnv()
+
+		cmd := getTestCmd(t)
+
+		enabled, err := getProfilingEnabled(cmd, adminToken)
+		require.NoError(t, err)
+		require.True(t, enabled)
+	})
+}
+
 func TestGetIPFSTimeout(t *testing.T) {
 	t.Run("Not specified -> default value", func(t *testing.T) {
 		cmd := getTestCmd(t)
@@ -1172,6 +1590,63 @@ func TestGetMQParameters(t *testing.T) {
 	})
 }
 
+func TestGetMQFailoverParameters(t *testing.T) {
+	t.Run("Not specified -> default value", func(t *testing.T) {
+		failoverURLs, confirmsEnabled, confirmTimeout, err := getMQFailoverParameters(getTestCmd(t))
+		require.NoError(t, err)
+		require.Empty(t, failoverURLs)
+		require.False(t, confirmsEnabled)
+		require.Zero(t, confirmTimeout)
+	})
+
+	t.Run("Valid env values", func(t *testing.T) {
+		restoreURLsEnv := setEnv(t, mqFailoverURLsEnvKey,
+			"amqp://orb.mq2.domain1.com:5672,amqps://orb.mq3.domain1.com:5671")
+		restoreConfirmsEnv := setEnv(t, mqPublisherConfirmsEnabledEnvKey, "true")
+		restoreTimeoutEnv := setEnv(t, mqPublishConfirmTimeoutEnvKey, "5s")
+
+		defer func() {
+			restoreURLsEnv()
+			restoreConfirmsEnv()
+			restoreTimeoutEnv()
+		}()
+
+		failoverURLs, confirmsEnabled, confirmTimeout, err := getMQFailoverParameters(getTestCmd(t))
+		require.NoError(t, err)
+		require.Equal(t, []string{"amqp://orb.mq2.domain1.com:5672", "amqps://orb.mq3.domain1.com:5671"},
+			failoverURLs)
+		require.True(t, confirmsEnabled)
+		require.Equal(t, 5*time.Second, confirmTimeout)
+	})
+
+	t.Run("Invalid failover URL -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, mqFailoverURLsEnvKey, "http://orb.mq2.domain1.com")
+		defer restoreEnv()
+
+		_, _, _, err := getMQFailoverParameters(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid AMQP URL")
+	})
+
+	t.Run("Invalid publisher confirms value -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, mqPublisherConfirmsEnabledEnvKey, "xxx")
+		defer restoreEnv()
+
+		_, _, _, err := getMQFailoverParameters(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for mq-publisher-confirms-enabled")
+	})
+
+	t.Run("Invalid confirm timeout -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, mqPublishConfirmTimeoutEnvKey, "xxx")
+		defer restoreEnv()
+
+		_, _, _, err := getMQFailoverParameters(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "mq-publish-confirm-timeout")
+	})
+}
+
 func TestCreateActivityPubStore(t *testing.T) {
 	t.Run("Fail to create CouchDB provider", func(t *testing.T) {
 		errExpected := errors.New("injected open store error")
@@ -1222,6 +1697,17 @@ func TestGetFollowAuthParameters(t *testing.T) {
 		require.Equal(t, acceptListPolicy, policy)
 	})
 
+	t.Run("Accept list hold policy", func(t *testing.T) {
+		restoreEnv := setEnv(t, followAuthPolicyEnvKey, string(acceptListHoldPolicy))
+		defer restoreEnv()
+
+		cmd := getTestCmd(t)
+
+		policy, err := getFollowAuthPolicy(cmd)
+		require.NoError(t, err)
+		require.Equal(t, acceptListHoldPolicy, policy)
+	})
+
 	t.Run("Not specified -> default value", func(t *testing.T) {
 		cmd := getTestCmd(t)
 
@@ -1337,6 +1823,1295 @@ func TestGetActivityPubClientParameters(t *testing.T) {
 	})
 }
 
+func TestGetLeaderElectionParameters(t *testing.T) {
+	t.Run("Valid env values", func(t *testing.T) {
+		restoreEnabledEnv := setEnv(t, leaderElectionEnabledEnvKey, "true")
+		restoreLeaseEnv := setEnv(t, leaderLeaseDurationEnvKey, "15s")
+
+		defer func() {
+			restoreEnabledEnv()
+			restoreLeaseEnv()
+		}()
+
+		enabled, leaseDuration, err := getLeaderElectionParameters(getTestCmd(t))
+		require.NoError(t, err)
+		require.True(t, enabled)
+		require.Equal(t, 15*time.Second, leaseDuration)
+	})
+
+	t.Run("Not specified -> default values", func(t *testing.T) {
+		enabled, leaseDuration, err := getLeaderElectionParameters(getTestCmd(t))
+		require.NoError(t, err)
+		require.False(t, enabled)
+		require.Equal(t, leaderelection.DefaultLeaseDuration, leaseDuration)
+	})
+
+	t.Run("Invalid enabled value -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, leaderElectionEnabledEnvKey, "xxx")
+		defer restoreEnv()
+
+		_, _, err := getLeaderElectionParameters(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for leader-election-enabled")
+	})
+
+	t.Run("Invalid lease duration -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, leaderLeaseDurationEnvKey, "xxx")
+		defer restoreEnv()
+
+		_, _, err := getLeaderElectionParameters(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), leaderLeaseDurationFlagName)
+	})
+}
+
+func TestGetObserverShardingParameters(t *testing.T) {
+	t.Run("Valid env values", func(t *testing.T) {
+		restoreEnabledEnv := setEnv(t, observerShardingEnabledEnvKey, "true")
+		restoreIDEnv := setEnv(t, observerShardIDEnvKey, "orb-1")
+		restoreIntervalEnv := setEnv(t, observerShardHeartbeatIntervalEnvKey, "5s")
+
+		defer func() {
+			restoreEnabledEnv()
+			restoreIDEnv()
+			restoreIntervalEnv()
+		}()
+
+		enabled, shardID, heartbeatInterval, err := getObserverShardingParameters(getTestCmd(t))
+		require.NoError(t, err)
+		require.True(t, enabled)
+		require.Equal(t, "orb-1", shardID)
+		require.Equal(t, 5*time.Second, heartbeatInterval)
+	})
+
+	t.Run("Not specified -> default values", func(t *testing.T) {
+		hostname, err := os.Hostname()
+		require.NoError(t, err)
+
+		enabled, shardID, heartbeatInterval, err := getObserverShardingParameters(getTestCmd(t))
+		require.NoError(t, err)
+		require.False(t, enabled)
+		require.Equal(t, hostname, shardID)
+		require.Equal(t, shard.DefaultHeartbeatInterval, heartbeatInterval)
+	})
+
+	t.Run("Invalid enabled value -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, observerShardingEnabledEnvKey, "xxx")
+		defer restoreEnv()
+
+		_, _, _, err := getObserverShardingParameters(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for observer-sharding-enabled")
+	})
+
+	t.Run("Invalid heartbeat interval -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, observerShardHeartbeatIntervalEnvKey, "xxx")
+		defer restoreEnv()
+
+		_, _, _, err := getObserverShardingParameters(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), observerShardHeartbeatIntervalFlagName)
+	})
+}
+
+func TestGetActivitySinkParameters(t *testing.T) {
+	t.Run("Valid env values", func(t *testing.T) {
+		restoreURLEnv := setEnv(t, activitySinkWebhookURLEnvKey, "https://sink.example.com/events")
+		restoreIntervalEnv := setEnv(t, activitySinkIntervalEnvKey, "30s")
+		restoreBatchSizeEnv := setEnv(t, activitySinkBatchSizeEnvKey, "500")
+
+		defer func() {
+			restoreURLEnv()
+			restoreIntervalEnv()
+			restoreBatchSizeEnv()
+		}()
+
+		params, err := getActivitySinkParameters(getTestCmd(t))
+		require.NoError(t, err)
+		require.Equal(t, "https://sink.example.com/events", params.webhookURL)
+		require.Equal(t, 30*time.Second, params.interval)
+		require.Equal(t, 500, params.batchSize)
+	})
+
+	t.Run("Not specified -> default values", func(t *testing.T) {
+		params, err := getActivitySinkParameters(getTestCmd(t))
+		require.NoError(t, err)
+		require.Empty(t, params.webhookURL)
+		require.Equal(t, defaultActivitySinkInterval, params.interval)
+		require.Equal(t, defaultActivitySinkBatchSize, params.batchSize)
+	})
+
+	t.Run("Invalid webhook URL -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, activitySinkWebhookURLEnvKey, "ftp://sink.example.com")
+		defer restoreEnv()
+
+		_, err := getActivitySinkParameters(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for activity-sink-webhook-url")
+	})
+
+	t.Run("Invalid interval -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, activitySinkIntervalEnvKey, "xxx")
+		defer restoreEnv()
+
+		_, err := getActivitySinkParameters(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), activitySinkIntervalFlagName)
+	})
+
+	t.Run("Invalid batch size -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, activitySinkBatchSizeEnvKey, "xxx")
+		defer restoreEnv()
+
+		_, err := getActivitySinkParameters(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value [xxx] for parameter [activity-sink-batch-size]")
+	})
+
+	t.Run("Zero batch size -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, activitySinkBatchSizeEnvKey, "0")
+		defer restoreEnv()
+
+		_, err := getActivitySinkParameters(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "must be greater than 0")
+	})
+}
+
+func TestGetAcceptListRegistryParameters(t *testing.T) {
+	const (
+		registryURL = "https://registry.example.com/acceptlists.jws"
+		publicKey   = "6iRTL9HiqT3X/xk4h8h3fmuKcO4QqX6j4aJ1BtQfaBk"
+	)
+
+	t.Run("Valid env values", func(t *testing.T) {
+		restoreURLEnv := setEnv(t, acceptListRegistryURLEnvKey, registryURL)
+		restoreKeyEnv := setEnv(t, acceptListRegistryPublicKeyEnvKey, publicKey+"=")
+		restoreIntervalEnv := setEnv(t, acceptListRegistryIntervalEnvKey, "10m")
+
+		defer func() {
+			restoreURLEnv()
+			restoreKeyEnv()
+			restoreIntervalEnv()
+		}()
+
+		params, err := getAcceptListRegistryParameters(getTestCmd(t))
+		require.NoError(t, err)
+		require.Equal(t, registryURL, params.url)
+		require.Len(t, params.publicKey, 32)
+		require.Equal(t, 10*time.Minute, params.interval)
+	})
+
+	t.Run("Not specified -> disabled", func(t *testing.T) {
+		params, err := getAcceptListRegistryParameters(getTestCmd(t))
+		require.NoError(t, err)
+		require.Empty(t, params.url)
+	})
+
+	t.Run("Default interval", func(t *testing.T) {
+		restoreURLEnv := setEnv(t, acceptListRegistryURLEnvKey, registryURL)
+		restoreKeyEnv := setEnv(t, acceptListRegistryPublicKeyEnvKey, publicKey)
+
+		defer func() {
+			restoreURLEnv()
+			restoreKeyEnv()
+		}()
+
+		params, err := getAcceptListRegistryParameters(getTestCmd(t))
+		require.NoError(t, err)
+		require.Equal(t, defaultAcceptListRegistryInterval, params.interval)
+	})
+
+	t.Run("Invalid URL -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, acceptListRegistryURLEnvKey, "ftp://registry.example.com")
+		defer restoreEnv()
+
+		_, err := getAcceptListRegistryParameters(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for acceptlist-registry-url")
+	})
+
+	t.Run("Missing public key -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, acceptListRegistryURLEnvKey, registryURL)
+		defer restoreEnv()
+
+		_, err := getAcceptListRegistryParameters(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "acceptlist-registry-public-key is required")
+	})
+
+	t.Run("Invalid public key -> error", func(t *testing.T) {
+		restoreURLEnv := setEnv(t, acceptListRegistryURLEnvKey, registryURL)
+		restoreKeyEnv := setEnv(t, acceptListRegistryPublicKeyEnvKey, "!!!")
+
+		defer func() {
+			restoreURLEnv()
+			restoreKeyEnv()
+		}()
+
+		_, err := getAcceptListRegistryParameters(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for acceptlist-registry-public-key")
+
+		restoreKeyEnv2 := setEnv(t, acceptListRegistryPublicKeyEnvKey, "YWJj")
+		defer restoreKeyEnv2()
+
+		_, err = getAcceptListRegistryParameters(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "expecting an Ed25519 public key")
+	})
+
+	t.Run("Invalid interval -> error", func(t *testing.T) {
+		restoreURLEnv := setEnv(t, acceptListRegistryURLEnvKey, registryURL)
+		restoreKeyEnv := setEnv(t, acceptListRegistryPublicKeyEnvKey, publicKey)
+		restoreIntervalEnv := setEnv(t, acceptListRegistryIntervalEnvKey, "5")
+
+		defer func() {
+			restoreURLEnv()
+			restoreKeyEnv()
+			restoreIntervalEnv()
+		}()
+
+		_, err := getAcceptListRegistryParameters(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "missing unit in duration")
+
+		restoreIntervalEnv2 := setEnv(t, acceptListRegistryIntervalEnvKey, "0s")
+		defer restoreIntervalEnv2()
+
+		_, err = getAcceptListRegistryParameters(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "value must be greater than 0")
+	})
+}
+
+func TestGetGraphQLEnabled(t *testing.T) {
+	t.Run("Not specified -> default value", func(t *testing.T) {
+		enabled, err := getGraphQLEnabled(getTestCmd(t))
+		require.NoError(t, err)
+		require.False(t, enabled)
+	})
+
+	t.Run("Valid env value", func(t *testing.T) {
+		restoreEnv := setEnv(t, graphQLEnabledEnvKey, "true")
+		defer restoreEnv()
+
+		enabled, err := getGraphQLEnabled(getTestCmd(t))
+		require.NoError(t, err)
+		require.True(t, enabled)
+	})
+
+	t.Run("Invalid value -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, graphQLEnabledEnvKey, "xxx")
+		defer restoreEnv()
+
+		_, err := getGraphQLEnabled(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for graphql-enabled")
+	})
+}
+
+func TestGetUniversalResolverDriverEnabled(t *testing.T) {
+	t.Run("Not specified -> default value", func(t *testing.T) {
+		enabled, err := getUniversalResolverDriverEnabled(getTestCmd(t))
+		require.NoError(t, err)
+		require.False(t, enabled)
+	})
+
+	t.Run("Valid env value", func(t *testing.T) {
+		restoreEnv := setEnv(t, universalResolverDriverEnabledEnvKey, "true")
+		defer restoreEnv()
+
+		enabled, err := getUniversalResolverDriverEnabled(getTestCmd(t))
+		require.NoError(t, err)
+		require.True(t, enabled)
+	})
+
+	t.Run("Invalid value -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, universalResolverDriverEnabledEnvKey, "xxx")
+		defer restoreEnv()
+
+		_, err := getUniversalResolverDriverEnabled(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for universal-resolver-driver-enabled")
+	})
+}
+
+func TestGetWebhooksEnabled(t *testing.T) {
+	t.Run("Not specified -> default value", func(t *testing.T) {
+		enabled, err := getWebhooksEnabled(getTestCmd(t))
+		require.NoError(t, err)
+		require.False(t, enabled)
+	})
+
+	t.Run("Valid env value", func(t *testing.T) {
+		restoreEnv := setEnv(t, webhooksEnabledEnvKey, "true")
+		defer restoreEnv()
+
+		enabled, err := getWebhooksEnabled(getTestCmd(t))
+		require.NoError(t, err)
+		require.True(t, enabled)
+	})
+
+	t.Run("Invalid value -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, webhooksEnabledEnvKey, "xxx")
+		defer restoreEnv()
+
+		_, err := getWebhooksEnabled(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for webhooks-enabled")
+	})
+}
+
+func TestGetDIDWebhooksEnabled(t *testing.T) {
+	t.Run("Not specified -> default value", func(t *testing.T) {
+		enabled, err := getDIDWebhooksEnabled(getTestCmd(t))
+		require.NoError(t, err)
+		require.False(t, enabled)
+	})
+
+	t.Run("Valid env value", func(t *testing.T) {
+		restoreEnv := setEnv(t, didWebhooksEnabledEnvKey, "true")
+		defer restoreEnv()
+
+		enabled, err := getDIDWebhooksEnabled(getTestCmd(t))
+		require.NoError(t, err)
+		require.True(t, enabled)
+	})
+
+	t.Run("Invalid value -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, didWebhooksEnabledEnvKey, "xxx")
+		defer restoreEnv()
+
+		_, err := getDIDWebhooksEnabled(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for did-webhooks-enabled")
+	})
+}
+
+func TestGetOperationStatusEnabled(t *testing.T) {
+	t.Run("Not specified -> default value", func(t *testing.T) {
+		enabled, err := getOperationStatusEnabled(getTestCmd(t))
+		require.NoError(t, err)
+		require.False(t, enabled)
+	})
+
+	t.Run("Valid env value", func(t *testing.T) {
+		restoreEnv := setEnv(t, operationStatusEnabledEnvKey, "true")
+		defer restoreEnv()
+
+		enabled, err := getOperationStatusEnabled(getTestCmd(t))
+		require.NoError(t, err)
+		require.True(t, enabled)
+	})
+
+	t.Run("Invalid value -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, operationStatusEnabledEnvKey, "xxx")
+		defer restoreEnv()
+
+		_, err := getOperationStatusEnabled(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for operation-status-enabled")
+	})
+}
+
+func TestGetAnchorExplorerEnabled(t *testing.T) {
+	t.Run("Not specified -> default value", func(t *testing.T) {
+		enabled, err := getAnchorExplorerEnabled(getTestCmd(t))
+		require.NoError(t, err)
+		require.False(t, enabled)
+	})
+
+	t.Run("Valid env value", func(t *testing.T) {
+		restoreEnv := setEnv(t, anchorExplorerEnabledEnvKey, "true")
+		defer restoreEnv()
+
+		enabled, err := getAnchorExplorerEnabled(getTestCmd(t))
+		require.NoError(t, err)
+		require.True(t, enabled)
+	})
+
+	t.Run("Invalid value -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, anchorExplorerEnabledEnvKey, "xxx")
+		defer restoreEnv()
+
+		_, err := getAnchorExplorerEnabled(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for anchor-explorer-enabled")
+	})
+}
+
+func TestGetMigrationEnabled(t *testing.T) {
+	t.Run("Not specified -> default value", func(t *testing.T) {
+		enabled, err := getMigrationEnabled(getTestCmd(t))
+		require.NoError(t, err)
+		require.False(t, enabled)
+	})
+
+	t.Run("Valid env value", func(t *testing.T) {
+		restoreEnv := setEnv(t, migrationEnabledEnvKey, "true")
+		defer restoreEnv()
+
+		restoreIPFSEnv := setEnv(t, ipfsURLEnvKey, "https://ipfs.io")
+		defer restoreIPFSEnv()
+
+		enabled, err := getMigrationEnabled(getTestCmd(t))
+		require.NoError(t, err)
+		require.True(t, enabled)
+	})
+
+	t.Run("IPFS URL not specified -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, migrationEnabledEnvKey, "true")
+		defer restoreEnv()
+
+		_, err := getMigrationEnabled(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "ipfs-url must be specified when migration-enabled is true")
+	})
+
+	t.Run("Invalid value -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, migrationEnabledEnvKey, "xxx")
+		defer restoreEnv()
+
+		_, err := getMigrationEnabled(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for migration-enabled")
+	})
+}
+
+func TestGetDIDNetwork(t *testing.T) {
+	t.Run("Not specified -> no network", func(t *testing.T) {
+		network, err := getDIDNetwork(getTestCmd(t))
+		require.NoError(t, err)
+		require.Empty(t, network)
+	})
+
+	t.Run("Valid env value", func(t *testing.T) {
+		restoreEnv := setEnv(t, didNetworkEnvKey, "testnet1")
+		defer restoreEnv()
+
+		network, err := getDIDNetwork(getTestCmd(t))
+		require.NoError(t, err)
+		require.Equal(t, "testnet1", network)
+	})
+
+	t.Run("Invalid value -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, didNetworkEnvKey, "test:net")
+		defer restoreEnv()
+
+		_, err := getDIDNetwork(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "did-network: invalid value [test:net]")
+	})
+}
+
+func TestGetIPFSPubSubAnchorTopic(t *testing.T) {
+	t.Run("Not specified -> disabled", func(t *testing.T) {
+		topic, err := getIPFSPubSubAnchorTopic(getTestCmd(t))
+		require.NoError(t, err)
+		require.Empty(t, topic)
+	})
+
+	t.Run("Valid env value", func(t *testing.T) {
+		restoreEnv := setEnv(t, ipfsPubSubAnchorTopicEnvKey, "orb-anchors")
+		defer restoreEnv()
+
+		restoreIPFSEnv := setEnv(t, ipfsURLEnvKey, "localhost:5001")
+		defer restoreIPFSEnv()
+
+		topic, err := getIPFSPubSubAnchorTopic(getTestCmd(t))
+		require.NoError(t, err)
+		require.Equal(t, "orb-anchors", topic)
+	})
+
+	t.Run("IPFS URL not specified -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, ipfsPubSubAnchorTopicEnvKey, "orb-anchors")
+		defer restoreEnv()
+
+		_, err := getIPFSPubSubAnchorTopic(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "ipfs-url must be specified when ipfs-pubsub-anchor-topic is set")
+	})
+}
+
+func TestGetStoreMigrationsEnabled(t *testing.T) {
+	t.Run("Not specified -> default value", func(t *testing.T) {
+		enabled, err := getStoreMigrationsEnabled(getTestCmd(t))
+		require.NoError(t, err)
+		require.True(t, enabled)
+	})
+
+	t.Run("Valid env value", func(t *testing.T) {
+		restoreEnv := setEnv(t, storeMigrationsEnabledEnvKey, "false")
+		defer restoreEnv()
+
+		enabled, err := getStoreMigrationsEnabled(getTestCmd(t))
+		require.NoError(t, err)
+		require.False(t, enabled)
+	})
+
+	t.Run("Invalid value -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, storeMigrationsEnabledEnvKey, "xxx")
+		defer restoreEnv()
+
+		_, err := getStoreMigrationsEnabled(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for store-migrations-enabled")
+	})
+}
+
+func TestGetDeliveryAnalyticsEnabled(t *testing.T) {
+	t.Run("Not specified -> default value", func(t *testing.T) {
+		enabled, err := getDeliveryAnalyticsEnabled(getTestCmd(t))
+		require.NoError(t, err)
+		require.False(t, enabled)
+	})
+
+	t.Run("Valid env value", func(t *testing.T) {
+		restoreEnv := setEnv(t, deliveryAnalyticsEnabledEnvKey, "true")
+		defer restoreEnv()
+
+		enabled, err := getDeliveryAnalyticsEnabled(getTestCmd(t))
+		require.NoError(t, err)
+		require.True(t, enabled)
+	})
+
+	t.Run("Invalid value -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, deliveryAnalyticsEnabledEnvKey, "xxx")
+		defer restoreEnv()
+
+		_, err := getDeliveryAnalyticsEnabled(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for delivery-analytics-enabled")
+	})
+}
+
+func TestGetPersistentMetricsParameters(t *testing.T) {
+	t.Run("Not specified -> default values", func(t *testing.T) {
+		hostname, err := os.Hostname()
+		require.NoError(t, err)
+
+		enabled, instance, err := getPersistentMetricsParameters(getTestCmd(t))
+		require.NoError(t, err)
+		require.True(t, enabled)
+		require.Equal(t, hostname, instance)
+	})
+
+	t.Run("Valid env values", func(t *testing.T) {
+		restoreEnabledEnv := setEnv(t, persistentMetricsEnabledEnvKey, "false")
+		restoreInstanceEnv := setEnv(t, persistentMetricsInstanceEnvKey, "orb-1")
+
+		defer func() {
+			restoreEnabledEnv()
+			restoreInstanceEnv()
+		}()
+
+		enabled, instance, err := getPersistentMetricsParameters(getTestCmd(t))
+		require.NoError(t, err)
+		require.False(t, enabled)
+		require.Equal(t, "orb-1", instance)
+	})
+
+	t.Run("Invalid enabled value -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, persistentMetricsEnabledEnvKey, "xxx")
+		defer restoreEnv()
+
+		_, _, err := getPersistentMetricsParameters(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for persistent-metrics-enabled")
+	})
+}
+
+func TestGetInboxQuarantineEnabled(t *testing.T) {
+	t.Run("Not specified -> default value", func(t *testing.T) {
+		enabled, err := getInboxQuarantineEnabled(getTestCmd(t))
+		require.NoError(t, err)
+		require.False(t, enabled)
+	})
+
+	t.Run("Valid env value", func(t *testing.T) {
+		restoreEnv := setEnv(t, inboxQuarantineEnabledEnvKey, "true")
+		defer restoreEnv()
+
+		enabled, err := getInboxQuarantineEnabled(getTestCmd(t))
+		require.NoError(t, err)
+		require.True(t, enabled)
+	})
+
+	t.Run("Invalid value -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, inboxQuarantineEnabledEnvKey, "xxx")
+		defer restoreEnv()
+
+		_, err := getInboxQuarantineEnabled(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for inbox-quarantine-enabled")
+	})
+}
+
+func TestGetAccessTokensEnabled(t *testing.T) {
+	t.Run("Not specified -> default value", func(t *testing.T) {
+		enabled, err := getAccessTokensEnabled(getTestCmd(t))
+		require.NoError(t, err)
+		require.False(t, enabled)
+	})
+
+	t.Run("Valid env value", func(t *testing.T) {
+		restoreEnv := setEnv(t, accessTokensEnabledEnvKey, "true")
+		defer restoreEnv()
+
+		enabled, err := getAccessTokensEnabled(getTestCmd(t))
+		require.NoError(t, err)
+		require.True(t, enabled)
+	})
+
+	t.Run("Invalid value -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, accessTokensEnabledEnvKey, "xxx")
+		defer restoreEnv()
+
+		_, err := getAccessTokensEnabled(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for access-tokens-enabled")
+	})
+}
+
+func TestGetActivitySearchEnabled(t *testing.T) {
+	t.Run("Not specified -> default value", func(t *testing.T) {
+		enabled, err := getActivitySearchEnabled(getTestCmd(t))
+		require.NoError(t, err)
+		require.False(t, enabled)
+	})
+
+	t.Run("Valid env value", func(t *testing.T) {
+		restoreEnv := setEnv(t, activitySearchEnabledEnvKey, "true")
+		defer restoreEnv()
+
+		enabled, err := getActivitySearchEnabled(getTestCmd(t))
+		require.NoError(t, err)
+		require.True(t, enabled)
+	})
+
+	t.Run("Invalid value -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, activitySearchEnabledEnvKey, "xxx")
+		defer restoreEnv()
+
+		_, err := getActivitySearchEnabled(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for activity-search-enabled")
+	})
+}
+
+func TestGetJSONLDRemoteContextFetchEnabled(t *testing.T) {
+	t.Run("Not specified -> default value", func(t *testing.T) {
+		enabled, err := getJSONLDRemoteContextFetchEnabled(getTestCmd(t))
+		require.NoError(t, err)
+		require.False(t, enabled)
+	})
+
+	t.Run("Valid env value", func(t *testing.T) {
+		restoreEnv := setEnv(t, jsonldRemoteContextFetchEnabledEnvKey, "true")
+		defer restoreEnv()
+
+		enabled, err := getJSONLDRemoteContextFetchEnabled(getTestCmd(t))
+		require.NoError(t, err)
+		require.True(t, enabled)
+	})
+
+	t.Run("Invalid value -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, jsonldRemoteContextFetchEnabledEnvKey, "xxx")
+		defer restoreEnv()
+
+		_, err := getJSONLDRemoteContextFetchEnabled(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for jsonld-remote-context-fetch-enabled")
+	})
+}
+
+func TestGetVCTLogAllowListEnabled(t *testing.T) {
+	t.Run("Not specified -> default value", func(t *testing.T) {
+		enabled, err := getVCTLogAllowListEnabled(getTestCmd(t))
+		require.NoError(t, err)
+		require.False(t, enabled)
+	})
+
+	t.Run("Valid env value", func(t *testing.T) {
+		restoreEnv := setEnv(t, vctLogAllowListEnabledEnvKey, "true")
+		defer restoreEnv()
+
+		enabled, err := getVCTLogAllowListEnabled(getTestCmd(t))
+		require.NoError(t, err)
+		require.True(t, enabled)
+	})
+
+	t.Run("Invalid value -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, vctLogAllowListEnabledEnvKey, "xxx")
+		defer restoreEnv()
+
+		_, err := getVCTLogAllowListEnabled(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for vct-log-allow-list-enabled")
+	})
+}
+
+func TestGetLinksetSigningParameters(t *testing.T) {
+	t.Run("Not specified -> default value", func(t *testing.T) {
+		enabled, required, err := getLinksetSigningParameters(getTestCmd(t))
+		require.NoError(t, err)
+		require.False(t, enabled)
+		require.False(t, required)
+	})
+
+	t.Run("Valid env value", func(t *testing.T) {
+		restoreEnv := setEnv(t, linksetSigningEnabledEnvKey, "true")
+		defer restoreEnv()
+
+		restoreRequiredEnv := setEnv(t, linksetSignatureRequiredEnvKey, "true")
+		defer restoreRequiredEnv()
+
+		enabled, required, err := getLinksetSigningParameters(getTestCmd(t))
+		require.NoError(t, err)
+		require.True(t, enabled)
+		require.True(t, required)
+	})
+
+	t.Run("Invalid value -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, linksetSigningEnabledEnvKey, "xxx")
+		defer restoreEnv()
+
+		_, _, err := getLinksetSigningParameters(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for linkset-signing-enabled")
+	})
+
+	t.Run("Invalid signature required value -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, linksetSignatureRequiredEnvKey, "xxx")
+		defer restoreEnv()
+
+		_, _, err := getLinksetSigningParameters(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for linkset-signature-required")
+	})
+}
+
+func TestGetInviteWitnessReciprocation(t *testing.T) {
+	t.Run("Not specified -> default value", func(t *testing.T) {
+		policy, err := getInviteWitnessReciprocation(getTestCmd(t))
+		require.NoError(t, err)
+		require.Equal(t, noReciprocation, policy)
+	})
+
+	t.Run("Valid env value", func(t *testing.T) {
+		restoreEnv := setEnv(t, inviteWitnessReciprocationEnvKey, "invite-witness-and-follow")
+		defer restoreEnv()
+
+		policy, err := getInviteWitnessReciprocation(getTestCmd(t))
+		require.NoError(t, err)
+		require.Equal(t, inviteWitnessAndFollowReciprocation, policy)
+	})
+
+	t.Run("Valid arg value", func(t *testing.T) {
+		policy, err := getInviteWitnessReciprocation(getTestCmd(t,
+			"--"+inviteWitnessReciprocationFlagName, "invite-witness"))
+		require.NoError(t, err)
+		require.Equal(t, inviteWitnessReciprocation, policy)
+	})
+
+	t.Run("Invalid value -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, inviteWitnessReciprocationEnvKey, "xxx")
+		defer restoreEnv()
+
+		_, err := getInviteWitnessReciprocation(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unsupported reciprocation policy: xxx")
+	})
+}
+
+func TestGetWitnessProofBatchParameters(t *testing.T) {
+	t.Run("Not specified -> default value", func(t *testing.T) {
+		window, size, err := getWitnessProofBatchParameters(getTestCmd(t))
+		require.NoError(t, err)
+		require.Zero(t, window)
+		require.Equal(t, defaultWitnessProofBatchSize, size)
+	})
+
+	t.Run("Valid env values", func(t *testing.T) {
+		restoreWindow := setEnv(t, witnessProofBatchWindowEnvKey, "500ms")
+		defer restoreWindow()
+
+		restoreSize := setEnv(t, witnessProofBatchSizeEnvKey, "50")
+		defer restoreSize()
+
+		window, size, err := getWitnessProofBatchParameters(getTestCmd(t))
+		require.NoError(t, err)
+		require.Equal(t, 500*time.Millisecond, window)
+		require.Equal(t, 50, size)
+	})
+
+	t.Run("Invalid window -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, witnessProofBatchWindowEnvKey, "xxx")
+		defer restoreEnv()
+
+		_, _, err := getWitnessProofBatchParameters(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), witnessProofBatchWindowFlagName)
+	})
+
+	t.Run("Negative window -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, witnessProofBatchWindowEnvKey, "-1s")
+		defer restoreEnv()
+
+		_, _, err := getWitnessProofBatchParameters(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "value must not be negative")
+	})
+
+	t.Run("Invalid size -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, witnessProofBatchSizeEnvKey, "xxx")
+		defer restoreEnv()
+
+		_, _, err := getWitnessProofBatchParameters(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for witness-proof-batch-size")
+	})
+
+	t.Run("Zero size -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, witnessProofBatchSizeEnvKey, "0")
+		defer restoreEnv()
+
+		_, _, err := getWitnessProofBatchParameters(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "value must be greater than 0")
+	})
+}
+
+func TestGetInboxQueueParameters(t *testing.T) {
+	const actor1 = "https://orb.domain2.com/services/orb"
+
+	t.Run("Not specified -> default values", func(t *testing.T) {
+		workers, queueSize, policy, err := getInboxQueueParameters(getTestCmd(t))
+		require.NoError(t, err)
+		require.Equal(t, defaultInboxWorkers, workers)
+		require.Equal(t, defaultInboxWorkers, queueSize)
+		require.Equal(t, defaultInboxSenderMaxInFlight, policy.MaxInFlight(actor1))
+	})
+
+	t.Run("Valid env values", func(t *testing.T) {
+		restoreWorkers := setEnv(t, inboxWorkersEnvKey, "4")
+		defer restoreWorkers()
+
+		restoreQueueSize := setEnv(t, inboxQueueSizeEnvKey, "50")
+		defer restoreQueueSize()
+
+		restoreMaxInFlight := setEnv(t, inboxSenderMaxInFlightEnvKey, "2")
+		defer restoreMaxInFlight()
+
+		restoreOverrides := setEnv(t, inboxSenderMaxInFlightOverridesEnvKey, actor1+"=3")
+		defer restoreOverrides()
+
+		workers, queueSize, policy, err := getInboxQueueParameters(getTestCmd(t))
+		require.NoError(t, err)
+		require.Equal(t, 4, workers)
+		require.Equal(t, 50, queueSize)
+		require.Equal(t, 3, policy.MaxInFlight(actor1))
+		require.Equal(t, 2, policy.MaxInFlight("https://orb.domain3.com/services/orb"))
+	})
+
+	t.Run("Invalid workers -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, inboxWorkersEnvKey, "xxx")
+		defer restoreEnv()
+
+		_, _, _, err := getInboxQueueParameters(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for inbox-workers")
+	})
+
+	t.Run("Zero queue size -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, inboxQueueSizeEnvKey, "0")
+		defer restoreEnv()
+
+		_, _, _, err := getInboxQueueParameters(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "value must be greater than 0")
+	})
+
+	t.Run("Queue size less than workers -> error", func(t *testing.T) {
+		restoreWorkers := setEnv(t, inboxWorkersEnvKey, "4")
+		defer restoreWorkers()
+
+		restoreQueueSize := setEnv(t, inboxQueueSizeEnvKey, "2")
+		defer restoreQueueSize()
+
+		_, _, _, err := getInboxQueueParameters(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "value must not be less than the value of inbox-workers")
+	})
+
+	t.Run("Invalid sender max in flight -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, inboxSenderMaxInFlightEnvKey, "-1")
+		defer restoreEnv()
+
+		_, _, _, err := getInboxQueueParameters(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "value must be greater than 0")
+	})
+
+	t.Run("Invalid override -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, inboxSenderMaxInFlightOverridesEnvKey, actor1)
+		defer restoreEnv()
+
+		_, _, _, err := getInboxQueueParameters(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "expecting actorIRI=limit")
+	})
+
+	t.Run("Invalid override limit -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, inboxSenderMaxInFlightOverridesEnvKey, actor1+"=0")
+		defer restoreEnv()
+
+		_, _, _, err := getInboxQueueParameters(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "limit must be a positive integer")
+	})
+}
+
+func TestGetActorKeyPinningPolicy(t *testing.T) {
+	t.Run("Not specified -> default value", func(t *testing.T) {
+		policy, err := getActorKeyPinningPolicy(getTestCmd(t))
+		require.NoError(t, err)
+		require.Equal(t, noKeyPinning, policy)
+	})
+
+	t.Run("Valid env value", func(t *testing.T) {
+		restoreEnv := setEnv(t, actorKeyPinningEnvKey, "approve")
+		defer restoreEnv()
+
+		policy, err := getActorKeyPinningPolicy(getTestCmd(t))
+		require.NoError(t, err)
+		require.Equal(t, approveKeyPinning, policy)
+	})
+
+	t.Run("Valid arg value", func(t *testing.T) {
+		policy, err := getActorKeyPinningPolicy(getTestCmd(t, "--"+actorKeyPinningFlagName, "alert"))
+		require.NoError(t, err)
+		require.Equal(t, alertKeyPinning, policy)
+	})
+
+	t.Run("Invalid value -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, actorKeyPinningEnvKey, "xxx")
+		defer restoreEnv()
+
+		_, err := getActorKeyPinningPolicy(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unsupported key pinning policy: xxx")
+	})
+}
+
+func TestGetWitnessExpiryParameters(t *testing.T) {
+	t.Run("Not specified -> default value", func(t *testing.T) {
+		expiration, renewalWindow, err := getWitnessExpiryParameters(getTestCmd(t))
+		require.NoError(t, err)
+		require.Zero(t, expiration)
+		require.Zero(t, renewalWindow)
+	})
+
+	t.Run("Valid env values", func(t *testing.T) {
+		restoreExpiration := setEnv(t, witnessExpirationEnvKey, "720h")
+		defer restoreExpiration()
+
+		restoreWindow := setEnv(t, witnessRenewalWindowEnvKey, "48h")
+		defer restoreWindow()
+
+		expiration, renewalWindow, err := getWitnessExpiryParameters(getTestCmd(t))
+		require.NoError(t, err)
+		require.Equal(t, 720*time.Hour, expiration)
+		require.Equal(t, 48*time.Hour, renewalWindow)
+	})
+
+	t.Run("Invalid expiration -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, witnessExpirationEnvKey, "5")
+		defer restoreEnv()
+
+		_, _, err := getWitnessExpiryParameters(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "missing unit in duration")
+	})
+
+	t.Run("Negative expiration -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, witnessExpirationEnvKey, "-1h")
+		defer restoreEnv()
+
+		_, _, err := getWitnessExpiryParameters(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "value must not be negative")
+	})
+
+	t.Run("Invalid renewal window -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, witnessRenewalWindowEnvKey, "5")
+		defer restoreEnv()
+
+		_, _, err := getWitnessExpiryParameters(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "missing unit in duration")
+	})
+
+	t.Run("Renewal window not less than expiration -> error", func(t *testing.T) {
+		restoreExpiration := setEnv(t, witnessExpirationEnvKey, "1h")
+		defer restoreExpiration()
+
+		restoreWindow := setEnv(t, witnessRenewalWindowEnvKey, "1h")
+		defer restoreWindow()
+
+		_, _, err := getWitnessExpiryParameters(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "must be less than witness-expiration")
+	})
+}
+
+func TestGetWitnessProofCacheSize(t *testing.T) {
+	t.Run("Not specified -> default value", func(t *testing.T) {
+		size, err := getWitnessProofCacheSize(getTestCmd(t))
+		require.NoError(t, err)
+		require.Equal(t, defaultWitnessProofCacheSize, size)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		restoreEnv := setEnv(t, witnessProofCacheSizeEnvKey, "0")
+		defer restoreEnv()
+
+		size, err := getWitnessProofCacheSize(getTestCmd(t))
+		require.NoError(t, err)
+		require.Zero(t, size)
+	})
+
+	t.Run("Invalid value -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, witnessProofCacheSizeEnvKey, "xxx")
+		defer restoreEnv()
+
+		_, err := getWitnessProofCacheSize(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for witness-proof-cache-size")
+	})
+
+	t.Run("Negative value -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, witnessProofCacheSizeEnvKey, "-1")
+		defer restoreEnv()
+
+		_, err := getWitnessProofCacheSize(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "value must not be negative")
+	})
+}
+
+func TestGetDBParameters_LegacyDatabase(t *testing.T) {
+	restoreDBType := setEnv(t, databaseTypeEnvKey, databaseTypeMongoDBOption)
+	defer restoreDBType()
+
+	t.Run("Not specified", func(t *testing.T) {
+		params, err := getDBParameters(getTestCmd(t), true)
+		require.NoError(t, err)
+		require.Empty(t, params.legacyDatabaseType)
+	})
+
+	t.Run("Valid env values", func(t *testing.T) {
+		restoreType := setEnv(t, legacyDatabaseTypeEnvKey, databaseTypeCouchDBOption)
+		defer restoreType()
+
+		restoreURL := setEnv(t, legacyDatabaseURLEnvKey, "admin:password@couchdb.example.com:5984")
+		defer restoreURL()
+
+		restorePrefix := setEnv(t, legacyDatabasePrefixEnvKey, "orb")
+		defer restorePrefix()
+
+		params, err := getDBParameters(getTestCmd(t), true)
+		require.NoError(t, err)
+		require.Equal(t, databaseTypeCouchDBOption, params.legacyDatabaseType)
+		require.Equal(t, "admin:password@couchdb.example.com:5984", params.legacyDatabaseURL)
+		require.Equal(t, "orb", params.legacyDatabasePrefix)
+	})
+
+	t.Run("Invalid type -> error", func(t *testing.T) {
+		restoreType := setEnv(t, legacyDatabaseTypeEnvKey, databaseTypeMemOption)
+		defer restoreType()
+
+		_, err := getDBParameters(getTestCmd(t), true)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unsupported legacy database type: mem")
+	})
+}
+
+func TestGetDBParameters_ReadDatabase(t *testing.T) {
+	restoreDBType := setEnv(t, databaseTypeEnvKey, databaseTypeMongoDBOption)
+	defer restoreDBType()
+
+	t.Run("Not specified", func(t *testing.T) {
+		params, err := getDBParameters(getTestCmd(t), true)
+		require.NoError(t, err)
+		require.Empty(t, params.readDatabaseURL)
+		require.Equal(t, defaultReadDatabaseMaxStaleness, params.readDatabaseMaxStaleness)
+	})
+
+	t.Run("Valid env values", func(t *testing.T) {
+		restoreURL := setEnv(t, readDatabaseURLEnvKey, "mongodb://mongodb.example.com:27017/?readPreference=secondary")
+		defer restoreURL()
+
+		restoreStaleness := setEnv(t, readDatabaseMaxStalenessEnvKey, "2m")
+		defer restoreStaleness()
+
+		params, err := getDBParameters(getTestCmd(t), true)
+		require.NoError(t, err)
+		require.Equal(t, "mongodb://mongodb.example.com:27017/?readPreference=secondary", params.readDatabaseURL)
+		require.Equal(t, 2*time.Minute, params.readDatabaseMaxStaleness)
+	})
+
+	t.Run("Invalid max staleness -> error", func(t *testing.T) {
+		restoreStaleness := setEnv(t, readDatabaseMaxStalenessEnvKey, "xxx")
+		defer restoreStaleness()
+
+		_, err := getDBParameters(getTestCmd(t), true)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), readDatabaseMaxStalenessFlagName)
+	})
+
+	t.Run("Legacy database -> error", func(t *testing.T) {
+		restoreType := setEnv(t, legacyDatabaseTypeEnvKey, databaseTypeCouchDBOption)
+		defer restoreType()
+
+		restoreURL := setEnv(t, readDatabaseURLEnvKey, "mongodb://mongodb.example.com:27017")
+		defer restoreURL()
+
+		_, err := getDBParameters(getTestCmd(t), true)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "may not be used with "+legacyDatabaseTypeFlagName)
+	})
+
+	t.Run("Memory database -> error", func(t *testing.T) {
+		restoreType := setEnv(t, databaseTypeEnvKey, databaseTypeMemOption)
+		defer restoreType()
+
+		restoreURL := setEnv(t, readDatabaseURLEnvKey, "mongodb://mongodb.example.com:27017")
+		defer restoreURL()
+
+		_, err := getDBParameters(getTestCmd(t), true)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "is not supported for database type mem")
+	})
+}
+
+func TestGetTenants(t *testing.T) {
+	t.Run("Not specified", func(t *testing.T) {
+		tenants, err := getTenants(getTestCmd(t))
+		require.NoError(t, err)
+		require.Nil(t, tenants)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		tenantsFile := writeConfigFile(t, `
+tenants:
+  - id: tenant1
+    host: tenant1.example.com
+    externalEndpoint: https://tenant1.example.com
+`)
+
+		tenants, err := getTenants(getTestCmd(t, "--"+tenantsFileFlagName, tenantsFile))
+		require.NoError(t, err)
+		require.Len(t, tenants, 1)
+		require.Equal(t, "tenant1", tenants[0].ID)
+	})
+
+	t.Run("Invalid file -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, tenantsFileEnvKey, "./invalid/tenants.yaml")
+		defer restoreEnv()
+
+		_, err := getTenants(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), tenantsFileFlagName)
+	})
+}
+
+func TestGetOperationQuotas(t *testing.T) {
+	t.Run("Not specified", func(t *testing.T) {
+		quotas, err := getOperationQuotas(getTestCmd(t))
+		require.NoError(t, err)
+		require.Nil(t, quotas)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		quotasFile := writeConfigFile(t, `
+apiKeys:
+  - name: tier1
+    key: key1
+    operationsPerHour: 100
+    anchorsPerDay: 50
+`)
+
+		quotas, err := getOperationQuotas(getTestCmd(t, "--"+operationQuotasFileFlagName, quotasFile))
+		require.NoError(t, err)
+		require.Nil(t, quotas.Default)
+		require.Len(t, quotas.APIKeys, 1)
+		require.Equal(t, "tier1", quotas.APIKeys[0].Name)
+		require.Equal(t, 100, quotas.APIKeys[0].OperationsPerHour)
+	})
+
+	t.Run("Invalid file -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, operationQuotasFileEnvKey, "./invalid/quotas.yaml")
+		defer restoreEnv()
+
+		_, err := getOperationQuotas(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), operationQuotasFileFlagName)
+	})
+}
+
+func TestGetAnchorReconcileParameters(t *testing.T) {
+	t.Run("Valid env values", func(t *testing.T) {
+		restoreIntervalEnv := setEnv(t, anchorReconcileIntervalEnvKey, "30m")
+		restoreDaysEnv := setEnv(t, anchorReconcileDaysEnvKey, "3")
+
+		defer func() {
+			restoreIntervalEnv()
+			restoreDaysEnv()
+		}()
+
+		interval, days, err := getAnchorReconcileParameters(getTestCmd(t))
+		require.NoError(t, err)
+		require.Equal(t, 30*time.Minute, interval)
+		require.Equal(t, 3, days)
+	})
+
+	t.Run("Not specified -> default values", func(t *testing.T) {
+		interval, days, err := getAnchorReconcileParameters(getTestCmd(t))
+		require.NoError(t, err)
+		require.Equal(t, defaultAnchorReconcileInterval, interval)
+		require.Equal(t, defaultAnchorReconcileDays, days)
+	})
+
+	t.Run("Invalid interval -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, anchorReconcileIntervalEnvKey, "invalid")
+		defer restoreEnv()
+
+		_, _, err := getAnchorReconcileParameters(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), anchorReconcileIntervalFlagName)
+	})
+
+	t.Run("Invalid days -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, anchorReconcileDaysEnvKey, "invalid")
+		defer restoreEnv()
+
+		_, _, err := getAnchorReconcileParameters(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value [invalid] for parameter [anchor-reconcile-days]")
+	})
+
+	t.Run("Days less than 1 -> error", func(t *testing.T) {
+		restoreEnv := setEnv(t, anchorReconcileDaysEnvKey, "0")
+		defer restoreEnv()
+
+		_, _, err := getAnchorReconcileParameters(getTestCmd(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "value for parameter [anchor-reconcile-days] must be greater than 0")
+	})
+}
+
 func TestGetActivityPubIRICacheParameters(t *testing.T) {
 	t.Run("Valid env value -> error", func(t *testing.T) {
 		restoreSizeEnv := setEnv(t, activityPubIRICacheSizeEnvKey, "1000")
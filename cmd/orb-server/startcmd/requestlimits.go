@@ -0,0 +1,83 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package startcmd
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+	restcommon "github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+
+	"github.com/trustbloc/orb/pkg/httpserver/limits"
+)
+
+const (
+	httpMaxBodySizeFlagName  = "http-max-body-size"
+	httpMaxBodySizeEnvKey    = "HTTP_MAX_BODY_SIZE"
+	httpMaxBodySizeFlagUsage = "The maximum size (in bytes) of the body of an HTTP POST request. If the body is " +
+		"larger then the request is rejected with a 413 (Request Entity Too Large). Defaults to 2097152 (2MB). " +
+		commonEnvVarUsageText + httpMaxBodySizeEnvKey
+
+	httpMaxJSONDepthFlagName  = "http-max-json-depth"
+	httpMaxJSONDepthEnvKey    = "HTTP_MAX_JSON_DEPTH"
+	httpMaxJSONDepthFlagUsage = "The maximum nesting depth of objects and arrays in the JSON body of an HTTP POST " +
+		"request. If the depth is exceeded then the request is rejected with a 400 (Bad Request). Defaults to 32. " +
+		commonEnvVarUsageText + httpMaxJSONDepthEnvKey
+
+	httpMaxJSONElementsFlagName  = "http-max-json-elements"
+	httpMaxJSONElementsEnvKey    = "HTTP_MAX_JSON_ELEMENTS"
+	httpMaxJSONElementsFlagUsage = "The maximum number of elements (object keys, values and array elements) in the " +
+		"JSON body of an HTTP POST request. If the number of elements is exceeded then the request is rejected " +
+		"with a 400 (Bad Request). Defaults to 100000. " + commonEnvVarUsageText + httpMaxJSONElementsEnvKey
+)
+
+func getRequestLimits(cmd *cobra.Command) (limits.Config, error) {
+	maxBodySizeStr, err := cmdutils.GetUserSetVarFromString(cmd, httpMaxBodySizeFlagName, httpMaxBodySizeEnvKey, true)
+	if err != nil {
+		return limits.Config{}, err
+	}
+
+	var maxBodySize int64
+
+	if maxBodySizeStr != "" {
+		maxBodySize, err = strconv.ParseInt(maxBodySizeStr, 10, 64)
+		if err != nil || maxBodySize <= 0 {
+			return limits.Config{}, fmt.Errorf("invalid value [%s] for parameter [%s]: must be a positive integer",
+				maxBodySizeStr, httpMaxBodySizeFlagName)
+		}
+	}
+
+	maxJSONDepth, err := getUint(cmd, httpMaxJSONDepthFlagName, httpMaxJSONDepthEnvKey)
+	if err != nil {
+		return limits.Config{}, err
+	}
+
+	maxJSONElements, err := getUint(cmd, httpMaxJSONElementsFlagName, httpMaxJSONElementsEnvKey)
+	if err != nil {
+		return limits.Config{}, err
+	}
+
+	return limits.Config{
+		MaxBodySize:     maxBodySize,
+		MaxJSONDepth:    int(maxJSONDepth),
+		MaxJSONElements: int(maxJSONElements),
+	}, nil
+}
+
+// applyRequestLimits wraps all POST handlers with a handler that enforces the request limits.
+func applyRequestLimits(handlers []restcommon.HTTPHandler, cfg limits.Config) []restcommon.HTTPHandler {
+	for i, handler := range handlers {
+		if handler.Method() == http.MethodPost {
+			handlers[i] = limits.NewHandlerWrapper(handler, cfg)
+		}
+	}
+
+	return handlers
+}
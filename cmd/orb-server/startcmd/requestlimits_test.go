@@ -0,0 +1,101 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package startcmd
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	restcommon "github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+
+	"github.com/trustbloc/orb/pkg/httpserver/limits"
+)
+
+func TestGetRequestLimits(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		startCmd := GetStartCmd()
+		require.NoError(t, startCmd.ParseFlags(nil))
+
+		cfg, err := getRequestLimits(startCmd)
+		require.NoError(t, err)
+		require.Equal(t, limits.Config{}, cfg)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		startCmd := GetStartCmd()
+		require.NoError(t, startCmd.ParseFlags([]string{
+			"--" + httpMaxBodySizeFlagName, "1048576",
+			"--" + httpMaxJSONDepthFlagName, "16",
+			"--" + httpMaxJSONElementsFlagName, "5000",
+		}))
+
+		cfg, err := getRequestLimits(startCmd)
+		require.NoError(t, err)
+		require.Equal(t, int64(1048576), cfg.MaxBodySize)
+		require.Equal(t, 16, cfg.MaxJSONDepth)
+		require.Equal(t, 5000, cfg.MaxJSONElements)
+	})
+
+	t.Run("invalid body size", func(t *testing.T) {
+		startCmd := GetStartCmd()
+		require.NoError(t, startCmd.ParseFlags([]string{"--" + httpMaxBodySizeFlagName, "-1"}))
+
+		_, err := getRequestLimits(startCmd)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value [-1] for parameter [http-max-body-size]")
+	})
+
+	t.Run("invalid JSON depth", func(t *testing.T) {
+		startCmd := GetStartCmd()
+		require.NoError(t, startCmd.ParseFlags([]string{"--" + httpMaxJSONDepthFlagName, "xxx"}))
+
+		_, err := getRequestLimits(startCmd)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value [xxx] for parameter [http-max-json-depth]")
+	})
+
+	t.Run("invalid JSON elements", func(t *testing.T) {
+		startCmd := GetStartCmd()
+		require.NoError(t, startCmd.ParseFlags([]string{"--" + httpMaxJSONElementsFlagName, "xxx"}))
+
+		_, err := getRequestLimits(startCmd)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value [xxx] for parameter [http-max-json-elements]")
+	})
+}
+
+func TestApplyRequestLimits(t *testing.T) {
+	handlers := applyRequestLimits([]restcommon.HTTPHandler{
+		&mockHandler{method: http.MethodGet},
+		&mockHandler{method: http.MethodPost},
+	}, limits.Config{})
+
+	_, ok := handlers[0].(*limits.HandlerWrapper)
+	require.False(t, ok)
+
+	_, ok = handlers[1].(*limits.HandlerWrapper)
+	require.True(t, ok)
+}
+
+type mockHandler struct {
+	method string
+}
+
+func (h *mockHandler) Path() string {
+	return "/path"
+}
+
+func (h *mockHandler) Method() string {
+	return h.method
+}
+
+func (h *mockHandler) Handler() restcommon.HTTPRequestHandler {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package startcmd
+
+import (
+	"compress/flate"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+	restcommon "github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+
+	httpcompression "github.com/trustbloc/orb/pkg/httpserver/compression"
+)
+
+const (
+	httpCompressionEnabledFlagName  = "http-compression-enabled"
+	httpCompressionEnabledEnvKey    = "HTTP_COMPRESSION_ENABLED"
+	httpCompressionEnabledFlagUsage = "Set to true to compress the responses of HTTP GET requests (e.g. " +
+		"ActivityPub collections and DID resolution) using gzip or deflate, depending on the Accept-Encoding " +
+		"header of the request. Only JSON and text responses are compressed. Defaults to false. " +
+		commonEnvVarUsageText + httpCompressionEnabledEnvKey
+
+	httpCompressionMinSizeFlagName  = "http-compression-min-size"
+	httpCompressionMinSizeEnvKey    = "HTTP_COMPRESSION_MIN_SIZE"
+	httpCompressionMinSizeFlagUsage = "The minimum size (in bytes) of a response body that is compressed. " +
+		"Defaults to 1024. " + commonEnvVarUsageText + httpCompressionMinSizeEnvKey
+
+	httpCompressionLevelFlagName  = "http-compression-level"
+	httpCompressionLevelEnvKey    = "HTTP_COMPRESSION_LEVEL"
+	httpCompressionLevelFlagUsage = "The compression level, from 1 (best speed) to 9 (best compression). " +
+		"Defaults to 6. " + commonEnvVarUsageText + httpCompressionLevelEnvKey
+)
+
+type responseCompressionParams struct {
+	enabled bool
+	cfg     httpcompression.Config
+}
+
+func getResponseCompression(cmd *cobra.Command) (*responseCompressionParams, error) {
+	enabledStr := cmdutils.GetUserSetOptionalVarFromString(cmd, httpCompressionEnabledFlagName,
+		httpCompressionEnabledEnvKey)
+
+	var enabled bool
+
+	if enabledStr != "" {
+		var err error
+
+		enabled, err = strconv.ParseBool(enabledStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", httpCompressionEnabledFlagName, err)
+		}
+	}
+
+	minSize, err := getUint(cmd, httpCompressionMinSizeFlagName, httpCompressionMinSizeEnvKey)
+	if err != nil {
+		return nil, err
+	}
+
+	level, err := getUint(cmd, httpCompressionLevelFlagName, httpCompressionLevelEnvKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if level > flate.BestCompression {
+		return nil, fmt.Errorf("invalid value [%d] for parameter [%s]: must be between 1 and %d",
+			level, httpCompressionLevelFlagName, flate.BestCompression)
+	}
+
+	return &responseCompressionParams{
+		enabled: enabled,
+		cfg: httpcompression.Config{
+			MinSize: int(minSize),
+			Level:   int(level),
+		},
+	}, nil
+}
+
+func createResponseCompressionFlags(startCmd *cobra.Command) {
+	startCmd.Flags().String(httpCompressionEnabledFlagName, "", httpCompressionEnabledFlagUsage)
+	startCmd.Flags().String(httpCompressionMinSizeFlagName, "", httpCompressionMinSizeFlagUsage)
+	startCmd.Flags().String(httpCompressionLevelFlagName, "", httpCompressionLevelFlagUsage)
+}
+
+// applyResponseCompression wraps all GET handlers with a handler that compresses the response, if enabled.
+func applyResponseCompression(handlers []restcommon.HTTPHandler,
+	params *responseCompressionParams) []restcommon.HTTPHandler {
+	if !params.enabled {
+		return handlers
+	}
+
+	for i, handler := range handlers {
+		if handler.Method() == http.MethodGet {
+			handlers[i] = httpcompression.NewHandlerWrapper(handler, params.cfg)
+		}
+	}
+
+	return handlers
+}
@@ -0,0 +1,95 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package startcmd
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	restcommon "github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+
+	httpcompression "github.com/trustbloc/orb/pkg/httpserver/compression"
+)
+
+func TestGetResponseCompression(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		startCmd := GetStartCmd()
+		require.NoError(t, startCmd.ParseFlags(nil))
+
+		params, err := getResponseCompression(startCmd)
+		require.NoError(t, err)
+		require.False(t, params.enabled)
+		require.Equal(t, httpcompression.Config{}, params.cfg)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		startCmd := GetStartCmd()
+		require.NoError(t, startCmd.ParseFlags([]string{
+			"--" + httpCompressionEnabledFlagName, "true",
+			"--" + httpCompressionMinSizeFlagName, "512",
+			"--" + httpCompressionLevelFlagName, "9",
+		}))
+
+		params, err := getResponseCompression(startCmd)
+		require.NoError(t, err)
+		require.True(t, params.enabled)
+		require.Equal(t, 512, params.cfg.MinSize)
+		require.Equal(t, 9, params.cfg.Level)
+	})
+
+	t.Run("invalid enabled", func(t *testing.T) {
+		startCmd := GetStartCmd()
+		require.NoError(t, startCmd.ParseFlags([]string{"--" + httpCompressionEnabledFlagName, "xxx"}))
+
+		_, err := getResponseCompression(startCmd)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for http-compression-enabled")
+	})
+
+	t.Run("invalid min size", func(t *testing.T) {
+		startCmd := GetStartCmd()
+		require.NoError(t, startCmd.ParseFlags([]string{"--" + httpCompressionMinSizeFlagName, "-1"}))
+
+		_, err := getResponseCompression(startCmd)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value [-1] for parameter [http-compression-min-size]")
+	})
+
+	t.Run("invalid level", func(t *testing.T) {
+		startCmd := GetStartCmd()
+		require.NoError(t, startCmd.ParseFlags([]string{"--" + httpCompressionLevelFlagName, "10"}))
+
+		_, err := getResponseCompression(startCmd)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value [10] for parameter [http-compression-level]")
+	})
+}
+
+func TestApplyResponseCompression(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		handlers := applyResponseCompression([]restcommon.HTTPHandler{
+			&mockHandler{method: http.MethodGet},
+		}, &responseCompressionParams{})
+
+		_, ok := handlers[0].(*httpcompression.HandlerWrapper)
+		require.False(t, ok)
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		handlers := applyResponseCompression([]restcommon.HTTPHandler{
+			&mockHandler{method: http.MethodGet},
+			&mockHandler{method: http.MethodPost},
+		}, &responseCompressionParams{enabled: true})
+
+		_, ok := handlers[0].(*httpcompression.HandlerWrapper)
+		require.True(t, ok)
+
+		_, ok = handlers[1].(*httpcompression.HandlerWrapper)
+		require.False(t, ok)
+	})
+}
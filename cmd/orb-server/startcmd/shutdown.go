@@ -0,0 +1,116 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package startcmd
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const operationQueueDrainInterval = 100 * time.Millisecond
+
+// shutdownStep is a single step in the graceful shutdown sequence.
+type shutdownStep struct {
+	name string
+	stop func(ctx context.Context) error
+}
+
+func newShutdownStep(name string, stop func()) shutdownStep {
+	return shutdownStep{
+		name: name,
+		stop: func(context.Context) error {
+			stop()
+
+			return nil
+		},
+	}
+}
+
+// shutdown runs the given steps in order. Each step is expected to stop accepting new work and to wait
+// for its in-flight work to complete. If all of the steps don't complete within the given timeout then
+// an error is returned and the remaining steps are abandoned.
+func shutdown(timeout time.Duration, steps ...shutdownStep) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var (
+		mutex   sync.Mutex
+		current string
+	)
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		for _, step := range steps {
+			if ctx.Err() != nil {
+				return
+			}
+
+			mutex.Lock()
+			current = step.name
+			mutex.Unlock()
+
+			logger.Infof("Stopping %s ...", step.name)
+
+			if err := step.stop(ctx); err != nil {
+				logger.Warnf("Error stopping %s: %s", step.name, err)
+			} else {
+				logger.Debugf("... stopped %s", step.name)
+			}
+		}
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		return fmt.Errorf("shutdown did not complete within %s while stopping %s", timeout, current)
+	}
+}
+
+type operationQueue interface {
+	Len() uint
+}
+
+// newOperationQueueDrainStep returns a step that waits (up to the given timeout) for the pending operations
+// in the queue to be cut into a batch by the batch writer. Any operations that remain in the queue are left
+// in the database and are processed by another server instance (or by this instance when it restarts).
+func newOperationQueueDrainStep(q operationQueue, timeout time.Duration) shutdownStep {
+	return shutdownStep{
+		name: "operation queue drain",
+		stop: func(ctx context.Context) error {
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			ticker := time.NewTicker(operationQueueDrainInterval)
+			defer ticker.Stop()
+
+			for {
+				pending := q.Len()
+				if pending == 0 {
+					return nil
+				}
+
+				select {
+				case <-ticker.C:
+				case <-ctx.Done():
+					logger.Warnf("%d operations are still pending in the operation queue. They will be "+
+						"processed by another server instance.", pending)
+
+					return nil
+				}
+			}
+		},
+	}
+}
@@ -0,0 +1,97 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package startcmd
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestShutdown(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		var stopped []string
+
+		err := shutdown(time.Second,
+			newShutdownStep("service1", func() { stopped = append(stopped, "service1") }),
+			shutdownStep{name: "service2", stop: func(context.Context) error {
+				stopped = append(stopped, "service2")
+
+				return errors.New("injected stop error")
+			}},
+			newShutdownStep("service3", func() { stopped = append(stopped, "service3") }),
+		)
+		require.NoError(t, err)
+		require.Equal(t, []string{"service1", "service2", "service3"}, stopped)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		var service3Stopped uint32
+
+		err := shutdown(50*time.Millisecond,
+			newShutdownStep("service1", func() {}),
+			shutdownStep{name: "service2", stop: func(ctx context.Context) error {
+				<-ctx.Done()
+
+				return ctx.Err()
+			}},
+			newShutdownStep("service3", func() { atomic.StoreUint32(&service3Stopped, 1) }),
+		)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "shutdown did not complete within 50ms while stopping service2")
+
+		time.Sleep(10 * time.Millisecond)
+
+		require.Equal(t, uint32(0), atomic.LoadUint32(&service3Stopped))
+	})
+}
+
+func TestOperationQueueDrainStep(t *testing.T) {
+	t.Run("drained", func(t *testing.T) {
+		q := &mockOperationQueue{}
+		q.setLen(3)
+
+		go func() {
+			time.Sleep(200 * time.Millisecond)
+
+			q.setLen(0)
+		}()
+
+		step := newOperationQueueDrainStep(q, time.Second)
+
+		start := time.Now()
+
+		require.NoError(t, step.stop(context.Background()))
+		require.True(t, time.Since(start) >= 200*time.Millisecond)
+		require.True(t, time.Since(start) < time.Second)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		q := &mockOperationQueue{}
+		q.setLen(3)
+
+		step := newOperationQueueDrainStep(q, 50*time.Millisecond)
+
+		require.NoError(t, step.stop(context.Background()))
+	})
+}
+
+type mockOperationQueue struct {
+	len uint32
+}
+
+func (m *mockOperationQueue) setLen(n uint32) {
+	atomic.StoreUint32(&m.len, n)
+}
+
+func (m *mockOperationQueue) Len() uint {
+	return uint(atomic.LoadUint32(&m.len))
+}
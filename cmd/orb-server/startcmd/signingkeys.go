@@ -0,0 +1,253 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package startcmd
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/spf13/cobra"
+	cmdutils "github.com/trustbloc/edge-core/pkg/utils/cmd"
+
+	"github.com/trustbloc/orb/pkg/kms/aws"
+	"github.com/trustbloc/orb/pkg/kms/vault"
+)
+
+type kmsType string
+
+const (
+	// kmsTypeLocal uses the KMS that is configured with the kms-endpoint/kms-store-endpoint flags
+	// or, if neither is set, the local KMS.
+	kmsTypeLocal kmsType = "local"
+	kmsTypeAWS   kmsType = "aws"
+	kmsTypeVault kmsType = "vault"
+
+	httpSignatureKeyPurpose    = "HTTP signature"
+	anchorCredentialKeyPurpose = "anchor credential"
+
+	awsAccessKeyIDEnvKey     = "AWS_ACCESS_KEY_ID"
+	awsSecretAccessKeyEnvKey = "AWS_SECRET_ACCESS_KEY"
+	awsSessionTokenEnvKey    = "AWS_SESSION_TOKEN"
+)
+
+const (
+	kmsTypeFlagUsageText = "Valid values are 'local' (the default, which uses the KMS configured with " +
+		"kms-endpoint/kms-store-endpoint or the local KMS), 'aws' (AWS KMS) and 'vault' (HashiCorp Vault Transit). " +
+		"Only Ed25519 keys are supported by the 'aws' and 'vault' types. "
+
+	httpSignatureKMSTypeFlagName  = "http-signature-kms-type"
+	httpSignatureKMSTypeEnvKey    = "ORB_HTTP_SIGNATURE_KMS_TYPE"
+	httpSignatureKMSTypeFlagUsage = "The type of KMS that holds the key used to sign ActivityPub HTTP requests. " +
+		kmsTypeFlagUsageText + commonEnvVarUsageText + httpSignatureKMSTypeEnvKey
+
+	httpSignatureKMSKeyIDFlagName  = "http-signature-kms-key-id"
+	httpSignatureKMSKeyIDEnvKey    = "ORB_HTTP_SIGNATURE_KMS_KEY_ID"
+	httpSignatureKMSKeyIDFlagUsage = "The ID of the key used to sign ActivityPub HTTP requests. This parameter is " +
+		"required if the HTTP signature KMS type is 'aws' or 'vault'. " +
+		commonEnvVarUsageText + httpSignatureKMSKeyIDEnvKey
+
+	anchorCredentialKMSTypeFlagName  = "anchor-credential-kms-type"
+	anchorCredentialKMSTypeEnvKey    = "ORB_ANCHOR_CREDENTIAL_KMS_TYPE"
+	anchorCredentialKMSTypeFlagUsage = "The type of KMS that holds the key used to sign anchor credentials. " +
+		kmsTypeFlagUsageText + commonEnvVarUsageText + anchorCredentialKMSTypeEnvKey
+
+	anchorCredentialKMSKeyIDFlagName  = "anchor-credential-kms-key-id"
+	anchorCredentialKMSKeyIDEnvKey    = "ORB_ANCHOR_CREDENTIAL_KMS_KEY_ID"
+	anchorCredentialKMSKeyIDFlagUsage = "The ID of the key used to sign anchor credentials. This parameter is " +
+		"required if the anchor credential KMS type is 'aws' or 'vault'. " +
+		commonEnvVarUsageText + anchorCredentialKMSKeyIDEnvKey
+
+	awsKMSRegionFlagName  = "aws-kms-region"
+	awsKMSRegionEnvKey    = "ORB_AWS_KMS_REGION"
+	awsKMSRegionFlagUsage = "The AWS region of the AWS KMS. The AWS credentials are read from the standard " +
+		awsAccessKeyIDEnvKey + ", " + awsSecretAccessKeyEnvKey + " and " + awsSessionTokenEnvKey +
+		" environment variables. " + commonEnvVarUsageText + awsKMSRegionEnvKey
+
+	awsKMSEndpointFlagName  = "aws-kms-endpoint"
+	awsKMSEndpointEnvKey    = "ORB_AWS_KMS_ENDPOINT"
+	awsKMSEndpointFlagUsage = "Overrides the AWS KMS endpoint (for example, for a VPC endpoint). " +
+		commonEnvVarUsageText + awsKMSEndpointEnvKey
+
+	vaultURLFlagName  = "vault-url"
+	vaultURLEnvKey    = "ORB_VAULT_URL"
+	vaultURLFlagUsage = "The URL of the HashiCorp Vault server. " + commonEnvVarUsageText + vaultURLEnvKey
+
+	vaultTokenFlagName  = "vault-token"
+	vaultTokenEnvKey    = "ORB_VAULT_TOKEN"
+	vaultTokenFlagUsage = "The token used to authenticate with the HashiCorp Vault server. " +
+		commonEnvVarUsageText + vaultTokenEnvKey
+
+	vaultTransitMountPathFlagName  = "vault-transit-mount-path"
+	vaultTransitMountPathEnvKey    = "ORB_VAULT_TRANSIT_MOUNT_PATH"
+	vaultTransitMountPathFlagUsage = "The path at which the Vault Transit secrets engine is mounted. Defaults to 'transit'. " +
+		commonEnvVarUsageText + vaultTransitMountPathEnvKey
+)
+
+// signingKeyParameters contains the KMS configuration of a key that is used for a specific purpose.
+type signingKeyParameters struct {
+	kmsType kmsType
+	keyID   string
+}
+
+// externalKMSParameters contains the connection parameters for the external KMS backends.
+type externalKMSParameters struct {
+	awsRegion             string
+	awsEndpoint           string
+	awsCredentials        aws.Credentials
+	vaultURL              string
+	vaultToken            string
+	vaultTransitMountPath string
+}
+
+func getSigningKeyParameters(cmd *cobra.Command) (httpSignatureKey, anchorCredentialKey *signingKeyParameters,
+	externalKMS *externalKMSParameters, err error) {
+	httpSignatureKey, err = getSigningKeyParametersForPurpose(cmd,
+		httpSignatureKMSTypeFlagName, httpSignatureKMSTypeEnvKey,
+		httpSignatureKMSKeyIDFlagName, httpSignatureKMSKeyIDEnvKey)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	anchorCredentialKey, err = getSigningKeyParametersForPurpose(cmd,
+		anchorCredentialKMSTypeFlagName, anchorCredentialKMSTypeEnvKey,
+		anchorCredentialKMSKeyIDFlagName, anchorCredentialKMSKeyIDEnvKey)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	externalKMS = &externalKMSParameters{
+		awsRegion:   cmdutils.GetUserSetOptionalVarFromString(cmd, awsKMSRegionFlagName, awsKMSRegionEnvKey),
+		awsEndpoint: cmdutils.GetUserSetOptionalVarFromString(cmd, awsKMSEndpointFlagName, awsKMSEndpointEnvKey),
+		awsCredentials: aws.Credentials{
+			AccessKeyID:     os.Getenv(awsAccessKeyIDEnvKey),
+			SecretAccessKey: os.Getenv(awsSecretAccessKeyEnvKey),
+			SessionToken:    os.Getenv(awsSessionTokenEnvKey),
+		},
+		vaultURL:   cmdutils.GetUserSetOptionalVarFromString(cmd, vaultURLFlagName, vaultURLEnvKey),
+		vaultToken: cmdutils.GetUserSetOptionalVarFromString(cmd, vaultTokenFlagName, vaultTokenEnvKey),
+		vaultTransitMountPath: cmdutils.GetUserSetOptionalVarFromString(cmd,
+			vaultTransitMountPathFlagName, vaultTransitMountPathEnvKey),
+	}
+
+	for _, p := range []*signingKeyParameters{httpSignatureKey, anchorCredentialKey} {
+		switch p.kmsType {
+		case kmsTypeAWS:
+			if externalKMS.awsRegion == "" {
+				return nil, nil, nil, fmt.Errorf("%s is required for KMS type [%s]", awsKMSRegionFlagName, p.kmsType)
+			}
+		case kmsTypeVault:
+			if externalKMS.vaultURL == "" {
+				return nil, nil, nil, fmt.Errorf("%s is required for KMS type [%s]", vaultURLFlagName, p.kmsType)
+			}
+		case kmsTypeLocal:
+		}
+	}
+
+	return httpSignatureKey, anchorCredentialKey, externalKMS, nil
+}
+
+func getSigningKeyParametersForPurpose(cmd *cobra.Command, typeFlagName, typeEnvKey,
+	kmsKeyIDFlagName, kmsKeyIDEnvKey string) (*signingKeyParameters, error) {
+	kmsTypeStr := cmdutils.GetUserSetOptionalVarFromString(cmd, typeFlagName, typeEnvKey)
+
+	p := &signingKeyParameters{
+		kmsType: kmsTypeLocal,
+		keyID:   cmdutils.GetUserSetOptionalVarFromString(cmd, kmsKeyIDFlagName, kmsKeyIDEnvKey),
+	}
+
+	if kmsTypeStr != "" {
+		p.kmsType = kmsType(kmsTypeStr)
+	}
+
+	switch p.kmsType {
+	case kmsTypeAWS, kmsTypeVault:
+		if p.keyID == "" {
+			return nil, fmt.Errorf("%s is required for KMS type [%s]", kmsKeyIDFlagName, p.kmsType)
+		}
+	case kmsTypeLocal:
+		if p.keyID != "" {
+			return nil, fmt.Errorf("%s is not supported for KMS type [%s]. Use %s instead",
+				kmsKeyIDFlagName, p.kmsType, keyIDFlagName)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported %s: [%s]", typeFlagName, p.kmsType)
+	}
+
+	return p, nil
+}
+
+type signingKeyManager interface {
+	Get(keyID string) (interface{}, error)
+	ExportPubKeyBytes(keyID string) ([]byte, error)
+}
+
+type signingCrypto interface {
+	Sign(msg []byte, kh interface{}) ([]byte, error)
+}
+
+// signingKey holds the KMS backend and key ID that are used to sign for a specific purpose.
+type signingKey struct {
+	keyID  string
+	km     signingKeyManager
+	cr     signingCrypto
+	pubKey []byte
+}
+
+// Sign signs the given data using the key.
+func (k *signingKey) Sign(data []byte) ([]byte, error) {
+	kh, err := k.km.Get(k.keyID)
+	if err != nil {
+		return nil, fmt.Errorf("get key handle [%s]: %w", k.keyID, err)
+	}
+
+	return k.cr.Sign(data, kh)
+}
+
+// createSigningKey returns the signing key for the given purpose. If the KMS type is 'local' then
+// the given local KMS and key ID are used.
+func createSigningKey(purpose string, p *signingKeyParameters, externalKMS *externalKMSParameters,
+	httpClient *http.Client, localKM signingKeyManager, localCrypto signingCrypto,
+	localKeyID string) (*signingKey, error) {
+	key := &signingKey{}
+
+	switch p.kmsType {
+	case kmsTypeAWS:
+		var opts []aws.Option
+
+		if externalKMS.awsEndpoint != "" {
+			opts = append(opts, aws.WithEndpoint(externalKMS.awsEndpoint))
+		}
+
+		client := aws.New(externalKMS.awsRegion, externalKMS.awsCredentials, httpClient, opts...)
+
+		key.keyID, key.km, key.cr = p.keyID, client, client
+	case kmsTypeVault:
+		var opts []vault.Option
+
+		if externalKMS.vaultTransitMountPath != "" {
+			opts = append(opts, vault.WithMountPath(externalKMS.vaultTransitMountPath))
+		}
+
+		client := vault.New(externalKMS.vaultURL, externalKMS.vaultToken, httpClient, opts...)
+
+		key.keyID, key.km, key.cr = p.keyID, client, client
+	default:
+		key.keyID, key.km, key.cr = localKeyID, localKM, localCrypto
+	}
+
+	pubKey, err := key.km.ExportPubKeyBytes(key.keyID)
+	if err != nil {
+		return nil, fmt.Errorf("export %s public key [%s] from %s KMS: %w", purpose, key.keyID, p.kmsType, err)
+	}
+
+	key.pubKey = pubKey
+
+	logger.Infof("Using key [%s] from %s KMS for %s signing", key.keyID, p.kmsType, purpose)
+
+	return key, nil
+}
@@ -0,0 +1,177 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package startcmd
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSigningKeyParameters(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		httpSigKey, anchorCredKey, externalKMS, err := getSigningKeyParameters(newSigningKeysCmd(t))
+		require.NoError(t, err)
+		require.Equal(t, kmsTypeLocal, httpSigKey.kmsType)
+		require.Empty(t, httpSigKey.keyID)
+		require.Equal(t, kmsTypeLocal, anchorCredKey.kmsType)
+		require.NotNil(t, externalKMS)
+	})
+
+	t.Run("aws and vault", func(t *testing.T) {
+		require.NoError(t, os.Setenv(awsAccessKeyIDEnvKey, "AKIDEXAMPLE"))
+		require.NoError(t, os.Setenv(awsSecretAccessKeyEnvKey, "secret"))
+
+		defer func() {
+			require.NoError(t, os.Unsetenv(awsAccessKeyIDEnvKey))
+			require.NoError(t, os.Unsetenv(awsSecretAccessKeyEnvKey))
+		}()
+
+		httpSigKey, anchorCredKey, externalKMS, err := getSigningKeyParameters(newSigningKeysCmd(t,
+			"--"+httpSignatureKMSTypeFlagName, string(kmsTypeAWS),
+			"--"+httpSignatureKMSKeyIDFlagName, "alias/orb-http-sig",
+			"--"+awsKMSRegionFlagName, "us-east-1",
+			"--"+anchorCredentialKMSTypeFlagName, string(kmsTypeVault),
+			"--"+anchorCredentialKMSKeyIDFlagName, "orb-anchor-cred",
+			"--"+vaultURLFlagName, "https://vault.example.com",
+			"--"+vaultTokenFlagName, "s.token",
+		))
+		require.NoError(t, err)
+		require.Equal(t, kmsTypeAWS, httpSigKey.kmsType)
+		require.Equal(t, "alias/orb-http-sig", httpSigKey.keyID)
+		require.Equal(t, kmsTypeVault, anchorCredKey.kmsType)
+		require.Equal(t, "orb-anchor-cred", anchorCredKey.keyID)
+		require.Equal(t, "us-east-1", externalKMS.awsRegion)
+		require.Equal(t, "AKIDEXAMPLE", externalKMS.awsCredentials.AccessKeyID)
+		require.Equal(t, "secret", externalKMS.awsCredentials.SecretAccessKey)
+		require.Equal(t, "https://vault.example.com", externalKMS.vaultURL)
+		require.Equal(t, "s.token", externalKMS.vaultToken)
+	})
+
+	t.Run("invalid KMS type", func(t *testing.T) {
+		_, _, _, err := getSigningKeyParameters(newSigningKeysCmd(t,
+			"--"+httpSignatureKMSTypeFlagName, "invalid"))
+		require.EqualError(t, err, "unsupported http-signature-kms-type: [invalid]")
+	})
+
+	t.Run("missing key ID", func(t *testing.T) {
+		_, _, _, err := getSigningKeyParameters(newSigningKeysCmd(t,
+			"--"+anchorCredentialKMSTypeFlagName, string(kmsTypeVault)))
+		require.EqualError(t, err, "anchor-credential-kms-key-id is required for KMS type [vault]")
+	})
+
+	t.Run("key ID with local KMS", func(t *testing.T) {
+		_, _, _, err := getSigningKeyParameters(newSigningKeysCmd(t,
+			"--"+httpSignatureKMSKeyIDFlagName, "key1"))
+		require.EqualError(t, err,
+			"http-signature-kms-key-id is not supported for KMS type [local]. Use key-id instead")
+	})
+
+	t.Run("missing AWS region", func(t *testing.T) {
+		_, _, _, err := getSigningKeyParameters(newSigningKeysCmd(t,
+			"--"+httpSignatureKMSTypeFlagName, string(kmsTypeAWS),
+			"--"+httpSignatureKMSKeyIDFlagName, "key1"))
+		require.EqualError(t, err, "aws-kms-region is required for KMS type [aws]")
+	})
+
+	t.Run("missing Vault URL", func(t *testing.T) {
+		_, _, _, err := getSigningKeyParameters(newSigningKeysCmd(t,
+			"--"+httpSignatureKMSTypeFlagName, string(kmsTypeVault),
+			"--"+httpSignatureKMSKeyIDFlagName, "key1"))
+		require.EqualError(t, err, "vault-url is required for KMS type [vault]")
+	})
+}
+
+func TestCreateSigningKey(t *testing.T) {
+	pubKey := newPublicKey(t)
+
+	t.Run("local", func(t *testing.T) {
+		km := &mockSigningKeyManager{mockPubKeyExporter: mockPubKeyExporter{keys: map[string][]byte{"key1": pubKey}}}
+
+		key, err := createSigningKey(httpSignatureKeyPurpose, &signingKeyParameters{kmsType: kmsTypeLocal},
+			&externalKMSParameters{}, http.DefaultClient, km, &mockSigningCrypto{}, "key1")
+		require.NoError(t, err)
+		require.Equal(t, "key1", key.keyID)
+		require.Equal(t, pubKey, key.pubKey)
+		require.Equal(t, km, key.km)
+
+		signature, err := key.Sign([]byte("data"))
+		require.NoError(t, err)
+		require.Equal(t, []byte("data"), signature)
+	})
+
+	t.Run("vault", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/v1/orb-transit/keys/orb-key", r.URL.Path)
+
+			_, err := fmt.Fprintf(w, `{"data":{"type":"ed25519","latest_version":1,"keys":{"1":{"public_key":"%s"}}}}`,
+				base64.StdEncoding.EncodeToString(pubKey))
+			require.NoError(t, err)
+		}))
+		defer srv.Close()
+
+		key, err := createSigningKey(anchorCredentialKeyPurpose,
+			&signingKeyParameters{kmsType: kmsTypeVault, keyID: "orb-key"},
+			&externalKMSParameters{vaultURL: srv.URL, vaultToken: "s.token", vaultTransitMountPath: "orb-transit"},
+			http.DefaultClient, nil, nil, "")
+		require.NoError(t, err)
+		require.Equal(t, "orb-key", key.keyID)
+		require.Equal(t, pubKey, key.pubKey)
+	})
+
+	t.Run("aws export error", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		defer srv.Close()
+
+		_, err := createSigningKey(httpSignatureKeyPurpose,
+			&signingKeyParameters{kmsType: kmsTypeAWS, keyID: "alias/orb-key"},
+			&externalKMSParameters{awsRegion: "us-east-1", awsEndpoint: srv.URL},
+			http.DefaultClient, nil, nil, "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "export HTTP signature public key [alias/orb-key] from aws KMS")
+	})
+
+	t.Run("local export error", func(t *testing.T) {
+		km := &mockSigningKeyManager{}
+
+		_, err := createSigningKey(httpSignatureKeyPurpose, &signingKeyParameters{kmsType: kmsTypeLocal},
+			&externalKMSParameters{}, http.DefaultClient, km, &mockSigningCrypto{}, "key1")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "export HTTP signature public key [key1] from local KMS: key not found")
+	})
+}
+
+func newSigningKeysCmd(t *testing.T, args ...string) *cobra.Command {
+	t.Helper()
+
+	cmd := GetStartCmd()
+	require.NoError(t, cmd.ParseFlags(args))
+
+	return cmd
+}
+
+type mockSigningKeyManager struct {
+	mockPubKeyExporter
+}
+
+func (m *mockSigningKeyManager) Get(keyID string) (interface{}, error) {
+	return keyID, nil
+}
+
+type mockSigningCrypto struct{}
+
+func (m *mockSigningCrypto) Sign(msg []byte, _ interface{}) ([]byte, error) {
+	return msg, nil
+}
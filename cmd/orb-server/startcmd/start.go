@@ -16,13 +16,14 @@ import (
 	"encoding/pem"
 	"errors"
 	"fmt"
-	"net"
 	"net/http"
 	"net/url"
 	"os"
 	"os/signal"
 	"reflect"
+	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -53,6 +54,7 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/vdr"
 	vdrweb "github.com/hyperledger/aries-framework-go/pkg/vdr/web"
 	"github.com/hyperledger/aries-framework-go/spi/storage"
+	shell "github.com/ipfs/go-ipfs-api"
 	jsonld "github.com/piprate/json-gold/ld"
 	"github.com/spf13/cobra"
 	"github.com/trustbloc/edge-core/pkg/log"
@@ -67,14 +69,30 @@ import (
 	"github.com/trustbloc/sidetree-core-go/pkg/restapi/diddochandler"
 
 	"github.com/trustbloc/orb/internal/pkg/ldcontext"
+	"github.com/trustbloc/orb/pkg/activitypub/accesstoken"
+	"github.com/trustbloc/orb/pkg/activitypub/anchordigest"
+	"github.com/trustbloc/orb/pkg/activitypub/archive"
 	"github.com/trustbloc/orb/pkg/activitypub/client"
 	"github.com/trustbloc/orb/pkg/activitypub/client/transport"
+	"github.com/trustbloc/orb/pkg/activitypub/deliveryreceipt"
+	"github.com/trustbloc/orb/pkg/activitypub/evidence"
 	"github.com/trustbloc/orb/pkg/activitypub/httpsig"
+	"github.com/trustbloc/orb/pkg/activitypub/keypin"
+	"github.com/trustbloc/orb/pkg/activitypub/multikey"
+	"github.com/trustbloc/orb/pkg/activitypub/pendingdelivery"
+	"github.com/trustbloc/orb/pkg/activitypub/profile"
+	"github.com/trustbloc/orb/pkg/activitypub/quarantine"
+	"github.com/trustbloc/orb/pkg/activitypub/rejection"
 	aphandler "github.com/trustbloc/orb/pkg/activitypub/resthandler"
+	"github.com/trustbloc/orb/pkg/activitypub/search"
 	apservice "github.com/trustbloc/orb/pkg/activitypub/service"
 	"github.com/trustbloc/orb/pkg/activitypub/service/acceptlist"
+	acceptlistregistry "github.com/trustbloc/orb/pkg/activitypub/service/acceptlist/registry"
 	"github.com/trustbloc/orb/pkg/activitypub/service/activityhandler"
+	"github.com/trustbloc/orb/pkg/activitypub/service/activitysink"
 	"github.com/trustbloc/orb/pkg/activitypub/service/anchorsynctask"
+	"github.com/trustbloc/orb/pkg/activitypub/service/logallowlist"
+	logallowlisthandler "github.com/trustbloc/orb/pkg/activitypub/service/logallowlist/resthandler"
 	"github.com/trustbloc/orb/pkg/activitypub/service/monitoring"
 	apspi "github.com/trustbloc/orb/pkg/activitypub/service/spi"
 	"github.com/trustbloc/orb/pkg/activitypub/service/vct"
@@ -82,12 +100,15 @@ import (
 	apmemstore "github.com/trustbloc/orb/pkg/activitypub/store/memstore"
 	activitypubspi "github.com/trustbloc/orb/pkg/activitypub/store/spi"
 	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/activitypub/witnessexpiry"
 	"github.com/trustbloc/orb/pkg/anchor/anchorevent/vcresthandler"
 	"github.com/trustbloc/orb/pkg/anchor/builder"
+	"github.com/trustbloc/orb/pkg/anchor/explorer"
 	"github.com/trustbloc/orb/pkg/anchor/graph"
 	"github.com/trustbloc/orb/pkg/anchor/handler/acknowlegement"
 	"github.com/trustbloc/orb/pkg/anchor/handler/credential"
 	"github.com/trustbloc/orb/pkg/anchor/handler/proof"
+	"github.com/trustbloc/orb/pkg/anchor/linksetsig"
 	"github.com/trustbloc/orb/pkg/anchor/linkstore"
 	"github.com/trustbloc/orb/pkg/anchor/witness/policy"
 	"github.com/trustbloc/orb/pkg/anchor/witness/policy/inspector"
@@ -97,44 +118,91 @@ import (
 	ipfscas "github.com/trustbloc/orb/pkg/cas/ipfs"
 	"github.com/trustbloc/orb/pkg/cas/resolver"
 	"github.com/trustbloc/orb/pkg/config"
+	"github.com/trustbloc/orb/pkg/config/dynamic"
+	dynamicconfighandler "github.com/trustbloc/orb/pkg/config/dynamic/resthandler"
 	sidetreecontext "github.com/trustbloc/orb/pkg/context"
 	"github.com/trustbloc/orb/pkg/context/common"
 	"github.com/trustbloc/orb/pkg/context/opqueue"
 	orbpc "github.com/trustbloc/orb/pkg/context/protocol/client"
 	orbpcp "github.com/trustbloc/orb/pkg/context/protocol/provider"
+	"github.com/trustbloc/orb/pkg/deliverystats"
 	localdiscovery "github.com/trustbloc/orb/pkg/discovery/did/local"
 	discoveryclient "github.com/trustbloc/orb/pkg/discovery/endpoint/client"
 	discoveryrest "github.com/trustbloc/orb/pkg/discovery/endpoint/restapi"
+	"github.com/trustbloc/orb/pkg/document/composition"
+	"github.com/trustbloc/orb/pkg/document/diffhandler"
+	"github.com/trustbloc/orb/pkg/document/protocolhandler"
 	"github.com/trustbloc/orb/pkg/document/remoteresolver"
+	"github.com/trustbloc/orb/pkg/document/resolutionhandler"
 	"github.com/trustbloc/orb/pkg/document/resolvehandler"
+	"github.com/trustbloc/orb/pkg/document/snapshot"
+	"github.com/trustbloc/orb/pkg/document/uniresolver"
 	"github.com/trustbloc/orb/pkg/document/updatehandler"
 	"github.com/trustbloc/orb/pkg/document/updatehandler/decorator"
+	"github.com/trustbloc/orb/pkg/document/validatehandler"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/faultinjection"
+	faultinjectionhandler "github.com/trustbloc/orb/pkg/faultinjection/resthandler"
+	"github.com/trustbloc/orb/pkg/featureflag"
+	featureflaghandler "github.com/trustbloc/orb/pkg/featureflag/resthandler"
+	"github.com/trustbloc/orb/pkg/graphql"
+	"github.com/trustbloc/orb/pkg/graphql/orbschema"
+	"github.com/trustbloc/orb/pkg/grpcapi"
 	"github.com/trustbloc/orb/pkg/httpserver"
 	"github.com/trustbloc/orb/pkg/httpserver/auth"
 	"github.com/trustbloc/orb/pkg/httpserver/auth/signature"
+	"github.com/trustbloc/orb/pkg/httpserver/debug"
+	"github.com/trustbloc/orb/pkg/httpserver/idempotency"
+	"github.com/trustbloc/orb/pkg/httpserver/quota"
+	"github.com/trustbloc/orb/pkg/jwks"
+	"github.com/trustbloc/orb/pkg/ldcache"
+	"github.com/trustbloc/orb/pkg/leaderelection"
+	leaderhandler "github.com/trustbloc/orb/pkg/leaderelection/resthandler"
+	"github.com/trustbloc/orb/pkg/linkset"
+	"github.com/trustbloc/orb/pkg/maintenance"
+	maintenancehandler "github.com/trustbloc/orb/pkg/maintenance/resthandler"
 	"github.com/trustbloc/orb/pkg/metrics"
+	"github.com/trustbloc/orb/pkg/metrics/counterstore"
+	"github.com/trustbloc/orb/pkg/migration"
+	migrationhandler "github.com/trustbloc/orb/pkg/migration/resthandler"
 	"github.com/trustbloc/orb/pkg/nodeinfo"
 	"github.com/trustbloc/orb/pkg/observer"
+	"github.com/trustbloc/orb/pkg/observer/ipfspubsub"
+	"github.com/trustbloc/orb/pkg/observer/latency"
+	"github.com/trustbloc/orb/pkg/observer/shard"
+	"github.com/trustbloc/orb/pkg/opstatus"
 	"github.com/trustbloc/orb/pkg/protocolversion/factoryregistry"
 	"github.com/trustbloc/orb/pkg/pubsub/amqp"
 	"github.com/trustbloc/orb/pkg/pubsub/mempubsub"
+	"github.com/trustbloc/orb/pkg/pubsub/redelivery"
 	"github.com/trustbloc/orb/pkg/pubsub/spi"
 	"github.com/trustbloc/orb/pkg/resolver/resource"
 	"github.com/trustbloc/orb/pkg/resolver/resource/registry"
 	"github.com/trustbloc/orb/pkg/resolver/resource/registry/didanchorinfo"
+	"github.com/trustbloc/orb/pkg/stats"
 	anchoreventstore "github.com/trustbloc/orb/pkg/store/anchorevent"
 	"github.com/trustbloc/orb/pkg/store/anchoreventstatus"
+	"github.com/trustbloc/orb/pkg/store/anchorindex"
 	casstore "github.com/trustbloc/orb/pkg/store/cas"
 	didanchorstore "github.com/trustbloc/orb/pkg/store/didanchor"
+	"github.com/trustbloc/orb/pkg/store/dualwrite"
 	"github.com/trustbloc/orb/pkg/store/expiry"
 	opstore "github.com/trustbloc/orb/pkg/store/operation"
+	"github.com/trustbloc/orb/pkg/store/operation/suffixindex"
 	unpublishedopstore "github.com/trustbloc/orb/pkg/store/operation/unpublished"
+	"github.com/trustbloc/orb/pkg/store/processedanchor"
+	"github.com/trustbloc/orb/pkg/store/readreplica"
+	snapshotstore "github.com/trustbloc/orb/pkg/store/snapshot"
 	proofstore "github.com/trustbloc/orb/pkg/store/witness"
 	"github.com/trustbloc/orb/pkg/store/wrapper"
 	"github.com/trustbloc/orb/pkg/taskmgr"
+	taskhandler "github.com/trustbloc/orb/pkg/taskmgr/resthandler"
+	"github.com/trustbloc/orb/pkg/tenant"
 	"github.com/trustbloc/orb/pkg/vcsigner"
 	"github.com/trustbloc/orb/pkg/webcas"
 	wfclient "github.com/trustbloc/orb/pkg/webfinger/client"
+	"github.com/trustbloc/orb/pkg/webhook"
+	webhookhandler "github.com/trustbloc/orb/pkg/webhook/resthandler"
 )
 
 const (
@@ -152,10 +220,19 @@ const (
 	defaultVerifyLatestFromAnchorOrigin   = false
 	defaultLocalCASReplicateInIPFSEnabled = false
 	defaultDevModeEnabled                 = false
+	defaultProfilingEnabled               = false
 	defaultPolicyCacheExpiry              = 30 * time.Second
 	defaultCasCacheSize                   = 1000
+	defaultAnchorLatencyMaxEntries        = 50
+	defaultAnchorLatencyWindow            = time.Hour
+	defaultOperationStatusLifespan        = 24 * time.Hour
 
 	unpublishedDIDLabel = "uAAA"
+
+	// adminTokenID is the ID of the authorization token (see auth-tokens) that is required by the debug endpoints.
+	adminTokenID = "admin"
+
+	opQueueBloomFilterFalsePositiveRate = 0.01
 )
 
 var logger = log.New("orb-server")
@@ -175,6 +252,12 @@ const (
 
 	webKeyStoreKey = "web-key-store"
 	kidKey         = "kid"
+
+	// leaderElectionName is the name of the election for the instance that runs the scheduled background tasks.
+	leaderElectionName = "orb-tasks"
+
+	// observerShardGroup is the name of the group of instances among which the processing of anchors is sharded.
+	observerShardGroup = "orb-observer"
 )
 
 type pubSub interface {
@@ -219,6 +302,15 @@ func createStartCmd() *cobra.Command {
 		Short: "Start orb-server",
 		Long:  "Start orb-server",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := loadConfigFile(cmd); err != nil {
+				return err
+			}
+
+			validateOnly, err := getValidateConfig(cmd)
+			if err != nil {
+				return err
+			}
+
 			parameters, err := getOrbParameters(cmd)
 			if err != nil {
 				return err
@@ -226,6 +318,12 @@ func createStartCmd() *cobra.Command {
 
 			logger.Infof("Orb parameters: %+v", parameters)
 
+			if validateOnly {
+				logger.Infof("The configuration is valid.")
+
+				return nil
+			}
+
 			return startOrbServices(parameters)
 		},
 	}
@@ -309,7 +407,6 @@ func importPrivateKey(km kms.KeyManager, parameters *orbParameters, cfg storage.
 	}, parameters.syncTimeout)
 }
 
-// nolint: gocyclo,funlen,gocognit
 func startOrbServices(parameters *orbParameters) error {
 	if parameters.logLevel != "" {
 		setLogLevels(logger, parameters.logLevel)
@@ -320,14 +417,272 @@ func startOrbServices(parameters *orbParameters) error {
 		return err
 	}
 
+	pubSub := newPubSub(parameters)
+
+	services, err := newOrbServices(parameters, storeProviders, pubSub)
+	if err != nil {
+		return err
+	}
+
+	var handlers []restcommon.HTTPHandler
+
+	for _, svc := range services {
+		handlers = append(handlers, svc.handlers...)
+	}
+
+	httpServer := httpserver.NewWithOptions(
+		parameters.hostURL,
+		parameters.tlsParams.serveCertPath,
+		parameters.tlsParams.serveKeyPath,
+		handlers,
+		httpserver.WithCORSPolicies(parameters.corsPolicies...),
+	)
+
+	metricsHttpServer := httpserver.New(
+		parameters.hostMetricsURL, "", "",
+		metrics.NewHandler(),
+	)
+
+	err = metricsHttpServer.Start()
+	if err != nil {
+		return fmt.Errorf("start metrics HTTP server at %s: %w", parameters.hostMetricsURL, err)
+	}
+
+	var grpcServer *grpcapi.Server
+
+	if parameters.grpcHostURL != "" {
+		// Multi-tenant mode is rejected when the parameters are parsed, so there's only one service.
+		grpcServer = grpcapi.NewServer(
+			parameters.grpcHostURL,
+			parameters.tlsParams.serveCertPath,
+			parameters.tlsParams.serveKeyPath,
+			services[0].grpcHandler,
+		)
+
+		err = grpcServer.Start()
+		if err != nil {
+			return fmt.Errorf("start gRPC server at %s: %w", parameters.grpcHostURL, err)
+		}
+	}
+
+	srv := &HTTPServer{}
+
+	err = srv.Start(httpServer)
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("Stopping Orb services ...")
+
+	// Stop accepting new HTTP (and gRPC) requests and wait for in-flight requests to complete, then stop each
+	// of the services and finally close the shared publisher/subscriber.
+	steps := []shutdownStep{{name: "HTTP server", stop: httpServer.Stop}}
+
+	if grpcServer != nil {
+		steps = append(steps, shutdownStep{name: "gRPC server", stop: grpcServer.Stop})
+	}
+
+	for _, svc := range services {
+		steps = append(steps, svc.shutdownSteps...)
+	}
+
+	steps = append(steps,
+		shutdownStep{name: "publisher/subscriber", stop: func(context.Context) error { return pubSub.Close() }},
+		shutdownStep{name: "metrics HTTP server", stop: metricsHttpServer.Stop},
+	)
+
+	err = shutdown(parameters.shutdownTimeout, steps...)
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("Stopped Orb services.")
+
+	return nil
+}
+
+// orbService contains the HTTP handlers, the gRPC handler and the shutdown steps of an Orb service.
+type orbService struct {
+	handlers      []restcommon.HTTPHandler
+	grpcHandler   *grpcapi.Handler
+	shutdownSteps []shutdownStep
+}
+
+// newOrbServices creates a single Orb service or, if tenants are configured, a service for each tenant. The
+// stores and message queue topics of each tenant are namespaced by the tenant ID and the tenant's HTTP handlers
+// are only selected for requests with the tenant's host and/or base path.
+func newOrbServices(parameters *orbParameters, storeProviders *storageProviders,
+	mq pubSub) ([]*orbService, error) {
+	if len(parameters.tenants) == 0 {
+		svc, err := newOrbService(parameters, storeProviders, mq)
+		if err != nil {
+			return nil, err
+		}
+
+		return []*orbService{svc}, nil
+	}
+
+	services := make([]*orbService, len(parameters.tenants))
+
+	for i, t := range parameters.tenants {
+		logger.Infof("Creating Orb service for tenant [%s] - Host: [%s], Base path: [%s], External endpoint: [%s]",
+			t.ID, t.Host, t.BasePath, t.ExternalEndpoint)
+
+		tenantParameters, err := parameters.forTenant(t)
+		if err != nil {
+			return nil, fmt.Errorf("tenant [%s]: %w", t.ID, err)
+		}
+
+		svc, err := newOrbService(tenantParameters, storeProviders.forTenant(t.ID), tenant.NewPubSub(mq, t.ID))
+		if err != nil {
+			return nil, fmt.Errorf("create Orb service for tenant [%s]: %w", t.ID, err)
+		}
+
+		svc.handlers = tenant.WrapHandlers(svc.handlers, t)
+
+		services[i] = svc
+	}
+
+	return services, nil
+}
+
+// sharedPubSub prevents a service from closing the publisher/subscriber that's shared by all services.
+// Closing it only closes the subscriptions that were made through it, so that the components that
+// consume these subscriptions (such as the ActivityPub outbox) are able to stop.
+type sharedPubSub struct {
+	pubSub
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newSharedPubSub(ps pubSub) *sharedPubSub {
+	return &sharedPubSub{
+		pubSub: ps,
+		done:   make(chan struct{}),
+	}
+}
+
+func (p *sharedPubSub) Subscribe(ctx context.Context, topic string) (<-chan *message.Message, error) {
+	msgChan, err := p.pubSub.Subscribe(ctx, topic)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.forward(msgChan), nil
+}
+
+func (p *sharedPubSub) SubscribeWithOpts(ctx context.Context, topic string,
+	opts ...spi.Option) (<-chan *message.Message, error) {
+	msgChan, err := p.pubSub.SubscribeWithOpts(ctx, topic, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.forward(msgChan), nil
+}
+
+func (p *sharedPubSub) Close() error {
+	p.closeOnce.Do(func() {
+		close(p.done)
+	})
+
+	return nil
+}
+
+func (p *sharedPubSub) forward(msgChan <-chan *message.Message) <-chan *message.Message {
+	out := make(chan *message.Message)
+
+	go func() {
+		defer func() {
+			// The shared subscription remains open until the shared publisher/subscriber is closed, so
+			// any further messages are nacked in order for them to be redelivered.
+			for msg := range msgChan {
+				msg.Nack()
+			}
+		}()
+
+		defer close(out)
+
+		for {
+			select {
+			case <-p.done:
+				return
+			case msg, ok := <-msgChan:
+				if !ok {
+					return
+				}
+
+				select {
+				case out <- msg:
+				case <-p.done:
+					msg.Nack()
+
+					return
+				}
+			}
+		}
+	}()
+
+	return out
+}
+
+func newPubSub(parameters *orbParameters) pubSub {
+	if parameters.mqURL != "" {
+		return amqp.New(amqp.Config{
+			URI:                        parameters.mqURL,
+			MaxConnectionSubscriptions: parameters.mqMaxConnectionSubscriptions,
+			FailoverURIs:               parameters.mqFailoverURLs,
+			PublisherConfirmsEnabled:   parameters.mqPublisherConfirmsEnabled,
+			PublishConfirmTimeout:      parameters.mqPublishConfirmTimeout,
+		})
+	}
+
+	return mempubsub.New(mempubsub.DefaultConfig())
+}
+
+// newOrbService creates and starts the components of an Orb service and returns the service's HTTP handlers
+// and the steps to shut the service down. The given publisher/subscriber is not closed by the service.
+// nolint: gocyclo,funlen,gocognit
+func newOrbService(parameters *orbParameters, storeProviders *storageProviders,
+	mq pubSub) (*orbService, error) {
+	if parameters.storeMigrationsEnabled {
+		if err := applyStoreMigrations(storeProviders.provider, false); err != nil {
+			return nil, err
+		}
+	}
+
+	faultInjector, err := newFaultInjector(parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	if faultInjector != nil {
+		storeProviders = storeProviders.withFaultInjector(faultInjector)
+	}
+
 	configStore, err := storeProviders.provider.OpenStore("orb-config")
 	if err != nil {
-		return fmt.Errorf("open store: %w", err)
+		return nil, fmt.Errorf("open store: %w", err)
+	}
+
+	err = updateAcceptLists(acceptlist.NewManager(configStore), parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	logAllowList := logallowlist.NewManager(configStore)
+
+	if parameters.vctLogAllowListEnabled {
+		err = addLocalVCTLog(logAllowList, parameters.vctURL)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	rootCAs, err := tlsutils.GetCertPool(parameters.tlsParams.systemCertPool, parameters.tlsParams.caCerts)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	tlsConfig := &tls.Config{RootCAs: rootCAs, MinVersion: tls.VersionTLS12}
@@ -338,33 +693,18 @@ func startOrbServices(parameters *orbParameters) error {
 		}
 	}
 
-	httpTransport := &http.Transport{
-		TLSClientConfig: tlsConfig,
-		DialContext: (&net.Dialer{
-			Timeout:   parameters.httpDialTimeout,
-			KeepAlive: 30 * time.Second,
-		}).DialContext,
-		ForceAttemptHTTP2:     true,
-		MaxIdleConns:          2000,
-		MaxConnsPerHost:       100,
-		IdleConnTimeout:       90 * time.Second,
-		TLSHandshakeTimeout:   5 * time.Second,
-		ExpectContinueTimeout: 1 * time.Second,
-	}
+	httpClient := newHTTPClient(parameters, tlsConfig)
 
-	httpClient := &http.Client{
-		Timeout:   parameters.httpTimeout,
-		Transport: httpTransport,
-	}
+	httpDestinations := newHTTPDestinations(parameters.httpDestinations)
 
-	km, cr, err := createKMSAndCrypto(parameters, httpClient, storeProviders.kmsSecretsProvider, configStore)
+	kmsHTTPClient := httpDestinations.kms.Client(httpClient.Transport)
+	ipfsHTTPClient := httpDestinations.cas.Client(httpClient.Transport)
+
+	km, cr, err := createKMSAndCrypto(parameters, kmsHTTPClient, storeProviders.kmsSecretsProvider, configStore)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// TODO: If we decide to offer deactivate and recover we should configure this
-	parameters.updateDocumentStoreTypes = []operation.Type{operation.TypeUpdate}
-
 	casIRI := mustParseURL(parameters.externalEndpoint, casPath)
 
 	var coreCASClient extendedcasclient.Client
@@ -372,8 +712,8 @@ func startOrbServices(parameters *orbParameters) error {
 	switch {
 	case strings.EqualFold(parameters.casType, "ipfs"):
 		logger.Infof("Initializing Orb CAS with IPFS.")
-		coreCASClient = ipfscas.New(parameters.ipfsURL, parameters.ipfsTimeout, defaultCasCacheSize, metrics.Get(),
-			extendedcasclient.WithCIDVersion(parameters.cidVersion))
+		coreCASClient = ipfscas.NewWithHTTPClient(parameters.ipfsURL, ipfsHTTPClient, defaultCasCacheSize,
+			metrics.Get(), extendedcasclient.WithCIDVersion(parameters.cidVersion))
 	case strings.EqualFold(parameters.casType, "local"):
 		logger.Infof("Initializing Orb CAS with local storage provider.")
 
@@ -383,44 +723,71 @@ func startOrbServices(parameters *orbParameters) error {
 			logger.Infof("Local CAS writes will be replicated in IPFS.")
 
 			coreCASClient, err = casstore.New(storeProviders.provider, casIRI.String(),
-				ipfscas.New(parameters.ipfsURL, parameters.ipfsTimeout, defaultCasCacheSize, metrics.Get(),
+				ipfscas.NewWithHTTPClient(parameters.ipfsURL, ipfsHTTPClient, defaultCasCacheSize, metrics.Get(),
 					extendedcasclient.WithCIDVersion(parameters.cidVersion)),
 				metrics.Get(), defaultCasCacheSize, extendedcasclient.WithCIDVersion(parameters.cidVersion))
 			if err != nil {
-				return err
+				return nil, err
 			}
 		} else {
 			coreCASClient, err = casstore.New(storeProviders.provider, casIRI.String(), nil,
 				metrics.Get(), defaultCasCacheSize, extendedcasclient.WithCIDVersion(parameters.cidVersion))
 			if err != nil {
-				return err
+				return nil, err
 			}
 		}
 
 	default:
-		return fmt.Errorf("%s is not a valid CAS type. It must be either local or ipfs", parameters.casType)
+		return nil, fmt.Errorf("%s is not a valid CAS type. It must be either local or ipfs", parameters.casType)
+	}
+
+	if faultInjector != nil {
+		coreCASClient = faultinjection.NewCASClient(coreCASClient, faultInjector)
 	}
 
 	didAnchors, err := didanchorstore.New(storeProviders.provider)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	opStore, err := opstore.New(storeProviders.provider, metrics.Get())
+	persistentOpStore, err := opstore.New(storeProviders.provider, metrics.Get())
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	var (
+		opStore     common.OperationStore = persistentOpStore
+		suffixIndex *suffixindex.Index
+	)
+
+	if parameters.didSuffixIndexSize > 0 {
+		suffixIndex, err = suffixindex.New(storeProviders.provider, persistentOpStore, parameters.didSuffixIndexSize,
+			suffixindex.WithSyncInterval(parameters.didSuffixIndexSyncInterval))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create DID suffix index: %w", err)
+		}
+
+		// Unknown DIDs are rejected by the index without querying the operation store.
+		opStore = suffixindex.NewOperationStore(persistentOpStore, suffixIndex)
+	}
+
+	statsAggregator, err := stats.NewAggregator(storeProviders.provider)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stats aggregator: %w", err)
 	}
 
+	anchorLatency := latency.New(defaultAnchorLatencyMaxEntries, defaultAnchorLatencyWindow)
+
 	ldStorageProvider := cachedstore.NewProvider(storeProviders.provider, ariesmemstorage.NewProvider())
 
 	contextStore, err := ldstore.NewContextStore(ldStorageProvider)
 	if err != nil {
-		return fmt.Errorf("create JSON-LD context store: %w", err)
+		return nil, fmt.Errorf("create JSON-LD context store: %w", err)
 	}
 
 	remoteProviderStore, err := ldstore.NewRemoteProviderStore(ldStorageProvider)
 	if err != nil {
-		return fmt.Errorf("create remote provider store: %w", err)
+		return nil, fmt.Errorf("create remote provider store: %w", err)
 	}
 
 	ldStore := &ldStoreProvider{
@@ -428,9 +795,19 @@ func startOrbServices(parameters *orbParameters) error {
 		RemoteProviderStore: remoteProviderStore,
 	}
 
-	orbDocumentLoader, err := createJSONLDDocumentLoader(ldStore, httpClient, parameters.contextProviderURLs)
+	ldLoader, err := createJSONLDDocumentLoader(ldStore, httpClient, parameters.contextProviderURLs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load Orb contexts: %s", err.Error())
+	}
+
+	// Contexts are loaded from the persistent context cache so that signature verification doesn't depend on
+	// third-party context hosts at runtime.
+	orbDocumentLoader, err := ldcache.New(
+		ldcache.Config{FetchRemote: parameters.jsonldRemoteContextFetchEnabled},
+		ldLoader, contextStore, storeProviders.provider, httpClient,
+	)
 	if err != nil {
-		return fmt.Errorf("failed to load Orb contexts: %s", err.Error())
+		return nil, fmt.Errorf("failed to create JSON-LD context cache: %w", err)
 	}
 
 	useHTTPOpt := false
@@ -447,16 +824,28 @@ func startOrbServices(parameters *orbParameters) error {
 
 	if parameters.keyID == "" {
 		if err = createKID(km, parameters, configStore); err != nil {
-			return fmt.Errorf("create kid: %w", err)
+			return nil, fmt.Errorf("create kid: %w", err)
 		}
 	}
 
 	if parameters.keyID != "" && parameters.privateKeyBase64 != "" {
 		if err = importPrivateKey(km, parameters, configStore); err != nil {
-			return fmt.Errorf("import kid: %w", err)
+			return nil, fmt.Errorf("import kid: %w", err)
 		}
 	}
 
+	httpSignatureKey, err := createSigningKey(httpSignatureKeyPurpose, parameters.httpSignatureKey,
+		parameters.externalKMS, kmsHTTPClient, km, cr, parameters.keyID)
+	if err != nil {
+		return nil, fmt.Errorf("create HTTP signature key: %w", err)
+	}
+
+	anchorCredentialKey, err := createSigningKey(anchorCredentialKeyPurpose, parameters.anchorCredentialKey,
+		parameters.externalKMS, kmsHTTPClient, km, cr, parameters.keyID)
+	if err != nil {
+		return nil, fmt.Errorf("create anchor credential key: %w", err)
+	}
+
 	apServicePublicKeyIRI := mustParseURL(parameters.externalEndpoint,
 		fmt.Sprintf("%s/keys/%s", activityPubServicesPath, aphandler.MainKeyID))
 
@@ -466,7 +855,7 @@ func startOrbServices(parameters *orbParameters) error {
 		AuthTokens:    parameters.authTokens,
 	})
 	if err != nil {
-		return fmt.Errorf("create server Token Manager: %w", err)
+		return nil, fmt.Errorf("create server Token Manager: %w", err)
 	}
 
 	// clientTokenManager is used by the HTTP transport to determine whether an outbound
@@ -476,21 +865,36 @@ func startOrbServices(parameters *orbParameters) error {
 		AuthTokens:    parameters.clientAuthTokens,
 	})
 	if err != nil {
-		return fmt.Errorf("create client Token Manager: %w", err)
+		return nil, fmt.Errorf("create client Token Manager: %w", err)
 	}
 
-	apGetSigner, apPostSigner := getActivityPubSigners(parameters, km, cr)
+	apGetSigner, apPostSigner, apKeyIDSetters := getActivityPubSigners(parameters, httpSignatureKey)
 
-	t := transport.New(httpClient, apServicePublicKeyIRI, apGetSigner, apPostSigner, clientTokenManager)
+	// Requests to other Orb domains use a client with circuit breakers so that an unresponsive domain
+	// doesn't hold up the workers.
+	federationHTTPClient := newFederationHTTPClient(parameters, httpClient, faultInjector)
+
+	t := transport.New(httpDestinations.activityPub.Client(federationHTTPClient.Transport),
+		apServicePublicKeyIRI, apGetSigner, apPostSigner, clientTokenManager)
+
+	apClient := client.New(client.Config{
+		CacheSize:       parameters.apClientCacheSize,
+		CacheExpiration: parameters.apClientCacheExpiration,
+	}, t)
 
 	wfClient := wfclient.New(wfclient.WithHTTPClient(httpClient))
 
-	webCASResolver := resolver.NewWebCASResolver(t, wfClient, webFingerURIScheme)
+	webCASTransport := transport.New(httpDestinations.cas.Client(federationHTTPClient.Transport),
+		apServicePublicKeyIRI, apGetSigner, apPostSigner, clientTokenManager)
+
+	webCASResolver := resolver.NewWebCASResolver(webCASTransport, wfClient, webFingerURIScheme,
+		resolver.WithLinksetSignatureVerifier(linksetsig.NewVerifier(apClient)),
+		resolver.WithLinksetSignatureRequired(parameters.linksetSignatureRequired))
 
 	var ipfsReader *ipfscas.Client
 	var casResolver *resolver.Resolver
 	if parameters.ipfsURL != "" {
-		ipfsReader = ipfscas.New(parameters.ipfsURL, parameters.ipfsTimeout, defaultCasCacheSize, metrics.Get(),
+		ipfsReader = ipfscas.NewWithHTTPClient(parameters.ipfsURL, ipfsHTTPClient, defaultCasCacheSize, metrics.Get(),
 			extendedcasclient.WithCIDVersion(parameters.cidVersion))
 		casResolver = resolver.New(coreCASClient, ipfsReader, webCASResolver, metrics.Get())
 	} else {
@@ -503,53 +907,135 @@ func startOrbServices(parameters *orbParameters) error {
 		DocLoader:   orbDocumentLoader,
 	}
 
-	anchorGraph := graph.New(graphProviders)
+	var graphOpts []graph.Option
+
+	var linksetSigner *linksetsig.Signer
+
+	if parameters.linksetSigningEnabled {
+		linksetSigner, err = linksetsig.NewSigner(storeProviders.provider, httpSignatureKey.km, httpSignatureKey.cr,
+			httpSignatureKey.keyID, apServicePublicKeyIRI)
+		if err != nil {
+			return nil, fmt.Errorf("create linkset signer: %w", err)
+		}
+
+		graphOpts = append(graphOpts, graph.WithSigner(linksetSigner))
+	}
+
+	anchorGraph := graph.New(graphProviders, graphOpts...)
+
+	var taskMgrOpts []taskmgr.Opt
+
+	var leaderElector *leaderelection.Elector
+
+	stopLeaderElection := func() {}
+
+	if parameters.leaderElectionEnabled {
+		instanceID := uuid.New().String()
+
+		leaderElector = leaderelection.New(leaderElectionName, instanceID, configStore,
+			leaderelection.WithLeaseDuration(parameters.leaderLeaseDuration))
+
+		stopLeaderElection = leaderElector.Stop
+
+		taskMgrOpts = append(taskMgrOpts, taskmgr.WithInstanceID(instanceID), taskmgr.WithLeaderElector(leaderElector))
+	}
+
+	for taskID, schedule := range parameters.taskSchedules {
+		taskMgrOpts = append(taskMgrOpts, taskmgr.WithSchedule(taskID, schedule))
+	}
 
-	taskMgr := taskmgr.New(configStore, parameters.taskMgrCheckInterval)
+	taskMgr := taskmgr.New(configStore, parameters.taskMgrCheckInterval, taskMgrOpts...)
 
 	expiryService := expiry.NewService(taskMgr, parameters.dataExpiryCheckInterval)
 
+	if storeProviders.dualWriteVerifier != nil {
+		taskMgr.RegisterTask("dual-write-verifier", parameters.legacyDatabaseVerifyInterval,
+			storeProviders.dualWriteVerifier.Run)
+	}
+
+	if parameters.acceptListRegistry.url != "" {
+		registryImporter, e := acceptlistregistry.New(
+			acceptlistregistry.Config{
+				URL:       parameters.acceptListRegistry.url,
+				PublicKey: parameters.acceptListRegistry.publicKey,
+				Interval:  parameters.acceptListRegistry.interval,
+			},
+			acceptlist.NewManager(configStore), configStore, httpClient,
+		)
+		if e != nil {
+			return nil, fmt.Errorf("failed to create accept list registry importer: %w", e)
+		}
+
+		registryImporter.Register(taskMgr)
+	}
+
+	var deliveryStats *deliverystats.Collector
+
+	if parameters.deliveryAnalyticsEnabled {
+		deliveryStats, err = deliverystats.NewCollector(storeProviders.provider, expiryService, metrics.Get())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create delivery stats collector: %w", err)
+		}
+	}
+
+	var metricsCounterPersister *counterstore.Persister
+
+	if parameters.persistentMetricsEnabled {
+		metricsCounterPersister, err = counterstore.NewPersister(storeProviders.provider, metrics.Get(),
+			parameters.persistentMetricsInstance)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create metrics counter persister: %w", err)
+		}
+	}
+
 	var updateDocumentStore *unpublishedopstore.Store
 	if parameters.updateDocumentStoreEnabled {
 		updateDocumentStore, err = unpublishedopstore.New(storeProviders.provider,
 			parameters.unpublishedOperationLifespan, expiryService, metrics.Get())
 		if err != nil {
-			return fmt.Errorf("failed to create unpublished document store: %w", err)
+			return nil, fmt.Errorf("failed to create unpublished document store: %w", err)
 		}
 	}
 
+	// The operations that can't be applied to a document are recorded for review by an operator.
+	compositionErrors := composition.NewRecorder(0)
+
 	// get protocol client provider
-	pcp, err := getProtocolClientProvider(parameters, coreCASClient, casResolver, opStore, storeProviders.provider, updateDocumentStore)
+	// The operations that are stored by the observer are counted by the stats aggregator.
+	pcp, err := getProtocolClientProvider(parameters, coreCASClient, casResolver,
+		stats.NewOperationStore(opStore, statsAggregator), storeProviders.provider, updateDocumentStore,
+		compositionErrors)
 	if err != nil {
-		return fmt.Errorf("failed to create protocol client provider: %s", err.Error())
+		return nil, fmt.Errorf("failed to create protocol client provider: %s", err.Error())
 	}
 
 	pc, err := pcp.ForNamespace(parameters.didNamespace)
 	if err != nil {
-		return fmt.Errorf("failed to get protocol client for namespace [%s]: %s", parameters.didNamespace, err.Error())
+		return nil, fmt.Errorf("failed to get protocol client for namespace [%s]: %s",
+			parameters.didNamespace, err.Error())
 	}
 
 	u, err := url.Parse(parameters.externalEndpoint)
 	if err != nil {
-		return fmt.Errorf("parse external endpoint: %w", err)
+		return nil, fmt.Errorf("parse external endpoint: %w", err)
 	}
 
 	signingParams := vcsigner.SigningParams{
-		VerificationMethod: "did:web:" + u.Host + "#" + parameters.keyID,
+		VerificationMethod: "did:web:" + u.Host + "#" + anchorCredentialKey.keyID,
 		Domain:             parameters.anchorCredentialParams.domain,
 		SignatureSuite:     parameters.anchorCredentialParams.signatureSuite,
 	}
 
 	signingProviders := &vcsigner.Providers{
-		KeyManager: km,
-		Crypto:     cr,
+		KeyManager: anchorCredentialKey.km,
+		Crypto:     anchorCredentialKey.cr,
 		DocLoader:  orbDocumentLoader,
 		Metrics:    metrics.Get(),
 	}
 
 	vcSigner, err := vcsigner.New(signingProviders, signingParams)
 	if err != nil {
-		return fmt.Errorf("failed to create vc signer: %s", err.Error())
+		return nil, fmt.Errorf("failed to create vc signer: %s", err.Error())
 	}
 
 	vcBuilderParams := builder.Params{
@@ -559,17 +1045,17 @@ func startOrbServices(parameters *orbParameters) error {
 
 	vcBuilder, err := builder.New(vcBuilderParams)
 	if err != nil {
-		return fmt.Errorf("failed to create vc builder: %s", err.Error())
+		return nil, fmt.Errorf("failed to create vc builder: %s", err.Error())
 	}
 
 	anchorEventStore, err := anchoreventstore.New(storeProviders.provider, orbDocumentLoader)
 	if err != nil {
-		return fmt.Errorf("failed to create anchor event store: %s", err.Error())
+		return nil, fmt.Errorf("failed to create anchor event store: %s", err.Error())
 	}
 
 	witnessProofStore, err := proofstore.New(storeProviders.provider, expiryService, parameters.maxWitnessDelay)
 	if err != nil {
-		return fmt.Errorf("failed to create proof store: %s", err.Error())
+		return nil, fmt.Errorf("failed to create proof store: %s", err.Error())
 	}
 
 	var processorOpts []processor.Option
@@ -577,7 +1063,36 @@ func startOrbServices(parameters *orbParameters) error {
 		processorOpts = append(processorOpts, processor.WithUnpublishedOperationStore(updateDocumentStore))
 	}
 
-	opProcessor := processor.New(parameters.didNamespace, opStore, pc, processorOpts...)
+	var opProcessor operationProcessor = processor.New(parameters.didNamespace, opStore, pc, processorOpts...)
+
+	var (
+		didSnapshotStore *snapshotstore.Store
+		didSnapshotter   *snapshot.Snapshotter
+	)
+
+	if parameters.didSnapshotInterval > 0 {
+		didSnapshotStore, err = snapshotstore.New(storeProviders.provider)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create DID snapshot store: %w", err)
+		}
+
+		var snapshotOpts []snapshot.Option
+		if parameters.updateDocumentStoreEnabled {
+			snapshotOpts = append(snapshotOpts, snapshot.WithUnpublishedOperationStore(updateDocumentStore))
+		}
+
+		// DIDs are resolved from the latest snapshot (if valid) so that only the operations after the
+		// snapshot are applied.
+		opProcessor = snapshot.NewProcessor(opProcessor, opStore, pc, didSnapshotStore, snapshotOpts...)
+
+		didSnapshotter = snapshot.NewSnapshotter(
+			snapshot.Config{
+				Namespace: parameters.didNamespace,
+				Interval:  int(parameters.didSnapshotInterval),
+			},
+			opStore, pc, didSnapshotStore,
+		)
+	}
 
 	didAnchoringInfoProvider := didanchorinfo.New(parameters.didNamespace, didAnchors, opProcessor)
 
@@ -587,57 +1102,119 @@ func startOrbServices(parameters *orbParameters) error {
 
 	apServiceIRI := mustParseURL(parameters.externalEndpoint, activityPubServicesPath)
 
-	var pubSub pubSub
+	// Queue consumers are paused while the node is in maintenance mode.
+	maintenanceMode := maintenance.New(parameters.maintenanceRetryAfter)
 
-	if parameters.mqURL != "" {
-		pubSub = amqp.New(amqp.Config{
-			URI:                        parameters.mqURL,
-			MaxConnectionSubscriptions: parameters.mqMaxConnectionSubscriptions,
-		})
-	} else {
-		pubSub = mempubsub.New(mempubsub.DefaultConfig())
+	pubSub := maintenance.NewPubSub(newSharedPubSub(mq), maintenanceMode)
+
+	apSignatureStore, err := archive.NewSignatureStore(storeProviders.provider)
+	if err != nil {
+		return nil, fmt.Errorf("create activity signature store: %w", err)
 	}
 
 	apConfig := &apservice.Config{
-		ServiceEndpoint:        activityPubServicesPath,
-		ServiceIRI:             apServiceIRI,
-		VerifyActorInSignature: parameters.httpSignaturesEnabled,
-		MaxWitnessDelay:        parameters.maxWitnessDelay,
-		IRICacheSize:           parameters.apIRICacheSize,
-		IRICacheExpiration:     parameters.apIRICacheExpiration,
+		ServiceEndpoint:         activityPubServicesPath,
+		ServiceIRI:              apServiceIRI,
+		VerifyActorInSignature:  parameters.httpSignaturesEnabled,
+		RetryOpts:               redelivery.DefaultConfig(),
+		MaxWitnessDelay:         parameters.maxWitnessDelay,
+		WitnessProofBatchWindow: parameters.witnessProofBatchWindow,
+		WitnessProofBatchSize:   parameters.witnessProofBatchSize,
+		WitnessProofCacheSize:   parameters.witnessProofCacheSize,
+		IRICacheSize:            parameters.apIRICacheSize,
+		IRICacheExpiration:      parameters.apIRICacheExpiration,
+		SignatureStore:          apSignatureStore,
+		InboxWorkers:            parameters.inboxWorkers,
+		InboxQueueSize:          parameters.inboxQueueSize,
+		InboxSenderPolicy:       parameters.inboxSenderPolicy,
+	}
+
+	var apQuarantine *quarantine.Store
+
+	if parameters.inboxQuarantineEnabled {
+		apQuarantine, err = quarantine.NewStore(storeProviders.provider, expiryService)
+		if err != nil {
+			return nil, fmt.Errorf("create activity quarantine store: %w", err)
+		}
+
+		apConfig.Quarantine = apQuarantine
 	}
 
-	apStore, err := createActivityPubStore(storeProviders.provider, apConfig.ServiceEndpoint)
-	if err != nil {
-		return err
+	var apEvidence *evidence.Store
+
+	if parameters.inboxEvidenceRetention > 0 {
+		apEvidence, err = evidence.NewStore(storeProviders.provider, expiryService, parameters.inboxEvidenceRetention)
+		if err != nil {
+			return nil, fmt.Errorf("create inbox evidence store: %w", err)
+		}
+
+		apConfig.InboxEvidence = apEvidence
 	}
 
-	pubKey, err := km.ExportPubKeyBytes(parameters.keyID)
+	apRejections := rejection.NewRecorder(parameters.inboxRejectionHistorySize, metrics.Get())
+
+	apConfig.InboxRejections = apRejections
+
+	apStore, err := createActivityPubStore(storeProviders.provider, apConfig.ServiceEndpoint)
 	if err != nil {
-		return fmt.Errorf("failed to export pub key: %w", err)
+		return nil, err
+	}
+
+	apStore = stats.NewActivityStore(apStore, statsAggregator)
+
+	var activitySearchIndex *search.Index
+
+	if parameters.activitySearchEnabled {
+		activitySearchIndex, err = search.NewIndex(storeProviders.provider)
+		if err != nil {
+			return nil, fmt.Errorf("create activity search index: %w", err)
+		}
+
+		apStore = search.NewActivityStore(apStore, activitySearchIndex)
 	}
 
-	publicKey, err := getActivityPubPublicKey(pubKey, apServiceIRI, apServicePublicKeyIRI)
+	publicKey, err := getActivityPubPublicKey(httpSignatureKey.pubKey, apServiceIRI, apServicePublicKeyIRI)
 	if err != nil {
-		return fmt.Errorf("get public key: %w", err)
+		return nil, fmt.Errorf("get public key: %w", err)
 	}
 
-	apClient := client.New(client.Config{
-		CacheSize:       parameters.apClientCacheSize,
-		CacheExpiration: parameters.apClientCacheExpiration,
-	}, t)
+	var apActorRetriever actorRetriever = apClient
+
+	var actorKeyPins *keypin.Manager
+
+	if parameters.actorKeyPinning != noKeyPinning {
+		actorKeyPins, err = keypin.New(
+			keypin.Config{
+				ServiceIRI:      apServiceIRI,
+				RequireApproval: parameters.actorKeyPinning == approveKeyPinning,
+			},
+			apClient, apStore, storeProviders.provider, metrics.Get(),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("create actor key pinning manager: %w", err)
+		}
+
+		apActorRetriever = actorKeyPins
+	}
 
-	apSigVerifier := getActivityPubVerifier(parameters, km, cr, apClient)
+	apSigVerifier := getActivityPubVerifier(parameters, km, cr, apActorRetriever)
+
+	var monitoringOpts []monitoring.Option
+
+	if parameters.vctLogAllowListEnabled {
+		monitoringOpts = append(monitoringOpts, monitoring.WithLogAllowList(logAllowList))
+	}
 
 	monitoringSvc, err := monitoring.New(storeProviders.provider, orbDocumentLoader, wfClient,
-		httpClient, taskMgr, parameters.vctMonitoringInterval)
+		httpDestinations.vct.Client(httpClient.Transport), taskMgr, parameters.vctMonitoringInterval,
+		monitoringOpts...)
 	if err != nil {
-		return fmt.Errorf("new VCT monitoring service: %w", err)
+		return nil, fmt.Errorf("new VCT monitoring service: %w", err)
 	}
 
 	witnessPolicy, err := policy.New(configStore, defaultPolicyCacheExpiry)
 	if err != nil {
-		return fmt.Errorf("failed to create witness policy: %s", err.Error())
+		return nil, fmt.Errorf("failed to create witness policy: %s", err.Error())
 	}
 
 	var activityPubService *apservice.Service
@@ -651,14 +1228,14 @@ func startOrbServices(parameters *orbParameters) error {
 
 	policyInspector, err := inspector.New(witnessPolicyInspectorProviders, parameters.maxWitnessDelay)
 	if err != nil {
-		return fmt.Errorf("failed to create witness policy inspector: %s", err.Error())
+		return nil, fmt.Errorf("failed to create witness policy inspector: %s", err.Error())
 	}
 
 	anchorEventStatusStore, err := anchoreventstatus.New(storeProviders.provider, expiryService,
 		parameters.maxWitnessDelay, anchoreventstatus.WithPolicyHandler(policyInspector),
 		anchoreventstatus.WithCheckStatusAfterTime(parameters.anchorStatusInProcessGracePeriod))
 	if err != nil {
-		return fmt.Errorf("failed to create vc status store: %s", err.Error())
+		return nil, fmt.Errorf("failed to create vc status store: %s", err.Error())
 	}
 
 	taskMgr.RegisterTask("anchor-status-monitor", parameters.anchorStatusMonitoringInterval, anchorEventStatusStore.CheckInProcessAnchors)
@@ -676,7 +1253,7 @@ func startOrbServices(parameters *orbParameters) error {
 		pubSub)
 
 	witness := vct.New(parameters.vctURL, vcSigner, metrics.Get(),
-		vct.WithHTTPClient(httpClient),
+		vct.WithHTTPClient(httpDestinations.vct.Client(federationHTTPClient.Transport)),
 		vct.WithDocumentLoader(orbDocumentLoader),
 	)
 
@@ -684,7 +1261,12 @@ func startOrbServices(parameters *orbParameters) error {
 
 	anchorLinkStore, err := linkstore.New(storeProviders.provider)
 	if err != nil {
-		return fmt.Errorf("open store: %w", err)
+		return nil, fmt.Errorf("open store: %w", err)
+	}
+
+	processedAnchors, err := processedanchor.New(storeProviders.provider)
+	if err != nil {
+		return nil, fmt.Errorf("open processed anchor store: %w", err)
 	}
 
 	// create new observer and start it
@@ -702,12 +1284,92 @@ func startOrbServices(parameters *orbParameters) error {
 		AnchorLinkStore:        anchorLinkStore,
 	}
 
-	o, err := observer.New(apConfig.ServiceIRI, providers,
+	observerOpts := []observer.Option{
 		observer.WithDiscoveryDomain(parameters.discoveryDomain),
 		observer.WithSubscriberPoolSize(parameters.observerQueuePoolSize),
+		observer.WithAnchorProcessedListener(statsAggregator),
+		observer.WithAnchorTimingsListener(anchorLatency),
+		observer.WithProcessedAnchorStore(processedAnchors),
+	}
+
+	stopObserverSharding := func() {}
+
+	if parameters.observerShardingEnabled {
+		var shardMgr *shard.Manager
+
+		shardMgr, err = shard.New(observerShardGroup, parameters.observerShardID, storeProviders.provider,
+			shard.WithHeartbeatInterval(parameters.observerShardHeartbeatInterval))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create observer shard manager: %w", err)
+		}
+
+		// Join the shard group before the observer starts consuming anchors.
+		shardMgr.Start()
+
+		stopObserverSharding = shardMgr.Stop
+
+		observerOpts = append(observerOpts, observer.WithShardRouter(shardMgr))
+	}
+
+	var anchorIndex *anchorindex.Store
+
+	if parameters.anchorExplorerEnabled {
+		anchorIndex, err = anchorindex.New(storeProviders.provider)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create anchor index store: %w", err)
+		}
+
+		observerOpts = append(observerOpts, observer.WithAnchorProcessedListener(anchorIndex))
+	}
+
+	var migrationTracker *migration.Tracker
+
+	if parameters.migrationEnabled {
+		// The importer waits for the imported operations to be processed by the observer.
+		migrationTracker = migration.NewTracker()
+
+		observerOpts = append(observerOpts, observer.WithAnchorProcessedListener(migrationTracker))
+	}
+
+	var (
+		webhookStore    *webhook.Store
+		webhookNotifier *webhook.Notifier
+	)
+
+	if parameters.webhooksEnabled {
+		webhookStore, err = webhook.NewStore(storeProviders.provider, expiryService)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create webhook store: %w", err)
+		}
+
+		webhookNotifier = webhook.NewNotifier(webhookStore, httpClient)
+
+		observerOpts = append(observerOpts, observer.WithAnchorProcessedListener(webhookNotifier))
+	}
+
+	if didSnapshotter != nil {
+		observerOpts = append(observerOpts, observer.WithAnchorProcessedListener(didSnapshotter))
+	}
+
+	var (
+		opStatusStore   *opstatus.Store
+		opStatusTracker *opstatus.Tracker
 	)
+
+	if parameters.operationStatusEnabled {
+		opStatusStore, err = opstatus.NewStore(storeProviders.provider, expiryService, defaultOperationStatusLifespan)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create operation status store: %w", err)
+		}
+
+		opStatusTracker = opstatus.NewTracker(opStatusStore)
+
+		observerOpts = append(observerOpts, observer.WithAnchorProcessedListener(opStatusTracker))
+	}
+
+	o, err := observer.New(apConfig.ServiceIRI, providers, observerOpts...)
 	if err != nil {
-		return fmt.Errorf("failed to create observer: %w", err)
+		return nil, fmt.Errorf("failed to create observer: %w", err)
 	}
 
 	anchorEventHandler := acknowlegement.New(anchorLinkStore)
@@ -723,11 +1385,41 @@ func startOrbServices(parameters *orbParameters) error {
 		},
 	)
 	if err != nil {
-		return fmt.Errorf("failed to register anchor sync task: %w", err)
+		return nil, fmt.Errorf("failed to register anchor sync task: %w", err)
 	}
 
-	activityPubService, err = apservice.New(apConfig,
-		apStore, t, apSigVerifier, pubSub, apClient, resourceResolver, authTokenManager, metrics.Get(),
+	err = anchorsynctask.RegisterReconcile(
+		anchorsynctask.ReconcileConfig{
+			ServiceIRI: apServiceIRI,
+			Interval:   parameters.anchorReconcileInterval,
+			Days:       parameters.anchorReconcileDays,
+		},
+		taskMgr, anchordigest.NewClient(t), apStore, storeProviders.provider,
+		func() apspi.InboxHandler {
+			return activityPubService.InboxHandler()
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register anchor reconcile task: %w", err)
+	}
+
+	if parameters.activitySink.webhookURL != "" {
+		err = activitysink.Register(
+			activitysink.Config{
+				ServiceIRI: apServiceIRI,
+				Interval:   parameters.activitySink.interval,
+				BatchSize:  parameters.activitySink.batchSize,
+			},
+			taskMgr,
+			activitysink.NewWebhookPublisher(parameters.activitySink.webhookURL, httpClient, parameters.httpTimeout),
+			apStore, storeProviders.provider,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to register activity sink task: %w", err)
+		}
+	}
+
+	apHandlerOpts := []apspi.HandlerOpt{
 		apspi.WithProofHandler(proofHandler),
 		apspi.WithWitness(witness),
 		apspi.WithAnchorEventHandler(credential.New(
@@ -738,16 +1430,76 @@ func startOrbServices(parameters *orbParameters) error {
 		apspi.WithAnchorEventAcknowledgementHandler(anchorEventHandler),
 		// TODO: Define the following ActivityPub handlers.
 		// apspi.WithUndeliverableHandler(undeliverableHandler),
+	}
+
+	if deliveryStats != nil {
+		apHandlerOpts = append(apHandlerOpts, apspi.WithDeliveryListener(deliveryStats))
+	}
+
+	var deliveryReceipts *deliveryreceipt.Store
+
+	if parameters.deliveryReceiptRetention > 0 {
+		deliveryReceipts, err = deliveryreceipt.NewStore(storeProviders.provider, expiryService,
+			parameters.deliveryReceiptRetention)
+		if err != nil {
+			return nil, fmt.Errorf("create delivery receipt store: %w", err)
+		}
+
+		apHandlerOpts = append(apHandlerOpts, apspi.WithDeliveryReceipts(deliveryReceipts))
+	}
+
+	if parameters.pendingDeliveryRetention > 0 {
+		pendingDeliveries, e := pendingdelivery.NewStore(storeProviders.provider, expiryService,
+			parameters.pendingDeliveryRetention)
+		if e != nil {
+			return nil, fmt.Errorf("create pending delivery store: %w", e)
+		}
+
+		apHandlerOpts = append(apHandlerOpts, apspi.WithPendingDeliveries(pendingDeliveries))
+	}
+
+	var witnessExpiry *witnessexpiry.Manager
+
+	if parameters.witnessExpiration > 0 {
+		witnessExpiry, err = witnessexpiry.New(
+			witnessexpiry.Config{
+				ServiceIRI:    apServiceIRI,
+				Expiration:    parameters.witnessExpiration,
+				RenewalWindow: parameters.witnessRenewalWindow,
+			},
+			apStore, storeProviders.provider,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("create witness expiry manager: %w", err)
+		}
+
+		apHandlerOpts = append(apHandlerOpts, apspi.WithWitnessConfirmationListener(witnessExpiry))
+	}
+
+	if parameters.inviteWitnessReciprocation != noReciprocation {
+		apHandlerOpts = append(apHandlerOpts, apspi.WithWitnessReciprocation(&apspi.WitnessReciprocationPolicy{
+			InviteWitness: true,
+			Follow:        parameters.inviteWitnessReciprocation == inviteWitnessAndFollowReciprocation,
+		}))
+	}
+
+	activityPubService, err = apservice.New(apConfig,
+		apStore, t, apSigVerifier, pubSub, apClient, resourceResolver, authTokenManager, metrics.Get(),
+		apHandlerOpts...,
 	)
 	if err != nil {
-		return fmt.Errorf("failed to create ActivityPub service: %s", err.Error())
+		return nil, fmt.Errorf("failed to create ActivityPub service: %s", err.Error())
+	}
+
+	if witnessExpiry != nil {
+		witnessExpiry.Register(taskMgr, activityPubService.Outbox())
 	}
 
 	o.Start()
 
 	vcStore, err := storeProviders.provider.OpenStore("verifiable")
 	if err != nil {
-		return fmt.Errorf("open store: %w", err)
+		return nil, fmt.Errorf("open store: %w", err)
 	}
 
 	anchorWriterProviders := &writer.Providers{
@@ -769,6 +1521,25 @@ func startOrbServices(parameters *orbParameters) error {
 		VCStore:                vcStore,
 	}
 
+	var ipfsPubSubSubscriber *ipfspubsub.Subscriber
+
+	if parameters.ipfsPubSubAnchorTopic != "" {
+		ipfsShell := shell.NewShell(parameters.ipfsURL)
+
+		anchorWriterProviders.AnchorAnnouncer = ipfspubsub.NewAnnouncer(parameters.ipfsPubSubAnchorTopic, ipfsShell)
+
+		ipfsPubSubSubscriber = ipfspubsub.NewSubscriber(
+			&ipfspubsub.Config{
+				Topic:      parameters.ipfsPubSubAnchorTopic,
+				ServiceIRI: apServiceIRI,
+			},
+			ipfsShell, apStore,
+			func() apspi.InboxHandler {
+				return activityPubService.InboxHandler()
+			},
+		)
+	}
+
 	anchorWriter, err := writer.New(parameters.didNamespace,
 		apServiceIRI, casIRI,
 		anchorWriterProviders,
@@ -778,7 +1549,7 @@ func startOrbServices(parameters *orbParameters) error {
 		resourceResolver,
 		metrics.Get())
 	if err != nil {
-		return fmt.Errorf("failed to create writer: %s", err.Error())
+		return nil, fmt.Errorf("failed to create writer: %s", err.Error())
 	}
 
 	opQueueCfg := opqueue.Config{
@@ -786,19 +1557,50 @@ func startOrbServices(parameters *orbParameters) error {
 		TaskMonitorInterval: parameters.taskMgrCheckInterval,
 	}
 
-	opQueue, err := opqueue.New(opQueueCfg, pubSub, storeProviders.provider, taskMgr, expiryService, metrics.Get())
+	var opQueueOpts []opqueue.Option
+
+	if webhookNotifier != nil {
+		opQueueOpts = append(opQueueOpts, opqueue.WithRejectedOperationListener(webhookNotifier))
+	}
+
+	if opStatusTracker != nil {
+		opQueueOpts = append(opQueueOpts, opqueue.WithRejectedOperationListener(opStatusTracker))
+	}
+
+	if parameters.opQueueBloomFilterSize > 0 {
+		opQueueOpts = append(opQueueOpts,
+			opqueue.WithBloomFilter(parameters.opQueueBloomFilterSize, opQueueBloomFilterFalsePositiveRate))
+	}
+
+	opQueue, err := opqueue.New(opQueueCfg, pubSub, storeProviders.provider, taskMgr, expiryService, metrics.Get(),
+		opQueueOpts...)
 	if err != nil {
-		return fmt.Errorf("failed to create operation queue: %s", err.Error())
+		return nil, fmt.Errorf("failed to create operation queue: %s", err.Error())
 	}
 
 	opQueue.Start()
 
-	// create new batch writer
+	// The batch cut-off controller cuts a batch when the max operations, max size or max latency is reached.
+	batchCutoffController := newBatchCutoffController(parameters.batchCutoff)
+	batchProtocolClient := batchCutoffController.ProtocolClient(pc)
+
+	var batchAnchorWriter batch.AnchorWriter = anchorWriter
+
+	batchOpQueue := batchCutoffController.OperationQueue(opQueue, batchProtocolClient)
+
+	if opStatusTracker != nil {
+		// Track the status of operations as they move through the queue and the anchor writer.
+		batchAnchorWriter = opStatusTracker.AnchorWriter(batchAnchorWriter)
+		batchOpQueue = opStatusTracker.OperationQueue(batchOpQueue)
+	}
+
+	// create new batch writer. The batch writer isn't gated on leader election since each operation is delivered
+	// to exactly one instance's operation queue and every instance cuts and anchors the operations in its own queue.
 	batchWriter, err := batch.New(parameters.didNamespace,
-		sidetreecontext.New(pc, anchorWriter, opQueue),
+		sidetreecontext.New(batchProtocolClient, batchAnchorWriter, batchOpQueue),
 		batch.WithBatchTimeout(parameters.batchWriterTimeout))
 	if err != nil {
-		return fmt.Errorf("failed to create batch writer: %s", err.Error())
+		return nil, fmt.Errorf("failed to create batch writer: %s", err.Error())
 	}
 
 	// start routine for creating batches
@@ -806,6 +1608,38 @@ func startOrbServices(parameters *orbParameters) error {
 
 	logger.Infof("started batch writer")
 
+	var (
+		migrationStore    *migration.Store
+		migrationImporter *migration.Importer
+	)
+
+	if migrationTracker != nil {
+		migrationStore, err = migration.NewStore(storeProviders.provider)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create migration store: %w", err)
+		}
+
+		migrationImporter = migration.NewImporter(
+			&migration.Config{
+				Namespace:    parameters.didNamespace,
+				AnchorOrigin: apServiceIRI.String(),
+			},
+			&migration.Providers{
+				OperationProvider: migration.NewOperationProvider(ipfsReader),
+				Writer:            batchWriter,
+				ProtocolClient:    pc,
+				Store:             migrationStore,
+				Tracker:           migrationTracker,
+			},
+		)
+
+		migrationImporter.Start()
+	}
+
+	if leaderElector != nil {
+		leaderElector.Start()
+	}
+
 	// start the task manager
 	taskMgr.Start()
 
@@ -845,24 +1679,97 @@ func startOrbServices(parameters *orbParameters) error {
 		didDocHandlerOpts...,
 	)
 
+	featureFlags, err := newFeatureFlags(configStore)
+	if err != nil {
+		return nil, fmt.Errorf("create feature flags: %w", err)
+	}
+
 	apEndpointCfg := &aphandler.Config{
 		BasePath:               activityPubServicesPath,
 		ObjectIRI:              apServiceIRI,
 		VerifyActorInSignature: parameters.httpSignaturesEnabled,
 		PageSize:               parameters.activityPubPageSize,
+		Visibility:             parameters.apCollectionVisibility,
+		Features:               featureFlags,
+		Capabilities:           newCapabilities(parameters),
+	}
+
+	var accessTokens *accesstoken.Store
+
+	if parameters.accessTokensEnabled {
+		accessTokens, err = accesstoken.NewStore(storeProviders.provider, expiryService)
+		if err != nil {
+			return nil, fmt.Errorf("create access token store: %w", err)
+		}
+
+		apEndpointCfg.AccessTokens = accessTokens
+	}
+
+	apServicesHandler := aphandler.NewServices(apEndpointCfg, apStore, publicKey, authTokenManager)
+	apPublicKeysHandler := aphandler.NewPublicKeys(apEndpointCfg, apStore, publicKey, authTokenManager)
+
+	jwksHandler, err := jwks.New(publicKey, signingParams.VerificationMethod, anchorCredentialKey.pubKey)
+	if err != nil {
+		return nil, fmt.Errorf("create JWKS handler: %w", err)
+	}
+
+	dynamicConfig, err := newDynamicConfig(parameters, configStore, apEndpointCfg)
+	if err != nil {
+		return nil, fmt.Errorf("create dynamic configuration: %w", err)
+	}
+
+	if linksetSigner != nil {
+		apPublicKeysHandler.SetPublicKeyResolver(&linksetPublicKeyResolver{
+			signer:       linksetSigner,
+			apServiceIRI: apServiceIRI,
+		})
+	}
+
+	if parameters.httpSignaturesEnabled {
+		signers := apKeyIDSetters
+
+		if linksetSigner != nil {
+			signers = append(signers, linksetSigner)
+		}
+
+		keyRotator := newHTTPSignatureKeyRotator(httpSignatureKey.keyID, httpSignatureKey.km, apServiceIRI,
+			apServicePublicKeyIRI, signers, apServicesHandler, apPublicKeysHandler, jwksHandler)
+
+		if err = keyRotator.register(dynamicConfig); err != nil {
+			return nil, fmt.Errorf("register HTTP signature key rotator: %w", err)
+		}
+	}
+
+	if err = registerBatchCutoffParameters(dynamicConfig, batchCutoffController); err != nil {
+		return nil, fmt.Errorf("register batch cut-off parameters: %w", err)
+	}
+
+	err = registerActivityPubParameters(dynamicConfig, apConfig.RetryOpts, parameters.inboxSenderPolicy)
+	if err != nil {
+		return nil, fmt.Errorf("register ActivityPub parameters: %w", err)
+	}
+
+	if err = registerHTTPDestinations(dynamicConfig, httpDestinations); err != nil {
+		return nil, fmt.Errorf("register HTTP destination parameters: %w", err)
+	}
+
+	actorProfile, err := newActorProfile(dynamicConfig, apServicesHandler)
+	if err != nil {
+		return nil, fmt.Errorf("create actor profile: %w", err)
 	}
 
 	var resolveHandlerOpts []resolvehandler.Option
 	resolveHandlerOpts = append(resolveHandlerOpts, resolvehandler.WithUnpublishedDIDLabel(unpublishedDIDLabel))
 	resolveHandlerOpts = append(resolveHandlerOpts, resolvehandler.WithEnableDIDDiscovery(parameters.didDiscoveryEnabled))
 	resolveHandlerOpts = append(resolveHandlerOpts, resolvehandler.WithEnableResolutionFromAnchorOrigin(parameters.resolveFromAnchorOrigin))
+	resolveHandlerOpts = append(resolveHandlerOpts, resolvehandler.WithNetwork(parameters.didNetwork))
 
 	var updateHandlerOpts []updatehandler.Option
 
 	if parameters.createDocumentStoreEnabled {
 		store, openErr := storeProviders.provider.OpenStore("create-document")
 		if openErr != nil {
-			return fmt.Errorf("failed to open 'create-document' store: %w", openErr)
+			return nil, fmt.Errorf("failed to open 'create-document' store: %w", openErr)
 		}
 
 		resolveHandlerOpts = append(resolveHandlerOpts, resolvehandler.WithCreateDocumentStore(store))
@@ -885,12 +1792,18 @@ func startOrbServices(parameters *orbParameters) error {
 
 	orbDocUpdateHandler := updatehandler.New(didDocHandler, metrics.Get(), updateHandlerOpts...)
 
+	// The gRPC API submits operations to the same handlers (and requires the same auth tokens) as the REST API.
+	operationsGRPCHandler := grpcapi.NewHandler(parameters.didNamespace, pc, orbDocUpdateHandler, orbDocResolveHandler,
+		grpcapi.WithAuthTokens(authTokenManager, baseUpdatePath, baseResolvePath+"/{id}"),
+		grpcapi.WithMaintenanceMode(maintenanceMode),
+	)
+
 	// create discovery rest api
 	endpointDiscoveryOp, err := discoveryrest.New(
 		&discoveryrest.Config{
-			PubKey:                    pubKey,
+			PubKey:                    anchorCredentialKey.pubKey,
 			VerificationMethodType:    verificationMethodType,
-			KID:                       parameters.keyID,
+			KID:                       anchorCredentialKey.keyID,
 			ResolutionPath:            baseResolvePath,
 			OperationPath:             baseUpdatePath,
 			WebCASPath:                casPath,
@@ -899,6 +1812,8 @@ func startOrbServices(parameters *orbParameters) error {
 			DiscoveryMinimumResolvers: parameters.discoveryMinimumResolvers,
 			VctURL:                    parameters.vctURL,
 			DiscoveryVctDomains:       parameters.discoveryVctDomains,
+			DIDNamespace:              parameters.didNamespace,
+			DIDNetwork:                parameters.didNetwork,
 		},
 		&discoveryrest.Providers{
 			ResourceRegistry: resourceRegistry,
@@ -907,12 +1822,12 @@ func startOrbServices(parameters *orbParameters) error {
 			WebfingerClient:  wfClient,
 		})
 	if err != nil {
-		return fmt.Errorf("discovery rest: %w", err)
+		return nil, fmt.Errorf("discovery rest: %w", err)
 	}
 
 	var usingMongoDB bool
 
-	if parameters.dbParameters.databaseType == databaseTypeMongoDBOption {
+	if storeProviders.provider.dbType == databaseTypeMongoDBOption {
 		usingMongoDB = true
 	}
 
@@ -925,13 +1840,59 @@ func startOrbServices(parameters *orbParameters) error {
 	nodeInfoLogger := log.New("nodeinfo")
 
 	nodeInfoService := nodeinfo.NewService(apServiceIRI, parameters.nodeInfoRefreshInterval, apStore, usingMongoDB,
-		nodeInfoLogger)
+		nodeInfoLogger, nodeinfo.WithDIDCounter(didAnchors))
+
+	var operationsHandler restcommon.HTTPHandler = diddochandler.NewUpdateHandler(baseUpdatePath,
+		orbDocUpdateHandler, pc, metrics.Get())
+
+	var quotaMgr *quota.Manager
+
+	if parameters.operationQuotas != nil {
+		quotaStore, e := quota.NewStore(storeProviders.provider, expiryService)
+		if e != nil {
+			return nil, fmt.Errorf("create quota usage store: %w", e)
+		}
+
+		quotaMgr = quota.NewManager(parameters.operationQuotas, quotaStore)
+
+		// The quota wrapper is inside the idempotency wrapper so that replayed responses aren't counted
+		// against the quotas.
+		operationsHandler = quota.NewHandlerWrapper(operationsHandler, quotaMgr)
+	}
+
+	if parameters.operationIdempotencyWindow > 0 {
+		idempotencyStore, e := idempotency.NewStore(storeProviders.provider, expiryService,
+			parameters.operationIdempotencyWindow)
+		if e != nil {
+			return nil, fmt.Errorf("create idempotency key store: %w", e)
+		}
+
+		operationsHandler = idempotency.NewHandlerWrapper(operationsHandler, idempotencyStore)
+	}
+
+	if opStatusStore != nil {
+		operationsHandler = opstatus.NewHandlerWrapper(operationsHandler)
+	}
+
+	var webCASOpts []webcas.Option
+
+	if linksetSigner != nil {
+		webCASOpts = append(webCASOpts, webcas.WithLinksetSignatures(linksetSigner))
+	}
 
 	handlers := make([]restcommon.HTTPHandler, 0)
 
 	handlers = append(handlers,
-		auth.NewHandlerWrapper(diddochandler.NewUpdateHandler(baseUpdatePath, orbDocUpdateHandler, pc, metrics.Get()), authTokenManager),
-		signature.NewHandlerWrapper(diddochandler.NewResolveHandler(baseResolvePath, orbDocResolveHandler, metrics.Get()),
+		maintenance.NewHandlerWrapper(
+			auth.NewHandlerWrapper(operationsHandler, authTokenManager),
+			maintenanceMode,
+		),
+		auth.NewHandlerWrapper(validatehandler.New(baseUpdatePath, parameters.didNamespace, pc), authTokenManager),
+		auth.NewHandlerWrapper(protocolhandler.NewVersionHandler(basePath, nodeinfo.OrbVersion, pc), authTokenManager),
+		auth.NewHandlerWrapper(protocolhandler.NewParametersHandler(basePath, pc), authTokenManager),
+		auth.NewHandlerWrapper(diffhandler.New(uniresolver.BasePath, parameters.didNamespace, opStore, pc),
+			authTokenManager),
+		signature.NewHandlerWrapper(resolutionhandler.New(baseResolvePath, orbDocResolveHandler, metrics.Get()),
 			&aphandler.Config{
 				ObjectIRI:              apServiceIRI,
 				VerifyActorInSignature: parameters.httpSignaturesEnabled,
@@ -939,12 +1900,15 @@ func startOrbServices(parameters *orbParameters) error {
 			},
 			apStore, apSigVerifier, authTokenManager,
 		),
-		activityPubService.InboxHTTPHandler(),
-		aphandler.NewServices(apEndpointCfg, apStore, publicKey, authTokenManager),
-		aphandler.NewPublicKeys(apEndpointCfg, apStore, publicKey, authTokenManager),
+		maintenance.NewHandlerWrapper(activityPubService.InboxHTTPHandler(), maintenanceMode),
+		apServicesHandler,
+		apPublicKeysHandler,
 		aphandler.NewFollowers(apEndpointCfg, apStore, apSigVerifier, authTokenManager),
+		aphandler.NewAnchorDigest(apEndpointCfg, apStore, apSigVerifier, authTokenManager),
+		aphandler.NewProvenance(apEndpointCfg, apStore, apSigVerifier, authTokenManager),
 		aphandler.NewFollowing(apEndpointCfg, apStore, apSigVerifier, authTokenManager),
 		aphandler.NewOutbox(apEndpointCfg, apStore, apSigVerifier, activitypubspi.SortAscending, authTokenManager),
+		aphandler.NewOutboxChanges(apEndpointCfg, apStore, apSigVerifier, authTokenManager),
 		aphandler.NewInbox(apEndpointCfg, apStore, apSigVerifier, activitypubspi.SortAscending, authTokenManager),
 		aphandler.NewWitnesses(apEndpointCfg, apStore, apSigVerifier, authTokenManager),
 		aphandler.NewWitnessing(apEndpointCfg, apStore, apSigVerifier, authTokenManager),
@@ -959,86 +1923,520 @@ func startOrbServices(parameters *orbParameters) error {
 				VerifyActorInSignature: parameters.httpSignaturesEnabled,
 				PageSize:               parameters.activityPubPageSize,
 			},
-			apStore, apSigVerifier, coreCASClient, authTokenManager,
+			apStore, apSigVerifier, coreCASClient, authTokenManager, webCASOpts...,
 		),
 		auth.NewHandlerWrapper(policyhandler.New(configStore), authTokenManager),
+		auth.NewHandlerWrapper(policyhandler.NewSimulator(configStore), authTokenManager),
 		auth.NewHandlerWrapper(nodeinfo.NewHandler(nodeinfo.V2_0, nodeInfoService, nodeInfoLogger), authTokenManager),
 		auth.NewHandlerWrapper(nodeinfo.NewHandler(nodeinfo.V2_1, nodeInfoService, nodeInfoLogger), authTokenManager),
+		auth.NewHandlerWrapper(stats.NewHandler(statsAggregator), authTokenManager),
+		auth.NewHandlerWrapper(latency.NewHandler(anchorLatency), authTokenManager),
 		auth.NewHandlerWrapper(vcresthandler.New(vcStore), authTokenManager),
+		auth.NewHandlerWrapper(dynamicconfighandler.NewReader(dynamicConfig), authTokenManager),
+		auth.NewHandlerWrapper(dynamicconfighandler.NewWriter(dynamicConfig), authTokenManager),
 	)
 
+	if activitySearchIndex != nil {
+		handlers = append(handlers,
+			aphandler.NewSearch(apEndpointCfg, apStore, activitySearchIndex, apSigVerifier, authTokenManager),
+		)
+	}
+
+	if deliveryReceipts != nil {
+		handlers = append(handlers,
+			aphandler.NewDeliveries(apEndpointCfg, apStore, deliveryReceipts, apSigVerifier, authTokenManager),
+		)
+	}
+
+	if faultInjector != nil {
+		handlers = append(handlers,
+			auth.NewHandlerWrapper(faultinjectionhandler.NewReader(faultInjector), authTokenManager),
+			auth.NewHandlerWrapper(faultinjectionhandler.NewWriter(faultInjector), authTokenManager),
+		)
+	}
+
 	handlers = append(handlers,
 		endpointDiscoveryOp.GetRESTHandlers()...)
 
+	handlers = append(handlers, jwksHandler)
+
 	for _, handler := range ldrest.New(ldsvc.New(ldStore)).GetRESTHandlers() {
 		handlers = append(handlers, auth.NewHandlerWrapper(&httpHandler{handler}, authTokenManager))
 	}
 
-	if parameters.followAuthPolicy == acceptListPolicy || parameters.inviteWitnessAuthPolicy == acceptListPolicy {
-		// Register endpoints to manage the 'accept list'.
-		handlers = append(handlers, auth.NewHandlerWrapper(
-			aphandler.NewAcceptListWriter(apEndpointCfg, acceptlist.NewManager(configStore)), authTokenManager),
+	if parameters.graphQLEnabled {
+		schema := orbschema.New(&orbschema.Config{ServiceIRI: apServiceIRI}, apStore, anchorGraph,
+			orbDocResolveHandler)
+
+		handlers = append(handlers,
+			auth.NewHandlerWrapper(graphql.NewGetHandler(schema), authTokenManager),
+			auth.NewHandlerWrapper(graphql.NewPostHandler(schema), authTokenManager),
 		)
-		handlers = append(handlers, auth.NewHandlerWrapper(
-			aphandler.NewAcceptListReader(apEndpointCfg, acceptlist.NewManager(configStore)), authTokenManager),
+	}
+
+	if parameters.universalResolverDriverEnabled {
+		handlers = append(handlers,
+			auth.NewHandlerWrapper(uniresolver.New(orbDocResolveHandler, metrics.Get()), authTokenManager),
 		)
 	}
 
-	httpServer := httpserver.New(
-		parameters.hostURL,
-		parameters.tlsParams.serveCertPath,
-		parameters.tlsParams.serveKeyPath,
-		handlers...,
-	)
+	if anchorIndex != nil {
+		handlers = append(handlers,
+			auth.NewHandlerWrapper(explorer.NewSearch(anchorIndex), authTokenManager),
+			auth.NewHandlerWrapper(explorer.NewAnchor(anchorIndex), authTokenManager),
+		)
+	}
 
-	metricsHttpServer := httpserver.New(
-		parameters.hostMetricsURL, "", "",
-		metrics.NewHandler(),
+	if opStatusStore != nil {
+		handlers = append(handlers,
+			auth.NewHandlerWrapper(opstatus.NewHandler(baseUpdatePath, opStatusStore), authTokenManager),
+		)
+	}
+
+	handlers = append(handlers,
+		auth.NewHandlerWrapper(opqueue.NewHandler(basePath, opQueue), authTokenManager),
 	)
 
-	activityPubService.Start()
+	stopMigrationImporter := func() {}
 
-	nodeInfoService.Start()
+	if migrationImporter != nil {
+		handlers = append(handlers,
+			auth.NewHandlerWrapper(migrationhandler.NewImport(migrationImporter), authTokenManager),
+			auth.NewHandlerWrapper(migrationhandler.NewStatus(migrationImporter), authTokenManager),
+			auth.NewHandlerWrapper(migrationhandler.NewProvenance(migrationStore), authTokenManager),
+		)
 
-	err = metricsHttpServer.Start()
-	if err != nil {
-		return fmt.Errorf("start metrics HTTP server at %s: %w", parameters.hostMetricsURL, err)
+		stopMigrationImporter = migrationImporter.Stop
 	}
 
-	srv := &HTTPServer{}
+	stopDeliveryStats := func() {}
 
-	err = srv.Start(httpServer)
-	if err != nil {
-		return err
+	if deliveryStats != nil {
+		handlers = append(handlers,
+			auth.NewHandlerWrapper(deliverystats.NewHandler(deliveryStats), authTokenManager),
+		)
+
+		deliveryStats.Start()
+
+		stopDeliveryStats = deliveryStats.Stop
 	}
 
-	logger.Infof("Stopping Orb services ...")
+	stopMetricsCounterPersister := func() {}
+
+	if metricsCounterPersister != nil {
+		metricsCounterPersister.Start()
 
-	nodeInfoService.Stop()
+		stopMetricsCounterPersister = metricsCounterPersister.Stop
+	}
 
-	opQueue.Stop()
+	stopWebhookNotifier := func() {}
 
-	batchWriter.Stop()
+	if webhookNotifier != nil {
+		webhookNotifier.ListenActivities(activityPubService.Subscribe())
 
-	o.Stop()
+		handlers = append(handlers,
+			auth.NewHandlerWrapper(webhookhandler.NewRegistrar(webhookStore), authTokenManager),
+			auth.NewHandlerWrapper(webhookhandler.NewReader(webhookStore), authTokenManager),
+			auth.NewHandlerWrapper(webhookhandler.NewRemover(webhookStore), authTokenManager),
+			auth.NewHandlerWrapper(webhookhandler.NewDeliveries(webhookStore), authTokenManager),
+		)
 
-	activityPubService.Stop()
+		if parameters.didWebhooksEnabled {
+			handlers = append(handlers,
+				auth.NewHandlerWrapper(webhookhandler.NewDIDRegistrar(webhookStore, orbDocResolveHandler),
+					authTokenManager),
+			)
+		}
 
-	taskMgr.Stop()
+		webhookNotifier.Start()
 
-	if err := pubSub.Close(); err != nil {
-		logger.Warnf("Error closing publisher/subscriber: %s", err)
+		stopWebhookNotifier = webhookNotifier.Stop
 	}
 
-	logger.Infof("Stopped Orb services.")
+	stopDIDSnapshotter := func() {}
+
+	if didSnapshotter != nil {
+		didSnapshotter.Start()
+
+		stopDIDSnapshotter = didSnapshotter.Stop
+	}
+
+	if parameters.followAuthPolicy == acceptListPolicy || parameters.followAuthPolicy == acceptListHoldPolicy ||
+		parameters.inviteWitnessAuthPolicy == acceptListPolicy {
+		// Register endpoints to manage the 'accept list'.
+		handlers = append(handlers, auth.NewHandlerWrapper(
+			aphandler.NewAcceptListWriter(apEndpointCfg, acceptlist.NewManager(configStore)), authTokenManager),
+		)
+		handlers = append(handlers, auth.NewHandlerWrapper(
+			aphandler.NewAcceptListReader(apEndpointCfg, acceptlist.NewManager(configStore)), authTokenManager),
+		)
+	}
+
+	if parameters.followAuthPolicy == acceptListHoldPolicy {
+		pendingFollowsHandlers, e := newPendingFollowsHandlers(apEndpointCfg, apStore, apSigVerifier,
+			parameters.authTokens, activityPubService)
+		if e != nil {
+			return nil, fmt.Errorf("create pending follows handlers: %w", e)
+		}
+
+		handlers = append(handlers, pendingFollowsHandlers...)
+	}
+
+	if parameters.authTokens[adminTokenID] != "" {
+		archiveExporter := archive.NewExporter(
+			&archive.Config{
+				ServiceIRI: apServiceIRI,
+				KeyID:      httpSignatureKey.keyID,
+				PublicKey:  httpSignatureKey.pubKey,
+			},
+			apStore, apSignatureStore, httpSignatureKey,
+		)
+
+		archiveHandler, e := newArchiveHandler(parameters.authTokens, archiveExporter)
+		if e != nil {
+			return nil, fmt.Errorf("create archive handler: %w", e)
+		}
+
+		handlers = append(handlers, archiveHandler)
+
+		if apQuarantine != nil {
+			quarantineHandlers, e := newQuarantineHandlers(parameters.authTokens, apQuarantine,
+				activityPubService.InboxHTTPHandler().Handler())
+			if e != nil {
+				return nil, fmt.Errorf("create quarantine handlers: %w", e)
+			}
+
+			handlers = append(handlers, quarantineHandlers...)
+		}
+
+		if accessTokens != nil {
+			accessTokenHandlers, e := newAccessTokenHandlers(parameters.authTokens, accessTokens)
+			if e != nil {
+				return nil, fmt.Errorf("create access token handlers: %w", e)
+			}
+
+			handlers = append(handlers, accessTokenHandlers...)
+		}
+
+		if apEvidence != nil {
+			evidenceHandler, e := newInboxEvidenceHandler(parameters.authTokens, apEvidence)
+			if e != nil {
+				return nil, fmt.Errorf("create inbox evidence handler: %w", e)
+			}
+
+			handlers = append(handlers, evidenceHandler)
+		}
+
+		rejectionsHandler, e := newInboxRejectionsHandler(parameters.authTokens, apRejections)
+		if e != nil {
+			return nil, fmt.Errorf("create inbox rejections handler: %w", e)
+		}
+
+		handlers = append(handlers, rejectionsHandler)
+
+		compositionErrorsHandler, e := newCompositionErrorsHandler(parameters.authTokens, compositionErrors)
+		if e != nil {
+			return nil, fmt.Errorf("create composition errors handler: %w", e)
+		}
+
+		handlers = append(handlers, compositionErrorsHandler)
+
+		if quotaMgr != nil {
+			quotaHandler, e := newQuotaUsageHandler(parameters.authTokens, quotaMgr)
+			if e != nil {
+				return nil, fmt.Errorf("create quota usage handler: %w", e)
+			}
+
+			handlers = append(handlers, quotaHandler)
+		}
+
+		if actorKeyPins != nil {
+			keyPinHandlers, e := newKeyPinHandlers(parameters.authTokens, actorKeyPins)
+			if e != nil {
+				return nil, fmt.Errorf("create actor key pin handlers: %w", e)
+			}
+
+			handlers = append(handlers, keyPinHandlers...)
+		}
+
+		if witnessExpiry != nil {
+			witnessExpiryHandler, e := newWitnessExpiryHandler(parameters.authTokens, witnessExpiry)
+			if e != nil {
+				return nil, fmt.Errorf("create witness expiry handler: %w", e)
+			}
+
+			handlers = append(handlers, witnessExpiryHandler)
+		}
+
+		contextPinHandlers, e := newContextPinHandlers(parameters.authTokens, orbDocumentLoader)
+		if e != nil {
+			return nil, fmt.Errorf("create context pin handlers: %w", e)
+		}
+
+		handlers = append(handlers, contextPinHandlers...)
+
+		featureFlagHandlers, e := newFeatureFlagHandlers(parameters.authTokens, featureFlags)
+		if e != nil {
+			return nil, fmt.Errorf("create feature flag handlers: %w", e)
+		}
+
+		handlers = append(handlers, featureFlagHandlers...)
+
+		logAllowListHandlers, e := newLogAllowListHandlers(parameters.authTokens, logAllowList)
+		if e != nil {
+			return nil, fmt.Errorf("create log allow list handlers: %w", e)
+		}
+
+		handlers = append(handlers, logAllowListHandlers...)
+
+		profileHandlers, e := newProfileHandlers(parameters.authTokens, actorProfile)
+		if e != nil {
+			return nil, fmt.Errorf("create profile handlers: %w", e)
+		}
+
+		handlers = append(handlers, profileHandlers...)
+
+		taskHandlers, e := newTaskHandlers(parameters.authTokens, taskMgr)
+		if e != nil {
+			return nil, fmt.Errorf("create task handlers: %w", e)
+		}
+
+		handlers = append(handlers, taskHandlers...)
+
+		maintenanceHandlers, e := newMaintenanceHandlers(parameters.authTokens, maintenanceMode)
+		if e != nil {
+			return nil, fmt.Errorf("create maintenance handlers: %w", e)
+		}
+
+		handlers = append(handlers, maintenanceHandlers...)
+
+		if leaderElector != nil {
+			leaderHandler, e := newLeaderHandler(parameters.authTokens, leaderElector)
+			if e != nil {
+				return nil, fmt.Errorf("create leader election handler: %w", e)
+			}
+
+			handlers = append(handlers, leaderHandler)
+		}
+	} else {
+		logger.Infof("The activity archive, task and maintenance endpoints are disabled since no admin token " +
+			"is configured")
+	}
+
+	if parameters.enableProfiling {
+		debugHandlers, e := newDebugHandlers(parameters.authTokens)
+		if e != nil {
+			return nil, fmt.Errorf("create debug handlers: %w", e)
+		}
+
+		handlers = append(handlers, debugHandlers...)
+	}
+
+	handlers = applyAdminIPFilter(handlers, parameters.adminIPFilter, authTokenManager,
+		parameters.authTokens[adminTokenID])
+
+	activityPubService.Start()
+
+	stopIPFSPubSubSubscriber := func() {}
+
+	if ipfsPubSubSubscriber != nil {
+		ipfsPubSubSubscriber.Start()
+
+		stopIPFSPubSubSubscriber = ipfsPubSubSubscriber.Stop
+	}
+
+	nodeInfoService.Start()
+
+	statsAggregator.Start()
+
+	stopSuffixIndex := func() {}
+
+	if suffixIndex != nil {
+		suffixIndex.Start()
+
+		stopSuffixIndex = suffixIndex.Stop
+	}
+
+	dynamicConfig.Start()
+	featureFlags.Start()
+
+	return &orbService{
+		handlers: applyRequestLimits(applyResponseCompression(handlers, parameters.responseCompression),
+			parameters.requestLimits),
+		grpcHandler: operationsGRPCHandler,
+		// Give the batch writer a chance to cut the pending operations and then wait for in-flight
+		// ActivityPub and observer messages to be processed.
+		shutdownSteps: []shutdownStep{
+			newOperationQueueDrainStep(opQueue, parameters.shutdownTimeout/2),
+			newShutdownStep("batch writer", batchWriter.Stop),
+			newShutdownStep("operation queue", opQueue.Stop),
+			newShutdownStep("IPFS pubsub subscriber", stopIPFSPubSubSubscriber),
+			newShutdownStep("ActivityPub service", activityPubService.Stop),
+			newShutdownStep("observer", o.Stop),
+			newShutdownStep("observer sharding", stopObserverSharding),
+			newShutdownStep("webhook notifier", stopWebhookNotifier),
+			newShutdownStep("DID snapshotter", stopDIDSnapshotter),
+			newShutdownStep("DID suffix index", stopSuffixIndex),
+			newShutdownStep("migration importer", stopMigrationImporter),
+			newShutdownStep("NodeInfo service", nodeInfoService.Stop),
+			newShutdownStep("stats aggregator", statsAggregator.Stop),
+			newShutdownStep("delivery stats collector", stopDeliveryStats),
+			newShutdownStep("metrics counter persister", stopMetricsCounterPersister),
+			newShutdownStep("dynamic configuration", dynamicConfig.Stop),
+			newShutdownStep("feature flags", featureFlags.Stop),
+			newShutdownStep("task manager", taskMgr.Stop),
+			newShutdownStep("leader election", stopLeaderElection),
+			// Releases any messages that are held while in maintenance mode.
+			{name: "maintenance publisher/subscriber", stop: func(context.Context) error { return pubSub.Close() }},
+		},
+	}, nil
+}
+
+// newDynamicConfig registers the parameters that may be updated at runtime and subscribes the
+// affected components to changes.
+func newDynamicConfig(parameters *orbParameters, configStore storage.Store,
+	apEndpointCfg *aphandler.Config) (*dynamic.Manager, error) {
+	dynamicConfig := dynamic.New(configStore)
+
+	err := dynamicConfig.Register(&dynamic.Parameter{
+		Name:         activityPubPageSizeFlagName,
+		Description:  "The maximum page size for an ActivityPub collection or ordered collection.",
+		DefaultValue: strconv.Itoa(parameters.activityPubPageSize),
+		Validate:     dynamic.PositiveInt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("register parameter [%s]: %w", activityPubPageSizeFlagName, err)
+	}
+
+	err = dynamicConfig.Subscribe(activityPubPageSizeFlagName, func(value string) {
+		pageSize, e := strconv.Atoi(value)
+		if e != nil {
+			logger.Warnf("Invalid value for [%s]: %s", activityPubPageSizeFlagName, e)
+
+			return
+		}
+
+		apEndpointCfg.SetPageSize(pageSize)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("subscribe to parameter [%s]: %w", activityPubPageSizeFlagName, err)
+	}
+
+	return dynamicConfig, nil
+}
+
+// newFeatureFlags registers the features that may be enabled or disabled at runtime, either for the entire
+// deployment or for individual peers.
+func newFeatureFlags(configStore storage.Store) (*featureflag.Manager, error) {
+	featureFlags := featureflag.New(configStore)
+
+	err := featureFlags.Register(&featureflag.Flag{
+		Name: featureflag.MultikeyPublicKey,
+		Description: "Publish the public key of the service in Multikey (publicKeyMultibase) format in addition " +
+			"to the PEM format.",
+		Default: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("register feature flag [%s]: %w", featureflag.MultikeyPublicKey, err)
+	}
+
+	return featureFlags, nil
+}
+
+// newFeatureFlagHandlers returns the handlers that list the feature flags and allow a feature to be enabled or
+// disabled. The handlers require the admin token, regardless of the authorization token definitions.
+func newFeatureFlagHandlers(authTokens map[string]string,
+	m *featureflag.Manager) ([]restcommon.HTTPHandler, error) {
+	tm, err := newAdminTokenManager("^"+featureflaghandler.Path+"$", authTokens)
+	if err != nil {
+		return nil, err
+	}
+
+	return []restcommon.HTTPHandler{
+		auth.NewHandlerWrapper(featureflaghandler.NewReader(m), tm),
+		auth.NewHandlerWrapper(featureflaghandler.NewWriter(m), tm),
+	}, nil
+}
+
+// addLocalVCTLog adds the VCT log of this service to the log allow list, unless the log is already in the
+// list, in which case the existing entry (which may contain a public key) is left as is.
+func addLocalVCTLog(l *logallowlist.Manager, vctURL string) error {
+	if vctURL == "" {
+		return nil
+	}
+
+	_, err := l.Get(vctURL)
+	if err == nil {
+		return nil
+	}
+
+	if !errors.Is(err, orberrors.ErrContentNotFound) {
+		return fmt.Errorf("get log [%s] from allow list: %w", vctURL, err)
+	}
+
+	err = l.Update([]*logallowlist.Log{{URL: vctURL}}, nil)
+	if err != nil {
+		return fmt.Errorf("add log [%s] to allow list: %w", vctURL, err)
+	}
 
 	return nil
 }
 
+// newLogAllowListHandlers returns the handlers that list the trusted VCT logs and allow logs to be added and
+// removed. The handlers require the admin token, regardless of the authorization token definitions.
+func newLogAllowListHandlers(authTokens map[string]string,
+	m *logallowlist.Manager) ([]restcommon.HTTPHandler, error) {
+	tm, err := newAdminTokenManager("^"+logallowlisthandler.Path+"$", authTokens)
+	if err != nil {
+		return nil, err
+	}
+
+	return []restcommon.HTTPHandler{
+		auth.NewHandlerWrapper(logallowlisthandler.NewReader(m), tm),
+		auth.NewHandlerWrapper(logallowlisthandler.NewWriter(m), tm),
+	}, nil
+}
+
+// newActorProfile registers the profile of the service actor with the dynamic configuration and updates the
+// services handler whenever the profile changes.
+func newActorProfile(dynamicConfig *dynamic.Manager, servicesHandler *aphandler.Services) (*profile.Manager, error) {
+	actorProfile, err := profile.NewManager(dynamicConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	err = actorProfile.Subscribe(func(p *profile.Profile) {
+		opts, e := p.Options()
+		if e != nil {
+			logger.Warnf("Invalid actor profile: %s", e)
+
+			return
+		}
+
+		servicesHandler.SetProfile(opts...)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("subscribe to actor profile: %w", err)
+	}
+
+	return actorProfile, nil
+}
+
+// newCapabilities returns the capabilities of this server which are published in the service document so that
+// peers may choose compatible formats when exchanging activities with this server.
+func newCapabilities(parameters *orbParameters) *vocab.CapabilitiesType {
+	return &vocab.CapabilitiesType{
+		SignatureSuites:  []string{vcsigner.Ed25519Signature2018, vcsigner.JSONWebSignature2020},
+		LinksetFormats:   []string{linkset.ContentTypeJSON, linkset.ContentTypeNative},
+		ProtocolVersions: parameters.sidetreeProtocolVersions,
+		ContentEncodings: []string{transport.GzipContentEncoding},
+	}
+}
+
 func getProtocolClientProvider(parameters *orbParameters, casClient casapi.Client, casResolver common.CASResolver,
-	opStore common.OperationStore, provider storage.Provider,
-	unpublishedOpStore *unpublishedopstore.Store) (*orbpcp.ClientProvider, error) {
-	versions := []string{"1.0"}
+	opStore common.OperationStore, provider storage.Provider, unpublishedOpStore *unpublishedopstore.Store,
+	compositionErrors *composition.Recorder) (*orbpcp.ClientProvider, error) {
+	versions := parameters.sidetreeProtocolVersions
 
 	sidetreeCfg := config.Sidetree{
 		MethodContext:                parameters.methodContext,
@@ -1048,6 +2446,8 @@ func getProtocolClientProvider(parameters *orbParameters, casClient casapi.Clien
 		UpdateDocumentStoreTypes:     parameters.updateDocumentStoreTypes,
 		IncludeUnpublishedOperations: parameters.includeUnpublishedOperations,
 		IncludePublishedOperations:   parameters.includePublishedOperations,
+		CompressionAlgorithm:         parameters.batchCompressionAlgorithm,
+		CompositionErrors:            compositionErrors,
 	}
 
 	registry := factoryregistry.New()
@@ -1137,41 +2537,55 @@ type storageProvider struct {
 type storageProviders struct {
 	provider           *storageProvider
 	kmsSecretsProvider storage.Provider
+	dualWriteVerifier  *dualwrite.Verifier
 }
 
 //nolint: gocyclo
 func createStoreProviders(parameters *orbParameters) (*storageProviders, error) {
 	var edgeServiceProvs storageProviders
 
-	switch { //nolint: dupl
-	case strings.EqualFold(parameters.dbParameters.databaseType, databaseTypeMemOption):
-		edgeServiceProvs.provider = &storageProvider{ariesmemstorage.NewProvider(), databaseTypeMemOption}
-	case strings.EqualFold(parameters.dbParameters.databaseType, databaseTypeCouchDBOption):
-		couchDBProvider, err :=
-			ariescouchdbstorage.NewProvider(parameters.dbParameters.databaseURL,
-				ariescouchdbstorage.WithDBPrefix(parameters.dbParameters.databasePrefix),
-				ariescouchdbstorage.WithLogger(logger))
-		if err != nil {
-			return &storageProviders{}, err
+	provider, err := createStoreProvider(parameters.dbParameters.databaseType, parameters.dbParameters.databaseURL,
+		parameters.dbParameters.databasePrefix, parameters.databaseTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	edgeServiceProvs.provider = provider
+
+	if parameters.dbParameters.readDatabaseURL != "" {
+		replicaProvider, e := createStoreProvider(parameters.dbParameters.databaseType,
+			parameters.dbParameters.readDatabaseURL, parameters.dbParameters.databasePrefix,
+			parameters.databaseTimeout)
+		if e != nil {
+			return nil, fmt.Errorf("read database: %w", e)
 		}
 
-		edgeServiceProvs.provider = &storageProvider{wrapper.NewProvider(couchDBProvider, "CouchDB"),
-			databaseTypeCouchDBOption}
-	case strings.EqualFold(parameters.dbParameters.databaseType, databaseTypeMongoDBOption):
-		mongoDBProvider, err := ariesmongodbstorage.NewProvider(parameters.dbParameters.databaseURL,
-			ariesmongodbstorage.WithDBPrefix(parameters.dbParameters.databasePrefix),
-			ariesmongodbstorage.WithLogger(logger),
-			ariesmongodbstorage.WithTimeout(parameters.databaseTimeout))
-		if err != nil {
-			return nil, fmt.Errorf("create MongoDB storage provider: %w", err)
+		logger.Infof("Queries are served by the read database - max staleness: %s",
+			parameters.dbParameters.readDatabaseMaxStaleness)
+
+		edgeServiceProvs.provider = &storageProvider{
+			readreplica.NewProvider(provider.Provider, replicaProvider.Provider,
+				readreplica.WithMaxStaleness(parameters.dbParameters.readDatabaseMaxStaleness)),
+			provider.dbType,
 		}
+	}
 
-		edgeServiceProvs.provider = &storageProvider{wrapper.NewProvider(mongoDBProvider, "MongoDB"),
-			databaseTypeMongoDBOption}
+	if parameters.dbParameters.legacyDatabaseType != "" {
+		legacyProvider, e := createStoreProvider(parameters.dbParameters.legacyDatabaseType,
+			parameters.dbParameters.legacyDatabaseURL, parameters.dbParameters.legacyDatabasePrefix,
+			parameters.databaseTimeout)
+		if e != nil {
+			return nil, fmt.Errorf("legacy database: %w", e)
+		}
 
-	default:
-		return &storageProviders{}, fmt.Errorf("database type not set to a valid type." +
-			" run start --help to see the available options")
+		logger.Infof("Dual-write mode enabled - legacy database: %s, new database: %s",
+			legacyProvider.dbType, provider.dbType)
+
+		dualWriteProvider := dualwrite.NewProvider(provider.Provider, legacyProvider.Provider)
+
+		// Queries are served by the legacy database so the capabilities of the legacy database apply.
+		edgeServiceProvs.provider = &storageProvider{dualWriteProvider, legacyProvider.dbType}
+		edgeServiceProvs.dualWriteVerifier = dualwrite.NewVerifier(dualWriteProvider, dualwrite.WithCopyMissing(true))
 	}
 
 	if parameters.kmsStoreEndpoint != "" || parameters.kmsEndpoint != "" {
@@ -1208,6 +2622,36 @@ func createStoreProviders(parameters *orbParameters) (*storageProviders, error)
 	return &edgeServiceProvs, nil
 }
 
+func createStoreProvider(databaseType, databaseURL, databasePrefix string,
+	timeout time.Duration) (*storageProvider, error) {
+	switch {
+	case strings.EqualFold(databaseType, databaseTypeMemOption):
+		return &storageProvider{ariesmemstorage.NewProvider(), databaseTypeMemOption}, nil
+	case strings.EqualFold(databaseType, databaseTypeCouchDBOption):
+		couchDBProvider, err := ariescouchdbstorage.NewProvider(databaseURL,
+			ariescouchdbstorage.WithDBPrefix(databasePrefix),
+			ariescouchdbstorage.WithLogger(logger))
+		if err != nil {
+			return nil, err
+		}
+
+		return &storageProvider{wrapper.NewProvider(couchDBProvider, "CouchDB"), databaseTypeCouchDBOption}, nil
+	case strings.EqualFold(databaseType, databaseTypeMongoDBOption):
+		mongoDBProvider, err := ariesmongodbstorage.NewProvider(databaseURL,
+			ariesmongodbstorage.WithDBPrefix(databasePrefix),
+			ariesmongodbstorage.WithLogger(logger),
+			ariesmongodbstorage.WithTimeout(timeout))
+		if err != nil {
+			return nil, fmt.Errorf("create MongoDB storage provider: %w", err)
+		}
+
+		return &storageProvider{wrapper.NewProvider(mongoDBProvider, "MongoDB"), databaseTypeMongoDBOption}, nil
+	default:
+		return nil, fmt.Errorf("database type not set to a valid type." +
+			" run start --help to see the available options")
+	}
+}
+
 func getOrInit(cfg storage.Store, keyID string, v interface{}, initFn func() (interface{}, error),
 	timeout uint64) error {
 	src, err := cfg.Get(keyID)
@@ -1289,13 +2733,23 @@ func getActivityPubPublicKey(pubKey []byte, apServiceIRI, apServicePublicKeyIRI
 		Bytes: pubDerKey,
 	})
 
+	multibaseKey, err := multikey.EncodeEd25519(pubKey)
+	if err != nil {
+		return nil, fmt.Errorf("encode multibase pub key: %w", err)
+	}
+
 	return vocab.NewPublicKey(
 		vocab.WithID(apServicePublicKeyIRI),
 		vocab.WithOwner(apServiceIRI),
 		vocab.WithPublicKeyPem(string(pemBytes)),
+		vocab.WithPublicKeyMultibase(multibaseKey),
 	), nil
 }
 
+type operationProcessor interface {
+	Resolve(uniqueSuffix string, additionalOps ...*operation.AnchoredOperation) (*protocol.ResolutionModel, error)
+}
+
 type signer interface {
 	SignRequest(pubKeyID string, req *http.Request) error
 }
@@ -1304,11 +2758,16 @@ type signatureVerifier interface {
 	VerifyRequest(req *http.Request) (bool, *url.URL, error)
 }
 
-func getActivityPubSigners(parameters *orbParameters, km kms.KeyManager,
-	cr acrypto.Crypto) (getSigner signer, postSigner signer) {
+// getActivityPubSigners returns the signers of outbound ActivityPub requests along with the signers whose key
+// must be updated when the HTTP signature key is rotated.
+func getActivityPubSigners(parameters *orbParameters, key *signingKey) (getSigner signer, postSigner signer,
+	keyIDSetters []keyIDSetter) {
 	if parameters.httpSignaturesEnabled {
-		getSigner = httpsig.NewSigner(httpsig.DefaultGetSignerConfig(), cr, km, parameters.keyID)
-		postSigner = httpsig.NewSigner(httpsig.DefaultPostSignerConfig(), cr, km, parameters.keyID)
+		httpGetSigner := httpsig.NewSigner(httpsig.DefaultGetSignerConfig(), key.cr, key.km, key.keyID)
+		httpPostSigner := httpsig.NewSigner(httpsig.DefaultPostSignerConfig(), key.cr, key.km, key.keyID)
+
+		getSigner, postSigner = httpGetSigner, httpPostSigner
+		keyIDSetters = []keyIDSetter{httpGetSigner, httpPostSigner}
 	} else {
 		getSigner = &transport.NoOpSigner{}
 		postSigner = &transport.NoOpSigner{}
@@ -1317,10 +2776,17 @@ func getActivityPubSigners(parameters *orbParameters, km kms.KeyManager,
 	return
 }
 
+type actorRetriever interface {
+	GetPublicKey(keyIRI *url.URL) (*vocab.PublicKeyType, error)
+	GetActor(actorIRI *url.URL) (*vocab.ActorType, error)
+	InvalidateActor(actorIRI *url.URL)
+	InvalidatePublicKey(keyIRI *url.URL)
+}
+
 func getActivityPubVerifier(parameters *orbParameters, km kms.KeyManager,
-	cr acrypto.Crypto, apClient *client.Client) signatureVerifier {
+	cr acrypto.Crypto, retriever actorRetriever) signatureVerifier {
 	if parameters.httpSignaturesEnabled {
-		return httpsig.NewVerifier(apClient, cr, km)
+		return httpsig.NewVerifier(retriever, cr, km)
 	}
 
 	logger.Warnf("HTTP signature verification for ActivityPub is disabled.")
@@ -1350,6 +2816,251 @@ func (h *httpHandler) Handler() restcommon.HTTPRequestHandler {
 	return restcommon.HTTPRequestHandler(h.a.Handle())
 }
 
+// newDebugHandlers returns the profiling and execution trace handlers. All of the handlers require the admin token,
+// regardless of the authorization token definitions.
+func newDebugHandlers(authTokens map[string]string) ([]restcommon.HTTPHandler, error) {
+	tm, err := newAdminTokenManager("^"+debug.BasePath+"/.*", authTokens)
+	if err != nil {
+		return nil, err
+	}
+
+	var handlers []restcommon.HTTPHandler
+
+	for _, handler := range debug.NewHandlers() {
+		handlers = append(handlers, auth.NewHandlerWrapper(handler, tm))
+	}
+
+	return handlers, nil
+}
+
+// newArchiveHandler returns the handler that exports the signed activity archive. The handler requires the
+// admin token, regardless of the authorization token definitions.
+func newArchiveHandler(authTokens map[string]string, exporter *archive.Exporter) (restcommon.HTTPHandler, error) {
+	tm, err := newAdminTokenManager("^"+archive.Path+"$", authTokens)
+	if err != nil {
+		return nil, err
+	}
+
+	return auth.NewHandlerWrapper(archive.NewHandler(exporter), tm), nil
+}
+
+// newQuarantineHandlers returns the handlers that list, delete and reprocess the quarantined inbox requests.
+// The handlers require the admin token, regardless of the authorization token definitions.
+func newQuarantineHandlers(authTokens map[string]string, s *quarantine.Store,
+	inbox restcommon.HTTPRequestHandler) ([]restcommon.HTTPHandler, error) {
+	tm, err := newAdminTokenManager("^"+quarantine.Path+"(/.*)?$", authTokens)
+	if err != nil {
+		return nil, err
+	}
+
+	return []restcommon.HTTPHandler{
+		auth.NewHandlerWrapper(quarantine.NewList(s), tm),
+		auth.NewHandlerWrapper(quarantine.NewDelete(s), tm),
+		auth.NewHandlerWrapper(quarantine.NewReprocess(s, inbox), tm),
+	}, nil
+}
+
+// newAccessTokenHandlers returns the handlers that issue, list and revoke the access tokens of partner systems.
+// The handlers require the admin token, regardless of the authorization token definitions.
+func newAccessTokenHandlers(authTokens map[string]string, s *accesstoken.Store) ([]restcommon.HTTPHandler, error) {
+	tm, err := newAdminTokenManager("^"+accesstoken.Path+"(/.*)?$", authTokens)
+	if err != nil {
+		return nil, err
+	}
+
+	return []restcommon.HTTPHandler{
+		auth.NewHandlerWrapper(accesstoken.NewList(s), tm),
+		auth.NewHandlerWrapper(accesstoken.NewIssue(s, activityPubCollectionPaths()), tm),
+		auth.NewHandlerWrapper(accesstoken.NewRevoke(s), tm),
+	}, nil
+}
+
+// newInboxEvidenceHandler returns the handler that retrieves the raw inbox request of an activity. The handler
+// requires the admin token, regardless of the authorization token definitions.
+func newInboxEvidenceHandler(authTokens map[string]string, s *evidence.Store) (restcommon.HTTPHandler, error) {
+	tm, err := newAdminTokenManager("^"+evidence.Path+"$", authTokens)
+	if err != nil {
+		return nil, err
+	}
+
+	return auth.NewHandlerWrapper(evidence.NewHandler(s), tm), nil
+}
+
+// newInboxRejectionsHandler returns the handler that retrieves the recent inbox rejections. The handler
+// requires the admin token, regardless of the authorization token definitions.
+func newInboxRejectionsHandler(authTokens map[string]string,
+	r *rejection.Recorder) (restcommon.HTTPHandler, error) {
+	tm, err := newAdminTokenManager("^"+rejection.Path+"$", authTokens)
+	if err != nil {
+		return nil, err
+	}
+
+	return auth.NewHandlerWrapper(rejection.NewHandler(r), tm), nil
+}
+
+// newCompositionErrorsHandler returns the handler that retrieves the recent document composition errors. The
+// handler requires the admin token, regardless of the authorization token definitions.
+func newCompositionErrorsHandler(authTokens map[string]string,
+	r *composition.Recorder) (restcommon.HTTPHandler, error) {
+	tm, err := newAdminTokenManager("^"+composition.Path+"$", authTokens)
+	if err != nil {
+		return nil, err
+	}
+
+	return auth.NewHandlerWrapper(composition.NewHandler(r), tm), nil
+}
+
+// newQuotaUsageHandler returns the handler that retrieves the quota usage of the API keys. The handler
+// requires the admin token, regardless of the authorization token definitions.
+func newQuotaUsageHandler(authTokens map[string]string, m *quota.Manager) (restcommon.HTTPHandler, error) {
+	tm, err := newAdminTokenManager("^"+quota.UsagePath+"$", authTokens)
+	if err != nil {
+		return nil, err
+	}
+
+	return auth.NewHandlerWrapper(quota.NewUsageHandler(m), tm), nil
+}
+
+// newKeyPinHandlers returns the handlers that list the pinned actor keys and the key change alerts and allow a key
+// change to be approved. The handlers require the admin token, regardless of the authorization token definitions.
+func newKeyPinHandlers(authTokens map[string]string, m *keypin.Manager) ([]restcommon.HTTPHandler, error) {
+	tm, err := newAdminTokenManager("^"+keypin.Path+"(/.*)?$", authTokens)
+	if err != nil {
+		return nil, err
+	}
+
+	return []restcommon.HTTPHandler{
+		auth.NewHandlerWrapper(keypin.NewPins(m), tm),
+		auth.NewHandlerWrapper(keypin.NewAlerts(m), tm),
+		auth.NewHandlerWrapper(keypin.NewApprove(m), tm),
+	}, nil
+}
+
+// newContextPinHandlers returns the handlers that list the pinned JSON-LD contexts and allow a context to be pinned.
+// The handlers require the admin token, regardless of the authorization token definitions.
+func newContextPinHandlers(authTokens map[string]string, l *ldcache.Loader) ([]restcommon.HTTPHandler, error) {
+	tm, err := newAdminTokenManager("^"+ldcache.Path+"$", authTokens)
+	if err != nil {
+		return nil, err
+	}
+
+	return []restcommon.HTTPHandler{
+		auth.NewHandlerWrapper(ldcache.NewPins(l), tm),
+		auth.NewHandlerWrapper(ldcache.NewAddPin(l), tm),
+	}, nil
+}
+
+// newWitnessExpiryHandler returns the handler that lists the witness relationships nearing expiration. The handler
+// requires the admin token, regardless of the authorization token definitions.
+func newWitnessExpiryHandler(authTokens map[string]string,
+	m *witnessexpiry.Manager) (restcommon.HTTPHandler, error) {
+	tm, err := newAdminTokenManager("^"+witnessexpiry.Path+"$", authTokens)
+	if err != nil {
+		return nil, err
+	}
+
+	return auth.NewHandlerWrapper(witnessexpiry.NewHandler(m), tm), nil
+}
+
+// newProfileHandlers returns the handlers that retrieve and update the profile of the service actor. The
+// handlers require the admin token, regardless of the authorization token definitions.
+func newProfileHandlers(authTokens map[string]string,
+	actorProfile *profile.Manager) ([]restcommon.HTTPHandler, error) {
+	tm, err := newAdminTokenManager("^"+profile.Path+"$", authTokens)
+	if err != nil {
+		return nil, err
+	}
+
+	return []restcommon.HTTPHandler{
+		auth.NewHandlerWrapper(profile.NewReader(actorProfile), tm),
+		auth.NewHandlerWrapper(profile.NewWriter(actorProfile), tm),
+	}, nil
+}
+
+// newTaskHandlers returns the handlers that report the status of the background tasks and allow a task to be
+// triggered, paused or resumed. The handlers require the admin token, regardless of the authorization token
+// definitions.
+func newTaskHandlers(authTokens map[string]string, taskMgr *taskmgr.Manager) ([]restcommon.HTTPHandler, error) {
+	tm, err := newAdminTokenManager("^"+taskhandler.Path+"$", authTokens)
+	if err != nil {
+		return nil, err
+	}
+
+	return []restcommon.HTTPHandler{
+		auth.NewHandlerWrapper(taskhandler.NewReader(taskMgr), tm),
+		auth.NewHandlerWrapper(taskhandler.NewWriter(taskMgr), tm),
+	}, nil
+}
+
+// newMaintenanceHandlers returns the handlers that report the maintenance mode status of the node and allow the
+// node to be put into (or taken out of) maintenance mode. The handlers require the admin token, regardless of the
+// authorization token definitions.
+func newMaintenanceHandlers(authTokens map[string]string,
+	mode *maintenance.Mode) ([]restcommon.HTTPHandler, error) {
+	tm, err := newAdminTokenManager("^"+maintenancehandler.Path+"$", authTokens)
+	if err != nil {
+		return nil, err
+	}
+
+	return []restcommon.HTTPHandler{
+		auth.NewHandlerWrapper(maintenancehandler.NewReader(mode), tm),
+		auth.NewHandlerWrapper(maintenancehandler.NewWriter(mode), tm),
+	}, nil
+}
+
+// newLeaderHandler returns the handler that reports the leader election status. The handler requires the admin
+// token, regardless of the authorization token definitions.
+func newLeaderHandler(authTokens map[string]string,
+	elector *leaderelection.Elector) (restcommon.HTTPHandler, error) {
+	tm, err := newAdminTokenManager("^"+leaderhandler.Path+"$", authTokens)
+	if err != nil {
+		return nil, err
+	}
+
+	return auth.NewHandlerWrapper(leaderhandler.NewReader(elector), tm), nil
+}
+
+type followApprover interface {
+	ApproveFollow(followID *url.URL) error
+	RejectFollow(followID *url.URL) error
+}
+
+// newPendingFollowsHandlers returns the handlers that retrieve and approve/reject the 'Follow' requests that
+// are held for approval. The handlers require the admin token, regardless of the authorization token definitions.
+func newPendingFollowsHandlers(cfg *aphandler.Config, activityStore activitypubspi.Store,
+	verifier signatureVerifier, authTokens map[string]string,
+	approver followApprover) ([]restcommon.HTTPHandler, error) {
+	if authTokens[adminTokenID] == "" {
+		return nil, fmt.Errorf("an admin token is required for follow authorization policy [%s]",
+			acceptListHoldPolicy)
+	}
+
+	tm, err := newAdminTokenManager("^"+cfg.BasePath+aphandler.PendingFollowsPath+"$", authTokens)
+	if err != nil {
+		return nil, err
+	}
+
+	return []restcommon.HTTPHandler{
+		auth.NewHandlerWrapper(aphandler.NewPendingFollows(cfg, activityStore, verifier, tm), tm),
+		auth.NewHandlerWrapper(aphandler.NewPendingFollowsWriter(cfg, approver), tm),
+	}, nil
+}
+
+// newAdminTokenManager returns a token manager which requires the admin token for the endpoints
+// that match the given expression.
+func newAdminTokenManager(endpointExpression string, authTokens map[string]string) (*auth.TokenManager, error) {
+	return auth.NewTokenManager(auth.Config{
+		AuthTokensDef: []*auth.TokenDef{
+			{
+				EndpointExpression: endpointExpression,
+				ReadTokens:         []string{adminTokenID},
+				WriteTokens:        []string{adminTokenID},
+			},
+		},
+		AuthTokens: authTokens,
+	})
+}
+
 type ldStoreProvider struct {
 	ContextStore        ldstore.ContextStore
 	RemoteProviderStore ldstore.RemoteProviderStore
@@ -1387,6 +3098,8 @@ func NewAcceptRejectHandler(targetType string, policy acceptRejectPolicy, config
 	switch policy {
 	case acceptListPolicy:
 		return activityhandler.NewAcceptListAuthHandler(targetType, acceptlist.NewManager(configStore))
+	case acceptListHoldPolicy:
+		return activityhandler.NewAcceptListHoldAuthHandler(targetType, acceptlist.NewManager(configStore))
 	default:
 		return &activityhandler.AcceptAllActorsAuth{}
 	}
@@ -7,12 +7,38 @@ package startcmd
 
 import (
 	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
+	"time"
 
 	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
 	ariesmockstorage "github.com/hyperledger/aries-framework-go/component/storageutil/mock"
 	ariesspi "github.com/hyperledger/aries-framework-go/spi/storage"
 	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/archive"
+	apmocks "github.com/trustbloc/orb/pkg/activitypub/mocks"
+	"github.com/trustbloc/orb/pkg/activitypub/profile"
+	aphandler "github.com/trustbloc/orb/pkg/activitypub/resthandler"
+	"github.com/trustbloc/orb/pkg/activitypub/service/activityhandler"
+	"github.com/trustbloc/orb/pkg/activitypub/service/logallowlist"
+	logallowlisthandler "github.com/trustbloc/orb/pkg/activitypub/service/logallowlist/resthandler"
+	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/config/dynamic"
+	"github.com/trustbloc/orb/pkg/document/composition"
+	"github.com/trustbloc/orb/pkg/featureflag"
+	featureflaghandler "github.com/trustbloc/orb/pkg/featureflag/resthandler"
+	"github.com/trustbloc/orb/pkg/leaderelection"
+	leaderhandler "github.com/trustbloc/orb/pkg/leaderelection/resthandler"
+	"github.com/trustbloc/orb/pkg/maintenance"
+	maintenancehandler "github.com/trustbloc/orb/pkg/maintenance/resthandler"
+	"github.com/trustbloc/orb/pkg/store/dualwrite"
+	"github.com/trustbloc/orb/pkg/store/readreplica"
+	"github.com/trustbloc/orb/pkg/taskmgr"
+	taskhandler "github.com/trustbloc/orb/pkg/taskmgr/resthandler"
 )
 
 func TestCreateProviders(t *testing.T) {
@@ -46,6 +72,45 @@ func TestCreateProviders(t *testing.T) {
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "database type not set to a valid type")
 	})
+	t.Run("test dual-write mode", func(t *testing.T) {
+		providers, err := createStoreProviders(&orbParameters{
+			dbParameters: &dbParameters{
+				databaseType:           databaseTypeMemOption,
+				kmsSecretsDatabaseType: databaseTypeMemOption,
+				legacyDatabaseType:     databaseTypeMemOption,
+			},
+		})
+		require.NoError(t, err)
+		require.NotNil(t, providers.dualWriteVerifier)
+		require.Equal(t, databaseTypeMemOption, providers.provider.dbType)
+
+		_, ok := providers.provider.Provider.(*dualwrite.Provider)
+		require.True(t, ok)
+	})
+	t.Run("test invalid legacy database type", func(t *testing.T) {
+		_, err := createStoreProviders(&orbParameters{
+			dbParameters: &dbParameters{
+				databaseType:       databaseTypeMemOption,
+				legacyDatabaseType: "data1",
+			},
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "legacy database: database type not set to a valid type")
+	})
+	t.Run("test read database", func(t *testing.T) {
+		providers, err := createStoreProviders(&orbParameters{
+			dbParameters: &dbParameters{
+				databaseType:           databaseTypeMemOption,
+				kmsSecretsDatabaseType: databaseTypeMemOption,
+				readDatabaseURL:        "mem",
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, databaseTypeMemOption, providers.provider.dbType)
+
+		_, ok := providers.provider.Provider.(*readreplica.Provider)
+		require.True(t, ok)
+	})
 }
 
 func TestCreateKMSAndCrypto(t *testing.T) {
@@ -138,3 +203,271 @@ func TestPrepareMasterKeyReader(t *testing.T) {
 		require.Contains(t, err.Error(), "open key.file: no such file or directory")
 	})
 }
+
+func TestNewDebugHandlers(t *testing.T) {
+	handlers, err := newDebugHandlers(map[string]string{adminTokenID: "ADMIN_TOKEN"})
+	require.NoError(t, err)
+	require.NotEmpty(t, handlers)
+
+	for _, h := range handlers {
+		rw := httptest.NewRecorder()
+
+		h.Handler()(rw, httptest.NewRequest(h.Method(), "/debug/pprof/", nil))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusUnauthorized, result.StatusCode, "admin token should be required")
+		require.NoError(t, result.Body.Close())
+	}
+}
+
+func TestNewPendingFollowsHandlers(t *testing.T) {
+	cfg := &aphandler.Config{BasePath: activityPubServicesPath}
+
+	t.Run("Success", func(t *testing.T) {
+		handlers, err := newPendingFollowsHandlers(cfg, memstore.New(""), &noOpVerifier{},
+			map[string]string{adminTokenID: "ADMIN_TOKEN"}, &mockFollowApprover{})
+		require.NoError(t, err)
+		require.Len(t, handlers, 2)
+
+		for _, h := range handlers {
+			require.Equal(t, activityPubServicesPath+aphandler.PendingFollowsPath, h.Path())
+
+			rw := httptest.NewRecorder()
+
+			h.Handler()(rw, httptest.NewRequest(h.Method(), h.Path(), nil))
+
+			result := rw.Result()
+			require.Equal(t, http.StatusUnauthorized, result.StatusCode, "admin token should be required")
+			require.NoError(t, result.Body.Close())
+		}
+	})
+
+	t.Run("No admin token", func(t *testing.T) {
+		_, err := newPendingFollowsHandlers(cfg, memstore.New(""), &noOpVerifier{}, nil, &mockFollowApprover{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "an admin token is required")
+	})
+}
+
+func TestNewAcceptRejectHandler(t *testing.T) {
+	h := NewAcceptRejectHandler(activityhandler.FollowType, acceptListHoldPolicy, &ariesmockstorage.Store{})
+	require.IsType(t, &activityhandler.AcceptListHoldAuthHandler{}, h)
+
+	h = NewAcceptRejectHandler(activityhandler.FollowType, acceptListPolicy, &ariesmockstorage.Store{})
+	require.IsType(t, &activityhandler.AcceptListAuthHandler{}, h)
+
+	h = NewAcceptRejectHandler(activityhandler.FollowType, acceptAllPolicy, &ariesmockstorage.Store{})
+	require.IsType(t, &activityhandler.AcceptAllActorsAuth{}, h)
+}
+
+func TestNewTaskHandlers(t *testing.T) {
+	handlers, err := newTaskHandlers(map[string]string{adminTokenID: "ADMIN_TOKEN"},
+		taskmgr.New(&ariesmockstorage.Store{}, time.Second))
+	require.NoError(t, err)
+	require.Len(t, handlers, 2)
+
+	for _, h := range handlers {
+		require.Equal(t, taskhandler.Path, h.Path())
+
+		rw := httptest.NewRecorder()
+
+		h.Handler()(rw, httptest.NewRequest(h.Method(), h.Path(), nil))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusUnauthorized, result.StatusCode, "admin token should be required")
+		require.NoError(t, result.Body.Close())
+	}
+}
+
+func TestNewMaintenanceHandlers(t *testing.T) {
+	handlers, err := newMaintenanceHandlers(map[string]string{adminTokenID: "ADMIN_TOKEN"},
+		maintenance.New(time.Minute))
+	require.NoError(t, err)
+	require.Len(t, handlers, 2)
+
+	for _, h := range handlers {
+		require.Equal(t, maintenancehandler.Path, h.Path())
+
+		rw := httptest.NewRecorder()
+
+		h.Handler()(rw, httptest.NewRequest(h.Method(), h.Path(), nil))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusUnauthorized, result.StatusCode, "admin token should be required")
+		require.NoError(t, result.Body.Close())
+	}
+}
+
+func TestNewProfileHandlers(t *testing.T) {
+	configStore, err := mem.NewProvider().OpenStore("orb-config")
+	require.NoError(t, err)
+
+	dynamicConfig := dynamic.New(configStore)
+
+	servicesHandler := aphandler.NewServices(&aphandler.Config{
+		BasePath:  "/services/orb",
+		ObjectIRI: vocab.MustParseURL("https://orb.domain1.com/services/orb"),
+	}, nil, nil, &apmocks.AuthTokenMgr{})
+
+	actorProfile, err := newActorProfile(dynamicConfig, servicesHandler)
+	require.NoError(t, err)
+
+	handlers, err := newProfileHandlers(map[string]string{adminTokenID: "ADMIN_TOKEN"}, actorProfile)
+	require.NoError(t, err)
+	require.Len(t, handlers, 2)
+
+	for _, h := range handlers {
+		require.Equal(t, profile.Path, h.Path())
+
+		rw := httptest.NewRecorder()
+
+		h.Handler()(rw, httptest.NewRequest(h.Method(), h.Path(), nil))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusUnauthorized, result.StatusCode, "admin token should be required")
+		require.NoError(t, result.Body.Close())
+	}
+
+	_, err = newActorProfile(dynamicConfig, servicesHandler)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "already registered")
+}
+
+func TestNewFeatureFlagHandlers(t *testing.T) {
+	configStore, err := mem.NewProvider().OpenStore("orb-config")
+	require.NoError(t, err)
+
+	featureFlags, err := newFeatureFlags(configStore)
+	require.NoError(t, err)
+	require.True(t, featureFlags.Enabled(featureflag.MultikeyPublicKey))
+
+	handlers, err := newFeatureFlagHandlers(map[string]string{adminTokenID: "ADMIN_TOKEN"}, featureFlags)
+	require.NoError(t, err)
+	require.Len(t, handlers, 2)
+
+	for _, h := range handlers {
+		require.Equal(t, featureflaghandler.Path, h.Path())
+
+		rw := httptest.NewRecorder()
+
+		h.Handler()(rw, httptest.NewRequest(h.Method(), h.Path(), nil))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusUnauthorized, result.StatusCode, "admin token should be required")
+		require.NoError(t, result.Body.Close())
+	}
+}
+
+func TestNewLogAllowListHandlers(t *testing.T) {
+	configStore, err := mem.NewProvider().OpenStore("orb-config")
+	require.NoError(t, err)
+
+	handlers, err := newLogAllowListHandlers(map[string]string{adminTokenID: "ADMIN_TOKEN"},
+		logallowlist.NewManager(configStore))
+	require.NoError(t, err)
+	require.Len(t, handlers, 2)
+
+	for _, h := range handlers {
+		require.Equal(t, logallowlisthandler.Path, h.Path())
+
+		rw := httptest.NewRecorder()
+
+		h.Handler()(rw, httptest.NewRequest(h.Method(), h.Path(), nil))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusUnauthorized, result.StatusCode, "admin token should be required")
+		require.NoError(t, result.Body.Close())
+	}
+}
+
+func TestAddLocalVCTLog(t *testing.T) {
+	const vctURL = "https://vct.example.com/maple2021"
+
+	configStore, err := mem.NewProvider().OpenStore("orb-config")
+	require.NoError(t, err)
+
+	l := logallowlist.NewManager(configStore)
+
+	require.NoError(t, addLocalVCTLog(l, ""))
+
+	require.NoError(t, addLocalVCTLog(l, vctURL))
+
+	logs, err := l.GetAll()
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	require.Equal(t, vctURL, logs[0].URL)
+
+	require.NoError(t, l.Update([]*logallowlist.Log{{URL: vctURL, PublicKey: "cHVia2V5"}}, nil))
+
+	// An existing entry should not be replaced.
+	require.NoError(t, addLocalVCTLog(l, vctURL))
+
+	entry, err := l.Get(vctURL)
+	require.NoError(t, err)
+	require.Equal(t, "cHVia2V5", entry.PublicKey)
+
+	require.Error(t, addLocalVCTLog(l, "vct.example.com"))
+}
+
+func TestNewLeaderHandler(t *testing.T) {
+	h, err := newLeaderHandler(map[string]string{adminTokenID: "ADMIN_TOKEN"},
+		leaderelection.New(leaderElectionName, "instance1", &ariesmockstorage.Store{}))
+	require.NoError(t, err)
+	require.Equal(t, leaderhandler.Path, h.Path())
+
+	rw := httptest.NewRecorder()
+
+	h.Handler()(rw, httptest.NewRequest(h.Method(), h.Path(), nil))
+
+	result := rw.Result()
+	require.Equal(t, http.StatusUnauthorized, result.StatusCode, "admin token should be required")
+	require.NoError(t, result.Body.Close())
+}
+
+func TestNewArchiveHandler(t *testing.T) {
+	h, err := newArchiveHandler(map[string]string{adminTokenID: "ADMIN_TOKEN"},
+		archive.NewExporter(&archive.Config{}, nil, nil, nil))
+	require.NoError(t, err)
+	require.Equal(t, archive.Path, h.Path())
+
+	rw := httptest.NewRecorder()
+
+	h.Handler()(rw, httptest.NewRequest(h.Method(), archive.Path+"?from=2022-06-01T00:00:00Z", nil))
+
+	result := rw.Result()
+	require.Equal(t, http.StatusUnauthorized, result.StatusCode, "admin token should be required")
+	require.NoError(t, result.Body.Close())
+}
+
+func TestNewCompositionErrorsHandler(t *testing.T) {
+	h, err := newCompositionErrorsHandler(map[string]string{adminTokenID: "ADMIN_TOKEN"}, composition.NewRecorder(0))
+	require.NoError(t, err)
+	require.Equal(t, composition.Path, h.Path())
+
+	rw := httptest.NewRecorder()
+
+	h.Handler()(rw, httptest.NewRequest(h.Method(), composition.Path, nil))
+
+	result := rw.Result()
+	require.Equal(t, http.StatusUnauthorized, result.StatusCode, "admin token should be required")
+	require.NoError(t, result.Body.Close())
+}
+
+func TestNewCapabilities(t *testing.T) {
+	c := newCapabilities(&orbParameters{sidetreeProtocolVersions: []string{"1.0"}})
+	require.True(t, c.SupportsSignatureSuite("JsonWebSignature2020"))
+	require.True(t, c.SupportsLinksetFormat("application/linkset+json"))
+	require.True(t, c.SupportsProtocolVersion("1.0"))
+	require.False(t, c.SupportsProtocolVersion("2.0"))
+	require.True(t, c.SupportsContentEncoding("gzip"))
+}
+
+type mockFollowApprover struct{}
+
+func (m *mockFollowApprover) ApproveFollow(*url.URL) error {
+	return nil
+}
+
+func (m *mockFollowApprover) RejectFollow(*url.URL) error {
+	return nil
+}
@@ -0,0 +1,134 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package startcmd
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/trustbloc/orb/pkg/activitypub/service/activityhandler"
+	"github.com/trustbloc/orb/pkg/tenant"
+)
+
+type acceptListUpdater interface {
+	Update(acceptType string, additions, deletions []*url.URL) error
+}
+
+// forTenant returns a copy of the parameters with the tenant's configuration applied. The anchor credential
+// parameters that default to the server's external endpoint are changed to the tenant's external endpoint.
+func (p *orbParameters) forTenant(t *tenant.Config) (*orbParameters, error) {
+	tp := *p
+
+	tp.tenants = nil
+	tp.externalEndpoint = t.ExternalEndpoint
+
+	// The tenant's key is created in (or imported into) the tenant's key store.
+	tp.keyID = t.KeyID
+	tp.privateKeyBase64 = t.PrivateKey
+
+	acp := *p.anchorCredentialParams
+
+	if acp.issuer == p.externalEndpoint {
+		acp.issuer = t.ExternalEndpoint
+	}
+
+	if acp.domain == p.externalEndpoint {
+		acp.domain = t.ExternalEndpoint
+	}
+
+	if acp.url == fmt.Sprintf("%s/vc", p.externalEndpoint) {
+		acp.url = fmt.Sprintf("%s/vc", t.ExternalEndpoint)
+	}
+
+	tp.anchorCredentialParams = &acp
+
+	if t.FollowAuthPolicy != "" {
+		policy := acceptRejectPolicy(t.FollowAuthPolicy)
+
+		if policy != acceptAllPolicy && policy != acceptListPolicy && policy != acceptListHoldPolicy {
+			return nil, fmt.Errorf("unsupported follow authorization policy: %s", policy)
+		}
+
+		tp.followAuthPolicy = policy
+	}
+
+	if t.InviteWitnessAuthPolicy != "" {
+		policy := acceptRejectPolicy(t.InviteWitnessAuthPolicy)
+
+		if policy != acceptAllPolicy && policy != acceptListPolicy {
+			return nil, fmt.Errorf("unsupported invite witness authorization policy: %s", policy)
+		}
+
+		tp.inviteWitnessAuthPolicy = policy
+	}
+
+	var err error
+
+	tp.followAcceptList, err = parseURLs(t.FollowAcceptList)
+	if err != nil {
+		return nil, fmt.Errorf("follow accept list: %w", err)
+	}
+
+	tp.inviteWitnessAcceptList, err = parseURLs(t.InviteWitnessAcceptList)
+	if err != nil {
+		return nil, fmt.Errorf("invite witness accept list: %w", err)
+	}
+
+	return &tp, nil
+}
+
+// forTenant returns storage providers whose store names are prefixed with the tenant ID.
+func (p *storageProviders) forTenant(tenantID string) *storageProviders {
+	tp := &storageProviders{
+		provider: &storageProvider{
+			Provider: tenant.NewStoreProvider(p.provider.Provider, tenantID),
+			dbType:   p.provider.dbType,
+		},
+		dualWriteVerifier: p.dualWriteVerifier,
+	}
+
+	if p.kmsSecretsProvider != nil {
+		tp.kmsSecretsProvider = tenant.NewStoreProvider(p.kmsSecretsProvider, tenantID)
+	}
+
+	return tp
+}
+
+// updateAcceptLists adds the configured service IRIs (if any) to the 'follow' and 'invite-witness' accept lists.
+func updateAcceptLists(mgr acceptListUpdater, parameters *orbParameters) error {
+	if len(parameters.followAcceptList) > 0 {
+		err := mgr.Update(activityhandler.FollowType, parameters.followAcceptList, nil)
+		if err != nil {
+			return fmt.Errorf("update follow accept list: %w", err)
+		}
+	}
+
+	if len(parameters.inviteWitnessAcceptList) > 0 {
+		err := mgr.Update(activityhandler.InviteWitnessType, parameters.inviteWitnessAcceptList, nil)
+		if err != nil {
+			return fmt.Errorf("update invite witness accept list: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func parseURLs(values []string) ([]*url.URL, error) {
+	var urls []*url.URL
+
+	for _, v := range values {
+		u, err := url.Parse(strings.TrimSpace(v))
+		if err != nil {
+			return nil, fmt.Errorf("parse URL [%s]: %w", v, err)
+		}
+
+		urls = append(urls, u)
+	}
+
+	return urls, nil
+}
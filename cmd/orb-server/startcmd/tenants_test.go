@@ -0,0 +1,204 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package startcmd
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+
+	ariesmemstorage "github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/service/acceptlist"
+	"github.com/trustbloc/orb/pkg/activitypub/service/activityhandler"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/tenant"
+)
+
+func TestOrbParameters_ForTenant(t *testing.T) {
+	const externalEndpoint = "https://orb.example.com"
+
+	newParameters := func() *orbParameters {
+		return &orbParameters{
+			externalEndpoint: externalEndpoint,
+			keyID:            "server-key",
+			privateKeyBase64: "server-private-key",
+			anchorCredentialParams: &anchorCredentialParams{
+				issuer:         externalEndpoint,
+				domain:         "https://custom.example.com",
+				url:            externalEndpoint + "/vc",
+				signatureSuite: "Ed25519Signature2018",
+			},
+			followAuthPolicy:        acceptAllPolicy,
+			inviteWitnessAuthPolicy: acceptAllPolicy,
+			tenants:                 []*tenant.Config{{ID: "tenant1"}},
+		}
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		parameters := newParameters()
+
+		tp, err := parameters.forTenant(&tenant.Config{
+			ID:                      "tenant1",
+			Host:                    "tenant1.example.com",
+			ExternalEndpoint:        "https://tenant1.example.com",
+			KeyID:                   "tenant1-key",
+			PrivateKey:              "tenant1-private-key",
+			FollowAuthPolicy:        string(acceptListHoldPolicy),
+			InviteWitnessAuthPolicy: string(acceptListPolicy),
+			FollowAcceptList:        []string{"https://orb.domain1.com/services/orb"},
+			InviteWitnessAcceptList: []string{"https://orb.domain2.com/services/orb"},
+		})
+		require.NoError(t, err)
+
+		require.Nil(t, tp.tenants)
+		require.Equal(t, "https://tenant1.example.com", tp.externalEndpoint)
+		require.Equal(t, "tenant1-key", tp.keyID)
+		require.Equal(t, "tenant1-private-key", tp.privateKeyBase64)
+		require.Equal(t, "https://tenant1.example.com", tp.anchorCredentialParams.issuer)
+		require.Equal(t, "https://custom.example.com", tp.anchorCredentialParams.domain)
+		require.Equal(t, "https://tenant1.example.com/vc", tp.anchorCredentialParams.url)
+		require.Equal(t, "Ed25519Signature2018", tp.anchorCredentialParams.signatureSuite)
+		require.Equal(t, acceptListHoldPolicy, tp.followAuthPolicy)
+		require.Equal(t, acceptListPolicy, tp.inviteWitnessAuthPolicy)
+		require.Len(t, tp.followAcceptList, 1)
+		require.Equal(t, "https://orb.domain1.com/services/orb", tp.followAcceptList[0].String())
+		require.Len(t, tp.inviteWitnessAcceptList, 1)
+		require.Equal(t, "https://orb.domain2.com/services/orb", tp.inviteWitnessAcceptList[0].String())
+
+		// The server's parameters must not be modified.
+		require.Equal(t, externalEndpoint, parameters.externalEndpoint)
+		require.Equal(t, "server-key", parameters.keyID)
+		require.Equal(t, externalEndpoint, parameters.anchorCredentialParams.issuer)
+		require.Equal(t, acceptAllPolicy, parameters.followAuthPolicy)
+		require.Len(t, parameters.tenants, 1)
+	})
+
+	t.Run("Default policies and generated key", func(t *testing.T) {
+		tp, err := newParameters().forTenant(&tenant.Config{
+			ID:               "tenant1",
+			BasePath:         "/tenant1",
+			ExternalEndpoint: "https://tenant1.example.com",
+		})
+		require.NoError(t, err)
+
+		require.Empty(t, tp.keyID)
+		require.Empty(t, tp.privateKeyBase64)
+		require.Equal(t, acceptAllPolicy, tp.followAuthPolicy)
+		require.Equal(t, acceptAllPolicy, tp.inviteWitnessAuthPolicy)
+		require.Empty(t, tp.followAcceptList)
+		require.Empty(t, tp.inviteWitnessAcceptList)
+	})
+
+	t.Run("Invalid follow policy", func(t *testing.T) {
+		_, err := newParameters().forTenant(&tenant.Config{
+			ID:               "tenant1",
+			ExternalEndpoint: "https://tenant1.example.com",
+			FollowAuthPolicy: "xxx",
+		})
+		require.EqualError(t, err, "unsupported follow authorization policy: xxx")
+	})
+
+	t.Run("Invalid invite witness policy", func(t *testing.T) {
+		_, err := newParameters().forTenant(&tenant.Config{
+			ID:                      "tenant1",
+			ExternalEndpoint:        "https://tenant1.example.com",
+			InviteWitnessAuthPolicy: string(acceptListHoldPolicy),
+		})
+		require.EqualError(t, err, "unsupported invite witness authorization policy: accept-list-hold")
+	})
+
+	t.Run("Invalid accept list", func(t *testing.T) {
+		_, err := newParameters().forTenant(&tenant.Config{
+			ID:               "tenant1",
+			ExternalEndpoint: "https://tenant1.example.com",
+			FollowAcceptList: []string{":invalid"},
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "follow accept list")
+
+		_, err = newParameters().forTenant(&tenant.Config{
+			ID:                      "tenant1",
+			ExternalEndpoint:        "https://tenant1.example.com",
+			InviteWitnessAcceptList: []string{":invalid"},
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invite witness accept list")
+	})
+}
+
+func TestStorageProviders_ForTenant(t *testing.T) {
+	t.Run("Local KMS", func(t *testing.T) {
+		p := &storageProviders{
+			provider:           &storageProvider{ariesmemstorage.NewProvider(), databaseTypeMemOption},
+			kmsSecretsProvider: ariesmemstorage.NewProvider(),
+		}
+
+		tp := p.forTenant("tenant1")
+		require.Equal(t, databaseTypeMemOption, tp.provider.dbType)
+		require.IsType(t, &tenant.StoreProvider{}, tp.provider.Provider)
+		require.IsType(t, &tenant.StoreProvider{}, tp.kmsSecretsProvider)
+	})
+
+	t.Run("Remote KMS", func(t *testing.T) {
+		p := &storageProviders{
+			provider: &storageProvider{ariesmemstorage.NewProvider(), databaseTypeMemOption},
+		}
+
+		require.Nil(t, p.forTenant("tenant1").kmsSecretsProvider)
+	})
+}
+
+func TestUpdateAcceptLists(t *testing.T) {
+	service1IRI := vocab.MustParseURL("https://orb.domain1.com/services/orb")
+	service2IRI := vocab.MustParseURL("https://orb.domain2.com/services/orb")
+
+	t.Run("Success", func(t *testing.T) {
+		store, err := ariesmemstorage.NewProvider().OpenStore("config")
+		require.NoError(t, err)
+
+		mgr := acceptlist.NewManager(store)
+
+		require.NoError(t, updateAcceptLists(mgr, &orbParameters{
+			followAcceptList:        []*url.URL{service1IRI},
+			inviteWitnessAcceptList: []*url.URL{service2IRI},
+		}))
+
+		follow, err := mgr.Get(activityhandler.FollowType)
+		require.NoError(t, err)
+		require.Equal(t, []*url.URL{service1IRI}, follow)
+
+		inviteWitness, err := mgr.Get(activityhandler.InviteWitnessType)
+		require.NoError(t, err)
+		require.Equal(t, []*url.URL{service2IRI}, inviteWitness)
+	})
+
+	t.Run("Nothing to update", func(t *testing.T) {
+		require.NoError(t, updateAcceptLists(&mockAcceptListUpdater{err: errors.New("unexpected")}, &orbParameters{}))
+	})
+
+	t.Run("Update error", func(t *testing.T) {
+		errExpected := errors.New("injected update error")
+
+		err := updateAcceptLists(&mockAcceptListUpdater{err: errExpected},
+			&orbParameters{followAcceptList: []*url.URL{service1IRI}})
+		require.True(t, errors.Is(err, errExpected))
+
+		err = updateAcceptLists(&mockAcceptListUpdater{err: errExpected},
+			&orbParameters{inviteWitnessAcceptList: []*url.URL{service2IRI}})
+		require.True(t, errors.Is(err, errExpected))
+	})
+}
+
+type mockAcceptListUpdater struct {
+	err error
+}
+
+func (m *mockAcceptListUpdater) Update(string, []*url.URL, []*url.URL) error {
+	return m.err
+}
@@ -0,0 +1,235 @@
+//go:build js && wasm
+// +build js,wasm
+
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package main is the WASM build of the Orb client-side verifier. It exposes the following functions on the
+// global 'orb' object, each of which returns a Promise:
+//
+//	orb.resolveLongFormDID(did)                           -> resolution result (JSON)
+//	orb.verifyResolutionResult(resolutionResultJSON)      -> undefined
+//	orb.verifyAnchorEvent(anchorEventJSON)                -> {credential, payload} (JSON)
+//
+// The verifier may be configured by setting the global 'orbConfig' object before the module is loaded, for
+// example: {namespace: "did:orb", anchorOrigins: ["https://orb.domain1.com"], methodContexts: [], enableBase: false}.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"syscall/js"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/ld"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	ldstore "github.com/hyperledger/aries-framework-go/pkg/store/ld"
+	"github.com/hyperledger/aries-framework-go/pkg/vdr"
+	vdrweb "github.com/hyperledger/aries-framework-go/pkg/vdr/web"
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
+
+	"github.com/trustbloc/orb/internal/pkg/ldcontext"
+	"github.com/trustbloc/orb/pkg/orbclient/verifier"
+)
+
+const defaultNamespace = "did:orb"
+
+type anchorResult struct {
+	Credential json.RawMessage `json:"credential"`
+	Payload    *anchorPayload  `json:"payload"`
+}
+
+type anchorPayload struct {
+	Namespace      string     `json:"namespace"`
+	Version        uint64     `json:"version"`
+	CoreIndex      string     `json:"coreIndex"`
+	OperationCount uint64     `json:"operationCount"`
+	AnchorOrigin   string     `json:"anchorOrigin,omitempty"`
+	Published      *time.Time `json:"published,omitempty"`
+}
+
+func main() {
+	v, err := newVerifier(js.Global().Get("orbConfig"))
+	if err != nil {
+		panic(fmt.Errorf("create verifier: %w", err))
+	}
+
+	orb := js.Global().Get("Object").New()
+
+	orb.Set("resolveLongFormDID", newPromiseFunc(func(arg string) (interface{}, error) {
+		result, e := v.ResolveLongFormDID(arg)
+		if e != nil {
+			return nil, e
+		}
+
+		return marshal(result)
+	}))
+
+	orb.Set("verifyResolutionResult", newPromiseFunc(func(arg string) (interface{}, error) {
+		result := &document.ResolutionResult{}
+
+		if e := json.Unmarshal([]byte(arg), result); e != nil {
+			return nil, fmt.Errorf("unmarshal resolution result: %w", e)
+		}
+
+		return js.Undefined(), v.VerifyResolutionResult(result)
+	}))
+
+	orb.Set("verifyAnchorEvent", newPromiseFunc(func(arg string) (interface{}, error) {
+		anchor, e := v.VerifyAnchorEvent([]byte(arg))
+		if e != nil {
+			return nil, e
+		}
+
+		vcBytes, e := anchor.Credential.MarshalJSON()
+		if e != nil {
+			return nil, fmt.Errorf("marshal credential: %w", e)
+		}
+
+		return marshal(&anchorResult{
+			Credential: vcBytes,
+			Payload: &anchorPayload{
+				Namespace:      anchor.Payload.Namespace,
+				Version:        anchor.Payload.Version,
+				CoreIndex:      anchor.Payload.CoreIndex,
+				OperationCount: anchor.Payload.OperationCount,
+				AnchorOrigin:   anchor.Payload.AnchorOrigin,
+				Published:      anchor.Payload.Published,
+			},
+		})
+	}))
+
+	js.Global().Set("orb", orb)
+
+	// Block forever so that the functions remain available to JavaScript.
+	select {}
+}
+
+func newVerifier(cfg js.Value) (*verifier.Verifier, error) {
+	docLoader, err := newDocumentLoader()
+	if err != nil {
+		return nil, fmt.Errorf("create document loader: %w", err)
+	}
+
+	namespace := defaultNamespace
+
+	opts := []verifier.Option{
+		verifier.WithJSONLDDocumentLoader(docLoader),
+		verifier.WithPublicKeyFetcher(
+			verifiable.NewVDRKeyResolver(vdr.New(vdr.WithVDR(vdrweb.New()))).PublicKeyFetcher(),
+		),
+	}
+
+	if cfg.Type() == js.TypeObject {
+		if ns := cfg.Get("namespace"); ns.Type() == js.TypeString {
+			namespace = ns.String()
+		}
+
+		opts = append(opts,
+			verifier.WithAnchorOrigins(toStrings(cfg.Get("anchorOrigins"))),
+			verifier.WithMethodContext(toStrings(cfg.Get("methodContexts"))),
+			verifier.WithEnableBase(cfg.Get("enableBase").Truthy()),
+		)
+	}
+
+	return verifier.New(namespace, opts...)
+}
+
+// newPromiseFunc returns a JavaScript function that invokes the given function with its first (string) argument
+// in a separate goroutine and returns a Promise. The function may not be invoked on the JavaScript event loop
+// since it may block (for example, when public keys are fetched over HTTP).
+func newPromiseFunc(fn func(arg string) (interface{}, error)) js.Func {
+	return js.FuncOf(func(_ js.Value, args []js.Value) interface{} {
+		handler := js.FuncOf(func(_ js.Value, promiseArgs []js.Value) interface{} {
+			resolve, reject := promiseArgs[0], promiseArgs[1]
+
+			go func() {
+				if len(args) == 0 || args[0].Type() != js.TypeString {
+					reject.Invoke(newJSError(errors.New("expecting a string argument")))
+
+					return
+				}
+
+				result, err := fn(args[0].String())
+				if err != nil {
+					reject.Invoke(newJSError(err))
+
+					return
+				}
+
+				resolve.Invoke(result)
+			}()
+
+			return nil
+		})
+
+		return js.Global().Get("Promise").New(handler)
+	})
+}
+
+func newJSError(err error) js.Value {
+	return js.Global().Get("Error").New(err.Error())
+}
+
+func marshal(obj interface{}) (interface{}, error) {
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return nil, fmt.Errorf("marshal result: %w", err)
+	}
+
+	return string(b), nil
+}
+
+func toStrings(arr js.Value) []string {
+	if arr.Type() != js.TypeObject {
+		return nil
+	}
+
+	values := make([]string, arr.Length())
+
+	for i := range values {
+		values[i] = arr.Index(i).String()
+	}
+
+	return values
+}
+
+type ldProvider struct {
+	contextStore        ldstore.ContextStore
+	remoteProviderStore ldstore.RemoteProviderStore
+}
+
+func (p *ldProvider) JSONLDContextStore() ldstore.ContextStore {
+	return p.contextStore
+}
+
+func (p *ldProvider) JSONLDRemoteProviderStore() ldstore.RemoteProviderStore {
+	return p.remoteProviderStore
+}
+
+// newDocumentLoader returns a JSON-LD document loader that's preloaded with the contexts that are embedded in
+// the Orb binary. The contexts are held in memory.
+func newDocumentLoader() (*ld.DocumentLoader, error) {
+	contextStore, err := ldstore.NewContextStore(mem.NewProvider())
+	if err != nil {
+		return nil, fmt.Errorf("create JSON-LD context store: %w", err)
+	}
+
+	remoteProviderStore, err := ldstore.NewRemoteProviderStore(mem.NewProvider())
+	if err != nil {
+		return nil, fmt.Errorf("create remote provider store: %w", err)
+	}
+
+	return ld.NewDocumentLoader(
+		&ldProvider{
+			contextStore:        contextStore,
+			remoteProviderStore: remoteProviderStore,
+		},
+		ld.WithExtraContexts(ldcontext.MustGetAll()...),
+	)
+}
@@ -24,6 +24,7 @@ require (
 	github.com/igor-pavlenko/httpsignatures-go v0.0.21
 	github.com/ipfs/go-cid v0.0.7
 	github.com/ipfs/go-ipfs-api v0.2.0
+	github.com/klauspost/compress v1.13.6
 	github.com/mr-tron/base58 v1.2.0
 	github.com/multiformats/go-multibase v0.0.3
 	github.com/multiformats/go-multihash v0.0.14
@@ -36,10 +37,14 @@ require (
 	github.com/trustbloc/edge-core v0.1.7
 	github.com/trustbloc/sidetree-core-go v0.7.1-0.20211229172717-b542d0074b38
 	github.com/trustbloc/vct v0.1.3
+	github.com/xeipuuv/gojsonschema v1.2.0
 	github.com/youmark/pkcs8 v0.0.0-20201027041543-1326539a0a0a // indirect
 	go.mongodb.org/mongo-driver v1.8.0
 	golang.org/x/crypto v0.0.0-20211202192323-5770296d904e // indirect
 	golang.org/x/text v0.3.7 // indirect
+	google.golang.org/grpc v1.39.0
+	google.golang.org/protobuf v1.27.1
+	gopkg.in/yaml.v2 v2.4.0
 )
 
 go 1.16
@@ -0,0 +1,282 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package accesstoken
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/store/expiry"
+)
+
+var logger = log.New("access-token")
+
+// ErrNotFound is returned when an access token isn't found.
+var ErrNotFound = errors.New("access token not found")
+
+const (
+	storeName = "access-token"
+
+	// tokenTag is added to every token so that all tokens may be queried.
+	tokenTag = "token"
+	// expiryTag holds the time (Unix time) after which the token is deleted.
+	expiryTag = "expiry"
+
+	secretSize = 32
+
+	// separator separates the token ID from the secret in the bearer token.
+	separator   = "."
+	bearerParts = 2
+)
+
+// Token contains the details of an access token that grants a partner read access to specific collections.
+// The secret of the token is never stored, only its hash.
+type Token struct {
+	ID          string    `json:"id"`
+	Partner     string    `json:"partner"`
+	Collections []string  `json:"collections"`
+	Issued      time.Time `json:"issued"`
+	Expires     time.Time `json:"expires"`
+}
+
+type tokenRecord struct {
+	*Token
+
+	SecretHash string `json:"secretHash"`
+}
+
+type expiryService interface {
+	Register(store storage.Store, expiryTagName, storeName string, opts ...expiry.Option)
+}
+
+// Store issues, verifies and revokes the access tokens that grant partner systems read access to specific
+// collections (for example, /witnesses) without sharing the global authorization tokens. Expired tokens are
+// deleted by the expiry service.
+type Store struct {
+	store   storage.Store
+	marshal func(v interface{}) ([]byte, error)
+}
+
+// NewStore returns a new access token store.
+func NewStore(provider storage.Provider, expiryService expiryService) (*Store, error) {
+	s, err := provider.OpenStore(storeName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open access token store: %w", err)
+	}
+
+	err = provider.SetStoreConfig(storeName, storage.StoreConfiguration{TagNames: []string{tokenTag, expiryTag}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set store configuration: %w", err)
+	}
+
+	expiryService.Register(s, expiryTag, storeName)
+
+	return &Store{
+		store:   s,
+		marshal: json.Marshal,
+	}, nil
+}
+
+// Issue issues a new token for the given partner which grants read access to the given collections until the
+// token expires. The token is returned along with the bearer value (which contains the secret) that must be
+// provided to the partner. The bearer value can't be retrieved afterwards.
+func (s *Store) Issue(partner string, collections []string, lifespan time.Duration) (*Token, string, error) {
+	secret, err := generateSecret()
+	if err != nil {
+		return nil, "", fmt.Errorf("generate secret: %w", err)
+	}
+
+	now := time.Now().UTC()
+
+	token := &Token{
+		ID:          uuid.New().String(),
+		Partner:     partner,
+		Collections: collections,
+		Issued:      now,
+		Expires:     now.Add(lifespan),
+	}
+
+	recordBytes, err := s.marshal(&tokenRecord{Token: token, SecretHash: hash(secret)})
+	if err != nil {
+		return nil, "", fmt.Errorf("marshal access token: %w", err)
+	}
+
+	err = s.store.Put(token.ID, recordBytes,
+		storage.Tag{Name: tokenTag},
+		storage.Tag{Name: expiryTag, Value: strconv.FormatInt(token.Expires.Unix(), 10)},
+	)
+	if err != nil {
+		return nil, "", orberrors.NewTransient(fmt.Errorf("store access token [%s]: %w", token.ID, err))
+	}
+
+	logger.Infof("Issued access token [%s] to partner [%s] for collections %s which expires at %s",
+		token.ID, partner, collections, token.Expires)
+
+	return token, token.ID + separator + secret, nil
+}
+
+// Get returns the token for the given ID or ErrNotFound if the token doesn't exist.
+func (s *Store) Get(id string) (*Token, error) {
+	record, err := s.get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return record.Token, nil
+}
+
+// GetAll returns all tokens, most recently issued first.
+func (s *Store) GetAll() ([]*Token, error) {
+	it, err := s.store.Query(tokenTag)
+	if err != nil {
+		return nil, orberrors.NewTransient(fmt.Errorf("query access tokens: %w", err))
+	}
+
+	defer func() {
+		if e := it.Close(); e != nil {
+			logger.Warnf("Error closing iterator: %s", e)
+		}
+	}()
+
+	var tokens []*Token
+
+	for {
+		ok, e := it.Next()
+		if e != nil {
+			return nil, orberrors.NewTransient(fmt.Errorf("next access token: %w", e))
+		}
+
+		if !ok {
+			break
+		}
+
+		value, e := it.Value()
+		if e != nil {
+			return nil, orberrors.NewTransient(fmt.Errorf("get access token from iterator: %w", e))
+		}
+
+		record := &tokenRecord{}
+
+		if e := json.Unmarshal(value, record); e != nil {
+			return nil, fmt.Errorf("unmarshal access token: %w", e)
+		}
+
+		tokens = append(tokens, record.Token)
+	}
+
+	sort.Slice(tokens, func(i, j int) bool {
+		return tokens[i].Issued.After(tokens[j].Issued)
+	})
+
+	return tokens, nil
+}
+
+// Revoke deletes the token with the given ID. ErrNotFound is returned if the token doesn't exist.
+func (s *Store) Revoke(id string) error {
+	if _, err := s.get(id); err != nil {
+		return err
+	}
+
+	if err := s.store.Delete(id); err != nil {
+		return orberrors.NewTransient(fmt.Errorf("delete access token [%s]: %w", id, err))
+	}
+
+	logger.Infof("Revoked access token [%s]", id)
+
+	return nil
+}
+
+// Verify returns true if the given bearer value belongs to an unexpired token that grants access to the given
+// collection (for example, /witnesses).
+func (s *Store) Verify(bearer, collection string) bool {
+	parts := strings.SplitN(bearer, separator, bearerParts)
+	if len(parts) != bearerParts {
+		return false
+	}
+
+	record, err := s.get(parts[0])
+	if err != nil {
+		if !errors.Is(err, ErrNotFound) {
+			logger.Warnf("Error retrieving access token [%s]: %s", parts[0], err)
+		}
+
+		return false
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hash(parts[1])), []byte(record.SecretHash)) != 1 {
+		logger.Debugf("Invalid secret for access token [%s]", record.ID)
+
+		return false
+	}
+
+	if time.Now().After(record.Expires) {
+		logger.Debugf("Access token [%s] expired at %s", record.ID, record.Expires)
+
+		return false
+	}
+
+	for _, c := range record.Collections {
+		if c == collection {
+			return true
+		}
+	}
+
+	logger.Debugf("Access token [%s] of partner [%s] doesn't grant access to collection [%s]",
+		record.ID, record.Partner, collection)
+
+	return false
+}
+
+func (s *Store) get(id string) (*tokenRecord, error) {
+	recordBytes, err := s.store.Get(id)
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return nil, ErrNotFound
+		}
+
+		return nil, orberrors.NewTransient(fmt.Errorf("get access token [%s]: %w", id, err))
+	}
+
+	record := &tokenRecord{}
+
+	err = json.Unmarshal(recordBytes, record)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal access token [%s]: %w", id, err)
+	}
+
+	return record, nil
+}
+
+func generateSecret() (string, error) {
+	b := make([]byte, secretSize)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+func hash(value string) string {
+	h := sha256.Sum256([]byte(value))
+
+	return hex.EncodeToString(h[:])
+}
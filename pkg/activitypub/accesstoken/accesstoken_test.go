@@ -0,0 +1,248 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package accesstoken
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/store/expiry"
+	"github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+const (
+	partner1    = "partner1"
+	witnesses   = "/witnesses"
+	followers   = "/followers"
+	witnessing  = "/witnessing"
+	invalidHash = "xxx"
+)
+
+func TestStore(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		es := &mockExpiryService{}
+
+		s, err := NewStore(mem.NewProvider(), es)
+		require.NoError(t, err)
+		require.Equal(t, storeName, es.storeName)
+		require.Equal(t, expiryTag, es.expiryTagName)
+
+		tokens, err := s.GetAll()
+		require.NoError(t, err)
+		require.Empty(t, tokens)
+
+		token1, bearer1, err := s.Issue(partner1, []string{witnesses}, time.Hour)
+		require.NoError(t, err)
+		require.NotEmpty(t, token1.ID)
+		require.Equal(t, partner1, token1.Partner)
+		require.Equal(t, []string{witnesses}, token1.Collections)
+		require.Equal(t, token1.Issued.Add(time.Hour), token1.Expires)
+		require.True(t, strings.HasPrefix(bearer1, token1.ID+separator))
+
+		time.Sleep(time.Millisecond)
+
+		token2, _, err := s.Issue("partner2", []string{followers, witnesses}, time.Hour)
+		require.NoError(t, err)
+
+		tokens, err = s.GetAll()
+		require.NoError(t, err)
+		require.Len(t, tokens, 2)
+		require.Equal(t, token2.ID, tokens[0].ID)
+		require.Equal(t, token1.ID, tokens[1].ID)
+
+		token, err := s.Get(token1.ID)
+		require.NoError(t, err)
+		require.Equal(t, partner1, token.Partner)
+
+		require.NoError(t, s.Revoke(token1.ID))
+
+		_, err = s.Get(token1.ID)
+		require.ErrorIs(t, err, ErrNotFound)
+
+		require.ErrorIs(t, s.Revoke(token1.ID), ErrNotFound)
+	})
+
+	t.Run("Open store error", func(t *testing.T) {
+		p := &mocks.Provider{}
+		p.OpenStoreReturns(nil, errors.New("injected open error"))
+
+		_, err := NewStore(p, &mockExpiryService{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected open error")
+	})
+
+	t.Run("Set store config error", func(t *testing.T) {
+		p := &mocks.Provider{}
+		p.SetStoreConfigReturns(errors.New("injected config error"))
+
+		_, err := NewStore(p, &mockExpiryService{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected config error")
+	})
+
+	t.Run("Store errors", func(t *testing.T) {
+		errExpected := errors.New("injected store error")
+
+		store := &mocks.Store{}
+		store.PutReturns(errExpected)
+		store.GetReturns(nil, errExpected)
+		store.QueryReturns(nil, errExpected)
+
+		p := &mocks.Provider{}
+		p.OpenStoreReturns(store, nil)
+
+		s, err := NewStore(p, &mockExpiryService{})
+		require.NoError(t, err)
+
+		_, _, err = s.Issue(partner1, []string{witnesses}, time.Hour)
+		require.ErrorIs(t, err, errExpected)
+
+		_, err = s.Get("id1")
+		require.ErrorIs(t, err, errExpected)
+
+		_, err = s.GetAll()
+		require.ErrorIs(t, err, errExpected)
+
+		require.ErrorIs(t, s.Revoke("id1"), errExpected)
+
+		store.GetReturns([]byte(`{"id":"id1"}`), nil)
+		store.DeleteReturns(errExpected)
+
+		require.ErrorIs(t, s.Revoke("id1"), errExpected)
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		s, err := NewStore(mem.NewProvider(), &mockExpiryService{})
+		require.NoError(t, err)
+
+		s.marshal = func(v interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		_, _, err = s.Issue(partner1, []string{witnesses}, time.Hour)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected marshal error")
+	})
+
+	t.Run("Iterator errors", func(t *testing.T) {
+		errExpected := errors.New("injected iterator error")
+
+		it := &mocks.Iterator{}
+		it.NextReturns(false, errExpected)
+
+		store := &mocks.Store{}
+		store.QueryReturns(it, nil)
+
+		p := &mocks.Provider{}
+		p.OpenStoreReturns(store, nil)
+
+		s, err := NewStore(p, &mockExpiryService{})
+		require.NoError(t, err)
+
+		_, err = s.GetAll()
+		require.ErrorIs(t, err, errExpected)
+
+		it.NextReturns(true, nil)
+		it.ValueReturns(nil, errExpected)
+
+		_, err = s.GetAll()
+		require.ErrorIs(t, err, errExpected)
+
+		it.ValueReturns([]byte("xxx"), nil)
+
+		_, err = s.GetAll()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal access token")
+	})
+
+	t.Run("Unmarshal error", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.GetReturns([]byte("xxx"), nil)
+
+		p := &mocks.Provider{}
+		p.OpenStoreReturns(store, nil)
+
+		s, err := NewStore(p, &mockExpiryService{})
+		require.NoError(t, err)
+
+		_, err = s.Get("id1")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal access token [id1]")
+	})
+}
+
+func TestStore_Verify(t *testing.T) {
+	s, err := NewStore(mem.NewProvider(), &mockExpiryService{})
+	require.NoError(t, err)
+
+	token, bearer, err := s.Issue(partner1, []string{witnesses, followers}, time.Hour)
+	require.NoError(t, err)
+
+	t.Run("Success", func(t *testing.T) {
+		require.True(t, s.Verify(bearer, witnesses))
+		require.True(t, s.Verify(bearer, followers))
+	})
+
+	t.Run("Collection not granted", func(t *testing.T) {
+		require.False(t, s.Verify(bearer, witnessing))
+	})
+
+	t.Run("Invalid secret", func(t *testing.T) {
+		require.False(t, s.Verify(token.ID+separator+invalidHash, witnesses))
+	})
+
+	t.Run("Invalid format", func(t *testing.T) {
+		require.False(t, s.Verify(token.ID, witnesses))
+	})
+
+	t.Run("Unknown token", func(t *testing.T) {
+		require.False(t, s.Verify("unknown"+separator+invalidHash, witnesses))
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		_, expiredBearer, err := s.Issue(partner1, []string{witnesses}, -time.Minute)
+		require.NoError(t, err)
+
+		require.False(t, s.Verify(expiredBearer, witnesses))
+	})
+
+	t.Run("Revoked", func(t *testing.T) {
+		id, revokedBearer, err := s.Issue(partner1, []string{witnesses}, time.Hour)
+		require.NoError(t, err)
+
+		require.True(t, s.Verify(revokedBearer, witnesses))
+		require.NoError(t, s.Revoke(id.ID))
+		require.False(t, s.Verify(revokedBearer, witnesses))
+	})
+
+	t.Run("Store error", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.GetReturns(nil, errors.New("injected store error"))
+
+		p := &mocks.Provider{}
+		p.OpenStoreReturns(store, nil)
+
+		s, err := NewStore(p, &mockExpiryService{})
+		require.NoError(t, err)
+
+		require.False(t, s.Verify(bearer, witnesses))
+	})
+}
+
+type mockExpiryService struct {
+	storeName     string
+	expiryTagName string
+}
+
+func (m *mockExpiryService) Register(_ storage.Store, expiryTagName, storeName string, _ ...expiry.Option) {
+	m.storeName = storeName
+	m.expiryTagName = expiryTagName
+}
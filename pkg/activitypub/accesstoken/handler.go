@@ -0,0 +1,289 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package accesstoken
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+)
+
+const (
+	// Path is the path of the access token endpoint.
+	Path = "/access-tokens"
+
+	idPathVariable = "id"
+
+	tokenPath = Path + "/{" + idPathVariable + "}"
+
+	// DefaultLifespan is the lifespan of a token if the lifespan isn't specified in the request.
+	DefaultLifespan = 30 * 24 * time.Hour
+	// MaxLifespan is the maximum lifespan of a token.
+	MaxLifespan = 365 * 24 * time.Hour
+
+	notFoundResponse            = "Not Found."
+	internalServerErrorResponse = "Internal Server Error."
+)
+
+// IssueRequest is the request to issue a new access token.
+type IssueRequest struct {
+	Partner string `json:"partner"`
+	// Collections contains the collections to which the token grants read access, for example ["witnesses"].
+	Collections []string `json:"collections"`
+	// ExpiresIn (optional) is the lifespan of the token, for example "720h". If not set then DefaultLifespan is used.
+	ExpiresIn string `json:"expiresIn,omitempty"`
+}
+
+// IssueResponse contains the issued token along with the bearer token that must be provided to the partner.
+// The partner includes the bearer token in the Authorization header, i.e. "Authorization: Bearer <accessToken>".
+type IssueResponse struct {
+	*Token
+
+	AccessToken string `json:"accessToken"`
+}
+
+type tokenStore interface {
+	Issue(partner string, collections []string, lifespan time.Duration) (*Token, string, error)
+	GetAll() ([]*Token, error)
+	Revoke(id string) error
+}
+
+// List implements a REST handler that returns the access tokens (without their secrets), most recent first.
+type List struct {
+	store   tokenStore
+	marshal func(v interface{}) ([]byte, error)
+}
+
+// NewList returns a new access token list handler.
+func NewList(store tokenStore) *List {
+	return &List{
+		store:   store,
+		marshal: json.Marshal,
+	}
+}
+
+// Path returns the HTTP REST endpoint of the handler.
+func (h *List) Path() string {
+	return Path
+}
+
+// Method returns the HTTP method, which is always GET.
+func (h *List) Method() string {
+	return http.MethodGet
+}
+
+// Handler returns the HTTP REST handle.
+func (h *List) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *List) handle(w http.ResponseWriter, _ *http.Request) {
+	tokens, err := h.store.GetAll()
+	if err != nil {
+		logger.Errorf("[%s] Error retrieving access tokens: %s", Path, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	if tokens == nil {
+		tokens = []*Token{}
+	}
+
+	tokensBytes, err := h.marshal(tokens)
+	if err != nil {
+		logger.Errorf("[%s] Error marshalling access tokens: %s", Path, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	writeResponse(w, http.StatusOK, tokensBytes)
+}
+
+// Issue implements a REST handler that issues a new access token, for example:
+// POST /access-tokens {"partner":"partner1","collections":["witnesses"],"expiresIn":"720h"}.
+type Issue struct {
+	store       tokenStore
+	collections map[string]string
+	marshal     func(v interface{}) ([]byte, error)
+}
+
+// NewIssue returns a new access token issue handler. The given collections map the names of the collections
+// that may be granted (for example, "witnesses") to their endpoints (for example, "/witnesses").
+func NewIssue(store tokenStore, collections map[string]string) *Issue {
+	return &Issue{
+		store:       store,
+		collections: collections,
+		marshal:     json.Marshal,
+	}
+}
+
+// Path returns the HTTP REST endpoint of the handler.
+func (h *Issue) Path() string {
+	return Path
+}
+
+// Method returns the HTTP method, which is always POST.
+func (h *Issue) Method() string {
+	return http.MethodPost
+}
+
+// Handler returns the HTTP REST handle.
+func (h *Issue) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Issue) handle(w http.ResponseWriter, req *http.Request) {
+	reqBytes, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		logger.Errorf("[%s] Error reading request body: %s", Path, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	issueReq := &IssueRequest{}
+
+	if err = json.Unmarshal(reqBytes, issueReq); err != nil {
+		writeResponse(w, http.StatusBadRequest, []byte(fmt.Sprintf("invalid request: %s", err)))
+
+		return
+	}
+
+	collections, lifespan, err := h.validate(issueReq)
+	if err != nil {
+		writeResponse(w, http.StatusBadRequest, []byte(err.Error()))
+
+		return
+	}
+
+	token, accessToken, err := h.store.Issue(issueReq.Partner, collections, lifespan)
+	if err != nil {
+		logger.Errorf("[%s] Error issuing access token: %s", Path, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	respBytes, err := h.marshal(&IssueResponse{Token: token, AccessToken: accessToken})
+	if err != nil {
+		logger.Errorf("[%s] Error marshalling access token: %s", Path, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	writeResponse(w, http.StatusOK, respBytes)
+}
+
+func (h *Issue) validate(issueReq *IssueRequest) ([]string, time.Duration, error) {
+	if strings.TrimSpace(issueReq.Partner) == "" {
+		return nil, 0, errors.New("partner is required")
+	}
+
+	if len(issueReq.Collections) == 0 {
+		return nil, 0, errors.New("at least one collection is required")
+	}
+
+	collections := make([]string, len(issueReq.Collections))
+
+	for i, name := range issueReq.Collections {
+		path, ok := h.collections[strings.ToLower(strings.TrimPrefix(strings.TrimSpace(name), "/"))]
+		if !ok {
+			return nil, 0, fmt.Errorf("unsupported collection [%s]", name)
+		}
+
+		collections[i] = path
+	}
+
+	lifespan := DefaultLifespan
+
+	if issueReq.ExpiresIn != "" {
+		var err error
+
+		lifespan, err = time.ParseDuration(issueReq.ExpiresIn)
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid expiresIn [%s]: %w", issueReq.ExpiresIn, err)
+		}
+
+		if lifespan <= 0 || lifespan > MaxLifespan {
+			return nil, 0, fmt.Errorf("expiresIn [%s] must be greater than 0 and at most %s",
+				issueReq.ExpiresIn, MaxLifespan)
+		}
+	}
+
+	return collections, lifespan, nil
+}
+
+// Revoke implements a REST handler that revokes an access token, for example:
+// DELETE /access-tokens/{id}.
+type Revoke struct {
+	store tokenStore
+}
+
+// NewRevoke returns a new access token revoke handler.
+func NewRevoke(store tokenStore) *Revoke {
+	return &Revoke{store: store}
+}
+
+// Path returns the HTTP REST endpoint of the handler.
+func (h *Revoke) Path() string {
+	return tokenPath
+}
+
+// Method returns the HTTP method, which is always DELETE.
+func (h *Revoke) Method() string {
+	return http.MethodDelete
+}
+
+// Handler returns the HTTP REST handle.
+func (h *Revoke) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Revoke) handle(w http.ResponseWriter, req *http.Request) {
+	id := mux.Vars(req)[idPathVariable]
+
+	if err := h.store.Revoke(id); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			writeResponse(w, http.StatusNotFound, []byte(notFoundResponse))
+
+			return
+		}
+
+		logger.Errorf("[%s] Error revoking access token [%s]: %s", Path, id, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	writeResponse(w, http.StatusOK, nil)
+}
+
+func writeResponse(w http.ResponseWriter, status int, body []byte) {
+	w.WriteHeader(status)
+
+	if len(body) > 0 {
+		if _, err := w.Write(body); err != nil {
+			logger.Warnf("[%s] Unable to write response: %s", Path, err)
+		}
+	}
+}
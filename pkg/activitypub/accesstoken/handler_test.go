@@ -0,0 +1,204 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package accesstoken
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/internal/testutil/httptestutil"
+)
+
+var collections = map[string]string{ //nolint:gochecknoglobals
+	"witnesses": witnesses,
+	"followers": followers,
+}
+
+func TestList(t *testing.T) {
+	s := newTestStore(t)
+
+	h := NewList(s)
+	require.Equal(t, Path, h.Path())
+	require.Equal(t, http.MethodGet, h.Method())
+	require.NotNil(t, h.Handler())
+
+	t.Run("Empty", func(t *testing.T) {
+		code, body := httptestutil.Get(t, h.handle, Path)
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, "[]", string(body))
+	})
+
+	token, bearer, err := s.Issue(partner1, []string{witnesses}, time.Hour)
+	require.NoError(t, err)
+
+	t.Run("Success", func(t *testing.T) {
+		code, body := httptestutil.Get(t, h.handle, Path)
+		require.Equal(t, http.StatusOK, code)
+		require.NotContains(t, string(body), bearer[len(token.ID)+1:])
+
+		var tokens []*Token
+		require.NoError(t, json.Unmarshal(body, &tokens))
+		require.Len(t, tokens, 1)
+		require.Equal(t, token.ID, tokens[0].ID)
+		require.Equal(t, partner1, tokens[0].Partner)
+	})
+
+	t.Run("Store error", func(t *testing.T) {
+		code, _ := httptestutil.Get(t, NewList(&mockStore{err: errors.New("injected store error")}).handle, Path)
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		h := NewList(s)
+		h.marshal = func(v interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		code, _ := httptestutil.Get(t, h.handle, Path)
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+}
+
+func TestIssue(t *testing.T) {
+	s := newTestStore(t)
+
+	h := NewIssue(s, collections)
+	require.Equal(t, Path, h.Path())
+	require.Equal(t, http.MethodPost, h.Method())
+	require.NotNil(t, h.Handler())
+
+	t.Run("Success", func(t *testing.T) {
+		code, body := httptestutil.Serve(t, h.handle, http.MethodPost, Path,
+			[]byte(`{"partner":"partner1","collections":["Witnesses","/followers"],"expiresIn":"1h"}`), nil)
+		require.Equal(t, http.StatusOK, code)
+
+		resp := &IssueResponse{}
+		require.NoError(t, json.Unmarshal(body, resp))
+		require.NotEmpty(t, resp.ID)
+		require.Equal(t, partner1, resp.Partner)
+		require.Equal(t, []string{witnesses, followers}, resp.Collections)
+		require.Equal(t, resp.Issued.Add(time.Hour), resp.Expires)
+		require.True(t, s.Verify(resp.AccessToken, witnesses))
+	})
+
+	t.Run("Default lifespan", func(t *testing.T) {
+		code, body := httptestutil.Serve(t, h.handle, http.MethodPost, Path,
+			[]byte(`{"partner":"partner1","collections":["witnesses"]}`), nil)
+		require.Equal(t, http.StatusOK, code)
+
+		resp := &IssueResponse{}
+		require.NoError(t, json.Unmarshal(body, resp))
+		require.Equal(t, resp.Issued.Add(DefaultLifespan), resp.Expires)
+	})
+
+	t.Run("Bad request", func(t *testing.T) {
+		for _, tc := range []struct {
+			body     string
+			expected string
+		}{
+			{body: `{`, expected: "invalid request"},
+			{body: `{"collections":["witnesses"]}`, expected: "partner is required"},
+			{body: `{"partner":"partner1"}`, expected: "at least one collection is required"},
+			{body: `{"partner":"partner1","collections":["liked"]}`, expected: "unsupported collection [liked]"},
+			{
+				body:     `{"partner":"partner1","collections":["witnesses"],"expiresIn":"xxx"}`,
+				expected: "invalid expiresIn [xxx]",
+			},
+			{
+				body:     `{"partner":"partner1","collections":["witnesses"],"expiresIn":"-1h"}`,
+				expected: "expiresIn [-1h] must be greater than 0",
+			},
+			{
+				body:     `{"partner":"partner1","collections":["witnesses"],"expiresIn":"9000h"}`,
+				expected: "expiresIn [9000h] must be greater than 0",
+			},
+		} {
+			code, body := httptestutil.Serve(t, h.handle, http.MethodPost, Path, []byte(tc.body), nil)
+			require.Equal(t, http.StatusBadRequest, code)
+			require.Contains(t, string(body), tc.expected)
+		}
+	})
+
+	t.Run("Store error", func(t *testing.T) {
+		h := NewIssue(&mockStore{err: errors.New("injected store error")}, collections)
+
+		code, _ := httptestutil.Serve(t, h.handle, http.MethodPost, Path,
+			[]byte(`{"partner":"partner1","collections":["witnesses"]}`), nil)
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		h := NewIssue(s, collections)
+		h.marshal = func(v interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		code, _ := httptestutil.Serve(t, h.handle, http.MethodPost, Path,
+			[]byte(`{"partner":"partner1","collections":["witnesses"]}`), nil)
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+}
+
+func TestRevoke(t *testing.T) {
+	s := newTestStore(t)
+
+	h := NewRevoke(s)
+	require.Equal(t, Path+"/{id}", h.Path())
+	require.Equal(t, http.MethodDelete, h.Method())
+	require.NotNil(t, h.Handler())
+
+	token, _, err := s.Issue(partner1, []string{witnesses}, time.Hour)
+	require.NoError(t, err)
+
+	t.Run("Success", func(t *testing.T) {
+		code, _ := httptestutil.Serve(t, h.handle, http.MethodDelete, Path+"/"+token.ID, nil,
+			map[string]string{"id": token.ID})
+		require.Equal(t, http.StatusOK, code)
+
+		_, err := s.Get(token.ID)
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		code, _ := httptestutil.Serve(t, h.handle, http.MethodDelete, Path+"/"+token.ID, nil,
+			map[string]string{"id": token.ID})
+		require.Equal(t, http.StatusNotFound, code)
+	})
+
+	t.Run("Store error", func(t *testing.T) {
+		code, _ := httptestutil.Serve(t, NewRevoke(&mockStore{err: errors.New("injected store error")}).handle,
+			http.MethodDelete, Path+"/id1", nil, map[string]string{"id": "id1"})
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+}
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+
+	s, err := NewStore(mem.NewProvider(), &mockExpiryService{})
+	require.NoError(t, err)
+
+	return s
+}
+
+type mockStore struct {
+	err error
+}
+
+func (m *mockStore) Issue(string, []string, time.Duration) (*Token, string, error) {
+	return nil, "", m.err
+}
+
+func (m *mockStore) GetAll() ([]*Token, error) {
+	return nil, m.err
+}
+
+func (m *mockStore) Revoke(string) error {
+	return m.err
+}
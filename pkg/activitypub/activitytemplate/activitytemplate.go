@@ -0,0 +1,116 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package activitytemplate
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+)
+
+// ErrNoAnchorEvent is returned by NewProofAccept if the 'Offer' activity doesn't contain an anchor event.
+var ErrNoAnchorEvent = errors.New("offer does not contain an anchor event")
+
+// NewWitnessOffer returns an 'Offer' activity that requests the given witnesses to witness the anchor event.
+// The offer is addressed to the witnesses and to the public collection, it targets the anchor witness IRI
+// and it expires after the given delay.
+func NewWitnessOffer(anchorEvent *vocab.AnchorEventType, witnesses []*url.URL,
+	maxWitnessDelay time.Duration) *vocab.ActivityType {
+	startTime := time.Now()
+	endTime := startTime.Add(maxWitnessDelay)
+
+	return vocab.NewOfferActivity(
+		vocab.NewObjectProperty(
+			vocab.WithAnchorEvent(anchorEvent),
+		),
+		vocab.WithTo(appendPublic(witnesses...)...),
+		vocab.WithStartTime(&startTime),
+		vocab.WithEndTime(&endTime),
+		vocab.WithTarget(vocab.NewObjectProperty(vocab.WithIRI(vocab.AnchorWitnessTargetIRI))),
+	)
+}
+
+// NewProofAccept returns an 'Accept' activity that replies to the given witness 'Offer' with the given proof.
+// The returned activity contains a copy of the offer with only the essential fields (the anchor event is
+// replaced by its index) and an anchor receipt holding the proof, which is valid for the given delay.
+// The activity is addressed to the actor of the offer and to the public collection.
+func NewProofAccept(offer *vocab.ActivityType, proof *vocab.ObjectType,
+	maxWitnessDelay time.Duration) (*vocab.ActivityType, error) {
+	anchorEvent := offer.Object().AnchorEvent()
+	if anchorEvent == nil {
+		return nil, fmt.Errorf("offer [%s]: %w", offer.ID(), ErrNoAnchorEvent)
+	}
+
+	startTime := time.Now()
+	endTime := startTime.Add(maxWitnessDelay)
+
+	oa := vocab.NewOfferActivity(
+		vocab.NewObjectProperty(vocab.WithIRI(anchorEvent.Index())),
+		vocab.WithID(offer.ID().URL()),
+		vocab.WithActor(offer.Actor()),
+		vocab.WithTo(offer.To()...),
+		vocab.WithTarget(offer.Target()),
+	)
+
+	return vocab.NewAcceptActivity(
+		vocab.NewObjectProperty(vocab.WithActivity(oa)),
+		vocab.WithTo(appendPublic(oa.Actor())...),
+		vocab.WithResult(vocab.NewObjectProperty(
+			vocab.WithObject(vocab.NewObject(
+				vocab.WithType(vocab.TypeAnchorReceipt),
+				vocab.WithInReplyTo(anchorEvent.Index()),
+				vocab.WithStartTime(&startTime),
+				vocab.WithEndTime(&endTime),
+				vocab.WithAttachment(vocab.NewObjectProperty(vocab.WithObject(proof))),
+			)),
+		)),
+	), nil
+}
+
+// NewAnchorAnnounce returns an 'Announce' activity that announces the given (embedded) anchor event
+// to the followers and to the public collection.
+func NewAnchorAnnounce(followers *url.URL, anchorEvent *vocab.AnchorEventType) *vocab.ActivityType {
+	return newAnnounce(followers, vocab.NewObjectProperty(vocab.WithAnchorEvent(anchorEvent)))
+}
+
+// NewAnchorRefAnnounce returns an 'Announce' activity that announces a reference to an anchor event (i.e. an
+// anchor event which only contains the URL) to the followers and to the public collection.
+func NewAnchorRefAnnounce(followers, anchorEventURL *url.URL) *vocab.ActivityType {
+	return newAnnounce(followers,
+		vocab.NewObjectProperty(vocab.WithAnchorEvent(vocab.NewAnchorEvent(vocab.WithURL(anchorEventURL)))),
+	)
+}
+
+func newAnnounce(followers *url.URL, item *vocab.ObjectProperty) *vocab.ActivityType {
+	published := time.Now()
+
+	return vocab.NewAnnounceActivity(
+		vocab.NewObjectProperty(
+			vocab.WithCollection(
+				vocab.NewCollection([]*vocab.ObjectProperty{item}),
+			),
+		),
+		vocab.WithTo(followers, vocab.PublicIRI),
+		vocab.WithPublishedTime(&published),
+	)
+}
+
+// appendPublic appends the public IRI to the given recipients if it isn't already included.
+func appendPublic(to ...*url.URL) []*url.URL {
+	recipients := append(make([]*url.URL, 0, len(to)+1), to...)
+
+	for _, iri := range to {
+		if iri.String() == vocab.PublicIRI.String() {
+			return recipients
+		}
+	}
+
+	return append(recipients, vocab.PublicIRI)
+}
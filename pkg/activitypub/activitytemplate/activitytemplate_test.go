@@ -0,0 +1,126 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package activitytemplate
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/internal/aptestutil"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+)
+
+var (
+	service1IRI  = testutil.MustParseURL("https://orb.domain1.com/services/orb")
+	service2IRI  = testutil.MustParseURL("https://orb.domain2.com/services/orb")
+	service3IRI  = testutil.MustParseURL("https://orb.domain3.com/services/orb")
+	followersIRI = testutil.MustParseURL("https://orb.domain1.com/services/orb/followers")
+)
+
+func TestNewWitnessOffer(t *testing.T) {
+	anchorEvent := aptestutil.NewMockAnchorEvent(t)
+
+	witnesses := []*url.URL{service2IRI, service3IRI}
+
+	offer := NewWitnessOffer(anchorEvent, witnesses, time.Minute)
+	require.True(t, offer.Type().Is(vocab.TypeOffer))
+	require.Equal(t, anchorEvent, offer.Object().AnchorEvent())
+	require.True(t, offer.To().Equals(vocab.Urls{service2IRI, service3IRI, vocab.PublicIRI}))
+	require.Equal(t, vocab.AnchorWitnessTargetIRI.String(), offer.Target().IRI().String())
+	require.NotNil(t, offer.StartTime())
+	require.NotNil(t, offer.EndTime())
+	require.Equal(t, time.Minute, offer.EndTime().Sub(*offer.StartTime()))
+
+	// The given witnesses must not be modified.
+	require.Len(t, witnesses, 2)
+
+	offer = NewWitnessOffer(anchorEvent, []*url.URL{service2IRI, vocab.PublicIRI}, time.Minute)
+	require.True(t, offer.To().Equals(vocab.Urls{service2IRI, vocab.PublicIRI}))
+}
+
+func TestNewProofAccept(t *testing.T) {
+	anchorEvent := aptestutil.NewMockAnchorEvent(t)
+
+	offer := NewWitnessOffer(anchorEvent, []*url.URL{service2IRI}, time.Minute)
+	offer.SetID(aptestutil.NewActivityID(service1IRI))
+	offer.SetActor(service1IRI)
+
+	proof := vocab.NewObject(vocab.WithType(vocab.TypeVerifiableCredential))
+
+	t.Run("Success", func(t *testing.T) {
+		accept, err := NewProofAccept(offer, proof, 2*time.Minute)
+		require.NoError(t, err)
+		require.True(t, accept.Type().Is(vocab.TypeAccept))
+		require.True(t, accept.To().Equals(vocab.Urls{service1IRI, vocab.PublicIRI}))
+
+		oa := accept.Object().Activity()
+		require.NotNil(t, oa)
+		require.True(t, oa.Type().Is(vocab.TypeOffer))
+		require.Equal(t, offer.ID().String(), oa.ID().String())
+		require.Equal(t, service1IRI.String(), oa.Actor().String())
+		require.Equal(t, anchorEvent.Index().String(), oa.Object().IRI().String())
+		require.Equal(t, vocab.AnchorWitnessTargetIRI.String(), oa.Target().IRI().String())
+
+		receipt := accept.Result().Object()
+		require.NotNil(t, receipt)
+		require.True(t, receipt.Type().Is(vocab.TypeAnchorReceipt))
+		require.Equal(t, anchorEvent.Index().String(), receipt.InReplyTo().String())
+		require.Equal(t, 2*time.Minute, receipt.EndTime().Sub(*receipt.StartTime()))
+		require.Len(t, receipt.Attachment(), 1)
+		require.Equal(t, proof, receipt.Attachment()[0].Object())
+
+		acceptBytes, err := json.Marshal(accept)
+		require.NoError(t, err)
+
+		accept2 := &vocab.ActivityType{}
+		require.NoError(t, json.Unmarshal(acceptBytes, accept2))
+		require.Equal(t, anchorEvent.Index().String(), accept2.Result().Object().InReplyTo().String())
+	})
+
+	t.Run("No anchor event", func(t *testing.T) {
+		_, err := NewProofAccept(
+			vocab.NewOfferActivity(vocab.NewObjectProperty(vocab.WithIRI(service2IRI))),
+			proof, time.Minute,
+		)
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrNoAnchorEvent))
+	})
+}
+
+func TestNewAnchorAnnounce(t *testing.T) {
+	anchorEvent := aptestutil.NewMockAnchorEvent(t)
+
+	announce := NewAnchorAnnounce(followersIRI, anchorEvent)
+	require.True(t, announce.Type().Is(vocab.TypeAnnounce))
+	require.True(t, announce.To().Equals(vocab.Urls{followersIRI, vocab.PublicIRI}))
+	require.NotNil(t, announce.Published())
+
+	items := announce.Object().Collection().Items()
+	require.Len(t, items, 1)
+	require.Equal(t, anchorEvent, items[0].AnchorEvent())
+}
+
+func TestNewAnchorRefAnnounce(t *testing.T) {
+	anchorEventURL := aptestutil.NewRandomHashlink(t)
+
+	announce := NewAnchorRefAnnounce(followersIRI, anchorEventURL)
+	require.True(t, announce.Type().Is(vocab.TypeAnnounce))
+	require.True(t, announce.To().Equals(vocab.Urls{followersIRI, vocab.PublicIRI}))
+	require.NotNil(t, announce.Published())
+
+	items := announce.Object().Collection().Items()
+	require.Len(t, items, 1)
+	require.True(t, items[0].Type().Is(vocab.TypeAnchorEvent))
+	require.Nil(t, items[0].AnchorEvent().Index())
+	require.Equal(t, anchorEventURL.String(), items[0].AnchorEvent().URL()[0].String())
+}
@@ -0,0 +1,236 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package anchordigest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	store "github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+)
+
+var logger = log.New("activitypub_anchordigest")
+
+const (
+	// Path is the path (relative to the service IRI) of the anchor digest endpoint.
+	Path = "/anchordigest"
+
+	// DateFormat is the format of the dates in a digest.
+	DateFormat = "2006-01-02"
+)
+
+// DayDigest contains the number of anchor activities that were published on a given day along with the Merkle root
+// of the activity IDs.
+type DayDigest struct {
+	Date  string `json:"date"`
+	Count int    `json:"count"`
+	Root  string `json:"root"`
+}
+
+// Digest is a compact digest of the anchor activities (i.e. 'Create' and 'Announce' activities) that were
+// published by a service, grouped by day.
+type Digest struct {
+	Service string       `json:"service"`
+	Since   string       `json:"since"`
+	Days    []*DayDigest `json:"days"`
+}
+
+// DayActivities contains the IDs of the anchor activities that were published by a service on a given day.
+type DayActivities struct {
+	Date       string   `json:"date"`
+	Root       string   `json:"root"`
+	Activities []string `json:"activities"`
+}
+
+type activityStore interface {
+	QueryReferences(refType store.ReferenceType, query *store.Criteria,
+		opts ...store.QueryOpt) (store.ReferenceIterator, error)
+	GetActivity(activityIRI *url.URL) (*vocab.ActivityType, error)
+}
+
+// Digester computes digests of the anchor activities in a service's collection.
+type Digester struct {
+	activityStore activityStore
+}
+
+// NewDigester returns a new digester.
+func NewDigester(activityStore activityStore) *Digester {
+	return &Digester{activityStore: activityStore}
+}
+
+// Digest returns the digest of the anchor activities in the given collection that were published on or
+// after the given day.
+func (d *Digester) Digest(refType store.ReferenceType, serviceIRI *url.URL, since time.Time) (*Digest, error) {
+	from := truncateToDay(since)
+
+	activitiesByDay, err := d.getAnchorActivities(refType, serviceIRI, from, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	digest := &Digest{
+		Service: serviceIRI.String(),
+		Since:   from.Format(DateFormat),
+		Days:    make([]*DayDigest, 0, len(activitiesByDay)),
+	}
+
+	for date, ids := range activitiesByDay {
+		digest.Days = append(digest.Days, &DayDigest{
+			Date:  date,
+			Count: len(ids),
+			Root:  MerkleRoot(ids),
+		})
+	}
+
+	sort.Slice(digest.Days, func(i, j int) bool {
+		return digest.Days[i].Date < digest.Days[j].Date
+	})
+
+	return digest, nil
+}
+
+// DayActivities returns the IDs of the anchor activities in the given collection that were published on
+// the given day.
+func (d *Digester) DayActivities(refType store.ReferenceType, serviceIRI *url.URL,
+	date time.Time) (*DayActivities, error) {
+	from := truncateToDay(date)
+
+	activitiesByDay, err := d.getAnchorActivities(refType, serviceIRI, from, from.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	day := from.Format(DateFormat)
+
+	ids := activitiesByDay[day]
+
+	sort.Strings(ids)
+
+	return &DayActivities{
+		Date:       day,
+		Root:       MerkleRoot(ids),
+		Activities: append([]string{}, ids...),
+	}, nil
+}
+
+// getAnchorActivities returns the IDs of the anchor activities that were published within the given time range,
+// keyed by day. If 'to' is zero then there is no upper bound. The collection is traversed from the most recent
+// activity backwards and the traversal stops at the first activity that was published before 'from'.
+func (d *Digester) getAnchorActivities(refType store.ReferenceType, serviceIRI *url.URL,
+	from, to time.Time) (map[string][]string, error) {
+	it, err := d.activityStore.QueryReferences(refType, store.NewCriteria(store.WithObjectIRI(serviceIRI)),
+		store.WithSortOrder(store.SortDescending))
+	if err != nil {
+		return nil, fmt.Errorf("query %s: %w", refType, err)
+	}
+
+	defer func() {
+		if errClose := it.Close(); errClose != nil {
+			logger.Warnf("Error closing iterator: %s", errClose)
+		}
+	}()
+
+	activitiesByDay := make(map[string][]string)
+
+	for {
+		activityID, e := it.Next()
+		if e != nil {
+			if errors.Is(e, store.ErrNotFound) {
+				return activitiesByDay, nil
+			}
+
+			return nil, fmt.Errorf("next %s reference: %w", refType, e)
+		}
+
+		activity, e := d.activityStore.GetActivity(activityID)
+		if e != nil {
+			if errors.Is(e, store.ErrNotFound) {
+				logger.Debugf("Activity [%s] not found in %s", activityID, refType)
+
+				continue
+			}
+
+			return nil, fmt.Errorf("get activity [%s]: %w", activityID, e)
+		}
+
+		published := activity.Published()
+		if published == nil {
+			continue
+		}
+
+		if published.Before(from) {
+			return activitiesByDay, nil
+		}
+
+		if !to.IsZero() && !published.Before(to) {
+			continue
+		}
+
+		if !activity.Type().IsAny(vocab.TypeCreate, vocab.TypeAnnounce) {
+			continue
+		}
+
+		day := published.UTC().Format(DateFormat)
+
+		activitiesByDay[day] = append(activitiesByDay[day], activity.ID().String())
+	}
+}
+
+// MerkleRoot returns the (hex encoded) root of the Merkle tree whose leaves are the SHA-256 hashes of the
+// given IDs in sorted order. If a level has an odd number of nodes then the last node is paired with itself.
+// An empty string is returned if no IDs are provided.
+func MerkleRoot(ids []string) string {
+	if len(ids) == 0 {
+		return ""
+	}
+
+	sorted := append([]string{}, ids...)
+
+	sort.Strings(sorted)
+
+	level := make([][]byte, len(sorted))
+
+	for i, id := range sorted {
+		h := sha256.Sum256([]byte(id))
+
+		level[i] = h[:]
+	}
+
+	for len(level) > 1 {
+		var next [][]byte
+
+		for i := 0; i < len(level); i += 2 {
+			right := level[i]
+
+			if i+1 < len(level) {
+				right = level[i+1]
+			}
+
+			h := sha256.Sum256(append(append([]byte{}, level[i]...), right...))
+
+			next = append(next, h[:])
+		}
+
+		level = next
+	}
+
+	return hex.EncodeToString(level[0])
+}
+
+func truncateToDay(t time.Time) time.Time {
+	t = t.UTC()
+
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
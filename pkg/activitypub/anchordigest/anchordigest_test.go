@@ -0,0 +1,153 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package anchordigest
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
+	store "github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+)
+
+var serviceIRI = testutil.MustParseURL("https://orb.domain1.com/services/orb")
+
+func TestDigester_Digest(t *testing.T) {
+	day1 := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+
+	activityStore := memstore.New("")
+
+	addActivity(t, activityStore, vocab.TypeCreate, day1.AddDate(0, 0, -1))
+	id1 := addActivity(t, activityStore, vocab.TypeCreate, day1)
+	id2 := addActivity(t, activityStore, vocab.TypeAnnounce, day1.Add(time.Hour))
+	addActivity(t, activityStore, vocab.TypeFollow, day1.Add(2*time.Hour))
+	id3 := addActivity(t, activityStore, vocab.TypeCreate, day2)
+
+	d := NewDigester(activityStore)
+
+	t.Run("Success", func(t *testing.T) {
+		digest, err := d.Digest(store.Outbox, serviceIRI, day1.Add(5*time.Hour))
+		require.NoError(t, err)
+		require.Equal(t, serviceIRI.String(), digest.Service)
+		require.Equal(t, "2021-06-01", digest.Since)
+		require.Len(t, digest.Days, 2)
+
+		require.Equal(t, "2021-06-01", digest.Days[0].Date)
+		require.Equal(t, 2, digest.Days[0].Count)
+		require.Equal(t, MerkleRoot([]string{id1.String(), id2.String()}), digest.Days[0].Root)
+
+		require.Equal(t, "2021-06-02", digest.Days[1].Date)
+		require.Equal(t, 1, digest.Days[1].Count)
+		require.Equal(t, MerkleRoot([]string{id3.String()}), digest.Days[1].Root)
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		digest, err := d.Digest(store.Outbox, serviceIRI, day2.AddDate(0, 0, 1))
+		require.NoError(t, err)
+		require.Empty(t, digest.Days)
+	})
+
+	t.Run("Query error", func(t *testing.T) {
+		errExpected := errors.New("injected query error")
+
+		_, err := NewDigester(&mockActivityStore{Store: activityStore, queryErr: errExpected}).
+			Digest(store.Outbox, serviceIRI, day1)
+		require.True(t, errors.Is(err, errExpected))
+	})
+
+	t.Run("Get activity error", func(t *testing.T) {
+		errExpected := errors.New("injected get activity error")
+
+		_, err := NewDigester(&mockActivityStore{Store: activityStore, getActivityErr: errExpected}).
+			Digest(store.Outbox, serviceIRI, day1)
+		require.True(t, errors.Is(err, errExpected))
+	})
+}
+
+func TestDigester_DayActivities(t *testing.T) {
+	day1 := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	activityStore := memstore.New("")
+
+	id1 := addActivity(t, activityStore, vocab.TypeCreate, day1)
+	id2 := addActivity(t, activityStore, vocab.TypeCreate, day1.Add(time.Hour))
+	addActivity(t, activityStore, vocab.TypeCreate, day1.AddDate(0, 0, 1))
+
+	activities, err := NewDigester(activityStore).DayActivities(store.Outbox, serviceIRI, day1)
+	require.NoError(t, err)
+	require.Equal(t, "2021-06-01", activities.Date)
+	require.Equal(t, []string{id1.String(), id2.String()}, activities.Activities)
+	require.Equal(t, MerkleRoot(activities.Activities), activities.Root)
+}
+
+func TestMerkleRoot(t *testing.T) {
+	require.Empty(t, MerkleRoot(nil))
+
+	root := MerkleRoot([]string{"a", "b", "c"})
+	require.Len(t, root, 64)
+	require.Equal(t, root, MerkleRoot([]string{"c", "a", "b"}))
+	require.NotEqual(t, root, MerkleRoot([]string{"a", "b"}))
+	require.NotEqual(t, MerkleRoot([]string{"a"}), MerkleRoot([]string{"a", "a"}))
+}
+
+func addActivity(t *testing.T, s store.Store, activityType vocab.Type, published time.Time) *url.URL {
+	t.Helper()
+
+	activityID := testutil.NewMockID(serviceIRI, "/activities/"+published.Format(time.RFC3339Nano))
+
+	var activity *vocab.ActivityType
+
+	obj := vocab.NewObjectProperty(vocab.WithIRI(testutil.MustParseURL("https://example.com/object")))
+
+	switch activityType {
+	case vocab.TypeAnnounce:
+		activity = vocab.NewAnnounceActivity(obj, vocab.WithID(activityID), vocab.WithActor(serviceIRI),
+			vocab.WithPublishedTime(&published))
+	case vocab.TypeFollow:
+		activity = vocab.NewFollowActivity(obj, vocab.WithID(activityID), vocab.WithActor(serviceIRI),
+			vocab.WithPublishedTime(&published))
+	default:
+		activity = vocab.NewCreateActivity(obj, vocab.WithID(activityID), vocab.WithActor(serviceIRI),
+			vocab.WithPublishedTime(&published))
+	}
+
+	require.NoError(t, s.AddActivity(activity))
+	require.NoError(t, s.AddReference(store.Outbox, serviceIRI, activityID))
+
+	return activityID
+}
+
+type mockActivityStore struct {
+	store.Store
+
+	queryErr       error
+	getActivityErr error
+}
+
+func (m *mockActivityStore) GetActivity(activityID *url.URL) (*vocab.ActivityType, error) {
+	if m.getActivityErr != nil {
+		return nil, m.getActivityErr
+	}
+
+	return m.Store.GetActivity(activityID)
+}
+
+func (m *mockActivityStore) QueryReferences(refType store.ReferenceType, query *store.Criteria,
+	opts ...store.QueryOpt) (store.ReferenceIterator, error) {
+	if m.queryErr != nil {
+		return nil, m.queryErr
+	}
+
+	return m.Store.QueryReferences(refType, query, opts...)
+}
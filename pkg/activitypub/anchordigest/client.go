@@ -0,0 +1,119 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package anchordigest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/trustbloc/orb/pkg/activitypub/client/transport"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+)
+
+const (
+	sinceParam = "since"
+	dateParam  = "date"
+)
+
+type httpTransport interface {
+	Get(ctx context.Context, req *transport.Request) (*http.Response, error)
+}
+
+// Client retrieves anchor digests, and the activities that they reference, from a remote service.
+type Client struct {
+	transport httpTransport
+}
+
+// NewClient returns a new anchor digest client. The given transport signs the requests so that the
+// remote service may authorize this service as a follower.
+func NewClient(t httpTransport) *Client {
+	return &Client{transport: t}
+}
+
+// GetDigest retrieves the digest of the anchor activities that were published by the given service on or
+// after the given day.
+func (c *Client) GetDigest(serviceIRI *url.URL, since time.Time) (*Digest, error) {
+	digestIRI, err := url.Parse(fmt.Sprintf("%s%s?%s=%s", serviceIRI, Path, sinceParam,
+		truncateToDay(since).Format(DateFormat)))
+	if err != nil {
+		return nil, fmt.Errorf("parse digest IRI: %w", err)
+	}
+
+	digest := &Digest{}
+
+	err = c.get(digestIRI, digest)
+	if err != nil {
+		return nil, err
+	}
+
+	return digest, nil
+}
+
+// GetDayActivities retrieves the IDs of the anchor activities that were published by the given service on
+// the given day (in the format, YYYY-MM-DD).
+func (c *Client) GetDayActivities(serviceIRI *url.URL, date string) (*DayActivities, error) {
+	dayIRI, err := url.Parse(fmt.Sprintf("%s%s?%s=%s", serviceIRI, Path, dateParam, url.QueryEscape(date)))
+	if err != nil {
+		return nil, fmt.Errorf("parse digest IRI: %w", err)
+	}
+
+	activities := &DayActivities{}
+
+	err = c.get(dayIRI, activities)
+	if err != nil {
+		return nil, err
+	}
+
+	return activities, nil
+}
+
+// GetActivity retrieves the activity with the given ID.
+func (c *Client) GetActivity(activityIRI *url.URL) (*vocab.ActivityType, error) {
+	activity := &vocab.ActivityType{}
+
+	err := c.get(activityIRI, activity)
+	if err != nil {
+		return nil, err
+	}
+
+	return activity, nil
+}
+
+func (c *Client) get(iri *url.URL, v interface{}) error {
+	resp, err := c.transport.Get(context.Background(), transport.NewRequest(iri,
+		transport.WithHeader(transport.AcceptHeader, transport.ActivityStreamsContentType)))
+	if err != nil {
+		return fmt.Errorf("request to %s failed: %w", iri, err)
+	}
+
+	defer func() {
+		if e := resp.Body.Close(); e != nil {
+			logger.Warnf("Error closing response body from %s: %s", iri, e)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("request to %s returned status code %d", iri, resp.StatusCode)
+	}
+
+	respBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading response from %s: %w", iri, err)
+	}
+
+	err = json.Unmarshal(respBytes, v)
+	if err != nil {
+		return fmt.Errorf("invalid response from %s: %w", iri, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package anchordigest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/client/transport"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+)
+
+func TestClient(t *testing.T) {
+	activityID := testutil.MustParseURL("https://orb.domain1.com/services/orb/activities/1")
+
+	activity := vocab.NewCreateActivity(
+		vocab.NewObjectProperty(vocab.WithIRI(testutil.MustParseURL("https://example.com/object"))),
+		vocab.WithID(activityID),
+	)
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/services/orb"+Path, func(w http.ResponseWriter, req *http.Request) {
+		var resp interface{}
+
+		if date := req.URL.Query().Get(dateParam); date != "" {
+			resp = &DayActivities{Date: date, Activities: []string{activityID.String()}}
+		} else {
+			resp = &Digest{Since: req.URL.Query().Get(sinceParam)}
+		}
+
+		respBytes, err := json.Marshal(resp)
+		require.NoError(t, err)
+
+		_, err = w.Write(respBytes)
+		require.NoError(t, err)
+	})
+
+	mux.HandleFunc("/services/orb/activities/1", func(w http.ResponseWriter, req *http.Request) {
+		respBytes, err := json.Marshal(activity)
+		require.NoError(t, err)
+
+		_, err = w.Write(respBytes)
+		require.NoError(t, err)
+	})
+
+	mux.HandleFunc("/services/orb/invalid", func(w http.ResponseWriter, req *http.Request) {
+		_, err := w.Write([]byte("{"))
+		require.NoError(t, err)
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	serviceIRI := testutil.MustParseURL(server.URL + "/services/orb")
+
+	c := NewClient(transport.Default())
+
+	t.Run("GetDigest", func(t *testing.T) {
+		digest, err := c.GetDigest(serviceIRI, time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		require.Equal(t, "2021-06-01", digest.Since)
+	})
+
+	t.Run("GetDayActivities", func(t *testing.T) {
+		activities, err := c.GetDayActivities(serviceIRI, "2021-06-01")
+		require.NoError(t, err)
+		require.Equal(t, "2021-06-01", activities.Date)
+		require.Equal(t, []string{activityID.String()}, activities.Activities)
+	})
+
+	t.Run("GetActivity", func(t *testing.T) {
+		a, err := c.GetActivity(testutil.MustParseURL(server.URL + "/services/orb/activities/1"))
+		require.NoError(t, err)
+		require.Equal(t, activityID.String(), a.ID().String())
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		_, err := c.GetActivity(testutil.MustParseURL(server.URL + "/services/orb/activities/2"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "returned status code 404")
+	})
+
+	t.Run("Invalid response", func(t *testing.T) {
+		_, err := c.GetActivity(testutil.MustParseURL(server.URL + "/services/orb/invalid"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid response")
+	})
+}
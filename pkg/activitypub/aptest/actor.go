@@ -0,0 +1,184 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package aptest contains fixtures for testing integrations with the Orb ActivityPub service, including mock
+// actors with signing keys, signed HTTP requests, populated activity stores, and an in-process federation of
+// two ActivityPub nodes. It is intended for use in tests only.
+package aptest
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/url"
+	"testing"
+
+	mockcrypto "github.com/hyperledger/aries-framework-go/pkg/mock/crypto"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/httpsig"
+	"github.com/trustbloc/orb/pkg/activitypub/resthandler"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+const mainKeyID = "/keys/main-key"
+
+// Actor is a mock 'Service' actor with an Ed25519 key pair that's used to sign HTTP requests.
+type Actor struct {
+	// IRI is the IRI of the actor.
+	IRI *url.URL
+	// PublicKey is the public key of the actor, which is included in the actor document.
+	PublicKey *vocab.PublicKeyType
+
+	privateKey ed25519.PrivateKey
+}
+
+// NewActor returns a new actor with the given IRI and a newly generated key pair.
+func NewActor(t *testing.T, iri *url.URL) *Actor {
+	t.Helper()
+
+	pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	keyBytes, err := x509.MarshalPKIXPublicKey(pubKey)
+	require.NoError(t, err)
+
+	pemBytes := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: keyBytes})
+
+	return &Actor{
+		IRI: iri,
+		PublicKey: vocab.NewPublicKey(
+			vocab.WithID(NewID(iri, mainKeyID)),
+			vocab.WithOwner(iri),
+			vocab.WithPublicKeyPem(string(pemBytes)),
+		),
+		privateKey: privKey,
+	}
+}
+
+// Service returns the actor document. The collection endpoints are relative to the actor IRI, as they are
+// for an Orb service.
+func (a *Actor) Service() *vocab.ActorType {
+	return vocab.NewService(a.IRI,
+		vocab.WithPublicKey(a.PublicKey),
+		vocab.WithInbox(a.InboxIRI()),
+		vocab.WithOutbox(NewID(a.IRI, resthandler.OutboxPath)),
+		vocab.WithFollowers(a.FollowersIRI()),
+		vocab.WithFollowing(NewID(a.IRI, resthandler.FollowingPath)),
+		vocab.WithWitnesses(a.WitnessesIRI()),
+		vocab.WithWitnessing(NewID(a.IRI, resthandler.WitnessingPath)),
+		vocab.WithLiked(NewID(a.IRI, resthandler.LikedPath)),
+	)
+}
+
+// InboxIRI returns the IRI of the actor's inbox.
+func (a *Actor) InboxIRI() *url.URL {
+	return NewID(a.IRI, resthandler.InboxPath)
+}
+
+// FollowersIRI returns the IRI of the actor's followers collection.
+func (a *Actor) FollowersIRI() *url.URL {
+	return NewID(a.IRI, resthandler.FollowersPath)
+}
+
+// WitnessesIRI returns the IRI of the actor's witnesses collection.
+func (a *Actor) WitnessesIRI() *url.URL {
+	return NewID(a.IRI, resthandler.WitnessesPath)
+}
+
+// GetSigner returns a signer for HTTP GET requests which uses the actor's private key.
+func (a *Actor) GetSigner() *httpsig.Signer {
+	return httpsig.NewSigner(httpsig.DefaultGetSignerConfig(), a.crypto(), &mockkms.KeyManager{}, mainKeyID)
+}
+
+// PostSigner returns a signer for HTTP POST requests which uses the actor's private key.
+func (a *Actor) PostSigner() *httpsig.Signer {
+	return httpsig.NewSigner(httpsig.DefaultPostSignerConfig(), a.crypto(), &mockkms.KeyManager{}, mainKeyID)
+}
+
+// Sign adds an HTTP signature to the given request using the GET or POST signer, depending on the method
+// of the request.
+func (a *Actor) Sign(t *testing.T, req *http.Request) {
+	t.Helper()
+
+	signer := a.GetSigner()
+	if req.Method == http.MethodPost {
+		signer = a.PostSigner()
+	}
+
+	require.NoError(t, signer.SignRequest(a.PublicKey.ID.String(), req))
+}
+
+func (a *Actor) crypto() *mockcrypto.Crypto {
+	return &mockcrypto.Crypto{
+		SignFn: func(msg []byte, _ interface{}) ([]byte, error) {
+			return ed25519.Sign(a.privateKey, msg), nil
+		},
+	}
+}
+
+// NewSignatureVerifier returns an HTTP signature verifier that resolves the actors and public keys of the
+// given actors without making HTTP requests. It may be passed to the REST handlers and the ActivityPub
+// service in order to verify requests that were signed by the given actors.
+func NewSignatureVerifier(actors ...*Actor) *httpsig.Verifier {
+	return httpsig.NewVerifier(newActorRetriever(actors...), &mockcrypto.Crypto{}, &mockkms.KeyManager{})
+}
+
+// NewID returns a new ID by appending the given path to the given IRI.
+func NewID(iri *url.URL, path string) *url.URL {
+	id, err := url.Parse(iri.String() + path)
+	if err != nil {
+		panic(err)
+	}
+
+	return id
+}
+
+// actorRetriever resolves the actors and public keys of a static set of actors.
+type actorRetriever struct {
+	actors map[string]*Actor
+	keys   map[string]*Actor
+}
+
+func newActorRetriever(actors ...*Actor) *actorRetriever {
+	r := &actorRetriever{
+		actors: make(map[string]*Actor),
+		keys:   make(map[string]*Actor),
+	}
+
+	for _, a := range actors {
+		r.actors[a.IRI.String()] = a
+		r.keys[a.PublicKey.ID.String()] = a
+	}
+
+	return r
+}
+
+func (r *actorRetriever) GetActor(actorIRI *url.URL) (*vocab.ActorType, error) {
+	a, ok := r.actors[actorIRI.String()]
+	if !ok {
+		return nil, orberrors.ErrContentNotFound
+	}
+
+	return a.Service(), nil
+}
+
+func (r *actorRetriever) GetPublicKey(keyIRI *url.URL) (*vocab.PublicKeyType, error) {
+	a, ok := r.keys[keyIRI.String()]
+	if !ok {
+		return nil, orberrors.ErrContentNotFound
+	}
+
+	return a.PublicKey, nil
+}
+
+func (r *actorRetriever) InvalidateActor(*url.URL) {}
+
+func (r *actorRetriever) InvalidatePublicKey(*url.URL) {}
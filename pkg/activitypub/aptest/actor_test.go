@@ -0,0 +1,94 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package aptest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+)
+
+func TestActor(t *testing.T) {
+	actorIRI := mustParseURL("https://domain1.com/services/orb")
+
+	a := NewActor(t, actorIRI)
+	require.Equal(t, actorIRI.String(), a.IRI.String())
+	require.Equal(t, "https://domain1.com/services/orb/keys/main-key", a.PublicKey.ID.String())
+	require.Equal(t, "https://domain1.com/services/orb/inbox", a.InboxIRI().String())
+	require.Equal(t, "https://domain1.com/services/orb/followers", a.FollowersIRI().String())
+	require.Equal(t, "https://domain1.com/services/orb/witnesses", a.WitnessesIRI().String())
+
+	service := a.Service()
+	require.True(t, service.Type().Is(vocab.TypeService))
+	require.Equal(t, a.PublicKey.ID.String(), service.PublicKey().ID.String())
+	require.Equal(t, a.InboxIRI().String(), service.Inbox().String())
+}
+
+func TestActor_Sign(t *testing.T) {
+	a1 := NewActor(t, mustParseURL("https://domain1.com/services/orb"))
+	a2 := NewActor(t, mustParseURL("https://domain2.com/services/orb"))
+
+	v := NewSignatureVerifier(a1)
+
+	t.Run("GET", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "https://domain2.com/services/orb/outbox", nil)
+
+		a1.Sign(t, req)
+
+		ok, actorIRI, err := v.VerifyRequest(req)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, a1.IRI.String(), actorIRI.String())
+	})
+
+	t.Run("POST", func(t *testing.T) {
+		req := NewSignedRequest(t, a1, http.MethodPost, "https://domain2.com/services/orb/inbox", []byte("{}"))
+		require.NotEmpty(t, req.Header.Get("Digest"))
+
+		ok, actorIRI, err := v.VerifyRequest(req)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, a1.IRI.String(), actorIRI.String())
+	})
+
+	t.Run("Unknown actor", func(t *testing.T) {
+		req := NewSignedRequest(t, a2, http.MethodGet, "https://domain1.com/services/orb/outbox", nil)
+
+		ok, _, err := v.VerifyRequest(req)
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("Not signed", func(t *testing.T) {
+		ok, _, err := v.VerifyRequest(httptest.NewRequest(http.MethodGet, "https://domain1.com/services/orb", nil))
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+}
+
+func TestNewID(t *testing.T) {
+	require.Equal(t, "https://domain1.com/services/orb/inbox",
+		NewID(mustParseURL("https://domain1.com/services/orb"), "/inbox").String())
+
+	require.Panics(t, func() {
+		NewID(mustParseURL("https://domain1.com"), "/%%")
+	})
+}
+
+func mustParseURL(raw string) *url.URL {
+	u, err := url.Parse(raw)
+	if err != nil {
+		panic(err)
+	}
+
+	return u
+}
@@ -0,0 +1,334 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package aptest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	mockcrypto "github.com/hyperledger/aries-framework-go/pkg/mock/crypto"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/client"
+	"github.com/trustbloc/orb/pkg/activitypub/client/transport"
+	"github.com/trustbloc/orb/pkg/activitypub/fed"
+	"github.com/trustbloc/orb/pkg/activitypub/httpsig"
+	"github.com/trustbloc/orb/pkg/activitypub/resthandler"
+	"github.com/trustbloc/orb/pkg/activitypub/service/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
+	store "github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/store/storeutil"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	discoveryrest "github.com/trustbloc/orb/pkg/discovery/endpoint/restapi"
+	"github.com/trustbloc/orb/pkg/httpserver/auth"
+)
+
+const (
+	defaultServiceEndpoint = "/services/orb"
+
+	// DefaultTimeout is the default amount of time that the Wait functions wait for a condition to be met.
+	DefaultTimeout = 5 * time.Second
+
+	pollInterval = 20 * time.Millisecond
+)
+
+type nodeOptions struct {
+	serviceEndpoint string
+	store           store.Store
+	handlerOpts     []spi.HandlerOpt
+}
+
+// NodeOpt sets a node option.
+type NodeOpt func(opts *nodeOptions)
+
+// WithServiceEndpoint sets the base path of the ActivityPub service of the node. Defaults to "/services/orb".
+func WithServiceEndpoint(endpoint string) NodeOpt {
+	return func(opts *nodeOptions) {
+		opts.serviceEndpoint = endpoint
+	}
+}
+
+// WithStore sets the ActivityPub store of the node. Defaults to a new in-memory store.
+func WithStore(s store.Store) NodeOpt {
+	return func(opts *nodeOptions) {
+		opts.store = s
+	}
+}
+
+// WithHandlerOpts sets the handler options (for example, the anchor event handler or the follow authorization
+// handler) of the ActivityPub service of the node.
+func WithHandlerOpts(opts ...spi.HandlerOpt) NodeOpt {
+	return func(options *nodeOptions) {
+		options.handlerOpts = append(options.handlerOpts, opts...)
+	}
+}
+
+// Node is an in-process ActivityPub node which serves the Orb ActivityPub endpoints over HTTP on a local port.
+// Requests posted to the inbox must be signed with the HTTP signature of the sending actor; the public key of
+// the actor is resolved over HTTP, as it is between Orb servers.
+type Node struct {
+	*fed.Federator
+
+	// Actor is the service actor of the node.
+	Actor *Actor
+	// Store is the ActivityPub store of the node.
+	Store store.Store
+
+	server *httptest.Server
+}
+
+// NewNode starts a new ActivityPub node. The node is stopped when the test completes.
+func NewNode(t *testing.T, opts ...NodeOpt) *Node {
+	t.Helper()
+
+	options := &nodeOptions{serviceEndpoint: defaultServiceEndpoint}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	// Create the listener first since the service IRI includes the (random) port.
+	server := httptest.NewUnstartedServer(nil)
+
+	serviceIRI, err := url.Parse(fmt.Sprintf("http://%s%s", server.Listener.Addr(), options.serviceEndpoint))
+	require.NoError(t, err)
+
+	if options.store == nil {
+		options.store = memstore.New(options.serviceEndpoint)
+	}
+
+	// Signatures are required for posts to the inbox. All other endpoints are open.
+	tm, err := auth.NewTokenManager(auth.Config{
+		AuthTokensDef: []*auth.TokenDef{
+			{EndpointExpression: options.serviceEndpoint + resthandler.InboxPath, WriteTokens: []string{"admin"}},
+		},
+		AuthTokens: map[string]string{"admin": uuid.New().String()},
+	})
+	require.NoError(t, err)
+
+	actor := NewActor(t, serviceIRI)
+
+	f, err := fed.New(
+		&fed.Config{
+			ServiceEndpoint:        options.serviceEndpoint,
+			ServiceIRI:             serviceIRI,
+			PublicKey:              actor.PublicKey,
+			PublicKeyIRI:           actor.PublicKey.ID.URL(),
+			VerifyActorInSignature: true,
+		},
+		&fed.Providers{
+			Store:      options.store,
+			GetSigner:  actor.GetSigner(),
+			PostSigner: actor.PostSigner(),
+			SignatureVerifier: httpsig.NewVerifier(client.New(client.Config{}, transport.Default()),
+				&mockcrypto.Crypto{}, &mockkms.KeyManager{}),
+			AuthTokenManager: tm,
+		},
+		options.handlerOpts...,
+	)
+	require.NoError(t, err)
+
+	router := mux.NewRouter()
+
+	for _, h := range f.HTTPHandlers() {
+		router.HandleFunc(h.Path(), h.Handler()).Methods(h.Method())
+	}
+
+	// The outbox resolves the recipients of an activity from the host-meta document of the recipient's server.
+	router.HandleFunc(discoveryrest.HostMetaJSONEndpoint, hostMetaHandler(serviceIRI)).Methods(http.MethodGet)
+
+	server.Config.Handler = router
+	server.Start()
+
+	f.Start()
+
+	n := &Node{
+		Federator: f,
+		Actor:     actor,
+		Store:     options.store,
+		server:    server,
+	}
+
+	t.Cleanup(n.stop)
+
+	return n
+}
+
+// IRI returns the IRI of the service actor of the node.
+func (n *Node) IRI() *url.URL {
+	return n.Actor.IRI
+}
+
+// URL returns the base URL of the HTTP server of the node.
+func (n *Node) URL() string {
+	return n.server.URL
+}
+
+// Post posts the given activity to the outbox of the node and returns the ID of the activity.
+func (n *Node) Post(t *testing.T, activity *vocab.ActivityType) *url.URL {
+	t.Helper()
+
+	var (
+		activityID *url.URL
+		err        error
+	)
+
+	// The service may not have started processing messages yet.
+	n.waitFor(t, DefaultTimeout, func() bool {
+		activityID, err = n.Outbox().Post(activity)
+
+		return err == nil
+	})
+
+	return activityID
+}
+
+// Follow posts a 'Follow' activity to the given node and waits for the 'Follow' to be accepted.
+func (n *Node) Follow(t *testing.T, target *Node) {
+	t.Helper()
+
+	n.Post(t, vocab.NewFollowActivity(
+		vocab.NewObjectProperty(vocab.WithIRI(target.IRI())),
+		vocab.WithTo(target.IRI()),
+	))
+
+	n.WaitForReference(t, store.Following, target.IRI())
+}
+
+// References returns the references of the given type (for example, store.Follower) of the service actor.
+func (n *Node) References(t *testing.T, refType store.ReferenceType) []*url.URL {
+	t.Helper()
+
+	it, err := n.Store.QueryReferences(refType, store.NewCriteria(store.WithObjectIRI(n.IRI())))
+	require.NoError(t, err)
+
+	refs, err := storeutil.ReadReferences(it, -1)
+	require.NoError(t, err)
+
+	return refs
+}
+
+// Activities returns the activities of the given type that are referenced by the service actor with the given
+// reference type (for example, store.Inbox). All activities are returned if no activity type is given.
+func (n *Node) Activities(t *testing.T, refType store.ReferenceType, activityType vocab.Type) []*vocab.ActivityType {
+	t.Helper()
+
+	it, err := n.Store.QueryActivities(store.NewCriteria(
+		store.WithObjectIRI(n.IRI()),
+		store.WithReferenceType(refType),
+	))
+	require.NoError(t, err)
+
+	activities, err := storeutil.ReadActivities(it, -1)
+	require.NoError(t, err)
+
+	if activityType == "" {
+		return activities
+	}
+
+	var result []*vocab.ActivityType
+
+	for _, a := range activities {
+		if a.Type().Is(activityType) {
+			result = append(result, a)
+		}
+	}
+
+	return result
+}
+
+// WaitForReference waits until the service actor has a reference of the given type to the given IRI.
+func (n *Node) WaitForReference(t *testing.T, refType store.ReferenceType, iri *url.URL) {
+	t.Helper()
+
+	n.waitFor(t, DefaultTimeout, func() bool {
+		for _, ref := range n.References(t, refType) {
+			if ref.String() == iri.String() {
+				return true
+			}
+		}
+
+		return false
+	})
+}
+
+// WaitForActivities waits until the service actor references at least the given number of activities of the
+// given type with the given reference type (for example, store.Inbox), and returns the activities.
+func (n *Node) WaitForActivities(t *testing.T, refType store.ReferenceType, activityType vocab.Type,
+	num int) []*vocab.ActivityType {
+	t.Helper()
+
+	var activities []*vocab.ActivityType
+
+	n.waitFor(t, DefaultTimeout, func() bool {
+		activities = n.Activities(t, refType, activityType)
+
+		return len(activities) >= num
+	})
+
+	return activities
+}
+
+func (n *Node) waitFor(t *testing.T, timeout time.Duration, condition func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(timeout)
+
+	for !condition() {
+		if time.Now().After(deadline) {
+			require.FailNowf(t, "timed out", "[%s] condition not met after %s", n.IRI(), timeout)
+		}
+
+		time.Sleep(pollInterval)
+	}
+}
+
+// hostMetaHandler returns a handler that serves a host-meta document which links to the given service IRI.
+func hostMetaHandler(serviceIRI *url.URL) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		err := json.NewEncoder(w).Encode(&discoveryrest.JRD{
+			Links: []discoveryrest.Link{
+				{Rel: "self", Type: discoveryrest.ActivityJSONType, Href: serviceIRI.String()},
+			},
+		})
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}
+}
+
+func (n *Node) stop() {
+	n.Federator.Stop()
+	n.server.Close()
+}
+
+// Federation is a federation of two in-process ActivityPub nodes.
+type Federation struct {
+	Node1 *Node
+	Node2 *Node
+}
+
+// NewFederation starts two ActivityPub nodes with the given options. The nodes are stopped when the test
+// completes. Each node may be configured separately by creating the nodes with NewNode.
+func NewFederation(t *testing.T, opts ...NodeOpt) *Federation {
+	t.Helper()
+
+	return &Federation{
+		Node1: NewNode(t, opts...),
+		Node2: NewNode(t, opts...),
+	}
+}
@@ -0,0 +1,53 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package aptest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/service/mocks"
+	"github.com/trustbloc/orb/pkg/activitypub/service/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
+	store "github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+)
+
+func TestFederation(t *testing.T) {
+	anchorEventHandler := mocks.NewAnchorEventHandler()
+
+	f := NewFederation(t, WithHandlerOpts(spi.WithAnchorEventHandler(anchorEventHandler)))
+	require.NotEqual(t, f.Node1.IRI().String(), f.Node2.IRI().String())
+	require.Contains(t, f.Node1.IRI().String(), f.Node1.URL())
+
+	// Node2 follows Node1.
+	f.Node2.Follow(t, f.Node1)
+
+	f.Node1.WaitForReference(t, store.Follower, f.Node2.IRI())
+
+	// Node1 publishes an anchor event to its followers.
+	create := vocab.NewCreateActivity(NewAnchorEventRef(t), vocab.WithTo(f.Node1.Actor.FollowersIRI()))
+
+	f.Node1.Post(t, create)
+
+	activities := f.Node2.WaitForActivities(t, store.Inbox, vocab.TypeCreate, 1)
+	require.Len(t, activities, 1)
+	require.Equal(t, f.Node1.IRI().String(), activities[0].Actor().String())
+
+	require.Len(t, f.Node1.Activities(t, store.Outbox, vocab.TypeCreate), 1)
+	require.NotEmpty(t, f.Node1.Activities(t, store.Outbox, ""))
+}
+
+func TestNewNode(t *testing.T) {
+	s := memstore.New("/services/custom")
+
+	n := NewNode(t, WithServiceEndpoint("/services/custom"), WithStore(s))
+	require.Equal(t, n.URL()+"/services/custom", n.IRI().String())
+	require.Equal(t, s, n.Store)
+	require.Empty(t, n.References(t, store.Follower))
+}
@@ -0,0 +1,50 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package aptest
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+)
+
+// NewSignedRequest returns an inbound HTTP request (suitable for passing directly to an HTTP handler) that is
+// signed by the given actor.
+func NewSignedRequest(t *testing.T, actor *Actor, method, target string, body []byte) *http.Request {
+	t.Helper()
+
+	req := httptest.NewRequest(method, target, bytes.NewReader(body))
+
+	actor.Sign(t, req)
+
+	return req
+}
+
+// NewSignedActivityRequest returns an inbound HTTP POST request containing the given activity, signed by the
+// given actor. If the activity doesn't have an actor then the actor of the activity is set to the given actor.
+func NewSignedActivityRequest(t *testing.T, actor *Actor, inboxIRI *url.URL,
+	activity *vocab.ActivityType) *http.Request {
+	t.Helper()
+
+	if activity.Actor() == nil {
+		activity.SetActor(actor.IRI)
+	}
+
+	activityBytes, err := vocab.Marshal(activity)
+	require.NoError(t, err)
+
+	req := NewSignedRequest(t, actor, http.MethodPost, inboxIRI.String(), activityBytes)
+	req.Header.Set("Content-Type", "application/activity+json")
+
+	return req
+}
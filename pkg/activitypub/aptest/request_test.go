@@ -0,0 +1,49 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package aptest
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+)
+
+func TestNewSignedActivityRequest(t *testing.T) {
+	a1 := NewActor(t, mustParseURL("https://domain1.com/services/orb"))
+	a2 := NewActor(t, mustParseURL("https://domain2.com/services/orb"))
+
+	follow := vocab.NewFollowActivity(
+		vocab.NewObjectProperty(vocab.WithIRI(a2.IRI)),
+		vocab.WithID(NewID(a1.IRI, "/activities/1")),
+		vocab.WithTo(a2.IRI),
+	)
+
+	req := NewSignedActivityRequest(t, a1, a2.InboxIRI(), follow)
+	require.Equal(t, http.MethodPost, req.Method)
+	require.Equal(t, a2.InboxIRI().String(), req.URL.String())
+	require.Equal(t, a1.IRI.String(), follow.Actor().String())
+
+	body, err := ioutil.ReadAll(req.Body)
+	require.NoError(t, err)
+
+	activity := &vocab.ActivityType{}
+	require.NoError(t, vocab.UnmarshalJSON(body, activity))
+	require.True(t, activity.Type().Is(vocab.TypeFollow))
+	require.Equal(t, a1.IRI.String(), activity.Actor().String())
+
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	ok, actorIRI, err := NewSignatureVerifier(a1, a2).VerifyRequest(req)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, a1.IRI.String(), actorIRI.String())
+}
@@ -0,0 +1,187 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package aptest
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
+	"github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/hashlink"
+)
+
+// NewActivity returns a new activity of the given type from the given actor, addressed to the given recipients.
+// The following types are supported:
+// - Create and Announce: the object is a reference to a new (random) anchor event.
+// - Like: the object is a reference to a new (random) anchor event.
+// - Follow: the object is the first recipient (which is required).
+func NewActivity(t *testing.T, activityType vocab.Type, actorIRI *url.URL, to ...*url.URL) *vocab.ActivityType {
+	t.Helper()
+
+	published := time.Now()
+
+	opts := []vocab.Opt{
+		vocab.WithID(NewID(actorIRI, "/activities/"+uuid.New().String())),
+		vocab.WithActor(actorIRI),
+		vocab.WithTo(to...),
+		vocab.WithPublishedTime(&published),
+	}
+
+	switch activityType {
+	case vocab.TypeCreate:
+		return vocab.NewCreateActivity(NewAnchorEventRef(t), opts...)
+	case vocab.TypeAnnounce:
+		return vocab.NewAnnounceActivity(NewAnchorEventRef(t), opts...)
+	case vocab.TypeLike:
+		return vocab.NewLikeActivity(NewAnchorEventRef(t), opts...)
+	case vocab.TypeFollow:
+		require.NotEmpty(t, to, "a recipient is required for a 'Follow' activity")
+
+		return vocab.NewFollowActivity(vocab.NewObjectProperty(vocab.WithIRI(to[0])), opts...)
+	default:
+		require.FailNowf(t, "unsupported activity type", "activity type [%s] is not supported", activityType)
+
+		return nil
+	}
+}
+
+// NewActivities returns the given number of new activities of the given type. (See NewActivity.)
+func NewActivities(t *testing.T, activityType vocab.Type, actorIRI *url.URL, num int,
+	to ...*url.URL) []*vocab.ActivityType {
+	t.Helper()
+
+	activities := make([]*vocab.ActivityType, num)
+
+	for i := 0; i < num; i++ {
+		activities[i] = NewActivity(t, activityType, actorIRI, to...)
+	}
+
+	return activities
+}
+
+// NewAnchorEventRef returns an object property containing a reference to a new (random) anchor event.
+func NewAnchorEventRef(t *testing.T) *vocab.ObjectProperty {
+	t.Helper()
+
+	hl, err := hashlink.New().CreateHashLink([]byte(uuid.New().String()), nil)
+	require.NoError(t, err)
+
+	hlURL, err := url.Parse(hl)
+	require.NoError(t, err)
+
+	return vocab.NewObjectProperty(vocab.WithAnchorEvent(vocab.NewAnchorEvent(vocab.WithURL(hlURL))))
+}
+
+type activitySpec struct {
+	refType      spi.ReferenceType
+	activities   []*vocab.ActivityType
+	activityType vocab.Type
+	actorIRI     *url.URL
+	num          int
+}
+
+type referenceSpec struct {
+	refType spi.ReferenceType
+	iris    []*url.URL
+}
+
+// StoreBuilder builds an in-memory ActivityPub store for a service that's populated with actors, activities
+// and references (for example, followers and witnesses).
+type StoreBuilder struct {
+	serviceIRI *url.URL
+	actors     []*vocab.ActorType
+	activities []*activitySpec
+	references []*referenceSpec
+}
+
+// NewStoreBuilder returns a new store builder for the given service.
+func NewStoreBuilder(serviceIRI *url.URL) *StoreBuilder {
+	return &StoreBuilder{serviceIRI: serviceIRI}
+}
+
+// WithActors adds the given actors to the store.
+func (b *StoreBuilder) WithActors(actors ...*vocab.ActorType) *StoreBuilder {
+	b.actors = append(b.actors, actors...)
+
+	return b
+}
+
+// WithActivities adds the given activities to the store along with a reference of the given type
+// (for example, spi.Inbox or spi.Outbox) from the service to each activity.
+func (b *StoreBuilder) WithActivities(refType spi.ReferenceType, activities ...*vocab.ActivityType) *StoreBuilder {
+	b.activities = append(b.activities, &activitySpec{refType: refType, activities: activities})
+
+	return b
+}
+
+// WithNewActivities adds the given number of new activities of the given type from the given actor to the store,
+// along with a reference of the given type from the service to each activity. (See NewActivity.)
+func (b *StoreBuilder) WithNewActivities(refType spi.ReferenceType, activityType vocab.Type, actorIRI *url.URL,
+	num int) *StoreBuilder {
+	b.activities = append(b.activities, &activitySpec{
+		refType:      refType,
+		activityType: activityType,
+		actorIRI:     actorIRI,
+		num:          num,
+	})
+
+	return b
+}
+
+// WithReferences adds references of the given type (for example, spi.Follower or spi.Witness) from the service
+// to the given IRIs.
+func (b *StoreBuilder) WithReferences(refType spi.ReferenceType, iris ...*url.URL) *StoreBuilder {
+	b.references = append(b.references, &referenceSpec{refType: refType, iris: iris})
+
+	return b
+}
+
+// Build returns a new in-memory store that's populated with the actors, activities, and references
+// of the builder.
+func (b *StoreBuilder) Build(t *testing.T) *memstore.Store {
+	t.Helper()
+
+	s := memstore.New(b.serviceIRI.String())
+
+	b.Populate(t, s)
+
+	return s
+}
+
+// Populate adds the actors, activities, and references of the builder to the given store.
+func (b *StoreBuilder) Populate(t *testing.T, s spi.Store) {
+	t.Helper()
+
+	for _, actor := range b.actors {
+		require.NoError(t, s.PutActor(actor))
+	}
+
+	for _, spec := range b.activities {
+		activities := spec.activities
+		if activities == nil {
+			activities = NewActivities(t, spec.activityType, spec.actorIRI, spec.num)
+		}
+
+		for _, activity := range activities {
+			require.NoError(t, s.AddActivity(activity))
+			require.NoError(t, s.AddReference(spec.refType, b.serviceIRI, activity.ID().URL(),
+				spi.WithActivityType(activity.Type().Types()[0])))
+		}
+	}
+
+	for _, spec := range b.references {
+		for _, iri := range spec.iris {
+			require.NoError(t, s.AddReference(spec.refType, b.serviceIRI, iri))
+		}
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package aptest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/store/storeutil"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+)
+
+func TestNewActivity(t *testing.T) {
+	actorIRI := mustParseURL("https://domain1.com/services/orb")
+	toIRI := mustParseURL("https://domain2.com/services/orb")
+
+	for _, activityType := range []vocab.Type{vocab.TypeCreate, vocab.TypeAnnounce, vocab.TypeLike} {
+		a := NewActivity(t, activityType, actorIRI, toIRI)
+		require.True(t, a.Type().Is(activityType))
+		require.Equal(t, actorIRI.String(), a.Actor().String())
+		require.Len(t, a.To(), 1)
+		require.NoError(t, a.Object().AnchorEvent().Validate())
+	}
+
+	follow := NewActivity(t, vocab.TypeFollow, actorIRI, toIRI)
+	require.True(t, follow.Type().Is(vocab.TypeFollow))
+	require.Equal(t, toIRI.String(), follow.Object().IRI().String())
+
+	activities := NewActivities(t, vocab.TypeCreate, actorIRI, 3)
+	require.Len(t, activities, 3)
+	require.NotEqual(t, activities[0].ID().String(), activities[1].ID().String())
+	require.NotEqual(t, activities[0].Object().AnchorEvent().URL()[0].String(),
+		activities[1].Object().AnchorEvent().URL()[0].String())
+}
+
+func TestStoreBuilder(t *testing.T) {
+	serviceIRI := mustParseURL("https://domain1.com/services/orb")
+	follower1 := mustParseURL("https://domain2.com/services/orb")
+	follower2 := mustParseURL("https://domain3.com/services/orb")
+	witness := mustParseURL("https://domain4.com/services/orb")
+
+	actor := NewActor(t, serviceIRI)
+	likes := NewActivities(t, vocab.TypeLike, follower1, 2)
+
+	s := NewStoreBuilder(serviceIRI).
+		WithActors(actor.Service()).
+		WithNewActivities(spi.Outbox, vocab.TypeCreate, serviceIRI, 5).
+		WithNewActivities(spi.Inbox, vocab.TypeAnnounce, follower1, 3).
+		WithActivities(spi.Inbox, likes...).
+		WithReferences(spi.Follower, follower1, follower2).
+		WithReferences(spi.Witness, witness).
+		Build(t)
+
+	a, err := s.GetActor(serviceIRI)
+	require.NoError(t, err)
+	require.Equal(t, serviceIRI.String(), a.ID().String())
+
+	it, err := s.QueryActivities(spi.NewCriteria(spi.WithObjectIRI(serviceIRI), spi.WithReferenceType(spi.Outbox)))
+	require.NoError(t, err)
+
+	activities, err := storeutil.ReadActivities(it, -1)
+	require.NoError(t, err)
+	require.Len(t, activities, 5)
+
+	it, err = s.QueryActivities(spi.NewCriteria(spi.WithObjectIRI(serviceIRI), spi.WithReferenceType(spi.Inbox)))
+	require.NoError(t, err)
+
+	activities, err = storeutil.ReadActivities(it, -1)
+	require.NoError(t, err)
+	require.Len(t, activities, 5)
+
+	rit, err := s.QueryReferences(spi.Follower, spi.NewCriteria(spi.WithObjectIRI(serviceIRI)))
+	require.NoError(t, err)
+
+	refs, err := storeutil.ReadReferences(rit, -1)
+	require.NoError(t, err)
+	require.Len(t, refs, 2)
+
+	rit, err = s.QueryReferences(spi.Witness, spi.NewCriteria(spi.WithObjectIRI(serviceIRI)))
+	require.NoError(t, err)
+
+	refs, err = storeutil.ReadReferences(rit, -1)
+	require.NoError(t, err)
+	require.Len(t, refs, 1)
+	require.Equal(t, witness.String(), refs[0].String())
+}
@@ -0,0 +1,411 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	store "github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+)
+
+var logger = log.New("activitypub_archive")
+
+// RecordType is the type of record in an archive.
+type RecordType string
+
+const (
+	// RecordTypeHeader is the first record in the archive.
+	RecordTypeHeader RecordType = "header"
+	// RecordTypeEntry is an archived activity.
+	RecordTypeEntry RecordType = "entry"
+	// RecordTypeTrailer is the last record in the archive and contains the signature.
+	RecordTypeTrailer RecordType = "trailer"
+)
+
+const (
+	collectionInbox  = "inbox"
+	collectionOutbox = "outbox"
+)
+
+// ErrTampered indicates that the contents of an archive do not match the hash chain or the signature.
+var ErrTampered = errors.New("archive has been tampered with")
+
+// Header is the first record in the archive.
+type Header struct {
+	Type       RecordType `json:"type"`
+	ServiceIRI string     `json:"serviceIRI"`
+	From       time.Time  `json:"from"`
+	To         time.Time  `json:"to"`
+	Created    time.Time  `json:"created"`
+}
+
+// Entry contains an archived activity along with the material that is required to verify it, i.e. the public key
+// of the actor (as stored at the time of export) and, for activities that were received by the inbox, the headers
+// of the HTTP signature that was verified when the activity was received.
+type Entry struct {
+	Type             RecordType           `json:"type"`
+	Sequence         int                  `json:"sequence"`
+	PreviousHash     string               `json:"previousHash"`
+	Collection       string               `json:"collection"`
+	Activity         *vocab.ActivityType  `json:"activity"`
+	ActorPublicKey   *vocab.PublicKeyType `json:"actorPublicKey,omitempty"`
+	SignatureHeaders map[string]string    `json:"signatureHeaders,omitempty"`
+}
+
+// Trailer is the last record in the archive. It contains the hash of the last record in the hash chain and the
+// signature of the hash.
+type Trailer struct {
+	Type      RecordType `json:"type"`
+	Count     int        `json:"count"`
+	Hash      string     `json:"hash"`
+	KeyID     string     `json:"keyID"`
+	PublicKey string     `json:"publicKey"`
+	Signature string     `json:"signature"`
+}
+
+// Config holds the configuration for the archive exporter.
+type Config struct {
+	ServiceIRI *url.URL
+	KeyID      string
+	PublicKey  []byte
+}
+
+type activityStore interface {
+	GetActor(actorIRI *url.URL) (*vocab.ActorType, error)
+	GetActivity(activityID *url.URL) (*vocab.ActivityType, error)
+	QueryReferences(refType store.ReferenceType, query *store.Criteria,
+		opts ...store.QueryOpt) (store.ReferenceIterator, error)
+}
+
+type signatureStore interface {
+	GetSignatureHeaders(activityID *url.URL) (map[string]string, error)
+}
+
+type signer interface {
+	Sign(data []byte) ([]byte, error)
+}
+
+// Exporter exports the inbox and outbox activities that were published within a given time range into a signed,
+// tamper-evident archive.
+//
+// The archive consists of newline-delimited JSON records: a header, an entry for each activity and a trailer.
+// The records form a hash chain, i.e. each entry contains the SHA-256 hash of the preceding record, and the
+// hash of the last record is signed and included in the trailer. Therefore, modifying, removing or reordering
+// any record invalidates the archive.
+type Exporter struct {
+	*Config
+
+	activityStore  activityStore
+	signatureStore signatureStore
+	signer         signer
+	now            func() time.Time
+}
+
+// NewExporter returns a new archive exporter.
+func NewExporter(cfg *Config, activityStore activityStore, signatureStore signatureStore, s signer) *Exporter {
+	return &Exporter{
+		Config:         cfg,
+		activityStore:  activityStore,
+		signatureStore: signatureStore,
+		signer:         s,
+		now:            time.Now,
+	}
+}
+
+// Export writes the archive of the activities that were published within the given time range [from, to)
+// to the given writer.
+func (e *Exporter) Export(w io.Writer, from, to time.Time) error {
+	if !from.Before(to) {
+		return fmt.Errorf("invalid time range: 'from' [%s] must be before 'to' [%s]", from, to)
+	}
+
+	aw := &archiveWriter{w: w}
+
+	err := aw.write(&Header{
+		Type:       RecordTypeHeader,
+		ServiceIRI: e.ServiceIRI.String(),
+		From:       from.UTC(),
+		To:         to.UTC(),
+		Created:    e.now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	keys := make(map[string]*vocab.PublicKeyType)
+
+	for _, c := range []struct {
+		name    string
+		refType store.ReferenceType
+	}{
+		{name: collectionInbox, refType: store.Inbox},
+		{name: collectionOutbox, refType: store.Outbox},
+	} {
+		err = e.exportCollection(aw, c.name, c.refType, from, to, keys)
+		if err != nil {
+			return err
+		}
+	}
+
+	signature, err := e.signer.Sign([]byte(aw.hash))
+	if err != nil {
+		return fmt.Errorf("sign archive: %w", err)
+	}
+
+	logger.Infof("Exported %d activities published between [%s] and [%s]", aw.count, from, to)
+
+	return aw.write(&Trailer{
+		Type:      RecordTypeTrailer,
+		Count:     aw.count,
+		Hash:      aw.hash,
+		KeyID:     e.KeyID,
+		PublicKey: base64.RawURLEncoding.EncodeToString(e.PublicKey),
+		Signature: base64.RawURLEncoding.EncodeToString(signature),
+	})
+}
+
+func (e *Exporter) exportCollection(aw *archiveWriter, collection string, refType store.ReferenceType,
+	from, to time.Time, keys map[string]*vocab.PublicKeyType) error {
+	it, err := e.activityStore.QueryReferences(refType, store.NewCriteria(store.WithObjectIRI(e.ServiceIRI)))
+	if err != nil {
+		return fmt.Errorf("query %s: %w", collection, err)
+	}
+
+	defer func() {
+		if errClose := it.Close(); errClose != nil {
+			logger.Warnf("Error closing %s iterator: %s", collection, errClose)
+		}
+	}()
+
+	var (
+		activityID *url.URL
+		entry      *Entry
+	)
+
+	for {
+		activityID, err = it.Next()
+		if err != nil {
+			if errors.Is(err, store.ErrNotFound) {
+				return nil
+			}
+
+			return fmt.Errorf("get next reference in %s: %w", collection, err)
+		}
+
+		entry, err = e.newEntry(collection, activityID, from, to, keys)
+		if err != nil {
+			return err
+		}
+
+		if entry == nil {
+			continue
+		}
+
+		err = aw.writeEntry(entry)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (e *Exporter) newEntry(collection string, activityID *url.URL, from, to time.Time,
+	keys map[string]*vocab.PublicKeyType) (*Entry, error) {
+	activity, err := e.activityStore.GetActivity(activityID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			logger.Warnf("Activity [%s] in %s not found in the activity store", activityID, collection)
+
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("get activity [%s]: %w", activityID, err)
+	}
+
+	published := activity.Published()
+	if published == nil || published.Before(from) || !published.Before(to) {
+		return nil, nil
+	}
+
+	entry := &Entry{
+		Type:       RecordTypeEntry,
+		Collection: collection,
+		Activity:   activity,
+	}
+
+	if activity.Actor() != nil {
+		entry.ActorPublicKey, err = e.getActorKey(activity.Actor(), keys)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if collection == collectionInbox {
+		entry.SignatureHeaders, err = e.signatureStore.GetSignatureHeaders(activityID)
+		if err != nil {
+			return nil, fmt.Errorf("get signature headers for activity [%s]: %w", activityID, err)
+		}
+	}
+
+	return entry, nil
+}
+
+func (e *Exporter) getActorKey(actorIRI *url.URL, keys map[string]*vocab.PublicKeyType) (*vocab.PublicKeyType, error) {
+	if key, ok := keys[actorIRI.String()]; ok {
+		return key, nil
+	}
+
+	var key *vocab.PublicKeyType
+
+	actor, err := e.activityStore.GetActor(actorIRI)
+	if err != nil {
+		if !errors.Is(err, store.ErrNotFound) {
+			return nil, fmt.Errorf("get actor [%s]: %w", actorIRI, err)
+		}
+
+		logger.Debugf("Actor [%s] not found in the activity store", actorIRI)
+	} else {
+		key = actor.PublicKey()
+	}
+
+	keys[actorIRI.String()] = key
+
+	return key, nil
+}
+
+// archiveWriter writes records to the archive and maintains the hash chain.
+type archiveWriter struct {
+	w     io.Writer
+	hash  string
+	count int
+}
+
+func (aw *archiveWriter) writeEntry(entry *Entry) error {
+	aw.count++
+
+	entry.Sequence = aw.count
+	entry.PreviousHash = aw.hash
+
+	return aw.write(entry)
+}
+
+func (aw *archiveWriter) write(record interface{}) error {
+	recordBytes, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("marshal archive record: %w", err)
+	}
+
+	_, err = aw.w.Write(append(recordBytes, '\n'))
+	if err != nil {
+		return fmt.Errorf("write archive record: %w", err)
+	}
+
+	aw.hash = hashOf(recordBytes)
+
+	return nil
+}
+
+type signatureVerifier interface {
+	Verify(keyID string, publicKey, msg, signature []byte) error
+}
+
+// Verify reads the given archive and verifies the hash chain and the signature. An ErrTampered error is returned
+// if the hash chain is broken or if the signature is invalid. Note that the public key is included in the
+// archive, so the verifier must ensure that the key belongs to the service that produced the archive.
+func Verify(r io.Reader, verifier signatureVerifier) (*Trailer, error) {
+	br := bufio.NewReader(r)
+
+	line, err := br.ReadBytes('\n')
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+
+	header := &Header{}
+
+	err = json.Unmarshal(line, header)
+	if err != nil || header.Type != RecordTypeHeader {
+		return nil, fmt.Errorf("%w: invalid header", ErrTampered)
+	}
+
+	hash := hashOf(bytes.TrimSuffix(line, []byte("\n")))
+
+	for sequence := 1; ; sequence++ {
+		line, err = br.ReadBytes('\n')
+		if err != nil {
+			return nil, fmt.Errorf("%w: missing trailer: %s", ErrTampered, err)
+		}
+
+		record := &struct {
+			Type         RecordType `json:"type"`
+			Sequence     int        `json:"sequence"`
+			PreviousHash string     `json:"previousHash"`
+		}{}
+
+		err = json.Unmarshal(line, record)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid record: %s", ErrTampered, err)
+		}
+
+		if record.Type == RecordTypeTrailer {
+			return verifyTrailer(line, hash, sequence-1, verifier)
+		}
+
+		if record.Type != RecordTypeEntry || record.Sequence != sequence || record.PreviousHash != hash {
+			return nil, fmt.Errorf("%w: hash chain is broken at entry %d", ErrTampered, sequence)
+		}
+
+		hash = hashOf(bytes.TrimSuffix(line, []byte("\n")))
+	}
+}
+
+func verifyTrailer(line []byte, hash string, count int, verifier signatureVerifier) (*Trailer, error) {
+	trailer := &Trailer{}
+
+	err := json.Unmarshal(line, trailer)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid trailer: %s", ErrTampered, err)
+	}
+
+	if trailer.Hash != hash || trailer.Count != count {
+		return nil, fmt.Errorf("%w: trailer does not match the hash chain", ErrTampered)
+	}
+
+	publicKey, err := base64.RawURLEncoding.DecodeString(trailer.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid public key: %s", ErrTampered, err)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(trailer.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid signature: %s", ErrTampered, err)
+	}
+
+	err = verifier.Verify(trailer.KeyID, publicKey, []byte(trailer.Hash), signature)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid signature: %s", ErrTampered, err)
+	}
+
+	return trailer, nil
+}
+
+func hashOf(data []byte) string {
+	h := sha256.Sum256(data)
+
+	return hex.EncodeToString(h[:])
+}
@@ -0,0 +1,338 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
+	store "github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+)
+
+const keyID = "key1"
+
+var (
+	serviceIRI = testutil.MustParseURL("https://orb.domain1.com/services/orb")
+	service2   = testutil.MustParseURL("https://orb.domain2.com/services/orb")
+)
+
+func TestExporter_Export(t *testing.T) {
+	now := time.Now()
+
+	from := now.Add(-time.Hour)
+	to := now
+
+	activityStore := memstore.New("")
+
+	require.NoError(t, activityStore.PutActor(vocab.NewService(service2,
+		vocab.WithPublicKey(vocab.NewPublicKey(
+			vocab.WithID(testutil.MustParseURL(service2.String()+"/keys/main-key")),
+			vocab.WithOwner(service2),
+			vocab.WithPublicKeyPem("-----BEGIN PUBLIC KEY-----..."),
+		)),
+	)))
+
+	sigStore, err := NewSignatureStore(mem.NewProvider())
+	require.NoError(t, err)
+
+	inbox1 := addActivity(t, activityStore, store.Inbox, service2, now.Add(-30*time.Minute))
+	addActivity(t, activityStore, store.Inbox, service2, now.Add(-2*time.Hour))
+	outbox1 := addActivity(t, activityStore, store.Outbox, serviceIRI, now.Add(-10*time.Minute))
+	addActivity(t, activityStore, store.Outbox, serviceIRI, now.Add(time.Minute))
+
+	require.NoError(t, sigStore.PutSignatureHeaders(inbox1, map[string]string{"signature": "xxx"}))
+
+	s := newMockSigner(t)
+
+	exporter := NewExporter(&Config{ServiceIRI: serviceIRI, KeyID: keyID, PublicKey: s.publicKey},
+		activityStore, sigStore, s)
+
+	t.Run("Success", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+
+		require.NoError(t, exporter.Export(buf, from, to))
+
+		trailer, err := Verify(bytes.NewReader(buf.Bytes()), &ed25519Verifier{})
+		require.NoError(t, err)
+		require.Equal(t, 2, trailer.Count)
+		require.Equal(t, keyID, trailer.KeyID)
+
+		records := readRecords(t, buf.Bytes())
+		require.Len(t, records, 4)
+
+		entry := &Entry{}
+		require.NoError(t, json.Unmarshal(records[1], entry))
+		require.Equal(t, collectionInbox, entry.Collection)
+		require.Equal(t, inbox1.String(), entry.Activity.ID().String())
+		require.NotNil(t, entry.ActorPublicKey)
+		require.Equal(t, "-----BEGIN PUBLIC KEY-----...", entry.ActorPublicKey.PublicKeyPem)
+		require.Equal(t, map[string]string{"signature": "xxx"}, entry.SignatureHeaders)
+
+		entry = &Entry{}
+		require.NoError(t, json.Unmarshal(records[2], entry))
+		require.Equal(t, collectionOutbox, entry.Collection)
+		require.Equal(t, outbox1.String(), entry.Activity.ID().String())
+		require.Nil(t, entry.ActorPublicKey)
+		require.Empty(t, entry.SignatureHeaders)
+	})
+
+	t.Run("Invalid time range", func(t *testing.T) {
+		err := exporter.Export(&bytes.Buffer{}, to, from)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid time range")
+	})
+
+	t.Run("Sign error", func(t *testing.T) {
+		errExpected := errors.New("injected sign error")
+
+		e := NewExporter(&Config{ServiceIRI: serviceIRI}, activityStore, sigStore, &mockSigner{err: errExpected})
+
+		err := e.Export(&bytes.Buffer{}, from, to)
+		require.True(t, errors.Is(err, errExpected))
+	})
+
+	t.Run("Query error", func(t *testing.T) {
+		errExpected := errors.New("injected query error")
+
+		e := NewExporter(&Config{ServiceIRI: serviceIRI},
+			&mockActivityStore{Store: activityStore, queryErr: errExpected}, sigStore, s)
+
+		err := e.Export(&bytes.Buffer{}, from, to)
+		require.True(t, errors.Is(err, errExpected))
+	})
+
+	t.Run("Get activity error", func(t *testing.T) {
+		errExpected := errors.New("injected get activity error")
+
+		e := NewExporter(&Config{ServiceIRI: serviceIRI},
+			&mockActivityStore{Store: activityStore, getActivityErr: errExpected}, sigStore, s)
+
+		err := e.Export(&bytes.Buffer{}, from, to)
+		require.True(t, errors.Is(err, errExpected))
+	})
+
+	t.Run("Get actor error", func(t *testing.T) {
+		errExpected := errors.New("injected get actor error")
+
+		e := NewExporter(&Config{ServiceIRI: serviceIRI},
+			&mockActivityStore{Store: activityStore, getActorErr: errExpected}, sigStore, s)
+
+		err := e.Export(&bytes.Buffer{}, from, to)
+		require.True(t, errors.Is(err, errExpected))
+	})
+}
+
+func TestVerify(t *testing.T) {
+	now := time.Now()
+
+	activityStore := memstore.New("")
+
+	sigStore, err := NewSignatureStore(mem.NewProvider())
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		addActivity(t, activityStore, store.Outbox, serviceIRI, now.Add(-time.Duration(i+1)*time.Minute))
+	}
+
+	s := newMockSigner(t)
+
+	buf := &bytes.Buffer{}
+
+	require.NoError(t, NewExporter(&Config{ServiceIRI: serviceIRI, KeyID: keyID, PublicKey: s.publicKey},
+		activityStore, sigStore, s).Export(buf, now.Add(-time.Hour), now))
+
+	records := readRecords(t, buf.Bytes())
+	require.Len(t, records, 5)
+
+	t.Run("Success", func(t *testing.T) {
+		trailer, err := Verify(bytes.NewReader(buf.Bytes()), &ed25519Verifier{})
+		require.NoError(t, err)
+		require.Equal(t, 3, trailer.Count)
+	})
+
+	t.Run("Modified entry", func(t *testing.T) {
+		modified := append([][]byte{}, records...)
+		modified[2] = bytes.Replace(modified[2], []byte(`"outbox"`), []byte(`"inbox"`), 1)
+
+		_, err := Verify(joinRecords(modified), &ed25519Verifier{})
+		require.True(t, errors.Is(err, ErrTampered))
+		require.Contains(t, err.Error(), "hash chain is broken at entry 3")
+	})
+
+	t.Run("Removed entry", func(t *testing.T) {
+		modified := append(append([][]byte{}, records[:2]...), records[3:]...)
+
+		_, err := Verify(joinRecords(modified), &ed25519Verifier{})
+		require.True(t, errors.Is(err, ErrTampered))
+	})
+
+	t.Run("Removed last entry", func(t *testing.T) {
+		modified := append(append([][]byte{}, records[:3]...), records[4])
+
+		_, err := Verify(joinRecords(modified), &ed25519Verifier{})
+		require.True(t, errors.Is(err, ErrTampered))
+		require.Contains(t, err.Error(), "trailer does not match the hash chain")
+	})
+
+	t.Run("Modified header", func(t *testing.T) {
+		modified := append([][]byte{}, records...)
+		modified[0] = bytes.Replace(modified[0], []byte("orb.domain1.com"), []byte("orb.domain3.com"), 1)
+
+		_, err := Verify(joinRecords(modified), &ed25519Verifier{})
+		require.True(t, errors.Is(err, ErrTampered))
+	})
+
+	t.Run("Invalid signature", func(t *testing.T) {
+		_, err := Verify(bytes.NewReader(buf.Bytes()), &ed25519Verifier{err: errors.New("invalid signature")})
+		require.True(t, errors.Is(err, ErrTampered))
+		require.Contains(t, err.Error(), "invalid signature")
+	})
+
+	t.Run("Missing trailer", func(t *testing.T) {
+		_, err := Verify(joinRecords(records[:4]), &ed25519Verifier{})
+		require.True(t, errors.Is(err, ErrTampered))
+		require.Contains(t, err.Error(), "missing trailer")
+	})
+
+	t.Run("Invalid header", func(t *testing.T) {
+		_, err := Verify(strings.NewReader("{}\n"), &ed25519Verifier{})
+		require.True(t, errors.Is(err, ErrTampered))
+		require.Contains(t, err.Error(), "invalid header")
+	})
+
+	t.Run("Empty archive", func(t *testing.T) {
+		_, err := Verify(strings.NewReader(""), &ed25519Verifier{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "read header")
+	})
+}
+
+func addActivity(t *testing.T, s store.Store, refType store.ReferenceType, actor *url.URL,
+	published time.Time) *url.URL {
+	t.Helper()
+
+	activityID := testutil.NewMockID(serviceIRI, "/activities/"+published.Format(time.RFC3339Nano))
+
+	activity := vocab.NewCreateActivity(
+		vocab.NewObjectProperty(vocab.WithIRI(testutil.MustParseURL("https://example.com/object"))),
+		vocab.WithID(activityID),
+		vocab.WithActor(actor),
+		vocab.WithPublishedTime(&published),
+	)
+
+	require.NoError(t, s.AddActivity(activity))
+	require.NoError(t, s.AddReference(refType, serviceIRI, activityID))
+
+	return activityID
+}
+
+func readRecords(t *testing.T, archive []byte) [][]byte {
+	t.Helper()
+
+	var records [][]byte
+
+	scanner := bufio.NewScanner(bytes.NewReader(archive))
+	scanner.Buffer(nil, len(archive))
+
+	for scanner.Scan() {
+		records = append(records, append([]byte{}, scanner.Bytes()...))
+	}
+
+	require.NoError(t, scanner.Err())
+
+	return records
+}
+
+func joinRecords(records [][]byte) *bytes.Reader {
+	return bytes.NewReader(append(bytes.Join(records, []byte("\n")), '\n'))
+}
+
+type mockSigner struct {
+	privateKey ed25519.PrivateKey
+	publicKey  ed25519.PublicKey
+	err        error
+}
+
+func newMockSigner(t *testing.T) *mockSigner {
+	t.Helper()
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	return &mockSigner{privateKey: privateKey, publicKey: publicKey}
+}
+
+func (m *mockSigner) Sign(data []byte) ([]byte, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	return ed25519.Sign(m.privateKey, data), nil
+}
+
+type ed25519Verifier struct {
+	err error
+}
+
+func (m *ed25519Verifier) Verify(_ string, publicKey, msg, signature []byte) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	if !ed25519.Verify(publicKey, msg, signature) {
+		return errors.New("signature verification failed")
+	}
+
+	return nil
+}
+
+type mockActivityStore struct {
+	store.Store
+
+	queryErr       error
+	getActivityErr error
+	getActorErr    error
+}
+
+func (m *mockActivityStore) GetActivity(activityID *url.URL) (*vocab.ActivityType, error) {
+	if m.getActivityErr != nil {
+		return nil, m.getActivityErr
+	}
+
+	return m.Store.GetActivity(activityID)
+}
+
+func (m *mockActivityStore) GetActor(actorIRI *url.URL) (*vocab.ActorType, error) {
+	if m.getActorErr != nil {
+		return nil, m.getActorErr
+	}
+
+	return m.Store.GetActor(actorIRI)
+}
+
+func (m *mockActivityStore) QueryReferences(refType store.ReferenceType, query *store.Criteria,
+	opts ...store.QueryOpt) (store.ReferenceIterator, error) {
+	if m.queryErr != nil {
+		return nil, m.queryErr
+	}
+
+	return m.Store.QueryReferences(refType, query, opts...)
+}
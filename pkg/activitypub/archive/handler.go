@@ -0,0 +1,132 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package archive
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+)
+
+const (
+	// Path is the path of the archive export endpoint.
+	Path = "/archive"
+
+	fromParam = "from"
+	toParam   = "to"
+
+	contentTypeNDJSON = "application/x-ndjson"
+
+	badRequestResponse          = "Bad Request."
+	internalServerErrorResponse = "Internal Server Error."
+)
+
+type exporter interface {
+	Export(w io.Writer, from, to time.Time) error
+}
+
+// Handler implements a REST handler which exports an archive of the inbox and outbox activities that were
+// published within the time range given by the 'from' and 'to' query parameters (RFC 3339). If 'to' is not
+// specified then the current time is used.
+type Handler struct {
+	exporter exporter
+	now      func() time.Time
+}
+
+// NewHandler returns a new archive export handler.
+func NewHandler(exporter exporter) *Handler {
+	return &Handler{
+		exporter: exporter,
+		now:      time.Now,
+	}
+}
+
+// Path returns the HTTP REST endpoint of the handler.
+func (h *Handler) Path() string {
+	return Path
+}
+
+// Method returns the HTTP method of the handler.
+func (h *Handler) Method() string {
+	return http.MethodGet
+}
+
+// Handler returns the HTTP REST handle.
+func (h *Handler) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Handler) handle(w http.ResponseWriter, req *http.Request) {
+	from, to, err := h.getTimeRange(req)
+	if err != nil {
+		logger.Infof("[%s] Invalid request: %s", Path, err)
+
+		writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	// The archive is written to a buffer so that an error status may be returned if the export fails.
+	buf := &bytes.Buffer{}
+
+	err = h.exporter.Export(buf, from, to)
+	if err != nil {
+		logger.Errorf("[%s] Error exporting archive: %s", Path, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	w.Header().Set("Content-Type", contentTypeNDJSON)
+	w.Header().Set("Content-Disposition",
+		fmt.Sprintf(`attachment; filename="activities-%s-%s.ndjson"`,
+			from.UTC().Format("20060102T150405Z"), to.UTC().Format("20060102T150405Z")))
+
+	writeResponse(w, http.StatusOK, buf.Bytes())
+}
+
+func (h *Handler) getTimeRange(req *http.Request) (time.Time, time.Time, error) {
+	fromStr := req.URL.Query().Get(fromParam)
+	if fromStr == "" {
+		return time.Time{}, time.Time{}, fmt.Errorf("parameter [%s] is required", fromParam)
+	}
+
+	from, err := time.Parse(time.RFC3339, fromStr)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid value for parameter [%s]: %w", fromParam, err)
+	}
+
+	to := h.now()
+
+	if toStr := req.URL.Query().Get(toParam); toStr != "" {
+		to, err = time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid value for parameter [%s]: %w", toParam, err)
+		}
+	}
+
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, fmt.Errorf("parameter [%s] must be before [%s]", fromParam, toParam)
+	}
+
+	return from, to, nil
+}
+
+func writeResponse(w http.ResponseWriter, status int, body []byte) {
+	w.WriteHeader(status)
+
+	if len(body) > 0 {
+		if _, err := w.Write(body); err != nil {
+			logger.Warnf("[%s] Unable to write response: %s", Path, err)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package archive
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	now := time.Date(2022, 6, 7, 20, 0, 0, 0, time.UTC)
+
+	t.Run("Success", func(t *testing.T) {
+		e := &mockExporter{archive: "archive"}
+
+		h := NewHandler(e)
+		h.now = func() time.Time { return now }
+
+		require.Equal(t, Path, h.Path())
+		require.Equal(t, http.MethodGet, h.Method())
+		require.NotNil(t, h.Handler())
+
+		rw := httptest.NewRecorder()
+
+		h.Handler()(rw, httptest.NewRequest(http.MethodGet, Path+"?from=2022-06-01T00:00:00Z", nil))
+
+		result := rw.Result()
+
+		require.Equal(t, http.StatusOK, result.StatusCode)
+		require.Equal(t, contentTypeNDJSON, result.Header.Get("Content-Type"))
+		require.Equal(t, `attachment; filename="activities-20220601T000000Z-20220607T200000Z.ndjson"`,
+			result.Header.Get("Content-Disposition"))
+
+		body, err := ioutil.ReadAll(result.Body)
+		require.NoError(t, err)
+		require.Equal(t, "archive", string(body))
+		require.NoError(t, result.Body.Close())
+
+		require.Equal(t, time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC), e.from)
+		require.Equal(t, now, e.to)
+	})
+
+	t.Run("Bad request", func(t *testing.T) {
+		h := NewHandler(&mockExporter{})
+
+		for _, query := range []string{
+			"",
+			"?from=xxx",
+			"?from=2022-06-01T00:00:00Z&to=xxx",
+			"?from=2022-06-01T00:00:00Z&to=2022-05-01T00:00:00Z",
+		} {
+			rw := httptest.NewRecorder()
+
+			h.Handler()(rw, httptest.NewRequest(http.MethodGet, Path+query, nil))
+
+			result := rw.Result()
+			require.Equal(t, http.StatusBadRequest, result.StatusCode, query)
+			require.NoError(t, result.Body.Close())
+		}
+	})
+
+	t.Run("Export error", func(t *testing.T) {
+		h := NewHandler(&mockExporter{err: errors.New("injected export error")})
+
+		rw := httptest.NewRecorder()
+
+		h.Handler()(rw, httptest.NewRequest(http.MethodGet,
+			Path+"?from=2022-06-01T00:00:00Z&to=2022-06-02T00:00:00Z", nil))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusInternalServerError, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+}
+
+type mockExporter struct {
+	archive  string
+	err      error
+	from, to time.Time
+}
+
+func (m *mockExporter) Export(w io.Writer, from, to time.Time) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	m.from, m.to = from, to
+
+	_, err := w.Write([]byte(m.archive))
+
+	return err
+}
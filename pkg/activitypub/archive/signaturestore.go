@@ -0,0 +1,72 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package archive
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+const signatureStoreName = "activity-signature"
+
+// SignatureStore stores the HTTP signature headers of the activities received by the inbox.
+type SignatureStore struct {
+	store storage.Store
+}
+
+// NewSignatureStore returns a new signature store.
+func NewSignatureStore(provider storage.Provider) (*SignatureStore, error) {
+	s, err := provider.OpenStore(signatureStoreName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open activity signature store: %w", err)
+	}
+
+	return &SignatureStore{store: s}, nil
+}
+
+// PutSignatureHeaders stores the HTTP signature headers for the given activity.
+func (s *SignatureStore) PutSignatureHeaders(activityID *url.URL, headers map[string]string) error {
+	headersBytes, err := json.Marshal(headers)
+	if err != nil {
+		return fmt.Errorf("marshal signature headers for activity [%s]: %w", activityID, err)
+	}
+
+	err = s.store.Put(activityID.String(), headersBytes)
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("store signature headers for activity [%s]: %w", activityID, err))
+	}
+
+	return nil
+}
+
+// GetSignatureHeaders returns the HTTP signature headers for the given activity or nil if no headers
+// were stored for the activity.
+func (s *SignatureStore) GetSignatureHeaders(activityID *url.URL) (map[string]string, error) {
+	headersBytes, err := s.store.Get(activityID.String())
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return nil, nil
+		}
+
+		return nil, orberrors.NewTransient(fmt.Errorf("get signature headers for activity [%s]: %w", activityID, err))
+	}
+
+	headers := make(map[string]string)
+
+	err = json.Unmarshal(headersBytes, &headers)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal signature headers for activity [%s]: %w", activityID, err)
+	}
+
+	return headers, nil
+}
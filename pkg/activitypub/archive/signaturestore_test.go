@@ -0,0 +1,97 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package archive
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+	"github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+func TestNewSignatureStore(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		s, err := NewSignatureStore(mem.NewProvider())
+		require.NoError(t, err)
+		require.NotNil(t, s)
+	})
+
+	t.Run("Open store error", func(t *testing.T) {
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(nil, errors.New("open store error"))
+
+		s, err := NewSignatureStore(provider)
+		require.EqualError(t, err, "failed to open activity signature store: open store error")
+		require.Nil(t, s)
+	})
+}
+
+func TestSignatureStore(t *testing.T) {
+	activityID := testutil.MustParseURL("https://orb.domain2.com/services/orb/activities/activity1")
+
+	headers := map[string]string{
+		"date":      "Tue, 07 Jun 2022 20:51:35 GMT",
+		"signature": "xxx",
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		s, err := NewSignatureStore(mem.NewProvider())
+		require.NoError(t, err)
+
+		h, err := s.GetSignatureHeaders(activityID)
+		require.NoError(t, err)
+		require.Nil(t, h)
+
+		require.NoError(t, s.PutSignatureHeaders(activityID, headers))
+
+		h, err = s.GetSignatureHeaders(activityID)
+		require.NoError(t, err)
+		require.Equal(t, headers, h)
+	})
+
+	t.Run("Store error", func(t *testing.T) {
+		errExpected := errors.New("injected store error")
+
+		store := &mocks.Store{}
+		store.PutReturns(errExpected)
+		store.GetReturns(nil, errExpected)
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := NewSignatureStore(provider)
+		require.NoError(t, err)
+
+		err = s.PutSignatureHeaders(activityID, headers)
+		require.True(t, errors.Is(err, errExpected))
+		require.True(t, orberrors.IsTransient(err))
+
+		_, err = s.GetSignatureHeaders(activityID)
+		require.True(t, errors.Is(err, errExpected))
+		require.True(t, orberrors.IsTransient(err))
+	})
+
+	t.Run("Unmarshal error", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.GetReturns([]byte("{"), nil)
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := NewSignatureStore(provider)
+		require.NoError(t, err)
+
+		_, err = s.GetSignatureHeaders(activityID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal signature headers")
+	})
+}
@@ -109,16 +109,28 @@ func New(cfg Config, t httpTransport) *Client {
 
 	logger.Debugf("Creating actor cache with size=%d, expiration=%s", cacheSize, cacheExpiration)
 
-	c.actorCache = gcache.New(cacheSize).ARC().
+	// The caches are keyed by the string form of the IRI since different instances of the same URL
+	// are not equal when used as a map key.
+	c.actorCache = gcache.New(cacheSize).LRU().
 		Expiration(cacheExpiration).
 		LoaderFunc(func(i interface{}) (interface{}, error) {
-			return c.getActor(i.(*url.URL))
+			actorIRI, err := url.Parse(i.(string))
+			if err != nil {
+				return nil, fmt.Errorf("parse actor IRI [%s]: %w", i, err)
+			}
+
+			return c.getActor(actorIRI)
 		}).Build()
 
-	c.publicKeyCache = gcache.New(cacheSize).ARC().
+	c.publicKeyCache = gcache.New(cacheSize).LRU().
 		Expiration(cacheExpiration).
 		LoaderFunc(func(i interface{}) (interface{}, error) {
-			return c.getPublicKey(i.(*url.URL))
+			keyIRI, err := url.Parse(i.(string))
+			if err != nil {
+				return nil, fmt.Errorf("parse public key IRI [%s]: %w", i, err)
+			}
+
+			return c.getPublicKey(keyIRI)
 		}).Build()
 
 	return c
@@ -127,7 +139,7 @@ func New(cfg Config, t httpTransport) *Client {
 // GetActor retrieves the actor at the given IRI.
 //nolint:interfacer
 func (c *Client) GetActor(actorIRI *url.URL) (*vocab.ActorType, error) {
-	result, err := c.actorCache.Get(actorIRI)
+	result, err := c.actorCache.Get(actorIRI.String())
 	if err != nil {
 		logger.Debugf("Got error retrieving actor from cache for IRI [%s]: %s", actorIRI, err)
 
@@ -137,6 +149,14 @@ func (c *Client) GetActor(actorIRI *url.URL) (*vocab.ActorType, error) {
 	return result.(*vocab.ActorType), nil
 }
 
+// InvalidateActor removes the actor with the given IRI from the cache so that the actor is retrieved
+// from the remote server on the next call to GetActor.
+func (c *Client) InvalidateActor(actorIRI *url.URL) {
+	if c.actorCache.Remove(actorIRI.String()) {
+		logger.Debugf("Removed actor [%s] from the cache", actorIRI)
+	}
+}
+
 func (c *Client) getActor(actorIRI *url.URL) (*vocab.ActorType, error) {
 	respBytes, err := c.get(actorIRI)
 	if err != nil {
@@ -158,7 +178,7 @@ func (c *Client) getActor(actorIRI *url.URL) (*vocab.ActorType, error) {
 // GetPublicKey retrieves the public key at the given IRI.
 //nolint:interfacer
 func (c *Client) GetPublicKey(keyIRI *url.URL) (*vocab.PublicKeyType, error) {
-	result, err := c.publicKeyCache.Get(keyIRI)
+	result, err := c.publicKeyCache.Get(keyIRI.String())
 	if err != nil {
 		logger.Debugf("Got error retrieving public key from cache for IRI [%s]: %s", keyIRI, err)
 
@@ -168,6 +188,14 @@ func (c *Client) GetPublicKey(keyIRI *url.URL) (*vocab.PublicKeyType, error) {
 	return result.(*vocab.PublicKeyType), nil
 }
 
+// InvalidatePublicKey removes the public key with the given IRI from the cache so that the key is retrieved
+// from the remote server on the next call to GetPublicKey.
+func (c *Client) InvalidatePublicKey(keyIRI *url.URL) {
+	if c.publicKeyCache.Remove(keyIRI.String()) {
+		logger.Debugf("Removed public key [%s] from the cache", keyIRI)
+	}
+}
+
 func (c *Client) getPublicKey(keyIRI *url.URL) (*vocab.PublicKeyType, error) {
 	respBytes, err := c.get(keyIRI)
 	if err != nil {
@@ -179,6 +179,57 @@ func TestClient_GetActor(t *testing.T) {
 
 			require.NoError(t, result.Body.Close())
 		})
+
+		t.Run("Cached for a different instance of the same IRI", func(t *testing.T) {
+			rw := httptest.NewRecorder()
+
+			_, err := rw.Write(actorBytes)
+			require.NoError(t, err)
+
+			result := rw.Result()
+
+			httpClient := &mocks.HTTPTransport{}
+			httpClient.GetReturnsOnCall(0, result, nil)
+			httpClient.GetReturnsOnCall(1, nil, errExpected)
+
+			c := New(Config{}, httpClient)
+
+			_, e := c.GetActor(actorIRI)
+			require.NoError(t, e)
+
+			actor, e := c.GetActor(testutil.MustParseURL(actorIRI.String()))
+			require.NoError(t, e)
+			require.Equal(t, actorIRI.String(), actor.ID().String())
+			require.Equal(t, 1, httpClient.GetCallCount())
+
+			require.NoError(t, result.Body.Close())
+		})
+
+		t.Run("Invalidated", func(t *testing.T) {
+			rw := httptest.NewRecorder()
+
+			_, err := rw.Write(actorBytes)
+			require.NoError(t, err)
+
+			result := rw.Result()
+
+			httpClient := &mocks.HTTPTransport{}
+			httpClient.GetReturnsOnCall(0, result, nil)
+			httpClient.GetReturnsOnCall(1, nil, errExpected)
+
+			c := New(Config{}, httpClient)
+
+			_, e := c.GetActor(actorIRI)
+			require.NoError(t, e)
+
+			c.InvalidateActor(actorIRI)
+
+			_, e = c.GetActor(actorIRI)
+			require.Error(t, e)
+			require.True(t, errors.Is(e, errExpected))
+
+			require.NoError(t, result.Body.Close())
+		})
 	})
 }
 
@@ -533,6 +584,16 @@ func TestClient_GetPublicKey(t *testing.T) {
 		require.NotNil(t, publicKey)
 		require.Equal(t, keyIRI.String(), publicKey.ID.String())
 
+		_, e = c.GetPublicKey(keyIRI)
+		require.NoError(t, e)
+		require.Equal(t, 1, httpClient.GetCallCount())
+
+		c.InvalidatePublicKey(keyIRI)
+
+		_, e = c.GetPublicKey(keyIRI)
+		require.Error(t, e, "expecting the key to be retrieved again")
+		require.Equal(t, 2, httpClient.GetCallCount())
+
 		require.NoError(t, result.Body.Close())
 	})
 
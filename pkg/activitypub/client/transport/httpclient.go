@@ -0,0 +1,167 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package transport
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	defaultDialTimeout         = 2 * time.Second
+	defaultKeepAlive           = 30 * time.Second
+	defaultMaxIdleConns        = 2000
+	defaultMaxIdleConnsPerHost = 100
+	defaultMaxConnsPerHost     = 100
+	defaultIdleConnTimeout     = 90 * time.Second
+	defaultTLSHandshakeTimeout = 5 * time.Second
+)
+
+// HTTPClientConfig contains the configuration of the HTTP client that is shared by all outbound requests
+// (ActivityPub, WebCAS, WebFinger, etc.) so that connections are pooled and reused across requests.
+type HTTPClientConfig struct {
+	// TLSConfig is the TLS configuration of the client.
+	TLSConfig *tls.Config
+	// Timeout is the default timeout of a request, including reading the response body. Zero means no timeout.
+	Timeout time.Duration
+	// HostTimeouts contains the request timeouts for specific hosts. These timeouts override the default timeout.
+	HostTimeouts map[string]time.Duration
+	// DialTimeout is the maximum amount of time a dial waits for a connect to complete.
+	DialTimeout time.Duration
+	// MaxIdleConns is the maximum number of idle (keep-alive) connections across all hosts.
+	MaxIdleConns int
+	// MaxIdleConnsPerHost is the maximum number of idle (keep-alive) connections to keep per host.
+	MaxIdleConnsPerHost int
+	// MaxConnsPerHost limits the total number of connections per host (dialing, active and idle). Zero means no limit.
+	MaxConnsPerHost int
+	// IdleConnTimeout is the maximum amount of time an idle (keep-alive) connection remains idle before closing itself.
+	IdleConnTimeout time.Duration
+	// Proxy (optional) is the proxy configuration. If not set then the proxy is taken from the environment.
+	Proxy *ProxyConfig
+}
+
+// NewHTTPClient returns a new HTTP client with a pooled transport that attempts to use HTTP/2. Default values are
+// used for any limits that aren't set in the given configuration.
+func NewHTTPClient(cfg *HTTPClientConfig) *http.Client {
+	t := &http.Transport{
+		Proxy:           newProxyFunc(cfg.Proxy),
+		TLSClientConfig: cfg.TLSConfig,
+		DialContext: (&net.Dialer{
+			Timeout:   durationOrDefault(cfg.DialTimeout, defaultDialTimeout),
+			KeepAlive: defaultKeepAlive,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          intOrDefault(cfg.MaxIdleConns, defaultMaxIdleConns),
+		MaxIdleConnsPerHost:   intOrDefault(cfg.MaxIdleConnsPerHost, defaultMaxIdleConnsPerHost),
+		MaxConnsPerHost:       intOrDefault(cfg.MaxConnsPerHost, defaultMaxConnsPerHost),
+		IdleConnTimeout:       durationOrDefault(cfg.IdleConnTimeout, defaultIdleConnTimeout),
+		TLSHandshakeTimeout:   defaultTLSHandshakeTimeout,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+
+	if len(cfg.HostTimeouts) == 0 {
+		return &http.Client{
+			Timeout:   cfg.Timeout,
+			Transport: t,
+		}
+	}
+
+	// The timeouts are applied by the round tripper since the client timeout applies to all hosts.
+	return &http.Client{
+		Transport: newTimeoutRoundTripper(t, cfg.Timeout, cfg.HostTimeouts),
+	}
+}
+
+// timeoutRoundTripper applies a timeout to each request according to the host of the request.
+type timeoutRoundTripper struct {
+	http.RoundTripper
+
+	defaultTimeout time.Duration
+	hostTimeouts   map[string]time.Duration
+}
+
+func newTimeoutRoundTripper(rt http.RoundTripper, defaultTimeout time.Duration,
+	hostTimeouts map[string]time.Duration) *timeoutRoundTripper {
+	timeouts := make(map[string]time.Duration)
+
+	for host, timeout := range hostTimeouts {
+		timeouts[strings.ToLower(host)] = timeout
+	}
+
+	return &timeoutRoundTripper{
+		RoundTripper:   rt,
+		defaultTimeout: defaultTimeout,
+		hostTimeouts:   timeouts,
+	}
+}
+
+// RoundTrip executes the request with the timeout of the request's host.
+func (rt *timeoutRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	timeout := rt.timeout(req)
+	if timeout <= 0 {
+		return rt.RoundTripper.RoundTrip(req)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+
+	resp, err := rt.RoundTripper.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+
+		return nil, err
+	}
+
+	// The context is cancelled when the response body is closed.
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+
+	return resp, nil
+}
+
+func (rt *timeoutRoundTripper) timeout(req *http.Request) time.Duration {
+	if timeout, ok := rt.hostTimeouts[strings.ToLower(req.URL.Host)]; ok {
+		return timeout
+	}
+
+	if timeout, ok := rt.hostTimeouts[strings.ToLower(req.URL.Hostname())]; ok {
+		return timeout
+	}
+
+	return rt.defaultTimeout
+}
+
+type cancelOnClose struct {
+	io.ReadCloser
+
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	defer c.cancel()
+
+	return c.ReadCloser.Close()
+}
+
+func durationOrDefault(value, defaultValue time.Duration) time.Duration {
+	if value > 0 {
+		return value
+	}
+
+	return defaultValue
+}
+
+func intOrDefault(value, defaultValue int) int {
+	if value > 0 {
+		return value
+	}
+
+	return defaultValue
+}
@@ -0,0 +1,87 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package transport
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHTTPClient(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		client := NewHTTPClient(&HTTPClientConfig{Timeout: time.Second})
+		require.Equal(t, time.Second, client.Timeout)
+
+		tr, ok := client.Transport.(*http.Transport)
+		require.True(t, ok)
+		require.True(t, tr.ForceAttemptHTTP2)
+		require.Equal(t, defaultMaxIdleConns, tr.MaxIdleConns)
+		require.Equal(t, defaultMaxIdleConnsPerHost, tr.MaxIdleConnsPerHost)
+		require.Equal(t, defaultMaxConnsPerHost, tr.MaxConnsPerHost)
+		require.Equal(t, defaultIdleConnTimeout, tr.IdleConnTimeout)
+	})
+
+	t.Run("pool limits", func(t *testing.T) {
+		client := NewHTTPClient(&HTTPClientConfig{
+			MaxIdleConns:        10,
+			MaxIdleConnsPerHost: 5,
+			MaxConnsPerHost:     20,
+			IdleConnTimeout:     time.Minute,
+		})
+
+		tr, ok := client.Transport.(*http.Transport)
+		require.True(t, ok)
+		require.Equal(t, 10, tr.MaxIdleConns)
+		require.Equal(t, 5, tr.MaxIdleConnsPerHost)
+		require.Equal(t, 20, tr.MaxConnsPerHost)
+		require.Equal(t, time.Minute, tr.IdleConnTimeout)
+	})
+
+	t.Run("host timeouts", func(t *testing.T) {
+		slowServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(200 * time.Millisecond)
+
+			_, err := w.Write([]byte("slow"))
+			require.NoError(t, err)
+		}))
+		defer slowServer.Close()
+
+		slowURL, err := url.Parse(slowServer.URL)
+		require.NoError(t, err)
+
+		client := NewHTTPClient(&HTTPClientConfig{
+			Timeout:      50 * time.Millisecond,
+			HostTimeouts: map[string]time.Duration{slowURL.Host: time.Second},
+		})
+		require.Zero(t, client.Timeout)
+
+		resp, err := client.Get(slowServer.URL)
+		require.NoError(t, err)
+
+		body, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, "slow", string(body))
+
+		client = NewHTTPClient(&HTTPClientConfig{
+			Timeout:      time.Second,
+			HostTimeouts: map[string]time.Duration{slowURL.Hostname(): 50 * time.Millisecond},
+		})
+
+		_, err = client.Get(slowServer.URL) //nolint:bodyclose
+		require.Error(t, err)
+		require.True(t, errors.Is(err, context.DeadlineExceeded))
+	})
+}
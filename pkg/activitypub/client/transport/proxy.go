@@ -0,0 +1,114 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package transport
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ProxyConfig contains the proxy configuration of outbound requests.
+type ProxyConfig struct {
+	// URL is the URL of the proxy (http, https or socks5) that is used for all destinations, except for those
+	// that have an override or that are in the no-proxy list. If nil then requests are sent directly.
+	URL *url.URL
+	// Overrides contains the proxies for specific destination domains, keyed by domain. A domain matches the host
+	// itself along with all of its subdomains and the most specific domain wins. A nil proxy URL means that requests
+	// to the domain are sent directly.
+	Overrides map[string]*url.URL
+	// NoProxyDomains contains the domains to which requests are always sent directly. A domain matches the host
+	// itself along with all of its subdomains. The value "*" matches all hosts.
+	NoProxyDomains []string
+	// NoProxyNets contains the IP ranges to which requests are always sent directly.
+	NoProxyNets []*net.IPNet
+}
+
+// proxySelector selects the proxy of a request according to the destination host.
+type proxySelector struct {
+	*ProxyConfig
+
+	overrides map[string]*url.URL
+}
+
+// newProxyFunc returns the function that selects the proxy of a request. If no proxy configuration is provided
+// then the proxy is taken from the environment (HTTP_PROXY, HTTPS_PROXY and NO_PROXY).
+func newProxyFunc(cfg *ProxyConfig) func(req *http.Request) (*url.URL, error) {
+	if cfg == nil {
+		return http.ProxyFromEnvironment
+	}
+
+	overrides := make(map[string]*url.URL, len(cfg.Overrides))
+
+	for domain, proxyURL := range cfg.Overrides {
+		overrides[normalizeDomain(domain)] = proxyURL
+	}
+
+	s := &proxySelector{
+		ProxyConfig: cfg,
+		overrides:   overrides,
+	}
+
+	return s.proxy
+}
+
+func (s *proxySelector) proxy(req *http.Request) (*url.URL, error) {
+	host := strings.ToLower(req.URL.Hostname())
+
+	if s.bypass(host) {
+		return nil, nil
+	}
+
+	var (
+		match    string
+		proxyURL = s.URL
+	)
+
+	for domain, u := range s.overrides {
+		if len(domain) > len(match) && matchesDomain(host, domain) {
+			match = domain
+			proxyURL = u
+		}
+	}
+
+	return proxyURL, nil
+}
+
+func (s *proxySelector) bypass(host string) bool {
+	for _, domain := range s.NoProxyDomains {
+		if domain == "*" || matchesDomain(host, normalizeDomain(domain)) {
+			return true
+		}
+	}
+
+	if len(s.NoProxyNets) == 0 {
+		return false
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+
+	for _, ipNet := range s.NoProxyNets {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesDomain returns true if the host is the given domain or a subdomain of the domain.
+func matchesDomain(host, domain string) bool {
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
+
+func normalizeDomain(domain string) string {
+	return strings.TrimPrefix(strings.ToLower(strings.TrimSpace(domain)), ".")
+}
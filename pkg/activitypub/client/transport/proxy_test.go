@@ -0,0 +1,89 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package transport
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+)
+
+func TestNewProxyFunc(t *testing.T) {
+	defaultProxy := testutil.MustParseURL("http://proxy.corp.com:3128")
+	socksProxy := testutil.MustParseURL("socks5://socks.corp.com:1080")
+
+	_, ipNet, e := net.ParseCIDR("10.0.0.0/8")
+	require.NoError(t, e)
+
+	proxy := newProxyFunc(&ProxyConfig{
+		URL: defaultProxy,
+		Overrides: map[string]*url.URL{
+			"domain2.com":           socksProxy,
+			".internal.domain2.com": nil,
+		},
+		NoProxyDomains: []string{"localhost", ".corp.com"},
+		NoProxyNets:    []*net.IPNet{ipNet},
+	})
+
+	for _, tc := range []struct {
+		target   string
+		expected *url.URL
+	}{
+		{target: "https://orb.domain1.com/services/orb", expected: defaultProxy},
+		{target: "https://domain2.com/services/orb", expected: socksProxy},
+		{target: "https://orb.Domain2.com:443/cas/xxx", expected: socksProxy},
+		{target: "https://orb.internal.domain2.com/services/orb", expected: nil},
+		{target: "https://notdomain2.com/services/orb", expected: defaultProxy},
+		{target: "http://localhost:8080/kms", expected: nil},
+		{target: "https://ipfs.corp.com/api/v0", expected: nil},
+		{target: "http://10.1.2.3:5001/api/v0", expected: nil},
+		{target: "http://192.168.1.1/api/v0", expected: defaultProxy},
+	} {
+		proxyURL, err := proxy(httptest.NewRequest(http.MethodGet, tc.target, nil))
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, proxyURL, tc.target)
+	}
+
+	t.Run("No default proxy", func(t *testing.T) {
+		p := newProxyFunc(&ProxyConfig{
+			Overrides: map[string]*url.URL{"domain2.com": socksProxy},
+		})
+
+		proxyURL, err := p(httptest.NewRequest(http.MethodGet, "https://orb.domain1.com", nil))
+		require.NoError(t, err)
+		require.Nil(t, proxyURL)
+
+		proxyURL, err = p(httptest.NewRequest(http.MethodGet, "https://orb.domain2.com", nil))
+		require.NoError(t, err)
+		require.Equal(t, socksProxy, proxyURL)
+	})
+
+	t.Run("No proxy for all hosts", func(t *testing.T) {
+		p := newProxyFunc(&ProxyConfig{URL: defaultProxy, NoProxyDomains: []string{"*"}})
+
+		proxyURL, err := p(httptest.NewRequest(http.MethodGet, "https://orb.domain1.com", nil))
+		require.NoError(t, err)
+		require.Nil(t, proxyURL)
+	})
+
+	t.Run("With HTTP client", func(t *testing.T) {
+		client := NewHTTPClient(&HTTPClientConfig{Proxy: &ProxyConfig{URL: defaultProxy}})
+
+		tr, ok := client.Transport.(*http.Transport)
+		require.True(t, ok)
+
+		proxyURL, err := tr.Proxy(httptest.NewRequest(http.MethodGet, "https://orb.domain1.com", nil))
+		require.NoError(t, err)
+		require.Equal(t, defaultProxy, proxyURL)
+	})
+}
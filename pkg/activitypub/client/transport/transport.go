@@ -30,6 +30,12 @@ const (
 
 	// ActivityStreamsContentType is the content type used for activity streams messages.
 	ActivityStreamsContentType = `application/ld+json; profile="https://www.w3.org/ns/activitystreams"`
+
+	// ContentEncodingHeader specifies the encoding (compression) of the request body.
+	ContentEncodingHeader = "Content-Encoding"
+
+	// GzipContentEncoding is the content encoding of a gzip-compressed request body.
+	GzipContentEncoding = "gzip"
 )
 
 // Signer signs an HTTP request and adds the signature to the header of the request.
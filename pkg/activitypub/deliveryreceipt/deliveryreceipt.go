@@ -0,0 +1,307 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deliveryreceipt
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/store/expiry"
+)
+
+var logger = log.New("delivery-receipt")
+
+var errNotFound = errors.New("delivery receipt not found")
+
+const (
+	storeName = "delivery-receipt"
+
+	// activityTag holds the hash of the activity ID so that all receipts for an activity may be queried.
+	activityTag = "activity"
+	// expiryTag holds the time (Unix time) after which the receipt is deleted.
+	expiryTag = "expiry"
+
+	defaultRetention = 7 * 24 * time.Hour
+)
+
+// Status is the delivery status of an activity to a recipient inbox.
+type Status string
+
+const (
+	// StatusPending indicates that the activity hasn't been delivered yet.
+	StatusPending Status = "pending"
+	// StatusDelivered indicates that the activity was delivered.
+	StatusDelivered Status = "delivered"
+	// StatusFailed indicates that the last delivery attempt failed. The delivery may still be retried.
+	StatusFailed Status = "failed"
+)
+
+// Receipt contains the delivery outcome of an activity to a single inbox.
+type Receipt struct {
+	Activity    string     `json:"activity"`
+	Inbox       string     `json:"inbox"`
+	Status      Status     `json:"status"`
+	Attempts    int        `json:"attempts"`
+	Created     time.Time  `json:"created"`
+	LastAttempt *time.Time `json:"lastAttempt,omitempty"`
+	Error       string     `json:"error,omitempty"`
+}
+
+// Summary contains the delivery receipts of an activity along with the number of receipts in each status.
+type Summary struct {
+	Activity   string     `json:"activity"`
+	Total      int        `json:"total"`
+	Delivered  int        `json:"delivered"`
+	Failed     int        `json:"failed"`
+	Pending    int        `json:"pending"`
+	Deliveries []*Receipt `json:"deliveries"`
+}
+
+// NewSummary returns a summary of the given receipts.
+func NewSummary(activityID fmt.Stringer, receipts []*Receipt) *Summary {
+	s := &Summary{
+		Activity:   activityID.String(),
+		Total:      len(receipts),
+		Deliveries: receipts,
+	}
+
+	if s.Deliveries == nil {
+		s.Deliveries = []*Receipt{}
+	}
+
+	for _, r := range receipts {
+		switch r.Status {
+		case StatusDelivered:
+			s.Delivered++
+		case StatusFailed:
+			s.Failed++
+		default:
+			s.Pending++
+		}
+	}
+
+	return s
+}
+
+type expiryService interface {
+	Register(store storage.Store, expiryTagName, storeName string, opts ...expiry.Option)
+}
+
+// Store tracks the delivery outcome of each activity posted to the outbox for each recipient inbox. A receipt is
+// added (with status 'pending') for each inbox when the activity is posted, and is updated after each delivery
+// attempt. Receipts are deleted by the expiry service after the retention period.
+type Store struct {
+	store     storage.Store
+	retention time.Duration
+	marshal   func(v interface{}) ([]byte, error)
+}
+
+// NewStore returns a new delivery receipt store. If retention is 0 then a default of 7 days is used.
+func NewStore(provider storage.Provider, expiryService expiryService, retention time.Duration) (*Store, error) {
+	s, err := provider.OpenStore(storeName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open delivery receipt store: %w", err)
+	}
+
+	err = provider.SetStoreConfig(storeName,
+		storage.StoreConfiguration{TagNames: []string{activityTag, expiryTag}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set store configuration: %w", err)
+	}
+
+	expiryService.Register(s, expiryTag, storeName)
+
+	if retention == 0 {
+		retention = defaultRetention
+	}
+
+	return &Store{
+		store:     s,
+		retention: retention,
+		marshal:   json.Marshal,
+	}, nil
+}
+
+// Pending adds a 'pending' receipt for each of the given inboxes.
+func (s *Store) Pending(activityID *url.URL, inboxes []*url.URL) error {
+	now := time.Now().UTC()
+
+	operations := make([]storage.Operation, len(inboxes))
+
+	for i, inbox := range inboxes {
+		r := &Receipt{
+			Activity: activityID.String(),
+			Inbox:    inbox.String(),
+			Status:   StatusPending,
+			Created:  now,
+		}
+
+		op, err := s.newPutOperation(r)
+		if err != nil {
+			return err
+		}
+
+		operations[i] = op
+	}
+
+	if len(operations) == 0 {
+		return nil
+	}
+
+	err := s.store.Batch(operations)
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("store delivery receipts for activity [%s]: %w", activityID, err))
+	}
+
+	return nil
+}
+
+// DeliveryAttempted updates the receipt of the given activity for the given inbox with the result of a delivery
+// attempt. The error is nil if the delivery succeeded. Errors are logged since the delivery itself isn't affected.
+func (s *Store) DeliveryAttempted(activityID string, inbox *url.URL, deliveryErr error) {
+	r, err := s.get(activityID, inbox.String())
+	if err != nil {
+		if !errors.Is(err, errNotFound) {
+			logger.Warnf("Error retrieving delivery receipt of activity [%s] to [%s]: %s", activityID, inbox, err)
+
+			return
+		}
+
+		// The receipt wasn't added when the activity was posted (for example, if receipts were enabled
+		// after the activity was posted).
+		r = &Receipt{
+			Activity: activityID,
+			Inbox:    inbox.String(),
+			Created:  time.Now().UTC(),
+		}
+	}
+
+	now := time.Now().UTC()
+
+	r.Attempts++
+	r.LastAttempt = &now
+
+	if deliveryErr != nil {
+		r.Status = StatusFailed
+		r.Error = deliveryErr.Error()
+	} else {
+		r.Status = StatusDelivered
+		r.Error = ""
+	}
+
+	op, err := s.newPutOperation(r)
+	if err != nil {
+		logger.Warnf("Error updating delivery receipt of activity [%s] to [%s]: %s", activityID, inbox, err)
+
+		return
+	}
+
+	err = s.store.Put(op.Key, op.Value, op.Tags...)
+	if err != nil {
+		logger.Warnf("Error storing delivery receipt of activity [%s] to [%s]: %s", activityID, inbox, err)
+	}
+}
+
+// Get returns the receipts of the given activity, sorted by inbox.
+func (s *Store) Get(activityID *url.URL) ([]*Receipt, error) {
+	it, err := s.store.Query(fmt.Sprintf("%s:%s", activityTag, hash(activityID.String())))
+	if err != nil {
+		return nil, orberrors.NewTransient(fmt.Errorf("query delivery receipts: %w", err))
+	}
+
+	defer func() {
+		if e := it.Close(); e != nil {
+			logger.Warnf("Error closing iterator: %s", e)
+		}
+	}()
+
+	var receipts []*Receipt
+
+	for {
+		ok, e := it.Next()
+		if e != nil {
+			return nil, orberrors.NewTransient(fmt.Errorf("next delivery receipt: %w", e))
+		}
+
+		if !ok {
+			break
+		}
+
+		value, e := it.Value()
+		if e != nil {
+			return nil, orberrors.NewTransient(fmt.Errorf("get delivery receipt from iterator: %w", e))
+		}
+
+		r := &Receipt{}
+
+		if e := json.Unmarshal(value, r); e != nil {
+			return nil, fmt.Errorf("unmarshal delivery receipt: %w", e)
+		}
+
+		receipts = append(receipts, r)
+	}
+
+	sort.Slice(receipts, func(i, j int) bool {
+		return receipts[i].Inbox < receipts[j].Inbox
+	})
+
+	return receipts, nil
+}
+
+func (s *Store) get(activityID, inbox string) (*Receipt, error) {
+	value, err := s.store.Get(hash(activityID, inbox))
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return nil, errNotFound
+		}
+
+		return nil, orberrors.NewTransient(fmt.Errorf("get delivery receipt: %w", err))
+	}
+
+	r := &Receipt{}
+
+	err = json.Unmarshal(value, r)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal delivery receipt: %w", err)
+	}
+
+	return r, nil
+}
+
+func (s *Store) newPutOperation(r *Receipt) (storage.Operation, error) {
+	value, err := s.marshal(r)
+	if err != nil {
+		return storage.Operation{}, fmt.Errorf("marshal delivery receipt: %w", err)
+	}
+
+	return storage.Operation{
+		Key:   hash(r.Activity, r.Inbox),
+		Value: value,
+		Tags: []storage.Tag{
+			{Name: activityTag, Value: hash(r.Activity)},
+			{Name: expiryTag, Value: strconv.FormatInt(r.Created.Add(s.retention).Unix(), 10)},
+		},
+	}, nil
+}
+
+func hash(values ...string) string {
+	h := sha256.Sum256([]byte(strings.Join(values, "\n")))
+
+	return hex.EncodeToString(h[:])
+}
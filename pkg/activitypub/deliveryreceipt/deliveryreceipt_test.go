@@ -0,0 +1,238 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deliveryreceipt
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+	"github.com/trustbloc/orb/pkg/store/expiry"
+	"github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+var (
+	activity1 = testutil.MustParseURL("https://domain1.com/services/orb/activities/1")
+	activity2 = testutil.MustParseURL("https://domain1.com/services/orb/activities/2")
+	inbox1    = testutil.MustParseURL("https://domain2.com/services/orb/inbox")
+	inbox2    = testutil.MustParseURL("https://domain3.com/services/orb/inbox")
+)
+
+func TestNewStore(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		es := &mockExpiryService{}
+
+		s, err := NewStore(mem.NewProvider(), es, 0)
+		require.NoError(t, err)
+		require.NotNil(t, s)
+		require.Equal(t, defaultRetention, s.retention)
+		require.Equal(t, storeName, es.storeName)
+		require.Equal(t, expiryTag, es.expiryTagName)
+	})
+
+	t.Run("Open store error", func(t *testing.T) {
+		p := &mocks.Provider{}
+		p.OpenStoreReturns(nil, errors.New("injected open error"))
+
+		_, err := NewStore(p, &mockExpiryService{}, time.Hour)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected open error")
+	})
+
+	t.Run("Set store config error", func(t *testing.T) {
+		p := &mocks.Provider{}
+		p.SetStoreConfigReturns(errors.New("injected config error"))
+
+		_, err := NewStore(p, &mockExpiryService{}, time.Hour)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected config error")
+	})
+}
+
+func TestStore(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		s, err := NewStore(mem.NewProvider(), &mockExpiryService{}, time.Hour)
+		require.NoError(t, err)
+
+		receipts, err := s.Get(activity1)
+		require.NoError(t, err)
+		require.Empty(t, receipts)
+
+		require.NoError(t, s.Pending(activity1, nil))
+		require.NoError(t, s.Pending(activity1, []*url.URL{inbox2, inbox1}))
+		require.NoError(t, s.Pending(activity2, []*url.URL{inbox1}))
+
+		receipts, err = s.Get(activity1)
+		require.NoError(t, err)
+		require.Len(t, receipts, 2)
+		require.Equal(t, inbox1.String(), receipts[0].Inbox)
+		require.Equal(t, StatusPending, receipts[0].Status)
+		require.Zero(t, receipts[0].Attempts)
+		require.Equal(t, inbox2.String(), receipts[1].Inbox)
+
+		s.DeliveryAttempted(activity1.String(), inbox1, errors.New("server responded with error 500"))
+		s.DeliveryAttempted(activity1.String(), inbox2, nil)
+
+		receipts, err = s.Get(activity1)
+		require.NoError(t, err)
+		require.Len(t, receipts, 2)
+		require.Equal(t, StatusFailed, receipts[0].Status)
+		require.Equal(t, 1, receipts[0].Attempts)
+		require.Contains(t, receipts[0].Error, "500")
+		require.NotNil(t, receipts[0].LastAttempt)
+		require.Equal(t, StatusDelivered, receipts[1].Status)
+
+		// Redelivery succeeds.
+		s.DeliveryAttempted(activity1.String(), inbox1, nil)
+
+		receipts, err = s.Get(activity1)
+		require.NoError(t, err)
+		require.Equal(t, StatusDelivered, receipts[0].Status)
+		require.Equal(t, 2, receipts[0].Attempts)
+		require.Empty(t, receipts[0].Error)
+
+		summary := NewSummary(activity1, receipts)
+		require.Equal(t, activity1.String(), summary.Activity)
+		require.Equal(t, 2, summary.Total)
+		require.Equal(t, 2, summary.Delivered)
+
+		receipts, err = s.Get(activity2)
+		require.NoError(t, err)
+		require.Len(t, receipts, 1)
+		require.Equal(t, StatusPending, receipts[0].Status)
+	})
+
+	t.Run("Delivery attempted without pending receipt", func(t *testing.T) {
+		s, err := NewStore(mem.NewProvider(), &mockExpiryService{}, time.Hour)
+		require.NoError(t, err)
+
+		s.DeliveryAttempted(activity1.String(), inbox1, nil)
+
+		receipts, err := s.Get(activity1)
+		require.NoError(t, err)
+		require.Len(t, receipts, 1)
+		require.Equal(t, StatusDelivered, receipts[0].Status)
+	})
+
+	t.Run("Store errors", func(t *testing.T) {
+		errExpected := errors.New("injected store error")
+
+		store := &mocks.Store{}
+		store.BatchReturns(errExpected)
+		store.GetReturns(nil, errExpected)
+		store.QueryReturns(nil, errExpected)
+
+		p := &mocks.Provider{}
+		p.OpenStoreReturns(store, nil)
+
+		s, err := NewStore(p, &mockExpiryService{}, time.Hour)
+		require.NoError(t, err)
+
+		require.ErrorIs(t, s.Pending(activity1, []*url.URL{inbox1}), errExpected)
+
+		_, err = s.Get(activity1)
+		require.ErrorIs(t, err, errExpected)
+
+		require.NotPanics(t, func() { s.DeliveryAttempted(activity1.String(), inbox1, nil) })
+		require.Zero(t, store.PutCallCount())
+
+		store.GetReturns(nil, storage.ErrDataNotFound)
+		store.PutReturns(errExpected)
+
+		require.NotPanics(t, func() { s.DeliveryAttempted(activity1.String(), inbox1, nil) })
+		require.Equal(t, 1, store.PutCallCount())
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		s, err := NewStore(mem.NewProvider(), &mockExpiryService{}, time.Hour)
+		require.NoError(t, err)
+
+		errExpected := errors.New("injected marshal error")
+
+		s.marshal = func(v interface{}) ([]byte, error) { return nil, errExpected }
+
+		require.ErrorIs(t, s.Pending(activity1, []*url.URL{inbox1}), errExpected)
+		require.NotPanics(t, func() { s.DeliveryAttempted(activity1.String(), inbox1, nil) })
+	})
+
+	t.Run("Iterator errors", func(t *testing.T) {
+		errExpected := errors.New("injected iterator error")
+
+		it := &mocks.Iterator{}
+		it.NextReturns(false, errExpected)
+
+		store := &mocks.Store{}
+		store.QueryReturns(it, nil)
+
+		p := &mocks.Provider{}
+		p.OpenStoreReturns(store, nil)
+
+		s, err := NewStore(p, &mockExpiryService{}, time.Hour)
+		require.NoError(t, err)
+
+		_, err = s.Get(activity1)
+		require.ErrorIs(t, err, errExpected)
+
+		it.NextReturns(true, nil)
+		it.ValueReturns(nil, errExpected)
+
+		_, err = s.Get(activity1)
+		require.ErrorIs(t, err, errExpected)
+
+		it.ValueReturns([]byte("xxx"), nil)
+
+		_, err = s.Get(activity1)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal delivery receipt")
+	})
+
+	t.Run("Unmarshal error", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.GetReturns([]byte("xxx"), nil)
+
+		p := &mocks.Provider{}
+		p.OpenStoreReturns(store, nil)
+
+		s, err := NewStore(p, &mockExpiryService{}, time.Hour)
+		require.NoError(t, err)
+
+		require.NotPanics(t, func() { s.DeliveryAttempted(activity1.String(), inbox1, nil) })
+		require.Zero(t, store.PutCallCount())
+	})
+}
+
+func TestNewSummary(t *testing.T) {
+	summary := NewSummary(activity1, nil)
+	require.NotNil(t, summary.Deliveries)
+	require.Zero(t, summary.Total)
+
+	summary = NewSummary(activity1, []*Receipt{
+		{Inbox: inbox1.String(), Status: StatusDelivered},
+		{Inbox: inbox2.String(), Status: StatusFailed},
+		{Inbox: "https://domain4.com/services/orb/inbox", Status: StatusPending},
+	})
+	require.Equal(t, 3, summary.Total)
+	require.Equal(t, 1, summary.Delivered)
+	require.Equal(t, 1, summary.Failed)
+	require.Equal(t, 1, summary.Pending)
+}
+
+type mockExpiryService struct {
+	storeName     string
+	expiryTagName string
+}
+
+func (m *mockExpiryService) Register(_ storage.Store, expiryTagName, storeName string, _ ...expiry.Option) {
+	m.storeName = storeName
+	m.expiryTagName = expiryTagName
+}
@@ -0,0 +1,205 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package evidence
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	"github.com/trustbloc/orb/pkg/activitypub/client/transport"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/store/expiry"
+)
+
+var logger = log.New("inbox-evidence")
+
+var (
+	// ErrNotFound is returned when the evidence for an activity isn't found.
+	ErrNotFound = errors.New("inbox evidence not found")
+
+	// ErrNoActivityID is returned by NewRecord if the request body doesn't contain an activity with an ID.
+	ErrNoActivityID = errors.New("request body does not contain an activity ID")
+)
+
+const (
+	storeName = "inbox-evidence"
+
+	// expiryTag holds the time (Unix time) after which the record is deleted.
+	expiryTag = "expiry"
+
+	defaultRetention = 30 * 24 * time.Hour
+)
+
+// Record is the raw HTTP request of an activity that was accepted by the inbox. The record holds the original
+// signed headers and body so that the HTTP signature of the request may be verified by a third party.
+type Record struct {
+	ActivityID   string    `json:"activityId"`
+	ActivityType string    `json:"activityType,omitempty"`
+	Actor        string    `json:"actor,omitempty"`
+	Received     time.Time `json:"received"`
+
+	Method     string      `json:"method"`
+	RequestURI string      `json:"requestUri"`
+	Host       string      `json:"host"`
+	Headers    http.Header `json:"headers"`
+	Body       []byte      `json:"body"`
+}
+
+// NewRecord returns a new evidence record for the given request and body. The Authorization header
+// isn't retained. If the body is compressed then it's retained as is, since the digest of the HTTP signature
+// is computed over the compressed body.
+func NewRecord(req *http.Request, body []byte) (*Record, error) {
+	activityBytes, err := decodeBody(req.Header.Get(transport.ContentEncodingHeader), body)
+	if err != nil {
+		return nil, fmt.Errorf("decode body: %w", err)
+	}
+
+	activity := &vocab.ActivityType{}
+
+	err = json.Unmarshal(activityBytes, activity)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal activity: %w", err)
+	}
+
+	if activity.ID() == nil {
+		return nil, ErrNoActivityID
+	}
+
+	headers := req.Header.Clone()
+	headers.Del("Authorization")
+
+	record := &Record{
+		ActivityID: activity.ID().String(),
+		Received:   time.Now().UTC(),
+		Method:     req.Method,
+		RequestURI: req.URL.RequestURI(),
+		Host:       req.Host,
+		Headers:    headers,
+		Body:       body,
+	}
+
+	if activity.Type() != nil {
+		record.ActivityType = activity.Type().String()
+	}
+
+	if activity.Actor() != nil {
+		record.Actor = activity.Actor().String()
+	}
+
+	return record, nil
+}
+
+func decodeBody(encoding string, body []byte) ([]byte, error) {
+	if !strings.EqualFold(strings.TrimSpace(encoding), transport.GzipContentEncoding) {
+		return body, nil
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	return ioutil.ReadAll(zr)
+}
+
+type expiryService interface {
+	Register(store storage.Store, expiryTagName, storeName string, opts ...expiry.Option)
+}
+
+// Store stores the raw HTTP requests of the activities that are accepted by the inbox so that disputes about
+// the HTTP signature of an activity may be resolved using the original request. The records are keyed by
+// activity ID and are deleted by the expiry service after the retention period.
+type Store struct {
+	store     storage.Store
+	retention time.Duration
+	marshal   func(v interface{}) ([]byte, error)
+}
+
+// NewStore returns a new inbox evidence store. If retention is 0 then a default of 30 days is used.
+func NewStore(provider storage.Provider, expiryService expiryService, retention time.Duration) (*Store, error) {
+	s, err := provider.OpenStore(storeName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open inbox evidence store: %w", err)
+	}
+
+	err = provider.SetStoreConfig(storeName, storage.StoreConfiguration{TagNames: []string{expiryTag}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set store configuration: %w", err)
+	}
+
+	expiryService.Register(s, expiryTag, storeName)
+
+	if retention == 0 {
+		retention = defaultRetention
+	}
+
+	return &Store{
+		store:     s,
+		retention: retention,
+		marshal:   json.Marshal,
+	}, nil
+}
+
+// Put stores the given record, replacing an existing record for the same activity.
+func (s *Store) Put(record *Record) error {
+	recordBytes, err := s.marshal(record)
+	if err != nil {
+		return fmt.Errorf("marshal inbox evidence for activity [%s]: %w", record.ActivityID, err)
+	}
+
+	err = s.store.Put(hash(record.ActivityID), recordBytes,
+		storage.Tag{Name: expiryTag, Value: strconv.FormatInt(record.Received.Add(s.retention).Unix(), 10)},
+	)
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("store inbox evidence for activity [%s]: %w", record.ActivityID, err))
+	}
+
+	logger.Debugf("Stored inbox evidence for activity [%s] from actor [%s]", record.ActivityID, record.Actor)
+
+	return nil
+}
+
+// Get returns the record for the given activity ID or ErrNotFound if the record doesn't exist.
+func (s *Store) Get(activityID string) (*Record, error) {
+	recordBytes, err := s.store.Get(hash(activityID))
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return nil, ErrNotFound
+		}
+
+		return nil, orberrors.NewTransient(fmt.Errorf("get inbox evidence for activity [%s]: %w", activityID, err))
+	}
+
+	record := &Record{}
+
+	err = json.Unmarshal(recordBytes, record)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal inbox evidence for activity [%s]: %w", activityID, err)
+	}
+
+	return record, nil
+}
+
+func hash(value string) string {
+	h := sha256.Sum256([]byte(value))
+
+	return hex.EncodeToString(h[:])
+}
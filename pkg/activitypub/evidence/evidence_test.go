@@ -0,0 +1,201 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package evidence
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/store/expiry"
+	"github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+const (
+	inboxPath   = "/services/orb/inbox"
+	activityID1 = "https://orb.domain2.com/activities/1"
+	activity1   = `{"@context":"https://www.w3.org/ns/activitystreams","id":"` + activityID1 + `",` +
+		`"type":"Follow","actor":"https://orb.domain2.com/services/orb",` +
+		`"object":"https://orb.domain1.com/services/orb"}`
+)
+
+func TestNewRecord(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		req := newInboxRequest(t, []byte(activity1))
+
+		record, err := NewRecord(req, []byte(activity1))
+		require.NoError(t, err)
+		require.Equal(t, activityID1, record.ActivityID)
+		require.Equal(t, "Follow", record.ActivityType)
+		require.Equal(t, "https://orb.domain2.com/services/orb", record.Actor)
+		require.Equal(t, http.MethodPost, record.Method)
+		require.Equal(t, inboxPath, record.RequestURI)
+		require.Equal(t, "orb.domain1.com", record.Host)
+		require.Equal(t, req.Header.Get("Signature"), record.Headers.Get("Signature"))
+		require.Equal(t, req.Header.Get("Date"), record.Headers.Get("Date"))
+		require.Empty(t, record.Headers.Get("Authorization"))
+		require.Equal(t, activity1, string(record.Body))
+	})
+
+	t.Run("Compressed body", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+
+		zw := gzip.NewWriter(buf)
+		_, err := zw.Write([]byte(activity1))
+		require.NoError(t, err)
+		require.NoError(t, zw.Close())
+
+		req := newInboxRequest(t, buf.Bytes())
+		req.Header.Set("Content-Encoding", "gzip")
+
+		record, err := NewRecord(req, buf.Bytes())
+		require.NoError(t, err)
+		require.Equal(t, activityID1, record.ActivityID)
+		require.Equal(t, buf.Bytes(), record.Body)
+
+		_, err = NewRecord(req, []byte(activity1))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "decode body")
+	})
+
+	t.Run("Not an activity", func(t *testing.T) {
+		_, err := NewRecord(newInboxRequest(t, []byte("xxx")), []byte("xxx"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal activity")
+	})
+
+	t.Run("No activity ID", func(t *testing.T) {
+		body := []byte(`{"@context":"https://www.w3.org/ns/activitystreams","type":"Follow"}`)
+
+		_, err := NewRecord(newInboxRequest(t, body), body)
+		require.ErrorIs(t, err, ErrNoActivityID)
+	})
+}
+
+func TestStore(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		es := &mockExpiryService{}
+
+		s, err := NewStore(mem.NewProvider(), es, time.Hour)
+		require.NoError(t, err)
+		require.Equal(t, time.Hour, s.retention)
+		require.Equal(t, storeName, es.storeName)
+		require.Equal(t, expiryTag, es.expiryTagName)
+
+		_, err = s.Get(activityID1)
+		require.ErrorIs(t, err, ErrNotFound)
+
+		record, err := NewRecord(newInboxRequest(t, []byte(activity1)), []byte(activity1))
+		require.NoError(t, err)
+
+		require.NoError(t, s.Put(record))
+
+		r, err := s.Get(activityID1)
+		require.NoError(t, err)
+		require.Equal(t, record.Body, r.Body)
+		require.Equal(t, record.Headers, r.Headers)
+	})
+
+	t.Run("Default retention", func(t *testing.T) {
+		s, err := NewStore(mem.NewProvider(), &mockExpiryService{}, 0)
+		require.NoError(t, err)
+		require.Equal(t, defaultRetention, s.retention)
+	})
+
+	t.Run("Open store error", func(t *testing.T) {
+		p := &mocks.Provider{}
+		p.OpenStoreReturns(nil, errors.New("injected open error"))
+
+		_, err := NewStore(p, &mockExpiryService{}, time.Hour)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected open error")
+	})
+
+	t.Run("Set store config error", func(t *testing.T) {
+		p := &mocks.Provider{}
+		p.SetStoreConfigReturns(errors.New("injected config error"))
+
+		_, err := NewStore(p, &mockExpiryService{}, time.Hour)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected config error")
+	})
+
+	t.Run("Store errors", func(t *testing.T) {
+		errExpected := errors.New("injected store error")
+
+		store := &mocks.Store{}
+		store.PutReturns(errExpected)
+		store.GetReturns(nil, errExpected)
+
+		p := &mocks.Provider{}
+		p.OpenStoreReturns(store, nil)
+
+		s, err := NewStore(p, &mockExpiryService{}, time.Hour)
+		require.NoError(t, err)
+
+		require.ErrorIs(t, s.Put(&Record{ActivityID: activityID1}), errExpected)
+
+		_, err = s.Get(activityID1)
+		require.ErrorIs(t, err, errExpected)
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		s, err := NewStore(mem.NewProvider(), &mockExpiryService{}, time.Hour)
+		require.NoError(t, err)
+
+		errExpected := errors.New("injected marshal error")
+
+		s.marshal = func(v interface{}) ([]byte, error) { return nil, errExpected }
+
+		require.ErrorIs(t, s.Put(&Record{ActivityID: activityID1}), errExpected)
+	})
+
+	t.Run("Unmarshal error", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.GetReturns([]byte("xxx"), nil)
+
+		p := &mocks.Provider{}
+		p.OpenStoreReturns(store, nil)
+
+		s, err := NewStore(p, &mockExpiryService{}, time.Hour)
+		require.NoError(t, err)
+
+		_, err = s.Get(activityID1)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal inbox evidence")
+	})
+}
+
+func newInboxRequest(t *testing.T, body []byte) *http.Request {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "https://orb.domain1.com"+inboxPath, bytes.NewReader(body))
+	req.Header.Set("Date", "Tue, 07 Jun 2022 20:51:35 GMT")
+	req.Header.Set("Digest", "SHA-256=xxx")
+	req.Header.Set("Signature", `keyId="https://orb.domain2.com/services/orb/keys/main-key",signature="xxx"`)
+	req.Header.Set("Authorization", "Bearer xxx")
+
+	return req
+}
+
+type mockExpiryService struct {
+	storeName     string
+	expiryTagName string
+}
+
+func (m *mockExpiryService) Register(_ storage.Store, expiryTagName, storeName string, _ ...expiry.Option) {
+	m.storeName = storeName
+	m.expiryTagName = expiryTagName
+}
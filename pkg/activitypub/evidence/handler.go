@@ -0,0 +1,108 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package evidence
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+)
+
+const (
+	// Path is the path of the inbox evidence endpoint.
+	Path = "/inbox-evidence"
+
+	// activityIDParam is the query parameter that holds the ID of the activity.
+	activityIDParam = "id"
+
+	badRequestResponse          = "Bad Request."
+	notFoundResponse            = "Not Found."
+	internalServerErrorResponse = "Internal Server Error."
+)
+
+type recordRetriever interface {
+	Get(activityID string) (*Record, error)
+}
+
+// Handler implements a REST handler that returns the raw inbox request of the activity with the given ID,
+// for example: GET /inbox-evidence?id=https://orb.domain1.com/services/orb/activities/1234.
+type Handler struct {
+	store   recordRetriever
+	marshal func(v interface{}) ([]byte, error)
+}
+
+// NewHandler returns a new inbox evidence handler.
+func NewHandler(store recordRetriever) *Handler {
+	return &Handler{
+		store:   store,
+		marshal: json.Marshal,
+	}
+}
+
+// Path returns the HTTP REST endpoint of the handler.
+func (h *Handler) Path() string {
+	return Path
+}
+
+// Method returns the HTTP method, which is always GET.
+func (h *Handler) Method() string {
+	return http.MethodGet
+}
+
+// Handler returns the HTTP REST handle.
+func (h *Handler) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Handler) handle(w http.ResponseWriter, req *http.Request) {
+	activityID := req.URL.Query().Get(activityIDParam)
+	if activityID == "" {
+		logger.Debugf("[%s] Missing query parameter [%s]", Path, activityIDParam)
+
+		writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	record, err := h.store.Get(activityID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			writeResponse(w, http.StatusNotFound, []byte(notFoundResponse))
+
+			return
+		}
+
+		logger.Errorf("[%s] Error retrieving inbox evidence for activity [%s]: %s", Path, activityID, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	recordBytes, err := h.marshal(record)
+	if err != nil {
+		logger.Errorf("[%s] Error marshalling inbox evidence for activity [%s]: %s", Path, activityID, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	writeResponse(w, http.StatusOK, recordBytes)
+}
+
+func writeResponse(w http.ResponseWriter, status int, body []byte) {
+	w.WriteHeader(status)
+
+	if _, err := w.Write(body); err != nil {
+		logger.Warnf("[%s] Unable to write response: %s", Path, err)
+	}
+}
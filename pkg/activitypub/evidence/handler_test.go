@@ -0,0 +1,80 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package evidence
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/internal/testutil/httptestutil"
+)
+
+func TestHandler(t *testing.T) {
+	s, err := NewStore(mem.NewProvider(), &mockExpiryService{}, time.Hour)
+	require.NoError(t, err)
+
+	record, err := NewRecord(newInboxRequest(t, []byte(activity1)), []byte(activity1))
+	require.NoError(t, err)
+
+	require.NoError(t, s.Put(record))
+
+	h := NewHandler(s)
+	require.Equal(t, Path, h.Path())
+	require.Equal(t, http.MethodGet, h.Method())
+	require.NotNil(t, h.Handler())
+
+	t.Run("Success", func(t *testing.T) {
+		code, body := httptestutil.Get(t, h.handle, Path+"?id="+url.QueryEscape(activityID1))
+		require.Equal(t, http.StatusOK, code)
+
+		r := &Record{}
+		require.NoError(t, json.Unmarshal(body, r))
+		require.Equal(t, activityID1, r.ActivityID)
+		require.Equal(t, activity1, string(r.Body))
+		require.Equal(t, record.Headers.Get("Signature"), r.Headers.Get("Signature"))
+	})
+
+	t.Run("Missing activity ID", func(t *testing.T) {
+		code, _ := httptestutil.Get(t, h.handle, Path)
+		require.Equal(t, http.StatusBadRequest, code)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		code, _ := httptestutil.Get(t, h.handle, Path+"?id="+url.QueryEscape("https://orb.domain2.com/activities/2"))
+		require.Equal(t, http.StatusNotFound, code)
+	})
+
+	t.Run("Store error", func(t *testing.T) {
+		code, _ := httptestutil.Get(t, NewHandler(&mockStore{err: errors.New("injected store error")}).handle,
+			Path+"?id="+url.QueryEscape(activityID1))
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		h := NewHandler(s)
+		h.marshal = func(v interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		code, _ := httptestutil.Get(t, h.handle, Path+"?id="+url.QueryEscape(activityID1))
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+}
+
+type mockStore struct {
+	record *Record
+	err    error
+}
+
+func (m *mockStore) Get(string) (*Record, error) {
+	return m.record, m.err
+}
@@ -0,0 +1,333 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fed
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/trustbloc/edge-core/pkg/log"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+
+	"github.com/trustbloc/orb/pkg/activitypub/client"
+	"github.com/trustbloc/orb/pkg/activitypub/client/transport"
+	"github.com/trustbloc/orb/pkg/activitypub/resthandler"
+	apservice "github.com/trustbloc/orb/pkg/activitypub/service"
+	"github.com/trustbloc/orb/pkg/activitypub/service/spi"
+	store "github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/httpserver/auth"
+	"github.com/trustbloc/orb/pkg/lifecycle"
+	"github.com/trustbloc/orb/pkg/metrics"
+	"github.com/trustbloc/orb/pkg/pubsub/mempubsub"
+	"github.com/trustbloc/orb/pkg/resolver/resource"
+)
+
+var logger = log.New("activitypub_fed")
+
+const defaultPageSize = 50
+
+type signatureVerifier interface {
+	VerifyRequest(req *http.Request) (bool, *url.URL, error)
+}
+
+type authTokenManager interface {
+	IsAuthRequired(endpoint, method string) (bool, error)
+	RequiredAuthTokens(endpoint, method string) ([]string, error)
+}
+
+type resourceResolver interface {
+	ResolveHostMetaLink(uri, linkType string) (string, error)
+}
+
+type metricsProvider interface {
+	InboxHandlerTime(activityType string, value time.Duration)
+	OutboxPostTime(value time.Duration)
+	OutboxResolveInboxesTime(value time.Duration)
+	OutboxIncrementActivityCount(activityType string)
+}
+
+// Config holds the configuration parameters of the federation layer.
+type Config struct {
+	// ServiceEndpoint is the base path of the ActivityPub service (for example, "/services/orb").
+	ServiceEndpoint string
+
+	// ServiceIRI is the IRI of the ActivityPub service (actor).
+	ServiceIRI *url.URL
+
+	// PublicKey (optional) is the public key of the service which is used by other servers to verify
+	// HTTP signatures. If set then the service and public key endpoints are included in the HTTP handlers.
+	PublicKey *vocab.PublicKeyType
+
+	// PublicKeyIRI is the ID of the public key that is included in the HTTP signatures of outbound requests.
+	PublicKeyIRI *url.URL
+
+	VerifyActorInSignature    bool
+	PageSize                  int
+	ActivityHandlerBufferSize int
+	MaxWitnessDelay           time.Duration
+	IRICacheSize              int
+	IRICacheExpiration        time.Duration
+	ClientCacheSize           int
+	ClientCacheExpiration     time.Duration
+}
+
+// Providers contains the dependencies of the federation layer. Store, GetSigner, PostSigner, and
+// SignatureVerifier are required. Defaults are used for the remaining providers if they aren't set.
+type Providers struct {
+	// Store is the ActivityPub store.
+	Store store.Store
+
+	// GetSigner signs outbound HTTP GET requests.
+	GetSigner transport.Signer
+
+	// PostSigner signs outbound HTTP POST requests.
+	PostSigner transport.Signer
+
+	// SignatureVerifier verifies the HTTP signatures of inbound requests.
+	SignatureVerifier signatureVerifier
+
+	// HTTPClient is used for outbound requests. Defaults to a new http.Client.
+	HTTPClient *http.Client
+
+	// PubSub delivers activities to the inbox and outbox handlers. Defaults to an in-memory publisher/subscriber
+	// which is only suitable for a single instance.
+	PubSub apservice.PubSub
+
+	// AuthTokenManager determines which requests require authorization. Defaults to a token manager that doesn't
+	// require authorization for any endpoint.
+	AuthTokenManager authTokenManager
+
+	// ResourceResolver resolves host-meta links. Defaults to a resolver that uses HTTPClient.
+	ResourceResolver resourceResolver
+
+	// Metrics defaults to the Orb metrics provider.
+	Metrics metricsProvider
+}
+
+// Federator embeds the Orb ActivityPub federation layer (inbox, outbox, and activity handlers) into
+// another service.
+type Federator struct {
+	*lifecycle.Lifecycle
+
+	service    *apservice.Service
+	client     *client.Client
+	handlers   []common.HTTPHandler
+	pubSub     apservice.PubSub
+	ownsPubSub bool
+}
+
+// New returns a new Federator. The given handler options are passed to the inbox and outbox activity handlers.
+func New(cfg *Config, p *Providers, opts ...spi.HandlerOpt) (*Federator, error) {
+	if err := validate(cfg, p); err != nil {
+		return nil, err
+	}
+
+	d, err := resolveDependencies(p)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &Federator{pubSub: p.PubSub}
+
+	if f.pubSub == nil {
+		logger.Infof("Using in-memory publisher/subscriber for the ActivityPub service")
+
+		f.pubSub = mempubsub.New(mempubsub.DefaultConfig())
+		f.ownsPubSub = true
+	}
+
+	t := transport.New(d.httpClient, cfg.PublicKeyIRI, p.GetSigner, p.PostSigner, d.tm)
+
+	f.client = client.New(client.Config{
+		CacheSize:       cfg.ClientCacheSize,
+		CacheExpiration: cfg.ClientCacheExpiration,
+	}, t)
+
+	f.service, err = apservice.New(
+		&apservice.Config{
+			ServiceEndpoint:           cfg.ServiceEndpoint,
+			ServiceIRI:                cfg.ServiceIRI,
+			ActivityHandlerBufferSize: cfg.ActivityHandlerBufferSize,
+			VerifyActorInSignature:    cfg.VerifyActorInSignature,
+			MaxWitnessDelay:           cfg.MaxWitnessDelay,
+			IRICacheSize:              cfg.IRICacheSize,
+			IRICacheExpiration:        cfg.IRICacheExpiration,
+		},
+		p.Store, t, p.SignatureVerifier, f.pubSub, f.client, d.resourceResolver, d.tm, d.metrics, opts...,
+	)
+	if err != nil {
+		f.closePubSub()
+
+		return nil, fmt.Errorf("create ActivityPub service: %w", err)
+	}
+
+	f.handlers = newHTTPHandlers(cfg, p.Store, p.SignatureVerifier, d.tm, f.service)
+
+	f.Lifecycle = lifecycle.New("activitypub-fed",
+		lifecycle.WithStart(f.start),
+		lifecycle.WithStop(f.stop),
+	)
+
+	return f, nil
+}
+
+// Outbox returns the outbox, which allows the embedding service to post activities.
+func (f *Federator) Outbox() spi.Outbox {
+	return f.service.Outbox()
+}
+
+// InboxHandler returns the handler for inbox activities.
+func (f *Federator) InboxHandler() spi.InboxHandler {
+	return f.service.InboxHandler()
+}
+
+// Subscribe allows the embedding service to receive activities that were handled by the inbox.
+func (f *Federator) Subscribe() <-chan *vocab.ActivityType {
+	return f.service.Subscribe()
+}
+
+// ApproveFollow approves the given 'Follow' activity which is pending approval.
+func (f *Federator) ApproveFollow(followID *url.URL) error {
+	return f.service.ApproveFollow(followID)
+}
+
+// RejectFollow rejects the given 'Follow' activity which is pending approval.
+func (f *Federator) RejectFollow(followID *url.URL) error {
+	return f.service.RejectFollow(followID)
+}
+
+// Client returns the ActivityPub client which retrieves actors and collections from remote servers.
+func (f *Federator) Client() *client.Client {
+	return f.client
+}
+
+// HTTPHandlers returns the HTTP handlers of the inbox, outbox, and collection endpoints. These handlers
+// must be registered with the HTTP server of the embedding service.
+func (f *Federator) HTTPHandlers() []common.HTTPHandler {
+	return f.handlers
+}
+
+func (f *Federator) start() {
+	f.service.Start()
+}
+
+func (f *Federator) stop() {
+	f.service.Stop()
+	f.closePubSub()
+}
+
+func (f *Federator) closePubSub() {
+	if !f.ownsPubSub {
+		return
+	}
+
+	if err := f.pubSub.Close(); err != nil {
+		logger.Warnf("Error closing publisher/subscriber: %s", err)
+	}
+}
+
+func newHTTPHandlers(cfg *Config, s store.Store, verifier signatureVerifier, tm authTokenManager,
+	service *apservice.Service) []common.HTTPHandler {
+	pageSize := cfg.PageSize
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+
+	handlerCfg := &resthandler.Config{
+		BasePath:               cfg.ServiceEndpoint,
+		ObjectIRI:              cfg.ServiceIRI,
+		VerifyActorInSignature: cfg.VerifyActorInSignature,
+		PageSize:               pageSize,
+	}
+
+	handlers := []common.HTTPHandler{
+		service.InboxHTTPHandler(),
+		resthandler.NewPostOutbox(handlerCfg, service.Outbox(), s, verifier, tm),
+		resthandler.NewOutbox(handlerCfg, s, verifier, store.SortAscending, tm),
+		resthandler.NewInbox(handlerCfg, s, verifier, store.SortAscending, tm),
+		resthandler.NewFollowers(handlerCfg, s, verifier, tm),
+		resthandler.NewFollowing(handlerCfg, s, verifier, tm),
+		resthandler.NewWitnesses(handlerCfg, s, verifier, tm),
+		resthandler.NewWitnessing(handlerCfg, s, verifier, tm),
+		resthandler.NewLiked(handlerCfg, s, verifier, tm),
+		resthandler.NewLikes(handlerCfg, s, verifier, store.SortAscending, tm),
+		resthandler.NewShares(handlerCfg, s, verifier, store.SortAscending, tm),
+		resthandler.NewActivity(handlerCfg, s, verifier, store.SortAscending, tm),
+	}
+
+	if cfg.PublicKey != nil {
+		handlers = append(handlers,
+			resthandler.NewServices(handlerCfg, s, cfg.PublicKey, tm),
+			resthandler.NewPublicKeys(handlerCfg, s, cfg.PublicKey, tm),
+		)
+	}
+
+	return handlers
+}
+
+// dependencies contains the providers with defaults applied.
+type dependencies struct {
+	httpClient       *http.Client
+	tm               authTokenManager
+	resourceResolver resourceResolver
+	metrics          metricsProvider
+}
+
+func resolveDependencies(p *Providers) (*dependencies, error) {
+	d := &dependencies{
+		httpClient:       p.HTTPClient,
+		tm:               p.AuthTokenManager,
+		resourceResolver: p.ResourceResolver,
+		metrics:          p.Metrics,
+	}
+
+	if d.httpClient == nil {
+		d.httpClient = &http.Client{}
+	}
+
+	if d.tm == nil {
+		tm, err := auth.NewTokenManager(auth.Config{})
+		if err != nil {
+			return nil, fmt.Errorf("create auth token manager: %w", err)
+		}
+
+		d.tm = tm
+	}
+
+	if d.resourceResolver == nil {
+		d.resourceResolver = resource.New(d.httpClient, nil)
+	}
+
+	if d.metrics == nil {
+		d.metrics = metrics.Get()
+	}
+
+	return d, nil
+}
+
+func validate(cfg *Config, p *Providers) error {
+	switch {
+	case cfg == nil:
+		return errors.New("config is required")
+	case cfg.ServiceIRI == nil:
+		return errors.New("service IRI is required")
+	case cfg.PublicKeyIRI == nil:
+		return errors.New("public key IRI is required")
+	case p == nil || p.Store == nil:
+		return errors.New("ActivityPub store is required")
+	case p.GetSigner == nil || p.PostSigner == nil:
+		return errors.New("HTTP signers are required")
+	case p.SignatureVerifier == nil:
+		return errors.New("signature verifier is required")
+	default:
+		return nil
+	}
+}
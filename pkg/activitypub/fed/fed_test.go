@@ -0,0 +1,132 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fed
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	clientmocks "github.com/trustbloc/orb/pkg/activitypub/client/mocks"
+	"github.com/trustbloc/orb/pkg/activitypub/service/mocks"
+	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+	orbmocks "github.com/trustbloc/orb/pkg/mocks"
+)
+
+const serviceEndpoint = "/services/orb"
+
+var (
+	serviceIRI   = testutil.MustParseURL("https://example.com/services/orb")
+	publicKeyIRI = testutil.MustParseURL("https://example.com/services/orb/keys/main-key")
+)
+
+func TestNew(t *testing.T) {
+	t.Run("Success - defaults", func(t *testing.T) {
+		f, err := New(newConfig(), newProviders())
+		require.NoError(t, err)
+		require.NotNil(t, f)
+
+		f.Start()
+		defer f.Stop()
+
+		require.NotNil(t, f.Outbox())
+		require.NotNil(t, f.InboxHandler())
+		require.NotNil(t, f.Subscribe())
+		require.NotNil(t, f.Client())
+		require.Len(t, f.HTTPHandlers(), 12)
+
+		for _, h := range f.HTTPHandlers() {
+			require.Contains(t, h.Path(), serviceEndpoint)
+		}
+	})
+
+	t.Run("Success - with public key and custom providers", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.PublicKey = vocab.NewPublicKey(
+			vocab.WithID(publicKeyIRI),
+			vocab.WithOwner(serviceIRI),
+			vocab.WithPublicKeyPem("-----BEGIN PUBLIC KEY-----"),
+		)
+
+		p := newProviders()
+		p.HTTPClient = &http.Client{}
+		p.PubSub = mocks.NewPubSub()
+		p.AuthTokenManager = &mockAuthTokenManager{}
+		p.ResourceResolver = &mocks.WebFingerResolver{}
+		p.Metrics = &orbmocks.MetricsProvider{}
+
+		f, err := New(cfg, p)
+		require.NoError(t, err)
+		require.NotNil(t, f)
+		require.Len(t, f.HTTPHandlers(), 14)
+
+		f.Start()
+		f.Stop()
+	})
+
+	t.Run("Validation errors", func(t *testing.T) {
+		_, err := New(nil, newProviders())
+		require.EqualError(t, err, "config is required")
+
+		cfg := newConfig()
+		cfg.ServiceIRI = nil
+
+		_, err = New(cfg, newProviders())
+		require.EqualError(t, err, "service IRI is required")
+
+		cfg = newConfig()
+		cfg.PublicKeyIRI = nil
+
+		_, err = New(cfg, newProviders())
+		require.EqualError(t, err, "public key IRI is required")
+
+		_, err = New(newConfig(), nil)
+		require.EqualError(t, err, "ActivityPub store is required")
+
+		p := newProviders()
+		p.PostSigner = nil
+
+		_, err = New(newConfig(), p)
+		require.EqualError(t, err, "HTTP signers are required")
+
+		p = newProviders()
+		p.SignatureVerifier = nil
+
+		_, err = New(newConfig(), p)
+		require.EqualError(t, err, "signature verifier is required")
+	})
+}
+
+func newConfig() *Config {
+	return &Config{
+		ServiceEndpoint: serviceEndpoint,
+		ServiceIRI:      serviceIRI,
+		PublicKeyIRI:    publicKeyIRI,
+	}
+}
+
+func newProviders() *Providers {
+	return &Providers{
+		Store:             memstore.New(serviceEndpoint),
+		GetSigner:         &clientmocks.HTTPSigner{},
+		PostSigner:        &clientmocks.HTTPSigner{},
+		SignatureVerifier: &mocks.SignatureVerifier{},
+	}
+}
+
+type mockAuthTokenManager struct{}
+
+func (m *mockAuthTokenManager) IsAuthRequired(string, string) (bool, error) {
+	return false, nil
+}
+
+func (m *mockAuthTokenManager) RequiredAuthTokens(string, string) ([]string, error) {
+	return nil, nil
+}
@@ -8,16 +8,17 @@ package httpsig
 
 import (
 	"crypto/ed25519"
-	"crypto/x509"
-	"encoding/pem"
 	"errors"
 	"fmt"
 	"net/url"
+	"sync"
 
 	"github.com/hyperledger/aries-framework-go/pkg/crypto"
 	ariesverifier "github.com/hyperledger/aries-framework-go/pkg/doc/signature/verifier"
 	"github.com/hyperledger/aries-framework-go/pkg/kms"
 	httpsig "github.com/igor-pavlenko/httpsignatures-go"
+
+	"github.com/trustbloc/orb/pkg/activitypub/multikey"
 )
 
 const orbHTTPSigAlgorithm = "https://github.com/trustbloc/orb/httpsig"
@@ -30,16 +31,28 @@ type keyResolver interface {
 	Resolve(keyID string) (*ariesverifier.PublicKey, error)
 }
 
+// KeyManager returns a handle to a signing key. It is implemented by the Aries KMS as well as
+// by the external KMS backends (AWS KMS and Vault Transit).
+type KeyManager interface {
+	Get(keyID string) (interface{}, error)
+}
+
+// Crypto signs data using a key handle returned by the KeyManager.
+type Crypto interface {
+	Sign(msg []byte, kh interface{}) ([]byte, error)
+}
+
 // SignatureHashAlgorithm is a custom httpsignatures.SignatureHashAlgorithm that uses KMS to sign HTTP requests.
 type SignatureHashAlgorithm struct {
-	Crypto      crypto.Crypto
-	KMS         kms.KeyManager
+	Crypto      Crypto
+	KMS         KeyManager
 	keyResolver keyResolver
 	keyID       string
+	mutex       sync.RWMutex
 }
 
 // NewSignerAlgorithm returns a new SignatureHashAlgorithm which uses KMS to sign HTTP requests.
-func NewSignerAlgorithm(c crypto.Crypto, km kms.KeyManager, keyID string) *SignatureHashAlgorithm {
+func NewSignerAlgorithm(c Crypto, km KeyManager, keyID string) *SignatureHashAlgorithm {
 	return &SignatureHashAlgorithm{
 		Crypto: c,
 		KMS:    km,
@@ -62,9 +75,28 @@ func (a *SignatureHashAlgorithm) Algorithm() string {
 	return orbHTTPSigAlgorithm
 }
 
+// SetKeyID sets the ID of the KMS key that is used to sign requests. This function may be called
+// while requests are being signed, for example when the key is rotated.
+func (a *SignatureHashAlgorithm) SetKeyID(keyID string) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.keyID = keyID
+}
+
+// KeyID returns the ID of the KMS key that is used to sign requests.
+func (a *SignatureHashAlgorithm) KeyID() string {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+
+	return a.keyID
+}
+
 // Create signs data with the secret.
 func (a *SignatureHashAlgorithm) Create(secret httpsig.Secret, data []byte) ([]byte, error) {
-	kh, err := a.KMS.Get(a.keyID)
+	keyID := a.KeyID()
+
+	kh, err := a.KMS.Get(keyID)
 	if err != nil {
 		return nil, fmt.Errorf("get key handle: %w", err)
 	}
@@ -76,7 +108,7 @@ func (a *SignatureHashAlgorithm) Create(secret httpsig.Secret, data []byte) ([]b
 		return nil, fmt.Errorf("sign data: %w", err)
 	}
 
-	logger.Debugf("... successfully signed data with keyID from KMS [%s]", a.keyID)
+	logger.Debugf("... successfully signed data with keyID from KMS [%s]", keyID)
 
 	return sig, nil
 }
@@ -129,21 +161,16 @@ func (r *KeyResolver) Resolve(keyID string) (*ariesverifier.PublicKey, error) {
 		return nil, fmt.Errorf("retrieve public key for ID [%s]: %w", keyID, err)
 	}
 
-	block, rest := pem.Decode([]byte(pubKey.PublicKeyPem))
-	if block == nil {
-		logger.Warnf("invalid public key: nil block. Rest: %s", rest)
-
-		return nil, fmt.Errorf("invalid public key for ID [%s]: nil block", keyID)
-	}
-
-	pk, err := x509.ParsePKIXPublicKey(block.Bytes)
+	pk, err := multikey.Ed25519PublicKey(pubKey)
 	if err != nil {
-		return nil, fmt.Errorf("parse public key for ID [%s]: %w", keyID, err)
+		logger.Warnf("Invalid public key for ID [%s]: %s", keyID, err)
+
+		return nil, fmt.Errorf("invalid public key for ID [%s]: %w", keyID, err)
 	}
 
 	return &ariesverifier.PublicKey{
 		Type:  kms.ED25519, // TODO: Support other algorithms?
-		Value: pk.(ed25519.PublicKey),
+		Value: pk,
 	}, nil
 }
 
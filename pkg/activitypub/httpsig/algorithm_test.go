@@ -20,6 +20,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/trustbloc/orb/pkg/activitypub/mocks"
+	"github.com/trustbloc/orb/pkg/activitypub/multikey"
 	servicemocks "github.com/trustbloc/orb/pkg/activitypub/service/mocks"
 	"github.com/trustbloc/orb/pkg/activitypub/vocab"
 	"github.com/trustbloc/orb/pkg/internal/testutil"
@@ -153,6 +154,24 @@ func TestKeyResolver_Resolve(t *testing.T) {
 		require.NotNil(t, pk)
 	})
 
+	t.Run("Multibase key -> success", func(t *testing.T) {
+		multibaseKey, err := multikey.EncodeEd25519(pubKey)
+		require.NoError(t, err)
+
+		resolver := NewKeyResolver(servicemocks.NewActivitPubClient().
+			WithPublicKey(vocab.NewPublicKey(
+				vocab.WithID(pubKeyIRI),
+				vocab.WithPublicKeyMultibase(multibaseKey),
+			)),
+		)
+		require.NotNil(t, resolver)
+
+		pk, err := resolver.Resolve(pubKeyIRI.String())
+		require.NoError(t, err)
+		require.NotNil(t, pk)
+		require.Equal(t, []byte(pubKey), pk.Value)
+	})
+
 	t.Run("Invalid key ID -> error", func(t *testing.T) {
 		resolver := NewKeyResolver(pubKeyRetriever)
 		require.NotNil(t, resolver)
@@ -11,8 +11,6 @@ import (
 	"net/http"
 	"time"
 
-	"github.com/hyperledger/aries-framework-go/pkg/crypto"
-	"github.com/hyperledger/aries-framework-go/pkg/kms"
 	httpsig "github.com/igor-pavlenko/httpsignatures-go"
 	"github.com/trustbloc/edge-core/pkg/log"
 )
@@ -49,16 +47,18 @@ type signer interface {
 // Signer signs HTTP requests.
 type Signer struct {
 	SignerConfig
+	algo   *SignatureHashAlgorithm
 	signer func() signer
 }
 
 // NewSigner returns a new signer.
-func NewSigner(cfg SignerConfig, cr crypto.Crypto, km kms.KeyManager, keyID string) *Signer {
+func NewSigner(cfg SignerConfig, cr Crypto, km KeyManager, keyID string) *Signer {
 	algo := NewSignerAlgorithm(cr, km, keyID)
 	secretRetriever := &SecretRetriever{}
 
 	return &Signer{
 		SignerConfig: cfg,
+		algo:         algo,
 		signer: func() signer {
 			// Return a new instance for each signature since the HTTP signature
 			// implementation is not thread safe.
@@ -71,6 +71,14 @@ func NewSigner(cfg SignerConfig, cr crypto.Crypto, km kms.KeyManager, keyID stri
 	}
 }
 
+// SetKeyID sets the ID of the KMS key that is used to sign requests. Requests that are signed
+// after this call use the new key.
+func (s *Signer) SetKeyID(keyID string) {
+	logger.Infof("Setting HTTP signature key ID to [%s]", keyID)
+
+	s.algo.SetKeyID(keyID)
+}
+
 // SignRequest signs an HTTP request.
 func (s *Signer) SignRequest(pubKeyID string, req *http.Request) error {
 	req.Header.Add(dateHeader, date())
@@ -47,6 +47,20 @@ func TestSigner(t *testing.T) {
 		require.NotEmpty(t, req.Header["Signature"])
 	})
 
+	t.Run("Set key ID", func(t *testing.T) {
+		s := NewSigner(DefaultGetSignerConfig(), &mockcrypto.Crypto{}, &mockkms.KeyManager{}, keyID)
+		require.Equal(t, keyID, s.algo.KeyID())
+
+		s.SetKeyID("654321")
+		require.Equal(t, "654321", s.algo.KeyID())
+
+		req, err := http.NewRequest(http.MethodGet, "https://domain1.com", nil)
+		require.NoError(t, err)
+
+		require.NoError(t, s.SignRequest("pubKeyID", req))
+		require.NotEmpty(t, req.Header["Signature"])
+	})
+
 	t.Run("Signer error", func(t *testing.T) {
 		errExpected := errors.New("injected KMS error")
 
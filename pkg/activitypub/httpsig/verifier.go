@@ -27,6 +27,8 @@ type actorRetriever interface {
 	publicKeyRetriever
 
 	GetActor(actorIRI *url.URL) (*vocab.ActorType, error)
+	InvalidateActor(actorIRI *url.URL)
+	InvalidatePublicKey(keyIRI *url.URL)
 }
 
 type verifier interface {
@@ -68,13 +70,6 @@ func NewVerifier(actorRetriever actorRetriever, cr crypto.Crypto, km kms.KeyMana
 func (v *Verifier) VerifyRequest(req *http.Request) (bool, *url.URL, error) {
 	logger.Debugf("Verifying request. Headers: %s", req.Header)
 
-	err := v.verifier().Verify(req)
-	if err != nil {
-		logger.Infof("Signature verification failed for request %s: %s", req.URL, err)
-
-		return false, nil, nil
-	}
-
 	keyID := getKeyIDFromSignatureHeader(req)
 	if keyID == "" {
 		logger.Debugf("'keyId' not found in Signature header in request %s", req.URL)
@@ -82,8 +77,6 @@ func (v *Verifier) VerifyRequest(req *http.Request) (bool, *url.URL, error) {
 		return false, nil, nil
 	}
 
-	logger.Debugf("Verifying keyId [%s] from signature header ...", keyID)
-
 	keyIRI, err := url.Parse(keyID)
 	if err != nil {
 		logger.Debugf("invalid public key ID [%s] in request %s: %s", keyID, req.URL, err)
@@ -91,6 +84,19 @@ func (v *Verifier) VerifyRequest(req *http.Request) (bool, *url.URL, error) {
 		return false, nil, nil
 	}
 
+	err = v.verifier().Verify(req)
+	if err != nil {
+		logger.Infof("Signature verification failed for request %s: %s", req.URL, err)
+
+		// The cached key may be stale (for example, if the key was rotated), so remove it from the cache
+		// so that the key is retrieved from the remote server when the next request is verified.
+		v.actorRetriever.InvalidatePublicKey(keyIRI)
+
+		return false, nil, nil
+	}
+
+	logger.Debugf("Verifying keyId [%s] from signature header ...", keyID)
+
 	publicKey, err := v.actorRetriever.GetPublicKey(keyIRI)
 	if err != nil {
 		return false, nil, fmt.Errorf("get public key [%s]: %w", keyIRI, err)
@@ -115,6 +121,10 @@ func (v *Verifier) VerifyRequest(req *http.Request) (bool, *url.URL, error) {
 		logger.Debugf("public key [%s] of actor [%s] does not match the provided public key ID [%s] in request %s",
 			actor.PublicKey().ID, actor.ID(), publicKey.ID, req.URL)
 
+		// The cached actor may be stale, so remove the actor and key from the cache.
+		v.actorRetriever.InvalidateActor(publicKey.Owner.URL())
+		v.actorRetriever.InvalidatePublicKey(keyIRI)
+
 		return false, nil, nil
 	}
 
@@ -102,6 +102,10 @@ func TestVerifier_VerifyRequest(t *testing.T) {
 		cr := &mockcrypto.Crypto{}
 		km := &mockkms.KeyManager{}
 
+		retriever := servicemocks.NewActivitPubClient().
+			WithPublicKey(publicKey).
+			WithActor(aptestutil.NewMockService(actorIRI, aptestutil.WithPublicKey(publicKey)))
+
 		v := NewVerifier(retriever, cr, km)
 
 		req, err := http.NewRequest(http.MethodPost, "https://domain1.com", bytes.NewBuffer(payload))
@@ -113,6 +117,7 @@ func TestVerifier_VerifyRequest(t *testing.T) {
 		require.NoError(t, err)
 		require.False(t, ok)
 		require.Nil(t, actorID)
+		require.Equal(t, []string{publicKey.ID.String()}, retriever.Invalidated())
 	})
 
 	t.Run("Key ID not found in signature header", func(t *testing.T) {
@@ -211,11 +216,13 @@ func TestVerifier_VerifyRequest(t *testing.T) {
 			vocab.WithPublicKeyPem(string(pubKeyPem)),
 		)
 
+		retriever := servicemocks.NewActivitPubClient().
+			WithPublicKey(publicKey).
+			WithActor(aptestutil.NewMockService(actorIRI, aptestutil.WithPublicKey(actorPublicKey)))
+
 		v := &Verifier{
-			actorRetriever: servicemocks.NewActivitPubClient().
-				WithPublicKey(publicKey).
-				WithActor(aptestutil.NewMockService(actorIRI, aptestutil.WithPublicKey(actorPublicKey))),
-			verifier: func() verifier { return &mocks.HTTPSignatureVerifier{} },
+			actorRetriever: retriever,
+			verifier:       func() verifier { return &mocks.HTTPSignatureVerifier{} },
 		}
 
 		req, err := http.NewRequest(http.MethodPost, "https://domain1.com", bytes.NewBuffer(payload))
@@ -227,6 +234,7 @@ func TestVerifier_VerifyRequest(t *testing.T) {
 		require.NoError(t, err)
 		require.False(t, ok)
 		require.Nil(t, actorID)
+		require.Equal(t, []string{actorIRI.String(), publicKey.ID.String()}, retriever.Invalidated())
 	})
 }
 
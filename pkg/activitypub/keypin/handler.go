@@ -0,0 +1,204 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keypin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+)
+
+const (
+	// Path is the path of the endpoint that returns the pinned actor keys.
+	Path = "/keypins"
+	// AlertsPath is the path of the endpoint that returns the key change alerts.
+	AlertsPath = Path + "/alerts"
+
+	idPathVariable = "id"
+
+	approvePath = AlertsPath + "/{" + idPathVariable + "}/approve"
+
+	notFoundResponse            = "Not Found."
+	conflictResponse            = "Alert is not pending approval."
+	internalServerErrorResponse = "Internal Server Error."
+)
+
+type pinManager interface {
+	Pins() ([]*Pin, error)
+	Alerts() ([]*Alert, error)
+	Approve(alertID string) (*Alert, error)
+}
+
+// Pins implements a REST handler that returns the pinned actor keys.
+type Pins struct {
+	manager pinManager
+	marshal func(v interface{}) ([]byte, error)
+}
+
+// NewPins returns a new pinned keys handler.
+func NewPins(manager pinManager) *Pins {
+	return &Pins{
+		manager: manager,
+		marshal: json.Marshal,
+	}
+}
+
+// Path returns the HTTP REST endpoint of the handler.
+func (h *Pins) Path() string {
+	return Path
+}
+
+// Method returns the HTTP method, which is always GET.
+func (h *Pins) Method() string {
+	return http.MethodGet
+}
+
+// Handler returns the HTTP REST handle.
+func (h *Pins) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Pins) handle(w http.ResponseWriter, _ *http.Request) {
+	pins, err := h.manager.Pins()
+	if err != nil {
+		logger.Errorf("[%s] Error retrieving pinned keys: %s", Path, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	if pins == nil {
+		pins = []*Pin{}
+	}
+
+	writeJSON(w, h.marshal, pins)
+}
+
+// Alerts implements a REST handler that returns the key change alerts, most recent first.
+type Alerts struct {
+	manager pinManager
+	marshal func(v interface{}) ([]byte, error)
+}
+
+// NewAlerts returns a new key change alerts handler.
+func NewAlerts(manager pinManager) *Alerts {
+	return &Alerts{
+		manager: manager,
+		marshal: json.Marshal,
+	}
+}
+
+// Path returns the HTTP REST endpoint of the handler.
+func (h *Alerts) Path() string {
+	return AlertsPath
+}
+
+// Method returns the HTTP method, which is always GET.
+func (h *Alerts) Method() string {
+	return http.MethodGet
+}
+
+// Handler returns the HTTP REST handle.
+func (h *Alerts) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Alerts) handle(w http.ResponseWriter, _ *http.Request) {
+	alerts, err := h.manager.Alerts()
+	if err != nil {
+		logger.Errorf("[%s] Error retrieving key change alerts: %s", AlertsPath, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	if alerts == nil {
+		alerts = []*Alert{}
+	}
+
+	writeJSON(w, h.marshal, alerts)
+}
+
+// Approve implements a REST handler that approves a pending key change alert, after which the new key is pinned
+// for the actor.
+type Approve struct {
+	manager pinManager
+	marshal func(v interface{}) ([]byte, error)
+}
+
+// NewApprove returns a new key change approval handler.
+func NewApprove(manager pinManager) *Approve {
+	return &Approve{
+		manager: manager,
+		marshal: json.Marshal,
+	}
+}
+
+// Path returns the HTTP REST endpoint of the handler.
+func (h *Approve) Path() string {
+	return approvePath
+}
+
+// Method returns the HTTP method, which is always POST.
+func (h *Approve) Method() string {
+	return http.MethodPost
+}
+
+// Handler returns the HTTP REST handle.
+func (h *Approve) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Approve) handle(w http.ResponseWriter, req *http.Request) {
+	id := mux.Vars(req)[idPathVariable]
+
+	alert, err := h.manager.Approve(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrNotFound):
+			writeResponse(w, http.StatusNotFound, []byte(notFoundResponse))
+		case errors.Is(err, errNotPending):
+			writeResponse(w, http.StatusConflict, []byte(conflictResponse))
+		default:
+			logger.Errorf("[%s] Error approving key change alert [%s]: %s", AlertsPath, id, err)
+
+			writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+		}
+
+		return
+	}
+
+	writeJSON(w, h.marshal, alert)
+}
+
+func writeJSON(w http.ResponseWriter, marshal func(v interface{}) ([]byte, error), v interface{}) {
+	respBytes, err := marshal(v)
+	if err != nil {
+		logger.Errorf("[%s] Error marshalling response: %s", Path, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	writeResponse(w, http.StatusOK, respBytes)
+}
+
+func writeResponse(w http.ResponseWriter, status int, body []byte) {
+	w.WriteHeader(status)
+
+	if len(body) > 0 {
+		if _, err := w.Write(body); err != nil {
+			logger.Warnf("[%s] Unable to write response: %s", Path, err)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keypin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	servicemocks "github.com/trustbloc/orb/pkg/activitypub/service/mocks"
+	store "github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	"github.com/trustbloc/orb/pkg/internal/testutil/httptestutil"
+)
+
+func TestPins(t *testing.T) {
+	apClient := servicemocks.NewActivitPubClient().WithPublicKey(newKey(key2IRI, actor2IRI, pem1))
+
+	m, apStore := newTestManager(t, true, apClient)
+
+	h := NewPins(m)
+	require.Equal(t, Path, h.Path())
+	require.Equal(t, http.MethodGet, h.Method())
+	require.NotNil(t, h.Handler())
+
+	t.Run("Empty", func(t *testing.T) {
+		code, body := httptestutil.Get(t, h.handle, Path)
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, "[]", string(body))
+	})
+
+	require.NoError(t, apStore.AddReference(store.Following, serviceIRI, actor2IRI))
+
+	_, err := m.GetPublicKey(key2IRI)
+	require.NoError(t, err)
+
+	t.Run("Success", func(t *testing.T) {
+		code, body := httptestutil.Get(t, h.handle, Path)
+		require.Equal(t, http.StatusOK, code)
+
+		var pins []*Pin
+		require.NoError(t, json.Unmarshal(body, &pins))
+		require.Len(t, pins, 1)
+		require.Equal(t, actor2IRI.String(), pins[0].Actor)
+	})
+
+	t.Run("Manager error", func(t *testing.T) {
+		code, _ := httptestutil.Get(t, NewPins(&mockManager{err: errors.New("injected error")}).handle, Path)
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		h := NewPins(m)
+		h.marshal = func(v interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		code, _ := httptestutil.Get(t, h.handle, Path)
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+}
+
+func TestAlertsAndApprove(t *testing.T) {
+	apClient := servicemocks.NewActivitPubClient().WithPublicKey(newKey(key2IRI, actor2IRI, pem1))
+
+	m, apStore := newTestManager(t, true, apClient)
+
+	alertsHandler := NewAlerts(m)
+	require.Equal(t, AlertsPath, alertsHandler.Path())
+	require.Equal(t, http.MethodGet, alertsHandler.Method())
+	require.NotNil(t, alertsHandler.Handler())
+
+	approveHandler := NewApprove(m)
+	require.Equal(t, approvePath, approveHandler.Path())
+	require.Equal(t, http.MethodPost, approveHandler.Method())
+	require.NotNil(t, approveHandler.Handler())
+
+	t.Run("Empty", func(t *testing.T) {
+		code, body := httptestutil.Get(t, alertsHandler.handle, AlertsPath)
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, "[]", string(body))
+	})
+
+	require.NoError(t, apStore.AddReference(store.Following, serviceIRI, actor2IRI))
+
+	_, err := m.GetPublicKey(key2IRI)
+	require.NoError(t, err)
+
+	apClient.WithPublicKey(newKey(key2IRI, actor2IRI, pem2))
+
+	_, err = m.GetPublicKey(key2IRI)
+	require.ErrorIs(t, err, ErrKeyNotApproved)
+
+	var alerts []*Alert
+
+	t.Run("Alerts", func(t *testing.T) {
+		code, body := httptestutil.Get(t, alertsHandler.handle, AlertsPath)
+		require.Equal(t, http.StatusOK, code)
+
+		require.NoError(t, json.Unmarshal(body, &alerts))
+		require.Len(t, alerts, 1)
+		require.Equal(t, AlertStatusPending, alerts[0].Status)
+	})
+
+	t.Run("Approve", func(t *testing.T) {
+		vars := map[string]string{idPathVariable: alerts[0].ID}
+
+		code, body := httptestutil.Serve(t, approveHandler.handle, http.MethodPost, AlertsPath, nil, vars)
+		require.Equal(t, http.StatusOK, code)
+
+		alert := &Alert{}
+		require.NoError(t, json.Unmarshal(body, alert))
+		require.Equal(t, AlertStatusApproved, alert.Status)
+
+		code, _ = httptestutil.Serve(t, approveHandler.handle, http.MethodPost, AlertsPath, nil, vars)
+		require.Equal(t, http.StatusConflict, code)
+	})
+
+	t.Run("Approve not found", func(t *testing.T) {
+		code, _ := httptestutil.Serve(t, approveHandler.handle, http.MethodPost, AlertsPath, nil,
+			map[string]string{idPathVariable: "unknown"})
+		require.Equal(t, http.StatusNotFound, code)
+	})
+
+	t.Run("Manager error", func(t *testing.T) {
+		mgr := &mockManager{err: errors.New("injected error")}
+
+		code, _ := httptestutil.Get(t, NewAlerts(mgr).handle, AlertsPath)
+		require.Equal(t, http.StatusInternalServerError, code)
+
+		code, _ = httptestutil.Serve(t, NewApprove(mgr).handle, http.MethodPost, AlertsPath, nil,
+			map[string]string{idPathVariable: "id1"})
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+}
+
+type mockManager struct {
+	err error
+}
+
+func (m *mockManager) Pins() ([]*Pin, error) {
+	return nil, m.err
+}
+
+func (m *mockManager) Alerts() ([]*Alert, error) {
+	return nil, m.err
+}
+
+func (m *mockManager) Approve(string) (*Alert, error) {
+	return nil, m.err
+}
@@ -0,0 +1,309 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keypin
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	"github.com/trustbloc/orb/pkg/activitypub/multikey"
+	store "github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+var logger = log.New("actor-key-pin")
+
+// ErrKeyNotApproved is returned when the public key of an actor doesn't match the pinned key and the new key
+// hasn't been approved.
+var ErrKeyNotApproved = errors.New("public key of actor changed and the new key has not been approved")
+
+var errNotPending = errors.New("alert is not pending approval")
+
+// pinnedCollections contains the collections whose actors have their keys pinned.
+var pinnedCollections = []store.ReferenceType{store.Following, store.Witness}
+
+type actorRetriever interface {
+	GetPublicKey(keyIRI *url.URL) (*vocab.PublicKeyType, error)
+	GetActor(actorIRI *url.URL) (*vocab.ActorType, error)
+	InvalidateActor(actorIRI *url.URL)
+	InvalidatePublicKey(keyIRI *url.URL)
+}
+
+type metricsProvider interface {
+	ActorKeyChanged(host string)
+}
+
+// Config contains the configuration for actor key pinning.
+type Config struct {
+	ServiceIRI *url.URL
+	// RequireApproval indicates that a changed key is rejected until the key change alert is approved by an
+	// administrator. Otherwise the new key is pinned and the alert is informational.
+	RequireApproval bool
+}
+
+// Manager pins the public keys of followed and witness actors on first use (TOFU). The manager wraps the actor
+// retriever that's used to verify HTTP signatures so that, when the public key of a pinned actor changes, an alert
+// is raised (the key change metric is incremented and an alert is added to the alerts collection) and, if approval
+// is required, the new key is rejected until the alert is approved.
+type Manager struct {
+	actorRetriever
+	serviceIRI       *url.URL
+	requireApproval  bool
+	activityPubStore store.Store
+	store            *pinStore
+	metrics          metricsProvider
+	mutex            sync.Mutex
+}
+
+// New returns a new actor key pinning manager.
+func New(cfg Config, retriever actorRetriever, apStore store.Store, storageProvider storage.Provider,
+	metrics metricsProvider) (*Manager, error) {
+	s, err := newStore(storageProvider)
+	if err != nil {
+		return nil, fmt.Errorf("create actor key pin store: %w", err)
+	}
+
+	return &Manager{
+		actorRetriever:   retriever,
+		serviceIRI:       cfg.ServiceIRI,
+		requireApproval:  cfg.RequireApproval,
+		activityPubStore: apStore,
+		store:            s,
+		metrics:          metrics,
+	}, nil
+}
+
+// GetPublicKey returns the public key for the given key IRI. If the owner of the key is a followed or witness
+// actor then the key is checked against the pinned key (or pinned if the actor has no pinned key).
+func (m *Manager) GetPublicKey(keyIRI *url.URL) (*vocab.PublicKeyType, error) {
+	key, err := m.actorRetriever.GetPublicKey(keyIRI)
+	if err != nil {
+		return nil, err
+	}
+
+	if key.Owner == nil || key.Owner.URL() == nil {
+		return key, nil
+	}
+
+	actor := key.Owner.URL()
+
+	pinned, err := m.isPinned(actor)
+	if err != nil {
+		return nil, err
+	}
+
+	if !pinned {
+		return key, nil
+	}
+
+	err = m.check(actor, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// Pins returns the pinned keys.
+func (m *Manager) Pins() ([]*Pin, error) {
+	return m.store.getPins()
+}
+
+// Alerts returns the key change alerts, most recent first.
+func (m *Manager) Alerts() ([]*Alert, error) {
+	return m.store.getAlerts()
+}
+
+// Approve approves the pending key change alert with the given ID. The new key is pinned for the actor.
+func (m *Manager) Approve(alertID string) (*Alert, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	alert, err := m.store.getAlert(alertID)
+	if err != nil {
+		return nil, err
+	}
+
+	if alert.Status != AlertStatusPending {
+		return nil, fmt.Errorf("alert [%s] has status [%s]: %w", alertID, alert.Status, errNotPending)
+	}
+
+	err = m.store.putPin(&Pin{
+		Actor:              alert.Actor,
+		KeyID:              alert.NewKeyID,
+		PublicKeyPem:       alert.NewPublicKeyPem,
+		PublicKeyMultibase: alert.NewPublicKeyMultibase,
+		Pinned:             time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	alert.Status = AlertStatusApproved
+
+	err = m.store.putAlert(alert)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Infof("Approved key change alert [%s]. Key [%s] is now pinned for actor [%s]",
+		alertID, alert.NewKeyID, alert.Actor)
+
+	return alert, nil
+}
+
+func (m *Manager) check(actor *url.URL, key *vocab.PublicKeyType) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	pin, err := m.store.getPin(actor.String())
+	if err != nil {
+		if !errors.Is(err, ErrNotFound) {
+			return err
+		}
+
+		logger.Infof("Pinning public key [%s] of actor [%s]", key.ID, actor)
+
+		return m.store.putPin(newPin(actor, key))
+	}
+
+	if keyValue(pin.PublicKeyPem, pin.PublicKeyMultibase) == keyValue(key.PublicKeyPem, key.PublicKeyMultibase) {
+		return nil
+	}
+
+	return m.handleKeyChange(actor, pin, key)
+}
+
+func (m *Manager) handleKeyChange(actor *url.URL, pin *Pin, key *vocab.PublicKeyType) error {
+	alertID := hash(actor.String(), keyValue(key.PublicKeyPem, key.PublicKeyMultibase))
+
+	alert, err := m.store.getAlert(alertID)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	if alert != nil {
+		// The alert was already raised for this key.
+		if alert.Status == AlertStatusPending {
+			return fmt.Errorf("actor [%s], key [%s]: %w", actor, key.ID, ErrKeyNotApproved)
+		}
+
+		return m.store.putPin(newPin(actor, key))
+	}
+
+	alert = &Alert{
+		ID:                    alertID,
+		Actor:                 actor.String(),
+		Detected:              time.Now().UTC(),
+		Status:                AlertStatusAccepted,
+		PinnedKeyID:           pin.KeyID,
+		NewKeyID:              key.ID.String(),
+		NewPublicKeyPem:       key.PublicKeyPem,
+		NewPublicKeyMultibase: key.PublicKeyMultibase,
+	}
+
+	if m.requireApproval {
+		alert.Status = AlertStatusPending
+	}
+
+	err = m.store.putAlert(alert)
+	if err != nil {
+		return err
+	}
+
+	m.metrics.ActorKeyChanged(actor.Host)
+
+	logger.Warnf("Public key of actor [%s] changed from pinned key [%s] to key [%s]. Alert [%s], status: %s",
+		actor, pin.KeyID, key.ID, alertID, alert.Status)
+
+	if m.requireApproval {
+		return fmt.Errorf("actor [%s], key [%s]: %w", actor, key.ID, ErrKeyNotApproved)
+	}
+
+	return m.store.putPin(newPin(actor, key))
+}
+
+// isPinned returns true if the given actor is followed by this service or is a witness of this service.
+func (m *Manager) isPinned(actor *url.URL) (bool, error) {
+	for _, refType := range pinnedCollections {
+		exists, err := m.hasReference(refType, actor)
+		if err != nil {
+			return false, err
+		}
+
+		if exists {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (m *Manager) hasReference(refType store.ReferenceType, refIRI *url.URL) (bool, error) {
+	it, err := m.activityPubStore.QueryReferences(refType,
+		store.NewCriteria(
+			store.WithObjectIRI(m.serviceIRI),
+			store.WithReferenceIRI(refIRI),
+		),
+	)
+	if err != nil {
+		return false, orberrors.NewTransient(fmt.Errorf("query references: %w", err))
+	}
+
+	defer func() {
+		if e := it.Close(); e != nil {
+			logger.Warnf("Error closing iterator: %s", e)
+		}
+	}()
+
+	_, err = it.Next()
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return false, nil
+		}
+
+		return false, orberrors.NewTransient(fmt.Errorf("get next reference: %w", err))
+	}
+
+	return true, nil
+}
+
+func newPin(actor *url.URL, key *vocab.PublicKeyType) *Pin {
+	return &Pin{
+		Actor:              actor.String(),
+		KeyID:              key.ID.String(),
+		PublicKeyPem:       key.PublicKeyPem,
+		PublicKeyMultibase: key.PublicKeyMultibase,
+		Pinned:             time.Now().UTC(),
+	}
+}
+
+// keyValue returns the Multikey encoding of the given public key so that the same key published in a different
+// format (PEM or Multikey) isn't treated as a key change. If the key can't be decoded then the raw values are used.
+func keyValue(publicKeyPem, publicKeyMultibase string) string {
+	pubKey, err := multikey.Ed25519PublicKey(&vocab.PublicKeyType{
+		PublicKeyPem:       publicKeyPem,
+		PublicKeyMultibase: publicKeyMultibase,
+	})
+	if err != nil {
+		return publicKeyPem + publicKeyMultibase
+	}
+
+	value, err := multikey.EncodeEd25519(pubKey)
+	if err != nil {
+		return publicKeyPem + publicKeyMultibase
+	}
+
+	return value
+}
@@ -0,0 +1,332 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keypin
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/multikey"
+	servicemocks "github.com/trustbloc/orb/pkg/activitypub/service/mocks"
+	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
+	store "github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+	"github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+const (
+	pem1 = "-----BEGIN PUBLIC KEY-----\nkey1\n-----END PUBLIC KEY-----"
+	pem2 = "-----BEGIN PUBLIC KEY-----\nkey2\n-----END PUBLIC KEY-----"
+)
+
+var (
+	serviceIRI = testutil.MustParseURL("https://domain1.com/services/orb")
+	actor2IRI  = testutil.MustParseURL("https://domain2.com/services/orb")
+	key2IRI    = testutil.MustParseURL("https://domain2.com/services/orb/keys/main-key")
+	actor3IRI  = testutil.MustParseURL("https://domain3.com/services/orb")
+	key3IRI    = testutil.MustParseURL("https://domain3.com/services/orb/keys/main-key")
+)
+
+func TestNew(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		m, err := New(Config{ServiceIRI: serviceIRI}, servicemocks.NewActivitPubClient(), memstore.New("service1"),
+			mem.NewProvider(), &mockMetrics{})
+		require.NoError(t, err)
+		require.NotNil(t, m)
+	})
+
+	t.Run("Store error", func(t *testing.T) {
+		p := &mocks.Provider{}
+		p.OpenStoreReturns(nil, errors.New("injected open error"))
+
+		_, err := New(Config{ServiceIRI: serviceIRI}, servicemocks.NewActivitPubClient(), memstore.New("service1"),
+			p, &mockMetrics{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected open error")
+	})
+}
+
+func TestManager_GetPublicKey(t *testing.T) {
+	t.Run("Actor not followed -> not pinned", func(t *testing.T) {
+		apClient := servicemocks.NewActivitPubClient().WithPublicKey(newKey(key3IRI, actor3IRI, pem1))
+
+		m, _ := newTestManager(t, false, apClient)
+
+		key, err := m.GetPublicKey(key3IRI)
+		require.NoError(t, err)
+		require.Equal(t, pem1, key.PublicKeyPem)
+
+		pins, err := m.Pins()
+		require.NoError(t, err)
+		require.Empty(t, pins)
+	})
+
+	t.Run("Key without owner -> not pinned", func(t *testing.T) {
+		apClient := servicemocks.NewActivitPubClient().WithPublicKey(
+			vocab.NewPublicKey(vocab.WithID(key2IRI), vocab.WithPublicKeyPem(pem1)))
+
+		m, apStore := newTestManager(t, false, apClient)
+		require.NoError(t, apStore.AddReference(store.Following, serviceIRI, actor2IRI))
+
+		_, err := m.GetPublicKey(key2IRI)
+		require.NoError(t, err)
+
+		pins, err := m.Pins()
+		require.NoError(t, err)
+		require.Empty(t, pins)
+	})
+
+	t.Run("Key change -> accepted", func(t *testing.T) {
+		apClient := servicemocks.NewActivitPubClient().WithPublicKey(newKey(key2IRI, actor2IRI, pem1))
+
+		m, apStore := newTestManager(t, false, apClient)
+		require.NoError(t, apStore.AddReference(store.Following, serviceIRI, actor2IRI))
+
+		_, err := m.GetPublicKey(key2IRI)
+		require.NoError(t, err)
+
+		pins, err := m.Pins()
+		require.NoError(t, err)
+		require.Len(t, pins, 1)
+		require.Equal(t, actor2IRI.String(), pins[0].Actor)
+		require.Equal(t, pem1, pins[0].PublicKeyPem)
+
+		// Same key.
+		_, err = m.GetPublicKey(key2IRI)
+		require.NoError(t, err)
+
+		alerts, err := m.Alerts()
+		require.NoError(t, err)
+		require.Empty(t, alerts)
+
+		apClient.WithPublicKey(newKey(key2IRI, actor2IRI, pem2))
+
+		key, err := m.GetPublicKey(key2IRI)
+		require.NoError(t, err)
+		require.Equal(t, pem2, key.PublicKeyPem)
+
+		alerts, err = m.Alerts()
+		require.NoError(t, err)
+		require.Len(t, alerts, 1)
+		require.Equal(t, AlertStatusAccepted, alerts[0].Status)
+		require.Equal(t, actor2IRI.String(), alerts[0].Actor)
+		require.Equal(t, key2IRI.String(), alerts[0].PinnedKeyID)
+		require.Equal(t, pem2, alerts[0].NewPublicKeyPem)
+
+		pins, err = m.Pins()
+		require.NoError(t, err)
+		require.Len(t, pins, 1)
+		require.Equal(t, pem2, pins[0].PublicKeyPem)
+
+		require.Equal(t, 1, m.metrics.(*mockMetrics).Count())
+
+		// The key changes back to the original key, which raises another alert.
+		apClient.WithPublicKey(newKey(key2IRI, actor2IRI, pem1))
+
+		_, err = m.GetPublicKey(key2IRI)
+		require.NoError(t, err)
+
+		alerts, err = m.Alerts()
+		require.NoError(t, err)
+		require.Len(t, alerts, 2)
+		require.Equal(t, 2, m.metrics.(*mockMetrics).Count())
+	})
+
+	t.Run("Same key in Multikey format -> not a key change", func(t *testing.T) {
+		pubKey, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		der, err := x509.MarshalPKIXPublicKey(pubKey)
+		require.NoError(t, err)
+
+		pemKey := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+
+		multibaseKey, err := multikey.EncodeEd25519(pubKey)
+		require.NoError(t, err)
+
+		apClient := servicemocks.NewActivitPubClient().WithPublicKey(newKey(key2IRI, actor2IRI, pemKey))
+
+		m, apStore := newTestManager(t, true, apClient)
+		require.NoError(t, apStore.AddReference(store.Following, serviceIRI, actor2IRI))
+
+		_, err = m.GetPublicKey(key2IRI)
+		require.NoError(t, err)
+
+		apClient.WithPublicKey(vocab.NewPublicKey(
+			vocab.WithID(key2IRI),
+			vocab.WithOwner(actor2IRI),
+			vocab.WithPublicKeyMultibase(multibaseKey),
+		))
+
+		key, err := m.GetPublicKey(key2IRI)
+		require.NoError(t, err)
+		require.Equal(t, multibaseKey, key.PublicKeyMultibase)
+
+		alerts, err := m.Alerts()
+		require.NoError(t, err)
+		require.Empty(t, alerts)
+
+		// A different key in Multikey format is a key change.
+		pubKey2, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		multibaseKey2, err := multikey.EncodeEd25519(pubKey2)
+		require.NoError(t, err)
+
+		apClient.WithPublicKey(vocab.NewPublicKey(
+			vocab.WithID(key2IRI),
+			vocab.WithOwner(actor2IRI),
+			vocab.WithPublicKeyMultibase(multibaseKey2),
+		))
+
+		_, err = m.GetPublicKey(key2IRI)
+		require.ErrorIs(t, err, ErrKeyNotApproved)
+
+		alerts, err = m.Alerts()
+		require.NoError(t, err)
+		require.Len(t, alerts, 1)
+		require.Equal(t, multibaseKey2, alerts[0].NewPublicKeyMultibase)
+	})
+
+	t.Run("Key change -> approval required", func(t *testing.T) {
+		apClient := servicemocks.NewActivitPubClient().WithPublicKey(newKey(key2IRI, actor2IRI, pem1))
+
+		m, apStore := newTestManager(t, true, apClient)
+		require.NoError(t, apStore.AddReference(store.Witness, serviceIRI, actor2IRI))
+
+		_, err := m.GetPublicKey(key2IRI)
+		require.NoError(t, err)
+
+		apClient.WithPublicKey(newKey(key2IRI, actor2IRI, pem2))
+
+		_, err = m.GetPublicKey(key2IRI)
+		require.ErrorIs(t, err, ErrKeyNotApproved)
+
+		// The alert is raised only once.
+		_, err = m.GetPublicKey(key2IRI)
+		require.ErrorIs(t, err, ErrKeyNotApproved)
+
+		require.Equal(t, 1, m.metrics.(*mockMetrics).Count())
+
+		alerts, err := m.Alerts()
+		require.NoError(t, err)
+		require.Len(t, alerts, 1)
+		require.Equal(t, AlertStatusPending, alerts[0].Status)
+
+		alert, err := m.Approve(alerts[0].ID)
+		require.NoError(t, err)
+		require.Equal(t, AlertStatusApproved, alert.Status)
+
+		key, err := m.GetPublicKey(key2IRI)
+		require.NoError(t, err)
+		require.Equal(t, pem2, key.PublicKeyPem)
+
+		_, err = m.Approve(alerts[0].ID)
+		require.ErrorIs(t, err, errNotPending)
+
+		_, err = m.Approve("unknown")
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Retriever error", func(t *testing.T) {
+		errExpected := errors.New("injected retriever error")
+
+		m, _ := newTestManager(t, false, servicemocks.NewActivitPubClient().WithError(errExpected))
+
+		_, err := m.GetPublicKey(key2IRI)
+		require.ErrorIs(t, err, errExpected)
+	})
+
+	t.Run("Query references error", func(t *testing.T) {
+		errExpected := errors.New("injected query error")
+
+		apStore := &servicemocks.ActivityStore{}
+		apStore.QueryReferencesReturns(nil, errExpected)
+
+		m, err := New(Config{ServiceIRI: serviceIRI},
+			servicemocks.NewActivitPubClient().WithPublicKey(newKey(key2IRI, actor2IRI, pem1)),
+			apStore, mem.NewProvider(), &mockMetrics{})
+		require.NoError(t, err)
+
+		_, err = m.GetPublicKey(key2IRI)
+		require.ErrorIs(t, err, errExpected)
+	})
+
+	t.Run("Store error", func(t *testing.T) {
+		errExpected := errors.New("injected store error")
+
+		s := &mocks.Store{}
+		s.GetReturns(nil, errExpected)
+
+		p := &mocks.Provider{}
+		p.OpenStoreReturns(s, nil)
+
+		apStore := memstore.New("service1")
+		require.NoError(t, apStore.AddReference(store.Following, serviceIRI, actor2IRI))
+
+		m, err := New(Config{ServiceIRI: serviceIRI},
+			servicemocks.NewActivitPubClient().WithPublicKey(newKey(key2IRI, actor2IRI, pem1)),
+			apStore, p, &mockMetrics{})
+		require.NoError(t, err)
+
+		_, err = m.GetPublicKey(key2IRI)
+		require.ErrorIs(t, err, errExpected)
+
+		_, err = m.Approve("id1")
+		require.ErrorIs(t, err, errExpected)
+	})
+}
+
+func newTestManager(t *testing.T, requireApproval bool,
+	apClient *servicemocks.ActivityPubClient) (*Manager, store.Store) {
+	t.Helper()
+
+	apStore := memstore.New("service1")
+
+	m, err := New(Config{ServiceIRI: serviceIRI, RequireApproval: requireApproval}, apClient, apStore,
+		mem.NewProvider(), &mockMetrics{})
+	require.NoError(t, err)
+
+	return m, apStore
+}
+
+func newKey(keyIRI, owner *url.URL, pemKey string) *vocab.PublicKeyType {
+	return vocab.NewPublicKey(
+		vocab.WithID(keyIRI),
+		vocab.WithOwner(owner),
+		vocab.WithPublicKeyPem(pemKey),
+	)
+}
+
+type mockMetrics struct {
+	mutex sync.Mutex
+	count int
+}
+
+func (m *mockMetrics) ActorKeyChanged(string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.count++
+}
+
+func (m *mockMetrics) Count() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.count
+}
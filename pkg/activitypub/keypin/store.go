@@ -0,0 +1,240 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keypin
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+// ErrNotFound is returned when a pin or alert isn't found.
+var ErrNotFound = errors.New("not found")
+
+const (
+	storeName = "actor-key-pin"
+
+	// pinTag is added to every pin so that all pins may be queried.
+	pinTag = "pin"
+	// alertTag is added to every alert so that all alerts may be queried.
+	alertTag = "alert"
+
+	pinKeyPrefix   = "pin-"
+	alertKeyPrefix = "alert-"
+)
+
+// AlertStatus is the status of a key change alert.
+type AlertStatus string
+
+const (
+	// AlertStatusAccepted indicates that the new key was accepted (and pinned) automatically.
+	AlertStatusAccepted AlertStatus = "accepted"
+	// AlertStatusPending indicates that the new key is rejected until it's approved by an administrator.
+	AlertStatusPending AlertStatus = "pending"
+	// AlertStatusApproved indicates that the new key was approved by an administrator.
+	AlertStatusApproved AlertStatus = "approved"
+)
+
+// Pin contains the public key that's pinned for an actor.
+type Pin struct {
+	Actor              string    `json:"actor"`
+	KeyID              string    `json:"keyId"`
+	PublicKeyPem       string    `json:"publicKeyPem"`
+	PublicKeyMultibase string    `json:"publicKeyMultibase,omitempty"`
+	Pinned             time.Time `json:"pinned"`
+}
+
+// Alert is raised when the public key of an actor doesn't match the pinned key.
+type Alert struct {
+	// ID is the hash of the actor and the new key, so that a single alert is raised for each key change.
+	ID                    string      `json:"id"`
+	Actor                 string      `json:"actor"`
+	Detected              time.Time   `json:"detected"`
+	Status                AlertStatus `json:"status"`
+	PinnedKeyID           string      `json:"pinnedKeyId"`
+	NewKeyID              string      `json:"newKeyId"`
+	NewPublicKeyPem       string      `json:"newPublicKeyPem"`
+	NewPublicKeyMultibase string      `json:"newPublicKeyMultibase,omitempty"`
+}
+
+// pinStore persists the pinned keys and the key change alerts.
+type pinStore struct {
+	store storage.Store
+}
+
+func newStore(provider storage.Provider) (*pinStore, error) {
+	s, err := provider.OpenStore(storeName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open actor key pin store: %w", err)
+	}
+
+	err = provider.SetStoreConfig(storeName, storage.StoreConfiguration{TagNames: []string{pinTag, alertTag}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set store configuration: %w", err)
+	}
+
+	return &pinStore{store: s}, nil
+}
+
+func (s *pinStore) putPin(pin *Pin) error {
+	return s.put(pinKeyPrefix+hash(pin.Actor), pin, pinTag)
+}
+
+func (s *pinStore) getPin(actor string) (*Pin, error) {
+	pin := &Pin{}
+
+	if err := s.get(pinKeyPrefix+hash(actor), pin); err != nil {
+		return nil, err
+	}
+
+	return pin, nil
+}
+
+func (s *pinStore) getPins() ([]*Pin, error) {
+	var pins []*Pin
+
+	err := s.query(pinTag, func(value []byte) error {
+		pin := &Pin{}
+
+		if err := json.Unmarshal(value, pin); err != nil {
+			return err
+		}
+
+		pins = append(pins, pin)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(pins, func(i, j int) bool {
+		return pins[i].Actor < pins[j].Actor
+	})
+
+	return pins, nil
+}
+
+func (s *pinStore) putAlert(alert *Alert) error {
+	return s.put(alertKeyPrefix+alert.ID, alert, alertTag)
+}
+
+func (s *pinStore) getAlert(id string) (*Alert, error) {
+	alert := &Alert{}
+
+	if err := s.get(alertKeyPrefix+id, alert); err != nil {
+		return nil, err
+	}
+
+	return alert, nil
+}
+
+// getAlerts returns all alerts, most recent first.
+func (s *pinStore) getAlerts() ([]*Alert, error) {
+	var alerts []*Alert
+
+	err := s.query(alertTag, func(value []byte) error {
+		alert := &Alert{}
+
+		if err := json.Unmarshal(value, alert); err != nil {
+			return err
+		}
+
+		alerts = append(alerts, alert)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(alerts, func(i, j int) bool {
+		return alerts[i].Detected.After(alerts[j].Detected)
+	})
+
+	return alerts, nil
+}
+
+func (s *pinStore) put(key string, value interface{}, tag string) error {
+	valueBytes, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("marshal %s [%s]: %w", tag, key, err)
+	}
+
+	err = s.store.Put(key, valueBytes, storage.Tag{Name: tag})
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("store %s [%s]: %w", tag, key, err))
+	}
+
+	return nil
+}
+
+func (s *pinStore) get(key string, value interface{}) error {
+	valueBytes, err := s.store.Get(key)
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return ErrNotFound
+		}
+
+		return orberrors.NewTransient(fmt.Errorf("get [%s]: %w", key, err))
+	}
+
+	err = json.Unmarshal(valueBytes, value)
+	if err != nil {
+		return fmt.Errorf("unmarshal [%s]: %w", key, err)
+	}
+
+	return nil
+}
+
+func (s *pinStore) query(tag string, handle func(value []byte) error) error {
+	it, err := s.store.Query(tag)
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("query %s: %w", tag, err))
+	}
+
+	defer func() {
+		if e := it.Close(); e != nil {
+			logger.Warnf("Error closing iterator: %s", e)
+		}
+	}()
+
+	for {
+		ok, e := it.Next()
+		if e != nil {
+			return orberrors.NewTransient(fmt.Errorf("next %s: %w", tag, e))
+		}
+
+		if !ok {
+			return nil
+		}
+
+		value, e := it.Value()
+		if e != nil {
+			return orberrors.NewTransient(fmt.Errorf("get %s from iterator: %w", tag, e))
+		}
+
+		if e := handle(value); e != nil {
+			return fmt.Errorf("unmarshal %s: %w", tag, e)
+		}
+	}
+}
+
+func hash(values ...string) string {
+	h := sha256.Sum256([]byte(strings.Join(values, "\n")))
+
+	return hex.EncodeToString(h[:])
+}
@@ -0,0 +1,157 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keypin
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+func TestStore(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		s, err := newStore(mem.NewProvider())
+		require.NoError(t, err)
+
+		_, err = s.getPin(actor2IRI.String())
+		require.ErrorIs(t, err, ErrNotFound)
+
+		_, err = s.getAlert("id1")
+		require.ErrorIs(t, err, ErrNotFound)
+
+		require.NoError(t, s.putPin(&Pin{Actor: actor3IRI.String(), KeyID: key3IRI.String(), PublicKeyPem: pem1}))
+		require.NoError(t, s.putPin(&Pin{Actor: actor2IRI.String(), KeyID: key2IRI.String(), PublicKeyPem: pem1}))
+
+		pin, err := s.getPin(actor2IRI.String())
+		require.NoError(t, err)
+		require.Equal(t, key2IRI.String(), pin.KeyID)
+
+		pins, err := s.getPins()
+		require.NoError(t, err)
+		require.Len(t, pins, 2)
+		require.Equal(t, actor2IRI.String(), pins[0].Actor)
+		require.Equal(t, actor3IRI.String(), pins[1].Actor)
+
+		now := time.Now()
+
+		require.NoError(t, s.putAlert(&Alert{ID: "id1", Detected: now.Add(-time.Minute), Status: AlertStatusPending}))
+		require.NoError(t, s.putAlert(&Alert{ID: "id2", Detected: now, Status: AlertStatusAccepted}))
+
+		alert, err := s.getAlert("id1")
+		require.NoError(t, err)
+		require.Equal(t, AlertStatusPending, alert.Status)
+
+		alerts, err := s.getAlerts()
+		require.NoError(t, err)
+		require.Len(t, alerts, 2)
+		require.Equal(t, "id2", alerts[0].ID)
+		require.Equal(t, "id1", alerts[1].ID)
+	})
+
+	t.Run("Open store error", func(t *testing.T) {
+		p := &mocks.Provider{}
+		p.OpenStoreReturns(nil, errors.New("injected open error"))
+
+		_, err := newStore(p)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected open error")
+	})
+
+	t.Run("Set store config error", func(t *testing.T) {
+		p := &mocks.Provider{}
+		p.SetStoreConfigReturns(errors.New("injected config error"))
+
+		_, err := newStore(p)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected config error")
+	})
+
+	t.Run("Store errors", func(t *testing.T) {
+		errExpected := errors.New("injected store error")
+
+		store := &mocks.Store{}
+		store.PutReturns(errExpected)
+		store.GetReturns(nil, errExpected)
+		store.QueryReturns(nil, errExpected)
+
+		p := &mocks.Provider{}
+		p.OpenStoreReturns(store, nil)
+
+		s, err := newStore(p)
+		require.NoError(t, err)
+
+		require.ErrorIs(t, s.putPin(&Pin{Actor: actor2IRI.String()}), errExpected)
+		require.ErrorIs(t, s.putAlert(&Alert{ID: "id1"}), errExpected)
+
+		_, err = s.getPin(actor2IRI.String())
+		require.ErrorIs(t, err, errExpected)
+
+		_, err = s.getAlert("id1")
+		require.ErrorIs(t, err, errExpected)
+
+		_, err = s.getPins()
+		require.ErrorIs(t, err, errExpected)
+
+		_, err = s.getAlerts()
+		require.ErrorIs(t, err, errExpected)
+	})
+
+	t.Run("Iterator errors", func(t *testing.T) {
+		errExpected := errors.New("injected iterator error")
+
+		it := &mocks.Iterator{}
+		it.NextReturns(false, errExpected)
+
+		store := &mocks.Store{}
+		store.QueryReturns(it, nil)
+
+		p := &mocks.Provider{}
+		p.OpenStoreReturns(store, nil)
+
+		s, err := newStore(p)
+		require.NoError(t, err)
+
+		_, err = s.getPins()
+		require.ErrorIs(t, err, errExpected)
+
+		it.NextReturns(true, nil)
+		it.ValueReturns(nil, errExpected)
+
+		_, err = s.getAlerts()
+		require.ErrorIs(t, err, errExpected)
+
+		it.ValueReturns([]byte("xxx"), nil)
+
+		_, err = s.getPins()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal pin")
+
+		_, err = s.getAlerts()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal alert")
+	})
+
+	t.Run("Unmarshal error", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.GetReturns([]byte("xxx"), nil)
+
+		p := &mocks.Provider{}
+		p.OpenStoreReturns(store, nil)
+
+		s, err := newStore(p)
+		require.NoError(t, err)
+
+		_, err = s.getPin(actor2IRI.String())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal")
+	})
+}
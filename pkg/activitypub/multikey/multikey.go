@@ -0,0 +1,92 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package multikey
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	"github.com/multiformats/go-multibase"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+)
+
+// ed25519Prefix is the (varint encoded) multicodec prefix of an Ed25519 public key.
+var ed25519Prefix = []byte{0xed, 0x01}
+
+// EncodeEd25519 returns the Multikey encoding of the given Ed25519 public key, i.e. the multicodec-prefixed
+// key encoded as a base58-btc multibase value (for example, "z6Mk...").
+func EncodeEd25519(pubKey ed25519.PublicKey) (string, error) {
+	if len(pubKey) != ed25519.PublicKeySize {
+		return "", fmt.Errorf("invalid Ed25519 public key size: %d", len(pubKey))
+	}
+
+	value, err := multibase.Encode(multibase.Base58BTC, append(append([]byte{}, ed25519Prefix...), pubKey...))
+	if err != nil {
+		return "", fmt.Errorf("multibase encode: %w", err)
+	}
+
+	return value, nil
+}
+
+// DecodeEd25519 decodes an Ed25519 public key from the given Multikey value.
+func DecodeEd25519(value string) (ed25519.PublicKey, error) {
+	encoding, data, err := multibase.Decode(value)
+	if err != nil {
+		return nil, fmt.Errorf("multibase decode: %w", err)
+	}
+
+	if encoding != multibase.Base58BTC {
+		return nil, fmt.Errorf("unsupported multibase encoding: %c", encoding)
+	}
+
+	if !bytes.HasPrefix(data, ed25519Prefix) {
+		return nil, errors.New("unsupported multicodec: only Ed25519 public keys are supported")
+	}
+
+	pubKey := data[len(ed25519Prefix):]
+
+	if len(pubKey) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid Ed25519 public key size: %d", len(pubKey))
+	}
+
+	return ed25519.PublicKey(pubKey), nil
+}
+
+// Ed25519PublicKey returns the Ed25519 key of the given ActivityPub public key. The key is decoded from the
+// 'publicKeyMultibase' property if it's set, otherwise from the 'publicKeyPem' property.
+func Ed25519PublicKey(publicKey *vocab.PublicKeyType) (ed25519.PublicKey, error) {
+	if publicKey.PublicKeyMultibase != "" {
+		pubKey, err := DecodeEd25519(publicKey.PublicKeyMultibase)
+		if err != nil {
+			return nil, fmt.Errorf("invalid multibase public key: %w", err)
+		}
+
+		return pubKey, nil
+	}
+
+	block, _ := pem.Decode([]byte(publicKey.PublicKeyPem))
+	if block == nil {
+		return nil, errors.New("invalid PEM public key: nil block")
+	}
+
+	pk, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse public key: %w", err)
+	}
+
+	pubKey, ok := pk.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported type for public key: %T", pk)
+	}
+
+	return pubKey, nil
+}
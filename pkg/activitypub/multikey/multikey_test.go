@@ -0,0 +1,167 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package multikey
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+	"testing"
+
+	"github.com/multiformats/go-multibase"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	pubKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	t.Run("Success", func(t *testing.T) {
+		value, err := EncodeEd25519(pubKey)
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(value, "z6Mk"))
+
+		decoded, err := DecodeEd25519(value)
+		require.NoError(t, err)
+		require.Equal(t, pubKey, decoded)
+	})
+
+	t.Run("Invalid key size", func(t *testing.T) {
+		_, err := EncodeEd25519(pubKey[:10])
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid Ed25519 public key size")
+
+		value, err := multibase.Encode(multibase.Base58BTC, append([]byte{0xed, 0x01}, pubKey[:10]...))
+		require.NoError(t, err)
+
+		_, err = DecodeEd25519(value)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid Ed25519 public key size")
+	})
+
+	t.Run("Invalid multibase value", func(t *testing.T) {
+		_, err := DecodeEd25519("!invalid")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "multibase decode")
+	})
+
+	t.Run("Unsupported encoding", func(t *testing.T) {
+		value, err := multibase.Encode(multibase.Base64url, append([]byte{0xed, 0x01}, pubKey...))
+		require.NoError(t, err)
+
+		_, err = DecodeEd25519(value)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unsupported multibase encoding")
+	})
+
+	t.Run("Unsupported multicodec", func(t *testing.T) {
+		value, err := multibase.Encode(multibase.Base58BTC, append([]byte{0xe7, 0x01}, pubKey...))
+		require.NoError(t, err)
+
+		_, err = DecodeEd25519(value)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unsupported multicodec")
+	})
+}
+
+func TestEd25519PublicKey(t *testing.T) {
+	keyID := testutil.MustParseURL("https://example.com/services/orb/keys/main-key")
+
+	pubKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	multibaseKey, err := EncodeEd25519(pubKey)
+	require.NoError(t, err)
+
+	pemKey := toPEM(t, pubKey)
+
+	t.Run("Multibase", func(t *testing.T) {
+		pk, err := Ed25519PublicKey(vocab.NewPublicKey(
+			vocab.WithID(keyID),
+			vocab.WithPublicKeyMultibase(multibaseKey),
+		))
+		require.NoError(t, err)
+		require.Equal(t, pubKey, pk)
+	})
+
+	t.Run("PEM", func(t *testing.T) {
+		pk, err := Ed25519PublicKey(vocab.NewPublicKey(
+			vocab.WithID(keyID),
+			vocab.WithPublicKeyPem(pemKey),
+		))
+		require.NoError(t, err)
+		require.Equal(t, pubKey, pk)
+	})
+
+	t.Run("Multibase and PEM -> multibase takes precedence", func(t *testing.T) {
+		pubKey2, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		pk, err := Ed25519PublicKey(vocab.NewPublicKey(
+			vocab.WithID(keyID),
+			vocab.WithPublicKeyMultibase(multibaseKey),
+			vocab.WithPublicKeyPem(toPEM(t, pubKey2)),
+		))
+		require.NoError(t, err)
+		require.Equal(t, pubKey, pk)
+	})
+
+	t.Run("Invalid multibase", func(t *testing.T) {
+		_, err := Ed25519PublicKey(vocab.NewPublicKey(
+			vocab.WithID(keyID),
+			vocab.WithPublicKeyMultibase("zinvalid"),
+		))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid multibase public key")
+	})
+
+	t.Run("Invalid PEM", func(t *testing.T) {
+		_, err := Ed25519PublicKey(vocab.NewPublicKey(
+			vocab.WithID(keyID),
+			vocab.WithPublicKeyPem("invalid"),
+		))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid PEM")
+	})
+
+	t.Run("Parse error", func(t *testing.T) {
+		_, err := Ed25519PublicKey(vocab.NewPublicKey(
+			vocab.WithID(keyID),
+			vocab.WithPublicKeyPem(string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: []byte("x")}))),
+		))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "parse public key")
+	})
+
+	t.Run("Unsupported key type", func(t *testing.T) {
+		privKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		_, err = Ed25519PublicKey(vocab.NewPublicKey(
+			vocab.WithID(keyID),
+			vocab.WithPublicKeyPem(toPEM(t, &privKey.PublicKey)),
+		))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unsupported type for public key")
+	})
+}
+
+func toPEM(t *testing.T, pubKey interface{}) string {
+	t.Helper()
+
+	der, err := x509.MarshalPKIXPublicKey(pubKey)
+	require.NoError(t, err)
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
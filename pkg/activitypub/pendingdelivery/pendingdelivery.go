@@ -0,0 +1,234 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pendingdelivery
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	service "github.com/trustbloc/orb/pkg/activitypub/service/spi"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/store/expiry"
+)
+
+var logger = log.New("pending-delivery")
+
+const (
+	storeName = "pending-delivery"
+
+	// activityTag holds the hash of the activity ID. The tag is set on all records so that all pending
+	// deliveries may be queried by tag name.
+	activityTag = "activity"
+	// expiryTag holds the time (Unix time) after which the pending delivery is abandoned and deleted.
+	expiryTag = "expiry"
+
+	defaultRetention = 7 * 24 * time.Hour
+)
+
+type delivery struct {
+	Activity string    `json:"activity"`
+	Inbox    string    `json:"inbox"`
+	Created  time.Time `json:"created"`
+}
+
+type expiryService interface {
+	Register(store storage.Store, expiryTagName, storeName string, opts ...expiry.Option)
+}
+
+// Store persists a record for each recipient inbox of an activity that's posted to the outbox. The record is
+// removed after the activity is delivered to the inbox (or the delivery is abandoned), so the records that remain
+// after a restart are the deliveries that must be resumed. Records older than the retention period are deleted by
+// the expiry service.
+type Store struct {
+	store     storage.Store
+	retention time.Duration
+	marshal   func(v interface{}) ([]byte, error)
+}
+
+// NewStore returns a new pending delivery store. If retention is 0 then a default of 7 days is used.
+func NewStore(provider storage.Provider, expiryService expiryService, retention time.Duration) (*Store, error) {
+	s, err := provider.OpenStore(storeName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open pending delivery store: %w", err)
+	}
+
+	err = provider.SetStoreConfig(storeName,
+		storage.StoreConfiguration{TagNames: []string{activityTag, expiryTag}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set store configuration: %w", err)
+	}
+
+	expiryService.Register(s, expiryTag, storeName)
+
+	if retention == 0 {
+		retention = defaultRetention
+	}
+
+	return &Store{
+		store:     s,
+		retention: retention,
+		marshal:   json.Marshal,
+	}, nil
+}
+
+// Add persists a pending delivery of the given activity for each of the given inboxes.
+func (s *Store) Add(activityID *url.URL, inboxes []*url.URL) error {
+	if len(inboxes) == 0 {
+		return nil
+	}
+
+	now := time.Now().UTC()
+
+	operations := make([]storage.Operation, len(inboxes))
+
+	for i, inbox := range inboxes {
+		d := &delivery{
+			Activity: activityID.String(),
+			Inbox:    inbox.String(),
+			Created:  now,
+		}
+
+		value, err := s.marshal(d)
+		if err != nil {
+			return fmt.Errorf("marshal pending delivery: %w", err)
+		}
+
+		operations[i] = storage.Operation{
+			Key:   hash(d.Activity, d.Inbox),
+			Value: value,
+			Tags: []storage.Tag{
+				{Name: activityTag, Value: hash(d.Activity)},
+				{Name: expiryTag, Value: strconv.FormatInt(now.Add(s.retention).Unix(), 10)},
+			},
+		}
+	}
+
+	err := s.store.Batch(operations)
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("store pending deliveries for activity [%s]: %w", activityID, err))
+	}
+
+	logger.Debugf("Stored %d pending deliveries for activity [%s]", len(inboxes), activityID)
+
+	return nil
+}
+
+// Remove removes the pending delivery of the given activity to the given inbox.
+func (s *Store) Remove(activityID string, inbox *url.URL) error {
+	err := s.store.Delete(hash(activityID, inbox.String()))
+	if err != nil && !errors.Is(err, storage.ErrDataNotFound) {
+		return orberrors.NewTransient(fmt.Errorf("delete pending delivery of activity [%s] to [%s]: %w",
+			activityID, inbox, err))
+	}
+
+	logger.Debugf("Removed pending delivery of activity [%s] to [%s]", activityID, inbox)
+
+	return nil
+}
+
+// GetAll returns all pending deliveries, grouped by activity. The activities are sorted by the time that they
+// were added.
+func (s *Store) GetAll() ([]*service.PendingDelivery, error) {
+	deliveries, err := s.query()
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(deliveries, func(i, j int) bool {
+		return deliveries[i].Created.Before(deliveries[j].Created)
+	})
+
+	var pending []*service.PendingDelivery
+
+	activities := make(map[string]*service.PendingDelivery)
+
+	for _, d := range deliveries {
+		inbox, e := url.Parse(d.Inbox)
+		if e != nil {
+			logger.Warnf("Ignoring pending delivery of activity [%s] to invalid inbox [%s]: %s", d.Activity, d.Inbox, e)
+
+			continue
+		}
+
+		p, ok := activities[d.Activity]
+		if !ok {
+			activityID, e := url.Parse(d.Activity)
+			if e != nil {
+				logger.Warnf("Ignoring pending delivery of invalid activity ID [%s]: %s", d.Activity, e)
+
+				continue
+			}
+
+			p = &service.PendingDelivery{ActivityID: activityID}
+
+			activities[d.Activity] = p
+			pending = append(pending, p)
+		}
+
+		p.Inboxes = append(p.Inboxes, inbox)
+	}
+
+	return pending, nil
+}
+
+func (s *Store) query() ([]*delivery, error) {
+	it, err := s.store.Query(activityTag)
+	if err != nil {
+		return nil, orberrors.NewTransient(fmt.Errorf("query pending deliveries: %w", err))
+	}
+
+	defer func() {
+		if e := it.Close(); e != nil {
+			logger.Warnf("Error closing iterator: %s", e)
+		}
+	}()
+
+	var deliveries []*delivery
+
+	for {
+		ok, e := it.Next()
+		if e != nil {
+			return nil, orberrors.NewTransient(fmt.Errorf("next pending delivery: %w", e))
+		}
+
+		if !ok {
+			break
+		}
+
+		value, e := it.Value()
+		if e != nil {
+			return nil, orberrors.NewTransient(fmt.Errorf("get pending delivery from iterator: %w", e))
+		}
+
+		d := &delivery{}
+
+		if e := json.Unmarshal(value, d); e != nil {
+			return nil, fmt.Errorf("unmarshal pending delivery: %w", e)
+		}
+
+		deliveries = append(deliveries, d)
+	}
+
+	return deliveries, nil
+}
+
+func hash(values ...string) string {
+	h := sha256.Sum256([]byte(strings.Join(values, "\n")))
+
+	return hex.EncodeToString(h[:])
+}
@@ -0,0 +1,204 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pendingdelivery
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+	"github.com/trustbloc/orb/pkg/store/expiry"
+	"github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+var (
+	activity1 = testutil.MustParseURL("https://domain1.com/services/orb/activities/1")
+	activity2 = testutil.MustParseURL("https://domain1.com/services/orb/activities/2")
+	inbox1    = testutil.MustParseURL("https://domain2.com/services/orb/inbox")
+	inbox2    = testutil.MustParseURL("https://domain3.com/services/orb/inbox")
+)
+
+func TestNewStore(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		es := &mockExpiryService{}
+
+		s, err := NewStore(mem.NewProvider(), es, 0)
+		require.NoError(t, err)
+		require.NotNil(t, s)
+		require.Equal(t, defaultRetention, s.retention)
+		require.Equal(t, storeName, es.storeName)
+		require.Equal(t, expiryTag, es.expiryTagName)
+	})
+
+	t.Run("Open store error", func(t *testing.T) {
+		p := &mocks.Provider{}
+		p.OpenStoreReturns(nil, errors.New("injected open error"))
+
+		_, err := NewStore(p, &mockExpiryService{}, time.Hour)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected open error")
+	})
+
+	t.Run("Set store config error", func(t *testing.T) {
+		p := &mocks.Provider{}
+		p.SetStoreConfigReturns(errors.New("injected config error"))
+
+		_, err := NewStore(p, &mockExpiryService{}, time.Hour)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected config error")
+	})
+}
+
+func TestStore(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		s, err := NewStore(mem.NewProvider(), &mockExpiryService{}, time.Hour)
+		require.NoError(t, err)
+
+		pending, err := s.GetAll()
+		require.NoError(t, err)
+		require.Empty(t, pending)
+
+		require.NoError(t, s.Add(activity1, nil))
+		require.NoError(t, s.Add(activity1, []*url.URL{inbox1, inbox2}))
+		require.NoError(t, s.Add(activity2, []*url.URL{inbox1}))
+
+		pending, err = s.GetAll()
+		require.NoError(t, err)
+		require.Len(t, pending, 2)
+
+		for _, p := range pending {
+			switch p.ActivityID.String() {
+			case activity1.String():
+				require.Len(t, p.Inboxes, 2)
+			case activity2.String():
+				require.Len(t, p.Inboxes, 1)
+				require.Equal(t, inbox1.String(), p.Inboxes[0].String())
+			default:
+				t.Fatalf("unexpected activity [%s]", p.ActivityID)
+			}
+		}
+
+		require.NoError(t, s.Remove(activity1.String(), inbox1))
+		require.NoError(t, s.Remove(activity2.String(), inbox1))
+
+		// Removing a delivery that isn't pending is not an error.
+		require.NoError(t, s.Remove(activity2.String(), inbox1))
+
+		pending, err = s.GetAll()
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		require.Equal(t, activity1.String(), pending[0].ActivityID.String())
+		require.Len(t, pending[0].Inboxes, 1)
+		require.Equal(t, inbox2.String(), pending[0].Inboxes[0].String())
+	})
+
+	t.Run("Store errors", func(t *testing.T) {
+		errExpected := errors.New("injected store error")
+
+		store := &mocks.Store{}
+		store.BatchReturns(errExpected)
+		store.DeleteReturns(errExpected)
+		store.QueryReturns(nil, errExpected)
+
+		p := &mocks.Provider{}
+		p.OpenStoreReturns(store, nil)
+
+		s, err := NewStore(p, &mockExpiryService{}, time.Hour)
+		require.NoError(t, err)
+
+		require.ErrorIs(t, s.Add(activity1, []*url.URL{inbox1}), errExpected)
+		require.ErrorIs(t, s.Remove(activity1.String(), inbox1), errExpected)
+
+		_, err = s.GetAll()
+		require.ErrorIs(t, err, errExpected)
+
+		store.DeleteReturns(storage.ErrDataNotFound)
+
+		require.NoError(t, s.Remove(activity1.String(), inbox1))
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		s, err := NewStore(mem.NewProvider(), &mockExpiryService{}, time.Hour)
+		require.NoError(t, err)
+
+		errExpected := errors.New("injected marshal error")
+
+		s.marshal = func(v interface{}) ([]byte, error) { return nil, errExpected }
+
+		require.ErrorIs(t, s.Add(activity1, []*url.URL{inbox1}), errExpected)
+	})
+
+	t.Run("Iterator errors", func(t *testing.T) {
+		errExpected := errors.New("injected iterator error")
+
+		it := &mocks.Iterator{}
+		it.NextReturns(false, errExpected)
+		it.CloseReturns(errors.New("injected close error"))
+
+		store := &mocks.Store{}
+		store.QueryReturns(it, nil)
+
+		p := &mocks.Provider{}
+		p.OpenStoreReturns(store, nil)
+
+		s, err := NewStore(p, &mockExpiryService{}, time.Hour)
+		require.NoError(t, err)
+
+		_, err = s.GetAll()
+		require.ErrorIs(t, err, errExpected)
+
+		it.NextReturns(true, nil)
+		it.ValueReturns(nil, errExpected)
+
+		_, err = s.GetAll()
+		require.ErrorIs(t, err, errExpected)
+
+		it.ValueReturns([]byte("xxx"), nil)
+
+		_, err = s.GetAll()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal pending delivery")
+	})
+
+	t.Run("Invalid URLs", func(t *testing.T) {
+		it := &mocks.Iterator{}
+		it.NextReturnsOnCall(0, true, nil)
+		it.NextReturnsOnCall(1, true, nil)
+		it.NextReturnsOnCall(2, false, nil)
+		it.ValueReturnsOnCall(0, []byte(`{"activity":"https://domain1.com/activities/1","inbox":":xxx"}`), nil)
+		it.ValueReturnsOnCall(1, []byte(`{"activity":":xxx","inbox":"https://domain2.com/inbox"}`), nil)
+
+		store := &mocks.Store{}
+		store.QueryReturns(it, nil)
+
+		p := &mocks.Provider{}
+		p.OpenStoreReturns(store, nil)
+
+		s, err := NewStore(p, &mockExpiryService{}, time.Hour)
+		require.NoError(t, err)
+
+		pending, err := s.GetAll()
+		require.NoError(t, err)
+		require.Empty(t, pending)
+	})
+}
+
+type mockExpiryService struct {
+	storeName     string
+	expiryTagName string
+}
+
+func (m *mockExpiryService) Register(_ storage.Store, expiryTagName, storeName string, _ ...expiry.Option) {
+	m.storeName = storeName
+	m.expiryTagName = expiryTagName
+}
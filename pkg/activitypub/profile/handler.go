@@ -0,0 +1,163 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package profile
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+// Path is the path of the profile endpoint.
+const Path = "/profile"
+
+const (
+	badRequestResponse          = "Bad Request."
+	internalServerErrorResponse = "Internal Server Error."
+)
+
+type profileManager interface {
+	Get() (*Profile, error)
+	Update(p *Profile) error
+}
+
+// Reader implements a REST handler that returns the profile of the service actor.
+type Reader struct {
+	mgr     profileManager
+	marshal func(v interface{}) ([]byte, error)
+}
+
+// NewReader returns a new profile reader.
+func NewReader(mgr profileManager) *Reader {
+	return &Reader{
+		mgr:     mgr,
+		marshal: json.Marshal,
+	}
+}
+
+// Path returns the HTTP REST endpoint of the handler.
+func (h *Reader) Path() string {
+	return Path
+}
+
+// Method returns the HTTP method, which is always GET.
+func (h *Reader) Method() string {
+	return http.MethodGet
+}
+
+// Handler returns the HTTP REST handle.
+func (h *Reader) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Reader) handle(w http.ResponseWriter, _ *http.Request) {
+	p, err := h.mgr.Get()
+	if err != nil {
+		logger.Errorf("[%s] Error retrieving profile: %s", Path, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	profileBytes, err := h.marshal(p)
+	if err != nil {
+		logger.Errorf("[%s] Error marshalling profile: %s", Path, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	writeResponse(w, http.StatusOK, profileBytes)
+}
+
+// Writer implements a REST handler that replaces the profile of the service actor. The request is a JSON
+// profile, for example {"name":"Orb Domain1","icon":{"url":"https://orb.domain1.com/icon.png"}}.
+type Writer struct {
+	mgr     profileManager
+	readAll func(r io.Reader) ([]byte, error)
+}
+
+// NewWriter returns a new profile writer.
+func NewWriter(mgr profileManager) *Writer {
+	return &Writer{
+		mgr:     mgr,
+		readAll: ioutil.ReadAll,
+	}
+}
+
+// Path returns the HTTP REST endpoint of the handler.
+func (h *Writer) Path() string {
+	return Path
+}
+
+// Method returns the HTTP method, which is always POST.
+func (h *Writer) Method() string {
+	return http.MethodPost
+}
+
+// Handler returns the HTTP REST handle.
+func (h *Writer) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Writer) handle(w http.ResponseWriter, req *http.Request) {
+	reqBytes, err := h.readAll(req.Body)
+	if err != nil {
+		logger.Errorf("[%s] Error reading request body: %s", Path, err)
+
+		writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	p := &Profile{}
+
+	if err := json.Unmarshal(reqBytes, p); err != nil {
+		logger.Infof("[%s] Invalid profile request: %s", Path, err)
+
+		writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	if err := h.mgr.Update(p); err != nil {
+		if orberrors.IsBadRequest(err) {
+			logger.Infof("[%s] Error updating profile: %s", Path, err)
+
+			writeResponse(w, http.StatusBadRequest, []byte(err.Error()))
+
+			return
+		}
+
+		logger.Errorf("[%s] Error updating profile: %s", Path, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	logger.Infof("[%s] Updated profile: %s", Path, reqBytes)
+
+	writeResponse(w, http.StatusOK, nil)
+}
+
+func writeResponse(w http.ResponseWriter, status int, body []byte) {
+	w.WriteHeader(status)
+
+	if len(body) > 0 {
+		if _, err := w.Write(body); err != nil {
+			logger.Warnf("[%s] Unable to write response: %s", Path, err)
+		}
+	}
+}
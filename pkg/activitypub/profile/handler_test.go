@@ -0,0 +1,117 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package profile
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/internal/testutil/httptestutil"
+)
+
+func TestReader(t *testing.T) {
+	m, err := NewManager(newDynamicConfig(t))
+	require.NoError(t, err)
+
+	h := NewReader(m)
+	require.Equal(t, Path, h.Path())
+	require.Equal(t, http.MethodGet, h.Method())
+	require.NotNil(t, h.Handler())
+
+	t.Run("Empty", func(t *testing.T) {
+		code, body := httptestutil.Get(t, h.handle, Path)
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, "{}", string(body))
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		p1, err := Parse(profile1)
+		require.NoError(t, err)
+		require.NoError(t, m.Update(p1))
+
+		code, body := httptestutil.Get(t, h.handle, Path)
+		require.Equal(t, http.StatusOK, code)
+
+		p := &Profile{}
+		require.NoError(t, json.Unmarshal(body, p))
+		require.Equal(t, p1, p)
+	})
+
+	t.Run("Manager error", func(t *testing.T) {
+		code, _ := httptestutil.Get(t, NewReader(&mockManager{err: errors.New("injected error")}).handle, Path)
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		h := NewReader(m)
+		h.marshal = func(v interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		code, _ := httptestutil.Get(t, h.handle, Path)
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+}
+
+func TestWriter(t *testing.T) {
+	m, err := NewManager(newDynamicConfig(t))
+	require.NoError(t, err)
+
+	h := NewWriter(m)
+	require.Equal(t, Path, h.Path())
+	require.Equal(t, http.MethodPost, h.Method())
+	require.NotNil(t, h.Handler())
+
+	t.Run("Success", func(t *testing.T) {
+		code, _ := httptestutil.Serve(t, h.handle, http.MethodPost, Path, []byte(profile1), nil)
+		require.Equal(t, http.StatusOK, code)
+
+		p, err := m.Get()
+		require.NoError(t, err)
+		require.Equal(t, "Orb Domain1", p.Name)
+	})
+
+	t.Run("Invalid JSON", func(t *testing.T) {
+		code, _ := httptestutil.Serve(t, h.handle, http.MethodPost, Path, []byte("{"), nil)
+		require.Equal(t, http.StatusBadRequest, code)
+	})
+
+	t.Run("Invalid profile", func(t *testing.T) {
+		code, body := httptestutil.Serve(t, h.handle, http.MethodPost, Path, []byte(`{"icon":{"url":"icon.png"}}`), nil)
+		require.Equal(t, http.StatusBadRequest, code)
+		require.Contains(t, string(body), "icon URL must be an absolute HTTP(S) URL")
+	})
+
+	t.Run("Read error", func(t *testing.T) {
+		h := NewWriter(m)
+		h.readAll = func(io.Reader) ([]byte, error) { return nil, errors.New("injected read error") }
+
+		code, _ := httptestutil.Serve(t, h.handle, http.MethodPost, Path, []byte(profile1), nil)
+		require.Equal(t, http.StatusBadRequest, code)
+	})
+
+	t.Run("Manager error", func(t *testing.T) {
+		code, _ := httptestutil.Serve(t, NewWriter(&mockManager{err: errors.New("injected error")}).handle,
+			http.MethodPost, Path, []byte(profile1), nil)
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+}
+
+type mockManager struct {
+	err error
+}
+
+func (m *mockManager) Get() (*Profile, error) {
+	return nil, m.err
+}
+
+func (m *mockManager) Update(*Profile) error {
+	return m.err
+}
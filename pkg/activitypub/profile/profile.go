@@ -0,0 +1,227 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package profile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/config/dynamic"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+var logger = log.New("actor-profile")
+
+// ParameterName is the name of the dynamic configuration parameter that holds the profile.
+const ParameterName = "actor-profile"
+
+const (
+	maxNameLength    = 100
+	maxSummaryLength = 2000
+	maxContacts      = 10
+	maxContactLength = 255
+)
+
+// Icon is the icon of the service actor.
+type Icon struct {
+	URL       string `json:"url"`
+	MediaType string `json:"mediaType,omitempty"`
+}
+
+// Contact is a name/value pair that contains contact information, for example
+// {"name":"Email","value":"admin@orb.domain1.com"}.
+type Contact struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Profile contains the human-readable information that is presented in the service actor document.
+type Profile struct {
+	Name    string     `json:"name,omitempty"`
+	Summary string     `json:"summary,omitempty"`
+	Icon    *Icon      `json:"icon,omitempty"`
+	Contact []*Contact `json:"contact,omitempty"`
+}
+
+// Parse parses and validates the given JSON profile. An empty value results in an empty profile.
+func Parse(value string) (*Profile, error) {
+	p := &Profile{}
+
+	if strings.TrimSpace(value) == "" {
+		return p, nil
+	}
+
+	if err := json.Unmarshal([]byte(value), p); err != nil {
+		return nil, fmt.Errorf("unmarshal profile: %w", err)
+	}
+
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Validate validates the profile.
+func (p *Profile) Validate() error {
+	if len(p.Name) > maxNameLength {
+		return fmt.Errorf("name must not exceed %d characters", maxNameLength)
+	}
+
+	if len(p.Summary) > maxSummaryLength {
+		return fmt.Errorf("summary must not exceed %d characters", maxSummaryLength)
+	}
+
+	if p.Icon != nil {
+		if err := validateIcon(p.Icon); err != nil {
+			return err
+		}
+	}
+
+	if len(p.Contact) > maxContacts {
+		return fmt.Errorf("no more than %d contacts may be specified", maxContacts)
+	}
+
+	for _, c := range p.Contact {
+		if c == nil || c.Name == "" || c.Value == "" {
+			return errors.New("contact name and value are required")
+		}
+
+		if len(c.Name) > maxContactLength || len(c.Value) > maxContactLength {
+			return fmt.Errorf("contact name and value must not exceed %d characters", maxContactLength)
+		}
+	}
+
+	return nil
+}
+
+// Options returns the options that add the profile to the service actor.
+func (p *Profile) Options() ([]vocab.Opt, error) {
+	var opts []vocab.Opt
+
+	if p.Name != "" {
+		opts = append(opts, vocab.WithName(p.Name))
+	}
+
+	if p.Summary != "" {
+		opts = append(opts, vocab.WithSummary(p.Summary))
+	}
+
+	if p.Icon != nil {
+		iconURL, err := url.Parse(p.Icon.URL)
+		if err != nil {
+			return nil, fmt.Errorf("parse icon URL: %w", err)
+		}
+
+		opts = append(opts, vocab.WithIcon(vocab.NewImage(iconURL, p.Icon.MediaType)))
+	}
+
+	for _, c := range p.Contact {
+		pv, err := vocab.NewPropertyValue(c.Name, c.Value)
+		if err != nil {
+			return nil, fmt.Errorf("create contact property [%s]: %w", c.Name, err)
+		}
+
+		opts = append(opts, vocab.WithAttachment(vocab.NewObjectProperty(vocab.WithObject(pv))))
+	}
+
+	return opts, nil
+}
+
+func validateIcon(icon *Icon) error {
+	iconURL, err := url.Parse(icon.URL)
+	if err != nil {
+		return fmt.Errorf("invalid icon URL: %w", err)
+	}
+
+	if (iconURL.Scheme != "https" && iconURL.Scheme != "http") || iconURL.Host == "" {
+		return fmt.Errorf("icon URL must be an absolute HTTP(S) URL: %s", icon.URL)
+	}
+
+	if icon.MediaType != "" && !strings.HasPrefix(icon.MediaType, "image/") {
+		return fmt.Errorf("invalid icon media type: %s", icon.MediaType)
+	}
+
+	return nil
+}
+
+type dynamicConfig interface {
+	Register(p *dynamic.Parameter) error
+	Subscribe(name string, handler dynamic.ChangeHandler) error
+	Get(name string) (string, error)
+	Update(values map[string]string) error
+}
+
+// Manager manages the profile of the service actor. The profile is persisted as a dynamic configuration
+// parameter so that it is shared by all server instances in the domain and updates made on one instance
+// are picked up by the others.
+type Manager struct {
+	cfg dynamicConfig
+}
+
+// NewManager registers the profile parameter with the given dynamic configuration and returns a new manager.
+func NewManager(cfg dynamicConfig) (*Manager, error) {
+	err := cfg.Register(&dynamic.Parameter{
+		Name:        ParameterName,
+		Description: "The profile (name, summary, icon and contact information) of the service actor.",
+		Validate: func(value string) error {
+			_, err := Parse(value)
+
+			return err
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("register parameter [%s]: %w", ParameterName, err)
+	}
+
+	return &Manager{cfg: cfg}, nil
+}
+
+// Get returns the current profile.
+func (m *Manager) Get() (*Profile, error) {
+	value, err := m.cfg.Get(ParameterName)
+	if err != nil {
+		return nil, err
+	}
+
+	return Parse(value)
+}
+
+// Update validates and stores the given profile.
+func (m *Manager) Update(p *Profile) error {
+	if err := p.Validate(); err != nil {
+		return orberrors.NewBadRequest(err)
+	}
+
+	profileBytes, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("marshal profile: %w", err)
+	}
+
+	return m.cfg.Update(map[string]string{ParameterName: string(profileBytes)})
+}
+
+// Subscribe registers a handler that is notified when the profile changes. The handler is immediately
+// invoked with the current profile.
+func (m *Manager) Subscribe(handler func(p *Profile)) error {
+	return m.cfg.Subscribe(ParameterName, func(value string) {
+		p, err := Parse(value)
+		if err != nil {
+			logger.Warnf("Invalid value for [%s]: %s", ParameterName, err)
+
+			return
+		}
+
+		handler(p)
+	})
+}
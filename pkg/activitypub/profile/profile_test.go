@@ -0,0 +1,217 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package profile
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/config/dynamic"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+	storemocks "github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+const profile1 = `{"name":"Orb Domain1","summary":"Orb node for domain1",` +
+	`"icon":{"url":"https://orb.domain1.com/icon.png","mediaType":"image/png"},` +
+	`"contact":[{"name":"Email","value":"admin@orb.domain1.com"}]}`
+
+func TestParse(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		p, err := Parse(profile1)
+		require.NoError(t, err)
+		require.Equal(t, "Orb Domain1", p.Name)
+		require.Equal(t, "Orb node for domain1", p.Summary)
+		require.NotNil(t, p.Icon)
+		require.Equal(t, "https://orb.domain1.com/icon.png", p.Icon.URL)
+		require.Equal(t, "image/png", p.Icon.MediaType)
+		require.Len(t, p.Contact, 1)
+		require.Equal(t, "Email", p.Contact[0].Name)
+		require.Equal(t, "admin@orb.domain1.com", p.Contact[0].Value)
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		p, err := Parse("")
+		require.NoError(t, err)
+		require.Equal(t, &Profile{}, p)
+	})
+
+	t.Run("Invalid JSON", func(t *testing.T) {
+		_, err := Parse("{")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal profile")
+	})
+
+	t.Run("Validation error", func(t *testing.T) {
+		_, err := Parse(`{"icon":{"url":"icon.png"}}`)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "icon URL must be an absolute HTTP(S) URL")
+	})
+}
+
+func TestProfile_Validate(t *testing.T) {
+	require.NoError(t, (&Profile{}).Validate())
+
+	tests := []struct {
+		name    string
+		profile *Profile
+		errMsg  string
+	}{
+		{"Name too long", &Profile{Name: strings.Repeat("x", maxNameLength+1)}, "name must not exceed"},
+		{"Summary too long", &Profile{Summary: strings.Repeat("x", maxSummaryLength+1)}, "summary must not exceed"},
+		{"Invalid icon URL", &Profile{Icon: &Icon{URL: "://xxx"}}, "invalid icon URL"},
+		{"Relative icon URL", &Profile{Icon: &Icon{URL: "/icon.png"}}, "absolute HTTP(S) URL"},
+		{
+			"Invalid icon media type",
+			&Profile{Icon: &Icon{URL: "https://orb.domain1.com/icon.png", MediaType: "text/plain"}},
+			"invalid icon media type",
+		},
+		{"Too many contacts", &Profile{Contact: make([]*Contact, maxContacts+1)}, "no more than"},
+		{"Nil contact", &Profile{Contact: []*Contact{nil}}, "contact name and value are required"},
+		{"Missing contact value", &Profile{Contact: []*Contact{{Name: "Email"}}}, "name and value are required"},
+		{
+			"Contact too long",
+			&Profile{Contact: []*Contact{{Name: "Email", Value: strings.Repeat("x", maxContactLength+1)}}},
+			"must not exceed",
+		},
+	}
+
+	for _, tc := range tests {
+		test := tc
+
+		t.Run(test.name, func(t *testing.T) {
+			err := test.profile.Validate()
+			require.Error(t, err)
+			require.Contains(t, err.Error(), test.errMsg)
+		})
+	}
+}
+
+func TestProfile_Options(t *testing.T) {
+	serviceIRI := testutil.MustParseURL("https://orb.domain1.com/services/orb")
+
+	t.Run("Success", func(t *testing.T) {
+		p, err := Parse(profile1)
+		require.NoError(t, err)
+
+		opts, err := p.Options()
+		require.NoError(t, err)
+
+		service := vocab.NewService(serviceIRI, opts...)
+		require.Equal(t, "Orb Domain1", service.Name())
+		require.Equal(t, "Orb node for domain1", service.Summary())
+		require.NotNil(t, service.Icon())
+		require.Equal(t, "https://orb.domain1.com/icon.png", service.Icon().URL.String())
+		require.Len(t, service.Attachment(), 1)
+		require.True(t, service.Attachment()[0].Type().Is(vocab.TypePropertyValue))
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		opts, err := (&Profile{}).Options()
+		require.NoError(t, err)
+		require.Empty(t, opts)
+	})
+
+	t.Run("Invalid icon URL", func(t *testing.T) {
+		_, err := (&Profile{Icon: &Icon{URL: "://xxx"}}).Options()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "parse icon URL")
+	})
+}
+
+func TestManager(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		cfg := newDynamicConfig(t)
+
+		m, err := NewManager(cfg)
+		require.NoError(t, err)
+
+		var profiles []*Profile
+
+		require.NoError(t, m.Subscribe(func(p *Profile) {
+			profiles = append(profiles, p)
+		}))
+
+		require.Len(t, profiles, 1)
+		require.Equal(t, &Profile{}, profiles[0])
+
+		p, err := m.Get()
+		require.NoError(t, err)
+		require.Equal(t, &Profile{}, p)
+
+		p1, err := Parse(profile1)
+		require.NoError(t, err)
+
+		require.NoError(t, m.Update(p1))
+
+		p, err = m.Get()
+		require.NoError(t, err)
+		require.Equal(t, p1, p)
+
+		require.Len(t, profiles, 2)
+		require.Equal(t, p1, profiles[1])
+	})
+
+	t.Run("Already registered", func(t *testing.T) {
+		cfg := newDynamicConfig(t)
+
+		_, err := NewManager(cfg)
+		require.NoError(t, err)
+
+		_, err = NewManager(cfg)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "already registered")
+	})
+
+	t.Run("Invalid profile", func(t *testing.T) {
+		m, err := NewManager(newDynamicConfig(t))
+		require.NoError(t, err)
+
+		err = m.Update(&Profile{Icon: &Icon{URL: "icon.png"}})
+		require.Error(t, err)
+		require.True(t, orberrors.IsBadRequest(err))
+	})
+
+	t.Run("Register error", func(t *testing.T) {
+		errExpected := errors.New("injected get error")
+
+		s := &storemocks.Store{}
+		s.GetReturns(nil, errExpected)
+
+		_, err := NewManager(dynamic.New(s))
+		require.ErrorIs(t, err, errExpected)
+		require.Contains(t, err.Error(), "register parameter")
+	})
+
+	t.Run("Store error", func(t *testing.T) {
+		errExpected := errors.New("injected batch error")
+
+		s := &storemocks.Store{}
+		s.GetReturns(nil, storage.ErrDataNotFound)
+		s.BatchReturns(errExpected)
+
+		m, err := NewManager(dynamic.New(s))
+		require.NoError(t, err)
+
+		require.ErrorIs(t, m.Update(&Profile{Name: "Orb"}), errExpected)
+	})
+}
+
+func newDynamicConfig(t *testing.T) *dynamic.Manager {
+	t.Helper()
+
+	s, err := mem.NewProvider().OpenStore("orb-config")
+	require.NoError(t, err)
+
+	return dynamic.New(s)
+}
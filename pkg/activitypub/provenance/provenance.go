@@ -0,0 +1,261 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package provenance
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	store "github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/anchor/util"
+	"github.com/trustbloc/orb/pkg/hashlink"
+)
+
+var logger = log.New("activitypub_provenance")
+
+const (
+	// Path is the path (relative to the service IRI) of the anchor provenance endpoint.
+	Path = "/anchorevents/{id}/provenance"
+
+	maxActivities = 100
+)
+
+// ErrNotFound is returned if no provenance was recorded for an anchor.
+var ErrNotFound = errors.New("anchor provenance not found")
+
+// Activity contains the details of an activity in the provenance chain of an anchor.
+type Activity struct {
+	ID        string     `json:"id"`
+	Type      string     `json:"type"`
+	Actor     string     `json:"actor,omitempty"`
+	Published *time.Time `json:"published,omitempty"`
+}
+
+// Chain is the chain of activities that led to the local acceptance of an anchor, in the order in which they
+// were published. The chain starts with the 'Create' activity (if the anchor was received from the origin or was
+// created locally) or the 'Announce' activity through which the anchor was received, and ends with the 'Like'
+// activity with which the anchor was acknowledged. Witnesses contains the domains of the witnesses whose proofs
+// are included in the anchor (if the anchor was embedded in one of the activities).
+type Chain struct {
+	Anchor       string      `json:"anchor"`
+	AttributedTo string      `json:"attributedTo,omitempty"`
+	Activities   []*Activity `json:"activities"`
+	Witnesses    []string    `json:"witnesses,omitempty"`
+}
+
+type activityStore interface {
+	QueryActivities(query *store.Criteria, opts ...store.QueryOpt) (store.ActivityIterator, error)
+}
+
+// Builder builds the provenance chain of an anchor from the provenance references in the activity store.
+type Builder struct {
+	activityStore activityStore
+}
+
+// NewBuilder returns a new provenance chain builder.
+func NewBuilder(activityStore activityStore) *Builder {
+	return &Builder{activityStore: activityStore}
+}
+
+// AnchorIRI returns the IRI under which the provenance of the given anchor is stored, i.e. the canonical
+// hashlink (without metadata) of the anchor. The anchor may be specified as a hashlink or as a resource hash.
+func AnchorIRI(anchor string) (*url.URL, error) {
+	resourceHash := anchor
+
+	if strings.HasPrefix(anchor, hashlink.HLPrefix) {
+		var err error
+
+		resourceHash, err = hashlink.GetResourceHashFromHashLink(anchor)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if resourceHash == "" || strings.Contains(resourceHash, ":") {
+		return nil, fmt.Errorf("invalid anchor [%s]", anchor)
+	}
+
+	return url.Parse(hashlink.GetHashLinkFromResourceHash(resourceHash))
+}
+
+// Build returns the provenance chain of the given anchor (hashlink or resource hash) or ErrNotFound if no
+// provenance was recorded for the anchor.
+func (b *Builder) Build(anchor string) (*Chain, error) {
+	anchorIRI, err := AnchorIRI(anchor)
+	if err != nil {
+		return nil, err
+	}
+
+	activities, err := b.getActivities(anchorIRI)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(activities) == 0 {
+		return nil, ErrNotFound
+	}
+
+	chain := &Chain{
+		Anchor:     anchorIRI.String(),
+		Activities: make([]*Activity, len(activities)),
+	}
+
+	for i, activity := range activities {
+		chain.Activities[i] = newActivity(activity)
+
+		anchorEvent := getAnchorEvent(activity)
+		if anchorEvent == nil {
+			continue
+		}
+
+		if chain.AttributedTo == "" && anchorEvent.AttributedTo() != nil {
+			chain.AttributedTo = anchorEvent.AttributedTo().String()
+		}
+
+		if chain.Witnesses == nil && anchorEvent.Index() != nil {
+			chain.Witnesses = getWitnesses(anchorEvent)
+		}
+	}
+
+	return chain, nil
+}
+
+func (b *Builder) getActivities(anchorIRI *url.URL) ([]*vocab.ActivityType, error) {
+	it, err := b.activityStore.QueryActivities(
+		store.NewCriteria(
+			store.WithReferenceType(store.Provenance),
+			store.WithObjectIRI(anchorIRI),
+		),
+		store.WithPageSize(maxActivities),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query provenance of anchor [%s]: %w", anchorIRI, err)
+	}
+
+	defer func() {
+		if e := it.Close(); e != nil {
+			logger.Warnf("Error closing iterator: %s", e)
+		}
+	}()
+
+	var activities []*vocab.ActivityType
+
+	for len(activities) < maxActivities {
+		activity, e := it.Next()
+		if e != nil {
+			if errors.Is(e, store.ErrNotFound) {
+				break
+			}
+
+			return nil, fmt.Errorf("get next activity in provenance of anchor [%s]: %w", anchorIRI, e)
+		}
+
+		activities = append(activities, activity)
+	}
+
+	sort.SliceStable(activities, func(i, j int) bool {
+		return published(activities[i]).Before(published(activities[j]))
+	})
+
+	return activities, nil
+}
+
+func newActivity(activity *vocab.ActivityType) *Activity {
+	a := &Activity{
+		ID:        activity.ID().String(),
+		Published: activity.Published(),
+	}
+
+	if activity.Type() != nil && len(activity.Type().Types()) > 0 {
+		a.Type = string(activity.Type().Types()[0])
+	}
+
+	if activity.Actor() != nil {
+		a.Actor = activity.Actor().String()
+	}
+
+	return a
+}
+
+// getAnchorEvent returns the anchor event in the given 'Create' or 'Announce' activity or nil if the activity
+// doesn't contain an anchor event.
+func getAnchorEvent(activity *vocab.ActivityType) *vocab.AnchorEventType {
+	obj := activity.Object()
+	if obj == nil {
+		return nil
+	}
+
+	if obj.AnchorEvent() != nil {
+		return obj.AnchorEvent()
+	}
+
+	if obj.Collection() != nil {
+		for _, item := range obj.Collection().Items() {
+			if item.AnchorEvent() != nil {
+				return item.AnchorEvent()
+			}
+		}
+	}
+
+	return nil
+}
+
+// getWitnesses returns the (unique) domains of the proofs on the anchor credential in the given anchor event.
+func getWitnesses(anchorEvent *vocab.AnchorEventType) []string {
+	witnessDoc, err := util.GetWitnessDoc(anchorEvent)
+	if err != nil {
+		logger.Debugf("Unable to get witness document from anchor event [%s]: %s", anchorEvent.URL(), err)
+
+		return nil
+	}
+
+	var proofs []interface{}
+
+	switch p := witnessDoc["proof"].(type) {
+	case []interface{}:
+		proofs = p
+	case map[string]interface{}:
+		proofs = []interface{}{p}
+	}
+
+	var witnesses []string
+
+	added := make(map[string]bool)
+
+	for _, p := range proofs {
+		proof, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		domain, ok := proof["domain"].(string)
+		if !ok || domain == "" || added[domain] {
+			continue
+		}
+
+		added[domain] = true
+
+		witnesses = append(witnesses, domain)
+	}
+
+	return witnesses
+}
+
+func published(activity *vocab.ActivityType) time.Time {
+	if activity.Published() == nil {
+		return time.Time{}
+	}
+
+	return *activity.Published()
+}
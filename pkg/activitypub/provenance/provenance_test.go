@@ -0,0 +1,195 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package provenance
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
+	store "github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/anchor/anchorevent"
+	"github.com/trustbloc/orb/pkg/anchor/subject"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+)
+
+const (
+	anchorHash = "uEiDaapVGORqCmdWe5kkAbDzPpUFBuq_RgAYwhCXH5ehXbw"
+	anchorHL   = "hl:" + anchorHash + ":uoQ-BeEtodHRwczovL2V4YW1wbGUuY29tL2Nhcw"
+
+	witnessDoc = `{
+  "@context": ["https://www.w3.org/2018/credentials/v1"],
+  "type": "VerifiableCredential",
+  "issuer": "https://orb.domain1.com",
+  "issuanceDate": "2021-01-27T09:30:10Z",
+  "credentialSubject": {},
+  "proof": [
+    {"type": "Ed25519Signature2018", "domain": "https://orb.domain1.com"},
+    {"type": "Ed25519Signature2018", "domain": "https://orb.domain2.com"},
+    {"type": "Ed25519Signature2018", "domain": "https://orb.domain2.com"},
+    {"type": "Ed25519Signature2018"}
+  ]
+}`
+)
+
+var (
+	service1IRI = testutil.MustParseURL("https://orb.domain1.com/services/orb")
+	service2IRI = testutil.MustParseURL("https://orb.domain2.com/services/orb")
+	service3IRI = testutil.MustParseURL("https://orb.domain3.com/services/orb")
+)
+
+func TestAnchorIRI(t *testing.T) {
+	iri, err := AnchorIRI(anchorHL)
+	require.NoError(t, err)
+	require.Equal(t, "hl:"+anchorHash, iri.String())
+
+	iri, err = AnchorIRI(anchorHash)
+	require.NoError(t, err)
+	require.Equal(t, "hl:"+anchorHash, iri.String())
+
+	_, err = AnchorIRI("")
+	require.Error(t, err)
+
+	_, err = AnchorIRI("hl:")
+	require.Error(t, err)
+
+	_, err = AnchorIRI("https://orb.domain1.com/cas/" + anchorHash)
+	require.Error(t, err)
+}
+
+func TestBuilder_Build(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		s := memstore.New("")
+
+		anchorIRI, err := AnchorIRI(anchorHL)
+		require.NoError(t, err)
+
+		now := time.Now()
+
+		anchorEvent := newAnchorEvent(t)
+
+		// Added out of order in order to test that the activities are sorted by published time.
+		like := addActivity(t, s, anchorIRI, vocab.NewLikeActivity(
+			vocab.NewObjectProperty(vocab.WithAnchorEvent(vocab.NewAnchorEvent(
+				vocab.WithURL(testutil.MustParseURL(anchorHL))),
+			)),
+			vocab.WithID(testutil.NewMockID(service3IRI, "/activities/like")),
+			vocab.WithActor(service3IRI),
+			vocab.WithPublishedTime(timePtr(now.Add(time.Minute))),
+		))
+
+		announce := addActivity(t, s, anchorIRI, vocab.NewAnnounceActivity(
+			vocab.NewObjectProperty(vocab.WithCollection(vocab.NewCollection(
+				[]*vocab.ObjectProperty{vocab.NewObjectProperty(vocab.WithAnchorEvent(anchorEvent))},
+			))),
+			vocab.WithID(testutil.NewMockID(service2IRI, "/activities/announce")),
+			vocab.WithActor(service2IRI),
+			vocab.WithPublishedTime(&now),
+		))
+
+		// An activity for another anchor.
+		addActivity(t, s, testutil.MustParseURL("hl:uEiBTDSWBv0PsUzqKXjm4yMDuavCZGHq4sg2jNlWKN5yWSA"),
+			vocab.NewLikeActivity(
+				vocab.NewObjectProperty(vocab.WithIRI(service1IRI)),
+				vocab.WithID(testutil.NewMockID(service3IRI, "/activities/like2")),
+				vocab.WithActor(service3IRI),
+			))
+
+		chain, err := NewBuilder(s).Build(anchorHash)
+		require.NoError(t, err)
+		require.Equal(t, "hl:"+anchorHash, chain.Anchor)
+		require.Equal(t, service1IRI.String(), chain.AttributedTo)
+		require.Equal(t, []string{"https://orb.domain1.com", "https://orb.domain2.com"}, chain.Witnesses)
+		require.Len(t, chain.Activities, 2)
+
+		require.Equal(t, announce.ID().String(), chain.Activities[0].ID)
+		require.Equal(t, string(vocab.TypeAnnounce), chain.Activities[0].Type)
+		require.Equal(t, service2IRI.String(), chain.Activities[0].Actor)
+		require.NotNil(t, chain.Activities[0].Published)
+
+		require.Equal(t, like.ID().String(), chain.Activities[1].ID)
+		require.Equal(t, string(vocab.TypeLike), chain.Activities[1].Type)
+		require.Equal(t, service3IRI.String(), chain.Activities[1].Actor)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := NewBuilder(memstore.New("")).Build(anchorHL)
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("invalid anchor", func(t *testing.T) {
+		_, err := NewBuilder(memstore.New("")).Build("hl:")
+		require.Error(t, err)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		_, err := NewBuilder(&mockActivityStore{err: errors.New("injected query error")}).Build(anchorHL)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected query error")
+	})
+}
+
+func TestGetWitnesses(t *testing.T) {
+	require.Equal(t, []string{"https://orb.domain1.com", "https://orb.domain2.com"},
+		getWitnesses(newAnchorEvent(t)))
+
+	require.Empty(t, getWitnesses(vocab.NewAnchorEvent()))
+}
+
+func newAnchorEvent(t *testing.T) *vocab.AnchorEventType {
+	t.Helper()
+
+	payload := &subject.Payload{
+		OperationCount: 1,
+		CoreIndex:      "coreIndex",
+		Namespace:      "did:orb",
+		Version:        0,
+		AnchorOrigin:   service1IRI.String(),
+		PreviousAnchors: []*subject.SuffixAnchor{
+			{Suffix: "EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A"},
+		},
+	}
+
+	contentObj, err := anchorevent.BuildContentObject(payload)
+	require.NoError(t, err)
+
+	vcDoc, err := vocab.UnmarshalToDoc([]byte(witnessDoc))
+	require.NoError(t, err)
+
+	anchorEvent, err := anchorevent.BuildAnchorEvent(payload, contentObj.GeneratorID, contentObj.Payload, vcDoc)
+	require.NoError(t, err)
+
+	return anchorEvent
+}
+
+func addActivity(t *testing.T, s store.Store, anchorIRI *url.URL,
+	activity *vocab.ActivityType) *vocab.ActivityType {
+	t.Helper()
+
+	require.NoError(t, s.AddActivity(activity))
+	require.NoError(t, s.AddReference(store.Provenance, anchorIRI, activity.ID().URL(),
+		store.WithActivityType(activity.Type().Types()[0])))
+
+	return activity
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+type mockActivityStore struct {
+	err error
+}
+
+func (m *mockActivityStore) QueryActivities(*store.Criteria, ...store.QueryOpt) (store.ActivityIterator, error) {
+	return nil, m.err
+}
@@ -0,0 +1,270 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package quarantine
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+)
+
+const (
+	// Path is the path of the quarantine endpoint.
+	Path = "/quarantine"
+
+	idPathVariable = "id"
+
+	entryPath     = Path + "/{" + idPathVariable + "}"
+	reprocessPath = entryPath + "/reprocess"
+
+	notFoundResponse            = "Not Found."
+	internalServerErrorResponse = "Internal Server Error."
+)
+
+type entryStore interface {
+	Get(id string) (*Entry, error)
+	GetAll() ([]*Entry, error)
+	Delete(id string) error
+}
+
+// List implements a REST handler that returns the quarantined activities, most recent first.
+type List struct {
+	store   entryStore
+	marshal func(v interface{}) ([]byte, error)
+}
+
+// NewList returns a new quarantine list handler.
+func NewList(store entryStore) *List {
+	return &List{
+		store:   store,
+		marshal: json.Marshal,
+	}
+}
+
+// Path returns the HTTP REST endpoint of the handler.
+func (h *List) Path() string {
+	return Path
+}
+
+// Method returns the HTTP method, which is always GET.
+func (h *List) Method() string {
+	return http.MethodGet
+}
+
+// Handler returns the HTTP REST handle.
+func (h *List) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *List) handle(w http.ResponseWriter, _ *http.Request) {
+	entries, err := h.store.GetAll()
+	if err != nil {
+		logger.Errorf("[%s] Error retrieving quarantined activities: %s", Path, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	if entries == nil {
+		entries = []*Entry{}
+	}
+
+	entriesBytes, err := h.marshal(entries)
+	if err != nil {
+		logger.Errorf("[%s] Error marshalling quarantined activities: %s", Path, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	writeResponse(w, http.StatusOK, entriesBytes)
+}
+
+// Delete implements a REST handler that deletes a quarantined activity.
+type Delete struct {
+	store entryStore
+}
+
+// NewDelete returns a new quarantine delete handler.
+func NewDelete(store entryStore) *Delete {
+	return &Delete{store: store}
+}
+
+// Path returns the HTTP REST endpoint of the handler.
+func (h *Delete) Path() string {
+	return entryPath
+}
+
+// Method returns the HTTP method, which is always DELETE.
+func (h *Delete) Method() string {
+	return http.MethodDelete
+}
+
+// Handler returns the HTTP REST handle.
+func (h *Delete) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Delete) handle(w http.ResponseWriter, req *http.Request) {
+	id := mux.Vars(req)[idPathVariable]
+
+	if _, err := h.store.Get(id); err != nil {
+		writeGetError(w, id, err)
+
+		return
+	}
+
+	if err := h.store.Delete(id); err != nil {
+		logger.Errorf("[%s] Error deleting quarantined activity [%s]: %s", Path, id, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	logger.Infof("[%s] Deleted quarantined activity [%s]", Path, id)
+
+	writeResponse(w, http.StatusOK, nil)
+}
+
+// ReprocessResult is the response of a reprocess request.
+type ReprocessResult struct {
+	ID string `json:"id"`
+	// Processed is true if the inbox accepted the request, in which case the entry is deleted. Otherwise the
+	// entry remains in quarantine (with the new failure reason if the request failed verification again).
+	Processed bool `json:"processed"`
+	// Status is the status code that was returned by the inbox.
+	Status int `json:"status"`
+}
+
+// Reprocess implements a REST handler that submits a quarantined request to the inbox again, for example after
+// the actor's key was fixed or the actor was added to the accept list. The request is verified again by the inbox.
+type Reprocess struct {
+	store   entryStore
+	inbox   common.HTTPRequestHandler
+	marshal func(v interface{}) ([]byte, error)
+}
+
+// NewReprocess returns a new quarantine reprocess handler. The given handler is the HTTP handler of the inbox.
+func NewReprocess(store entryStore, inbox common.HTTPRequestHandler) *Reprocess {
+	return &Reprocess{
+		store:   store,
+		inbox:   inbox,
+		marshal: json.Marshal,
+	}
+}
+
+// Path returns the HTTP REST endpoint of the handler.
+func (h *Reprocess) Path() string {
+	return reprocessPath
+}
+
+// Method returns the HTTP method, which is always POST.
+func (h *Reprocess) Method() string {
+	return http.MethodPost
+}
+
+// Handler returns the HTTP REST handle.
+func (h *Reprocess) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Reprocess) handle(w http.ResponseWriter, req *http.Request) {
+	id := mux.Vars(req)[idPathVariable]
+
+	entry, err := h.store.Get(id)
+	if err != nil {
+		writeGetError(w, id, err)
+
+		return
+	}
+
+	inboxReq, err := entry.Request()
+	if err != nil {
+		logger.Errorf("[%s] Error creating request for quarantined activity [%s]: %s", Path, id, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	rw := &statusRecorder{header: make(http.Header), status: http.StatusOK}
+
+	h.inbox(rw, inboxReq.WithContext(req.Context()))
+
+	result := &ReprocessResult{
+		ID:        id,
+		Processed: rw.status >= http.StatusOK && rw.status < http.StatusMultipleChoices,
+		Status:    rw.status,
+	}
+
+	if result.Processed {
+		logger.Infof("[%s] Quarantined activity [%s] was processed by the inbox", Path, id)
+
+		if err := h.store.Delete(id); err != nil {
+			logger.Warnf("[%s] Error deleting quarantined activity [%s]: %s", Path, id, err)
+		}
+	} else {
+		logger.Infof("[%s] Quarantined activity [%s] was rejected by the inbox with status %d", Path, id, rw.status)
+	}
+
+	resultBytes, err := h.marshal(result)
+	if err != nil {
+		logger.Errorf("[%s] Error marshalling reprocess result: %s", Path, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	writeResponse(w, http.StatusOK, resultBytes)
+}
+
+func writeGetError(w http.ResponseWriter, id string, err error) {
+	if errors.Is(err, ErrNotFound) {
+		writeResponse(w, http.StatusNotFound, []byte(notFoundResponse))
+
+		return
+	}
+
+	logger.Errorf("[%s] Error retrieving quarantined activity [%s]: %s", Path, id, err)
+
+	writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+}
+
+func writeResponse(w http.ResponseWriter, status int, body []byte) {
+	w.WriteHeader(status)
+
+	if len(body) > 0 {
+		if _, err := w.Write(body); err != nil {
+			logger.Warnf("[%s] Unable to write response: %s", Path, err)
+		}
+	}
+}
+
+// statusRecorder is a response writer that records the status code returned by the inbox.
+type statusRecorder struct {
+	header http.Header
+	status int
+}
+
+func (r *statusRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+}
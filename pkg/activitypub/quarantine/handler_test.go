@@ -0,0 +1,215 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package quarantine
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/internal/testutil/httptestutil"
+)
+
+func TestList(t *testing.T) {
+	s := newTestStore(t)
+
+	h := NewList(s)
+	require.Equal(t, Path, h.Path())
+	require.Equal(t, http.MethodGet, h.Method())
+	require.NotNil(t, h.Handler())
+
+	t.Run("Empty", func(t *testing.T) {
+		code, body := httptestutil.Get(t, h.handle, Path)
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, "[]", string(body))
+	})
+
+	entry := NewEntry(newInboxRequest(t, []byte(activity1)), []byte(activity1), "invalid HTTP signature")
+	require.NoError(t, s.Put(entry))
+
+	t.Run("Success", func(t *testing.T) {
+		code, body := httptestutil.Get(t, h.handle, Path)
+		require.Equal(t, http.StatusOK, code)
+
+		var entries []*Entry
+		require.NoError(t, json.Unmarshal(body, &entries))
+		require.Len(t, entries, 1)
+		require.Equal(t, entry.ID, entries[0].ID)
+		require.Equal(t, "invalid HTTP signature", entries[0].Reason)
+	})
+
+	t.Run("Store error", func(t *testing.T) {
+		code, _ := httptestutil.Get(t, NewList(&mockStore{err: errors.New("injected store error")}).handle, Path)
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		h := NewList(s)
+		h.marshal = func(v interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		code, _ := httptestutil.Get(t, h.handle, Path)
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+}
+
+func TestDelete(t *testing.T) {
+	s := newTestStore(t)
+
+	h := NewDelete(s)
+	require.Equal(t, Path+"/{id}", h.Path())
+	require.Equal(t, http.MethodDelete, h.Method())
+	require.NotNil(t, h.Handler())
+
+	entry := NewEntry(newInboxRequest(t, []byte(activity1)), []byte(activity1), "invalid HTTP signature")
+	require.NoError(t, s.Put(entry))
+
+	t.Run("Success", func(t *testing.T) {
+		code, _ := httptestutil.Serve(t, h.handle, http.MethodDelete, Path+"/"+entry.ID, nil,
+			map[string]string{"id": entry.ID})
+		require.Equal(t, http.StatusOK, code)
+
+		_, err := s.Get(entry.ID)
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		code, _ := httptestutil.Serve(t, h.handle, http.MethodDelete, Path+"/xxx", nil, map[string]string{"id": "xxx"})
+		require.Equal(t, http.StatusNotFound, code)
+	})
+
+	t.Run("Store error", func(t *testing.T) {
+		code, _ := httptestutil.Serve(t, NewDelete(&mockStore{err: errors.New("injected store error")}).handle,
+			http.MethodDelete, Path+"/id1", nil, map[string]string{"id": "id1"})
+		require.Equal(t, http.StatusInternalServerError, code)
+
+		code, _ = httptestutil.Serve(t,
+			NewDelete(&mockStore{entry: entry, deleteErr: errors.New("injected delete error")}).handle,
+			http.MethodDelete, Path+"/id1", nil, map[string]string{"id": "id1"})
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+}
+
+func TestReprocess(t *testing.T) {
+	s := newTestStore(t)
+
+	var (
+		inboxStatus = http.StatusOK
+		inboxReq    *http.Request
+	)
+
+	inbox := func(w http.ResponseWriter, req *http.Request) {
+		inboxReq = req
+
+		w.WriteHeader(inboxStatus)
+	}
+
+	h := NewReprocess(s, inbox)
+	require.Equal(t, Path+"/{id}/reprocess", h.Path())
+	require.Equal(t, http.MethodPost, h.Method())
+	require.NotNil(t, h.Handler())
+
+	entry := NewEntry(newInboxRequest(t, []byte(activity1)), []byte(activity1), "invalid HTTP signature")
+	require.NoError(t, s.Put(entry))
+
+	vars := map[string]string{"id": entry.ID}
+
+	t.Run("Rejected", func(t *testing.T) {
+		inboxStatus = http.StatusUnauthorized
+
+		code, body := httptestutil.Serve(t, h.handle, http.MethodPost, Path+"/"+entry.ID+"/reprocess", nil, vars)
+		require.Equal(t, http.StatusOK, code)
+
+		result := &ReprocessResult{}
+		require.NoError(t, json.Unmarshal(body, result))
+		require.Equal(t, entry.ID, result.ID)
+		require.False(t, result.Processed)
+		require.Equal(t, http.StatusUnauthorized, result.Status)
+
+		_, err := s.Get(entry.ID)
+		require.NoError(t, err)
+	})
+
+	t.Run("Processed", func(t *testing.T) {
+		inboxStatus = http.StatusOK
+
+		code, body := httptestutil.Serve(t, h.handle, http.MethodPost, Path+"/"+entry.ID+"/reprocess", nil, vars)
+		require.Equal(t, http.StatusOK, code)
+
+		result := &ReprocessResult{}
+		require.NoError(t, json.Unmarshal(body, result))
+		require.True(t, result.Processed)
+		require.Equal(t, http.StatusOK, result.Status)
+
+		require.Equal(t, inboxPath, inboxReq.URL.RequestURI())
+		require.Equal(t, entry.Headers.Get("Signature"), inboxReq.Header.Get("Signature"))
+
+		_, err := s.Get(entry.ID)
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		code, _ := httptestutil.Serve(t, h.handle, http.MethodPost, Path+"/xxx/reprocess", nil,
+			map[string]string{"id": "xxx"})
+		require.Equal(t, http.StatusNotFound, code)
+	})
+
+	t.Run("Invalid entry", func(t *testing.T) {
+		code, _ := httptestutil.Serve(t,
+			NewReprocess(&mockStore{entry: &Entry{ID: "id1", Method: "bad method"}}, inbox).handle, http.MethodPost,
+			Path+"/id1/reprocess", nil, map[string]string{"id": "id1"})
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+
+	t.Run("Delete error", func(t *testing.T) {
+		h := NewReprocess(&mockStore{entry: entry, deleteErr: errors.New("injected error")}, inbox)
+
+		code, _ := httptestutil.Serve(t, h.handle, http.MethodPost, Path+"/id1/reprocess", nil,
+			map[string]string{"id": "id1"})
+		require.Equal(t, http.StatusOK, code)
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		require.NoError(t, s.Put(entry))
+
+		h := NewReprocess(s, inbox)
+		h.marshal = func(v interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		code, _ := httptestutil.Serve(t, h.handle, http.MethodPost, Path+"/"+entry.ID+"/reprocess", nil, vars)
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+}
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+
+	s, err := NewStore(mem.NewProvider(), &mockExpiryService{})
+	require.NoError(t, err)
+
+	return s
+}
+
+type mockStore struct {
+	entry     *Entry
+	err       error
+	deleteErr error
+}
+
+func (m *mockStore) Get(string) (*Entry, error) {
+	return m.entry, m.err
+}
+
+func (m *mockStore) GetAll() ([]*Entry, error) {
+	return nil, m.err
+}
+
+func (m *mockStore) Delete(string) error {
+	return m.deleteErr
+}
@@ -0,0 +1,268 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package quarantine
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/store/expiry"
+)
+
+var logger = log.New("activity-quarantine")
+
+// ErrNotFound is returned when a quarantined activity isn't found.
+var ErrNotFound = errors.New("quarantined activity not found")
+
+const (
+	storeName = "activity-quarantine"
+
+	// quarantineTag is added to every entry so that all entries may be queried.
+	quarantineTag = "quarantined"
+	// expiryTag holds the time (Unix time) after which the entry is deleted.
+	expiryTag = "expiry"
+
+	defaultRetention = 7 * 24 * time.Hour
+)
+
+// Entry is an inbox request that failed verification.
+type Entry struct {
+	// ID is the hash of the request body, so that a request that is retried by the sender replaces
+	// the existing entry.
+	ID       string    `json:"id"`
+	Reason   string    `json:"reason"`
+	Received time.Time `json:"received"`
+
+	// ActivityID, ActivityType and Actor are taken from the request body (if it contains an activity)
+	// in order to facilitate the review of the entry.
+	ActivityID   string `json:"activityId,omitempty"`
+	ActivityType string `json:"activityType,omitempty"`
+	Actor        string `json:"actor,omitempty"`
+
+	Method     string      `json:"method"`
+	RequestURI string      `json:"requestUri"`
+	Host       string      `json:"host"`
+	Headers    http.Header `json:"headers"`
+	Body       []byte      `json:"body"`
+}
+
+// NewEntry returns a new quarantine entry for the given request and body. The Authorization header
+// isn't retained.
+func NewEntry(req *http.Request, body []byte, reason string) *Entry {
+	hash := sha256.Sum256(body)
+
+	headers := req.Header.Clone()
+	headers.Del("Authorization")
+
+	entry := &Entry{
+		ID:         hex.EncodeToString(hash[:]),
+		Reason:     reason,
+		Received:   time.Now().UTC(),
+		Method:     req.Method,
+		RequestURI: req.URL.RequestURI(),
+		Host:       req.Host,
+		Headers:    headers,
+		Body:       body,
+	}
+
+	activity := &vocab.ActivityType{}
+
+	if err := json.Unmarshal(body, activity); err == nil {
+		if activity.ID() != nil {
+			entry.ActivityID = activity.ID().String()
+		}
+
+		if activity.Type() != nil {
+			entry.ActivityType = activity.Type().String()
+		}
+
+		if activity.Actor() != nil {
+			entry.Actor = activity.Actor().String()
+		}
+	}
+
+	return entry
+}
+
+// Request returns a copy of the original request so that the request may be processed again.
+func (e *Entry) Request() (*http.Request, error) {
+	req, err := http.NewRequest(e.Method, e.RequestURI, bytes.NewReader(e.Body)) //nolint:noctx
+	if err != nil {
+		return nil, fmt.Errorf("create request for quarantined activity [%s]: %w", e.ID, err)
+	}
+
+	req.Host = e.Host
+	req.Header = e.Headers.Clone()
+
+	return req, nil
+}
+
+type expiryService interface {
+	Register(store storage.Store, expiryTagName, storeName string, opts ...expiry.Option)
+}
+
+type options struct {
+	retention time.Duration
+}
+
+// Opt sets a quarantine store option.
+type Opt func(opts *options)
+
+// WithRetention sets the amount of time for which quarantined activities are kept.
+func WithRetention(value time.Duration) Opt {
+	return func(opts *options) {
+		opts.retention = value
+	}
+}
+
+// Store stores the inbox requests that failed verification (for example, because of an invalid HTTP signature
+// or an unknown actor) so that they may be reviewed and, once the problem is fixed, processed again. Entries
+// are deleted by the expiry service after the retention period.
+type Store struct {
+	store     storage.Store
+	retention time.Duration
+}
+
+// NewStore returns a new quarantine store.
+func NewStore(provider storage.Provider, expiryService expiryService, opts ...Opt) (*Store, error) {
+	options := &options{
+		retention: defaultRetention,
+	}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	s, err := provider.OpenStore(storeName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open activity quarantine store: %w", err)
+	}
+
+	err = provider.SetStoreConfig(storeName,
+		storage.StoreConfiguration{TagNames: []string{quarantineTag, expiryTag}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set store configuration: %w", err)
+	}
+
+	expiryService.Register(s, expiryTag, storeName)
+
+	return &Store{
+		store:     s,
+		retention: options.retention,
+	}, nil
+}
+
+// Put stores the given entry, replacing an existing entry with the same ID.
+func (s *Store) Put(entry *Entry) error {
+	entryBytes, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("marshal quarantined activity [%s]: %w", entry.ID, err)
+	}
+
+	err = s.store.Put(entry.ID, entryBytes,
+		storage.Tag{Name: quarantineTag},
+		storage.Tag{Name: expiryTag, Value: strconv.FormatInt(entry.Received.Add(s.retention).Unix(), 10)},
+	)
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("store quarantined activity [%s]: %w", entry.ID, err))
+	}
+
+	logger.Infof("Quarantined activity [%s] with ID [%s] from actor [%s]: %s",
+		entry.ActivityID, entry.ID, entry.Actor, entry.Reason)
+
+	return nil
+}
+
+// Get returns the entry with the given ID or ErrNotFound if the entry doesn't exist.
+func (s *Store) Get(id string) (*Entry, error) {
+	entryBytes, err := s.store.Get(id)
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return nil, ErrNotFound
+		}
+
+		return nil, orberrors.NewTransient(fmt.Errorf("get quarantined activity [%s]: %w", id, err))
+	}
+
+	entry := &Entry{}
+
+	err = json.Unmarshal(entryBytes, entry)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal quarantined activity [%s]: %w", id, err)
+	}
+
+	return entry, nil
+}
+
+// GetAll returns all entries, most recent first.
+func (s *Store) GetAll() ([]*Entry, error) {
+	it, err := s.store.Query(quarantineTag)
+	if err != nil {
+		return nil, orberrors.NewTransient(fmt.Errorf("query quarantined activities: %w", err))
+	}
+
+	defer func() {
+		if e := it.Close(); e != nil {
+			logger.Warnf("Error closing iterator: %s", e)
+		}
+	}()
+
+	var entries []*Entry
+
+	for {
+		ok, e := it.Next()
+		if e != nil {
+			return nil, orberrors.NewTransient(fmt.Errorf("next quarantined activity: %w", e))
+		}
+
+		if !ok {
+			break
+		}
+
+		value, e := it.Value()
+		if e != nil {
+			return nil, orberrors.NewTransient(fmt.Errorf("get quarantined activity from iterator: %w", e))
+		}
+
+		entry := &Entry{}
+
+		if e := json.Unmarshal(value, entry); e != nil {
+			return nil, fmt.Errorf("unmarshal quarantined activity: %w", e)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Received.After(entries[j].Received)
+	})
+
+	return entries, nil
+}
+
+// Delete deletes the entry with the given ID.
+func (s *Store) Delete(id string) error {
+	err := s.store.Delete(id)
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("delete quarantined activity [%s]: %w", id, err))
+	}
+
+	return nil
+}
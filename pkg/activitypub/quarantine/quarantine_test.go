@@ -0,0 +1,227 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package quarantine
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/store/expiry"
+	"github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+const (
+	inboxPath = "/services/orb/inbox"
+	activity1 = `{"@context":"https://www.w3.org/ns/activitystreams","id":"https://orb.domain2.com/activities/1",` +
+		`"type":"Follow","actor":"https://orb.domain2.com/services/orb",` +
+		`"object":"https://orb.domain1.com/services/orb"}`
+)
+
+func TestNewEntry(t *testing.T) {
+	t.Run("Activity", func(t *testing.T) {
+		req := newInboxRequest(t, []byte(activity1))
+
+		entry := NewEntry(req, []byte(activity1), "invalid HTTP signature")
+		require.NotEmpty(t, entry.ID)
+		require.Equal(t, "invalid HTTP signature", entry.Reason)
+		require.Equal(t, "https://orb.domain2.com/activities/1", entry.ActivityID)
+		require.Equal(t, "Follow", entry.ActivityType)
+		require.Equal(t, "https://orb.domain2.com/services/orb", entry.Actor)
+		require.Equal(t, http.MethodPost, entry.Method)
+		require.Equal(t, inboxPath, entry.RequestURI)
+		require.Equal(t, "orb.domain1.com", entry.Host)
+		require.NotEmpty(t, entry.Headers.Get("Signature"))
+		require.Empty(t, entry.Headers.Get("Authorization"))
+
+		// The ID is derived from the body.
+		require.Equal(t, entry.ID, NewEntry(req, []byte(activity1), "some other reason").ID)
+
+		replayReq, err := entry.Request()
+		require.NoError(t, err)
+		require.Equal(t, http.MethodPost, replayReq.Method)
+		require.Equal(t, inboxPath, replayReq.URL.RequestURI())
+		require.Equal(t, "orb.domain1.com", replayReq.Host)
+		require.Equal(t, req.Header.Get("Signature"), replayReq.Header.Get("Signature"))
+
+		body, err := ioutil.ReadAll(replayReq.Body)
+		require.NoError(t, err)
+		require.Equal(t, activity1, string(body))
+	})
+
+	t.Run("Not an activity", func(t *testing.T) {
+		entry := NewEntry(newInboxRequest(t, []byte("xxx")), []byte("xxx"), "invalid HTTP signature")
+		require.NotEmpty(t, entry.ID)
+		require.Empty(t, entry.ActivityID)
+		require.Empty(t, entry.Actor)
+	})
+
+	t.Run("Invalid method", func(t *testing.T) {
+		entry := &Entry{ID: "id1", Method: "bad method", RequestURI: inboxPath}
+
+		_, err := entry.Request()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "create request for quarantined activity [id1]")
+	})
+}
+
+func TestStore(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		es := &mockExpiryService{}
+
+		s, err := NewStore(mem.NewProvider(), es, WithRetention(time.Hour))
+		require.NoError(t, err)
+		require.Equal(t, storeName, es.storeName)
+		require.Equal(t, expiryTag, es.expiryTagName)
+
+		entries, err := s.GetAll()
+		require.NoError(t, err)
+		require.Empty(t, entries)
+
+		entry1 := NewEntry(newInboxRequest(t, []byte(activity1)), []byte(activity1), "reason1")
+		entry2 := NewEntry(newInboxRequest(t, []byte("xxx")), []byte("xxx"), "reason2")
+		entry2.Received = entry1.Received.Add(time.Second)
+
+		require.NoError(t, s.Put(entry1))
+		require.NoError(t, s.Put(entry2))
+
+		entries, err = s.GetAll()
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		require.Equal(t, entry2.ID, entries[0].ID)
+		require.Equal(t, entry1.ID, entries[1].ID)
+
+		e, err := s.Get(entry1.ID)
+		require.NoError(t, err)
+		require.Equal(t, entry1.ActivityID, e.ActivityID)
+		require.Equal(t, entry1.Body, e.Body)
+
+		require.NoError(t, s.Delete(entry1.ID))
+
+		_, err = s.Get(entry1.ID)
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Open store error", func(t *testing.T) {
+		p := &mocks.Provider{}
+		p.OpenStoreReturns(nil, errors.New("injected open error"))
+
+		_, err := NewStore(p, &mockExpiryService{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected open error")
+	})
+
+	t.Run("Set store config error", func(t *testing.T) {
+		p := &mocks.Provider{}
+		p.SetStoreConfigReturns(errors.New("injected config error"))
+
+		_, err := NewStore(p, &mockExpiryService{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected config error")
+	})
+
+	t.Run("Store errors", func(t *testing.T) {
+		errExpected := errors.New("injected store error")
+
+		store := &mocks.Store{}
+		store.PutReturns(errExpected)
+		store.GetReturns(nil, errExpected)
+		store.QueryReturns(nil, errExpected)
+		store.DeleteReturns(errExpected)
+
+		p := &mocks.Provider{}
+		p.OpenStoreReturns(store, nil)
+
+		s, err := NewStore(p, &mockExpiryService{})
+		require.NoError(t, err)
+
+		require.ErrorIs(t, s.Put(&Entry{ID: "id1"}), errExpected)
+
+		_, err = s.Get("id1")
+		require.ErrorIs(t, err, errExpected)
+
+		_, err = s.GetAll()
+		require.ErrorIs(t, err, errExpected)
+
+		require.ErrorIs(t, s.Delete("id1"), errExpected)
+	})
+
+	t.Run("Iterator errors", func(t *testing.T) {
+		errExpected := errors.New("injected iterator error")
+
+		it := &mocks.Iterator{}
+		it.NextReturns(false, errExpected)
+
+		store := &mocks.Store{}
+		store.QueryReturns(it, nil)
+
+		p := &mocks.Provider{}
+		p.OpenStoreReturns(store, nil)
+
+		s, err := NewStore(p, &mockExpiryService{})
+		require.NoError(t, err)
+
+		_, err = s.GetAll()
+		require.ErrorIs(t, err, errExpected)
+
+		it.NextReturns(true, nil)
+		it.ValueReturns(nil, errExpected)
+
+		_, err = s.GetAll()
+		require.ErrorIs(t, err, errExpected)
+
+		it.ValueReturns([]byte("xxx"), nil)
+
+		_, err = s.GetAll()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal quarantined activity")
+	})
+
+	t.Run("Unmarshal error", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.GetReturns([]byte("xxx"), nil)
+
+		p := &mocks.Provider{}
+		p.OpenStoreReturns(store, nil)
+
+		s, err := NewStore(p, &mockExpiryService{})
+		require.NoError(t, err)
+
+		_, err = s.Get("id1")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal quarantined activity [id1]")
+	})
+}
+
+func newInboxRequest(t *testing.T, body []byte) *http.Request {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "https://orb.domain1.com"+inboxPath, bytes.NewReader(body))
+	req.Header.Set("Date", "Tue, 07 Jun 2022 20:51:35 GMT")
+	req.Header.Set("Signature", `keyId="https://orb.domain2.com/services/orb/keys/main-key",signature="xxx"`)
+	req.Header.Set("Authorization", "Bearer xxx")
+
+	return req
+}
+
+type mockExpiryService struct {
+	storeName     string
+	expiryTagName string
+}
+
+func (m *mockExpiryService) Register(_ storage.Store, expiryTagName, storeName string, _ ...expiry.Option) {
+	m.storeName = storeName
+	m.expiryTagName = expiryTagName
+}
@@ -0,0 +1,101 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rejection
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+)
+
+const (
+	// Path is the path of the inbox rejections endpoint.
+	Path = "/inbox-rejections"
+
+	// reasonParam is the (optional) query parameter that filters the rejections by reason.
+	reasonParam = "reason"
+
+	internalServerErrorResponse = "Internal Server Error."
+)
+
+// Response contains the rejection counts (by reason) along with the most recent rejections.
+type Response struct {
+	Counts     map[Reason]uint64 `json:"counts"`
+	Rejections []*Rejection      `json:"rejections"`
+}
+
+type rejectionRetriever interface {
+	Counts() map[Reason]uint64
+	Recent() []*Rejection
+}
+
+// Handler implements a REST handler that returns the recent inbox rejections, for example:
+// GET /inbox-rejections?reason=signature.
+type Handler struct {
+	recorder rejectionRetriever
+	marshal  func(v interface{}) ([]byte, error)
+}
+
+// NewHandler returns a new inbox rejections handler.
+func NewHandler(recorder rejectionRetriever) *Handler {
+	return &Handler{
+		recorder: recorder,
+		marshal:  json.Marshal,
+	}
+}
+
+// Path returns the HTTP REST endpoint of the handler.
+func (h *Handler) Path() string {
+	return Path
+}
+
+// Method returns the HTTP method, which is always GET.
+func (h *Handler) Method() string {
+	return http.MethodGet
+}
+
+// Handler returns the HTTP REST handle.
+func (h *Handler) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Handler) handle(w http.ResponseWriter, req *http.Request) {
+	reason := Reason(req.URL.Query().Get(reasonParam))
+
+	resp := &Response{
+		Counts:     h.recorder.Counts(),
+		Rejections: []*Rejection{},
+	}
+
+	for _, r := range h.recorder.Recent() {
+		if reason == "" || r.Reason == reason {
+			resp.Rejections = append(resp.Rejections, r)
+		}
+	}
+
+	respBytes, err := h.marshal(resp)
+	if err != nil {
+		logger.Errorf("[%s] Error marshalling inbox rejections: %s", Path, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	writeResponse(w, http.StatusOK, respBytes)
+}
+
+func writeResponse(w http.ResponseWriter, status int, body []byte) {
+	w.WriteHeader(status)
+
+	if _, err := w.Write(body); err != nil {
+		logger.Warnf("[%s] Unable to write response: %s", Path, err)
+	}
+}
@@ -0,0 +1,64 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rejection
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/internal/testutil/httptestutil"
+)
+
+func TestHandler(t *testing.T) {
+	r := NewRecorder(10, newMockMetrics())
+
+	r.Record(&Rejection{Reason: ReasonSignature, KeyID: "https://orb.domain2.com/keys/k1"})
+	r.Record(&Rejection{Reason: ReasonUnsupportedType, ActivityID: "https://orb.domain2.com/activities/1"})
+
+	h := NewHandler(r)
+	require.Equal(t, Path, h.Path())
+	require.Equal(t, http.MethodGet, h.Method())
+	require.NotNil(t, h.Handler())
+
+	t.Run("Success", func(t *testing.T) {
+		code, body := httptestutil.Get(t, h.handle, Path)
+		require.Equal(t, http.StatusOK, code)
+
+		resp := &Response{}
+		require.NoError(t, json.Unmarshal(body, resp))
+		require.Len(t, resp.Rejections, 2)
+		require.Equal(t, ReasonUnsupportedType, resp.Rejections[0].Reason)
+		require.Equal(t, uint64(1), resp.Counts[ReasonSignature])
+		require.Equal(t, uint64(0), resp.Counts[ReasonValidation])
+	})
+
+	t.Run("Filter by reason", func(t *testing.T) {
+		code, body := httptestutil.Get(t, h.handle, Path+"?reason=signature")
+		require.Equal(t, http.StatusOK, code)
+
+		resp := &Response{}
+		require.NoError(t, json.Unmarshal(body, resp))
+		require.Len(t, resp.Rejections, 1)
+		require.Equal(t, "https://orb.domain2.com/keys/k1", resp.Rejections[0].KeyID)
+
+		code, body = httptestutil.Get(t, h.handle, Path+"?reason=validation")
+		require.Equal(t, http.StatusOK, code)
+		require.Contains(t, string(body), `"rejections":[]`)
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		h := NewHandler(r)
+		h.marshal = func(v interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		code, _ := httptestutil.Get(t, h.handle, Path)
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+}
@@ -0,0 +1,138 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rejection
+
+import (
+	"sync"
+	"time"
+
+	"github.com/trustbloc/edge-core/pkg/log"
+)
+
+var logger = log.New("inbox-rejection")
+
+// Reason is the reason that an activity was rejected by the inbox.
+type Reason string
+
+const (
+	// ReasonSignature indicates that the HTTP signature of the request could not be verified.
+	ReasonSignature Reason = "signature"
+
+	// ReasonNotInAcceptList indicates that the actor of the activity is not in the accept list.
+	ReasonNotInAcceptList Reason = "accept-list"
+
+	// ReasonUnsupportedType indicates that the type of the activity is not supported.
+	ReasonUnsupportedType Reason = "unsupported-type"
+
+	// ReasonValidation indicates that the activity is invalid.
+	ReasonValidation Reason = "validation"
+
+	defaultHistorySize = 100
+)
+
+// Reasons contains all of the rejection reasons.
+var Reasons = []Reason{ //nolint:gochecknoglobals
+	ReasonSignature, ReasonNotInAcceptList, ReasonUnsupportedType, ReasonValidation,
+}
+
+// Rejection holds the details of an activity that was rejected by the inbox.
+type Rejection struct {
+	Time         time.Time `json:"time"`
+	Reason       Reason    `json:"reason"`
+	ActivityID   string    `json:"activityId,omitempty"`
+	ActivityType string    `json:"activityType,omitempty"`
+	Actor        string    `json:"actor,omitempty"`
+	KeyID        string    `json:"keyId,omitempty"`
+	Detail       string    `json:"detail,omitempty"`
+}
+
+type metricsProvider interface {
+	InboxActivityRejected(reason string)
+}
+
+// Recorder counts the activities that are rejected by the inbox (by reason) and keeps the most recent
+// rejections in a ring buffer so that federation misconfigurations may be diagnosed.
+type Recorder struct {
+	metrics metricsProvider
+
+	mutex  sync.RWMutex
+	counts map[Reason]uint64
+	buffer []*Rejection
+	next   int
+	full   bool
+}
+
+// NewRecorder returns a new rejection recorder which keeps the given number of recent rejections. If
+// historySize is zero then a default size is used.
+func NewRecorder(historySize int, metrics metricsProvider) *Recorder {
+	if historySize <= 0 {
+		historySize = defaultHistorySize
+	}
+
+	return &Recorder{
+		metrics: metrics,
+		counts:  make(map[Reason]uint64),
+		buffer:  make([]*Rejection, historySize),
+	}
+}
+
+// Record records the given rejection. The time of the rejection is set if it wasn't provided.
+func (r *Recorder) Record(rejection *Rejection) {
+	if rejection.Time.IsZero() {
+		rejection.Time = time.Now()
+	}
+
+	logger.Debugf("Recording inbox rejection - Reason: %s, Activity: %s, Actor: %s, Detail: %s",
+		rejection.Reason, rejection.ActivityID, rejection.Actor, rejection.Detail)
+
+	r.metrics.InboxActivityRejected(string(rejection.Reason))
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.counts[rejection.Reason]++
+
+	r.buffer[r.next] = rejection
+	r.next = (r.next + 1) % len(r.buffer)
+
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// Recent returns the most recent rejections, newest first.
+func (r *Recorder) Recent() []*Rejection {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	n := r.next
+	if r.full {
+		n = len(r.buffer)
+	}
+
+	rejections := make([]*Rejection, 0, n)
+
+	for i := 1; i <= n; i++ {
+		rejections = append(rejections, r.buffer[(r.next-i+len(r.buffer))%len(r.buffer)])
+	}
+
+	return rejections
+}
+
+// Counts returns the number of rejections for each reason since the recorder was created.
+func (r *Recorder) Counts() map[Reason]uint64 {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	counts := make(map[Reason]uint64, len(Reasons))
+
+	for _, reason := range Reasons {
+		counts[reason] = r.counts[reason]
+	}
+
+	return counts
+}
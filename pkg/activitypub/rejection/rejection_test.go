@@ -0,0 +1,87 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rejection
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		m := newMockMetrics()
+
+		r := NewRecorder(3, m)
+		require.Empty(t, r.Recent())
+
+		r.Record(&Rejection{Reason: ReasonSignature, KeyID: "https://orb.domain2.com/keys/k1"})
+		r.Record(&Rejection{Reason: ReasonValidation, Detail: "no actor specified"})
+
+		recent := r.Recent()
+		require.Len(t, recent, 2)
+		require.Equal(t, ReasonValidation, recent[0].Reason)
+		require.Equal(t, ReasonSignature, recent[1].Reason)
+		require.False(t, recent[0].Time.IsZero())
+
+		for i := 0; i < 3; i++ {
+			r.Record(&Rejection{Reason: ReasonNotInAcceptList, ActivityID: fmt.Sprintf("activity%d", i)})
+		}
+
+		recent = r.Recent()
+		require.Len(t, recent, 3)
+		require.Equal(t, "activity2", recent[0].ActivityID)
+		require.Equal(t, "activity1", recent[1].ActivityID)
+		require.Equal(t, "activity0", recent[2].ActivityID)
+
+		counts := r.Counts()
+		require.Len(t, counts, len(Reasons))
+		require.Equal(t, uint64(1), counts[ReasonSignature])
+		require.Equal(t, uint64(1), counts[ReasonValidation])
+		require.Equal(t, uint64(3), counts[ReasonNotInAcceptList])
+		require.Equal(t, uint64(0), counts[ReasonUnsupportedType])
+
+		require.Equal(t, 3, m.get(ReasonNotInAcceptList))
+		require.Equal(t, 1, m.get(ReasonSignature))
+	})
+
+	t.Run("Default history size", func(t *testing.T) {
+		r := NewRecorder(0, newMockMetrics())
+
+		for i := 0; i < defaultHistorySize+10; i++ {
+			r.Record(&Rejection{Reason: ReasonUnsupportedType})
+		}
+
+		require.Len(t, r.Recent(), defaultHistorySize)
+		require.Equal(t, uint64(defaultHistorySize+10), r.Counts()[ReasonUnsupportedType])
+	})
+}
+
+type mockMetrics struct {
+	mutex  sync.Mutex
+	counts map[string]int
+}
+
+func newMockMetrics() *mockMetrics {
+	return &mockMetrics{counts: make(map[string]int)}
+}
+
+func (m *mockMetrics) InboxActivityRejected(reason string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.counts[reason]++
+}
+
+func (m *mockMetrics) get(reason Reason) int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.counts[string(reason)]
+}
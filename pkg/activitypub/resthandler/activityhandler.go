@@ -166,13 +166,13 @@ func (h *Activities) handleActivitiesPage(rw http.ResponseWriter, req *http.Requ
 	pageNum, ok := h.getPageNum(req)
 	if ok {
 		page, err = h.getPage(objectIRI, id, refType,
-			spi.WithPageSize(h.PageSize),
+			spi.WithPageSize(h.GetPageSize()),
 			spi.WithPageNum(pageNum),
 			spi.WithSortOrder(h.sortOrder),
 		)
 	} else {
 		page, err = h.getPage(objectIRI, id, refType,
-			spi.WithPageSize(h.PageSize),
+			spi.WithPageSize(h.GetPageSize()),
 			spi.WithSortOrder(h.sortOrder),
 		)
 	}
@@ -227,7 +227,7 @@ func (h *Activities) getActivities(objectIRI, id *url.URL,
 		return nil, fmt.Errorf("failed to get total items from reference query: %w", err)
 	}
 
-	lastURL, err := h.getPageURL(id, getLastPageNum(totalItems, h.PageSize, h.sortOrder))
+	lastURL, err := h.getPageURL(id, getLastPageNum(totalItems, h.GetPageSize(), h.sortOrder))
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,143 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/trustbloc/orb/pkg/activitypub/anchordigest"
+	"github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+const (
+	sinceParam = "since"
+	dateParam  = "date"
+
+	defaultDigestDays = 7
+	maxDigestDays     = 90
+)
+
+type digester interface {
+	Digest(refType spi.ReferenceType, serviceIRI *url.URL, since time.Time) (*anchordigest.Digest, error)
+	DayActivities(refType spi.ReferenceType, serviceIRI *url.URL, date time.Time) (*anchordigest.DayActivities, error)
+}
+
+// AnchorDigest implements a REST handler that returns a compact digest of the anchor activities in the service's
+// outbox, i.e. the number of activities and the Merkle root of the activity IDs for each day on or after the day
+// given by the 'since' parameter (defaults to 7 days ago). If the 'date' parameter is specified then the IDs of
+// the anchor activities that were published on that day are returned instead.
+type AnchorDigest struct {
+	*handler
+
+	digester    digester
+	jsonMarshal func(v interface{}) ([]byte, error)
+	now         func() time.Time
+}
+
+// NewAnchorDigest returns a new 'anchordigest' REST handler.
+func NewAnchorDigest(cfg *Config, activityStore spi.Store, verifier signatureVerifier,
+	tm authTokenManager) *AnchorDigest {
+	h := &AnchorDigest{
+		digester:    anchordigest.NewDigester(activityStore),
+		jsonMarshal: json.Marshal,
+		now:         time.Now,
+	}
+
+	h.handler = newHandler(AnchorDigestPath, cfg, activityStore, h.handle, verifier, spi.SortDescending, tm)
+
+	return h
+}
+
+func (h *AnchorDigest) handle(w http.ResponseWriter, req *http.Request) {
+	ok, _, err := h.Authorize(req)
+	if err != nil {
+		logger.Errorf("[%s] Error authorizing request: %s", h.endpoint, err)
+
+		h.writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	// As with the outbox, only the public activities are included if the client isn't authorized.
+	refType := spi.PublicOutbox
+
+	if ok {
+		refType = spi.Outbox
+	}
+
+	var result interface{}
+
+	if date := req.URL.Query().Get(dateParam); date != "" {
+		result, err = h.getDayActivities(refType, date)
+	} else {
+		result, err = h.getDigest(refType, req.URL.Query().Get(sinceParam))
+	}
+
+	if err != nil {
+		if orberrors.IsBadRequest(err) {
+			logger.Debugf("[%s] Invalid request: %s", h.endpoint, err)
+
+			h.writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+			return
+		}
+
+		logger.Errorf("[%s] Error computing anchor digest: %s", h.endpoint, err)
+
+		h.writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	resultBytes, err := h.jsonMarshal(result)
+	if err != nil {
+		logger.Errorf("[%s] Unable to marshal anchor digest: %s", h.endpoint, err)
+
+		h.writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	h.writeResponse(w, http.StatusOK, resultBytes)
+}
+
+func (h *AnchorDigest) getDigest(refType spi.ReferenceType, sinceStr string) (*anchordigest.Digest, error) {
+	now := h.now()
+
+	since := now.AddDate(0, 0, -defaultDigestDays)
+
+	if sinceStr != "" {
+		var err error
+
+		since, err = time.Parse(anchordigest.DateFormat, sinceStr)
+		if err != nil {
+			return nil, orberrors.NewBadRequest(fmt.Errorf("invalid value for parameter [%s]: %w", sinceParam, err))
+		}
+
+		if since.Before(now.AddDate(0, 0, -maxDigestDays)) {
+			return nil, orberrors.NewBadRequest(fmt.Errorf("parameter [%s] must be within %d days",
+				sinceParam, maxDigestDays))
+		}
+	}
+
+	return h.digester.Digest(refType, h.ObjectIRI, since)
+}
+
+func (h *AnchorDigest) getDayActivities(refType spi.ReferenceType,
+	dateStr string) (*anchordigest.DayActivities, error) {
+	date, err := time.Parse(anchordigest.DateFormat, dateStr)
+	if err != nil {
+		return nil, orberrors.NewBadRequest(fmt.Errorf("invalid value for parameter [%s]: %w", dateParam, err))
+	}
+
+	return h.digester.DayActivities(refType, h.ObjectIRI, date)
+}
@@ -0,0 +1,197 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/anchordigest"
+	apmocks "github.com/trustbloc/orb/pkg/activitypub/mocks"
+	"github.com/trustbloc/orb/pkg/activitypub/service/mocks"
+	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
+	"github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+	"github.com/trustbloc/orb/pkg/internal/testutil/httptestutil"
+)
+
+const anchorDigestURL = "https://example1.com/services/orb/anchordigest"
+
+func TestNewAnchorDigest(t *testing.T) {
+	cfg := &Config{
+		BasePath:  basePath,
+		ObjectIRI: serviceIRI,
+	}
+
+	h := NewAnchorDigest(cfg, memstore.New(""), &mocks.SignatureVerifier{}, &apmocks.AuthTokenMgr{})
+	require.NotNil(t, h.Handler())
+	require.Equal(t, http.MethodGet, h.Method())
+	require.Equal(t, basePath+AnchorDigestPath, h.Path())
+}
+
+func TestAnchorDigest_Handler(t *testing.T) {
+	now := time.Now()
+
+	activityStore := memstore.New("")
+
+	privateID := addAnchorActivity(t, activityStore, now.Add(-2*time.Hour), false)
+	publicID := addAnchorActivity(t, activityStore, now.Add(-time.Hour), true)
+
+	cfg := &Config{
+		BasePath:  basePath,
+		ObjectIRI: serviceIRI,
+	}
+
+	t.Run("Authorized -> All anchors", func(t *testing.T) {
+		verifier := &mocks.SignatureVerifier{}
+		verifier.VerifyRequestReturns(true, service2IRI, nil)
+
+		h := NewAnchorDigest(cfg, activityStore, verifier, &apmocks.AuthTokenMgr{})
+
+		digest := &anchordigest.Digest{}
+		require.Equal(t, http.StatusOK, getAnchorDigest(t, h, anchorDigestURL, digest))
+		require.Equal(t, serviceIRI.String(), digest.Service)
+
+		count := 0
+		for _, day := range digest.Days {
+			count += day.Count
+		}
+
+		require.Equal(t, 2, count)
+	})
+
+	t.Run("Unauthorized -> Public anchors", func(t *testing.T) {
+		verifier := &mocks.SignatureVerifier{}
+		verifier.VerifyRequestReturns(false, nil, nil)
+
+		tm := &apmocks.AuthTokenMgr{}
+		tm.RequiredAuthTokensReturns([]string{"admin", "read"}, nil)
+
+		h := NewAnchorDigest(cfg, activityStore, verifier, tm)
+
+		day := now.Add(-time.Hour).UTC().Format(anchordigest.DateFormat)
+
+		activities := &anchordigest.DayActivities{}
+		require.Equal(t, http.StatusOK, getAnchorDigest(t, h, anchorDigestURL+"?date="+day, activities))
+		require.Equal(t, day, activities.Date)
+		require.Equal(t, []string{publicID.String()}, activities.Activities)
+		require.NotContains(t, activities.Activities, privateID.String())
+		require.Equal(t, anchordigest.MerkleRoot([]string{publicID.String()}), activities.Root)
+	})
+
+	t.Run("Since", func(t *testing.T) {
+		verifier := &mocks.SignatureVerifier{}
+		verifier.VerifyRequestReturns(true, service2IRI, nil)
+
+		h := NewAnchorDigest(cfg, activityStore, verifier, &apmocks.AuthTokenMgr{})
+
+		since := now.AddDate(0, 0, -30).UTC().Format(anchordigest.DateFormat)
+
+		digest := &anchordigest.Digest{}
+		require.Equal(t, http.StatusOK, getAnchorDigest(t, h, anchorDigestURL+"?since="+since, digest))
+		require.Equal(t, since, digest.Since)
+	})
+
+	t.Run("Invalid parameters", func(t *testing.T) {
+		verifier := &mocks.SignatureVerifier{}
+		verifier.VerifyRequestReturns(true, service2IRI, nil)
+
+		h := NewAnchorDigest(cfg, activityStore, verifier, &apmocks.AuthTokenMgr{})
+
+		tooOld := now.AddDate(0, 0, -maxDigestDays-1).UTC().Format(anchordigest.DateFormat)
+
+		for _, query := range []string{"?since=xxx", "?since=" + tooOld, "?date=xxx"} {
+			require.Equal(t, http.StatusBadRequest, getAnchorDigest(t, h, anchorDigestURL+query, nil))
+		}
+	})
+
+	t.Run("Verify error", func(t *testing.T) {
+		verifier := &mocks.SignatureVerifier{}
+		verifier.VerifyRequestReturns(false, nil, errors.New("injected verify error"))
+
+		// The HTTP signature is only checked if the endpoint requires an auth token.
+		tm := &apmocks.AuthTokenMgr{}
+		tm.RequiredAuthTokensReturns([]string{"admin", "read"}, nil)
+
+		h := NewAnchorDigest(cfg, activityStore, verifier, tm)
+
+		require.Equal(t, http.StatusInternalServerError, getAnchorDigest(t, h, anchorDigestURL, nil))
+	})
+
+	t.Run("Digester error", func(t *testing.T) {
+		verifier := &mocks.SignatureVerifier{}
+		verifier.VerifyRequestReturns(true, service2IRI, nil)
+
+		h := NewAnchorDigest(cfg, activityStore, verifier, &apmocks.AuthTokenMgr{})
+		h.digester = &mockDigester{err: errors.New("injected digester error")}
+
+		require.Equal(t, http.StatusInternalServerError, getAnchorDigest(t, h, anchorDigestURL, nil))
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		verifier := &mocks.SignatureVerifier{}
+		verifier.VerifyRequestReturns(true, service2IRI, nil)
+
+		h := NewAnchorDigest(cfg, activityStore, verifier, &apmocks.AuthTokenMgr{})
+		h.jsonMarshal = func(v interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		require.Equal(t, http.StatusInternalServerError, getAnchorDigest(t, h, anchorDigestURL, nil))
+	})
+}
+
+func addAnchorActivity(t *testing.T, s spi.Store, published time.Time, public bool) *url.URL {
+	t.Helper()
+
+	activityID := testutil.NewMockID(serviceIRI, "/activities/"+published.Format(time.RFC3339Nano))
+
+	activity := vocab.NewCreateActivity(
+		vocab.NewObjectProperty(vocab.WithIRI(testutil.MustParseURL("https://example.com/object"))),
+		vocab.WithID(activityID),
+		vocab.WithActor(serviceIRI),
+		vocab.WithPublishedTime(&published),
+	)
+
+	require.NoError(t, s.AddActivity(activity))
+	require.NoError(t, s.AddReference(spi.Outbox, serviceIRI, activityID))
+
+	if public {
+		require.NoError(t, s.AddReference(spi.PublicOutbox, serviceIRI, activityID))
+	}
+
+	return activityID
+}
+
+func getAnchorDigest(t *testing.T, h *AnchorDigest, reqURL string, v interface{}) int {
+	t.Helper()
+
+	status, respBytes := httptestutil.Get(t, h.handle, reqURL)
+
+	if status == http.StatusOK && v != nil {
+		require.NoError(t, json.Unmarshal(respBytes, v))
+	}
+
+	return status
+}
+
+type mockDigester struct {
+	err error
+}
+
+func (m *mockDigester) Digest(spi.ReferenceType, *url.URL, time.Time) (*anchordigest.Digest, error) {
+	return nil, m.err
+}
+
+func (m *mockDigester) DayActivities(spi.ReferenceType, *url.URL, time.Time) (*anchordigest.DayActivities, error) {
+	return nil, m.err
+}
@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
 
 	store "github.com/trustbloc/orb/pkg/activitypub/store/spi"
 	"github.com/trustbloc/orb/pkg/httpserver/auth"
@@ -18,6 +19,31 @@ import (
 
 type authorizeActorFunc func(actorIRI *url.URL) (bool, error)
 
+// Visibility specifies who may view a collection.
+type Visibility string
+
+const (
+	// VisibilityPublic indicates that no restrictions (other than the authorization token definitions) apply
+	// when viewing a collection. This is the default.
+	VisibilityPublic Visibility = "public"
+	// VisibilityAuthenticated indicates that a collection may only be viewed if the request is authenticated with
+	// either a valid bearer token or a valid HTTP signature.
+	VisibilityAuthenticated Visibility = "authenticated"
+	// VisibilityPrivate indicates that a collection may only be viewed if the request has a valid bearer token or
+	// if the request is signed (HTTP signature) by a follower or a witness of this service.
+	VisibilityPrivate Visibility = "private"
+)
+
+// ParseVisibility parses the given visibility.
+func ParseVisibility(value string) (Visibility, error) {
+	switch v := Visibility(strings.ToLower(value)); v {
+	case VisibilityPublic, VisibilityAuthenticated, VisibilityPrivate:
+		return v, nil
+	default:
+		return "", fmt.Errorf("unsupported visibility [%s]", value)
+	}
+}
+
 // AuthHandler handles authorization of an HTTP request. Both bearer token and HTTP signature authorization
 // are performed.
 type AuthHandler struct {
@@ -25,9 +51,12 @@ type AuthHandler struct {
 
 	tokenVerifier  *auth.TokenVerifier
 	endpoint       string
+	collection     string
+	method         string
 	verifier       signatureVerifier
 	activityStore  store.Store
 	authorizeActor authorizeActorFunc
+	visibility     Visibility
 	writeResponse  func(w http.ResponseWriter, status int, body []byte)
 }
 
@@ -44,9 +73,12 @@ func NewAuthHandler(cfg *Config, endpoint, method string, s store.Store, verifie
 		Config:         cfg,
 		tokenVerifier:  auth.NewTokenVerifier(tm, ep, method),
 		endpoint:       ep,
+		collection:     endpoint,
+		method:         method,
 		verifier:       verifier,
 		activityStore:  s,
 		authorizeActor: authorizeActor,
+		visibility:     cfg.Visibility[endpoint],
 		writeResponse: func(w http.ResponseWriter, status int, body []byte) {
 			w.WriteHeader(status)
 
@@ -82,6 +114,13 @@ func (h *AuthHandler) Authorize(req *http.Request) (bool, *url.URL, error) {
 
 	logger.Debugf("[%s] Authorization failed using bearer token for request %s.", h.endpoint, req.URL)
 
+	if h.verifyAccessToken(req) {
+		logger.Debugf("[%s] Authorization succeeded using access token for request %s", h.endpoint, req.URL)
+
+		// The bearer of an access token is a partner system rather than an actor.
+		return true, nil, nil
+	}
+
 	if h.verifier == nil {
 		return false, nil, nil
 	}
@@ -110,11 +149,69 @@ func (h *AuthHandler) Authorize(req *http.Request) (bool, *url.URL, error) {
 	return ok, actorIRI, nil
 }
 
+// AuthorizeVisibility ensures that the requester may view the resource according to the visibility that is
+// configured for the endpoint.
+func (h *AuthHandler) AuthorizeVisibility(req *http.Request) (bool, error) {
+	if h.visibility != VisibilityAuthenticated && h.visibility != VisibilityPrivate {
+		return true, nil
+	}
+
+	// Open access (i.e. no token is required for the endpoint) doesn't count as authentication.
+	if h.tokenVerifier.TokenRequired() && h.tokenVerifier.Verify(req) {
+		return true, nil
+	}
+
+	if h.verifyAccessToken(req) {
+		return true, nil
+	}
+
+	if h.verifier == nil {
+		return false, nil
+	}
+
+	ok, actorIRI, err := h.verifier.VerifyRequest(req)
+	if err != nil {
+		return false, fmt.Errorf("verify HTTP signature: %w", err)
+	}
+
+	if !ok {
+		logger.Debugf("[%s] Denying access to anonymous request %s since the visibility is [%s].",
+			h.endpoint, req.URL, h.visibility)
+
+		return false, nil
+	}
+
+	if h.visibility == VisibilityAuthenticated {
+		return true, nil
+	}
+
+	return h.isWitnessOrFollower(actorIRI)
+}
+
+// verifyAccessToken returns true if the request is a GET and the bearer token is an access token that grants
+// read access to the collection of this endpoint.
+func (h *AuthHandler) verifyAccessToken(req *http.Request) bool {
+	if h.AccessTokens == nil || h.method != http.MethodGet {
+		return false
+	}
+
+	hdr := req.Header.Get(authHeader)
+	if !strings.HasPrefix(hdr, tokenPrefix) {
+		return false
+	}
+
+	return h.AccessTokens.Verify(strings.TrimPrefix(hdr, tokenPrefix), h.collection)
+}
+
 func (h *AuthHandler) ensureActorIsWitnessOrFollower(actorIRI *url.URL) (bool, error) {
 	if !h.VerifyActorInSignature {
 		return true, nil
 	}
 
+	return h.isWitnessOrFollower(actorIRI)
+}
+
+func (h *AuthHandler) isWitnessOrFollower(actorIRI *url.URL) (bool, error) {
 	// Ensure that the actor is a follower or a witness, otherwise deny access.
 	isFollower, err := h.hasReference(store.Follower, actorIRI)
 	if err != nil {
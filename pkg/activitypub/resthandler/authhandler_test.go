@@ -19,6 +19,8 @@ import (
 	apmocks "github.com/trustbloc/orb/pkg/activitypub/mocks"
 	"github.com/trustbloc/orb/pkg/activitypub/service/mocks"
 	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
+	"github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
 )
 
 //go:generate counterfeiter -o ../mocks/authtokenmgr.gen.go --fake-name AuthTokenMgr . authTokenManager
@@ -227,3 +229,211 @@ func TestNewAuthHandler(t *testing.T) {
 		require.Nil(t, actorIRI)
 	})
 }
+
+func TestAuthHandler_AuthorizeVisibility(t *testing.T) {
+	const followersURL = "https://example.com/services/orb/followers"
+
+	actorIRI := testutil.MustParseURL("https://example2.com/services/orb")
+	otherActorIRI := testutil.MustParseURL("https://example3.com/services/orb")
+
+	activityStore := memstore.New("")
+	require.NoError(t, activityStore.AddReference(spi.Follower, serviceIRI, actorIRI))
+
+	newAuthHandler := func(visibility Visibility, tokens []string, verifier signatureVerifier) *AuthHandler {
+		tm := &apmocks.AuthTokenMgr{}
+		tm.RequiredAuthTokensReturns(tokens, nil)
+
+		cfg := &Config{
+			BasePath:   basePath,
+			ObjectIRI:  serviceIRI,
+			Visibility: map[string]Visibility{FollowersPath: visibility},
+		}
+
+		return NewAuthHandler(cfg, FollowersPath, http.MethodGet, activityStore, verifier, tm, nil)
+	}
+
+	t.Run("Public", func(t *testing.T) {
+		h := newAuthHandler(VisibilityPublic, []string{"read"}, &mocks.SignatureVerifier{})
+
+		ok, err := h.AuthorizeVisibility(httptest.NewRequest(http.MethodGet, followersURL, nil))
+		require.NoError(t, err)
+		require.True(t, ok)
+	})
+
+	t.Run("Authenticated", func(t *testing.T) {
+		t.Run("Anonymous -> unauthorized", func(t *testing.T) {
+			h := newAuthHandler(VisibilityAuthenticated, nil, &mocks.SignatureVerifier{})
+
+			ok, err := h.AuthorizeVisibility(httptest.NewRequest(http.MethodGet, followersURL, nil))
+			require.NoError(t, err)
+			require.False(t, ok)
+		})
+
+		t.Run("Bearer token -> success", func(t *testing.T) {
+			h := newAuthHandler(VisibilityAuthenticated, []string{"READ_TOKEN"}, nil)
+
+			req := httptest.NewRequest(http.MethodGet, followersURL, nil)
+			req.Header[authHeader] = []string{tokenPrefix + "READ_TOKEN"}
+
+			ok, err := h.AuthorizeVisibility(req)
+			require.NoError(t, err)
+			require.True(t, ok)
+		})
+
+		t.Run("No HTTP signature verifier -> unauthorized", func(t *testing.T) {
+			h := newAuthHandler(VisibilityAuthenticated, nil, nil)
+
+			ok, err := h.AuthorizeVisibility(httptest.NewRequest(http.MethodGet, followersURL, nil))
+			require.NoError(t, err)
+			require.False(t, ok)
+		})
+
+		t.Run("HTTP signature -> success", func(t *testing.T) {
+			verifier := &mocks.SignatureVerifier{}
+			verifier.VerifyRequestReturns(true, otherActorIRI, nil)
+
+			h := newAuthHandler(VisibilityAuthenticated, nil, verifier)
+
+			ok, err := h.AuthorizeVisibility(httptest.NewRequest(http.MethodGet, followersURL, nil))
+			require.NoError(t, err)
+			require.True(t, ok)
+		})
+
+		t.Run("HTTP signature verifier error", func(t *testing.T) {
+			errExpected := errors.New("injected verifier error")
+
+			verifier := &mocks.SignatureVerifier{}
+			verifier.VerifyRequestReturns(false, nil, errExpected)
+
+			h := newAuthHandler(VisibilityAuthenticated, nil, verifier)
+
+			_, err := h.AuthorizeVisibility(httptest.NewRequest(http.MethodGet, followersURL, nil))
+			require.Error(t, err)
+			require.Contains(t, err.Error(), errExpected.Error())
+		})
+	})
+
+	t.Run("Private", func(t *testing.T) {
+		t.Run("Follower -> success", func(t *testing.T) {
+			verifier := &mocks.SignatureVerifier{}
+			verifier.VerifyRequestReturns(true, actorIRI, nil)
+
+			h := newAuthHandler(VisibilityPrivate, nil, verifier)
+
+			ok, err := h.AuthorizeVisibility(httptest.NewRequest(http.MethodGet, followersURL, nil))
+			require.NoError(t, err)
+			require.True(t, ok)
+		})
+
+		t.Run("Not a follower or witness -> unauthorized", func(t *testing.T) {
+			verifier := &mocks.SignatureVerifier{}
+			verifier.VerifyRequestReturns(true, otherActorIRI, nil)
+
+			h := newAuthHandler(VisibilityPrivate, nil, verifier)
+
+			ok, err := h.AuthorizeVisibility(httptest.NewRequest(http.MethodGet, followersURL, nil))
+			require.NoError(t, err)
+			require.False(t, ok)
+		})
+	})
+}
+
+func TestAuthHandler_AccessToken(t *testing.T) {
+	const witnessesURL = "https://example.com/services/orb/witnesses"
+
+	accessTokens := &mockAccessTokenVerifier{tokens: map[string]string{"partner-token": WitnessesPath}}
+
+	newAuthHandler := func(endpoint, method string, visibility Visibility) *AuthHandler {
+		tm := &apmocks.AuthTokenMgr{}
+		tm.RequiredAuthTokensReturns([]string{"READ_TOKEN"}, nil)
+
+		cfg := &Config{
+			BasePath:     basePath,
+			ObjectIRI:    serviceIRI,
+			Visibility:   map[string]Visibility{endpoint: visibility},
+			AccessTokens: accessTokens,
+		}
+
+		return NewAuthHandler(cfg, endpoint, method, memstore.New(""), nil, tm, nil)
+	}
+
+	newRequest := func(method, token string) *http.Request {
+		req := httptest.NewRequest(method, witnessesURL, nil)
+		req.Header[authHeader] = []string{tokenPrefix + token}
+
+		return req
+	}
+
+	t.Run("Authorize", func(t *testing.T) {
+		t.Run("Success", func(t *testing.T) {
+			ok, actorIRI, err := newAuthHandler(WitnessesPath, http.MethodGet, VisibilityPublic).
+				Authorize(newRequest(http.MethodGet, "partner-token"))
+			require.NoError(t, err)
+			require.True(t, ok)
+			require.Nil(t, actorIRI)
+		})
+
+		t.Run("Collection not granted -> unauthorized", func(t *testing.T) {
+			ok, _, err := newAuthHandler(FollowersPath, http.MethodGet, VisibilityPublic).
+				Authorize(newRequest(http.MethodGet, "partner-token"))
+			require.NoError(t, err)
+			require.False(t, ok)
+		})
+
+		t.Run("POST -> unauthorized", func(t *testing.T) {
+			ok, _, err := newAuthHandler(WitnessesPath, http.MethodPost, VisibilityPublic).
+				Authorize(newRequest(http.MethodPost, "partner-token"))
+			require.NoError(t, err)
+			require.False(t, ok)
+		})
+
+		t.Run("Invalid token -> unauthorized", func(t *testing.T) {
+			ok, _, err := newAuthHandler(WitnessesPath, http.MethodGet, VisibilityPublic).
+				Authorize(newRequest(http.MethodGet, "invalid-token"))
+			require.NoError(t, err)
+			require.False(t, ok)
+		})
+	})
+
+	t.Run("AuthorizeVisibility", func(t *testing.T) {
+		t.Run("Success", func(t *testing.T) {
+			ok, err := newAuthHandler(WitnessesPath, http.MethodGet, VisibilityPrivate).
+				AuthorizeVisibility(newRequest(http.MethodGet, "partner-token"))
+			require.NoError(t, err)
+			require.True(t, ok)
+		})
+
+		t.Run("Collection not granted -> unauthorized", func(t *testing.T) {
+			ok, err := newAuthHandler(FollowersPath, http.MethodGet, VisibilityPrivate).
+				AuthorizeVisibility(newRequest(http.MethodGet, "partner-token"))
+			require.NoError(t, err)
+			require.False(t, ok)
+		})
+	})
+}
+
+func TestParseVisibility(t *testing.T) {
+	v, err := ParseVisibility("Public")
+	require.NoError(t, err)
+	require.Equal(t, VisibilityPublic, v)
+
+	v, err = ParseVisibility("authenticated")
+	require.NoError(t, err)
+	require.Equal(t, VisibilityAuthenticated, v)
+
+	v, err = ParseVisibility("private")
+	require.NoError(t, err)
+	require.Equal(t, VisibilityPrivate, v)
+
+	_, err = ParseVisibility("secret")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unsupported visibility [secret]")
+}
+
+type mockAccessTokenVerifier struct {
+	tokens map[string]string
+}
+
+func (m *mockAccessTokenVerifier) Verify(token, collection string) bool {
+	return m.tokens[token] == collection
+}
@@ -0,0 +1,109 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/trustbloc/orb/pkg/activitypub/deliveryreceipt"
+	"github.com/trustbloc/orb/pkg/activitypub/store/spi"
+)
+
+type deliveryReceiptStore interface {
+	Get(activityID *url.URL) ([]*deliveryreceipt.Receipt, error)
+}
+
+// Deliveries implements a REST handler that returns the delivery outcome (delivered, failed or pending) of an
+// activity posted to the outbox for each recipient inbox. The activity is specified by its ID, for example:
+//
+//	GET /services/orb/outbox/f4b7f3ad-6ee1-4a3b-9fa8-0c8dd1aa7c2e/deliveries
+type Deliveries struct {
+	*Activity
+
+	receipts    deliveryReceiptStore
+	jsonMarshal func(v interface{}) ([]byte, error)
+}
+
+// NewDeliveries returns a new outbox deliveries REST handler.
+func NewDeliveries(cfg *Config, activityStore spi.Store, receipts deliveryReceiptStore, verifier signatureVerifier,
+	tm authTokenManager) *Deliveries {
+	h := &Deliveries{
+		Activity:    &Activity{},
+		receipts:    receipts,
+		jsonMarshal: json.Marshal,
+	}
+
+	h.handler = newHandler(DeliveriesPath, cfg, activityStore, h.handle, verifier, spi.SortAscending, tm)
+
+	return h
+}
+
+func (h *Deliveries) handle(w http.ResponseWriter, req *http.Request) {
+	ok, _, err := h.Authorize(req)
+	if err != nil {
+		logger.Errorf("[%s] Error authorizing request: %s", h.endpoint, err)
+
+		h.writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	if !ok {
+		h.writeResponse(w, http.StatusUnauthorized, []byte(unauthorizedResponse))
+
+		return
+	}
+
+	activityIRI, err := h.getActivityIRI(req)
+	if err != nil {
+		logger.Debugf("[%s] Get activity IRI: %s", h.endpoint, err)
+
+		h.writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	_, err = h.activityStore.GetActivity(activityIRI)
+	if err != nil {
+		if errors.Is(err, spi.ErrNotFound) {
+			logger.Debugf("[%s] Activity ID not found [%s]", h.endpoint, activityIRI)
+
+			h.writeResponse(w, http.StatusNotFound, []byte(notFoundResponse))
+
+			return
+		}
+
+		logger.Errorf("[%s] Unable to retrieve activity [%s]: %s", h.endpoint, activityIRI, err)
+
+		h.writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	receipts, err := h.receipts.Get(activityIRI)
+	if err != nil {
+		logger.Errorf("[%s] Unable to retrieve delivery receipts of activity [%s]: %s", h.endpoint, activityIRI, err)
+
+		h.writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	summaryBytes, err := h.jsonMarshal(deliveryreceipt.NewSummary(activityIRI, receipts))
+	if err != nil {
+		logger.Errorf("[%s] Unable to marshal delivery receipts of activity [%s]: %s", h.endpoint, activityIRI, err)
+
+		h.writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	h.writeResponse(w, http.StatusOK, summaryBytes)
+}
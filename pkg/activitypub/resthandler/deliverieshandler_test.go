@@ -0,0 +1,218 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/deliveryreceipt"
+	apmocks "github.com/trustbloc/orb/pkg/activitypub/mocks"
+	"github.com/trustbloc/orb/pkg/activitypub/service/mocks"
+	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+	"github.com/trustbloc/orb/pkg/internal/testutil/httptestutil"
+)
+
+const (
+	deliveriesActivityID = "f4b7f3ad-6ee1-4a3b-9fa8-0c8dd1aa7c2e"
+	deliveriesURL        = "https://example1.com/services/orb/outbox/" + deliveriesActivityID + "/deliveries"
+)
+
+func TestNewDeliveries(t *testing.T) {
+	cfg := &Config{
+		BasePath:  basePath,
+		ObjectIRI: serviceIRI,
+	}
+
+	h := NewDeliveries(cfg, memstore.New(""), &mockDeliveryReceiptStore{}, &mocks.SignatureVerifier{},
+		&apmocks.AuthTokenMgr{})
+	require.NotNil(t, h.Handler())
+	require.Equal(t, http.MethodGet, h.Method())
+	require.Equal(t, basePath+DeliveriesPath, h.Path())
+}
+
+func TestDeliveries_Handler(t *testing.T) {
+	activityStore := memstore.New("")
+
+	activityID := testutil.NewMockID(serviceIRI, "/activities/"+deliveriesActivityID)
+
+	create := vocab.NewCreateActivity(
+		vocab.NewObjectProperty(vocab.WithIRI(testutil.MustParseURL("https://example.com/object"))),
+		vocab.WithID(activityID),
+		vocab.WithActor(serviceIRI),
+	)
+
+	require.NoError(t, activityStore.AddActivity(create))
+
+	receipts := &mockDeliveryReceiptStore{
+		receipts: []*deliveryreceipt.Receipt{
+			{
+				Activity: activityID.String(),
+				Inbox:    "https://example2.com/services/orb/inbox",
+				Status:   deliveryreceipt.StatusDelivered,
+				Attempts: 1,
+			},
+			{
+				Activity: activityID.String(),
+				Inbox:    "https://example3.com/services/orb/inbox",
+				Status:   deliveryreceipt.StatusFailed,
+				Attempts: 2,
+				Error:    "connection refused",
+			},
+			{
+				Activity: activityID.String(),
+				Inbox:    "https://example4.com/services/orb/inbox",
+				Status:   deliveryreceipt.StatusPending,
+			},
+		},
+	}
+
+	cfg := &Config{
+		BasePath:  basePath,
+		ObjectIRI: serviceIRI,
+	}
+
+	verifier := &mocks.SignatureVerifier{}
+	verifier.VerifyRequestReturns(true, service2IRI, nil)
+
+	t.Run("Success", func(t *testing.T) {
+		restore := setIDParam(deliveriesActivityID)
+		defer restore()
+
+		h := NewDeliveries(cfg, activityStore, receipts, verifier, &apmocks.AuthTokenMgr{})
+
+		summary := &deliveryreceipt.Summary{}
+		require.Equal(t, http.StatusOK, getDeliveries(t, h, summary))
+		require.Equal(t, activityID.String(), summary.Activity)
+		require.Equal(t, 3, summary.Total)
+		require.Equal(t, 1, summary.Delivered)
+		require.Equal(t, 1, summary.Failed)
+		require.Equal(t, 1, summary.Pending)
+		require.Len(t, summary.Deliveries, 3)
+		require.Equal(t, "connection refused", summary.Deliveries[1].Error)
+	})
+
+	t.Run("No receipts", func(t *testing.T) {
+		restore := setIDParam(deliveriesActivityID)
+		defer restore()
+
+		h := NewDeliveries(cfg, activityStore, &mockDeliveryReceiptStore{}, verifier, &apmocks.AuthTokenMgr{})
+
+		summary := &deliveryreceipt.Summary{}
+		require.Equal(t, http.StatusOK, getDeliveries(t, h, summary))
+		require.Equal(t, 0, summary.Total)
+		require.Empty(t, summary.Deliveries)
+	})
+
+	t.Run("Activity not found", func(t *testing.T) {
+		restore := setIDParam("unknown")
+		defer restore()
+
+		h := NewDeliveries(cfg, activityStore, receipts, verifier, &apmocks.AuthTokenMgr{})
+
+		require.Equal(t, http.StatusNotFound, getDeliveries(t, h, nil))
+	})
+
+	t.Run("No activity ID", func(t *testing.T) {
+		restore := setIDParam("")
+		defer restore()
+
+		h := NewDeliveries(cfg, activityStore, receipts, verifier, &apmocks.AuthTokenMgr{})
+
+		require.Equal(t, http.StatusBadRequest, getDeliveries(t, h, nil))
+	})
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		restore := setIDParam(deliveriesActivityID)
+		defer restore()
+
+		v := &mocks.SignatureVerifier{}
+		v.VerifyRequestReturns(false, nil, nil)
+
+		tm := &apmocks.AuthTokenMgr{}
+		tm.RequiredAuthTokensReturns([]string{"admin", "read"}, nil)
+
+		h := NewDeliveries(cfg, activityStore, receipts, v, tm)
+
+		require.Equal(t, http.StatusUnauthorized, getDeliveries(t, h, nil))
+	})
+
+	t.Run("Verify error", func(t *testing.T) {
+		restore := setIDParam(deliveriesActivityID)
+		defer restore()
+
+		v := &mocks.SignatureVerifier{}
+		v.VerifyRequestReturns(false, nil, errors.New("injected verify error"))
+
+		tm := &apmocks.AuthTokenMgr{}
+		tm.RequiredAuthTokensReturns([]string{"admin", "read"}, nil)
+
+		h := NewDeliveries(cfg, activityStore, receipts, v, tm)
+
+		require.Equal(t, http.StatusInternalServerError, getDeliveries(t, h, nil))
+	})
+
+	t.Run("Activity store error", func(t *testing.T) {
+		restore := setIDParam(deliveriesActivityID)
+		defer restore()
+
+		s := &mocks.ActivityStore{}
+		s.GetActivityReturns(nil, errors.New("injected store error"))
+
+		h := NewDeliveries(cfg, s, receipts, verifier, &apmocks.AuthTokenMgr{})
+
+		require.Equal(t, http.StatusInternalServerError, getDeliveries(t, h, nil))
+	})
+
+	t.Run("Receipt store error", func(t *testing.T) {
+		restore := setIDParam(deliveriesActivityID)
+		defer restore()
+
+		h := NewDeliveries(cfg, activityStore, &mockDeliveryReceiptStore{err: errors.New("injected receipt error")},
+			verifier, &apmocks.AuthTokenMgr{})
+
+		require.Equal(t, http.StatusInternalServerError, getDeliveries(t, h, nil))
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		restore := setIDParam(deliveriesActivityID)
+		defer restore()
+
+		h := NewDeliveries(cfg, activityStore, receipts, verifier, &apmocks.AuthTokenMgr{})
+		h.jsonMarshal = func(v interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		require.Equal(t, http.StatusInternalServerError, getDeliveries(t, h, nil))
+	})
+}
+
+func getDeliveries(t *testing.T, h *Deliveries, v interface{}) int {
+	t.Helper()
+
+	status, respBytes := httptestutil.Get(t, h.handle, deliveriesURL)
+
+	if status == http.StatusOK && v != nil {
+		require.NoError(t, json.Unmarshal(respBytes, v))
+	}
+
+	return status
+}
+
+type mockDeliveryReceiptStore struct {
+	receipts []*deliveryreceipt.Receipt
+	err      error
+}
+
+func (m *mockDeliveryReceiptStore) Get(*url.URL) ([]*deliveryreceipt.Receipt, error) {
+	return m.receipts, m.err
+}
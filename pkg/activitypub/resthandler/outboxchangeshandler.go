@@ -0,0 +1,210 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/store/storeutil"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+)
+
+const (
+	// The scope of a sync token is included in the token since anonymous clients only see the public outbox.
+	syncScopeAll    = "all"
+	syncScopePublic = "public"
+
+	syncTokenParts = 2
+)
+
+var errInvalidSyncToken = errors.New("invalid sync token")
+
+// OutboxChanges is the response of the outbox changes endpoint.
+type OutboxChanges struct {
+	// Activities contains the activities that were added to the outbox since the given sync token, oldest first.
+	Activities []*vocab.ActivityType `json:"activities"`
+	// SyncToken is the opaque token that must be provided in the next request in order to retrieve subsequent changes.
+	SyncToken string `json:"syncToken"`
+	// More is true if more changes are available, in which case the client should request them immediately.
+	More bool `json:"more"`
+}
+
+// ReadOutboxChanges implements a REST handler that returns the activities that were added to the outbox since the
+// given (opaque) sync token along with a new sync token, so that a replica may mirror the outbox incrementally
+// without walking the pages of the outbox. If no token is provided then the changes are returned from the
+// beginning of the outbox. At most one page of changes is returned per request, so the client should keep
+// requesting changes while "more" is true. For example:
+//
+//	GET /services/orb/outbox/changes?since=YWxsOjEwMA
+//
+// The caller has access to all activities if they are authorized, otherwise only public activities are returned.
+type ReadOutboxChanges struct {
+	*handler
+}
+
+// NewOutboxChanges returns a new outbox changes REST handler.
+func NewOutboxChanges(cfg *Config, activityStore spi.Store, verifier signatureVerifier,
+	tm authTokenManager) *ReadOutboxChanges {
+	h := &ReadOutboxChanges{}
+
+	h.handler = newHandler(OutboxChangesPath, cfg, activityStore, h.handle, verifier, spi.SortAscending, tm)
+
+	// The changes are a view of the outbox, so the visibility and the access tokens of the outbox apply.
+	h.visibility = cfg.Visibility[OutboxPath]
+	h.collection = OutboxPath
+
+	return h
+}
+
+func (h *ReadOutboxChanges) handle(w http.ResponseWriter, req *http.Request) {
+	ok, _, err := h.Authorize(req)
+	if err != nil {
+		logger.Errorf("[%s] Error authorizing request: %s", h.endpoint, err)
+
+		h.writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	refType, scope := spi.PublicOutbox, syncScopePublic
+
+	if ok {
+		refType, scope = spi.Outbox, syncScopeAll
+	}
+
+	offset, err := parseSyncToken(h.getParams(req)[sinceParam], scope)
+	if err != nil {
+		logger.Debugf("[%s] Invalid sync token: %s", h.endpoint, err)
+
+		h.writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	changes, err := h.getChanges(refType, scope, offset)
+	if err != nil {
+		if errors.Is(err, errInvalidSyncToken) {
+			logger.Debugf("[%s] Invalid sync token: %s", h.endpoint, err)
+
+			h.writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+			return
+		}
+
+		logger.Errorf("[%s] Error retrieving outbox changes: %s", h.endpoint, err)
+
+		h.writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	changesBytes, err := h.marshal(changes)
+	if err != nil {
+		logger.Errorf("[%s] Unable to marshal outbox changes: %s", h.endpoint, err)
+
+		h.writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	h.writeResponse(w, http.StatusOK, changesBytes)
+}
+
+func (h *ReadOutboxChanges) getChanges(refType spi.ReferenceType, scope string, offset int) (*OutboxChanges, error) {
+	pageSize := h.GetPageSize()
+
+	// The outbox is append-only and sorted by the time that the activities were added, so the offset of the
+	// next activity identifies the changes. The query starts at the page that contains the offset.
+	it, err := h.activityStore.QueryActivities(
+		spi.NewCriteria(
+			spi.WithReferenceType(refType),
+			spi.WithObjectIRI(h.ObjectIRI),
+		),
+		spi.WithPageSize(pageSize),
+		spi.WithPageNum(offset/pageSize),
+		spi.WithSortOrder(spi.SortAscending),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query outbox: %w", err)
+	}
+
+	defer func() {
+		if e := it.Close(); e != nil {
+			logger.Errorf("failed to close iterator: %s", e)
+		}
+	}()
+
+	totalItems, err := it.TotalItems()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get total items from activity query: %w", err)
+	}
+
+	if offset > totalItems {
+		return nil, fmt.Errorf("%w: offset %d exceeds the size of the outbox (%d)",
+			errInvalidSyncToken, offset, totalItems)
+	}
+
+	// Skip the activities on the page that precede the offset.
+	skip := offset % pageSize
+
+	activities, err := storeutil.ReadActivities(it, skip+pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("read outbox: %w", err)
+	}
+
+	if len(activities) > skip {
+		activities = activities[skip:]
+	} else {
+		activities = []*vocab.ActivityType{}
+	}
+
+	next := offset + len(activities)
+
+	return &OutboxChanges{
+		Activities: activities,
+		SyncToken:  newSyncToken(scope, next),
+		More:       next < totalItems,
+	}, nil
+}
+
+func newSyncToken(scope string, offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(scope + ":" + strconv.Itoa(offset)))
+}
+
+func parseSyncToken(values []string, scope string) (int, error) {
+	if len(values) == 0 || values[0] == "" {
+		return 0, nil
+	}
+
+	tokenBytes, err := base64.RawURLEncoding.DecodeString(values[0])
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", errInvalidSyncToken, err)
+	}
+
+	parts := strings.Split(string(tokenBytes), ":")
+	if len(parts) != syncTokenParts {
+		return 0, errInvalidSyncToken
+	}
+
+	if parts[0] != scope {
+		return 0, fmt.Errorf("%w: the token was issued for scope [%s] but the scope of the request is [%s]",
+			errInvalidSyncToken, parts[0], scope)
+	}
+
+	offset, err := strconv.Atoi(parts[1])
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("%w: invalid offset [%s]", errInvalidSyncToken, parts[1])
+	}
+
+	return offset, nil
+}
@@ -0,0 +1,212 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apmocks "github.com/trustbloc/orb/pkg/activitypub/mocks"
+	"github.com/trustbloc/orb/pkg/activitypub/service/mocks"
+	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
+	"github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/internal/testutil/httptestutil"
+)
+
+const outboxChangesURL = "https://example1.com/services/orb/outbox/changes"
+
+func TestNewOutboxChanges(t *testing.T) {
+	cfg := &Config{
+		BasePath:  basePath,
+		ObjectIRI: serviceIRI,
+		PageSize:  4,
+	}
+
+	h := NewOutboxChanges(cfg, memstore.New(""), &mocks.SignatureVerifier{}, &apmocks.AuthTokenMgr{})
+	require.NotNil(t, h)
+	require.Equal(t, "/services/orb/outbox/changes", h.Path())
+	require.Equal(t, http.MethodGet, h.Method())
+	require.NotNil(t, h.Handler())
+}
+
+func TestOutboxChanges_Handler(t *testing.T) {
+	activityStore := memstore.New("")
+
+	activities := newMockActivities(vocab.TypeCreate, 10, func(i int) string {
+		return fmt.Sprintf("https://example1.com/activities/activity_%d", i)
+	})
+
+	for i, activity := range activities {
+		require.NoError(t, activityStore.AddActivity(activity))
+		require.NoError(t, activityStore.AddReference(spi.Outbox, serviceIRI, activity.ID().URL()))
+
+		if i%2 == 0 {
+			require.NoError(t, activityStore.AddReference(spi.PublicOutbox, serviceIRI, activity.ID().URL()))
+		}
+	}
+
+	cfg := &Config{
+		BasePath:  basePath,
+		ObjectIRI: serviceIRI,
+		PageSize:  4,
+	}
+
+	verifier := &mocks.SignatureVerifier{}
+	verifier.VerifyRequestReturns(true, service2IRI, nil)
+
+	h := NewOutboxChanges(cfg, activityStore, verifier, &apmocks.AuthTokenMgr{})
+
+	t.Run("Authorized -> All items", func(t *testing.T) {
+		changes := &OutboxChanges{}
+
+		require.Equal(t, http.StatusOK, getOutboxChanges(t, h, "", changes))
+		require.Len(t, changes.Activities, 4)
+		require.Equal(t, activities[0].ID().String(), changes.Activities[0].ID().String())
+		require.True(t, changes.More)
+
+		var ids []string
+
+		for _, a := range changes.Activities {
+			ids = append(ids, a.ID().String())
+		}
+
+		for changes.More {
+			since := changes.SyncToken
+
+			changes = &OutboxChanges{}
+
+			require.Equal(t, http.StatusOK, getOutboxChanges(t, h, since, changes))
+
+			for _, a := range changes.Activities {
+				ids = append(ids, a.ID().String())
+			}
+		}
+
+		require.Len(t, ids, len(activities))
+
+		for i, activity := range activities {
+			require.Equal(t, activity.ID().String(), ids[i])
+		}
+
+		// No changes since the last token.
+		since := changes.SyncToken
+
+		changes = &OutboxChanges{}
+
+		require.Equal(t, http.StatusOK, getOutboxChanges(t, h, since, changes))
+		require.Empty(t, changes.Activities)
+		require.False(t, changes.More)
+		require.Equal(t, since, changes.SyncToken)
+	})
+
+	t.Run("Offset within a page", func(t *testing.T) {
+		changes := &OutboxChanges{}
+
+		// The changes up to the end of the page that contains the offset are returned.
+		require.Equal(t, http.StatusOK, getOutboxChanges(t, h, newSyncToken(syncScopeAll, 5), changes))
+		require.Len(t, changes.Activities, 3)
+		require.Equal(t, activities[5].ID().String(), changes.Activities[0].ID().String())
+		require.Equal(t, activities[7].ID().String(), changes.Activities[2].ID().String())
+		require.True(t, changes.More)
+		require.Equal(t, newSyncToken(syncScopeAll, 8), changes.SyncToken)
+	})
+
+	t.Run("Unauthorized -> Public items", func(t *testing.T) {
+		v := &mocks.SignatureVerifier{}
+		v.VerifyRequestReturns(false, nil, nil)
+
+		tm := &apmocks.AuthTokenMgr{}
+		tm.RequiredAuthTokensReturns([]string{"admin", "read"}, nil)
+
+		h := NewOutboxChanges(cfg, activityStore, v, tm)
+
+		changes := &OutboxChanges{}
+
+		require.Equal(t, http.StatusOK, getOutboxChanges(t, h, newSyncToken(syncScopePublic, 3), changes))
+		require.Len(t, changes.Activities, 1)
+		require.Equal(t, activities[6].ID().String(), changes.Activities[0].ID().String())
+		require.True(t, changes.More)
+
+		since := changes.SyncToken
+
+		changes = &OutboxChanges{}
+
+		require.Equal(t, http.StatusOK, getOutboxChanges(t, h, since, changes))
+		require.Len(t, changes.Activities, 1)
+		require.Equal(t, activities[8].ID().String(), changes.Activities[0].ID().String())
+		require.False(t, changes.More)
+		require.Equal(t, newSyncToken(syncScopePublic, 5), changes.SyncToken)
+
+		t.Run("Token of another scope", func(t *testing.T) {
+			require.Equal(t, http.StatusBadRequest, getOutboxChanges(t, h, newSyncToken(syncScopeAll, 3), nil))
+		})
+	})
+
+	t.Run("Invalid sync token", func(t *testing.T) {
+		for _, token := range []string{
+			"!!!",
+			base64.RawURLEncoding.EncodeToString([]byte("all")),
+			base64.RawURLEncoding.EncodeToString([]byte("all:xxx")),
+			newSyncToken(syncScopeAll, -1),
+			newSyncToken(syncScopeAll, 11),
+		} {
+			require.Equal(t, http.StatusBadRequest, getOutboxChanges(t, h, token, nil), token)
+		}
+	})
+
+	t.Run("Authorization error", func(t *testing.T) {
+		v := &mocks.SignatureVerifier{}
+		v.VerifyRequestReturns(false, nil, errors.New("injected auth error"))
+
+		tm := &apmocks.AuthTokenMgr{}
+		tm.RequiredAuthTokensReturns([]string{"admin", "read"}, nil)
+
+		h := NewOutboxChanges(cfg, activityStore, v, tm)
+
+		require.Equal(t, http.StatusInternalServerError, getOutboxChanges(t, h, "", nil))
+	})
+
+	t.Run("Store error", func(t *testing.T) {
+		s := &mocks.ActivityStore{}
+		s.QueryActivitiesReturns(nil, errors.New("injected store error"))
+
+		h := NewOutboxChanges(cfg, s, verifier, &apmocks.AuthTokenMgr{})
+
+		require.Equal(t, http.StatusInternalServerError, getOutboxChanges(t, h, "", nil))
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		h := NewOutboxChanges(cfg, activityStore, verifier, &apmocks.AuthTokenMgr{})
+		h.marshal = func(v interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		require.Equal(t, http.StatusInternalServerError, getOutboxChanges(t, h, "", nil))
+	})
+}
+
+func getOutboxChanges(t *testing.T, h *ReadOutboxChanges, since string, v interface{}) int {
+	t.Helper()
+
+	target := outboxChangesURL
+	if since != "" {
+		target += "?" + sinceParam + "=" + since
+	}
+
+	status, respBytes := httptestutil.Get(t, h.handle, target)
+
+	if status == http.StatusOK && v != nil {
+		require.NoError(t, json.Unmarshal(respBytes, v))
+	}
+
+	return status
+}
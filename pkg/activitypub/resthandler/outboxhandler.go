@@ -13,6 +13,7 @@ import (
 	"net/http"
 	"net/url"
 
+	"github.com/google/uuid"
 	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
 
 	store "github.com/trustbloc/orb/pkg/activitypub/store/spi"
@@ -20,6 +21,8 @@ import (
 	orberrors "github.com/trustbloc/orb/pkg/errors"
 )
 
+const locationHeader = "Location"
+
 type outbox interface {
 	Post(activity *vocab.ActivityType) (*url.URL, error)
 }
@@ -34,7 +37,10 @@ type Outbox struct {
 	marshal  func(v interface{}) ([]byte, error)
 }
 
-// NewPostOutbox returns a new REST handler to post activities to the outbox.
+// NewPostOutbox returns a new REST handler to post activities to the outbox. As per the ActivityPub
+// client-to-server protocol, a bare object (i.e. an object that isn't an activity) may also be posted,
+// in which case the object is assigned an ID (if it doesn't have one) and wrapped in a 'Create' activity
+// that's addressed to the recipients of the object.
 func NewPostOutbox(cfg *Config, ob outbox, s store.Store, verifier signatureVerifier, tm authTokenManager) *Outbox {
 	h := &Outbox{
 		Config:   cfg,
@@ -126,13 +132,26 @@ func (h *Outbox) handlePost(w http.ResponseWriter, req *http.Request) { //nolint
 		return
 	}
 
+	w.Header().Set(locationHeader, activityID.String())
+
 	h.writeResponse(w, http.StatusOK, activityIDBytes)
 }
 
 func (h *Outbox) unmarshalAndValidateActivity(activityBytes []byte) (*vocab.ActivityType, error) {
+	obj := &vocab.ObjectType{}
+
+	err := json.Unmarshal(activityBytes, obj)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal object: %w", err)
+	}
+
+	if !obj.Type().IsActivity() {
+		return h.wrapInCreate(activityBytes)
+	}
+
 	activity := &vocab.ActivityType{}
 
-	err := json.Unmarshal(activityBytes, activity)
+	err = json.Unmarshal(activityBytes, activity)
 	if err != nil {
 		return nil, fmt.Errorf("unmarshal activity: %w", err)
 	}
@@ -149,6 +168,52 @@ func (h *Outbox) unmarshalAndValidateActivity(activityBytes []byte) (*vocab.Acti
 	return activity, nil
 }
 
+// wrapInCreate wraps the given bare object in a 'Create' activity whose actor is the local service.
+func (h *Outbox) wrapInCreate(objBytes []byte) (*vocab.ActivityType, error) {
+	objProp := &vocab.ObjectProperty{}
+
+	err := json.Unmarshal(objBytes, objProp)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal object: %w", err)
+	}
+
+	var obj *vocab.ObjectType
+
+	switch {
+	case objProp.AnchorEvent() != nil:
+		obj = objProp.AnchorEvent().ObjectType
+	case objProp.AnchorObject() != nil:
+		obj = objProp.AnchorObject().ObjectType
+	default:
+		obj = objProp.Object()
+	}
+
+	if obj == nil || obj.Type() == nil {
+		return nil, fmt.Errorf("unsupported object type [%s]", objProp.Type())
+	}
+
+	if obj.ID() == nil {
+		obj.SetID(h.newObjectID())
+	}
+
+	logger.Debugf("[%s] Wrapping object [%s] of type [%s] in a 'Create' activity", h.endpoint, obj.ID(), obj.Type())
+
+	return vocab.NewCreateActivity(objProp,
+		vocab.WithActor(h.ObjectIRI),
+		vocab.WithTo(obj.To()...),
+	), nil
+}
+
+func (h *Outbox) newObjectID() *url.URL {
+	id, err := url.Parse(fmt.Sprintf("%s/objects/%s", h.ObjectIRI, uuid.New()))
+	if err != nil {
+		// Should never happen since the service IRI is a valid URL.
+		panic(err)
+	}
+
+	return id
+}
+
 func (h *Outbox) authorizeActor(actorIRI *url.URL) (bool, error) {
 	if !h.VerifyActorInSignature {
 		return true, nil
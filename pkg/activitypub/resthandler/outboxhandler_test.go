@@ -13,6 +13,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -89,9 +90,96 @@ func TestOutbox_Handler(t *testing.T) {
 
 		require.NoError(t, json.Unmarshal(respBytes, &id))
 		require.Equal(t, activityID.String(), id)
+		require.Equal(t, activityID.String(), result.Header.Get("Location"))
 		require.NoError(t, result.Body.Close())
 	})
 
+	t.Run("Bare object -> Success", func(t *testing.T) {
+		verifier := &mocks.SignatureVerifier{}
+		verifier.VerifyRequestReturns(true, serviceIRI, nil)
+
+		anchorEventURL := testutil.MustParseURL("hl:uEiDzUEQi2qRreCTfvp2AKmTaxuqUUZZNhbxe5RTBH59AWw")
+
+		t.Run("Anchor event", func(t *testing.T) {
+			outb := mocks.NewOutbox().WithActivityID(activityID)
+
+			h := NewPostOutbox(cfg, outb, activityStore, verifier, tm)
+
+			objBytes, err := json.Marshal(vocab.NewAnchorEvent(vocab.WithURL(anchorEventURL)))
+			require.NoError(t, err)
+
+			rw := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, outboxURL, bytes.NewBuffer(objBytes))
+
+			h.handlePost(rw, req)
+
+			result := rw.Result()
+			require.Equal(t, http.StatusOK, result.StatusCode)
+			require.NoError(t, result.Body.Close())
+
+			require.Len(t, outb.Activities(), 1)
+
+			create := outb.Activities()[0]
+			require.True(t, create.Type().Is(vocab.TypeCreate))
+			require.Equal(t, serviceIRI.String(), create.Actor().String())
+
+			anchorEvent := create.Object().AnchorEvent()
+			require.NotNil(t, anchorEvent)
+			require.Equal(t, anchorEventURL.String(), anchorEvent.URL()[0].String())
+			require.NotNil(t, anchorEvent.ID())
+			require.True(t, strings.HasPrefix(anchorEvent.ID().String(), serviceIRI.String()+"/objects/"))
+		})
+
+		t.Run("Object with ID", func(t *testing.T) {
+			outb := mocks.NewOutbox().WithActivityID(activityID)
+
+			h := NewPostOutbox(cfg, outb, activityStore, verifier, tm)
+
+			objectID := testutil.NewMockID(serviceIRI, "/objects/123")
+
+			objBytes, err := json.Marshal(vocab.NewObject(
+				vocab.WithID(objectID),
+				vocab.WithType(vocab.TypeVerifiableCredential),
+				vocab.WithTo(service2IRI),
+			))
+			require.NoError(t, err)
+
+			rw := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, outboxURL, bytes.NewBuffer(objBytes))
+
+			h.handlePost(rw, req)
+
+			result := rw.Result()
+			require.Equal(t, http.StatusOK, result.StatusCode)
+			require.NoError(t, result.Body.Close())
+
+			require.Len(t, outb.Activities(), 1)
+
+			create := outb.Activities()[0]
+			require.True(t, create.Type().Is(vocab.TypeCreate))
+			require.Equal(t, objectID.String(), create.Object().Object().ID().String())
+			require.True(t, create.To().Contains(service2IRI))
+		})
+
+		t.Run("Unsupported object", func(t *testing.T) {
+			h := NewPostOutbox(cfg, ob, activityStore, verifier, tm)
+
+			collBytes, err := json.Marshal(vocab.NewCollection(nil))
+			require.NoError(t, err)
+
+			for _, objBytes := range [][]byte{[]byte(`{"id":"https://example1.com/objects/123"}`), collBytes} {
+				rw := httptest.NewRecorder()
+				req := httptest.NewRequest(http.MethodPost, outboxURL, bytes.NewBuffer(objBytes))
+
+				h.handlePost(rw, req)
+
+				result := rw.Result()
+				require.Equal(t, http.StatusBadRequest, result.StatusCode)
+				require.NoError(t, result.Body.Close())
+			}
+		})
+	})
+
 	t.Run("Actor verification not required -> Success", func(t *testing.T) {
 		verifier := &mocks.SignatureVerifier{}
 		verifier.VerifyRequestReturns(true, serviceIRI, nil)
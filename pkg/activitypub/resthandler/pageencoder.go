@@ -0,0 +1,193 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"bytes"
+	"net/url"
+	"strconv"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+)
+
+// estimatedBytesPerItem is used to size the buffer of a page so that it doesn't need to grow.
+const estimatedBytesPerItem = 128
+
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		return &bytes.Buffer{}
+	},
+}
+
+// referencePage is a page of references (IRIs). The page is marshalled by a specialized encoder which produces the
+// same JSON as vocab.CollectionPageType (or vocab.OrderedCollectionPageType) but without building an intermediate
+// document and without boxing each item in an ObjectProperty. Reference pages are requested frequently (for example,
+// when a service synchronizes with its followers) and may be large.
+type referencePage struct {
+	ordered    bool
+	id         *url.URL
+	prev       *url.URL
+	next       *url.URL
+	totalItems int
+	items      []*url.URL
+}
+
+// marshalReferencePage marshals a reference page using the specialized encoder. Any other object is marshalled
+// using vocab.Marshal.
+func marshalReferencePage(v interface{}) ([]byte, error) {
+	page, ok := v.(*referencePage)
+	if !ok {
+		return vocab.Marshal(v)
+	}
+
+	return page.marshal(), nil
+}
+
+// marshal encodes the page into a pooled buffer and returns a copy of the encoded bytes. The fields are written
+// in alphabetical order, which is the same order that is produced by vocab.Marshal.
+func (p *referencePage) marshal() []byte {
+	buf := bufferPool.Get().(*bytes.Buffer) //nolint:errcheck,forcetypeassert
+	defer bufferPool.Put(buf)
+
+	buf.Reset()
+	buf.Grow((len(p.items) + 4) * estimatedBytesPerItem) //nolint:gomnd
+
+	buf.WriteString(`{"@context":`)
+	writeString(buf, string(vocab.ContextActivityStreams))
+
+	if p.id != nil {
+		buf.WriteString(`,"id":`)
+		writeString(buf, p.id.String())
+	}
+
+	if !p.ordered {
+		p.writeItems(buf, "items")
+	}
+
+	if p.next != nil {
+		buf.WriteString(`,"next":`)
+		writeString(buf, p.next.String())
+	}
+
+	if p.ordered {
+		p.writeItems(buf, "orderedItems")
+	}
+
+	if p.prev != nil {
+		buf.WriteString(`,"prev":`)
+		writeString(buf, p.prev.String())
+	}
+
+	buf.WriteString(`,"totalItems":`)
+
+	var numBuf [20]byte
+
+	buf.Write(strconv.AppendInt(numBuf[:0], int64(p.totalItems), 10)) //nolint:gomnd
+
+	buf.WriteString(`,"type":`)
+
+	if p.ordered {
+		writeString(buf, string(vocab.TypeOrderedCollectionPage))
+	} else {
+		writeString(buf, string(vocab.TypeCollectionPage))
+	}
+
+	buf.WriteByte('}')
+
+	result := make([]byte, buf.Len())
+	copy(result, buf.Bytes())
+
+	return result
+}
+
+func (p *referencePage) writeItems(buf *bytes.Buffer, name string) {
+	if len(p.items) == 0 {
+		return
+	}
+
+	buf.WriteString(`,"`)
+	buf.WriteString(name)
+	buf.WriteString(`":[`)
+
+	for i, item := range p.items {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		writeString(buf, item.String())
+	}
+
+	buf.WriteByte(']')
+}
+
+const hex = "0123456789abcdef"
+
+// writeString writes the given string as a JSON string. Characters are escaped in the same way as
+// json.Encoder with HTML escaping disabled.
+func writeString(buf *bytes.Buffer, s string) {
+	buf.WriteByte('"')
+
+	start := 0
+
+	for i := 0; i < len(s); {
+		if b := s[i]; b < utf8.RuneSelf {
+			if b >= 0x20 && b != '"' && b != '\\' { //nolint:gomnd
+				i++
+
+				continue
+			}
+
+			buf.WriteString(s[start:i])
+
+			switch b {
+			case '"', '\\':
+				buf.WriteByte('\\')
+				buf.WriteByte(b)
+			case '\n':
+				buf.WriteString(`\n`)
+			case '\r':
+				buf.WriteString(`\r`)
+			case '\t':
+				buf.WriteString(`\t`)
+			default:
+				buf.WriteString(`\u00`)
+				buf.WriteByte(hex[b>>4])
+				buf.WriteByte(hex[b&0xF])
+			}
+
+			i++
+			start = i
+
+			continue
+		}
+
+		c, size := utf8.DecodeRuneInString(s[i:])
+
+		switch {
+		case c == utf8.RuneError && size == 1:
+			// Invalid UTF-8 is replaced with the Unicode replacement character.
+			buf.WriteString(s[start:i])
+			buf.WriteRune(utf8.RuneError)
+		case c == '\u2028' || c == '\u2029':
+			buf.WriteString(s[start:i])
+			buf.WriteString(`\u202`)
+			buf.WriteByte(hex[c&0xF])
+		default:
+			i += size
+
+			continue
+		}
+
+		i += size
+		start = i
+	}
+
+	buf.WriteString(s[start:])
+	buf.WriteByte('"')
+}
@@ -0,0 +1,169 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+)
+
+func TestReferencePage_Marshal(t *testing.T) {
+	id := testutil.MustParseURL("https://example.com/services/orb/followers?page=true&page-num=1")
+	prev := testutil.MustParseURL("https://example.com/services/orb/followers?page=true&page-num=0")
+	next := testutil.MustParseURL("https://example.com/services/orb/followers?page=true&page-num=2")
+
+	items := testutil.NewMockURLs(5, func(i int) string {
+		return fmt.Sprintf("https://example%d.com/services/orb", i)
+	})
+
+	t.Run("Collection page", func(t *testing.T) {
+		page := &referencePage{id: id, prev: prev, next: next, totalItems: 12, items: items}
+
+		expected, err := vocab.Marshal(newVocabPage(page))
+		require.NoError(t, err)
+
+		pageBytes, err := marshalReferencePage(page)
+		require.NoError(t, err)
+
+		require.Equal(t, testutil.GetCanonical(t, string(expected)), testutil.GetCanonical(t, string(pageBytes)))
+	})
+
+	t.Run("Ordered collection page", func(t *testing.T) {
+		page := &referencePage{ordered: true, id: id, prev: prev, next: next, totalItems: 12, items: items}
+
+		expected, err := vocab.Marshal(newVocabPage(page))
+		require.NoError(t, err)
+
+		pageBytes, err := marshalReferencePage(page)
+		require.NoError(t, err)
+
+		require.Equal(t, testutil.GetCanonical(t, string(expected)), testutil.GetCanonical(t, string(pageBytes)))
+	})
+
+	t.Run("Empty page", func(t *testing.T) {
+		page := &referencePage{id: id}
+
+		expected, err := vocab.Marshal(newVocabPage(page))
+		require.NoError(t, err)
+
+		pageBytes, err := marshalReferencePage(page)
+		require.NoError(t, err)
+
+		require.Equal(t, testutil.GetCanonical(t, string(expected)), testutil.GetCanonical(t, string(pageBytes)))
+	})
+
+	t.Run("Other object", func(t *testing.T) {
+		coll := vocab.NewCollection(nil, vocab.WithID(id), vocab.WithTotalItems(12))
+
+		expected, err := vocab.Marshal(coll)
+		require.NoError(t, err)
+
+		collBytes, err := marshalReferencePage(coll)
+		require.NoError(t, err)
+		require.Equal(t, expected, collBytes)
+	})
+}
+
+func TestWriteString(t *testing.T) {
+	for _, s := range []string{
+		"https://example.com/services/orb?page=true&page-num=1",
+		`quote" and backslash\`,
+		"control \n\r\t\x00\x1f",
+		"unicode \u00e9 \u2028 \u2029",
+		"<html>",
+		"",
+	} {
+		buf := &bytes.Buffer{}
+		enc := json.NewEncoder(buf)
+		enc.SetEscapeHTML(false)
+
+		require.NoError(t, enc.Encode(s))
+
+		actual := &bytes.Buffer{}
+		writeString(actual, s)
+
+		require.Equal(t, string(bytes.TrimSuffix(buf.Bytes(), []byte("\n"))), actual.String())
+	}
+
+	// The encoding of invalid UTF-8 differs between Go versions of encoding/json, but the decoded value is the same.
+	actual := &bytes.Buffer{}
+	writeString(actual, "invalid \xff utf-8")
+
+	require.Equal(t, "\"invalid \ufffd utf-8\"", actual.String())
+
+	var decoded string
+	require.NoError(t, json.Unmarshal(actual.Bytes(), &decoded))
+	require.Equal(t, "invalid \ufffd utf-8", decoded)
+}
+
+func BenchmarkReferencePage_Marshal(b *testing.B) {
+	page := newBenchmarkPage()
+
+	b.Run("Specialized encoder", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			if _, err := marshalReferencePage(page); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("vocab.Marshal", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			if _, err := vocab.Marshal(newVocabPage(page)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func newBenchmarkPage() *referencePage {
+	return &referencePage{
+		ordered:    true,
+		id:         testutil.MustParseURL("https://example.com/services/orb/liked?page=true&page-num=1"),
+		prev:       testutil.MustParseURL("https://example.com/services/orb/liked?page=true&page-num=0"),
+		next:       testutil.MustParseURL("https://example.com/services/orb/liked?page=true&page-num=2"),
+		totalItems: 1000,
+		items: testutil.NewMockURLs(100, func(i int) string {
+			return fmt.Sprintf("hl:uEiCsFp-ft8tI1DFGbXs78tw-HS561mMPa3Z6GsGAHElrNQ%d:uoQ-BeEJpcGZz", i)
+		}),
+	}
+}
+
+// newVocabPage converts the reference page into the equivalent vocab page (which is how reference pages
+// were previously created).
+func newVocabPage(page *referencePage) interface{} {
+	items := make([]*vocab.ObjectProperty, len(page.items))
+
+	for i, ref := range page.items {
+		items[i] = vocab.NewObjectProperty(vocab.WithIRI(ref))
+	}
+
+	opts := []vocab.Opt{
+		vocab.WithContext(vocab.ContextActivityStreams),
+		vocab.WithID(page.id),
+		vocab.WithPrev(page.prev),
+		vocab.WithNext(page.next),
+		vocab.WithTotalItems(page.totalItems),
+	}
+
+	if page.ordered {
+		return vocab.NewOrderedCollectionPage(items, opts...)
+	}
+
+	return vocab.NewCollectionPage(items, opts...)
+}
@@ -0,0 +1,156 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+
+	"github.com/trustbloc/orb/pkg/activitypub/store/spi"
+)
+
+type followApprover interface {
+	ApproveFollow(followID *url.URL) error
+	RejectFollow(followID *url.URL) error
+}
+
+// PendingFollowsWriter implements a REST handler to approve or reject 'Follow' requests that are pending approval.
+type PendingFollowsWriter struct {
+	endpoint string
+	approver followApprover
+	readAll  func(r io.Reader) ([]byte, error)
+}
+
+// NewPendingFollowsWriter returns a new REST handler to approve or reject pending 'Follow' requests.
+func NewPendingFollowsWriter(cfg *Config, approver followApprover) *PendingFollowsWriter {
+	return &PendingFollowsWriter{
+		approver: approver,
+		endpoint: fmt.Sprintf("%s%s", cfg.BasePath, PendingFollowsPath),
+		readAll:  ioutil.ReadAll,
+	}
+}
+
+// Method returns the HTTP method, which is always POST.
+func (h *PendingFollowsWriter) Method() string {
+	return http.MethodPost
+}
+
+// Path returns the base path of the target URL for this handler.
+func (h *PendingFollowsWriter) Path() string {
+	return h.endpoint
+}
+
+// Handler returns the handler that should be invoked when an HTTP POST is requested to the target endpoint.
+// This handler must be registered with an HTTP server.
+func (h *PendingFollowsWriter) Handler() common.HTTPRequestHandler {
+	return h.handlePost
+}
+
+func (h *PendingFollowsWriter) handlePost(w http.ResponseWriter, req *http.Request) {
+	reqBytes, err := h.readAll(req.Body)
+	if err != nil {
+		logger.Errorf("[%s] Error reading request body: %s", h.endpoint, err)
+
+		writeResponse(h.endpoint, w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	logger.Debugf("[%s] Got request to update pending follows: %s", h.endpoint, reqBytes)
+
+	approvals, rejections, err := unmarshalAndValidatePendingFollowsRequest(reqBytes)
+	if err != nil {
+		logger.Infof("[%s] Error validating request: %s", h.endpoint, err)
+
+		writeResponse(h.endpoint, w, http.StatusBadRequest, []byte(err.Error()))
+
+		return
+	}
+
+	for _, followID := range approvals {
+		if err = h.approver.ApproveFollow(followID); err != nil {
+			h.writeError(w, followID, err)
+
+			return
+		}
+	}
+
+	for _, followID := range rejections {
+		if err = h.approver.RejectFollow(followID); err != nil {
+			h.writeError(w, followID, err)
+
+			return
+		}
+	}
+
+	writeResponse(h.endpoint, w, http.StatusOK, nil)
+}
+
+func (h *PendingFollowsWriter) writeError(w http.ResponseWriter, followID *url.URL, err error) {
+	if errors.Is(err, spi.ErrNotFound) {
+		logger.Infof("[%s] Pending follow not found [%s]: %s", h.endpoint, followID, err)
+
+		writeResponse(h.endpoint, w, http.StatusNotFound, []byte(notFoundResponse))
+
+		return
+	}
+
+	logger.Errorf("[%s] Error updating pending follow [%s]: %s", h.endpoint, followID, err)
+
+	writeResponse(h.endpoint, w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+}
+
+type pendingFollowsRequest struct {
+	Approve []string `json:"approve"`
+	Reject  []string `json:"reject"`
+}
+
+func unmarshalAndValidatePendingFollowsRequest(reqBytes []byte) ([]*url.URL, []*url.URL, error) {
+	r := &pendingFollowsRequest{}
+
+	if err := json.Unmarshal(reqBytes, r); err != nil {
+		return nil, nil, fmt.Errorf("invalid pending follows request: %w", err)
+	}
+
+	if len(r.Approve) == 0 && len(r.Reject) == 0 {
+		return nil, nil, fmt.Errorf("invalid pending follows request: no approvals or rejections specified")
+	}
+
+	approvals, err := parseFollowIDs(r.Approve)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid pending follows request: %w", err)
+	}
+
+	rejections, err := parseFollowIDs(r.Reject)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid pending follows request: %w", err)
+	}
+
+	return approvals, rejections, nil
+}
+
+func parseFollowIDs(rawIDs []string) ([]*url.URL, error) {
+	ids := make([]*url.URL, len(rawIDs))
+
+	for i, rawID := range rawIDs {
+		id, err := url.Parse(rawID)
+		if err != nil || !id.IsAbs() {
+			return nil, fmt.Errorf("invalid 'Follow' activity ID: %s", rawID)
+		}
+
+		ids[i] = id
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,120 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	"github.com/trustbloc/orb/pkg/internal/testutil/httptestutil"
+)
+
+const (
+	pendingFollowsURL = "https://example.com/services/orb/pendingfollows"
+	followID1         = "https://domain1.com/services/orb/activities/1"
+	followID2         = "https://domain2.com/services/orb/activities/2"
+)
+
+func TestNewPendingFollowsWriter(t *testing.T) {
+	cfg := &Config{
+		BasePath: "/services/orb",
+	}
+
+	h := NewPendingFollowsWriter(cfg, &mockFollowApprover{})
+	require.NotNil(t, h.Handler())
+	require.Equal(t, http.MethodPost, h.Method())
+	require.Equal(t, "/services/orb/pendingfollows", h.Path())
+}
+
+func TestPendingFollowsWriter_Handler(t *testing.T) {
+	cfg := &Config{
+		BasePath: "/services/orb",
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		approver := &mockFollowApprover{}
+
+		h := NewPendingFollowsWriter(cfg, approver)
+
+		status, _ := httptestutil.Post(t, h.handlePost, pendingFollowsURL,
+			[]byte(fmt.Sprintf(`{"approve":["%s"],"reject":["%s"]}`, followID1, followID2)))
+		require.Equal(t, http.StatusOK, status)
+
+		require.Len(t, approver.approved, 1)
+		require.Equal(t, followID1, approver.approved[0].String())
+		require.Len(t, approver.rejected, 1)
+		require.Equal(t, followID2, approver.rejected[0].String())
+	})
+
+	t.Run("Invalid request", func(t *testing.T) {
+		h := NewPendingFollowsWriter(cfg, &mockFollowApprover{})
+
+		for _, body := range []string{`{`, `{}`, `{"approve":[":invalid"]}`, `{"reject":["relative"]}`} {
+			status, _ := httptestutil.Post(t, h.handlePost, pendingFollowsURL, []byte(body))
+			require.Equal(t, http.StatusBadRequest, status)
+		}
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		h := NewPendingFollowsWriter(cfg, &mockFollowApprover{err: fmt.Errorf("not pending: %w", spi.ErrNotFound)})
+
+		status, _ := httptestutil.Post(t, h.handlePost, pendingFollowsURL,
+			[]byte(fmt.Sprintf(`{"approve":["%s"]}`, followID1)))
+		require.Equal(t, http.StatusNotFound, status)
+	})
+
+	t.Run("Approver error", func(t *testing.T) {
+		h := NewPendingFollowsWriter(cfg, &mockFollowApprover{err: errors.New("injected approver error")})
+
+		status, _ := httptestutil.Post(t, h.handlePost, pendingFollowsURL,
+			[]byte(fmt.Sprintf(`{"reject":["%s"]}`, followID2)))
+		require.Equal(t, http.StatusInternalServerError, status)
+	})
+
+	t.Run("Read body error", func(t *testing.T) {
+		h := NewPendingFollowsWriter(cfg, &mockFollowApprover{})
+		h.readAll = func(r io.Reader) ([]byte, error) {
+			return nil, errors.New("injected read error")
+		}
+
+		status, _ := httptestutil.Post(t, h.handlePost, pendingFollowsURL, nil)
+		require.Equal(t, http.StatusInternalServerError, status)
+	})
+}
+
+type mockFollowApprover struct {
+	approved []*url.URL
+	rejected []*url.URL
+	err      error
+}
+
+func (m *mockFollowApprover) ApproveFollow(followID *url.URL) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	m.approved = append(m.approved, followID)
+
+	return nil
+}
+
+func (m *mockFollowApprover) RejectFollow(followID *url.URL) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	m.rejected = append(m.rejected, followID)
+
+	return nil
+}
@@ -0,0 +1,106 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/trustbloc/orb/pkg/activitypub/provenance"
+	"github.com/trustbloc/orb/pkg/activitypub/store/spi"
+)
+
+type provenanceBuilder interface {
+	Build(anchor string) (*provenance.Chain, error)
+}
+
+// Provenance implements a REST handler that returns the provenance chain of an anchor, i.e. the activities that
+// led to the local acceptance of the anchor (the original 'Create' activity or the 'Announce' activity through
+// which the anchor was received, and the 'Like' activity with which it was acknowledged) along with the witnesses
+// of the anchor. The anchor is specified by its hashlink or resource hash, for example:
+//
+//	GET /services/orb/anchorevents/uEiDaapVGORqCmdWe5kkAbDzPpUFBuq_RgAYwhCXH5ehXbw/provenance
+type Provenance struct {
+	*handler
+
+	builder     provenanceBuilder
+	jsonMarshal func(v interface{}) ([]byte, error)
+}
+
+// NewProvenance returns a new anchor provenance REST handler.
+func NewProvenance(cfg *Config, activityStore spi.Store, verifier signatureVerifier,
+	tm authTokenManager) *Provenance {
+	h := &Provenance{
+		builder:     provenance.NewBuilder(activityStore),
+		jsonMarshal: json.Marshal,
+	}
+
+	h.handler = newHandler(ProvenancePath, cfg, activityStore, h.handle, verifier, spi.SortAscending, tm)
+
+	return h
+}
+
+func (h *Provenance) handle(w http.ResponseWriter, req *http.Request) {
+	ok, _, err := h.Authorize(req)
+	if err != nil {
+		logger.Errorf("[%s] Error authorizing request: %s", h.endpoint, err)
+
+		h.writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	if !ok {
+		h.writeResponse(w, http.StatusUnauthorized, []byte(unauthorizedResponse))
+
+		return
+	}
+
+	anchor := getIDParam(req)
+	if anchor == "" {
+		h.writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	if _, err = provenance.AnchorIRI(anchor); err != nil {
+		logger.Debugf("[%s] Invalid anchor [%s]: %s", h.endpoint, anchor, err)
+
+		h.writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	chain, err := h.builder.Build(anchor)
+	if err != nil {
+		if errors.Is(err, provenance.ErrNotFound) {
+			logger.Debugf("[%s] Provenance not found for anchor [%s]", h.endpoint, anchor)
+
+			h.writeResponse(w, http.StatusNotFound, []byte(notFoundResponse))
+
+			return
+		}
+
+		logger.Errorf("[%s] Error building provenance of anchor [%s]: %s", h.endpoint, anchor, err)
+
+		h.writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	chainBytes, err := h.jsonMarshal(chain)
+	if err != nil {
+		logger.Errorf("[%s] Unable to marshal provenance of anchor [%s]: %s", h.endpoint, anchor, err)
+
+		h.writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	h.writeResponse(w, http.StatusOK, chainBytes)
+}
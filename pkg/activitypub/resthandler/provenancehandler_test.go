@@ -0,0 +1,174 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apmocks "github.com/trustbloc/orb/pkg/activitypub/mocks"
+	"github.com/trustbloc/orb/pkg/activitypub/provenance"
+	"github.com/trustbloc/orb/pkg/activitypub/service/mocks"
+	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
+	"github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+	"github.com/trustbloc/orb/pkg/internal/testutil/httptestutil"
+)
+
+const (
+	provenanceAnchor = "uEiDaapVGORqCmdWe5kkAbDzPpUFBuq_RgAYwhCXH5ehXbw"
+	provenanceURL    = "https://example1.com/services/orb/anchorevents/" + provenanceAnchor + "/provenance"
+)
+
+func TestNewProvenance(t *testing.T) {
+	cfg := &Config{
+		BasePath:  basePath,
+		ObjectIRI: serviceIRI,
+	}
+
+	h := NewProvenance(cfg, memstore.New(""), &mocks.SignatureVerifier{}, &apmocks.AuthTokenMgr{})
+	require.NotNil(t, h.Handler())
+	require.Equal(t, http.MethodGet, h.Method())
+	require.Equal(t, basePath+ProvenancePath, h.Path())
+}
+
+func TestProvenance_Handler(t *testing.T) {
+	activityStore := memstore.New("")
+
+	anchorIRI, err := provenance.AnchorIRI(provenanceAnchor)
+	require.NoError(t, err)
+
+	activityID := testutil.NewMockID(service2IRI, "/activities/create")
+
+	create := vocab.NewCreateActivity(
+		vocab.NewObjectProperty(vocab.WithIRI(testutil.MustParseURL("https://example.com/object"))),
+		vocab.WithID(activityID),
+		vocab.WithActor(service2IRI),
+	)
+
+	require.NoError(t, activityStore.AddActivity(create))
+	require.NoError(t, activityStore.AddReference(spi.Provenance, anchorIRI, activityID,
+		spi.WithActivityType(vocab.TypeCreate)))
+
+	cfg := &Config{
+		BasePath:  basePath,
+		ObjectIRI: serviceIRI,
+	}
+
+	verifier := &mocks.SignatureVerifier{}
+	verifier.VerifyRequestReturns(true, service2IRI, nil)
+
+	t.Run("Success", func(t *testing.T) {
+		restore := setIDParam(provenanceAnchor)
+		defer restore()
+
+		h := NewProvenance(cfg, activityStore, verifier, &apmocks.AuthTokenMgr{})
+
+		chain := &provenance.Chain{}
+		require.Equal(t, http.StatusOK, getProvenance(t, h, chain))
+		require.Equal(t, anchorIRI.String(), chain.Anchor)
+		require.Len(t, chain.Activities, 1)
+		require.Equal(t, activityID.String(), chain.Activities[0].ID)
+		require.Equal(t, service2IRI.String(), chain.Activities[0].Actor)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		restore := setIDParam("uEiBTDSWBv0PsUzqKXjm4yMDuavCZGHq4sg2jNlWKN5yWSA")
+		defer restore()
+
+		h := NewProvenance(cfg, activityStore, verifier, &apmocks.AuthTokenMgr{})
+
+		require.Equal(t, http.StatusNotFound, getProvenance(t, h, nil))
+	})
+
+	t.Run("Invalid anchor", func(t *testing.T) {
+		h := NewProvenance(cfg, activityStore, verifier, &apmocks.AuthTokenMgr{})
+
+		restore := setIDParam("")
+		require.Equal(t, http.StatusBadRequest, getProvenance(t, h, nil))
+		restore()
+
+		restore = setIDParam("hl:")
+		require.Equal(t, http.StatusBadRequest, getProvenance(t, h, nil))
+		restore()
+	})
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		restore := setIDParam(provenanceAnchor)
+		defer restore()
+
+		v := &mocks.SignatureVerifier{}
+		v.VerifyRequestReturns(false, nil, nil)
+
+		tm := &apmocks.AuthTokenMgr{}
+		tm.RequiredAuthTokensReturns([]string{"admin", "read"}, nil)
+
+		h := NewProvenance(cfg, activityStore, v, tm)
+
+		require.Equal(t, http.StatusUnauthorized, getProvenance(t, h, nil))
+	})
+
+	t.Run("Verify error", func(t *testing.T) {
+		restore := setIDParam(provenanceAnchor)
+		defer restore()
+
+		v := &mocks.SignatureVerifier{}
+		v.VerifyRequestReturns(false, nil, errors.New("injected verify error"))
+
+		tm := &apmocks.AuthTokenMgr{}
+		tm.RequiredAuthTokensReturns([]string{"admin", "read"}, nil)
+
+		h := NewProvenance(cfg, activityStore, v, tm)
+
+		require.Equal(t, http.StatusInternalServerError, getProvenance(t, h, nil))
+	})
+
+	t.Run("Builder error", func(t *testing.T) {
+		restore := setIDParam(provenanceAnchor)
+		defer restore()
+
+		h := NewProvenance(cfg, activityStore, verifier, &apmocks.AuthTokenMgr{})
+		h.builder = &mockProvenanceBuilder{err: errors.New("injected builder error")}
+
+		require.Equal(t, http.StatusInternalServerError, getProvenance(t, h, nil))
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		restore := setIDParam(provenanceAnchor)
+		defer restore()
+
+		h := NewProvenance(cfg, activityStore, verifier, &apmocks.AuthTokenMgr{})
+		h.jsonMarshal = func(v interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		require.Equal(t, http.StatusInternalServerError, getProvenance(t, h, nil))
+	})
+}
+
+func getProvenance(t *testing.T, h *Provenance, v interface{}) int {
+	t.Helper()
+
+	status, respBytes := httptestutil.Get(t, h.handle, provenanceURL)
+
+	if status == http.StatusOK && v != nil {
+		require.NoError(t, json.Unmarshal(respBytes, v))
+	}
+
+	return status
+}
+
+type mockProvenanceBuilder struct {
+	err error
+}
+
+func (m *mockProvenanceBuilder) Build(string) (*provenance.Chain, error) {
+	return nil, m.err
+}
@@ -48,6 +48,14 @@ func NewLiked(cfg *Config, activityStore spi.Store, verifier signatureVerifier,
 		getObjectIRI(cfg.ObjectIRI), getID("liked"), verifier, tm)
 }
 
+// NewPendingFollows returns a new 'pendingfollows' REST handler that retrieves the references of the 'Follow'
+// activities that are pending approval.
+func NewPendingFollows(cfg *Config, activityStore spi.Store, verifier signatureVerifier,
+	tm authTokenManager) *Reference {
+	return NewReference(PendingFollowsPath, spi.PendingFollow, spi.SortAscending, false, cfg, activityStore,
+		getObjectIRI(cfg.ObjectIRI), getID("pendingfollows"), verifier, tm)
+}
+
 type createCollectionFunc func(items []*vocab.ObjectProperty, opts ...vocab.Opt) interface{}
 
 type signatureVerifier interface {
@@ -58,11 +66,11 @@ type signatureVerifier interface {
 type Reference struct {
 	*handler
 
-	refType              spi.ReferenceType
-	createCollection     createCollectionFunc
-	createCollectionPage createCollectionFunc
-	getID                getIDFunc
-	getObjectIRI         getObjectIRIFunc
+	refType          spi.ReferenceType
+	ordered          bool
+	createCollection createCollectionFunc
+	getID            getIDFunc
+	getObjectIRI     getObjectIRIFunc
 }
 
 // NewReference returns a new reference REST handler.
@@ -70,15 +78,18 @@ func NewReference(path string, refType spi.ReferenceType, sortOrder spi.SortOrde
 	cfg *Config, activityStore spi.Store, getObjectIRI getObjectIRIFunc, getID getIDFunc,
 	verifier signatureVerifier, tm authTokenManager) *Reference {
 	h := &Reference{
-		refType:              refType,
-		createCollection:     createCollection(ordered),
-		createCollectionPage: createCollectionPage(ordered),
-		getID:                getID,
-		getObjectIRI:         getObjectIRI,
+		refType:          refType,
+		ordered:          ordered,
+		createCollection: createCollection(ordered),
+		getID:            getID,
+		getObjectIRI:     getObjectIRI,
 	}
 
 	h.handler = newHandler(path, cfg, activityStore, h.handle, verifier, sortOrder, tm)
 
+	// Reference pages are marshalled using a specialized encoder in order to reduce allocations.
+	h.marshal = marshalReferencePage
+
 	return h
 }
 
@@ -148,17 +159,17 @@ func (h *Reference) handleReference(w http.ResponseWriter, objectIRI, id *url.UR
 }
 
 func (h *Reference) handleReferencePage(w http.ResponseWriter, req *http.Request, objectIRI, id *url.URL) {
-	var page interface{}
+	var page *referencePage
 
 	var err error
 
 	pageNum, ok := h.getPageNum(req)
 	if ok {
 		page, err = h.getPage(objectIRI, id,
-			spi.WithPageSize(h.PageSize), spi.WithPageNum(pageNum), spi.WithSortOrder(h.sortOrder))
+			spi.WithPageSize(h.GetPageSize()), spi.WithPageNum(pageNum), spi.WithSortOrder(h.sortOrder))
 	} else {
 		page, err = h.getPage(objectIRI, id,
-			spi.WithPageSize(h.PageSize), spi.WithSortOrder(h.sortOrder))
+			spi.WithPageSize(h.GetPageSize()), spi.WithSortOrder(h.sortOrder))
 	}
 
 	if err != nil {
@@ -210,7 +221,7 @@ func (h *Reference) getReference(objectIRI, id *url.URL) (interface{}, error) {
 		return nil, fmt.Errorf("failed to get total items from reference query: %w", err)
 	}
 
-	lastURL, err := h.getPageURL(id, getLastPageNum(totalItems, h.PageSize, h.sortOrder))
+	lastURL, err := h.getPageURL(id, getLastPageNum(totalItems, h.GetPageSize(), h.sortOrder))
 	if err != nil {
 		return nil, err
 	}
@@ -224,7 +235,7 @@ func (h *Reference) getReference(objectIRI, id *url.URL) (interface{}, error) {
 	), nil
 }
 
-func (h *Reference) getPage(objectIRI, id *url.URL, opts ...spi.QueryOpt) (interface{}, error) {
+func (h *Reference) getPage(objectIRI, id *url.URL, opts ...spi.QueryOpt) (*referencePage, error) {
 	it, err := h.activityStore.QueryReferences(
 		h.refType,
 		spi.NewCriteria(spi.WithObjectIRI(objectIRI)),
@@ -248,12 +259,6 @@ func (h *Reference) getPage(objectIRI, id *url.URL, opts ...spi.QueryOpt) (inter
 		return nil, err
 	}
 
-	items := make([]*vocab.ObjectProperty, len(refs))
-
-	for i, ref := range refs {
-		items[i] = vocab.NewObjectProperty(vocab.WithIRI(ref))
-	}
-
 	totalItems, err := it.TotalItems()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get total items from reference query: %w", err)
@@ -264,13 +269,14 @@ func (h *Reference) getPage(objectIRI, id *url.URL, opts ...spi.QueryOpt) (inter
 		return nil, err
 	}
 
-	return h.createCollectionPage(items,
-		vocab.WithContext(vocab.ContextActivityStreams),
-		vocab.WithID(id),
-		vocab.WithPrev(prev),
-		vocab.WithNext(next),
-		vocab.WithTotalItems(totalItems),
-	), nil
+	return &referencePage{
+		ordered:    h.ordered,
+		id:         id,
+		prev:       prev,
+		next:       next,
+		totalItems: totalItems,
+		items:      refs,
+	}, nil
 }
 
 func createCollection(ordered bool) createCollectionFunc {
@@ -284,15 +290,3 @@ func createCollection(ordered bool) createCollectionFunc {
 		return vocab.NewCollection(items, opts...)
 	}
 }
-
-func createCollectionPage(ordered bool) createCollectionFunc {
-	if ordered {
-		return func(items []*vocab.ObjectProperty, opts ...vocab.Opt) interface{} {
-			return vocab.NewOrderedCollectionPage(items, opts...)
-		}
-	}
-
-	return func(items []*vocab.ObjectProperty, opts ...vocab.Opt) interface{} {
-		return vocab.NewCollectionPage(items, opts...)
-	}
-}
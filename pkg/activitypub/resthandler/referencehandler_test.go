@@ -52,6 +52,27 @@ func TestNewFollowers(t *testing.T) {
 	require.Equal(t, "https://example1.com/services/orb/followers", id.String())
 }
 
+func TestNewPendingFollows(t *testing.T) {
+	cfg := &Config{
+		BasePath:  basePath,
+		ObjectIRI: serviceIRI,
+		PageSize:  4,
+	}
+
+	h := NewPendingFollows(cfg, memstore.New(""), &mocks.SignatureVerifier{}, &apmocks.AuthTokenMgr{})
+	require.NotNil(t, h)
+	require.Equal(t, "/services/orb/pendingfollows", h.Path())
+	require.Equal(t, http.MethodGet, h.Method())
+	require.NotNil(t, h.Handler())
+
+	objectIRI, err := h.getObjectIRI(nil)
+	require.NoError(t, err)
+
+	id, err := h.getID(objectIRI, nil)
+	require.NoError(t, err)
+	require.Equal(t, "https://example1.com/services/orb/pendingfollows", id.String())
+}
+
 func TestNewFollowing(t *testing.T) {
 	cfg := &Config{
 		BasePath:  basePath,
@@ -425,6 +446,55 @@ func TestWitnesses_Handler(t *testing.T) {
 	})
 }
 
+func TestWitnesses_Visibility(t *testing.T) {
+	cfg := &Config{
+		BasePath:   basePath,
+		ObjectIRI:  serviceIRI,
+		PageSize:   4,
+		Visibility: map[string]Visibility{WitnessesPath: VisibilityAuthenticated},
+	}
+
+	verifier := &mocks.SignatureVerifier{}
+
+	h := NewWitnesses(cfg, memstore.New(""), verifier, &apmocks.AuthTokenMgr{})
+	require.NotNil(t, h)
+
+	t.Run("Anonymous -> unauthorized", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+
+		h.Handler()(rw, httptest.NewRequest(http.MethodGet, "https://example1.com/services/orb/witnesses", nil))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusUnauthorized, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+
+	t.Run("Verifier error -> internal server error", func(t *testing.T) {
+		verifier.VerifyRequestReturns(false, nil, errors.New("injected verifier error"))
+		defer verifier.VerifyRequestReturns(false, nil, nil)
+
+		rw := httptest.NewRecorder()
+
+		h.Handler()(rw, httptest.NewRequest(http.MethodGet, "https://example1.com/services/orb/witnesses", nil))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusInternalServerError, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+
+	t.Run("Signed request -> success", func(t *testing.T) {
+		verifier.VerifyRequestReturns(true, serviceIRI, nil)
+
+		rw := httptest.NewRecorder()
+
+		h.Handler()(rw, httptest.NewRequest(http.MethodGet, "https://example1.com/services/orb/witnesses", nil))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusOK, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+}
+
 func TestWitnessing_Handler(t *testing.T) {
 	witnessing := testutil.NewMockURLs(19, func(i int) string {
 		return fmt.Sprintf("https://example%d.com/services/orb", i+1)
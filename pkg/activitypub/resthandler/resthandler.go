@@ -13,11 +13,14 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"sync/atomic"
 
 	"github.com/gorilla/mux"
 	"github.com/trustbloc/edge-core/pkg/log"
 	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
 
+	"github.com/trustbloc/orb/pkg/activitypub/anchordigest"
+	"github.com/trustbloc/orb/pkg/activitypub/provenance"
 	"github.com/trustbloc/orb/pkg/activitypub/store/spi"
 	"github.com/trustbloc/orb/pkg/activitypub/vocab"
 	orberrors "github.com/trustbloc/orb/pkg/errors"
@@ -50,6 +53,18 @@ const (
 	ActivitiesPath = "/activities/{id}"
 	// AcceptListPath specifies the endpoint to manage an "accept list" for a service.
 	AcceptListPath = "/acceptlist"
+	// AnchorDigestPath specifies the endpoint that returns a digest of the anchor activities in the service's outbox.
+	AnchorDigestPath = anchordigest.Path
+	// PendingFollowsPath specifies the endpoint to manage the 'Follow' requests that are pending approval.
+	PendingFollowsPath = "/pendingfollows"
+	// ProvenancePath specifies the endpoint that returns the provenance chain of an anchor.
+	ProvenancePath = provenance.Path
+	// DeliveriesPath specifies the endpoint that returns the delivery receipts of an activity posted to the outbox.
+	DeliveriesPath = OutboxPath + "/{id}/deliveries"
+	// OutboxChangesPath specifies the endpoint that returns the activities added to the outbox since a sync token.
+	OutboxChangesPath = OutboxPath + "/changes"
+	// SearchPath specifies the endpoint that searches the activities in the store.
+	SearchPath = "/search"
 )
 
 const (
@@ -69,10 +84,61 @@ const (
 
 // Config contains configuration parameters for the handler.
 type Config struct {
+	// pageSizeOverride must be the first field in order to guarantee 64-bit alignment for atomic operations.
+	pageSizeOverride int64
+
 	BasePath               string
 	ObjectIRI              *url.URL
 	PageSize               int
 	VerifyActorInSignature bool
+
+	// Visibility contains the visibility of the collections, keyed by endpoint (for example, "/followers").
+	// If the visibility isn't specified for an endpoint then VisibilityPublic is assumed.
+	Visibility map[string]Visibility
+
+	// Features (optional) is consulted for the features that are rolled out incrementally. If not set then
+	// the features are enabled.
+	Features featureFlags
+
+	// AccessTokens (optional) verifies the scoped access tokens that grant partner systems read access to
+	// specific collections. If not set then only the configured authorization tokens are accepted.
+	AccessTokens accessTokenVerifier
+
+	// Capabilities (optional) contains the features supported by this service (signature suites, linkset formats,
+	// protocol versions, etc.) which are published in the service document so that peers may choose compatible
+	// formats.
+	Capabilities *vocab.CapabilitiesType
+}
+
+type featureFlags interface {
+	Enabled(name string) bool
+}
+
+type accessTokenVerifier interface {
+	Verify(token, collection string) bool
+}
+
+// featureEnabled returns true if the given feature is enabled or if no feature flags are configured.
+func (c *Config) featureEnabled(name string) bool {
+	if c.Features == nil {
+		return true
+	}
+
+	return c.Features.Enabled(name)
+}
+
+// SetPageSize overrides PageSize. This function may be called while the handlers are serving requests.
+func (c *Config) SetPageSize(pageSize int) {
+	atomic.StoreInt64(&c.pageSizeOverride, int64(pageSize))
+}
+
+// GetPageSize returns the page size set with SetPageSize or, if not set, the configured PageSize.
+func (c *Config) GetPageSize() int {
+	if pageSize := atomic.LoadInt64(&c.pageSizeOverride); pageSize > 0 {
+		return int(pageSize)
+	}
+
+	return c.PageSize
 }
 
 type handler struct {
@@ -122,7 +188,24 @@ func (h *handler) Method() string {
 // Handler returns the handler that should be invoked when an HTTP GET is requested to the target endpoint.
 // This handler must be registered with an HTTP server.
 func (h *handler) Handler() common.HTTPRequestHandler {
-	return h.handler
+	return func(w http.ResponseWriter, req *http.Request) {
+		ok, err := h.AuthorizeVisibility(req)
+		if err != nil {
+			logger.Errorf("[%s] Error authorizing request: %s", h.endpoint, err)
+
+			h.writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+			return
+		}
+
+		if !ok {
+			h.writeResponse(w, http.StatusUnauthorized, []byte(unauthorizedResponse))
+
+			return
+		}
+
+		h.handler(w, req)
+	}
 }
 
 func (h *handler) getPageID(objectIRI fmt.Stringer, pageNum int) string {
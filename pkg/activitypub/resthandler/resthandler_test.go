@@ -42,6 +42,22 @@ func TestNewHandler(t *testing.T) {
 	require.Equal(t, "{page-num}", h.Params()[pageNumParam])
 }
 
+func TestConfig_SetPageSize(t *testing.T) {
+	cfg := &Config{
+		BasePath:  basePath,
+		ObjectIRI: serviceIRI,
+		PageSize:  4,
+	}
+
+	require.Equal(t, 4, cfg.GetPageSize())
+
+	cfg.SetPageSize(10)
+	require.Equal(t, 10, cfg.GetPageSize())
+
+	cfg.SetPageSize(0)
+	require.Equal(t, 4, cfg.GetPageSize())
+}
+
 func TestGetFirstPageNum(t *testing.T) {
 	t.Run("Sort ascending", func(t *testing.T) {
 		require.Equal(t, 0, getFirstPageNum(10, 3, spi.SortAscending))
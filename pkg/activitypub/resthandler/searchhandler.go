@@ -0,0 +1,161 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/trustbloc/orb/pkg/activitypub/search"
+	"github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+)
+
+const (
+	queryParam      = "q"
+	maxResultsParam = "max-results"
+
+	defaultSearchMaxResults = 100
+	maxSearchMaxResults     = 1000
+)
+
+type searchIndex interface {
+	Search(query string, maxResults int) ([]*url.URL, error)
+}
+
+// Search implements a REST handler that returns the activities that contain all of the terms in the given query
+// (for example, an activity ID, a CID or a DID suffix). The results are returned in an ordered collection, most
+// recent first. For example:
+//
+//	GET /services/orb/search?q=uEiDaapVGORqCmdWe5kkAbDzPpUFBuq_RgAYwhCXH5ehXbw&max-results=10
+type Search struct {
+	*handler
+
+	index searchIndex
+}
+
+// NewSearch returns a new activity search REST handler.
+func NewSearch(cfg *Config, activityStore spi.Store, index searchIndex, verifier signatureVerifier,
+	tm authTokenManager) *Search {
+	h := &Search{
+		index: index,
+	}
+
+	h.handler = newHandler(SearchPath, cfg, activityStore, h.handle, verifier, spi.SortDescending, tm)
+
+	return h
+}
+
+func (h *Search) handle(w http.ResponseWriter, req *http.Request) {
+	ok, _, err := h.Authorize(req)
+	if err != nil {
+		logger.Errorf("[%s] Error authorizing request: %s", h.endpoint, err)
+
+		h.writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	if !ok {
+		h.writeResponse(w, http.StatusUnauthorized, []byte(unauthorizedResponse))
+
+		return
+	}
+
+	query := req.URL.Query().Get(queryParam)
+
+	maxResults, err := getMaxResults(req)
+	if err != nil {
+		logger.Debugf("[%s] Invalid request: %s", h.endpoint, err)
+
+		h.writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	activityIDs, err := h.index.Search(query, maxResults)
+	if err != nil {
+		if errors.Is(err, search.ErrInvalidQuery) {
+			logger.Debugf("[%s] Invalid query [%s]: %s", h.endpoint, query, err)
+
+			h.writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+			return
+		}
+
+		logger.Errorf("[%s] Error searching for [%s]: %s", h.endpoint, query, err)
+
+		h.writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	items := make([]*vocab.ObjectProperty, 0, len(activityIDs))
+
+	for _, activityID := range activityIDs {
+		activity, e := h.activityStore.GetActivity(activityID)
+		if e != nil {
+			if errors.Is(e, spi.ErrNotFound) {
+				logger.Debugf("[%s] Activity in search index not found in store [%s]", h.endpoint, activityID)
+
+				continue
+			}
+
+			logger.Errorf("[%s] Unable to retrieve activity [%s]: %s", h.endpoint, activityID, e)
+
+			h.writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+			return
+		}
+
+		items = append(items, vocab.NewObjectProperty(vocab.WithActivity(activity)))
+	}
+
+	id, err := url.Parse(fmt.Sprintf("%s%s?%s=%s", h.ObjectIRI, SearchPath, queryParam, url.QueryEscape(query)))
+	if err != nil {
+		logger.Errorf("[%s] Invalid search results ID: %s", h.endpoint, err)
+
+		h.writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	resultsBytes, err := h.marshal(vocab.NewOrderedCollection(items,
+		vocab.WithContext(vocab.ContextActivityStreams),
+		vocab.WithID(id),
+	))
+	if err != nil {
+		logger.Errorf("[%s] Unable to marshal search results for [%s]: %s", h.endpoint, query, err)
+
+		h.writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	h.writeResponse(w, http.StatusOK, resultsBytes)
+}
+
+func getMaxResults(req *http.Request) (int, error) {
+	value := req.URL.Query().Get(maxResultsParam)
+	if value == "" {
+		return defaultSearchMaxResults, nil
+	}
+
+	maxResults, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value for %s [%s]: %w", maxResultsParam, value, err)
+	}
+
+	if maxResults <= 0 || maxResults > maxSearchMaxResults {
+		return 0, fmt.Errorf("%s must be between 1 and %d", maxResultsParam, maxSearchMaxResults)
+	}
+
+	return maxResults, nil
+}
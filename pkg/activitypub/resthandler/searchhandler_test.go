@@ -0,0 +1,183 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apmocks "github.com/trustbloc/orb/pkg/activitypub/mocks"
+	"github.com/trustbloc/orb/pkg/activitypub/search"
+	"github.com/trustbloc/orb/pkg/activitypub/service/mocks"
+	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+	"github.com/trustbloc/orb/pkg/internal/testutil/httptestutil"
+)
+
+const searchURL = "https://example1.com/services/orb/search"
+
+func TestNewSearch(t *testing.T) {
+	cfg := &Config{
+		BasePath:  basePath,
+		ObjectIRI: serviceIRI,
+	}
+
+	h := NewSearch(cfg, memstore.New(""), &mockSearchIndex{}, &mocks.SignatureVerifier{}, &apmocks.AuthTokenMgr{})
+	require.NotNil(t, h.Handler())
+	require.Equal(t, http.MethodGet, h.Method())
+	require.Equal(t, basePath+SearchPath, h.Path())
+}
+
+func TestSearch_Handler(t *testing.T) {
+	activityStore := memstore.New("")
+
+	activityID1 := testutil.NewMockID(serviceIRI, "/activities/1")
+	activityID2 := testutil.NewMockID(serviceIRI, "/activities/2")
+	activityID3 := testutil.NewMockID(serviceIRI, "/activities/3")
+
+	for _, activityID := range []*url.URL{activityID1, activityID2} {
+		require.NoError(t, activityStore.AddActivity(vocab.NewCreateActivity(
+			vocab.NewObjectProperty(vocab.WithIRI(testutil.MustParseURL("https://example.com/object"))),
+			vocab.WithID(activityID),
+			vocab.WithActor(serviceIRI),
+		)))
+	}
+
+	cfg := &Config{
+		BasePath:  basePath,
+		ObjectIRI: serviceIRI,
+	}
+
+	verifier := &mocks.SignatureVerifier{}
+	verifier.VerifyRequestReturns(true, service2IRI, nil)
+
+	t.Run("Success", func(t *testing.T) {
+		// activityID3 isn't in the activity store so it should be ignored.
+		index := &mockSearchIndex{results: []*url.URL{activityID2, activityID3, activityID1}}
+
+		h := NewSearch(cfg, activityStore, index, verifier, &apmocks.AuthTokenMgr{})
+
+		status, respBytes := httptestutil.Get(t, h.handle, searchURL+"?q=object&max-results=10")
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, "object", index.query)
+		require.Equal(t, 10, index.maxResults)
+
+		coll := &vocab.OrderedCollectionType{}
+		require.NoError(t, coll.UnmarshalJSON(respBytes))
+		require.Equal(t, 2, coll.TotalItems())
+		require.Len(t, coll.Items(), 2)
+		require.Equal(t, activityID2.String(), coll.Items()[0].Activity().ID().String())
+		require.Equal(t, activityID1.String(), coll.Items()[1].Activity().ID().String())
+		require.Equal(t, fmt.Sprintf("%s/search?q=object", serviceIRI), coll.ID().String())
+	})
+
+	t.Run("Default max results", func(t *testing.T) {
+		index := &mockSearchIndex{}
+
+		h := NewSearch(cfg, activityStore, index, verifier, &apmocks.AuthTokenMgr{})
+
+		status, _ := httptestutil.Get(t, h.handle, searchURL+"?q=object")
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, defaultSearchMaxResults, index.maxResults)
+	})
+
+	t.Run("Invalid max results", func(t *testing.T) {
+		h := NewSearch(cfg, activityStore, &mockSearchIndex{}, verifier, &apmocks.AuthTokenMgr{})
+
+		status, _ := httptestutil.Get(t, h.handle, searchURL+"?q=object&max-results=xxx")
+		require.Equal(t, http.StatusBadRequest, status)
+
+		status, _ = httptestutil.Get(t, h.handle, searchURL+"?q=object&max-results=0")
+		require.Equal(t, http.StatusBadRequest, status)
+
+		status, _ = httptestutil.Get(t, h.handle, searchURL+fmt.Sprintf("?q=object&max-results=%d", maxSearchMaxResults+1))
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("Invalid query", func(t *testing.T) {
+		index := &mockSearchIndex{err: fmt.Errorf("invalid: %w", search.ErrInvalidQuery)}
+
+		h := NewSearch(cfg, activityStore, index, verifier, &apmocks.AuthTokenMgr{})
+
+		status, _ := httptestutil.Get(t, h.handle, searchURL+"?q=orb")
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		v := &mocks.SignatureVerifier{}
+		v.VerifyRequestReturns(false, nil, nil)
+
+		tm := &apmocks.AuthTokenMgr{}
+		tm.RequiredAuthTokensReturns([]string{"admin", "read"}, nil)
+
+		h := NewSearch(cfg, activityStore, &mockSearchIndex{}, v, tm)
+
+		status, _ := httptestutil.Get(t, h.handle, searchURL+"?q=object")
+		require.Equal(t, http.StatusUnauthorized, status)
+	})
+
+	t.Run("Verify error", func(t *testing.T) {
+		v := &mocks.SignatureVerifier{}
+		v.VerifyRequestReturns(false, nil, errors.New("injected verify error"))
+
+		tm := &apmocks.AuthTokenMgr{}
+		tm.RequiredAuthTokensReturns([]string{"admin", "read"}, nil)
+
+		h := NewSearch(cfg, activityStore, &mockSearchIndex{}, v, tm)
+
+		status, _ := httptestutil.Get(t, h.handle, searchURL+"?q=object")
+		require.Equal(t, http.StatusInternalServerError, status)
+	})
+
+	t.Run("Search error", func(t *testing.T) {
+		index := &mockSearchIndex{err: errors.New("injected search error")}
+
+		h := NewSearch(cfg, activityStore, index, verifier, &apmocks.AuthTokenMgr{})
+
+		status, _ := httptestutil.Get(t, h.handle, searchURL+"?q=object")
+		require.Equal(t, http.StatusInternalServerError, status)
+	})
+
+	t.Run("Activity store error", func(t *testing.T) {
+		s := &mocks.ActivityStore{}
+		s.GetActivityReturns(nil, errors.New("injected store error"))
+
+		h := NewSearch(cfg, s, &mockSearchIndex{results: []*url.URL{activityID1}}, verifier,
+			&apmocks.AuthTokenMgr{})
+
+		status, _ := httptestutil.Get(t, h.handle, searchURL+"?q=object")
+		require.Equal(t, http.StatusInternalServerError, status)
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		h := NewSearch(cfg, activityStore, &mockSearchIndex{}, verifier, &apmocks.AuthTokenMgr{})
+		h.marshal = func(v interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		status, _ := httptestutil.Get(t, h.handle, searchURL+"?q=object")
+		require.Equal(t, http.StatusInternalServerError, status)
+	})
+}
+
+type mockSearchIndex struct {
+	results    []*url.URL
+	err        error
+	query      string
+	maxResults int
+}
+
+func (m *mockSearchIndex) Search(query string, maxResults int) ([]*url.URL, error) {
+	m.query = query
+	m.maxResults = maxResults
+
+	return m.results, m.err
+}
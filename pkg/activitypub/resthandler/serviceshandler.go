@@ -7,29 +7,54 @@ SPDX-License-Identifier: Apache-2.0
 package resthandler
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/trustbloc/orb/pkg/activitypub/store/spi"
 	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/featureflag"
 )
 
 // MainKeyID is the ID of the service's public key.
 const MainKeyID = "main-key"
 
+// collectionSummariesExpiry is the period for which the collection summaries (total items of
+// the followers, following, etc. collections) are cached before being read from the store again.
+const collectionSummariesExpiry = time.Minute
+
+// publicKeyResolver resolves the service's public keys other than the main key. An ErrContentNotFound error is
+// returned if the key doesn't exist.
+type publicKeyResolver interface {
+	ResolvePublicKey(keyIRI *url.URL) (*vocab.PublicKeyType, error)
+}
+
 // Services implements the 'services' REST handler to retrieve a given ActivityPub service (actor).
 type Services struct {
 	*handler
 
-	publicKey *vocab.PublicKeyType
+	publicKey   *vocab.PublicKeyType
+	profile     []vocab.Opt
+	keyResolver publicKeyResolver
+	mutex       sync.RWMutex
+
+	summaries        *vocab.CollectionSummariesType
+	summariesExpiry  time.Time
+	summariesMutex   sync.Mutex
+	summariesTimeNow func() time.Time
 }
 
 // NewServices returns a new 'services' REST handler.
 func NewServices(cfg *Config, activityStore spi.Store, publicKey *vocab.PublicKeyType,
 	tm authTokenManager) *Services {
 	h := &Services{
-		publicKey: publicKey,
+		publicKey:        publicKey,
+		summariesTimeNow: time.Now,
 	}
 
 	h.handler = newHandler("", cfg, activityStore, h.handle, nil, spi.SortAscending, tm)
@@ -49,6 +74,88 @@ func NewPublicKeys(cfg *Config, activityStore spi.Store, publicKey *vocab.Public
 	return h
 }
 
+// SetPublicKey replaces the public key of the service. This function may be called while
+// the handler is serving requests, for example when the HTTP signature key is rotated.
+func (h *Services) SetPublicKey(publicKey *vocab.PublicKeyType) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.publicKey = publicKey
+}
+
+// SetPublicKeyResolver sets the resolver of the public keys other than the main key, for example the keys
+// that were used to sign anchor linksets before the HTTP signature key was rotated.
+func (h *Services) SetPublicKeyResolver(resolver publicKeyResolver) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.keyResolver = resolver
+}
+
+func (h *Services) getPublicKey() *vocab.PublicKeyType {
+	h.mutex.RLock()
+	publicKey := h.publicKey
+	h.mutex.RUnlock()
+
+	return h.formatPublicKey(publicKey)
+}
+
+// getPublicKeyByID returns the public key with the given ID. ErrContentNotFound is returned if
+// the key doesn't exist.
+func (h *Services) getPublicKeyByID(keyID string) (*vocab.PublicKeyType, error) {
+	if keyID == MainKeyID {
+		return h.getPublicKey(), nil
+	}
+
+	h.mutex.RLock()
+	resolver := h.keyResolver
+	h.mutex.RUnlock()
+
+	if resolver == nil {
+		return nil, orberrors.ErrContentNotFound
+	}
+
+	keyIRI, err := newID(h.ObjectIRI, strings.Replace(PublicKeysPath, "{id}", keyID, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey, err := resolver.ResolvePublicKey(keyIRI)
+	if err != nil {
+		return nil, err
+	}
+
+	return h.formatPublicKey(publicKey), nil
+}
+
+func (h *Services) formatPublicKey(publicKey *vocab.PublicKeyType) *vocab.PublicKeyType {
+	if publicKey == nil || publicKey.PublicKeyMultibase == "" || h.featureEnabled(featureflag.MultikeyPublicKey) {
+		return publicKey
+	}
+
+	// The Multikey format is disabled so only the PEM-encoded key is published.
+	pemKey := *publicKey
+	pemKey.PublicKeyMultibase = ""
+
+	return &pemKey
+}
+
+// SetProfile replaces the options that add the profile (name, summary, icon, contact information, etc.)
+// to the service. This function may be called while the handler is serving requests.
+func (h *Services) SetProfile(opts ...vocab.Opt) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.profile = opts
+}
+
+func (h *Services) getProfile() []vocab.Opt {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	return h.profile
+}
+
 func (h *Services) handle(w http.ResponseWriter, req *http.Request) {
 	if !h.tokenVerifier.Verify(req) {
 		h.writeResponse(w, http.StatusUnauthorized, []byte(unauthorizedResponse))
@@ -94,17 +201,24 @@ func (h *Services) handlePublicKey(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	// Currently only one key is supported. In the future we may wish to have
-	// multiple keys per service.
-	if keyID != MainKeyID {
-		logger.Infof("[%s] Public key [%s] not found for [%s]", h.endpoint, h.ObjectIRI, keyID)
+	publicKey, err := h.getPublicKeyByID(keyID)
+	if err != nil {
+		if errors.Is(err, orberrors.ErrContentNotFound) {
+			logger.Infof("[%s] Public key [%s] not found for [%s]", h.endpoint, h.ObjectIRI, keyID)
+
+			h.writeResponse(w, http.StatusNotFound, []byte(notFoundResponse))
+
+			return
+		}
+
+		logger.Errorf("[%s] Error resolving public key [%s] for [%s]: %s", h.endpoint, keyID, h.ObjectIRI, err)
 
-		h.writeResponse(w, http.StatusNotFound, []byte(notFoundResponse))
+		h.writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
 
 		return
 	}
 
-	publicKeyBytes, err := h.marshal(h.publicKey)
+	publicKeyBytes, err := h.marshal(publicKey)
 	if err != nil {
 		logger.Errorf("[%s] Unable to marshal public key [%s]: %s", h.endpoint, h.ObjectIRI, err)
 
@@ -164,8 +278,8 @@ func (h *Services) newService() (*vocab.ActorType, error) {
 		return nil, err
 	}
 
-	return vocab.NewService(h.ObjectIRI,
-		vocab.WithPublicKey(h.publicKey),
+	opts := []vocab.Opt{
+		vocab.WithPublicKey(h.getPublicKey()),
 		vocab.WithInbox(inbox),
 		vocab.WithOutbox(outbox),
 		vocab.WithFollowers(followers),
@@ -175,7 +289,94 @@ func (h *Services) newService() (*vocab.ActorType, error) {
 		vocab.WithLiked(liked),
 		vocab.WithLikes(likes),
 		vocab.WithShares(shares),
-	), nil
+		vocab.WithCollections(h.getCollectionSummaries(followers, following, witnesses, witnessing)),
+	}
+
+	if h.Capabilities != nil {
+		opts = append(opts, vocab.WithCapabilities(h.Capabilities))
+	}
+
+	return vocab.NewService(h.ObjectIRI, append(opts, h.getProfile()...)...), nil
+}
+
+// getCollectionSummaries returns the summaries of the publicly visible collections of the service. The
+// summaries are cached for a short period so that the store isn't queried on every request. If the summaries
+// can't be read from the store then nil is returned so that the service may still be served.
+func (h *Services) getCollectionSummaries(followers, following, witnesses,
+	witnessing *url.URL) *vocab.CollectionSummariesType {
+	h.summariesMutex.Lock()
+	defer h.summariesMutex.Unlock()
+
+	now := h.summariesTimeNow()
+
+	if h.summaries != nil && now.Before(h.summariesExpiry) {
+		return h.summaries
+	}
+
+	summaries := &vocab.CollectionSummariesType{}
+
+	collections := []struct {
+		path    string
+		refType spi.ReferenceType
+		id      *url.URL
+		summary **vocab.CollectionSummaryType
+	}{
+		{path: FollowersPath, refType: spi.Follower, id: followers, summary: &summaries.Followers},
+		{path: FollowingPath, refType: spi.Following, id: following, summary: &summaries.Following},
+		{path: WitnessesPath, refType: spi.Witness, id: witnesses, summary: &summaries.Witnesses},
+		{path: WitnessingPath, refType: spi.Witnessing, id: witnessing, summary: &summaries.Witnessing},
+	}
+
+	for _, c := range collections {
+		if !h.isPublic(c.path) {
+			continue
+		}
+
+		totalItems, err := h.getTotalItems(c.refType)
+		if err != nil {
+			logger.Warnf("[%s] Unable to get total items of collection [%s]: %s", h.endpoint, c.id, err)
+
+			return nil
+		}
+
+		*c.summary = vocab.NewCollectionSummary(c.id, totalItems)
+	}
+
+	if *summaries == (vocab.CollectionSummariesType{}) {
+		// None of the collections are public.
+		return nil
+	}
+
+	h.summaries = summaries
+	h.summariesExpiry = now.Add(collectionSummariesExpiry)
+
+	return summaries
+}
+
+func (h *Services) isPublic(path string) bool {
+	visibility := h.Visibility[path]
+
+	return visibility == "" || visibility == VisibilityPublic
+}
+
+func (h *Services) getTotalItems(refType spi.ReferenceType) (int, error) {
+	it, err := h.activityStore.QueryReferences(refType, spi.NewCriteria(spi.WithObjectIRI(h.ObjectIRI)))
+	if err != nil {
+		return 0, fmt.Errorf("query references: %w", err)
+	}
+
+	defer func() {
+		if e := it.Close(); e != nil {
+			logger.Errorf("failed to close iterator: %s", e.Error())
+		}
+	}()
+
+	totalItems, err := it.TotalItems()
+	if err != nil {
+		return 0, fmt.Errorf("get total items: %w", err)
+	}
+
+	return totalItems, nil
 }
 
 func newID(iri fmt.Stringer, path string) (*url.URL, error) {
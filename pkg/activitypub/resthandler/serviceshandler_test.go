@@ -7,18 +7,27 @@ SPDX-License-Identifier: Apache-2.0
 package resthandler
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	apmocks "github.com/trustbloc/orb/pkg/activitypub/mocks"
+	"github.com/trustbloc/orb/pkg/activitypub/service/mocks"
 	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
+	"github.com/trustbloc/orb/pkg/activitypub/store/spi"
 	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/featureflag"
 	"github.com/trustbloc/orb/pkg/internal/testutil"
+	"github.com/trustbloc/orb/pkg/internal/testutil/httptestutil"
 )
 
 const (
@@ -97,6 +106,90 @@ func TestServices_Handler(t *testing.T) {
 		require.NoError(t, result.Body.Close())
 	})
 
+	t.Run("Profile", func(t *testing.T) {
+		h := NewServices(cfg, activityStore, publicKey, &apmocks.AuthTokenMgr{})
+		require.NotNil(t, h)
+
+		email, err := vocab.NewPropertyValue("Email", "admin@example1.com")
+		require.NoError(t, err)
+
+		h.SetProfile(
+			vocab.WithName("Example1"),
+			vocab.WithSummary("Orb node for example1.com"),
+			vocab.WithIcon(vocab.NewImage(testutil.MustParseURL("https://example1.com/icon.png"), "image/png")),
+			vocab.WithAttachment(vocab.NewObjectProperty(vocab.WithObject(email))),
+		)
+
+		rw := httptest.NewRecorder()
+
+		h.handle(rw, httptest.NewRequest(http.MethodGet, serviceIRI.String(), nil))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusOK, result.StatusCode)
+
+		respBytes, err := ioutil.ReadAll(result.Body)
+		require.NoError(t, err)
+		require.NoError(t, result.Body.Close())
+
+		service := &vocab.ActorType{}
+		require.NoError(t, json.Unmarshal(respBytes, service))
+		require.Equal(t, "Example1", service.Name())
+		require.Equal(t, "Orb node for example1.com", service.Summary())
+		require.NotNil(t, service.Icon())
+		require.Equal(t, "https://example1.com/icon.png", service.Icon().URL.String())
+		require.Len(t, service.Attachment(), 1)
+		require.NotNil(t, service.Inbox())
+		require.NotNil(t, service.PublicKey())
+
+		h.SetProfile()
+
+		rw = httptest.NewRecorder()
+
+		h.handle(rw, httptest.NewRequest(http.MethodGet, serviceIRI.String(), nil))
+
+		result = rw.Result()
+		require.Equal(t, http.StatusOK, result.StatusCode)
+
+		respBytes, err = ioutil.ReadAll(result.Body)
+		require.NoError(t, err)
+		require.NoError(t, result.Body.Close())
+
+		require.Equal(t, testutil.GetCanonical(t, serviceJSON), testutil.GetCanonical(t, string(respBytes)))
+	})
+
+	t.Run("Capabilities", func(t *testing.T) {
+		cfg := &Config{
+			BasePath:  basePath,
+			ObjectIRI: serviceIRI,
+			PageSize:  4,
+			Capabilities: &vocab.CapabilitiesType{
+				SignatureSuites:  []string{"JsonWebSignature2020"},
+				ContentEncodings: []string{"gzip"},
+			},
+		}
+
+		h := NewServices(cfg, activityStore, publicKey, &apmocks.AuthTokenMgr{})
+		require.NotNil(t, h)
+
+		rw := httptest.NewRecorder()
+
+		h.handle(rw, httptest.NewRequest(http.MethodGet, serviceIRI.String(), nil))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusOK, result.StatusCode)
+
+		respBytes, err := ioutil.ReadAll(result.Body)
+		require.NoError(t, err)
+		require.NoError(t, result.Body.Close())
+
+		service := &vocab.ActorType{}
+		require.NoError(t, json.Unmarshal(respBytes, service))
+		require.NotNil(t, service.Capabilities())
+		require.True(t, service.Capabilities().SupportsSignatureSuite("JsonWebSignature2020"))
+		require.True(t, service.Capabilities().SupportsContentEncoding("gzip"))
+		require.NotNil(t, service.Inbox())
+	})
+
 	t.Run("Marshal error", func(t *testing.T) {
 		h := NewServices(cfg, activityStore, publicKey, &apmocks.AuthTokenMgr{})
 		require.NotNil(t, h)
@@ -141,6 +234,106 @@ func TestServices_Handler(t *testing.T) {
 	})
 }
 
+func TestServices_Collections(t *testing.T) {
+	service3IRI := testutil.MustParseURL("https://example3.com/services/orb")
+
+	t.Run("Total items", func(t *testing.T) {
+		cfg := &Config{
+			BasePath:  basePath,
+			ObjectIRI: serviceIRI,
+			PageSize:  4,
+		}
+
+		activityStore := memstore.New("")
+
+		require.NoError(t, activityStore.AddReference(spi.Follower, serviceIRI, service2IRI))
+		require.NoError(t, activityStore.AddReference(spi.Follower, serviceIRI, service3IRI))
+		require.NoError(t, activityStore.AddReference(spi.Witness, serviceIRI, service2IRI))
+
+		now := time.Now()
+
+		h := NewServices(cfg, activityStore, publicKey, &apmocks.AuthTokenMgr{})
+		h.summariesTimeNow = func() time.Time { return now }
+
+		collections := getService(t, h).Collections()
+		require.NotNil(t, collections)
+		require.Equal(t, 2, collections.Followers.TotalItems)
+		require.Equal(t, testutil.NewMockID(serviceIRI, FollowersPath).String(), collections.Followers.ID.String())
+		require.Zero(t, collections.Following.TotalItems)
+		require.Equal(t, 1, collections.Witnesses.TotalItems)
+		require.Zero(t, collections.Witnessing.TotalItems)
+
+		require.NoError(t, activityStore.AddReference(spi.Witnessing, serviceIRI, service3IRI))
+
+		// The cached summaries are returned.
+		collections = getService(t, h).Collections()
+		require.Zero(t, collections.Witnessing.TotalItems)
+
+		h.summariesTimeNow = func() time.Time { return now.Add(collectionSummariesExpiry) }
+
+		collections = getService(t, h).Collections()
+		require.Equal(t, 1, collections.Witnessing.TotalItems)
+	})
+
+	t.Run("Private collections", func(t *testing.T) {
+		cfg := &Config{
+			BasePath:  basePath,
+			ObjectIRI: serviceIRI,
+			PageSize:  4,
+			Visibility: map[string]Visibility{
+				FollowersPath:  VisibilityPrivate,
+				FollowingPath:  VisibilityAuthenticated,
+				WitnessingPath: VisibilityPublic,
+			},
+		}
+
+		h := NewServices(cfg, memstore.New(""), publicKey, &apmocks.AuthTokenMgr{})
+
+		collections := getService(t, h).Collections()
+		require.NotNil(t, collections)
+		require.Nil(t, collections.Followers)
+		require.Nil(t, collections.Following)
+		require.NotNil(t, collections.Witnesses)
+		require.NotNil(t, collections.Witnessing)
+
+		cfg.Visibility[WitnessesPath] = VisibilityPrivate
+		cfg.Visibility[WitnessingPath] = VisibilityPrivate
+
+		h = NewServices(cfg, memstore.New(""), publicKey, &apmocks.AuthTokenMgr{})
+
+		require.Nil(t, getService(t, h).Collections())
+	})
+
+	t.Run("Store error", func(t *testing.T) {
+		cfg := &Config{
+			BasePath:  basePath,
+			ObjectIRI: serviceIRI,
+			PageSize:  4,
+		}
+
+		s := &mocks.ActivityStore{}
+		s.QueryReferencesReturns(nil, fmt.Errorf("injected store error"))
+
+		h := NewServices(cfg, s, publicKey, &apmocks.AuthTokenMgr{})
+
+		service := getService(t, h)
+		require.Nil(t, service.Collections())
+		require.NotNil(t, service.Followers())
+	})
+}
+
+func getService(t *testing.T, h *Services) *vocab.ActorType {
+	t.Helper()
+
+	status, respBytes := httptestutil.Get(t, h.handle, serviceIRI.String())
+	require.Equal(t, http.StatusOK, status)
+
+	service := &vocab.ActorType{}
+	require.NoError(t, json.Unmarshal(respBytes, service))
+
+	return service
+}
+
 func TestPublicKeys_Handler(t *testing.T) {
 	cfg := &Config{
 		BasePath:  basePath,
@@ -173,6 +366,79 @@ func TestPublicKeys_Handler(t *testing.T) {
 		require.NoError(t, result.Body.Close())
 	})
 
+	t.Run("Set public key", func(t *testing.T) {
+		h := NewPublicKeys(cfg, activityStore, publicKey, &apmocks.AuthTokenMgr{})
+		require.NotNil(t, h)
+
+		newPublicKey := vocab.NewPublicKey(
+			vocab.WithID(publicKeyIRI),
+			vocab.WithOwner(serviceIRI),
+			vocab.WithPublicKeyPem("-----BEGIN PUBLIC KEY-----\nMCowBQYDK2VwAyEA....."),
+		)
+
+		h.SetPublicKey(newPublicKey)
+
+		rw := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, serviceIRI.String(), nil)
+
+		restoreID := setIDParam(MainKeyID)
+		defer restoreID()
+
+		h.handlePublicKey(rw, req)
+
+		result := rw.Result()
+		require.Equal(t, http.StatusOK, result.StatusCode)
+
+		respBytes, err := ioutil.ReadAll(result.Body)
+		require.NoError(t, err)
+		require.NoError(t, result.Body.Close())
+
+		require.Contains(t, string(respBytes), "MCowBQYDK2VwAyEA")
+	})
+
+	t.Run("Multikey feature flag", func(t *testing.T) {
+		multikeyPublicKey := vocab.NewPublicKey(
+			vocab.WithID(publicKeyIRI),
+			vocab.WithOwner(serviceIRI),
+			vocab.WithPublicKeyPem(keyPem),
+			vocab.WithPublicKeyMultibase("z6MkiTBz1ymuepAQ4HEHYSF1H8quG5GLVVQR3djdX3mDooWp"),
+		)
+
+		features := &mockFeatureFlags{enabled: map[string]bool{featureflag.MultikeyPublicKey: true}}
+
+		h := NewPublicKeys(&Config{BasePath: basePath, ObjectIRI: serviceIRI, Features: features},
+			activityStore, multikeyPublicKey, &apmocks.AuthTokenMgr{})
+
+		restoreID := setIDParam(MainKeyID)
+		defer restoreID()
+
+		getKey := func() string {
+			rw := httptest.NewRecorder()
+
+			h.handlePublicKey(rw, httptest.NewRequest(http.MethodGet, serviceIRI.String(), nil))
+
+			result := rw.Result()
+			require.Equal(t, http.StatusOK, result.StatusCode)
+
+			respBytes, err := ioutil.ReadAll(result.Body)
+			require.NoError(t, err)
+			require.NoError(t, result.Body.Close())
+
+			return string(respBytes)
+		}
+
+		require.Contains(t, getKey(), "publicKeyMultibase")
+
+		features.enabled[featureflag.MultikeyPublicKey] = false
+
+		key := getKey()
+		require.NotContains(t, key, "publicKeyMultibase")
+		require.Contains(t, key, "publicKeyPem")
+
+		// The original key should not have been modified.
+		require.NotEmpty(t, multikeyPublicKey.PublicKeyMultibase)
+	})
+
 	t.Run("No key ID -> BadRequest", func(t *testing.T) {
 		h := NewPublicKeys(cfg, activityStore, publicKey, &apmocks.AuthTokenMgr{})
 		require.NotNil(t, h)
@@ -204,6 +470,56 @@ func TestPublicKeys_Handler(t *testing.T) {
 		require.NoError(t, result.Body.Close())
 	})
 
+	t.Run("Resolved key", func(t *testing.T) {
+		rotatedKeyIRI := testutil.NewMockID(serviceIRI, "/keys/main-key-ref1")
+
+		h := NewPublicKeys(cfg, activityStore, publicKey, &apmocks.AuthTokenMgr{})
+		h.SetPublicKeyResolver(&mockPublicKeyResolver{
+			keys: map[string]*vocab.PublicKeyType{
+				rotatedKeyIRI.String(): vocab.NewPublicKey(vocab.WithID(rotatedKeyIRI), vocab.WithOwner(serviceIRI)),
+			},
+		})
+
+		rw := httptest.NewRecorder()
+
+		restoreID := setIDParam("main-key-ref1")
+		defer restoreID()
+
+		h.handlePublicKey(rw, httptest.NewRequest(http.MethodGet, serviceIRI.String(), nil))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusOK, result.StatusCode)
+		require.Contains(t, rw.Body.String(), rotatedKeyIRI.String())
+		require.NoError(t, result.Body.Close())
+
+		rw = httptest.NewRecorder()
+
+		restoreID2 := setIDParam("main-key-ref2")
+		defer restoreID2()
+
+		h.handlePublicKey(rw, httptest.NewRequest(http.MethodGet, serviceIRI.String(), nil))
+
+		result = rw.Result()
+		require.Equal(t, http.StatusNotFound, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+
+	t.Run("Resolver error", func(t *testing.T) {
+		h := NewPublicKeys(cfg, activityStore, publicKey, &apmocks.AuthTokenMgr{})
+		h.SetPublicKeyResolver(&mockPublicKeyResolver{err: errors.New("injected resolver error")})
+
+		rw := httptest.NewRecorder()
+
+		restoreID := setIDParam("main-key-ref1")
+		defer restoreID()
+
+		h.handlePublicKey(rw, httptest.NewRequest(http.MethodGet, serviceIRI.String(), nil))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusInternalServerError, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+
 	t.Run("Marshal error", func(t *testing.T) {
 		h := NewPublicKeys(cfg, activityStore, publicKey, &apmocks.AuthTokenMgr{})
 		require.NotNil(t, h)
@@ -261,6 +577,28 @@ const (
     "https://w3id.org/security/v1",
     "https://w3id.org/activityanchors/v1"
   ],
+  "collections": {
+    "followers": {
+      "id": "https://example1.com/services/orb/followers",
+      "totalItems": 0,
+      "type": "Collection"
+    },
+    "following": {
+      "id": "https://example1.com/services/orb/following",
+      "totalItems": 0,
+      "type": "Collection"
+    },
+    "witnesses": {
+      "id": "https://example1.com/services/orb/witnesses",
+      "totalItems": 0,
+      "type": "Collection"
+    },
+    "witnessing": {
+      "id": "https://example1.com/services/orb/witnessing",
+      "totalItems": 0,
+      "type": "Collection"
+    }
+  },
   "followers": "https://example1.com/services/orb/followers",
   "following": "https://example1.com/services/orb/following",
   "id": "https://example1.com/services/orb",
@@ -285,3 +623,29 @@ const (
   "publicKeyPem": "-----BEGIN PUBLIC KEY-----\nMIIBIjANBgkqhki....."
 }`
 )
+
+type mockFeatureFlags struct {
+	enabled map[string]bool
+}
+
+func (m *mockFeatureFlags) Enabled(name string) bool {
+	return m.enabled[name]
+}
+
+type mockPublicKeyResolver struct {
+	keys map[string]*vocab.PublicKeyType
+	err  error
+}
+
+func (m *mockPublicKeyResolver) ResolvePublicKey(keyIRI *url.URL) (*vocab.PublicKeyType, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	publicKey, ok := m.keys[keyIRI.String()]
+	if !ok {
+		return nil, orberrors.ErrContentNotFound
+	}
+
+	return publicKey, nil
+}
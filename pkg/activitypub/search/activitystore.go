@@ -0,0 +1,47 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package search
+
+import (
+	"github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+)
+
+type indexer interface {
+	Add(activity *vocab.ActivityType) error
+}
+
+// ActivityStore wraps an ActivityPub store and adds activities to the search index when they are stored.
+type ActivityStore struct {
+	spi.Store
+
+	index indexer
+}
+
+// NewActivityStore returns a new ActivityPub store wrapper.
+func NewActivityStore(s spi.Store, index indexer) *ActivityStore {
+	return &ActivityStore{
+		Store: s,
+		index: index,
+	}
+}
+
+// AddActivity adds the activity to the underlying store and to the search index. An error from the search index
+// is logged and otherwise ignored since the activity itself was stored.
+func (s *ActivityStore) AddActivity(activity *vocab.ActivityType) error {
+	err := s.Store.AddActivity(activity)
+	if err != nil {
+		return err
+	}
+
+	err = s.index.Add(activity)
+	if err != nil {
+		logger.Warnf("Error adding activity [%s] to the search index: %s", activity.ID(), err)
+	}
+
+	return nil
+}
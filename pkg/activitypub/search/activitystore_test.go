@@ -0,0 +1,74 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package search
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/service/mocks"
+	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+)
+
+func TestActivityStore_AddActivity(t *testing.T) {
+	activity := newCreateActivity(service1IRI, "1", cid1)
+
+	t.Run("Success", func(t *testing.T) {
+		x, err := NewIndex(mem.NewProvider())
+		require.NoError(t, err)
+
+		s := NewActivityStore(memstore.New("service1"), x)
+
+		require.NoError(t, s.AddActivity(activity))
+
+		a, err := s.GetActivity(activity.ID().URL())
+		require.NoError(t, err)
+		require.Equal(t, activity.ID().String(), a.ID().String())
+
+		results, err := x.Search(cid1, 0)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+	})
+
+	t.Run("Store error", func(t *testing.T) {
+		errExpected := errors.New("injected store error")
+
+		apStore := &mocks.ActivityStore{}
+		apStore.AddActivityReturns(errExpected)
+
+		x := &mockIndexer{}
+
+		s := NewActivityStore(apStore, x)
+
+		require.ErrorIs(t, s.AddActivity(activity), errExpected)
+		require.Zero(t, x.count)
+	})
+
+	t.Run("Index error -> ignored", func(t *testing.T) {
+		x := &mockIndexer{err: errors.New("injected index error")}
+
+		s := NewActivityStore(memstore.New("service1"), x)
+
+		require.NoError(t, s.AddActivity(activity))
+		require.Equal(t, 1, x.count)
+	})
+}
+
+type mockIndexer struct {
+	count int
+	err   error
+}
+
+func (m *mockIndexer) Add(*vocab.ActivityType) error {
+	m.count++
+
+	return m.err
+}
@@ -0,0 +1,357 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package search
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+var logger = log.New("activity-search")
+
+// ErrInvalidQuery is returned if the query doesn't contain any searchable terms.
+var ErrInvalidQuery = errors.New("query must contain at least one term with a minimum length of 4 characters")
+
+const (
+	storeName = "activity-search"
+
+	// termTag holds the hash of a term so that all activities containing the term may be queried.
+	termTag = "term"
+
+	contextProperty = "@context"
+
+	// activityIDKey distinguishes the index entry of an activity's ID from the entries of its terms.
+	activityIDKey = "activity-id"
+
+	// Terms shorter than this (for example "orb" or "did") match too many activities to be useful.
+	minTermLength = 4
+	// Longer terms (for example, embedded proofs) are not indexed.
+	maxTermLength = 512
+	// maxTermsPerActivity bounds the number of index entries that are written for a single activity.
+	maxTermsPerActivity = 500
+)
+
+// entry is stored in the index for each term of an activity.
+type entry struct {
+	Activity string    `json:"activity"`
+	Indexed  time.Time `json:"indexed"`
+}
+
+// Index is a search index over the IDs and content of activities. The string values of an activity (IDs, CIDs,
+// hashlinks, DIDs, etc.) are split into terms (on any character other than a letter, digit, '-' or '_') and an
+// index entry, tagged with the hash of the term, is stored for each term. A query returns the activities that
+// contain all of the terms in the query. Terms are case-insensitive. An entry is also stored for the activity ID
+// itself so that a query for an activity ID matches only that activity.
+type Index struct {
+	store   storage.Store
+	marshal func(v interface{}) ([]byte, error)
+}
+
+// NewIndex returns a new activity search index.
+func NewIndex(provider storage.Provider) (*Index, error) {
+	s, err := provider.OpenStore(storeName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open activity search store: %w", err)
+	}
+
+	err = provider.SetStoreConfig(storeName, storage.StoreConfiguration{TagNames: []string{termTag}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set store configuration: %w", err)
+	}
+
+	return &Index{
+		store:   s,
+		marshal: json.Marshal,
+	}, nil
+}
+
+// Add indexes the ID and content of the given activity.
+func (x *Index) Add(activity *vocab.ActivityType) error {
+	activityBytes, err := x.marshal(activity)
+	if err != nil {
+		return fmt.Errorf("marshal activity [%s]: %w", activity.ID(), err)
+	}
+
+	var doc interface{}
+
+	err = json.Unmarshal(activityBytes, &doc)
+	if err != nil {
+		return fmt.Errorf("unmarshal activity [%s]: %w", activity.ID(), err)
+	}
+
+	terms := newTermSet()
+
+	collectTerms(doc, terms)
+
+	if len(terms.values) > maxTermsPerActivity {
+		logger.Debugf("Activity [%s] contains %d terms. Only the first %d terms are indexed.",
+			activity.ID(), len(terms.values), maxTermsPerActivity)
+
+		terms.values = terms.values[:maxTermsPerActivity]
+	}
+
+	e := &entry{
+		Activity: activity.ID().String(),
+		Indexed:  time.Now().UTC(),
+	}
+
+	value, err := x.marshal(e)
+	if err != nil {
+		return fmt.Errorf("marshal index entry: %w", err)
+	}
+
+	operations := make([]storage.Operation, len(terms.values), len(terms.values)+1)
+
+	for i, term := range terms.values {
+		operations[i] = storage.Operation{
+			Key:   hash(term, e.Activity),
+			Value: value,
+			Tags:  []storage.Tag{{Name: termTag, Value: hash(term)}},
+		}
+	}
+
+	operations = append(operations, storage.Operation{
+		Key:   hash(activityIDKey, e.Activity),
+		Value: value,
+	})
+
+	err = x.store.Batch(operations)
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("store index entries for activity [%s]: %w", activity.ID(), err))
+	}
+
+	logger.Debugf("Indexed %d terms for activity [%s]", len(operations), activity.ID())
+
+	return nil
+}
+
+// Search returns the IDs of the activities that contain all of the terms in the given query, most recently
+// indexed first. At most maxResults IDs are returned.
+func (x *Index) Search(query string, maxResults int) ([]*url.URL, error) {
+	// A query for an activity ID matches exactly (rather than matching every activity that shares its terms).
+	match, err := x.get(hash(activityIDKey, strings.TrimSpace(query)))
+	if err != nil {
+		return nil, err
+	}
+
+	if match != nil {
+		return toActivityIDs([]*entry{match}), nil
+	}
+
+	terms := newTermSet()
+	terms.addAll(query)
+
+	if len(terms.values) == 0 {
+		return nil, ErrInvalidQuery
+	}
+
+	// The longest term is likely to be the most selective (for example, a CID or DID suffix) so its entries are
+	// retrieved and the remaining terms are checked against each candidate.
+	sort.SliceStable(terms.values, func(i, j int) bool {
+		return len(terms.values[i]) > len(terms.values[j])
+	})
+
+	candidates, err := x.query(terms.values[0])
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*entry
+
+	for _, candidate := range candidates {
+		ok, e := x.containsAll(candidate.Activity, terms.values[1:])
+		if e != nil {
+			return nil, e
+		}
+
+		if ok {
+			results = append(results, candidate)
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Indexed.After(results[j].Indexed)
+	})
+
+	if maxResults > 0 && len(results) > maxResults {
+		results = results[:maxResults]
+	}
+
+	return toActivityIDs(results), nil
+}
+
+// get returns the index entry for the given key or nil if the entry doesn't exist.
+func (x *Index) get(key string) (*entry, error) {
+	value, err := x.store.Get(key)
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return nil, nil
+		}
+
+		return nil, orberrors.NewTransient(fmt.Errorf("get index entry: %w", err))
+	}
+
+	e := &entry{}
+
+	err = json.Unmarshal(value, e)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal index entry: %w", err)
+	}
+
+	return e, nil
+}
+
+func (x *Index) query(term string) ([]*entry, error) {
+	it, err := x.store.Query(fmt.Sprintf("%s:%s", termTag, hash(term)))
+	if err != nil {
+		return nil, orberrors.NewTransient(fmt.Errorf("query search index: %w", err))
+	}
+
+	defer func() {
+		if e := it.Close(); e != nil {
+			logger.Warnf("Error closing iterator: %s", e)
+		}
+	}()
+
+	var entries []*entry
+
+	for {
+		ok, e := it.Next()
+		if e != nil {
+			return nil, orberrors.NewTransient(fmt.Errorf("next index entry: %w", e))
+		}
+
+		if !ok {
+			break
+		}
+
+		value, e := it.Value()
+		if e != nil {
+			return nil, orberrors.NewTransient(fmt.Errorf("get index entry from iterator: %w", e))
+		}
+
+		ent := &entry{}
+
+		e = json.Unmarshal(value, ent)
+		if e != nil {
+			return nil, fmt.Errorf("unmarshal index entry: %w", e)
+		}
+
+		entries = append(entries, ent)
+	}
+
+	return entries, nil
+}
+
+func (x *Index) containsAll(activityID string, terms []string) (bool, error) {
+	for _, term := range terms {
+		_, err := x.store.Get(hash(term, activityID))
+		if err != nil {
+			if errors.Is(err, storage.ErrDataNotFound) {
+				return false, nil
+			}
+
+			return false, orberrors.NewTransient(fmt.Errorf("get index entry: %w", err))
+		}
+	}
+
+	return true, nil
+}
+
+func toActivityIDs(entries []*entry) []*url.URL {
+	activityIDs := make([]*url.URL, 0, len(entries))
+
+	for _, e := range entries {
+		activityID, err := url.Parse(e.Activity)
+		if err != nil {
+			logger.Warnf("Invalid activity ID in search index [%s]: %s", e.Activity, err)
+
+			continue
+		}
+
+		activityIDs = append(activityIDs, activityID)
+	}
+
+	return activityIDs
+}
+
+// collectTerms adds the terms of all of the string values in the given JSON document (except for the JSON-LD
+// context, which is the same for most activities).
+func collectTerms(doc interface{}, terms *termSet) {
+	switch v := doc.(type) {
+	case string:
+		terms.addAll(v)
+	case []interface{}:
+		for _, item := range v {
+			collectTerms(item, terms)
+		}
+	case map[string]interface{}:
+		// Sort the property names so that the terms are always collected in the same order.
+		names := make([]string, 0, len(v))
+
+		for name := range v {
+			if name != contextProperty {
+				names = append(names, name)
+			}
+		}
+
+		sort.Strings(names)
+
+		for _, name := range names {
+			collectTerms(v[name], terms)
+		}
+	}
+}
+
+// termSet holds a set of unique terms in the order in which they were added.
+type termSet struct {
+	values []string
+	exists map[string]struct{}
+}
+
+func newTermSet() *termSet {
+	return &termSet{exists: make(map[string]struct{})}
+}
+
+func (s *termSet) addAll(value string) {
+	for _, term := range strings.FieldsFunc(strings.ToLower(value), isSeparator) {
+		if len(term) < minTermLength || len(term) > maxTermLength {
+			continue
+		}
+
+		if _, ok := s.exists[term]; ok {
+			continue
+		}
+
+		s.exists[term] = struct{}{}
+		s.values = append(s.values, term)
+	}
+}
+
+func isSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_'
+}
+
+func hash(values ...string) string {
+	h := sha256.Sum256([]byte(strings.Join(values, "\n")))
+
+	return hex.EncodeToString(h[:])
+}
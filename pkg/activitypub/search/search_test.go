@@ -0,0 +1,247 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package search
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+	"github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+const (
+	cid1      = "uEiDaapVGORqCmdWe5kkAbDzPpUFBuq_RgAYwhCXH5ehXbw"
+	cid2      = "uEiBTDSWBv0PsUzqKXjm4yMDuavCZGHq4sg2jNlWKN5yWSA"
+	didSuffix = "EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A"
+)
+
+var (
+	service1IRI = testutil.MustParseURL("https://domain1.com/services/orb")
+	service2IRI = testutil.MustParseURL("https://domain2.com/services/orb")
+)
+
+func TestNewIndex(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		x, err := NewIndex(mem.NewProvider())
+		require.NoError(t, err)
+		require.NotNil(t, x)
+	})
+
+	t.Run("Open store error", func(t *testing.T) {
+		p := &mocks.Provider{}
+		p.OpenStoreReturns(nil, errors.New("injected open error"))
+
+		_, err := NewIndex(p)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected open error")
+	})
+
+	t.Run("Set store config error", func(t *testing.T) {
+		p := &mocks.Provider{}
+		p.SetStoreConfigReturns(errors.New("injected config error"))
+
+		_, err := NewIndex(p)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected config error")
+	})
+}
+
+func TestIndex_Search(t *testing.T) {
+	x, err := NewIndex(mem.NewProvider())
+	require.NoError(t, err)
+
+	create1 := newCreateActivity(service1IRI, "1", fmt.Sprintf("hl:%s:uoQ-BeEJpcGZzOi8v", cid1))
+	create2 := newCreateActivity(service1IRI, "2", fmt.Sprintf("https://domain1.com/cas/%s", cid2))
+	create3 := newCreateActivity(service2IRI, "3", fmt.Sprintf("did:orb:%s:%s", cid2, didSuffix))
+
+	require.NoError(t, x.Add(create1))
+	require.NoError(t, x.Add(create2))
+	require.NoError(t, x.Add(create3))
+
+	t.Run("CID", func(t *testing.T) {
+		results, err := x.Search(cid1, 0)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.Equal(t, create1.ID().String(), results[0].String())
+
+		results, err = x.Search(cid2, 0)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		require.Equal(t, create3.ID().String(), results[0].String(), "most recent should be first")
+		require.Equal(t, create2.ID().String(), results[1].String())
+	})
+
+	t.Run("DID", func(t *testing.T) {
+		results, err := x.Search(fmt.Sprintf("did:orb:%s:%s", cid2, didSuffix), 0)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.Equal(t, create3.ID().String(), results[0].String())
+
+		results, err = x.Search(didSuffix, 0)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+	})
+
+	t.Run("Case-insensitive", func(t *testing.T) {
+		results, err := x.Search(strings.ToLower(cid1), 0)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.Equal(t, create1.ID().String(), results[0].String())
+	})
+
+	t.Run("Activity ID", func(t *testing.T) {
+		results, err := x.Search(create2.ID().String(), 0)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.Equal(t, create2.ID().String(), results[0].String())
+	})
+
+	t.Run("All terms must match", func(t *testing.T) {
+		results, err := x.Search(cid1+" "+cid2, 0)
+		require.NoError(t, err)
+		require.Empty(t, results)
+
+		results, err = x.Search("domain2.com "+cid2, 0)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.Equal(t, create3.ID().String(), results[0].String())
+	})
+
+	t.Run("Max results", func(t *testing.T) {
+		results, err := x.Search("domain1", 0)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+
+		results, err = x.Search("domain1", 1)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.Equal(t, create2.ID().String(), results[0].String())
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		results, err := x.Search("uEiAnotfound", 0)
+		require.NoError(t, err)
+		require.Empty(t, results)
+	})
+
+	t.Run("Invalid query", func(t *testing.T) {
+		_, err := x.Search("did:orb", 0)
+		require.ErrorIs(t, err, ErrInvalidQuery)
+
+		_, err = x.Search("", 0)
+		require.ErrorIs(t, err, ErrInvalidQuery)
+	})
+}
+
+func TestIndex_Error(t *testing.T) {
+	activity := newCreateActivity(service1IRI, "1", cid1)
+
+	t.Run("Marshal error", func(t *testing.T) {
+		x, err := NewIndex(mem.NewProvider())
+		require.NoError(t, err)
+
+		x.marshal = func(v interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		err = x.Add(activity)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected marshal error")
+	})
+
+	t.Run("Batch error", func(t *testing.T) {
+		s := &mocks.Store{}
+		s.BatchReturns(errors.New("injected batch error"))
+
+		x, err := NewIndex(newMockProvider(s))
+		require.NoError(t, err)
+
+		err = x.Add(activity)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "injected batch error")
+	})
+
+	t.Run("Query error", func(t *testing.T) {
+		s := &mocks.Store{}
+		s.GetReturns(nil, storage.ErrDataNotFound)
+		s.QueryReturns(nil, errors.New("injected query error"))
+
+		x, err := NewIndex(newMockProvider(s))
+		require.NoError(t, err)
+
+		_, err = x.Search(cid1, 0)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "injected query error")
+	})
+
+	t.Run("Iterator error", func(t *testing.T) {
+		it := &mocks.Iterator{}
+		it.NextReturns(false, errors.New("injected next error"))
+
+		s := &mocks.Store{}
+		s.GetReturns(nil, storage.ErrDataNotFound)
+		s.QueryReturns(it, nil)
+
+		x, err := NewIndex(newMockProvider(s))
+		require.NoError(t, err)
+
+		_, err = x.Search(cid1, 0)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "injected next error")
+	})
+
+	t.Run("Get error", func(t *testing.T) {
+		it := &mocks.Iterator{}
+		it.NextReturnsOnCall(0, true, nil)
+		it.ValueReturns([]byte(`{"activity":"https://domain1.com/services/orb/activities/1"}`), nil)
+
+		s := &mocks.Store{}
+		s.QueryReturns(it, nil)
+		s.GetReturns(nil, errors.New("injected get error"))
+
+		x, err := NewIndex(newMockProvider(s))
+		require.NoError(t, err)
+
+		_, err = x.Search(cid1+" "+cid2, 0)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "injected get error")
+
+		s.GetReturns(nil, storage.ErrDataNotFound)
+
+		it.NextReturnsOnCall(1, true, nil)
+
+		results, err := x.Search(cid1+" "+cid2, 0)
+		require.NoError(t, err)
+		require.Empty(t, results)
+	})
+}
+
+func newCreateActivity(serviceIRI fmt.Stringer, id, objectValue string) *vocab.ActivityType {
+	return vocab.NewCreateActivity(
+		vocab.NewObjectProperty(vocab.WithIRI(testutil.MustParseURL(objectValue))),
+		vocab.WithID(testutil.MustParseURL(fmt.Sprintf("%s/activities/%s", serviceIRI, id))),
+		vocab.WithActor(testutil.MustParseURL(serviceIRI.String())),
+	)
+}
+
+func newMockProvider(s storage.Store) *mocks.Provider {
+	p := &mocks.Provider{}
+	p.OpenStoreReturns(s, nil)
+
+	return p
+}
@@ -0,0 +1,397 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package registry
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+var logger = log.New("acceptlist-registry")
+
+// ErrInvalidSignature is returned when the signature of the registry can't be verified.
+var ErrInvalidSignature = errors.New("invalid registry signature")
+
+const (
+	taskName = "acceptlist-registry"
+
+	// stateKey is the key in the config store of the last registry that was imported.
+	stateKey = "acceptlist-registry"
+
+	algEdDSA = "EdDSA"
+
+	defaultInterval     = time.Hour
+	defaultFetchTimeout = 30 * time.Second
+	maxRegistrySize     = 1024 * 1024
+
+	jwsParts = 3
+)
+
+type httpClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+type acceptListMgr interface {
+	Update(acceptType string, additions, deletions []*url.URL) error
+}
+
+type taskManager interface {
+	RegisterTask(taskType string, interval time.Duration, task func())
+}
+
+// Config contains the configuration parameters for the registry importer.
+type Config struct {
+	// URL is the URL of the registry.
+	URL string
+	// PublicKey is the key that's used to verify the signature of the registry.
+	PublicKey ed25519.PublicKey
+	// Interval is the interval at which the registry is fetched. Defaults to one hour.
+	Interval time.Duration
+}
+
+// Registry is a list of trusted Orb services, by accept list type, that's published by a consortium.
+// The registry is served as a compact JWS (signed with EdDSA) with the JSON registry as its payload.
+type Registry struct {
+	// Issued is the time that the registry was issued. A registry that was issued before the last
+	// registry that was imported is rejected.
+	Issued      time.Time     `json:"issued"`
+	AcceptLists []*AcceptList `json:"acceptLists"`
+}
+
+// AcceptList contains the URIs of the trusted services for an accept list type (for example, "follow"
+// or "invite-witness").
+type AcceptList struct {
+	Type string   `json:"type"`
+	URLs []string `json:"url"`
+}
+
+// Importer periodically fetches a signed registry of trusted services and merges it into the accept lists.
+// The URIs in the registry are added to the accept lists and the URIs that were imported from a previous
+// version of the registry, but are no longer in the registry, are removed.
+type Importer struct {
+	url       string
+	publicKey ed25519.PublicKey
+	interval  time.Duration
+	client    httpClient
+	mgr       acceptListMgr
+	store     storage.Store
+	mutex     sync.Mutex
+}
+
+// New returns a new registry importer. The given store holds the last registry that was imported.
+func New(cfg Config, mgr acceptListMgr, configStore storage.Store, client httpClient) (*Importer, error) {
+	if _, err := url.Parse(cfg.URL); err != nil || cfg.URL == "" {
+		return nil, fmt.Errorf("invalid registry URL [%s]", cfg.URL)
+	}
+
+	if len(cfg.PublicKey) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid registry public key size: %d", len(cfg.PublicKey))
+	}
+
+	interval := cfg.Interval
+
+	if interval == 0 {
+		interval = defaultInterval
+	}
+
+	return &Importer{
+		url:       cfg.URL,
+		publicKey: cfg.PublicKey,
+		interval:  interval,
+		client:    client,
+		mgr:       mgr,
+		store:     configStore,
+	}, nil
+}
+
+// Register registers the task that imports the registry.
+func (i *Importer) Register(taskMgr taskManager) {
+	logger.Infof("Registering accept list registry task - URL: %s, Interval: %s.", i.url, i.interval)
+
+	taskMgr.RegisterTask(taskName, i.interval, i.run)
+}
+
+func (i *Importer) run() {
+	if err := i.Import(); err != nil {
+		logger.Warnf("Error importing accept list registry from [%s]: %s", i.url, err)
+	}
+}
+
+// Import fetches and verifies the registry and merges it into the accept lists.
+func (i *Importer) Import() error {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	jws, err := i.fetch()
+	if err != nil {
+		return err
+	}
+
+	registry, err := i.verify(jws)
+	if err != nil {
+		return err
+	}
+
+	last, err := i.lastImported()
+	if err != nil {
+		return err
+	}
+
+	if last != nil && registry.Issued.Before(last.Issued) {
+		return fmt.Errorf("registry issued at %s is older than the last imported registry (issued at %s)",
+			registry.Issued, last.Issued)
+	}
+
+	err = i.merge(registry, last)
+	if err != nil {
+		return err
+	}
+
+	return i.saveImported(registry)
+}
+
+func (i *Importer) merge(registry, last *Registry) error {
+	current := toURLs(registry)
+	previous := toURLs(last)
+
+	for acceptType := range previous {
+		if _, ok := current[acceptType]; !ok {
+			current[acceptType] = nil
+		}
+	}
+
+	for acceptType, additions := range current {
+		deletions := difference(previous[acceptType], additions)
+
+		err := i.mgr.Update(acceptType, additions, deletions)
+		if err != nil {
+			return fmt.Errorf("update accept list [%s]: %w", acceptType, err)
+		}
+
+		logger.Debugf("Merged accept list [%s] from registry - Additions: %s, Deletions: %s",
+			acceptType, additions, deletions)
+	}
+
+	logger.Infof("Imported accept list registry issued at %s from [%s]", registry.Issued, i.url)
+
+	return nil
+}
+
+func (i *Importer) fetch() ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultFetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, i.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("new request for registry [%s]: %w", i.url, err)
+	}
+
+	resp, err := i.client.Do(req)
+	if err != nil {
+		return nil, orberrors.NewTransient(fmt.Errorf("retrieve registry [%s]: %w", i.url, err))
+	}
+
+	defer func() {
+		if e := resp.Body.Close(); e != nil {
+			logger.Warnf("Error closing response body: %s", e)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("retrieve registry [%s]: unexpected status code %d", i.url, resp.StatusCode)
+	}
+
+	content, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxRegistrySize+1))
+	if err != nil {
+		return nil, orberrors.NewTransient(fmt.Errorf("read registry [%s]: %w", i.url, err))
+	}
+
+	if len(content) > maxRegistrySize {
+		return nil, fmt.Errorf("registry [%s] exceeds the maximum size of %d bytes", i.url, maxRegistrySize)
+	}
+
+	return content, nil
+}
+
+// verify verifies the signature of the given compact JWS and returns the registry in its payload.
+func (i *Importer) verify(jws []byte) (*Registry, error) {
+	parts := strings.Split(strings.TrimSpace(string(jws)), ".")
+	if len(parts) != jwsParts {
+		return nil, errors.New("registry is not a compact JWS")
+	}
+
+	err := verifyHeader(parts[0])
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("decode registry signature: %w", err)
+	}
+
+	if !ed25519.Verify(i.publicKey, []byte(parts[0]+"."+parts[1]), signature) {
+		return nil, ErrInvalidSignature
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("decode registry payload: %w", err)
+	}
+
+	registry := &Registry{}
+
+	err = json.Unmarshal(payload, registry)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal registry: %w", err)
+	}
+
+	err = validate(registry)
+	if err != nil {
+		return nil, fmt.Errorf("invalid registry: %w", err)
+	}
+
+	return registry, nil
+}
+
+func (i *Importer) lastImported() (*Registry, error) {
+	value, err := i.store.Get(stateKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return nil, nil
+		}
+
+		return nil, orberrors.NewTransient(fmt.Errorf("get last imported registry: %w", err))
+	}
+
+	registry := &Registry{}
+
+	err = json.Unmarshal(value, registry)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal last imported registry: %w", err)
+	}
+
+	return registry, nil
+}
+
+func (i *Importer) saveImported(registry *Registry) error {
+	value, err := json.Marshal(registry)
+	if err != nil {
+		return fmt.Errorf("marshal registry: %w", err)
+	}
+
+	err = i.store.Put(stateKey, value)
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("store imported registry: %w", err))
+	}
+
+	return nil
+}
+
+func verifyHeader(encodedHeader string) error {
+	headerBytes, err := base64.RawURLEncoding.DecodeString(encodedHeader)
+	if err != nil {
+		return fmt.Errorf("decode registry JWS header: %w", err)
+	}
+
+	header := &struct {
+		Alg string `json:"alg"`
+	}{}
+
+	err = json.Unmarshal(headerBytes, header)
+	if err != nil {
+		return fmt.Errorf("unmarshal registry JWS header: %w", err)
+	}
+
+	if header.Alg != algEdDSA {
+		return fmt.Errorf("unsupported registry signature algorithm [%s]", header.Alg)
+	}
+
+	return nil
+}
+
+func validate(registry *Registry) error {
+	if registry.Issued.IsZero() {
+		return errors.New("issued time is required")
+	}
+
+	for _, list := range registry.AcceptLists {
+		if list == nil || list.Type == "" {
+			return errors.New("accept list type is required")
+		}
+
+		for _, rawURL := range list.URLs {
+			if _, err := url.Parse(rawURL); err != nil {
+				return fmt.Errorf("invalid URL in accept list [%s]: %w", list.Type, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// toURLs returns the URLs in the given (validated) registry by accept list type.
+func toURLs(registry *Registry) map[string][]*url.URL {
+	urls := make(map[string][]*url.URL)
+
+	if registry == nil {
+		return urls
+	}
+
+	for _, list := range registry.AcceptLists {
+		for _, rawURL := range list.URLs {
+			u, err := url.Parse(rawURL)
+			if err != nil {
+				// Shouldn't happen since the registry was validated.
+				continue
+			}
+
+			urls[list.Type] = append(urls[list.Type], u)
+		}
+	}
+
+	return urls
+}
+
+// difference returns the URLs in a that aren't in b.
+func difference(a, b []*url.URL) []*url.URL {
+	var diff []*url.URL
+
+	for _, u := range a {
+		if !contains(b, u) {
+			diff = append(diff, u)
+		}
+	}
+
+	return diff
+}
+
+func contains(arr []*url.URL, u *url.URL) bool {
+	for _, v := range arr {
+		if v.String() == u.String() {
+			return true
+		}
+	}
+
+	return false
+}
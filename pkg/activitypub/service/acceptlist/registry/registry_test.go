@@ -0,0 +1,460 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package registry
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/service/acceptlist"
+	servicemocks "github.com/trustbloc/orb/pkg/activitypub/service/mocks"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+const (
+	followType = "follow"
+	inviteType = "invite-witness"
+
+	service1 = "https://orb.domain1.com/services/orb"
+	service2 = "https://orb.domain2.com/services/orb"
+	service3 = "https://orb.domain3.com/services/orb"
+)
+
+func TestNew(t *testing.T) {
+	pubKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	configStore, err := mem.NewProvider().OpenStore("orb-config")
+	require.NoError(t, err)
+
+	mgr := acceptlist.NewManager(configStore)
+
+	t.Run("Success", func(t *testing.T) {
+		i, err := New(Config{URL: "https://registry.example.com", PublicKey: pubKey}, mgr, configStore,
+			http.DefaultClient)
+		require.NoError(t, err)
+		require.NotNil(t, i)
+		require.Equal(t, defaultInterval, i.interval)
+
+		i.Register(servicemocks.NewTaskManager("service1"))
+	})
+
+	t.Run("Invalid URL", func(t *testing.T) {
+		_, err := New(Config{PublicKey: pubKey}, mgr, configStore, http.DefaultClient)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid registry URL")
+
+		_, err = New(Config{URL: ":", PublicKey: pubKey}, mgr, configStore, http.DefaultClient)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid registry URL")
+	})
+
+	t.Run("Invalid public key", func(t *testing.T) {
+		_, err := New(Config{URL: "https://registry.example.com", PublicKey: pubKey[1:]}, mgr, configStore,
+			http.DefaultClient)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid registry public key size")
+	})
+}
+
+func TestImporter_Import(t *testing.T) {
+	pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	t.Run("Success", func(t *testing.T) {
+		configStore, err := mem.NewProvider().OpenStore("orb-config")
+		require.NoError(t, err)
+
+		mgr := acceptlist.NewManager(configStore)
+
+		// Manually added entry which isn't in the registry.
+		require.NoError(t, mgr.Update(followType, []*url.URL{mustParseURL(t, service3)}, nil))
+
+		srv := newRegistryServer()
+		defer srv.Close()
+
+		i, err := New(Config{URL: srv.URL, PublicKey: pubKey}, mgr, configStore, http.DefaultClient)
+		require.NoError(t, err)
+
+		issued := time.Now().Add(-time.Hour).UTC()
+
+		srv.set(http.StatusOK, sign(t, privKey, algEdDSA, &Registry{
+			Issued: issued,
+			AcceptLists: []*AcceptList{
+				{Type: followType, URLs: []string{service1, service2}},
+				{Type: inviteType, URLs: []string{service1}},
+			},
+		}))
+
+		require.NoError(t, i.Import())
+
+		requireAcceptList(t, mgr, followType, service1, service2, service3)
+		requireAcceptList(t, mgr, inviteType, service1)
+
+		// Import the same registry again.
+		require.NoError(t, i.Import())
+
+		requireAcceptList(t, mgr, followType, service1, service2, service3)
+		requireAcceptList(t, mgr, inviteType, service1)
+
+		// service2 is removed from the "follow" list and the "invite-witness" list is removed altogether.
+		srv.set(http.StatusOK, sign(t, privKey, algEdDSA, &Registry{
+			Issued: issued.Add(time.Minute),
+			AcceptLists: []*AcceptList{
+				{Type: followType, URLs: []string{service1}},
+			},
+		}))
+
+		require.NoError(t, i.Import())
+
+		// The manually added entry is retained.
+		requireAcceptList(t, mgr, followType, service1, service3)
+		requireAcceptList(t, mgr, inviteType)
+
+		// A registry issued before the last imported registry is rejected.
+		srv.set(http.StatusOK, sign(t, privKey, algEdDSA, &Registry{
+			Issued: issued,
+			AcceptLists: []*AcceptList{
+				{Type: followType, URLs: []string{service1, service2}},
+			},
+		}))
+
+		err = i.Import()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "older than the last imported registry")
+
+		requireAcceptList(t, mgr, followType, service1, service3)
+	})
+
+	t.Run("Task", func(t *testing.T) {
+		configStore, err := mem.NewProvider().OpenStore("orb-config")
+		require.NoError(t, err)
+
+		mgr := acceptlist.NewManager(configStore)
+
+		srv := newRegistryServer()
+		defer srv.Close()
+
+		srv.set(http.StatusOK, sign(t, privKey, algEdDSA, &Registry{
+			Issued:      time.Now(),
+			AcceptLists: []*AcceptList{{Type: followType, URLs: []string{service1}}},
+		}))
+
+		i, err := New(Config{URL: srv.URL, PublicKey: pubKey, Interval: 100 * time.Millisecond}, mgr, configStore,
+			http.DefaultClient)
+		require.NoError(t, err)
+
+		taskMgr := servicemocks.NewTaskManager("service1").WithInterval(50 * time.Millisecond)
+
+		i.Register(taskMgr)
+
+		taskMgr.Start()
+		defer taskMgr.Stop()
+
+		require.Eventually(t, func() bool {
+			urls, e := mgr.Get(followType)
+
+			return e == nil && len(urls) == 1
+		}, time.Second, 50*time.Millisecond)
+	})
+
+	t.Run("Fetch error", func(t *testing.T) {
+		i, srv := newTestImporter(t, pubKey)
+		defer srv.Close()
+
+		srv.set(http.StatusInternalServerError, "")
+
+		err = i.Import()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unexpected status code 500")
+
+		srv.Close()
+
+		err = i.Import()
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+	})
+
+	t.Run("Registry too large", func(t *testing.T) {
+		i, srv := newTestImporter(t, pubKey)
+		defer srv.Close()
+
+		srv.set(http.StatusOK, string(make([]byte, maxRegistrySize+1)))
+
+		err = i.Import()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "exceeds the maximum size")
+	})
+
+	t.Run("Invalid signature", func(t *testing.T) {
+		i, srv := newTestImporter(t, pubKey)
+		defer srv.Close()
+
+		_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		srv.set(http.StatusOK, sign(t, otherKey, algEdDSA, &Registry{Issued: time.Now()}))
+
+		require.ErrorIs(t, i.Import(), ErrInvalidSignature)
+	})
+
+	t.Run("Unsupported algorithm", func(t *testing.T) {
+		i, srv := newTestImporter(t, pubKey)
+		defer srv.Close()
+
+		srv.set(http.StatusOK, sign(t, privKey, "ES256", &Registry{Issued: time.Now()}))
+
+		err = i.Import()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unsupported registry signature algorithm")
+	})
+
+	t.Run("Invalid JWS", func(t *testing.T) {
+		i, srv := newTestImporter(t, pubKey)
+		defer srv.Close()
+
+		srv.set(http.StatusOK, "xxx")
+
+		err = i.Import()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "not a compact JWS")
+
+		srv.set(http.StatusOK, "!.xxx.xxx")
+
+		err = i.Import()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "decode registry JWS header")
+
+		srv.set(http.StatusOK, encode([]byte("{"))+".xxx.xxx")
+
+		err = i.Import()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal registry JWS header")
+
+		srv.set(http.StatusOK, encode([]byte(`{"alg":"EdDSA"}`))+".xxx.!")
+
+		err = i.Import()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "decode registry signature")
+	})
+
+	t.Run("Invalid registry", func(t *testing.T) {
+		i, srv := newTestImporter(t, pubKey)
+		defer srv.Close()
+
+		srv.set(http.StatusOK, signPayload(t, privKey, algEdDSA, []byte("{")))
+
+		err = i.Import()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal registry")
+
+		srv.set(http.StatusOK, sign(t, privKey, algEdDSA, &Registry{}))
+
+		err = i.Import()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "issued time is required")
+
+		srv.set(http.StatusOK, sign(t, privKey, algEdDSA, &Registry{
+			Issued:      time.Now(),
+			AcceptLists: []*AcceptList{{URLs: []string{service1}}},
+		}))
+
+		err = i.Import()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "accept list type is required")
+
+		srv.set(http.StatusOK, sign(t, privKey, algEdDSA, &Registry{
+			Issued:      time.Now(),
+			AcceptLists: []*AcceptList{{Type: followType, URLs: []string{":"}}},
+		}))
+
+		err = i.Import()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid URL in accept list")
+	})
+
+	t.Run("Config store error", func(t *testing.T) {
+		errExpected := errors.New("injected store error")
+
+		srv := newRegistryServer()
+		defer srv.Close()
+
+		srv.set(http.StatusOK, sign(t, privKey, algEdDSA, &Registry{Issued: time.Now()}))
+
+		configStore := &mocks.Store{}
+		configStore.GetReturns(nil, errExpected)
+
+		i, err := New(Config{URL: srv.URL, PublicKey: pubKey}, &mockAcceptListMgr{}, configStore,
+			http.DefaultClient)
+		require.NoError(t, err)
+
+		err = i.Import()
+		require.ErrorIs(t, err, errExpected)
+		require.True(t, orberrors.IsTransient(err))
+
+		configStore.GetReturns([]byte("{"), nil)
+
+		err = i.Import()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal last imported registry")
+
+		configStore = &mocks.Store{}
+		configStore.GetReturns(nil, storage.ErrDataNotFound)
+		configStore.PutReturns(errExpected)
+
+		i, err = New(Config{URL: srv.URL, PublicKey: pubKey}, &mockAcceptListMgr{}, configStore,
+			http.DefaultClient)
+		require.NoError(t, err)
+
+		err = i.Import()
+		require.ErrorIs(t, err, errExpected)
+		require.True(t, orberrors.IsTransient(err))
+	})
+
+	t.Run("Accept list update error", func(t *testing.T) {
+		errExpected := errors.New("injected update error")
+
+		srv := newRegistryServer()
+		defer srv.Close()
+
+		srv.set(http.StatusOK, sign(t, privKey, algEdDSA, &Registry{
+			Issued:      time.Now(),
+			AcceptLists: []*AcceptList{{Type: followType, URLs: []string{service1}}},
+		}))
+
+		configStore, err := mem.NewProvider().OpenStore("orb-config")
+		require.NoError(t, err)
+
+		i, err := New(Config{URL: srv.URL, PublicKey: pubKey}, &mockAcceptListMgr{err: errExpected}, configStore,
+			http.DefaultClient)
+		require.NoError(t, err)
+
+		require.ErrorIs(t, i.Import(), errExpected)
+
+		// The registry isn't saved if the update failed.
+		_, err = configStore.Get(stateKey)
+		require.Error(t, err)
+	})
+}
+
+func newTestImporter(t *testing.T, pubKey ed25519.PublicKey) (*Importer, *registryServer) {
+	t.Helper()
+
+	configStore, err := mem.NewProvider().OpenStore("orb-config")
+	require.NoError(t, err)
+
+	srv := newRegistryServer()
+
+	i, err := New(Config{URL: srv.URL, PublicKey: pubKey}, acceptlist.NewManager(configStore), configStore,
+		http.DefaultClient)
+	require.NoError(t, err)
+
+	return i, srv
+}
+
+func requireAcceptList(t *testing.T, mgr *acceptlist.Manager, acceptType string, expected ...string) {
+	t.Helper()
+
+	urls, err := mgr.Get(acceptType)
+	require.NoError(t, err)
+
+	actual := make([]string, len(urls))
+
+	for i, u := range urls {
+		actual[i] = u.String()
+	}
+
+	require.ElementsMatch(t, expected, actual)
+}
+
+func sign(t *testing.T, privKey ed25519.PrivateKey, alg string, registry *Registry) string {
+	t.Helper()
+
+	payload, err := json.Marshal(registry)
+	require.NoError(t, err)
+
+	return signPayload(t, privKey, alg, payload)
+}
+
+func signPayload(t *testing.T, privKey ed25519.PrivateKey, alg string, payload []byte) string {
+	t.Helper()
+
+	header, err := json.Marshal(map[string]string{"alg": alg})
+	require.NoError(t, err)
+
+	signingInput := encode(header) + "." + encode(payload)
+
+	return signingInput + "." + encode(ed25519.Sign(privKey, []byte(signingInput)))
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func mustParseURL(t *testing.T, raw string) *url.URL {
+	t.Helper()
+
+	u, err := url.Parse(raw)
+	require.NoError(t, err)
+
+	return u
+}
+
+type registryServer struct {
+	*httptest.Server
+
+	mutex      sync.RWMutex
+	statusCode int
+	content    string
+}
+
+func newRegistryServer() *registryServer {
+	s := &registryServer{statusCode: http.StatusNotFound}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		s.mutex.RLock()
+		defer s.mutex.RUnlock()
+
+		w.WriteHeader(s.statusCode)
+
+		if _, err := w.Write([]byte(s.content)); err != nil {
+			panic(err)
+		}
+	}))
+
+	return s
+}
+
+func (s *registryServer) set(statusCode int, content string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.statusCode = statusCode
+	s.content = content
+}
+
+type mockAcceptListMgr struct {
+	err error
+}
+
+func (m *mockAcceptListMgr) Update(string, []*url.URL, []*url.URL) error {
+	return m.err
+}
@@ -57,6 +57,28 @@ func (h *AcceptListAuthHandler) AuthorizeActor(actor *vocab.ActorType) (bool, er
 	return false, nil
 }
 
+// AcceptListHoldAuthHandler implements an authorization handler that approves a request if the actor URI is included
+// in the 'accept list'. Requests by all other actors are held for approval by an administrator.
+type AcceptListHoldAuthHandler struct {
+	*AcceptListAuthHandler
+}
+
+// NewAcceptListHoldAuthHandler returns a new accept list authorization handler which holds requests by actors
+// that aren't in the accept list.
+func NewAcceptListHoldAuthHandler(allowType string, mgr acceptListMgr) *AcceptListHoldAuthHandler {
+	return &AcceptListHoldAuthHandler{
+		AcceptListAuthHandler: NewAcceptListAuthHandler(allowType, mgr),
+	}
+}
+
+// HoldForApproval always returns true since a request by an actor that isn't in the accept list is held
+// for approval.
+func (h *AcceptListHoldAuthHandler) HoldForApproval(actor *vocab.ActorType) bool {
+	logger.Debugf("Holding request by actor [%s] for approval for type [%s]", actor.ID(), h.allowType)
+
+	return true
+}
+
 func contains(arr []*url.URL, uri *url.URL) bool {
 	for _, s := range arr {
 		if s.String() == uri.String() {
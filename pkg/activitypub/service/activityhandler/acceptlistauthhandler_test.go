@@ -67,3 +67,25 @@ func TestAcceptListAuthHandler_AuthorizeActor(t *testing.T) {
 		require.False(t, ok)
 	})
 }
+
+func TestAcceptListHoldAuthHandler(t *testing.T) {
+	service1 := vocab.MustParseURL("https://domain1.com/services/orb")
+	service2 := vocab.MustParseURL("https://domain2.com/services/orb")
+
+	mgr := &mocks.AcceptListMgr{}
+	mgr.GetReturns([]*url.URL{service1}, nil)
+
+	h := NewAcceptListHoldAuthHandler(FollowType, mgr)
+	require.NotNil(t, h)
+
+	ok, err := h.AuthorizeActor(vocab.NewService(service1))
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	actor := vocab.NewService(service2)
+
+	ok, err = h.AuthorizeActor(actor)
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.True(t, h.HoldForApproval(actor))
+}
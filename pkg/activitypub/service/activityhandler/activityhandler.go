@@ -16,6 +16,7 @@ import (
 	"github.com/trustbloc/edge-core/pkg/log"
 
 	"github.com/trustbloc/orb/pkg/activitypub/client"
+	"github.com/trustbloc/orb/pkg/activitypub/provenance"
 	service "github.com/trustbloc/orb/pkg/activitypub/service/spi"
 	store "github.com/trustbloc/orb/pkg/activitypub/store/spi"
 	"github.com/trustbloc/orb/pkg/activitypub/vocab"
@@ -26,8 +27,10 @@ import (
 var logger = log.New("activitypub_service")
 
 const (
-	defaultBufferSize      = 100
-	defaultMaxWitnessDelay = 10 * time.Minute
+	defaultBufferSize                  = 100
+	defaultMaxWitnessDelay             = 10 * time.Minute
+	defaultWitnessProofBatchSize       = 20
+	defaultWitnessProofCacheExpiration = 5 * time.Minute
 )
 
 // Config holds the configuration parameters for the activity handler.
@@ -45,6 +48,21 @@ type Config struct {
 	// MaxWitnessDelay is the maximum delay from when the witness receives the transaction (via an Offer) for
 	// the witness to include the transaction into the ledger.
 	MaxWitnessDelay time.Duration
+
+	// WitnessProofBatchWindow is the maximum amount of time that a witness proof is held so that it may be
+	// delivered to the anchor origin along with other proofs. If zero then proofs are not batched.
+	WitnessProofBatchWindow time.Duration
+
+	// WitnessProofBatchSize is the maximum number of witness proofs that are delivered in a single batch.
+	WitnessProofBatchSize int
+
+	// WitnessProofCacheSize is the maximum number of witness proofs that are cached so that a proof may
+	// be reused when the same anchor credential is offered again (for example, when an 'Offer' is redelivered).
+	// If zero then proofs are not cached.
+	WitnessProofCacheSize int
+
+	// WitnessProofCacheExpiration is the amount of time that a witness proof remains in the cache.
+	WitnessProofCacheExpiration time.Duration
 }
 
 type activityPubClient interface {
@@ -77,6 +95,14 @@ func newHandler(cfg *Config, s store.Store, activityPubClient activityPubClient,
 		cfg.MaxWitnessDelay = defaultMaxWitnessDelay
 	}
 
+	if cfg.WitnessProofBatchSize == 0 {
+		cfg.WitnessProofBatchSize = defaultWitnessProofBatchSize
+	}
+
+	if cfg.WitnessProofCacheExpiration == 0 {
+		cfg.WitnessProofCacheExpiration = defaultWitnessProofCacheExpiration
+	}
+
 	h := &handler{
 		Config:            cfg,
 		store:             s,
@@ -180,6 +206,27 @@ func (h *handler) undoActivity(activity *vocab.ActivityType) error {
 	}
 }
 
+// addProvenance adds the given activity to the provenance of the given anchor. Errors are only logged since
+// the provenance is informational.
+func (h *handler) addProvenance(anchorRef *url.URL, activity *vocab.ActivityType) {
+	anchorIRI, err := provenance.AnchorIRI(anchorRef.String())
+	if err != nil {
+		logger.Debugf("[%s] Not adding provenance for anchor [%s]: %s", h.ServiceName, anchorRef, err)
+
+		return
+	}
+
+	logger.Debugf("[%s] Adding '%s' activity [%s] to provenance of anchor [%s]",
+		h.ServiceName, activity.Type(), activity.ID(), anchorIRI)
+
+	err = h.store.AddReference(store.Provenance, anchorIRI, activity.ID().URL(),
+		store.WithActivityType(activity.Type().Types()[0]))
+	if err != nil {
+		logger.Warnf("[%s] Error adding '%s' activity [%s] to provenance of anchor [%s]: %s",
+			h.ServiceName, activity.Type(), activity.ID(), anchorIRI, err)
+	}
+}
+
 func (h *handler) notify(activity *vocab.ActivityType) {
 	h.mutex.RLock()
 	subscribers := h.subscribers
@@ -19,6 +19,8 @@ import (
 	"github.com/trustbloc/sidetree-core-go/pkg/canonicalizer"
 
 	"github.com/trustbloc/orb/pkg/activitypub/client"
+	"github.com/trustbloc/orb/pkg/activitypub/provenance"
+	"github.com/trustbloc/orb/pkg/activitypub/rejection"
 	servicemocks "github.com/trustbloc/orb/pkg/activitypub/service/mocks"
 	"github.com/trustbloc/orb/pkg/activitypub/service/spi"
 	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
@@ -83,7 +85,10 @@ func TestHandler_HandleUnsupportedActivity(t *testing.T) {
 		ServiceIRI:  testutil.MustParseURL("http://localhost:8301/services/service1"),
 	}
 
-	h := NewInbox(cfg, &servicemocks.ActivityStore{}, &servicemocks.Outbox{}, servicemocks.NewActivitPubClient())
+	rejections := &mockRejectionRecorder{}
+
+	h := NewInbox(cfg, &servicemocks.ActivityStore{}, &servicemocks.Outbox{}, servicemocks.NewActivitPubClient(),
+		spi.WithRejectionRecorder(rejections))
 	require.NotNil(t, h)
 
 	h.Start()
@@ -96,6 +101,11 @@ func TestHandler_HandleUnsupportedActivity(t *testing.T) {
 		err := h.HandleActivity(activity)
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "unsupported activity type")
+
+		recorded := rejections.get()
+		require.Len(t, recorded, 1)
+		require.Equal(t, rejection.ReasonUnsupportedType, recorded[0].Reason)
+		require.Equal(t, "unsupported_type", recorded[0].ActivityType)
 	})
 }
 
@@ -155,6 +165,8 @@ func TestHandler_InboxHandleCreateActivity(t *testing.T) {
 			refs, err := storeutil.ReadReferences(it, -1)
 			require.NoError(t, err)
 			require.NotEmpty(t, refs)
+
+			requireProvenance(t, activityStore, anchorEventURL, create.ID().URL())
 		})
 
 		t.Run("Handler error", func(t *testing.T) {
@@ -267,6 +279,8 @@ func TestHandler_OutboxHandleCreateActivity(t *testing.T) {
 			refs, err := storeutil.ReadReferences(it, -1)
 			require.NoError(t, err)
 			require.NotEmpty(t, refs)
+
+			requireProvenance(t, activityStore, anchorEvent.URL()[0], create.ID().URL())
 		})
 	})
 
@@ -342,8 +356,9 @@ func TestHandler_HandleFollowActivity(t *testing.T) {
 		WithActor(vocab.NewService(service3IRI))
 
 	followerAuth := servicemocks.NewActorAuth()
+	rejections := &mockRejectionRecorder{}
 
-	h := NewInbox(cfg, as, ob, apClient, spi.WithFollowAuth(followerAuth))
+	h := NewInbox(cfg, as, ob, apClient, spi.WithFollowAuth(followerAuth), spi.WithRejectionRecorder(rejections))
 	require.NotNil(t, h)
 
 	h.Start()
@@ -418,6 +433,12 @@ func TestHandler_HandleFollowActivity(t *testing.T) {
 			require.NoError(t, err)
 			require.False(t, containsIRI(followers, service3IRI))
 			require.Len(t, ob.Activities().QueryByType(vocab.TypeReject), 1)
+
+			recorded := rejections.get()
+			require.Len(t, recorded, 1)
+			require.Equal(t, rejection.ReasonNotInAcceptList, recorded[0].Reason)
+			require.Equal(t, follow.ID().String(), recorded[0].ActivityID)
+			require.Equal(t, service3IRI.String(), recorded[0].Actor)
 		})
 	})
 
@@ -495,6 +516,143 @@ func TestHandler_HandleFollowActivity(t *testing.T) {
 	})
 }
 
+func TestHandler_HoldFollowActivity(t *testing.T) {
+	service1IRI := testutil.MustParseURL("http://localhost:8301/services/service1")
+	service2IRI := testutil.MustParseURL("http://localhost:8302/services/service2")
+	service3IRI := testutil.MustParseURL("http://localhost:8303/services/service3")
+	service4IRI := testutil.MustParseURL("http://localhost:8304/services/service4")
+
+	cfg := &Config{
+		ServiceName: "service1",
+		ServiceIRI:  service1IRI,
+	}
+
+	ob := servicemocks.NewOutbox()
+	as := memstore.New(cfg.ServiceName)
+
+	apClient := servicemocks.NewActivitPubClient().
+		WithActor(vocab.NewService(service2IRI)).
+		WithActor(vocab.NewService(service3IRI)).
+		WithActor(vocab.NewService(service4IRI))
+
+	acceptListMgr := &servicemocks.AcceptListMgr{}
+	acceptListMgr.GetReturns([]*url.URL{service2IRI}, nil)
+
+	h := NewInbox(cfg, as, ob, apClient, spi.WithFollowAuth(NewAcceptListHoldAuthHandler(FollowType, acceptListMgr)))
+	require.NotNil(t, h)
+
+	h.Start()
+	defer h.Stop()
+
+	newFollow := func(actorIRI *url.URL) *vocab.ActivityType {
+		follow := vocab.NewFollowActivity(
+			vocab.NewObjectProperty(vocab.WithIRI(service1IRI)),
+			vocab.WithID(aptestutil.NewActivityID(actorIRI)),
+			vocab.WithActor(actorIRI),
+			vocab.WithTo(service1IRI),
+		)
+
+		require.NoError(t, h.HandleActivity(follow))
+		require.NoError(t, as.AddActivity(follow))
+
+		return follow
+	}
+
+	t.Run("Auto-accept", func(t *testing.T) {
+		newFollow(service2IRI)
+
+		require.True(t, hasFollower(t, h, service2IRI))
+		require.Len(t, ob.Activities().QueryByType(vocab.TypeAccept), 1)
+	})
+
+	t.Run("Hold -> Approve", func(t *testing.T) {
+		follow := newFollow(service3IRI)
+
+		require.False(t, hasFollower(t, h, service3IRI))
+		require.True(t, isPending(t, h, follow.ID().URL()))
+		require.Empty(t, ob.Activities().QueryByType(vocab.TypeReject))
+
+		require.NoError(t, h.ApproveFollow(follow.ID().URL()))
+
+		require.True(t, hasFollower(t, h, service3IRI))
+		require.False(t, isPending(t, h, follow.ID().URL()))
+		require.Len(t, ob.Activities().QueryByType(vocab.TypeAccept), 2)
+
+		err := h.ApproveFollow(follow.ID().URL())
+		require.True(t, errors.Is(err, store.ErrNotFound))
+	})
+
+	t.Run("Hold -> Reject", func(t *testing.T) {
+		follow := newFollow(service4IRI)
+
+		require.True(t, isPending(t, h, follow.ID().URL()))
+
+		require.NoError(t, h.RejectFollow(follow.ID().URL()))
+
+		require.False(t, hasFollower(t, h, service4IRI))
+		require.False(t, isPending(t, h, follow.ID().URL()))
+		require.Len(t, ob.Activities().QueryByType(vocab.TypeReject), 1)
+
+		err := h.RejectFollow(follow.ID().URL())
+		require.True(t, errors.Is(err, store.ErrNotFound))
+	})
+
+	t.Run("Activity not found", func(t *testing.T) {
+		followID := aptestutil.NewActivityID(service4IRI)
+
+		require.NoError(t, as.AddReference(store.PendingFollow, service1IRI, followID))
+
+		err := h.ApproveFollow(followID)
+		require.True(t, errors.Is(err, store.ErrNotFound))
+	})
+
+	t.Run("Resolve actor error", func(t *testing.T) {
+		follow := newFollow(service4IRI)
+
+		apClient.WithError(client.ErrNotFound)
+		defer func() { apClient.WithError(nil) }()
+
+		err := h.ApproveFollow(follow.ID().URL())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unable to retrieve actor")
+		require.True(t, isPending(t, h, follow.ID().URL()))
+	})
+
+	t.Run("Store error", func(t *testing.T) {
+		errExpected := errors.New("injected store error")
+
+		s := &servicemocks.ActivityStore{}
+		s.QueryReferencesReturns(nil, errExpected)
+
+		h := NewInbox(cfg, s, ob, apClient)
+
+		err := h.RejectFollow(aptestutil.NewActivityID(service4IRI))
+		require.True(t, errors.Is(err, errExpected))
+		require.True(t, orberrors.IsTransient(err))
+	})
+}
+
+func hasFollower(t *testing.T, h *Inbox, actorIRI *url.URL) bool {
+	t.Helper()
+
+	it, err := h.store.QueryReferences(store.Follower, store.NewCriteria(store.WithObjectIRI(h.ServiceIRI)))
+	require.NoError(t, err)
+
+	followers, err := storeutil.ReadReferences(it, -1)
+	require.NoError(t, err)
+
+	return containsIRI(followers, actorIRI)
+}
+
+func isPending(t *testing.T, h *Inbox, followID *url.URL) bool {
+	t.Helper()
+
+	pending, err := h.hasReference(h.ServiceIRI, followID, store.PendingFollow)
+	require.NoError(t, err)
+
+	return pending
+}
+
 func TestHandler_HandleInviteWitnessActivity(t *testing.T) {
 	log.SetLevel("activitypub_service", log.DEBUG)
 
@@ -677,6 +835,131 @@ func TestHandler_HandleInviteWitnessActivity(t *testing.T) {
 	})
 }
 
+func TestHandler_ReciprocateInviteWitnessActivity(t *testing.T) {
+	service1IRI := testutil.MustParseURL("http://localhost:8301/services/service1")
+	service2IRI := testutil.MustParseURL("http://localhost:8302/services/service2")
+	service3IRI := testutil.MustParseURL("http://localhost:8303/services/service3")
+	service4IRI := testutil.MustParseURL("http://localhost:8304/services/service4")
+
+	cfg := &Config{
+		ServiceName: "service1",
+		ServiceIRI:  service1IRI,
+	}
+
+	apClient := servicemocks.NewActivitPubClient().
+		WithActor(vocab.NewService(service2IRI)).
+		WithActor(vocab.NewService(service3IRI)).
+		WithActor(vocab.NewService(service4IRI))
+
+	newInvite := func(actorIRI *url.URL, opts ...vocab.Opt) *vocab.ActivityType {
+		return vocab.NewInviteActivity(
+			vocab.NewObjectProperty(vocab.WithIRI(vocab.AnchorWitnessTargetIRI)),
+			append([]vocab.Opt{
+				vocab.WithID(aptestutil.NewActivityID(actorIRI)),
+				vocab.WithActor(actorIRI),
+				vocab.WithTo(service1IRI),
+				vocab.WithTarget(vocab.NewObjectProperty(vocab.WithIRI(service1IRI))),
+			}, opts...)...,
+		)
+	}
+
+	t.Run("Invite and follow", func(t *testing.T) {
+		ob := servicemocks.NewOutbox()
+
+		h := NewInbox(cfg, memstore.New(cfg.ServiceName), ob, apClient,
+			spi.WithInviteWitnessAuth(servicemocks.NewActorAuth().WithAccept()),
+			spi.WithWitnessReciprocation(&spi.WitnessReciprocationPolicy{InviteWitness: true, Follow: true}),
+		)
+
+		invite := newInvite(service2IRI)
+
+		require.NoError(t, h.HandleActivity(invite))
+
+		require.Len(t, ob.Activities().QueryByType(vocab.TypeAccept), 1)
+
+		invites := ob.Activities().QueryByType(vocab.TypeInvite)
+		require.Len(t, invites, 1)
+		require.Equal(t, service1IRI.String(), invites[0].Actor().String())
+		require.Equal(t, service2IRI.String(), invites[0].Target().IRI().String())
+		require.NotNil(t, invites[0].InReplyTo())
+		require.Equal(t, invite.ID().String(), invites[0].InReplyTo().String())
+
+		follows := ob.Activities().QueryByType(vocab.TypeFollow)
+		require.Len(t, follows, 1)
+		require.Equal(t, service2IRI.String(), follows[0].Object().IRI().String())
+
+		// The same actor invites again. The invitation was already accepted so it's not reciprocated again.
+		require.NoError(t, h.HandleActivity(newInvite(service2IRI)))
+
+		require.Len(t, ob.Activities().QueryByType(vocab.TypeAccept), 2)
+		require.Len(t, ob.Activities().QueryByType(vocab.TypeInvite), 1)
+		require.Len(t, ob.Activities().QueryByType(vocab.TypeFollow), 1)
+	})
+
+	t.Run("Reciprocal invitation", func(t *testing.T) {
+		ob := servicemocks.NewOutbox()
+
+		h := NewInbox(cfg, memstore.New(cfg.ServiceName), ob, apClient,
+			spi.WithInviteWitnessAuth(servicemocks.NewActorAuth().WithAccept()),
+			spi.WithWitnessReciprocation(&spi.WitnessReciprocationPolicy{InviteWitness: true, Follow: true}),
+		)
+
+		// An invitation that was sent in reciprocation isn't reciprocated (loop protection).
+		require.NoError(t, h.HandleActivity(newInvite(service3IRI,
+			vocab.WithInReplyTo(aptestutil.NewActivityID(service1IRI)))))
+
+		require.Len(t, ob.Activities().QueryByType(vocab.TypeAccept), 1)
+		require.Empty(t, ob.Activities().QueryByType(vocab.TypeInvite))
+		require.Empty(t, ob.Activities().QueryByType(vocab.TypeFollow))
+	})
+
+	t.Run("Already a witness and following", func(t *testing.T) {
+		ob := servicemocks.NewOutbox()
+		as := memstore.New(cfg.ServiceName)
+
+		require.NoError(t, as.AddReference(store.Witness, service1IRI, service4IRI))
+		require.NoError(t, as.AddReference(store.Following, service1IRI, service4IRI))
+
+		h := NewInbox(cfg, as, ob, apClient,
+			spi.WithInviteWitnessAuth(servicemocks.NewActorAuth().WithAccept()),
+			spi.WithWitnessReciprocation(&spi.WitnessReciprocationPolicy{InviteWitness: true, Follow: true}),
+		)
+
+		require.NoError(t, h.HandleActivity(newInvite(service4IRI)))
+
+		require.Len(t, ob.Activities().QueryByType(vocab.TypeAccept), 1)
+		require.Empty(t, ob.Activities().QueryByType(vocab.TypeInvite))
+		require.Empty(t, ob.Activities().QueryByType(vocab.TypeFollow))
+	})
+
+	t.Run("Invite only", func(t *testing.T) {
+		ob := servicemocks.NewOutbox()
+
+		h := NewInbox(cfg, memstore.New(cfg.ServiceName), ob, apClient,
+			spi.WithInviteWitnessAuth(servicemocks.NewActorAuth().WithAccept()),
+			spi.WithWitnessReciprocation(&spi.WitnessReciprocationPolicy{InviteWitness: true}),
+		)
+
+		require.NoError(t, h.HandleActivity(newInvite(service2IRI)))
+
+		require.Len(t, ob.Activities().QueryByType(vocab.TypeInvite), 1)
+		require.Empty(t, ob.Activities().QueryByType(vocab.TypeFollow))
+	})
+
+	t.Run("No policy", func(t *testing.T) {
+		ob := servicemocks.NewOutbox()
+
+		h := NewInbox(cfg, memstore.New(cfg.ServiceName), ob, apClient,
+			spi.WithInviteWitnessAuth(servicemocks.NewActorAuth().WithAccept()),
+		)
+
+		require.NoError(t, h.HandleActivity(newInvite(service2IRI)))
+
+		require.Len(t, ob.Activities().QueryByType(vocab.TypeAccept), 1)
+		require.Empty(t, ob.Activities().QueryByType(vocab.TypeInvite))
+	})
+}
+
 func TestHandler_HandleAcceptActivity(t *testing.T) {
 	service1IRI := testutil.MustParseURL("http://localhost:8301/services/service1")
 	service2IRI := testutil.MustParseURL("http://localhost:8302/services/service2")
@@ -863,6 +1146,89 @@ func TestHandler_HandleAcceptActivity(t *testing.T) {
 	})
 }
 
+func TestHandler_HandleAcceptWitnessRenewal(t *testing.T) {
+	service1IRI := testutil.MustParseURL("http://localhost:8301/services/service1")
+	service2IRI := testutil.MustParseURL("http://localhost:8302/services/service2")
+
+	cfg := &Config{
+		ServiceName: "service2",
+		ServiceIRI:  service2IRI,
+	}
+
+	newAccept := func(t *testing.T, as store.Store) *vocab.ActivityType {
+		t.Helper()
+
+		invite := vocab.NewInviteActivity(
+			vocab.NewObjectProperty(vocab.WithIRI(vocab.AnchorWitnessTargetIRI)),
+			vocab.WithID(aptestutil.NewActivityID(service2IRI)),
+			vocab.WithActor(service2IRI),
+			vocab.WithTo(service1IRI),
+			vocab.WithTarget(vocab.NewObjectProperty(vocab.WithIRI(service1IRI))),
+		)
+
+		require.NoError(t, as.AddActivity(invite))
+		require.NoError(t, as.AddReference(store.Outbox, service2IRI, invite.ID().URL()))
+
+		return vocab.NewAcceptActivity(
+			vocab.NewObjectProperty(vocab.WithActivity(invite)),
+			vocab.WithID(aptestutil.NewActivityID(service1IRI)),
+			vocab.WithActor(service1IRI),
+			vocab.WithTo(service2IRI),
+		)
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		as := memstore.New(cfg.ServiceName)
+		listener := &mockWitnessConfirmationListener{}
+
+		h := NewInbox(cfg, as, servicemocks.NewOutbox(), servicemocks.NewActivitPubClient(),
+			spi.WithWitnessConfirmationListener(listener))
+		require.NotNil(t, h)
+
+		h.Start()
+		defer h.Stop()
+
+		require.NoError(t, h.HandleActivity(newAccept(t, as)))
+
+		it, err := as.QueryReferences(store.Witness, store.NewCriteria(store.WithObjectIRI(h.ServiceIRI)))
+		require.NoError(t, err)
+
+		witnesses, err := storeutil.ReadReferences(it, -1)
+		require.NoError(t, err)
+		require.Len(t, witnesses, 1)
+		require.True(t, containsIRI(witnesses, service1IRI))
+
+		// A second 'Accept' from the same witness renews the relationship.
+		require.NoError(t, h.HandleActivity(newAccept(t, as)))
+
+		it, err = as.QueryReferences(store.Witness, store.NewCriteria(store.WithObjectIRI(h.ServiceIRI)))
+		require.NoError(t, err)
+
+		witnesses, err = storeutil.ReadReferences(it, -1)
+		require.NoError(t, err)
+		require.Len(t, witnesses, 1)
+
+		require.Len(t, listener.Confirmed(), 2)
+		require.Equal(t, service1IRI.String(), listener.Confirmed()[1].String())
+	})
+
+	t.Run("Listener error", func(t *testing.T) {
+		as := memstore.New(cfg.ServiceName)
+		errExpected := errors.New("injected listener error")
+
+		h := NewInbox(cfg, as, servicemocks.NewOutbox(), servicemocks.NewActivitPubClient(),
+			spi.WithWitnessConfirmationListener(&mockWitnessConfirmationListener{err: errExpected}))
+		require.NotNil(t, h)
+
+		h.Start()
+		defer h.Stop()
+
+		err := h.HandleActivity(newAccept(t, as))
+		require.Error(t, err)
+		require.True(t, errors.Is(err, errExpected))
+	})
+}
+
 func TestHandler_HandleAcceptActivityValidationError(t *testing.T) {
 	service1IRI := testutil.MustParseURL("http://localhost:8301/services/service1")
 	service2IRI := testutil.MustParseURL("http://localhost:8302/services/service2")
@@ -1979,6 +2345,226 @@ func TestHandler_HandleAcceptOfferActivity(t *testing.T) {
 	})
 }
 
+func TestHandler_HandleOfferActivityWithBatchedProofs(t *testing.T) {
+	service1IRI := testutil.MustParseURL("http://localhost:8301/services/service1")
+	service2IRI := testutil.MustParseURL("http://localhost:8302/services/service2")
+
+	cfg := &Config{
+		ServiceName:             "service2",
+		ServiceIRI:              service2IRI,
+		WitnessProofBatchWindow: time.Hour,
+		WitnessProofBatchSize:   2,
+		WitnessProofCacheSize:   10,
+	}
+
+	ob := servicemocks.NewOutbox().WithActivityID(testutil.NewMockID(service2IRI, "/activities/123456789"))
+	witness := servicemocks.NewWitnessHandler().WithProof([]byte(proof))
+
+	h := NewInbox(cfg, memstore.New(cfg.ServiceName), ob, servicemocks.NewActivitPubClient(), spi.WithWitness(witness))
+	require.NotNil(t, h)
+
+	h.Start()
+
+	newOffer := func() *vocab.ActivityType {
+		startTime := time.Now()
+		endTime := startTime.Add(time.Hour)
+
+		return vocab.NewOfferActivity(
+			vocab.NewObjectProperty(vocab.WithAnchorEvent(aptestutil.NewMockAnchorEvent(t))),
+			vocab.WithID(aptestutil.NewActivityID(service1IRI)),
+			vocab.WithActor(service1IRI),
+			vocab.WithTo(service2IRI),
+			vocab.WithStartTime(&startTime),
+			vocab.WithEndTime(&endTime),
+			vocab.WithTarget(vocab.NewObjectProperty(vocab.WithIRI(vocab.AnchorWitnessTargetIRI))),
+		)
+	}
+
+	require.NoError(t, h.HandleActivity(newOffer()))
+	require.Empty(t, ob.Activities())
+
+	require.NoError(t, h.HandleActivity(newOffer()))
+	require.Len(t, ob.Activities(), 1)
+
+	batch := ob.Activities()[0]
+	require.NotNil(t, batch.Object().Collection())
+	require.Len(t, batch.Object().Collection().Items(), 2)
+
+	// The same anchor credential was offered twice so the cached proof should have been used.
+	require.Len(t, witness.AnchorCreds(), 1)
+
+	require.NoError(t, h.HandleActivity(newOffer()))
+	require.Len(t, ob.Activities(), 1)
+
+	// Pending proofs are delivered when the handler is stopped.
+	h.Stop()
+
+	require.Len(t, ob.Activities(), 2)
+	require.Nil(t, ob.Activities()[1].Object().Collection())
+	require.True(t, ob.Activities()[1].Object().Type().Is(vocab.TypeOffer))
+}
+
+func TestHandler_HandleAcceptOfferBatch(t *testing.T) {
+	log.SetLevel("activitypub_service", log.WARNING)
+
+	service1IRI := testutil.MustParseURL("http://localhost:8301/services/service1")
+	service2IRI := testutil.MustParseURL("http://localhost:8302/services/service2")
+
+	cfg := &Config{
+		ServiceName: "service1",
+		ServiceIRI:  service1IRI,
+	}
+
+	proofHandler := servicemocks.NewProofHandler()
+
+	h := NewInbox(cfg, memstore.New(cfg.ServiceName), &servicemocks.Outbox{}, servicemocks.NewActivitPubClient(),
+		spi.WithProofHandler(proofHandler))
+	require.NotNil(t, h)
+
+	h.Start()
+	defer h.Stop()
+
+	result, err := vocab.NewObjectWithDocument(vocab.MustUnmarshalToDoc([]byte(proof)))
+	require.NoError(t, err)
+
+	startTime := time.Now()
+	endTime := startTime.Add(time.Hour)
+
+	newAcceptOffer := func(actor *url.URL) (*vocab.ActivityType, *vocab.AnchorEventType) {
+		anchorEvent := aptestutil.NewMockAnchorEvent(t)
+
+		offer := vocab.NewOfferActivity(
+			vocab.NewObjectProperty(vocab.WithAnchorEvent(anchorEvent)),
+			vocab.WithID(aptestutil.NewActivityID(service1IRI)),
+			vocab.WithActor(service1IRI),
+			vocab.WithTo(service2IRI),
+			vocab.WithStartTime(&startTime),
+			vocab.WithEndTime(&endTime),
+			vocab.WithTarget(vocab.NewObjectProperty(vocab.WithIRI(vocab.AnchorWitnessTargetIRI))),
+		)
+
+		// Make sure the activity is in our outbox or else it will fail check.
+		require.NoError(t, h.store.AddActivity(offer))
+		require.NoError(t, h.store.AddReference(store.Outbox, h.ServiceIRI, offer.ID().URL()))
+
+		return vocab.NewAcceptActivity(
+			vocab.NewObjectProperty(vocab.WithActivity(vocab.NewOfferActivity(
+				vocab.NewObjectProperty(vocab.WithIRI(anchorEvent.Index())),
+				vocab.WithID(offer.ID().URL()),
+				vocab.WithActor(offer.Actor()),
+				vocab.WithTo(offer.To()...),
+				vocab.WithTarget(offer.Target()),
+			))),
+			vocab.WithID(aptestutil.NewActivityID(service2IRI)),
+			vocab.WithTo(offer.Actor(), vocab.PublicIRI),
+			vocab.WithActor(actor),
+			vocab.WithResult(vocab.NewObjectProperty(
+				vocab.WithObject(vocab.NewObject(
+					vocab.WithType(vocab.TypeAnchorReceipt),
+					vocab.WithInReplyTo(anchorEvent.Index()),
+					vocab.WithStartTime(&startTime),
+					vocab.WithEndTime(&endTime),
+					vocab.WithAttachment(vocab.NewObjectProperty(vocab.WithObject(result))),
+				),
+				)),
+			),
+		), anchorEvent
+	}
+
+	newBatch := func(activities ...*vocab.ActivityType) *vocab.ActivityType {
+		items := make([]*vocab.ObjectProperty, len(activities))
+
+		for i, a := range activities {
+			items[i] = vocab.NewObjectProperty(vocab.WithActivity(a))
+		}
+
+		return vocab.NewAcceptActivity(
+			vocab.NewObjectProperty(vocab.WithCollection(vocab.NewCollection(items))),
+			vocab.WithID(aptestutil.NewActivityID(service2IRI)),
+			vocab.WithActor(service2IRI),
+			vocab.WithTo(service1IRI, vocab.PublicIRI),
+		)
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		accept1, anchorEvent1 := newAcceptOffer(service2IRI)
+		accept2, _ := newAcceptOffer(service2IRI)
+
+		batch := newBatch(accept1, accept2)
+
+		batchBytes, err := canonicalizer.MarshalCanonical(batch)
+		require.NoError(t, err)
+
+		batch = &vocab.ActivityType{}
+		require.NoError(t, batch.UnmarshalJSON(batchBytes))
+
+		require.NoError(t, h.HandleActivity(batch))
+		require.NotEmpty(t, proofHandler.Proof(anchorEvent1.Index().String()))
+	})
+
+	t.Run("HandleProof error", func(t *testing.T) {
+		errExpected := orberrors.NewTransient(fmt.Errorf("injected proof handler error"))
+
+		proofHandler.WithError(errExpected)
+		defer proofHandler.WithError(nil)
+
+		accept1, _ := newAcceptOffer(service2IRI)
+		accept2, _ := newAcceptOffer(service2IRI)
+
+		err := h.HandleActivity(newBatch(accept1, accept2))
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "injected proof handler error")
+	})
+
+	t.Run("No actor", func(t *testing.T) {
+		accept1, _ := newAcceptOffer(service2IRI)
+
+		batch := newBatch(accept1)
+		batch.SetActor(nil)
+
+		err := h.HandleActivity(batch)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "no actor specified")
+	})
+
+	t.Run("Empty batch", func(t *testing.T) {
+		err := h.HandleActivity(newBatch())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "no activities in batch")
+	})
+
+	t.Run("Actor mismatch", func(t *testing.T) {
+		accept1, _ := newAcceptOffer(service2IRI)
+		accept2, _ := newAcceptOffer(service1IRI)
+
+		err := h.HandleActivity(newBatch(accept1, accept2))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "must be the same as the actor of the batch")
+	})
+
+	t.Run("Not an 'Accept' activity", func(t *testing.T) {
+		err := h.HandleActivity(newBatch(vocab.NewFollowActivity(
+			vocab.NewObjectProperty(vocab.WithIRI(service1IRI)),
+			vocab.WithActor(service2IRI),
+		)))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "batch may only contain 'Accept' activities")
+	})
+
+	t.Run("Not an 'Accept' offer activity", func(t *testing.T) {
+		err := h.HandleActivity(newBatch(vocab.NewAcceptActivity(
+			vocab.NewObjectProperty(vocab.WithActivity(vocab.NewFollowActivity(
+				vocab.NewObjectProperty(vocab.WithIRI(service2IRI)),
+				vocab.WithActor(service1IRI),
+			))),
+			vocab.WithActor(service2IRI),
+		)))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "batch may only contain 'Accept' offer activities")
+	})
+}
+
 func TestHandler_HandleUndoFollowActivity(t *testing.T) {
 	service1IRI := testutil.MustParseURL("http://localhost:8301/services/service1")
 	service2IRI := testutil.MustParseURL("http://localhost:8302/services/service2")
@@ -3117,6 +3703,32 @@ func (l *mockActivitySubscriber) Activity(iri fmt.Stringer) *vocab.ActivityType
 	return l.activities[iri.String()]
 }
 
+type mockWitnessConfirmationListener struct {
+	mutex     sync.Mutex
+	confirmed []*url.URL
+	err       error
+}
+
+func (m *mockWitnessConfirmationListener) WitnessConfirmed(witness *url.URL) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.err != nil {
+		return m.err
+	}
+
+	m.confirmed = append(m.confirmed, witness)
+
+	return nil
+}
+
+func (m *mockWitnessConfirmationListener) Confirmed() []*url.URL {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.confirmed
+}
+
 type stopFunc func()
 
 func startInboxOutboxWithMocks(t *testing.T, inboxServiceIRI,
@@ -3199,3 +3811,36 @@ func TestAcceptAllActorsAuth_AuthorizeActor(t *testing.T) {
 	require.NoError(t, err)
 	require.True(t, ok)
 }
+
+func requireProvenance(t *testing.T, activityStore store.Store, anchorRef, activityID *url.URL) {
+	t.Helper()
+
+	anchorIRI, err := provenance.AnchorIRI(anchorRef.String())
+	require.NoError(t, err)
+
+	it, err := activityStore.QueryReferences(store.Provenance, store.NewCriteria(store.WithObjectIRI(anchorIRI)))
+	require.NoError(t, err)
+
+	refs, err := storeutil.ReadReferences(it, -1)
+	require.NoError(t, err)
+	require.Contains(t, refs, activityID)
+}
+
+type mockRejectionRecorder struct {
+	mutex      sync.Mutex
+	rejections []*rejection.Rejection
+}
+
+func (m *mockRejectionRecorder) Record(r *rejection.Rejection) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.rejections = append(m.rejections, r)
+}
+
+func (m *mockRejectionRecorder) get() []*rejection.Rejection {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.rejections
+}
@@ -7,12 +7,18 @@ SPDX-License-Identifier: Apache-2.0
 package activityhandler
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/url"
 	"time"
 
+	"github.com/bluele/gcache"
+
+	"github.com/trustbloc/orb/pkg/activitypub/activitytemplate"
+	"github.com/trustbloc/orb/pkg/activitypub/rejection"
 	"github.com/trustbloc/orb/pkg/activitypub/resthandler"
 	service "github.com/trustbloc/orb/pkg/activitypub/service/spi"
 	store "github.com/trustbloc/orb/pkg/activitypub/store/spi"
@@ -30,6 +36,8 @@ type Inbox struct {
 
 	outbox       service.Outbox
 	followersIRI *url.URL
+	proofCache   gcache.Cache
+	proofBatcher *proofBatcher
 }
 
 // NewInbox returns a new ActivityPub inbox activity handler.
@@ -67,9 +75,31 @@ func NewInbox(cfg *Config, s store.Store, outbox service.Outbox,
 		h.inboxUndoLike,
 	)
 
+	if cfg.WitnessProofCacheSize > 0 {
+		h.proofCache = gcache.New(cfg.WitnessProofCacheSize).ARC().Expiration(cfg.WitnessProofCacheExpiration).Build()
+	}
+
+	if cfg.WitnessProofBatchWindow > 0 {
+		logger.Infof("[%s] Witness proofs will be batched with window %s and maximum batch size %d",
+			cfg.ServiceName, cfg.WitnessProofBatchWindow, cfg.WitnessProofBatchSize)
+
+		h.proofBatcher = newProofBatcher(cfg.ServiceName, cfg.ServiceIRI, cfg.WitnessProofBatchWindow,
+			cfg.WitnessProofBatchSize, outbox.Post)
+	}
+
 	return h
 }
 
+// Stop delivers any pending (batched) witness proofs and stops the handler. The handler must
+// be stopped before the outbox so that the pending proofs may be posted.
+func (h *Inbox) Stop() {
+	if h.proofBatcher != nil {
+		h.proofBatcher.flushAll()
+	}
+
+	h.handler.Stop()
+}
+
 // HandleActivity handles the ActivityPub activity in the inbox.
 //nolint:cyclop
 func (h *Inbox) HandleActivity(activity *vocab.ActivityType) error {
@@ -95,7 +125,11 @@ func (h *Inbox) HandleActivity(activity *vocab.ActivityType) error {
 	case typeProp.Is(vocab.TypeUndo):
 		return h.handleUndoActivity(activity)
 	default:
-		return fmt.Errorf("unsupported activity type: %s", typeProp.Types())
+		err := fmt.Errorf("unsupported activity type: %s", typeProp.Types())
+
+		h.recordRejection(activity, rejection.ReasonUnsupportedType, err.Error())
+
+		return err
 	}
 }
 
@@ -139,6 +173,8 @@ func (h *Inbox) handleEmbeddedAnchorEvent(create *vocab.ActivityType,
 		return fmt.Errorf("error handling 'Create' activity [%s]: %w", create.ID(), err)
 	}
 
+	h.addProvenance(anchorEvent.URL()[0], create)
+
 	if announce {
 		if err := h.announceAnchorEvent(create); err != nil {
 			logger.Warnf("[%s] Unable to announce 'Create' activity [%s] to our followers: %s",
@@ -154,6 +190,8 @@ func (h *Inbox) handleAnchorEventRef(create *vocab.ActivityType, anchorEventURL
 		return fmt.Errorf("error handling 'Create' activity [%s]: %w", create.ID(), err)
 	}
 
+	h.addProvenance(anchorEventURL, create)
+
 	if announce {
 		if err := h.announceAnchorEventRef(create); err != nil {
 			logger.Warnf("[%s] Unable to announce 'Create' activity [%s] to our followers: %s",
@@ -200,15 +238,133 @@ func (h *Inbox) handleReferenceActivity(activity *vocab.ActivityType, refType st
 	if accept {
 		logger.Debugf("[%s] Request for %s to activity %s has been accepted", h.ServiceName, h.ServiceIRI, actor.ID())
 
-		return h.acceptActor(activity, actor, refType)
+		if err := h.acceptActor(activity, actor, refType); err != nil {
+			return err
+		}
+
+		if refType == store.Witnessing {
+			h.reciprocateWitnessInvitation(activity, actorIRI)
+		}
+
+		return nil
+	}
+
+	if holder, ok := auth.(service.ApprovalHolder); ok && refType == store.Follower && holder.HoldForApproval(actor) {
+		return h.holdForApproval(activity)
 	}
 
 	logger.Debugf("[%s] Request for %s to activity %s has been rejected. Replying with 'Reject' activity",
 		h.ServiceName, actorIRI, h.ServiceIRI)
 
+	h.recordRejection(activity, rejection.ReasonNotInAcceptList,
+		fmt.Sprintf("actor is not authorized to add %s to its %s collection", h.ServiceIRI, refType))
+
 	return h.postReject(activity, actorIRI)
 }
 
+func (h *Inbox) recordRejection(activity *vocab.ActivityType, reason rejection.Reason, detail string) {
+	if h.Rejections == nil {
+		return
+	}
+
+	r := &rejection.Rejection{
+		Reason:       reason,
+		ActivityID:   activity.ID().String(),
+		ActivityType: activity.Type().String(),
+		Detail:       detail,
+	}
+
+	if activity.Actor() != nil {
+		r.Actor = activity.Actor().String()
+	}
+
+	h.Rejections.Record(r)
+}
+
+func (h *Inbox) holdForApproval(follow *vocab.ActivityType) error {
+	logger.Infof("[%s] Holding 'Follow' activity [%s] from %s for approval", h.ServiceName, follow.ID(), follow.Actor())
+
+	err := h.store.AddReference(store.PendingFollow, h.ServiceIRI, follow.ID().URL(),
+		store.WithActivityType(vocab.TypeFollow))
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("unable to store pending follow: %w", err))
+	}
+
+	return nil
+}
+
+// ApproveFollow approves the given 'Follow' activity which was held for approval. The actor is added to the
+// service's followers and an 'Accept' activity is posted to the actor.
+func (h *Inbox) ApproveFollow(followID *url.URL) error {
+	follow, err := h.getPendingFollow(followID)
+	if err != nil {
+		return err
+	}
+
+	actor, err := h.client.GetActor(follow.Actor())
+	if err != nil {
+		return fmt.Errorf("unable to retrieve actor [%s]: %w", follow.Actor(), err)
+	}
+
+	logger.Infof("[%s] 'Follow' activity [%s] from %s has been approved", h.ServiceName, followID, follow.Actor())
+
+	err = h.acceptActor(follow, actor, store.Follower)
+	if err != nil {
+		return err
+	}
+
+	return h.deletePendingFollow(followID)
+}
+
+// RejectFollow rejects the given 'Follow' activity which was held for approval. A 'Reject' activity is posted
+// to the actor.
+func (h *Inbox) RejectFollow(followID *url.URL) error {
+	follow, err := h.getPendingFollow(followID)
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("[%s] 'Follow' activity [%s] from %s has been rejected", h.ServiceName, followID, follow.Actor())
+
+	err = h.postReject(follow, follow.Actor())
+	if err != nil {
+		return err
+	}
+
+	return h.deletePendingFollow(followID)
+}
+
+func (h *Inbox) getPendingFollow(followID *url.URL) (*vocab.ActivityType, error) {
+	pending, err := h.hasReference(h.ServiceIRI, followID, store.PendingFollow)
+	if err != nil {
+		return nil, err
+	}
+
+	if !pending {
+		return nil, fmt.Errorf("'Follow' activity [%s] is not pending approval: %w", followID, store.ErrNotFound)
+	}
+
+	follow, err := h.store.GetActivity(followID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, fmt.Errorf("get 'Follow' activity [%s]: %w", followID, err)
+		}
+
+		return nil, orberrors.NewTransient(fmt.Errorf("get 'Follow' activity [%s]: %w", followID, err))
+	}
+
+	return follow, nil
+}
+
+func (h *Inbox) deletePendingFollow(followID *url.URL) error {
+	err := h.store.DeleteReference(store.PendingFollow, h.ServiceIRI, followID)
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("unable to delete pending follow [%s]: %w", followID, err))
+	}
+
+	return nil
+}
+
 func (h *Inbox) handleFollowActivity(follow *vocab.ActivityType) error {
 	return h.handleReferenceActivity(follow, store.Follower, h.FollowerAuth,
 		func() *url.URL {
@@ -235,6 +391,99 @@ func (h *Inbox) handleInviteActivity(invite *vocab.ActivityType) error {
 	return fmt.Errorf("unsupported object type for 'Invite' activity: %s", object)
 }
 
+// reciprocateWitnessInvitation sends an 'InviteWitness' (and optionally a 'Follow') to the actor of an accepted
+// 'InviteWitness' according to the reciprocation policy. The reciprocal invitation references the original
+// invitation (using 'inReplyTo') so that the other service doesn't reciprocate it in turn. Errors are logged
+// since the original invitation has already been accepted.
+func (h *Inbox) reciprocateWitnessInvitation(invite *vocab.ActivityType, actorIRI *url.URL) {
+	policy := h.WitnessReciprocation
+
+	if policy == nil || (!policy.InviteWitness && !policy.Follow) {
+		return
+	}
+
+	if invite.InReplyTo() != nil {
+		logger.Debugf("[%s] Not reciprocating 'InviteWitness' [%s] from %s since it is a reciprocal invitation",
+			h.ServiceName, invite.ID(), actorIRI)
+
+		return
+	}
+
+	if policy.InviteWitness {
+		if err := h.postReciprocalInvite(invite, actorIRI); err != nil {
+			logger.Warnf("[%s] Unable to reciprocate 'InviteWitness' [%s] from %s: %s",
+				h.ServiceName, invite.ID(), actorIRI, err)
+		}
+	}
+
+	if policy.Follow {
+		if err := h.postReciprocalFollow(actorIRI); err != nil {
+			logger.Warnf("[%s] Unable to follow %s after accepting 'InviteWitness' [%s]: %s",
+				h.ServiceName, actorIRI, invite.ID(), err)
+		}
+	}
+}
+
+func (h *Inbox) postReciprocalInvite(invite *vocab.ActivityType, actorIRI *url.URL) error {
+	isWitness, err := h.hasReference(h.ServiceIRI, actorIRI, store.Witness)
+	if err != nil {
+		return err
+	}
+
+	if isWitness {
+		logger.Debugf("[%s] Not sending reciprocal 'InviteWitness' to %s since it is already a witness",
+			h.ServiceName, actorIRI)
+
+		return nil
+	}
+
+	reciprocalInvite := vocab.NewInviteActivity(
+		vocab.NewObjectProperty(vocab.WithIRI(vocab.AnchorWitnessTargetIRI)),
+		vocab.WithTarget(vocab.NewObjectProperty(vocab.WithIRI(actorIRI))),
+		vocab.WithActor(h.ServiceIRI),
+		vocab.WithTo(actorIRI),
+		vocab.WithInReplyTo(invite.ID().URL()),
+	)
+
+	logger.Infof("[%s] Sending reciprocal 'InviteWitness' to %s", h.ServiceName, actorIRI)
+
+	if _, err := h.outbox.Post(reciprocalInvite); err != nil {
+		return orberrors.NewTransient(fmt.Errorf("post 'InviteWitness' to %s: %w", actorIRI, err))
+	}
+
+	return nil
+}
+
+func (h *Inbox) postReciprocalFollow(actorIRI *url.URL) error {
+	isFollowing, err := h.hasReference(h.ServiceIRI, actorIRI, store.Following)
+	if err != nil {
+		return err
+	}
+
+	if isFollowing {
+		logger.Debugf("[%s] Not sending 'Follow' to %s since it is already being followed", h.ServiceName, actorIRI)
+
+		return nil
+	}
+
+	published := time.Now()
+
+	follow := vocab.NewFollowActivity(
+		vocab.NewObjectProperty(vocab.WithIRI(actorIRI)),
+		vocab.WithActor(h.ServiceIRI),
+		vocab.WithTo(actorIRI),
+		vocab.WithPublishedTime(&published),
+	)
+
+	logger.Infof("[%s] Sending 'Follow' to %s after accepting its 'InviteWitness'", h.ServiceName, actorIRI)
+
+	if _, err := h.outbox.Post(follow); err != nil {
+		return orberrors.NewTransient(fmt.Errorf("post 'Follow' to %s: %w", actorIRI, err))
+	}
+
+	return nil
+}
+
 func (h *Inbox) validateActivity(activity *vocab.ActivityType, getTargetIRI func() *url.URL) error {
 	if activity.Actor() == nil {
 		return fmt.Errorf("no actor specified")
@@ -266,6 +515,10 @@ func (h *Inbox) acceptActor(activity *vocab.ActivityType, actor *vocab.ActorType
 func (h *Inbox) handleAcceptActivity(accept *vocab.ActivityType) error {
 	logger.Debugf("[%s] Handling 'Accept' activity: %s", h.ServiceName, accept.ID())
 
+	if accept.Object().Collection() != nil {
+		return h.handleAcceptProofBatch(accept)
+	}
+
 	if err := h.validateAcceptRejectActivity(accept); err != nil {
 		return err
 	}
@@ -373,6 +626,10 @@ func (h *Inbox) handleAcceptInviteActivity(accept *vocab.ActivityType) error {
 	}
 
 	if objectIRI.String() == vocab.AnchorWitnessTargetIRI.String() {
+		if h.WitnessConfirmation != nil {
+			return h.handleAcceptWitnessRenewal(accept)
+		}
+
 		err := h.handleAccept(accept, store.Witness)
 		if err != nil {
 			return fmt.Errorf("handle accept 'Invite' witness activity %s: %w", accept.ID(), err)
@@ -384,6 +641,32 @@ func (h *Inbox) handleAcceptInviteActivity(accept *vocab.ActivityType) error {
 	return fmt.Errorf("unsupported object for accept 'Invite' activity: %s", objectIRI)
 }
 
+// handleAcceptWitnessRenewal adds the actor to the witnesses collection (if not already added) and notifies
+// the witness confirmation listener. An 'Accept' from an existing witness confirms (renews) the relationship.
+func (h *Inbox) handleAcceptWitnessRenewal(accept *vocab.ActivityType) error {
+	exists, err := h.hasReference(h.ServiceIRI, accept.Actor(), store.Witness)
+	if err != nil {
+		return fmt.Errorf("query '%s' for actor %s: %w", store.Witness, accept.Actor(), err)
+	}
+
+	if !exists {
+		err = h.store.AddReference(store.Witness, h.ServiceIRI, accept.Actor())
+		if err != nil {
+			return orberrors.NewTransient(fmt.Errorf("handle accept 'Invite' witness activity %s: %w",
+				accept.ID(), err))
+		}
+	} else {
+		logger.Debugf("[%s] Witness %s renewed the witness relationship", h.ServiceName, accept.Actor())
+	}
+
+	err = h.WitnessConfirmation.WitnessConfirmed(accept.Actor())
+	if err != nil {
+		return fmt.Errorf("notify witness confirmation for %s: %w", accept.Actor(), err)
+	}
+
+	return nil
+}
+
 func (h *Inbox) postAccept(activity *vocab.ActivityType, toIRI *url.URL) error {
 	acceptActivity := vocab.NewAcceptActivity(
 		vocab.NewObjectProperty(vocab.WithActivity(activity)),
@@ -493,40 +776,19 @@ func (h *Inbox) handleOfferActivity(offer *vocab.ActivityType) error {
 		return fmt.Errorf("get witness document for 'Offer' activity [%s]: %w", offer.ID(), err)
 	}
 
-	result, err := h.witnessAnchorCredential(witnessDoc)
+	result, err := h.getWitnessProof(witnessDoc)
 	if err != nil {
 		return fmt.Errorf("error creating result for 'Offer' activity [%s]: %w", offer.ID(), err)
 	}
 
-	startTime := time.Now()
-	endTime := startTime.Add(h.MaxWitnessDelay)
-
-	// Create a new offer activity with only the bare essentials to return in the 'Accept'.
-	oa := vocab.NewOfferActivity(
-		vocab.NewObjectProperty(vocab.WithIRI(anchorEvent.Index())),
-		vocab.WithID(offer.ID().URL()),
-		vocab.WithActor(offer.Actor()),
-		vocab.WithTo(offer.To()...),
-		vocab.WithTarget(offer.Target()),
-	)
-
-	accept := vocab.NewAcceptActivity(
-		vocab.NewObjectProperty(vocab.WithActivity(oa)),
-		vocab.WithTo(oa.Actor(), vocab.PublicIRI),
-		vocab.WithResult(vocab.NewObjectProperty(
-			vocab.WithObject(vocab.NewObject(
-				vocab.WithType(vocab.TypeAnchorReceipt),
-				vocab.WithInReplyTo(anchorEvent.Index()),
-				vocab.WithStartTime(&startTime),
-				vocab.WithEndTime(&endTime),
-				vocab.WithAttachment(vocab.NewObjectProperty(vocab.WithObject(result))),
-			),
-			),
-		)),
-	)
-
-	_, err = h.outbox.Post(accept)
+	accept, err := activitytemplate.NewProofAccept(offer, result, h.MaxWitnessDelay)
 	if err != nil {
+		return fmt.Errorf("create 'Accept' for 'Offer' activity [%s]: %w", offer.ID(), err)
+	}
+
+	if h.proofBatcher != nil {
+		h.proofBatcher.add(offer.Actor(), accept)
+	} else if _, err = h.outbox.Post(accept); err != nil {
 		return orberrors.NewTransient(fmt.Errorf("unable to reply with 'Like' to %s for offer [%s]: %w",
 			offer.Actor(), offer.ID(), err))
 	}
@@ -575,6 +837,82 @@ func (h *Inbox) handleAcceptOfferActivity(accept, offer *vocab.ActivityType) err
 	return nil
 }
 
+// handleAcceptProofBatch handles an 'Accept' activity whose object is a collection of 'Accept' offer
+// activities (witness proofs) that were batched by the witness. Each of the batched activities is handled
+// as if it was received individually. All of the batched activities must be from the same actor as the batch.
+func (h *Inbox) handleAcceptProofBatch(batch *vocab.ActivityType) error {
+	logger.Debugf("[%s] Handling batch of 'Accept' offer activities: %s", h.ServiceName, batch.ID())
+
+	accepts, err := h.validateAcceptProofBatch(batch)
+	if err != nil {
+		return fmt.Errorf("invalid batch of 'Accept' offer activities [%s]: %w", batch.ID(), err)
+	}
+
+	var (
+		firstErr  error
+		transient bool
+	)
+
+	for _, accept := range accepts {
+		if e := h.handleAcceptActivity(accept); e != nil {
+			logger.Warnf("[%s] Error handling 'Accept' offer activity [%s] in batch [%s]: %s",
+				h.ServiceName, accept.ID(), batch.ID(), e)
+
+			if firstErr == nil {
+				firstErr = e
+			}
+
+			transient = transient || orberrors.IsTransient(e)
+		}
+	}
+
+	if firstErr == nil {
+		return nil
+	}
+
+	err = fmt.Errorf("handle batch of 'Accept' offer activities [%s]: %w", batch.ID(), firstErr)
+
+	if transient {
+		// The whole batch is redelivered. Proofs that were already handled are handled again, which is harmless.
+		return orberrors.NewTransient(err)
+	}
+
+	return err
+}
+
+func (h *Inbox) validateAcceptProofBatch(batch *vocab.ActivityType) ([]*vocab.ActivityType, error) {
+	if batch.Actor() == nil {
+		return nil, errors.New("no actor specified")
+	}
+
+	items := batch.Object().Collection().Items()
+	if len(items) == 0 {
+		return nil, errors.New("no activities in batch")
+	}
+
+	accepts := make([]*vocab.ActivityType, len(items))
+
+	for i, item := range items {
+		accept := item.Activity()
+
+		if accept == nil || !accept.Type().Is(vocab.TypeAccept) {
+			return nil, errors.New("batch may only contain 'Accept' activities")
+		}
+
+		if accept.Actor() == nil || accept.Actor().String() != batch.Actor().String() {
+			return nil, errors.New("the actor of a batched activity must be the same as the actor of the batch")
+		}
+
+		if !accept.Object().Type().Is(vocab.TypeOffer) {
+			return nil, errors.New("batch may only contain 'Accept' offer activities")
+		}
+
+		accepts[i] = accept
+	}
+
+	return accepts, nil
+}
+
 func (h *Inbox) handleAnchorEvent(actor *url.URL, anchorEvent *vocab.AnchorEventType) error {
 	anchorEventRef := anchorEvent.URL()[0]
 
@@ -693,6 +1031,8 @@ func (h *Inbox) handleAnnounceCollection(announce *vocab.ActivityType, items []*
 			logger.Warnf("[%s] Error adding 'Announce' activity %s to 'shares' of anchor event %s: %s",
 				h.ServiceIRI, announce.ID(), anchorEventID, err)
 		}
+
+		h.addProvenance(anchorEventID, announce)
 	}
 
 	return nil
@@ -730,25 +1070,7 @@ func (h *Inbox) handleLikeActivity(like *vocab.ActivityType) error {
 }
 
 func (h *Inbox) announceAnchorEvent(create *vocab.ActivityType) error {
-	anchorEvent := create.Object().AnchorEvent()
-
-	published := time.Now()
-
-	announce := vocab.NewAnnounceActivity(
-		vocab.NewObjectProperty(
-			vocab.WithCollection(
-				vocab.NewCollection(
-					[]*vocab.ObjectProperty{
-						vocab.NewObjectProperty(
-							vocab.WithAnchorEvent(anchorEvent),
-						),
-					},
-				),
-			),
-		),
-		vocab.WithTo(h.followersIRI, vocab.PublicIRI),
-		vocab.WithPublishedTime(&published),
-	)
+	announce := activitytemplate.NewAnchorAnnounce(h.followersIRI, create.Object().AnchorEvent())
 
 	if _, err := h.outbox.Post(announce); err != nil {
 		return orberrors.NewTransient(err)
@@ -764,23 +1086,7 @@ func (h *Inbox) announceAnchorEventRef(create *vocab.ActivityType) error {
 
 	anchorEventURL := create.Object().AnchorEvent().URL()[0]
 
-	published := time.Now()
-
-	announce := vocab.NewAnnounceActivity(
-		vocab.NewObjectProperty(
-			vocab.WithCollection(
-				vocab.NewCollection(
-					[]*vocab.ObjectProperty{
-						vocab.NewObjectProperty(
-							vocab.WithURL(anchorEventURL),
-						),
-					},
-				),
-			),
-		),
-		vocab.WithTo(h.followersIRI, vocab.PublicIRI),
-		vocab.WithPublishedTime(&published),
-	)
+	announce := activitytemplate.NewAnchorRefAnnounce(h.followersIRI, anchorEventURL)
 
 	activityID, err := h.outbox.Post(announce)
 	if err != nil {
@@ -882,12 +1188,42 @@ func (h *Inbox) validateLikeActivity(like *vocab.ActivityType) error {
 	return nil
 }
 
-func (h *Inbox) witnessAnchorCredential(vc vocab.Document) (*vocab.ObjectType, error) {
+// getWitnessProof returns the witness proof for the given anchor credential. If the same anchor credential
+// was recently witnessed (for example, the 'Offer' was redelivered) then the cached proof is returned.
+func (h *Inbox) getWitnessProof(vc vocab.Document) (*vocab.ObjectType, error) {
 	bytes, err := json.Marshal(vc)
 	if err != nil {
 		return nil, fmt.Errorf("unable to marshal object in 'Offer' activity: %w", err)
 	}
 
+	if h.proofCache == nil {
+		return h.witnessAnchorCredential(bytes)
+	}
+
+	digest := sha256.Sum256(bytes)
+	key := hex.EncodeToString(digest[:])
+
+	cached, err := h.proofCache.Get(key)
+	if err == nil {
+		logger.Debugf("[%s] Using cached witness proof for anchor credential [%s]", h.ServiceName, key)
+
+		return cached.(*vocab.ObjectType), nil
+	}
+
+	result, err := h.witnessAnchorCredential(bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	err = h.proofCache.Set(key, result)
+	if err != nil {
+		logger.Warnf("[%s] Unable to cache witness proof for anchor credential [%s]: %s", h.ServiceName, key, err)
+	}
+
+	return result, nil
+}
+
+func (h *Inbox) witnessAnchorCredential(bytes []byte) (*vocab.ObjectType, error) {
 	response, err := h.Witness.Witness(bytes)
 	if err != nil {
 		return nil, err
@@ -88,6 +88,8 @@ func (h *handler) handleCreateActivity(create *vocab.ActivityType) error {
 		return orberrors.NewTransient(fmt.Errorf("store anchor event reference: %w", err))
 	}
 
+	h.addProvenance(anchorEvent.URL()[0], create)
+
 	return nil
 }
 
@@ -129,5 +131,7 @@ func (h *handler) handleLikeActivity(like *vocab.ActivityType) error {
 		return orberrors.NewTransient(fmt.Errorf("add anchor reference to 'Liked' collection: %w", err))
 	}
 
+	h.addProvenance(ref.URL()[0], like)
+
 	return nil
 }
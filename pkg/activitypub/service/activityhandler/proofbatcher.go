@@ -0,0 +1,168 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package activityhandler
+
+import (
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+)
+
+type postFunc func(activity *vocab.ActivityType) (*url.URL, error)
+
+// proofBatcher collects the 'Accept' offer activities (witness proofs) that are destined for the same
+// anchor origin and delivers them in a single 'Accept' activity whose object is a collection of the
+// individual 'Accept' activities. A batch is delivered when the batch window expires or when the maximum
+// batch size is reached, whichever comes first. A batch that contains only one proof is delivered as a
+// regular 'Accept' activity.
+type proofBatcher struct {
+	serviceName string
+	serviceIRI  *url.URL
+	window      time.Duration
+	maxSize     int
+	post        postFunc
+
+	mutex   sync.Mutex
+	batches map[string]*proofBatch
+}
+
+type proofBatch struct {
+	origin  *url.URL
+	accepts []*vocab.ActivityType
+	timer   *time.Timer
+}
+
+func newProofBatcher(serviceName string, serviceIRI *url.URL, window time.Duration, maxSize int,
+	post postFunc) *proofBatcher {
+	return &proofBatcher{
+		serviceName: serviceName,
+		serviceIRI:  serviceIRI,
+		window:      window,
+		maxSize:     maxSize,
+		post:        post,
+		batches:     make(map[string]*proofBatch),
+	}
+}
+
+// add adds the given 'Accept' offer activity to the batch for the given anchor origin.
+func (b *proofBatcher) add(origin *url.URL, accept *vocab.ActivityType) {
+	key := origin.String()
+
+	b.mutex.Lock()
+
+	batch, ok := b.batches[key]
+	if !ok {
+		batch = &proofBatch{origin: origin}
+
+		b.batches[key] = batch
+
+		batch.timer = time.AfterFunc(b.window, func() {
+			b.flush(key, batch)
+		})
+	}
+
+	batch.accepts = append(batch.accepts, accept)
+
+	if len(batch.accepts) < b.maxSize {
+		b.mutex.Unlock()
+
+		return
+	}
+
+	batch.timer.Stop()
+
+	delete(b.batches, key)
+
+	b.mutex.Unlock()
+
+	logger.Debugf("[%s] Maximum batch size reached for proofs to [%s]", b.serviceName, origin)
+
+	b.deliver(batch)
+}
+
+// flushAll delivers all pending batches.
+func (b *proofBatcher) flushAll() {
+	b.mutex.Lock()
+
+	batches := b.batches
+
+	b.batches = make(map[string]*proofBatch)
+
+	b.mutex.Unlock()
+
+	for _, batch := range batches {
+		batch.timer.Stop()
+
+		b.deliver(batch)
+	}
+}
+
+func (b *proofBatcher) flush(key string, batch *proofBatch) {
+	b.mutex.Lock()
+
+	current, ok := b.batches[key]
+	if !ok || current != batch {
+		// The batch has already been delivered.
+		b.mutex.Unlock()
+
+		return
+	}
+
+	delete(b.batches, key)
+
+	b.mutex.Unlock()
+
+	b.deliver(batch)
+}
+
+func (b *proofBatcher) deliver(batch *proofBatch) {
+	activity := batch.accepts[0]
+
+	if len(batch.accepts) > 1 {
+		activity = b.newBatch(batch)
+	}
+
+	logger.Debugf("[%s] Delivering %d proof(s) to [%s]", b.serviceName, len(batch.accepts), batch.origin)
+
+	if _, err := b.post(activity); err != nil {
+		logger.Errorf("[%s] Unable to deliver %d proof(s) to [%s]: %s",
+			b.serviceName, len(batch.accepts), batch.origin, err)
+	}
+}
+
+func (b *proofBatcher) newBatch(batch *proofBatch) *vocab.ActivityType {
+	items := make([]*vocab.ObjectProperty, len(batch.accepts))
+
+	for i, accept := range batch.accepts {
+		// The outbox only populates the ID and actor of the top-level activity so these
+		// fields are populated here for each of the batched activities.
+		accept.SetID(b.newActivityID())
+		accept.SetActor(b.serviceIRI)
+
+		items[i] = vocab.NewObjectProperty(vocab.WithActivity(accept))
+	}
+
+	return vocab.NewAcceptActivity(
+		vocab.NewObjectProperty(vocab.WithCollection(vocab.NewCollection(items))),
+		vocab.WithTo(batch.origin, vocab.PublicIRI),
+	)
+}
+
+func (b *proofBatcher) newActivityID() *url.URL {
+	id, err := url.Parse(fmt.Sprintf("%s/activities/%s", b.serviceIRI, uuid.New()))
+	if err != nil {
+		// Should never happen since we've already validated the URLs
+		panic(err)
+	}
+
+	return id
+}
@@ -0,0 +1,111 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package activityhandler
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	servicemocks "github.com/trustbloc/orb/pkg/activitypub/service/mocks"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+)
+
+func TestProofBatcher(t *testing.T) {
+	service1IRI := testutil.MustParseURL("http://localhost:8301/services/service1")
+	service2IRI := testutil.MustParseURL("http://localhost:8302/services/service2")
+	service3IRI := testutil.MustParseURL("http://localhost:8303/services/service3")
+
+	t.Run("Maximum batch size reached", func(t *testing.T) {
+		ob := servicemocks.NewOutbox()
+
+		b := newProofBatcher("service2", service2IRI, time.Hour, 2, ob.Post)
+
+		b.add(service1IRI, newMockAccept(service1IRI))
+		b.add(service3IRI, newMockAccept(service3IRI))
+
+		require.Empty(t, ob.Activities())
+
+		b.add(service1IRI, newMockAccept(service1IRI))
+
+		require.Len(t, ob.Activities(), 1)
+
+		batch := ob.Activities()[0]
+		require.True(t, batch.Type().Is(vocab.TypeAccept))
+		require.Equal(t, service1IRI.String(), batch.To()[0].String())
+
+		coll := batch.Object().Collection()
+		require.NotNil(t, coll)
+		require.Len(t, coll.Items(), 2)
+
+		for _, item := range coll.Items() {
+			accept := item.Activity()
+			require.NotNil(t, accept)
+			require.NotNil(t, accept.ID())
+			require.Equal(t, service2IRI.String(), accept.Actor().String())
+		}
+
+		b.flushAll()
+
+		require.Len(t, ob.Activities(), 2)
+
+		// A batch with only one proof is delivered as is.
+		require.Nil(t, ob.Activities()[1].Object().Collection())
+		require.Equal(t, service3IRI.String(), ob.Activities()[1].To()[0].String())
+	})
+
+	t.Run("Batch window expired", func(t *testing.T) {
+		ob := servicemocks.NewOutbox()
+
+		b := newProofBatcher("service2", service2IRI, 50*time.Millisecond, 10, ob.Post)
+
+		b.add(service1IRI, newMockAccept(service1IRI))
+		b.add(service1IRI, newMockAccept(service1IRI))
+		b.add(service1IRI, newMockAccept(service1IRI))
+
+		require.Empty(t, ob.Activities())
+
+		time.Sleep(200 * time.Millisecond)
+
+		require.Len(t, ob.Activities(), 1)
+		require.Len(t, ob.Activities()[0].Object().Collection().Items(), 3)
+
+		// Nothing else should be delivered.
+		b.flushAll()
+
+		require.Len(t, ob.Activities(), 1)
+	})
+
+	t.Run("Post error", func(t *testing.T) {
+		ob := servicemocks.NewOutbox().WithError(errors.New("injected post error"))
+
+		b := newProofBatcher("service2", service2IRI, time.Hour, 1, ob.Post)
+
+		require.NotPanics(t, func() {
+			b.add(service1IRI, newMockAccept(service1IRI))
+		})
+
+		require.Empty(t, ob.Activities())
+	})
+}
+
+func newMockAccept(origin *url.URL) *vocab.ActivityType {
+	anchorsIRI := testutil.MustParseURL("hl:uEiAsiwjaXOYDmOHxmvDl3Mx0TfJ0uCar5YXqumjFJUNIBg")
+
+	return vocab.NewAcceptActivity(
+		vocab.NewObjectProperty(vocab.WithActivity(vocab.NewOfferActivity(
+			vocab.NewObjectProperty(vocab.WithIRI(anchorsIRI)),
+			vocab.WithActor(origin),
+			vocab.WithTarget(vocab.NewObjectProperty(vocab.WithIRI(vocab.AnchorWitnessTargetIRI))),
+		))),
+		vocab.WithTo(origin, vocab.PublicIRI),
+	)
+}
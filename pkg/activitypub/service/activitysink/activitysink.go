@@ -0,0 +1,292 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package activitysink
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	store "github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/store/storeutil"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+)
+
+var logger = log.New("activity_sink")
+
+const (
+	taskName         = "activity-sink"
+	defaultInterval  = 10 * time.Second
+	defaultBatchSize = 100
+)
+
+// collections contains the ActivityPub collections that are replicated to the sink.
+var collections = []store.ReferenceType{store.Inbox, store.Outbox}
+
+// Event is a replication event that's published to the sink for each activity in the inbox and outbox.
+// Since delivery is at-least-once, consumers should use the ID (or the collection and sequence) to
+// de-duplicate events.
+type Event struct {
+	// ID is the ID of the activity.
+	ID string `json:"id"`
+	// Type is the type of the activity (e.g. Create, Announce, Follow).
+	Type string `json:"type"`
+	// Collection is the collection (INBOX or OUTBOX) that contains the activity.
+	Collection string `json:"collection"`
+	// Sequence is the position of the activity within the collection.
+	Sequence int `json:"sequence"`
+	// Actor is the actor that posted the activity.
+	Actor string `json:"actor,omitempty"`
+	// Published is the time that the activity was published.
+	Published *time.Time `json:"published,omitempty"`
+	// Anchors contains the hashlinks of the anchors in a Create or Announce activity.
+	Anchors []string `json:"anchors,omitempty"`
+	// Activity is the full activity.
+	Activity *vocab.ActivityType `json:"activity"`
+}
+
+type publisher interface {
+	Publish(events []*Event) error
+}
+
+type taskManager interface {
+	RegisterTask(taskType string, interval time.Duration, task func())
+}
+
+// Config contains configuration parameters for the activity sink task.
+type Config struct {
+	ServiceIRI *url.URL
+	Interval   time.Duration
+	// BatchSize is the maximum number of events that are published in a single call to the publisher.
+	BatchSize int
+}
+
+// task replicates the activities in the inbox and outbox of a service to an external sink (e.g. a data
+// warehouse). The activities are read from the ActivityPub store in the order in which they were added and
+// are published in batches. The position within each collection is checkpointed only after a batch has been
+// successfully published, so an event is re-published if the checkpoint fails to be saved (at-least-once
+// delivery).
+type task struct {
+	serviceIRI       *url.URL
+	batchSize        int
+	publisher        publisher
+	activityPubStore store.Store
+	store            *checkpointStore
+}
+
+// Register registers the activity sink task.
+func Register(cfg Config, taskMgr taskManager, pub publisher, apStore store.Store,
+	storageProvider storage.Provider) error {
+	s, err := newCheckpointStore(storageProvider)
+	if err != nil {
+		return fmt.Errorf("create checkpoint store: %w", err)
+	}
+
+	interval := cfg.Interval
+
+	if interval == 0 {
+		interval = defaultInterval
+	}
+
+	batchSize := cfg.BatchSize
+
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+
+	t := &task{
+		serviceIRI:       cfg.ServiceIRI,
+		batchSize:        batchSize,
+		publisher:        pub,
+		activityPubStore: apStore,
+		store:            s,
+	}
+
+	logger.Infof("Registering activity-sink task - ServiceIRI: %s, Interval: %s, Batch size: %d.",
+		cfg.ServiceIRI, interval, batchSize)
+
+	taskMgr.RegisterTask(taskName, interval, t.run)
+
+	return nil
+}
+
+func (t *task) run() {
+	for _, refType := range collections {
+		n, err := t.replicate(refType)
+		if err != nil {
+			logger.Warnf("Error replicating %s activities to the sink: %s", refType, err)
+		}
+
+		if n > 0 {
+			logger.Infof("Replicated %d %s activities to the sink", n, refType)
+		}
+	}
+}
+
+func (t *task) replicate(refType store.ReferenceType) (int, error) {
+	var total int
+
+	for {
+		n, err := t.replicateBatch(refType)
+		if err != nil {
+			return total, err
+		}
+
+		if n == 0 {
+			return total, nil
+		}
+
+		total += n
+	}
+}
+
+func (t *task) replicateBatch(refType store.ReferenceType) (int, error) {
+	offset, err := t.store.Get(refType)
+	if err != nil {
+		return 0, fmt.Errorf("get checkpoint: %w", err)
+	}
+
+	refs, err := t.getReferences(refType, offset)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(refs) == 0 {
+		return 0, nil
+	}
+
+	events := make([]*Event, 0, len(refs))
+
+	for i, ref := range refs {
+		a, e := t.activityPubStore.GetActivity(ref)
+		if e != nil {
+			if errors.Is(e, store.ErrNotFound) {
+				logger.Warnf("Activity [%s] in %s not found in the ActivityPub store. Skipping.", ref, refType)
+
+				continue
+			}
+
+			return 0, fmt.Errorf("get activity [%s]: %w", ref, e)
+		}
+
+		events = append(events, newEvent(refType, offset+i, a))
+	}
+
+	if len(events) > 0 {
+		err = t.publisher.Publish(events)
+		if err != nil {
+			return 0, fmt.Errorf("publish %d events at offset %d: %w", len(events), offset, err)
+		}
+	}
+
+	err = t.store.Put(refType, offset+len(refs))
+	if err != nil {
+		return 0, fmt.Errorf("update checkpoint: %w", err)
+	}
+
+	logger.Debugf("Published %d %s events at offset %d", len(events), refType, offset)
+
+	return len(refs), nil
+}
+
+// getReferences returns up to a batch of references in the given collection starting at the given offset.
+func (t *task) getReferences(refType store.ReferenceType, offset int) ([]*url.URL, error) {
+	it, err := t.activityPubStore.QueryReferences(refType, store.NewCriteria(store.WithObjectIRI(t.serviceIRI)),
+		store.WithSortOrder(store.SortAscending),
+		store.WithPageSize(t.batchSize),
+		store.WithPageNum(offset/t.batchSize),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query %s: %w", refType, err)
+	}
+
+	defer func() {
+		if errClose := it.Close(); errClose != nil {
+			logger.Warnf("Error closing iterator: %s", errClose)
+		}
+	}()
+
+	refs, err := storeutil.ReadReferences(it, t.batchSize)
+	if err != nil {
+		return nil, fmt.Errorf("read %s references: %w", refType, err)
+	}
+
+	// The checkpoint may be in the middle of a page if the previous batch was partial.
+	skip := offset % t.batchSize
+
+	if skip >= len(refs) {
+		return nil, nil
+	}
+
+	return refs[skip:], nil
+}
+
+func newEvent(refType store.ReferenceType, sequence int, a *vocab.ActivityType) *Event {
+	e := &Event{
+		ID:         a.ID().String(),
+		Type:       a.Type().String(),
+		Collection: string(refType),
+		Sequence:   sequence,
+		Published:  a.Published(),
+		Anchors:    anchors(a),
+		Activity:   a,
+	}
+
+	if a.Actor() != nil {
+		e.Actor = a.Actor().String()
+	}
+
+	return e
+}
+
+func anchors(a *vocab.ActivityType) []string {
+	obj := a.Object()
+
+	if obj == nil {
+		return nil
+	}
+
+	switch {
+	case a.Type().Is(vocab.TypeCreate):
+		return anchorURLs(obj)
+
+	case a.Type().Is(vocab.TypeAnnounce):
+		var items []*vocab.ObjectProperty
+
+		switch {
+		case obj.Collection() != nil:
+			items = obj.Collection().Items()
+		case obj.OrderedCollection() != nil:
+			items = obj.OrderedCollection().Items()
+		}
+
+		var urls []string
+
+		for _, item := range items {
+			urls = append(urls, anchorURLs(item)...)
+		}
+
+		return urls
+
+	default:
+		return nil
+	}
+}
+
+func anchorURLs(obj *vocab.ObjectProperty) []string {
+	anchorEvent := obj.AnchorEvent()
+
+	if anchorEvent == nil || len(anchorEvent.URL()) == 0 {
+		return nil
+	}
+
+	return []string{anchorEvent.URL()[0].String()}
+}
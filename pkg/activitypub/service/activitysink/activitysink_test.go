@@ -0,0 +1,268 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package activitysink
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/service/mocks"
+	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
+	store "github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/internal/aptestutil"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+)
+
+var (
+	serviceIRI  = testutil.MustParseURL("https://domain1.com/services/orb")
+	service2IRI = testutil.MustParseURL("https://domain2.com/services/orb")
+)
+
+func TestRegister(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		require.NoError(t, Register(Config{}, mocks.NewTaskManager("activity-sink"), &mockPublisher{},
+			memstore.New("service1"), storage.NewMockStoreProvider()))
+	})
+
+	t.Run("Open store error", func(t *testing.T) {
+		p := storage.NewMockStoreProvider()
+
+		errExpected := errors.New("injected open store error")
+
+		p.ErrOpenStoreHandle = errExpected
+
+		err := Register(Config{}, mocks.NewTaskManager("activity-sink"), &mockPublisher{},
+			memstore.New("service1"), p)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errExpected.Error())
+	})
+}
+
+func TestTask(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		apStore := memstore.New("service1")
+
+		inbox := addActivities(t, apStore, store.Inbox, newMockActivities(t, 5))
+		outbox := addActivities(t, apStore, store.Outbox, newMockActivities(t, 3))
+
+		pub := &mockPublisher{}
+
+		tsk := newTestTask(t, apStore, pub, 2)
+
+		tsk.run()
+
+		require.Len(t, pub.events, len(inbox)+len(outbox))
+		require.Equal(t, 5, pub.numCalls)
+
+		for i, e := range pub.events[:len(inbox)] {
+			require.Equal(t, inbox[i].ID().String(), e.ID)
+			require.Equal(t, string(store.Inbox), e.Collection)
+			require.Equal(t, i, e.Sequence)
+			require.Equal(t, service2IRI.String(), e.Actor)
+		}
+
+		require.NotNil(t, pub.events[0].Published)
+
+		require.Len(t, pub.events[0].Anchors, 1)
+		require.Len(t, pub.events[1].Anchors, 2)
+		require.Empty(t, pub.events[2].Anchors)
+
+		for i, e := range pub.events[len(inbox):] {
+			require.Equal(t, outbox[i].ID().String(), e.ID)
+			require.Equal(t, string(store.Outbox), e.Collection)
+			require.Equal(t, i, e.Sequence)
+		}
+
+		// Nothing new to publish.
+		tsk.run()
+
+		require.Len(t, pub.events, len(inbox)+len(outbox))
+
+		// Publish new activities starting from the checkpoint.
+		inbox = append(inbox, addActivities(t, apStore, store.Inbox, newMockActivities(t, 2))...)
+
+		tsk.run()
+
+		require.Len(t, pub.events, len(inbox)+len(outbox))
+
+		last := pub.events[len(pub.events)-1]
+		require.Equal(t, inbox[len(inbox)-1].ID().String(), last.ID)
+		require.Equal(t, len(inbox)-1, last.Sequence)
+	})
+
+	t.Run("Publish error -> retried", func(t *testing.T) {
+		apStore := memstore.New("service1")
+
+		inbox := addActivities(t, apStore, store.Inbox, newMockActivities(t, 3))
+
+		pub := &mockPublisher{err: errors.New("injected publish error")}
+
+		tsk := newTestTask(t, apStore, pub, 10)
+
+		tsk.run()
+
+		require.Empty(t, pub.events)
+
+		offset, err := tsk.store.Get(store.Inbox)
+		require.NoError(t, err)
+		require.Equal(t, 0, offset)
+
+		pub.err = nil
+
+		tsk.run()
+
+		require.Len(t, pub.events, len(inbox))
+	})
+
+	t.Run("Checkpoint error -> events re-published", func(t *testing.T) {
+		apStore := memstore.New("service1")
+
+		inbox := addActivities(t, apStore, store.Inbox, newMockActivities(t, 3))
+
+		pub := &mockPublisher{}
+
+		sp := storage.NewMockStoreProvider()
+		sp.Store.ErrPut = errors.New("injected put error")
+
+		s, err := newCheckpointStore(sp)
+		require.NoError(t, err)
+
+		tsk := newTestTask(t, apStore, pub, 10)
+		tsk.store = s
+
+		tsk.run()
+
+		require.Len(t, pub.events, len(inbox))
+
+		sp.Store.ErrPut = nil
+
+		tsk.run()
+
+		require.Len(t, pub.events, 2*len(inbox))
+	})
+
+	t.Run("Activity not found -> skipped", func(t *testing.T) {
+		apStore := memstore.New("service1")
+
+		inbox := addActivities(t, apStore, store.Inbox, newMockActivities(t, 2))
+
+		missingIRI := testutil.MustParseURL("https://domain2.com/activities/missing")
+
+		require.NoError(t, apStore.AddReference(store.Inbox, serviceIRI, missingIRI))
+
+		pub := &mockPublisher{}
+
+		tsk := newTestTask(t, apStore, pub, 10)
+
+		tsk.run()
+
+		require.Len(t, pub.events, len(inbox))
+
+		offset, err := tsk.store.Get(store.Inbox)
+		require.NoError(t, err)
+		require.Equal(t, len(inbox)+1, offset)
+	})
+
+	t.Run("Checkpoint get error", func(t *testing.T) {
+		apStore := memstore.New("service1")
+
+		addActivities(t, apStore, store.Inbox, newMockActivities(t, 2))
+
+		sp := storage.NewMockStoreProvider()
+		sp.Store.ErrGet = errors.New("injected get error")
+
+		s, err := newCheckpointStore(sp)
+		require.NoError(t, err)
+
+		pub := &mockPublisher{}
+
+		tsk := newTestTask(t, apStore, pub, 10)
+		tsk.store = s
+
+		tsk.run()
+
+		require.Empty(t, pub.events)
+	})
+}
+
+func newTestTask(t *testing.T, apStore store.Store, pub publisher, batchSize int) *task {
+	t.Helper()
+
+	s, err := newCheckpointStore(storage.NewMockStoreProvider())
+	require.NoError(t, err)
+
+	return &task{
+		serviceIRI:       serviceIRI,
+		batchSize:        batchSize,
+		publisher:        pub,
+		activityPubStore: apStore,
+		store:            s,
+	}
+}
+
+// newMockActivities returns a Create activity with one anchor, an Announce activity with two anchors
+// followed by Follow activities up to the given number.
+func newMockActivities(t *testing.T, num int) []*vocab.ActivityType {
+	t.Helper()
+
+	activities := []*vocab.ActivityType{
+		aptestutil.NewMockCreateActivity(service2IRI, serviceIRI,
+			vocab.NewObjectProperty(vocab.WithAnchorEvent(aptestutil.NewMockAnchorEventRef(t))),
+		),
+		aptestutil.NewMockAnnounceActivity(service2IRI, serviceIRI,
+			vocab.NewObjectProperty(vocab.WithCollection(vocab.NewCollection(
+				[]*vocab.ObjectProperty{
+					vocab.NewObjectProperty(vocab.WithAnchorEvent(aptestutil.NewMockAnchorEventRef(t))),
+					vocab.NewObjectProperty(vocab.WithAnchorEvent(aptestutil.NewMockAnchorEventRef(t))),
+				},
+			))),
+		),
+	}
+
+	for len(activities) < num {
+		activities = append(activities, vocab.NewFollowActivity(
+			vocab.NewObjectProperty(vocab.WithIRI(serviceIRI)),
+			vocab.WithID(aptestutil.NewActivityID(service2IRI)),
+			vocab.WithActor(service2IRI),
+		))
+	}
+
+	return activities[:num]
+}
+
+func addActivities(t *testing.T, apStore store.Store, refType store.ReferenceType,
+	activities []*vocab.ActivityType) []*vocab.ActivityType {
+	t.Helper()
+
+	for _, a := range activities {
+		require.NoError(t, apStore.AddActivity(a))
+		require.NoError(t, apStore.AddReference(refType, serviceIRI, a.ID().URL()))
+	}
+
+	return activities
+}
+
+type mockPublisher struct {
+	events   []*Event
+	numCalls int
+	err      error
+}
+
+func (m *mockPublisher) Publish(events []*Event) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	m.numCalls++
+	m.events = append(m.events, events...)
+
+	return nil
+}
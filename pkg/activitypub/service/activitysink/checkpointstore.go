@@ -0,0 +1,80 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package activitysink
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+
+	store "github.com/trustbloc/orb/pkg/activitypub/store/spi"
+)
+
+const storeName = "activity-sink"
+
+// checkpointStore stores the number of activities in each collection that have been published to the sink.
+type checkpointStore struct {
+	store     storage.Store
+	marshal   func(v interface{}) ([]byte, error)
+	unmarshal func(data []byte, v interface{}) error
+}
+
+func newCheckpointStore(storageProvider storage.Provider) (*checkpointStore, error) {
+	s, err := storageProvider.OpenStore(storeName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open activity-sink store: %w", err)
+	}
+
+	return &checkpointStore{
+		store:     s,
+		marshal:   json.Marshal,
+		unmarshal: json.Unmarshal,
+	}, nil
+}
+
+type checkpoint struct {
+	Offset int `json:"offset"`
+}
+
+// Get returns the offset of the next activity to be published for the given collection. Zero is returned
+// if no activities have been published.
+func (s *checkpointStore) Get(refType store.ReferenceType) (int, error) {
+	cpBytes, err := s.store.Get(string(refType))
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return 0, nil
+		}
+
+		return 0, fmt.Errorf("get from DB: %w", err)
+	}
+
+	cp := &checkpoint{}
+
+	err = s.unmarshal(cpBytes, cp)
+	if err != nil {
+		return 0, fmt.Errorf("unmarshal checkpoint [%s]: %w", cpBytes, err)
+	}
+
+	return cp.Offset, nil
+}
+
+// Put saves the offset of the next activity to be published for the given collection.
+func (s *checkpointStore) Put(refType store.ReferenceType, offset int) error {
+	cpBytes, err := s.marshal(&checkpoint{Offset: offset})
+	if err != nil {
+		return fmt.Errorf("marshal checkpoint: %w", err)
+	}
+
+	err = s.store.Put(string(refType), cpBytes)
+	if err != nil {
+		return fmt.Errorf("put to DB [%s]: %w", cpBytes, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package activitysink
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/stretchr/testify/require"
+
+	store "github.com/trustbloc/orb/pkg/activitypub/store/spi"
+)
+
+func TestCheckpointStore(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		s, err := newCheckpointStore(storage.NewMockStoreProvider())
+		require.NoError(t, err)
+
+		offset, err := s.Get(store.Inbox)
+		require.NoError(t, err)
+		require.Equal(t, 0, offset)
+
+		require.NoError(t, s.Put(store.Inbox, 10))
+		require.NoError(t, s.Put(store.Outbox, 5))
+
+		offset, err = s.Get(store.Inbox)
+		require.NoError(t, err)
+		require.Equal(t, 10, offset)
+
+		offset, err = s.Get(store.Outbox)
+		require.NoError(t, err)
+		require.Equal(t, 5, offset)
+	})
+
+	t.Run("Open store error", func(t *testing.T) {
+		p := storage.NewMockStoreProvider()
+
+		errExpected := errors.New("injected open store error")
+
+		p.ErrOpenStoreHandle = errExpected
+
+		_, err := newCheckpointStore(p)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errExpected.Error())
+	})
+
+	t.Run("Get error", func(t *testing.T) {
+		p := storage.NewMockStoreProvider()
+
+		errExpected := errors.New("injected get error")
+
+		p.Store.ErrGet = errExpected
+
+		s, err := newCheckpointStore(p)
+		require.NoError(t, err)
+
+		_, err = s.Get(store.Inbox)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errExpected.Error())
+	})
+
+	t.Run("Put error", func(t *testing.T) {
+		p := storage.NewMockStoreProvider()
+
+		errExpected := errors.New("injected put error")
+
+		p.Store.ErrPut = errExpected
+
+		s, err := newCheckpointStore(p)
+		require.NoError(t, err)
+
+		err = s.Put(store.Inbox, 1)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errExpected.Error())
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		s, err := newCheckpointStore(storage.NewMockStoreProvider())
+		require.NoError(t, err)
+
+		errExpected := errors.New("injected marshal error")
+
+		s.marshal = func(v interface{}) ([]byte, error) { return nil, errExpected }
+
+		err = s.Put(store.Inbox, 1)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errExpected.Error())
+	})
+
+	t.Run("Unmarshal error", func(t *testing.T) {
+		s, err := newCheckpointStore(storage.NewMockStoreProvider())
+		require.NoError(t, err)
+
+		require.NoError(t, s.Put(store.Inbox, 1))
+
+		errExpected := errors.New("injected unmarshal error")
+
+		s.unmarshal = func(data []byte, v interface{}) error { return errExpected }
+
+		_, err = s.Get(store.Inbox)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errExpected.Error())
+	})
+}
@@ -0,0 +1,89 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package activitysink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+const defaultWebhookTimeout = 10 * time.Second
+
+type httpClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// WebhookPublisher publishes replication events as a JSON array to an HTTP endpoint. The endpoint is expected
+// to return a 2xx status code once the events have been durably stored, otherwise the batch is retried.
+type WebhookPublisher struct {
+	url        string
+	httpClient httpClient
+	timeout    time.Duration
+	marshal    func(v interface{}) ([]byte, error)
+}
+
+// NewWebhookPublisher returns a new webhook publisher. If the timeout is zero then a default timeout is used.
+func NewWebhookPublisher(url string, client httpClient, timeout time.Duration) *WebhookPublisher {
+	if timeout == 0 {
+		timeout = defaultWebhookTimeout
+	}
+
+	return &WebhookPublisher{
+		url:        url,
+		httpClient: client,
+		timeout:    timeout,
+		marshal:    json.Marshal,
+	}
+}
+
+// Publish posts the given events to the webhook.
+func (p *WebhookPublisher) Publish(events []*Event) error {
+	eventsBytes, err := p.marshal(events)
+	if err != nil {
+		return fmt.Errorf("marshal events: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(eventsBytes))
+	if err != nil {
+		return fmt.Errorf("create request for webhook [%s]: %w", p.url, err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("post events to webhook [%s]: %w", p.url, err))
+	}
+
+	defer func() {
+		if errClose := resp.Body.Close(); errClose != nil {
+			logger.Warnf("Error closing response body from webhook [%s]: %s", p.url, errClose)
+		}
+	}()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		respBytes, e := ioutil.ReadAll(resp.Body)
+		if e != nil {
+			logger.Debugf("Error reading response body from webhook [%s]: %s", p.url, e)
+		}
+
+		return orberrors.NewTransient(fmt.Errorf("webhook [%s] returned status code %d: %s",
+			p.url, resp.StatusCode, respBytes))
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package activitysink
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+func TestWebhookPublisher(t *testing.T) {
+	events := []*Event{
+		{ID: "https://domain2.com/activities/1", Type: "Create", Collection: "INBOX", Sequence: 0},
+		{ID: "https://domain2.com/activities/2", Type: "Announce", Collection: "INBOX", Sequence: 1},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		var received []*Event
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodPost, r.Method)
+			require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+			body, err := ioutil.ReadAll(r.Body)
+			require.NoError(t, err)
+			require.NoError(t, json.Unmarshal(body, &received))
+
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer srv.Close()
+
+		p := NewWebhookPublisher(srv.URL, http.DefaultClient, 0)
+
+		require.NoError(t, p.Publish(events))
+		require.Len(t, received, len(events))
+		require.Equal(t, events[1].ID, received[1].ID)
+	})
+
+	t.Run("Error status code", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer srv.Close()
+
+		p := NewWebhookPublisher(srv.URL, http.DefaultClient, 0)
+
+		err := p.Publish(events)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "returned status code 500")
+	})
+
+	t.Run("HTTP client error", func(t *testing.T) {
+		errExpected := errors.New("injected HTTP error")
+
+		p := NewWebhookPublisher("https://sink.example.com/events", &mockHTTPClient{err: errExpected}, 0)
+
+		err := p.Publish(events)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), errExpected.Error())
+	})
+
+	t.Run("Invalid URL", func(t *testing.T) {
+		p := NewWebhookPublisher(string([]byte{0x7f}), http.DefaultClient, 0)
+
+		err := p.Publish(events)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "create request")
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		errExpected := errors.New("injected marshal error")
+
+		p := NewWebhookPublisher("https://sink.example.com/events", http.DefaultClient, 0)
+		p.marshal = func(v interface{}) ([]byte, error) { return nil, errExpected }
+
+		err := p.Publish(events)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errExpected.Error())
+	})
+}
+
+type mockHTTPClient struct {
+	err error
+}
+
+func (m *mockHTTPClient) Do(*http.Request) (*http.Response, error) {
+	return nil, m.err
+}
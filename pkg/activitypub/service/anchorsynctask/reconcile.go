@@ -0,0 +1,210 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package anchorsynctask
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+
+	"github.com/trustbloc/orb/pkg/activitypub/anchordigest"
+	"github.com/trustbloc/orb/pkg/activitypub/service/spi"
+	store "github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+)
+
+const (
+	reconcileTaskName        = "anchor-reconcile"
+	defaultReconcileDays     = 7
+	defaultReconcileInterval = time.Hour
+)
+
+type digestClient interface {
+	GetDigest(serviceIRI *url.URL, since time.Time) (*anchordigest.Digest, error)
+	GetDayActivities(serviceIRI *url.URL, date string) (*anchordigest.DayActivities, error)
+	GetActivity(activityIRI *url.URL) (*vocab.ActivityType, error)
+}
+
+// ReconcileConfig contains configuration parameters for the anchor reconciliation task.
+type ReconcileConfig struct {
+	ServiceIRI *url.URL
+	Interval   time.Duration
+	// Days is the number of days (including the current day) that are reconciled with each followed service.
+	Days int
+}
+
+// reconcileTask compares the anchor digest of each of the services that this service is following with the
+// digest of the anchors that were previously reconciled. For each day whose digest has changed, the IDs of
+// the anchor activities are retrieved and any activity that hasn't been processed is retrieved and processed.
+// This repairs gaps that result from activities that were never delivered to (or were lost by) the inbox.
+type reconcileTask struct {
+	*task
+
+	client digestClient
+	store  *reconcileStore
+	days   int
+}
+
+// RegisterReconcile registers the anchor reconciliation task.
+func RegisterReconcile(cfg ReconcileConfig, taskMgr taskManager, client digestClient, apStore store.Store,
+	storageProvider storage.Provider, handlerFactory func() spi.InboxHandler) error {
+	s, err := newReconcileStore(storageProvider)
+	if err != nil {
+		return fmt.Errorf("create reconcile store: %w", err)
+	}
+
+	interval := cfg.Interval
+
+	if interval == 0 {
+		interval = defaultReconcileInterval
+	}
+
+	days := cfg.Days
+
+	if days <= 0 {
+		days = defaultReconcileDays
+	}
+
+	t := &reconcileTask{
+		task: &task{
+			serviceIRI:       cfg.ServiceIRI,
+			activityPubStore: apStore,
+			getHandler:       handlerFactory,
+		},
+		client: client,
+		store:  s,
+		days:   days,
+	}
+
+	logger.Infof("Registering anchor-reconcile task - ServiceIRI: %s, Interval: %s, Days: %d.",
+		cfg.ServiceIRI, interval, days)
+
+	taskMgr.RegisterTask(reconcileTaskName, interval, t.run)
+
+	return nil
+}
+
+func (m *reconcileTask) run() {
+	following, err := m.getFollowing()
+	if err != nil {
+		logger.Errorf("Error retrieving my following list: %s", err)
+
+		return
+	}
+
+	since := time.Now().AddDate(0, 0, -(m.days - 1))
+
+	for _, serviceIRI := range following {
+		numRepaired, e := m.reconcile(serviceIRI, since)
+		if e != nil {
+			logger.Warnf("Error reconciling anchors with service [%s]: %s", serviceIRI, e)
+		}
+
+		if numRepaired > 0 {
+			logger.Infof("Repaired %d missing anchor events from service [%s]", numRepaired, serviceIRI)
+		}
+	}
+}
+
+func (m *reconcileTask) reconcile(serviceIRI *url.URL, since time.Time) (int, error) {
+	digest, err := m.client.GetDigest(serviceIRI, since)
+	if err != nil {
+		return 0, fmt.Errorf("get digest: %w", err)
+	}
+
+	var numRepaired int
+
+	for _, day := range digest.Days {
+		root, e := m.store.GetRoot(serviceIRI, day.Date)
+		if e != nil {
+			return numRepaired, fmt.Errorf("get reconciled root for [%s]: %w", day.Date, e)
+		}
+
+		if root == day.Root {
+			logger.Debugf("Anchors from service [%s] for [%s] are already reconciled", serviceIRI, day.Date)
+
+			continue
+		}
+
+		logger.Debugf("Digest of anchors from service [%s] for [%s] has changed. Reconciling %d anchors.",
+			serviceIRI, day.Date, day.Count)
+
+		n, e := m.reconcileDay(serviceIRI, day.Date)
+
+		numRepaired += n
+
+		if e != nil {
+			return numRepaired, fmt.Errorf("reconcile anchors for [%s]: %w", day.Date, e)
+		}
+	}
+
+	return numRepaired, nil
+}
+
+func (m *reconcileTask) reconcileDay(serviceIRI *url.URL, date string) (int, error) {
+	dayActivities, err := m.client.GetDayActivities(serviceIRI, date)
+	if err != nil {
+		return 0, fmt.Errorf("get activities: %w", err)
+	}
+
+	if anchordigest.MerkleRoot(dayActivities.Activities) != dayActivities.Root {
+		return 0, fmt.Errorf("root of activity IDs does not match the root in the response")
+	}
+
+	var numRepaired int
+
+	for _, id := range dayActivities.Activities {
+		repaired, e := m.reconcileActivity(serviceIRI, id)
+		if e != nil {
+			return numRepaired, e
+		}
+
+		if repaired {
+			numRepaired++
+		}
+	}
+
+	err = m.store.PutRoot(serviceIRI, date, dayActivities.Root)
+	if err != nil {
+		return numRepaired, fmt.Errorf("store reconciled root: %w", err)
+	}
+
+	return numRepaired, nil
+}
+
+func (m *reconcileTask) reconcileActivity(serviceIRI *url.URL, id string) (bool, error) {
+	activityIRI, err := url.Parse(id)
+	if err != nil {
+		return false, fmt.Errorf("parse activity ID [%s]: %w", id, err)
+	}
+
+	_, err = m.activityPubStore.GetActivity(activityIRI)
+	if err == nil {
+		return false, nil
+	}
+
+	if !errors.Is(err, store.ErrNotFound) {
+		return false, fmt.Errorf("get activity [%s]: %w", id, err)
+	}
+
+	logger.Debugf("Activity [%s] from service [%s] is missing. Retrieving it.", id, serviceIRI)
+
+	a, err := m.client.GetActivity(activityIRI)
+	if err != nil {
+		return false, fmt.Errorf("retrieve activity [%s]: %w", id, err)
+	}
+
+	// Ensure that the service isn't attempting to inject an activity that it didn't publish.
+	if a.ID().String() != id || a.Actor() == nil || a.Actor().String() != serviceIRI.String() {
+		return false, fmt.Errorf("activity [%s] was not published by service [%s]", id, serviceIRI)
+	}
+
+	return m.syncActivity(serviceIRI, a)
+}
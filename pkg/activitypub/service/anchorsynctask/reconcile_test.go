@@ -0,0 +1,272 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package anchorsynctask
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/anchordigest"
+	"github.com/trustbloc/orb/pkg/activitypub/service/mocks"
+	"github.com/trustbloc/orb/pkg/activitypub/service/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
+	spi2 "github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+)
+
+func TestRegisterReconcile(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		require.NoError(t, RegisterReconcile(
+			ReconcileConfig{},
+			mocks.NewTaskManager("anchor-reconcile"), &mockDigestClient{},
+			memstore.New("service1"), storage.NewMockStoreProvider(),
+			func() spi.InboxHandler {
+				return nil
+			},
+		))
+	})
+
+	t.Run("Open store error", func(t *testing.T) {
+		p := storage.NewMockStoreProvider()
+
+		errExpected := errors.New("injected open store error")
+
+		p.ErrOpenStoreHandle = errExpected
+
+		err := RegisterReconcile(
+			ReconcileConfig{},
+			mocks.NewTaskManager("anchor-reconcile"), &mockDigestClient{},
+			memstore.New("service1"), p,
+			func() spi.InboxHandler {
+				return nil
+			},
+		)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errExpected.Error())
+	})
+}
+
+func TestReconcileTask(t *testing.T) {
+	serviceIRI := testutil.MustParseURL("https://domain1.com/services/orb")
+	service2IRI := testutil.MustParseURL("https://domain2.com/services/orb")
+	service3IRI := testutil.MustParseURL("https://domain3.com/services/orb")
+
+	const date = "2021-06-01"
+
+	a1 := newMockAnchorActivity(service2IRI, service2IRI, 1)
+	a2 := newMockAnchorActivity(service2IRI, service2IRI, 2)
+	a3 := newMockAnchorActivity(service2IRI, service2IRI, 3)
+
+	ids := []string{a1.ID().String(), a2.ID().String(), a3.ID().String()}
+
+	newClient := func() *mockDigestClient {
+		return &mockDigestClient{
+			digest: &anchordigest.Digest{
+				Days: []*anchordigest.DayDigest{
+					{Date: date, Count: len(ids), Root: anchordigest.MerkleRoot(ids)},
+				},
+			},
+			dayActivities: &anchordigest.DayActivities{
+				Date:       date,
+				Root:       anchordigest.MerkleRoot(ids),
+				Activities: ids,
+			},
+			activities: []*vocab.ActivityType{a1, a2, a3},
+		}
+	}
+
+	newReconcileTask := func(t *testing.T, client digestClient, apStore spi2.Store,
+		handler spi.InboxHandler) *reconcileTask {
+		t.Helper()
+
+		s, err := newReconcileStore(storage.NewMockStoreProvider())
+		require.NoError(t, err)
+
+		return &reconcileTask{
+			task: &task{
+				serviceIRI:       serviceIRI,
+				activityPubStore: apStore,
+				getHandler: func() spi.InboxHandler {
+					return handler
+				},
+			},
+			client: client,
+			store:  s,
+			days:   defaultReconcileDays,
+		}
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		apStore := memstore.New("service1")
+
+		require.NoError(t, apStore.AddReference(spi2.Following, serviceIRI, service2IRI))
+		require.NoError(t, apStore.AddActivity(a1)) // This activity was already processed.
+
+		handler := &mockHandler{}
+		client := newClient()
+
+		task := newReconcileTask(t, client, apStore, handler)
+
+		task.run()
+
+		require.Len(t, handler.activities, 2)
+		require.Equal(t, 1, client.numDayRequests)
+
+		root, err := task.store.GetRoot(service2IRI, date)
+		require.NoError(t, err)
+		require.Equal(t, anchordigest.MerkleRoot(ids), root)
+
+		// The digest hasn't changed so the day shouldn't be reconciled again.
+		task.run()
+
+		require.Len(t, handler.activities, 2)
+		require.Equal(t, 1, client.numDayRequests)
+	})
+
+	t.Run("Invalid root", func(t *testing.T) {
+		apStore := memstore.New("service1")
+
+		require.NoError(t, apStore.AddReference(spi2.Following, serviceIRI, service2IRI))
+
+		handler := &mockHandler{}
+		client := newClient()
+		client.dayActivities.Activities = ids[:2]
+
+		task := newReconcileTask(t, client, apStore, handler)
+
+		n, err := task.reconcile(service2IRI, time.Now())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "root of activity IDs does not match")
+		require.Zero(t, n)
+		require.Empty(t, handler.activities)
+	})
+
+	t.Run("Activity not published by service", func(t *testing.T) {
+		apStore := memstore.New("service1")
+
+		handler := &mockHandler{}
+		client := newClient()
+		client.activities = []*vocab.ActivityType{newMockAnchorActivity(service2IRI, service3IRI, 1)}
+
+		task := newReconcileTask(t, client, apStore, handler)
+
+		_, err := task.reconcile(service2IRI, time.Now())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "was not published by service")
+		require.Empty(t, handler.activities)
+
+		root, err := task.store.GetRoot(service2IRI, date)
+		require.NoError(t, err)
+		require.Empty(t, root)
+	})
+
+	t.Run("Client errors", func(t *testing.T) {
+		errExpected := errors.New("injected client error")
+
+		apStore := memstore.New("service1")
+
+		client := newClient()
+		client.digestErr = errExpected
+
+		_, err := newReconcileTask(t, client, apStore, &mockHandler{}).reconcile(service2IRI, time.Now())
+		require.True(t, errors.Is(err, errExpected))
+
+		client = newClient()
+		client.dayErr = errExpected
+
+		_, err = newReconcileTask(t, client, apStore, &mockHandler{}).reconcile(service2IRI, time.Now())
+		require.True(t, errors.Is(err, errExpected))
+
+		client = newClient()
+		client.activityErr = errExpected
+
+		_, err = newReconcileTask(t, client, apStore, &mockHandler{}).reconcile(service2IRI, time.Now())
+		require.True(t, errors.Is(err, errExpected))
+	})
+
+	t.Run("Handler error", func(t *testing.T) {
+		errExpected := errors.New("injected handler error")
+
+		n, err := newReconcileTask(t, newClient(), memstore.New("service1"), &mockHandler{err: errExpected}).
+			reconcile(service2IRI, time.Now())
+		require.True(t, errors.Is(err, errExpected))
+		require.Zero(t, n)
+	})
+
+	t.Run("Get following error", func(t *testing.T) {
+		s := &mocks.ActivityStore{}
+		s.QueryReferencesReturns(nil, errors.New("injected query error"))
+
+		handler := &mockHandler{}
+		client := newClient()
+
+		newReconcileTask(t, client, s, handler).run()
+
+		require.Empty(t, handler.activities)
+		require.Zero(t, client.numDayRequests)
+	})
+}
+
+func newMockAnchorActivity(serviceIRI, actorIRI *url.URL, num int) *vocab.ActivityType {
+	published := time.Now()
+
+	return vocab.NewCreateActivity(
+		vocab.NewObjectProperty(vocab.WithIRI(testutil.MustParseURL("https://example.com/object"))),
+		vocab.WithID(testutil.NewMockID(serviceIRI, fmt.Sprintf("/activities/%d", num))),
+		vocab.WithActor(actorIRI),
+		vocab.WithPublishedTime(&published),
+	)
+}
+
+type mockDigestClient struct {
+	digest         *anchordigest.Digest
+	dayActivities  *anchordigest.DayActivities
+	activities     []*vocab.ActivityType
+	digestErr      error
+	dayErr         error
+	activityErr    error
+	numDayRequests int
+}
+
+func (m *mockDigestClient) GetDigest(*url.URL, time.Time) (*anchordigest.Digest, error) {
+	if m.digestErr != nil {
+		return nil, m.digestErr
+	}
+
+	return m.digest, nil
+}
+
+func (m *mockDigestClient) GetDayActivities(*url.URL, string) (*anchordigest.DayActivities, error) {
+	if m.dayErr != nil {
+		return nil, m.dayErr
+	}
+
+	m.numDayRequests++
+
+	return m.dayActivities, nil
+}
+
+func (m *mockDigestClient) GetActivity(activityIRI *url.URL) (*vocab.ActivityType, error) {
+	if m.activityErr != nil {
+		return nil, m.activityErr
+	}
+
+	for _, a := range m.activities {
+		if a.ID().String() == activityIRI.String() {
+			return a, nil
+		}
+	}
+
+	return nil, fmt.Errorf("activity [%s] not found", activityIRI)
+}
@@ -0,0 +1,60 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package anchorsynctask
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+const reconcileStoreName = "anchor-reconcile"
+
+// reconcileStore stores the Merkle root of the anchor activities of a service that were reconciled for a given day.
+type reconcileStore struct {
+	store storage.Store
+}
+
+func newReconcileStore(storageProvider storage.Provider) (*reconcileStore, error) {
+	store, err := storageProvider.OpenStore(reconcileStoreName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open anchor-reconcile store: %w", err)
+	}
+
+	return &reconcileStore{store: store}, nil
+}
+
+// GetRoot returns the reconciled root for the given service and day or an empty string if the day hasn't
+// been reconciled.
+func (s *reconcileStore) GetRoot(serviceIRI *url.URL, date string) (string, error) {
+	rootBytes, err := s.store.Get(reconcileKey(serviceIRI, date))
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return "", nil
+		}
+
+		return "", fmt.Errorf("get from DB: %w", err)
+	}
+
+	return string(rootBytes), nil
+}
+
+// PutRoot stores the reconciled root for the given service and day.
+func (s *reconcileStore) PutRoot(serviceIRI *url.URL, date, root string) error {
+	err := s.store.Put(reconcileKey(serviceIRI, date), []byte(root))
+	if err != nil {
+		return fmt.Errorf("put to DB: %w", err)
+	}
+
+	return nil
+}
+
+func reconcileKey(serviceIRI *url.URL, date string) string {
+	return serviceIRI.String() + "_" + date
+}
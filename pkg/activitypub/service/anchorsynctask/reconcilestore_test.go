@@ -0,0 +1,91 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package anchorsynctask
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+)
+
+func TestReconcileStore(t *testing.T) {
+	var (
+		service1IRI = testutil.MustParseURL("https://domain1.com/services/orb")
+		service2IRI = testutil.MustParseURL("https://domain2.com/services/orb")
+	)
+
+	t.Run("Success", func(t *testing.T) {
+		s, err := newReconcileStore(storage.NewMockStoreProvider())
+		require.NoError(t, err)
+
+		root, err := s.GetRoot(service1IRI, "2021-06-01")
+		require.NoError(t, err)
+		require.Empty(t, root)
+
+		require.NoError(t, s.PutRoot(service1IRI, "2021-06-01", "root1"))
+		require.NoError(t, s.PutRoot(service2IRI, "2021-06-01", "root2"))
+
+		root, err = s.GetRoot(service1IRI, "2021-06-01")
+		require.NoError(t, err)
+		require.Equal(t, "root1", root)
+
+		root, err = s.GetRoot(service2IRI, "2021-06-01")
+		require.NoError(t, err)
+		require.Equal(t, "root2", root)
+	})
+
+	t.Run("Open store error", func(t *testing.T) {
+		p := storage.NewMockStoreProvider()
+
+		errExpected := errors.New("injected open store error")
+
+		p.ErrOpenStoreHandle = errExpected
+
+		s, err := newReconcileStore(p)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errExpected.Error())
+		require.Nil(t, s)
+	})
+
+	t.Run("Get error", func(t *testing.T) {
+		errExpected := errors.New("injected Get error")
+
+		p := storage.NewMockStoreProvider()
+		p.Store = &storage.MockStore{
+			ErrGet: errExpected,
+			Store:  make(map[string]storage.DBEntry),
+		}
+
+		s, err := newReconcileStore(p)
+		require.NoError(t, err)
+
+		_, err = s.GetRoot(service1IRI, "2021-06-01")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errExpected.Error())
+	})
+
+	t.Run("Put error", func(t *testing.T) {
+		errExpected := errors.New("injected Put error")
+
+		p := storage.NewMockStoreProvider()
+		p.Store = &storage.MockStore{
+			ErrPut: errExpected,
+			Store:  make(map[string]storage.DBEntry),
+		}
+
+		s, err := newReconcileStore(p)
+		require.NoError(t, err)
+
+		err = s.PutRoot(service1IRI, "2021-06-01", "root1")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errExpected.Error())
+	})
+}
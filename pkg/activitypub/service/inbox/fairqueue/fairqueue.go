@@ -0,0 +1,325 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fairqueue
+
+import (
+	"sync"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/trustbloc/edge-core/pkg/log"
+)
+
+var logger = log.New("activitypub_service")
+
+const defaultMaxInFlight = 1
+
+// Policy returns the maximum number of messages from the given sender that may be processed concurrently.
+type Policy interface {
+	MaxInFlight(sender string) int
+}
+
+// StaticPolicy is a Policy which applies a default limit to all senders except for those
+// that have an explicit limit.
+type StaticPolicy struct {
+	mutex        sync.RWMutex
+	defaultLimit int
+	limits       map[string]int
+}
+
+// NewStaticPolicy returns a new static policy. If defaultLimit is less than 1 then a limit of 1 is used.
+func NewStaticPolicy(defaultLimit int, limits map[string]int) *StaticPolicy {
+	if defaultLimit < 1 {
+		defaultLimit = defaultMaxInFlight
+	}
+
+	return &StaticPolicy{
+		defaultLimit: defaultLimit,
+		limits:       limits,
+	}
+}
+
+// MaxInFlight returns the maximum number of messages from the given sender that may be processed concurrently.
+func (p *StaticPolicy) MaxInFlight(sender string) int {
+	if limit, ok := p.limits[sender]; ok {
+		return limit
+	}
+
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	return p.defaultLimit
+}
+
+// DefaultLimit returns the limit that applies to senders that don't have an explicit limit.
+func (p *StaticPolicy) DefaultLimit() int {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	return p.defaultLimit
+}
+
+// SetDefaultLimit updates the limit that applies to senders that don't have an explicit limit. If the
+// limit is less than 1 then a limit of 1 is used.
+func (p *StaticPolicy) SetDefaultLimit(limit int) {
+	if limit < 1 {
+		limit = defaultMaxInFlight
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.defaultLimit = limit
+}
+
+// Config holds the configuration for the queue.
+type Config struct {
+	// Workers is the number of messages that are processed concurrently. Defaults to 1.
+	Workers int
+
+	// MaxQueued is the maximum number of messages (across all senders) that are held in the queue. Add blocks
+	// while the queue is full. Defaults to the number of workers.
+	MaxQueued int
+
+	// Policy limits the number of messages from a single sender that are processed concurrently.
+	// Defaults to one message per sender.
+	Policy Policy
+}
+
+type senderQueue struct {
+	pending  []*message.Message
+	inFlight int
+}
+
+// Queue holds a FIFO queue of messages for each sender and dispatches the messages to a pool of workers,
+// visiting the senders in round-robin order so that a flood of messages from one sender doesn't prevent
+// the messages from other senders from being processed.
+type Queue struct {
+	handle    func(msg *message.Message)
+	workers   int
+	maxQueued int
+	policy    Policy
+
+	mutex   sync.Mutex
+	cond    *sync.Cond
+	senders map[string]*senderQueue
+	order   []string
+	next    int
+	queued  int
+	started bool
+	stopped bool
+	wg      sync.WaitGroup
+}
+
+// New returns a new queue which invokes the given handler for each message.
+func New(cfg Config, handle func(msg *message.Message)) *Queue {
+	workers := cfg.Workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	maxQueued := cfg.MaxQueued
+	if maxQueued < workers {
+		maxQueued = workers
+	}
+
+	policy := cfg.Policy
+	if policy == nil {
+		policy = NewStaticPolicy(defaultMaxInFlight, nil)
+	}
+
+	q := &Queue{
+		handle:    handle,
+		workers:   workers,
+		maxQueued: maxQueued,
+		policy:    policy,
+		senders:   make(map[string]*senderQueue),
+	}
+
+	q.cond = sync.NewCond(&q.mutex)
+
+	return q
+}
+
+// Start starts the workers.
+func (q *Queue) Start() {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.started {
+		return
+	}
+
+	q.started = true
+
+	logger.Debugf("Starting %d workers. Max queued: %d", q.workers, q.maxQueued)
+
+	q.wg.Add(q.workers)
+
+	for i := 0; i < q.workers; i++ {
+		go q.work()
+	}
+}
+
+// Stop stops the workers after the messages that are currently being processed have completed. Messages that
+// are still queued (and any message that's subsequently added) are Nacked.
+func (q *Queue) Stop() {
+	q.mutex.Lock()
+
+	q.stopped = true
+
+	for _, sq := range q.senders {
+		for _, msg := range sq.pending {
+			msg.Nack()
+		}
+
+		sq.pending = nil
+	}
+
+	q.queued = 0
+
+	q.cond.Broadcast()
+	q.mutex.Unlock()
+
+	q.wg.Wait()
+}
+
+// Add adds a message from the given sender to the queue. This function blocks while the queue is full.
+func (q *Queue) Add(sender string, msg *message.Message) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for q.queued >= q.maxQueued && !q.stopped {
+		q.cond.Wait()
+	}
+
+	if q.stopped {
+		logger.Debugf("Queue is stopped. Nacking message [%s]", msg.UUID)
+
+		msg.Nack()
+
+		return
+	}
+
+	sq, ok := q.senders[sender]
+	if !ok {
+		sq = &senderQueue{}
+
+		q.senders[sender] = sq
+		q.order = append(q.order, sender)
+	}
+
+	sq.pending = append(sq.pending, msg)
+	q.queued++
+
+	q.cond.Broadcast()
+}
+
+func (q *Queue) work() {
+	defer q.wg.Done()
+
+	for {
+		sender, msg, ok := q.take()
+		if !ok {
+			return
+		}
+
+		q.handle(msg)
+
+		q.done(sender)
+	}
+}
+
+// take blocks until a message may be processed (or the queue is stopped) and returns the message along
+// with its sender.
+func (q *Queue) take() (string, *message.Message, bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for {
+		if q.stopped {
+			return "", nil, false
+		}
+
+		if sender, msg, ok := q.nextMessage(); ok {
+			// Wake up any blocked Add since there's now room in the queue.
+			q.cond.Broadcast()
+
+			return sender, msg, true
+		}
+
+		q.cond.Wait()
+	}
+}
+
+// nextMessage returns the next message from the first sender (starting after the sender that was last
+// visited) that has a pending message and hasn't reached its in-flight limit. The mutex must be held.
+func (q *Queue) nextMessage() (string, *message.Message, bool) {
+	for i := 0; i < len(q.order); i++ {
+		idx := (q.next + i) % len(q.order)
+		sender := q.order[idx]
+		sq := q.senders[sender]
+
+		if len(sq.pending) == 0 || sq.inFlight >= q.maxInFlight(sender) {
+			continue
+		}
+
+		msg := sq.pending[0]
+
+		sq.pending[0] = nil
+		sq.pending = sq.pending[1:]
+		sq.inFlight++
+		q.queued--
+		q.next = idx + 1
+
+		return sender, msg, true
+	}
+
+	return "", nil, false
+}
+
+func (q *Queue) done(sender string) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	sq := q.senders[sender]
+	sq.inFlight--
+
+	if sq.inFlight == 0 && len(sq.pending) == 0 {
+		q.remove(sender)
+	}
+
+	// Wake up any worker that's waiting for the sender's in-flight count to drop.
+	q.cond.Broadcast()
+}
+
+// remove removes the given sender from the queue. The mutex must be held.
+func (q *Queue) remove(sender string) {
+	delete(q.senders, sender)
+
+	for i, s := range q.order {
+		if s != sender {
+			continue
+		}
+
+		q.order = append(q.order[:i], q.order[i+1:]...)
+
+		if i < q.next {
+			q.next--
+		}
+
+		return
+	}
+}
+
+func (q *Queue) maxInFlight(sender string) int {
+	limit := q.policy.MaxInFlight(sender)
+	if limit < 1 {
+		return 1
+	}
+
+	return limit
+}
@@ -0,0 +1,264 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fairqueue
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	sender1 = "https://domain1.com/services/orb"
+	sender2 = "https://domain2.com/services/orb"
+	sender3 = "https://domain3.com/services/orb"
+)
+
+func TestStaticPolicy(t *testing.T) {
+	p := NewStaticPolicy(0, map[string]int{sender2: 5})
+
+	require.Equal(t, 1, p.MaxInFlight(sender1))
+	require.Equal(t, 5, p.MaxInFlight(sender2))
+
+	p = NewStaticPolicy(3, nil)
+
+	require.Equal(t, 3, p.MaxInFlight(sender1))
+	require.Equal(t, 3, p.DefaultLimit())
+
+	p.SetDefaultLimit(4)
+	require.Equal(t, 4, p.MaxInFlight(sender1))
+
+	p.SetDefaultLimit(0)
+	require.Equal(t, 1, p.MaxInFlight(sender1))
+}
+
+func TestNew(t *testing.T) {
+	q := New(Config{}, func(*message.Message) {})
+	require.NotNil(t, q)
+	require.Equal(t, 1, q.workers)
+	require.Equal(t, 1, q.maxQueued)
+	require.NotNil(t, q.policy)
+
+	q = New(Config{Workers: 5, MaxQueued: 2}, func(*message.Message) {})
+	require.Equal(t, 5, q.workers)
+	require.Equal(t, 5, q.maxQueued)
+}
+
+func TestQueue_RoundRobin(t *testing.T) {
+	var (
+		mutex     sync.Mutex
+		processed []string
+	)
+
+	q := New(Config{Workers: 1, MaxQueued: 100}, func(msg *message.Message) {
+		mutex.Lock()
+		processed = append(processed, msg.Metadata["sender"])
+		mutex.Unlock()
+
+		msg.Ack()
+	})
+
+	// Flood the queue with messages from sender1 before the other senders' messages are added.
+	for i := 0; i < 10; i++ {
+		q.Add(sender1, newMessage(sender1))
+	}
+
+	q.Add(sender2, newMessage(sender2))
+	q.Add(sender3, newMessage(sender3))
+
+	q.Start()
+	defer q.Stop()
+
+	require.Eventually(t, func() bool {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		return len(processed) == 12
+	}, time.Second, 10*time.Millisecond)
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	// sender2 and sender3 shouldn't have to wait for all of sender1's messages to be processed.
+	require.Equal(t, []string{sender1, sender2, sender3}, processed[:3])
+}
+
+func TestQueue_MaxInFlight(t *testing.T) {
+	var (
+		mutex       sync.Mutex
+		inFlight    = make(map[string]int)
+		maxInFlight = make(map[string]int)
+		count       int
+	)
+
+	q := New(Config{
+		Workers:   4,
+		MaxQueued: 100,
+		Policy:    NewStaticPolicy(1, map[string]int{sender2: 3}),
+	}, func(msg *message.Message) {
+		sender := msg.Metadata["sender"]
+
+		mutex.Lock()
+		inFlight[sender]++
+		if inFlight[sender] > maxInFlight[sender] {
+			maxInFlight[sender] = inFlight[sender]
+		}
+		mutex.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+
+		mutex.Lock()
+		inFlight[sender]--
+		count++
+		mutex.Unlock()
+
+		msg.Ack()
+	})
+
+	q.Start()
+	defer q.Stop()
+
+	for i := 0; i < 6; i++ {
+		q.Add(sender1, newMessage(sender1))
+		q.Add(sender2, newMessage(sender2))
+	}
+
+	require.Eventually(t, func() bool {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		return count == 12
+	}, 2*time.Second, 10*time.Millisecond)
+
+	mutex.Lock()
+	require.Equal(t, 1, maxInFlight[sender1])
+	require.Equal(t, 3, maxInFlight[sender2])
+	mutex.Unlock()
+
+	// Senders are removed from the queue once they have no pending or in-flight messages.
+	require.Eventually(t, func() bool {
+		q.mutex.Lock()
+		defer q.mutex.Unlock()
+
+		return len(q.senders) == 0 && len(q.order) == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestQueue_Stop(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	q := New(Config{Workers: 1, MaxQueued: 2}, func(msg *message.Message) {
+		close(started)
+
+		<-release
+
+		msg.Ack()
+	})
+
+	q.Start()
+	q.Start() // Should be ignored.
+
+	msg1 := newMessage(sender1)
+	msg2 := newMessage(sender1)
+
+	q.Add(sender1, msg1)
+
+	<-started
+
+	q.Add(sender1, msg2)
+
+	stopped := make(chan struct{})
+
+	go func() {
+		q.Stop()
+		close(stopped)
+	}()
+
+	// The queued message should be Nacked.
+	select {
+	case <-msg2.Nacked():
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for queued message to be Nacked")
+	}
+
+	// Stop should wait for the message that's in flight.
+	select {
+	case <-stopped:
+		t.Fatal("queue stopped before in-flight message completed")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+
+	<-stopped
+
+	select {
+	case <-msg1.Acked():
+	default:
+		t.Fatal("in-flight message should have been Acked")
+	}
+
+	// A message added after the queue is stopped should be Nacked.
+	msg3 := newMessage(sender2)
+
+	q.Add(sender2, msg3)
+
+	select {
+	case <-msg3.Nacked():
+	default:
+		t.Fatal("message should have been Nacked")
+	}
+}
+
+func TestQueue_AddBlocksWhenFull(t *testing.T) {
+	release := make(chan struct{})
+
+	q := New(Config{Workers: 1, MaxQueued: 1}, func(msg *message.Message) {
+		<-release
+
+		msg.Ack()
+	})
+
+	q.Add(sender1, newMessage(sender1))
+
+	added := make(chan struct{})
+
+	go func() {
+		q.Add(sender2, newMessage(sender2))
+		close(added)
+	}()
+
+	select {
+	case <-added:
+		t.Fatal("Add should block while the queue is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	q.Start()
+
+	select {
+	case <-added:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for Add")
+	}
+
+	close(release)
+
+	q.Stop()
+}
+
+func newMessage(sender string) *message.Message {
+	msg := message.NewMessage(watermill.NewUUID(), nil)
+	msg.Metadata["sender"] = sender
+
+	return msg
+}
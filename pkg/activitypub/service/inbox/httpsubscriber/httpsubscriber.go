@@ -7,10 +7,17 @@ SPDX-License-Identifier: Apache-2.0
 package httpsubscriber
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
 	wmhttp "github.com/ThreeDotsLabs/watermill-http/pkg/http"
@@ -18,6 +25,10 @@ import (
 	"github.com/trustbloc/edge-core/pkg/log"
 	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
 
+	"github.com/trustbloc/orb/pkg/activitypub/client/transport"
+	"github.com/trustbloc/orb/pkg/activitypub/evidence"
+	"github.com/trustbloc/orb/pkg/activitypub/quarantine"
+	"github.com/trustbloc/orb/pkg/activitypub/rejection"
 	"github.com/trustbloc/orb/pkg/httpserver/auth"
 	"github.com/trustbloc/orb/pkg/lifecycle"
 )
@@ -27,15 +38,51 @@ var logger = log.New("activitypub_service")
 const (
 	// ActorIRIKey is the metadata key for the actor IRI.
 	ActorIRIKey = "actor-iri"
+	// SignatureHeadersKey is the metadata key for the (JSON-encoded) headers that were used to verify
+	// the HTTP signature of the request.
+	SignatureHeadersKey = "signature-headers"
 
 	defaultBufferSize = 100
 	stopTimeout       = 250 * time.Millisecond
+
+	// maxDecodedBodySize is the maximum size of a compressed request body once it's decompressed.
+	maxDecodedBodySize = 20 * 1024 * 1024
+)
+
+var (
+	errUnsupportedEncoding = errors.New("unsupported content encoding")
+	errBodyTooLarge        = errors.New("decoded request body is too large")
 )
 
+// QuarantineStore stores the requests that fail verification so that they may be reviewed and processed again.
+type QuarantineStore interface {
+	Put(entry *quarantine.Entry) error
+}
+
+// EvidenceStore stores the raw requests of the activities that are accepted by the inbox.
+type EvidenceStore interface {
+	Put(record *evidence.Record) error
+}
+
+// RejectionRecorder records the requests that are rejected by the inbox.
+type RejectionRecorder interface {
+	Record(r *rejection.Rejection)
+}
+
 // Config holds the HTTP subscriber configuration parameters.
 type Config struct {
 	ServiceEndpoint string
 	BufferSize      int
+
+	// Quarantine (optional) stores the requests whose HTTP signature could not be verified.
+	Quarantine QuarantineStore
+
+	// Evidence (optional) stores the raw requests (headers and body) of the HTTP-signed activities that
+	// were accepted, so that a dispute about the signature of an activity may be resolved.
+	Evidence EvidenceStore
+
+	// Rejections (optional) records the requests whose HTTP signature could not be verified.
+	Rejections RejectionRecorder
 }
 
 type signatureVerifier interface {
@@ -112,33 +159,36 @@ func (s *Subscriber) Handler() common.HTTPRequestHandler {
 }
 
 func (s *Subscriber) handleMessage(w http.ResponseWriter, r *http.Request) {
-	var actorIRI *url.URL
+	var (
+		actorIRI         *url.URL
+		signatureHeaders []byte
+		signedBody       []byte
+	)
 
 	if !s.tokenVerifier.Verify(r) {
 		logger.Debugf("Request was not verified using authorization bearer tokens. Verifying request via HTTP signature")
 
-		verified, actor, err := s.verifier.VerifyRequest(r)
-		if err != nil {
-			logger.Errorf("[%s] Error verifying HTTP signature: %s", s.ServiceEndpoint, err)
-
-			w.WriteHeader(http.StatusInternalServerError)
-
-			return
-		}
-
-		if !verified {
-			logger.Infof("[%s] Invalid HTTP signature", s.ServiceEndpoint)
-
-			w.WriteHeader(http.StatusUnauthorized)
+		actor, body, status := s.verifySignature(r)
+		if status != http.StatusOK {
+			w.WriteHeader(status)
 
 			return
 		}
 
 		actorIRI = actor
+		signatureHeaders = getSignatureHeaders(r)
+		signedBody = body
 	} else {
 		logger.Debugf("Request was verified with a bearer token or no authorization was required.")
 	}
 
+	// The body is decoded after the HTTP signature is verified since the digest is computed over the encoded body.
+	if status := s.decodeBody(r); status != http.StatusOK {
+		w.WriteHeader(status)
+
+		return
+	}
+
 	msg, err := s.unmarshalMessage("", r)
 	if err != nil {
 		logger.Warnf("[%s] Error reading message: %s", s.ServiceEndpoint, err)
@@ -152,6 +202,10 @@ func (s *Subscriber) handleMessage(w http.ResponseWriter, r *http.Request) {
 		msg.Metadata[ActorIRIKey] = actorIRI.String()
 	}
 
+	if signatureHeaders != nil {
+		msg.Metadata[SignatureHeadersKey] = string(signatureHeaders)
+	}
+
 	logger.Debugf("[%s] Handling message [%s] from actor [%s]", s.ServiceEndpoint, msg.UUID, actorIRI)
 
 	err = s.publish(msg)
@@ -163,7 +217,125 @@ func (s *Subscriber) handleMessage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	s.respond(msg, w, r)
+	if s.respond(msg, w, r) && signedBody != nil {
+		s.storeEvidence(r, signedBody)
+	}
+}
+
+// verifySignature verifies the HTTP signature of the request and returns the actor IRI along with
+// http.StatusOK if the signature is valid, otherwise the status code of the response. If a quarantine store is
+// configured then a request that fails verification is quarantined. The request body is also returned if
+// a quarantine or evidence store is configured.
+func (s *Subscriber) verifySignature(r *http.Request) (*url.URL, []byte, int) {
+	var body []byte
+
+	readBody := s.Quarantine != nil || s.Evidence != nil
+
+	if readBody {
+		// The body is read before verification so that it's available if the request must be quarantined.
+		var err error
+
+		body, err = ioutil.ReadAll(r.Body)
+		if err != nil {
+			logger.Warnf("[%s] Error reading request body: %s", s.ServiceEndpoint, err)
+
+			return nil, nil, http.StatusBadRequest
+		}
+
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	verified, actor, err := s.verifier.VerifyRequest(r)
+	if err != nil {
+		logger.Errorf("[%s] Error verifying HTTP signature: %s", s.ServiceEndpoint, err)
+
+		s.quarantine(r, body, fmt.Sprintf("error verifying HTTP signature: %s", err))
+		s.recordRejection(r, fmt.Sprintf("error verifying HTTP signature: %s", err))
+
+		return nil, nil, http.StatusInternalServerError
+	}
+
+	if !verified {
+		logger.Infof("[%s] Invalid HTTP signature", s.ServiceEndpoint)
+
+		s.quarantine(r, body, "invalid HTTP signature")
+		s.recordRejection(r, "invalid HTTP signature")
+
+		return nil, nil, http.StatusUnauthorized
+	}
+
+	if readBody {
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	return actor, body, http.StatusOK
+}
+
+// decodeBody replaces the body of the request with the decompressed body if the request specifies a supported
+// content encoding (gzip). Returns http.StatusOK if the body was decoded (or isn't encoded), otherwise the status
+// code of the response.
+func (s *Subscriber) decodeBody(r *http.Request) int {
+	encoding := r.Header.Get(transport.ContentEncodingHeader)
+	if encoding == "" {
+		return http.StatusOK
+	}
+
+	body, err := decode(r.Body, encoding)
+	if err != nil {
+		logger.Warnf("[%s] Error decoding request body: %s", s.ServiceEndpoint, err)
+
+		switch {
+		case errors.Is(err, errUnsupportedEncoding):
+			return http.StatusUnsupportedMediaType
+		case errors.Is(err, errBodyTooLarge):
+			return http.StatusRequestEntityTooLarge
+		default:
+			return http.StatusBadRequest
+		}
+	}
+
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	return http.StatusOK
+}
+
+func (s *Subscriber) quarantine(r *http.Request, body []byte, reason string) {
+	if s.Quarantine == nil {
+		return
+	}
+
+	if err := s.Quarantine.Put(quarantine.NewEntry(r, body, reason)); err != nil {
+		logger.Errorf("[%s] Error quarantining request: %s", s.ServiceEndpoint, err)
+	}
+}
+
+func (s *Subscriber) recordRejection(r *http.Request, detail string) {
+	if s.Rejections == nil {
+		return
+	}
+
+	s.Rejections.Record(&rejection.Rejection{
+		Reason: rejection.ReasonSignature,
+		KeyID:  getSignatureKeyID(r),
+		Detail: detail,
+	})
+}
+
+func (s *Subscriber) storeEvidence(r *http.Request, body []byte) {
+	if s.Evidence == nil {
+		return
+	}
+
+	record, err := evidence.NewRecord(r, body)
+	if err != nil {
+		logger.Debugf("[%s] Not storing evidence of request: %s", s.ServiceEndpoint, err)
+
+		return
+	}
+
+	if err := s.Evidence.Put(record); err != nil {
+		logger.Warnf("[%s] Error storing evidence of activity [%s]: %s", s.ServiceEndpoint, record.ActivityID, err)
+	}
 }
 
 func (s *Subscriber) publish(msg *message.Message) error {
@@ -182,13 +354,16 @@ func (s *Subscriber) publish(msg *message.Message) error {
 	}
 }
 
-func (s *Subscriber) respond(msg *message.Message, w http.ResponseWriter, r *http.Request) {
+// respond writes the response once the message is acknowledged and returns true if the message was acked.
+func (s *Subscriber) respond(msg *message.Message, w http.ResponseWriter, r *http.Request) bool {
 	select {
 	case <-msg.Acked():
 		logger.Debugf("[%s] Ack received for message [%s]", s.ServiceEndpoint, msg.UUID)
 
 		w.WriteHeader(http.StatusOK)
 
+		return true
+
 	case <-msg.Nacked():
 		logger.Warnf("[%s] Nack received for message [%s]", s.ServiceEndpoint, msg.UUID)
 
@@ -207,6 +382,84 @@ func (s *Subscriber) respond(msg *message.Message, w http.ResponseWriter, r *htt
 
 		w.WriteHeader(http.StatusServiceUnavailable)
 	}
+
+	return false
+}
+
+// getSignatureHeaders returns the JSON-encoded headers that make up the signing string of the HTTP signature,
+// along with the signature itself, so that the signature may be verified again at a later time.
+func getSignatureHeaders(r *http.Request) []byte {
+	headers := map[string]string{
+		"(request-target)": fmt.Sprintf("%s %s", strings.ToLower(r.Method), r.URL.RequestURI()),
+		"host":             r.Host,
+	}
+
+	for _, name := range []string{"Date", "Digest", "Signature"} {
+		if value := r.Header.Get(name); value != "" {
+			headers[strings.ToLower(name)] = value
+		}
+	}
+
+	headersBytes, err := json.Marshal(headers)
+	if err != nil {
+		// This shouldn't happen since we're marshalling a map of strings.
+		logger.Warnf("Error marshalling signature headers: %s", err)
+
+		return nil
+	}
+
+	return headersBytes
+}
+
+// getSignatureKeyID returns the value of the keyId parameter in the Signature header of the request or
+// an empty string if the parameter isn't found.
+func getSignatureKeyID(r *http.Request) string {
+	const keyIDParam = "keyId="
+
+	for _, param := range strings.Split(r.Header.Get("Signature"), ",") {
+		param = strings.TrimSpace(param)
+
+		if strings.HasPrefix(param, keyIDParam) {
+			return strings.Trim(strings.TrimPrefix(param, keyIDParam), `"`)
+		}
+	}
+
+	return ""
+}
+
+func decode(body io.Reader, encoding string) ([]byte, error) {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "identity":
+		return ioutil.ReadAll(body)
+	case transport.GzipContentEncoding:
+		return decodeGzip(body)
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnsupportedEncoding, encoding)
+	}
+}
+
+func decodeGzip(body io.Reader) ([]byte, error) {
+	zr, err := gzip.NewReader(body)
+	if err != nil {
+		return nil, fmt.Errorf("gzip reader: %w", err)
+	}
+
+	defer func() {
+		if e := zr.Close(); e != nil {
+			logger.Debugf("Error closing gzip reader: %s", e)
+		}
+	}()
+
+	decoded, err := ioutil.ReadAll(io.LimitReader(zr, maxDecodedBodySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("decompress body: %w", err)
+	}
+
+	if len(decoded) > maxDecodedBodySize {
+		return nil, errBodyTooLarge
+	}
+
+	return decoded, nil
 }
 
 func (s *Subscriber) stop() {
@@ -8,7 +8,9 @@ package httpsubscriber
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -19,7 +21,10 @@ import (
 	wmhttp "github.com/ThreeDotsLabs/watermill-http/pkg/http"
 	"github.com/stretchr/testify/require"
 
+	"github.com/trustbloc/orb/pkg/activitypub/evidence"
 	apmocks "github.com/trustbloc/orb/pkg/activitypub/mocks"
+	"github.com/trustbloc/orb/pkg/activitypub/quarantine"
+	"github.com/trustbloc/orb/pkg/activitypub/rejection"
 	"github.com/trustbloc/orb/pkg/activitypub/service/mocks"
 	"github.com/trustbloc/orb/pkg/internal/testutil"
 	"github.com/trustbloc/orb/pkg/lifecycle"
@@ -79,6 +84,22 @@ func TestSubscriber_HandleAck(t *testing.T) {
 	require.NoError(t, result.Body.Close())
 }
 
+func TestGetSignatureHeaders(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, serviceURL+"?param=value", nil)
+	req.Header.Set("Date", "Tue, 07 Jun 2022 20:51:35 GMT")
+	req.Header.Set("Signature", `keyId="https://orb.domain1.com/services/orb/keys/main-key",signature="xxx"`)
+
+	headers := make(map[string]string)
+
+	require.NoError(t, json.Unmarshal(getSignatureHeaders(req), &headers))
+	require.Equal(t, map[string]string{
+		"(request-target)": "post /services/service1?param=value",
+		"host":             "localhost:8202",
+		"date":             "Tue, 07 Jun 2022 20:51:35 GMT",
+		"signature":        `keyId="https://orb.domain1.com/services/orb/keys/main-key",signature="xxx"`,
+	}, headers)
+}
+
 func TestSubscriber_HandleNack(t *testing.T) {
 	sigVerifier := &mocks.SignatureVerifier{}
 	sigVerifier.VerifyRequestReturns(true, testutil.MustParseURL(serviceURL), nil)
@@ -332,3 +353,416 @@ func TestSubscriber_NoHTTPSignatureRequired(t *testing.T) {
 	require.Equal(t, http.StatusOK, result.StatusCode)
 	require.NoError(t, result.Body.Close())
 }
+
+func TestSubscriber_Quarantine(t *testing.T) {
+	tm := &apmocks.AuthTokenMgr{}
+	tm.RequiredAuthTokensReturns([]string{"admin"}, nil)
+
+	body := []byte(`{"id":"https://orb.domain2.com/activities/1","type":"Create",` +
+		`"actor":"https://orb.domain2.com/services/orb"}`)
+
+	t.Run("Invalid HTTP signature", func(t *testing.T) {
+		sigVerifier := &mocks.SignatureVerifier{}
+		sigVerifier.VerifyRequestReturns(false, nil, nil)
+
+		q := &mockQuarantine{}
+
+		s := New(&Config{ServiceEndpoint: endpoint, Quarantine: q}, sigVerifier, tm)
+		defer s.Stop()
+
+		rw := httptest.NewRecorder()
+
+		s.handleMessage(rw, httptest.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body)))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusUnauthorized, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+
+		require.Len(t, q.entries, 1)
+		require.Equal(t, "invalid HTTP signature", q.entries[0].Reason)
+		require.Equal(t, body, q.entries[0].Body)
+		require.Equal(t, "https://orb.domain2.com/activities/1", q.entries[0].ActivityID)
+	})
+
+	t.Run("HTTP signature error", func(t *testing.T) {
+		sigVerifier := &mocks.SignatureVerifier{}
+		sigVerifier.VerifyRequestReturns(false, nil, fmt.Errorf("injected verifier error"))
+
+		q := &mockQuarantine{err: fmt.Errorf("injected quarantine error")}
+
+		s := New(&Config{ServiceEndpoint: endpoint, Quarantine: q}, sigVerifier, tm)
+		defer s.Stop()
+
+		rw := httptest.NewRecorder()
+
+		s.handleMessage(rw, httptest.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body)))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusInternalServerError, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+
+		require.Len(t, q.entries, 1)
+		require.Contains(t, q.entries[0].Reason, "injected verifier error")
+	})
+
+	t.Run("Valid HTTP signature", func(t *testing.T) {
+		sigVerifier := &mocks.SignatureVerifier{}
+		sigVerifier.VerifyRequestReturns(true, testutil.MustParseURL(serviceURL), nil)
+
+		q := &mockQuarantine{}
+
+		s := New(&Config{ServiceEndpoint: endpoint, Quarantine: q}, sigVerifier, tm)
+		defer s.Stop()
+
+		msgChan, err := s.Subscribe(context.Background(), "")
+		require.NoError(t, err)
+
+		payloads := make(chan []byte, 1)
+
+		go func() {
+			for msg := range msgChan {
+				payloads <- msg.Payload
+
+				msg.Ack()
+			}
+		}()
+
+		rw := httptest.NewRecorder()
+
+		s.handleMessage(rw, httptest.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body)))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusOK, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+
+		require.Equal(t, body, []byte(<-payloads))
+		require.Empty(t, q.entries)
+	})
+
+	t.Run("Read body error", func(t *testing.T) {
+		s := New(&Config{ServiceEndpoint: endpoint, Quarantine: &mockQuarantine{}}, &mocks.SignatureVerifier{}, tm)
+		defer s.Stop()
+
+		rw := httptest.NewRecorder()
+
+		s.handleMessage(rw, httptest.NewRequest(http.MethodPost, endpoint, &errorReader{}))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusBadRequest, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+}
+
+func TestSubscriber_Rejections(t *testing.T) {
+	tm := &apmocks.AuthTokenMgr{}
+	tm.RequiredAuthTokensReturns([]string{"admin"}, nil)
+
+	const keyID = "https://orb.domain2.com/services/orb/keys/main-key"
+
+	newRequest := func() *http.Request {
+		req := httptest.NewRequest(http.MethodPost, endpoint, bytes.NewReader([]byte(`{}`)))
+		req.Header.Set("Signature", `keyId="`+keyID+`",algorithm="hs2019",headers="(request-target) date",`+
+			`signature="c2lnbmF0dXJl"`)
+
+		return req
+	}
+
+	t.Run("Invalid HTTP signature", func(t *testing.T) {
+		sigVerifier := &mocks.SignatureVerifier{}
+		sigVerifier.VerifyRequestReturns(false, nil, nil)
+
+		rejections := &mockRejections{}
+
+		s := New(&Config{ServiceEndpoint: endpoint, Rejections: rejections}, sigVerifier, tm)
+		defer s.Stop()
+
+		rw := httptest.NewRecorder()
+
+		s.handleMessage(rw, newRequest())
+
+		result := rw.Result()
+		require.Equal(t, http.StatusUnauthorized, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+
+		require.Len(t, rejections.rejections, 1)
+		require.Equal(t, rejection.ReasonSignature, rejections.rejections[0].Reason)
+		require.Equal(t, keyID, rejections.rejections[0].KeyID)
+		require.Equal(t, "invalid HTTP signature", rejections.rejections[0].Detail)
+	})
+
+	t.Run("HTTP signature error", func(t *testing.T) {
+		sigVerifier := &mocks.SignatureVerifier{}
+		sigVerifier.VerifyRequestReturns(false, nil, fmt.Errorf("injected verifier error"))
+
+		rejections := &mockRejections{}
+
+		s := New(&Config{ServiceEndpoint: endpoint, Rejections: rejections}, sigVerifier, tm)
+		defer s.Stop()
+
+		rw := httptest.NewRecorder()
+
+		s.handleMessage(rw, httptest.NewRequest(http.MethodPost, endpoint, bytes.NewReader([]byte(`{}`))))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusInternalServerError, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+
+		require.Len(t, rejections.rejections, 1)
+		require.Empty(t, rejections.rejections[0].KeyID)
+		require.Contains(t, rejections.rejections[0].Detail, "injected verifier error")
+	})
+}
+
+func TestSubscriber_Evidence(t *testing.T) {
+	tm := &apmocks.AuthTokenMgr{}
+	tm.RequiredAuthTokensReturns([]string{"admin"}, nil)
+
+	body := []byte(`{"id":"https://orb.domain2.com/activities/1","type":"Create",` +
+		`"actor":"https://orb.domain2.com/services/orb"}`)
+
+	sigVerifier := &mocks.SignatureVerifier{}
+	sigVerifier.VerifyRequestReturns(true, testutil.MustParseURL(serviceURL), nil)
+
+	t.Run("Acked", func(t *testing.T) {
+		es := &mockEvidenceStore{}
+
+		s := New(&Config{ServiceEndpoint: endpoint, Evidence: es}, sigVerifier, tm)
+		defer s.Stop()
+
+		subscribe(t, s, true)
+
+		req := httptest.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+		req.Header.Set("Signature", "xxx")
+
+		require.Equal(t, http.StatusOK, handle(t, s, req))
+
+		require.Len(t, es.records, 1)
+		require.Equal(t, "https://orb.domain2.com/activities/1", es.records[0].ActivityID)
+		require.Equal(t, body, es.records[0].Body)
+		require.Equal(t, "xxx", es.records[0].Headers.Get("Signature"))
+	})
+
+	t.Run("Nacked", func(t *testing.T) {
+		es := &mockEvidenceStore{}
+
+		s := New(&Config{ServiceEndpoint: endpoint, Evidence: es}, sigVerifier, tm)
+		defer s.Stop()
+
+		subscribe(t, s, false)
+
+		require.Equal(t, http.StatusInternalServerError,
+			handle(t, s, httptest.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))))
+		require.Empty(t, es.records)
+	})
+
+	t.Run("Not an activity", func(t *testing.T) {
+		es := &mockEvidenceStore{}
+
+		s := New(&Config{ServiceEndpoint: endpoint, Evidence: es}, sigVerifier, tm)
+		defer s.Stop()
+
+		subscribe(t, s, true)
+
+		require.Equal(t, http.StatusOK,
+			handle(t, s, httptest.NewRequest(http.MethodPost, endpoint, bytes.NewReader([]byte(`{}`)))))
+		require.Empty(t, es.records)
+	})
+
+	t.Run("Store error", func(t *testing.T) {
+		es := &mockEvidenceStore{err: fmt.Errorf("injected store error")}
+
+		s := New(&Config{ServiceEndpoint: endpoint, Evidence: es}, sigVerifier, tm)
+		defer s.Stop()
+
+		subscribe(t, s, true)
+
+		require.Equal(t, http.StatusOK,
+			handle(t, s, httptest.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))))
+		require.Len(t, es.records, 1)
+	})
+
+	t.Run("Authorization bearer token", func(t *testing.T) {
+		es := &mockEvidenceStore{}
+
+		s := New(&Config{ServiceEndpoint: endpoint, Evidence: es}, sigVerifier, &apmocks.AuthTokenMgr{})
+		defer s.Stop()
+
+		subscribe(t, s, true)
+
+		require.Equal(t, http.StatusOK,
+			handle(t, s, httptest.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))))
+		require.Empty(t, es.records)
+	})
+}
+
+func TestSubscriber_ContentEncoding(t *testing.T) {
+	tm := &apmocks.AuthTokenMgr{}
+	tm.RequiredAuthTokensReturns([]string{"admin"}, nil)
+
+	body := []byte(`{"id":"https://orb.domain2.com/activities/1","type":"Create",` +
+		`"actor":"https://orb.domain2.com/services/orb"}`)
+
+	sigVerifier := &mocks.SignatureVerifier{}
+	sigVerifier.VerifyRequestReturns(true, testutil.MustParseURL(serviceURL), nil)
+
+	t.Run("Gzip", func(t *testing.T) {
+		es := &mockEvidenceStore{}
+
+		s := New(&Config{ServiceEndpoint: endpoint, Evidence: es}, sigVerifier, tm)
+		defer s.Stop()
+
+		msgChan, err := s.Subscribe(context.Background(), "")
+		require.NoError(t, err)
+
+		var payload []byte
+
+		go func() {
+			for msg := range msgChan {
+				payload = msg.Payload
+
+				msg.Ack()
+			}
+		}()
+
+		compressed := gzipBytes(t, body)
+
+		req := httptest.NewRequest(http.MethodPost, endpoint, bytes.NewReader(compressed))
+		req.Header.Set("Content-Encoding", "gzip")
+
+		require.Equal(t, http.StatusOK, handle(t, s, req))
+		require.Equal(t, body, payload)
+
+		// The evidence holds the body that was signed.
+		require.Len(t, es.records, 1)
+		require.Equal(t, compressed, es.records[0].Body)
+	})
+
+	t.Run("Identity", func(t *testing.T) {
+		s := New(&Config{ServiceEndpoint: endpoint}, sigVerifier, tm)
+		defer s.Stop()
+
+		subscribe(t, s, true)
+
+		req := httptest.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+		req.Header.Set("Content-Encoding", "identity")
+
+		require.Equal(t, http.StatusOK, handle(t, s, req))
+	})
+
+	t.Run("Unsupported encoding", func(t *testing.T) {
+		s := New(&Config{ServiceEndpoint: endpoint}, sigVerifier, tm)
+		defer s.Stop()
+
+		subscribe(t, s, true)
+
+		req := httptest.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+		req.Header.Set("Content-Encoding", "br")
+
+		require.Equal(t, http.StatusUnsupportedMediaType, handle(t, s, req))
+	})
+
+	t.Run("Invalid gzip", func(t *testing.T) {
+		s := New(&Config{ServiceEndpoint: endpoint}, sigVerifier, tm)
+		defer s.Stop()
+
+		subscribe(t, s, true)
+
+		req := httptest.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+		req.Header.Set("Content-Encoding", "gzip")
+
+		require.Equal(t, http.StatusBadRequest, handle(t, s, req))
+	})
+
+	t.Run("Too large", func(t *testing.T) {
+		s := New(&Config{ServiceEndpoint: endpoint}, sigVerifier, tm)
+		defer s.Stop()
+
+		subscribe(t, s, true)
+
+		req := httptest.NewRequest(http.MethodPost, endpoint,
+			bytes.NewReader(gzipBytes(t, make([]byte, maxDecodedBodySize+1))))
+		req.Header.Set("Content-Encoding", "gzip")
+
+		require.Equal(t, http.StatusRequestEntityTooLarge, handle(t, s, req))
+	})
+}
+
+func gzipBytes(t *testing.T, b []byte) []byte {
+	t.Helper()
+
+	buf := &bytes.Buffer{}
+
+	zw := gzip.NewWriter(buf)
+
+	_, err := zw.Write(b)
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	return buf.Bytes()
+}
+
+func subscribe(t *testing.T, s *Subscriber, ack bool) {
+	t.Helper()
+
+	msgChan, err := s.Subscribe(context.Background(), "")
+	require.NoError(t, err)
+
+	go func() {
+		for msg := range msgChan {
+			if ack {
+				msg.Ack()
+			} else {
+				msg.Nack()
+			}
+		}
+	}()
+}
+
+func handle(t *testing.T, s *Subscriber, req *http.Request) int {
+	t.Helper()
+
+	rw := httptest.NewRecorder()
+
+	s.handleMessage(rw, req)
+
+	result := rw.Result()
+	require.NoError(t, result.Body.Close())
+
+	return result.StatusCode
+}
+
+type mockEvidenceStore struct {
+	records []*evidence.Record
+	err     error
+}
+
+func (m *mockEvidenceStore) Put(record *evidence.Record) error {
+	m.records = append(m.records, record)
+
+	return m.err
+}
+
+type mockQuarantine struct {
+	entries []*quarantine.Entry
+	err     error
+}
+
+func (m *mockQuarantine) Put(entry *quarantine.Entry) error {
+	m.entries = append(m.entries, entry)
+
+	return m.err
+}
+
+type mockRejections struct {
+	rejections []*rejection.Rejection
+}
+
+func (m *mockRejections) Record(r *rejection.Rejection) {
+	m.rejections = append(m.rejections, r)
+}
+
+type errorReader struct{}
+
+func (r *errorReader) Read([]byte) (int, error) {
+	return 0, fmt.Errorf("injected read error")
+}
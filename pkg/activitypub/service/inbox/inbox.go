@@ -20,12 +20,17 @@ import (
 	"github.com/trustbloc/edge-core/pkg/log"
 	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
 
+	"github.com/trustbloc/orb/pkg/activitypub/evidence"
+	"github.com/trustbloc/orb/pkg/activitypub/quarantine"
+	"github.com/trustbloc/orb/pkg/activitypub/rejection"
+	"github.com/trustbloc/orb/pkg/activitypub/service/inbox/fairqueue"
 	"github.com/trustbloc/orb/pkg/activitypub/service/inbox/httpsubscriber"
 	service "github.com/trustbloc/orb/pkg/activitypub/service/spi"
 	store "github.com/trustbloc/orb/pkg/activitypub/store/spi"
 	"github.com/trustbloc/orb/pkg/activitypub/vocab"
 	orberrors "github.com/trustbloc/orb/pkg/errors"
 	"github.com/trustbloc/orb/pkg/lifecycle"
+	"github.com/trustbloc/orb/pkg/pubsub/spi"
 	"github.com/trustbloc/orb/pkg/pubsub/wmlogger"
 )
 
@@ -37,6 +42,12 @@ type pubSub interface {
 	Close() error
 }
 
+// pooledSubscriber is implemented by publishers/subscribers that are able to deliver more than one
+// unacknowledged message at a time using a pool of subscribers.
+type pooledSubscriber interface {
+	SubscribeWithOpts(ctx context.Context, topic string, opts ...spi.Option) (<-chan *message.Message, error)
+}
+
 type signatureVerifier interface {
 	VerifyRequest(req *http.Request) (bool, *url.URL, error)
 }
@@ -49,12 +60,61 @@ type authTokenManager interface {
 	RequiredAuthTokens(endpoint, method string) ([]string, error)
 }
 
+// SignatureStore stores the HTTP signature headers of the activities that are received by the inbox.
+type SignatureStore interface {
+	PutSignatureHeaders(activityID *url.URL, headers map[string]string) error
+}
+
+// QuarantineStore stores the inbox requests that fail verification.
+type QuarantineStore interface {
+	Put(entry *quarantine.Entry) error
+}
+
+// EvidenceStore stores the raw requests of the activities that are accepted by the inbox.
+type EvidenceStore interface {
+	Put(record *evidence.Record) error
+}
+
+// RejectionRecorder records the activities that are rejected by the inbox.
+type RejectionRecorder interface {
+	Record(r *rejection.Rejection)
+}
+
 // Config holds configuration parameters for the Inbox.
 type Config struct {
 	ServiceEndpoint        string
 	ServiceIRI             *url.URL
 	Topic                  string
 	VerifyActorInSignature bool
+
+	// SignatureStore (optional) stores the HTTP signature headers of received activities
+	// so that the activities may be verified again at a later time (for example, when archived).
+	SignatureStore SignatureStore
+
+	// Quarantine (optional) stores the requests whose HTTP signature could not be verified so that they
+	// may be reviewed and processed again.
+	Quarantine QuarantineStore
+
+	// Evidence (optional) stores the raw HTTP-signed requests of accepted activities so that disputes about
+	// the signature of an activity may be resolved.
+	Evidence EvidenceStore
+
+	// Rejections (optional) records the activities that are rejected because the HTTP signature could not be
+	// verified or because the activity is invalid.
+	Rejections RejectionRecorder
+
+	// Workers (optional) is the number of activities that are processed concurrently. Defaults to 1.
+	Workers int
+
+	// QueueSize (optional) is the maximum number of activities that are received ahead of the workers. The
+	// queued activities are processed in round-robin order by sender so that a flood of activities from one
+	// sender doesn't prevent the activities of other senders from being processed. Defaults to the number
+	// of workers.
+	QueueSize int
+
+	// SenderPolicy (optional) limits the number of activities from a single sender that are processed
+	// concurrently. Defaults to one activity per sender.
+	SenderPolicy fairqueue.Policy
 }
 
 // Inbox implements the ActivityPub inbox.
@@ -64,12 +124,15 @@ type Inbox struct {
 
 	router                 *message.Router
 	httpSubscriber         *httpsubscriber.Subscriber
+	queue                  *fairqueue.Queue
 	msgChannel             <-chan *message.Message
 	activityHandler        service.ActivityHandler
 	activityStore          store.Store
 	jsonUnmarshal          func(data []byte, v interface{}) error
 	metrics                metricsProvider
 	verifyActorInSignature bool
+	done                   chan struct{}
+	listenerDone           chan struct{}
 }
 
 // New returns a new ActivityPub inbox.
@@ -81,6 +144,8 @@ func New(cfg *Config, s store.Store, pubSub pubSub, activityHandler service.Acti
 		activityStore:   s,
 		jsonUnmarshal:   json.Unmarshal,
 		metrics:         metrics,
+		done:            make(chan struct{}),
+		listenerDone:    make(chan struct{}),
 	}
 
 	h.Lifecycle = lifecycle.New(cfg.ServiceEndpoint,
@@ -88,14 +153,26 @@ func New(cfg *Config, s store.Store, pubSub pubSub, activityHandler service.Acti
 		lifecycle.WithStop(h.stop),
 	)
 
-	msgChan, err := pubSub.Subscribe(context.Background(), cfg.Topic)
+	msgChan, err := subscribe(pubSub, cfg.Topic, cfg.QueueSize)
 	if err != nil {
 		return nil, fmt.Errorf("subscribe to topic [%s]: %w", cfg.Topic, err)
 	}
 
+	h.queue = fairqueue.New(
+		fairqueue.Config{
+			Workers:   cfg.Workers,
+			MaxQueued: cfg.QueueSize,
+			Policy:    cfg.SenderPolicy,
+		},
+		h.handle,
+	)
+
 	httpSubscriber := httpsubscriber.New(
 		&httpsubscriber.Config{
 			ServiceEndpoint: cfg.ServiceEndpoint,
+			Quarantine:      cfg.Quarantine,
+			Evidence:        cfg.Evidence,
+			Rejections:      cfg.Rejections,
 		},
 		sigVerifier, tm,
 	)
@@ -137,6 +214,9 @@ func (h *Inbox) HTTPHandler() common.HTTPHandler {
 }
 
 func (h *Inbox) start() {
+	// Start the workers
+	h.queue.Start()
+
 	// Start the router
 	go h.route()
 
@@ -147,12 +227,24 @@ func (h *Inbox) start() {
 	<-h.router.Running()
 }
 
+// stop closes the router so that no new activities are accepted and then waits for the activities
+// that are currently being processed (if any) to complete. Activities that are queued are Nacked.
 func (h *Inbox) stop() {
 	if err := h.router.Close(); err != nil {
 		logger.Warnf("[%s] Error closing router: %s", h.ServiceEndpoint, err)
 	} else {
 		logger.Debugf("[%s] Closed router", h.ServiceEndpoint)
 	}
+
+	close(h.done)
+
+	// The queue is stopped before waiting for the listener since the listener may be blocked adding
+	// a message to a full queue.
+	h.queue.Stop()
+
+	<-h.listenerDone
+
+	logger.Infof("[%s] Inbox stopped", h.ServiceEndpoint)
 }
 
 func (h *Inbox) route() {
@@ -169,13 +261,43 @@ func (h *Inbox) route() {
 func (h *Inbox) listen() {
 	logger.Debugf("[%s] Starting message listener", h.ServiceEndpoint)
 
-	for msg := range h.msgChannel {
-		logger.Debugf("[%s] Got new message: %s: %s", h.ServiceEndpoint, msg.UUID, msg.Payload)
+	defer close(h.listenerDone)
+
+	for {
+		select {
+		case <-h.done:
+			logger.Debugf("[%s] Message listener stopped", h.ServiceEndpoint)
+
+			return
+		case msg, ok := <-h.msgChannel:
+			if !ok {
+				logger.Debugf("[%s] Message listener stopped", h.ServiceEndpoint)
+
+				return
+			}
+
+			logger.Debugf("[%s] Got new message: %s: %s", h.ServiceEndpoint, msg.UUID, msg.Payload)
+
+			h.queue.Add(h.senderOf(msg), msg)
+		}
+	}
+}
+
+// senderOf returns the actor that sent the given message. The actor in the HTTP signature is used if
+// available, otherwise the actor in the activity is used.
+func (h *Inbox) senderOf(msg *message.Message) string {
+	if actorIRI := msg.Metadata[httpsubscriber.ActorIRIKey]; actorIRI != "" {
+		return actorIRI
+	}
 
-		h.handle(msg)
+	activity := &vocab.ActivityType{}
+
+	if err := h.jsonUnmarshal(msg.Payload, activity); err != nil || activity.Actor() == nil {
+		// The message will be rejected when it's handled.
+		return ""
 	}
 
-	logger.Debugf("[%s] Message listener stopped", h.ServiceEndpoint)
+	return activity.Actor().String()
 }
 
 func (h *Inbox) handle(msg *message.Message) {
@@ -211,6 +333,8 @@ func (h *Inbox) handleActivityMsg(msg *message.Message) (*vocab.ActivityType, er
 	if err != nil {
 		logger.Errorf("[%s] Error validating activity for message [%s]: %s", h.ServiceEndpoint, msg.UUID, err)
 
+		h.recordValidationError(msg, err)
+
 		return nil, err
 	}
 
@@ -246,11 +370,73 @@ func (h *Inbox) handleActivityMsg(msg *message.Message) (*vocab.ActivityType, er
 	} else if e := h.activityStore.AddReference(store.Inbox, h.ServiceIRI, activity.ID().URL(),
 		store.WithActivityType(activity.Type().Types()[0])); e != nil {
 		logger.Errorf("[%s] Error adding reference to activity [%s]: %s", h.ServiceEndpoint, activity.ID(), e)
+	} else {
+		h.storeSignatureHeaders(msg, activity)
 	}
 
 	return activity, err
 }
 
+func (h *Inbox) recordValidationError(msg *message.Message, err error) {
+	if h.Rejections == nil {
+		return
+	}
+
+	r := &rejection.Rejection{
+		Reason: rejection.ReasonValidation,
+		Actor:  msg.Metadata[httpsubscriber.ActorIRIKey],
+		Detail: err.Error(),
+	}
+
+	// The activity may not be valid JSON, in which case only the detail is recorded.
+	activity := &vocab.ActivityType{}
+
+	if e := h.jsonUnmarshal(msg.Payload, activity); e == nil {
+		r.ActivityID = activity.ID().String()
+		r.ActivityType = activity.Type().String()
+
+		if r.Actor == "" && activity.Actor() != nil {
+			r.Actor = activity.Actor().String()
+		}
+	}
+
+	h.Rejections.Record(r)
+}
+
+func (h *Inbox) storeSignatureHeaders(msg *message.Message, activity *vocab.ActivityType) {
+	if h.SignatureStore == nil {
+		return
+	}
+
+	headersJSON := msg.Metadata[httpsubscriber.SignatureHeadersKey]
+	if headersJSON == "" {
+		return
+	}
+
+	headers := make(map[string]string)
+
+	if err := h.jsonUnmarshal([]byte(headersJSON), &headers); err != nil {
+		logger.Errorf("[%s] Error unmarshalling signature headers for activity [%s]: %s",
+			h.ServiceEndpoint, activity.ID(), err)
+
+		return
+	}
+
+	if err := h.SignatureStore.PutSignatureHeaders(activity.ID().URL(), headers); err != nil {
+		logger.Errorf("[%s] Error storing signature headers for activity [%s]: %s",
+			h.ServiceEndpoint, activity.ID(), err)
+	}
+}
+
+func subscribe(pubSub pubSub, topic string, queueSize int) (<-chan *message.Message, error) {
+	ps, ok := pubSub.(pooledSubscriber)
+	if !ok || queueSize <= 1 {
+		return pubSub.Subscribe(context.Background(), topic)
+	}
+
+	return ps.SubscribeWithOpts(context.Background(), topic, spi.WithPool(uint(queueSize)))
+}
+
 func (h *Inbox) unmarshalAndValidateActivity(msg *message.Message) (*vocab.ActivityType, error) {
 	activity := &vocab.ActivityType{}
 
@@ -26,7 +26,9 @@ import (
 	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
 
 	apmocks "github.com/trustbloc/orb/pkg/activitypub/mocks"
+	"github.com/trustbloc/orb/pkg/activitypub/rejection"
 	"github.com/trustbloc/orb/pkg/activitypub/resthandler"
+	"github.com/trustbloc/orb/pkg/activitypub/service/inbox/fairqueue"
 	"github.com/trustbloc/orb/pkg/activitypub/service/inbox/httpsubscriber"
 	"github.com/trustbloc/orb/pkg/activitypub/service/mocks"
 	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
@@ -75,6 +77,100 @@ func TestInbox_StartStop(t *testing.T) {
 	require.Equal(t, lifecycle.StateStopped, ib.State())
 }
 
+func TestInbox_FairQueue(t *testing.T) {
+	cfg := &Config{
+		ServiceEndpoint: "/services/service1/inbox",
+		ServiceIRI:      testutil.MustParseURL("https://example1.com/services/service1"),
+		Topic:           "activities",
+		Workers:         2,
+		QueueSize:       10,
+		SenderPolicy:    fairqueue.NewStaticPolicy(1, nil),
+	}
+
+	tm := &apmocks.AuthTokenMgr{}
+
+	activityHandler := &mocks.ActivityHandler{}
+	activityStore := memstore.New(cfg.ServiceEndpoint)
+	pubSub := mocks.NewPubSub()
+
+	ib, err := New(cfg, activityStore, pubSub, activityHandler,
+		&mocks.SignatureVerifier{}, tm, &orbmocks.MetricsProvider{})
+	require.NoError(t, err)
+	require.NotNil(t, ib)
+
+	ib.Start()
+
+	actor1 := testutil.MustParseURL("https://example2.com/services/service2")
+	actor2 := testutil.MustParseURL("https://example3.com/services/service3")
+
+	var activities []*vocab.ActivityType
+
+	for _, actor := range []*url.URL{actor1, actor1, actor1, actor2} {
+		activity := vocab.NewCreateActivity(
+			vocab.NewObjectProperty(vocab.WithIRI(testutil.MustParseURL("https://example.com/object1"))),
+			vocab.WithID(newActivityID(cfg.ServiceEndpoint)),
+			vocab.WithActor(actor),
+		)
+
+		activityBytes, e := json.Marshal(activity)
+		require.NoError(t, e)
+
+		require.NoError(t, pubSub.Publish(cfg.Topic, message.NewMessage(watermill.NewUUID(), activityBytes)))
+
+		activities = append(activities, activity)
+	}
+
+	require.Eventually(t, func() bool {
+		return activityHandler.HandleActivityCallCount() == len(activities)
+	}, time.Second, 10*time.Millisecond)
+
+	for _, activity := range activities {
+		a, e := activityStore.GetActivity(activity.ID().URL())
+		require.NoError(t, e)
+		require.NotNil(t, a)
+	}
+
+	ib.Stop()
+
+	require.Equal(t, lifecycle.StateStopped, ib.State())
+}
+
+func TestSubscribe(t *testing.T) {
+	t.Run("Without pool", func(t *testing.T) {
+		// The embedded interface hides the pooled subscribe function of the mock.
+		msgChan, err := subscribe(&struct{ pubSub }{pubSub: mocks.NewPubSub()}, "activities", 10)
+		require.NoError(t, err)
+		require.NotNil(t, msgChan)
+	})
+}
+
+func TestInbox_SenderOf(t *testing.T) {
+	ib := &Inbox{jsonUnmarshal: json.Unmarshal}
+
+	actor := testutil.MustParseURL("https://example2.com/services/service2")
+
+	activityBytes, err := json.Marshal(vocab.NewCreateActivity(
+		vocab.NewObjectProperty(vocab.WithIRI(testutil.MustParseURL("https://example.com/object1"))),
+		vocab.WithActor(actor),
+	))
+	require.NoError(t, err)
+
+	t.Run("Actor in HTTP signature", func(t *testing.T) {
+		msg := message.NewMessage(watermill.NewUUID(), activityBytes)
+		msg.Metadata[httpsubscriber.ActorIRIKey] = "https://example3.com/services/service3"
+
+		require.Equal(t, "https://example3.com/services/service3", ib.senderOf(msg))
+	})
+
+	t.Run("Actor in activity", func(t *testing.T) {
+		require.Equal(t, actor.String(), ib.senderOf(message.NewMessage(watermill.NewUUID(), activityBytes)))
+	})
+
+	t.Run("Invalid activity", func(t *testing.T) {
+		require.Empty(t, ib.senderOf(message.NewMessage(watermill.NewUUID(), []byte("{"))))
+	})
+}
+
 func TestInbox_Handle(t *testing.T) {
 	const service1URL = "http://localhost:8202/services/service1"
 
@@ -352,6 +448,8 @@ func TestInbox_Error(t *testing.T) {
 		stop := startHTTPServer(t, ":8206", ib.HTTPHandler())
 		defer stop()
 
+		time.Sleep(100 * time.Millisecond)
+
 		activity := vocab.NewCreateActivity(
 			vocab.NewObjectProperty(
 				vocab.WithObject(
@@ -709,6 +807,133 @@ func TestUnmarshalAndValidateActivity(t *testing.T) {
 	})
 }
 
+func TestInbox_RecordValidationError(t *testing.T) {
+	activityID := testutil.MustParseURL("https://example1.com/activities/activity1")
+	actorIRI := testutil.MustParseURL("https://example1.com/services/service1")
+
+	tm := &apmocks.AuthTokenMgr{}
+	tm.RequiredAuthTokensReturns([]string{"admin"}, nil)
+
+	rejections := &mockRejectionRecorder{}
+
+	ib, e := New(&Config{VerifyActorInSignature: true, Rejections: rejections}, memstore.New(""),
+		mocks.NewPubSub(), nil, nil, tm, &orbmocks.MetricsProvider{})
+	require.NoError(t, e)
+	require.NotNil(t, ib)
+
+	t.Run("Actor mismatch", func(t *testing.T) {
+		activity := vocab.NewCreateActivity(nil, vocab.WithID(activityID), vocab.WithActor(actorIRI))
+
+		activityBytes, err := json.Marshal(activity)
+		require.NoError(t, err)
+
+		msg := message.NewMessage("msg1", activityBytes)
+		msg.Metadata[httpsubscriber.ActorIRIKey] = "https://example1.com/services/service2"
+
+		_, err = ib.handleActivityMsg(msg)
+		require.Error(t, err)
+
+		require.Len(t, rejections.rejections, 1)
+
+		r := rejections.rejections[0]
+		require.Equal(t, rejection.ReasonValidation, r.Reason)
+		require.Equal(t, activityID.String(), r.ActivityID)
+		require.Equal(t, string(vocab.TypeCreate), r.ActivityType)
+		require.Equal(t, "https://example1.com/services/service2", r.Actor)
+		require.Contains(t, r.Detail, "does not match the actor in the HTTP signature")
+	})
+
+	t.Run("Invalid JSON", func(t *testing.T) {
+		rejections.rejections = nil
+
+		_, err := ib.handleActivityMsg(message.NewMessage("msg1", []byte("{")))
+		require.Error(t, err)
+
+		require.Len(t, rejections.rejections, 1)
+
+		r := rejections.rejections[0]
+		require.Equal(t, rejection.ReasonValidation, r.Reason)
+		require.Empty(t, r.ActivityID)
+		require.Empty(t, r.Actor)
+		require.Contains(t, r.Detail, "unmarshal activity")
+	})
+}
+
+func TestStoreSignatureHeaders(t *testing.T) {
+	activityID := testutil.MustParseURL("https://example1.com/activities/activity1")
+	activity := vocab.NewCreateActivity(nil, vocab.WithID(activityID))
+
+	tm := &apmocks.AuthTokenMgr{}
+	tm.RequiredAuthTokensReturns([]string{"admin"}, nil)
+
+	sigStore := &mockSignatureStore{}
+
+	ib, e := New(&Config{SignatureStore: sigStore}, memstore.New(""), mocks.NewPubSub(),
+		nil, nil, tm, &orbmocks.MetricsProvider{})
+	require.NoError(t, e)
+	require.NotNil(t, ib)
+
+	t.Run("Success", func(t *testing.T) {
+		msg := message.NewMessage("msg1", nil)
+		msg.Metadata[httpsubscriber.SignatureHeadersKey] = `{"date":"Tue, 07 Jun 2022 20:51:35 GMT","signature":"xxx"}`
+
+		ib.storeSignatureHeaders(msg, activity)
+
+		require.Equal(t, map[string]string{
+			"date":      "Tue, 07 Jun 2022 20:51:35 GMT",
+			"signature": "xxx",
+		}, sigStore.headers[activityID.String()])
+	})
+
+	t.Run("No signature headers", func(t *testing.T) {
+		sigStore.headers = nil
+
+		ib.storeSignatureHeaders(message.NewMessage("msg1", nil), activity)
+
+		require.Empty(t, sigStore.headers)
+	})
+
+	t.Run("Invalid signature headers", func(t *testing.T) {
+		sigStore.headers = nil
+
+		msg := message.NewMessage("msg1", nil)
+		msg.Metadata[httpsubscriber.SignatureHeadersKey] = "{"
+
+		ib.storeSignatureHeaders(msg, activity)
+
+		require.Empty(t, sigStore.headers)
+	})
+
+	t.Run("Store error", func(t *testing.T) {
+		sigStore.headers = nil
+		sigStore.err = errors.New("injected store error")
+
+		msg := message.NewMessage("msg1", nil)
+		msg.Metadata[httpsubscriber.SignatureHeadersKey] = `{"signature":"xxx"}`
+
+		require.NotPanics(t, func() { ib.storeSignatureHeaders(msg, activity) })
+	})
+}
+
+type mockSignatureStore struct {
+	headers map[string]map[string]string
+	err     error
+}
+
+func (m *mockSignatureStore) PutSignatureHeaders(activityID *url.URL, headers map[string]string) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	if m.headers == nil {
+		m.headers = make(map[string]map[string]string)
+	}
+
+	m.headers[activityID.String()] = headers
+
+	return nil
+}
+
 func newHTTPRequest(u string, activity *vocab.ActivityType) (*http.Request, error) {
 	activityBytes, err := json.Marshal(activity)
 	if err != nil {
@@ -749,3 +974,11 @@ func startHTTPServer(t *testing.T, listenAddress string, handlers ...common.HTTP
 		require.NoError(t, httpServer.Stop(context.Background()))
 	}
 }
+
+type mockRejectionRecorder struct {
+	rejections []*rejection.Rejection
+}
+
+func (m *mockRejectionRecorder) Record(r *rejection.Rejection) {
+	m.rejections = append(m.rejections, r)
+}
@@ -0,0 +1,201 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package logallowlist
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+var logger = log.New("log_allow_list")
+
+const (
+	logTag    = "vct-log-allow"
+	keyPrefix = logTag + "-"
+)
+
+// Log contains the URL of a trusted VCT log and (optionally) the base64-encoded public key of the log.
+// If a public key is specified then the log is only trusted if the public key published by the log
+// matches the given key.
+type Log struct {
+	URL       string `json:"url"`
+	PublicKey string `json:"publicKey,omitempty"`
+}
+
+// Manager manages reads and updates to the set of trusted VCT logs.
+type Manager struct {
+	store     storage.Store
+	unmarshal func(data []byte, v interface{}) error
+}
+
+// NewManager returns a new log allow list manager.
+func NewManager(s storage.Store) *Manager {
+	return &Manager{
+		store:     s,
+		unmarshal: json.Unmarshal,
+	}
+}
+
+// Update adds the given logs to the allow list and removes the logs with the given URLs. If a log
+// already exists in the allow list then its public key is replaced with the new value.
+func (m *Manager) Update(additions []*Log, removals []string) error {
+	var operations []storage.Operation
+
+	for _, l := range additions {
+		entry, err := normalize(l)
+		if err != nil {
+			return orberrors.NewBadRequest(err)
+		}
+
+		value, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("marshal log [%s]: %w", entry.URL, err)
+		}
+
+		operations = append(operations, storage.Operation{
+			Key:   newKey(entry.URL),
+			Value: value,
+			Tags:  []storage.Tag{{Name: logTag}},
+		})
+	}
+
+	for _, logURL := range removals {
+		operations = append(operations, storage.Operation{
+			Key: newKey(NormalizeURL(logURL)),
+		})
+	}
+
+	if len(operations) == 0 {
+		logger.Debugf("No new additions or removals for the log allow list.")
+
+		return nil
+	}
+
+	err := m.store.Batch(operations)
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("batch update: %w", err))
+	}
+
+	logger.Debugf("Successfully updated the log allow list - Additions: %d, Removals: %s", len(additions), removals)
+
+	return nil
+}
+
+// Get returns the log for the given URL. If the log isn't in the allow list then
+// orberrors.ErrContentNotFound is returned.
+func (m *Manager) Get(logURL string) (*Log, error) {
+	value, err := m.store.Get(newKey(NormalizeURL(logURL)))
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return nil, orberrors.ErrContentNotFound
+		}
+
+		return nil, orberrors.NewTransientf("get log [%s]: %w", logURL, err)
+	}
+
+	l := &Log{}
+
+	err = m.unmarshal(value, l)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal log [%s]: %w", logURL, err)
+	}
+
+	return l, nil
+}
+
+// GetAll returns all of the logs in the allow list, sorted by URL.
+func (m *Manager) GetAll() ([]*Log, error) {
+	it, err := m.store.Query(logTag)
+	if err != nil {
+		return nil, orberrors.NewTransientf("query logs: %w", err)
+	}
+
+	defer storage.Close(it, logger)
+
+	var logs []*Log
+
+	for {
+		ok, e := it.Next()
+		if e != nil {
+			return nil, orberrors.NewTransientf("query next item: %w", e)
+		}
+
+		if !ok {
+			break
+		}
+
+		value, e := it.Value()
+		if e != nil {
+			return nil, orberrors.NewTransientf("get value: %w", e)
+		}
+
+		l := &Log{}
+
+		e = m.unmarshal(value, l)
+		if e != nil {
+			logger.Warnf("Error unmarshalling log: %s. The item will be ignored.", e)
+
+			continue
+		}
+
+		logs = append(logs, l)
+	}
+
+	sort.Slice(logs, func(i, j int) bool {
+		return logs[i].URL < logs[j].URL
+	})
+
+	return logs, nil
+}
+
+// NormalizeURL returns the given log URL without a trailing slash so that the URL in an anchor
+// proof matches the URL in the allow list regardless of how the URL was entered.
+func NormalizeURL(logURL string) string {
+	return strings.TrimSuffix(strings.TrimSpace(logURL), "/")
+}
+
+func normalize(l *Log) (*Log, error) {
+	if l == nil || l.URL == "" {
+		return nil, errors.New("log URL is required")
+	}
+
+	logURL := NormalizeURL(l.URL)
+
+	u, err := url.Parse(logURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid log URL [%s]: %w", l.URL, err)
+	}
+
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid log URL [%s]: scheme and host are required", l.URL)
+	}
+
+	if l.PublicKey != "" {
+		if _, err = base64.StdEncoding.DecodeString(l.PublicKey); err != nil {
+			return nil, fmt.Errorf("invalid public key for log [%s]: %w", l.URL, err)
+		}
+	}
+
+	return &Log{
+		URL:       logURL,
+		PublicKey: l.PublicKey,
+	}, nil
+}
+
+func newKey(logURL string) string {
+	return keyPrefix + logURL
+}
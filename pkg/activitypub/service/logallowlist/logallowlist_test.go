@@ -0,0 +1,220 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package logallowlist
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	storagemocks "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/stretchr/testify/require"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+const (
+	log1URL = "https://vct1.example.com/maple2021"
+	log2URL = "https://vct2.example.com/maple2021"
+	log3URL = "https://vct3.example.com/maple2021"
+
+	publicKey = "BHMdvFEJ1M3pSYyXUx6VT3qk2/kQrzmjnsmNgeJHSpfBDT0JKNn2JGF6Z8JnqSZOuxHrTgW6a1fB+9lWZ8vVqS4="
+)
+
+func TestManager(t *testing.T) {
+	s, err := mem.NewProvider().OpenStore("orb-config")
+	require.NoError(t, err)
+
+	mgr := NewManager(s)
+	require.NotNil(t, mgr)
+
+	logs, err := mgr.GetAll()
+	require.NoError(t, err)
+	require.Empty(t, logs)
+
+	require.NoError(t, mgr.Update(nil, nil))
+
+	require.NoError(t, mgr.Update(
+		[]*Log{
+			{URL: log2URL + "/", PublicKey: publicKey},
+			{URL: log1URL},
+			{URL: log3URL},
+		},
+		nil,
+	))
+
+	logs, err = mgr.GetAll()
+	require.NoError(t, err)
+	require.Len(t, logs, 3)
+	require.Equal(t, log1URL, logs[0].URL)
+	require.Empty(t, logs[0].PublicKey)
+	require.Equal(t, log2URL, logs[1].URL)
+	require.Equal(t, publicKey, logs[1].PublicKey)
+	require.Equal(t, log3URL, logs[2].URL)
+
+	l, err := mgr.Get(log2URL)
+	require.NoError(t, err)
+	require.Equal(t, log2URL, l.URL)
+	require.Equal(t, publicKey, l.PublicKey)
+
+	l, err = mgr.Get(log1URL + "/")
+	require.NoError(t, err)
+	require.Equal(t, log1URL, l.URL)
+
+	require.NoError(t, mgr.Update(
+		[]*Log{{URL: log1URL, PublicKey: publicKey}},
+		[]string{log3URL + "/"},
+	))
+
+	l, err = mgr.Get(log1URL)
+	require.NoError(t, err)
+	require.Equal(t, publicKey, l.PublicKey)
+
+	_, err = mgr.Get(log3URL)
+	require.True(t, errors.Is(err, orberrors.ErrContentNotFound))
+
+	logs, err = mgr.GetAll()
+	require.NoError(t, err)
+	require.Len(t, logs, 2)
+}
+
+func TestManagerUpdateError(t *testing.T) {
+	s, err := mem.NewProvider().OpenStore("orb-config")
+	require.NoError(t, err)
+
+	mgr := NewManager(s)
+
+	t.Run("No URL", func(t *testing.T) {
+		err := mgr.Update([]*Log{{PublicKey: publicKey}}, nil)
+		require.Error(t, err)
+		require.True(t, orberrors.IsBadRequest(err))
+		require.Contains(t, err.Error(), "log URL is required")
+	})
+
+	t.Run("Invalid URL", func(t *testing.T) {
+		err := mgr.Update([]*Log{{URL: ":invalid"}}, nil)
+		require.Error(t, err)
+		require.True(t, orberrors.IsBadRequest(err))
+		require.Contains(t, err.Error(), "invalid log URL")
+
+		err = mgr.Update([]*Log{{URL: "vct.example.com"}}, nil)
+		require.Error(t, err)
+		require.True(t, orberrors.IsBadRequest(err))
+		require.Contains(t, err.Error(), "scheme and host are required")
+	})
+
+	t.Run("Invalid public key", func(t *testing.T) {
+		err := mgr.Update([]*Log{{URL: log1URL, PublicKey: "!invalid!"}}, nil)
+		require.Error(t, err)
+		require.True(t, orberrors.IsBadRequest(err))
+		require.Contains(t, err.Error(), "invalid public key")
+	})
+
+	t.Run("Batch error", func(t *testing.T) {
+		errExpected := errors.New("injected batch error")
+
+		mgr := NewManager(&storagemocks.MockStore{
+			Store:    make(map[string]storagemocks.DBEntry),
+			ErrBatch: errExpected,
+		})
+
+		err := mgr.Update([]*Log{{URL: log1URL}}, nil)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), errExpected.Error())
+	})
+}
+
+func TestManagerGetError(t *testing.T) {
+	t.Run("Get error", func(t *testing.T) {
+		errExpected := errors.New("injected get error")
+
+		mgr := NewManager(&storagemocks.MockStore{
+			Store:  make(map[string]storagemocks.DBEntry),
+			ErrGet: errExpected,
+		})
+
+		_, err := mgr.Get(log1URL)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), errExpected.Error())
+	})
+
+	t.Run("Unmarshal error", func(t *testing.T) {
+		mgr := NewManager(&storagemocks.MockStore{
+			Store: map[string]storagemocks.DBEntry{
+				newKey(log1URL): {Value: []byte("invalid JSON")},
+			},
+		})
+
+		_, err := mgr.Get(log1URL)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal log")
+	})
+}
+
+func TestManagerGetAllError(t *testing.T) {
+	t.Run("Query error", func(t *testing.T) {
+		errExpected := errors.New("injected query error")
+
+		mgr := NewManager(&storagemocks.MockStore{
+			Store:    make(map[string]storagemocks.DBEntry),
+			ErrQuery: errExpected,
+		})
+
+		_, err := mgr.GetAll()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errExpected.Error())
+	})
+
+	t.Run("Iterator.Next error", func(t *testing.T) {
+		errExpected := errors.New("injected iterator Next error")
+
+		mgr := NewManager(&storagemocks.MockStore{
+			Store:   make(map[string]storagemocks.DBEntry),
+			ErrNext: errExpected,
+		})
+
+		_, err := mgr.GetAll()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errExpected.Error())
+	})
+
+	t.Run("Iterator.Value error", func(t *testing.T) {
+		errExpected := errors.New("injected iterator Value error")
+
+		mgr := NewManager(&storagemocks.MockStore{
+			Store: map[string]storagemocks.DBEntry{
+				newKey(log1URL): {
+					Value: []byte(`{"url":"` + log1URL + `"}`),
+					Tags:  []storage.Tag{{Name: logTag}},
+				},
+			},
+			ErrValue: errExpected,
+		})
+
+		_, err := mgr.GetAll()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errExpected.Error())
+	})
+
+	t.Run("Unmarshal error -> ignore", func(t *testing.T) {
+		mgr := NewManager(&storagemocks.MockStore{
+			Store: map[string]storagemocks.DBEntry{
+				newKey(log1URL): {
+					Value: []byte("invalid JSON"),
+					Tags:  []storage.Tag{{Name: logTag}},
+				},
+			},
+		})
+
+		logs, err := mgr.GetAll()
+		require.NoError(t, err, "unmarshal errors should be ignored")
+		require.Empty(t, logs)
+	})
+}
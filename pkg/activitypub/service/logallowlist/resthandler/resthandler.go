@@ -0,0 +1,183 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/trustbloc/edge-core/pkg/log"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+
+	"github.com/trustbloc/orb/pkg/activitypub/service/logallowlist"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+// Path is the path of the log allow list endpoint.
+const Path = "/logallowlist"
+
+const (
+	badRequestResponse          = "Bad Request."
+	internalServerErrorResponse = "Internal Server Error."
+)
+
+var logger = log.New("log-allow-list-rest-handler")
+
+type logManager interface {
+	GetAll() ([]*logallowlist.Log, error)
+	Update(additions []*logallowlist.Log, removals []string) error
+}
+
+// UpdateRequest contains the logs to add to and remove from the allow list.
+type UpdateRequest struct {
+	Add    []*logallowlist.Log `json:"add,omitempty"`
+	Remove []string            `json:"remove,omitempty"`
+}
+
+// Reader implements a REST handler that returns the logs in the allow list.
+type Reader struct {
+	mgr     logManager
+	marshal func(v interface{}) ([]byte, error)
+}
+
+// NewReader returns a new log allow list reader.
+func NewReader(mgr logManager) *Reader {
+	return &Reader{
+		mgr:     mgr,
+		marshal: json.Marshal,
+	}
+}
+
+// Path returns the HTTP REST endpoint for the log allow list service.
+func (h *Reader) Path() string {
+	return Path
+}
+
+// Method returns the HTTP method, which is always GET.
+func (h *Reader) Method() string {
+	return http.MethodGet
+}
+
+// Handler returns the HTTP REST handle for the log allow list service.
+func (h *Reader) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Reader) handle(w http.ResponseWriter, _ *http.Request) {
+	logs, err := h.mgr.GetAll()
+	if err != nil {
+		logger.Errorf("[%s] Error retrieving log allow list: %s", Path, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	if logs == nil {
+		logs = []*logallowlist.Log{}
+	}
+
+	logsBytes, err := h.marshal(logs)
+	if err != nil {
+		logger.Errorf("[%s] Error marshalling log allow list: %s", Path, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	writeResponse(w, http.StatusOK, logsBytes)
+}
+
+// Writer implements a REST handler that adds logs to and removes logs from the allow list. For example,
+// {"add":[{"url":"https://vct.domain1.com/maple2021","publicKey":"BHMd..."}],"remove":["https://vct.domain2.com"]}.
+type Writer struct {
+	mgr     logManager
+	readAll func(r io.Reader) ([]byte, error)
+}
+
+// NewWriter returns a new log allow list writer.
+func NewWriter(mgr logManager) *Writer {
+	return &Writer{
+		mgr:     mgr,
+		readAll: ioutil.ReadAll,
+	}
+}
+
+// Path returns the HTTP REST endpoint for the log allow list service.
+func (h *Writer) Path() string {
+	return Path
+}
+
+// Method returns the HTTP method, which is always POST.
+func (h *Writer) Method() string {
+	return http.MethodPost
+}
+
+// Handler returns the HTTP REST handle for the log allow list service.
+func (h *Writer) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Writer) handle(w http.ResponseWriter, req *http.Request) {
+	reqBytes, err := h.readAll(req.Body)
+	if err != nil {
+		logger.Errorf("[%s] Error reading request body: %s", Path, err)
+
+		writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	update := &UpdateRequest{}
+
+	err = json.Unmarshal(reqBytes, update)
+	if err != nil {
+		logger.Infof("[%s] Invalid log allow list request: %s", Path, reqBytes)
+
+		writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	err = h.mgr.Update(update.Add, update.Remove)
+	if err != nil {
+		if orberrors.IsBadRequest(err) {
+			logger.Infof("[%s] Invalid log allow list request: %s", Path, err)
+
+			writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+			return
+		}
+
+		logger.Errorf("[%s] Error updating log allow list: %s", Path, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	logger.Infof("[%s] Updated log allow list: %s", Path, reqBytes)
+
+	writeResponse(w, http.StatusOK, nil)
+}
+
+func writeResponse(w http.ResponseWriter, status int, body []byte) {
+	w.WriteHeader(status)
+
+	if len(body) > 0 {
+		if _, err := w.Write(body); err != nil {
+			logger.Warnf("[%s] Unable to write response: %s", Path, err)
+
+			return
+		}
+
+		logger.Debugf("[%s] Wrote response: %s", Path, body)
+	}
+}
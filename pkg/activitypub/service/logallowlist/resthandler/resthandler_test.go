@@ -0,0 +1,136 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/service/logallowlist"
+	"github.com/trustbloc/orb/pkg/internal/testutil/httptestutil"
+	storemocks "github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+const (
+	log1URL = "https://vct1.example.com/maple2021"
+	log2URL = "https://vct2.example.com/maple2021"
+)
+
+func TestReader(t *testing.T) {
+	mgr := newManager(t)
+
+	h := NewReader(mgr)
+	require.Equal(t, Path, h.Path())
+	require.Equal(t, http.MethodGet, h.Method())
+	require.NotNil(t, h.Handler())
+
+	t.Run("empty", func(t *testing.T) {
+		status, respBytes := httptestutil.Get(t, NewReader(newManager(t)).Handler(), Path)
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, "[]", string(respBytes))
+	})
+
+	t.Run("success", func(t *testing.T) {
+		require.NoError(t, mgr.Update([]*logallowlist.Log{{URL: log1URL}, {URL: log2URL}}, nil))
+
+		status, respBytes := httptestutil.Get(t, h.Handler(), Path)
+		require.Equal(t, http.StatusOK, status)
+
+		var logs []*logallowlist.Log
+		require.NoError(t, json.Unmarshal(respBytes, &logs))
+		require.Len(t, logs, 2)
+		require.Equal(t, log1URL, logs[0].URL)
+		require.Equal(t, log2URL, logs[1].URL)
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		s := &storemocks.Store{}
+		s.QueryReturns(nil, errors.New("injected query error"))
+
+		status, _ := httptestutil.Get(t, NewReader(logallowlist.NewManager(s)).Handler(), Path)
+		require.Equal(t, http.StatusInternalServerError, status)
+	})
+
+	t.Run("marshal error", func(t *testing.T) {
+		h := NewReader(mgr)
+		h.marshal = func(v interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		status, _ := httptestutil.Get(t, h.Handler(), Path)
+		require.Equal(t, http.StatusInternalServerError, status)
+	})
+}
+
+func TestWriter(t *testing.T) {
+	h := NewWriter(newManager(t))
+	require.Equal(t, Path, h.Path())
+	require.Equal(t, http.MethodPost, h.Method())
+	require.NotNil(t, h.Handler())
+
+	t.Run("success", func(t *testing.T) {
+		mgr := newManager(t)
+
+		status, _ := httptestutil.Post(t, NewWriter(mgr).Handler(), Path,
+			[]byte(`{"add":[{"url":"`+log1URL+`"},{"url":"`+log2URL+`","publicKey":"cHVia2V5"}]}`))
+		require.Equal(t, http.StatusOK, status)
+
+		logs, err := mgr.GetAll()
+		require.NoError(t, err)
+		require.Len(t, logs, 2)
+		require.Equal(t, "cHVia2V5", logs[1].PublicKey)
+
+		status, _ = httptestutil.Post(t, NewWriter(mgr).Handler(), Path, []byte(`{"remove":["`+log1URL+`"]}`))
+		require.Equal(t, http.StatusOK, status)
+
+		logs, err = mgr.GetAll()
+		require.NoError(t, err)
+		require.Len(t, logs, 1)
+		require.Equal(t, log2URL, logs[0].URL)
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		status, _ := httptestutil.Post(t, h.Handler(), Path, []byte(`{`))
+		require.Equal(t, http.StatusBadRequest, status)
+
+		status, _ = httptestutil.Post(t, h.Handler(), Path, []byte(`{"add":[{"publicKey":"cHVia2V5"}]}`))
+		require.Equal(t, http.StatusBadRequest, status)
+
+		status, _ = httptestutil.Post(t, h.Handler(), Path, []byte(`{"add":[{"url":"`+log1URL+`","publicKey":"!"}]}`))
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("read error", func(t *testing.T) {
+		h := NewWriter(newManager(t))
+		h.readAll = func(r io.Reader) ([]byte, error) { return nil, errors.New("injected read error") }
+
+		status, _ := httptestutil.Post(t, h.Handler(), Path, []byte(`{}`))
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		s := &storemocks.Store{}
+		s.BatchReturns(errors.New("injected batch error"))
+
+		status, _ := httptestutil.Post(t, NewWriter(logallowlist.NewManager(s)).Handler(), Path,
+			[]byte(`{"add":[{"url":"`+log1URL+`"}]}`))
+		require.Equal(t, http.StatusInternalServerError, status)
+	})
+}
+
+func newManager(t *testing.T) *logallowlist.Manager {
+	t.Helper()
+
+	configStore, err := mem.NewProvider().OpenStore("orb-config")
+	require.NoError(t, err)
+
+	return logallowlist.NewManager(configStore)
+}
@@ -9,6 +9,7 @@ package mocks
 import (
 	"fmt"
 	"net/url"
+	"sync"
 
 	"github.com/trustbloc/orb/pkg/activitypub/client"
 	"github.com/trustbloc/orb/pkg/activitypub/vocab"
@@ -17,10 +18,12 @@ import (
 
 // ActivityPubClient is a mock ActivityPub client.
 type ActivityPubClient struct {
-	actors     map[string]*vocab.ActorType
-	keys       map[string]*vocab.PublicKeyType
-	activities []*vocab.ActivityType
-	err        error
+	mutex       sync.Mutex
+	actors      map[string]*vocab.ActorType
+	keys        map[string]*vocab.PublicKeyType
+	activities  []*vocab.ActivityType
+	err         error
+	invalidated []string
 }
 
 // NewActivitPubClient returns a mock ActivityPub client.
@@ -62,6 +65,7 @@ func (m *ActivityPubClient) WithError(err error) *ActivityPubClient {
 }
 
 // GetPublicKey returns the public key for the given IRI.
+//
 //nolint:interfacer
 func (m *ActivityPubClient) GetPublicKey(keyIRI *url.URL) (*vocab.PublicKeyType, error) {
 	if m.err != nil {
@@ -77,6 +81,7 @@ func (m *ActivityPubClient) GetPublicKey(keyIRI *url.URL) (*vocab.PublicKeyType,
 }
 
 // GetActor returns the actor for the given IRI.
+//
 //nolint:interfacer
 func (m *ActivityPubClient) GetActor(actorIRI *url.URL) (*vocab.ActorType, error) {
 	if m.err != nil {
@@ -91,6 +96,30 @@ func (m *ActivityPubClient) GetActor(actorIRI *url.URL) (*vocab.ActorType, error
 	return actor, nil
 }
 
+// InvalidateActor records the IRI of the invalidated actor.
+func (m *ActivityPubClient) InvalidateActor(actorIRI *url.URL) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.invalidated = append(m.invalidated, actorIRI.String())
+}
+
+// InvalidatePublicKey records the IRI of the invalidated public key.
+func (m *ActivityPubClient) InvalidatePublicKey(keyIRI *url.URL) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.invalidated = append(m.invalidated, keyIRI.String())
+}
+
+// Invalidated returns the IRIs of the actors and public keys that were invalidated.
+func (m *ActivityPubClient) Invalidated() []string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.invalidated
+}
+
 // GetReferences simply returns an iterator that contains the IRI passed as an arg.
 func (m *ActivityPubClient) GetReferences(iri *url.URL) (client.ReferenceIterator, error) {
 	if m.err != nil {
@@ -7,7 +7,9 @@ SPDX-License-Identifier: Apache-2.0
 package monitoring
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -19,7 +21,10 @@ import (
 	"github.com/piprate/json-gold/ld"
 	"github.com/trustbloc/edge-core/pkg/log"
 	"github.com/trustbloc/vct/pkg/client/vct"
+	"github.com/trustbloc/vct/pkg/controller/command"
 
+	"github.com/trustbloc/orb/pkg/activitypub/service/logallowlist"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
 	"github.com/trustbloc/orb/pkg/webfinger/model"
 )
 
@@ -41,12 +46,31 @@ type webfingerClient interface {
 	GetLedgerType(domain string) (string, error)
 }
 
+type logAllowList interface {
+	Get(logURL string) (*logallowlist.Log, error)
+}
+
+// ErrUntrustedLog is returned from Watch if the VCT log referenced in the proof is not trusted.
+var ErrUntrustedLog = errors.New("untrusted VCT log")
+
 // Client for the monitoring.
 type Client struct {
 	documentLoader ld.DocumentLoader
 	store          storage.Store
 	http           httpClient
 	wfClient       webfingerClient
+	logAllowList   logAllowList
+}
+
+// Option is a monitoring client option.
+type Option func(c *Client)
+
+// WithLogAllowList sets the allow list of trusted VCT logs. If set then Watch returns ErrUntrustedLog
+// for any log that isn't in the allow list or whose public key doesn't match the key in the allow list.
+func WithLogAllowList(l logAllowList) Option {
+	return func(c *Client) {
+		c.logAllowList = l
+	}
 }
 
 type taskManager interface {
@@ -55,7 +79,7 @@ type taskManager interface {
 
 // New returns monitoring client.
 func New(provider storage.Provider, documentLoader ld.DocumentLoader, wfClient webfingerClient,
-	httpClient httpClient, taskMgr taskManager, interval time.Duration) (*Client, error) {
+	httpClient httpClient, taskMgr taskManager, interval time.Duration, opts ...Option) (*Client, error) {
 	store, err := provider.OpenStore(storeName)
 	if err != nil {
 		return nil, fmt.Errorf("open store: %w", err)
@@ -73,6 +97,10 @@ func New(provider storage.Provider, documentLoader ld.DocumentLoader, wfClient w
 		wfClient:       wfClient,
 	}
 
+	for _, opt := range opts {
+		opt(client)
+	}
+
 	logger.Infof("Registering task [%s] to be run at intervals of %s", taskID, interval)
 
 	taskMgr.RegisterTask(taskID, interval, client.worker)
@@ -218,6 +246,11 @@ func (c *Client) Watch(vc *verifiable.Credential, endTime time.Time, domain stri
 		return nil
 	}
 
+	err := c.checkTrustedLog(domain)
+	if err != nil {
+		return err
+	}
+
 	lt, err := c.wfClient.GetLedgerType(domain)
 	if err != nil {
 		if errors.Is(err, model.ErrResourceNotFound) {
@@ -271,6 +304,49 @@ func (c *Client) Watch(vc *verifiable.Credential, endTime time.Time, domain stri
 	return c.store.Put(key(vc.ID), src, storage.Tag{Name: tagNotConfirmed})
 }
 
+// checkTrustedLog returns an error if a log allow list is configured and the given log is either not in the
+// allow list or the public key published by the log doesn't match the public key in the allow list.
+func (c *Client) checkTrustedLog(domain string) error {
+	if c.logAllowList == nil {
+		return nil
+	}
+
+	l, err := c.logAllowList.Get(domain)
+	if err != nil {
+		if errors.Is(err, orberrors.ErrContentNotFound) {
+			return fmt.Errorf("%w: log [%s] is not in the allow list", ErrUntrustedLog, domain)
+		}
+
+		return fmt.Errorf("get log [%s] from allow list: %w", domain, err)
+	}
+
+	if l.PublicKey == "" {
+		return nil
+	}
+
+	expectedKey, err := base64.StdEncoding.DecodeString(l.PublicKey)
+	if err != nil {
+		return fmt.Errorf("decode public key of log [%s] in allow list: %w", domain, err)
+	}
+
+	webResp, err := vct.New(domain, vct.WithHTTPClient(c.http)).Webfinger(context.Background())
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("webfinger log [%s]: %w", domain, err))
+	}
+
+	pubKeyStr, ok := webResp.Properties[command.PublicKeyType].(string)
+	if !ok {
+		return fmt.Errorf("%w: no public key published by log [%s]", ErrUntrustedLog, domain)
+	}
+
+	pubKey, err := base64.StdEncoding.DecodeString(pubKeyStr)
+	if err != nil || !bytes.Equal(pubKey, expectedKey) {
+		return fmt.Errorf("%w: public key of log [%s] does not match the allow list", ErrUntrustedLog, domain)
+	}
+
+	return nil
+}
+
 func key(id string) string {
 	return keyPrefix + id
 }
@@ -11,6 +11,7 @@ import (
 	"errors"
 	"io/ioutil"
 	"net/http"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -24,8 +25,10 @@ import (
 	"github.com/hyperledger/aries-framework-go/spi/storage"
 	"github.com/stretchr/testify/require"
 
+	"github.com/trustbloc/orb/pkg/activitypub/service/logallowlist"
 	"github.com/trustbloc/orb/pkg/activitypub/service/mocks"
 	. "github.com/trustbloc/orb/pkg/activitypub/service/monitoring"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
 	"github.com/trustbloc/orb/pkg/internal/testutil"
 	wfclient "github.com/trustbloc/orb/pkg/webfinger/client"
 )
@@ -524,6 +527,120 @@ func TestClient_Watch(t *testing.T) {
 	})
 }
 
+func TestClient_WatchLogAllowList(t *testing.T) {
+	const (
+		logURL    = "https://vct.com/maple2021"
+		publicKey = "BL0zrdTbR4mc1ZBuaXOh52IYeYKd9hlXrB3eZ+GR9WsHHGhrNaJJB9bpEXvM4zo2vnm34nQezBJ1/a/cQS/j+Q0="
+	)
+
+	wfClient := wfclient.New(wfclient.WithHTTPClient(
+		httpMock(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				Body:       ioutil.NopCloser(bytes.NewBufferString(webfingerPayload)),
+				StatusCode: http.StatusOK,
+			}, nil
+		})))
+
+	vctHTTPClient := httpMock(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "/.well-known/webfinger") {
+			return &http.Response{
+				Body: ioutil.NopCloser(bytes.NewBufferString(
+					`{"properties":{"https://trustbloc.dev/ns/public-key":"` + publicKey + `"}}`)),
+				StatusCode: http.StatusOK,
+			}, nil
+		}
+
+		return &http.Response{
+			Body:       ioutil.NopCloser(bytes.NewBufferString(`{"audit_path":[[]]}`)),
+			StatusCode: http.StatusOK,
+		}, nil
+	})
+
+	newVC := func() *verifiable.Credential {
+		ID := "https://orb.domain.com/" + uuid.New().String()
+
+		return &verifiable.Credential{
+			ID:      ID,
+			Context: []string{"https://www.w3.org/2018/credentials/v1"},
+			Subject: ID,
+			Issuer:  verifiable.Issuer{ID: ID},
+			Issued:  &util.TimeWrapper{},
+			Types:   []string{"VerifiableCredential"},
+		}
+	}
+
+	newClient := func(t *testing.T, httpClient httpMock, logs ...*logallowlist.Log) *Client {
+		t.Helper()
+
+		configStore, err := mem.NewProvider().OpenStore("orb-config")
+		require.NoError(t, err)
+
+		allowList := logallowlist.NewManager(configStore)
+		require.NoError(t, allowList.Update(logs, nil))
+
+		client, err := New(mem.NewProvider(), testutil.GetLoader(t), wfClient, httpClient,
+			mocks.NewTaskManager("vct-monitor"), time.Second, WithLogAllowList(allowList))
+		require.NoError(t, err)
+
+		return client
+	}
+
+	t.Run("Log in allow list", func(t *testing.T) {
+		client := newClient(t, vctHTTPClient, &logallowlist.Log{URL: logURL})
+
+		require.NoError(t, client.Watch(newVC(), time.Now().Add(time.Minute), logURL, time.Now()))
+	})
+
+	t.Run("Log in allow list with matching public key", func(t *testing.T) {
+		client := newClient(t, vctHTTPClient, &logallowlist.Log{URL: logURL + "/", PublicKey: publicKey})
+
+		require.NoError(t, client.Watch(newVC(), time.Now().Add(time.Minute), logURL, time.Now()))
+	})
+
+	t.Run("Log not in allow list", func(t *testing.T) {
+		client := newClient(t, vctHTTPClient, &logallowlist.Log{URL: "https://vct2.com/maple2021"})
+
+		err := client.Watch(newVC(), time.Now().Add(time.Minute), logURL, time.Now())
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrUntrustedLog))
+		require.Contains(t, err.Error(), "is not in the allow list")
+	})
+
+	t.Run("Public key mismatch", func(t *testing.T) {
+		client := newClient(t, vctHTTPClient, &logallowlist.Log{URL: logURL, PublicKey: "cHVia2V5"})
+
+		err := client.Watch(newVC(), time.Now().Add(time.Minute), logURL, time.Now())
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrUntrustedLog))
+		require.Contains(t, err.Error(), "does not match the allow list")
+	})
+
+	t.Run("No public key published by log", func(t *testing.T) {
+		client := newClient(t, httpMock(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				Body:       ioutil.NopCloser(bytes.NewBufferString(`{"properties":{}}`)),
+				StatusCode: http.StatusOK,
+			}, nil
+		}), &logallowlist.Log{URL: logURL, PublicKey: publicKey})
+
+		err := client.Watch(newVC(), time.Now().Add(time.Minute), logURL, time.Now())
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrUntrustedLog))
+		require.Contains(t, err.Error(), "no public key published by log")
+	})
+
+	t.Run("Log webfinger error", func(t *testing.T) {
+		client := newClient(t, httpMock(func(req *http.Request) (*http.Response, error) {
+			return nil, errors.New("injected HTTP error")
+		}), &logallowlist.Log{URL: logURL, PublicKey: publicKey})
+
+		err := client.Watch(newVC(), time.Now().Add(time.Minute), logURL, time.Now())
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "injected HTTP error")
+	})
+}
+
 func checkQueue(t *testing.T, db storage.Provider, expected int) {
 	t.Helper()
 
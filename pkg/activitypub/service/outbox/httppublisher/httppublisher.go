@@ -7,14 +7,18 @@ SPDX-License-Identifier: Apache-2.0
 package httppublisher
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
+	"time"
 
 	wmhttp "github.com/ThreeDotsLabs/watermill-http/pkg/http"
 	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/ThreeDotsLabs/watermill/message/router/middleware"
 	"github.com/trustbloc/edge-core/pkg/log"
 
 	"github.com/trustbloc/orb/pkg/activitypub/client/transport"
@@ -23,13 +27,27 @@ import (
 
 var logger = log.New("activitypub_service")
 
-// MetadataSendTo is the metadata key for the destination URL.
-const MetadataSendTo = "send_to"
+const (
+	// MetadataSendTo is the metadata key for the destination URL.
+	MetadataSendTo = "send_to"
+
+	// MetadataContentEncoding is the metadata key for the content encoding (for example, "gzip") with which the
+	// payload is sent. The payload is sent as is if the metadata isn't specified.
+	MetadataContentEncoding = "content_encoding"
+)
 
 type httpTransport interface {
 	Post(ctx context.Context, req *transport.Request, payload []byte) (*http.Response, error)
 }
 
+type deliveryListener interface {
+	Delivered(inbox *url.URL, latency time.Duration, err error)
+}
+
+type deliveryReceipts interface {
+	DeliveryAttempted(activityID string, inbox *url.URL, err error)
+}
+
 // Publisher is an implementation of a Watermill Publisher that publishes messages over HTTP.
 type Publisher struct {
 	*lifecycle.Lifecycle
@@ -38,10 +56,30 @@ type Publisher struct {
 	httpTransport  httpTransport
 	jsonMarshal    func(v interface{}) ([]byte, error)
 	newRequestFunc func(string, *message.Message) (*transport.Request, error)
+	listener       deliveryListener
+	receipts       deliveryReceipts
+}
+
+// Opt is an HTTP Publisher option.
+type Opt func(p *Publisher)
+
+// WithDeliveryListener sets the listener that's notified of the result (and latency) of each delivery.
+func WithDeliveryListener(listener deliveryListener) Opt {
+	return func(p *Publisher) {
+		p.listener = listener
+	}
+}
+
+// WithDeliveryReceipts sets the store that records the result of each delivery attempt of an activity. The ID of
+// the activity is taken from the correlation ID of the message.
+func WithDeliveryReceipts(receipts deliveryReceipts) Opt {
+	return func(p *Publisher) {
+		p.receipts = receipts
+	}
 }
 
 // New creates a new HTTP Publisher.
-func New(serviceName string, t httpTransport) *Publisher {
+func New(serviceName string, t httpTransport, opts ...Opt) *Publisher {
 	p := &Publisher{
 		ServiceName:   serviceName,
 		Lifecycle:     lifecycle.New(serviceName),
@@ -51,6 +89,10 @@ func New(serviceName string, t httpTransport) *Publisher {
 
 	p.newRequestFunc = p.newRequest
 
+	for _, opt := range opts {
+		opt(p)
+	}
+
 	// The service must be started immediately.
 	p.Start()
 
@@ -84,7 +126,30 @@ func (p *Publisher) publish(topic string, msg *message.Message) error {
 
 	logger.Debugf("[%s] Sending message [%s] to [%s] ", p.ServiceName, msg.UUID, req.URL)
 
-	resp, err := p.httpTransport.Post(context.Background(), req, msg.Payload)
+	start := time.Now()
+
+	err = p.post(req, msg)
+
+	if p.listener != nil {
+		p.listener.Delivered(req.URL, time.Since(start), err)
+	}
+
+	if p.receipts != nil {
+		if activityID := middleware.MessageCorrelationID(msg); activityID != "" {
+			p.receipts.DeliveryAttempted(activityID, req.URL, err)
+		}
+	}
+
+	return err
+}
+
+func (p *Publisher) post(req *transport.Request, msg *message.Message) error {
+	payload, err := encode(msg.Payload, req.Header.Get(transport.ContentEncodingHeader))
+	if err != nil {
+		return fmt.Errorf("encode message [%s]: %w", msg.UUID, err)
+	}
+
+	resp, err := p.httpTransport.Post(context.Background(), req, payload)
 	if err != nil {
 		return fmt.Errorf("send message [%s]: %w", msg.UUID, err)
 	}
@@ -116,14 +181,59 @@ func (p *Publisher) newRequest(_ string, msg *message.Message) (*transport.Reque
 		return nil, fmt.Errorf("parse URL %s: %w", to, err)
 	}
 
-	metadataBytes, err := p.jsonMarshal(msg.Metadata)
+	metadata := msg.Metadata
+
+	encoding, ok := metadata[MetadataContentEncoding]
+	if ok {
+		if encoding != transport.GzipContentEncoding {
+			return nil, fmt.Errorf("unsupported content encoding [%s]", encoding)
+		}
+
+		// The content encoding applies to this hop only so it isn't passed on to the receiver.
+		metadata = make(message.Metadata, len(msg.Metadata))
+
+		for k, v := range msg.Metadata {
+			if k != MetadataContentEncoding {
+				metadata[k] = v
+			}
+		}
+	}
+
+	metadataBytes, err := p.jsonMarshal(metadata)
 	if err != nil {
 		return nil, fmt.Errorf("marshal metadata to JSON: %w", err)
 	}
 
-	return transport.NewRequest(toURL,
+	opts := []transport.Option{
 		transport.WithHeader(transport.AcceptHeader, transport.ActivityStreamsContentType),
 		transport.WithHeader(wmhttp.HeaderUUID, msg.UUID),
 		transport.WithHeader(wmhttp.HeaderMetadata, string(metadataBytes)),
-	), nil
+	}
+
+	if encoding != "" {
+		opts = append(opts, transport.WithHeader(transport.ContentEncodingHeader, encoding))
+	}
+
+	return transport.NewRequest(toURL, opts...), nil
+}
+
+// encode compresses the payload if the content encoding is gzip.
+func encode(payload []byte, encoding string) ([]byte, error) {
+	if encoding != transport.GzipContentEncoding {
+		return payload, nil
+	}
+
+	buf := &bytes.Buffer{}
+
+	zw := gzip.NewWriter(buf)
+
+	if _, err := zw.Write(payload); err != nil {
+		return nil, err
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
 }
@@ -7,18 +7,23 @@ SPDX-License-Identifier: Apache-2.0
 package httppublisher
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/ThreeDotsLabs/watermill"
 	wmhttp "github.com/ThreeDotsLabs/watermill-http/pkg/http"
 	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/ThreeDotsLabs/watermill/message/router/middleware"
 	"github.com/stretchr/testify/require"
 	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
 
@@ -46,7 +51,7 @@ func TestPublisher_Publish(t *testing.T) {
 
 	httpServer := httpserver.New(":8100", "", "",
 		newTestHandler("/services/service1", func(w http.ResponseWriter, req *http.Request) {
-			payload, err := ioutil.ReadAll(req.Body)
+			payload, err := readBody(req)
 			if err != nil {
 				w.WriteHeader(http.StatusBadRequest)
 
@@ -115,6 +120,76 @@ func TestPublisher_Publish(t *testing.T) {
 		require.Equal(t, payload2, []byte(m2.Payload))
 	})
 
+	t.Run("Gzip", func(t *testing.T) {
+		payload := []byte("payload1")
+
+		msg := message.NewMessage(watermill.NewUUID(), payload)
+		msg.Metadata[MetadataSendTo] = serviceURL
+		msg.Metadata[MetadataContentEncoding] = transport.GzipContentEncoding
+
+		require.NoError(t, p.Publish("topic", msg))
+
+		mutex.RLock()
+		m, ok := messagesReceived[msg.UUID]
+		mutex.RUnlock()
+
+		require.True(t, ok)
+		require.Equal(t, payload, []byte(m.Payload))
+		require.Empty(t, m.Metadata[MetadataContentEncoding])
+	})
+
+	t.Run("Delivery listener", func(t *testing.T) {
+		l := &mockDeliveryListener{}
+
+		pl := New("service1", transport.Default(), WithDeliveryListener(l))
+
+		msg := message.NewMessage(watermill.NewUUID(), []byte("payload"))
+		msg.Metadata[MetadataSendTo] = serviceURL
+
+		require.NoError(t, pl.Publish("topic", msg))
+
+		msg = message.NewMessage(watermill.NewUUID(), []byte("payload"))
+		msg.Metadata[MetadataSendTo] = "http://localhost:8100/services/unknown"
+
+		require.Error(t, pl.Publish("topic", msg))
+
+		require.Len(t, l.inboxes, 2)
+		require.Equal(t, serviceURL, l.inboxes[0].String())
+		require.NoError(t, l.errs[0])
+		require.Equal(t, "http://localhost:8100/services/unknown", l.inboxes[1].String())
+		require.Error(t, l.errs[1])
+	})
+
+	t.Run("Delivery receipts", func(t *testing.T) {
+		r := &mockDeliveryReceipts{}
+
+		pl := New("service1", transport.Default(), WithDeliveryReceipts(r))
+
+		msg := message.NewMessage(watermill.NewUUID(), []byte("payload"))
+		msg.Metadata[MetadataSendTo] = serviceURL
+		middleware.SetCorrelationID("https://domain1.com/services/orb/activities/1", msg)
+
+		require.NoError(t, pl.Publish("topic", msg))
+
+		msg = message.NewMessage(watermill.NewUUID(), []byte("payload"))
+		msg.Metadata[MetadataSendTo] = "http://localhost:8100/services/unknown"
+		middleware.SetCorrelationID("https://domain1.com/services/orb/activities/2", msg)
+
+		require.Error(t, pl.Publish("topic", msg))
+
+		// No correlation ID -> no receipt.
+		msg = message.NewMessage(watermill.NewUUID(), []byte("payload"))
+		msg.Metadata[MetadataSendTo] = serviceURL
+
+		require.NoError(t, pl.Publish("topic", msg))
+
+		require.Len(t, r.activityIDs, 2)
+		require.Equal(t, "https://domain1.com/services/orb/activities/1", r.activityIDs[0])
+		require.NoError(t, r.errs[0])
+		require.Equal(t, "https://domain1.com/services/orb/activities/2", r.activityIDs[1])
+		require.Error(t, r.errs[1])
+	})
+
 	t.Run("NewRequest error", func(t *testing.T) {
 		err := p.Publish("topic", message.NewMessage(watermill.NewUUID(), []byte("payload")))
 		require.Error(t, err)
@@ -158,6 +233,29 @@ func TestNewRequest(t *testing.T) {
 		require.Equal(t, serviceURL, md[MetadataSendTo])
 	})
 
+	t.Run("Content encoding", func(t *testing.T) {
+		msg := message.NewMessage(watermill.NewUUID(), []byte("payload1"))
+		msg.Metadata[MetadataSendTo] = serviceURL
+		msg.Metadata[MetadataContentEncoding] = transport.GzipContentEncoding
+
+		req, err := p.newRequest("", msg)
+		require.NoError(t, err)
+		require.Equal(t, transport.GzipContentEncoding, req.Header.Get(transport.ContentEncodingHeader))
+
+		var md message.Metadata
+		require.NoError(t, json.Unmarshal([]byte(req.Header.Get(wmhttp.HeaderMetadata)), &md))
+		require.Equal(t, serviceURL, md[MetadataSendTo])
+		require.Empty(t, md[MetadataContentEncoding])
+
+		// The metadata of the message is unchanged.
+		require.Equal(t, transport.GzipContentEncoding, msg.Metadata[MetadataContentEncoding])
+
+		msg.Metadata[MetadataContentEncoding] = "br"
+
+		_, err = p.newRequest("", msg)
+		require.EqualError(t, err, "unsupported content encoding [br]")
+	})
+
 	t.Run("No SendTo metadata", func(t *testing.T) {
 		_, err := p.newRequest("", message.NewMessage(watermill.NewUUID(), []byte("payload")))
 		require.EqualError(t, err, "metadata [send_to] not found in message")
@@ -187,6 +285,24 @@ func TestNewRequest(t *testing.T) {
 	})
 }
 
+func readBody(req *http.Request) ([]byte, error) {
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Header.Get(transport.ContentEncodingHeader) != transport.GzipContentEncoding {
+		return body, nil
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	return ioutil.ReadAll(zr)
+}
+
 type testHandler struct {
 	path    string
 	handler common.HTTPRequestHandler
@@ -210,3 +326,23 @@ func (m *testHandler) Method() string {
 func (m *testHandler) Handler() common.HTTPRequestHandler {
 	return m.handler
 }
+
+type mockDeliveryListener struct {
+	inboxes []*url.URL
+	errs    []error
+}
+
+func (m *mockDeliveryListener) Delivered(inbox *url.URL, _ time.Duration, err error) {
+	m.inboxes = append(m.inboxes, inbox)
+	m.errs = append(m.errs, err)
+}
+
+type mockDeliveryReceipts struct {
+	activityIDs []string
+	errs        []error
+}
+
+func (m *mockDeliveryReceipts) DeliveryAttempted(activityID string, _ *url.URL, err error) {
+	m.activityIDs = append(m.activityIDs, activityID)
+	m.errs = append(m.errs, err)
+}
@@ -9,6 +9,7 @@ package outbox
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -46,6 +47,10 @@ const (
 	defaultConcurrentHTTPRequests = 10
 	defaultCacheSize              = 100
 	defaultCacheExpiration        = time.Minute
+
+	// minCompressedPayloadSize is the minimum size of an activity that's compressed when it's delivered to
+	// an inbox that supports compression. Smaller activities aren't worth compressing.
+	minCompressedPayloadSize = 1024
 )
 
 type redeliveryService interface {
@@ -101,6 +106,12 @@ type Outbox struct {
 	jsonUnmarshal        func(data []byte, v interface{}) error
 	iriCache             gcache.Cache
 	metrics              metricsProvider
+	deliveryReceipts     service.DeliveryReceipts
+	pendingDeliveries    service.PendingDeliveries
+
+	// gzipInboxes contains the inboxes (keyed by URL) of the actors that advertise support for gzip-compressed
+	// requests in their capabilities.
+	gzipInboxes sync.Map
 }
 
 type httpTransport interface {
@@ -144,6 +155,8 @@ func New(cnfg *Config, s store.Store, pubSub pubSub, t httpTransport, activityHa
 		jsonMarshal:          json.Marshal,
 		jsonUnmarshal:        json.Unmarshal,
 		metrics:              metrics,
+		deliveryReceipts:     options.DeliveryReceipts,
+		pendingDeliveries:    options.PendingDeliveries,
 	}
 
 	h.Lifecycle = lifecycle.New(cfg.ServiceName,
@@ -164,7 +177,12 @@ func New(cnfg *Config, s store.Store, pubSub pubSub, t httpTransport, activityHa
 		panic(err)
 	}
 
-	httpPublisher := httppublisher.New(cfg.ServiceName, t)
+	httpPublisher := httppublisher.New(cfg.ServiceName, t,
+		httppublisher.WithDeliveryListener(options.DeliveryListener),
+		httppublisher.WithDeliveryReceipts(&deliveryTracker{
+			DeliveryReceipts: options.DeliveryReceipts,
+			pending:          options.PendingDeliveries,
+		}))
 
 	router.AddHandler(
 		"outbox-"+cfg.ServiceName, cfg.Topic,
@@ -194,6 +212,9 @@ func (h *Outbox) start() {
 
 	// Wait for router to start
 	<-h.router.Running()
+
+	// Resume the deliveries that were pending when the service was last stopped.
+	go h.resumePendingDeliveries()
 }
 
 func (h *Outbox) stop() {
@@ -250,6 +271,19 @@ func (h *Outbox) Post(activity *vocab.ActivityType) (*url.URL, error) {
 		return nil, fmt.Errorf("resolve inboxes: %w", err)
 	}
 
+	err = h.deliveryReceipts.Pending(activity.ID().URL(), inboxes)
+	if err != nil {
+		// The delivery receipts are informational, so the activity is still published.
+		logger.Warnf("[%s] Error storing delivery receipts for activity [%s]: %s", h.ServiceName, activity.ID(), err)
+	}
+
+	err = h.pendingDeliveries.Add(activity.ID().URL(), inboxes)
+	if err != nil {
+		// The activity is still published but it won't be redelivered if the service is restarted before
+		// delivery completes.
+		logger.Warnf("[%s] Error storing pending deliveries for activity [%s]: %s", h.ServiceName, activity.ID(), err)
+	}
+
 	for _, actorInbox := range inboxes {
 		err = h.publish(activity.ID().String(), activityBytes, actorInbox)
 		if err != nil {
@@ -286,6 +320,10 @@ func (h *Outbox) publish(id string, activityBytes []byte, to fmt.Stringer) error
 	msg.Metadata.Set(metadataEventType, h.Topic)
 	msg.Metadata.Set(httppublisher.MetadataSendTo, to.String())
 
+	if len(activityBytes) >= minCompressedPayloadSize && h.supportsGzip(to.String()) {
+		msg.Metadata.Set(httppublisher.MetadataContentEncoding, transport.GzipContentEncoding)
+	}
+
 	middleware.SetCorrelationID(id, msg)
 
 	logger.Debugf("[%s] Publishing %s", h.ServiceName, h.Topic)
@@ -330,6 +368,8 @@ func (h *Outbox) handleUndeliverableActivity(msg *message.Message) {
 			h.ServiceName, activity.ID(), toURL, err)
 
 		h.undeliverableHandler.HandleUndeliverableActivity(activity, toURL)
+
+		h.removePendingDelivery(msg.Metadata[middleware.CorrelationIDMetadataKey], toURL)
 	} else {
 		activityID := msg.Metadata[middleware.CorrelationIDMetadataKey]
 
@@ -338,6 +378,77 @@ func (h *Outbox) handleUndeliverableActivity(msg *message.Message) {
 	}
 }
 
+func (h *Outbox) removePendingDelivery(activityID, toURL string) {
+	inbox, err := url.Parse(toURL)
+	if err != nil {
+		logger.Warnf("[%s] Invalid inbox URL [%s]: %s", h.ServiceName, toURL, err)
+
+		return
+	}
+
+	if err := h.pendingDeliveries.Remove(activityID, inbox); err != nil {
+		logger.Warnf("[%s] Error removing pending delivery of activity [%s] to [%s]: %s",
+			h.ServiceName, activityID, inbox, err)
+	}
+}
+
+// resumePendingDeliveries publishes the activities whose delivery didn't complete before the service was
+// last stopped. Since the pending delivery is only removed after the inbox acknowledges the activity, an
+// activity may be delivered to the same inbox more than once.
+func (h *Outbox) resumePendingDeliveries() {
+	pending, err := h.pendingDeliveries.GetAll()
+	if err != nil {
+		logger.Errorf("[%s] Error retrieving pending deliveries: %s", h.ServiceName, err)
+
+		return
+	}
+
+	if len(pending) == 0 {
+		return
+	}
+
+	logger.Infof("[%s] Resuming delivery of %d activities", h.ServiceName, len(pending))
+
+	for _, p := range pending {
+		h.resumePendingDelivery(p)
+	}
+}
+
+func (h *Outbox) resumePendingDelivery(p *service.PendingDelivery) {
+	activity, err := h.activityStore.GetActivity(p.ActivityID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			logger.Warnf("[%s] Activity [%s] of pending delivery not found. The pending delivery is abandoned.",
+				h.ServiceName, p.ActivityID)
+
+			for _, inbox := range p.Inboxes {
+				h.removePendingDelivery(p.ActivityID.String(), inbox.String())
+			}
+		} else {
+			logger.Errorf("[%s] Error retrieving activity [%s] of pending delivery: %s",
+				h.ServiceName, p.ActivityID, err)
+		}
+
+		return
+	}
+
+	activityBytes, err := h.jsonMarshal(activity)
+	if err != nil {
+		logger.Errorf("[%s] Error marshalling activity [%s] of pending delivery: %s", h.ServiceName, p.ActivityID, err)
+
+		return
+	}
+
+	for _, inbox := range p.Inboxes {
+		logger.Debugf("[%s] Resuming delivery of activity [%s] to [%s]", h.ServiceName, p.ActivityID, inbox)
+
+		if err := h.publish(p.ActivityID.String(), activityBytes, inbox); err != nil {
+			logger.Errorf("[%s] Error resuming delivery of activity [%s] to [%s]: %s",
+				h.ServiceName, p.ActivityID, inbox, err)
+		}
+	}
+}
+
 func (h *Outbox) redeliver() {
 	for msg := range h.redeliveryChan {
 		logger.Infof("[%s] Attempting to redeliver message [%s]", h.ServiceName, msg.UUID)
@@ -383,9 +494,26 @@ func (h *Outbox) resolveInbox(iri *url.URL) (*url.URL, error) {
 		return nil, err
 	}
 
+	// The capabilities of the actor are recorded so that the activity is delivered in a format that's supported
+	// by the actor. An actor that doesn't publish its capabilities (e.g. an older version) is sent uncompressed
+	// activities.
+	if actor.Inbox() != nil {
+		if actor.Capabilities().SupportsContentEncoding(transport.GzipContentEncoding) {
+			h.gzipInboxes.Store(actor.Inbox().String(), true)
+		} else {
+			h.gzipInboxes.Delete(actor.Inbox().String())
+		}
+	}
+
 	return actor.Inbox(), nil
 }
 
+func (h *Outbox) supportsGzip(inbox string) bool {
+	_, ok := h.gzipInboxes.Load(inbox)
+
+	return ok
+}
+
 func (h *Outbox) resolveActorIRIs(iri *url.URL) ([]*url.URL, error) {
 	if iri.String() == vocab.PublicIRI.String() {
 		// Should not attempt to publish to the 'Public' URI.
@@ -597,6 +725,54 @@ type noOpUndeliverableHandler struct{}
 func (h *noOpUndeliverableHandler) HandleUndeliverableActivity(*vocab.ActivityType, string) {
 }
 
+type noOpDeliveryListener struct{}
+
+func (l *noOpDeliveryListener) Delivered(*url.URL, time.Duration, error) {
+}
+
+type noOpDeliveryReceipts struct{}
+
+func (r *noOpDeliveryReceipts) Pending(*url.URL, []*url.URL) error {
+	return nil
+}
+
+func (r *noOpDeliveryReceipts) DeliveryAttempted(string, *url.URL, error) {
+}
+
+type noOpPendingDeliveries struct{}
+
+func (p *noOpPendingDeliveries) Add(*url.URL, []*url.URL) error {
+	return nil
+}
+
+func (p *noOpPendingDeliveries) Remove(string, *url.URL) error {
+	return nil
+}
+
+func (p *noOpPendingDeliveries) GetAll() ([]*service.PendingDelivery, error) {
+	return nil, nil
+}
+
+// deliveryTracker forwards delivery attempts to the delivery receipts and removes the pending delivery
+// after the activity is successfully delivered to the inbox.
+type deliveryTracker struct {
+	service.DeliveryReceipts
+
+	pending service.PendingDeliveries
+}
+
+func (t *deliveryTracker) DeliveryAttempted(activityID string, inbox *url.URL, err error) {
+	t.DeliveryReceipts.DeliveryAttempted(activityID, inbox, err)
+
+	if err != nil {
+		return
+	}
+
+	if e := t.pending.Remove(activityID, inbox); e != nil {
+		logger.Warnf("Error removing pending delivery of activity [%s] to [%s]: %s", activityID, inbox, e)
+	}
+}
+
 func newHandlerOptions(opts []service.HandlerOpt) *service.Handlers {
 	options := defaultOptions()
 
@@ -610,5 +786,8 @@ func newHandlerOptions(opts []service.HandlerOpt) *service.Handlers {
 func defaultOptions() *service.Handlers {
 	return &service.Handlers{
 		UndeliverableHandler: &noOpUndeliverableHandler{},
+		DeliveryListener:     &noOpDeliveryListener{},
+		DeliveryReceipts:     &noOpDeliveryReceipts{},
+		PendingDeliveries:    &noOpPendingDeliveries{},
 	}
 }
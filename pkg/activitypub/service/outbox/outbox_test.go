@@ -13,12 +13,14 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"strconv"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/ThreeDotsLabs/watermill/message"
 	"github.com/stretchr/testify/require"
 	"github.com/trustbloc/edge-core/pkg/log"
 	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
@@ -27,6 +29,7 @@ import (
 	"github.com/trustbloc/orb/pkg/activitypub/client/transport"
 	"github.com/trustbloc/orb/pkg/activitypub/resthandler"
 	"github.com/trustbloc/orb/pkg/activitypub/service/mocks"
+	"github.com/trustbloc/orb/pkg/activitypub/service/outbox/httppublisher"
 	"github.com/trustbloc/orb/pkg/activitypub/service/spi"
 	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
 	store "github.com/trustbloc/orb/pkg/activitypub/store/spi"
@@ -185,6 +188,8 @@ func TestOutbox_Post(t *testing.T) {
 	undeliverableHandler := mocks.NewUndeliverableHandler()
 	activityStore := memstore.New("service1")
 	pubSub := mocks.NewPubSub()
+	receipts := &mockDeliveryReceipts{}
+	pendingDeliveries := &mockPendingDeliveries{}
 
 	require.NoError(t, activityStore.AddReference(store.Follower, service1URL, service2URL))
 
@@ -203,7 +208,8 @@ func TestOutbox_Post(t *testing.T) {
 
 	ob, err := New(cfg, activityStore, pubSub, transport.Default(),
 		&mocks.ActivityHandler{}, client.New(client.Config{}, transport.Default()), &mocks.WebFingerResolver{},
-		&orbmocks.MetricsProvider{}, spi.WithUndeliverableHandler(undeliverableHandler))
+		&orbmocks.MetricsProvider{}, spi.WithUndeliverableHandler(undeliverableHandler),
+		spi.WithDeliveryReceipts(receipts), spi.WithPendingDeliveries(pendingDeliveries))
 	require.NoError(t, err)
 	require.NotNil(t, ob)
 
@@ -246,6 +252,16 @@ func TestOutbox_Post(t *testing.T) {
 	require.True(t, ok)
 	mutex.RUnlock()
 
+	receipts.mutex.RLock()
+	require.Len(t, receipts.pending[activity.ID().String()], 4)
+	require.Len(t, receipts.attempted[activity.ID().String()], 4)
+	receipts.mutex.RUnlock()
+
+	pendingDeliveries.mutex.RLock()
+	require.Len(t, pendingDeliveries.added[activity.ID().String()], 4)
+	require.Len(t, pendingDeliveries.removed[activity.ID().String()], 4)
+	pendingDeliveries.mutex.RUnlock()
+
 	a, err := activityStore.GetActivity(activity.ID().URL())
 	require.NoError(t, err)
 	require.NotNil(t, a)
@@ -270,6 +286,78 @@ func TestOutbox_Post(t *testing.T) {
 	ob.Stop()
 }
 
+func TestOutbox_ResumePendingDeliveries(t *testing.T) {
+	service1URL := testutil.MustParseURL("http://localhost:8002/services/service1")
+
+	var mutex sync.RWMutex
+
+	activitiesReceived := make(map[string]*vocab.ActivityType)
+
+	inboxServer := httptest.NewServer(http.HandlerFunc(mockInboxHandler(t, func(activity *vocab.ActivityType) {
+		mutex.Lock()
+		activitiesReceived[activity.ID().String()] = activity
+		mutex.Unlock()
+	})))
+	defer inboxServer.Close()
+
+	inboxURL := testutil.MustParseURL(inboxServer.URL + "/services/service2/inbox")
+
+	activityStore := memstore.New("service1")
+
+	activity1 := vocab.NewCreateActivity(
+		vocab.NewObjectProperty(vocab.WithIRI(testutil.MustParseURL("http://example.com/transactions/txn1"))),
+		vocab.WithID(testutil.MustParseURL("http://localhost:8002/services/service1/activities/1")),
+	)
+	require.NoError(t, activityStore.AddActivity(activity1))
+
+	// Activity 2 isn't in the activity store so its pending delivery should be abandoned.
+	activity2ID := testutil.MustParseURL("http://localhost:8002/services/service1/activities/2")
+
+	pendingDeliveries := &mockPendingDeliveries{
+		all: []*spi.PendingDelivery{
+			{ActivityID: activity1.ID().URL(), Inboxes: []*url.URL{inboxURL}},
+			{ActivityID: activity2ID, Inboxes: []*url.URL{inboxURL}},
+		},
+	}
+
+	cfg := &Config{
+		ServiceName: "service1",
+		ServiceIRI:  service1URL,
+		Topic:       "activities",
+	}
+
+	ob, err := New(cfg, activityStore, mocks.NewPubSub(), transport.Default(),
+		&mocks.ActivityHandler{}, mocks.NewActivitPubClient(), &mocks.WebFingerResolver{},
+		&orbmocks.MetricsProvider{}, spi.WithPendingDeliveries(pendingDeliveries))
+	require.NoError(t, err)
+
+	ob.Start()
+	defer ob.Stop()
+
+	time.Sleep(250 * time.Millisecond)
+
+	mutex.RLock()
+	_, ok := activitiesReceived[activity1.ID().String()]
+	require.True(t, ok)
+	mutex.RUnlock()
+
+	pendingDeliveries.mutex.RLock()
+	require.Len(t, pendingDeliveries.removed[activity1.ID().String()], 1)
+	require.Len(t, pendingDeliveries.removed[activity2ID.String()], 1)
+	pendingDeliveries.mutex.RUnlock()
+
+	t.Run("GetAll error", func(t *testing.T) {
+		pendingDeliveries := &mockPendingDeliveries{err: errors.New("injected GetAll error")}
+
+		ob, err := New(cfg, activityStore, mocks.NewPubSub(), transport.Default(),
+			&mocks.ActivityHandler{}, mocks.NewActivitPubClient(), &mocks.WebFingerResolver{},
+			&orbmocks.MetricsProvider{}, spi.WithPendingDeliveries(pendingDeliveries))
+		require.NoError(t, err)
+
+		require.NotPanics(t, ob.resumePendingDeliveries)
+	})
+}
+
 func TestOutbox_PostError(t *testing.T) {
 	log.SetLevel("activitypub_service", log.DEBUG)
 
@@ -594,12 +682,140 @@ func TestResolveInboxes(t *testing.T) {
 	})
 }
 
+func TestOutbox_ContentEncoding(t *testing.T) {
+	service1URL := testutil.MustParseURL("http://localhost:8002/services/service1")
+	service2URL := testutil.MustParseURL("http://localhost:8002/services/service2")
+	service3URL := testutil.MustParseURL("http://localhost:8002/services/service3")
+
+	// Service2 supports gzip-compressed requests whereas service3 doesn't publish its capabilities.
+	service2 := vocab.NewService(service2URL,
+		vocab.WithInbox(testutil.NewMockID(service2URL, resthandler.InboxPath)),
+		vocab.WithCapabilities(&vocab.CapabilitiesType{ContentEncodings: []string{"gzip"}}),
+	)
+	service3 := aptestutil.NewMockService(service3URL)
+
+	apClient := mocks.NewActivitPubClient().WithActor(service2).WithActor(service3)
+
+	cfg := &Config{
+		ServiceName: "service1",
+		ServiceIRI:  service1URL,
+		Topic:       "activities",
+	}
+
+	ob, err := New(cfg, &mocks.ActivityStore{}, mocks.NewPubSub(), transport.Default(),
+		&mocks.ActivityHandler{}, apClient, &mocks.WebFingerResolver{}, &orbmocks.MetricsProvider{})
+	require.NoError(t, err)
+
+	publisher := &mockPublisher{}
+	ob.publisher = publisher
+
+	inbox2, err := ob.resolveInbox(service2URL)
+	require.NoError(t, err)
+
+	inbox3, err := ob.resolveInbox(service3URL)
+	require.NoError(t, err)
+
+	largeActivity := make([]byte, minCompressedPayloadSize)
+
+	require.NoError(t, ob.publish("activity1", largeActivity, inbox2))
+	require.NoError(t, ob.publish("activity2", []byte("{}"), inbox2))
+	require.NoError(t, ob.publish("activity3", largeActivity, inbox3))
+
+	require.Len(t, publisher.messages, 3)
+	require.Equal(t, transport.GzipContentEncoding,
+		publisher.messages[0].Metadata[httppublisher.MetadataContentEncoding])
+	require.Empty(t, publisher.messages[1].Metadata[httppublisher.MetadataContentEncoding])
+	require.Empty(t, publisher.messages[2].Metadata[httppublisher.MetadataContentEncoding])
+}
+
+type mockPublisher struct {
+	messages []*message.Message
+}
+
+func (m *mockPublisher) Publish(_ string, messages ...*message.Message) error {
+	m.messages = append(m.messages, messages...)
+
+	return nil
+}
+
+func (m *mockPublisher) Close() error {
+	return nil
+}
+
 type testHandler struct {
 	path    string
 	method  string
 	handler common.HTTPRequestHandler
 }
 
+type mockDeliveryReceipts struct {
+	mutex     sync.RWMutex
+	pending   map[string][]*url.URL
+	attempted map[string][]error
+}
+
+func (m *mockDeliveryReceipts) Pending(activityID *url.URL, inboxes []*url.URL) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.pending == nil {
+		m.pending = make(map[string][]*url.URL)
+	}
+
+	m.pending[activityID.String()] = inboxes
+
+	return nil
+}
+
+func (m *mockDeliveryReceipts) DeliveryAttempted(activityID string, _ *url.URL, err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.attempted == nil {
+		m.attempted = make(map[string][]error)
+	}
+
+	m.attempted[activityID] = append(m.attempted[activityID], err)
+}
+
+type mockPendingDeliveries struct {
+	mutex   sync.RWMutex
+	all     []*spi.PendingDelivery
+	err     error
+	added   map[string][]*url.URL
+	removed map[string][]*url.URL
+}
+
+func (m *mockPendingDeliveries) Add(activityID *url.URL, inboxes []*url.URL) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.added == nil {
+		m.added = make(map[string][]*url.URL)
+	}
+
+	m.added[activityID.String()] = append(m.added[activityID.String()], inboxes...)
+
+	return m.err
+}
+
+func (m *mockPendingDeliveries) Remove(activityID string, inbox *url.URL) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.removed == nil {
+		m.removed = make(map[string][]*url.URL)
+	}
+
+	m.removed[activityID] = append(m.removed[activityID], inbox)
+
+	return m.err
+}
+
+func (m *mockPendingDeliveries) GetAll() ([]*spi.PendingDelivery, error) {
+	return m.all, m.err
+}
+
 func newTestHandler(path, method string, handler common.HTTPRequestHandler) *testHandler {
 	return &testHandler{
 		path:    path,
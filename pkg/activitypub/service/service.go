@@ -21,6 +21,7 @@ import (
 	"github.com/trustbloc/orb/pkg/activitypub/resthandler"
 	"github.com/trustbloc/orb/pkg/activitypub/service/activityhandler"
 	"github.com/trustbloc/orb/pkg/activitypub/service/inbox"
+	"github.com/trustbloc/orb/pkg/activitypub/service/inbox/fairqueue"
 	"github.com/trustbloc/orb/pkg/activitypub/service/outbox"
 	"github.com/trustbloc/orb/pkg/activitypub/service/spi"
 	store "github.com/trustbloc/orb/pkg/activitypub/store/spi"
@@ -52,8 +53,41 @@ type Config struct {
 	// MaxWitnessDelay is the maximum delay that the witnessed transaction becomes included into the ledger.
 	MaxWitnessDelay time.Duration
 
+	// WitnessProofBatchWindow (optional) is the maximum amount of time that a witness proof is held so that it
+	// may be delivered to the anchor origin along with other proofs. If zero then proofs are not batched.
+	WitnessProofBatchWindow time.Duration
+
+	// WitnessProofBatchSize (optional) is the maximum number of witness proofs that are delivered in a single batch.
+	WitnessProofBatchSize int
+
+	// WitnessProofCacheSize (optional) is the maximum number of witness proofs that are cached so that a proof
+	// may be reused when the same anchor credential is offered again. If zero then proofs are not cached.
+	WitnessProofCacheSize int
+
 	IRICacheSize       int
 	IRICacheExpiration time.Duration
+
+	// SignatureStore (optional) stores the HTTP signature headers of activities received by the inbox.
+	SignatureStore inbox.SignatureStore
+
+	// Quarantine (optional) stores the inbox requests that fail HTTP signature verification.
+	Quarantine inbox.QuarantineStore
+
+	// InboxEvidence (optional) stores the raw HTTP-signed requests of the activities accepted by the inbox.
+	InboxEvidence inbox.EvidenceStore
+
+	// InboxRejections (optional) records the activities that are rejected by the inbox.
+	InboxRejections inbox.RejectionRecorder
+
+	// InboxWorkers (optional) is the number of inbox activities that are processed concurrently.
+	InboxWorkers int
+
+	// InboxQueueSize (optional) is the maximum number of inbox activities that are received ahead of the workers.
+	InboxQueueSize int
+
+	// InboxSenderPolicy (optional) limits the number of activities from a single sender that are processed
+	// concurrently by the inbox.
+	InboxSenderPolicy fairqueue.Policy
 }
 
 // Service implements an ActivityPub service which has an inbox, outbox, and
@@ -131,8 +165,13 @@ func New(cfg *Config, activityStore store.Store, t httpTransport, sigVerifier si
 			BufferSize:      cfg.ActivityHandlerBufferSize,
 			ServiceIRI:      cfg.ServiceIRI,
 			MaxWitnessDelay: cfg.MaxWitnessDelay,
+
+			WitnessProofBatchWindow: cfg.WitnessProofBatchWindow,
+			WitnessProofBatchSize:   cfg.WitnessProofBatchSize,
+			WitnessProofCacheSize:   cfg.WitnessProofCacheSize,
 		},
-		activityStore, ob, activityPubClient, handlerOpts...)
+		activityStore, ob, activityPubClient,
+		append([]spi.HandlerOpt{spi.WithRejectionRecorder(cfg.InboxRejections)}, handlerOpts...)...)
 
 	ib, err := inbox.New(
 		&inbox.Config{
@@ -140,6 +179,13 @@ func New(cfg *Config, activityStore store.Store, t httpTransport, sigVerifier si
 			ServiceIRI:             cfg.ServiceIRI,
 			Topic:                  inboxActivitiesTopic,
 			VerifyActorInSignature: cfg.VerifyActorInSignature,
+			SignatureStore:         cfg.SignatureStore,
+			Quarantine:             cfg.Quarantine,
+			Evidence:               cfg.InboxEvidence,
+			Rejections:             cfg.InboxRejections,
+			Workers:                cfg.InboxWorkers,
+			QueueSize:              cfg.InboxQueueSize,
+			SenderPolicy:           cfg.InboxSenderPolicy,
 		},
 		activityStore, pubSub,
 		inboxHandler, sigVerifier, tm, m,
@@ -170,8 +216,9 @@ func (s *Service) start() {
 
 func (s *Service) stop() {
 	s.inbox.Stop()
-	s.outbox.Stop()
+	// The activity handler is stopped before the outbox so that any pending witness proofs may be posted.
 	s.activityHandler.Stop()
+	s.outbox.Stop()
 }
 
 // Outbox returns the outbox, which allows clients to post activities.
@@ -194,3 +241,13 @@ func (s *Service) InboxHTTPHandler() common.HTTPHandler {
 func (s *Service) Subscribe() <-chan *vocab.ActivityType {
 	return s.activityHandler.Subscribe()
 }
+
+// ApproveFollow approves the given 'Follow' activity which is pending approval.
+func (s *Service) ApproveFollow(followID *url.URL) error {
+	return s.activityHandler.ApproveFollow(followID)
+}
+
+// RejectFollow rejects the given 'Follow' activity which is pending approval.
+func (s *Service) RejectFollow(followID *url.URL) error {
+	return s.activityHandler.RejectFollow(followID)
+}
@@ -11,6 +11,7 @@ import (
 	"net/url"
 	"time"
 
+	"github.com/trustbloc/orb/pkg/activitypub/rejection"
 	"github.com/trustbloc/orb/pkg/activitypub/vocab"
 	"github.com/trustbloc/orb/pkg/lifecycle"
 )
@@ -56,6 +57,13 @@ type ActorAuth interface {
 	AuthorizeActor(actor *vocab.ActorType) (bool, error)
 }
 
+// ApprovalHolder may optionally be implemented by an ActorAuth. If HoldForApproval returns true then a
+// request by an actor that isn't authorized is held until it is approved (or rejected) by an administrator,
+// rather than being rejected immediately.
+type ApprovalHolder interface {
+	HoldForApproval(actor *vocab.ActorType) bool
+}
+
 // WitnessHandler is a handler that witnesses an anchor credential.
 type WitnessHandler interface {
 	Witness(anchorCred []byte) ([]byte, error)
@@ -91,6 +99,60 @@ type UndeliverableActivityHandler interface {
 	HandleUndeliverableActivity(activity *vocab.ActivityType, toURL string)
 }
 
+// DeliveryListener is notified of the result of each delivery of an activity to an inbox. The error is nil
+// if the delivery succeeded.
+type DeliveryListener interface {
+	Delivered(inbox *url.URL, latency time.Duration, err error)
+}
+
+// RejectionRecorder records the activities that are rejected by the inbox.
+type RejectionRecorder interface {
+	Record(r *rejection.Rejection)
+}
+
+// DeliveryReceipts records the delivery outcome of each activity posted to the outbox for each recipient inbox.
+type DeliveryReceipts interface {
+	// Pending records that the given activity is about to be delivered to the given inboxes.
+	Pending(activityID *url.URL, inboxes []*url.URL) error
+	// DeliveryAttempted records the result of an attempt to deliver the given activity to the given inbox. The
+	// error is nil if the delivery succeeded.
+	DeliveryAttempted(activityID string, inbox *url.URL, err error)
+}
+
+// PendingDelivery contains the inboxes to which an activity posted to the outbox has yet to be delivered.
+type PendingDelivery struct {
+	ActivityID *url.URL
+	Inboxes    []*url.URL
+}
+
+// PendingDeliveries persists the deliveries of activities posted to the outbox that haven't completed, so that the
+// deliveries may be resumed after a restart.
+type PendingDeliveries interface {
+	// Add persists the pending deliveries of the given activity to the given inboxes.
+	Add(activityID *url.URL, inboxes []*url.URL) error
+	// Remove removes the pending delivery of the given activity to the given inbox. It is called after the
+	// delivery succeeded or was abandoned.
+	Remove(activityID string, inbox *url.URL) error
+	// GetAll returns all pending deliveries.
+	GetAll() ([]*PendingDelivery, error)
+}
+
+// WitnessConfirmationListener is notified when a witness accepts (or re-accepts) an 'InviteWitness' request
+// from this service.
+type WitnessConfirmationListener interface {
+	WitnessConfirmed(witness *url.URL) error
+}
+
+// WitnessReciprocationPolicy specifies how the service responds to an actor after it accepts an 'InviteWitness'
+// request from the actor.
+type WitnessReciprocationPolicy struct {
+	// InviteWitness indicates that a reciprocal 'InviteWitness' is sent to the actor (unless the actor is
+	// already a witness of the service).
+	InviteWitness bool
+	// Follow indicates that a 'Follow' is sent to the actor (unless the service is already following the actor).
+	Follow bool
+}
+
 // Handlers contains handlers for various activity events, including undeliverable activities.
 type Handlers struct {
 	UndeliverableHandler  UndeliverableActivityHandler
@@ -100,6 +162,12 @@ type Handlers struct {
 	Witness               WitnessHandler
 	ProofHandler          ProofHandler
 	AnchorEventAckHandler AnchorEventAcknowledgementHandler
+	DeliveryListener      DeliveryListener
+	WitnessReciprocation  *WitnessReciprocationPolicy
+	WitnessConfirmation   WitnessConfirmationListener
+	DeliveryReceipts      DeliveryReceipts
+	PendingDeliveries     PendingDeliveries
+	Rejections            RejectionRecorder
 }
 
 // HandlerOpt sets a specific handler.
@@ -155,6 +223,54 @@ func WithAnchorEventAcknowledgementHandler(handler AnchorEventAcknowledgementHan
 	}
 }
 
+// WithDeliveryListener sets the listener that's notified of the result of each delivery of an activity to an inbox.
+func WithDeliveryListener(listener DeliveryListener) HandlerOpt {
+	return func(options *Handlers) {
+		options.DeliveryListener = listener
+	}
+}
+
+// WithDeliveryReceipts sets the store that records the delivery outcome of each activity posted to the outbox
+// for each recipient inbox.
+func WithDeliveryReceipts(receipts DeliveryReceipts) HandlerOpt {
+	return func(options *Handlers) {
+		options.DeliveryReceipts = receipts
+	}
+}
+
+// WithPendingDeliveries sets the store that persists the pending deliveries of activities posted to the outbox
+// so that the deliveries may be resumed after a restart.
+func WithPendingDeliveries(pending PendingDeliveries) HandlerOpt {
+	return func(options *Handlers) {
+		options.PendingDeliveries = pending
+	}
+}
+
+// WithWitnessReciprocation sets the policy that determines how the service responds to an actor after it
+// accepts an 'InviteWitness' request from the actor.
+func WithWitnessReciprocation(policy *WitnessReciprocationPolicy) HandlerOpt {
+	return func(options *Handlers) {
+		options.WitnessReciprocation = policy
+	}
+}
+
+// WithWitnessConfirmationListener sets the listener that's notified when a witness accepts an 'InviteWitness'
+// request. If set then an 'Accept' from an actor that is already a witness is treated as a renewal of the
+// witness relationship rather than as an error.
+func WithWitnessConfirmationListener(listener WitnessConfirmationListener) HandlerOpt {
+	return func(options *Handlers) {
+		options.WitnessConfirmation = listener
+	}
+}
+
+// WithRejectionRecorder sets the recorder that's notified when an inbox activity is rejected because the
+// actor isn't in the accept list or because the activity type isn't supported.
+func WithRejectionRecorder(recorder RejectionRecorder) HandlerOpt {
+	return func(options *Handlers) {
+		options.Rejections = recorder
+	}
+}
+
 // AcceptList contains the URIs that are to be accepted by an authorization handler
 // for the given type. Known types are "follow" and "invite-witness".
 type AcceptList struct {
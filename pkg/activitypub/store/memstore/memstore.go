@@ -36,17 +36,19 @@ func New(serviceName string) *Store {
 		serviceName:   serviceName,
 		activityStore: newActivitiesStore(),
 		referenceStores: map[spi.ReferenceType]*referenceStore{
-			spi.Inbox:        newReferenceStore(),
-			spi.Outbox:       newReferenceStore(),
-			spi.PublicOutbox: newReferenceStore(),
-			spi.Follower:     newReferenceStore(),
-			spi.Following:    newReferenceStore(),
-			spi.Witness:      newReferenceStore(),
-			spi.Witnessing:   newReferenceStore(),
-			spi.Like:         newReferenceStore(),
-			spi.Liked:        newReferenceStore(),
-			spi.Share:        newReferenceStore(),
-			spi.AnchorEvent:  newReferenceStore(),
+			spi.Inbox:         newReferenceStore(),
+			spi.Outbox:        newReferenceStore(),
+			spi.PublicOutbox:  newReferenceStore(),
+			spi.Follower:      newReferenceStore(),
+			spi.Following:     newReferenceStore(),
+			spi.Witness:       newReferenceStore(),
+			spi.Witnessing:    newReferenceStore(),
+			spi.Like:          newReferenceStore(),
+			spi.Liked:         newReferenceStore(),
+			spi.Share:         newReferenceStore(),
+			spi.AnchorEvent:   newReferenceStore(),
+			spi.PendingFollow: newReferenceStore(),
+			spi.Provenance:    newReferenceStore(),
 		},
 		actorStore: make(map[string]*vocab.ActorType),
 	}
@@ -48,6 +48,14 @@ const (
 	Share ReferenceType = "SHARE"
 	// AnchorEvent indicates that the reference is an anchor event.
 	AnchorEvent ReferenceType = "ANCHOR_EVENT"
+	// PendingFollow indicates that the reference is a 'Follow' activity that is being held for approval
+	// by an administrator.
+	PendingFollow ReferenceType = "PENDING_FOLLOW"
+	// Provenance indicates that the reference is an activity that led to the local acceptance of an anchor
+	// (i.e. the 'Create' or 'Announce' activity through which the anchor was received, or the 'Create' activity
+	// of a local anchor) or the 'Like' activity with which the anchor was acknowledged. The object IRI is the
+	// canonical hashlink of the anchor.
+	Provenance ReferenceType = "PROVENANCE"
 )
 
 // Store defines the functions of an ActivityPub store.
@@ -127,6 +127,7 @@ func NewFollowActivity(obj *ObjectProperty, opts ...Opt) *ActivityType {
 			WithID(options.ID),
 			WithType(TypeFollow),
 			WithTo(options.To...),
+			WithPublishedTime(options.Published),
 		),
 		activity: &activityType{
 			Actor:  NewURLProperty(options.Actor),
@@ -145,6 +146,7 @@ func NewInviteActivity(obj *ObjectProperty, opts ...Opt) *ActivityType {
 			WithID(options.ID),
 			WithType(TypeInvite),
 			WithTo(options.To...),
+			WithInReplyTo(options.InReplyTo),
 		),
 		activity: &activityType{
 			Actor:  NewURLProperty(options.Actor),
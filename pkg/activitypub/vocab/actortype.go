@@ -8,13 +8,15 @@ package vocab
 
 import (
 	"net/url"
+	"strings"
 )
 
 // PublicKeyType defines a public key object.
 type PublicKeyType struct {
-	ID           *URLProperty `json:"id"`
-	Owner        *URLProperty `json:"owner"`
-	PublicKeyPem string       `json:"publicKeyPem"`
+	ID                 *URLProperty `json:"id"`
+	Owner              *URLProperty `json:"owner"`
+	PublicKeyPem       string       `json:"publicKeyPem"`
+	PublicKeyMultibase string       `json:"publicKeyMultibase,omitempty"`
 }
 
 // NewPublicKey returns a new public key object.
@@ -22,12 +24,115 @@ func NewPublicKey(opts ...Opt) *PublicKeyType {
 	options := NewOptions(opts...)
 
 	return &PublicKeyType{
-		ID:           NewURLProperty(options.ID),
-		Owner:        NewURLProperty(options.Owner),
-		PublicKeyPem: options.PublicKeyPem,
+		ID:                 NewURLProperty(options.ID),
+		Owner:              NewURLProperty(options.Owner),
+		PublicKeyPem:       options.PublicKeyPem,
+		PublicKeyMultibase: options.PublicKeyMultibase,
 	}
 }
 
+// ImageType defines an 'Image' object, for example the icon of an actor.
+type ImageType struct {
+	Type      *TypeProperty `json:"type"`
+	URL       *URLProperty  `json:"url"`
+	MediaType string        `json:"mediaType,omitempty"`
+}
+
+// NewImage returns a new 'Image' object with the given URL and (optional) media type.
+func NewImage(u *url.URL, mediaType string) *ImageType {
+	return &ImageType{
+		Type:      NewTypeProperty(TypeImage),
+		URL:       NewURLProperty(u),
+		MediaType: mediaType,
+	}
+}
+
+// NewPropertyValue returns a new 'PropertyValue' object, which holds a name/value pair (for example,
+// contact information) that may be added as an attachment to an actor.
+func NewPropertyValue(name, value string) (*ObjectType, error) {
+	return NewObjectWithDocument(
+		Document{
+			propertyName:  name,
+			propertyValue: value,
+		},
+		WithType(TypePropertyValue),
+	)
+}
+
+// CollectionSummaryType summarizes a collection of an actor, i.e. it contains the URL of the collection
+// and the total number of items in the collection.
+type CollectionSummaryType struct {
+	ID         *URLProperty  `json:"id"`
+	Type       *TypeProperty `json:"type"`
+	TotalItems int           `json:"totalItems"`
+}
+
+// NewCollectionSummary returns a new collection summary.
+func NewCollectionSummary(id *url.URL, totalItems int) *CollectionSummaryType {
+	return &CollectionSummaryType{
+		ID:         NewURLProperty(id),
+		Type:       NewTypeProperty(TypeCollection),
+		TotalItems: totalItems,
+	}
+}
+
+// CollectionSummariesType contains the summaries of an actor's collections so that the connectivity of
+// the actor may be gauged without retrieving the collections.
+type CollectionSummariesType struct {
+	Followers  *CollectionSummaryType `json:"followers,omitempty"`
+	Following  *CollectionSummaryType `json:"following,omitempty"`
+	Witnesses  *CollectionSummaryType `json:"witnesses,omitempty"`
+	Witnessing *CollectionSummaryType `json:"witnessing,omitempty"`
+}
+
+// CapabilitiesType contains the features that are supported by an actor (service) so that a peer may select
+// compatible formats when it communicates with the actor, for example during a rollout in which the services of
+// a domain run different versions. A peer that doesn't publish its capabilities is assumed to support only the
+// default formats.
+type CapabilitiesType struct {
+	// SignatureSuites contains the linked data signature suites (e.g. Ed25519Signature2018) of the proofs that
+	// the actor is able to verify.
+	SignatureSuites []string `json:"signatureSuites,omitempty"`
+	// LinksetFormats contains the media types of the linkset formats (e.g. application/linkset+json) that the
+	// actor is able to read.
+	LinksetFormats []string `json:"linksetFormats,omitempty"`
+	// ProtocolVersions contains the Sidetree protocol versions that are supported by the actor.
+	ProtocolVersions []string `json:"protocolVersions,omitempty"`
+	// ContentEncodings contains the content encodings (e.g. gzip) of the requests that are accepted by the
+	// actor's inbox.
+	ContentEncodings []string `json:"contentEncodings,omitempty"`
+}
+
+// SupportsSignatureSuite returns true if the given signature suite is supported.
+func (t *CapabilitiesType) SupportsSignatureSuite(suite string) bool {
+	return t != nil && containsFold(t.SignatureSuites, suite)
+}
+
+// SupportsLinksetFormat returns true if the given linkset format (media type) is supported.
+func (t *CapabilitiesType) SupportsLinksetFormat(mediaType string) bool {
+	return t != nil && containsFold(t.LinksetFormats, mediaType)
+}
+
+// SupportsProtocolVersion returns true if the given protocol version is supported.
+func (t *CapabilitiesType) SupportsProtocolVersion(version string) bool {
+	return t != nil && containsFold(t.ProtocolVersions, version)
+}
+
+// SupportsContentEncoding returns true if the given content encoding is supported.
+func (t *CapabilitiesType) SupportsContentEncoding(encoding string) bool {
+	return t != nil && containsFold(t.ContentEncodings, encoding)
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // ActorType defines an 'actor'.
 type ActorType struct {
 	*ObjectType
@@ -36,16 +141,21 @@ type ActorType struct {
 }
 
 type actorType struct {
-	PublicKey  *PublicKeyType `json:"publicKey"`
-	Inbox      *URLProperty   `json:"inbox"`
-	Outbox     *URLProperty   `json:"outbox"`
-	Followers  *URLProperty   `json:"followers"`
-	Following  *URLProperty   `json:"following"`
-	Witnesses  *URLProperty   `json:"witnesses"`
-	Witnessing *URLProperty   `json:"witnessing"`
-	Liked      *URLProperty   `json:"liked"`
-	Likes      *URLProperty   `json:"likes"`
-	Shares     *URLProperty   `json:"shares"`
+	PublicKey    *PublicKeyType           `json:"publicKey"`
+	Inbox        *URLProperty             `json:"inbox"`
+	Outbox       *URLProperty             `json:"outbox"`
+	Followers    *URLProperty             `json:"followers"`
+	Following    *URLProperty             `json:"following"`
+	Witnesses    *URLProperty             `json:"witnesses"`
+	Witnessing   *URLProperty             `json:"witnessing"`
+	Liked        *URLProperty             `json:"liked"`
+	Likes        *URLProperty             `json:"likes"`
+	Shares       *URLProperty             `json:"shares"`
+	Collections  *CollectionSummariesType `json:"collections,omitempty"`
+	Name         string                   `json:"name,omitempty"`
+	Summary      string                   `json:"summary,omitempty"`
+	Icon         *ImageType               `json:"icon,omitempty"`
+	Capabilities *CapabilitiesType        `json:"capabilities,omitempty"`
 }
 
 // PublicKey returns the actor's public key.
@@ -116,6 +226,32 @@ func (t *ActorType) Liked() *url.URL {
 	return t.actor.Liked.URL()
 }
 
+// Collections returns the summaries of the actor's collections.
+func (t *ActorType) Collections() *CollectionSummariesType {
+	return t.actor.Collections
+}
+
+// Name returns the human-readable name of the actor.
+func (t *ActorType) Name() string {
+	return t.actor.Name
+}
+
+// Summary returns the summary of the actor.
+func (t *ActorType) Summary() string {
+	return t.actor.Summary
+}
+
+// Icon returns the actor's icon.
+func (t *ActorType) Icon() *ImageType {
+	return t.actor.Icon
+}
+
+// Capabilities returns the features that are supported by the actor, or nil if the actor doesn't publish
+// its capabilities.
+func (t *ActorType) Capabilities() *CapabilitiesType {
+	return t.actor.Capabilities
+}
+
 // MarshalJSON mmarshals the object to JSON.
 func (t *ActorType) MarshalJSON() ([]byte, error) {
 	return MarshalJSON(t.ObjectType, t.actor)
@@ -138,18 +274,24 @@ func NewService(id *url.URL, opts ...Opt) *ActorType {
 			WithContext(getContexts(options, ContextActivityStreams, ContextSecurity, ContextActivityAnchors)...),
 			WithID(id),
 			WithType(TypeService),
+			WithAttachment(options.Attachment...),
 		),
 		actor: &actorType{
-			PublicKey:  options.PublicKey,
-			Inbox:      NewURLProperty(options.Inbox),
-			Outbox:     NewURLProperty(options.Outbox),
-			Followers:  NewURLProperty(options.Followers),
-			Following:  NewURLProperty(options.Following),
-			Witnesses:  NewURLProperty(options.Witnesses),
-			Witnessing: NewURLProperty(options.Witnessing),
-			Liked:      NewURLProperty(options.Liked),
-			Likes:      NewURLProperty(options.Likes),
-			Shares:     NewURLProperty(options.Shares),
+			PublicKey:    options.PublicKey,
+			Inbox:        NewURLProperty(options.Inbox),
+			Outbox:       NewURLProperty(options.Outbox),
+			Followers:    NewURLProperty(options.Followers),
+			Following:    NewURLProperty(options.Following),
+			Witnesses:    NewURLProperty(options.Witnesses),
+			Witnessing:   NewURLProperty(options.Witnessing),
+			Liked:        NewURLProperty(options.Liked),
+			Likes:        NewURLProperty(options.Likes),
+			Shares:       NewURLProperty(options.Shares),
+			Collections:  options.Collections,
+			Name:         options.Name,
+			Summary:      options.Summary,
+			Icon:         options.Icon,
+			Capabilities: options.Capabilities,
 		},
 	}
 }
@@ -123,6 +123,162 @@ func TestActor(t *testing.T) {
 		require.Nil(t, a.Witnesses())
 		require.Nil(t, a.Witnessing())
 		require.Nil(t, a.Liked())
+		require.Empty(t, a.Name())
+		require.Empty(t, a.Summary())
+		require.Nil(t, a.Icon())
+		require.Empty(t, a.Attachment())
+		require.Nil(t, a.Collections())
+		require.Nil(t, a.Capabilities())
+		require.False(t, a.Capabilities().SupportsContentEncoding("gzip"))
+	})
+
+	t.Run("Capabilities", func(t *testing.T) {
+		service := NewService(serviceIRI,
+			WithCapabilities(&CapabilitiesType{
+				SignatureSuites:  []string{"Ed25519Signature2018", "JsonWebSignature2020"},
+				LinksetFormats:   []string{"application/linkset+json"},
+				ProtocolVersions: []string{"1.0", "1.1"},
+				ContentEncodings: []string{"gzip"},
+			}),
+		)
+
+		serviceBytes, err := json.Marshal(service)
+		require.NoError(t, err)
+
+		serviceDoc := make(map[string]json.RawMessage)
+		require.NoError(t, json.Unmarshal(serviceBytes, &serviceDoc))
+		require.JSONEq(t,
+			`{"signatureSuites":["Ed25519Signature2018","JsonWebSignature2020"],`+
+				`"linksetFormats":["application/linkset+json"],"protocolVersions":["1.0","1.1"],`+
+				`"contentEncodings":["gzip"]}`, string(serviceDoc["capabilities"]))
+
+		a := &ActorType{}
+		require.NoError(t, json.Unmarshal(serviceBytes, a))
+
+		capabilities := a.Capabilities()
+		require.NotNil(t, capabilities)
+		require.True(t, capabilities.SupportsSignatureSuite("JsonWebSignature2020"))
+		require.False(t, capabilities.SupportsSignatureSuite("BbsBlsSignature2020"))
+		require.True(t, capabilities.SupportsLinksetFormat("application/linkset+json"))
+		require.False(t, capabilities.SupportsLinksetFormat("application/linkset"))
+		require.True(t, capabilities.SupportsProtocolVersion("1.1"))
+		require.False(t, capabilities.SupportsProtocolVersion("2.0"))
+		require.True(t, capabilities.SupportsContentEncoding("GZIP"))
+		require.False(t, capabilities.SupportsContentEncoding("br"))
+	})
+
+	t.Run("Collections", func(t *testing.T) {
+		service := NewService(serviceIRI,
+			WithFollowers(followers),
+			WithWitnesses(witnesses),
+			WithCollections(&CollectionSummariesType{
+				Followers: NewCollectionSummary(followers, 3),
+				Witnesses: NewCollectionSummary(witnesses, 0),
+			}),
+		)
+
+		bytes, err := json.Marshal(service)
+		require.NoError(t, err)
+		require.Contains(t, string(bytes),
+			`"collections":{"followers":{"id":"`+followers.String()+`","totalItems":3,"type":"Collection"},`+
+				`"witnesses":{"id":"`+witnesses.String()+`","totalItems":0,"type":"Collection"}}`)
+
+		a := &ActorType{}
+		require.NoError(t, json.Unmarshal(bytes, a))
+
+		collections := a.Collections()
+		require.NotNil(t, collections)
+		require.NotNil(t, collections.Followers)
+		require.Equal(t, followers.String(), collections.Followers.ID.String())
+		require.True(t, collections.Followers.Type.Is(TypeCollection))
+		require.Equal(t, 3, collections.Followers.TotalItems)
+		require.NotNil(t, collections.Witnesses)
+		require.Zero(t, collections.Witnesses.TotalItems)
+		require.Nil(t, collections.Following)
+		require.Nil(t, collections.Witnessing)
+	})
+
+	t.Run("Profile", func(t *testing.T) {
+		icon := testutil.MustParseURL("https://alice.example.com/icon.png")
+
+		email, err := NewPropertyValue("Email", "admin@alice.example.com")
+		require.NoError(t, err)
+
+		service := NewService(serviceIRI,
+			WithPublicKey(publicKey),
+			WithInbox(inbox),
+			WithOutbox(outbox),
+			WithFollowers(followers),
+			WithFollowing(following),
+			WithWitnesses(witnesses),
+			WithWitnessing(witnessing),
+			WithLiked(liked),
+			WithShares(shares),
+			WithLikes(likes),
+			WithName("Alice's Orb Node"),
+			WithSummary("Orb node operated by Alice"),
+			WithIcon(NewImage(icon, "image/png")),
+			WithAttachment(NewObjectProperty(WithObject(email))),
+		)
+
+		bytes, err := canonicalizer.MarshalCanonical(service)
+		require.NoError(t, err)
+		t.Log(string(bytes))
+
+		require.Equal(t, testutil.GetCanonical(t, jsonServiceWithProfile), string(bytes))
+
+		a := &ActorType{}
+		require.NoError(t, json.Unmarshal(bytes, a))
+		require.Equal(t, "Alice's Orb Node", a.Name())
+		require.Equal(t, "Orb node operated by Alice", a.Summary())
+		require.NotNil(t, a.Icon())
+		require.True(t, a.Icon().Type.Is(TypeImage))
+		require.Equal(t, icon.String(), a.Icon().URL.String())
+		require.Equal(t, "image/png", a.Icon().MediaType)
+
+		require.Len(t, a.Attachment(), 1)
+
+		obj := a.Attachment()[0].Object()
+		require.NotNil(t, obj)
+		require.True(t, obj.Type().Is(TypePropertyValue))
+
+		name, ok := obj.Value("name")
+		require.True(t, ok)
+		require.Equal(t, "Email", name)
+
+		value, ok := obj.Value("value")
+		require.True(t, ok)
+		require.Equal(t, "admin@alice.example.com", value)
+	})
+}
+
+func TestPublicKey_Multibase(t *testing.T) {
+	const multibaseKey = "z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK"
+
+	keyID := testutil.MustParseURL("https://alice.example.com/services/orb/keys/main-key")
+	owner := testutil.MustParseURL("https://alice.example.com/services/orb")
+
+	publicKey := NewPublicKey(
+		WithID(keyID),
+		WithOwner(owner),
+		WithPublicKeyMultibase(multibaseKey),
+	)
+
+	bytes, err := json.Marshal(publicKey)
+	require.NoError(t, err)
+	require.Contains(t, string(bytes), `"publicKeyMultibase":"`+multibaseKey+`"`)
+
+	key := &PublicKeyType{}
+	require.NoError(t, json.Unmarshal(bytes, key))
+	require.Equal(t, keyID.String(), key.ID.String())
+	require.Equal(t, owner.String(), key.Owner.String())
+	require.Equal(t, multibaseKey, key.PublicKeyMultibase)
+	require.Empty(t, key.PublicKeyPem)
+
+	t.Run("Omitted if not set", func(t *testing.T) {
+		bytes, err := json.Marshal(NewPublicKey(WithID(keyID), WithOwner(owner), WithPublicKeyPem("pem")))
+		require.NoError(t, err)
+		require.NotContains(t, string(bytes), "publicKeyMultibase")
 	})
 }
 
@@ -149,3 +305,41 @@ const jsonService = `{
   "likes": "https://alice.example.com/services/orb/likes",
   "shares": "https://alice.example.com/services/orb/shares"
 }`
+
+const jsonServiceWithProfile = `{
+  "@context": [
+    "https://www.w3.org/ns/activitystreams",
+    "https://w3id.org/security/v1",
+    "https://w3id.org/activityanchors/v1"
+  ],
+  "id": "https://alice.example.com/services/orb",
+  "type": "Service",
+  "name": "Alice's Orb Node",
+  "summary": "Orb node operated by Alice",
+  "icon": {
+    "type": "Image",
+    "url": "https://alice.example.com/icon.png",
+    "mediaType": "image/png"
+  },
+  "attachment": [
+    {
+      "type": "PropertyValue",
+      "name": "Email",
+      "value": "admin@alice.example.com"
+    }
+  ],
+  "publicKey": {
+    "id": "https://alice.example.com/services/orb/keys/main-key",
+    "owner": "https://alice.example.com/services/orb",
+    "publicKeyPem": "-----BEGIN PUBLIC KEY-----\nMIIBIjANBgkqhki....."
+  },
+  "inbox": "https://alice.example.com/services/orb/inbox",
+  "outbox": "https://alice.example.com/services/orb/outbox",
+  "followers": "https://sally.example.com/services/orb/followers",
+  "following": "https://sally.example.com/services/orb/following",
+  "witnesses": "https://alice.example.com/services/orb/witnesses",
+  "witnessing": "https://alice.example.com/services/orb/witnessing",
+  "liked": "https://alice.example.com/services/orb/liked",
+  "likes": "https://alice.example.com/services/orb/likes",
+  "shares": "https://alice.example.com/services/orb/shares"
+}`
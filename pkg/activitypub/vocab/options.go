@@ -292,16 +292,21 @@ func WithResult(result *ObjectProperty) Opt {
 
 // ActorOptions holds the options for an Activity.
 type ActorOptions struct {
-	PublicKey  *PublicKeyType
-	Inbox      *url.URL
-	Outbox     *url.URL
-	Followers  *url.URL
-	Following  *url.URL
-	Witnesses  *url.URL
-	Witnessing *url.URL
-	Liked      *url.URL
-	Likes      *url.URL
-	Shares     *url.URL
+	PublicKey    *PublicKeyType
+	Inbox        *url.URL
+	Outbox       *url.URL
+	Followers    *url.URL
+	Following    *url.URL
+	Witnesses    *url.URL
+	Witnessing   *url.URL
+	Liked        *url.URL
+	Likes        *url.URL
+	Shares       *url.URL
+	Collections  *CollectionSummariesType
+	Name         string
+	Summary      string
+	Icon         *ImageType
+	Capabilities *CapabilitiesType
 }
 
 // WithPublicKey sets the 'publicKey' property on the actor.
@@ -374,10 +379,47 @@ func WithShares(shares *url.URL) Opt {
 	}
 }
 
+// WithCollections sets the 'collections' property, which contains the summaries of the actor's collections,
+// on the actor.
+func WithCollections(collections *CollectionSummariesType) Opt {
+	return func(opts *Options) {
+		opts.Collections = collections
+	}
+}
+
+// WithName sets the human-readable 'name' property on the actor.
+func WithName(name string) Opt {
+	return func(opts *Options) {
+		opts.Name = name
+	}
+}
+
+// WithSummary sets the 'summary' property on the actor.
+func WithSummary(summary string) Opt {
+	return func(opts *Options) {
+		opts.Summary = summary
+	}
+}
+
+// WithIcon sets the 'icon' property on the actor.
+func WithIcon(icon *ImageType) Opt {
+	return func(opts *Options) {
+		opts.Icon = icon
+	}
+}
+
+// WithCapabilities sets the 'capabilities' property (the features that are supported by the actor) on the actor.
+func WithCapabilities(capabilities *CapabilitiesType) Opt {
+	return func(opts *Options) {
+		opts.Capabilities = capabilities
+	}
+}
+
 // PublicKeyOptions holds the options for a Public Key.
 type PublicKeyOptions struct {
-	Owner        *url.URL
-	PublicKeyPem string
+	Owner              *url.URL
+	PublicKeyPem       string
+	PublicKeyMultibase string
 }
 
 // WithOwner sets the 'owner' property on the public key.
@@ -394,6 +436,13 @@ func WithPublicKeyPem(pem string) Opt {
 	}
 }
 
+// WithPublicKeyMultibase sets the 'publicKeyMultibase' property (a key in Multikey format) on the public key.
+func WithPublicKeyMultibase(value string) Opt {
+	return func(opts *Options) {
+		opts.PublicKeyMultibase = value
+	}
+}
+
 func getContexts(options *Options, contexts ...Context) []Context {
 	return append(contexts, options.Context...)
 }
@@ -77,6 +77,10 @@ const (
 	TypeOffer Type = "Offer"
 	// TypeUndo specifies the "Undo" activity type.
 	TypeUndo Type = "Undo"
+	// TypeImage specifies the "Image" object type.
+	TypeImage Type = "Image"
+	// TypePropertyValue specifies the "PropertyValue" object type, which holds a name/value pair.
+	TypePropertyValue Type = "PropertyValue"
 
 	// RelationshipWitness defines the 'witness' relationship of a Link.
 	RelationshipWitness = "witness"
@@ -105,6 +109,8 @@ const (
 	propertyAttachment   = "attachment"
 	propertyIndex        = "index"
 	propertyParent       = "parent"
+	propertyName         = "name"
+	propertyValue        = "value"
 )
 
 func reservedProperties() []string {
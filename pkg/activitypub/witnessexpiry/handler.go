@@ -0,0 +1,112 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package witnessexpiry
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+)
+
+const (
+	// Path is the path of the endpoint that returns the witness relationships nearing expiration.
+	Path = "/witnesses/expiring"
+
+	withinParam = "within"
+
+	badRequestResponse          = "Bad Request."
+	internalServerErrorResponse = "Internal Server Error."
+)
+
+type relationshipProvider interface {
+	Expiring(within time.Duration) ([]*Relationship, error)
+	RenewalWindow() time.Duration
+}
+
+// Handler implements a REST handler that returns the witness relationships that expire within a given period
+// (specified by the 'within' query parameter, e.g. ?within=48h). If the period isn't specified then the
+// relationships within the renewal window are returned.
+type Handler struct {
+	provider relationshipProvider
+	marshal  func(v interface{}) ([]byte, error)
+}
+
+// NewHandler returns a new handler for the witness relationships nearing expiration.
+func NewHandler(provider relationshipProvider) *Handler {
+	return &Handler{
+		provider: provider,
+		marshal:  json.Marshal,
+	}
+}
+
+// Path returns the HTTP REST endpoint of the handler.
+func (h *Handler) Path() string {
+	return Path
+}
+
+// Method returns the HTTP method, which is always GET.
+func (h *Handler) Method() string {
+	return http.MethodGet
+}
+
+// Handler returns the HTTP REST handle.
+func (h *Handler) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Handler) handle(w http.ResponseWriter, req *http.Request) {
+	within := h.provider.RenewalWindow()
+
+	if value := req.URL.Query().Get(withinParam); value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil || d < 0 {
+			logger.Debugf("[%s] Invalid value for parameter [%s]: %s", Path, withinParam, value)
+
+			writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+			return
+		}
+
+		within = d
+	}
+
+	relationships, err := h.provider.Expiring(within)
+	if err != nil {
+		logger.Errorf("[%s] Error retrieving witness relationships: %s", Path, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	if relationships == nil {
+		relationships = []*Relationship{}
+	}
+
+	respBytes, err := h.marshal(relationships)
+	if err != nil {
+		logger.Errorf("[%s] Error marshalling witness relationships: %s", Path, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	writeResponse(w, http.StatusOK, respBytes)
+}
+
+func writeResponse(w http.ResponseWriter, status int, body []byte) {
+	w.WriteHeader(status)
+
+	if len(body) > 0 {
+		if _, err := w.Write(body); err != nil {
+			logger.Warnf("[%s] Unable to write response: %s", Path, err)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package witnessexpiry
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/internal/testutil/httptestutil"
+)
+
+func TestHandler(t *testing.T) {
+	m, _, _ := newTestManager(t)
+
+	h := NewHandler(m)
+	require.Equal(t, Path, h.Path())
+	require.Equal(t, http.MethodGet, h.Method())
+	require.NotNil(t, h.Handler())
+
+	t.Run("Empty", func(t *testing.T) {
+		code, body := httptestutil.Get(t, h.handle, Path)
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, "[]", string(body))
+	})
+
+	now := time.Now()
+
+	require.NoError(t, m.store.put(&Relationship{Witness: witness1, Confirmed: now.Add(-expiration + time.Minute)}))
+	require.NoError(t, m.store.put(&Relationship{Witness: witness2, Confirmed: now}))
+
+	t.Run("Default (renewal window)", func(t *testing.T) {
+		code, body := httptestutil.Get(t, h.handle, Path)
+		require.Equal(t, http.StatusOK, code)
+
+		var relationships []*Relationship
+		require.NoError(t, json.Unmarshal(body, &relationships))
+		require.Len(t, relationships, 1)
+		require.Equal(t, witness1, relationships[0].Witness)
+		require.NotNil(t, relationships[0].Expires)
+	})
+
+	t.Run("Within", func(t *testing.T) {
+		code, body := httptestutil.Get(t, h.handle, Path+"?within=2h")
+		require.Equal(t, http.StatusOK, code)
+
+		var relationships []*Relationship
+		require.NoError(t, json.Unmarshal(body, &relationships))
+		require.Len(t, relationships, 2)
+	})
+
+	t.Run("Invalid within", func(t *testing.T) {
+		code, _ := httptestutil.Get(t, h.handle, Path+"?within=xxx")
+		require.Equal(t, http.StatusBadRequest, code)
+
+		code, _ = httptestutil.Get(t, h.handle, Path+"?within=-1h")
+		require.Equal(t, http.StatusBadRequest, code)
+	})
+
+	t.Run("Provider error", func(t *testing.T) {
+		code, _ := httptestutil.Get(t, NewHandler(&mockProvider{err: errors.New("injected error")}).handle, Path)
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		h := NewHandler(m)
+		h.marshal = func(v interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		code, _ := httptestutil.Get(t, h.handle, Path)
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+}
+
+type mockProvider struct {
+	err error
+}
+
+func (m *mockProvider) Expiring(time.Duration) ([]*Relationship, error) {
+	return nil, m.err
+}
+
+func (m *mockProvider) RenewalWindow() time.Duration {
+	return time.Hour
+}
@@ -0,0 +1,308 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package witnessexpiry
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	store "github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/store/storeutil"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+)
+
+var logger = log.New("witness-expiry")
+
+const (
+	taskName        = "witness-expiry"
+	defaultInterval = time.Minute
+
+	// defaultRenewalWindowDivisor is used to calculate the default renewal window as a fraction of the expiration.
+	defaultRenewalWindowDivisor = 10
+)
+
+type outbox interface {
+	Post(activity *vocab.ActivityType) (*url.URL, error)
+}
+
+type taskManager interface {
+	RegisterTask(taskType string, interval time.Duration, task func())
+}
+
+// Config contains configuration parameters for the witness expiry manager.
+type Config struct {
+	ServiceIRI *url.URL
+	// Expiration is the period within which a witness must re-confirm the witness relationship (by accepting
+	// an 'InviteWitness'), otherwise the witness is removed from the witnesses collection.
+	Expiration time.Duration
+	// RenewalWindow is the period before expiration in which a renewal 'InviteWitness' is sent to the witness.
+	// Defaults to a tenth of the expiration.
+	RenewalWindow time.Duration
+	// Interval is the interval at which the witness relationships are checked.
+	Interval time.Duration
+}
+
+// Manager expires the witness relationships of a service. Each time a witness accepts an 'InviteWitness', the
+// relationship is confirmed for the configured expiration period. When a relationship enters its renewal window,
+// a new 'InviteWitness' is posted to the witness and, if the witness doesn't accept it before the relationship
+// expires, the witness is removed from the witnesses collection.
+type Manager struct {
+	serviceIRI       *url.URL
+	expiration       time.Duration
+	renewalWindow    time.Duration
+	interval         time.Duration
+	activityPubStore store.Store
+	store            *relationshipStore
+	mutex            sync.Mutex
+	outbox           outbox
+}
+
+// New returns a new witness expiry manager.
+func New(cfg Config, apStore store.Store, storageProvider storage.Provider) (*Manager, error) {
+	if cfg.Expiration <= 0 {
+		return nil, errors.New("witness expiration must be greater than 0")
+	}
+
+	renewalWindow := cfg.RenewalWindow
+
+	if renewalWindow == 0 {
+		renewalWindow = cfg.Expiration / defaultRenewalWindowDivisor
+	}
+
+	if renewalWindow < 0 || renewalWindow >= cfg.Expiration {
+		return nil, fmt.Errorf("witness renewal window [%s] must be less than the expiration [%s]",
+			renewalWindow, cfg.Expiration)
+	}
+
+	s, err := newStore(storageProvider)
+	if err != nil {
+		return nil, fmt.Errorf("create witness relationship store: %w", err)
+	}
+
+	interval := cfg.Interval
+
+	if interval == 0 {
+		interval = defaultInterval
+	}
+
+	return &Manager{
+		serviceIRI:       cfg.ServiceIRI,
+		expiration:       cfg.Expiration,
+		renewalWindow:    renewalWindow,
+		interval:         interval,
+		activityPubStore: apStore,
+		store:            s,
+	}, nil
+}
+
+// Register registers the task that sends renewal invitations and removes expired witnesses. The given outbox
+// is used to post the renewal invitations.
+func (m *Manager) Register(taskMgr taskManager, ob outbox) {
+	m.outbox = ob
+
+	logger.Infof("Registering witness-expiry task - ServiceIRI: %s, Expiration: %s, Renewal window: %s, Interval: %s.",
+		m.serviceIRI, m.expiration, m.renewalWindow, m.interval)
+
+	taskMgr.RegisterTask(taskName, m.interval, m.run)
+}
+
+// WitnessConfirmed is invoked when the given witness accepts an 'InviteWitness' from this service. The witness
+// relationship is renewed for the configured expiration period.
+func (m *Manager) WitnessConfirmed(witness *url.URL) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	err := m.store.put(&Relationship{
+		Witness:   witness.String(),
+		Confirmed: time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Debugf("Witness relationship with [%s] confirmed", witness)
+
+	return nil
+}
+
+// Expiring returns the witness relationships that expire within the given period, soonest first.
+func (m *Manager) Expiring(within time.Duration) ([]*Relationship, error) {
+	relationships, err := m.store.getAll()
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := time.Now().Add(within)
+
+	var expiring []*Relationship
+
+	for _, r := range relationships {
+		expires := r.Confirmed.Add(m.expiration)
+
+		if expires.After(cutoff) {
+			continue
+		}
+
+		r.Expires = &expires
+
+		expiring = append(expiring, r)
+	}
+
+	sort.Slice(expiring, func(i, j int) bool {
+		return expiring[i].Expires.Before(*expiring[j].Expires)
+	})
+
+	return expiring, nil
+}
+
+// RenewalWindow returns the period before expiration in which a renewal is sent to the witness.
+func (m *Manager) RenewalWindow() time.Duration {
+	return m.renewalWindow
+}
+
+func (m *Manager) run() {
+	witnesses, err := m.getWitnesses()
+	if err != nil {
+		logger.Warnf("Error retrieving witnesses: %s", err)
+
+		return
+	}
+
+	for _, witness := range witnesses {
+		if e := m.check(witness); e != nil {
+			logger.Warnf("Error checking witness relationship with [%s]: %s", witness, e)
+		}
+	}
+
+	m.purge(witnesses)
+}
+
+func (m *Manager) check(witness *url.URL) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	now := time.Now().UTC()
+
+	r, err := m.store.get(witness.String())
+	if err != nil {
+		if !errors.Is(err, ErrNotFound) {
+			return err
+		}
+
+		// The witness was added before expiry was enabled (or the confirmation wasn't recorded), so the
+		// expiration period starts now.
+		logger.Debugf("Starting expiration period for witness [%s]", witness)
+
+		return m.store.put(&Relationship{Witness: witness.String(), Confirmed: now})
+	}
+
+	expires := r.Confirmed.Add(m.expiration)
+
+	if !now.Before(expires) {
+		return m.expire(witness)
+	}
+
+	if r.RenewalSent != nil || now.Before(expires.Add(-m.renewalWindow)) {
+		return nil
+	}
+
+	err = m.sendRenewal(witness)
+	if err != nil {
+		return err
+	}
+
+	r.RenewalSent = &now
+
+	return m.store.put(r)
+}
+
+func (m *Manager) expire(witness *url.URL) error {
+	err := m.activityPubStore.DeleteReference(store.Witness, m.serviceIRI, witness)
+	if err != nil {
+		return fmt.Errorf("delete witness [%s]: %w", witness, err)
+	}
+
+	err = m.store.delete(witness.String())
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("Witness [%s] was removed from the witnesses collection since the relationship expired", witness)
+
+	return nil
+}
+
+func (m *Manager) sendRenewal(witness *url.URL) error {
+	invite := vocab.NewInviteActivity(
+		vocab.NewObjectProperty(vocab.WithIRI(vocab.AnchorWitnessTargetIRI)),
+		vocab.WithTarget(vocab.NewObjectProperty(vocab.WithIRI(witness))),
+		vocab.WithActor(m.serviceIRI),
+		vocab.WithTo(witness),
+	)
+
+	activityID, err := m.outbox.Post(invite)
+	if err != nil {
+		return fmt.Errorf("post renewal 'InviteWitness' to [%s]: %w", witness, err)
+	}
+
+	logger.Infof("Sent renewal 'InviteWitness' [%s] to witness [%s]", activityID, witness)
+
+	return nil
+}
+
+// purge deletes the relationships of actors that are no longer witnesses (for example, if the invitation
+// was undone).
+func (m *Manager) purge(witnesses []*url.URL) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	relationships, err := m.store.getAll()
+	if err != nil {
+		logger.Warnf("Error retrieving witness relationships: %s", err)
+
+		return
+	}
+
+	current := make(map[string]struct{}, len(witnesses))
+
+	for _, w := range witnesses {
+		current[w.String()] = struct{}{}
+	}
+
+	for _, r := range relationships {
+		if _, ok := current[r.Witness]; ok {
+			continue
+		}
+
+		if e := m.store.delete(r.Witness); e != nil {
+			logger.Warnf("Error deleting witness relationship with [%s]: %s", r.Witness, e)
+		}
+	}
+}
+
+func (m *Manager) getWitnesses() ([]*url.URL, error) {
+	it, err := m.activityPubStore.QueryReferences(store.Witness,
+		store.NewCriteria(store.WithObjectIRI(m.serviceIRI)))
+	if err != nil {
+		return nil, fmt.Errorf("query witnesses: %w", err)
+	}
+
+	defer func() {
+		if e := it.Close(); e != nil {
+			logger.Warnf("Error closing iterator: %s", e)
+		}
+	}()
+
+	return storeutil.ReadReferences(it, -1)
+}
@@ -0,0 +1,273 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package witnessexpiry
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+
+	servicemocks "github.com/trustbloc/orb/pkg/activitypub/service/mocks"
+	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
+	store "github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/store/storeutil"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+	"github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+var (
+	serviceIRI  = testutil.MustParseURL("https://domain1.com/services/orb")
+	witness1IRI = testutil.MustParseURL(witness1)
+	witness2IRI = testutil.MustParseURL(witness2)
+)
+
+const (
+	expiration    = time.Hour
+	renewalWindow = 10 * time.Minute
+)
+
+func TestNew(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		m, err := New(newConfig(), memstore.New("service1"), mem.NewProvider())
+		require.NoError(t, err)
+		require.NotNil(t, m)
+		require.Equal(t, defaultInterval, m.interval)
+		require.Equal(t, renewalWindow, m.RenewalWindow())
+
+		m.Register(servicemocks.NewTaskManager("service1"), servicemocks.NewOutbox())
+	})
+
+	t.Run("Default renewal window", func(t *testing.T) {
+		m, err := New(Config{ServiceIRI: serviceIRI, Expiration: expiration}, memstore.New("service1"),
+			mem.NewProvider())
+		require.NoError(t, err)
+		require.Equal(t, expiration/defaultRenewalWindowDivisor, m.RenewalWindow())
+	})
+
+	t.Run("Invalid expiration", func(t *testing.T) {
+		_, err := New(Config{ServiceIRI: serviceIRI}, memstore.New("service1"), mem.NewProvider())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "witness expiration must be greater than 0")
+	})
+
+	t.Run("Invalid renewal window", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.RenewalWindow = expiration
+
+		_, err := New(cfg, memstore.New("service1"), mem.NewProvider())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "must be less than the expiration")
+	})
+
+	t.Run("Store error", func(t *testing.T) {
+		p := &mocks.Provider{}
+		p.OpenStoreReturns(nil, errors.New("injected open error"))
+
+		_, err := New(newConfig(), memstore.New("service1"), p)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected open error")
+	})
+}
+
+func TestManager(t *testing.T) {
+	t.Run("Start expiration period for existing witness", func(t *testing.T) {
+		m, apStore, ob := newTestManager(t)
+
+		require.NoError(t, apStore.AddReference(store.Witness, serviceIRI, witness1IRI))
+
+		m.run()
+
+		r, err := m.store.get(witness1)
+		require.NoError(t, err)
+		require.Nil(t, r.RenewalSent)
+		require.Empty(t, ob.Activities())
+	})
+
+	t.Run("Renewal", func(t *testing.T) {
+		m, apStore, ob := newTestManager(t)
+
+		require.NoError(t, apStore.AddReference(store.Witness, serviceIRI, witness1IRI))
+		require.NoError(t, m.store.put(&Relationship{
+			Witness:   witness1,
+			Confirmed: time.Now().Add(-expiration + renewalWindow/2),
+		}))
+
+		m.run()
+
+		invites := ob.Activities().QueryByType(vocab.TypeInvite)
+		require.Len(t, invites, 1)
+		require.Equal(t, witness1, invites[0].Target().IRI().String())
+		require.Equal(t, vocab.AnchorWitnessTargetIRI.String(), invites[0].Object().IRI().String())
+
+		r, err := m.store.get(witness1)
+		require.NoError(t, err)
+		require.NotNil(t, r.RenewalSent)
+
+		// The renewal is sent only once.
+		m.run()
+
+		require.Len(t, ob.Activities().QueryByType(vocab.TypeInvite), 1)
+
+		// The witness accepts the renewal.
+		require.NoError(t, m.WitnessConfirmed(witness1IRI))
+
+		r, err = m.store.get(witness1)
+		require.NoError(t, err)
+		require.Nil(t, r.RenewalSent)
+		require.True(t, time.Since(r.Confirmed) < time.Minute)
+	})
+
+	t.Run("Renewal outbox error", func(t *testing.T) {
+		m, apStore, _ := newTestManager(t)
+
+		m.outbox = servicemocks.NewOutbox().WithError(errors.New("injected outbox error"))
+
+		require.NoError(t, apStore.AddReference(store.Witness, serviceIRI, witness1IRI))
+		require.NoError(t, m.store.put(&Relationship{
+			Witness:   witness1,
+			Confirmed: time.Now().Add(-expiration + renewalWindow/2),
+		}))
+
+		m.run()
+
+		r, err := m.store.get(witness1)
+		require.NoError(t, err)
+		require.Nil(t, r.RenewalSent)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		m, apStore, _ := newTestManager(t)
+
+		require.NoError(t, apStore.AddReference(store.Witness, serviceIRI, witness1IRI))
+		require.NoError(t, apStore.AddReference(store.Witness, serviceIRI, witness2IRI))
+
+		now := time.Now()
+
+		require.NoError(t, m.store.put(&Relationship{
+			Witness:     witness1,
+			Confirmed:   now.Add(-expiration - time.Second),
+			RenewalSent: &now,
+		}))
+		require.NoError(t, m.store.put(&Relationship{Witness: witness2, Confirmed: now}))
+
+		m.run()
+
+		it, err := apStore.QueryReferences(store.Witness, store.NewCriteria(store.WithObjectIRI(serviceIRI)))
+		require.NoError(t, err)
+
+		witnesses, err := storeutil.ReadReferences(it, -1)
+		require.NoError(t, err)
+		require.Len(t, witnesses, 1)
+		require.Equal(t, witness2, witnesses[0].String())
+
+		_, err = m.store.get(witness1)
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Purge relationships of removed witnesses", func(t *testing.T) {
+		m, _, _ := newTestManager(t)
+
+		require.NoError(t, m.WitnessConfirmed(witness1IRI))
+
+		m.run()
+
+		_, err := m.store.get(witness1)
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Query witnesses error", func(t *testing.T) {
+		apStore := &servicemocks.ActivityStore{}
+		apStore.QueryReferencesReturns(nil, errors.New("injected query error"))
+
+		m, err := New(newConfig(), apStore, mem.NewProvider())
+		require.NoError(t, err)
+
+		require.NoError(t, m.WitnessConfirmed(witness1IRI))
+
+		m.run()
+
+		// The relationship isn't purged if the witnesses can't be retrieved.
+		_, err = m.store.get(witness1)
+		require.NoError(t, err)
+	})
+
+	t.Run("Store error", func(t *testing.T) {
+		errExpected := errors.New("injected store error")
+
+		s := &mocks.Store{}
+		s.PutReturns(errExpected)
+		s.GetReturns(nil, errExpected)
+		s.QueryReturns(nil, errExpected)
+
+		p := &mocks.Provider{}
+		p.OpenStoreReturns(s, nil)
+
+		apStore := memstore.New("service1")
+		require.NoError(t, apStore.AddReference(store.Witness, serviceIRI, witness1IRI))
+
+		m, err := New(newConfig(), apStore, p)
+		require.NoError(t, err)
+
+		require.ErrorIs(t, m.WitnessConfirmed(witness1IRI), errExpected)
+
+		_, err = m.Expiring(time.Hour)
+		require.ErrorIs(t, err, errExpected)
+
+		require.NotPanics(t, m.run)
+	})
+}
+
+func TestManager_Expiring(t *testing.T) {
+	m, _, _ := newTestManager(t)
+
+	now := time.Now()
+
+	require.NoError(t, m.store.put(&Relationship{Witness: witness1, Confirmed: now.Add(-expiration + time.Minute)}))
+	require.NoError(t, m.store.put(&Relationship{Witness: witness2, Confirmed: now.Add(-expiration + 5*time.Minute)}))
+
+	relationships, err := m.Expiring(2 * time.Minute)
+	require.NoError(t, err)
+	require.Len(t, relationships, 1)
+	require.Equal(t, witness1, relationships[0].Witness)
+	require.NotNil(t, relationships[0].Expires)
+
+	relationships, err = m.Expiring(renewalWindow)
+	require.NoError(t, err)
+	require.Len(t, relationships, 2)
+	require.Equal(t, witness1, relationships[0].Witness)
+	require.Equal(t, witness2, relationships[1].Witness)
+
+	relationships, err = m.Expiring(0)
+	require.NoError(t, err)
+	require.Empty(t, relationships)
+}
+
+func newConfig() Config {
+	return Config{
+		ServiceIRI:    serviceIRI,
+		Expiration:    expiration,
+		RenewalWindow: renewalWindow,
+	}
+}
+
+func newTestManager(t *testing.T) (*Manager, store.Store, *servicemocks.Outbox) {
+	t.Helper()
+
+	apStore := memstore.New("service1")
+	ob := servicemocks.NewOutbox()
+
+	m, err := New(newConfig(), apStore, mem.NewProvider())
+	require.NoError(t, err)
+
+	m.Register(servicemocks.NewTaskManager("service1"), ob)
+
+	return m, apStore, ob
+}
@@ -0,0 +1,154 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package witnessexpiry
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+// ErrNotFound is returned when a witness relationship isn't found.
+var ErrNotFound = errors.New("witness relationship not found")
+
+const (
+	storeName = "witness-relationship"
+
+	// relationshipTag is added to every entry so that all entries may be queried.
+	relationshipTag = "relationship"
+)
+
+// Relationship contains the status of the witness relationship between this service and a witness.
+type Relationship struct {
+	Witness string `json:"witness"`
+	// Confirmed is the time that the witness last accepted an 'InviteWitness' from this service.
+	Confirmed time.Time `json:"confirmed"`
+	// RenewalSent is the time that a renewal 'InviteWitness' was sent to the witness (if any) since the
+	// relationship was last confirmed.
+	RenewalSent *time.Time `json:"renewalSent,omitempty"`
+	// Expires is the time after which the witness is removed from the witnesses collection unless it
+	// confirms the relationship again. This field is set when the relationship is returned by the manager.
+	Expires *time.Time `json:"expires,omitempty"`
+}
+
+// relationshipStore persists the status of the witness relationships.
+type relationshipStore struct {
+	store storage.Store
+}
+
+func newStore(provider storage.Provider) (*relationshipStore, error) {
+	s, err := provider.OpenStore(storeName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open witness relationship store: %w", err)
+	}
+
+	err = provider.SetStoreConfig(storeName, storage.StoreConfiguration{TagNames: []string{relationshipTag}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set store configuration: %w", err)
+	}
+
+	return &relationshipStore{store: s}, nil
+}
+
+func (s *relationshipStore) put(r *Relationship) error {
+	rBytes, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("marshal witness relationship [%s]: %w", r.Witness, err)
+	}
+
+	err = s.store.Put(keyFor(r.Witness), rBytes, storage.Tag{Name: relationshipTag})
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("store witness relationship [%s]: %w", r.Witness, err))
+	}
+
+	return nil
+}
+
+func (s *relationshipStore) get(witness string) (*Relationship, error) {
+	rBytes, err := s.store.Get(keyFor(witness))
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return nil, ErrNotFound
+		}
+
+		return nil, orberrors.NewTransient(fmt.Errorf("get witness relationship [%s]: %w", witness, err))
+	}
+
+	r := &Relationship{}
+
+	err = json.Unmarshal(rBytes, r)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal witness relationship [%s]: %w", witness, err)
+	}
+
+	return r, nil
+}
+
+func (s *relationshipStore) getAll() ([]*Relationship, error) {
+	it, err := s.store.Query(relationshipTag)
+	if err != nil {
+		return nil, orberrors.NewTransient(fmt.Errorf("query witness relationships: %w", err))
+	}
+
+	defer func() {
+		if e := it.Close(); e != nil {
+			logger.Warnf("Error closing iterator: %s", e)
+		}
+	}()
+
+	var relationships []*Relationship
+
+	for {
+		ok, e := it.Next()
+		if e != nil {
+			return nil, orberrors.NewTransient(fmt.Errorf("next witness relationship: %w", e))
+		}
+
+		if !ok {
+			break
+		}
+
+		value, e := it.Value()
+		if e != nil {
+			return nil, orberrors.NewTransient(fmt.Errorf("get witness relationship from iterator: %w", e))
+		}
+
+		r := &Relationship{}
+
+		if e := json.Unmarshal(value, r); e != nil {
+			return nil, fmt.Errorf("unmarshal witness relationship: %w", e)
+		}
+
+		relationships = append(relationships, r)
+	}
+
+	return relationships, nil
+}
+
+func (s *relationshipStore) delete(witness string) error {
+	err := s.store.Delete(keyFor(witness))
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("delete witness relationship [%s]: %w", witness, err))
+	}
+
+	return nil
+}
+
+// keyFor returns the storage key for the given witness. The IRI is hashed since it contains characters
+// that may not be supported in a key by some storage providers.
+func keyFor(witness string) string {
+	hash := sha256.Sum256([]byte(witness))
+
+	return hex.EncodeToString(hash[:])
+}
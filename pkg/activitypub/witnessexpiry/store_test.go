@@ -0,0 +1,151 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package witnessexpiry
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+const (
+	witness1 = "https://domain2.com/services/orb"
+	witness2 = "https://domain3.com/services/orb"
+)
+
+func TestStore(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		s, err := newStore(mem.NewProvider())
+		require.NoError(t, err)
+
+		_, err = s.get(witness1)
+		require.ErrorIs(t, err, ErrNotFound)
+
+		relationships, err := s.getAll()
+		require.NoError(t, err)
+		require.Empty(t, relationships)
+
+		now := time.Now().UTC()
+
+		require.NoError(t, s.put(&Relationship{Witness: witness1, Confirmed: now}))
+		require.NoError(t, s.put(&Relationship{Witness: witness2, Confirmed: now, RenewalSent: &now}))
+
+		r, err := s.get(witness1)
+		require.NoError(t, err)
+		require.Equal(t, witness1, r.Witness)
+		require.True(t, now.Equal(r.Confirmed))
+		require.Nil(t, r.RenewalSent)
+
+		r, err = s.get(witness2)
+		require.NoError(t, err)
+		require.NotNil(t, r.RenewalSent)
+
+		relationships, err = s.getAll()
+		require.NoError(t, err)
+		require.Len(t, relationships, 2)
+
+		require.NoError(t, s.delete(witness1))
+
+		_, err = s.get(witness1)
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Open store error", func(t *testing.T) {
+		p := &mocks.Provider{}
+		p.OpenStoreReturns(nil, errors.New("injected open error"))
+
+		_, err := newStore(p)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected open error")
+	})
+
+	t.Run("Set store config error", func(t *testing.T) {
+		p := &mocks.Provider{}
+		p.SetStoreConfigReturns(errors.New("injected config error"))
+
+		_, err := newStore(p)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected config error")
+	})
+
+	t.Run("Store errors", func(t *testing.T) {
+		errExpected := errors.New("injected store error")
+
+		store := &mocks.Store{}
+		store.PutReturns(errExpected)
+		store.GetReturns(nil, errExpected)
+		store.QueryReturns(nil, errExpected)
+		store.DeleteReturns(errExpected)
+
+		p := &mocks.Provider{}
+		p.OpenStoreReturns(store, nil)
+
+		s, err := newStore(p)
+		require.NoError(t, err)
+
+		require.ErrorIs(t, s.put(&Relationship{Witness: witness1}), errExpected)
+
+		_, err = s.get(witness1)
+		require.ErrorIs(t, err, errExpected)
+
+		_, err = s.getAll()
+		require.ErrorIs(t, err, errExpected)
+
+		require.ErrorIs(t, s.delete(witness1), errExpected)
+	})
+
+	t.Run("Iterator errors", func(t *testing.T) {
+		errExpected := errors.New("injected iterator error")
+
+		it := &mocks.Iterator{}
+		it.NextReturns(false, errExpected)
+
+		store := &mocks.Store{}
+		store.QueryReturns(it, nil)
+
+		p := &mocks.Provider{}
+		p.OpenStoreReturns(store, nil)
+
+		s, err := newStore(p)
+		require.NoError(t, err)
+
+		_, err = s.getAll()
+		require.ErrorIs(t, err, errExpected)
+
+		it.NextReturns(true, nil)
+		it.ValueReturns(nil, errExpected)
+
+		_, err = s.getAll()
+		require.ErrorIs(t, err, errExpected)
+
+		it.ValueReturns([]byte("xxx"), nil)
+
+		_, err = s.getAll()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal witness relationship")
+	})
+
+	t.Run("Unmarshal error", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.GetReturns([]byte("xxx"), nil)
+
+		p := &mocks.Provider{}
+		p.OpenStoreReturns(store, nil)
+
+		s, err := newStore(p)
+		require.NoError(t, err)
+
+		_, err = s.get(witness1)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal witness relationship")
+	})
+}
@@ -114,6 +114,13 @@ func (g *Generator) CreateContentObject(payload *subject.Payload) (vocab.Documen
 		return nil, fmt.Errorf("payload is missing previous anchors")
 	}
 
+	network := g.network(payload.Namespace)
+
+	prefix := multihashPrefix
+	if network != "" {
+		prefix += separator + network
+	}
+
 	var resources []*resource
 
 	for _, value := range payload.PreviousAnchors {
@@ -122,7 +129,7 @@ func (g *Generator) CreateContentObject(payload *subject.Payload) (vocab.Documen
 		var res *resource
 
 		if value.Anchor == "" {
-			res = &resource{ID: fmt.Sprintf("%s:%s:%s", multihashPrefix, unpublishedLabel, value.Suffix)}
+			res = &resource{ID: fmt.Sprintf("%s:%s:%s", prefix, unpublishedLabel, value.Suffix)}
 		} else {
 			parts := strings.Split(value.Anchor, separator)
 
@@ -137,7 +144,7 @@ func (g *Generator) CreateContentObject(payload *subject.Payload) (vocab.Documen
 
 			prevAnchor := parts[0] + separator + parts[1]
 
-			res = &resource{ID: fmt.Sprintf("%s:%s:%s", multihashPrefix, parts[1], value.Suffix), PreviousAnchor: prevAnchor}
+			res = &resource{ID: fmt.Sprintf("%s:%s:%s", prefix, parts[1], value.Suffix), PreviousAnchor: prevAnchor}
 		}
 
 		resources = append(resources, res)
@@ -147,6 +154,7 @@ func (g *Generator) CreateContentObject(payload *subject.Payload) (vocab.Documen
 		Subject: payload.CoreIndex,
 		Properties: &propertiesType{
 			Generator: g.id,
+			Network:   network,
 			Resources: resources,
 		},
 	}
@@ -186,8 +194,13 @@ func (g *Generator) CreatePayload(anchorEvent *vocab.AnchorEventType) (*subject.
 		return nil, fmt.Errorf("failed to parse previous anchors from anchorEvent: %w", err)
 	}
 
+	namespace := g.namespace
+	if contentObj.Network() != "" {
+		namespace += separator + contentObj.Network()
+	}
+
 	return &subject.Payload{
-		Namespace:       g.namespace,
+		Namespace:       namespace,
 		Version:         g.version,
 		CoreIndex:       contentObj.Subject,
 		OperationCount:  operationCount,
@@ -221,8 +234,19 @@ func (g *Generator) getPreviousAnchors(resources []*resource, previous []*url.UR
 	return previousAnchors, nil
 }
 
+// network returns the network identifier of the given namespace (for example, "testnet" for did:orb:testnet)
+// or an empty string if the namespace doesn't include a network.
+func (g *Generator) network(namespace string) string {
+	if !strings.HasPrefix(namespace, g.namespace+separator) {
+		return ""
+	}
+
+	return namespace[len(g.namespace+separator):]
+}
+
 type propertiesType struct {
 	Generator string      `json:"https://w3id.org/activityanchors#generator,omitempty"`
+	Network   string      `json:"https://w3id.org/activityanchors#network,omitempty"`
 	Resources []*resource `json:"https://w3id.org/activityanchors#resources,omitempty"`
 }
 
@@ -244,6 +268,14 @@ func (t *contentObject) Resources() []*resource {
 	return t.Properties.Resources
 }
 
+func (t *contentObject) Network() string {
+	if t == nil || t.Properties == nil {
+		return ""
+	}
+
+	return t.Properties.Network
+}
+
 func getPreviousAnchorForResource(suffix, res string, previous []*url.URL) (*subject.SuffixAnchor, error) {
 	for _, prev := range previous {
 		if !strings.HasPrefix(prev.String(), res) {
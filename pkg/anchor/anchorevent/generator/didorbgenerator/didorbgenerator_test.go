@@ -125,6 +125,54 @@ func TestGenerator_CreateContentObject(t *testing.T) {
 	})
 }
 
+func TestGenerator_Network(t *testing.T) {
+	const namespace = Namespace + ":testnet"
+
+	gen := New()
+	require.NotNil(t, gen)
+
+	payload := &subject.Payload{
+		Namespace: namespace,
+		CoreIndex: coreIndexHL1,
+		PreviousAnchors: []*subject.SuffixAnchor{
+			{Suffix: suffix1},
+			{Suffix: suffix2, Anchor: parentHL1},
+		},
+	}
+
+	contentObjDoc, err := gen.CreateContentObject(payload)
+	require.NoError(t, err)
+
+	contentObj := &contentObject{}
+	require.NoError(t, contentObjDoc.Unmarshal(contentObj))
+	require.Equal(t, "testnet", contentObj.Network())
+	require.Len(t, contentObj.Resources(), 2)
+	require.Equal(t, fmt.Sprintf("%s:%s:%s", namespace, unpublishedLabel, suffix1), contentObj.Resources()[0].ID)
+	require.Equal(t, fmt.Sprintf("%s:%s:%s", namespace, parentMH1, suffix2), contentObj.Resources()[1].ID)
+
+	witnessAnchorObj, err := vocab.NewAnchorObject(ID, vocab.MustUnmarshalToDoc([]byte(verifiableCred)))
+	require.NoError(t, err)
+
+	indexAnchorObj, err := vocab.NewAnchorObject(ID, contentObjDoc,
+		vocab.WithLink(vocab.NewLink(witnessAnchorObj.URL()[0], vocab.RelationshipWitness)))
+	require.NoError(t, err)
+
+	anchorEvent := vocab.NewAnchorEvent(
+		vocab.WithIndex(indexAnchorObj.URL()[0]),
+		vocab.WithParent(testutil.MustParseURL(parentHL1)),
+		vocab.WithAttachment(vocab.NewObjectProperty(vocab.WithAnchorObject(indexAnchorObj))),
+		vocab.WithAttachment(vocab.NewObjectProperty(vocab.WithAnchorObject(witnessAnchorObj))),
+		vocab.WithAttributedTo(testutil.MustParseURL(service1)),
+	)
+
+	p, err := gen.CreatePayload(anchorEvent)
+	require.NoError(t, err)
+	require.Equal(t, namespace, p.Namespace)
+	require.Len(t, p.PreviousAnchors, 2)
+	require.Equal(t, suffix1, p.PreviousAnchors[0].Suffix)
+	require.Equal(t, parentHL1, p.PreviousAnchors[1].Anchor)
+}
+
 func TestGenerator_GetPayloadFromAnchorEvent(t *testing.T) {
 	gen := New()
 	require.NotNil(t, gen)
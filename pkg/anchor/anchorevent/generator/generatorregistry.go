@@ -8,6 +8,7 @@ package generator
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/trustbloc/orb/pkg/activitypub/vocab"
 	"github.com/trustbloc/orb/pkg/anchor/anchorevent/generator/didorbgenerator"
@@ -51,10 +52,12 @@ func (r *Registry) Get(id string) (Generator, error) {
 	return nil, fmt.Errorf("generator not found [%s]: %w", id, orberrors.ErrContentNotFound)
 }
 
-// GetByNamespaceAndVersion returns the generator for the given namespace and version.
+// GetByNamespaceAndVersion returns the generator for the given namespace and version. The namespace may
+// include a network (for example, did:orb:testnet) in which case the generator for the base namespace
+// (did:orb) is returned.
 func (r *Registry) GetByNamespaceAndVersion(ns string, ver uint64) (Generator, error) {
 	for _, generator := range r.generators {
-		if generator.Namespace() == ns && generator.Version() == ver {
+		if matchesNamespace(generator.Namespace(), ns) && generator.Version() == ver {
 			return generator, nil
 		}
 	}
@@ -62,3 +65,7 @@ func (r *Registry) GetByNamespaceAndVersion(ns string, ver uint64) (Generator, e
 	return nil, fmt.Errorf("generator not found for namespace [%s] and version [%d]: %w",
 		ns, ver, orberrors.ErrContentNotFound)
 }
+
+func matchesNamespace(generatorNS, ns string) bool {
+	return ns == generatorNS || strings.HasPrefix(ns, generatorNS+":")
+}
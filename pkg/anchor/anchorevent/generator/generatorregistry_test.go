@@ -59,6 +59,17 @@ func TestRegistry_GetByNamespaceAndVersion(t *testing.T) {
 		require.Equal(t, samplegenerator.Version, gen.Version())
 	})
 
+	t.Run("Namespace with network", func(t *testing.T) {
+		gen, err := r.GetByNamespaceAndVersion(didorbgenerator.Namespace+":testnet", didorbgenerator.Version)
+		require.NoError(t, err)
+		require.NotNil(t, gen)
+		require.Equal(t, didorbgenerator.Namespace, gen.Namespace())
+
+		_, err = r.GetByNamespaceAndVersion(didorbgenerator.Namespace+"x", didorbgenerator.Version)
+		require.Error(t, err)
+		require.True(t, errors.Is(err, orberrors.ErrContentNotFound))
+	})
+
 	t.Run("Not found", func(t *testing.T) {
 		gen, err := r.GetByNamespaceAndVersion("invalid", 1)
 		require.Error(t, err)
@@ -0,0 +1,297 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package explorer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/trustbloc/edge-core/pkg/log"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+
+	"github.com/trustbloc/orb/pkg/store/anchorindex"
+)
+
+var logger = log.New("anchor-explorer")
+
+const (
+	// AnchorsPath is the path of the anchor search endpoint.
+	AnchorsPath = "/explorer/anchors"
+
+	idPathVariable = "id"
+
+	fromParam     = "from"
+	toParam       = "to"
+	originParam   = "origin"
+	witnessParam  = "witness"
+	suffixParam   = "suffix"
+	pageParam     = "page"
+	pageSizeParam = "size"
+
+	defaultPageSize = 25
+	maxPageSize     = 100
+
+	dateLayout = "2006-01-02"
+)
+
+const (
+	notFoundResponse            = "Not Found."
+	internalServerErrorResponse = "Internal Server Error."
+)
+
+type anchorIndex interface {
+	Get(canonicalRef string) (*anchorindex.Entry, error)
+	Search(criteria *anchorindex.Criteria, pageNum, pageSize int) (*anchorindex.Results, error)
+}
+
+// SearchResults contains a page of anchors that match the search criteria.
+type SearchResults struct {
+	Total    int                  `json:"total"`
+	Page     int                  `json:"page"`
+	PageSize int                  `json:"pageSize"`
+	Anchors  []*anchorindex.Entry `json:"anchors"`
+}
+
+type handler struct {
+	index   anchorIndex
+	marshal func(v interface{}) ([]byte, error)
+}
+
+func newHandler(index anchorIndex) *handler {
+	return &handler{
+		index:   index,
+		marshal: json.Marshal,
+	}
+}
+
+// Search implements the anchor search endpoint. Anchors may be searched by time range ('from' and 'to'),
+// origin domain ('origin'), witness domain ('witness') or DID suffix ('suffix'). The results are
+// returned most recent first and are paged using the 'page' and 'size' parameters, for example:
+//
+//	GET /explorer/anchors?witness=witness1.com&from=2021-09-01&page=1&size=10
+type Search struct {
+	*handler
+}
+
+// NewSearch returns a new anchor search handler.
+func NewSearch(index anchorIndex) *Search {
+	return &Search{handler: newHandler(index)}
+}
+
+// Path returns the HTTP REST endpoint for the anchor search handler.
+func (h *Search) Path() string {
+	return AnchorsPath
+}
+
+// Method returns the HTTP REST method for the anchor search handler.
+func (h *Search) Method() string {
+	return http.MethodGet
+}
+
+// Handler returns the HTTP REST handle for the anchor search handler.
+func (h *Search) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Search) handle(w http.ResponseWriter, req *http.Request) {
+	criteria, err := getCriteria(req)
+	if err != nil {
+		writeResponse(w, http.StatusBadRequest, []byte(err.Error()))
+
+		return
+	}
+
+	page, err := getIntParam(req, pageParam, 0, 0, -1)
+	if err != nil {
+		writeResponse(w, http.StatusBadRequest, []byte(err.Error()))
+
+		return
+	}
+
+	pageSize, err := getIntParam(req, pageSizeParam, defaultPageSize, 1, maxPageSize)
+	if err != nil {
+		writeResponse(w, http.StatusBadRequest, []byte(err.Error()))
+
+		return
+	}
+
+	results, err := h.index.Search(criteria, page, pageSize)
+	if err != nil {
+		logger.Errorf("[%s] Error searching anchors: %s", AnchorsPath, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	h.writeJSON(w, &SearchResults{
+		Total:    results.Total,
+		Page:     page,
+		PageSize: pageSize,
+		Anchors:  results.Anchors,
+	})
+}
+
+// Anchor returns the indexed information of a single anchor.
+type Anchor struct {
+	*handler
+}
+
+// NewAnchor returns a new anchor handler.
+func NewAnchor(index anchorIndex) *Anchor {
+	return &Anchor{handler: newHandler(index)}
+}
+
+// Path returns the HTTP REST endpoint for the anchor handler.
+func (h *Anchor) Path() string {
+	return fmt.Sprintf("%s/{%s}", AnchorsPath, idPathVariable)
+}
+
+// Method returns the HTTP REST method for the anchor handler.
+func (h *Anchor) Method() string {
+	return http.MethodGet
+}
+
+// Handler returns the HTTP REST handle for the anchor handler.
+func (h *Anchor) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Anchor) handle(w http.ResponseWriter, req *http.Request) {
+	id := mux.Vars(req)[idPathVariable]
+
+	entry, err := h.index.Get(id)
+	if err != nil {
+		if errors.Is(err, anchorindex.ErrNotFound) {
+			writeResponse(w, http.StatusNotFound, []byte(notFoundResponse))
+
+			return
+		}
+
+		logger.Errorf("[%s] Error retrieving anchor [%s]: %s", AnchorsPath, id, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	h.writeJSON(w, entry)
+}
+
+func (h *handler) writeJSON(w http.ResponseWriter, v interface{}) {
+	respBytes, err := h.marshal(v)
+	if err != nil {
+		logger.Errorf("[%s] Error marshalling response: %s", AnchorsPath, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	writeResponse(w, http.StatusOK, respBytes)
+}
+
+func getCriteria(req *http.Request) (*anchorindex.Criteria, error) {
+	query := req.URL.Query()
+
+	from, err := getTime(query.Get(fromParam), false)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for parameter '%s': %w", fromParam, err)
+	}
+
+	to, err := getTime(query.Get(toParam), true)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for parameter '%s': %w", toParam, err)
+	}
+
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		return nil, fmt.Errorf("parameter '%s' must not be before parameter '%s'", toParam, fromParam)
+	}
+
+	criteria := &anchorindex.Criteria{
+		Origin:  query.Get(originParam),
+		Witness: query.Get(witnessParam),
+		Suffix:  query.Get(suffixParam),
+		From:    from,
+		To:      to,
+	}
+
+	n := 0
+
+	for _, v := range []string{criteria.Origin, criteria.Witness, criteria.Suffix} {
+		if v != "" {
+			n++
+		}
+	}
+
+	if n > 1 {
+		return nil, fmt.Errorf("only one of parameters '%s', '%s' or '%s' may be specified",
+			originParam, witnessParam, suffixParam)
+	}
+
+	return criteria, nil
+}
+
+// getTime parses the given value as either an RFC3339 time or a date. If endOfDay is true then a date
+// is interpreted as the end of the day (so that the whole day is included in the range).
+func getTime(value string, endOfDay bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err == nil {
+		return t, nil
+	}
+
+	t, err = time.Parse(dateLayout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expecting RFC3339 time or date (%s): %s", dateLayout, value)
+	}
+
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Second)
+	}
+
+	return t, nil
+}
+
+// getIntParam returns the value of the given integer parameter. A max value less than zero indicates no maximum.
+func getIntParam(req *http.Request, name string, defaultValue, minValue, maxValue int) (int, error) {
+	value := req.URL.Query().Get(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < minValue || (maxValue >= 0 && n > maxValue) {
+		return 0, fmt.Errorf("invalid value for parameter '%s': %s", name, value)
+	}
+
+	return n, nil
+}
+
+func writeResponse(w http.ResponseWriter, status int, body []byte) {
+	w.WriteHeader(status)
+
+	if len(body) > 0 {
+		if _, err := w.Write(body); err != nil {
+			logger.Warnf("[%s] Unable to write response: %s", AnchorsPath, err)
+
+			return
+		}
+
+		logger.Debugf("[%s] Wrote response: %s", AnchorsPath, body)
+	}
+}
@@ -0,0 +1,202 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package explorer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/internal/testutil/httptestutil"
+	"github.com/trustbloc/orb/pkg/store/anchorindex"
+)
+
+func TestSearch(t *testing.T) {
+	index := newIndex(t)
+
+	h := NewSearch(index)
+	require.Equal(t, AnchorsPath, h.Path())
+	require.Equal(t, http.MethodGet, h.Method())
+	require.NotNil(t, h.Handler())
+
+	t.Run("All", func(t *testing.T) {
+		code, body := httptestutil.Get(t, h.handle, AnchorsPath)
+		require.Equal(t, http.StatusOK, code)
+
+		results := &SearchResults{}
+		require.NoError(t, json.Unmarshal(body, results))
+		require.Equal(t, 3, results.Total)
+		require.Equal(t, 0, results.Page)
+		require.Equal(t, defaultPageSize, results.PageSize)
+		require.Len(t, results.Anchors, 3)
+		require.Equal(t, "anchor3", results.Anchors[0].CanonicalReference)
+	})
+
+	t.Run("Paging", func(t *testing.T) {
+		code, body := httptestutil.Get(t, h.handle, AnchorsPath+"?page=1&size=2")
+		require.Equal(t, http.StatusOK, code)
+
+		results := &SearchResults{}
+		require.NoError(t, json.Unmarshal(body, results))
+		require.Equal(t, 3, results.Total)
+		require.Equal(t, 1, results.Page)
+		require.Equal(t, 2, results.PageSize)
+		require.Len(t, results.Anchors, 1)
+		require.Equal(t, "anchor1", results.Anchors[0].CanonicalReference)
+	})
+
+	t.Run("Criteria", func(t *testing.T) {
+		results := search(t, h, "?witness=witness1.com")
+		require.Equal(t, 2, results.Total)
+
+		results = search(t, h, "?origin=orb.domain2.com")
+		require.Equal(t, 1, results.Total)
+		require.Equal(t, "anchor2", results.Anchors[0].CanonicalReference)
+
+		results = search(t, h, "?suffix=suffix3")
+		require.Equal(t, 1, results.Total)
+		require.Equal(t, "anchor3", results.Anchors[0].CanonicalReference)
+
+		results = search(t, h, "?from=2021-09-02")
+		require.Equal(t, 2, results.Total)
+
+		results = search(t, h, "?to=2021-09-02")
+		require.Equal(t, 2, results.Total)
+
+		results = search(t, h, "?from=2021-09-02T00:00:00Z&to=2021-09-02T23:59:59Z&witness=witness1.com")
+		require.Equal(t, 1, results.Total)
+		require.Equal(t, "anchor2", results.Anchors[0].CanonicalReference)
+	})
+
+	t.Run("Invalid parameters", func(t *testing.T) {
+		for _, query := range []string{
+			"?from=xxx",
+			"?to=2021-13-01",
+			"?from=2021-09-02&to=2021-09-01",
+			"?origin=orb.domain1.com&suffix=suffix1",
+			"?page=-1",
+			"?page=xxx",
+			"?size=0",
+			fmt.Sprintf("?size=%d", maxPageSize+1),
+		} {
+			code, _ := httptestutil.Get(t, h.handle, AnchorsPath+query)
+			require.Equal(t, http.StatusBadRequest, code, query)
+		}
+	})
+
+	t.Run("Search error", func(t *testing.T) {
+		code, _ := httptestutil.Get(t, NewSearch(&mockIndex{err: errors.New("injected error")}).handle, AnchorsPath)
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		h := NewSearch(index)
+		h.marshal = func(v interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		code, _ := httptestutil.Get(t, h.handle, AnchorsPath)
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+}
+
+func TestAnchor(t *testing.T) {
+	index := newIndex(t)
+
+	h := NewAnchor(index)
+	require.Equal(t, AnchorsPath+"/{id}", h.Path())
+	require.Equal(t, http.MethodGet, h.Method())
+	require.NotNil(t, h.Handler())
+
+	t.Run("Success", func(t *testing.T) {
+		code, body := httptestutil.Serve(t, h.handle, http.MethodGet, AnchorsPath+"/anchor1", nil,
+			map[string]string{idPathVariable: "anchor1"})
+		require.Equal(t, http.StatusOK, code)
+
+		entry := &anchorindex.Entry{}
+		require.NoError(t, json.Unmarshal(body, entry))
+		require.Equal(t, "hl:anchor1", entry.Hashlink)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		code, _ := httptestutil.Serve(t, h.handle, http.MethodGet, AnchorsPath+"/anchor4", nil,
+			map[string]string{idPathVariable: "anchor4"})
+		require.Equal(t, http.StatusNotFound, code)
+	})
+
+	t.Run("Index error", func(t *testing.T) {
+		code, _ := httptestutil.Serve(t, NewAnchor(&mockIndex{err: errors.New("injected error")}).handle,
+			http.MethodGet, AnchorsPath+"/anchor1", nil, map[string]string{idPathVariable: "anchor1"})
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+}
+
+func newIndex(t *testing.T) *anchorindex.Store {
+	t.Helper()
+
+	index, err := anchorindex.New(mem.NewProvider())
+	require.NoError(t, err)
+
+	day := time.Date(2021, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	require.NoError(t, index.Put(&anchorindex.Entry{
+		CanonicalReference: "anchor1",
+		Hashlink:           "hl:anchor1",
+		Origin:             "https://orb.domain1.com/services/orb",
+		Published:          day,
+		Witnesses:          []string{"https://witness1.com/vct"},
+		Suffixes:           []string{"suffix1"},
+	}))
+
+	require.NoError(t, index.Put(&anchorindex.Entry{
+		CanonicalReference: "anchor2",
+		Hashlink:           "hl:anchor2",
+		Origin:             "https://orb.domain2.com/services/orb",
+		Published:          day.AddDate(0, 0, 1),
+		Witnesses:          []string{"https://witness1.com/vct"},
+		Suffixes:           []string{"suffix2"},
+	}))
+
+	require.NoError(t, index.Put(&anchorindex.Entry{
+		CanonicalReference: "anchor3",
+		Hashlink:           "hl:anchor3",
+		Origin:             "https://orb.domain1.com/services/orb",
+		Published:          day.AddDate(0, 0, 2),
+		Witnesses:          []string{"https://witness2.com/vct"},
+		Suffixes:           []string{"suffix3"},
+	}))
+
+	return index
+}
+
+func search(t *testing.T, h *Search, query string) *SearchResults {
+	t.Helper()
+
+	code, body := httptestutil.Get(t, h.handle, AnchorsPath+query)
+	require.Equal(t, http.StatusOK, code, string(body))
+
+	results := &SearchResults{}
+	require.NoError(t, json.Unmarshal(body, results))
+
+	return results
+}
+
+type mockIndex struct {
+	err error
+}
+
+func (m *mockIndex) Get(string) (*anchorindex.Entry, error) {
+	return nil, m.err
+}
+
+func (m *mockIndex) Search(*anchorindex.Criteria, int, int) (*anchorindex.Results, error) {
+	return nil, m.err
+}
@@ -10,6 +10,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"sync"
 
 	"github.com/piprate/json-gold/ld"
 	"github.com/trustbloc/edge-core/pkg/log"
@@ -23,9 +24,14 @@ import (
 
 var logger = log.New("anchor-graph")
 
+const defaultMaxConcurrentFetches = 10
+
 // Graph manages anchor graph.
 type Graph struct {
 	*Providers
+
+	maxConcurrentFetches int
+	signer               linksetSigner
 }
 
 // Providers for anchor graph.
@@ -35,11 +41,37 @@ type Providers struct {
 	DocLoader   ld.DocumentLoader
 }
 
+// Option is a graph option.
+type Option func(g *Graph)
+
+// WithMaxConcurrentFetches sets the maximum number of CAS documents that are prefetched concurrently
+// while the anchor graph is traversed by GetNewDidAnchors. Defaults to 10.
+func WithMaxConcurrentFetches(value int) Option {
+	return func(g *Graph) {
+		g.maxConcurrentFetches = value
+	}
+}
+
+// WithSigner sets the signer that signs the anchor events that are added to the graph. The signature is
+// served along with the anchor event so that tampering with the anchor event in a CAS replica may be detected.
+func WithSigner(signer linksetSigner) Option {
+	return func(g *Graph) {
+		g.signer = signer
+	}
+}
+
 // New creates new graph manager.
-func New(providers *Providers) *Graph {
-	return &Graph{
-		Providers: providers,
+func New(providers *Providers, opts ...Option) *Graph {
+	g := &Graph{
+		Providers:            providers,
+		maxConcurrentFetches: defaultMaxConcurrentFetches,
+	}
+
+	for _, opt := range opts {
+		opt(g)
 	}
+
+	return g
 }
 
 type casResolver interface {
@@ -50,6 +82,10 @@ type casWriter interface {
 	Write(content []byte) (string, error)
 }
 
+type linksetSigner interface {
+	Sign(hl string, content []byte) error
+}
+
 // Add adds an anchor to the anchor graph.
 // Returns hl that contains anchor information.
 func (g *Graph) Add(anchorEvent *vocab.AnchorEventType) (string, error) { //nolint:interfacer
@@ -63,6 +99,13 @@ func (g *Graph) Add(anchorEvent *vocab.AnchorEventType) (string, error) { //noli
 		return "", errors.NewTransient(fmt.Errorf("failed to add anchor to graph: %w", err))
 	}
 
+	if g.signer != nil {
+		err = g.signer.Sign(hl, canonicalBytes)
+		if err != nil {
+			return "", fmt.Errorf("sign anchor event [%s]: %w", hl, err)
+		}
+	}
+
 	logger.Debugf("added anchor event[%s]: %s", hl, string(canonicalBytes))
 
 	return hl, nil
@@ -95,14 +138,41 @@ type Anchor struct {
 
 // GetDidAnchors returns all anchors that are referencing did suffix starting from hl.
 func (g *Graph) GetDidAnchors(hl, suffix string) ([]Anchor, error) {
-	var refs []Anchor
-
 	logger.Debugf("getting did anchors for hl[%s], suffix[%s]", hl, suffix)
 
+	return g.getDidAnchors(hl, suffix, nil, nil)
+}
+
+// GetNewDidAnchors returns the anchors that are referencing did suffix starting from hl, stopping at the first
+// anchor for which isKnown returns true. The known anchor and its ancestors are assumed to have been processed
+// already and aren't returned. While the previous anchors are traversed, the core index of each anchor is
+// prefetched from CAS (with bounded parallelism) so that the returned anchors may be processed without having
+// to wait for remote CAS reads.
+func (g *Graph) GetNewDidAnchors(hl, suffix string, isKnown func(hl string) bool) ([]Anchor, error) {
+	logger.Debugf("getting new did anchors for hl[%s], suffix[%s]", hl, suffix)
+
+	p := newPrefetcher(g.CasResolver, g.maxConcurrentFetches)
+
+	anchors, err := g.getDidAnchors(hl, suffix, isKnown, p)
+
+	p.wait()
+
+	return anchors, err
+}
+
+func (g *Graph) getDidAnchors(hl, suffix string, isKnown func(hl string) bool, p *prefetcher) ([]Anchor, error) {
+	var refs []Anchor
+
 	cur := hl
 	ok := true
 
 	for ok {
+		if isKnown != nil && isKnown(cur) {
+			logger.Debugf("anchor[%s] for did[%s] is already known - stopping traversal", cur, suffix)
+
+			break
+		}
+
 		anchorEvent, err := g.Read(cur)
 		if err != nil {
 			return nil, fmt.Errorf("failed to read anchor event[%s] for did[%s]: %w", cur, suffix, err)
@@ -118,6 +188,10 @@ func (g *Graph) GetDidAnchors(hl, suffix string) ([]Anchor, error) {
 			return nil, err
 		}
 
+		if p != nil {
+			p.prefetch(payload.CoreIndex)
+		}
+
 		previousAnchors := payload.PreviousAnchors
 
 		cur, ok = contains(suffix, previousAnchors)
@@ -148,3 +222,48 @@ func reverseOrder(original []Anchor) []Anchor {
 
 	return reversed
 }
+
+// prefetcher resolves CAS documents in the background so that they're stored in the local CAS by the
+// time they're needed. At most maxConcurrent documents are resolved at the same time.
+type prefetcher struct {
+	resolver casResolver
+	sem      chan struct{}
+	wg       sync.WaitGroup
+}
+
+func newPrefetcher(resolver casResolver, maxConcurrent int) *prefetcher {
+	if maxConcurrent <= 0 {
+		maxConcurrent = 1
+	}
+
+	return &prefetcher{
+		resolver: resolver,
+		sem:      make(chan struct{}, maxConcurrent),
+	}
+}
+
+func (p *prefetcher) prefetch(hl string) {
+	if hl == "" {
+		return
+	}
+
+	p.wg.Add(1)
+
+	go func() {
+		defer p.wg.Done()
+
+		p.sem <- struct{}{}
+		defer func() { <-p.sem }()
+
+		// Errors are ignored since the document will be resolved again (and the error reported)
+		// when the anchor is processed.
+		if _, _, err := p.resolver.Resolve(nil, hl, nil); err != nil {
+			logger.Debugf("error prefetching [%s]: %s", hl, err)
+		}
+	}()
+}
+
+// wait waits for all outstanding prefetches to complete.
+func (p *prefetcher) wait() {
+	p.wg.Wait()
+}
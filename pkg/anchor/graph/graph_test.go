@@ -7,6 +7,10 @@ SPDX-License-Identifier: Apache-2.0
 package graph
 
 import (
+	"errors"
+	"net/url"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -60,6 +64,26 @@ func TestGraph_Add(t *testing.T) {
 		require.NoError(t, err)
 		require.NotEmpty(t, hl)
 	})
+
+	t.Run("success - signed", func(t *testing.T) {
+		signer := &mockLinksetSigner{}
+
+		graph := New(providers, WithSigner(signer))
+
+		hl, err := graph.Add(newDefaultMockAnchorEvent(t))
+		require.NoError(t, err)
+		require.Equal(t, hl, signer.hl)
+		require.NotEmpty(t, signer.content)
+	})
+
+	t.Run("sign error", func(t *testing.T) {
+		errExpected := errors.New("injected sign error")
+
+		graph := New(providers, WithSigner(&mockLinksetSigner{err: errExpected}))
+
+		_, err := graph.Add(newDefaultMockAnchorEvent(t))
+		require.ErrorIs(t, err, errExpected)
+	})
 }
 
 func TestGraph_Read(t *testing.T) {
@@ -256,6 +280,82 @@ func TestGraph_GetDidAnchors(t *testing.T) {
 	})
 }
 
+func TestGraph_GetNewDidAnchors(t *testing.T) {
+	casClient, err := cas.New(mem.NewProvider(), casLink, nil, &metricsProvider{}, 0)
+	require.NoError(t, err)
+
+	resolver := &recordingResolver{
+		casResolver: casresolver.New(casClient, nil,
+			casresolver.NewWebCASResolver(
+				&apmocks.HTTPTransport{}, webfingerclient.New(), "https"),
+			&metricsProvider{}),
+	}
+
+	providers := &Providers{
+		CasWriter:   casClient,
+		CasResolver: resolver,
+		DocLoader:   testutil.GetLoader(t),
+	}
+
+	graph := New(providers, WithMaxConcurrentFetches(2))
+
+	// Create a chain of three anchors for the DID.
+	var hls []string
+
+	previous := ""
+
+	for _, coreIndex := range []string{"coreIndex-1", "coreIndex-2", "coreIndex-3"} {
+		hl, err := graph.Add(newMockAnchorEvent(t, &subject.Payload{
+			OperationCount:  1,
+			CoreIndex:       coreIndex,
+			Namespace:       testNS,
+			PreviousAnchors: []*subject.SuffixAnchor{{Suffix: testDID, Anchor: previous}},
+		}))
+		require.NoError(t, err)
+
+		hls = append(hls, hl)
+		previous = hl
+	}
+
+	t.Run("success - no known anchors", func(t *testing.T) {
+		resolver.reset()
+
+		didAnchors, err := graph.GetNewDidAnchors(hls[2], testDID, func(string) bool { return false })
+		require.NoError(t, err)
+		require.Len(t, didAnchors, 3)
+		require.Equal(t, hls[0], didAnchors[0].CID)
+		require.Equal(t, hls[2], didAnchors[2].CID)
+
+		require.ElementsMatch(t, []string{"coreIndex-1", "coreIndex-2", "coreIndex-3"}, resolver.coreIndexes())
+	})
+
+	t.Run("success - stop at known anchor", func(t *testing.T) {
+		resolver.reset()
+
+		didAnchors, err := graph.GetNewDidAnchors(hls[2], testDID, func(hl string) bool { return hl == hls[0] })
+		require.NoError(t, err)
+		require.Len(t, didAnchors, 2)
+		require.Equal(t, hls[1], didAnchors[0].CID)
+		require.Equal(t, hls[2], didAnchors[1].CID)
+
+		require.ElementsMatch(t, []string{"coreIndex-2", "coreIndex-3"}, resolver.coreIndexes())
+	})
+
+	t.Run("success - head anchor is known", func(t *testing.T) {
+		didAnchors, err := New(providers).GetNewDidAnchors(hls[2], testDID, func(string) bool { return true })
+		require.NoError(t, err)
+		require.Empty(t, didAnchors)
+	})
+
+	t.Run("error - head cid not found", func(t *testing.T) {
+		didAnchors, err := New(providers, WithMaxConcurrentFetches(0)).GetNewDidAnchors(
+			"hl:"+nonExistent, testDID, func(string) bool { return false })
+		require.Error(t, err)
+		require.Nil(t, didAnchors)
+		require.Contains(t, err.Error(), "failed to read anchor event")
+	})
+}
+
 func newDefaultMockAnchorEvent(t *testing.T) *vocab.AnchorEventType {
 	t.Helper()
 
@@ -310,3 +410,54 @@ func (m *metricsProvider) CASIncrementCacheHitCount() {
 
 func (m *metricsProvider) CASReadTime(casType string, value time.Duration) {
 }
+
+type recordingResolver struct {
+	casResolver
+
+	mutex    sync.Mutex
+	resolved []string
+}
+
+func (r *recordingResolver) Resolve(webCASURL *url.URL, hl string, data []byte) ([]byte, string, error) {
+	r.mutex.Lock()
+	r.resolved = append(r.resolved, hl)
+	r.mutex.Unlock()
+
+	return r.casResolver.Resolve(webCASURL, hl, data)
+}
+
+func (r *recordingResolver) reset() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.resolved = nil
+}
+
+// coreIndexes returns the resolved core indexes (i.e. the resolved values that aren't hashlinks).
+func (r *recordingResolver) coreIndexes() []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var coreIndexes []string
+
+	for _, hl := range r.resolved {
+		if !strings.HasPrefix(hl, "hl:") {
+			coreIndexes = append(coreIndexes, hl)
+		}
+	}
+
+	return coreIndexes
+}
+
+type mockLinksetSigner struct {
+	hl      string
+	content []byte
+	err     error
+}
+
+func (m *mockLinksetSigner) Sign(hl string, content []byte) error {
+	m.hl = hl
+	m.content = content
+
+	return m.err
+}
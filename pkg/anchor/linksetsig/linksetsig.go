@@ -0,0 +1,390 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package linksetsig
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	"github.com/trustbloc/orb/pkg/activitypub/multikey"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/hashlink"
+	"github.com/trustbloc/orb/pkg/linkset"
+)
+
+var logger = log.New("linkset-signature")
+
+// HeaderName is the name of the WebCAS response header that contains the detached JWS of the content.
+const HeaderName = "Orb-Linkset-Signature"
+
+// ErrInvalidSignature is returned when the detached JWS of the content can't be verified.
+var ErrInvalidSignature = errors.New("invalid linkset signature")
+
+// ErrNotLinkset is returned by AnchorOrigin if the content isn't an anchor linkset (for example, a Sidetree
+// batch file). Such content isn't signed.
+var ErrNotLinkset = errors.New("content is not an anchor linkset")
+
+const (
+	storeName = "linkset-signature"
+
+	// publicKeyPrefix is the prefix of the store keys under which the signing public keys are persisted.
+	publicKeyPrefix = "pubkey-"
+
+	// keyRefLength is the number of bytes of the public key hash that are used to reference the key.
+	keyRefLength = 16
+
+	algEdDSA = "EdDSA"
+	jwsParts = 3
+)
+
+type keyManager interface {
+	Get(keyID string) (interface{}, error)
+	ExportPubKeyBytes(keyID string) ([]byte, error)
+}
+
+type signingCrypto interface {
+	Sign(msg []byte, kh interface{}) ([]byte, error)
+}
+
+type publicKeyRetriever interface {
+	GetPublicKey(keyIRI *url.URL) (*vocab.PublicKeyType, error)
+	InvalidatePublicKey(keyIRI *url.URL)
+}
+
+type jwsHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// Signer signs the anchor linksets (anchor events) that are written to the CAS with a detached JWS (RFC 7515,
+// Appendix F) using the service's HTTP signature key, so that tampering with the content of a CAS replica may
+// be detected independently of the anchor credential. The signatures are stored by resource hash and are
+// served along with the content by the WebCAS endpoint.
+//
+// The key ID of the JWS references the specific key that was used for signing (the service's public key IRI
+// with a suffix derived from the key) and the public key is persisted, so that signatures created before the
+// HTTP signature key was rotated may still be verified.
+type Signer struct {
+	store        storage.Store
+	km           keyManager
+	cr           signingCrypto
+	publicKeyIRI *url.URL
+
+	mutex sync.RWMutex
+	keyID string
+	kid   string
+}
+
+// NewSigner returns a new linkset signer. The key ID of the JWS is derived from the given public key IRI.
+func NewSigner(provider storage.Provider, km keyManager, cr signingCrypto, keyID string,
+	publicKeyIRI *url.URL) (*Signer, error) {
+	store, err := provider.OpenStore(storeName)
+	if err != nil {
+		return nil, fmt.Errorf("open linkset signature store: %w", err)
+	}
+
+	return &Signer{
+		store:        store,
+		km:           km,
+		cr:           cr,
+		publicKeyIRI: publicKeyIRI,
+		keyID:        keyID,
+	}, nil
+}
+
+// SetKeyID sets the ID of the KMS key that is used for signing. This function is called when the HTTP
+// signature key is rotated.
+func (s *Signer) SetKeyID(keyID string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.keyID = keyID
+	s.kid = ""
+}
+
+// Sign signs the given content, which was written to the CAS at the given hash link, and stores the signature.
+func (s *Signer) Sign(hl string, content []byte) error {
+	resourceHash, err := hashlink.GetResourceHashFromHashLink(hl)
+	if err != nil {
+		return fmt.Errorf("get resource hash from [%s]: %w", hl, err)
+	}
+
+	jws, err := s.sign(content)
+	if err != nil {
+		return fmt.Errorf("sign linkset [%s]: %w", resourceHash, err)
+	}
+
+	err = s.store.Put(resourceHash, []byte(jws))
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("store signature of linkset [%s]: %w", resourceHash, err))
+	}
+
+	logger.Debugf("Signed linkset [%s]: %s", resourceHash, jws)
+
+	return nil
+}
+
+// Get returns the detached JWS of the content with the given resource hash. An empty string is returned if the
+// content wasn't signed.
+func (s *Signer) Get(resourceHash string) (string, error) {
+	jws, err := s.store.Get(resourceHash)
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return "", nil
+		}
+
+		return "", orberrors.NewTransient(fmt.Errorf("get signature of linkset [%s]: %w", resourceHash, err))
+	}
+
+	return string(jws), nil
+}
+
+// PublicKey returns the raw Ed25519 public key with the given IRI (i.e. the key ID of a JWS created by this
+// signer). orberrors.ErrContentNotFound is returned if the key was never used to sign a linkset.
+func (s *Signer) PublicKey(keyIRI *url.URL) ([]byte, error) {
+	ref := strings.TrimPrefix(keyIRI.String(), s.publicKeyIRI.String()+"-")
+	if ref == keyIRI.String() || ref == "" {
+		return nil, orberrors.ErrContentNotFound
+	}
+
+	pubKey, err := s.store.Get(publicKeyPrefix + ref)
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return nil, orberrors.ErrContentNotFound
+		}
+
+		return nil, orberrors.NewTransient(fmt.Errorf("get public key [%s]: %w", keyIRI, err))
+	}
+
+	return pubKey, nil
+}
+
+func (s *Signer) sign(content []byte) (string, error) {
+	keyID, kid, err := s.signingKey()
+	if err != nil {
+		return "", err
+	}
+
+	headerBytes, err := json.Marshal(&jwsHeader{Alg: algEdDSA, Kid: kid})
+	if err != nil {
+		return "", fmt.Errorf("marshal JWS header: %w", err)
+	}
+
+	encodedHeader := base64.RawURLEncoding.EncodeToString(headerBytes)
+
+	kh, err := s.km.Get(keyID)
+	if err != nil {
+		return "", fmt.Errorf("get key handle [%s]: %w", keyID, err)
+	}
+
+	signature, err := s.cr.Sign(signingInput(encodedHeader, content), kh)
+	if err != nil {
+		return "", fmt.Errorf("sign: %w", err)
+	}
+
+	// The payload is detached, i.e. it's omitted from the compact serialization.
+	return encodedHeader + ".." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// signingKey returns the current KMS key ID along with the corresponding JWS key ID. The public key is persisted
+// the first time that a key is used so that it may be resolved after the key is rotated.
+func (s *Signer) signingKey() (string, string, error) {
+	s.mutex.RLock()
+	keyID, kid := s.keyID, s.kid
+	s.mutex.RUnlock()
+
+	if kid != "" {
+		return keyID, kid, nil
+	}
+
+	pubKey, err := s.km.ExportPubKeyBytes(keyID)
+	if err != nil {
+		return "", "", fmt.Errorf("export public key [%s]: %w", keyID, err)
+	}
+
+	hash := sha256.Sum256(pubKey)
+	ref := base64.RawURLEncoding.EncodeToString(hash[:keyRefLength])
+
+	err = s.store.Put(publicKeyPrefix+ref, pubKey)
+	if err != nil {
+		return "", "", orberrors.NewTransient(fmt.Errorf("store public key [%s]: %w", keyID, err))
+	}
+
+	kid = s.publicKeyIRI.String() + "-" + ref
+
+	s.mutex.Lock()
+	if s.keyID == keyID {
+		s.kid = kid
+	}
+	s.mutex.Unlock()
+
+	logger.Debugf("Using key ID [%s] for KMS key [%s]", kid, keyID)
+
+	return keyID, kid, nil
+}
+
+// Verifier verifies the detached JWS of the anchor linksets that are retrieved from a remote CAS (WebCAS or IPFS).
+type Verifier struct {
+	retriever publicKeyRetriever
+}
+
+// NewVerifier returns a new linkset signature verifier which retrieves the public keys using the given retriever.
+func NewVerifier(retriever publicKeyRetriever) *Verifier {
+	return &Verifier{retriever: retriever}
+}
+
+// Verify verifies the given detached JWS of the given anchor linkset. The signature must have been created with
+// a key that's published by the anchor origin of the linkset (i.e. the service that created the linkset),
+// regardless of where the content was retrieved from, so that a CAS replica is unable to re-sign tampered content
+// with its own key. ErrInvalidSignature is returned if the signature is invalid.
+func (v *Verifier) Verify(content []byte, jws string) error {
+	anchorOrigin, err := AnchorOrigin(content)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidSignature, err)
+	}
+
+	parts := strings.Split(strings.TrimSpace(jws), ".")
+	if len(parts) != jwsParts || parts[1] != "" {
+		return fmt.Errorf("%w: not a detached compact JWS", ErrInvalidSignature)
+	}
+
+	keyIRI, err := parseHeader(parts[0])
+	if err != nil {
+		return err
+	}
+
+	// The keys of a service are published under the service IRI.
+	if !strings.HasPrefix(keyIRI.String(), strings.TrimSuffix(anchorOrigin.String(), "/")+"/") {
+		return fmt.Errorf("%w: key [%s] isn't published by anchor origin [%s]", ErrInvalidSignature, keyIRI,
+			anchorOrigin)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return fmt.Errorf("%w: decode signature: %s", ErrInvalidSignature, err)
+	}
+
+	input := signingInput(parts[0], content)
+
+	ok, err := v.verify(anchorOrigin, keyIRI, input, signature)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		// The cached key may be stale (for example, signatures created by older versions reference the service's
+		// main key, which may have been rotated), so invalidate the cached key and try again.
+		v.retriever.InvalidatePublicKey(keyIRI)
+
+		ok, err = v.verify(anchorOrigin, keyIRI, input, signature)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			return fmt.Errorf("%w: linkset from anchor origin [%s]", ErrInvalidSignature, anchorOrigin)
+		}
+	}
+
+	return nil
+}
+
+func (v *Verifier) verify(anchorOrigin, keyIRI *url.URL, input, signature []byte) (bool, error) {
+	publicKey, err := v.retriever.GetPublicKey(keyIRI)
+	if err != nil {
+		return false, orberrors.NewTransient(fmt.Errorf("get public key [%s]: %w", keyIRI, err))
+	}
+
+	if publicKey.Owner == nil || publicKey.Owner.String() != anchorOrigin.String() {
+		return false, fmt.Errorf("%w: key [%s] isn't owned by anchor origin [%s]", ErrInvalidSignature, keyIRI,
+			anchorOrigin)
+	}
+
+	pubKey, err := multikey.Ed25519PublicKey(publicKey)
+	if err != nil {
+		return false, fmt.Errorf("public key [%s]: %w", keyIRI, err)
+	}
+
+	return ed25519.Verify(pubKey, input, signature), nil
+}
+
+// AnchorOrigin returns the anchor origin of the given anchor linkset, i.e. the service that created (and signed)
+// the linkset. The content is either an anchor event or a (JSON or native) linkset. ErrNotLinkset is returned if
+// the content isn't an anchor linkset.
+func AnchorOrigin(content []byte) (*url.URL, error) {
+	anchorEvent := &vocab.AnchorEventType{}
+
+	if err := json.Unmarshal(content, anchorEvent); err == nil && anchorEvent.Type().Is(vocab.TypeAnchorEvent) {
+		if anchorEvent.AttributedTo() == nil {
+			return nil, errors.New("anchor event has no anchor origin")
+		}
+
+		return anchorEvent.AttributedTo().URL(), nil
+	}
+
+	ls := &linkset.Linkset{}
+
+	if err := json.Unmarshal(content, ls); err != nil || len(ls.Linkset) == 0 {
+		ls, err = linkset.ParseNative(content)
+		if err != nil || len(ls.Linkset) == 0 {
+			return nil, ErrNotLinkset
+		}
+	}
+
+	authors := ls.Linkset[0].Targets(linkset.RelationAuthor)
+	if len(authors) == 0 {
+		return nil, errors.New("linkset has no author")
+	}
+
+	anchorOrigin, err := url.Parse(authors[0].Href)
+	if err != nil || !anchorOrigin.IsAbs() {
+		return nil, fmt.Errorf("invalid linkset author [%s]", authors[0].Href)
+	}
+
+	return anchorOrigin, nil
+}
+
+func parseHeader(encodedHeader string) (*url.URL, error) {
+	headerBytes, err := base64.RawURLEncoding.DecodeString(encodedHeader)
+	if err != nil {
+		return nil, fmt.Errorf("%w: decode JWS header: %s", ErrInvalidSignature, err)
+	}
+
+	header := &jwsHeader{}
+
+	err = json.Unmarshal(headerBytes, header)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unmarshal JWS header: %s", ErrInvalidSignature, err)
+	}
+
+	if header.Alg != algEdDSA {
+		return nil, fmt.Errorf("%w: unsupported algorithm [%s]", ErrInvalidSignature, header.Alg)
+	}
+
+	keyIRI, err := url.Parse(header.Kid)
+	if err != nil || header.Kid == "" {
+		return nil, fmt.Errorf("%w: invalid key ID [%s]", ErrInvalidSignature, header.Kid)
+	}
+
+	return keyIRI, nil
+}
+
+// signingInput returns the JWS signing input, i.e. ASCII(BASE64URL(header) || '.' || BASE64URL(payload)).
+func signingInput(encodedHeader string, payload []byte) []byte {
+	return []byte(encodedHeader + "." + base64.RawURLEncoding.EncodeToString(payload))
+}
@@ -0,0 +1,432 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package linksetsig
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/multikey"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+	"github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+const (
+	key1 = "key1"
+	key2 = "key2"
+
+	hl1 = "hl:uEiDzUEQi2qRreCTfvp2AKmTaxuqUUZZNhbxe5RTBH59AWw"
+	rh1 = "uEiDzUEQi2qRreCTfvp2AKmTaxuqUUZZNhbxe5RTBH59AWw"
+
+	serviceIRI = "https://orb.domain1.com/services/orb"
+
+	content = `{"attributedTo":"https://orb.domain1.com/services/orb","type":"AnchorEvent",` +
+		`"url":"hl:uEiDzUEQi2qRreCTfvp2AKmTaxuqUUZZNhbxe5RTBH59AWw"}`
+)
+
+var publicKeyIRI = testutil.MustParseURL(serviceIRI + "/keys/main-key")
+
+func TestSigner(t *testing.T) {
+	km := newMockKeyManager(t, key1, key2)
+
+	t.Run("Success", func(t *testing.T) {
+		s, err := NewSigner(mem.NewProvider(), km, km, key1, publicKeyIRI)
+		require.NoError(t, err)
+
+		jws, err := s.Get(rh1)
+		require.NoError(t, err)
+		require.Empty(t, jws)
+
+		require.NoError(t, s.Sign(hl1, []byte(content)))
+
+		jws, err = s.Get(rh1)
+		require.NoError(t, err)
+		require.NotEmpty(t, jws)
+
+		parts := strings.Split(jws, ".")
+		require.Len(t, parts, 3)
+		require.Empty(t, parts[1])
+
+		kid := getKID(t, jws)
+		require.True(t, strings.HasPrefix(kid.String(), publicKeyIRI.String()+"-"))
+
+		pubKey, err := s.PublicKey(kid)
+		require.NoError(t, err)
+		require.Equal(t, []byte(km.pubKey(key1)), pubKey)
+
+		v := NewVerifier(&mockKeyRetriever{keys: map[string]ed25519.PublicKey{kid.String(): km.pubKey(key1)}})
+
+		require.NoError(t, v.Verify([]byte(content), jws))
+	})
+
+	t.Run("Key rotated", func(t *testing.T) {
+		s, err := NewSigner(mem.NewProvider(), km, km, key1, publicKeyIRI)
+		require.NoError(t, err)
+
+		require.NoError(t, s.Sign(hl1, []byte(content)))
+
+		jws1, err := s.Get(rh1)
+		require.NoError(t, err)
+
+		s.SetKeyID(key2)
+
+		require.NoError(t, s.Sign(hl1, []byte(content)))
+
+		jws2, err := s.Get(rh1)
+		require.NoError(t, err)
+		require.NotEqual(t, getKID(t, jws1), getKID(t, jws2))
+
+		// Signatures created with the previous key must still be verifiable after the key is rotated.
+		v := NewVerifier(&signerKeyRetriever{signer: s})
+
+		require.NoError(t, v.Verify([]byte(content), jws1))
+		require.NoError(t, v.Verify([]byte(content), jws2))
+	})
+
+	t.Run("Public key not found", func(t *testing.T) {
+		s, err := NewSigner(mem.NewProvider(), km, km, key1, publicKeyIRI)
+		require.NoError(t, err)
+
+		for _, keyIRI := range []string{
+			publicKeyIRI.String(),
+			publicKeyIRI.String() + "-",
+			publicKeyIRI.String() + "-unknown",
+			"https://orb.domain1.com/services/orb/keys/other-key",
+		} {
+			_, err = s.PublicKey(testutil.MustParseURL(keyIRI))
+			require.ErrorIs(t, err, orberrors.ErrContentNotFound, keyIRI)
+		}
+	})
+
+	t.Run("Open store error", func(t *testing.T) {
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(nil, errors.New("injected open error"))
+
+		_, err := NewSigner(provider, km, km, key1, publicKeyIRI)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected open error")
+	})
+
+	t.Run("Invalid hash link", func(t *testing.T) {
+		s, err := NewSigner(mem.NewProvider(), km, km, key1, publicKeyIRI)
+		require.NoError(t, err)
+
+		err = s.Sign("invalid", []byte(content))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get resource hash")
+	})
+
+	t.Run("Key not found", func(t *testing.T) {
+		s, err := NewSigner(mem.NewProvider(), km, km, "key3", publicKeyIRI)
+		require.NoError(t, err)
+
+		err = s.Sign(hl1, []byte(content))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "export public key [key3]")
+	})
+
+	t.Run("Key handle not found", func(t *testing.T) {
+		s, err := NewSigner(mem.NewProvider(), &mockKeyManager{keys: km.keys, missingHandle: true}, km, key1,
+			publicKeyIRI)
+		require.NoError(t, err)
+
+		err = s.Sign(hl1, []byte(content))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get key handle [key1]")
+	})
+
+	t.Run("Store errors", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.PutReturns(errors.New("injected put error"))
+		store.GetReturns(nil, errors.New("injected get error"))
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := NewSigner(provider, km, km, key1, publicKeyIRI)
+		require.NoError(t, err)
+
+		err = s.Sign(hl1, []byte(content))
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "injected put error")
+
+		_, err = s.Get(rh1)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "injected get error")
+
+		_, err = s.PublicKey(testutil.MustParseURL(publicKeyIRI.String() + "-ref"))
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "injected get error")
+	})
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	km := newMockKeyManager(t, key1, key2)
+
+	s, err := NewSigner(mem.NewProvider(), km, km, key1, publicKeyIRI)
+	require.NoError(t, err)
+
+	require.NoError(t, s.Sign(hl1, []byte(content)))
+
+	jws, err := s.Get(rh1)
+	require.NoError(t, err)
+
+	kid := getKID(t, jws).String()
+
+	t.Run("Cached key is stale", func(t *testing.T) {
+		retriever := &mockKeyRetriever{keys: map[string]ed25519.PublicKey{kid: km.pubKey(key2)}}
+		retriever.onInvalidate = func() {
+			retriever.keys[kid] = km.pubKey(key1)
+		}
+
+		require.NoError(t, NewVerifier(retriever).Verify([]byte(content), jws))
+		require.Equal(t, 1, retriever.invalidated)
+	})
+
+	t.Run("Tampered content", func(t *testing.T) {
+		retriever := &mockKeyRetriever{keys: map[string]ed25519.PublicKey{kid: km.pubKey(key1)}}
+
+		err := NewVerifier(retriever).Verify([]byte(strings.Replace(content, "hl:uEiD", "hl:uEiE", 1)), jws)
+		require.ErrorIs(t, err, ErrInvalidSignature)
+		require.Equal(t, 1, retriever.invalidated)
+	})
+
+	t.Run("Key isn't published by anchor origin", func(t *testing.T) {
+		retriever := &mockKeyRetriever{keys: map[string]ed25519.PublicKey{kid: km.pubKey(key1)}}
+
+		// A replica re-signs (tampered) content that was created by another anchor origin with its own key.
+		otherContent := strings.ReplaceAll(content, "orb.domain1.com", "orb.domain2.com")
+
+		err := NewVerifier(retriever).Verify([]byte(otherContent), jws)
+		require.ErrorIs(t, err, ErrInvalidSignature)
+		require.Contains(t, err.Error(), "isn't published by anchor origin [https://orb.domain2.com/services/orb]")
+	})
+
+	t.Run("Key isn't owned by anchor origin", func(t *testing.T) {
+		retriever := &mockKeyRetriever{
+			keys:  map[string]ed25519.PublicKey{kid: km.pubKey(key1)},
+			owner: testutil.MustParseURL("https://orb.domain1.com/services/other"),
+		}
+
+		err := NewVerifier(retriever).Verify([]byte(content), jws)
+		require.ErrorIs(t, err, ErrInvalidSignature)
+		require.Contains(t, err.Error(), "isn't owned by anchor origin")
+	})
+
+	t.Run("Content isn't an anchor linkset", func(t *testing.T) {
+		retriever := &mockKeyRetriever{keys: map[string]ed25519.PublicKey{kid: km.pubKey(key1)}}
+
+		err := NewVerifier(retriever).Verify([]byte(`{"operations":{}}`), jws)
+		require.ErrorIs(t, err, ErrInvalidSignature)
+		require.Contains(t, err.Error(), ErrNotLinkset.Error())
+	})
+
+	t.Run("Public key not found", func(t *testing.T) {
+		err := NewVerifier(&mockKeyRetriever{}).Verify([]byte(content), jws)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "get public key")
+	})
+
+	t.Run("Unsupported public key", func(t *testing.T) {
+		retriever := &mockKeyRetriever{keys: map[string]ed25519.PublicKey{kid: nil}}
+
+		err := NewVerifier(retriever).Verify([]byte(content), jws)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "public key ["+kid+"]")
+	})
+
+	t.Run("Invalid JWS", func(t *testing.T) {
+		v := NewVerifier(&mockKeyRetriever{})
+
+		encode := func(header string) string {
+			return base64.RawURLEncoding.EncodeToString([]byte(header))
+		}
+
+		for _, invalid := range []string{
+			"xxx",
+			encode(`{"alg":"EdDSA"}`) + ".cGF5bG9hZA.c2ln",
+			"&&&..c2ln",
+			encode(`{`) + "..c2ln",
+			encode(`{"alg":"ES256","kid":"https://orb.domain1.com/keys/k1"}`) + "..c2ln",
+			encode(`{"alg":"EdDSA"}`) + "..c2ln",
+			encode(`{"alg":"EdDSA","kid":"https://orb.domain1.com/keys/k1"}`) + "..&&&",
+		} {
+			require.ErrorIs(t, v.Verify([]byte(content), invalid), ErrInvalidSignature, invalid)
+		}
+	})
+}
+
+func TestAnchorOrigin(t *testing.T) {
+	const anchor = "hl:uEiDzUEQi2qRreCTfvp2AKmTaxuqUUZZNhbxe5RTBH59AWw"
+
+	t.Run("Anchor event", func(t *testing.T) {
+		anchorOrigin, err := AnchorOrigin([]byte(content))
+		require.NoError(t, err)
+		require.Equal(t, serviceIRI, anchorOrigin.String())
+	})
+
+	t.Run("JSON linkset", func(t *testing.T) {
+		anchorOrigin, err := AnchorOrigin([]byte(
+			`{"linkset":[{"anchor":"` + anchor + `","author":[{"href":"` + serviceIRI + `"}]}]}`))
+		require.NoError(t, err)
+		require.Equal(t, serviceIRI, anchorOrigin.String())
+	})
+
+	t.Run("Native linkset", func(t *testing.T) {
+		anchorOrigin, err := AnchorOrigin([]byte(`<` + serviceIRI + `>; rel="author"; anchor="` + anchor + `"`))
+		require.NoError(t, err)
+		require.Equal(t, serviceIRI, anchorOrigin.String())
+	})
+
+	t.Run("Not a linkset", func(t *testing.T) {
+		for _, c := range []string{`{"operations":{}}`, `{"type":"Create"}`, "xxx", ""} {
+			_, err := AnchorOrigin([]byte(c))
+			require.ErrorIs(t, err, ErrNotLinkset, c)
+		}
+	})
+
+	t.Run("No anchor origin", func(t *testing.T) {
+		_, err := AnchorOrigin([]byte(`{"type":"AnchorEvent","url":"` + anchor + `"}`))
+		require.EqualError(t, err, "anchor event has no anchor origin")
+
+		_, err = AnchorOrigin([]byte(`{"linkset":[{"anchor":"` + anchor + `","profile":[{"href":"x"}]}]}`))
+		require.EqualError(t, err, "linkset has no author")
+
+		_, err = AnchorOrigin([]byte(`{"linkset":[{"anchor":"` + anchor + `","author":[{"href":"orb"}]}]}`))
+		require.EqualError(t, err, "invalid linkset author [orb]")
+	})
+}
+
+func getKID(t *testing.T, jws string) *url.URL {
+	t.Helper()
+
+	kid, err := parseHeader(strings.Split(jws, ".")[0])
+	require.NoError(t, err)
+
+	return kid
+}
+
+type mockKeyManager struct {
+	keys          map[string]ed25519.PrivateKey
+	missingHandle bool
+}
+
+func newMockKeyManager(t *testing.T, keyIDs ...string) *mockKeyManager {
+	t.Helper()
+
+	km := &mockKeyManager{keys: make(map[string]ed25519.PrivateKey)}
+
+	for _, keyID := range keyIDs {
+		_, privKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		km.keys[keyID] = privKey
+	}
+
+	return km
+}
+
+func (m *mockKeyManager) Get(keyID string) (interface{}, error) {
+	privKey, ok := m.keys[keyID]
+	if !ok || m.missingHandle {
+		return nil, errors.New("key not found")
+	}
+
+	return privKey, nil
+}
+
+func (m *mockKeyManager) ExportPubKeyBytes(keyID string) ([]byte, error) {
+	privKey, ok := m.keys[keyID]
+	if !ok {
+		return nil, errors.New("key not found")
+	}
+
+	return privKey.Public().(ed25519.PublicKey), nil
+}
+
+func (m *mockKeyManager) Sign(msg []byte, kh interface{}) ([]byte, error) {
+	return ed25519.Sign(kh.(ed25519.PrivateKey), msg), nil
+}
+
+func (m *mockKeyManager) pubKey(keyID string) ed25519.PublicKey {
+	return m.keys[keyID].Public().(ed25519.PublicKey)
+}
+
+type mockKeyRetriever struct {
+	keys         map[string]ed25519.PublicKey
+	owner        *url.URL
+	invalidated  int
+	onInvalidate func()
+}
+
+func (m *mockKeyRetriever) GetPublicKey(keyIRI *url.URL) (*vocab.PublicKeyType, error) {
+	pubKey, ok := m.keys[keyIRI.String()]
+	if !ok {
+		return nil, errors.New("public key not found")
+	}
+
+	owner := m.owner
+	if owner == nil {
+		owner = testutil.MustParseURL(serviceIRI)
+	}
+
+	if pubKey == nil {
+		return vocab.NewPublicKey(vocab.WithID(keyIRI), vocab.WithOwner(owner)), nil
+	}
+
+	value, err := multikey.EncodeEd25519(pubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return vocab.NewPublicKey(vocab.WithID(keyIRI), vocab.WithOwner(owner), vocab.WithPublicKeyMultibase(value)), nil
+}
+
+func (m *mockKeyRetriever) InvalidatePublicKey(*url.URL) {
+	m.invalidated++
+
+	if m.onInvalidate != nil {
+		m.onInvalidate()
+	}
+}
+
+// signerKeyRetriever resolves the public keys from the signer, similar to a remote server that
+// retrieves the keys from the public keys endpoint.
+type signerKeyRetriever struct {
+	signer *Signer
+}
+
+func (m *signerKeyRetriever) GetPublicKey(keyIRI *url.URL) (*vocab.PublicKeyType, error) {
+	pubKey, err := m.signer.PublicKey(keyIRI)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := multikey.EncodeEd25519(pubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return vocab.NewPublicKey(vocab.WithID(keyIRI), vocab.WithOwner(testutil.MustParseURL(serviceIRI)),
+		vocab.WithPublicKeyMultibase(value)), nil
+}
+
+func (m *signerKeyRetriever) InvalidatePublicKey(*url.URL) {}
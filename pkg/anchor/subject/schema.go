@@ -0,0 +1,66 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package subject
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// ErrInvalidCredentialSubject is returned if the credential subject of an anchor credential doesn't conform to the
+// Orb anchor credential subject schema.
+var ErrInvalidCredentialSubject = errors.New("invalid anchor credential subject")
+
+const credentialSubjectSchemaFile = "schema/credentialsubject.json"
+
+// nolint: gochecknoglobals
+var (
+	//go:embed schema/*.json
+	fs embed.FS
+
+	credentialSubjectSchema = mustLoadSchema(credentialSubjectSchemaFile)
+)
+
+// ValidateCredentialSubject validates the credentialSubject of the given anchor credential (in JSON format) against
+// the Orb anchor credential subject schema. If the subject is invalid then an error (which wraps
+// ErrInvalidCredentialSubject) is returned containing the details of each violation.
+func ValidateCredentialSubject(vcBytes []byte) error {
+	result, err := credentialSubjectSchema.Validate(gojsonschema.NewBytesLoader(vcBytes))
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidCredentialSubject, err)
+	}
+
+	if result.Valid() {
+		return nil
+	}
+
+	violations := make([]string, len(result.Errors()))
+
+	for i, e := range result.Errors() {
+		violations[i] = e.String()
+	}
+
+	return fmt.Errorf("%w: %s", ErrInvalidCredentialSubject, strings.Join(violations, "; "))
+}
+
+func mustLoadSchema(file string) *gojsonschema.Schema {
+	schemaBytes, err := fs.ReadFile(file)
+	if err != nil {
+		panic(fmt.Errorf("read schema file [%s]: %w", file, err))
+	}
+
+	schema, err := gojsonschema.NewSchema(gojsonschema.NewBytesLoader(schemaBytes))
+	if err != nil {
+		panic(fmt.Errorf("load schema [%s]: %w", file, err))
+	}
+
+	return schema
+}
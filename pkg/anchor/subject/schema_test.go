@@ -0,0 +1,71 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package subject
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateCredentialSubject(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		require.NoError(t, ValidateCredentialSubject([]byte(
+			`{"credentialSubject":{"id":"hl:uEiBy8pPgN9eS3hpQAwpSwJJvm6Awpsnc8kR_fkbUPotehg"}}`,
+		)))
+
+		require.NoError(t, ValidateCredentialSubject([]byte(
+			`{"credentialSubject":{"id":"hl:uEiBN4vd1lgKx_K93ltpdI32T6nIGlwXhJcSwbeVAg8NMxg:uoQ-BeEJpcGZz"}}`,
+		)))
+
+		// A subject that only has an ID is marshalled as a string.
+		require.NoError(t, ValidateCredentialSubject([]byte(
+			`{"credentialSubject":"hl:uEiBy8pPgN9eS3hpQAwpSwJJvm6Awpsnc8kR_fkbUPotehg"}`,
+		)))
+	})
+
+	t.Run("Missing credential subject", func(t *testing.T) {
+		err := ValidateCredentialSubject([]byte(`{"issuer":"https://orb.domain1.com"}`))
+		require.ErrorIs(t, err, ErrInvalidCredentialSubject)
+		require.Contains(t, err.Error(), "credentialSubject is required")
+	})
+
+	t.Run("Invalid credential subject type", func(t *testing.T) {
+		err := ValidateCredentialSubject([]byte(`{"credentialSubject":10}`))
+		require.ErrorIs(t, err, ErrInvalidCredentialSubject)
+		require.Contains(t, err.Error(), "credentialSubject: Invalid type")
+	})
+
+	t.Run("Missing ID", func(t *testing.T) {
+		err := ValidateCredentialSubject([]byte(`{"credentialSubject":{}}`))
+		require.ErrorIs(t, err, ErrInvalidCredentialSubject)
+		require.Contains(t, err.Error(), "credentialSubject: id is required")
+	})
+
+	t.Run("Invalid ID", func(t *testing.T) {
+		err := ValidateCredentialSubject([]byte(`{"credentialSubject":{"id":"https://orb.domain1.com/cas/xxx"}}`))
+		require.ErrorIs(t, err, ErrInvalidCredentialSubject)
+		require.Contains(t, err.Error(), "credentialSubject.id: Does not match pattern")
+
+		err = ValidateCredentialSubject([]byte(`{"credentialSubject":{"id":""}}`))
+		require.ErrorIs(t, err, ErrInvalidCredentialSubject)
+		require.Contains(t, err.Error(), "credentialSubject.id: Does not match pattern")
+
+		err = ValidateCredentialSubject([]byte(`{"credentialSubject":"https://orb.domain1.com/cas/xxx"}`))
+		require.ErrorIs(t, err, ErrInvalidCredentialSubject)
+		require.Contains(t, err.Error(), "credentialSubject: Does not match pattern")
+
+		err = ValidateCredentialSubject([]byte(`{"credentialSubject":{"id":10}}`))
+		require.ErrorIs(t, err, ErrInvalidCredentialSubject)
+		require.Contains(t, err.Error(), "credentialSubject.id: Invalid type")
+	})
+
+	t.Run("Invalid JSON", func(t *testing.T) {
+		err := ValidateCredentialSubject([]byte(`{`))
+		require.ErrorIs(t, err, ErrInvalidCredentialSubject)
+	})
+}
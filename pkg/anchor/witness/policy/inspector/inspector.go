@@ -13,6 +13,7 @@ import (
 
 	"github.com/trustbloc/edge-core/pkg/log"
 
+	"github.com/trustbloc/orb/pkg/activitypub/activitytemplate"
 	"github.com/trustbloc/orb/pkg/activitypub/vocab"
 	"github.com/trustbloc/orb/pkg/anchor/witness/proof"
 )
@@ -92,18 +93,7 @@ func (c *Inspector) CheckPolicy(anchorID string) error {
 func (c *Inspector) postOfferActivity(anchorEvent *vocab.AnchorEventType, witnessesIRI []*url.URL) error {
 	logger.Debugf("sending anchor event[%s] to additional witnesses: %s", anchorEvent.Index(), witnessesIRI)
 
-	startTime := time.Now()
-	endTime := startTime.Add(c.maxWitnessDelay)
-
-	offer := vocab.NewOfferActivity(
-		vocab.NewObjectProperty(
-			vocab.WithAnchorEvent(anchorEvent),
-		),
-		vocab.WithTo(witnessesIRI...),
-		vocab.WithStartTime(&startTime),
-		vocab.WithEndTime(&endTime),
-		vocab.WithTarget(vocab.NewObjectProperty(vocab.WithIRI(vocab.AnchorWitnessTargetIRI))),
-	)
+	offer := activitytemplate.NewWitnessOffer(anchorEvent, witnessesIRI, c.maxWitnessDelay)
 
 	postID, err := c.Outbox().Post(offer)
 	if err != nil {
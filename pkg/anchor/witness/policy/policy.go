@@ -81,41 +81,61 @@ func (wp *WitnessPolicy) Evaluate(witnesses []*proof.WitnessProof) (bool, error)
 		return false, err
 	}
 
-	totalSystemWitnesses := 0
-	collectedSystemWitnesses := 0
+	result := EvaluateConfig(cfg, witnesses)
 
-	totalBatchWitnesses := 0
-	collectedBatchWitnesses := 0
+	logger.Debugf("witness policy[%s] evaluated to[%t] with batch[%t] and system[%t] for witnesses: %s",
+		cfg, result.Satisfied, result.Batch.Satisfied, result.System.Satisfied, witnesses)
+
+	return result.Satisfied, nil
+}
+
+// Evaluation contains the result of evaluating a witness policy.
+type Evaluation struct {
+	Satisfied bool  `json:"satisfied"`
+	Batch     Tally `json:"batch"`
+	System    Tally `json:"system"`
+}
+
+// Tally contains the number of witnesses of a given type that were considered by the policy and
+// the number of those witnesses that provided a proof.
+type Tally struct {
+	Total     int  `json:"total"`
+	Collected int  `json:"collected"`
+	Satisfied bool `json:"satisfied"`
+}
+
+// EvaluateConfig evaluates the given witness policy configuration against the provided witnesses.
+func EvaluateConfig(cfg *config.WitnessPolicyConfig, witnesses []*proof.WitnessProof) *Evaluation {
+	result := &Evaluation{}
 
 	for _, w := range witnesses {
 		logOK := checkLog(cfg.LogRequired, w.HasLog)
 
 		switch w.Type {
 		case proof.WitnessTypeBatch:
-			totalBatchWitnesses++
+			result.Batch.Total++
 
 			if logOK && w.Proof != nil {
-				collectedBatchWitnesses++
+				result.Batch.Collected++
 			}
 
 		case proof.WitnessTypeSystem:
-			totalSystemWitnesses++
+			result.System.Total++
 
 			if logOK && w.Proof != nil {
-				collectedSystemWitnesses++
+				result.System.Collected++
 			}
 		}
 	}
 
-	batchCondition := evaluate(collectedBatchWitnesses, totalBatchWitnesses, cfg.MinNumberBatch, cfg.MinPercentBatch)
-	systemCondition := evaluate(collectedSystemWitnesses, totalSystemWitnesses, cfg.MinNumberSystem, cfg.MinPercentSystem)
+	result.Batch.Satisfied = evaluate(result.Batch.Collected, result.Batch.Total, cfg.MinNumberBatch,
+		cfg.MinPercentBatch)
+	result.System.Satisfied = evaluate(result.System.Collected, result.System.Total, cfg.MinNumberSystem,
+		cfg.MinPercentSystem)
 
-	evaluated := cfg.OperatorFnc(batchCondition, systemCondition)
+	result.Satisfied = cfg.OperatorFnc(result.Batch.Satisfied, result.System.Satisfied)
 
-	logger.Debugf("witness policy[%s] evaluated to[%t] with batch[%t] and system[%t] for witnesses: %s",
-		cfg, evaluated, batchCondition, systemCondition, witnesses)
-
-	return evaluated, nil
+	return result
 }
 
 func (wp *WitnessPolicy) loadWitnessPolicy(key interface{}) (interface{}, *time.Duration, error) {
@@ -15,6 +15,7 @@ import (
 	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
 	"github.com/stretchr/testify/require"
 
+	"github.com/trustbloc/orb/pkg/anchor/witness/policy/config"
 	"github.com/trustbloc/orb/pkg/anchor/witness/proof"
 	storemocks "github.com/trustbloc/orb/pkg/store/mocks"
 )
@@ -697,6 +698,30 @@ func TestEvaluate(t *testing.T) {
 	})
 }
 
+func TestEvaluateConfig(t *testing.T) {
+	cfg, err := config.Parse("OutOf(1,batch) AND MinPercent(50,system) LogRequired")
+	require.NoError(t, err)
+
+	witnessProofs := []*proof.WitnessProof{
+		{Type: proof.WitnessTypeBatch, HasLog: true, Proof: []byte("proof")},
+		{Type: proof.WitnessTypeBatch, HasLog: true},
+		{Type: proof.WitnessTypeSystem, HasLog: true, Proof: []byte("proof")},
+		{Type: proof.WitnessTypeSystem, Proof: []byte("proof")},
+		{Type: proof.WitnessTypeSystem, HasLog: true},
+	}
+
+	result := EvaluateConfig(cfg, witnessProofs)
+	require.False(t, result.Satisfied)
+	require.Equal(t, Tally{Total: 2, Collected: 1, Satisfied: true}, result.Batch)
+	require.Equal(t, Tally{Total: 3, Collected: 1, Satisfied: false}, result.System)
+
+	witnessProofs[4].Proof = []byte("proof")
+
+	result = EvaluateConfig(cfg, witnessProofs)
+	require.True(t, result.Satisfied)
+	require.Equal(t, Tally{Total: 3, Collected: 2, Satisfied: true}, result.System)
+}
+
 func TestGetWitnessPolicyConfig(t *testing.T) {
 	t.Run("success - policy config retrieved from the cache", func(t *testing.T) {
 		configStore, err := mem.NewProvider().OpenStore(configStoreName)
@@ -0,0 +1,208 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+
+	"github.com/trustbloc/orb/pkg/anchor/witness/policy"
+	"github.com/trustbloc/orb/pkg/anchor/witness/policy/config"
+	"github.com/trustbloc/orb/pkg/anchor/witness/proof"
+)
+
+const simulateEndpoint = endpoint + "/simulate"
+
+// SimulationRequest contains a hypothetical set of witnesses, along with whether or not each witness
+// responded with a proof, that are evaluated against a witness policy.
+type SimulationRequest struct {
+	// Policy (optional) is the witness policy to evaluate. If not set then the current policy is used.
+	Policy    string              `json:"policy,omitempty"`
+	Witnesses []*SimulatedWitness `json:"witnesses"`
+}
+
+// SimulatedWitness is a hypothetical witness.
+type SimulatedWitness struct {
+	URI    string            `json:"uri,omitempty"`
+	Type   proof.WitnessType `json:"type"`
+	HasLog bool              `json:"hasLog,omitempty"`
+	// Proof indicates whether or not the witness responded with a proof.
+	Proof bool `json:"proof"`
+}
+
+// SimulationResponse contains the policy that was evaluated and the result of the evaluation.
+type SimulationResponse struct {
+	Policy string `json:"policy"`
+	policy.Evaluation
+}
+
+// PolicySimulator evaluates a witness policy against a hypothetical set of witnesses so that policy
+// changes may be validated before they're applied. Nothing is stored.
+type PolicySimulator struct {
+	configStore storage.Store
+	marshal     func(interface{}) ([]byte, error)
+}
+
+// NewSimulator returns a new PolicySimulator.
+func NewSimulator(cfgStore storage.Store) *PolicySimulator {
+	return &PolicySimulator{
+		configStore: cfgStore,
+		marshal:     json.Marshal,
+	}
+}
+
+// Path returns the HTTP REST endpoint for the PolicySimulator service.
+func (ps *PolicySimulator) Path() string {
+	return simulateEndpoint
+}
+
+// Method returns the HTTP REST method for the simulate policy service.
+func (ps *PolicySimulator) Method() string {
+	return http.MethodPost
+}
+
+// Handler returns the HTTP REST handle for the PolicySimulator service.
+func (ps *PolicySimulator) Handler() common.HTTPRequestHandler {
+	return ps.handle
+}
+
+func (ps *PolicySimulator) handle(w http.ResponseWriter, req *http.Request) {
+	request, witnessProofs, err := readSimulationRequest(req)
+	if err != nil {
+		logger.Infof("[%s] Invalid simulation request: %s", simulateEndpoint, err)
+
+		writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	policyStr := request.Policy
+
+	if policyStr == "" {
+		policyStr, err = ps.currentPolicy()
+		if err != nil {
+			logger.Errorf("[%s] Error retrieving current witness policy: %s", simulateEndpoint, err)
+
+			writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+			return
+		}
+	}
+
+	cfg, err := config.Parse(policyStr)
+	if err != nil {
+		logger.Infof("[%s] Invalid witness policy [%s]: %s", simulateEndpoint, policyStr, err)
+
+		writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	respBytes, err := ps.marshal(&SimulationResponse{
+		Policy:     policyStr,
+		Evaluation: *policy.EvaluateConfig(cfg, witnessProofs),
+	})
+	if err != nil {
+		logger.Errorf("[%s] Marshal simulation response error: %s", simulateEndpoint, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	writeResponse(w, http.StatusOK, respBytes)
+}
+
+// currentPolicy returns the witness policy from the config store or an empty string (the default policy)
+// if no policy was configured.
+func (ps *PolicySimulator) currentPolicy() (string, error) {
+	policyBytes, err := ps.configStore.Get(policy.WitnessPolicyKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return "", nil
+		}
+
+		return "", fmt.Errorf("get witness policy: %w", err)
+	}
+
+	var policyStr string
+
+	err = json.Unmarshal(policyBytes, &policyStr)
+	if err != nil {
+		return "", fmt.Errorf("unmarshal witness policy: %w", err)
+	}
+
+	return policyStr, nil
+}
+
+func readSimulationRequest(req *http.Request) (*SimulationRequest, []*proof.WitnessProof, error) {
+	reqBytes, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read request body: %w", err)
+	}
+
+	request := &SimulationRequest{}
+
+	err = json.Unmarshal(reqBytes, request)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unmarshal request: %w", err)
+	}
+
+	witnessProofs, err := toWitnessProofs(request.Witnesses)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return request, witnessProofs, nil
+}
+
+func toWitnessProofs(witnesses []*SimulatedWitness) ([]*proof.WitnessProof, error) {
+	witnessProofs := make([]*proof.WitnessProof, len(witnesses))
+
+	for i, w := range witnesses {
+		if w == nil {
+			return nil, errors.New("witness is nil")
+		}
+
+		if w.Type != proof.WitnessTypeBatch && w.Type != proof.WitnessTypeSystem {
+			return nil, fmt.Errorf("invalid witness type [%s]", w.Type)
+		}
+
+		witnessProof := &proof.WitnessProof{
+			Type:     w.Type,
+			HasLog:   w.HasLog,
+			Selected: true,
+		}
+
+		if w.URI != "" {
+			u, err := url.Parse(w.URI)
+			if err != nil {
+				return nil, fmt.Errorf("invalid witness URI [%s]: %w", w.URI, err)
+			}
+
+			witnessProof.URI = u
+		}
+
+		if w.Proof {
+			// The content of the proof isn't evaluated by the policy.
+			witnessProof.Proof = []byte(`{}`)
+		}
+
+		witnessProofs[i] = witnessProof
+	}
+
+	return witnessProofs, nil
+}
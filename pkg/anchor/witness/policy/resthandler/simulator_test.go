@@ -0,0 +1,157 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/anchor/witness/policy"
+	"github.com/trustbloc/orb/pkg/internal/testutil/httptestutil"
+	storemocks "github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+const simulationRequest = `{"witnesses":[` +
+	`{"uri":"https://orb.domain1.com/services/orb","type":"batch","hasLog":true,"proof":true},` +
+	`{"uri":"https://orb.domain2.com/services/orb","type":"system","hasLog":true,"proof":true},` +
+	`{"uri":"https://orb.domain3.com/services/orb","type":"system","proof":false}]}`
+
+func TestNewSimulator(t *testing.T) {
+	configStore, err := mem.NewProvider().OpenStore(configStoreName)
+	require.NoError(t, err)
+
+	simulator := NewSimulator(configStore)
+	require.NotNil(t, simulator)
+	require.Equal(t, "/policy/simulate", simulator.Path())
+	require.Equal(t, http.MethodPost, simulator.Method())
+	require.NotNil(t, simulator.Handler())
+}
+
+func TestSimulator(t *testing.T) {
+	t.Run("success - current policy", func(t *testing.T) {
+		configStore, err := mem.NewProvider().OpenStore(configStoreName)
+		require.NoError(t, err)
+
+		simulator := NewSimulator(configStore)
+
+		// No policy configured. The default policy requires all witnesses.
+		status, resp := simulate(t, simulator, []byte(simulationRequest))
+		require.Equal(t, http.StatusOK, status)
+		require.Empty(t, resp.Policy)
+		require.False(t, resp.Satisfied)
+		require.Equal(t, policy.Tally{Total: 1, Collected: 1, Satisfied: true}, resp.Batch)
+		require.Equal(t, policy.Tally{Total: 2, Collected: 1, Satisfied: false}, resp.System)
+
+		policyBytes, err := json.Marshal(testPolicy)
+		require.NoError(t, err)
+
+		require.NoError(t, configStore.Put(policy.WitnessPolicyKey, policyBytes))
+
+		status, resp = simulate(t, simulator, []byte(simulationRequest))
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, testPolicy, resp.Policy)
+		require.True(t, resp.Satisfied)
+	})
+
+	t.Run("success - provided policy", func(t *testing.T) {
+		configStore, err := mem.NewProvider().OpenStore(configStoreName)
+		require.NoError(t, err)
+
+		simulator := NewSimulator(configStore)
+
+		status, resp := simulate(t, simulator, []byte(
+			`{"policy":"OutOf(2,system) LogRequired","witnesses":[`+
+				`{"type":"system","hasLog":true,"proof":true},{"type":"system","proof":true}]}`,
+		))
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, "OutOf(2,system) LogRequired", resp.Policy)
+		require.False(t, resp.Satisfied)
+		require.Equal(t, policy.Tally{Total: 2, Collected: 1, Satisfied: false}, resp.System)
+
+		// The provided policy isn't stored.
+		_, err = configStore.Get(policy.WitnessPolicyKey)
+		require.Error(t, err)
+	})
+
+	t.Run("error - reader error", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+
+		NewSimulator(&storemocks.Store{}).handle(rw, httptest.NewRequest(http.MethodPost, simulateEndpoint, errReader(0)))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusBadRequest, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+
+	t.Run("error - invalid request", func(t *testing.T) {
+		simulator := NewSimulator(&storemocks.Store{})
+
+		status, _ := simulate(t, simulator, []byte("{"))
+		require.Equal(t, http.StatusBadRequest, status)
+
+		status, _ = simulate(t, simulator, []byte(`{"witnesses":[null]}`))
+		require.Equal(t, http.StatusBadRequest, status)
+
+		status, _ = simulate(t, simulator, []byte(`{"witnesses":[{"type":"other"}]}`))
+		require.Equal(t, http.StatusBadRequest, status)
+
+		status, _ = simulate(t, simulator, []byte(`{"witnesses":[{"type":"batch","uri":":"}]}`))
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("error - invalid policy", func(t *testing.T) {
+		status, _ := simulate(t, NewSimulator(&storemocks.Store{}), []byte(`{"policy":"InvalidPolicy"}`))
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("error - config store error", func(t *testing.T) {
+		configStore := &storemocks.Store{}
+		configStore.GetReturns(nil, errors.New("injected get error"))
+
+		status, _ := simulate(t, NewSimulator(configStore), []byte(simulationRequest))
+		require.Equal(t, http.StatusInternalServerError, status)
+	})
+
+	t.Run("error - invalid stored policy", func(t *testing.T) {
+		configStore := &storemocks.Store{}
+		configStore.GetReturns([]byte("{"), nil)
+
+		status, _ := simulate(t, NewSimulator(configStore), []byte(simulationRequest))
+		require.Equal(t, http.StatusInternalServerError, status)
+	})
+
+	t.Run("error - marshal error", func(t *testing.T) {
+		simulator := NewSimulator(&storemocks.Store{})
+		simulator.marshal = func(interface{}) ([]byte, error) {
+			return nil, errors.New("injected marshal error")
+		}
+
+		status, _ := simulate(t, simulator, []byte(`{"policy":"`+testPolicy+`"}`))
+		require.Equal(t, http.StatusInternalServerError, status)
+	})
+}
+
+func simulate(t *testing.T, simulator *PolicySimulator, body []byte) (int, *SimulationResponse) {
+	t.Helper()
+
+	status, respBytes := httptestutil.Post(t, simulator.handle, simulateEndpoint, body)
+
+	if status != http.StatusOK {
+		return status, nil
+	}
+
+	resp := &SimulationResponse{}
+	require.NoError(t, json.Unmarshal(respBytes, resp))
+
+	return status, resp
+}
@@ -24,6 +24,7 @@ import (
 	txnapi "github.com/trustbloc/sidetree-core-go/pkg/api/txn"
 	"github.com/trustbloc/sidetree-core-go/pkg/canonicalizer"
 
+	"github.com/trustbloc/orb/pkg/activitypub/activitytemplate"
 	"github.com/trustbloc/orb/pkg/activitypub/resthandler"
 	"github.com/trustbloc/orb/pkg/activitypub/service/vct"
 	"github.com/trustbloc/orb/pkg/activitypub/store/spi"
@@ -36,6 +37,7 @@ import (
 	"github.com/trustbloc/orb/pkg/anchor/vcpubsub"
 	"github.com/trustbloc/orb/pkg/anchor/witness/proof"
 	discoveryrest "github.com/trustbloc/orb/pkg/discovery/endpoint/restapi"
+	documentutil "github.com/trustbloc/orb/pkg/document/util"
 	"github.com/trustbloc/orb/pkg/errors"
 	"github.com/trustbloc/orb/pkg/hashlink"
 	resourceresolver "github.com/trustbloc/orb/pkg/resolver/resource"
@@ -59,6 +61,7 @@ type metricsProvider interface {
 	WriteAnchorSignLocalStoreTime(value time.Duration)
 	WriteAnchorSignLocalWatchTime(value time.Duration)
 	WriteAnchorResolveHostMetaLinkTime(value time.Duration)
+	AnchorIncrementWrittenCount()
 }
 
 // Writer implements writing anchors.
@@ -92,6 +95,15 @@ type Providers struct {
 	WFClient               webfingerClient
 	DocumentLoader         ld.DocumentLoader
 	VCStore                storage.Store
+
+	// AnchorAnnouncer (optional) announces the 'Create' activities of written anchors on an additional channel
+	// (for example, IPFS pubsub) so that the anchors may be observed by services that can't receive them
+	// via ActivityPub.
+	AnchorAnnouncer anchorAnnouncer
+}
+
+type anchorAnnouncer interface {
+	Announce(create *vocab.ActivityType) error
 }
 
 type webfingerClient interface {
@@ -249,6 +261,8 @@ func (c *Writer) WriteAnchor(anchor string, attachments []*protocol.AnchorDocume
 			anchorEvent.Index(), err)
 	}
 
+	c.metrics.AnchorIncrementWrittenCount()
+
 	return nil
 }
 
@@ -348,6 +362,17 @@ func (c *Writer) buildCredential(contentObj vocab.Document) (*verifiable.Credent
 		return nil, fmt.Errorf("build anchor credential: %w", err)
 	}
 
+	// Validate the credential subject before the credential is signed so that a malformed anchor is never published.
+	subjectBytes, err := json.Marshal(map[string]interface{}{"credentialSubject": vc.Subject})
+	if err != nil {
+		return nil, fmt.Errorf("marshal anchor credential subject: %w", err)
+	}
+
+	err = subject.ValidateCredentialSubject(subjectBytes)
+	if err != nil {
+		return nil, fmt.Errorf("anchor credential [%s]: %w", vc.ID, err)
+	}
+
 	return vc, nil
 }
 
@@ -578,6 +603,13 @@ func (c *Writer) postCreateActivity(anchorEvent *vocab.AnchorEventType, hl strin
 
 	logger.Debugf("Successfully posted 'Create' activity to my followers [%s]", postID)
 
+	if c.AnchorAnnouncer != nil {
+		if e := c.AnchorAnnouncer.Announce(create); e != nil {
+			// The anchor was already delivered to our followers, so don't fail.
+			logger.Warnf("Error announcing 'Create' activity [%s]: %s", postID, e)
+		}
+	}
+
 	return nil
 }
 
@@ -594,20 +626,7 @@ func (c *Writer) postOfferActivity(anchorEvent *vocab.AnchorEventType, batchWitn
 		return fmt.Errorf("failed to get witnesses: %w", err)
 	}
 
-	witnessesIRI = append(witnessesIRI, vocab.PublicIRI)
-
-	startTime := time.Now()
-	endTime := startTime.Add(c.maxWitnessDelay)
-
-	offer := vocab.NewOfferActivity(
-		vocab.NewObjectProperty(
-			vocab.WithAnchorEvent(anchorEvent),
-		),
-		vocab.WithTo(witnessesIRI...),
-		vocab.WithStartTime(&startTime),
-		vocab.WithEndTime(&endTime),
-		vocab.WithTarget(vocab.NewObjectProperty(vocab.WithIRI(vocab.AnchorWitnessTargetIRI))),
-	)
+	offer := activitytemplate.NewWitnessOffer(anchorEvent, witnessesIRI, c.maxWitnessDelay)
 
 	postID, err := c.Outbox.Post(offer)
 	if err != nil {
@@ -677,11 +696,15 @@ func (c *Writer) resolveWitness(ref *operation.Reference) (string, error) {
 		return "", fmt.Errorf("operation type '%s' not supported for assembling witness list", ref.Type)
 	}
 
-	anchorOrigin, ok := anchorOriginObj.(string)
-	if !ok {
+	// The witness is resolved from the primary anchor origin. Fallback anchor origins (if any) are only
+	// used for resolution and discovery.
+	anchorOrigins, err := documentutil.ParseAnchorOrigins(anchorOriginObj)
+	if err != nil {
 		return "", fmt.Errorf("unexpected interface '%T' for anchor origin", anchorOriginObj)
 	}
 
+	anchorOrigin := anchorOrigins[0]
+
 	logger.Debugf("Resolving witness for the following anchor origin: %s", anchorOrigin)
 
 	resolveStartTime := time.Now()
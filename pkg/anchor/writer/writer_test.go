@@ -34,6 +34,7 @@ import (
 	"github.com/trustbloc/orb/pkg/anchor/builder"
 	"github.com/trustbloc/orb/pkg/anchor/graph"
 	anchormocks "github.com/trustbloc/orb/pkg/anchor/mocks"
+	"github.com/trustbloc/orb/pkg/anchor/subject"
 	"github.com/trustbloc/orb/pkg/anchor/witness/proof"
 	"github.com/trustbloc/orb/pkg/cas/ipfs"
 	casresolver "github.com/trustbloc/orb/pkg/cas/resolver"
@@ -538,6 +539,58 @@ func TestWriter_WriteAnchor(t *testing.T) {
 		require.Contains(t, err.Error(), "build anchor credential: sign error")
 	})
 
+	t.Run("error - invalid anchor credential subject", func(t *testing.T) {
+		anchorEventStore, err := anchoreventstore.New(mem.NewProvider(), testutil.GetLoader(t))
+		require.NoError(t, err)
+
+		statusStore, err := anchoreventstatus.New(mem.NewProvider(), testutil.GetExpiryService(t), time.Minute)
+		require.NoError(t, err)
+
+		providers := &Providers{
+			AnchorGraph:            anchorGraph,
+			DidAnchors:             memdidanchor.New(),
+			AnchorBuilder:          &mockTxnBuilder{InvalidSubject: true},
+			OpProcessor:            &mockOpProcessor{},
+			Outbox:                 &mockOutbox{},
+			Signer:                 &mockSigner{},
+			MonitoringSvc:          &mockMonitoring{},
+			Witness:                &mockWitness{},
+			WitnessStore:           &mockWitnessStore{},
+			ActivityStore:          &mockActivityStore{},
+			AnchorEventStore:       anchorEventStore,
+			AnchorEventStatusStore: statusStore,
+			WFClient:               wfClient,
+		}
+
+		c, err := New(namespace, apServiceIRI, casIRI, providers, &anchormocks.AnchorPublisher{}, ps,
+			testMaxWitnessDelay, false,
+			resourceresolver.New(http.DefaultClient, nil), &mocks.MetricsProvider{})
+		require.NoError(t, err)
+
+		var testServerURL string
+
+		testServer := httptest.NewServer(
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, err = w.Write(generateValidExampleHostMetaResponse(t, testServerURL))
+				require.NoError(t, err)
+			}))
+		defer testServer.Close()
+
+		testServerURL = testServer.URL
+
+		opRefs := []*operation.Reference{
+			{
+				UniqueSuffix: "did-1",
+				Type:         operation.TypeCreate,
+				AnchorOrigin: fmt.Sprintf("%s/services/orb", testServerURL),
+			},
+		}
+
+		err = c.WriteAnchor("1.anchor", nil, opRefs, 0)
+		require.ErrorIs(t, err, subject.ErrInvalidCredentialSubject)
+		require.Contains(t, err.Error(), "credentialSubject.id: Does not match pattern")
+	})
+
 	t.Run("error - anchor credential signing error", func(t *testing.T) {
 		providersWithErr := &Providers{
 			AnchorGraph:   anchorGraph,
@@ -963,6 +1016,45 @@ func TestWriter_handle(t *testing.T) {
 		require.NoError(t, c.handle(anchorEvent))
 	})
 
+	t.Run("success - with anchor announcer", func(t *testing.T) {
+		anchorEventStore, err := anchoreventstore.New(mem.NewProvider(), testutil.GetLoader(t))
+		require.NoError(t, err)
+
+		vcStore, err := mem.NewProvider().OpenStore("verifiable")
+		require.NoError(t, err)
+
+		announcer := &mockAnchorAnnouncer{}
+
+		providers := &Providers{
+			AnchorGraph:      anchorGraph,
+			DidAnchors:       memdidanchor.New(),
+			AnchorBuilder:    &mockTxnBuilder{},
+			Outbox:           &mockOutbox{},
+			Signer:           &mockSigner{},
+			AnchorEventStore: anchorEventStore,
+			WitnessStore:     &mockWitnessStore{},
+			VCStore:          vcStore,
+			DocumentLoader:   testutil.GetLoader(t),
+			AnchorAnnouncer:  announcer,
+		}
+
+		c, err := New(namespace, apServiceIRI, casIRI, providers, &anchormocks.AnchorPublisher{}, ps,
+			testMaxWitnessDelay, signWithLocalWitness, nil, &mocks.MetricsProvider{})
+		require.NoError(t, err)
+
+		anchorEvent := &vocab.AnchorEventType{}
+		require.NoError(t, json.Unmarshal([]byte(jsonAnchorEvent), anchorEvent))
+
+		require.NoError(t, c.handle(anchorEvent))
+		require.Len(t, announcer.announced, 1)
+		require.True(t, announcer.announced[0].Type().Is(vocab.TypeCreate))
+
+		// An announcement error shouldn't cause the anchor to fail.
+		announcer.err = errors.New("injected announce error")
+
+		require.NoError(t, c.handle(anchorEvent))
+	})
+
 	t.Run("error - add anchor credential to txn graph error", func(t *testing.T) {
 		anchorEventStore, err := anchoreventstore.New(mem.NewProvider(), testutil.GetLoader(t))
 		require.NoError(t, err)
@@ -1369,8 +1461,8 @@ func TestWriter_getWitnesses(t *testing.T) {
 
 		testServer := httptest.NewServer(
 			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-				if numTimesMockServerHandlerHit == 5 {
-					// Ensure that one of the witnesses returned is a duplicate of another one.
+				if numTimesMockServerHandlerHit >= 5 {
+					// Ensure that the remaining witnesses returned are duplicates of another one.
 					_, err = w.Write(generateValidExampleHostMetaResponse(t, fmt.Sprintf("%s/4", testServerURL)))
 					require.NoError(t, err)
 				} else {
@@ -1434,6 +1526,12 @@ func TestWriter_getWitnesses(t *testing.T) {
 				Type:         operation.TypeCreate,
 				AnchorOrigin: testAnchorOrigin, // test re-use same origin (this will be the same as the one above)
 			},
+			{
+				UniqueSuffix: "did-7",
+				Type:         operation.TypeCreate,
+				// the witness is resolved from the primary anchor origin (the fallback origin is unreachable)
+				AnchorOrigin: []interface{}{testAnchorOrigin, "https://fallback.domain.com"},
+			},
 		}
 
 		witnesses, err := c.getWitnessesFromBatchOperations(opRefs)
@@ -1595,7 +1693,8 @@ func TestWriter_Read(t *testing.T) {
 }
 
 type mockTxnBuilder struct {
-	Err error
+	Err            error
+	InvalidSubject bool
 }
 
 func (m *mockTxnBuilder) Build(anchorHashlink string) (*verifiable.Credential, error) {
@@ -1603,6 +1702,10 @@ func (m *mockTxnBuilder) Build(anchorHashlink string) (*verifiable.Credential, e
 		return nil, m.Err
 	}
 
+	if m.InvalidSubject {
+		return &verifiable.Credential{Subject: &builder.CredentialSubject{}}, nil
+	}
+
 	return &verifiable.Credential{Subject: &builder.CredentialSubject{ID: anchorHashlink}}, nil
 }
 
@@ -1651,6 +1754,21 @@ func (m *mockOutbox) Post(activity *vocab.ActivityType) (*url.URL, error) {
 	return activity.ID().URL(), nil
 }
 
+type mockAnchorAnnouncer struct {
+	announced []*vocab.ActivityType
+	err       error
+}
+
+func (m *mockAnchorAnnouncer) Announce(create *vocab.ActivityType) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	m.announced = append(m.announced, create)
+
+	return nil
+}
+
 type mockSigner struct {
 	Err error
 }
@@ -0,0 +1,178 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"time"
+)
+
+const (
+	// FormatVersion is the version of the archive format.
+	FormatVersion = 1
+
+	// DatabaseTypeMongoDB is the database type of an archive created from a MongoDB database.
+	DatabaseTypeMongoDB = "mongodb"
+
+	manifestEntry   = "manifest.json"
+	collectionExt   = ".jsonl"
+	entryFileMode   = 0o600
+	maxManifestSize = 10 * 1024 * 1024
+)
+
+// Manifest describes the contents of a backup archive. The manifest is always the first entry of the archive
+// so that an archive may be validated before any data is restored.
+type Manifest struct {
+	FormatVersion  int           `json:"formatVersion"`
+	Created        time.Time     `json:"created"`
+	DatabaseType   string        `json:"databaseType"`
+	DatabasePrefix string        `json:"databasePrefix,omitempty"`
+	Collections    []*Collection `json:"collections"`
+}
+
+// Collection contains the name and document count of a collection in the archive.
+type Collection struct {
+	Database  string `json:"database"`
+	Name      string `json:"name"`
+	Documents int64  `json:"documents"`
+}
+
+// EntryName returns the name of the archive entry that contains the documents of the collection.
+func (c *Collection) EntryName() string {
+	return path.Join(c.Database, c.Name+collectionExt)
+}
+
+// archiveWriter writes a gzipped tar archive.
+type archiveWriter struct {
+	gw *gzip.Writer
+	tw *tar.Writer
+}
+
+func newArchiveWriter(w io.Writer) *archiveWriter {
+	gw := gzip.NewWriter(w)
+
+	return &archiveWriter{
+		gw: gw,
+		tw: tar.NewWriter(gw),
+	}
+}
+
+func (w *archiveWriter) writeManifest(manifest *Manifest) error {
+	manifestBytes, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal manifest: %w", err)
+	}
+
+	return w.writeEntry(manifestEntry, int64(len(manifestBytes)), bytes.NewReader(manifestBytes),
+		manifest.Created)
+}
+
+func (w *archiveWriter) writeEntry(name string, size int64, r io.Reader, modTime time.Time) error {
+	err := w.tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    entryFileMode,
+		Size:    size,
+		ModTime: modTime,
+	})
+	if err != nil {
+		return fmt.Errorf("write header of archive entry [%s]: %w", name, err)
+	}
+
+	_, err = io.Copy(w.tw, r)
+	if err != nil {
+		return fmt.Errorf("write archive entry [%s]: %w", name, err)
+	}
+
+	return nil
+}
+
+func (w *archiveWriter) Close() error {
+	if err := w.tw.Close(); err != nil {
+		return fmt.Errorf("close tar writer: %w", err)
+	}
+
+	if err := w.gw.Close(); err != nil {
+		return fmt.Errorf("close gzip writer: %w", err)
+	}
+
+	return nil
+}
+
+// archiveReader reads a gzipped tar archive that was written by archiveWriter.
+type archiveReader struct {
+	gr *gzip.Reader
+	tr *tar.Reader
+}
+
+func newArchiveReader(r io.Reader) (*archiveReader, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("open archive: %w", err)
+	}
+
+	return &archiveReader{
+		gr: gr,
+		tr: tar.NewReader(gr),
+	}, nil
+}
+
+// readManifest reads and validates the manifest, which must be the first entry of the archive.
+func (r *archiveReader) readManifest() (*Manifest, error) {
+	hdr, err := r.tr.Next()
+	if err != nil {
+		return nil, fmt.Errorf("read manifest: %w", err)
+	}
+
+	if hdr.Name != manifestEntry {
+		return nil, fmt.Errorf("expecting [%s] as the first entry of the archive but got [%s]",
+			manifestEntry, hdr.Name)
+	}
+
+	manifestBytes, err := ioutil.ReadAll(io.LimitReader(r.tr, maxManifestSize))
+	if err != nil {
+		return nil, fmt.Errorf("read manifest: %w", err)
+	}
+
+	manifest := &Manifest{}
+
+	err = json.Unmarshal(manifestBytes, manifest)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal manifest: %w", err)
+	}
+
+	if manifest.FormatVersion != FormatVersion {
+		return nil, fmt.Errorf("unsupported archive format version: %d", manifest.FormatVersion)
+	}
+
+	return manifest, nil
+}
+
+// next returns the name and contents of the next entry in the archive or io.EOF if there are no more entries.
+func (r *archiveReader) next() (string, io.Reader, error) {
+	hdr, err := r.tr.Next()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return "", nil, io.EOF
+		}
+
+		return "", nil, fmt.Errorf("read archive entry: %w", err)
+	}
+
+	return hdr.Name, r.tr, nil
+}
+
+func (r *archiveReader) Close() error {
+	return r.gr.Close()
+}
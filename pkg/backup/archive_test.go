@@ -0,0 +1,118 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchive(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		manifest := &Manifest{
+			FormatVersion:  FormatVersion,
+			Created:        time.Now().UTC(),
+			DatabaseType:   DatabaseTypeMongoDB,
+			DatabasePrefix: "orb",
+			Collections:    []*Collection{{Database: "orb_activity", Name: "activity", Documents: 2}},
+		}
+
+		buf := &bytes.Buffer{}
+
+		aw := newArchiveWriter(buf)
+		require.NoError(t, aw.writeManifest(manifest))
+
+		data := "{\"a\":1}\n{\"a\":2}\n"
+
+		require.NoError(t, aw.writeEntry(manifest.Collections[0].EntryName(), int64(len(data)),
+			strings.NewReader(data), manifest.Created))
+		require.NoError(t, aw.Close())
+
+		ar, err := newArchiveReader(buf)
+		require.NoError(t, err)
+
+		m, err := ar.readManifest()
+		require.NoError(t, err)
+		require.Equal(t, "orb", m.DatabasePrefix)
+		require.Len(t, m.Collections, 1)
+		require.Equal(t, int64(2), m.Collections[0].Documents)
+
+		name, r, err := ar.next()
+		require.NoError(t, err)
+		require.Equal(t, "orb_activity/activity.jsonl", name)
+
+		entryBytes, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+		require.Equal(t, data, string(entryBytes))
+
+		_, _, err = ar.next()
+		require.True(t, errors.Is(err, io.EOF))
+
+		require.NoError(t, ar.Close())
+	})
+
+	t.Run("Not an archive", func(t *testing.T) {
+		_, err := newArchiveReader(strings.NewReader("not gzipped"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "open archive")
+	})
+
+	t.Run("Manifest isn't the first entry", func(t *testing.T) {
+		ar, err := newArchiveReader(newTestArchive(t, "orb_activity/activity.jsonl", "{}"))
+		require.NoError(t, err)
+
+		_, err = ar.readManifest()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "expecting [manifest.json] as the first entry")
+	})
+
+	t.Run("Invalid manifest", func(t *testing.T) {
+		ar, err := newArchiveReader(newTestArchive(t, manifestEntry, "{"))
+		require.NoError(t, err)
+
+		_, err = ar.readManifest()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal manifest")
+	})
+
+	t.Run("Unsupported format version", func(t *testing.T) {
+		ar, err := newArchiveReader(newTestArchive(t, manifestEntry, `{"formatVersion":99}`))
+		require.NoError(t, err)
+
+		_, err = ar.readManifest()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unsupported archive format version: 99")
+	})
+}
+
+func newTestArchive(t *testing.T, name, contents string) io.Reader {
+	t.Helper()
+
+	buf := &bytes.Buffer{}
+
+	gw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gw)
+
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: entryFileMode, Size: int64(len(contents))}))
+
+	_, err := tw.Write([]byte(contents))
+	require.NoError(t, err)
+
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+
+	return buf
+}
@@ -0,0 +1,407 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package backup
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/trustbloc/edge-core/pkg/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var logger = log.New("backup")
+
+const (
+	defaultBatchSize = 500
+	maxLineSize      = 16 * 1024 * 1024 // The maximum size of a MongoDB document.
+)
+
+//nolint:gochecknoglobals
+var systemDatabases = map[string]struct{}{
+	"admin":  {},
+	"local":  {},
+	"config": {},
+}
+
+// MongoDB backs up and restores the Orb databases (activity, anchor, operation, configuration stores, etc.)
+// of a MongoDB server. Only the databases that start with the given prefix are included.
+type MongoDB struct {
+	client    *mongo.Client
+	prefix    string
+	batchSize int
+	now       func() time.Time
+}
+
+// Opt is a MongoDB backup option.
+type Opt func(m *MongoDB)
+
+// WithBatchSize sets the number of documents inserted in a single request when restoring.
+func WithBatchSize(size int) Opt {
+	return func(m *MongoDB) {
+		m.batchSize = size
+	}
+}
+
+// NewMongoDB returns a new MongoDB backup/restore provider.
+func NewMongoDB(client *mongo.Client, prefix string, opts ...Opt) *MongoDB {
+	m := &MongoDB{
+		client:    client,
+		prefix:    prefix,
+		batchSize: defaultBatchSize,
+		now:       time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
+}
+
+// Backup writes all collections of the Orb databases to the given writer as a gzipped tar archive. The documents
+// of each collection are written in canonical extended JSON, one document per line. The writes to the database
+// should be stopped (for example by enabling maintenance mode on all servers) in order for the backup to be
+// consistent.
+func (m *MongoDB) Backup(ctx context.Context, w io.Writer) (*Manifest, error) {
+	collections, err := m.collections(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tempDir, err := ioutil.TempDir("", "orb-backup")
+	if err != nil {
+		return nil, fmt.Errorf("create temp dir: %w", err)
+	}
+
+	defer func() {
+		if e := os.RemoveAll(tempDir); e != nil {
+			logger.Warnf("Error removing temp dir [%s]: %s", tempDir, e)
+		}
+	}()
+
+	manifest := &Manifest{
+		FormatVersion:  FormatVersion,
+		Created:        m.now().UTC(),
+		DatabaseType:   DatabaseTypeMongoDB,
+		DatabasePrefix: m.prefix,
+		Collections:    collections,
+	}
+
+	// The documents are exported to temporary files first since the manifest (which contains the document counts)
+	// must be the first entry of the archive.
+	files := make([]string, len(collections))
+
+	for i, c := range collections {
+		files[i] = filepath.Join(tempDir, fmt.Sprintf("%d%s", i, collectionExt))
+
+		c.Documents, err = m.exportCollection(ctx, c, files[i])
+		if err != nil {
+			return nil, err
+		}
+
+		logger.Infof("Exported %d documents from collection [%s.%s]", c.Documents, c.Database, c.Name)
+	}
+
+	aw := newArchiveWriter(w)
+
+	err = aw.writeManifest(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, c := range collections {
+		err = writeFileEntry(aw, c.EntryName(), files[i], manifest.Created)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = aw.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// Restore restores the collections in the given archive. The database prefix of the archive is replaced with the
+// database prefix of this provider so that a backup may be restored to a different deployment. The restore fails
+// if any of the collections already contains documents, unless overwrite is true in which case the existing
+// collections are dropped first. Indexes aren't restored since they're created by the server at startup.
+func (m *MongoDB) Restore(ctx context.Context, r io.Reader, overwrite bool) (*Manifest, error) {
+	ar, err := newArchiveReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if e := ar.Close(); e != nil {
+			logger.Warnf("Error closing archive: %s", e)
+		}
+	}()
+
+	manifest, err := ar.readManifest()
+	if err != nil {
+		return nil, err
+	}
+
+	if manifest.DatabaseType != DatabaseTypeMongoDB {
+		return nil, fmt.Errorf("unsupported database type in archive: %s", manifest.DatabaseType)
+	}
+
+	collections, err := m.prepareRestore(ctx, manifest, overwrite)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		name, entry, e := ar.next()
+		if e != nil {
+			if errors.Is(e, io.EOF) {
+				break
+			}
+
+			return nil, e
+		}
+
+		c, ok := collections[name]
+		if !ok {
+			return nil, fmt.Errorf("archive entry [%s] isn't in the manifest", name)
+		}
+
+		err = m.importCollection(ctx, c, entry)
+		if err != nil {
+			return nil, err
+		}
+
+		delete(collections, name)
+	}
+
+	if len(collections) > 0 {
+		return nil, fmt.Errorf("archive is missing %d collection(s) listed in the manifest", len(collections))
+	}
+
+	return manifest, nil
+}
+
+// TargetDatabase returns the name of the database to which the given database in the archive is restored.
+func (m *MongoDB) TargetDatabase(manifest *Manifest, database string) string {
+	return m.prefix + strings.TrimPrefix(database, manifest.DatabasePrefix)
+}
+
+func (m *MongoDB) collections(ctx context.Context) ([]*Collection, error) {
+	dbNames, err := m.client.ListDatabaseNames(ctx, bson.D{})
+	if err != nil {
+		return nil, fmt.Errorf("list databases: %w", err)
+	}
+
+	sort.Strings(dbNames)
+
+	var collections []*Collection
+
+	for _, dbName := range dbNames {
+		if _, ok := systemDatabases[dbName]; ok || !strings.HasPrefix(dbName, m.prefix) {
+			continue
+		}
+
+		names, e := m.client.Database(dbName).ListCollectionNames(ctx, bson.D{})
+		if e != nil {
+			return nil, fmt.Errorf("list collections of database [%s]: %w", dbName, e)
+		}
+
+		sort.Strings(names)
+
+		for _, name := range names {
+			if strings.HasPrefix(name, "system.") {
+				continue
+			}
+
+			collections = append(collections, &Collection{Database: dbName, Name: name})
+		}
+	}
+
+	return collections, nil
+}
+
+func (m *MongoDB) exportCollection(ctx context.Context, c *Collection, fileName string) (int64, error) {
+	f, err := os.Create(filepath.Clean(fileName))
+	if err != nil {
+		return 0, fmt.Errorf("create temp file: %w", err)
+	}
+
+	defer closeFile(f)
+
+	cursor, err := m.client.Database(c.Database).Collection(c.Name).Find(ctx, bson.D{})
+	if err != nil {
+		return 0, fmt.Errorf("query collection [%s.%s]: %w", c.Database, c.Name, err)
+	}
+
+	defer func() {
+		if e := cursor.Close(ctx); e != nil {
+			logger.Warnf("Error closing cursor: %s", e)
+		}
+	}()
+
+	bw := bufio.NewWriter(f)
+
+	var count int64
+
+	for cursor.Next(ctx) {
+		docBytes, e := bson.MarshalExtJSON(cursor.Current, true, false)
+		if e != nil {
+			return 0, fmt.Errorf("marshal document in collection [%s.%s]: %w", c.Database, c.Name, e)
+		}
+
+		if _, e = bw.Write(append(docBytes, '\n')); e != nil {
+			return 0, fmt.Errorf("write temp file: %w", e)
+		}
+
+		count++
+	}
+
+	if err = cursor.Err(); err != nil {
+		return 0, fmt.Errorf("iterate collection [%s.%s]: %w", c.Database, c.Name, err)
+	}
+
+	if err = bw.Flush(); err != nil {
+		return 0, fmt.Errorf("write temp file: %w", err)
+	}
+
+	return count, nil
+}
+
+// prepareRestore ensures that the target collections are empty (or drops them if overwrite is true) and returns
+// the target collections keyed by archive entry name.
+func (m *MongoDB) prepareRestore(ctx context.Context, manifest *Manifest,
+	overwrite bool) (map[string]*Collection, error) {
+	collections := make(map[string]*Collection)
+
+	for _, c := range manifest.Collections {
+		target := &Collection{
+			Database:  m.TargetDatabase(manifest, c.Database),
+			Name:      c.Name,
+			Documents: c.Documents,
+		}
+
+		collections[c.EntryName()] = target
+
+		coll := m.client.Database(target.Database).Collection(c.Name)
+
+		if overwrite {
+			if err := coll.Drop(ctx); err != nil {
+				return nil, fmt.Errorf("drop collection [%s.%s]: %w", coll.Database().Name(), c.Name, err)
+			}
+
+			continue
+		}
+
+		count, err := coll.EstimatedDocumentCount(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("count documents in collection [%s.%s]: %w", coll.Database().Name(), c.Name, err)
+		}
+
+		if count > 0 {
+			return nil, fmt.Errorf("collection [%s.%s] isn't empty (use overwrite to replace existing data)",
+				coll.Database().Name(), c.Name)
+		}
+	}
+
+	return collections, nil
+}
+
+func (m *MongoDB) importCollection(ctx context.Context, c *Collection, r io.Reader) error {
+	coll := m.client.Database(c.Database).Collection(c.Name)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLineSize)
+
+	var (
+		batch []interface{}
+		count int64
+	)
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+
+		if _, err := coll.InsertMany(ctx, batch); err != nil {
+			return fmt.Errorf("insert documents into collection [%s.%s]: %w", c.Database, c.Name, err)
+		}
+
+		count += int64(len(batch))
+		batch = nil
+
+		return nil
+	}
+
+	for scanner.Scan() {
+		doc := bson.D{}
+
+		if err := bson.UnmarshalExtJSON(scanner.Bytes(), true, &doc); err != nil {
+			return fmt.Errorf("unmarshal document for collection [%s.%s]: %w", c.Database, c.Name, err)
+		}
+
+		batch = append(batch, doc)
+
+		if len(batch) >= m.batchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read archive entry for collection [%s.%s]: %w", c.Database, c.Name, err)
+	}
+
+	if err := flush(); err != nil {
+		return err
+	}
+
+	if count != c.Documents {
+		return fmt.Errorf("expecting %d documents in collection [%s.%s] but restored %d",
+			c.Documents, c.Database, c.Name, count)
+	}
+
+	logger.Infof("Restored %d documents to collection [%s.%s]", count, c.Database, c.Name)
+
+	return nil
+}
+
+func writeFileEntry(aw *archiveWriter, name, fileName string, modTime time.Time) error {
+	f, err := os.Open(filepath.Clean(fileName))
+	if err != nil {
+		return fmt.Errorf("open temp file: %w", err)
+	}
+
+	defer closeFile(f)
+
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("stat temp file: %w", err)
+	}
+
+	return aw.writeEntry(name, info.Size(), f, modTime)
+}
+
+func closeFile(f *os.File) {
+	if err := f.Close(); err != nil {
+		logger.Warnf("Error closing file [%s]: %s", f.Name(), err)
+	}
+}
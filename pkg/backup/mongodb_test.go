@@ -0,0 +1,145 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package backup
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go-ext/component/storage/mongodb"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/trustbloc/orb/pkg/internal/testutil/mongodbtestutil"
+)
+
+func TestMongoDB(t *testing.T) {
+	mongoDBConnString, stopMongo := mongodbtestutil.StartMongoDB(t)
+	defer stopMongo()
+
+	ctx := context.Background()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(mongoDBConnString))
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, client.Disconnect(ctx))
+	}()
+
+	sourceProvider, err := mongodb.NewProvider(mongoDBConnString, mongodb.WithDBPrefix("orbsrc"))
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, sourceProvider.Close())
+	}()
+
+	activityStore, err := sourceProvider.OpenStore("activity")
+	require.NoError(t, err)
+
+	require.NoError(t, activityStore.Put("activity1", []byte(`{"id":"activity1"}`),
+		storage.Tag{Name: "type", Value: "Create"}))
+	require.NoError(t, activityStore.Put("activity2", []byte(`{"id":"activity2"}`),
+		storage.Tag{Name: "type", Value: "Announce"}))
+
+	configStore, err := sourceProvider.OpenStore("orb-config")
+	require.NoError(t, err)
+
+	require.NoError(t, configStore.Put("config1", []byte(`{"value":1}`)))
+
+	buf := &bytes.Buffer{}
+
+	manifest, err := NewMongoDB(client, "orbsrc").Backup(ctx, buf)
+	require.NoError(t, err)
+	require.Equal(t, DatabaseTypeMongoDB, manifest.DatabaseType)
+	require.Equal(t, "orbsrc", manifest.DatabasePrefix)
+	require.Len(t, manifest.Collections, 2)
+
+	archive := buf.Bytes()
+
+	t.Run("Restore to a different prefix", func(t *testing.T) {
+		target := NewMongoDB(client, "orbdst", WithBatchSize(1))
+
+		restored, err := target.Restore(ctx, bytes.NewReader(archive), false)
+		require.NoError(t, err)
+		require.Len(t, restored.Collections, 2)
+
+		targetProvider, err := mongodb.NewProvider(mongoDBConnString, mongodb.WithDBPrefix("orbdst"))
+		require.NoError(t, err)
+
+		defer func() {
+			require.NoError(t, targetProvider.Close())
+		}()
+
+		s, err := targetProvider.OpenStore("activity")
+		require.NoError(t, err)
+
+		value, err := s.Get("activity2")
+		require.NoError(t, err)
+		require.Equal(t, `{"id":"activity2"}`, string(value))
+
+		tags, err := s.GetTags("activity1")
+		require.NoError(t, err)
+		require.Equal(t, []storage.Tag{{Name: "type", Value: "Create"}}, tags)
+
+		s, err = targetProvider.OpenStore("orb-config")
+		require.NoError(t, err)
+
+		value, err = s.Get("config1")
+		require.NoError(t, err)
+		require.Equal(t, `{"value":1}`, string(value))
+
+		t.Run("Collection isn't empty", func(t *testing.T) {
+			_, err := target.Restore(ctx, bytes.NewReader(archive), false)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "isn't empty")
+		})
+
+		t.Run("Overwrite", func(t *testing.T) {
+			_, err := target.Restore(ctx, bytes.NewReader(archive), true)
+			require.NoError(t, err)
+		})
+	})
+
+	t.Run("Unsupported database type", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+
+		aw := newArchiveWriter(buf)
+		require.NoError(t, aw.writeManifest(&Manifest{FormatVersion: FormatVersion, DatabaseType: "couchdb"}))
+		require.NoError(t, aw.Close())
+
+		_, err := NewMongoDB(client, "orbdst").Restore(ctx, buf, false)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unsupported database type in archive: couchdb")
+	})
+
+	t.Run("Missing collection", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+
+		aw := newArchiveWriter(buf)
+		require.NoError(t, aw.writeManifest(&Manifest{
+			FormatVersion:  FormatVersion,
+			DatabaseType:   DatabaseTypeMongoDB,
+			DatabasePrefix: "orbsrc",
+			Collections:    []*Collection{{Database: "orbsrc_missing", Name: "missing"}},
+		}))
+		require.NoError(t, aw.Close())
+
+		_, err := NewMongoDB(client, "orbmissing").Restore(ctx, buf, false)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "archive is missing 1 collection(s)")
+	})
+}
+
+func TestTargetDatabase(t *testing.T) {
+	m := NewMongoDB(nil, "orb2")
+
+	require.Equal(t, "orb2_activity", m.TargetDatabase(&Manifest{DatabasePrefix: "orb1"}, "orb1_activity"))
+	require.Equal(t, "orb2activity", m.TargetDatabase(&Manifest{}, "activity"))
+}
@@ -13,6 +13,7 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net/http"
 	"strings"
 	"time"
 
@@ -65,6 +66,13 @@ func New(url string, timeout time.Duration, cacheSize int, metrics metricsProvid
 	return newClient(ipfs, cacheSize, metrics, opts...)
 }
 
+// NewWithHTTPClient creates a cas client which sends requests to IPFS using the given HTTP client. The timeout
+// of the requests is applied by the HTTP client.
+func NewWithHTTPClient(url string, httpClient *http.Client, cacheSize int, metrics metricsProvider,
+	opts ...extendedcasclient.CIDFormatOption) *Client {
+	return newClient(shell.NewShellWithClient(url, httpClient), cacheSize, metrics, opts...)
+}
+
 func newClient(ipfs ipfsClient, cacheSize int, metrics metricsProvider,
 	opts ...extendedcasclient.CIDFormatOption) *Client {
 	if cacheSize == 0 {
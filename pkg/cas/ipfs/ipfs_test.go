@@ -35,6 +35,20 @@ func TestNew(t *testing.T) {
 	require.NotNil(t, c)
 }
 
+func TestNewWithHTTPClient(t *testing.T) {
+	ipfs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ipfs.Close()
+
+	c := NewWithHTTPClient(ipfs.URL, ipfs.Client(), 0, &orbmocks.MetricsProvider{})
+	require.NotNil(t, c)
+
+	cid, err := c.Write([]byte("content"))
+	require.Error(t, err)
+	require.Empty(t, cid)
+}
+
 func TestWrite(t *testing.T) {
 	log.SetLevel(logModule, log.DEBUG)
 
@@ -9,20 +9,24 @@ package resolver
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/trustbloc/edge-core/pkg/log"
 
 	"github.com/trustbloc/orb/pkg/activitypub/client/transport"
+	"github.com/trustbloc/orb/pkg/anchor/linksetsig"
 	"github.com/trustbloc/orb/pkg/cas/extendedcasclient"
 	orberrors "github.com/trustbloc/orb/pkg/errors"
 	"github.com/trustbloc/orb/pkg/hashlink"
+	"github.com/trustbloc/orb/pkg/linkset"
 	"github.com/trustbloc/orb/pkg/multihash"
 	webfingerclient "github.com/trustbloc/orb/pkg/webfinger/client"
 )
@@ -35,6 +39,11 @@ const (
 	cidWithPossibleHintNumPartsWithDomainPort = 4
 )
 
+// webCASAcceptHeader prefers the content as it's stored in the remote CAS (so that its hash may be verified)
+// but also accepts linksets for interop with non-Orb servers that serve anchor data as linksets.
+const webCASAcceptHeader = transport.LDPlusJSONContentType + ", " + linkset.ContentTypeJSON + ";q=0.9, " +
+	linkset.ContentTypeNative + ";q=0.8"
+
 const logModule = "cas-resolver"
 
 var logger = log.New(logModule)
@@ -112,6 +121,11 @@ func (h *Resolver) Resolve(_ *url.URL, hashWithPossibleHint string, data []byte)
 			return nil, "", fmt.Errorf("read from IPFS: %w", e)
 		}
 
+		e = h.webCASResolver.verifyFromAnchorOrigin(resourceHash, data)
+		if e != nil {
+			return nil, "", e
+		}
+
 		return data, "", nil
 	}
 
@@ -288,6 +302,11 @@ func (h *Resolver) getAndStoreDataFromIPFS(cid, resourceHash string) ([]byte, st
 		return nil, "", fmt.Errorf("failed to read cid[%s] from ipfs: %w", cid, err)
 	}
 
+	err = h.webCASResolver.verifyFromAnchorOrigin(resourceHash, resp)
+	if err != nil {
+		return nil, "", err
+	}
+
 	localHL, err := h.storeLocallyAndVerifyHash(resp, resourceHash)
 	if err != nil {
 		return nil, "", fmt.Errorf("failure while storing data retrieved from the ipfs: %w",
@@ -325,19 +344,54 @@ func (h *Resolver) storeLocallyAndVerifyHash(data []byte, resourceHash string) (
 	return newHLFromLocalCAS, nil
 }
 
+type linksetSignatureVerifier interface {
+	Verify(content []byte, jws string) error
+}
+
 // WebCASResolver is used to resolve data from another Orb server's CAS.
 type WebCASResolver struct {
 	httpClient         httpClient
 	webFingerClient    *webfingerclient.Client
 	webFingerURIScheme string
+	signatureVerifier  linksetSignatureVerifier
+	signatureRequired  bool
+	knownSigners       *hostSet
+}
+
+// WebCASResolverOpt is a WebCAS resolver option.
+type WebCASResolverOpt func(w *WebCASResolver)
+
+// WithLinksetSignatureVerifier sets the verifier of the detached JWS that is returned (in the
+// linksetsig.HeaderName response header) along with the content that is retrieved from a WebCAS endpoint.
+// The signature of an anchor linkset that is retrieved from IPFS is retrieved from the WebCAS endpoint of
+// the anchor origin.
+func WithLinksetSignatureVerifier(verifier linksetSignatureVerifier) WebCASResolverOpt {
+	return func(w *WebCASResolver) {
+		w.signatureVerifier = verifier
+	}
+}
+
+// WithLinksetSignatureRequired indicates whether or not anchor linksets must be signed. If false then unsigned
+// anchor linksets are only rejected from anchor origins that previously signed their linksets.
+func WithLinksetSignatureRequired(required bool) WebCASResolverOpt {
+	return func(w *WebCASResolver) {
+		w.signatureRequired = required
+	}
 }
 
 // NewWebCASResolver returns a new WebCASResolver.
 func NewWebCASResolver(httpClient httpClient, webFingerClient *webfingerclient.Client,
-	webFingerURIScheme string) WebCASResolver {
-	return WebCASResolver{
+	webFingerURIScheme string, opts ...WebCASResolverOpt) WebCASResolver {
+	w := WebCASResolver{
 		httpClient: httpClient, webFingerClient: webFingerClient, webFingerURIScheme: webFingerURIScheme,
+		knownSigners: newHostSet(),
 	}
+
+	for _, opt := range opts {
+		opt(&w)
+	}
+
+	return w
 }
 
 // Resolve returns the data stored at cid via the WebCAS hosted at domain.
@@ -362,10 +416,37 @@ func (w *WebCASResolver) Resolve(domain, cid string) ([]byte, error) {
 
 // GetDataViaWebCASEndpoint retrieves data from the given webCASEndpoint and returns it.
 func (w *WebCASResolver) GetDataViaWebCASEndpoint(webCASEndpoint *url.URL) ([]byte, error) {
+	responseBody, header, err := w.get(webCASEndpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	// The signature is verified before a native linkset is converted since the signature is computed over the
+	// returned bytes. (A server that signs its linksets returns them as is, so a native linkset from an anchor
+	// origin that's known to sign is rejected.)
+	err = w.verifySignature(webCASEndpoint.String(), responseBody, header.Get(linksetsig.HeaderName))
+	if err != nil {
+		return nil, err
+	}
+
+	if isNativeLinkset(header.Get("Content-Type")) {
+		// Convert the native linkset to a JSON linkset since that's how anchor data is processed.
+		ls, e := linkset.ParseNative(responseBody)
+		if e != nil {
+			return nil, fmt.Errorf("failed to parse linkset from %s: %w", webCASEndpoint, e)
+		}
+
+		return json.Marshal(ls)
+	}
+
+	return responseBody, nil
+}
+
+func (w *WebCASResolver) get(webCASEndpoint *url.URL) ([]byte, http.Header, error) {
 	resp, err := w.httpClient.Get(context.Background(), transport.NewRequest(webCASEndpoint,
-		transport.WithHeader(transport.AcceptHeader, transport.LDPlusJSONContentType)))
+		transport.WithHeader(transport.AcceptHeader, webCASAcceptHeader)))
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute GET call on %s: %w", webCASEndpoint.String(), err)
+		return nil, nil, fmt.Errorf("failed to execute GET call on %s: %w", webCASEndpoint.String(), err)
 	}
 
 	defer func() {
@@ -377,13 +458,132 @@ func (w *WebCASResolver) GetDataViaWebCASEndpoint(webCASEndpoint *url.URL) ([]by
 
 	responseBody, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body from remote WebCAS endpoint: %w", err)
+		return nil, nil, fmt.Errorf("failed to read response body from remote WebCAS endpoint: %w", err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to retrieve data from %s. Response status code: %d. Response body: %s",
+		return nil, nil, fmt.Errorf("failed to retrieve data from %s. Response status code: %d. Response body: %s",
 			webCASEndpoint.String(), resp.StatusCode, string(responseBody))
 	}
 
-	return responseBody, nil
+	return responseBody, resp.Header, nil
+}
+
+// verifySignature verifies the detached JWS of the content (retrieved from the given source) against the keys
+// of the anchor origin of the content. If no signature was returned then an error is returned if signatures
+// are required or if the anchor origin previously signed its linksets, since the signature may have been
+// stripped in transit. Content that isn't an anchor linkset (e.g. a Sidetree batch file) isn't signed.
+func (w *WebCASResolver) verifySignature(source string, content []byte, jws string) error {
+	if w.signatureVerifier == nil {
+		return nil
+	}
+
+	anchorOrigin, err := linksetsig.AnchorOrigin(content)
+	if err != nil {
+		if jws == "" && (errors.Is(err, linksetsig.ErrNotLinkset) || !w.signatureRequired) {
+			return nil
+		}
+
+		return fmt.Errorf("verify linkset signature of content from %s: %w: %s",
+			source, linksetsig.ErrInvalidSignature, err)
+	}
+
+	if jws == "" {
+		if w.signatureRequired || w.knownSigners.contains(anchorOrigin.Host) {
+			return fmt.Errorf("verify linkset signature of content from %s: %w: signature is missing",
+				source, linksetsig.ErrInvalidSignature)
+		}
+
+		return nil
+	}
+
+	err = w.signatureVerifier.Verify(content, jws)
+	if err != nil {
+		return fmt.Errorf("verify linkset signature of content from %s: %w", source, err)
+	}
+
+	w.knownSigners.add(anchorOrigin.Host)
+
+	logger.Debugf("Verified linkset signature of content from %s", source)
+
+	return nil
+}
+
+// verifyFromAnchorOrigin verifies the linkset signature of content that was retrieved from IPFS. Since IPFS
+// doesn't return a signature along with the content, the signature is retrieved from the WebCAS endpoint of
+// the anchor origin of the linkset.
+func (w *WebCASResolver) verifyFromAnchorOrigin(resourceHash string, content []byte) error {
+	if w.signatureVerifier == nil {
+		return nil
+	}
+
+	anchorOrigin, err := linksetsig.AnchorOrigin(content)
+	if err != nil {
+		// Let verifySignature decide whether or not the unsigned content is acceptable.
+		return w.verifySignature("IPFS", content, "")
+	}
+
+	jws, err := w.getSignature(anchorOrigin, resourceHash)
+	if err != nil {
+		if w.signatureRequired || w.knownSigners.contains(anchorOrigin.Host) {
+			return orberrors.NewTransient(fmt.Errorf("get linkset signature of [%s] from anchor origin [%s]: %w",
+				resourceHash, anchorOrigin, err))
+		}
+
+		logger.Debugf("Unable to get linkset signature of [%s] from anchor origin [%s]: %s",
+			resourceHash, anchorOrigin, err)
+
+		return nil
+	}
+
+	return w.verifySignature("IPFS", content, jws)
+}
+
+// getSignature returns the linkset signature of the content with the given resource hash from the WebCAS
+// endpoint of the given anchor origin.
+func (w *WebCASResolver) getSignature(anchorOrigin *url.URL, resourceHash string) (string, error) {
+	webCASURL, err := w.webFingerClient.GetWebCASURL(
+		fmt.Sprintf("%s://%s", w.webFingerURIScheme, anchorOrigin.Host), resourceHash)
+	if err != nil {
+		return "", fmt.Errorf("failed to determine WebCAS URL via WebFinger: %w", err)
+	}
+
+	_, header, err := w.get(webCASURL)
+	if err != nil {
+		return "", err
+	}
+
+	return header.Get(linksetsig.HeaderName), nil
+}
+
+// hostSet is a set of hosts that may be accessed concurrently.
+type hostSet struct {
+	mutex sync.RWMutex
+	hosts map[string]struct{}
+}
+
+func newHostSet() *hostSet {
+	return &hostSet{hosts: make(map[string]struct{})}
+}
+
+func (s *hostSet) add(host string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.hosts[host] = struct{}{}
+}
+
+func (s *hostSet) contains(host string) bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	_, ok := s.hosts[host]
+
+	return ok
+}
+
+func isNativeLinkset(contentType string) bool {
+	mediaType := strings.Split(contentType, ";")[0]
+
+	return strings.EqualFold(strings.TrimSpace(mediaType), linkset.ContentTypeNative)
 }
@@ -29,6 +29,7 @@ import (
 	"github.com/trustbloc/orb/pkg/activitypub/resthandler"
 	"github.com/trustbloc/orb/pkg/activitypub/service/mocks"
 	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
+	"github.com/trustbloc/orb/pkg/anchor/linksetsig"
 	"github.com/trustbloc/orb/pkg/cas/extendedcasclient"
 	"github.com/trustbloc/orb/pkg/cas/ipfs"
 	resolvermocks "github.com/trustbloc/orb/pkg/cas/resolver/mocks"
@@ -36,6 +37,7 @@ import (
 	orberrors "github.com/trustbloc/orb/pkg/errors"
 	"github.com/trustbloc/orb/pkg/hashlink"
 	"github.com/trustbloc/orb/pkg/internal/testutil"
+	"github.com/trustbloc/orb/pkg/linkset"
 	orbmocks "github.com/trustbloc/orb/pkg/mocks"
 	"github.com/trustbloc/orb/pkg/store/cas"
 	"github.com/trustbloc/orb/pkg/webcas"
@@ -779,6 +781,276 @@ func TestResolver_Resolve(t *testing.T) {
 	})
 }
 
+func TestWebCASResolver_GetDataViaWebCASEndpoint(t *testing.T) {
+	const (
+		anchor = "hl:uEiDzUEQi2qRreCTfvp2AKmTaxuqUUZZNhbxe5RTBH59AWw"
+		author = "https://orb.domain1.com/services/orb"
+	)
+
+	var contentType, body, accept, signature string
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accept = r.Header.Get("Accept")
+
+		w.Header().Set("Content-Type", contentType)
+
+		if signature != "" {
+			w.Header().Set(linksetsig.HeaderName, signature)
+		}
+
+		_, errWrite := w.Write([]byte(body))
+		require.NoError(t, errWrite)
+	}))
+	defer testServer.Close()
+
+	resolver := createNewResolver(t, createInMemoryCAS(t), nil)
+
+	t.Run("JSON content", func(t *testing.T) {
+		contentType = "application/json"
+		body = sampleData
+
+		data, err := resolver.webCASResolver.GetDataViaWebCASEndpoint(testutil.MustParseURL(testServer.URL))
+		require.NoError(t, err)
+		require.Equal(t, sampleData, string(data))
+		require.Equal(t, "application/ld+json, application/linkset+json;q=0.9, application/linkset;q=0.8", accept)
+	})
+
+	t.Run("JSON linkset", func(t *testing.T) {
+		contentType = linkset.ContentTypeJSON
+		body = `{"linkset":[{"anchor":"` + anchor + `","author":[{"href":"` + author + `"}]}]}`
+
+		data, err := resolver.webCASResolver.GetDataViaWebCASEndpoint(testutil.MustParseURL(testServer.URL))
+		require.NoError(t, err)
+		require.Equal(t, body, string(data))
+	})
+
+	t.Run("Native linkset", func(t *testing.T) {
+		contentType = linkset.ContentTypeNative + "; charset=utf-8"
+		body = `<` + author + `>; rel="author"; anchor="` + anchor + `"`
+
+		data, err := resolver.webCASResolver.GetDataViaWebCASEndpoint(testutil.MustParseURL(testServer.URL))
+		require.NoError(t, err)
+		require.Equal(t, `{"linkset":[{"anchor":"`+anchor+`","author":[{"href":"`+author+`"}]}]}`, string(data))
+	})
+
+	t.Run("Invalid native linkset", func(t *testing.T) {
+		contentType = linkset.ContentTypeNative
+		body = `https://orb.domain1.com; rel="author"`
+
+		_, err := resolver.webCASResolver.GetDataViaWebCASEndpoint(testutil.MustParseURL(testServer.URL))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to parse linkset")
+	})
+
+	t.Run("Linkset signature", func(t *testing.T) {
+		anchorEvent := `{"attributedTo":"` + author + `","type":"AnchorEvent","url":"` + anchor + `"}`
+
+		contentType = "application/json"
+		body = anchorEvent
+
+		defer func() { signature = "" }()
+
+		verifier := &mockSignatureVerifier{}
+
+		webCASResolver := NewWebCASResolver(
+			transport.New(&http.Client{},
+				testutil.MustParseURL("https://example.com/keys/public-key"),
+				transport.DefaultSigner(), transport.DefaultSigner(), &apclientmocks.AuthTokenMgr{}),
+			webfingerclient.New(), "http", WithLinksetSignatureVerifier(verifier))
+
+		t.Run("No signature", func(t *testing.T) {
+			data, err := webCASResolver.GetDataViaWebCASEndpoint(testutil.MustParseURL(testServer.URL))
+			require.NoError(t, err)
+			require.Equal(t, anchorEvent, string(data))
+			require.Empty(t, verifier.jws)
+		})
+
+		t.Run("Valid signature", func(t *testing.T) {
+			signature = "eyJhbGciOiJFZERTQSJ9..c2ln"
+
+			data, err := webCASResolver.GetDataViaWebCASEndpoint(testutil.MustParseURL(testServer.URL))
+			require.NoError(t, err)
+			require.Equal(t, anchorEvent, string(data))
+			require.Equal(t, signature, verifier.jws)
+			require.Equal(t, anchorEvent, string(verifier.content))
+		})
+
+		t.Run("Invalid signature", func(t *testing.T) {
+			signature = "eyJhbGciOiJFZERTQSJ9..c2ln"
+			verifier.err = linksetsig.ErrInvalidSignature
+
+			_, err := webCASResolver.GetDataViaWebCASEndpoint(testutil.MustParseURL(testServer.URL))
+			require.ErrorIs(t, err, linksetsig.ErrInvalidSignature)
+		})
+
+		t.Run("Signature stripped from known signer", func(t *testing.T) {
+			signature = ""
+			verifier.err = nil
+
+			_, err := webCASResolver.GetDataViaWebCASEndpoint(testutil.MustParseURL(testServer.URL))
+			require.ErrorIs(t, err, linksetsig.ErrInvalidSignature)
+			require.Contains(t, err.Error(), "signature is missing")
+
+			contentType = linkset.ContentTypeNative
+			body = `<` + author + `>; rel="author"; anchor="` + anchor + `"`
+
+			defer func() {
+				contentType = "application/json"
+				body = anchorEvent
+			}()
+
+			_, err = webCASResolver.GetDataViaWebCASEndpoint(testutil.MustParseURL(testServer.URL))
+			require.ErrorIs(t, err, linksetsig.ErrInvalidSignature)
+		})
+
+		t.Run("Content isn't an anchor linkset", func(t *testing.T) {
+			body = sampleData
+
+			defer func() { body = anchorEvent }()
+
+			// Only anchor linksets are signed.
+			data, err := webCASResolver.GetDataViaWebCASEndpoint(testutil.MustParseURL(testServer.URL))
+			require.NoError(t, err)
+			require.Equal(t, sampleData, string(data))
+
+			signature = "eyJhbGciOiJFZERTQSJ9..c2ln"
+
+			defer func() { signature = "" }()
+
+			_, err = webCASResolver.GetDataViaWebCASEndpoint(testutil.MustParseURL(testServer.URL))
+			require.ErrorIs(t, err, linksetsig.ErrInvalidSignature)
+		})
+
+		t.Run("Signature required", func(t *testing.T) {
+			signature = ""
+
+			r := NewWebCASResolver(
+				transport.New(&http.Client{},
+					testutil.MustParseURL("https://example.com/keys/public-key"),
+					transport.DefaultSigner(), transport.DefaultSigner(), &apclientmocks.AuthTokenMgr{}),
+				webfingerclient.New(), "http", WithLinksetSignatureVerifier(&mockSignatureVerifier{}),
+				WithLinksetSignatureRequired(true))
+
+			_, err := r.GetDataViaWebCASEndpoint(testutil.MustParseURL(testServer.URL))
+			require.ErrorIs(t, err, linksetsig.ErrInvalidSignature)
+		})
+	})
+}
+
+func TestResolver_Resolve_IPFSLinksetSignature(t *testing.T) {
+	const signature = "eyJhbGciOiJFZERTQSJ9..c2ln"
+
+	router := mux.NewRouter()
+
+	// This test server is the anchor origin of the anchor event that's retrieved from IPFS.
+	originServer := httptest.NewServer(router)
+	defer originServer.Close()
+
+	anchorEvent := `{"attributedTo":"` + originServer.URL + `/services/orb","type":"AnchorEvent",` +
+		`"url":"hl:uEiDzUEQi2qRreCTfvp2AKmTaxuqUUZZNhbxe5RTBH59AWw"}`
+
+	originCAS := createInMemoryCAS(t)
+
+	hl, err := originCAS.Write([]byte(anchorEvent))
+	require.NoError(t, err)
+
+	rh, err := hashlink.GetResourceHashFromHashLink(hl)
+	require.NoError(t, err)
+
+	webCAS := webcas.New(&resthandler.Config{}, memstore.New(""), &mocks.SignatureVerifier{}, originCAS,
+		&apmocks.AuthTokenMgr{}, webcas.WithLinksetSignatures(&mockLinksetSignatures{signature: signature}))
+
+	router.HandleFunc(webCAS.Path(), webCAS.Handler())
+
+	operations, err := restapi.New(
+		&restapi.Config{BaseURL: originServer.URL, WebCASPath: "/cas"},
+		&restapi.Providers{CAS: originCAS, AnchorLinkStore: &orbmocks.AnchorLinkStore{}},
+	)
+	require.NoError(t, err)
+
+	router.HandleFunc(operations.GetRESTHandlers()[1].Path(), operations.GetRESTHandlers()[1].Handler())
+
+	var ipfsContent string
+
+	ipfsServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, ipfsContent)
+	}))
+	defer ipfsServer.Close()
+
+	newResolver := func(verifier linksetSignatureVerifier, opts ...WebCASResolverOpt) *Resolver {
+		webCASResolver := NewWebCASResolver(
+			transport.New(&http.Client{},
+				testutil.MustParseURL("https://example.com/keys/public-key"),
+				transport.DefaultSigner(), transport.DefaultSigner(), &apclientmocks.AuthTokenMgr{}),
+			webfingerclient.New(), httpScheme, append(opts, WithLinksetSignatureVerifier(verifier))...)
+
+		return New(createInMemoryCAS(t), ipfs.New(ipfsServer.URL, 5*time.Second, 0, &orbmocks.MetricsProvider{}),
+			webCASResolver, &orbmocks.MetricsProvider{})
+	}
+
+	t.Run("Signature retrieved from anchor origin", func(t *testing.T) {
+		ipfsContent = anchorEvent
+
+		verifier := &mockSignatureVerifier{}
+
+		data, localHL, err := newResolver(verifier).Resolve(nil, "ipfs:"+rh, nil)
+		require.NoError(t, err)
+		require.Equal(t, anchorEvent, string(data))
+		require.NotEmpty(t, localHL)
+		require.Equal(t, signature, verifier.jws)
+		require.Equal(t, anchorEvent, string(verifier.content))
+	})
+
+	t.Run("Invalid signature", func(t *testing.T) {
+		ipfsContent = anchorEvent
+
+		_, _, err := newResolver(&mockSignatureVerifier{err: linksetsig.ErrInvalidSignature}).
+			Resolve(nil, "ipfs:"+rh, nil)
+		require.ErrorIs(t, err, linksetsig.ErrInvalidSignature)
+	})
+
+	t.Run("Anchor origin unavailable", func(t *testing.T) {
+		ipfsContent = `{"attributedTo":"http://127.0.0.1:1/services/orb","type":"AnchorEvent",` +
+			`"url":"hl:uEiDzUEQi2qRreCTfvp2AKmTaxuqUUZZNhbxe5RTBH59AWw"}`
+
+		resourceHash, err := hashlink.New().CreateResourceHash([]byte(ipfsContent))
+		require.NoError(t, err)
+
+		verifier := &mockSignatureVerifier{}
+
+		data, _, err := newResolver(verifier).Resolve(nil, "ipfs:"+resourceHash, nil)
+		require.NoError(t, err)
+		require.Equal(t, ipfsContent, string(data))
+		require.Empty(t, verifier.jws)
+
+		_, _, err = newResolver(verifier, WithLinksetSignatureRequired(true)).Resolve(nil, "ipfs:"+resourceHash, nil)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "get linkset signature")
+	})
+}
+
+type mockSignatureVerifier struct {
+	content []byte
+	jws     string
+	err     error
+}
+
+func (m *mockSignatureVerifier) Verify(content []byte, jws string) error {
+	m.content = content
+	m.jws = jws
+
+	return m.err
+}
+
+type mockLinksetSignatures struct {
+	signature string
+}
+
+func (m *mockLinksetSignatures) Get(string) (string, error) {
+	return m.signature, nil
+}
+
 func createNewResolver(t *testing.T, casClient extendedcasclient.Client, ipfsReader ipfsReader) *Resolver {
 	t.Helper()
 
@@ -0,0 +1,262 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package circuitbreaker
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/trustbloc/edge-core/pkg/log"
+)
+
+var logger = log.New("circuitbreaker")
+
+// ErrOpen is returned when a request is rejected because the circuit breaker for the destination host is open.
+var ErrOpen = errors.New("circuit breaker is open")
+
+const (
+	defaultFailureThreshold = 5
+	defaultOpenTimeout      = 30 * time.Second
+)
+
+// State is the state of a circuit breaker.
+type State int
+
+const (
+	// StateClosed indicates that requests are allowed to the host.
+	StateClosed State = iota
+	// StateOpen indicates that requests to the host are rejected.
+	StateOpen
+	// StateHalfOpen indicates that a single trial request is allowed to the host in order to determine whether
+	// the host has recovered.
+	StateHalfOpen
+)
+
+// String returns the string representation of the state.
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(s))
+	}
+}
+
+// Config contains the circuit breaker configuration.
+type Config struct {
+	// FailureThreshold is the number of consecutive failures after which the circuit breaker
+	// for a host is opened.
+	FailureThreshold uint
+	// OpenTimeout is the amount of time that the circuit breaker remains open before a trial request
+	// is allowed to the host.
+	OpenTimeout time.Duration
+}
+
+type metricsProvider interface {
+	CircuitBreakerState(host string, state int)
+	CircuitBreakerRejected(host string)
+}
+
+// RoundTripper is an HTTP round tripper that maintains a circuit breaker for each destination host. When the
+// number of consecutive failed requests to a host reaches the failure threshold then the circuit breaker is
+// opened and subsequent requests to the host fail immediately with ErrOpen. After the open timeout, a single
+// trial request is allowed. If the trial request succeeds then the circuit breaker is closed, otherwise it
+// remains open for another timeout period. A request is considered to have failed if the transport returns
+// an error or if the response has a 5xx status code.
+type RoundTripper struct {
+	next     http.RoundTripper
+	cfg      Config
+	metrics  metricsProvider
+	mutex    sync.RWMutex
+	breakers map[string]*breaker
+	now      func() time.Time
+}
+
+// NewRoundTripper returns a new circuit breaker round tripper which wraps the given round tripper.
+func NewRoundTripper(next http.RoundTripper, cfg Config, metrics metricsProvider) *RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	if cfg.FailureThreshold == 0 {
+		cfg.FailureThreshold = defaultFailureThreshold
+	}
+
+	if cfg.OpenTimeout == 0 {
+		cfg.OpenTimeout = defaultOpenTimeout
+	}
+
+	return &RoundTripper{
+		next:     next,
+		cfg:      cfg,
+		metrics:  metrics,
+		breakers: make(map[string]*breaker),
+		now:      time.Now,
+	}
+}
+
+// RoundTrip executes the HTTP request unless the circuit breaker for the destination host is open.
+func (rt *RoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Host
+
+	b := rt.get(host)
+
+	if !b.allow(rt.now()) {
+		rt.metrics.CircuitBreakerRejected(host)
+
+		return nil, fmt.Errorf("%w for host [%s]", ErrOpen, host)
+	}
+
+	resp, err := rt.next.RoundTrip(req)
+
+	switch {
+	case err != nil && req.Context().Err() != nil:
+		// The request was cancelled by the caller so it says nothing about the health of the host.
+		b.release()
+	case err != nil || resp.StatusCode >= http.StatusInternalServerError:
+		b.failure(rt.now())
+	default:
+		b.success()
+	}
+
+	return resp, err
+}
+
+// State returns the state of the circuit breaker for the given host.
+func (rt *RoundTripper) State(host string) State {
+	rt.mutex.RLock()
+	b, ok := rt.breakers[host]
+	rt.mutex.RUnlock()
+
+	if !ok {
+		return StateClosed
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.state
+}
+
+func (rt *RoundTripper) get(host string) *breaker {
+	rt.mutex.RLock()
+	b, ok := rt.breakers[host]
+	rt.mutex.RUnlock()
+
+	if ok {
+		return b
+	}
+
+	rt.mutex.Lock()
+	defer rt.mutex.Unlock()
+
+	b, ok = rt.breakers[host]
+	if !ok {
+		b = &breaker{
+			host:             host,
+			failureThreshold: rt.cfg.FailureThreshold,
+			openTimeout:      rt.cfg.OpenTimeout,
+			metrics:          rt.metrics,
+		}
+
+		rt.breakers[host] = b
+
+		rt.metrics.CircuitBreakerState(host, int(StateClosed))
+	}
+
+	return b
+}
+
+type breaker struct {
+	mutex            sync.Mutex
+	host             string
+	failureThreshold uint
+	openTimeout      time.Duration
+	metrics          metricsProvider
+	state            State
+	failures         uint
+	openedTime       time.Time
+	trialInProgress  bool
+}
+
+func (b *breaker) allow(now time.Time) bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	switch b.state {
+	case StateOpen:
+		if now.Sub(b.openedTime) < b.openTimeout {
+			return false
+		}
+
+		b.setState(StateHalfOpen)
+
+		fallthrough
+	case StateHalfOpen:
+		if b.trialInProgress {
+			return false
+		}
+
+		b.trialInProgress = true
+
+		return true
+	default:
+		return true
+	}
+}
+
+func (b *breaker) success() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.failures = 0
+	b.trialInProgress = false
+
+	if b.state != StateClosed {
+		logger.Infof("Closing circuit breaker for host [%s]", b.host)
+
+		b.setState(StateClosed)
+	}
+}
+
+func (b *breaker) failure(now time.Time) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.failures++
+	b.trialInProgress = false
+
+	if b.state == StateHalfOpen || b.failures >= b.failureThreshold {
+		if b.state != StateOpen {
+			logger.Warnf("Opening circuit breaker for host [%s] after %d consecutive failure(s)",
+				b.host, b.failures)
+		}
+
+		b.openedTime = now
+		b.setState(StateOpen)
+	}
+}
+
+func (b *breaker) release() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.trialInProgress = false
+}
+
+func (b *breaker) setState(state State) {
+	b.state = state
+
+	b.metrics.CircuitBreakerState(b.host, int(state))
+}
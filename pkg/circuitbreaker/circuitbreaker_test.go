@@ -0,0 +1,224 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	host1 = "orb.domain1.com"
+	host2 = "orb.domain2.com"
+)
+
+func TestRoundTripper(t *testing.T) {
+	now := time.Now()
+
+	next := &mockRoundTripper{}
+	m := newMockMetrics()
+
+	rt := NewRoundTripper(next, Config{FailureThreshold: 2, OpenTimeout: time.Minute}, m)
+	rt.now = func() time.Time { return now }
+
+	require.Equal(t, StateClosed, rt.State(host1))
+
+	t.Run("Success", func(t *testing.T) {
+		_, err := rt.RoundTrip(newRequest(t, host1))
+		require.NoError(t, err)
+		require.Equal(t, StateClosed, rt.State(host1))
+	})
+
+	t.Run("Open after consecutive failures", func(t *testing.T) {
+		next.setErr(errors.New("connection refused"))
+
+		_, err := rt.RoundTrip(newRequest(t, host1))
+		require.Error(t, err)
+		require.Equal(t, StateClosed, rt.State(host1))
+
+		_, err = rt.RoundTrip(newRequest(t, host1))
+		require.Error(t, err)
+		require.Equal(t, StateOpen, rt.State(host1))
+		require.Equal(t, int(StateOpen), m.state(host1))
+
+		next.setErr(nil)
+
+		_, err = rt.RoundTrip(newRequest(t, host1))
+		require.True(t, errors.Is(err, ErrOpen))
+		require.Equal(t, 1, m.rejected(host1))
+
+		// Requests to other hosts are unaffected.
+		_, err = rt.RoundTrip(newRequest(t, host2))
+		require.NoError(t, err)
+		require.Equal(t, StateClosed, rt.State(host2))
+	})
+
+	t.Run("Half-open trial fails", func(t *testing.T) {
+		now = now.Add(time.Minute)
+
+		next.setStatus(http.StatusServiceUnavailable)
+
+		_, err := rt.RoundTrip(newRequest(t, host1))
+		require.NoError(t, err)
+		require.Equal(t, StateOpen, rt.State(host1))
+
+		_, err = rt.RoundTrip(newRequest(t, host1))
+		require.True(t, errors.Is(err, ErrOpen))
+	})
+
+	t.Run("Half-open trial succeeds", func(t *testing.T) {
+		now = now.Add(time.Minute)
+
+		next.setStatus(http.StatusOK)
+
+		_, err := rt.RoundTrip(newRequest(t, host1))
+		require.NoError(t, err)
+		require.Equal(t, StateClosed, rt.State(host1))
+		require.Equal(t, int(StateClosed), m.state(host1))
+	})
+
+	t.Run("Cancelled request", func(t *testing.T) {
+		next.setErr(context.Canceled)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		req := newRequest(t, host1).WithContext(ctx)
+
+		for i := 0; i < 3; i++ {
+			_, err := rt.RoundTrip(req)
+			require.Error(t, err)
+		}
+
+		require.Equal(t, StateClosed, rt.State(host1))
+	})
+}
+
+func TestBreaker_HalfOpen(t *testing.T) {
+	now := time.Now()
+
+	b := &breaker{host: host1, failureThreshold: 1, openTimeout: time.Minute, metrics: newMockMetrics()}
+
+	b.failure(now)
+	require.Equal(t, StateOpen, b.state)
+	require.False(t, b.allow(now))
+
+	now = now.Add(time.Minute)
+
+	require.True(t, b.allow(now))
+	require.Equal(t, StateHalfOpen, b.state)
+	require.False(t, b.allow(now), "only one trial request should be allowed")
+
+	b.release()
+	require.True(t, b.allow(now))
+}
+
+func TestState_String(t *testing.T) {
+	require.Equal(t, "closed", StateClosed.String())
+	require.Equal(t, "open", StateOpen.String())
+	require.Equal(t, "half-open", StateHalfOpen.String())
+	require.Equal(t, "unknown(5)", State(5).String())
+}
+
+func TestNewRoundTripper_Defaults(t *testing.T) {
+	rt := NewRoundTripper(nil, Config{}, newMockMetrics())
+	require.Equal(t, http.DefaultTransport, rt.next)
+	require.Equal(t, uint(defaultFailureThreshold), rt.cfg.FailureThreshold)
+	require.Equal(t, defaultOpenTimeout, rt.cfg.OpenTimeout)
+}
+
+func newRequest(t *testing.T, host string) *http.Request {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, "https://"+host+"/services/orb", nil)
+	require.NoError(t, err)
+
+	return req
+}
+
+type mockRoundTripper struct {
+	mutex  sync.Mutex
+	err    error
+	status int
+}
+
+func (m *mockRoundTripper) setErr(err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.err = err
+}
+
+func (m *mockRoundTripper) setStatus(status int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.status = status
+}
+
+func (m *mockRoundTripper) RoundTrip(*http.Request) (*http.Response, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	status := m.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	return &http.Response{StatusCode: status, Body: http.NoBody}, nil
+}
+
+type mockMetrics struct {
+	mutex         sync.Mutex
+	states        map[string]int
+	rejectedCount map[string]int
+}
+
+func newMockMetrics() *mockMetrics {
+	return &mockMetrics{
+		states:        make(map[string]int),
+		rejectedCount: make(map[string]int),
+	}
+}
+
+func (m *mockMetrics) CircuitBreakerState(host string, state int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.states[host] = state
+}
+
+func (m *mockMetrics) CircuitBreakerRejected(host string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.rejectedCount[host]++
+}
+
+func (m *mockMetrics) state(host string) int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.states[host]
+}
+
+func (m *mockMetrics) rejected(host string) int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.rejectedCount[host]
+}
@@ -0,0 +1,188 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package compression
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/trustbloc/edge-core/pkg/log"
+)
+
+var logger = log.New("compression")
+
+const (
+	// GZIP is the name of the GZIP compression algorithm.
+	GZIP = "GZIP"
+	// ZSTD is the name of the Zstandard compression algorithm.
+	ZSTD = "ZSTD"
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+type algorithm interface {
+	compress(data []byte) ([]byte, error)
+	decompress(data []byte) ([]byte, error)
+}
+
+// Provider compresses and decompresses batch files. Files are compressed with the requested algorithm. When a
+// file is decompressed, the algorithm is determined from the header (magic number) of the file, so files that
+// were compressed with any of the supported algorithms may be read, regardless of the algorithm that is
+// configured in the protocol.
+type Provider struct {
+	algorithms map[string]algorithm
+}
+
+// New returns a new compression provider which supports the GZIP and ZSTD algorithms.
+func New() *Provider {
+	return &Provider{
+		algorithms: map[string]algorithm{
+			GZIP: &gzipAlgorithm{},
+			ZSTD: newZstdAlgorithm(),
+		},
+	}
+}
+
+// IsSupported returns true if the given compression algorithm is supported.
+func IsSupported(alg string) bool {
+	switch strings.ToUpper(alg) {
+	case GZIP, ZSTD:
+		return true
+	default:
+		return false
+	}
+}
+
+// Compress compresses the given data using the given algorithm.
+func (p *Provider) Compress(alg string, data []byte) ([]byte, error) {
+	a, err := p.get(alg)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.compress(data)
+}
+
+// Decompress decompresses the given data. The algorithm is determined from the header of the data. If the header
+// doesn't match any of the supported algorithms then the given algorithm is used.
+func (p *Provider) Decompress(alg string, data []byte) ([]byte, error) {
+	detectedAlg := detect(data)
+	if detectedAlg != "" && !strings.EqualFold(detectedAlg, alg) {
+		logger.Debugf("Decompressing data with algorithm [%s] instead of [%s]", detectedAlg, alg)
+
+		alg = detectedAlg
+	}
+
+	a, err := p.get(alg)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.decompress(data)
+}
+
+func (p *Provider) get(alg string) (algorithm, error) {
+	a, ok := p.algorithms[strings.ToUpper(alg)]
+	if !ok {
+		return nil, fmt.Errorf("compression algorithm '%s' not supported", alg)
+	}
+
+	return a, nil
+}
+
+func detect(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, zstdMagic):
+		return ZSTD
+	case bytes.HasPrefix(data, gzipMagic):
+		return GZIP
+	default:
+		return ""
+	}
+}
+
+type gzipAlgorithm struct{}
+
+func (a *gzipAlgorithm) compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+
+	zw := gzip.NewWriter(&buf)
+
+	if _, err := zw.Write(data); err != nil {
+		return nil, fmt.Errorf("gzip compress: %w", err)
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("gzip compress: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (a *gzipAlgorithm) decompress(data []byte) ([]byte, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("gzip decompress: %w", err)
+	}
+
+	defer func() {
+		if e := zr.Close(); e != nil {
+			logger.Warnf("Error closing gzip reader: %s", e)
+		}
+	}()
+
+	result, err := ioutil.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("gzip decompress: %w", err)
+	}
+
+	return result, nil
+}
+
+// zstdAlgorithm uses a shared encoder and decoder since EncodeAll and DecodeAll may be called concurrently.
+type zstdAlgorithm struct {
+	encoder *zstd.Encoder
+	decoder *zstd.Decoder
+}
+
+func newZstdAlgorithm() *zstdAlgorithm {
+	// The encoder and decoder only fail to initialize if invalid options are provided.
+	encoder, err := zstd.NewWriter(nil)
+	if err != nil {
+		panic(err)
+	}
+
+	decoder, err := zstd.NewReader(nil)
+	if err != nil {
+		panic(err)
+	}
+
+	return &zstdAlgorithm{
+		encoder: encoder,
+		decoder: decoder,
+	}
+}
+
+func (a *zstdAlgorithm) compress(data []byte) ([]byte, error) {
+	return a.encoder.EncodeAll(data, nil), nil
+}
+
+func (a *zstdAlgorithm) decompress(data []byte) ([]byte, error) {
+	result, err := a.decoder.DecodeAll(data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("zstd decompress: %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,73 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package compression
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var data = []byte(`{"operations":{"create":[{"suffixData":{"deltaHash":"EiA","recoveryCommitment":"EiB"}}]}}`)
+
+func TestProvider(t *testing.T) {
+	p := New()
+
+	for _, alg := range []string{GZIP, ZSTD, "gzip", "zstd"} {
+		alg := alg
+
+		t.Run(alg, func(t *testing.T) {
+			compressed, err := p.Compress(alg, data)
+			require.NoError(t, err)
+			require.NotEqual(t, data, compressed)
+
+			decompressed, err := p.Decompress(alg, compressed)
+			require.NoError(t, err)
+			require.Equal(t, data, decompressed)
+		})
+	}
+
+	t.Run("algorithm detected from header", func(t *testing.T) {
+		compressed, err := p.Compress(ZSTD, data)
+		require.NoError(t, err)
+
+		decompressed, err := p.Decompress(GZIP, compressed)
+		require.NoError(t, err)
+		require.Equal(t, data, decompressed)
+
+		compressed, err = p.Compress(GZIP, data)
+		require.NoError(t, err)
+
+		decompressed, err = p.Decompress(ZSTD, compressed)
+		require.NoError(t, err)
+		require.Equal(t, data, decompressed)
+	})
+
+	t.Run("unsupported algorithm", func(t *testing.T) {
+		_, err := p.Compress("alg", data)
+		require.EqualError(t, err, "compression algorithm 'alg' not supported")
+
+		_, err = p.Decompress("alg", data)
+		require.EqualError(t, err, "compression algorithm 'alg' not supported")
+	})
+
+	t.Run("invalid data", func(t *testing.T) {
+		_, err := p.Decompress(GZIP, []byte("invalid"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "gzip decompress")
+
+		_, err = p.Decompress(ZSTD, append(zstdMagic, []byte("invalid")...))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "zstd decompress")
+	})
+}
+
+func TestIsSupported(t *testing.T) {
+	require.True(t, IsSupported(GZIP))
+	require.True(t, IsSupported("zstd"))
+	require.False(t, IsSupported("alg"))
+}
@@ -9,6 +9,7 @@ package config
 import (
 	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
 
+	"github.com/trustbloc/orb/pkg/document/composition"
 	"github.com/trustbloc/orb/pkg/store/operation/unpublished"
 )
 
@@ -23,4 +24,12 @@ type Sidetree struct {
 
 	IncludeUnpublishedOperations bool
 	IncludePublishedOperations   bool
+
+	// CompressionAlgorithm overrides the compression algorithm of the protocol for the batch files
+	// that are written by this server. Files are always decompressed with the algorithm in the file header.
+	CompressionAlgorithm string
+
+	// CompositionErrors (optional) records the operations that can't be applied to a document, and the
+	// documents that can't be transformed, so that they may be reviewed by an operator.
+	CompositionErrors *composition.Recorder
 }
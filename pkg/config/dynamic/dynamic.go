@@ -0,0 +1,377 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dynamic
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/lifecycle"
+)
+
+var logger = log.New("dynamic-config")
+
+const (
+	keyPrefix = "dynamic-config_"
+
+	defaultRefreshInterval = 10 * time.Second
+)
+
+// ErrParameterNotFound is returned when the requested parameter has not been registered.
+var ErrParameterNotFound = errors.New("parameter not found")
+
+// Parameter defines a configuration parameter that may be updated while the server is running.
+type Parameter struct {
+	// Name is the unique name of the parameter.
+	Name string
+	// Description is a human readable description of the parameter.
+	Description string
+	// DefaultValue is the value of the parameter if it has not been set in the config store.
+	DefaultValue string
+	// Validate (optional) validates a new value of the parameter before it is stored.
+	Validate func(value string) error
+}
+
+// Value contains the current value of a parameter.
+type Value struct {
+	Name         string `json:"name"`
+	Value        string `json:"value"`
+	DefaultValue string `json:"defaultValue"`
+	Description  string `json:"description,omitempty"`
+}
+
+// ChangeHandler is invoked with the new value when a parameter changes.
+type ChangeHandler func(value string)
+
+// Manager manages configuration parameters that may be updated at runtime. The values are stored in the
+// config store (which is shared by all server instances in the domain) and subscribers are notified
+// when a value changes. Values that are updated by another server instance are picked up on the next refresh.
+type Manager struct {
+	*lifecycle.Lifecycle
+
+	store           storage.Store
+	refreshInterval time.Duration
+	params          map[string]*param
+	mutex           sync.RWMutex
+	done            chan struct{}
+}
+
+type param struct {
+	*Parameter
+
+	value    string
+	handlers []ChangeHandler
+}
+
+// Option is an option for the dynamic configuration manager.
+type Option func(m *Manager)
+
+// WithRefreshInterval sets the interval at which parameter values are reloaded from the config store.
+func WithRefreshInterval(interval time.Duration) Option {
+	return func(m *Manager) {
+		m.refreshInterval = interval
+	}
+}
+
+// New returns a new dynamic configuration manager. Start must be called in order for values updated by
+// other server instances to be picked up.
+func New(store storage.Store, opts ...Option) *Manager {
+	m := &Manager{
+		store:           store,
+		refreshInterval: defaultRefreshInterval,
+		params:          make(map[string]*param),
+		done:            make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	m.Lifecycle = lifecycle.New("dynamic-config",
+		lifecycle.WithStart(m.start),
+		lifecycle.WithStop(m.stop),
+	)
+
+	return m
+}
+
+// Register registers a parameter that may be updated at runtime. The current value of the parameter
+// is loaded from the config store.
+func (m *Manager) Register(p *Parameter) error {
+	if p.Name == "" {
+		return errors.New("parameter name is required")
+	}
+
+	value, err := m.load(p)
+	if err != nil {
+		return err
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if _, exists := m.params[p.Name]; exists {
+		return fmt.Errorf("parameter [%s] is already registered", p.Name)
+	}
+
+	m.params[p.Name] = &param{Parameter: p, value: value}
+
+	logger.Debugf("Registered parameter [%s] with value [%s]", p.Name, value)
+
+	return nil
+}
+
+// Subscribe registers a handler that is notified when the given parameter changes. The handler is
+// immediately invoked with the current value.
+func (m *Manager) Subscribe(name string, handler ChangeHandler) error {
+	m.mutex.Lock()
+
+	p, ok := m.params[name]
+	if !ok {
+		m.mutex.Unlock()
+
+		return fmt.Errorf("subscribe to [%s]: %w", name, ErrParameterNotFound)
+	}
+
+	p.handlers = append(p.handlers, handler)
+	value := p.value
+
+	m.mutex.Unlock()
+
+	handler(value)
+
+	return nil
+}
+
+// Get returns the current value of the given parameter.
+func (m *Manager) Get(name string) (string, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	p, ok := m.params[name]
+	if !ok {
+		return "", fmt.Errorf("get [%s]: %w", name, ErrParameterNotFound)
+	}
+
+	return p.value, nil
+}
+
+// GetAll returns the current values of all registered parameters, sorted by name.
+func (m *Manager) GetAll() []*Value {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	values := make([]*Value, 0, len(m.params))
+
+	for _, p := range m.params {
+		values = append(values, &Value{
+			Name:         p.Name,
+			Value:        p.value,
+			DefaultValue: p.DefaultValue,
+			Description:  p.Description,
+		})
+	}
+
+	sort.Slice(values, func(i, j int) bool {
+		return values[i].Name < values[j].Name
+	})
+
+	return values
+}
+
+// Update validates and stores the given parameter values and notifies subscribers of the changes.
+// Either all of the values are updated or none are.
+func (m *Manager) Update(values map[string]string) error {
+	var operations []storage.Operation
+
+	for name, value := range values {
+		p, err := m.get(name)
+		if err != nil {
+			return orberrors.NewBadRequest(fmt.Errorf("update [%s]: %w", name, err))
+		}
+
+		if p.Validate != nil {
+			if err := p.Validate(value); err != nil {
+				return orberrors.NewBadRequest(fmt.Errorf("invalid value for [%s]: %w", name, err))
+			}
+		}
+
+		valueBytes, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("marshal value for [%s]: %w", name, err)
+		}
+
+		operations = append(operations, storage.Operation{
+			Key:   keyPrefix + name,
+			Value: valueBytes,
+		})
+	}
+
+	if len(operations) == 0 {
+		return nil
+	}
+
+	if err := m.store.Batch(operations); err != nil {
+		return orberrors.NewTransient(fmt.Errorf("store parameters: %w", err))
+	}
+
+	for name, value := range values {
+		m.set(name, value)
+	}
+
+	return nil
+}
+
+// PositiveInt validates that the given value is a positive integer.
+func PositiveInt(value string) error {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("invalid integer [%s]: %w", value, err)
+	}
+
+	if n <= 0 {
+		return fmt.Errorf("value must be greater than 0: %d", n)
+	}
+
+	return nil
+}
+
+// NonNegativeInt validates that the given value is an integer that is greater than or equal to 0.
+func NonNegativeInt(value string) error {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("invalid integer [%s]: %w", value, err)
+	}
+
+	if n < 0 {
+		return fmt.Errorf("value must not be negative: %d", n)
+	}
+
+	return nil
+}
+
+// PositiveDuration validates that the given value is a positive duration, for example "10s" or "1m".
+func PositiveDuration(value string) error {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("invalid duration [%s]: %w", value, err)
+	}
+
+	if d <= 0 {
+		return fmt.Errorf("duration must be greater than 0: %s", d)
+	}
+
+	return nil
+}
+
+func (m *Manager) get(name string) (*param, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	p, ok := m.params[name]
+	if !ok {
+		return nil, ErrParameterNotFound
+	}
+
+	return p, nil
+}
+
+// set sets the value of the given parameter and, if the value has changed, notifies the subscribers.
+func (m *Manager) set(name, value string) {
+	m.mutex.Lock()
+
+	p, ok := m.params[name]
+	if !ok || p.value == value {
+		m.mutex.Unlock()
+
+		return
+	}
+
+	p.value = value
+
+	handlers := make([]ChangeHandler, len(p.handlers))
+	copy(handlers, p.handlers)
+
+	m.mutex.Unlock()
+
+	logger.Infof("Parameter [%s] changed to [%s]", name, value)
+
+	for _, handler := range handlers {
+		handler(value)
+	}
+}
+
+func (m *Manager) load(p *Parameter) (string, error) {
+	valueBytes, err := m.store.Get(keyPrefix + p.Name)
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return p.DefaultValue, nil
+		}
+
+		return "", orberrors.NewTransient(fmt.Errorf("load parameter [%s]: %w", p.Name, err))
+	}
+
+	var value string
+
+	if err := json.Unmarshal(valueBytes, &value); err != nil {
+		return "", fmt.Errorf("unmarshal parameter [%s]: %w", p.Name, err)
+	}
+
+	return value, nil
+}
+
+func (m *Manager) refresh() {
+	m.mutex.RLock()
+
+	params := make([]*Parameter, 0, len(m.params))
+	for _, p := range m.params {
+		params = append(params, p.Parameter)
+	}
+
+	m.mutex.RUnlock()
+
+	for _, p := range params {
+		value, err := m.load(p)
+		if err != nil {
+			logger.Warnf("Error refreshing parameter [%s]: %s", p.Name, err)
+
+			continue
+		}
+
+		m.set(p.Name, value)
+	}
+}
+
+func (m *Manager) start() {
+	go func() {
+		logger.Infof("Started dynamic configuration manager.")
+
+		for {
+			select {
+			case <-time.After(m.refreshInterval):
+				m.refresh()
+			case <-m.done:
+				logger.Debugf("Stopped dynamic configuration manager.")
+
+				return
+			}
+		}
+	}()
+}
+
+func (m *Manager) stop() {
+	close(m.done)
+}
@@ -0,0 +1,208 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dynamic
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	storemocks "github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+const (
+	configStoreName = "orb-config"
+
+	pageSizeParam = "activitypub-page-size"
+	timeoutParam  = "batch-timeout"
+)
+
+func TestManager(t *testing.T) {
+	configStore, err := mem.NewProvider().OpenStore(configStoreName)
+	require.NoError(t, err)
+
+	m := New(configStore)
+	require.NotNil(t, m)
+
+	require.NoError(t, m.Register(&Parameter{
+		Name:         pageSizeParam,
+		Description:  "ActivityPub page size",
+		DefaultValue: "50",
+		Validate:     PositiveInt,
+	}))
+
+	require.NoError(t, m.Register(&Parameter{
+		Name:         timeoutParam,
+		DefaultValue: "2s",
+		Validate:     PositiveDuration,
+	}))
+
+	t.Run("register errors", func(t *testing.T) {
+		require.EqualError(t, m.Register(&Parameter{}), "parameter name is required")
+		require.EqualError(t, m.Register(&Parameter{Name: pageSizeParam}),
+			"parameter [activitypub-page-size] is already registered")
+	})
+
+	t.Run("get", func(t *testing.T) {
+		value, err := m.Get(pageSizeParam)
+		require.NoError(t, err)
+		require.Equal(t, "50", value)
+
+		_, err = m.Get("unknown")
+		require.True(t, errors.Is(err, ErrParameterNotFound))
+
+		values := m.GetAll()
+		require.Len(t, values, 2)
+		require.Equal(t, timeoutParam, values[1].Name)
+		require.Equal(t, "ActivityPub page size", values[0].Description)
+	})
+
+	t.Run("subscribe and update", func(t *testing.T) {
+		var (
+			mutex  sync.Mutex
+			values []string
+		)
+
+		require.NoError(t, m.Subscribe(pageSizeParam, func(value string) {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			values = append(values, value)
+		}))
+
+		require.NoError(t, m.Update(map[string]string{pageSizeParam: "100", timeoutParam: "5s"}))
+
+		// Updating to the same value should not result in a notification.
+		require.NoError(t, m.Update(map[string]string{pageSizeParam: "100"}))
+		require.NoError(t, m.Update(nil))
+
+		mutex.Lock()
+		require.Equal(t, []string{"50", "100"}, values)
+		mutex.Unlock()
+
+		value, err := m.Get(timeoutParam)
+		require.NoError(t, err)
+		require.Equal(t, "5s", value)
+
+		err = m.Subscribe("unknown", func(string) {})
+		require.True(t, errors.Is(err, ErrParameterNotFound))
+	})
+
+	t.Run("update errors", func(t *testing.T) {
+		err := m.Update(map[string]string{"unknown": "1"})
+		require.True(t, errors.Is(err, ErrParameterNotFound))
+		require.True(t, orberrors.IsBadRequest(err))
+
+		err = m.Update(map[string]string{pageSizeParam: "-1"})
+		require.True(t, orberrors.IsBadRequest(err))
+		require.Contains(t, err.Error(), "value must be greater than 0")
+
+		err = m.Update(map[string]string{timeoutParam: "xxx"})
+		require.True(t, orberrors.IsBadRequest(err))
+		require.Contains(t, err.Error(), "invalid duration")
+	})
+
+	t.Run("value loaded from store", func(t *testing.T) {
+		m2 := New(configStore)
+
+		require.NoError(t, m2.Register(&Parameter{Name: pageSizeParam, DefaultValue: "50"}))
+
+		value, err := m2.Get(pageSizeParam)
+		require.NoError(t, err)
+		require.Equal(t, "100", value)
+	})
+}
+
+func TestManager_Refresh(t *testing.T) {
+	configStore, err := mem.NewProvider().OpenStore(configStoreName)
+	require.NoError(t, err)
+
+	m1 := New(configStore, WithRefreshInterval(10*time.Millisecond))
+	m2 := New(configStore, WithRefreshInterval(10*time.Millisecond))
+
+	require.NoError(t, m1.Register(&Parameter{Name: pageSizeParam, DefaultValue: "50"}))
+	require.NoError(t, m2.Register(&Parameter{Name: pageSizeParam, DefaultValue: "50"}))
+
+	changed := make(chan string, 2)
+
+	require.NoError(t, m2.Subscribe(pageSizeParam, func(value string) {
+		changed <- value
+	}))
+
+	require.Equal(t, "50", <-changed)
+
+	m2.Start()
+	defer m2.Stop()
+
+	require.NoError(t, m1.Update(map[string]string{pageSizeParam: "25"}))
+
+	select {
+	case value := <-changed:
+		require.Equal(t, "25", value)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for change notification")
+	}
+}
+
+func TestManager_StoreError(t *testing.T) {
+	errExpected := errors.New("injected store error")
+
+	t.Run("get error", func(t *testing.T) {
+		s := &storemocks.Store{}
+		s.GetReturns(nil, errExpected)
+
+		m := New(s)
+
+		err := m.Register(&Parameter{Name: pageSizeParam})
+		require.True(t, errors.Is(err, errExpected))
+		require.True(t, orberrors.IsTransient(err))
+	})
+
+	t.Run("unmarshal error", func(t *testing.T) {
+		s := &storemocks.Store{}
+		s.GetReturns([]byte("{"), nil)
+
+		m := New(s)
+
+		err := m.Register(&Parameter{Name: pageSizeParam})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal parameter")
+	})
+
+	t.Run("batch error", func(t *testing.T) {
+		s := &storemocks.Store{}
+		s.GetReturns([]byte(`"50"`), nil)
+		s.BatchReturns(errExpected)
+
+		m := New(s)
+
+		require.NoError(t, m.Register(&Parameter{Name: pageSizeParam}))
+
+		err := m.Update(map[string]string{pageSizeParam: "10"})
+		require.True(t, errors.Is(err, errExpected))
+		require.True(t, orberrors.IsTransient(err))
+	})
+}
+
+func TestValidators(t *testing.T) {
+	require.NoError(t, PositiveInt("10"))
+	require.Error(t, PositiveInt("0"))
+	require.Error(t, PositiveInt("x"))
+
+	require.NoError(t, NonNegativeInt("0"))
+	require.Error(t, NonNegativeInt("-1"))
+	require.Error(t, NonNegativeInt("x"))
+
+	require.NoError(t, PositiveDuration("10s"))
+	require.Error(t, PositiveDuration("0s"))
+	require.Error(t, PositiveDuration("x"))
+}
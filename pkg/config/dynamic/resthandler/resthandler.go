@@ -0,0 +1,166 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/trustbloc/edge-core/pkg/log"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+
+	"github.com/trustbloc/orb/pkg/config/dynamic"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+const endpoint = "/config"
+
+const (
+	badRequestResponse          = "Bad Request."
+	notFoundResponse            = "Not Found."
+	internalServerErrorResponse = "Internal Server Error."
+)
+
+var logger = log.New("dynamic-config-rest-handler")
+
+type configManager interface {
+	GetAll() []*dynamic.Value
+	Update(values map[string]string) error
+}
+
+// Reader implements a REST handler that returns the current values of the dynamic configuration parameters.
+type Reader struct {
+	mgr     configManager
+	marshal func(v interface{}) ([]byte, error)
+}
+
+// NewReader returns a new dynamic configuration reader.
+func NewReader(mgr configManager) *Reader {
+	return &Reader{
+		mgr:     mgr,
+		marshal: json.Marshal,
+	}
+}
+
+// Path returns the HTTP REST endpoint for the dynamic configuration service.
+func (h *Reader) Path() string {
+	return endpoint
+}
+
+// Method returns the HTTP method, which is always GET.
+func (h *Reader) Method() string {
+	return http.MethodGet
+}
+
+// Handler returns the HTTP REST handle for the dynamic configuration service.
+func (h *Reader) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Reader) handle(w http.ResponseWriter, _ *http.Request) {
+	valuesBytes, err := h.marshal(h.mgr.GetAll())
+	if err != nil {
+		logger.Errorf("[%s] Error marshalling configuration: %s", endpoint, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	writeResponse(w, http.StatusOK, valuesBytes)
+}
+
+// Writer implements a REST handler that updates the values of dynamic configuration parameters.
+// The request is a JSON object of parameter names to values, for example {"activitypub-page-size":"100"}.
+type Writer struct {
+	mgr     configManager
+	readAll func(r io.Reader) ([]byte, error)
+}
+
+// NewWriter returns a new dynamic configuration writer.
+func NewWriter(mgr configManager) *Writer {
+	return &Writer{
+		mgr:     mgr,
+		readAll: ioutil.ReadAll,
+	}
+}
+
+// Path returns the HTTP REST endpoint for the dynamic configuration service.
+func (h *Writer) Path() string {
+	return endpoint
+}
+
+// Method returns the HTTP method, which is always POST.
+func (h *Writer) Method() string {
+	return http.MethodPost
+}
+
+// Handler returns the HTTP REST handle for the dynamic configuration service.
+func (h *Writer) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Writer) handle(w http.ResponseWriter, req *http.Request) {
+	reqBytes, err := h.readAll(req.Body)
+	if err != nil {
+		logger.Errorf("[%s] Error reading request body: %s", endpoint, err)
+
+		writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	values := make(map[string]string)
+
+	if err := json.Unmarshal(reqBytes, &values); err != nil {
+		logger.Infof("[%s] Invalid configuration request: %s", endpoint, err)
+
+		writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	if err := h.mgr.Update(values); err != nil {
+		switch {
+		case errors.Is(err, dynamic.ErrParameterNotFound):
+			logger.Infof("[%s] Error updating configuration: %s", endpoint, err)
+
+			writeResponse(w, http.StatusNotFound, []byte(notFoundResponse))
+		case orberrors.IsBadRequest(err):
+			logger.Infof("[%s] Error updating configuration: %s", endpoint, err)
+
+			writeResponse(w, http.StatusBadRequest, []byte(err.Error()))
+		default:
+			logger.Errorf("[%s] Error updating configuration: %s", endpoint, err)
+
+			writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+		}
+
+		return
+	}
+
+	logger.Debugf("[%s] Updated configuration: %s", endpoint, reqBytes)
+
+	writeResponse(w, http.StatusOK, nil)
+}
+
+func writeResponse(w http.ResponseWriter, status int, body []byte) {
+	w.WriteHeader(status)
+
+	if len(body) > 0 {
+		if _, err := w.Write(body); err != nil {
+			logger.Warnf("[%s] Unable to write response: %s", endpoint, err)
+
+			return
+		}
+
+		logger.Debugf("[%s] Wrote response: %s", endpoint, body)
+	}
+}
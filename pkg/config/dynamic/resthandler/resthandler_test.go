@@ -0,0 +1,122 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/config/dynamic"
+	"github.com/trustbloc/orb/pkg/internal/testutil/httptestutil"
+	storemocks "github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+const pageSizeParam = "activitypub-page-size"
+
+func TestReader(t *testing.T) {
+	mgr := newManager(t)
+
+	h := NewReader(mgr)
+	require.Equal(t, endpoint, h.Path())
+	require.Equal(t, http.MethodGet, h.Method())
+	require.NotNil(t, h.Handler())
+
+	t.Run("success", func(t *testing.T) {
+		status, respBytes := httptestutil.Get(t, h.Handler(), endpoint)
+		require.Equal(t, http.StatusOK, status)
+
+		var values []*dynamic.Value
+		require.NoError(t, json.Unmarshal(respBytes, &values))
+		require.Len(t, values, 1)
+		require.Equal(t, pageSizeParam, values[0].Name)
+		require.Equal(t, "50", values[0].Value)
+	})
+
+	t.Run("marshal error", func(t *testing.T) {
+		h := NewReader(mgr)
+		h.marshal = func(v interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		status, _ := httptestutil.Get(t, h.Handler(), endpoint)
+		require.Equal(t, http.StatusInternalServerError, status)
+	})
+}
+
+func TestWriter(t *testing.T) {
+	h := NewWriter(newManager(t))
+	require.Equal(t, endpoint, h.Path())
+	require.Equal(t, http.MethodPost, h.Method())
+	require.NotNil(t, h.Handler())
+
+	t.Run("success", func(t *testing.T) {
+		mgr := newManager(t)
+
+		status, _ := httptestutil.Post(t, NewWriter(mgr).Handler(), endpoint, []byte(`{"activitypub-page-size":"100"}`))
+		require.Equal(t, http.StatusOK, status)
+
+		value, err := mgr.Get(pageSizeParam)
+		require.NoError(t, err)
+		require.Equal(t, "100", value)
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		status, _ := httptestutil.Post(t, h.Handler(), endpoint, []byte(`{`))
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		status, _ := httptestutil.Post(t, h.Handler(), endpoint, []byte(`{"activitypub-page-size":"-1"}`))
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("unknown parameter", func(t *testing.T) {
+		status, _ := httptestutil.Post(t, h.Handler(), endpoint, []byte(`{"unknown":"1"}`))
+		require.Equal(t, http.StatusNotFound, status)
+	})
+
+	t.Run("read error", func(t *testing.T) {
+		h := NewWriter(newManager(t))
+		h.readAll = func(r io.Reader) ([]byte, error) { return nil, errors.New("injected read error") }
+
+		status, _ := httptestutil.Post(t, h.Handler(), endpoint, []byte(`{}`))
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		s := &storemocks.Store{}
+		s.GetReturns([]byte(`"50"`), nil)
+		s.BatchReturns(errors.New("injected batch error"))
+
+		mgr := dynamic.New(s)
+		require.NoError(t, mgr.Register(&dynamic.Parameter{Name: pageSizeParam}))
+
+		status, _ := httptestutil.Post(t, NewWriter(mgr).Handler(), endpoint, []byte(`{"activitypub-page-size":"100"}`))
+		require.Equal(t, http.StatusInternalServerError, status)
+	})
+}
+
+func newManager(t *testing.T) *dynamic.Manager {
+	t.Helper()
+
+	configStore, err := mem.NewProvider().OpenStore("orb-config")
+	require.NoError(t, err)
+
+	mgr := dynamic.New(configStore)
+
+	require.NoError(t, mgr.Register(&dynamic.Parameter{
+		Name:         pageSizeParam,
+		DefaultValue: "50",
+		Validate:     dynamic.PositiveInt,
+	}))
+
+	return mgr
+}
@@ -0,0 +1,343 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cutoff
+
+import (
+	"sync"
+	"time"
+
+	"github.com/trustbloc/edge-core/pkg/log"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+	"github.com/trustbloc/sidetree-core-go/pkg/batch/cutter"
+)
+
+var logger = log.New("batch-cutoff")
+
+// Controller determines when the batch writer cuts a batch. A batch is cut when the number of pending operations,
+// the total size of the pending operations, or the time since the first operation of the batch was queued
+// reaches its limit - whichever happens first. The limits may be changed while the server is running.
+//
+// The batch writer cuts a batch when the length of the operation queue reaches the maximum operation count of
+// the current protocol version. The Controller therefore wraps the protocol client (in order to lower the
+// maximum operation count) and the operation queue (in order to report a full queue when the size or latency
+// limit is reached and to limit the size of a batch).
+type Controller struct {
+	mutex         sync.RWMutex
+	maxOperations uint
+	maxBatchSize  uint
+	maxLatency    time.Duration
+	batchStart    time.Time
+	now           func() time.Time
+}
+
+// Limits contains the batch cut-off limits. A zero value means that the limit is not set.
+type Limits struct {
+	// MaxOperations is the maximum number of operations in a batch. This value may not exceed the
+	// maximum operation count of the protocol version.
+	MaxOperations uint
+	// MaxBatchSize is the maximum total size (in bytes) of the operation requests in a batch.
+	MaxBatchSize uint
+	// MaxLatency is the maximum time that an operation waits in the queue before a batch is cut.
+	MaxLatency time.Duration
+}
+
+// New returns a new batch cut-off controller.
+func New(limits Limits) *Controller {
+	return &Controller{
+		maxOperations: limits.MaxOperations,
+		maxBatchSize:  limits.MaxBatchSize,
+		maxLatency:    limits.MaxLatency,
+		now:           time.Now,
+	}
+}
+
+// SetMaxOperations sets the maximum number of operations in a batch.
+func (c *Controller) SetMaxOperations(value uint) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	logger.Infof("Setting max operations per batch to %d", value)
+
+	c.maxOperations = value
+}
+
+// SetMaxBatchSize sets the maximum total size (in bytes) of the operations in a batch.
+func (c *Controller) SetMaxBatchSize(value uint) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	logger.Infof("Setting max batch size to %d bytes", value)
+
+	c.maxBatchSize = value
+}
+
+// SetMaxLatency sets the maximum time that an operation waits in the queue before a batch is cut.
+func (c *Controller) SetMaxLatency(value time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	logger.Infof("Setting max batch latency to %s", value)
+
+	c.maxLatency = value
+}
+
+// Limits returns the current limits.
+func (c *Controller) Limits() Limits {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return Limits{
+		MaxOperations: c.maxOperations,
+		MaxBatchSize:  c.maxBatchSize,
+		MaxLatency:    c.maxLatency,
+	}
+}
+
+// ProtocolClient wraps the given protocol client so that the maximum operation count of a protocol version
+// is capped by the maximum operations per batch.
+func (c *Controller) ProtocolClient(pc protocol.Client) protocol.Client {
+	return &protocolClient{Client: pc, controller: c}
+}
+
+// OperationQueue wraps the given operation queue so that a batch is cut when the size or latency limit is reached.
+// The given protocol client is used to determine the maximum operation count of the current protocol version.
+func (c *Controller) OperationQueue(q cutter.OperationQueue, pc protocol.Client) cutter.OperationQueue {
+	return &operationQueue{OperationQueue: q, pc: pc, controller: c}
+}
+
+func (c *Controller) maxOperationCount(protocolMax uint) uint {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	if c.maxOperations == 0 || c.maxOperations > protocolMax {
+		return protocolMax
+	}
+
+	return c.maxOperations
+}
+
+// length returns the length of the queue that is reported to the batch writer. If the size or latency limit
+// has been reached then the maximum operation count is returned so that the batch writer cuts a batch.
+func (c *Controller) length(q cutter.OperationQueue, maxOperations uint) uint {
+	n := q.Len()
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if n == 0 {
+		c.batchStart = time.Time{}
+
+		return 0
+	}
+
+	if n >= maxOperations {
+		return n
+	}
+
+	if c.batchStart.IsZero() {
+		c.batchStart = c.now()
+	}
+
+	if c.maxLatency > 0 && c.now().Sub(c.batchStart) >= c.maxLatency {
+		logger.Debugf("Max batch latency of %s reached with %d pending operations", c.maxLatency, n)
+
+		return maxOperations
+	}
+
+	if c.maxBatchSize > 0 {
+		ops, err := q.Peek(n)
+		if err != nil {
+			logger.Warnf("Error peeking operations: %s", err)
+
+			return n
+		}
+
+		if size(ops) >= c.maxBatchSize {
+			logger.Debugf("Max batch size of %d bytes reached with %d pending operations", c.maxBatchSize, n)
+
+			return maxOperations
+		}
+	}
+
+	return n
+}
+
+// fit returns the number of operations (at least one) from the head of the given operations that fit
+// within the size limit.
+func (c *Controller) fit(ops operation.QueuedOperationsAtTime) uint {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	if c.maxBatchSize == 0 {
+		return uint(len(ops))
+	}
+
+	var total uint
+
+	for i, op := range ops {
+		total += uint(len(op.OperationRequest))
+
+		if total > c.maxBatchSize && i > 0 {
+			return uint(i)
+		}
+	}
+
+	return uint(len(ops))
+}
+
+func (c *Controller) batchCut(remaining uint) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if remaining == 0 {
+		c.batchStart = time.Time{}
+	} else {
+		c.batchStart = c.now()
+	}
+}
+
+func size(ops operation.QueuedOperationsAtTime) uint {
+	var total uint
+
+	for _, op := range ops {
+		total += uint(len(op.OperationRequest))
+	}
+
+	return total
+}
+
+type protocolClient struct {
+	protocol.Client
+
+	controller *Controller
+}
+
+func (pc *protocolClient) Current() (protocol.Version, error) {
+	pv, err := pc.Client.Current()
+	if err != nil {
+		return nil, err
+	}
+
+	return pc.wrap(pv), nil
+}
+
+func (pc *protocolClient) Get(version uint64) (protocol.Version, error) {
+	pv, err := pc.Client.Get(version)
+	if err != nil {
+		return nil, err
+	}
+
+	return pc.wrap(pv), nil
+}
+
+func (pc *protocolClient) wrap(pv protocol.Version) protocol.Version {
+	return &protocolVersion{v: pv, controller: pc.controller}
+}
+
+// protocolVersion wraps a protocol version in order to override the max operation count. The version is held
+// in a named field since an embedded protocol.Version would conflict with the Version method.
+type protocolVersion struct {
+	v          protocol.Version
+	controller *Controller
+}
+
+func (pv *protocolVersion) Version() string {
+	return pv.v.Version()
+}
+
+func (pv *protocolVersion) Protocol() protocol.Protocol {
+	p := pv.v.Protocol()
+
+	p.MaxOperationCount = pv.controller.maxOperationCount(p.MaxOperationCount)
+
+	return p
+}
+
+func (pv *protocolVersion) TransactionProcessor() protocol.TxnProcessor {
+	return pv.v.TransactionProcessor()
+}
+
+func (pv *protocolVersion) OperationParser() protocol.OperationParser {
+	return pv.v.OperationParser()
+}
+
+func (pv *protocolVersion) OperationApplier() protocol.OperationApplier {
+	return pv.v.OperationApplier()
+}
+
+func (pv *protocolVersion) OperationHandler() protocol.OperationHandler {
+	return pv.v.OperationHandler()
+}
+
+func (pv *protocolVersion) OperationProvider() protocol.OperationProvider {
+	return pv.v.OperationProvider()
+}
+
+func (pv *protocolVersion) DocumentComposer() protocol.DocumentComposer {
+	return pv.v.DocumentComposer()
+}
+
+func (pv *protocolVersion) DocumentValidator() protocol.DocumentValidator {
+	return pv.v.DocumentValidator()
+}
+
+func (pv *protocolVersion) DocumentTransformer() protocol.DocumentTransformer {
+	return pv.v.DocumentTransformer()
+}
+
+type operationQueue struct {
+	cutter.OperationQueue
+
+	pc         protocol.Client
+	controller *Controller
+}
+
+// Len returns the length of the queue or, if the size or latency limit has been reached, a length that
+// causes the batch writer to cut a batch.
+func (q *operationQueue) Len() uint {
+	pv, err := q.pc.Current()
+	if err != nil {
+		logger.Warnf("Error getting current protocol version: %s", err)
+
+		return q.OperationQueue.Len()
+	}
+
+	return q.controller.length(q.OperationQueue, q.controller.maxOperationCount(pv.Protocol().MaxOperationCount))
+}
+
+// Peek returns (up to) the given number of operations that fit within the size limit.
+func (q *operationQueue) Peek(num uint) (operation.QueuedOperationsAtTime, error) {
+	ops, err := q.OperationQueue.Peek(num)
+	if err != nil {
+		return nil, err
+	}
+
+	return ops[0:q.controller.fit(ops)], nil
+}
+
+// Remove removes (up to) the given number of operations that fit within the size limit.
+func (q *operationQueue) Remove(num uint) (ops operation.QueuedOperationsAtTime, ack func() uint, nack func(), err error) {
+	peeked, err := q.OperationQueue.Peek(num)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	n := q.controller.fit(peeked)
+	if n < num {
+		logger.Debugf("Limiting batch to %d operations due to the max batch size", n)
+	}
+
+	ops, ack, nack, err = q.OperationQueue.Remove(n)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	q.controller.batchCut(q.OperationQueue.Len())
+
+	return ops, ack, nack, nil
+}
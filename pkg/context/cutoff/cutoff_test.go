@@ -0,0 +1,253 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cutoff
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+	coremocks "github.com/trustbloc/sidetree-core-go/pkg/mocks"
+)
+
+const protocolMaxOperations = 10
+
+func TestController_MaxOperations(t *testing.T) {
+	c := New(Limits{MaxOperations: 3})
+
+	pc := c.ProtocolClient(newMockProtocolClient())
+
+	pv, err := pc.Current()
+	require.NoError(t, err)
+	require.Equal(t, uint(3), pv.Protocol().MaxOperationCount)
+
+	pv, err = pc.Get(0)
+	require.NoError(t, err)
+	require.Equal(t, uint(3), pv.Protocol().MaxOperationCount)
+
+	c.SetMaxOperations(100)
+
+	pv, err = pc.Current()
+	require.NoError(t, err)
+	require.Equal(t, uint(protocolMaxOperations), pv.Protocol().MaxOperationCount,
+		"max operations should be capped by the protocol")
+
+	c.SetMaxOperations(0)
+
+	pv, err = pc.Current()
+	require.NoError(t, err)
+	require.Equal(t, uint(protocolMaxOperations), pv.Protocol().MaxOperationCount)
+
+	t.Run("protocol client error", func(t *testing.T) {
+		errExpected := errors.New("injected protocol error")
+
+		pc := c.ProtocolClient(&mockProtocolClient{err: errExpected})
+
+		_, err := pc.Current()
+		require.True(t, errors.Is(err, errExpected))
+
+		_, err = pc.Get(0)
+		require.True(t, errors.Is(err, errExpected))
+
+		q := c.OperationQueue(newMockQueue(10, 10), pc)
+		require.Equal(t, uint(10), q.Len())
+	})
+}
+
+func TestController_MaxBatchSize(t *testing.T) {
+	c := New(Limits{MaxBatchSize: 100})
+
+	pc := c.ProtocolClient(newMockProtocolClient())
+
+	t.Run("under limit", func(t *testing.T) {
+		q := c.OperationQueue(newMockQueue(2, 40), pc)
+		require.Equal(t, uint(2), q.Len())
+	})
+
+	t.Run("limit reached", func(t *testing.T) {
+		mq := newMockQueue(4, 40)
+
+		q := c.OperationQueue(mq, pc)
+		require.Equal(t, uint(protocolMaxOperations), q.Len())
+
+		ops, err := q.Peek(protocolMaxOperations)
+		require.NoError(t, err)
+		require.Len(t, ops, 2)
+
+		ops, ack, _, err := q.Remove(protocolMaxOperations)
+		require.NoError(t, err)
+		require.Len(t, ops, 2)
+		require.Equal(t, uint(2), ack())
+		require.Equal(t, uint(2), q.Len())
+	})
+
+	t.Run("single operation exceeds limit", func(t *testing.T) {
+		q := c.OperationQueue(newMockQueue(2, 200), pc)
+
+		ops, _, _, err := q.Remove(protocolMaxOperations)
+		require.NoError(t, err)
+		require.Len(t, ops, 1)
+	})
+
+	t.Run("limit removed", func(t *testing.T) {
+		c.SetMaxBatchSize(0)
+		defer c.SetMaxBatchSize(100)
+
+		q := c.OperationQueue(newMockQueue(4, 40), pc)
+		require.Equal(t, uint(4), q.Len())
+
+		ops, _, _, err := q.Remove(protocolMaxOperations)
+		require.NoError(t, err)
+		require.Len(t, ops, 4)
+	})
+
+	t.Run("queue error", func(t *testing.T) {
+		errExpected := errors.New("injected queue error")
+
+		mq := newMockQueue(4, 40)
+		mq.err = errExpected
+
+		q := c.OperationQueue(mq, pc)
+		require.Equal(t, uint(4), q.Len())
+
+		_, err := q.Peek(1)
+		require.True(t, errors.Is(err, errExpected))
+
+		_, _, _, err = q.Remove(1)
+		require.True(t, errors.Is(err, errExpected))
+	})
+}
+
+func TestController_MaxLatency(t *testing.T) {
+	now := time.Now()
+
+	c := New(Limits{MaxLatency: time.Minute})
+	c.now = func() time.Time { return now }
+
+	pc := c.ProtocolClient(newMockProtocolClient())
+
+	mq := newMockQueue(2, 10)
+	q := c.OperationQueue(mq, pc)
+
+	require.Equal(t, uint(2), q.Len())
+
+	now = now.Add(30 * time.Second)
+	require.Equal(t, uint(2), q.Len())
+
+	now = now.Add(30 * time.Second)
+	require.Equal(t, uint(protocolMaxOperations), q.Len())
+
+	ops, _, _, err := q.Remove(protocolMaxOperations)
+	require.NoError(t, err)
+	require.Len(t, ops, 2)
+
+	require.Zero(t, q.Len())
+
+	mq.add(1, 10)
+	require.Equal(t, uint(1), q.Len())
+
+	c.SetMaxLatency(10 * time.Second)
+
+	now = now.Add(10 * time.Second)
+	require.Equal(t, uint(protocolMaxOperations), q.Len())
+
+	require.Equal(t, Limits{MaxLatency: 10 * time.Second}, c.Limits())
+}
+
+type mockProtocolClient struct {
+	pv  protocol.Version
+	err error
+}
+
+func newMockProtocolClient() *mockProtocolClient {
+	pv := &coremocks.ProtocolVersion{}
+	pv.ProtocolReturns(protocol.Protocol{MaxOperationCount: protocolMaxOperations})
+
+	return &mockProtocolClient{pv: pv}
+}
+
+func (m *mockProtocolClient) Current() (protocol.Version, error) {
+	return m.pv, m.err
+}
+
+func (m *mockProtocolClient) Get(uint64) (protocol.Version, error) {
+	return m.pv, m.err
+}
+
+type mockQueue struct {
+	mutex sync.Mutex
+	ops   operation.QueuedOperationsAtTime
+	err   error
+}
+
+func newMockQueue(num, opSize int) *mockQueue {
+	q := &mockQueue{}
+	q.add(num, opSize)
+
+	return q
+}
+
+func (m *mockQueue) add(num, opSize int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for i := 0; i < num; i++ {
+		m.ops = append(m.ops, &operation.QueuedOperationAtTime{
+			QueuedOperation: operation.QueuedOperation{OperationRequest: make([]byte, opSize)},
+		})
+	}
+}
+
+func (m *mockQueue) Add(*operation.QueuedOperation, uint64) (uint, error) {
+	return 0, nil
+}
+
+func (m *mockQueue) Peek(num uint) (operation.QueuedOperationsAtTime, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	n := int(num)
+	if len(m.ops) < n {
+		n = len(m.ops)
+	}
+
+	return m.ops[0:n], nil
+}
+
+func (m *mockQueue) Remove(num uint) (operation.QueuedOperationsAtTime, func() uint, func(), error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.err != nil {
+		return nil, nil, nil, m.err
+	}
+
+	n := int(num)
+	if len(m.ops) < n {
+		n = len(m.ops)
+	}
+
+	ops := m.ops[0:n]
+	m.ops = m.ops[n:]
+
+	return ops, func() uint { return uint(len(ops)) }, func() {}, nil
+}
+
+func (m *mockQueue) Len() uint {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return uint(len(m.ops))
+}
@@ -0,0 +1,150 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package opqueue
+
+import (
+	"hash/fnv"
+	"math"
+	"sync"
+	"time"
+)
+
+// bloomFilter is an in-memory filter of the suffixes that have queued operations. A bloom filter doesn't
+// support deletion so two generations of filters are maintained. A new generation is started after each rotation
+// period, which is set to the operation expiration of the queue. A suffix is therefore removed from the filter
+// no earlier than one expiration period after it was added, at which point the queued operation has expired.
+type bloomFilter struct {
+	mutex          sync.RWMutex
+	current        *bloomBits
+	previous       *bloomBits
+	numBits        uint64
+	numHashes      uint64
+	rotationPeriod time.Duration
+	rotationTime   time.Time
+	now            func() time.Time
+}
+
+type bloomBits []uint64
+
+func newBloomFilter(expectedItems uint, falsePositiveRate float64, rotationPeriod time.Duration) *bloomFilter {
+	if expectedItems == 0 {
+		expectedItems = 1
+	}
+
+	n := float64(expectedItems)
+
+	// Optimal number of bits (m) and hash functions (k) for n items with a false positive rate p:
+	// m = -n*ln(p)/(ln(2)^2) and k = (m/n)*ln(2).
+	numBits := uint64(math.Ceil(-n * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2)))
+	if numBits == 0 {
+		numBits = 64 //nolint:gomnd
+	}
+
+	numHashes := uint64(math.Ceil(float64(numBits) / n * math.Ln2))
+	if numHashes == 0 {
+		numHashes = 1
+	}
+
+	f := &bloomFilter{
+		numBits:        numBits,
+		numHashes:      numHashes,
+		rotationPeriod: rotationPeriod,
+		now:            time.Now,
+	}
+
+	f.current = f.newBits()
+	f.previous = f.newBits()
+	f.rotationTime = f.now().Add(rotationPeriod)
+
+	logger.Debugf("Created bloom filter with %d bits and %d hash functions", numBits, numHashes)
+
+	return f
+}
+
+// Add adds the given suffix to the filter.
+func (f *bloomFilter) Add(suffix string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.rotateIfRequired()
+
+	h1, h2 := hash(suffix)
+
+	for i := uint64(0); i < f.numHashes; i++ {
+		f.current.set((h1 + i*h2) % f.numBits)
+	}
+}
+
+// MayContain returns false if the given suffix is definitely not in the filter. If true is returned then
+// the suffix may be in the filter.
+func (f *bloomFilter) MayContain(suffix string) bool {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	h1, h2 := hash(suffix)
+
+	return f.contains(f.current, h1, h2) || f.contains(f.previous, h1, h2)
+}
+
+func (f *bloomFilter) contains(bits *bloomBits, h1, h2 uint64) bool {
+	for i := uint64(0); i < f.numHashes; i++ {
+		if !bits.isSet((h1 + i*h2) % f.numBits) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (f *bloomFilter) rotateIfRequired() {
+	now := f.now()
+
+	if now.Before(f.rotationTime) {
+		return
+	}
+
+	if now.Sub(f.rotationTime) >= f.rotationPeriod {
+		// More than one period has elapsed so both generations are stale.
+		f.previous = f.newBits()
+	} else {
+		f.previous = f.current
+	}
+
+	f.current = f.newBits()
+	f.rotationTime = now.Add(f.rotationPeriod)
+
+	logger.Debugf("Rotated bloom filter. Next rotation at %s", f.rotationTime)
+}
+
+func (f *bloomFilter) newBits() *bloomBits {
+	bits := make(bloomBits, (f.numBits+63)/64) //nolint:gomnd
+
+	return &bits
+}
+
+func (b *bloomBits) set(i uint64) {
+	(*b)[i/64] |= 1 << (i % 64) //nolint:gomnd
+}
+
+func (b *bloomBits) isSet(i uint64) bool {
+	return (*b)[i/64]&(1<<(i%64)) != 0 //nolint:gomnd
+}
+
+// hash returns two hash values which are combined (double hashing) to simulate k hash functions.
+func hash(value string) (uint64, uint64) {
+	h := fnv.New64a()
+
+	// Write never returns an error.
+	_, _ = h.Write([]byte(value)) //nolint:errcheck
+
+	h1 := h.Sum64()
+
+	// Derive a second hash from the first one (and ensure that it's odd so that it's never zero).
+	h2 := (h1>>33 | h1<<31) | 1 //nolint:gomnd
+
+	return h1, h2
+}
@@ -0,0 +1,80 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package opqueue
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBloomFilter(t *testing.T) {
+	const numItems = 1000
+
+	f := newBloomFilter(numItems, 0.01, time.Minute)
+
+	for i := 0; i < numItems; i++ {
+		f.Add(fmt.Sprintf("suffix-%d", i))
+	}
+
+	for i := 0; i < numItems; i++ {
+		require.True(t, f.MayContain(fmt.Sprintf("suffix-%d", i)))
+	}
+
+	falsePositives := 0
+
+	for i := 0; i < numItems; i++ {
+		if f.MayContain(fmt.Sprintf("other-%d", i)) {
+			falsePositives++
+		}
+	}
+
+	require.Less(t, falsePositives, numItems/20, "too many false positives")
+}
+
+func TestBloomFilter_Rotate(t *testing.T) {
+	now := time.Now()
+
+	f := newBloomFilter(100, 0.01, time.Minute)
+	f.now = func() time.Time { return now }
+	f.rotationTime = now.Add(time.Minute)
+
+	f.Add("suffix1")
+	require.True(t, f.MayContain("suffix1"))
+
+	// The first rotation moves suffix1 to the previous generation.
+	now = now.Add(time.Minute)
+
+	f.Add("suffix2")
+	require.True(t, f.MayContain("suffix1"))
+	require.True(t, f.MayContain("suffix2"))
+
+	// The second rotation removes suffix1.
+	now = now.Add(time.Minute)
+
+	f.Add("suffix3")
+	require.False(t, f.MayContain("suffix1"))
+	require.True(t, f.MayContain("suffix2"))
+	require.True(t, f.MayContain("suffix3"))
+
+	// Both generations are stale after more than one rotation period.
+	now = now.Add(3 * time.Minute)
+
+	f.Add("suffix4")
+	require.False(t, f.MayContain("suffix2"))
+	require.False(t, f.MayContain("suffix3"))
+	require.True(t, f.MayContain("suffix4"))
+}
+
+func TestBloomFilter_ZeroItems(t *testing.T) {
+	f := newBloomFilter(0, 0.01, time.Minute)
+
+	f.Add("suffix")
+	require.True(t, f.MayContain("suffix"))
+}
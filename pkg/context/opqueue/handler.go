@@ -0,0 +1,121 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package opqueue
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+)
+
+const (
+	// QueuePath is the path of the operation queue endpoint relative to the Sidetree base path.
+	QueuePath = "/queue"
+
+	didParam = "did"
+
+	badRequestResponse          = "Bad Request."
+	internalServerErrorResponse = "Internal Server Error."
+)
+
+// QueueResponse contains the operations for a DID that are pending in the operation queue.
+type QueueResponse struct {
+	Suffix     string              `json:"suffix"`
+	Pending    bool                `json:"pending"`
+	Operations []*PendingOperation `json:"operations"`
+}
+
+type pendingOperationsRetriever interface {
+	PendingOperations(suffix string) ([]*PendingOperation, error)
+}
+
+// Handler returns the operations for a given DID that are pending in the operation queue or in the batch that is
+// currently being cut, for example: GET /sidetree/v1/queue?did=EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A.
+// The DID may be specified either as the unique suffix or as the full DID.
+type Handler struct {
+	basePath string
+	queue    pendingOperationsRetriever
+	marshal  func(v interface{}) ([]byte, error)
+}
+
+// NewHandler returns a new operation queue handler. The endpoint of the handler is <basePath>/queue.
+func NewHandler(basePath string, queue pendingOperationsRetriever) *Handler {
+	return &Handler{
+		basePath: basePath,
+		queue:    queue,
+		marshal:  json.Marshal,
+	}
+}
+
+// Path returns the HTTP REST endpoint of the operation queue service.
+func (h *Handler) Path() string {
+	return h.basePath + QueuePath
+}
+
+// Method returns the HTTP method, which is always GET.
+func (h *Handler) Method() string {
+	return http.MethodGet
+}
+
+// Handler returns the HTTP REST handle for the operation queue service.
+func (h *Handler) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Handler) handle(w http.ResponseWriter, req *http.Request) {
+	did := req.URL.Query().Get(didParam)
+
+	// The unique suffix is the last segment of the DID.
+	suffix := did[strings.LastIndex(did, ":")+1:]
+	if suffix == "" {
+		logger.Debugf("[%s] The '%s' query parameter is required", h.Path(), didParam)
+
+		writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	ops, err := h.queue.PendingOperations(suffix)
+	if err != nil {
+		logger.Errorf("[%s] Error retrieving pending operations for suffix [%s]: %s", h.Path(), suffix, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	if ops == nil {
+		ops = []*PendingOperation{}
+	}
+
+	respBytes, err := h.marshal(&QueueResponse{
+		Suffix:     suffix,
+		Pending:    len(ops) > 0,
+		Operations: ops,
+	})
+	if err != nil {
+		logger.Errorf("[%s] Error marshalling pending operations for suffix [%s]: %s", h.Path(), suffix, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	writeResponse(w, http.StatusOK, respBytes)
+}
+
+func writeResponse(w http.ResponseWriter, status int, body []byte) {
+	w.WriteHeader(status)
+
+	if _, err := w.Write(body); err != nil {
+		logger.Warnf("Unable to write response: %s", err)
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package opqueue
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+)
+
+const (
+	sidetreeBasePath = "/sidetree/v1"
+	testSuffix       = "EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A"
+)
+
+func TestHandler(t *testing.T) {
+	q := &mockPendingOperationsRetriever{
+		ops: map[string][]*PendingOperation{
+			testSuffix: {
+				{ID: "op1", Suffix: testSuffix, Type: operation.TypeUpdate, Status: StatusBatched, ServerID: "server1"},
+			},
+		},
+	}
+
+	h := NewHandler(sidetreeBasePath, q)
+	require.Equal(t, sidetreeBasePath+"/queue", h.Path())
+	require.Equal(t, http.MethodGet, h.Method())
+	require.NotNil(t, h.Handler())
+
+	t.Run("Pending", func(t *testing.T) {
+		for _, did := range []string{testSuffix, "did:orb:uAAA:" + testSuffix} {
+			rw := getQueue(h, "?did="+did)
+			require.Equal(t, http.StatusOK, rw.Code)
+			require.Equal(t, "application/json", rw.Header().Get("Content-Type"))
+
+			resp := &QueueResponse{}
+			require.NoError(t, json.Unmarshal(rw.Body.Bytes(), resp))
+			require.Equal(t, testSuffix, resp.Suffix)
+			require.True(t, resp.Pending)
+			require.Len(t, resp.Operations, 1)
+			require.Equal(t, "op1", resp.Operations[0].ID)
+			require.Equal(t, StatusBatched, resp.Operations[0].Status)
+		}
+	})
+
+	t.Run("Not pending", func(t *testing.T) {
+		rw := getQueue(h, "?did=EiBnUaQfVSUo4Ks4fIAcBbXUnPpIUtBCBRAXtE3n3x1vNw")
+		require.Equal(t, http.StatusOK, rw.Code)
+
+		resp := &QueueResponse{}
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), resp))
+		require.False(t, resp.Pending)
+		require.Contains(t, rw.Body.String(), `"operations":[]`)
+	})
+
+	t.Run("Missing DID", func(t *testing.T) {
+		require.Equal(t, http.StatusBadRequest, getQueue(h, "").Code)
+		require.Equal(t, http.StatusBadRequest, getQueue(h, "?did=did:orb:uAAA:").Code)
+	})
+
+	t.Run("Queue error", func(t *testing.T) {
+		h := NewHandler(sidetreeBasePath, &mockPendingOperationsRetriever{err: errors.New("injected queue error")})
+
+		require.Equal(t, http.StatusInternalServerError, getQueue(h, "?did="+testSuffix).Code)
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		h := NewHandler(sidetreeBasePath, q)
+		h.marshal = func(v interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		require.Equal(t, http.StatusInternalServerError, getQueue(h, "?did="+testSuffix).Code)
+	})
+}
+
+func getQueue(h *Handler, query string) *httptest.ResponseRecorder {
+	rw := httptest.NewRecorder()
+
+	h.handle(rw, httptest.NewRequest(http.MethodGet, h.Path()+query, nil))
+
+	return rw
+}
+
+type mockPendingOperationsRetriever struct {
+	ops map[string][]*PendingOperation
+	err error
+}
+
+func (m *mockPendingOperationsRetriever) PendingOperations(suffix string) ([]*PendingOperation, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	return m.ops[suffix], nil
+}
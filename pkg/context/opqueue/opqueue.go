@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package opqueue
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -34,6 +35,7 @@ const (
 	tagOpExpiry    = "ExpiryTime"
 	tagOpQueueTask = "Task"
 	tagServerID    = "ServerID"
+	tagSuffix      = "Suffix"
 
 	defaultInterval             = 10 * time.Second
 	defaultTaskExpirationFactor = 2
@@ -65,6 +67,7 @@ type metricsProvider interface {
 	BatchCutTime(value time.Duration)
 	BatchRollbackTime(value time.Duration)
 	BatchSize(value float64)
+	QueueSize(value float64)
 }
 
 type taskManager interface {
@@ -102,6 +105,37 @@ type Config struct {
 	MaxRetries int
 }
 
+// RejectedOperationListener is notified when an operation is discarded from the queue since the maximum
+// number of retries was reached.
+type RejectedOperationListener interface {
+	OperationRejected(op *operation.QueuedOperation, reason string)
+}
+
+// Option sets an operation queue option.
+type Option func(q *Queue)
+
+// WithRejectedOperationListener adds a listener that's notified when an operation is discarded from the queue.
+func WithRejectedOperationListener(l RejectedOperationListener) Option {
+	return func(q *Queue) {
+		q.rejectedOpListeners = append(q.rejectedOpListeners, l)
+	}
+}
+
+// WithBloomFilter enables a pre-check for duplicate operations when an operation is added to the queue. An in-memory
+// bloom filter of the suffixes of queued operations is consulted and the database is only queried for an identical
+// queued operation if the suffix may be in the filter, which avoids most of the database queries during a burst of
+// creates. expectedOperations is the expected number of operations that are added within the operation expiration
+// period and falsePositiveRate is the acceptable rate of false positives (for example, 0.01).
+//
+// Note that the filter only contains the operations that were added to (or delivered to) this instance, so a
+// duplicate of an operation that was submitted to another instance may not be detected. The filter is never used
+// when looking up the pending operations of a suffix.
+func WithBloomFilter(expectedOperations uint, falsePositiveRate float64) Option {
+	return func(q *Queue) {
+		q.bloomFilter = newBloomFilter(expectedOperations, falsePositiveRate, q.opExpiration)
+	}
+}
+
 // Queue implements an operation queue that uses a publisher/subscriber.
 type Queue struct {
 	*lifecycle.Lifecycle
@@ -110,6 +144,7 @@ type Queue struct {
 	msgChan             <-chan *message.Message
 	mutex               sync.RWMutex
 	pending             []*queuedOperation
+	inBatch             map[string]*queuedOperation
 	marshal             func(interface{}) ([]byte, error)
 	unmarshal           func(data []byte, v interface{}) error
 	metrics             metricsProvider
@@ -121,11 +156,15 @@ type Queue struct {
 	taskMgr             taskManager
 	expiryService       dataExpiryService
 	maxRetries          int
+	rejectedOpListeners []RejectedOperationListener
+	bloomFilter         *bloomFilter
+	done                chan struct{}
+	listenerDone        chan struct{}
 }
 
 // New returns a new operation queue.
 func New(cfg Config, pubSub pubSub, p storage.Provider, taskMgr taskManager,
-	expiryService dataExpiryService, metrics metricsProvider) (*Queue, error) {
+	expiryService dataExpiryService, metrics metricsProvider, opts ...Option) (*Queue, error) {
 	msgChan, err := pubSub.SubscribeWithOpts(context.Background(), topic, spi.WithPool(cfg.PoolSize))
 	if err != nil {
 		return nil, fmt.Errorf("subscribe to topic [%s]: %w", topic, err)
@@ -136,7 +175,8 @@ func New(cfg Config, pubSub pubSub, p storage.Provider, taskMgr taskManager,
 		return nil, fmt.Errorf("open store: %w", err)
 	}
 
-	err = p.SetStoreConfig(storeName, storage.StoreConfiguration{TagNames: []string{tagOpQueueTask, tagOpExpiry}})
+	err = p.SetStoreConfig(storeName,
+		storage.StoreConfiguration{TagNames: []string{tagOpQueueTask, tagOpExpiry, tagSuffix}})
 	if err != nil {
 		return nil, fmt.Errorf("failed to set store configuration: %w", err)
 	}
@@ -157,9 +197,19 @@ func New(cfg Config, pubSub pubSub, p storage.Provider, taskMgr taskManager,
 		taskMgr:             taskMgr,
 		expiryService:       expiryService,
 		maxRetries:          cfg.MaxRetries,
+		inBatch:             make(map[string]*queuedOperation),
+		done:                make(chan struct{}),
+		listenerDone:        make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(q)
 	}
 
-	q.Lifecycle = lifecycle.New("operation-queue", lifecycle.WithStart(q.start))
+	q.Lifecycle = lifecycle.New("operation-queue",
+		lifecycle.WithStart(q.start),
+		lifecycle.WithStop(q.stop),
+	)
 
 	logger.Infof("[%s] Storing new operation queue task.", q.serverInstanceID)
 
@@ -171,8 +221,22 @@ func New(cfg Config, pubSub pubSub, p storage.Provider, taskMgr taskManager,
 	return q, nil
 }
 
-// Add publishes the given operation.
+// Add publishes the given operation. If the bloom filter is enabled and an identical operation is already
+// queued then the operation isn't added again.
 func (q *Queue) Add(op *operation.QueuedOperation, protocolVersion uint64) (uint, error) {
+	if q.State() != lifecycle.StateStarted {
+		return 0, lifecycle.ErrNotStarted
+	}
+
+	queued, err := q.isQueued(op)
+	if err != nil {
+		logger.Warnf("Unable to check for a duplicate of the operation for suffix [%s]: %s", op.UniqueSuffix, err)
+	} else if queued {
+		logger.Infof("Ignoring duplicate operation for suffix [%s] since it's already queued", op.UniqueSuffix)
+
+		return 0, nil
+	}
+
 	return q.post(
 		&operationMessage{
 			ID: uuid.New().String(),
@@ -205,6 +269,10 @@ func (q *Queue) post(op *operationMessage) (uint, error) {
 	logger.Debugf("Publishing operation message to topic [%s] - Msg [%s], OpID [%s], DID [%s], Retries [%d]",
 		topic, msg.UUID, op.ID, op.Operation.UniqueSuffix, op.Retries)
 
+	// The suffix is added to the filter before the operation is published (and subsequently stored) so that
+	// the filter never misses an operation that's in the database.
+	q.addToBloomFilter(op.Operation.UniqueSuffix)
+
 	err = q.pubSub.Publish(topic, msg)
 	if err != nil {
 		return 0, fmt.Errorf("publish queued operation: %w", err)
@@ -258,6 +326,13 @@ func (q *Queue) Remove(num uint) (ops operation.QueuedOperationsAtTime, ack func
 	items := q.pending[0:n]
 	q.pending = q.pending[n:]
 
+	q.metrics.QueueSize(float64(len(q.pending)))
+
+	// The operations are in the batch until the batch is either committed or rolled back.
+	for _, item := range items {
+		q.inBatch[item.key] = item
+	}
+
 	logger.Debugf("[%s] Removed %d operations", q.serverInstanceID, len(items))
 
 	return q.asQueuedOperations(items), q.newAckFunc(items), q.newNackFunc(items), nil
@@ -285,13 +360,37 @@ func (q *Queue) start() {
 	logger.Infof("Started operation queue")
 }
 
+// stop stops the message listener so that no new operations are accepted. Operations that are still
+// pending remain in the database and are reposted by another server instance once this instance's task expires.
+func (q *Queue) stop() {
+	close(q.done)
+
+	<-q.listenerDone
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	if len(q.pending) > 0 {
+		logger.Warnf("[%s] Operation queue stopped with %d pending operations", q.serverInstanceID, len(q.pending))
+	} else {
+		logger.Infof("[%s] Operation queue stopped", q.serverInstanceID)
+	}
+}
+
 func (q *Queue) listen() {
 	logger.Debugf("Starting message listener")
 
+	defer close(q.listenerDone)
+
 	ticker := time.NewTicker(q.taskMonitorInterval)
+	defer ticker.Stop()
 
 	for {
 		select {
+		case <-q.done:
+			logger.Debugf("Message listener stopped")
+
+			return
 		case msg, ok := <-q.msgChan:
 			if !ok {
 				logger.Debugf("Message listener stopped")
@@ -325,6 +424,8 @@ func (q *Queue) handleMessage(msg *message.Message) {
 		return
 	}
 
+	q.addToBloomFilter(op.Operation.UniqueSuffix)
+
 	key := uuid.New().String()
 
 	err = q.store.Put(key, msg.Payload,
@@ -336,6 +437,10 @@ func (q *Queue) handleMessage(msg *message.Message) {
 			Name:  tagOpExpiry,
 			Value: fmt.Sprintf("%d", time.Now().Add(q.opExpiration).Unix()),
 		},
+		storage.Tag{
+			Name:  tagSuffix,
+			Value: op.Operation.UniqueSuffix,
+		},
 	)
 	if err != nil {
 		logger.Warnf("Error storing operation info. The message will be nacked and retried: %w", err)
@@ -357,6 +462,8 @@ func (q *Queue) handleMessage(msg *message.Message) {
 		timeAdded:        time.Now(),
 	})
 
+	q.metrics.QueueSize(float64(len(q.pending)))
+
 	msg.Ack()
 }
 
@@ -364,6 +471,8 @@ func (q *Queue) newAckFunc(items []*queuedOperation) func() uint {
 	return func() uint {
 		logger.Infof("Committed %d operation messages...", len(items))
 
+		q.removeFromBatch(items)
+
 		if err := q.deleteOperations(items); err != nil {
 			logger.Errorf("[%s] Error deleting pending operations: %s", q.serverInstanceID, err)
 		} else {
@@ -386,11 +495,15 @@ func (q *Queue) newNackFunc(items []*queuedOperation) func() {
 	return func() {
 		logger.Infof("%d operations were rolled back. Re-posting...", len(items))
 
+		q.removeFromBatch(items)
+
 		for _, op := range items {
 			if op.Retries >= q.maxRetries {
 				logger.Warnf("... not re-posting operation [%s] for suffix [%s] since the retry count [%d] has reached the limit.",
 					op.ID, op.Operation.UniqueSuffix, op.Retries)
 
+				q.notifyRejected(op.operationMessage)
+
 				continue
 			}
 
@@ -418,6 +531,15 @@ func (q *Queue) newNackFunc(items []*queuedOperation) func() {
 	}
 }
 
+func (q *Queue) removeFromBatch(items []*queuedOperation) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, item := range items {
+		delete(q.inBatch, item.key)
+	}
+}
+
 func (q *Queue) monitorOtherServers() {
 	it, err := q.store.Query(tagOpQueueTask)
 	if err != nil {
@@ -464,6 +586,18 @@ func (q *Queue) monitorOtherServers() {
 	}
 }
 
+func (q *Queue) notifyRejected(op *operationMessage) {
+	if len(q.rejectedOpListeners) == 0 {
+		return
+	}
+
+	reason := fmt.Sprintf("the operation could not be anchored after %d retries", op.Retries)
+
+	for _, l := range q.rejectedOpListeners {
+		l.OperationRejected(&op.Operation.QueuedOperation, reason)
+	}
+}
+
 func (q *Queue) deleteOperations(items []*queuedOperation) error {
 	batchOperations := make([]storage.Operation, len(items))
 
@@ -537,6 +671,8 @@ func (q *Queue) repostOperations(serverID string) error {
 			logger.Warnf("Not re-posting operation [%s] for suffix [%s] since the retry count [%d] has reached the limit.",
 				op.ID, op.Operation.UniqueSuffix, op.Retries)
 
+			q.notifyRejected(op)
+
 			continue
 		}
 
@@ -628,6 +764,43 @@ func (q *Queue) nextOperation(it storage.Iterator) (string, *operationMessage, b
 	return key, op, true, nil
 }
 
+// isQueued returns true if an operation with the same request as the given operation is already queued (by any
+// instance). The bloom filter is consulted first so that the database is only queried if the suffix may have a
+// queued operation. False is always returned if the bloom filter isn't enabled.
+func (q *Queue) isQueued(op *operation.QueuedOperation) (bool, error) {
+	if q.bloomFilter == nil || !q.bloomFilter.MayContain(op.UniqueSuffix) {
+		return false, nil
+	}
+
+	it, err := q.store.Query(fmt.Sprintf("%s:%s", tagSuffix, op.UniqueSuffix))
+	if err != nil {
+		return false, fmt.Errorf("query operations for suffix [%s]: %w", op.UniqueSuffix, err)
+	}
+
+	defer storage.Close(it, logger)
+
+	for {
+		_, queuedOp, ok, e := q.nextOperation(it)
+		if e != nil {
+			return false, e
+		}
+
+		if !ok {
+			return false, nil
+		}
+
+		if bytes.Equal(queuedOp.Operation.OperationRequest, op.OperationRequest) {
+			return true, nil
+		}
+	}
+}
+
+func (q *Queue) addToBloomFilter(suffix string) {
+	if q.bloomFilter != nil {
+		q.bloomFilter.Add(suffix)
+	}
+}
+
 func resolveConfig(cfg Config) Config {
 	if cfg.TaskMonitorInterval == 0 {
 		cfg.TaskMonitorInterval = defaultInterval
@@ -10,6 +10,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"sync"
 	"testing"
 	"time"
 
@@ -381,6 +382,97 @@ func TestQueue_Error(t *testing.T) {
 	})
 }
 
+func TestQueue_Duplicate(t *testing.T) {
+	const suffix = "EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A"
+
+	taskMgr := servicemocks.NewTaskManager("taskmgr1")
+	expirySvc := expiry.NewService(taskMgr, time.Second)
+
+	op := newQueuedOperation(suffix, operation.TypeUpdate)
+
+	t.Run("Bloom filter enabled -> duplicate ignored", func(t *testing.T) {
+		ps := mempubsub.New(mempubsub.DefaultConfig())
+		defer ps.Stop()
+
+		q, err := New(Config{}, ps, storage.NewMockStoreProvider(), taskMgr, expirySvc,
+			&mocks.MetricsProvider{}, WithBloomFilter(100, 0.01))
+		require.NoError(t, err)
+
+		q.Start()
+		defer q.Stop()
+
+		_, err = q.Add(op, 100)
+		require.NoError(t, err)
+
+		time.Sleep(100 * time.Millisecond)
+
+		require.Equal(t, uint(1), q.Len())
+
+		_, err = q.Add(op, 100)
+		require.NoError(t, err)
+
+		time.Sleep(100 * time.Millisecond)
+
+		require.Equalf(t, uint(1), q.Len(), "duplicate operation should not have been added")
+
+		// A different operation for the same suffix is added.
+		_, err = q.Add(newQueuedOperation(suffix, operation.TypeRecover), 100)
+		require.NoError(t, err)
+
+		time.Sleep(100 * time.Millisecond)
+
+		require.Equal(t, uint(2), q.Len())
+	})
+
+	t.Run("Bloom filter disabled -> duplicate added", func(t *testing.T) {
+		ps := mempubsub.New(mempubsub.DefaultConfig())
+		defer ps.Stop()
+
+		q, err := New(Config{}, ps, storage.NewMockStoreProvider(), taskMgr, expirySvc, &mocks.MetricsProvider{})
+		require.NoError(t, err)
+
+		q.Start()
+		defer q.Stop()
+
+		_, err = q.Add(op, 100)
+		require.NoError(t, err)
+
+		_, err = q.Add(op, 100)
+		require.NoError(t, err)
+
+		time.Sleep(100 * time.Millisecond)
+
+		require.Equal(t, uint(2), q.Len())
+	})
+
+	t.Run("Query error -> operation added", func(t *testing.T) {
+		ps := mempubsub.New(mempubsub.DefaultConfig())
+		defer ps.Stop()
+
+		s := &storage.MockStore{
+			Store:    make(map[string]storage.DBEntry),
+			ErrQuery: errors.New("injected query error"),
+		}
+
+		q, err := New(Config{}, ps, storage.NewCustomMockStoreProvider(s), taskMgr, expirySvc,
+			&mocks.MetricsProvider{}, WithBloomFilter(100, 0.01))
+		require.NoError(t, err)
+
+		q.Start()
+		defer q.Stop()
+
+		_, err = q.Add(op, 100)
+		require.NoError(t, err)
+
+		_, err = q.Add(op, 100)
+		require.NoError(t, err)
+
+		time.Sleep(100 * time.Millisecond)
+
+		require.Equal(t, uint(2), q.Len())
+	})
+}
+
 func TestRepostWithMaxRetries(t *testing.T) {
 	log.SetLevel("sidetree_context", log.DEBUG)
 	log.SetLevel("pubsub", log.DEBUG)
@@ -399,10 +491,13 @@ func TestRepostWithMaxRetries(t *testing.T) {
 
 	defer ps.Stop()
 
+	rejectedOps := &mockRejectedOperationListener{}
+
 	q, err := New(Config{PoolSize: 8, TaskMonitorInterval: time.Second, MaxRetries: 1},
 		ps, storageProvider, taskMgr,
 		expiry.NewService(taskMgr, 750*time.Millisecond),
 		&mocks.MetricsProvider{},
+		WithRejectedOperationListener(rejectedOps),
 	)
 	require.NoError(t, err)
 	require.NotNil(t, q)
@@ -434,6 +529,7 @@ func TestRepostWithMaxRetries(t *testing.T) {
 	removedOps, _, _, err = q.Remove(5)
 	require.NoError(t, err)
 	require.Emptyf(t, removedOps, "no operations should have been remaining since the max retry count was reached")
+	require.Len(t, rejectedOps.get(), 5)
 }
 
 func TestMain(m *testing.M) {
@@ -501,3 +597,22 @@ func newProcessedOperations(n int) processedOperations {
 
 	return ops
 }
+
+type mockRejectedOperationListener struct {
+	mutex    sync.Mutex
+	suffixes []string
+}
+
+func (m *mockRejectedOperationListener) OperationRejected(op *operation.QueuedOperation, _ string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.suffixes = append(m.suffixes, op.UniqueSuffix)
+}
+
+func (m *mockRejectedOperationListener) get() []string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.suffixes
+}
@@ -0,0 +1,174 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package opqueue
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+
+	"github.com/trustbloc/orb/pkg/document/util"
+	"github.com/trustbloc/orb/pkg/lifecycle"
+)
+
+// PendingStatus is the status of a pending operation.
+type PendingStatus string
+
+const (
+	// StatusQueued indicates that the operation is waiting in the queue to be added to a batch.
+	StatusQueued PendingStatus = "queued"
+	// StatusBatched indicates that the operation was added to the batch that is currently being cut and anchored.
+	StatusBatched PendingStatus = "batched"
+)
+
+// PendingOperation contains information about an operation that hasn't been anchored yet.
+type PendingOperation struct {
+	ID              string         `json:"id"`
+	Suffix          string         `json:"suffix"`
+	Type            operation.Type `json:"type"`
+	ProtocolVersion uint64         `json:"protocolVersion"`
+	Status          PendingStatus  `json:"status"`
+	Retries         int            `json:"retries"`
+	// ServerID is the ID of the server instance whose queue holds the operation.
+	ServerID  string    `json:"serverId"`
+	TimeAdded time.Time `json:"timeAdded"`
+}
+
+// PendingOperations returns the operations for the given DID suffix that are waiting in the queue or that are
+// in the batch that is currently being cut. Operations in the queues of other server instances are also returned
+// (with a status of 'queued') since they are persisted until they're anchored. Operations that were published but
+// that haven't been delivered to a queue yet aren't included.
+func (q *Queue) PendingOperations(suffix string) ([]*PendingOperation, error) {
+	if q.State() != lifecycle.StateStarted {
+		return nil, lifecycle.ErrNotStarted
+	}
+
+	ops := q.localPendingOperations(suffix)
+
+	otherOps, err := q.otherPendingOperations(suffix)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(ops, otherOps...), nil
+}
+
+func (q *Queue) localPendingOperations(suffix string) []*PendingOperation {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var ops []*PendingOperation
+
+	for _, op := range q.inBatch {
+		if op.Operation.UniqueSuffix == suffix {
+			ops = append(ops, newPendingOperation(op.operationMessage, StatusBatched, q.serverInstanceID,
+				op.timeAdded))
+		}
+	}
+
+	for _, op := range q.pending {
+		if op.Operation.UniqueSuffix == suffix {
+			ops = append(ops, newPendingOperation(op.operationMessage, StatusQueued, q.serverInstanceID,
+				op.timeAdded))
+		}
+	}
+
+	sort.SliceStable(ops, func(i, j int) bool {
+		return ops[i].TimeAdded.Before(ops[j].TimeAdded)
+	})
+
+	return ops
+}
+
+// otherPendingOperations returns the operations for the given suffix that are persisted by other server instances.
+func (q *Queue) otherPendingOperations(suffix string) ([]*PendingOperation, error) {
+	it, err := q.store.Query(fmt.Sprintf("%s:%s", tagSuffix, suffix))
+	if err != nil {
+		return nil, fmt.Errorf("query operations for suffix [%s]: %w", suffix, err)
+	}
+
+	defer storage.Close(it, logger)
+
+	var ops []*PendingOperation
+
+	for {
+		_, op, ok, e := q.nextOperation(it)
+		if e != nil {
+			return nil, e
+		}
+
+		if !ok {
+			break
+		}
+
+		tags, e := it.Tags()
+		if e != nil {
+			return nil, fmt.Errorf("get tags for operation [%s]: %w", op.ID, e)
+		}
+
+		serverID, timeAdded := q.getServerAndTimeAdded(tags)
+
+		if serverID == q.serverInstanceID {
+			// Operations in our own queue were already included.
+			continue
+		}
+
+		ops = append(ops, newPendingOperation(op, StatusQueued, serverID, timeAdded))
+	}
+
+	return ops, nil
+}
+
+// getServerAndTimeAdded returns the server instance ID and the time that the operation was added to the
+// queue (which is derived from the expiry time of the operation).
+func (q *Queue) getServerAndTimeAdded(tags []storage.Tag) (string, time.Time) {
+	var (
+		serverID  string
+		timeAdded time.Time
+	)
+
+	for _, tag := range tags {
+		switch tag.Name {
+		case tagServerID:
+			serverID = tag.Value
+		case tagOpExpiry:
+			expiryTime, err := strconv.ParseInt(tag.Value, 10, 64)
+			if err != nil {
+				logger.Debugf("Invalid value for tag [%s]: %s", tagOpExpiry, err)
+
+				continue
+			}
+
+			timeAdded = time.Unix(expiryTime, 0).Add(-q.opExpiration)
+		}
+	}
+
+	return serverID, timeAdded
+}
+
+func newPendingOperation(op *operationMessage, status PendingStatus, serverID string,
+	timeAdded time.Time) *PendingOperation {
+	opType, err := util.GetOperationType(op.Operation.OperationRequest)
+	if err != nil {
+		logger.Debugf("Unable to determine the type of operation [%s]: %s", op.ID, err)
+	}
+
+	return &PendingOperation{
+		ID:              op.ID,
+		Suffix:          op.Operation.UniqueSuffix,
+		Type:            opType,
+		ProtocolVersion: op.Operation.ProtocolVersion,
+		Status:          status,
+		Retries:         op.Retries,
+		ServerID:        serverID,
+		TimeAdded:       timeAdded,
+	}
+}
@@ -0,0 +1,205 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package opqueue
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	spi "github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+
+	servicemocks "github.com/trustbloc/orb/pkg/activitypub/service/mocks"
+	"github.com/trustbloc/orb/pkg/lifecycle"
+	"github.com/trustbloc/orb/pkg/mocks"
+	"github.com/trustbloc/orb/pkg/pubsub/mempubsub"
+	"github.com/trustbloc/orb/pkg/store/expiry"
+)
+
+func TestQueue_PendingOperations(t *testing.T) {
+	const (
+		suffix1 = "EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A"
+		suffix2 = "EiBnUaQfVSUo4Ks4fIAcBbXUnPpIUtBCBRAXtE3n3x1vNw"
+	)
+
+	storageProvider := storage.NewMockStoreProvider()
+
+	ps1 := mempubsub.New(mempubsub.DefaultConfig())
+	defer ps1.Stop()
+
+	ps2 := mempubsub.New(mempubsub.DefaultConfig())
+	defer ps2.Stop()
+
+	taskMgr1 := servicemocks.NewTaskManager("taskmgr1")
+	taskMgr2 := servicemocks.NewTaskManager("taskmgr2")
+
+	q1, err := New(Config{}, ps1, storageProvider, taskMgr1, expiry.NewService(taskMgr1, time.Second),
+		&mocks.MetricsProvider{})
+	require.NoError(t, err)
+
+	q1.Start()
+	defer q1.Stop()
+
+	q2, err := New(Config{}, ps2, storageProvider, taskMgr2, expiry.NewService(taskMgr2, time.Second),
+		&mocks.MetricsProvider{})
+	require.NoError(t, err)
+
+	q2.Start()
+	defer q2.Stop()
+
+	_, err = q1.Add(newQueuedOperation(suffix1, operation.TypeUpdate), 100)
+	require.NoError(t, err)
+
+	time.Sleep(100 * time.Millisecond)
+
+	_, err = q1.Add(newQueuedOperation(suffix1, operation.TypeRecover), 100)
+	require.NoError(t, err)
+
+	_, err = q2.Add(newQueuedOperation(suffix1, operation.TypeDeactivate), 100)
+	require.NoError(t, err)
+
+	_, err = q2.Add(newQueuedOperation(suffix2, operation.TypeUpdate), 100)
+	require.NoError(t, err)
+
+	time.Sleep(100 * time.Millisecond)
+
+	ops, err := q1.PendingOperations(suffix1)
+	require.NoError(t, err)
+	require.Len(t, ops, 3)
+
+	require.Equal(t, operation.TypeUpdate, ops[0].Type)
+	require.Equal(t, StatusQueued, ops[0].Status)
+	require.Equal(t, "taskmgr1", ops[0].ServerID)
+	require.Equal(t, uint64(100), ops[0].ProtocolVersion)
+	require.Equal(t, suffix1, ops[0].Suffix)
+
+	require.Equal(t, operation.TypeRecover, ops[1].Type)
+	require.Equal(t, "taskmgr1", ops[1].ServerID)
+
+	require.Equal(t, operation.TypeDeactivate, ops[2].Type)
+	require.Equal(t, StatusQueued, ops[2].Status)
+	require.Equal(t, "taskmgr2", ops[2].ServerID)
+	require.False(t, ops[2].TimeAdded.IsZero())
+
+	_, ack, _, err := q1.Remove(1)
+	require.NoError(t, err)
+
+	ops, err = q1.PendingOperations(suffix1)
+	require.NoError(t, err)
+	require.Len(t, ops, 3)
+	require.Equal(t, operation.TypeUpdate, ops[0].Type)
+	require.Equal(t, StatusBatched, ops[0].Status)
+
+	ack()
+
+	ops, err = q1.PendingOperations(suffix1)
+	require.NoError(t, err)
+	require.Len(t, ops, 2)
+	require.Equal(t, operation.TypeRecover, ops[0].Type)
+	require.Equal(t, operation.TypeDeactivate, ops[1].Type)
+
+	ops, err = q2.PendingOperations(suffix2)
+	require.NoError(t, err)
+	require.Len(t, ops, 1)
+	require.Equal(t, "taskmgr2", ops[0].ServerID)
+
+	ops, err = q1.PendingOperations("unknown")
+	require.NoError(t, err)
+	require.Empty(t, ops)
+
+	t.Run("Nack", func(t *testing.T) {
+		_, _, nack, e := q1.Remove(1)
+		require.NoError(t, e)
+
+		nack()
+
+		time.Sleep(100 * time.Millisecond)
+
+		ops, e = q1.PendingOperations(suffix1)
+		require.NoError(t, e)
+		require.Len(t, ops, 2)
+		require.Equal(t, operation.TypeRecover, ops[0].Type)
+		require.Equal(t, StatusQueued, ops[0].Status)
+		require.Equal(t, 1, ops[0].Retries)
+	})
+}
+
+func TestQueue_PendingOperationsError(t *testing.T) {
+	ps := mempubsub.New(mempubsub.DefaultConfig())
+	defer ps.Stop()
+
+	taskMgr := servicemocks.NewTaskManager("taskmgr1")
+	expirySvc := expiry.NewService(taskMgr, time.Second)
+
+	t.Run("Not started", func(t *testing.T) {
+		q, err := New(Config{}, ps, storage.NewMockStoreProvider(), taskMgr, expirySvc, &mocks.MetricsProvider{})
+		require.NoError(t, err)
+
+		_, err = q.PendingOperations("suffix")
+		require.True(t, errors.Is(err, lifecycle.ErrNotStarted))
+	})
+
+	t.Run("Query error", func(t *testing.T) {
+		errExpected := errors.New("injected query error")
+
+		s := &storage.MockStore{
+			Store:    make(map[string]storage.DBEntry),
+			ErrQuery: errExpected,
+		}
+
+		q, err := New(Config{}, ps, storage.NewCustomMockStoreProvider(s), taskMgr, expirySvc,
+			&mocks.MetricsProvider{})
+		require.NoError(t, err)
+
+		q.Start()
+		defer q.Stop()
+
+		_, err = q.PendingOperations("suffix")
+		require.True(t, errors.Is(err, errExpected))
+	})
+
+	t.Run("Bloom filter", func(t *testing.T) {
+		s := &storage.MockStore{
+			Store:    make(map[string]storage.DBEntry),
+			ErrQuery: errors.New("injected query error"),
+		}
+
+		q, err := New(Config{}, ps, storage.NewCustomMockStoreProvider(s), taskMgr, expirySvc,
+			&mocks.MetricsProvider{}, WithBloomFilter(100, 0.01))
+		require.NoError(t, err)
+
+		q.Start()
+		defer q.Stop()
+
+		// The filter doesn't contain the operations of other instances so the store is always queried.
+		_, err = q.PendingOperations("suffix")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected query error")
+	})
+
+	t.Run("Invalid expiry tag", func(t *testing.T) {
+		q := &Queue{opExpiration: time.Minute}
+
+		serverID, timeAdded := q.getServerAndTimeAdded([]spi.Tag{
+			{Name: tagServerID, Value: "server1"},
+			{Name: tagOpExpiry, Value: "invalid"},
+		})
+		require.Equal(t, "server1", serverID)
+		require.True(t, timeAdded.IsZero())
+	})
+}
+
+func newQueuedOperation(suffix string, opType operation.Type) *operation.QueuedOperation {
+	return &operation.QueuedOperation{
+		UniqueSuffix:     suffix,
+		OperationRequest: []byte(fmt.Sprintf(`{"type":"%s","didSuffix":"%s"}`, opType, suffix)),
+	}
+}
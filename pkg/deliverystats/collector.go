@@ -0,0 +1,561 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deliverystats
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/lifecycle"
+	"github.com/trustbloc/orb/pkg/store/expiry"
+)
+
+var logger = log.New("delivery-stats")
+
+const (
+	storeName = "delivery-stats"
+
+	// bucketTag is set on each document. The value is the start of the bucket (Unix time).
+	bucketTag = "bucket"
+	// expiryTag holds the time (Unix time) after which the document is deleted.
+	expiryTag = "expiry"
+
+	bucketSize = time.Hour
+
+	defaultFlushInterval = 30 * time.Second
+	defaultRetention     = 7 * 24 * time.Hour
+)
+
+// Counts contains the delivery counts of a follower (inbox) for a period of time.
+type Counts struct {
+	Deliveries     uint64 `json:"deliveries"`
+	Failures       uint64 `json:"failures"`
+	TotalLatencyMS uint64 `json:"totalLatencyMs"`
+	MaxLatencyMS   uint64 `json:"maxLatencyMs"`
+}
+
+func (c *Counts) add(other *Counts) {
+	c.Deliveries += other.Deliveries
+	c.Failures += other.Failures
+	c.TotalLatencyMS += other.TotalLatencyMS
+
+	if other.MaxLatencyMS > c.MaxLatencyMS {
+		c.MaxLatencyMS = other.MaxLatencyMS
+	}
+}
+
+// HourlyCounts contains the counts of a follower for the hour that starts at the given time.
+type HourlyCounts struct {
+	Start time.Time `json:"start"`
+	*Counts
+}
+
+// Summary contains the delivery statistics of a follower (inbox) for the requested period.
+type Summary struct {
+	Inbox          string          `json:"inbox"`
+	Deliveries     uint64          `json:"deliveries"`
+	Failures       uint64          `json:"failures"`
+	FailureRate    float64         `json:"failureRate"`
+	AvgLatencyMS   uint64          `json:"avgLatencyMs"`
+	MaxLatencyMS   uint64          `json:"maxLatencyMs"`
+	LastDelivery   *time.Time      `json:"lastDelivery,omitempty"`
+	Hourly         []*HourlyCounts `json:"hourly,omitempty"`
+	totalLatencyMS uint64
+}
+
+// bucket contains the counts of all inboxes for an hour.
+type bucket struct {
+	Start   time.Time          `json:"start"`
+	Inboxes map[string]*Counts `json:"inboxes"`
+}
+
+type metricsProvider interface {
+	OutboxDeliveryTime(host string, value time.Duration)
+	OutboxDeliveryFailed(host string)
+}
+
+type expiryService interface {
+	Register(store storage.Store, expiryTagName, storeName string, opts ...expiry.Option)
+}
+
+type options struct {
+	flushInterval time.Duration
+	retention     time.Duration
+}
+
+// Opt sets a collector option.
+type Opt func(opts *options)
+
+// WithFlushInterval sets the interval at which the counts are persisted.
+func WithFlushInterval(value time.Duration) Opt {
+	return func(opts *options) {
+		opts.flushInterval = value
+	}
+}
+
+// WithRetention sets the amount of time for which the hourly counts are kept.
+func WithRetention(value time.Duration) Opt {
+	return func(opts *options) {
+		opts.retention = value
+	}
+}
+
+// Collector collects the latency and failure counts of the deliveries of activities to each follower's
+// (or witness's) inbox so that the peers that slow down anchor propagation may be identified. The counts are
+// aggregated into hourly buckets in memory and are periodically persisted. As with the stats aggregator, each
+// instance persists its own buckets so that instances don't overwrite each other's counts; the buckets of all
+// instances are summed when the stats are retrieved. Buckets are deleted by the expiry service after the
+// retention period.
+type Collector struct {
+	*lifecycle.Lifecycle
+
+	store         storage.Store
+	metrics       metricsProvider
+	instanceID    string
+	flushInterval time.Duration
+	retention     time.Duration
+	done          chan struct{}
+	wg            sync.WaitGroup
+	now           func() time.Time
+
+	mutex          sync.Mutex
+	buckets        map[int64]*bucket
+	lastDelivery   map[string]time.Time
+	version        uint64
+	flushedVersion uint64
+}
+
+// NewCollector returns a new delivery stats collector.
+func NewCollector(provider storage.Provider, expiryService expiryService, metrics metricsProvider,
+	opts ...Opt) (*Collector, error) {
+	options := &options{
+		flushInterval: defaultFlushInterval,
+		retention:     defaultRetention,
+	}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	store, err := provider.OpenStore(storeName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open delivery stats store: %w", err)
+	}
+
+	err = provider.SetStoreConfig(storeName, storage.StoreConfiguration{TagNames: []string{bucketTag, expiryTag}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set store configuration: %w", err)
+	}
+
+	expiryService.Register(store, expiryTag, storeName)
+
+	c := &Collector{
+		store:         store,
+		metrics:       metrics,
+		instanceID:    uuid.New().String(),
+		flushInterval: options.flushInterval,
+		retention:     options.retention,
+		done:          make(chan struct{}),
+		now:           time.Now,
+		buckets:       make(map[int64]*bucket),
+		lastDelivery:  make(map[string]time.Time),
+	}
+
+	c.Lifecycle = lifecycle.New("delivery-stats",
+		lifecycle.WithStart(c.start),
+		lifecycle.WithStop(c.stop),
+	)
+
+	return c, nil
+}
+
+// Retention returns the amount of time for which the hourly counts are kept.
+func (c *Collector) Retention() time.Duration {
+	return c.retention
+}
+
+// Delivered is invoked after an activity was delivered (or failed to be delivered) to the given inbox.
+func (c *Collector) Delivered(inbox *url.URL, latency time.Duration, err error) {
+	if err != nil {
+		c.metrics.OutboxDeliveryFailed(inbox.Host)
+	} else {
+		c.metrics.OutboxDeliveryTime(inbox.Host, latency)
+	}
+
+	now := c.now().UTC()
+	start := now.Truncate(bucketSize)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	b, ok := c.buckets[start.Unix()]
+	if !ok {
+		b = &bucket{Start: start, Inboxes: make(map[string]*Counts)}
+		c.buckets[start.Unix()] = b
+	}
+
+	counts, ok := b.Inboxes[inbox.String()]
+	if !ok {
+		counts = &Counts{}
+		b.Inboxes[inbox.String()] = counts
+	}
+
+	latencyMS := uint64(latency.Milliseconds())
+
+	counts.add(&Counts{Deliveries: 1, TotalLatencyMS: latencyMS, MaxLatencyMS: latencyMS})
+
+	if err != nil {
+		counts.Failures++
+	} else {
+		c.lastDelivery[inbox.String()] = now
+	}
+
+	c.version++
+}
+
+// Get returns the summaries of all followers for the given number of most recent hours (including the current
+// hour), sorted by average latency (slowest first). If inbox is specified then only the summary of the given
+// inbox is returned and it includes the hourly counts.
+func (c *Collector) Get(hours int, inbox string) ([]*Summary, error) {
+	from := c.now().UTC().Truncate(bucketSize).Add(-time.Duration(hours-1) * bucketSize)
+
+	buckets, err := c.query(from)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make(map[string]*Summary)
+
+	for _, b := range buckets {
+		for inboxURL, counts := range b.Inboxes {
+			if inbox != "" && inboxURL != inbox {
+				continue
+			}
+
+			s, ok := summaries[inboxURL]
+			if !ok {
+				s = &Summary{Inbox: inboxURL}
+				summaries[inboxURL] = s
+			}
+
+			s.add(counts)
+
+			if inbox != "" {
+				s.addHourly(b.Start, counts)
+			}
+		}
+	}
+
+	return c.sortedSummaries(summaries), nil
+}
+
+// addHourly adds the given counts to the hourly counts for the given hour. (The buckets of more than one
+// instance may exist for the same hour.)
+func (s *Summary) addHourly(start time.Time, counts *Counts) {
+	for _, h := range s.Hourly {
+		if h.Start.Equal(start) {
+			h.add(counts)
+
+			return
+		}
+	}
+
+	h := &HourlyCounts{Start: start, Counts: &Counts{}}
+	h.add(counts)
+
+	s.Hourly = append(s.Hourly, h)
+}
+
+func (s *Summary) add(counts *Counts) {
+	s.Deliveries += counts.Deliveries
+	s.Failures += counts.Failures
+	s.totalLatencyMS += counts.TotalLatencyMS
+
+	if counts.MaxLatencyMS > s.MaxLatencyMS {
+		s.MaxLatencyMS = counts.MaxLatencyMS
+	}
+}
+
+func (c *Collector) sortedSummaries(summaries map[string]*Summary) []*Summary {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	result := make([]*Summary, 0, len(summaries))
+
+	for _, s := range summaries {
+		if s.Deliveries > 0 {
+			s.FailureRate = float64(s.Failures) / float64(s.Deliveries)
+			s.AvgLatencyMS = s.totalLatencyMS / s.Deliveries
+		}
+
+		// The time of the last delivery is only known for the deliveries made by this instance.
+		if t, ok := c.lastDelivery[s.Inbox]; ok {
+			lastDelivery := t
+			s.LastDelivery = &lastDelivery
+		}
+
+		sort.Slice(s.Hourly, func(i, j int) bool {
+			return s.Hourly[i].Start.Before(s.Hourly[j].Start)
+		})
+
+		result = append(result, s)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].AvgLatencyMS == result[j].AvgLatencyMS {
+			return result[i].Inbox < result[j].Inbox
+		}
+
+		return result[i].AvgLatencyMS > result[j].AvgLatencyMS
+	})
+
+	return result
+}
+
+// query returns the buckets of all instances that start at or after the given time. The stored buckets of this
+// instance are replaced with the local buckets since the stored buckets may not yet have been flushed.
+func (c *Collector) query(from time.Time) ([]*bucket, error) {
+	it, err := c.store.Query(bucketTag)
+	if err != nil {
+		return nil, orberrors.NewTransient(fmt.Errorf("query delivery stats: %w", err))
+	}
+
+	defer func() {
+		if e := it.Close(); e != nil {
+			logger.Warnf("Error closing iterator: %s", e)
+		}
+	}()
+
+	local := c.localBuckets(from)
+
+	var buckets []*bucket
+
+	for {
+		ok, e := it.Next()
+		if e != nil {
+			return nil, orberrors.NewTransient(fmt.Errorf("next delivery stats: %w", e))
+		}
+
+		if !ok {
+			break
+		}
+
+		b, e := c.bucketFromIterator(it, from, local)
+		if e != nil {
+			return nil, e
+		}
+
+		if b != nil {
+			buckets = append(buckets, b)
+		}
+	}
+
+	for _, b := range local {
+		buckets = append(buckets, b)
+	}
+
+	return buckets, nil
+}
+
+func (c *Collector) bucketFromIterator(it storage.Iterator, from time.Time, local map[string]*bucket) (*bucket, error) {
+	key, err := it.Key()
+	if err != nil {
+		return nil, orberrors.NewTransient(fmt.Errorf("get key from iterator: %w", err))
+	}
+
+	if _, ok := local[key]; ok {
+		return nil, nil
+	}
+
+	tags, err := it.Tags()
+	if err != nil {
+		return nil, orberrors.NewTransient(fmt.Errorf("get tags from iterator: %w", err))
+	}
+
+	if !inRange(tags, from) {
+		return nil, nil
+	}
+
+	value, err := it.Value()
+	if err != nil {
+		return nil, orberrors.NewTransient(fmt.Errorf("get value from iterator: %w", err))
+	}
+
+	b := &bucket{}
+
+	err = json.Unmarshal(value, b)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal delivery stats [%s]: %w", key, err)
+	}
+
+	return b, nil
+}
+
+// inRange returns true if the bucket with the given tags ends after the given time, i.e. the bucket that
+// contains 'from' (the end of a bucket is exclusive) and all later buckets are in range.
+func inRange(tags []storage.Tag, from time.Time) bool {
+	for _, tag := range tags {
+		if tag.Name != bucketTag {
+			continue
+		}
+
+		start, err := strconv.ParseInt(tag.Value, 10, 64)
+		if err != nil {
+			return false
+		}
+
+		return time.Unix(start, 0).Add(bucketSize).After(from)
+	}
+
+	return false
+}
+
+// localBuckets returns copies of the buckets of this instance that start at or after the given time, keyed by
+// store key.
+func (c *Collector) localBuckets(from time.Time) map[string]*bucket {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	buckets := make(map[string]*bucket)
+
+	for start, b := range c.buckets {
+		if start < from.Unix() {
+			continue
+		}
+
+		cpy := &bucket{Start: b.Start, Inboxes: make(map[string]*Counts)}
+
+		for inbox, counts := range b.Inboxes {
+			cpy.Inboxes[inbox] = &Counts{}
+			cpy.Inboxes[inbox].add(counts)
+		}
+
+		buckets[c.bucketKey(start)] = cpy
+	}
+
+	return buckets
+}
+
+func (c *Collector) start() {
+	c.wg.Add(1)
+
+	go c.run()
+
+	logger.Infof("Started delivery stats collector [%s]", c.instanceID)
+}
+
+func (c *Collector) stop() {
+	close(c.done)
+
+	c.wg.Wait()
+
+	// Persist the counts that were collected since the last flush.
+	c.flush()
+
+	logger.Infof("Stopped delivery stats collector [%s]", c.instanceID)
+}
+
+func (c *Collector) run() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(c.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.flush()
+		case <-c.done:
+			return
+		}
+	}
+}
+
+func (c *Collector) flush() {
+	version, ops, err := c.snapshot()
+	if err != nil {
+		logger.Errorf("Error marshalling delivery stats: %s", err)
+
+		return
+	}
+
+	if len(ops) == 0 {
+		return
+	}
+
+	err = c.store.Batch(ops)
+	if err != nil {
+		// The counts are kept in memory, so they'll be persisted on the next flush.
+		logger.Warnf("Error persisting delivery stats: %s", err)
+
+		return
+	}
+
+	current := c.now().UTC().Truncate(bucketSize).Unix()
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.flushedVersion = version
+
+	if c.version != version {
+		// The counts were updated while flushing. Keep all buckets until the next flush.
+		return
+	}
+
+	// The buckets of previous hours won't change anymore and they've been persisted.
+	for start := range c.buckets {
+		if start != current {
+			delete(c.buckets, start)
+		}
+	}
+}
+
+// snapshot returns the store operations for the current buckets along with the version of the counts.
+// No operations are returned if the counts haven't changed since the last flush.
+func (c *Collector) snapshot() (uint64, []storage.Operation, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.version == c.flushedVersion {
+		return c.version, nil, nil
+	}
+
+	var ops []storage.Operation
+
+	for start, b := range c.buckets {
+		bucketBytes, err := json.Marshal(b)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		ops = append(ops, storage.Operation{
+			Key:   c.bucketKey(start),
+			Value: bucketBytes,
+			Tags: []storage.Tag{
+				{Name: bucketTag, Value: strconv.FormatInt(start, 10)},
+				{Name: expiryTag, Value: strconv.FormatInt(b.Start.Add(c.retention).Unix(), 10)},
+			},
+		})
+	}
+
+	return c.version, ops, nil
+}
+
+func (c *Collector) bucketKey(start int64) string {
+	return fmt.Sprintf("%s-%d", c.instanceID, start)
+}
@@ -0,0 +1,384 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deliverystats
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/stretchr/testify/require"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+	"github.com/trustbloc/orb/pkg/store/expiry"
+	"github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+var (
+	inbox1 = testutil.MustParseURL("https://domain1.com/services/orb/inbox")
+	inbox2 = testutil.MustParseURL("https://domain2.com/services/orb/inbox")
+)
+
+func TestNewCollector(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		es := &mockExpiryService{}
+
+		c, err := NewCollector(mem.NewProvider(), es, &mockMetrics{}, WithRetention(time.Hour))
+		require.NoError(t, err)
+		require.NotNil(t, c)
+		require.Equal(t, time.Hour, c.Retention())
+		require.Equal(t, storeName, es.storeName)
+		require.Equal(t, expiryTag, es.expiryTagName)
+	})
+
+	t.Run("Open store error", func(t *testing.T) {
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(nil, errors.New("injected open error"))
+
+		_, err := NewCollector(provider, &mockExpiryService{}, &mockMetrics{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected open error")
+	})
+
+	t.Run("Set store config error", func(t *testing.T) {
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(&mocks.Store{}, nil)
+		provider.SetStoreConfigReturns(errors.New("injected config error"))
+
+		_, err := NewCollector(provider, &mockExpiryService{}, &mockMetrics{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected config error")
+	})
+}
+
+func TestCollector_Get(t *testing.T) {
+	metrics := &mockMetrics{}
+
+	c, err := NewCollector(mem.NewProvider(), &mockExpiryService{}, metrics)
+	require.NoError(t, err)
+
+	c.Delivered(inbox1, 100*time.Millisecond, nil)
+	c.Delivered(inbox1, 300*time.Millisecond, errors.New("injected delivery error"))
+	c.Delivered(inbox2, 500*time.Millisecond, nil)
+
+	require.Equal(t, 2, metrics.deliveries)
+	require.Equal(t, 1, metrics.failures)
+
+	summaries, err := c.Get(1, "")
+	require.NoError(t, err)
+	require.Len(t, summaries, 2)
+
+	// Slowest first.
+	require.Equal(t, inbox2.String(), summaries[0].Inbox)
+	require.Equal(t, uint64(500), summaries[0].AvgLatencyMS)
+	require.NotNil(t, summaries[0].LastDelivery)
+	require.Empty(t, summaries[0].Hourly)
+
+	require.Equal(t, inbox1.String(), summaries[1].Inbox)
+	require.Equal(t, uint64(2), summaries[1].Deliveries)
+	require.Equal(t, uint64(1), summaries[1].Failures)
+	require.Equal(t, 0.5, summaries[1].FailureRate)
+	require.Equal(t, uint64(200), summaries[1].AvgLatencyMS)
+	require.Equal(t, uint64(300), summaries[1].MaxLatencyMS)
+
+	summaries, err = c.Get(1, inbox1.String())
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	require.Equal(t, inbox1.String(), summaries[0].Inbox)
+	require.Len(t, summaries[0].Hourly, 1)
+	require.Equal(t, uint64(2), summaries[0].Hourly[0].Deliveries)
+
+	summaries, err = c.Get(1, "https://domain3.com/services/orb/inbox")
+	require.NoError(t, err)
+	require.Empty(t, summaries)
+}
+
+func TestCollector_Flush(t *testing.T) {
+	t.Run("Multiple instances", func(t *testing.T) {
+		provider := mem.NewProvider()
+
+		c1, err := NewCollector(provider, &mockExpiryService{}, &mockMetrics{})
+		require.NoError(t, err)
+
+		c2, err := NewCollector(provider, &mockExpiryService{}, &mockMetrics{})
+		require.NoError(t, err)
+
+		c1.Delivered(inbox1, 100*time.Millisecond, nil)
+		c1.flush()
+
+		c2.Delivered(inbox1, 300*time.Millisecond, nil)
+
+		summaries, err := c2.Get(1, inbox1.String())
+		require.NoError(t, err)
+		require.Len(t, summaries, 1)
+		require.Equal(t, uint64(2), summaries[0].Deliveries)
+		require.Equal(t, uint64(200), summaries[0].AvgLatencyMS)
+		require.Len(t, summaries[0].Hourly, 1)
+		require.Equal(t, uint64(2), summaries[0].Hourly[0].Deliveries)
+
+		// Not yet flushed by c2.
+		summaries, err = c1.Get(1, "")
+		require.NoError(t, err)
+		require.Len(t, summaries, 1)
+		require.Equal(t, uint64(1), summaries[0].Deliveries)
+
+		c2.flush()
+
+		summaries, err = c1.Get(1, "")
+		require.NoError(t, err)
+		require.Len(t, summaries, 1)
+		require.Equal(t, uint64(2), summaries[0].Deliveries)
+	})
+
+	t.Run("Previous hours", func(t *testing.T) {
+		c, err := NewCollector(mem.NewProvider(), &mockExpiryService{}, &mockMetrics{})
+		require.NoError(t, err)
+
+		now := time.Now()
+
+		c.now = func() time.Time { return now.Add(-2 * time.Hour) }
+
+		c.Delivered(inbox1, 100*time.Millisecond, nil)
+		c.Delivered(inbox1, 100*time.Millisecond, nil)
+
+		c.now = func() time.Time { return now }
+
+		c.Delivered(inbox1, 400*time.Millisecond, nil)
+
+		c.flush()
+
+		require.Len(t, c.buckets, 1)
+
+		summaries, err := c.Get(3, inbox1.String())
+		require.NoError(t, err)
+		require.Len(t, summaries, 1)
+		require.Equal(t, uint64(3), summaries[0].Deliveries)
+		require.Equal(t, uint64(200), summaries[0].AvgLatencyMS)
+		require.Len(t, summaries[0].Hourly, 2)
+		require.Equal(t, uint64(2), summaries[0].Hourly[0].Deliveries)
+		require.Equal(t, uint64(1), summaries[0].Hourly[1].Deliveries)
+
+		summaries, err = c.Get(1, "")
+		require.NoError(t, err)
+		require.Len(t, summaries, 1)
+		require.Equal(t, uint64(1), summaries[0].Deliveries)
+	})
+
+	t.Run("Expiry tag", func(t *testing.T) {
+		store := &mocks.Store{}
+
+		c := newCollectorWithStore(t, store)
+
+		c.Delivered(inbox1, 100*time.Millisecond, nil)
+		c.flush()
+
+		require.Equal(t, 1, store.BatchCallCount())
+
+		ops := store.BatchArgsForCall(0)
+		require.Len(t, ops, 1)
+		require.Len(t, ops[0].Tags, 2)
+		require.Equal(t, bucketTag, ops[0].Tags[0].Name)
+		require.Equal(t, expiryTag, ops[0].Tags[1].Name)
+	})
+
+	t.Run("Batch error", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.BatchReturns(errors.New("injected batch error"))
+
+		c := newCollectorWithStore(t, store)
+
+		c.flush()
+		require.Zero(t, store.BatchCallCount())
+
+		c.Delivered(inbox1, 100*time.Millisecond, nil)
+
+		c.flush()
+		require.Equal(t, 1, store.BatchCallCount())
+		require.NotEqual(t, c.version, c.flushedVersion)
+
+		store.BatchReturns(nil)
+
+		c.flush()
+		require.Equal(t, 2, store.BatchCallCount())
+		require.Equal(t, c.version, c.flushedVersion)
+	})
+}
+
+func TestCollector_StartStop(t *testing.T) {
+	provider := mem.NewProvider()
+
+	c, err := NewCollector(provider, &mockExpiryService{}, &mockMetrics{}, WithFlushInterval(5*time.Millisecond))
+	require.NoError(t, err)
+
+	c2, err := NewCollector(provider, &mockExpiryService{}, &mockMetrics{})
+	require.NoError(t, err)
+
+	c.Start()
+
+	c.Delivered(inbox1, 100*time.Millisecond, nil)
+
+	time.Sleep(50 * time.Millisecond)
+
+	summaries, err := c2.Get(1, "")
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	require.Equal(t, uint64(1), summaries[0].Deliveries)
+
+	c.Delivered(inbox1, 100*time.Millisecond, nil)
+
+	c.Stop()
+
+	summaries, err = c2.Get(1, "")
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	require.Equal(t, uint64(2), summaries[0].Deliveries)
+}
+
+func TestCollector_GetError(t *testing.T) {
+	errExpected := errors.New("injected store error")
+
+	t.Run("Query error", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.QueryReturns(nil, errExpected)
+
+		_, err := newCollectorWithStore(t, store).Get(1, "")
+		require.True(t, errors.Is(err, errExpected))
+		require.True(t, orberrors.IsTransient(err))
+	})
+
+	t.Run("Iterator next error", func(t *testing.T) {
+		it := &mocks.Iterator{}
+		it.NextReturns(false, errExpected)
+		it.CloseReturns(errors.New("injected close error"))
+
+		store := &mocks.Store{}
+		store.QueryReturns(it, nil)
+
+		_, err := newCollectorWithStore(t, store).Get(1, "")
+		require.True(t, errors.Is(err, errExpected))
+	})
+
+	t.Run("Iterator key error", func(t *testing.T) {
+		it := &mocks.Iterator{}
+		it.NextReturns(true, nil)
+		it.KeyReturns("", errExpected)
+
+		store := &mocks.Store{}
+		store.QueryReturns(it, nil)
+
+		_, err := newCollectorWithStore(t, store).Get(1, "")
+		require.True(t, errors.Is(err, errExpected))
+	})
+
+	t.Run("Iterator tags error", func(t *testing.T) {
+		it := &mocks.Iterator{}
+		it.NextReturns(true, nil)
+		it.KeyReturns("key1", nil)
+		it.TagsReturns(nil, errExpected)
+
+		store := &mocks.Store{}
+		store.QueryReturns(it, nil)
+
+		_, err := newCollectorWithStore(t, store).Get(1, "")
+		require.True(t, errors.Is(err, errExpected))
+	})
+
+	t.Run("Iterator value error", func(t *testing.T) {
+		it := &mocks.Iterator{}
+		it.NextReturns(true, nil)
+		it.KeyReturns("key1", nil)
+		it.TagsReturns(currentBucketTags(), nil)
+		it.ValueReturns(nil, errExpected)
+
+		store := &mocks.Store{}
+		store.QueryReturns(it, nil)
+
+		_, err := newCollectorWithStore(t, store).Get(1, "")
+		require.True(t, errors.Is(err, errExpected))
+	})
+
+	t.Run("Unmarshal error", func(t *testing.T) {
+		it := &mocks.Iterator{}
+		it.NextReturns(true, nil)
+		it.KeyReturns("key1", nil)
+		it.TagsReturns(currentBucketTags(), nil)
+		it.ValueReturns([]byte("{"), nil)
+
+		store := &mocks.Store{}
+		store.QueryReturns(it, nil)
+
+		_, err := newCollectorWithStore(t, store).Get(1, "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal delivery stats")
+	})
+}
+
+func TestInRange(t *testing.T) {
+	now := time.Now()
+	start := now.Truncate(bucketSize)
+
+	require.True(t, inRange(currentBucketTags(), now.Add(-time.Hour)))
+	require.True(t, inRange(currentBucketTags(), now))
+	require.True(t, inRange(currentBucketTags(), start))
+	require.False(t, inRange(currentBucketTags(), start.Add(bucketSize)))
+	require.False(t, inRange(currentBucketTags(), now.Add(time.Hour)))
+	require.False(t, inRange([]storage.Tag{{Name: bucketTag, Value: "xxx"}}, now))
+	require.False(t, inRange([]storage.Tag{{Name: expiryTag, Value: "1"}}, now))
+}
+
+func newCollectorWithStore(t *testing.T, store *mocks.Store) *Collector {
+	t.Helper()
+
+	provider := &mocks.Provider{}
+	provider.OpenStoreReturns(store, nil)
+
+	c, err := NewCollector(provider, &mockExpiryService{}, &mockMetrics{})
+	require.NoError(t, err)
+
+	return c
+}
+
+func currentBucketTags() []storage.Tag {
+	return []storage.Tag{
+		{Name: bucketTag, Value: strconv.FormatInt(time.Now().Truncate(bucketSize).Unix(), 10)},
+	}
+}
+
+type mockExpiryService struct {
+	storeName     string
+	expiryTagName string
+}
+
+func (m *mockExpiryService) Register(_ storage.Store, expiryTagName, storeName string, _ ...expiry.Option) {
+	m.storeName = storeName
+	m.expiryTagName = expiryTagName
+}
+
+type mockMetrics struct {
+	mutex      sync.Mutex
+	deliveries int
+	failures   int
+}
+
+func (m *mockMetrics) OutboxDeliveryTime(string, time.Duration) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.deliveries++
+}
+
+func (m *mockMetrics) OutboxDeliveryFailed(string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.failures++
+}
@@ -0,0 +1,127 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deliverystats
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+)
+
+const (
+	// Path is the path of the delivery stats endpoint.
+	Path = "/delivery-stats"
+
+	hoursParam = "hours"
+	inboxParam = "inbox"
+
+	defaultHours = 24
+
+	internalServerErrorResponse = "Internal Server Error.\n"
+)
+
+type statsRetriever interface {
+	Get(hours int, inbox string) ([]*Summary, error)
+	Retention() time.Duration
+}
+
+// Handler implements the /delivery-stats REST endpoint. The optional 'hours' query parameter specifies the number
+// of most recent hours for which the stats are returned (defaults to 24) and the optional 'inbox' query parameter
+// restricts the result to the given inbox, in which case the hourly counts of the inbox are also returned.
+type Handler struct {
+	retriever statsRetriever
+	marshal   func(v interface{}) ([]byte, error)
+}
+
+// NewHandler returns the /delivery-stats REST handler.
+func NewHandler(retriever statsRetriever) *Handler {
+	return &Handler{
+		retriever: retriever,
+		marshal:   json.Marshal,
+	}
+}
+
+// Path returns the HTTP REST endpoint for the delivery stats handler.
+func (h *Handler) Path() string {
+	return Path
+}
+
+// Method returns the HTTP REST method for the delivery stats handler.
+func (h *Handler) Method() string {
+	return http.MethodGet
+}
+
+// Handler returns the HTTP REST handle for the delivery stats handler.
+func (h *Handler) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Handler) handle(w http.ResponseWriter, req *http.Request) {
+	hours, err := h.getHours(req)
+	if err != nil {
+		h.writeResponse(w, http.StatusBadRequest, []byte(err.Error()))
+
+		return
+	}
+
+	summaries, err := h.retriever.Get(hours, req.URL.Query().Get(inboxParam))
+	if err != nil {
+		logger.Errorf("[%s] Error retrieving delivery stats: %s", Path, err)
+
+		h.writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	respBytes, err := h.marshal(summaries)
+	if err != nil {
+		logger.Errorf("[%s] Error marshalling delivery stats: %s", Path, err)
+
+		h.writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	h.writeResponse(w, http.StatusOK, respBytes)
+}
+
+func (h *Handler) writeResponse(w http.ResponseWriter, status int, body []byte) {
+	w.WriteHeader(status)
+
+	if len(body) > 0 {
+		if _, err := w.Write(body); err != nil {
+			logger.Warnf("[%s] Unable to write response: %s", Path, err)
+
+			return
+		}
+
+		logger.Debugf("[%s] Wrote response: %s", Path, body)
+	}
+}
+
+func (h *Handler) getHours(req *http.Request) (int, error) {
+	value := req.URL.Query().Get(hoursParam)
+	if value == "" {
+		return defaultHours, nil
+	}
+
+	maxHours := int(h.retriever.Retention() / time.Hour)
+
+	hours, err := strconv.Atoi(value)
+	if err != nil || hours < 1 || hours > maxHours {
+		return 0, fmt.Errorf("invalid value for parameter '%s': %s. It must be between 1 and %d",
+			hoursParam, value, maxHours)
+	}
+
+	return hours, nil
+}
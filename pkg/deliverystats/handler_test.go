@@ -0,0 +1,88 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deliverystats
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/internal/testutil/httptestutil"
+)
+
+func TestHandler(t *testing.T) {
+	c, err := NewCollector(mem.NewProvider(), &mockExpiryService{}, &mockMetrics{})
+	require.NoError(t, err)
+
+	c.Delivered(inbox1, 100*time.Millisecond, nil)
+	c.Delivered(inbox2, 200*time.Millisecond, nil)
+
+	h := NewHandler(c)
+	require.Equal(t, Path, h.Path())
+	require.Equal(t, http.MethodGet, h.Method())
+	require.NotNil(t, h.Handler())
+
+	t.Run("All inboxes", func(t *testing.T) {
+		code, body := httptestutil.Get(t, h.handle, Path)
+		require.Equal(t, http.StatusOK, code)
+
+		var summaries []*Summary
+		require.NoError(t, json.Unmarshal(body, &summaries))
+		require.Len(t, summaries, 2)
+		require.Equal(t, inbox2.String(), summaries[0].Inbox)
+	})
+
+	t.Run("Inbox", func(t *testing.T) {
+		code, body := httptestutil.Get(t, h.handle, Path+"?hours=12&inbox="+url.QueryEscape(inbox1.String()))
+		require.Equal(t, http.StatusOK, code)
+
+		var summaries []*Summary
+		require.NoError(t, json.Unmarshal(body, &summaries))
+		require.Len(t, summaries, 1)
+		require.Equal(t, inbox1.String(), summaries[0].Inbox)
+		require.Len(t, summaries[0].Hourly, 1)
+	})
+
+	t.Run("Invalid hours", func(t *testing.T) {
+		for _, hours := range []string{"xxx", "0", "169"} {
+			code, body := httptestutil.Get(t, h.handle, Path+"?hours="+hours)
+			require.Equal(t, http.StatusBadRequest, code)
+			require.Contains(t, string(body), "invalid value for parameter 'hours'")
+		}
+	})
+
+	t.Run("Retriever error", func(t *testing.T) {
+		code, _ := httptestutil.Get(t, NewHandler(&mockRetriever{err: errors.New("injected error")}).handle, Path)
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		h := NewHandler(c)
+		h.marshal = func(v interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		code, _ := httptestutil.Get(t, h.handle, Path)
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+}
+
+type mockRetriever struct {
+	err error
+}
+
+func (m *mockRetriever) Get(int, string) ([]*Summary, error) {
+	return nil, m.err
+}
+
+func (m *mockRetriever) Retention() time.Duration {
+	return defaultRetention
+}
@@ -33,6 +33,7 @@ import (
 	"github.com/trustbloc/orb/pkg/activitypub/client/transport"
 	"github.com/trustbloc/orb/pkg/discovery/endpoint/client/models"
 	"github.com/trustbloc/orb/pkg/discovery/endpoint/restapi"
+	documentutil "github.com/trustbloc/orb/pkg/document/util"
 	"github.com/trustbloc/orb/pkg/orbclient/aoprovider"
 )
 
@@ -304,38 +305,60 @@ func (cs *Client) getEndpointAnchorOrigin(didURI string) (*models.Endpoint, erro
 		return nil, err
 	}
 
-	anchorOrigin, ok := result.(string)
-	if !ok {
+	anchorOrigins, err := documentutil.ParseAnchorOrigins(result)
+	if err != nil {
 		return nil, fmt.Errorf("get anchor origin didn't return string")
 	}
 
-	currentAnchorOrigin := anchorOrigin
-
-	var currentWebFingerResponse *restapi.JRD
+	currentAnchorOrigin, currentWebFingerResponse, err := cs.getLatestAnchorOriginWithFailover(anchorOrigins, didURI)
+	if err != nil {
+		return nil, err
+	}
 
 	for {
-		jrdLatestAnchorOrigin, errGet := cs.getLatestAnchorOrigin(currentAnchorOrigin, didURI)
-		if errGet != nil {
-			return nil, errGet
-		}
-
-		latestAnchorOrigin, ok := jrdLatestAnchorOrigin.Properties[anchorOriginProperty].(string)
+		latestAnchorOrigin, ok := currentWebFingerResponse.Properties[anchorOriginProperty].(string)
 		if !ok {
 			return nil, fmt.Errorf("%s property is not string", anchorOriginProperty)
 		}
 
 		if latestAnchorOrigin == currentAnchorOrigin {
-			currentWebFingerResponse = jrdLatestAnchorOrigin
-
 			break
 		}
 
 		currentAnchorOrigin = latestAnchorOrigin
+
+		currentWebFingerResponse, err = cs.getLatestAnchorOrigin(currentAnchorOrigin, didURI)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return cs.populateAnchorResolutionEndpoint(currentWebFingerResponse)
 }
 
+// getLatestAnchorOriginWithFailover queries each of the given anchor origins (primary first, followed by the
+// fallbacks) for the latest anchor origin and returns the first successful response along with the anchor origin
+// that was queried. The error from the primary anchor origin is returned if none of the anchor origins respond.
+func (cs *Client) getLatestAnchorOriginWithFailover(anchorOrigins []string,
+	didURI string) (string, *restapi.JRD, error) {
+	var primaryErr error
+
+	for i, anchorOrigin := range anchorOrigins {
+		jrd, err := cs.getLatestAnchorOrigin(anchorOrigin, didURI)
+		if err == nil {
+			return anchorOrigin, jrd, nil
+		}
+
+		logger.Debugf("failed to get latest anchor origin from anchor origin [%s]: %s", anchorOrigin, err)
+
+		if i == 0 {
+			primaryErr = err
+		}
+	}
+
+	return "", nil, primaryErr
+}
+
 func (cs *Client) getCIDAndSuffix(didURI string) (string, string, error) {
 	if !strings.HasPrefix(didURI, cs.namespace+docutil.NamespaceDelimiter) {
 		return "", "", fmt.Errorf("did[%s] must start with configured namespace[%s]", didURI, cs.namespace)
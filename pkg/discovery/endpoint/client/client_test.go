@@ -275,6 +275,75 @@ func TestConfigService_GetEndpointAnchorOrigin(t *testing.T) {
 		require.Equal(t, "https://localhost/resolve2", endpoint.ResolutionEndpoints[1])
 		require.Equal(t, "ipfs:cid", endpoint.AnchorURI)
 	})
+
+	t.Run("success - fallback anchor origin", func(t *testing.T) {
+		cs, err := New(nil, &mocks.CasClient{}, WithAuthToken("t1"))
+		require.NoError(t, err)
+
+		cs.httpClient = &mockHTTPClient{doFunc: func(req *http.Request) (*http.Response, error) {
+			if req.URL.Host == "unavailable.domain.com" {
+				return nil, fmt.Errorf("primary anchor origin unavailable")
+			}
+
+			if strings.Contains(req.URL.Path, "ipns/wwrrww/.well-known/host-meta.json") {
+				b, errMarshal := json.Marshal(restapi.JRD{Links: []restapi.Link{{
+					Rel:      "self",
+					Template: "https://localhost/.well-known/webfinger?resource={uri}",
+					Type:     "application/jrd+json",
+				}}})
+				require.NoError(t, errMarshal)
+				r := ioutil.NopCloser(bytes.NewReader(b))
+
+				return &http.Response{StatusCode: http.StatusOK, Body: r}, nil
+			}
+
+			if strings.Contains(req.URL.Path, ".well-known/webfinger") {
+				b, errMarshal := json.Marshal(restapi.JRD{
+					Properties: map[string]interface{}{
+						minResolvers:         float64(1),
+						anchorOriginProperty: ipnsURL,
+					},
+					Links: []restapi.Link{
+						{Href: "https://localhost/resolve1/did:orb:ipfs:a:123", Rel: "self", Type: "application/did+ld+json"},
+						{Href: "ipfs:cid", Rel: "via", Type: "application/ld+json"},
+					},
+				})
+
+				require.NoError(t, errMarshal)
+				r := ioutil.NopCloser(bytes.NewReader(b))
+
+				return &http.Response{StatusCode: http.StatusOK, Body: r}, nil
+			}
+
+			return nil, fmt.Errorf("unexpected request")
+		}}
+
+		cs.orbClient = &mockOrbClient{getAnchorOriginFunc: func(cid, suffix string) (interface{}, error) {
+			return []interface{}{"https://unavailable.domain.com", ipnsURL}, nil
+		}}
+
+		endpoint, err := cs.GetEndpointFromAnchorOrigin("did:orb:ipfs:a:123")
+		require.NoError(t, err)
+		require.Equal(t, "https://localhost/resolve1", endpoint.ResolutionEndpoints[0])
+		require.Equal(t, "ipfs:cid", endpoint.AnchorURI)
+	})
+
+	t.Run("error - all anchor origins unavailable", func(t *testing.T) {
+		cs, err := New(nil, &mocks.CasClient{}, WithAuthToken("t1"))
+		require.NoError(t, err)
+
+		cs.httpClient = &mockHTTPClient{doFunc: func(req *http.Request) (*http.Response, error) {
+			return nil, fmt.Errorf("%s unavailable", req.URL.Host)
+		}}
+
+		cs.orbClient = &mockOrbClient{getAnchorOriginFunc: func(cid, suffix string) (interface{}, error) {
+			return []interface{}{"https://primary.domain.com", "https://fallback.domain.com"}, nil
+		}}
+
+		_, err = cs.GetEndpointFromAnchorOrigin("did:orb:ipfs:a:123")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "primary.domain.com unavailable")
+	})
 }
 
 func TestConfigService_GetEndpoint(t *testing.T) { //nolint: gocyclo,gocognit,cyclop
@@ -15,6 +15,8 @@ type ErrorResponse struct {
 type WellKnownResponse struct {
 	ResolutionEndpoint string `json:"resolutionEndpoint,omitempty"`
 	OperationEndpoint  string `json:"operationEndpoint,omitempty"`
+	Namespace          string `json:"namespace,omitempty"`
+	Network            string `json:"network,omitempty"`
 }
 
 // JRD is a JSON Resource Descriptor as defined in https://datatracker.ietf.org/doc/html/rfc6415#appendix-A
@@ -55,7 +55,10 @@ const (
 
 const (
 	minResolvers = "https://trustbloc.dev/ns/min-resolvers"
+	network      = "https://trustbloc.dev/ns/network"
 	context      = "https://w3id.org/did/v1"
+
+	defaultDIDNamespace = "did:orb"
 )
 
 type cas interface {
@@ -86,6 +89,11 @@ func New(c *Config, p *Providers) (*Operation, error) {
 		return nil, fmt.Errorf("webCAS path cannot be empty")
 	}
 
+	didNamespace := c.DIDNamespace
+	if didNamespace == "" {
+		didNamespace = defaultDIDNamespace
+	}
+
 	return &Operation{
 		pubKey:                    c.PubKey,
 		kid:                       c.KID,
@@ -99,6 +107,8 @@ func New(c *Config, p *Providers) (*Operation, error) {
 		discoveryMinimumResolvers: c.DiscoveryMinimumResolvers,
 		discoveryDomains:          c.DiscoveryDomains,
 		discoveryVctDomains:       c.DiscoveryVctDomains,
+		didNamespace:              didNamespace,
+		didNetwork:                c.DIDNetwork,
 		anchorInfoRetriever:       NewAnchorInfoRetriever(p.ResourceRegistry),
 		cas:                       p.CAS,
 		anchorStore:               p.AnchorLinkStore,
@@ -122,6 +132,8 @@ type Operation struct {
 	discoveryDomains          []string
 	discoveryVctDomains       []string
 	discoveryMinimumResolvers int
+	didNamespace              string
+	didNetwork                string
 	cas                       cas
 	anchorStore               anchorLinkStore
 	wfClient                  webfingerClient
@@ -140,6 +152,14 @@ type Config struct {
 	DiscoveryDomains          []string
 	DiscoveryVctDomains       []string
 	DiscoveryMinimumResolvers int
+
+	// DIDNamespace is the namespace of the DIDs served by this node, including the network (if any).
+	// Defaults to did:orb.
+	DIDNamespace string
+
+	// DIDNetwork (optional) is the network identifier of the namespace (for example, "testnet" for
+	// did:orb:testnet).
+	DIDNetwork string
 }
 
 // Providers defines the providers for discovery operations.
@@ -173,6 +193,8 @@ func (o *Operation) wellKnownHandler(rw http.ResponseWriter, r *http.Request) {
 	writeResponse(rw, &WellKnownResponse{
 		ResolutionEndpoint: fmt.Sprintf("%s%s", o.baseURL, o.resolutionPath),
 		OperationEndpoint:  fmt.Sprintf("%s%s", o.baseURL, o.operationPath),
+		Namespace:          o.didNamespace,
+		Network:            o.didNetwork,
 	}, http.StatusOK)
 }
 
@@ -281,7 +303,7 @@ func (o *Operation) writeResponseForResourceRequest(rw http.ResponseWriter, reso
 		o.handleWebCASQuery(rw, resource)
 	case strings.HasPrefix(resource, fmt.Sprintf("%s/vct", o.baseURL)):
 		o.handleVCTQuery(rw, resource)
-	case strings.HasPrefix(resource, "did:orb:"):
+	case strings.HasPrefix(resource, o.didNamespace+":"):
 		o.handleDIDOrbQuery(rw, resource)
 	// TODO (#536): Support resources other than did:orb.
 	default:
@@ -300,7 +322,7 @@ func (o *Operation) handleDIDOrbQuery(rw http.ResponseWriter, resource string) {
 		return
 	}
 
-	did := getCanonicalDID(resource, anchorInfo.CanonicalReference)
+	did := getCanonicalDID(o.didNamespace, resource, anchorInfo.CanonicalReference)
 
 	resp := &JRD{
 		Properties: map[string]interface{}{
@@ -326,6 +348,10 @@ func (o *Operation) handleDIDOrbQuery(rw http.ResponseWriter, resource string) {
 		},
 	}
 
+	if o.didNetwork != "" {
+		resp.Properties[network] = o.didNetwork
+	}
+
 	for _, discoveryDomain := range o.appendAlternateDomains(o.discoveryDomains, anchorInfo.AnchorURI) {
 		resp.Links = append(resp.Links, Link{
 			Rel:  alternateRelation,
@@ -635,11 +661,11 @@ func contains(strs []string, str string) bool {
 	return false
 }
 
-func getCanonicalDID(resource, canonicalRef string) string {
+func getCanonicalDID(namespace, resource, canonicalRef string) string {
 	if canonicalRef != "" {
 		i := strings.LastIndex(resource, ":")
 		if i > 0 {
-			return fmt.Sprintf("did:orb:%s:%s", canonicalRef, resource[i+1:])
+			return fmt.Sprintf("%s:%s:%s", namespace, canonicalRef, resource[i+1:])
 		}
 	}
 
@@ -567,6 +567,52 @@ func TestWebFinger(t *testing.T) {
 	})
 }
 
+func TestWebFingerWithNetwork(t *testing.T) {
+	const (
+		anchorURI    = "hl:uEiALYp_C4wk2WegpfnCSoSTBdKZ1MVdDadn4rdmZl5GKzQ:uoQ-BeDVpcGZzOi8vUW1jcTZKV0RVa3l4ZWhxN1JWWmtQM052aUU0SHFSdW5SalgzOXZ1THZFSGFRTg" //nolint:lll
+		canonicalRef = "uEiBUQDRI5ttIzXbe1LZKUaZWb6yFsnMnrgDksAtQ-wCaKw"
+	)
+
+	resourceInfoProvider := newMockResourceInfoProvider().withAnchorURI(anchorURI).withCanonicalRef(canonicalRef)
+
+	c, err := restapi.New(&restapi.Config{
+		OperationPath:  "/op",
+		ResolutionPath: "/resolve",
+		WebCASPath:     "/cas",
+		BaseURL:        "http://base",
+		DIDNamespace:   "did:orb:testnet",
+		DIDNetwork:     "testnet",
+	}, &restapi.Providers{
+		ResourceRegistry: registry.New(registry.WithResourceInfoProvider(resourceInfoProvider)),
+		AnchorLinkStore:  &orbmocks.AnchorLinkStore{},
+	})
+	require.NoError(t, err)
+
+	handler := getHandler(t, c, restapi.WebFingerEndpoint)
+
+	t.Run("Success", func(t *testing.T) {
+		rr := serveHTTP(t, handler.Handler(), http.MethodGet, restapi.WebFingerEndpoint+
+			"?resource=did:orb:testnet:uAAA:suffix", nil, nil, false)
+
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		var w restapi.JRD
+
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &w))
+		require.Equal(t, "testnet", w.Properties["https://trustbloc.dev/ns/network"])
+		require.NotEmpty(t, w.Links)
+		require.Equal(t, "http://base/sidetree/v1/identifiers/did:orb:testnet:"+canonicalRef+":suffix",
+			w.Links[0].Href)
+	})
+
+	t.Run("DID from another network -> not found", func(t *testing.T) {
+		rr := serveHTTP(t, handler.Handler(), http.MethodGet, restapi.WebFingerEndpoint+
+			"?resource=did:orb:uAAA:suffix", nil, nil, false)
+
+		require.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
+
 func TestHostMeta(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		t.Run("via /.well.known/host-meta endpoint", func(t *testing.T) {
@@ -717,6 +763,31 @@ func TestWellKnown(t *testing.T) {
 	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &w))
 	require.Equal(t, w.OperationEndpoint, "http://base/op")
 	require.Equal(t, w.ResolutionEndpoint, "http://base/resolve")
+	require.Equal(t, "did:orb", w.Namespace)
+	require.Empty(t, w.Network)
+
+	t.Run("With network", func(t *testing.T) {
+		c, err := restapi.New(&restapi.Config{
+			OperationPath:  "/op",
+			ResolutionPath: "/resolve",
+			WebCASPath:     "/cas",
+			BaseURL:        "http://base",
+			DIDNamespace:   "did:orb:testnet",
+			DIDNetwork:     "testnet",
+		}, &restapi.Providers{})
+		require.NoError(t, err)
+
+		handler := getHandler(t, c, didOrbEndpoint)
+
+		rr := serveHTTP(t, handler.Handler(), http.MethodGet, didOrbEndpoint, nil, nil, false)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		var w restapi.WellKnownResponse
+
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &w))
+		require.Equal(t, "did:orb:testnet", w.Namespace)
+		require.Equal(t, "testnet", w.Network)
+	})
 }
 
 func TestWellKnownNodeInfo(t *testing.T) {
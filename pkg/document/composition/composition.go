@@ -0,0 +1,187 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package composition
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
+	"github.com/trustbloc/sidetree-core-go/pkg/patch"
+)
+
+// Error describes a failure to compose a document, i.e. the operation (and patch) that could not be applied to
+// the document, or the failure to transform the composed document into a DID document.
+type Error struct {
+	// ID is the unique suffix of the operation or, if the document couldn't be transformed, the ID of the document.
+	ID string `json:"id,omitempty"`
+	// OperationType is the type of the operation that couldn't be applied.
+	OperationType operation.Type `json:"operationType,omitempty"`
+	// TransactionTime is the transaction time of the operation that couldn't be applied.
+	TransactionTime uint64 `json:"transactionTime,omitempty"`
+	// TransactionNumber is the transaction number of the operation that couldn't be applied.
+	TransactionNumber uint64 `json:"transactionNumber,omitempty"`
+	// PatchIndex is the index of the patch (within the operation) that couldn't be applied or nil if the failure
+	// isn't caused by a patch.
+	PatchIndex *int `json:"patchIndex,omitempty"`
+	// Action is the action of the patch that couldn't be applied (for example, "add-public-keys").
+	Action string `json:"action,omitempty"`
+	// Reason describes the failure.
+	Reason string `json:"reason"`
+
+	cause error
+}
+
+// Error returns the error message.
+func (e *Error) Error() string {
+	msg := "document composition error"
+
+	if e.OperationType != "" {
+		msg += fmt.Sprintf(" - %s operation [%s] at transaction time %d, number %d",
+			e.OperationType, e.ID, e.TransactionTime, e.TransactionNumber)
+	} else if e.ID != "" {
+		msg += fmt.Sprintf(" - document [%s]", e.ID)
+	}
+
+	if e.PatchIndex != nil {
+		msg += fmt.Sprintf(", patch %d [%s]", *e.PatchIndex, e.Action)
+	}
+
+	return msg + ": " + e.Reason
+}
+
+// Unwrap returns the underlying error.
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Composer is a document composer that applies the patches one at a time so that the patch that couldn't be
+// applied is identified in the returned Error.
+type Composer struct {
+	composer protocol.DocumentComposer
+}
+
+// NewComposer returns a new composer that delegates to the given composer.
+func NewComposer(composer protocol.DocumentComposer) *Composer {
+	return &Composer{composer: composer}
+}
+
+// ApplyPatches applies the given patches to the document. If a patch can't be applied then an Error is returned
+// with the index and action of the patch.
+func (c *Composer) ApplyPatches(doc document.Document, patches []patch.Patch) (document.Document, error) {
+	if len(patches) == 0 {
+		return c.composer.ApplyPatches(doc, patches)
+	}
+
+	for i, p := range patches {
+		result, err := c.composer.ApplyPatches(doc, []patch.Patch{p})
+		if err != nil {
+			return nil, newPatchError(i, p, err)
+		}
+
+		doc = result
+	}
+
+	return doc, nil
+}
+
+func newPatchError(index int, p patch.Patch, err error) *Error {
+	e := &Error{
+		PatchIndex: &index,
+		Reason:     err.Error(),
+		cause:      err,
+	}
+
+	if action, actionErr := p.GetAction(); actionErr == nil {
+		e.Action = string(action)
+	}
+
+	return e
+}
+
+// OperationApplier is an operation applier that adds the details of the operation to the Error that's returned
+// if the patches of the operation can't be applied, and records the operation for review by an operator.
+type OperationApplier struct {
+	applier  protocol.OperationApplier
+	recorder *Recorder
+}
+
+// NewOperationApplier returns a new operation applier that delegates to the given applier. The recorder is optional.
+func NewOperationApplier(applier protocol.OperationApplier, recorder *Recorder) *OperationApplier {
+	return &OperationApplier{
+		applier:  applier,
+		recorder: recorder,
+	}
+}
+
+// Apply applies the given operation to the resolution model.
+func (a *OperationApplier) Apply(op *operation.AnchoredOperation,
+	rm *protocol.ResolutionModel) (*protocol.ResolutionModel, error) {
+	result, err := a.applier.Apply(op, rm)
+	if err == nil {
+		return result, nil
+	}
+
+	var patchErr *Error
+
+	// Only the failure to apply a patch is a composition error. Other failures (for example, an invalid
+	// commitment) are expected while the processor searches for the next valid operation.
+	if !errors.As(err, &patchErr) {
+		return nil, err
+	}
+
+	e := *patchErr
+	e.ID = op.UniqueSuffix
+	e.OperationType = op.Type
+	e.TransactionTime = op.TransactionTime
+	e.TransactionNumber = op.TransactionNumber
+	e.cause = err
+
+	a.recorder.Record(&e, op.OperationRequest)
+
+	return nil, &e
+}
+
+// Transformer is a document transformer that returns an Error if the composed document can't be transformed
+// (for example, if the document contains an invalid public key), and records the failure for review by
+// an operator.
+type Transformer struct {
+	transformer protocol.DocumentTransformer
+	recorder    *Recorder
+}
+
+// NewTransformer returns a new document transformer that delegates to the given transformer. The recorder
+// is optional.
+func NewTransformer(transformer protocol.DocumentTransformer, recorder *Recorder) *Transformer {
+	return &Transformer{
+		transformer: transformer,
+		recorder:    recorder,
+	}
+}
+
+// TransformDocument transforms the resolution model into a resolution result.
+func (t *Transformer) TransformDocument(rm *protocol.ResolutionModel,
+	info protocol.TransformationInfo) (*document.ResolutionResult, error) {
+	result, err := t.transformer.TransformDocument(rm, info)
+	if err == nil {
+		return result, nil
+	}
+
+	id, _ := info[document.IDProperty].(string)
+
+	e := &Error{
+		ID:     id,
+		Reason: err.Error(),
+		cause:  err,
+	}
+
+	t.recorder.Record(e, nil)
+
+	return nil, e
+}
@@ -0,0 +1,218 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package composition
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
+	"github.com/trustbloc/sidetree-core-go/pkg/patch"
+)
+
+const suffix = "EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A"
+
+func TestComposer_ApplyPatches(t *testing.T) {
+	patches := []patch.Patch{
+		{patch.ActionKey: patch.Replace},
+		{patch.ActionKey: patch.AddPublicKeys},
+		{patch.ActionKey: patch.AddServiceEndpoints},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		c := NewComposer(&mockComposer{})
+
+		doc, err := c.ApplyPatches(document.Document{}, patches)
+		require.NoError(t, err)
+		require.Equal(t, 3, doc["patches"])
+
+		doc, err = c.ApplyPatches(document.Document{}, nil)
+		require.NoError(t, err)
+		require.Nil(t, doc["patches"])
+	})
+
+	t.Run("Patch error", func(t *testing.T) {
+		errExpected := errors.New("invalid public key")
+
+		c := NewComposer(&mockComposer{failAction: patch.AddPublicKeys, err: errExpected})
+
+		doc, err := c.ApplyPatches(document.Document{}, patches)
+		require.Error(t, err)
+		require.Nil(t, doc)
+		require.True(t, errors.Is(err, errExpected))
+
+		var e *Error
+
+		require.True(t, errors.As(err, &e))
+		require.NotNil(t, e.PatchIndex)
+		require.Equal(t, 1, *e.PatchIndex)
+		require.Equal(t, string(patch.AddPublicKeys), e.Action)
+		require.Equal(t, "invalid public key", e.Reason)
+		require.EqualError(t, err, "document composition error, patch 1 [add-public-keys]: invalid public key")
+	})
+}
+
+func TestOperationApplier_Apply(t *testing.T) {
+	op := &operation.AnchoredOperation{
+		Type:              operation.TypeUpdate,
+		UniqueSuffix:      suffix,
+		OperationRequest:  []byte(`{"type":"update"}`),
+		TransactionTime:   1000,
+		TransactionNumber: 2,
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		r := NewRecorder(0)
+
+		rm, err := NewOperationApplier(&mockApplier{}, r).Apply(op, &protocol.ResolutionModel{})
+		require.NoError(t, err)
+		require.NotNil(t, rm)
+		require.Empty(t, r.Recent())
+	})
+
+	t.Run("Patch error", func(t *testing.T) {
+		r := NewRecorder(0)
+
+		index := 1
+
+		a := NewOperationApplier(&mockApplier{err: &Error{PatchIndex: &index, Action: "add-public-keys",
+			Reason: "invalid public key"}}, r)
+
+		rm, err := a.Apply(op, &protocol.ResolutionModel{})
+		require.Error(t, err)
+		require.Nil(t, rm)
+		require.EqualError(t, err, "document composition error - update operation ["+suffix+"] at transaction "+
+			"time 1000, number 2, patch 1 [add-public-keys]: invalid public key")
+
+		var e *Error
+
+		require.True(t, errors.As(err, &e))
+		require.Equal(t, suffix, e.ID)
+		require.Equal(t, operation.TypeUpdate, e.OperationType)
+		require.Equal(t, uint64(1000), e.TransactionTime)
+		require.Equal(t, uint64(2), e.TransactionNumber)
+
+		records := r.Recent()
+		require.Len(t, records, 1)
+		require.Equal(t, e, records[0].Error)
+		require.Equal(t, `{"type":"update"}`, string(records[0].Operation))
+	})
+
+	t.Run("Other error", func(t *testing.T) {
+		r := NewRecorder(0)
+
+		errExpected := errors.New("invalid commitment")
+
+		_, err := NewOperationApplier(&mockApplier{err: errExpected}, r).Apply(op, &protocol.ResolutionModel{})
+		require.Equal(t, errExpected, err)
+		require.Empty(t, r.Recent())
+	})
+
+	t.Run("No recorder", func(t *testing.T) {
+		index := 0
+
+		_, err := NewOperationApplier(&mockApplier{err: &Error{PatchIndex: &index, Reason: "invalid patch"}}, nil).
+			Apply(op, &protocol.ResolutionModel{})
+		require.Error(t, err)
+	})
+}
+
+func TestTransformer_TransformDocument(t *testing.T) {
+	did := "did:orb:uAAA:" + suffix
+	info := protocol.TransformationInfo{document.IDProperty: did}
+
+	t.Run("Success", func(t *testing.T) {
+		r := NewRecorder(0)
+
+		result, err := NewTransformer(&mockTransformer{}, r).TransformDocument(&protocol.ResolutionModel{}, info)
+		require.NoError(t, err)
+		require.NotNil(t, result)
+		require.Empty(t, r.Recent())
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		r := NewRecorder(0)
+
+		errExpected := errors.New("public key type not supported")
+
+		result, err := NewTransformer(&mockTransformer{err: errExpected}, r).
+			TransformDocument(&protocol.ResolutionModel{}, info)
+		require.Error(t, err)
+		require.Nil(t, result)
+		require.True(t, errors.Is(err, errExpected))
+		require.EqualError(t, err, "document composition error - document ["+did+"]: public key type not supported")
+
+		var e *Error
+
+		require.True(t, errors.As(err, &e))
+		require.Equal(t, did, e.ID)
+		require.Nil(t, e.PatchIndex)
+
+		records := r.Recent()
+		require.Len(t, records, 1)
+		require.Nil(t, records[0].Operation)
+	})
+}
+
+type mockComposer struct {
+	failAction patch.Action
+	err        error
+}
+
+func (m *mockComposer) ApplyPatches(doc document.Document, patches []patch.Patch) (document.Document, error) {
+	result := make(document.Document)
+
+	for k, v := range doc {
+		result[k] = v
+	}
+
+	for _, p := range patches {
+		action, err := p.GetAction()
+		if err != nil {
+			return nil, err
+		}
+
+		if action == m.failAction {
+			return nil, m.err
+		}
+
+		n, _ := result["patches"].(int)
+
+		result["patches"] = n + 1
+	}
+
+	return result, nil
+}
+
+type mockApplier struct {
+	err error
+}
+
+func (m *mockApplier) Apply(_ *operation.AnchoredOperation,
+	rm *protocol.ResolutionModel) (*protocol.ResolutionModel, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	return rm, nil
+}
+
+type mockTransformer struct {
+	err error
+}
+
+func (m *mockTransformer) TransformDocument(*protocol.ResolutionModel,
+	protocol.TransformationInfo) (*document.ResolutionResult, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	return &document.ResolutionResult{}, nil
+}
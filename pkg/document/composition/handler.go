@@ -0,0 +1,100 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package composition
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+)
+
+const (
+	// Path is the path of the composition errors endpoint.
+	Path = "/composition-errors"
+
+	// suffixParam is the (optional) query parameter that filters the errors by the unique suffix of the DID.
+	suffixParam = "suffix"
+
+	internalServerErrorResponse = "Internal Server Error."
+)
+
+// Response contains the recent composition errors.
+type Response struct {
+	Errors []*Record `json:"errors"`
+}
+
+type errorRetriever interface {
+	Recent() []*Record
+}
+
+// Handler implements a REST handler that returns the recent composition errors, for example:
+// GET /composition-errors?suffix=EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A.
+type Handler struct {
+	recorder errorRetriever
+	marshal  func(v interface{}) ([]byte, error)
+}
+
+// NewHandler returns a new composition errors handler.
+func NewHandler(recorder errorRetriever) *Handler {
+	return &Handler{
+		recorder: recorder,
+		marshal:  json.Marshal,
+	}
+}
+
+// Path returns the HTTP REST endpoint of the handler.
+func (h *Handler) Path() string {
+	return Path
+}
+
+// Method returns the HTTP method, which is always GET.
+func (h *Handler) Method() string {
+	return http.MethodGet
+}
+
+// Handler returns the HTTP REST handle.
+func (h *Handler) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Handler) handle(w http.ResponseWriter, req *http.Request) {
+	suffix := req.URL.Query().Get(suffixParam)
+
+	resp := &Response{
+		Errors: []*Record{},
+	}
+
+	for _, r := range h.recorder.Recent() {
+		// The ID of the error is either the unique suffix or the DID.
+		if suffix == "" || strings.Contains(r.Error.ID, suffix) {
+			resp.Errors = append(resp.Errors, r)
+		}
+	}
+
+	respBytes, err := h.marshal(resp)
+	if err != nil {
+		logger.Errorf("[%s] Error marshalling composition errors: %s", Path, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	writeResponse(w, http.StatusOK, respBytes)
+}
+
+func writeResponse(w http.ResponseWriter, status int, body []byte) {
+	w.WriteHeader(status)
+
+	if _, err := w.Write(body); err != nil {
+		logger.Warnf("[%s] Unable to write response: %s", Path, err)
+	}
+}
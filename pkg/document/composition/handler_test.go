@@ -0,0 +1,79 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package composition
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+
+	"github.com/trustbloc/orb/pkg/internal/testutil/httptestutil"
+)
+
+func TestHandler(t *testing.T) {
+	r := NewRecorder(10)
+
+	index := 1
+
+	r.Record(&Error{ID: suffix, OperationType: operation.TypeUpdate, PatchIndex: &index,
+		Action: "add-public-keys", Reason: "invalid public key"}, []byte(`{"type":"update"}`))
+	r.Record(&Error{ID: "did:orb:uAAA:EiBnUaQfVSUo4Ks4fIAcBbXUnPpIUtBCBRAXtE3n3x1vNw",
+		Reason: "public key type not supported"}, nil)
+
+	h := NewHandler(r)
+	require.Equal(t, Path, h.Path())
+	require.Equal(t, http.MethodGet, h.Method())
+	require.NotNil(t, h.Handler())
+
+	t.Run("Success", func(t *testing.T) {
+		code, body := httptestutil.Get(t, h.handle, Path)
+		require.Equal(t, http.StatusOK, code)
+
+		resp := &Response{}
+		require.NoError(t, json.Unmarshal(body, resp))
+		require.Len(t, resp.Errors, 2)
+	})
+
+	t.Run("Filter by suffix", func(t *testing.T) {
+		code, body := httptestutil.Get(t, h.handle, Path+"?suffix="+suffix)
+		require.Equal(t, http.StatusOK, code)
+
+		resp := &Response{}
+		require.NoError(t, json.Unmarshal(body, resp))
+		require.Len(t, resp.Errors, 1)
+		require.Equal(t, suffix, resp.Errors[0].Error.ID)
+		require.Equal(t, operation.TypeUpdate, resp.Errors[0].Error.OperationType)
+		require.NotNil(t, resp.Errors[0].Error.PatchIndex)
+		require.Equal(t, 1, *resp.Errors[0].Error.PatchIndex)
+		require.Equal(t, "add-public-keys", resp.Errors[0].Error.Action)
+		require.Equal(t, `{"type":"update"}`, string(resp.Errors[0].Operation))
+
+		code, body = httptestutil.Get(t, h.handle, Path+"?suffix=EiBnUaQfVSUo4Ks4fIAcBbXUnPpIUtBCBRAXtE3n3x1vNw")
+		require.Equal(t, http.StatusOK, code)
+
+		resp = &Response{}
+		require.NoError(t, json.Unmarshal(body, resp))
+		require.Len(t, resp.Errors, 1)
+		require.Equal(t, "public key type not supported", resp.Errors[0].Error.Reason)
+
+		code, body = httptestutil.Get(t, h.handle, Path+"?suffix=unknown")
+		require.Equal(t, http.StatusOK, code)
+		require.Contains(t, string(body), `"errors":[]`)
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		h := NewHandler(r)
+		h.marshal = func(v interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		code, _ := httptestutil.Get(t, h.handle, Path)
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+}
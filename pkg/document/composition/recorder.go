@@ -0,0 +1,149 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package composition
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/trustbloc/edge-core/pkg/log"
+)
+
+var logger = log.New("doc-composition")
+
+const defaultMaxRecords = 100
+
+// Record holds a composition error along with the operation that couldn't be applied, so that the operation
+// may be reviewed by an operator.
+type Record struct {
+	Error *Error `json:"error"`
+	// Operation is the operation request (if any) that couldn't be applied.
+	Operation json.RawMessage `json:"operation,omitempty"`
+	// FirstSeen is the time at which the error first occurred.
+	FirstSeen time.Time `json:"firstSeen"`
+	// LastSeen is the time at which the error last occurred.
+	LastSeen time.Time `json:"lastSeen"`
+	// Count is the number of times that the error occurred. The same error occurs each time that the document
+	// is resolved.
+	Count uint64 `json:"count"`
+}
+
+// Recorder keeps the most recent composition errors (in memory) so that the operations that couldn't be
+// applied may be reviewed by an operator. Since the same error occurs each time that the document is resolved,
+// the errors are deduplicated.
+type Recorder struct {
+	maxRecords int
+	timeNow    func() time.Time
+
+	mutex   sync.RWMutex
+	records map[string]*Record
+}
+
+// NewRecorder returns a new composition error recorder which keeps the given number of errors. If maxRecords
+// is zero then a default of 100 is used.
+func NewRecorder(maxRecords int) *Recorder {
+	if maxRecords <= 0 {
+		maxRecords = defaultMaxRecords
+	}
+
+	return &Recorder{
+		maxRecords: maxRecords,
+		timeNow:    time.Now,
+		records:    make(map[string]*Record),
+	}
+}
+
+// Record records the given error along with the operation request (if any). If the recorder is full then
+// the error that occurred least recently is discarded. This function does nothing if the recorder is nil.
+func (r *Recorder) Record(e *Error, opRequest []byte) {
+	if r == nil {
+		return
+	}
+
+	now := r.timeNow()
+	key := keyOf(e)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if record, ok := r.records[key]; ok {
+		logger.Debugf("Composition error occurred again: %s", e)
+
+		record.LastSeen = now
+		record.Count++
+
+		return
+	}
+
+	logger.Warnf("Recording composition error: %s", e)
+
+	if len(r.records) >= r.maxRecords {
+		r.evictOldest()
+	}
+
+	record := &Record{
+		Error:     e,
+		FirstSeen: now,
+		LastSeen:  now,
+		Count:     1,
+	}
+
+	if json.Valid(opRequest) {
+		record.Operation = opRequest
+	}
+
+	r.records[key] = record
+}
+
+// Recent returns the recorded errors, the most recent first.
+func (r *Recorder) Recent() []*Record {
+	r.mutex.RLock()
+
+	records := make([]*Record, 0, len(r.records))
+
+	for _, record := range r.records {
+		// A copy is returned since the record is updated when the error occurs again.
+		c := *record
+		records = append(records, &c)
+	}
+
+	r.mutex.RUnlock()
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].LastSeen.After(records[j].LastSeen)
+	})
+
+	return records
+}
+
+func (r *Recorder) evictOldest() {
+	var (
+		oldestKey string
+		oldest    time.Time
+	)
+
+	for key, record := range r.records {
+		if oldestKey == "" || record.LastSeen.Before(oldest) {
+			oldestKey, oldest = key, record.LastSeen
+		}
+	}
+
+	delete(r.records, oldestKey)
+}
+
+func keyOf(e *Error) string {
+	patchIndex := -1
+	if e.PatchIndex != nil {
+		patchIndex = *e.PatchIndex
+	}
+
+	return fmt.Sprintf("%s|%s|%d|%d|%d|%s",
+		e.ID, e.OperationType, e.TransactionTime, e.TransactionNumber, patchIndex, e.Reason)
+}
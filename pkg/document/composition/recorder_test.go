@@ -0,0 +1,74 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package composition
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+)
+
+func TestRecorder(t *testing.T) {
+	now := time.Now()
+
+	r := NewRecorder(2)
+	require.Equal(t, 2, r.maxRecords)
+
+	r.timeNow = func() time.Time { return now }
+
+	e1 := &Error{ID: "suffix1", OperationType: operation.TypeUpdate, TransactionTime: 1, Reason: "invalid patch"}
+	e2 := &Error{ID: "suffix2", OperationType: operation.TypeUpdate, TransactionTime: 2, Reason: "invalid patch"}
+	e3 := &Error{ID: "did:orb:uAAA:suffix3", Reason: "invalid public key"}
+
+	r.Record(e1, []byte(`{"type":"update"}`))
+
+	now = now.Add(time.Second)
+
+	r.Record(e2, []byte("not JSON"))
+
+	records := r.Recent()
+	require.Len(t, records, 2)
+	require.Equal(t, e2, records[0].Error)
+	require.Nil(t, records[0].Operation)
+	require.Equal(t, e1, records[1].Error)
+	require.Equal(t, `{"type":"update"}`, string(records[1].Operation))
+
+	// The same error occurs again.
+	now = now.Add(time.Second)
+
+	r.Record(&Error{ID: "suffix1", OperationType: operation.TypeUpdate, TransactionTime: 1, Reason: "invalid patch"},
+		nil)
+
+	records = r.Recent()
+	require.Len(t, records, 2)
+	require.Equal(t, e1, records[0].Error)
+	require.Equal(t, uint64(2), records[0].Count)
+	require.True(t, now.Equal(records[0].LastSeen))
+	require.True(t, now.Add(-2*time.Second).Equal(records[0].FirstSeen))
+
+	// The least recent error (e2) is discarded.
+	now = now.Add(time.Second)
+
+	r.Record(e3, nil)
+
+	records = r.Recent()
+	require.Len(t, records, 2)
+	require.Equal(t, e3, records[0].Error)
+	require.Equal(t, e1, records[1].Error)
+
+	t.Run("Default size", func(t *testing.T) {
+		require.Equal(t, defaultMaxRecords, NewRecorder(0).maxRecords)
+	})
+
+	t.Run("Nil recorder", func(t *testing.T) {
+		var nilRecorder *Recorder
+
+		require.NotPanics(t, func() { nilRecorder.Record(e1, nil) })
+	})
+}
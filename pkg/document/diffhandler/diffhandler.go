@@ -0,0 +1,320 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package diffhandler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/trustbloc/edge-core/pkg/log"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+	"github.com/trustbloc/sidetree-core-go/pkg/dochandler"
+	"github.com/trustbloc/sidetree-core-go/pkg/processor"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+
+	"github.com/trustbloc/orb/pkg/document/util"
+)
+
+var logger = log.New("did-diff-handler")
+
+const (
+	idParam          = "id"
+	fromVersionParam = "fromVersion"
+	toVersionParam   = "toVersion"
+
+	badRequestResponse          = "Bad Request."
+	notFoundResponse            = "Not Found."
+	internalServerErrorResponse = "Internal Server Error."
+)
+
+var errNotFound = errors.New("not found")
+
+type operationStore interface {
+	Get(suffix string) ([]*operation.AnchoredOperation, error)
+}
+
+// Response contains the JSON Patch (RFC 6902) that transforms the 'from' version of a DID document
+// into the 'to' version.
+type Response struct {
+	ID          string           `json:"id"`
+	FromVersion int              `json:"fromVersion"`
+	ToVersion   int              `json:"toVersion"`
+	Patch       []PatchOperation `json:"patch"`
+}
+
+// Handler returns the changes between two versions of a DID document. The versions of a DID document are
+// derived from its published operation history (in anchoring order): version 0 is the document produced by
+// the create operation and version N is the document after the Nth subsequent operation was applied.
+//
+// The 'toVersion' query parameter defaults to the latest version and the 'fromVersion' query parameter
+// defaults to the version before 'toVersion'.
+type Handler struct {
+	basePath  string
+	namespace string
+	opStore   operationStore
+	pc        protocol.Client
+	marshal   func(v interface{}) ([]byte, error)
+}
+
+// New returns a new DID document diff handler. The endpoint of the handler is <basePath>/{id}/diff.
+func New(basePath, namespace string, opStore operationStore, pc protocol.Client) *Handler {
+	return &Handler{
+		basePath:  basePath,
+		namespace: namespace,
+		opStore:   opStore,
+		pc:        pc,
+		marshal:   json.Marshal,
+	}
+}
+
+// Path returns the HTTP REST endpoint for the DID document diff service.
+func (h *Handler) Path() string {
+	return fmt.Sprintf("%s/{%s}/diff", h.basePath, idParam)
+}
+
+// Method returns the HTTP method, which is always GET.
+func (h *Handler) Method() string {
+	return http.MethodGet
+}
+
+// Handler returns the HTTP REST handle for the DID document diff service.
+func (h *Handler) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Handler) handle(w http.ResponseWriter, req *http.Request) {
+	id := mux.Vars(req)[idParam]
+
+	if !strings.HasPrefix(id, h.namespace+":") {
+		logger.Debugf("[%s] Invalid DID [%s] for namespace [%s]", h.Path(), id, h.namespace)
+
+		writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	suffix, err := util.GetSuffix(id)
+	if err != nil {
+		logger.Debugf("[%s] Invalid DID [%s]: %s", h.Path(), id, err)
+
+		writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	ops, err := h.getOperations(suffix)
+	if err != nil {
+		if errors.Is(err, errNotFound) {
+			logger.Debugf("[%s] DID not found [%s]", h.Path(), id)
+
+			writeResponse(w, http.StatusNotFound, []byte(notFoundResponse))
+
+			return
+		}
+
+		logger.Errorf("[%s] Error retrieving operations for DID [%s]: %s", h.Path(), id, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	fromVersion, toVersion, err := getVersions(req, len(ops)-1)
+	if err != nil {
+		logger.Debugf("[%s] Invalid version for DID [%s]: %s", h.Path(), id, err)
+
+		writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	resp, err := h.diff(id, suffix, ops, fromVersion, toVersion)
+	if err != nil {
+		logger.Errorf("[%s] Error computing diff for DID [%s] from version %d to version %d: %s",
+			h.Path(), id, fromVersion, toVersion, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	respBytes, err := h.marshal(resp)
+	if err != nil {
+		logger.Errorf("[%s] Error marshalling response: %s", h.Path(), err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, respBytes)
+}
+
+func (h *Handler) getOperations(suffix string) ([]*operation.AnchoredOperation, error) {
+	ops, err := h.opStore.Get(suffix)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, errNotFound
+		}
+
+		return nil, err
+	}
+
+	if len(ops) == 0 {
+		return nil, errNotFound
+	}
+
+	return sortOperations(ops), nil
+}
+
+func (h *Handler) diff(id, suffix string, ops []*operation.AnchoredOperation, fromVersion,
+	toVersion int) (*Response, error) {
+	fromDoc, err := h.resolveVersion(id, suffix, ops, fromVersion)
+	if err != nil {
+		return nil, fmt.Errorf("resolve version %d: %w", fromVersion, err)
+	}
+
+	toDoc, err := h.resolveVersion(id, suffix, ops, toVersion)
+	if err != nil {
+		return nil, fmt.Errorf("resolve version %d: %w", toVersion, err)
+	}
+
+	return &Response{
+		ID:          id,
+		FromVersion: fromVersion,
+		ToVersion:   toVersion,
+		Patch:       createPatch(fromDoc, toDoc),
+	}, nil
+}
+
+// resolveVersion resolves the DID document using only the operations up to and including the given version
+// and returns the document as generic JSON.
+func (h *Handler) resolveVersion(id, suffix string, ops []*operation.AnchoredOperation,
+	version int) (interface{}, error) {
+	pv, err := h.pc.Current()
+	if err != nil {
+		return nil, fmt.Errorf("get current protocol version: %w", err)
+	}
+
+	rm, err := processor.New(h.namespace, &historyStore{ops: ops[:version+1]}, h.pc).Resolve(suffix)
+	if err != nil {
+		return nil, err
+	}
+
+	ti := dochandler.GetTransformationInfoForPublished(h.namespace, id, suffix, rm)
+
+	result, err := pv.DocumentTransformer().TransformDocument(rm, ti)
+	if err != nil {
+		return nil, fmt.Errorf("transform document: %w", err)
+	}
+
+	docBytes, err := json.Marshal(result.Document)
+	if err != nil {
+		return nil, fmt.Errorf("marshal document: %w", err)
+	}
+
+	var doc interface{}
+
+	err = json.Unmarshal(docBytes, &doc)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal document: %w", err)
+	}
+
+	return doc, nil
+}
+
+func getVersions(req *http.Request, latest int) (fromVersion, toVersion int, err error) {
+	toVersion, err = getVersion(req, toVersionParam, latest)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	defaultFrom := toVersion - 1
+	if defaultFrom < 0 {
+		defaultFrom = 0
+	}
+
+	fromVersion, err = getVersion(req, fromVersionParam, defaultFrom)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if toVersion > latest {
+		return 0, 0, fmt.Errorf("%s [%d] is greater than the latest version [%d]", toVersionParam, toVersion, latest)
+	}
+
+	if fromVersion > toVersion {
+		return 0, 0, fmt.Errorf("%s [%d] is greater than %s [%d]",
+			fromVersionParam, fromVersion, toVersionParam, toVersion)
+	}
+
+	return fromVersion, toVersion, nil
+}
+
+func getVersion(req *http.Request, param string, defaultValue int) (int, error) {
+	value := req.URL.Query().Get(param)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	version, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value for %s [%s]: %w", param, value, err)
+	}
+
+	if version < 0 {
+		return 0, fmt.Errorf("%s [%d] must not be negative", param, version)
+	}
+
+	return version, nil
+}
+
+func sortOperations(ops []*operation.AnchoredOperation) []*operation.AnchoredOperation {
+	sorted := make([]*operation.AnchoredOperation, len(ops))
+	copy(sorted, ops)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].TransactionTime != sorted[j].TransactionTime {
+			return sorted[i].TransactionTime < sorted[j].TransactionTime
+		}
+
+		return sorted[i].TransactionNumber < sorted[j].TransactionNumber
+	})
+
+	return sorted
+}
+
+// historyStore is an operation store that returns a fixed set of operations for a DID so that the DID
+// document may be resolved at a given version.
+type historyStore struct {
+	ops []*operation.AnchoredOperation
+}
+
+func (s *historyStore) Get(string) ([]*operation.AnchoredOperation, error) {
+	return s.ops, nil
+}
+
+func writeJSONResponse(w http.ResponseWriter, status int, body []byte) {
+	w.Header().Set("Content-Type", "application/json")
+
+	writeResponse(w, status, body)
+}
+
+func writeResponse(w http.ResponseWriter, status int, body []byte) {
+	w.WriteHeader(status)
+
+	if _, err := w.Write(body); err != nil {
+		logger.Warnf("Unable to write response: %s", err)
+	}
+}
@@ -0,0 +1,290 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package diffhandler
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+	"github.com/trustbloc/sidetree-core-go/pkg/commitment"
+	"github.com/trustbloc/sidetree-core-go/pkg/patch"
+	"github.com/trustbloc/sidetree-core-go/pkg/util/ecsigner"
+	"github.com/trustbloc/sidetree-core-go/pkg/util/pubkey"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/client"
+
+	orbmocks "github.com/trustbloc/orb/pkg/mocks"
+)
+
+const (
+	basePath     = "/1.0/identifiers"
+	namespace    = "did:orb"
+	anchorOrigin = "https://orb.domain1.com"
+
+	sha2_256 = 18
+)
+
+func TestHandler(t *testing.T) {
+	pc, err := orbmocks.NewMockProtocolClientProvider().WithAllowedOrigins([]string{"*"}).ForNamespace(namespace)
+	require.NoError(t, err)
+
+	d := newTestDID(t, pc)
+	d.update(t, 2)
+	d.update(t, 3)
+
+	did := fmt.Sprintf("%s:uAAA:%s", namespace, d.suffix)
+
+	h := New(basePath, namespace, d.opStore, pc)
+	require.Equal(t, basePath+"/{id}/diff", h.Path())
+	require.Equal(t, http.MethodGet, h.Method())
+	require.NotNil(t, h.Handler())
+
+	t.Run("success - default versions", func(t *testing.T) {
+		status, resp := getDiff(t, h, did, "")
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, did, resp.ID)
+		require.Equal(t, 1, resp.FromVersion)
+		require.Equal(t, 2, resp.ToVersion)
+		require.Len(t, resp.Patch, 1)
+		require.Equal(t, OpAdd, resp.Patch[0].Op)
+		require.Equal(t, "/service/1", resp.Patch[0].Path)
+	})
+
+	t.Run("success - from create", func(t *testing.T) {
+		status, resp := getDiff(t, h, did, "?fromVersion=0&toVersion=1")
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, 0, resp.FromVersion)
+		require.Equal(t, 1, resp.ToVersion)
+		require.NotEmpty(t, resp.Patch)
+
+		for _, op := range resp.Patch {
+			require.Contains(t, op.Path, "/service")
+		}
+	})
+
+	t.Run("success - same version", func(t *testing.T) {
+		status, resp := getDiff(t, h, did, "?fromVersion=1&toVersion=1")
+		require.Equal(t, http.StatusOK, status)
+		require.NotNil(t, resp.Patch)
+		require.Empty(t, resp.Patch)
+	})
+
+	t.Run("success - create only", func(t *testing.T) {
+		d2 := newTestDID(t, pc)
+
+		status, resp := getDiff(t, New(basePath, namespace, d2.opStore, pc),
+			fmt.Sprintf("%s:uAAA:%s", namespace, d2.suffix), "")
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, 0, resp.FromVersion)
+		require.Equal(t, 0, resp.ToVersion)
+		require.Empty(t, resp.Patch)
+	})
+
+	t.Run("invalid DID", func(t *testing.T) {
+		status, _ := getDiff(t, h, "did:other:"+d.suffix, "")
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("invalid versions", func(t *testing.T) {
+		for _, query := range []string{
+			"?toVersion=x", "?fromVersion=x", "?fromVersion=-1", "?toVersion=3", "?fromVersion=2&toVersion=1",
+		} {
+			status, _ := getDiff(t, h, did, query)
+			require.Equalf(t, http.StatusBadRequest, status, "query: %s", query)
+		}
+	})
+
+	t.Run("DID not found", func(t *testing.T) {
+		status, _ := getDiff(t, h, namespace+":uAAA:EiDOQXC2GnoVyHwIRbjhLx_cNc6vmZaS04SZjZdlLLAPRg", "")
+		require.Equal(t, http.StatusNotFound, status)
+	})
+
+	t.Run("operation store error", func(t *testing.T) {
+		status, _ := getDiff(t, New(basePath, namespace, &mockOpStore{err: errors.New("injected store error")}, pc),
+			did, "")
+		require.Equal(t, http.StatusInternalServerError, status)
+	})
+
+	t.Run("no operations", func(t *testing.T) {
+		status, _ := getDiff(t, New(basePath, namespace, &mockOpStore{}, pc), did, "")
+		require.Equal(t, http.StatusNotFound, status)
+	})
+
+	t.Run("resolve error", func(t *testing.T) {
+		ops, err := d.opStore.Get(d.suffix)
+		require.NoError(t, err)
+
+		// Without the create operation the DID cannot be resolved.
+		status, _ := getDiff(t, New(basePath, namespace, &mockOpStore{ops: ops[1:]}, pc), did, "")
+		require.Equal(t, http.StatusInternalServerError, status)
+	})
+
+	t.Run("marshal error", func(t *testing.T) {
+		h := New(basePath, namespace, d.opStore, pc)
+		h.marshal = func(v interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		status, _ := getDiff(t, h, did, "")
+		require.Equal(t, http.StatusInternalServerError, status)
+	})
+}
+
+func getDiff(t *testing.T, h *Handler, did, query string) (int, *Response) {
+	t.Helper()
+
+	rw := httptest.NewRecorder()
+
+	req := httptest.NewRequest(http.MethodGet, basePath+"/"+did+"/diff"+query, nil)
+
+	h.Handler()(rw, mux.SetURLVars(req, map[string]string{idParam: did}))
+
+	result := rw.Result()
+
+	respBytes, err := ioutil.ReadAll(result.Body)
+	require.NoError(t, err)
+	require.NoError(t, result.Body.Close())
+
+	if result.StatusCode != http.StatusOK {
+		return result.StatusCode, nil
+	}
+
+	require.Equal(t, "application/json", result.Header.Get("Content-Type"))
+
+	resp := &Response{}
+	require.NoError(t, json.Unmarshal(respBytes, resp))
+
+	return result.StatusCode, resp
+}
+
+type testDID struct {
+	suffix     string
+	updateKey  *ecdsa.PrivateKey
+	opStore    *orbmocks.MockOperationStore
+	numUpdates int
+}
+
+// newTestDID creates a DID and anchors the create operation at transaction time 1.
+func newTestDID(t *testing.T, pc protocol.Client) *testDID {
+	t.Helper()
+
+	_, recoveryCommitment := newKeyAndCommitment(t)
+	updateKey, updateCommitment := newKeyAndCommitment(t)
+
+	p, err := patch.NewReplacePatch(`{}`)
+	require.NoError(t, err)
+
+	req, err := client.NewCreateRequest(&client.CreateRequestInfo{
+		Patches:            []patch.Patch{p},
+		RecoveryCommitment: recoveryCommitment,
+		UpdateCommitment:   updateCommitment,
+		MultihashCode:      sha2_256,
+		AnchorOrigin:       anchorOrigin,
+	})
+	require.NoError(t, err)
+
+	pv, err := pc.Current()
+	require.NoError(t, err)
+
+	op, err := pv.OperationParser().Parse(namespace, req)
+	require.NoError(t, err)
+
+	d := &testDID{
+		suffix:    op.UniqueSuffix,
+		updateKey: updateKey,
+		opStore:   orbmocks.NewMockOperationStore(),
+	}
+
+	d.anchor(t, d.newOperation(operation.TypeCreate, req), 1)
+
+	return d
+}
+
+// update creates an update operation (which adds a service) and anchors it at the given transaction time.
+func (d *testDID) update(t *testing.T, txnTime uint64) {
+	t.Helper()
+
+	d.numUpdates++
+
+	p, err := patch.NewAddServiceEndpointsPatch(fmt.Sprintf(
+		`[{"id":"svc%d","type":"type","serviceEndpoint":"http://www.example.com"}]`, d.numUpdates))
+	require.NoError(t, err)
+
+	nextUpdateKey, nextUpdateCommitment := newKeyAndCommitment(t)
+
+	updatePubKey, err := pubkey.GetPublicKeyJWK(&d.updateKey.PublicKey)
+	require.NoError(t, err)
+
+	revealValue, err := commitment.GetRevealValue(updatePubKey, sha2_256)
+	require.NoError(t, err)
+
+	req, err := client.NewUpdateRequest(&client.UpdateRequestInfo{
+		DidSuffix:        d.suffix,
+		RevealValue:      revealValue,
+		UpdateCommitment: nextUpdateCommitment,
+		UpdateKey:        updatePubKey,
+		Patches:          []patch.Patch{p},
+		MultihashCode:    sha2_256,
+		Signer:           ecsigner.New(d.updateKey, "ES256", ""),
+	})
+	require.NoError(t, err)
+
+	d.updateKey = nextUpdateKey
+
+	d.anchor(t, d.newOperation(operation.TypeUpdate, req), txnTime)
+}
+
+func (d *testDID) newOperation(opType operation.Type, req []byte) *operation.AnchoredOperation {
+	return &operation.AnchoredOperation{
+		Type:             opType,
+		UniqueSuffix:     d.suffix,
+		OperationRequest: req,
+		AnchorOrigin:     anchorOrigin,
+	}
+}
+
+func (d *testDID) anchor(t *testing.T, op *operation.AnchoredOperation, txnTime uint64) {
+	t.Helper()
+
+	op.TransactionTime = txnTime
+	op.CanonicalReference = fmt.Sprintf("hl:uEiA329wd6Aj36YRmp7NGkeB5ADnVt8ARdMZMPzfXsjw%04d", txnTime)
+
+	require.NoError(t, d.opStore.Put([]*operation.AnchoredOperation{op}))
+}
+
+func newKeyAndCommitment(t *testing.T) (*ecdsa.PrivateKey, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	pubKey, err := pubkey.GetPublicKeyJWK(&key.PublicKey)
+	require.NoError(t, err)
+
+	c, err := commitment.GetCommitment(pubKey, sha2_256)
+	require.NoError(t, err)
+
+	return key, c
+}
+
+type mockOpStore struct {
+	ops []*operation.AnchoredOperation
+	err error
+}
+
+func (m *mockOpStore) Get(string) ([]*operation.AnchoredOperation, error) {
+	return m.ops, m.err
+}
@@ -0,0 +1,139 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package diffhandler
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// JSON Patch (RFC 6902) operations that are generated by the diff.
+const (
+	OpAdd     = "add"
+	OpRemove  = "remove"
+	OpReplace = "replace"
+)
+
+// nolint: gochecknoglobals
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// PatchOperation is a JSON Patch (RFC 6902) operation.
+type PatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// MarshalJSON marshals the operation. The value is omitted from a 'remove' operation and is always included
+// (even if null) in an 'add' or 'replace' operation.
+func (o PatchOperation) MarshalJSON() ([]byte, error) {
+	if o.Op == OpRemove {
+		return json.Marshal(&struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+		}{Op: o.Op, Path: o.Path})
+	}
+
+	return json.Marshal(&struct {
+		Op    string      `json:"op"`
+		Path  string      `json:"path"`
+		Value interface{} `json:"value"`
+	}{Op: o.Op, Path: o.Path, Value: o.Value})
+}
+
+// createPatch returns the JSON Patch that transforms the 'from' document into the 'to' document. Both documents
+// must be the result of unmarshalling JSON into an interface{}. The patch is valid but not necessarily minimal,
+// for example, an element that's removed from the middle of an array results in a 'replace' of each of the
+// subsequent elements and a 'remove' of the last element.
+func createPatch(from, to interface{}) []PatchOperation {
+	return diff("", from, to, []PatchOperation{})
+}
+
+func diff(path string, from, to interface{}, patch []PatchOperation) []PatchOperation {
+	switch f := from.(type) {
+	case map[string]interface{}:
+		if t, ok := to.(map[string]interface{}); ok {
+			return diffObjects(path, f, t, patch)
+		}
+	case []interface{}:
+		if t, ok := to.([]interface{}); ok {
+			return diffArrays(path, f, t, patch)
+		}
+	}
+
+	if reflect.DeepEqual(from, to) {
+		return patch
+	}
+
+	return append(patch, PatchOperation{Op: OpReplace, Path: path, Value: to})
+}
+
+func diffObjects(path string, from, to map[string]interface{}, patch []PatchOperation) []PatchOperation {
+	for _, key := range sortedKeys(from) {
+		if _, ok := to[key]; !ok {
+			patch = append(patch, PatchOperation{Op: OpRemove, Path: childPath(path, key)})
+		}
+	}
+
+	for _, key := range sortedKeys(to) {
+		fromValue, ok := from[key]
+		if !ok {
+			patch = append(patch, PatchOperation{Op: OpAdd, Path: childPath(path, key), Value: to[key]})
+
+			continue
+		}
+
+		patch = diff(childPath(path, key), fromValue, to[key], patch)
+	}
+
+	return patch
+}
+
+func diffArrays(path string, from, to []interface{}, patch []PatchOperation) []PatchOperation {
+	common := len(from)
+	if len(to) < common {
+		common = len(to)
+	}
+
+	for i := 0; i < common; i++ {
+		patch = diff(indexPath(path, i), from[i], to[i], patch)
+	}
+
+	for i := common; i < len(to); i++ {
+		patch = append(patch, PatchOperation{Op: OpAdd, Path: indexPath(path, i), Value: to[i]})
+	}
+
+	// Remove from the end so that the indexes of the remaining elements don't change.
+	for i := len(from) - 1; i >= common; i-- {
+		patch = append(patch, PatchOperation{Op: OpRemove, Path: indexPath(path, i)})
+	}
+
+	return patch
+}
+
+func childPath(path, key string) string {
+	return path + "/" + pointerEscaper.Replace(key)
+}
+
+func indexPath(path string, i int) string {
+	return fmt.Sprintf("%s/%d", path, i)
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
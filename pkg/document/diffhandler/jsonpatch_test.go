@@ -0,0 +1,85 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package diffhandler
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreatePatch(t *testing.T) {
+	t.Run("no changes", func(t *testing.T) {
+		patch := createPatch(unmarshal(t, `{"a":[1,{"b":"c"}]}`), unmarshal(t, `{"a":[1,{"b":"c"}]}`))
+		require.NotNil(t, patch)
+		require.Empty(t, patch)
+	})
+
+	t.Run("object", func(t *testing.T) {
+		patch := createPatch(
+			unmarshal(t, `{"a":"1","b":"2","c":{"d":"3"}}`),
+			unmarshal(t, `{"a":"1","b":"4","c":{"e":"5"},"f~/g":null}`),
+		)
+
+		require.Equal(t, []PatchOperation{
+			{Op: OpReplace, Path: "/b", Value: "4"},
+			{Op: OpRemove, Path: "/c/d"},
+			{Op: OpAdd, Path: "/c/e", Value: "5"},
+			{Op: OpAdd, Path: "/f~0~1g", Value: nil},
+		}, patch)
+	})
+
+	t.Run("array", func(t *testing.T) {
+		patch := createPatch(unmarshal(t, `{"a":[1,2]}`), unmarshal(t, `{"a":[1,3,4,5]}`))
+
+		require.Equal(t, []PatchOperation{
+			{Op: OpReplace, Path: "/a/1", Value: float64(3)},
+			{Op: OpAdd, Path: "/a/2", Value: float64(4)},
+			{Op: OpAdd, Path: "/a/3", Value: float64(5)},
+		}, patch)
+
+		patch = createPatch(unmarshal(t, `{"a":[1,2,3]}`), unmarshal(t, `{"a":[1]}`))
+
+		require.Equal(t, []PatchOperation{
+			{Op: OpRemove, Path: "/a/2"},
+			{Op: OpRemove, Path: "/a/1"},
+		}, patch)
+	})
+
+	t.Run("type change", func(t *testing.T) {
+		patch := createPatch(unmarshal(t, `{"a":[1]}`), unmarshal(t, `{"a":{"b":1}}`))
+
+		require.Equal(t, []PatchOperation{
+			{Op: OpReplace, Path: "/a", Value: map[string]interface{}{"b": float64(1)}},
+		}, patch)
+	})
+}
+
+func TestPatchOperation_MarshalJSON(t *testing.T) {
+	patch := []PatchOperation{
+		{Op: OpAdd, Path: "/a", Value: "1"},
+		{Op: OpReplace, Path: "/b", Value: nil},
+		{Op: OpRemove, Path: "/c"},
+	}
+
+	patchBytes, err := json.Marshal(patch)
+	require.NoError(t, err)
+	require.Equal(t,
+		`[{"op":"add","path":"/a","value":"1"},{"op":"replace","path":"/b","value":null},{"op":"remove","path":"/c"}]`,
+		string(patchBytes))
+}
+
+func unmarshal(t *testing.T, doc string) interface{} {
+	t.Helper()
+
+	var v interface{}
+
+	require.NoError(t, json.Unmarshal([]byte(doc), &v))
+
+	return v
+}
@@ -0,0 +1,108 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package opvalidator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
+)
+
+// Result contains the result of validating an operation.
+type Result struct {
+	// Valid is true if the operation passed all validations.
+	Valid bool `json:"valid"`
+	// Error contains the reason why the operation is not valid.
+	Error string `json:"error,omitempty"`
+	// Type is the type of the operation (create, update, recover or deactivate).
+	Type operation.Type `json:"type,omitempty"`
+	// DIDSuffix is the unique suffix of the DID.
+	DIDSuffix string `json:"didSuffix,omitempty"`
+	// ProtocolVersion is the genesis time of the protocol version that was used to validate the operation.
+	ProtocolVersion uint64 `json:"protocolVersion"`
+	// Document is the document that results from a create operation.
+	Document document.Document `json:"document,omitempty"`
+}
+
+// Validate validates a Sidetree operation without adding it to the operation queue. The operation is parsed with
+// the given protocol version, which validates the size limits, the key formats, the signed data and the delta and
+// commitment values. A create operation is also applied in order to validate the patches and the resulting document.
+// Note that the signature of an update, recover or deactivate operation is verified only when the operation is
+// applied to the current document, so it is not verified here.
+//
+// This function is shared by the REST and gRPC APIs so that operations are validated in the same way regardless
+// of how they're submitted.
+func Validate(namespace string, opBytes []byte, pv protocol.Version) *Result {
+	result := &Result{ProtocolVersion: pv.Protocol().GenesisTime}
+
+	op, err := pv.OperationParser().Parse(namespace, opBytes)
+	if err != nil {
+		result.Error = fmt.Sprintf("parse operation: %s", err)
+
+		return result
+	}
+
+	result.Type = op.Type
+	result.DIDSuffix = op.UniqueSuffix
+
+	if op.Type == operation.TypeCreate {
+		doc, err := validateCreate(op, pv)
+		if err != nil {
+			result.Error = err.Error()
+
+			return result
+		}
+
+		result.Document = doc
+	} else if err := pv.DocumentValidator().IsValidPayload(op.OperationRequest); err != nil {
+		result.Error = fmt.Sprintf("validate payload: %s", err)
+
+		return result
+	}
+
+	result.Valid = true
+
+	return result
+}
+
+// validateCreate applies the create operation (in the same way as the document handler does when it returns
+// the response for a create request) and validates the resulting document.
+func validateCreate(op *operation.Operation, pv protocol.Version) (document.Document, error) {
+	anchoredOp := &operation.AnchoredOperation{
+		Type:             op.Type,
+		UniqueSuffix:     op.UniqueSuffix,
+		OperationRequest: op.OperationRequest,
+		TransactionTime:  uint64(time.Now().Unix()),
+		ProtocolVersion:  pv.Protocol().GenesisTime,
+		AnchorOrigin:     op.AnchorOrigin,
+	}
+
+	rm, err := pv.OperationApplier().Apply(anchoredOp, &protocol.ResolutionModel{})
+	if err != nil {
+		return nil, fmt.Errorf("apply create operation: %w", err)
+	}
+
+	if len(rm.Doc) == 0 {
+		return nil, errors.New("applying the delta resulted in an empty document (most likely due to an invalid patch)")
+	}
+
+	docBytes, err := json.Marshal(rm.Doc)
+	if err != nil {
+		return nil, fmt.Errorf("marshal document: %w", err)
+	}
+
+	if err := pv.DocumentValidator().IsValidOriginalDocument(docBytes); err != nil {
+		return nil, fmt.Errorf("validate document: %w", err)
+	}
+
+	return rm.Doc, nil
+}
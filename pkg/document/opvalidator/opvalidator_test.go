@@ -0,0 +1,90 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package opvalidator
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-core-go/pkg/commitment"
+	"github.com/trustbloc/sidetree-core-go/pkg/util/pubkey"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/client"
+
+	orbmocks "github.com/trustbloc/orb/pkg/mocks"
+)
+
+const (
+	namespace = "did:orb"
+
+	sha2_256 = 18
+
+	validDoc = `{"service":[{"id":"svc1","type":"type","serviceEndpoint":"http://www.example.com"}]}`
+)
+
+func TestValidate(t *testing.T) {
+	pc, err := orbmocks.NewMockProtocolClientProvider().WithAllowedOrigins([]string{"*"}).ForNamespace(namespace)
+	require.NoError(t, err)
+
+	pv, err := pc.Current()
+	require.NoError(t, err)
+
+	t.Run("valid create operation", func(t *testing.T) {
+		result := Validate(namespace, newCreateRequest(t, validDoc), pv)
+		require.True(t, result.Valid)
+		require.Empty(t, result.Error)
+		require.Equal(t, operation.TypeCreate, result.Type)
+		require.NotEmpty(t, result.DIDSuffix)
+		require.NotEmpty(t, result.Document)
+		require.Equal(t, pv.Protocol().GenesisTime, result.ProtocolVersion)
+	})
+
+	t.Run("invalid operation", func(t *testing.T) {
+		result := Validate(namespace, []byte(`{"type":"create"}`), pv)
+		require.False(t, result.Valid)
+		require.Contains(t, result.Error, "parse operation")
+	})
+
+	t.Run("invalid document", func(t *testing.T) {
+		result := Validate(namespace, newCreateRequest(t, `{"service":[{"id":"svc1"}]}`), pv)
+		require.False(t, result.Valid)
+		require.NotEmpty(t, result.Error)
+	})
+}
+
+func newCreateRequest(t *testing.T, doc string) []byte {
+	t.Helper()
+
+	reqBytes, err := client.NewCreateRequest(&client.CreateRequestInfo{
+		OpaqueDocument:     doc,
+		RecoveryCommitment: newCommitment(t),
+		UpdateCommitment:   newCommitment(t),
+		MultihashCode:      sha2_256,
+		AnchorOrigin:       "https://orb.domain1.com",
+	})
+	require.NoError(t, err)
+
+	return reqBytes
+}
+
+func newCommitment(t *testing.T) string {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	pubKey, err := pubkey.GetPublicKeyJWK(&key.PublicKey)
+	require.NoError(t, err)
+
+	c, err := commitment.GetCommitment(pubKey, sha2_256)
+	require.NoError(t, err)
+
+	return c
+}
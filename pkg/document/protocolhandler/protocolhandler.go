@@ -0,0 +1,185 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package protocolhandler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/trustbloc/edge-core/pkg/log"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+)
+
+var logger = log.New("protocol-handler")
+
+const (
+	versionPath    = "/version"
+	parametersPath = "/protocol-parameters"
+
+	serviceName = "orb"
+
+	internalServerErrorResponse = "Internal Server Error."
+)
+
+// VersionResponse contains the version of the Orb server and the active Sidetree protocol version.
+type VersionResponse struct {
+	Name            string `json:"name"`
+	Version         string `json:"version"`
+	ProtocolVersion string `json:"protocolVersion"`
+}
+
+// ParametersResponse contains the parameters of the active Sidetree protocol version.
+type ParametersResponse struct {
+	ProtocolVersion string `json:"protocolVersion"`
+	// GenesisTime is the (anchoring) time from which the protocol version is active.
+	GenesisTime uint64 `json:"genesisTime"`
+	// MultihashAlgorithms are the supported multihash algorithm codes. The first algorithm is used
+	// to calculate new commitments and suffixes.
+	MultihashAlgorithms    []uint `json:"multihashAlgorithms"`
+	MaxOperationCount      uint   `json:"maxOperationCount"`
+	MaxOperationSize       uint   `json:"maxOperationSize"`
+	MaxOperationHashLength uint   `json:"maxOperationHashLength"`
+	MaxDeltaSize           uint   `json:"maxDeltaSize"`
+	MaxCasURILength        uint   `json:"maxCasUriLength"`
+	// MaxOperationTimeDelta is the maximum time (in seconds) that an operation may be anchored after
+	// its 'anchorFrom' time.
+	MaxOperationTimeDelta       uint64   `json:"maxOperationTimeDelta"`
+	CompressionAlgorithm        string   `json:"compressionAlgorithm"`
+	MaxChunkFileSize            uint     `json:"maxChunkFileSize"`
+	MaxProvisionalIndexFileSize uint     `json:"maxProvisionalIndexFileSize"`
+	MaxCoreIndexFileSize        uint     `json:"maxCoreIndexFileSize"`
+	MaxProofFileSize            uint     `json:"maxProofFileSize"`
+	Patches                     []string `json:"patches"`
+	SignatureAlgorithms         []string `json:"signatureAlgorithms"`
+	KeyAlgorithms               []string `json:"keyAlgorithms"`
+}
+
+type handler struct {
+	basePath string
+	path     string
+	pc       protocol.Client
+	marshal  func(v interface{}) ([]byte, error)
+	response func(pv protocol.Version) interface{}
+}
+
+// Path returns the HTTP REST endpoint of the handler.
+func (h *handler) Path() string {
+	return h.basePath + h.path
+}
+
+// Method returns the HTTP method, which is always GET.
+func (h *handler) Method() string {
+	return http.MethodGet
+}
+
+// Handler returns the HTTP REST handle.
+func (h *handler) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *handler) handle(w http.ResponseWriter, _ *http.Request) {
+	pv, err := h.pc.Current()
+	if err != nil {
+		logger.Errorf("[%s] Error getting current protocol version: %s", h.Path(), err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	respBytes, err := h.marshal(h.response(pv))
+	if err != nil {
+		logger.Errorf("[%s] Error marshalling response: %s", h.Path(), err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	writeResponse(w, http.StatusOK, respBytes)
+}
+
+// VersionHandler returns the version of the Orb server along with the active protocol version.
+type VersionHandler struct {
+	*handler
+}
+
+// NewVersionHandler returns a new version handler. The endpoint of the handler is <basePath>/version.
+func NewVersionHandler(basePath, version string, pc protocol.Client) *VersionHandler {
+	return &VersionHandler{
+		handler: &handler{
+			basePath: basePath,
+			path:     versionPath,
+			pc:       pc,
+			marshal:  json.Marshal,
+			response: func(pv protocol.Version) interface{} {
+				return &VersionResponse{
+					Name:            serviceName,
+					Version:         version,
+					ProtocolVersion: pv.Version(),
+				}
+			},
+		},
+	}
+}
+
+// ParametersHandler returns the parameters of the active protocol version so that clients don't have to
+// hard-code limits such as the maximum operation size.
+type ParametersHandler struct {
+	*handler
+}
+
+// NewParametersHandler returns a new protocol parameters handler. The endpoint of the handler is
+// <basePath>/protocol-parameters.
+func NewParametersHandler(basePath string, pc protocol.Client) *ParametersHandler {
+	return &ParametersHandler{
+		handler: &handler{
+			basePath: basePath,
+			path:     parametersPath,
+			pc:       pc,
+			marshal:  json.Marshal,
+			response: func(pv protocol.Version) interface{} {
+				return newParametersResponse(pv)
+			},
+		},
+	}
+}
+
+func newParametersResponse(pv protocol.Version) *ParametersResponse {
+	p := pv.Protocol()
+
+	return &ParametersResponse{
+		ProtocolVersion:             pv.Version(),
+		GenesisTime:                 p.GenesisTime,
+		MultihashAlgorithms:         p.MultihashAlgorithms,
+		MaxOperationCount:           p.MaxOperationCount,
+		MaxOperationSize:            p.MaxOperationSize,
+		MaxOperationHashLength:      p.MaxOperationHashLength,
+		MaxDeltaSize:                p.MaxDeltaSize,
+		MaxCasURILength:             p.MaxCasURILength,
+		MaxOperationTimeDelta:       p.MaxOperationTimeDelta,
+		CompressionAlgorithm:        p.CompressionAlgorithm,
+		MaxChunkFileSize:            p.MaxChunkFileSize,
+		MaxProvisionalIndexFileSize: p.MaxProvisionalIndexFileSize,
+		MaxCoreIndexFileSize:        p.MaxCoreIndexFileSize,
+		MaxProofFileSize:            p.MaxProofFileSize,
+		Patches:                     p.Patches,
+		SignatureAlgorithms:         p.SignatureAlgorithms,
+		KeyAlgorithms:               p.KeyAlgorithms,
+	}
+}
+
+func writeResponse(w http.ResponseWriter, status int, body []byte) {
+	w.WriteHeader(status)
+
+	if _, err := w.Write(body); err != nil {
+		logger.Warnf("Unable to write response: %s", err)
+	}
+}
@@ -0,0 +1,156 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package protocolhandler
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+	coremocks "github.com/trustbloc/sidetree-core-go/pkg/mocks"
+
+	orbmocks "github.com/trustbloc/orb/pkg/mocks"
+)
+
+const (
+	namespace = "did:orb"
+	basePath  = "/sidetree/v1"
+	version   = "v1.0.0"
+)
+
+func TestVersionHandler(t *testing.T) {
+	pc, err := orbmocks.NewMockProtocolClientProvider().ForNamespace(namespace)
+	require.NoError(t, err)
+
+	h := NewVersionHandler(basePath, version, pc)
+	require.Equal(t, "/sidetree/v1/version", h.Path())
+	require.Equal(t, http.MethodGet, h.Method())
+	require.NotNil(t, h.Handler())
+
+	t.Run("Success", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+
+		h.Handler()(rw, httptest.NewRequest(http.MethodGet, h.Path(), nil))
+
+		result := rw.Result()
+		defer result.Body.Close()
+
+		require.Equal(t, http.StatusOK, result.StatusCode)
+		require.Equal(t, "application/json", result.Header.Get("Content-Type"))
+
+		resp := &VersionResponse{}
+		require.NoError(t, json.Unmarshal(readBody(t, result), resp))
+		require.Equal(t, serviceName, resp.Name)
+		require.Equal(t, version, resp.Version)
+	})
+
+	t.Run("Protocol client error", func(t *testing.T) {
+		h := NewVersionHandler(basePath, version, &mockProtocolClient{err: errors.New("injected protocol error")})
+
+		rw := httptest.NewRecorder()
+
+		h.Handler()(rw, httptest.NewRequest(http.MethodGet, h.Path(), nil))
+
+		result := rw.Result()
+		defer result.Body.Close()
+
+		require.Equal(t, http.StatusInternalServerError, result.StatusCode)
+		require.Equal(t, internalServerErrorResponse, string(readBody(t, result)))
+	})
+}
+
+func TestParametersHandler(t *testing.T) {
+	pc, err := orbmocks.NewMockProtocolClientProvider().ForNamespace(namespace)
+	require.NoError(t, err)
+
+	pv, err := pc.Current()
+	require.NoError(t, err)
+
+	p := pv.Protocol()
+
+	h := NewParametersHandler(basePath, pc)
+	require.Equal(t, "/sidetree/v1/protocol-parameters", h.Path())
+	require.Equal(t, http.MethodGet, h.Method())
+	require.NotNil(t, h.Handler())
+
+	t.Run("Success", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+
+		h.Handler()(rw, httptest.NewRequest(http.MethodGet, h.Path(), nil))
+
+		result := rw.Result()
+		defer result.Body.Close()
+
+		require.Equal(t, http.StatusOK, result.StatusCode)
+		require.Equal(t, "application/json", result.Header.Get("Content-Type"))
+
+		resp := &ParametersResponse{}
+		require.NoError(t, json.Unmarshal(readBody(t, result), resp))
+		require.Equal(t, p.GenesisTime, resp.GenesisTime)
+		require.Equal(t, p.MultihashAlgorithms, resp.MultihashAlgorithms)
+		require.Equal(t, p.MaxOperationCount, resp.MaxOperationCount)
+		require.Equal(t, p.MaxOperationSize, resp.MaxOperationSize)
+		require.Equal(t, p.MaxDeltaSize, resp.MaxDeltaSize)
+		require.Equal(t, p.MaxChunkFileSize, resp.MaxChunkFileSize)
+		require.Equal(t, p.Patches, resp.Patches)
+	})
+
+	t.Run("Protocol client error", func(t *testing.T) {
+		h := NewParametersHandler(basePath, &mockProtocolClient{err: errors.New("injected protocol error")})
+
+		rw := httptest.NewRecorder()
+
+		h.Handler()(rw, httptest.NewRequest(http.MethodGet, h.Path(), nil))
+
+		result := rw.Result()
+		defer result.Body.Close()
+
+		require.Equal(t, http.StatusInternalServerError, result.StatusCode)
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		h := NewParametersHandler(basePath, &mockProtocolClient{pv: &coremocks.ProtocolVersion{}})
+
+		h.marshal = func(interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		rw := httptest.NewRecorder()
+
+		h.Handler()(rw, httptest.NewRequest(http.MethodGet, h.Path(), nil))
+
+		result := rw.Result()
+		defer result.Body.Close()
+
+		require.Equal(t, http.StatusInternalServerError, result.StatusCode)
+	})
+}
+
+func readBody(t *testing.T, result *http.Response) []byte {
+	t.Helper()
+
+	body, err := ioutil.ReadAll(result.Body)
+	require.NoError(t, err)
+
+	return body
+}
+
+type mockProtocolClient struct {
+	pv  protocol.Version
+	err error
+}
+
+func (m *mockProtocolClient) Current() (protocol.Version, error) {
+	return m.pv, m.err
+}
+
+func (m *mockProtocolClient) Get(uint64) (protocol.Version, error) {
+	return m.pv, m.err
+}
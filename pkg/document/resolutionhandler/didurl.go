@@ -0,0 +1,178 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resolutionhandler
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
+)
+
+const (
+	didPrefix          = "did:"
+	minDIDParts        = 3
+	serviceProperty    = "service"
+	idProperty         = "id"
+	endpointProperty   = "serviceEndpoint"
+	fragmentSeparator  = "#"
+	parameterSeparator = "?"
+)
+
+// errInvalidDID indicates that the DID within the DID URL is not valid.
+var errInvalidDID = errors.New("invalid DID")
+
+// verificationProperties contains the properties of a DID document that may contain (embedded) verification
+// methods or services which may be dereferenced with a fragment.
+var verificationProperties = []string{ //nolint:gochecknoglobals
+	"verificationMethod",
+	"authentication",
+	"assertionMethod",
+	"keyAgreement",
+	"capabilityInvocation",
+	"capabilityDelegation",
+	serviceProperty,
+}
+
+// supportedParams contains the DID parameters that may be included in a DID URL.
+var supportedParams = map[string]struct{}{ //nolint:gochecknoglobals
+	serviceParam:     {},
+	relativeRefParam: {},
+}
+
+// didURL is a parsed DID URL, i.e. did:method:id?param=value#fragment.
+type didURL struct {
+	did      string
+	params   map[string]string
+	fragment string
+}
+
+func (u *didURL) String() string {
+	s := u.did
+
+	if len(u.params) > 0 {
+		values := url.Values{}
+
+		for k, v := range u.params {
+			values.Set(k, v)
+		}
+
+		s += parameterSeparator + values.Encode()
+	}
+
+	if u.fragment != "" {
+		s += fragmentSeparator + u.fragment
+	}
+
+	return s
+}
+
+func parseDIDURL(value string) (*didURL, error) {
+	u := &didURL{did: value, params: make(map[string]string)}
+
+	if i := strings.Index(u.did, fragmentSeparator); i >= 0 {
+		u.fragment = u.did[i+1:]
+		u.did = u.did[:i]
+
+		if u.fragment == "" {
+			return nil, fmt.Errorf("empty fragment in DID URL [%s]", value)
+		}
+	}
+
+	if i := strings.Index(u.did, parameterSeparator); i >= 0 {
+		query := u.did[i+1:]
+		u.did = u.did[:i]
+
+		values, err := url.ParseQuery(query)
+		if err != nil {
+			return nil, fmt.Errorf("invalid parameters in DID URL [%s]: %w", value, err)
+		}
+
+		for name := range values {
+			if _, ok := supportedParams[name]; !ok {
+				return nil, fmt.Errorf("unsupported DID parameter [%s] in DID URL [%s]", name, value)
+			}
+
+			u.params[name] = values.Get(name)
+		}
+	}
+
+	if !strings.HasPrefix(u.did, didPrefix) || len(strings.Split(u.did, ":")) < minDIDParts {
+		return nil, fmt.Errorf("%w [%s]", errInvalidDID, u.did)
+	}
+
+	return u, nil
+}
+
+// findResource returns the verification method or service in the DID document with the given fragment. The ID of
+// the resource may either be relative (#fragment) or absolute (did#fragment).
+func findResource(doc document.Document, did, fragment string) (map[string]interface{}, bool) {
+	for _, property := range verificationProperties {
+		if resource, ok := findByID(doc[property], did, fragment); ok {
+			return resource, true
+		}
+	}
+
+	return nil, false
+}
+
+// findService returns the service in the DID document with the given ID. The ID may be provided with or without
+// the leading '#'.
+func findService(doc document.Document, did, serviceID string) (map[string]interface{}, bool) {
+	return findByID(doc[serviceProperty], did, strings.TrimPrefix(serviceID, fragmentSeparator))
+}
+
+func findByID(value interface{}, did, fragment string) (map[string]interface{}, bool) {
+	entries, ok := value.([]interface{})
+	if !ok {
+		return nil, false
+	}
+
+	for _, entry := range entries {
+		// Entries may also be references (strings) to verification methods, which are skipped.
+		resource, isMap := entry.(map[string]interface{})
+		if !isMap {
+			continue
+		}
+
+		id, isString := resource[idProperty].(string)
+		if !isString {
+			continue
+		}
+
+		if id == fragmentSeparator+fragment || id == did+fragmentSeparator+fragment || id == fragment {
+			return resource, true
+		}
+
+		// The ID in the document may contain the canonical DID rather than the requested DID.
+		if i := strings.Index(id, fragmentSeparator); i > 0 && id[i+1:] == fragment &&
+			strings.HasPrefix(id, didPrefix) {
+			return resource, true
+		}
+	}
+
+	return nil, false
+}
+
+// serviceEndpointURL returns the endpoint URL of the given service. If the endpoint is a list then the first URL
+// in the list is returned. False is returned if the service doesn't have an endpoint URL (e.g. the endpoint is a map).
+func serviceEndpointURL(service map[string]interface{}) (string, bool) {
+	switch endpoint := service[endpointProperty].(type) {
+	case string:
+		return endpoint, endpoint != ""
+	case []interface{}:
+		for _, e := range endpoint {
+			if s, ok := e.(string); ok && s != "" {
+				return s, true
+			}
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,85 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resolutionhandler
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDIDURL(t *testing.T) {
+	const did = "did:orb:uAAA:EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A"
+
+	t.Run("DID", func(t *testing.T) {
+		u, err := parseDIDURL(did)
+		require.NoError(t, err)
+		require.Equal(t, did, u.did)
+		require.Empty(t, u.fragment)
+		require.Empty(t, u.params)
+		require.Equal(t, did, u.String())
+	})
+
+	t.Run("DID URL with parameters and fragment", func(t *testing.T) {
+		u, err := parseDIDURL(did + "?service=hub&relativeRef=%2Fpath#frag")
+		require.NoError(t, err)
+		require.Equal(t, did, u.did)
+		require.Equal(t, "frag", u.fragment)
+		require.Equal(t, map[string]string{serviceParam: "hub", relativeRefParam: "/path"}, u.params)
+		require.Equal(t, did+"?relativeRef=%2Fpath&service=hub#frag", u.String())
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		_, err := parseDIDURL(did + "#")
+		require.EqualError(t, err, "empty fragment in DID URL ["+did+"#]")
+
+		_, err = parseDIDURL(did + "?versionTime=2021")
+		require.EqualError(t, err, "unsupported DID parameter [versionTime] in DID URL ["+did+"?versionTime=2021]")
+
+		_, err = parseDIDURL(did + "?service=%zz")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid parameters in DID URL")
+
+		_, err = parseDIDURL("did:orb")
+		require.True(t, errors.Is(err, errInvalidDID))
+
+		_, err = parseDIDURL("http://example.com#frag")
+		require.True(t, errors.Is(err, errInvalidDID))
+	})
+}
+
+func TestServiceEndpointURL(t *testing.T) {
+	endpoint, ok := serviceEndpointURL(map[string]interface{}{endpointProperty: "https://example.com"})
+	require.True(t, ok)
+	require.Equal(t, "https://example.com", endpoint)
+
+	endpoint, ok = serviceEndpointURL(map[string]interface{}{endpointProperty: []interface{}{1, "https://example.com"}})
+	require.True(t, ok)
+	require.Equal(t, "https://example.com", endpoint)
+
+	_, ok = serviceEndpointURL(map[string]interface{}{endpointProperty: []interface{}{}})
+	require.False(t, ok)
+
+	_, ok = serviceEndpointURL(map[string]interface{}{endpointProperty: map[string]interface{}{}})
+	require.False(t, ok)
+
+	_, ok = serviceEndpointURL(map[string]interface{}{})
+	require.False(t, ok)
+}
+
+func TestFindByID(t *testing.T) {
+	_, ok := findByID("invalid", "did:orb:123", "key1")
+	require.False(t, ok)
+
+	_, ok = findByID([]interface{}{map[string]interface{}{"id": 1}}, "did:orb:123", "key1")
+	require.False(t, ok)
+
+	resource, ok := findByID([]interface{}{"#key1", map[string]interface{}{"id": "key1"}}, "did:orb:123", "key1")
+	require.True(t, ok)
+	require.Equal(t, "key1", resource["id"])
+}
@@ -0,0 +1,470 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resolutionhandler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/trustbloc/edge-core/pkg/log"
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+
+	"github.com/trustbloc/orb/pkg/document/composition"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+var logger = log.New("did-resolution-handler")
+
+const idPathVariable = "id"
+
+const (
+	// MediaTypeDIDLDJSON is the media type of the JSON-LD representation of a DID document.
+	MediaTypeDIDLDJSON = "application/did+ld+json"
+	// MediaTypeDIDJSON is the media type of the JSON representation of a DID document.
+	MediaTypeDIDJSON = "application/did+json"
+	// MediaTypeDIDResolution is the media type of a DID resolution (or dereferencing) result.
+	MediaTypeDIDResolution = `application/ld+json;profile="https://w3id.org/did-resolution"`
+
+	didResolutionProfile = "https://w3id.org/did-resolution"
+	didResolutionContext = "https://w3id.org/did-resolution/v1"
+)
+
+// Errors that are returned in the DID resolution metadata.
+const (
+	// ErrInvalidDID indicates that the DID is not valid.
+	ErrInvalidDID = "invalidDid"
+	// ErrInvalidDIDURL indicates that the DID URL is not valid or contains unsupported parameters.
+	ErrInvalidDIDURL = "invalidDidUrl"
+	// ErrNotFound indicates that the DID (or the resource within the DID document) was not found.
+	ErrNotFound = "notFound"
+	// ErrRepresentationNotSupported indicates that none of the media types in the Accept header is supported.
+	ErrRepresentationNotSupported = "representationNotSupported"
+	// ErrInvalidDIDDocument indicates that the DID document couldn't be composed from the operations of the DID.
+	// The details are included in the compositionError field of the DID resolution metadata.
+	ErrInvalidDIDDocument = "invalidDidDocument"
+	// ErrInternal indicates that an unexpected error occurred.
+	ErrInternal = "internalError"
+)
+
+const (
+	serviceParam     = "service"
+	relativeRefParam = "relativeRef"
+)
+
+// ResolutionMetadata contains the DID resolution (or dereferencing) metadata.
+type ResolutionMetadata struct {
+	ContentType string `json:"contentType,omitempty"`
+	Error       string `json:"error,omitempty"`
+	Message     string `json:"message,omitempty"`
+
+	// CompositionError describes the operation (and patch) that couldn't be applied if the error is
+	// ErrInvalidDIDDocument.
+	CompositionError *composition.Error `json:"compositionError,omitempty"`
+
+	// The following fields are only included if the handler is configured with the WithDriverMetadata option.
+
+	// DID contains the components of the resolved DID.
+	DID *DIDMetadata `json:"did,omitempty"`
+	// Retrieved is the time at which the DID was resolved.
+	Retrieved string `json:"retrieved,omitempty"`
+	// Duration is the time (in milliseconds) that it took to resolve the DID.
+	Duration int64 `json:"duration,omitempty"`
+}
+
+// DIDMetadata contains the components of a DID.
+type DIDMetadata struct {
+	DIDString        string `json:"didString"`
+	MethodSpecificID string `json:"methodSpecificId"`
+	Method           string `json:"method"`
+}
+
+// ResolutionResult is the DID resolution result that's returned if the client accepts the did-resolution profile.
+type ResolutionResult struct {
+	Context            interface{}         `json:"@context"`
+	Document           document.Document   `json:"didDocument"`
+	ResolutionMetadata *ResolutionMetadata `json:"didResolutionMetadata"`
+	DocumentMetadata   document.Metadata   `json:"didDocumentMetadata"`
+}
+
+// DereferencingResult is the result of dereferencing a DID URL (e.g. a DID URL with a fragment) that's returned
+// if the client accepts the did-resolution profile.
+type DereferencingResult struct {
+	Context               interface{}         `json:"@context"`
+	ContentStream         interface{}         `json:"contentStream"`
+	DereferencingMetadata *ResolutionMetadata `json:"dereferencingMetadata"`
+	ContentMetadata       document.Metadata   `json:"contentMetadata"`
+}
+
+type resolver interface {
+	ResolveDocument(id string) (*document.ResolutionResult, error)
+}
+
+type metricsProvider interface {
+	HTTPResolveTime(value time.Duration)
+}
+
+type options struct {
+	defaultRepresentation string
+	driverMetadata        bool
+}
+
+// Opt sets a DID resolution handler option.
+type Opt func(opts *options)
+
+// WithDefaultRepresentation sets the representation (MediaTypeDIDLDJSON, MediaTypeDIDJSON or
+// MediaTypeDIDResolution) of the response if the client accepts any media type or doesn't provide an
+// Accept header.
+func WithDefaultRepresentation(mediaType string) Opt {
+	return func(opts *options) {
+		opts.defaultRepresentation = mediaType
+	}
+}
+
+// WithDriverMetadata includes the components of the DID, the retrieval time and the duration of the resolution
+// in the DID resolution metadata, as is expected from a Universal Resolver driver.
+func WithDriverMetadata() Opt {
+	return func(opts *options) {
+		opts.driverMetadata = true
+	}
+}
+
+// Handler implements the W3C DID Resolution HTTP(S) binding. The DID (or DID URL) is provided in the path and the
+// representation of the response is selected using the Accept header. If the client accepts application/did+ld+json
+// or application/did+json then the DID document (or the dereferenced resource) is returned. If the client accepts
+// the did-resolution profile (application/ld+json;profile="https://w3id.org/did-resolution") then the DID resolution
+// (or dereferencing) result is returned. Otherwise (i.e. */*, application/json or no Accept header) the DID
+// resolution result is returned with content type application/did+ld+json, which is compatible with Sidetree
+// clients, unless a different default representation is configured. Errors are returned as a resolution result
+// with the error in the DID resolution metadata.
+//
+// DID URLs with a fragment (which must be percent-encoded in the path) are dereferenced to the verification method
+// or service in the DID document with the given ID. DID URLs with the 'service' parameter (and optional
+// 'relativeRef' parameter) are dereferenced to the service endpoint by redirecting the client (303 See Other).
+type Handler struct {
+	*options
+
+	basePath string
+	resolver resolver
+	metrics  metricsProvider
+	marshal  func(v interface{}) ([]byte, error)
+}
+
+// New returns a new DID resolution handler. The endpoint of the handler is <basePath>/{id}.
+func New(basePath string, resolver resolver, metrics metricsProvider, opts ...Opt) *Handler {
+	options := &options{}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	return &Handler{
+		options:  options,
+		basePath: basePath,
+		resolver: resolver,
+		metrics:  metrics,
+		marshal:  json.Marshal,
+	}
+}
+
+// Path returns the HTTP REST endpoint for the DID resolution service.
+func (h *Handler) Path() string {
+	return fmt.Sprintf("%s/{%s}", h.basePath, idPathVariable)
+}
+
+// Method returns the HTTP method, which is always GET.
+func (h *Handler) Method() string {
+	return http.MethodGet
+}
+
+// Handler returns the HTTP REST handle for the DID resolution service.
+func (h *Handler) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Handler) handle(w http.ResponseWriter, req *http.Request) {
+	startTime := time.Now()
+
+	defer func() {
+		h.metrics.HTTPResolveTime(time.Since(startTime))
+	}()
+
+	rep, ok := getRepresentation(req.Header.Get("Accept"))
+	if !ok {
+		h.writeError(w, http.StatusNotAcceptable, ErrRepresentationNotSupported,
+			fmt.Sprintf("none of the accepted media types are supported [%s]", req.Header.Get("Accept")))
+
+		return
+	}
+
+	if rep == "" {
+		rep = h.defaultRepresentation
+	}
+
+	u, err := parseDIDURL(mux.Vars(req)[idPathVariable])
+	if err != nil {
+		code := ErrInvalidDIDURL
+		if errors.Is(err, errInvalidDID) {
+			code = ErrInvalidDID
+		}
+
+		h.writeError(w, http.StatusBadRequest, code, err.Error())
+
+		return
+	}
+
+	// DID parameters may also be provided as (unencoded) query parameters of the request.
+	for _, param := range []string{serviceParam, relativeRefParam} {
+		if value := req.URL.Query().Get(param); value != "" && u.params[param] == "" {
+			u.params[param] = value
+		}
+	}
+
+	result, err := h.resolver.ResolveDocument(u.did)
+	if err != nil {
+		h.handleResolveError(w, u.did, err)
+
+		return
+	}
+
+	md := h.newResolutionMetadata(u.did, startTime)
+
+	switch {
+	case u.params[serviceParam] != "":
+		h.dereferenceService(w, rep, u, result, md)
+	case u.fragment != "":
+		h.dereferenceFragment(w, rep, u, result, md)
+	default:
+		h.writeResolutionResult(w, rep, result, md)
+	}
+}
+
+func (h *Handler) handleResolveError(w http.ResponseWriter, did string, err error) {
+	var compositionErr *composition.Error
+
+	switch {
+	case errors.As(err, &compositionErr):
+		logger.Warnf("Invalid DID document [%s]: %s", did, err)
+
+		h.writeJSON(w, http.StatusUnprocessableEntity, MediaTypeDIDResolution, &ResolutionResult{
+			Context: didResolutionContext,
+			ResolutionMetadata: &ResolutionMetadata{
+				Error:            ErrInvalidDIDDocument,
+				Message:          compositionErr.Error(),
+				CompositionError: compositionErr,
+			},
+			DocumentMetadata: document.Metadata{},
+		})
+
+	case strings.Contains(err.Error(), "bad request") || orberrors.IsBadRequest(err):
+		logger.Debugf("Invalid DID [%s]: %s", did, err)
+
+		h.writeError(w, http.StatusBadRequest, ErrInvalidDID, err.Error())
+
+	case strings.Contains(err.Error(), "not found"):
+		logger.Debugf("DID not found [%s]: %s", did, err)
+
+		h.writeError(w, http.StatusNotFound, ErrNotFound, err.Error())
+
+	default:
+		logger.Errorf("Error resolving DID [%s]: %s", did, err)
+
+		h.writeError(w, http.StatusInternalServerError, ErrInternal, "error resolving DID")
+	}
+}
+
+func (h *Handler) newResolutionMetadata(did string, startTime time.Time) *ResolutionMetadata {
+	md := &ResolutionMetadata{ContentType: MediaTypeDIDLDJSON}
+
+	if !h.driverMetadata {
+		return md
+	}
+
+	// The DID has already been validated so it contains at least the scheme, method and method-specific ID.
+	parts := strings.SplitN(did, ":", minDIDParts)
+
+	md.DID = &DIDMetadata{
+		DIDString:        did,
+		Method:           parts[1],
+		MethodSpecificID: parts[2],
+	}
+
+	md.Retrieved = time.Now().UTC().Format(time.RFC3339)
+	md.Duration = time.Since(startTime).Milliseconds()
+
+	return md
+}
+
+func (h *Handler) writeResolutionResult(w http.ResponseWriter, rep string,
+	result *document.ResolutionResult, md *ResolutionMetadata) {
+	status := http.StatusOK
+
+	// A deactivated DID is returned with status 410 (Gone) along with the document metadata.
+	if deactivated, ok := result.DocumentMetadata[document.DeactivatedProperty].(bool); ok && deactivated {
+		status = http.StatusGone
+	}
+
+	if rep == MediaTypeDIDLDJSON || rep == MediaTypeDIDJSON {
+		h.writeJSON(w, status, rep, result.Document)
+
+		return
+	}
+
+	// The default representation is the resolution result with the DID document content type for compatibility
+	// with Sidetree clients.
+	contentType := MediaTypeDIDLDJSON
+	if rep == MediaTypeDIDResolution {
+		contentType = MediaTypeDIDResolution
+	}
+
+	h.writeJSON(w, status, contentType, &ResolutionResult{
+		Context:            resolutionContext(result),
+		Document:           result.Document,
+		ResolutionMetadata: md,
+		DocumentMetadata:   result.DocumentMetadata,
+	})
+}
+
+func (h *Handler) dereferenceFragment(w http.ResponseWriter, rep string, u *didURL,
+	result *document.ResolutionResult, md *ResolutionMetadata) {
+	resource, ok := findResource(result.Document, u.did, u.fragment)
+	if !ok {
+		h.writeError(w, http.StatusNotFound, ErrNotFound,
+			fmt.Sprintf("resource [#%s] not found in DID document", u.fragment))
+
+		return
+	}
+
+	h.writeDereferencingResult(w, rep, resource, result.DocumentMetadata, md)
+}
+
+func (h *Handler) dereferenceService(w http.ResponseWriter, rep string, u *didURL,
+	result *document.ResolutionResult, md *ResolutionMetadata) {
+	serviceID := u.params[serviceParam]
+
+	service, ok := findService(result.Document, u.did, serviceID)
+	if !ok {
+		h.writeError(w, http.StatusNotFound, ErrNotFound,
+			fmt.Sprintf("service [%s] not found in DID document", serviceID))
+
+		return
+	}
+
+	endpoint, ok := serviceEndpointURL(service)
+	if !ok {
+		// The endpoint isn't a URL (e.g. it's a map) so return the service rather than redirecting.
+		h.writeDereferencingResult(w, rep, service, result.DocumentMetadata, md)
+
+		return
+	}
+
+	location := endpoint + u.params[relativeRefParam]
+
+	logger.Debugf("Redirecting to service endpoint [%s] for DID URL [%s]", location, u)
+
+	w.Header().Set("Location", location)
+	w.WriteHeader(http.StatusSeeOther)
+}
+
+func (h *Handler) writeDereferencingResult(w http.ResponseWriter, rep string, content interface{},
+	metadata document.Metadata, md *ResolutionMetadata) {
+	if rep == MediaTypeDIDResolution {
+		h.writeJSON(w, http.StatusOK, MediaTypeDIDResolution, &DereferencingResult{
+			Context:               didResolutionContext,
+			ContentStream:         content,
+			DereferencingMetadata: md,
+			ContentMetadata:       metadata,
+		})
+
+		return
+	}
+
+	contentType := rep
+	if contentType == "" {
+		contentType = MediaTypeDIDLDJSON
+	}
+
+	h.writeJSON(w, http.StatusOK, contentType, content)
+}
+
+func (h *Handler) writeError(w http.ResponseWriter, status int, code, msg string) {
+	h.writeJSON(w, status, MediaTypeDIDResolution, &ResolutionResult{
+		Context:            didResolutionContext,
+		ResolutionMetadata: &ResolutionMetadata{Error: code, Message: msg},
+		DocumentMetadata:   document.Metadata{},
+	})
+}
+
+func (h *Handler) writeJSON(w http.ResponseWriter, status int, contentType string, v interface{}) {
+	respBytes, err := h.marshal(v)
+	if err != nil {
+		logger.Errorf("Error marshalling response: %s", err)
+
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+
+	if _, e := w.Write(respBytes); e != nil {
+		logger.Warnf("Unable to write response: %s", e)
+	}
+}
+
+// getRepresentation returns the representation of the response for the given Accept header. An empty string is
+// returned for the default representation. False is returned if none of the accepted media types is supported.
+func getRepresentation(accept string) (string, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return "", true
+	}
+
+	for _, mediaRange := range strings.Split(accept, ",") {
+		parts := strings.Split(mediaRange, ";")
+
+		switch mediaType := strings.ToLower(strings.TrimSpace(parts[0])); mediaType {
+		case MediaTypeDIDLDJSON, MediaTypeDIDJSON:
+			return mediaType, true
+
+		case "application/ld+json":
+			if hasProfile(parts[1:], didResolutionProfile) {
+				return MediaTypeDIDResolution, true
+			}
+
+		case "application/json", "application/*", "*/*":
+			return "", true
+		}
+	}
+
+	return "", false
+}
+
+func hasProfile(params []string, profile string) bool {
+	for _, param := range params {
+		kv := strings.SplitN(strings.TrimSpace(param), "=", 2) //nolint:gomnd
+
+		if len(kv) == 2 && strings.EqualFold(kv[0], "profile") && strings.Trim(kv[1], `"`) == profile {
+			return true
+		}
+	}
+
+	return false
+}
+
+func resolutionContext(result *document.ResolutionResult) interface{} {
+	if result.Context != "" {
+		return result.Context
+	}
+
+	return didResolutionContext
+}
@@ -0,0 +1,396 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resolutionhandler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
+
+	"github.com/trustbloc/orb/pkg/document/composition"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+const (
+	basePath = "/sidetree/v1/identifiers"
+	did      = "did:orb:uAAA:EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A"
+)
+
+func TestNew(t *testing.T) {
+	h := New(basePath, &mockResolver{}, &mockMetrics{})
+	require.Equal(t, basePath+"/{id}", h.Path())
+	require.Equal(t, http.MethodGet, h.Method())
+	require.NotNil(t, h.Handler())
+}
+
+func TestHandler_Resolve(t *testing.T) {
+	result := newResolutionResult(false)
+
+	t.Run("Default representation", func(t *testing.T) {
+		for _, accept := range []string{"", "*/*", "application/json", "text/html, application/*"} {
+			rw := resolve(New(basePath, &mockResolver{result: result}, &mockMetrics{}), did, accept)
+			require.Equal(t, http.StatusOK, rw.Code, accept)
+			require.Equal(t, MediaTypeDIDLDJSON, rw.Header().Get("Content-Type"), accept)
+
+			rr := &ResolutionResult{}
+			require.NoError(t, json.Unmarshal(rw.Body.Bytes(), rr))
+			require.Equal(t, did, rr.Document["id"])
+			require.Equal(t, MediaTypeDIDLDJSON, rr.ResolutionMetadata.ContentType)
+			require.Empty(t, rr.ResolutionMetadata.Error)
+			require.Equal(t, did, rr.DocumentMetadata[document.CanonicalIDProperty])
+			require.Equal(t, didResolutionContext, rr.Context)
+		}
+	})
+
+	t.Run("DID document representation", func(t *testing.T) {
+		for _, accept := range []string{MediaTypeDIDLDJSON, MediaTypeDIDJSON, "text/html;q=0.9, application/did+json"} {
+			rw := resolve(New(basePath, &mockResolver{result: result}, &mockMetrics{}), did, accept)
+			require.Equal(t, http.StatusOK, rw.Code, accept)
+
+			doc := document.Document{}
+			require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &doc))
+			require.Equal(t, did, doc["id"])
+			require.Nil(t, doc["didDocument"])
+		}
+
+		rw := resolve(New(basePath, &mockResolver{result: result}, &mockMetrics{}), did, MediaTypeDIDJSON)
+		require.Equal(t, MediaTypeDIDJSON, rw.Header().Get("Content-Type"))
+	})
+
+	t.Run("DID resolution representation", func(t *testing.T) {
+		m := &mockMetrics{}
+
+		rw := resolve(New(basePath, &mockResolver{result: result}, m), did,
+			`application/ld+json; profile="https://w3id.org/did-resolution"`)
+		require.Equal(t, http.StatusOK, rw.Code)
+		require.Equal(t, MediaTypeDIDResolution, rw.Header().Get("Content-Type"))
+		require.True(t, m.called)
+
+		rr := &ResolutionResult{}
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), rr))
+		require.Equal(t, did, rr.Document["id"])
+		require.Equal(t, MediaTypeDIDLDJSON, rr.ResolutionMetadata.ContentType)
+	})
+
+	t.Run("Deactivated", func(t *testing.T) {
+		rw := resolve(New(basePath, &mockResolver{result: newResolutionResult(true)}, &mockMetrics{}), did,
+			MediaTypeDIDResolution)
+		require.Equal(t, http.StatusGone, rw.Code)
+
+		rr := &ResolutionResult{}
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), rr))
+		require.Equal(t, true, rr.DocumentMetadata[document.DeactivatedProperty])
+	})
+
+	t.Run("Representation not supported", func(t *testing.T) {
+		for _, accept := range []string{"text/html", "application/ld+json", `application/ld+json;profile="xxx"`} {
+			rw := resolve(New(basePath, &mockResolver{result: result}, &mockMetrics{}), did, accept)
+			requireError(t, rw, http.StatusNotAcceptable, ErrRepresentationNotSupported)
+		}
+	})
+
+	t.Run("Invalid DID", func(t *testing.T) {
+		for _, id := range []string{"orb:uAAA:123", "did:orb"} {
+			rw := resolve(New(basePath, &mockResolver{result: result}, &mockMetrics{}), id, "")
+			requireError(t, rw, http.StatusBadRequest, ErrInvalidDID)
+		}
+	})
+
+	t.Run("Invalid DID URL", func(t *testing.T) {
+		for _, id := range []string{did + "#", did + "?versionId=123", did + "?service=%zz"} {
+			rw := resolve(New(basePath, &mockResolver{result: result}, &mockMetrics{}), id, "")
+			requireError(t, rw, http.StatusBadRequest, ErrInvalidDIDURL)
+		}
+	})
+
+	t.Run("Resolver errors", func(t *testing.T) {
+		for _, test := range []struct {
+			err    error
+			status int
+			code   string
+		}{
+			{err: errors.New("bad request: invalid suffix"), status: http.StatusBadRequest, code: ErrInvalidDID},
+			{err: orberrors.NewBadRequest(errors.New("invalid")), status: http.StatusBadRequest, code: ErrInvalidDID},
+			{err: errors.New("document not found"), status: http.StatusNotFound, code: ErrNotFound},
+			{err: errors.New("injected error"), status: http.StatusInternalServerError, code: ErrInternal},
+		} {
+			rw := resolve(New(basePath, &mockResolver{err: test.err}, &mockMetrics{}), did, MediaTypeDIDLDJSON)
+			requireError(t, rw, test.status, test.code)
+		}
+	})
+
+	t.Run("Composition error", func(t *testing.T) {
+		patchIndex := 1
+
+		compositionErr := &composition.Error{
+			ID:                "EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A",
+			OperationType:     "update",
+			TransactionTime:   1000,
+			TransactionNumber: 2,
+			PatchIndex:        &patchIndex,
+			Action:            "add-public-keys",
+			Reason:            "invalid public key",
+		}
+
+		rw := resolve(New(basePath, &mockResolver{err: fmt.Errorf("resolve: %w", compositionErr)}, &mockMetrics{}),
+			did, MediaTypeDIDLDJSON)
+		requireError(t, rw, http.StatusUnprocessableEntity, ErrInvalidDIDDocument)
+
+		rr := &ResolutionResult{}
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), rr))
+		require.Equal(t, compositionErr.Error(), rr.ResolutionMetadata.Message)
+		require.NotNil(t, rr.ResolutionMetadata.CompositionError)
+		require.Equal(t, compositionErr.ID, rr.ResolutionMetadata.CompositionError.ID)
+		require.EqualValues(t, "update", rr.ResolutionMetadata.CompositionError.OperationType)
+		require.Equal(t, uint64(1000), rr.ResolutionMetadata.CompositionError.TransactionTime)
+		require.Equal(t, &patchIndex, rr.ResolutionMetadata.CompositionError.PatchIndex)
+		require.Equal(t, "add-public-keys", rr.ResolutionMetadata.CompositionError.Action)
+		require.Equal(t, "invalid public key", rr.ResolutionMetadata.CompositionError.Reason)
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		h := New(basePath, &mockResolver{result: result}, &mockMetrics{})
+		h.marshal = func(v interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		rw := resolve(h, did, "")
+		require.Equal(t, http.StatusInternalServerError, rw.Code)
+	})
+}
+
+func TestHandler_Options(t *testing.T) {
+	result := newResolutionResult(false)
+
+	t.Run("Default representation", func(t *testing.T) {
+		h := New(basePath, &mockResolver{result: result}, &mockMetrics{},
+			WithDefaultRepresentation(MediaTypeDIDResolution))
+
+		rw := resolve(h, did, "*/*")
+		require.Equal(t, http.StatusOK, rw.Code)
+		require.Equal(t, MediaTypeDIDResolution, rw.Header().Get("Content-Type"))
+
+		rw = resolve(h, did, MediaTypeDIDJSON)
+		require.Equal(t, http.StatusOK, rw.Code)
+		require.Equal(t, MediaTypeDIDJSON, rw.Header().Get("Content-Type"))
+	})
+
+	t.Run("Driver metadata", func(t *testing.T) {
+		h := New(basePath, &mockResolver{result: result}, &mockMetrics{}, WithDriverMetadata())
+
+		rw := resolve(h, did, MediaTypeDIDResolution)
+		require.Equal(t, http.StatusOK, rw.Code)
+
+		rr := &ResolutionResult{}
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), rr))
+		require.Equal(t, MediaTypeDIDLDJSON, rr.ResolutionMetadata.ContentType)
+		require.NotEmpty(t, rr.ResolutionMetadata.Retrieved)
+		require.NotNil(t, rr.ResolutionMetadata.DID)
+		require.Equal(t, did, rr.ResolutionMetadata.DID.DIDString)
+		require.Equal(t, "orb", rr.ResolutionMetadata.DID.Method)
+		require.Equal(t, strings.TrimPrefix(did, "did:orb:"), rr.ResolutionMetadata.DID.MethodSpecificID)
+
+		_, err := time.Parse(time.RFC3339, rr.ResolutionMetadata.Retrieved)
+		require.NoError(t, err)
+	})
+
+	t.Run("No driver metadata", func(t *testing.T) {
+		rw := resolve(New(basePath, &mockResolver{result: result}, &mockMetrics{}), did, MediaTypeDIDResolution)
+		require.Equal(t, http.StatusOK, rw.Code)
+
+		rr := &ResolutionResult{}
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), rr))
+		require.Nil(t, rr.ResolutionMetadata.DID)
+		require.Empty(t, rr.ResolutionMetadata.Retrieved)
+	})
+}
+
+func TestHandler_Dereference(t *testing.T) {
+	result := newResolutionResult(false)
+
+	t.Run("Fragment", func(t *testing.T) {
+		rw := resolve(New(basePath, &mockResolver{result: result}, &mockMetrics{}), did+"#key1", "")
+		require.Equal(t, http.StatusOK, rw.Code)
+		require.Equal(t, MediaTypeDIDLDJSON, rw.Header().Get("Content-Type"))
+
+		resource := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &resource))
+		require.Equal(t, "#key1", resource["id"])
+
+		rw = resolve(New(basePath, &mockResolver{result: result}, &mockMetrics{}), did+"#hub", MediaTypeDIDJSON)
+		require.Equal(t, http.StatusOK, rw.Code)
+		require.Equal(t, MediaTypeDIDJSON, rw.Header().Get("Content-Type"))
+
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &resource))
+		require.Equal(t, did+"#hub", resource["id"])
+	})
+
+	t.Run("Fragment - dereferencing result", func(t *testing.T) {
+		rw := resolve(New(basePath, &mockResolver{result: result}, &mockMetrics{}), did+"#key2",
+			MediaTypeDIDResolution)
+		require.Equal(t, http.StatusOK, rw.Code)
+		require.Equal(t, MediaTypeDIDResolution, rw.Header().Get("Content-Type"))
+
+		dr := &DereferencingResult{}
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), dr))
+		require.Equal(t, MediaTypeDIDLDJSON, dr.DereferencingMetadata.ContentType)
+		require.Equal(t, did, dr.ContentMetadata[document.CanonicalIDProperty])
+
+		resource, ok := dr.ContentStream.(map[string]interface{})
+		require.True(t, ok)
+		require.Equal(t, "did:orb:uAAA:canonical#key2", resource["id"])
+	})
+
+	t.Run("Fragment not found", func(t *testing.T) {
+		rw := resolve(New(basePath, &mockResolver{result: result}, &mockMetrics{}), did+"#key3", "")
+		requireError(t, rw, http.StatusNotFound, ErrNotFound)
+	})
+
+	t.Run("Service", func(t *testing.T) {
+		rw := resolve(New(basePath, &mockResolver{result: result}, &mockMetrics{}),
+			did+"?service=hub&relativeRef=%2Fpath%3Fq%3D1", "")
+		require.Equal(t, http.StatusSeeOther, rw.Code)
+		require.Equal(t, "https://hub.example.com/path?q=1", rw.Header().Get("Location"))
+	})
+
+	t.Run("Service in request query", func(t *testing.T) {
+		h := New(basePath, &mockResolver{result: result}, &mockMetrics{})
+
+		req := httptest.NewRequest(http.MethodGet, basePath+"/"+url.PathEscape(did)+"?service=list", nil)
+		rw := httptest.NewRecorder()
+
+		h.handle(rw, mux.SetURLVars(req, map[string]string{idPathVariable: did}))
+
+		require.Equal(t, http.StatusSeeOther, rw.Code)
+		require.Equal(t, "https://list1.example.com", rw.Header().Get("Location"))
+	})
+
+	t.Run("Service with map endpoint", func(t *testing.T) {
+		rw := resolve(New(basePath, &mockResolver{result: result}, &mockMetrics{}), did+"?service=%23map", "")
+		require.Equal(t, http.StatusOK, rw.Code)
+
+		service := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &service))
+		require.Equal(t, "#map", service["id"])
+	})
+
+	t.Run("Service not found", func(t *testing.T) {
+		rw := resolve(New(basePath, &mockResolver{result: result}, &mockMetrics{}), did+"?service=xxx", "")
+		requireError(t, rw, http.StatusNotFound, ErrNotFound)
+	})
+}
+
+func TestGetRepresentation(t *testing.T) {
+	for _, test := range []struct {
+		accept string
+		rep    string
+		ok     bool
+	}{
+		{accept: "", rep: "", ok: true},
+		{accept: "*/*", rep: "", ok: true},
+		{accept: "Application/DID+LD+JSON", rep: MediaTypeDIDLDJSON, ok: true},
+		{accept: "application/did+json", rep: MediaTypeDIDJSON, ok: true},
+		{accept: MediaTypeDIDResolution, rep: MediaTypeDIDResolution, ok: true},
+		{accept: `application/ld+json; profile=https://w3id.org/did-resolution`, rep: MediaTypeDIDResolution, ok: true},
+		{accept: "application/ld+json", ok: false},
+		{accept: "text/plain", ok: false},
+	} {
+		rep, ok := getRepresentation(test.accept)
+		require.Equal(t, test.ok, ok, test.accept)
+		require.Equal(t, test.rep, rep, test.accept)
+	}
+}
+
+func resolve(h *Handler, id, accept string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, basePath+"/"+url.PathEscape(id), nil)
+
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+
+	rw := httptest.NewRecorder()
+
+	h.handle(rw, mux.SetURLVars(req, map[string]string{idPathVariable: id}))
+
+	return rw
+}
+
+func requireError(t *testing.T, rw *httptest.ResponseRecorder, status int, code string) {
+	t.Helper()
+
+	require.Equal(t, status, rw.Code)
+	require.Equal(t, MediaTypeDIDResolution, rw.Header().Get("Content-Type"))
+
+	rr := &ResolutionResult{}
+	require.NoError(t, json.Unmarshal(rw.Body.Bytes(), rr))
+	require.Nil(t, rr.Document)
+	require.NotNil(t, rr.ResolutionMetadata)
+	require.Equal(t, code, rr.ResolutionMetadata.Error)
+	require.NotEmpty(t, rr.ResolutionMetadata.Message)
+}
+
+func newResolutionResult(deactivated bool) *document.ResolutionResult {
+	metadata := document.Metadata{document.CanonicalIDProperty: did}
+
+	if deactivated {
+		metadata[document.DeactivatedProperty] = true
+	}
+
+	return &document.ResolutionResult{
+		Context: didResolutionContext,
+		Document: document.Document{
+			"id": did,
+			"verificationMethod": []interface{}{
+				map[string]interface{}{"id": "#key1", "type": "JsonWebKey2020"},
+				map[string]interface{}{"id": "did:orb:uAAA:canonical#key2", "type": "JsonWebKey2020"},
+			},
+			"authentication": []interface{}{"#key1"},
+			"service": []interface{}{
+				map[string]interface{}{"id": did + "#hub", "serviceEndpoint": "https://hub.example.com"},
+				map[string]interface{}{
+					"id":              "#list",
+					"serviceEndpoint": []interface{}{"https://list1.example.com", "https://list2.example.com"},
+				},
+				map[string]interface{}{"id": "#map", "serviceEndpoint": map[string]interface{}{"uri": "x"}},
+			},
+		},
+		DocumentMetadata: metadata,
+	}
+}
+
+type mockResolver struct {
+	result *document.ResolutionResult
+	err    error
+}
+
+func (m *mockResolver) ResolveDocument(id string) (*document.ResolutionResult, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	if m.result == nil {
+		return nil, fmt.Errorf("%s not found", id)
+	}
+
+	return m.result, nil
+}
+
+type mockMetrics struct {
+	called bool
+}
+
+func (m *mockMetrics) HTTPResolveTime(time.Duration) {
+	m.called = true
+}
@@ -11,6 +11,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"reflect"
 	"strings"
 	"time"
 
@@ -29,6 +30,9 @@ import (
 
 var logger = log.New("orb-resolver")
 
+// networkProperty is the method metadata property that holds the network identifier of the namespace.
+const networkProperty = "network"
+
 // ErrDocumentNotFound is document not found error.
 var ErrDocumentNotFound = fmt.Errorf("document not found")
 
@@ -44,6 +48,7 @@ type ResolveHandler struct {
 	endpointClient   endpointClient
 
 	namespace string
+	network   string
 	domain    string
 
 	unpublishedDIDLabel string
@@ -110,6 +115,14 @@ func WithUnpublishedDIDLabel(label string) Option {
 	}
 }
 
+// WithNetwork sets the network identifier of the namespace (for example, "testnet" for did:orb:testnet),
+// which is included in the method metadata of resolved documents.
+func WithNetwork(network string) Option {
+	return func(opts *ResolveHandler) {
+		opts.network = network
+	}
+}
+
 // WithCreateDocumentStore will enable resolution from 'create' document store in case
 // that document is not found in operations store.
 func WithCreateDocumentStore(store storage.Store) Option {
@@ -151,20 +164,41 @@ func (r *ResolveHandler) ResolveDocument(id string) (*document.ResolutionResult,
 		r.metrics.DocumentResolveTime(time.Since(startTime))
 	}()
 
-	localResponse, err := r.resolveDocumentLocally(id)
+	response, err := r.resolveDocumentLocally(id)
 	if err != nil {
 		return nil, err
 	}
 
 	if r.enableResolutionFromAnchorOrigin && !strings.Contains(id, r.unpublishedDIDLabel) {
-		return r.resolveDocumentFromAnchorOriginAndCombineWithLocal(id, localResponse)
+		response, err = r.resolveDocumentFromAnchorOriginAndCombineWithLocal(id, response)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	return localResponse, nil
+	r.addNetwork(response)
+
+	return response, nil
+}
+
+// addNetwork adds the network identifier (if any) to the method metadata of the given resolution result.
+func (r *ResolveHandler) addNetwork(rr *document.ResolutionResult) {
+	if r.network == "" {
+		return
+	}
+
+	methodMetadata, err := util.GetMethodMetadata(rr.DocumentMetadata)
+	if err != nil {
+		logger.Debugf("Unable to add network to method metadata: %s", err)
+
+		return
+	}
+
+	methodMetadata[networkProperty] = r.network
 }
 
 func (r *ResolveHandler) resolveDocumentFromAnchorOriginAndCombineWithLocal(id string, localResponse *document.ResolutionResult) (*document.ResolutionResult, error) { //nolint:lll,funlen
-	localAnchorOrigin, err := util.GetAnchorOrigin(localResponse.DocumentMetadata)
+	localAnchorOrigins, err := util.GetAnchorOrigins(localResponse.DocumentMetadata)
 	if err != nil {
 		logger.Debugf("resolving locally since there was an error while getting anchor origin from local response[%s]: %s", id, err.Error()) //nolint:lll
 
@@ -172,12 +206,14 @@ func (r *ResolveHandler) resolveDocumentFromAnchorOriginAndCombineWithLocal(id s
 		return localResponse, nil
 	}
 
+	localAnchorOrigin := localAnchorOrigins[0]
+
 	if localAnchorOrigin == r.domain {
 		// nothing to do since DID's anchor origin equals current domain - return local response
 		return localResponse, nil
 	}
 
-	anchorOriginResponse, err := r.resolveDocumentFromAnchorOrigin(id, localAnchorOrigin)
+	anchorOriginResponse, err := r.resolveDocumentFromAnchorOrigins(id, localAnchorOrigins)
 	if err != nil {
 		logger.Debugf("resolving locally since there was an error while getting local anchor origin for id[%s]: %s",
 			id, err.Error()) //
@@ -320,7 +356,8 @@ func equalMetadata(anchorOrigin, local document.Metadata) error {
 		return fmt.Errorf("anchor origin and local recovery commitments don't match: %w", err)
 	}
 
-	if anchorOriginMethodMetadata[document.AnchorOriginProperty] != localMethodMetadata[document.AnchorOriginProperty] {
+	if !equalAnchorOrigins(anchorOriginMethodMetadata[document.AnchorOriginProperty],
+		localMethodMetadata[document.AnchorOriginProperty]) {
 		return fmt.Errorf("anchor origin[%s] and local[%s] anchor origins don't match",
 			anchorOriginMethodMetadata[document.AnchorOriginProperty], localMethodMetadata[document.AnchorOriginProperty])
 	}
@@ -333,6 +370,22 @@ func equalMetadata(anchorOrigin, local document.Metadata) error {
 	return nil
 }
 
+// equalAnchorOrigins returns true if the given anchor origin properties contain the same anchor origins
+// (the property may either be a single anchor origin or a list of anchor origins).
+func equalAnchorOrigins(anchorOrigin, local interface{}) bool {
+	anchorOrigins, err := util.ParseAnchorOrigins(anchorOrigin)
+	if err != nil {
+		return reflect.DeepEqual(anchorOrigin, local)
+	}
+
+	localAnchorOrigins, err := util.ParseAnchorOrigins(local)
+	if err != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(anchorOrigins, localAnchorOrigins)
+}
+
 func checkCommitment(anchorOrigin, local map[string]interface{}, commitmentType string) error {
 	ao, ok := anchorOrigin[commitmentType]
 	if !ok {
@@ -351,6 +404,32 @@ func checkCommitment(anchorOrigin, local map[string]interface{}, commitmentType
 	return nil
 }
 
+// resolveDocumentFromAnchorOrigins attempts to resolve the document from each of the given anchor origins
+// (primary first, followed by the fallbacks) and returns the first successful response. The current domain
+// is skipped since the document has already been resolved locally.
+func (r *ResolveHandler) resolveDocumentFromAnchorOrigins(id string, anchorOrigins []string) (*document.ResolutionResult, error) { //nolint:lll
+	var errs []string
+
+	for _, anchorOrigin := range anchorOrigins {
+		if anchorOrigin == r.domain {
+			continue
+		}
+
+		rr, err := r.resolveDocumentFromAnchorOrigin(id, anchorOrigin)
+		if err != nil {
+			logger.Debugf("error resolving id[%s] from anchor origin[%s]: %s", id, anchorOrigin, err)
+
+			errs = append(errs, fmt.Sprintf("%s: %s", anchorOrigin, err))
+
+			continue
+		}
+
+		return rr, nil
+	}
+
+	return nil, fmt.Errorf("unable to resolve id[%s] from anchor origins: %s", id, strings.Join(errs, "; "))
+}
+
 func (r *ResolveHandler) resolveDocumentFromAnchorOrigin(id, anchorOrigin string) (*document.ResolutionResult, error) { //nolint:lll
 	endpoint, err := r.getAnchorOriginEndpoint(anchorOrigin)
 	if err != nil {
@@ -80,6 +80,33 @@ func TestResolveHandler_Resolve(t *testing.T) {
 		require.NotNil(t, response)
 	})
 
+	t.Run("success - with network", func(t *testing.T) {
+		docMetadata := make(document.Metadata)
+		docMetadata[document.MethodProperty] = make(map[string]interface{})
+
+		coreHandler := &mocks.Resolver{}
+		coreHandler.ResolveDocumentReturns(&document.ResolutionResult{DocumentMetadata: docMetadata}, nil)
+
+		handler := NewResolveHandler(testNS, coreHandler, &mocks.Discovery{}, "", nil, nil, anchorGraph,
+			&orbmocks.MetricsProvider{},
+			WithUnpublishedDIDLabel(testLabel), WithNetwork("testnet"))
+
+		response, err := handler.ResolveDocument(testDID)
+		require.NoError(t, err)
+		require.NotNil(t, response)
+
+		methodMetadata, err := util.GetMethodMetadata(response.DocumentMetadata)
+		require.NoError(t, err)
+		require.Equal(t, "testnet", methodMetadata[networkProperty])
+
+		// No method metadata.
+		coreHandler.ResolveDocumentReturns(&document.ResolutionResult{}, nil)
+
+		response, err = handler.ResolveDocument(testDID)
+		require.NoError(t, err)
+		require.NotNil(t, response)
+	})
+
 	t.Run("success - unpublished operations provided from anchor origin (documents match)", func(t *testing.T) {
 		doc := make(document.Document)
 		doc["id"] = localID
@@ -134,6 +161,76 @@ func TestResolveHandler_Resolve(t *testing.T) {
 		require.Equal(t, localResolutionResult.Document, response.Document)
 	})
 
+	t.Run("success - unpublished operations provided from fallback anchor origin", func(t *testing.T) {
+		const fallbackAnchorOrigin = "https://fallback.domain.com"
+
+		anchorOrigins := []interface{}{anchorOriginDomain, fallbackAnchorOrigin}
+
+		doc := make(document.Document)
+		doc["id"] = localID
+
+		localMethodMetadata := make(map[string]interface{})
+		localMethodMetadata[document.AnchorOriginProperty] = anchorOrigins
+
+		localDocMetadata := make(document.Metadata)
+		localDocMetadata[document.MethodProperty] = localMethodMetadata
+
+		localResolutionResult := &document.ResolutionResult{Document: doc, DocumentMetadata: localDocMetadata}
+
+		coreHandler := &mocks.Resolver{}
+		coreHandler.ResolveDocumentReturnsOnCall(0, localResolutionResult, nil)
+
+		discovery := &mocks.Discovery{}
+
+		endpointClient := &mocks.EndpointClient{}
+		endpointClient.GetEndpointReturnsOnCall(0, nil, fmt.Errorf("primary anchor origin unavailable"))
+		endpointClient.GetEndpointReturnsOnCall(1,
+			&models.Endpoint{
+				ResolutionEndpoints: []string{fmt.Sprintf("%s/identifiers", fallbackAnchorOrigin)},
+			}, nil)
+
+		methodMetadata := make(map[string]interface{})
+		methodMetadata[document.AnchorOriginProperty] = []string{anchorOriginDomain, fallbackAnchorOrigin}
+		methodMetadata[document.RecoveryCommitmentProperty] = recoveryCommitment
+		methodMetadata[document.UpdateCommitmentProperty] = updateCommitment
+
+		unpublishedOps := []metadata.UnpublishedOperation{{Type: operation.TypeUpdate}}
+		methodMetadata[document.UnpublishedOperationsProperty] = unpublishedOps
+
+		docMetadata := make(document.Metadata)
+		docMetadata[document.MethodProperty] = methodMetadata
+
+		localResolutionResultWithOps := &document.ResolutionResult{Document: doc, DocumentMetadata: docMetadata}
+		coreHandler.ResolveDocumentReturnsOnCall(1, localResolutionResultWithOps, nil)
+
+		remoteResolver := &mocks.RemoteResolver{}
+		remoteResolver.ResolveDocumentFromResolutionEndpointsReturns(
+			&document.ResolutionResult{
+				Document:         doc,
+				DocumentMetadata: docMetadata,
+			}, nil)
+
+		handler := NewResolveHandler(testNS, coreHandler, discovery,
+			domain, endpointClient, remoteResolver, anchorGraph,
+			&orbmocks.MetricsProvider{},
+			WithUnpublishedDIDLabel(testLabel),
+			WithEnableResolutionFromAnchorOrigin(true))
+
+		response, err := handler.ResolveDocument(testDID)
+		require.NoError(t, err)
+		require.NotNil(t, response)
+		require.Equal(t, localResolutionResultWithOps, response)
+
+		require.Equal(t, 2, endpointClient.GetEndpointCallCount())
+		require.Equal(t, anchorOriginDomain, endpointClient.GetEndpointArgsForCall(0))
+		require.Equal(t, fallbackAnchorOrigin, endpointClient.GetEndpointArgsForCall(1))
+
+		require.Equal(t, 1, remoteResolver.ResolveDocumentFromResolutionEndpointsCallCount())
+
+		_, endpoints := remoteResolver.ResolveDocumentFromResolutionEndpointsArgsForCall(0)
+		require.Equal(t, []string{fmt.Sprintf("%s/identifiers", fallbackAnchorOrigin)}, endpoints)
+	})
+
 	t.Run("success - anchor origin missing did anchor origin info", func(t *testing.T) {
 		doc := make(document.Document)
 		doc["id"] = localID
@@ -1574,6 +1671,36 @@ func TestEqualMetadata(t *testing.T) {
 			"anchor origin[https://anchor-origin.domain.com] and local[https://other.domain.com] anchor origins don't match")
 	})
 
+	t.Run("success - anchor origin lists", func(t *testing.T) {
+		md := make(map[string]interface{})
+		md[document.RecoveryCommitmentProperty] = recoveryCommitment
+		md[document.UpdateCommitmentProperty] = updateCommitment
+		md[document.AnchorOriginProperty] = []string{anchorOriginDomain, "https://fallback.domain.com"}
+
+		docMD := make(document.Metadata)
+		docMD[document.MethodProperty] = md
+
+		md2 := make(map[string]interface{})
+		md2[document.RecoveryCommitmentProperty] = recoveryCommitment
+		md2[document.UpdateCommitmentProperty] = updateCommitment
+		md2[document.AnchorOriginProperty] = []interface{}{anchorOriginDomain, "https://fallback.domain.com"}
+
+		docMD2 := make(document.Metadata)
+		docMD2[document.MethodProperty] = md2
+
+		require.NoError(t, equalMetadata(docMD, docMD2))
+
+		md2[document.AnchorOriginProperty] = []interface{}{anchorOriginDomain}
+
+		err := equalMetadata(docMD, docMD2)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "anchor origins don't match")
+
+		err = equalMetadata(docMetadata, docMD)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "anchor origins don't match")
+	})
+
 	t.Run("error - different canonical ID", func(t *testing.T) {
 		md := make(map[string]interface{})
 		md[document.RecoveryCommitmentProperty] = recoveryCommitment
@@ -0,0 +1,355 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package snapshot
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+	"github.com/trustbloc/sidetree-core-go/pkg/commitment"
+
+	snapshotstore "github.com/trustbloc/orb/pkg/store/snapshot"
+)
+
+// state is the state of a DID while operations are being applied.
+type state struct {
+	model                 *protocol.ResolutionModel
+	fullTransactionTime   uint64
+	fullTransactionNumber uint64
+	recoveryCommitments   []string
+	updateCommitments     []string
+	applied               map[string]bool
+}
+
+func newStateFromSnapshot(s *snapshotstore.Snapshot) *state {
+	return &state{
+		model:                 s.Model,
+		fullTransactionTime:   s.FullTransactionTime,
+		fullTransactionNumber: s.FullTransactionNumber,
+		recoveryCommitments:   append([]string(nil), s.RecoveryCommitments...),
+		updateCommitments:     append([]string(nil), s.UpdateCommitments...),
+		applied:               make(map[string]bool),
+	}
+}
+
+func (s *state) setFull() {
+	s.fullTransactionTime = s.model.LastOperationTransactionTime
+	s.fullTransactionNumber = s.model.LastOperationTransactionNumber
+	s.updateCommitments = nil
+}
+
+type commitmentFunc func(rm *protocol.ResolutionModel) string
+
+func getRecoveryCommitment(rm *protocol.ResolutionModel) string {
+	return rm.RecoveryCommitment
+}
+
+func getUpdateCommitment(rm *protocol.ResolutionModel) string {
+	return rm.UpdateCommitment
+}
+
+// applier applies operations in the same way as the Sidetree operation processor, i.e. the first valid create
+// operation is applied, followed by the chain of recover/deactivate operations (linked by the recovery commitments)
+// and then the chain of update operations (linked by the update commitments) that were anchored after the last
+// create/recover/deactivate operation. In addition, the applier records the commitments that were consumed so
+// that operations may subsequently be applied from a snapshot of the state.
+type applier struct {
+	pc protocol.Client
+}
+
+// replay applies the given operations (which must be sorted) from the beginning.
+func (a *applier) replay(ops []*operation.AnchoredOperation) (*state, error) {
+	createOps, fullOps, updateOps := splitOperations(ops)
+
+	if len(createOps) == 0 {
+		return nil, errors.New("create operation not found")
+	}
+
+	st := &state{applied: make(map[string]bool)}
+
+	if !a.applyFirstValidCreateOperation(st, createOps) {
+		return nil, errors.New("valid create operation not found")
+	}
+
+	a.applyFullOperations(st, fullOps)
+	a.applyUpdateOperations(st, updateOps)
+
+	return st, nil
+}
+
+// continueFrom applies the given operations (which must be sorted) to the state of the given snapshot.
+// If a recover or deactivate operation is applied then false is returned since the entire chain of update
+// operations needs to be re-evaluated, in which case the operations must be replayed from the beginning.
+func (a *applier) continueFrom(snapshot *snapshotstore.Snapshot, ops []*operation.AnchoredOperation) (*state, bool) {
+	st := newStateFromSnapshot(snapshot)
+
+	_, fullOps, updateOps := splitOperations(ops)
+
+	if a.applyFullOperations(st, fullOps) {
+		return nil, false
+	}
+
+	a.applyUpdateOperations(st, updateOps)
+
+	return st, true
+}
+
+func (a *applier) applyFirstValidCreateOperation(st *state, ops []*operation.AnchoredOperation) bool {
+	for _, op := range ops {
+		rm, err := a.applyOperation(op, &protocol.ResolutionModel{})
+		if err != nil {
+			logger.Debugf("[%s] Skipped invalid create operation in anchor [%s]: %s",
+				op.UniqueSuffix, op.CanonicalReference, err)
+
+			continue
+		}
+
+		st.model = rm
+		st.applied[operationID(op)] = true
+		st.setFull()
+
+		return true
+	}
+
+	return false
+}
+
+// applyFullOperations applies the chain of recover/deactivate operations and returns true if any operation
+// was applied.
+func (a *applier) applyFullOperations(st *state, ops []*operation.AnchoredOperation) bool {
+	if len(ops) == 0 {
+		return false
+	}
+
+	if !a.applyChain(st, ops, getRecoveryCommitment, &st.recoveryCommitments) {
+		return false
+	}
+
+	st.setFull()
+
+	return true
+}
+
+// applyUpdateOperations applies the chain of update operations that were anchored after the last
+// create/recover/deactivate operation.
+func (a *applier) applyUpdateOperations(st *state, ops []*operation.AnchoredOperation) {
+	var filtered []*operation.AnchoredOperation
+
+	for _, op := range ops {
+		if isAfter(op, st.fullTransactionTime, st.fullTransactionNumber) {
+			filtered = append(filtered, op)
+		}
+	}
+
+	if len(filtered) == 0 {
+		return
+	}
+
+	a.applyChain(st, filtered, getUpdateCommitment, &st.updateCommitments)
+}
+
+// applyChain follows the commitments from the current state, applying the first valid operation
+// for each commitment. The consumed commitments are appended to the given slice and true is returned
+// if any operation was applied.
+func (a *applier) applyChain(st *state, ops []*operation.AnchoredOperation, getCommitment commitmentFunc,
+	consumed *[]string) bool {
+	opMap := a.createOperationCommitmentMap(ops)
+
+	processed := make(map[string]bool)
+
+	for _, c := range *consumed {
+		processed[c] = true
+	}
+
+	applied := false
+
+	c := getCommitment(st.model)
+
+	for {
+		if processed[c] {
+			logger.Debugf("Detected loop for commitment [%s]", c)
+
+			break
+		}
+
+		processed[c] = true
+
+		opsForCommitment, ok := opMap[c]
+		if !ok {
+			break
+		}
+
+		op, rm := a.applyFirstValidOperation(opsForCommitment, st.model, c, processed)
+		if rm == nil {
+			break
+		}
+
+		*consumed = append(*consumed, c)
+
+		st.model = rm
+		st.applied[operationID(op)] = true
+
+		applied = true
+
+		c = getCommitment(rm)
+	}
+
+	return applied
+}
+
+func (a *applier) applyFirstValidOperation(ops []*operation.AnchoredOperation, rm *protocol.ResolutionModel,
+	currCommitment string, processed map[string]bool) (*operation.AnchoredOperation, *protocol.ResolutionModel) {
+	for _, op := range ops {
+		nextCommitment, err := a.getNextCommitment(op)
+		if err != nil {
+			logger.Debugf("[%s] Skipped operation in anchor [%s]: %s", op.UniqueSuffix, op.CanonicalReference, err)
+
+			continue
+		}
+
+		if nextCommitment == currCommitment || processed[nextCommitment] {
+			logger.Debugf("[%s] Skipped operation in anchor [%s] since the next commitment has already been used",
+				op.UniqueSuffix, op.CanonicalReference)
+
+			continue
+		}
+
+		newRM, err := a.applyOperation(op, rm)
+		if err != nil {
+			logger.Debugf("[%s] Skipped invalid operation in anchor [%s]: %s",
+				op.UniqueSuffix, op.CanonicalReference, err)
+
+			continue
+		}
+
+		return op, newRM
+	}
+
+	return nil, nil
+}
+
+func (a *applier) createOperationCommitmentMap(
+	ops []*operation.AnchoredOperation) map[string][]*operation.AnchoredOperation {
+	opMap := make(map[string][]*operation.AnchoredOperation)
+
+	for _, op := range ops {
+		c, err := a.getRevealValueCommitment(op)
+		if err != nil {
+			logger.Debugf("[%s] Skipped operation in anchor [%s]: %s", op.UniqueSuffix, op.CanonicalReference, err)
+
+			continue
+		}
+
+		opMap[c] = append(opMap[c], op)
+	}
+
+	return opMap
+}
+
+func (a *applier) getRevealValueCommitment(op *operation.AnchoredOperation) (string, error) {
+	pv, err := a.pc.Get(op.ProtocolVersion)
+	if err != nil {
+		return "", fmt.Errorf("get protocol version [%d]: %w", op.ProtocolVersion, err)
+	}
+
+	rv, err := pv.OperationParser().GetRevealValue(op.OperationRequest)
+	if err != nil {
+		return "", fmt.Errorf("get reveal value: %w", err)
+	}
+
+	return commitment.GetCommitmentFromRevealValue(rv)
+}
+
+func (a *applier) getNextCommitment(op *operation.AnchoredOperation) (string, error) {
+	pv, err := a.pc.Get(op.ProtocolVersion)
+	if err != nil {
+		return "", fmt.Errorf("get protocol version [%d]: %w", op.ProtocolVersion, err)
+	}
+
+	return pv.OperationParser().GetCommitment(op.OperationRequest)
+}
+
+func (a *applier) applyOperation(op *operation.AnchoredOperation,
+	rm *protocol.ResolutionModel) (*protocol.ResolutionModel, error) {
+	pv, err := a.pc.Get(op.ProtocolVersion)
+	if err != nil {
+		return nil, fmt.Errorf("get protocol version [%d]: %w", op.ProtocolVersion, err)
+	}
+
+	return pv.OperationApplier().Apply(op, rm)
+}
+
+// splitOperations splits the operations into create, full (recover and deactivate) and update operations.
+func splitOperations(ops []*operation.AnchoredOperation) (createOps, fullOps,
+	updateOps []*operation.AnchoredOperation) {
+	for _, op := range ops {
+		switch op.Type {
+		case operation.TypeCreate:
+			createOps = append(createOps, op)
+		case operation.TypeUpdate:
+			updateOps = append(updateOps, op)
+		case operation.TypeRecover, operation.TypeDeactivate:
+			fullOps = append(fullOps, op)
+		}
+	}
+
+	return createOps, fullOps, updateOps
+}
+
+// sortOperations returns a copy of the given operations sorted by transaction time and number. Operations with
+// the same transaction time and number are sorted by ID so that the order is deterministic.
+func sortOperations(ops []*operation.AnchoredOperation) []*operation.AnchoredOperation {
+	sorted := make([]*operation.AnchoredOperation, len(ops))
+	copy(sorted, ops)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].TransactionTime != sorted[j].TransactionTime {
+			return sorted[i].TransactionTime < sorted[j].TransactionTime
+		}
+
+		if sorted[i].TransactionNumber != sorted[j].TransactionNumber {
+			return sorted[i].TransactionNumber < sorted[j].TransactionNumber
+		}
+
+		return operationID(sorted[i]) < operationID(sorted[j])
+	})
+
+	return sorted
+}
+
+// isAfter returns true if the operation was anchored after the given transaction.
+func isAfter(op *operation.AnchoredOperation, txnTime, txnNumber uint64) bool {
+	return op.TransactionTime > txnTime || (op.TransactionTime == txnTime && op.TransactionNumber > txnNumber)
+}
+
+// operationID returns an ID that is derived from the operation request and the hash link of the anchor that
+// contains the operation.
+func operationID(op *operation.AnchoredOperation) string {
+	h := sha256.New()
+
+	h.Write([]byte(op.CanonicalReference))
+	h.Write([]byte{0})
+	h.Write(op.OperationRequest)
+
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
+
+// hashOperations returns a hash of the IDs of the given (sorted) operations.
+func hashOperations(ops []*operation.AnchoredOperation) string {
+	h := sha256.New()
+
+	for _, op := range ops {
+		h.Write([]byte(operationID(op)))
+	}
+
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
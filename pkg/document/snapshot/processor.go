@@ -0,0 +1,196 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package snapshot
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/trustbloc/edge-core/pkg/log"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+
+	snapshotstore "github.com/trustbloc/orb/pkg/store/snapshot"
+)
+
+var logger = log.New("did-snapshot")
+
+var (
+	errSnapshotMismatch   = errors.New("the operations in the snapshot do not match the operations in the store")
+	errFullOperationFound = errors.New("a recover or deactivate operation was found after the snapshot")
+)
+
+type operationProcessor interface {
+	Resolve(uniqueSuffix string, additionalOps ...*operation.AnchoredOperation) (*protocol.ResolutionModel, error)
+}
+
+type operationStore interface {
+	Get(suffix string) ([]*operation.AnchoredOperation, error)
+}
+
+type snapshotReader interface {
+	Get(suffix string) (*snapshotstore.Snapshot, error)
+}
+
+// Option is an option for the snapshot processor.
+type Option func(p *Processor)
+
+// WithUnpublishedOperationStore sets the store of unpublished operations. If a DID has unpublished operations
+// then it is resolved by the Sidetree operation processor.
+func WithUnpublishedOperationStore(store operationStore) Option {
+	return func(p *Processor) {
+		p.unpublishedOpStore = store
+	}
+}
+
+// Processor resolves DIDs from the latest snapshot of the DID (if one exists) so that only the operations that
+// were anchored after the snapshot are applied. The snapshot is used only if the operations that were included in
+// the snapshot (identified by their hash links) are the same as the operations in the operation store, otherwise
+// (or if a snapshot doesn't exist) the DID is resolved by the Sidetree operation processor.
+type Processor struct {
+	processor          operationProcessor
+	opStore            operationStore
+	unpublishedOpStore operationStore
+	snapshotStore      snapshotReader
+	applier            *applier
+}
+
+// NewProcessor returns a new snapshot processor.
+func NewProcessor(processor operationProcessor, opStore operationStore, pc protocol.Client,
+	snapshotStore snapshotReader, opts ...Option) *Processor {
+	p := &Processor{
+		processor:     processor,
+		opStore:       opStore,
+		snapshotStore: snapshotStore,
+		applier:       &applier{pc: pc},
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// Resolve returns the resolution model of the given DID suffix.
+func (p *Processor) Resolve(suffix string,
+	additionalOps ...*operation.AnchoredOperation) (*protocol.ResolutionModel, error) {
+	if len(additionalOps) > 0 || p.hasUnpublishedOperations(suffix) {
+		return p.processor.Resolve(suffix, additionalOps...)
+	}
+
+	snapshot, err := p.snapshotStore.Get(suffix)
+	if err != nil {
+		if !errors.Is(err, snapshotstore.ErrNotFound) {
+			logger.Warnf("[%s] Error loading snapshot. The DID will be resolved from all operations: %s", suffix, err)
+		}
+
+		return p.processor.Resolve(suffix)
+	}
+
+	ops, err := p.opStore.Get(suffix)
+	if err != nil {
+		return p.processor.Resolve(suffix)
+	}
+
+	rm, err := p.resolveFromSnapshot(snapshot, ops)
+	if err != nil {
+		logger.Debugf("[%s] Unable to resolve from snapshot. The DID will be resolved from all operations: %s",
+			suffix, err)
+
+		return p.processor.Resolve(suffix)
+	}
+
+	return rm, nil
+}
+
+func (p *Processor) resolveFromSnapshot(snapshot *snapshotstore.Snapshot,
+	ops []*operation.AnchoredOperation) (*protocol.ResolutionModel, error) {
+	sortedOps := sortOperations(ops)
+
+	included, remaining, err := checkSnapshot(snapshot, sortedOps)
+	if err != nil {
+		return nil, err
+	}
+
+	st, ok := p.applier.continueFrom(snapshot, append(pendingOperations(snapshot, included), remaining...))
+	if !ok {
+		return nil, errFullOperationFound
+	}
+
+	logger.Debugf("[%s] Resolved from snapshot at checkpoint [%s] by applying %d of %d operations",
+		snapshot.Suffix, snapshot.CanonicalReference, len(st.applied), len(sortedOps))
+
+	rm := *st.model
+	rm.PublishedOperations = sortedOps
+	rm.UnpublishedOperations = nil
+
+	return &rm, nil
+}
+
+func (p *Processor) hasUnpublishedOperations(suffix string) bool {
+	if p.unpublishedOpStore == nil {
+		return false
+	}
+
+	ops, err := p.unpublishedOpStore.Get(suffix)
+
+	return err == nil && len(ops) > 0
+}
+
+// checkSnapshot splits the given (sorted) operations into the operations that were included in the snapshot
+// and the operations that were anchored after the snapshot. An error is returned if the included operations
+// don't match the operations of the snapshot.
+func checkSnapshot(snapshot *snapshotstore.Snapshot,
+	ops []*operation.AnchoredOperation) (included, remaining []*operation.AnchoredOperation, err error) {
+	n := len(ops)
+
+	for i, op := range ops {
+		if isAfter(op, snapshot.TransactionTime, snapshot.TransactionNumber) {
+			n = i
+
+			break
+		}
+	}
+
+	included, remaining = ops[:n], ops[n:]
+
+	if len(included) != snapshot.OperationCount {
+		return nil, nil, fmt.Errorf("%w: expecting %d operations but found %d",
+			errSnapshotMismatch, snapshot.OperationCount, len(included))
+	}
+
+	if hashOperations(included) != snapshot.OperationsHash {
+		return nil, nil, fmt.Errorf("%w: hash mismatch", errSnapshotMismatch)
+	}
+
+	return included, remaining, nil
+}
+
+// pendingOperations returns the operations that were included in the snapshot but were not applied.
+func pendingOperations(snapshot *snapshotstore.Snapshot,
+	included []*operation.AnchoredOperation) []*operation.AnchoredOperation {
+	if len(snapshot.PendingOperations) == 0 {
+		return nil
+	}
+
+	pendingIDs := make(map[string]bool)
+
+	for _, id := range snapshot.PendingOperations {
+		pendingIDs[id] = true
+	}
+
+	var pending []*operation.AnchoredOperation
+
+	for _, op := range included {
+		if pendingIDs[operationID(op)] {
+			pending = append(pending, op)
+		}
+	}
+
+	return pending
+}
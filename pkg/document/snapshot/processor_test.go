@@ -0,0 +1,407 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package snapshot
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+	"github.com/trustbloc/sidetree-core-go/pkg/commitment"
+	"github.com/trustbloc/sidetree-core-go/pkg/patch"
+	"github.com/trustbloc/sidetree-core-go/pkg/processor"
+	"github.com/trustbloc/sidetree-core-go/pkg/util/ecsigner"
+	"github.com/trustbloc/sidetree-core-go/pkg/util/pubkey"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/client"
+
+	orbmocks "github.com/trustbloc/orb/pkg/mocks"
+	snapshotstore "github.com/trustbloc/orb/pkg/store/snapshot"
+)
+
+const (
+	namespace    = "did:orb"
+	anchorOrigin = "https://orb.domain1.com"
+
+	sha2_256 = 18
+)
+
+func TestProcessor_Resolve(t *testing.T) {
+	pc, err := orbmocks.NewMockProtocolClientProvider().WithAllowedOrigins([]string{"*"}).ForNamespace(namespace)
+	require.NoError(t, err)
+
+	t.Run("no snapshot", func(t *testing.T) {
+		d := newTestDID(t, pc)
+		d.update(t, 2)
+
+		p, inner, _ := newTestProcessor(t, d.opStore, pc)
+
+		rm, err := p.Resolve(d.suffix)
+		require.NoError(t, err)
+		require.Equal(t, 1, inner.count())
+		requireSameState(t, d.resolve(t, pc), rm)
+	})
+
+	t.Run("success - resolved from snapshot", func(t *testing.T) {
+		d := newTestDID(t, pc)
+		d.update(t, 2)
+		d.update(t, 3)
+
+		p, inner, store := newTestProcessor(t, d.opStore, pc)
+
+		require.NoError(t, NewSnapshotter(Config{Namespace: namespace, Interval: 3}, d.opStore, pc, store).
+			Snapshot(d.suffix))
+
+		d.update(t, 4)
+		d.update(t, 5)
+
+		rm, err := p.Resolve(d.suffix)
+		require.NoError(t, err)
+		require.Zero(t, inner.count())
+		require.Len(t, rm.PublishedOperations, 5)
+		requireSameState(t, d.resolve(t, pc), rm)
+	})
+
+	t.Run("success - pending operation applied after snapshot", func(t *testing.T) {
+		d := newTestDID(t, pc)
+
+		// The second update is anchored before the first update, so it can't be applied until
+		// the first update is anchored.
+		update1 := d.newUpdate(t)
+		update2 := d.newUpdate(t)
+
+		d.anchor(t, update2, 2)
+		d.update(t, 3)
+
+		p, inner, store := newTestProcessor(t, d.opStore, pc)
+
+		require.NoError(t, NewSnapshotter(Config{Namespace: namespace, Interval: 3}, d.opStore, pc, store).
+			Snapshot(d.suffix))
+
+		s, err := store.Get(d.suffix)
+		require.NoError(t, err)
+		require.Len(t, s.PendingOperations, 2)
+
+		d.anchor(t, update1, 4)
+
+		rm, err := p.Resolve(d.suffix)
+		require.NoError(t, err)
+		require.Zero(t, inner.count())
+		requireSameState(t, d.resolve(t, pc), rm)
+		require.Len(t, rm.Doc["service"], 4)
+	})
+
+	t.Run("operation anchored before checkpoint -> resolve from all operations", func(t *testing.T) {
+		d := newTestDID(t, pc)
+		d.update(t, 3)
+		d.update(t, 4)
+
+		p, inner, store := newTestProcessor(t, d.opStore, pc)
+
+		require.NoError(t, NewSnapshotter(Config{Namespace: namespace, Interval: 3}, d.opStore, pc, store).
+			Snapshot(d.suffix))
+
+		// This operation was anchored before the snapshot checkpoint but was added to the store afterwards.
+		d.anchor(t, d.newUpdate(t), 2)
+
+		rm, err := p.Resolve(d.suffix)
+		require.NoError(t, err)
+		require.Equal(t, 1, inner.count())
+		requireSameState(t, d.resolve(t, pc), rm)
+	})
+
+	t.Run("recover after snapshot -> resolve from all operations", func(t *testing.T) {
+		d := newTestDID(t, pc)
+		d.update(t, 2)
+		d.update(t, 3)
+
+		p, inner, store := newTestProcessor(t, d.opStore, pc)
+
+		require.NoError(t, NewSnapshotter(Config{Namespace: namespace, Interval: 3}, d.opStore, pc, store).
+			Snapshot(d.suffix))
+
+		d.recover(t, 4)
+
+		rm, err := p.Resolve(d.suffix)
+		require.NoError(t, err)
+		require.Equal(t, 1, inner.count())
+		requireSameState(t, d.resolve(t, pc), rm)
+	})
+
+	t.Run("additional operations -> resolve from all operations", func(t *testing.T) {
+		d := newTestDID(t, pc)
+		d.update(t, 2)
+		d.update(t, 3)
+
+		p, inner, store := newTestProcessor(t, d.opStore, pc)
+
+		require.NoError(t, NewSnapshotter(Config{Namespace: namespace, Interval: 3}, d.opStore, pc, store).
+			Snapshot(d.suffix))
+
+		_, err := p.Resolve(d.suffix, d.newUpdate(t))
+		require.NoError(t, err)
+		require.Equal(t, 1, inner.count())
+	})
+
+	t.Run("unpublished operations -> resolve from all operations", func(t *testing.T) {
+		d := newTestDID(t, pc)
+		d.update(t, 2)
+		d.update(t, 3)
+
+		unpublishedOpStore := orbmocks.NewMockOperationStore()
+		require.NoError(t, unpublishedOpStore.Put([]*operation.AnchoredOperation{d.newUpdate(t)}))
+
+		store, err := snapshotstore.New(mem.NewProvider())
+		require.NoError(t, err)
+
+		require.NoError(t, NewSnapshotter(Config{Namespace: namespace, Interval: 3}, d.opStore, pc, store).
+			Snapshot(d.suffix))
+
+		inner := &countingProcessor{processor: processor.New(namespace, d.opStore, pc)}
+
+		p := NewProcessor(inner, d.opStore, pc, store, WithUnpublishedOperationStore(unpublishedOpStore))
+
+		_, err = p.Resolve(d.suffix)
+		require.NoError(t, err)
+		require.Equal(t, 1, inner.count())
+	})
+
+	t.Run("snapshot store error -> resolve from all operations", func(t *testing.T) {
+		d := newTestDID(t, pc)
+
+		inner := &countingProcessor{processor: processor.New(namespace, d.opStore, pc)}
+
+		p := NewProcessor(inner, d.opStore, pc, &mockSnapshotStore{err: errors.New("injected store error")})
+
+		_, err := p.Resolve(d.suffix)
+		require.NoError(t, err)
+		require.Equal(t, 1, inner.count())
+	})
+}
+
+func newTestProcessor(t *testing.T, opStore operationStore,
+	pc protocol.Client) (*Processor, *countingProcessor, *snapshotstore.Store) {
+	t.Helper()
+
+	store, err := snapshotstore.New(mem.NewProvider())
+	require.NoError(t, err)
+
+	inner := &countingProcessor{processor: processor.New(namespace, opStore, pc)}
+
+	return NewProcessor(inner, opStore, pc, store), inner, store
+}
+
+func requireSameState(t *testing.T, expected, actual *protocol.ResolutionModel) {
+	t.Helper()
+
+	require.Equal(t, expected.Doc, actual.Doc)
+	require.Equal(t, expected.UpdateCommitment, actual.UpdateCommitment)
+	require.Equal(t, expected.RecoveryCommitment, actual.RecoveryCommitment)
+	require.Equal(t, expected.Deactivated, actual.Deactivated)
+	require.Equal(t, expected.LastOperationTransactionTime, actual.LastOperationTransactionTime)
+	require.Equal(t, expected.LastOperationTransactionNumber, actual.LastOperationTransactionNumber)
+	require.Len(t, actual.PublishedOperations, len(expected.PublishedOperations))
+}
+
+type testDID struct {
+	suffix      string
+	updateKey   *ecdsa.PrivateKey
+	recoveryKey *ecdsa.PrivateKey
+	opStore     *orbmocks.MockOperationStore
+	numUpdates  int
+}
+
+// newTestDID creates a DID and anchors the create operation at transaction time 1.
+func newTestDID(t *testing.T, pc protocol.Client) *testDID {
+	t.Helper()
+
+	recoveryKey, recoveryCommitment := newKeyAndCommitment(t)
+	updateKey, updateCommitment := newKeyAndCommitment(t)
+
+	req, err := client.NewCreateRequest(&client.CreateRequestInfo{
+		OpaqueDocument:     `{"service":[{"id":"svc0","type":"type","serviceEndpoint":"http://www.example.com"}]}`,
+		RecoveryCommitment: recoveryCommitment,
+		UpdateCommitment:   updateCommitment,
+		MultihashCode:      sha2_256,
+		AnchorOrigin:       anchorOrigin,
+	})
+	require.NoError(t, err)
+
+	pv, err := pc.Current()
+	require.NoError(t, err)
+
+	op, err := pv.OperationParser().Parse(namespace, req)
+	require.NoError(t, err)
+
+	d := &testDID{
+		suffix:      op.UniqueSuffix,
+		updateKey:   updateKey,
+		recoveryKey: recoveryKey,
+		opStore:     orbmocks.NewMockOperationStore(),
+	}
+
+	d.anchor(t, d.newOperation(operation.TypeCreate, req), 1)
+
+	return d
+}
+
+// update creates an update operation (which adds a service) and anchors it at the given transaction time.
+func (d *testDID) update(t *testing.T, txnTime uint64) {
+	t.Helper()
+
+	d.anchor(t, d.newUpdate(t), txnTime)
+}
+
+func (d *testDID) newUpdate(t *testing.T) *operation.AnchoredOperation {
+	t.Helper()
+
+	d.numUpdates++
+
+	p, err := patch.NewAddServiceEndpointsPatch(fmt.Sprintf(
+		`[{"id":"svc%d","type":"type","serviceEndpoint":"http://www.example.com"}]`, d.numUpdates))
+	require.NoError(t, err)
+
+	nextUpdateKey, nextUpdateCommitment := newKeyAndCommitment(t)
+
+	updatePubKey, err := pubkey.GetPublicKeyJWK(&d.updateKey.PublicKey)
+	require.NoError(t, err)
+
+	revealValue, err := commitment.GetRevealValue(updatePubKey, sha2_256)
+	require.NoError(t, err)
+
+	req, err := client.NewUpdateRequest(&client.UpdateRequestInfo{
+		DidSuffix:        d.suffix,
+		RevealValue:      revealValue,
+		UpdateCommitment: nextUpdateCommitment,
+		UpdateKey:        updatePubKey,
+		Patches:          []patch.Patch{p},
+		MultihashCode:    sha2_256,
+		Signer:           ecsigner.New(d.updateKey, "ES256", ""),
+	})
+	require.NoError(t, err)
+
+	d.updateKey = nextUpdateKey
+
+	return d.newOperation(operation.TypeUpdate, req)
+}
+
+// recover creates a recover operation and anchors it at the given transaction time.
+func (d *testDID) recover(t *testing.T, txnTime uint64) {
+	t.Helper()
+
+	nextRecoveryKey, nextRecoveryCommitment := newKeyAndCommitment(t)
+	nextUpdateKey, nextUpdateCommitment := newKeyAndCommitment(t)
+
+	recoveryPubKey, err := pubkey.GetPublicKeyJWK(&d.recoveryKey.PublicKey)
+	require.NoError(t, err)
+
+	revealValue, err := commitment.GetRevealValue(recoveryPubKey, sha2_256)
+	require.NoError(t, err)
+
+	req, err := client.NewRecoverRequest(&client.RecoverRequestInfo{
+		DidSuffix:          d.suffix,
+		RevealValue:        revealValue,
+		OpaqueDocument:     `{"service":[{"id":"recovered","type":"type","serviceEndpoint":"http://www.example.com"}]}`,
+		RecoveryKey:        recoveryPubKey,
+		RecoveryCommitment: nextRecoveryCommitment,
+		UpdateCommitment:   nextUpdateCommitment,
+		MultihashCode:      sha2_256,
+		Signer:             ecsigner.New(d.recoveryKey, "ES256", ""),
+		AnchorOrigin:       anchorOrigin,
+	})
+	require.NoError(t, err)
+
+	d.recoveryKey = nextRecoveryKey
+	d.updateKey = nextUpdateKey
+
+	d.anchor(t, d.newOperation(operation.TypeRecover, req), txnTime)
+}
+
+func (d *testDID) newOperation(opType operation.Type, req []byte) *operation.AnchoredOperation {
+	return &operation.AnchoredOperation{
+		Type:             opType,
+		UniqueSuffix:     d.suffix,
+		OperationRequest: req,
+		AnchorOrigin:     anchorOrigin,
+	}
+}
+
+func (d *testDID) anchor(t *testing.T, op *operation.AnchoredOperation, txnTime uint64) {
+	t.Helper()
+
+	op.TransactionTime = txnTime
+	op.CanonicalReference = fmt.Sprintf("hl:uEiA329wd6Aj36YRmp7NGkeB5ADnVt8ARdMZMPzfXsjw%04d", txnTime)
+
+	require.NoError(t, d.opStore.Put([]*operation.AnchoredOperation{op}))
+}
+
+// resolve resolves the DID from all of its operations using the Sidetree operation processor.
+func (d *testDID) resolve(t *testing.T, pc protocol.Client) *protocol.ResolutionModel {
+	t.Helper()
+
+	rm, err := processor.New(namespace, d.opStore, pc).Resolve(d.suffix)
+	require.NoError(t, err)
+
+	return rm
+}
+
+func newKeyAndCommitment(t *testing.T) (*ecdsa.PrivateKey, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	pubKey, err := pubkey.GetPublicKeyJWK(&key.PublicKey)
+	require.NoError(t, err)
+
+	c, err := commitment.GetCommitment(pubKey, sha2_256)
+	require.NoError(t, err)
+
+	return key, c
+}
+
+type countingProcessor struct {
+	processor operationProcessor
+	mutex     sync.Mutex
+	n         int
+}
+
+func (p *countingProcessor) Resolve(suffix string,
+	additionalOps ...*operation.AnchoredOperation) (*protocol.ResolutionModel, error) {
+	p.mutex.Lock()
+	p.n++
+	p.mutex.Unlock()
+
+	return p.processor.Resolve(suffix, additionalOps...)
+}
+
+func (p *countingProcessor) count() int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return p.n
+}
+
+type mockSnapshotStore struct {
+	err    error
+	putErr error
+}
+
+func (m *mockSnapshotStore) Get(string) (*snapshotstore.Snapshot, error) {
+	return nil, m.err
+}
+
+func (m *mockSnapshotStore) Put(*snapshotstore.Snapshot) error {
+	return m.putErr
+}
@@ -0,0 +1,225 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package snapshot
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+
+	"github.com/trustbloc/orb/pkg/lifecycle"
+	"github.com/trustbloc/orb/pkg/observer"
+	snapshotstore "github.com/trustbloc/orb/pkg/store/snapshot"
+)
+
+const defaultQueueSize = 1000
+
+type snapshotStore interface {
+	Get(suffix string) (*snapshotstore.Snapshot, error)
+	Put(snapshot *snapshotstore.Snapshot) error
+}
+
+// Config contains the snapshotter configuration.
+type Config struct {
+	// Namespace is the DID namespace. Anchors of other namespaces are ignored.
+	Namespace string
+	// Interval is the minimum number of operations that must be anchored for a DID since its last
+	// snapshot (or since the DID was created) before a new snapshot is taken.
+	Interval int
+	// QueueSize is the maximum number of DIDs that may be waiting for a snapshot. If the queue is full
+	// then the DID is skipped and its snapshot is taken the next time that the DID is anchored.
+	QueueSize int
+}
+
+// Snapshotter takes snapshots of the composed state of DIDs at anchor checkpoints. After an anchor is processed,
+// a new snapshot is taken for each DID in the anchor that has at least 'Interval' operations after its previous
+// snapshot. The snapshots are taken in the background so that the observer isn't delayed.
+type Snapshotter struct {
+	*lifecycle.Lifecycle
+
+	namespace     string
+	interval      int
+	opStore       operationStore
+	snapshotStore snapshotStore
+	applier       *applier
+	queue         chan string
+	wg            sync.WaitGroup
+}
+
+// NewSnapshotter returns a new DID snapshotter.
+func NewSnapshotter(cfg Config, opStore operationStore, pc protocol.Client, store snapshotStore) *Snapshotter {
+	queueSize := cfg.QueueSize
+	if queueSize <= 0 {
+		queueSize = defaultQueueSize
+	}
+
+	s := &Snapshotter{
+		namespace:     cfg.Namespace,
+		interval:      cfg.Interval,
+		opStore:       opStore,
+		snapshotStore: store,
+		applier:       &applier{pc: pc},
+		queue:         make(chan string, queueSize),
+	}
+
+	s.Lifecycle = lifecycle.New("did-snapshotter",
+		lifecycle.WithStart(s.start),
+		lifecycle.WithStop(s.stop),
+	)
+
+	return s
+}
+
+// AnchorProcessed is invoked by the observer after an anchor has been processed. The DIDs in the anchor
+// are queued for a snapshot.
+func (s *Snapshotter) AnchorProcessed(anchor *observer.ProcessedAnchor) {
+	if anchor.Namespace != s.namespace || s.State() != lifecycle.StateStarted {
+		return
+	}
+
+	for _, suffix := range anchor.Suffixes {
+		select {
+		case s.queue <- suffix:
+		default:
+			logger.Debugf("[%s] Snapshot queue is full. Skipping snapshot.", suffix)
+		}
+	}
+}
+
+// Snapshot takes a snapshot of the given DID if at least 'Interval' operations were anchored since its last
+// snapshot. If the previous snapshot is still valid then only the operations after the previous snapshot
+// are applied.
+func (s *Snapshotter) Snapshot(suffix string) error {
+	ops, err := s.opStore.Get(suffix)
+	if err != nil {
+		return fmt.Errorf("get operations: %w", err)
+	}
+
+	if len(ops) < s.interval {
+		return nil
+	}
+
+	sortedOps := sortOperations(ops)
+
+	previous, err := s.snapshotStore.Get(suffix)
+	if err != nil && !errors.Is(err, snapshotstore.ErrNotFound) {
+		return fmt.Errorf("get snapshot: %w", err)
+	}
+
+	if previous != nil {
+		done, e := s.continueFrom(previous, sortedOps)
+		if e != nil || done {
+			return e
+		}
+	}
+
+	st, err := s.applier.replay(sortedOps)
+	if err != nil {
+		return fmt.Errorf("replay operations: %w", err)
+	}
+
+	return s.put(suffix, st, sortedOps, sortedOps)
+}
+
+// continueFrom takes a new snapshot from the previous snapshot. True is returned if the snapshot was taken
+// (or if a new snapshot isn't required yet) and false is returned if all of the operations need to be replayed.
+func (s *Snapshotter) continueFrom(previous *snapshotstore.Snapshot, ops []*operation.AnchoredOperation) (bool, error) {
+	included, remaining, err := checkSnapshot(previous, ops)
+	if err != nil {
+		logger.Infof("[%s] The previous snapshot is no longer valid and will be replaced: %s", previous.Suffix, err)
+
+		return false, nil
+	}
+
+	if len(remaining) < s.interval {
+		return true, nil
+	}
+
+	candidates := append(pendingOperations(previous, included), remaining...)
+
+	st, ok := s.applier.continueFrom(previous, candidates)
+	if !ok {
+		return false, nil
+	}
+
+	return true, s.put(previous.Suffix, st, ops, candidates)
+}
+
+// put stores a snapshot of the given state at the checkpoint of the last of the given operations. The candidate
+// operations (i.e. the operations that were passed to the applier) that were not applied are stored as pending
+// operations of the snapshot.
+func (s *Snapshotter) put(suffix string, st *state, ops, candidates []*operation.AnchoredOperation) error {
+	var pending []string
+
+	for _, op := range candidates {
+		if op.Type == operation.TypeCreate {
+			continue
+		}
+
+		id := operationID(op)
+
+		if !st.applied[id] {
+			pending = append(pending, id)
+		}
+	}
+
+	last := ops[len(ops)-1]
+
+	model := *st.model
+	model.PublishedOperations = nil
+	model.UnpublishedOperations = nil
+
+	err := s.snapshotStore.Put(&snapshotstore.Snapshot{
+		Suffix:                suffix,
+		Model:                 &model,
+		TransactionTime:       last.TransactionTime,
+		TransactionNumber:     last.TransactionNumber,
+		CanonicalReference:    last.CanonicalReference,
+		OperationCount:        len(ops),
+		OperationsHash:        hashOperations(ops),
+		FullTransactionTime:   st.fullTransactionTime,
+		FullTransactionNumber: st.fullTransactionNumber,
+		RecoveryCommitments:   st.recoveryCommitments,
+		UpdateCommitments:     st.updateCommitments,
+		PendingOperations:     pending,
+	})
+	if err != nil {
+		return fmt.Errorf("store snapshot: %w", err)
+	}
+
+	logger.Debugf("[%s] Took snapshot at checkpoint [%s] with %d operations (%d pending)",
+		suffix, last.CanonicalReference, len(ops), len(pending))
+
+	return nil
+}
+
+func (s *Snapshotter) start() {
+	s.wg.Add(1)
+
+	go func() {
+		defer s.wg.Done()
+
+		for suffix := range s.queue {
+			if err := s.Snapshot(suffix); err != nil {
+				logger.Warnf("[%s] Error taking snapshot: %s", suffix, err)
+			}
+		}
+	}()
+
+	logger.Infof("Started DID snapshotter with an interval of %d operations", s.interval)
+}
+
+func (s *Snapshotter) stop() {
+	close(s.queue)
+
+	s.wg.Wait()
+
+	logger.Infof("Stopped DID snapshotter")
+}
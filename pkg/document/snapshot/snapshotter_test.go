@@ -0,0 +1,167 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package snapshot
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+
+	orbmocks "github.com/trustbloc/orb/pkg/mocks"
+	"github.com/trustbloc/orb/pkg/observer"
+	snapshotstore "github.com/trustbloc/orb/pkg/store/snapshot"
+)
+
+func TestSnapshotter_Snapshot(t *testing.T) {
+	pc, err := orbmocks.NewMockProtocolClientProvider().WithAllowedOrigins([]string{"*"}).ForNamespace(namespace)
+	require.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		d := newTestDID(t, pc)
+		d.update(t, 2)
+
+		store, err := snapshotstore.New(mem.NewProvider())
+		require.NoError(t, err)
+
+		s := NewSnapshotter(Config{Namespace: namespace, Interval: 3}, d.opStore, pc, store)
+
+		// Not enough operations.
+		require.NoError(t, s.Snapshot(d.suffix))
+
+		_, err = store.Get(d.suffix)
+		require.True(t, errors.Is(err, snapshotstore.ErrNotFound))
+
+		d.update(t, 3)
+
+		require.NoError(t, s.Snapshot(d.suffix))
+
+		snapshot, err := store.Get(d.suffix)
+		require.NoError(t, err)
+		require.Equal(t, 3, snapshot.OperationCount)
+		require.Equal(t, uint64(3), snapshot.TransactionTime)
+		require.Len(t, snapshot.UpdateCommitments, 2)
+		require.Empty(t, snapshot.PendingOperations)
+		require.Empty(t, snapshot.Model.PublishedOperations)
+
+		// Not enough operations since the last snapshot.
+		d.update(t, 4)
+		d.update(t, 5)
+
+		require.NoError(t, s.Snapshot(d.suffix))
+
+		snapshot, err = store.Get(d.suffix)
+		require.NoError(t, err)
+		require.Equal(t, 3, snapshot.OperationCount)
+
+		// The new snapshot is taken from the previous snapshot.
+		d.update(t, 6)
+
+		require.NoError(t, s.Snapshot(d.suffix))
+
+		snapshot, err = store.Get(d.suffix)
+		require.NoError(t, err)
+		require.Equal(t, 6, snapshot.OperationCount)
+		require.Equal(t, uint64(6), snapshot.TransactionTime)
+		require.Len(t, snapshot.UpdateCommitments, 5)
+
+		expected := d.resolve(t, pc)
+		require.Equal(t, expected.Doc, snapshot.Model.Doc)
+		require.Equal(t, expected.UpdateCommitment, snapshot.Model.UpdateCommitment)
+	})
+
+	t.Run("previous snapshot is replaced after recover", func(t *testing.T) {
+		d := newTestDID(t, pc)
+		d.update(t, 2)
+
+		store, err := snapshotstore.New(mem.NewProvider())
+		require.NoError(t, err)
+
+		s := NewSnapshotter(Config{Namespace: namespace, Interval: 2}, d.opStore, pc, store)
+
+		require.NoError(t, s.Snapshot(d.suffix))
+
+		d.recover(t, 3)
+		d.update(t, 4)
+
+		require.NoError(t, s.Snapshot(d.suffix))
+
+		snapshot, err := store.Get(d.suffix)
+		require.NoError(t, err)
+		require.Equal(t, 4, snapshot.OperationCount)
+		require.Equal(t, uint64(3), snapshot.FullTransactionTime)
+		require.Len(t, snapshot.RecoveryCommitments, 1)
+		require.Len(t, snapshot.UpdateCommitments, 1)
+
+		expected := d.resolve(t, pc)
+		require.Equal(t, expected.Doc, snapshot.Model.Doc)
+		require.Equal(t, expected.RecoveryCommitment, snapshot.Model.RecoveryCommitment)
+	})
+
+	t.Run("operation store error", func(t *testing.T) {
+		store, err := snapshotstore.New(mem.NewProvider())
+		require.NoError(t, err)
+
+		s := NewSnapshotter(Config{Namespace: namespace, Interval: 2}, orbmocks.NewMockOperationStore(), pc, store)
+
+		err = s.Snapshot("unknown")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get operations")
+	})
+
+	t.Run("snapshot store error", func(t *testing.T) {
+		d := newTestDID(t, pc)
+		d.update(t, 2)
+
+		s := NewSnapshotter(Config{Namespace: namespace, Interval: 2}, d.opStore, pc,
+			&mockSnapshotStore{err: errors.New("injected get error")})
+
+		err := s.Snapshot(d.suffix)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected get error")
+
+		s = NewSnapshotter(Config{Namespace: namespace, Interval: 2}, d.opStore, pc,
+			&mockSnapshotStore{err: snapshotstore.ErrNotFound, putErr: errors.New("injected put error")})
+
+		err = s.Snapshot(d.suffix)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected put error")
+	})
+}
+
+func TestSnapshotter_AnchorProcessed(t *testing.T) {
+	pc, err := orbmocks.NewMockProtocolClientProvider().WithAllowedOrigins([]string{"*"}).ForNamespace(namespace)
+	require.NoError(t, err)
+
+	d := newTestDID(t, pc)
+	d.update(t, 2)
+
+	store, err := snapshotstore.New(mem.NewProvider())
+	require.NoError(t, err)
+
+	s := NewSnapshotter(Config{Namespace: namespace, Interval: 2}, d.opStore, pc, store)
+
+	s.Start()
+	defer s.Stop()
+
+	s.AnchorProcessed(&observer.ProcessedAnchor{Namespace: "did:other", Suffixes: []string{d.suffix}})
+
+	time.Sleep(100 * time.Millisecond)
+
+	_, err = store.Get(d.suffix)
+	require.True(t, errors.Is(err, snapshotstore.ErrNotFound))
+
+	s.AnchorProcessed(&observer.ProcessedAnchor{Namespace: namespace, Suffixes: []string{d.suffix, "unknown"}})
+
+	require.Eventually(t, func() bool {
+		_, e := store.Get(d.suffix)
+
+		return e == nil
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,63 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package uniresolver
+
+import (
+	"time"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
+
+	"github.com/trustbloc/orb/pkg/document/resolutionhandler"
+)
+
+const (
+	// BasePath is the base path of the resolution endpoint that's invoked by the Universal Resolver, i.e.
+	// a DID is resolved with GET /1.0/identifiers/{did}.
+	BasePath = "/1.0/identifiers"
+
+	// DriverPattern is the pattern of the DIDs that are resolved by the driver. This pattern is
+	// used in the 'drivers' section of the Universal Resolver configuration.
+	DriverPattern = "^(did:orb:.+)$"
+)
+
+// DriverConfig is the entry for the did:orb driver in the 'drivers' section of the Universal Resolver
+// configuration (config.json).
+type DriverConfig struct {
+	Pattern         string   `json:"pattern"`
+	URL             string   `json:"url"`
+	TestIdentifiers []string `json:"testIdentifiers,omitempty"`
+}
+
+type resolver interface {
+	ResolveDocument(id string) (*document.ResolutionResult, error)
+}
+
+type metricsProvider interface {
+	HTTPResolveTime(value time.Duration)
+}
+
+// New returns a Universal Resolver driver handler for did:orb. The handler accepts the same DIDs and DID URLs
+// as the Orb resolution endpoint, but the DID resolution result (including the driver metadata) is returned
+// by default rather than the Sidetree-compatible response, so that did:orb may be plugged into the DIF
+// Universal Resolver without a separate driver proxy.
+func New(resolver resolver, metrics metricsProvider) *resolutionhandler.Handler {
+	return resolutionhandler.New(BasePath, resolver, metrics,
+		resolutionhandler.WithDefaultRepresentation(resolutionhandler.MediaTypeDIDResolution),
+		resolutionhandler.WithDriverMetadata(),
+	)
+}
+
+// NewDriverConfig returns the Universal Resolver configuration for the did:orb driver. The given URL is the
+// external URL of the Orb server (e.g. https://orb.domain.com/), which the Universal Resolver appends the
+// driver path and DID to.
+func NewDriverConfig(url string, testIdentifiers ...string) *DriverConfig {
+	return &DriverConfig{
+		Pattern:         DriverPattern,
+		URL:             url,
+		TestIdentifiers: testIdentifiers,
+	}
+}
@@ -0,0 +1,95 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package uniresolver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
+
+	"github.com/trustbloc/orb/pkg/document/resolutionhandler"
+)
+
+const did = "did:orb:uAAA:EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A"
+
+func TestNew(t *testing.T) {
+	h := New(&mockResolver{}, &mockMetrics{})
+	require.Equal(t, BasePath+"/{id}", h.Path())
+	require.Equal(t, http.MethodGet, h.Method())
+
+	t.Run("Resolution result", func(t *testing.T) {
+		for _, accept := range []string{"", resolutionhandler.MediaTypeDIDResolution} {
+			req := httptest.NewRequest(http.MethodGet, BasePath+"/"+did, nil)
+
+			if accept != "" {
+				req.Header.Set("Accept", accept)
+			}
+
+			rw := httptest.NewRecorder()
+			h.Handler()(rw, mux.SetURLVars(req, map[string]string{"id": did}))
+
+			require.Equal(t, http.StatusOK, rw.Code)
+			require.Equal(t, resolutionhandler.MediaTypeDIDResolution, rw.Header().Get("Content-Type"))
+
+			rr := &resolutionhandler.ResolutionResult{}
+			require.NoError(t, json.Unmarshal(rw.Body.Bytes(), rr))
+			require.Equal(t, did, rr.Document["id"])
+			require.NotNil(t, rr.ResolutionMetadata)
+			require.NotNil(t, rr.ResolutionMetadata.DID)
+			require.Equal(t, "orb", rr.ResolutionMetadata.DID.Method)
+			require.NotEmpty(t, rr.ResolutionMetadata.Retrieved)
+		}
+	})
+
+	t.Run("DID document", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, BasePath+"/"+did, nil)
+		req.Header.Set("Accept", resolutionhandler.MediaTypeDIDLDJSON)
+
+		rw := httptest.NewRecorder()
+		h.Handler()(rw, mux.SetURLVars(req, map[string]string{"id": did}))
+
+		require.Equal(t, http.StatusOK, rw.Code)
+		require.Equal(t, resolutionhandler.MediaTypeDIDLDJSON, rw.Header().Get("Content-Type"))
+
+		doc := document.Document{}
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &doc))
+		require.Equal(t, did, doc["id"])
+	})
+}
+
+func TestNewDriverConfig(t *testing.T) {
+	cfg := NewDriverConfig("https://orb.domain1.com/", did)
+
+	cfgBytes, err := json.Marshal(cfg)
+	require.NoError(t, err)
+	require.Equal(t, `{"pattern":"^(did:orb:.+)$","url":"https://orb.domain1.com/","testIdentifiers":["`+did+`"]}`,
+		string(cfgBytes))
+
+	pattern := regexp.MustCompile(cfg.Pattern)
+	require.True(t, pattern.MatchString(did))
+	require.False(t, pattern.MatchString("did:web:domain1.com"))
+}
+
+type mockResolver struct{}
+
+func (m *mockResolver) ResolveDocument(id string) (*document.ResolutionResult, error) {
+	return &document.ResolutionResult{
+		Document:         document.Document{"id": id},
+		DocumentMetadata: document.Metadata{},
+	}, nil
+}
+
+type mockMetrics struct{}
+
+func (m *mockMetrics) HTTPResolveTime(time.Duration) {}
@@ -8,6 +8,7 @@ package decorator
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/trustbloc/edge-core/pkg/log"
@@ -99,12 +100,14 @@ func (d *OperationDecorator) Decorate(op *operation.Operation) (*operation.Opera
 	canonicalID := d.namespace + docutil.NamespaceDelimiter +
 		internalResult.CanonicalReference + docutil.NamespaceDelimiter + op.UniqueSuffix
 
-	localAnchorOrigin, ok := internalResult.AnchorOrigin.(string)
-	if !ok {
+	localAnchorOrigins, err := util.ParseAnchorOrigins(internalResult.AnchorOrigin)
+	if err != nil {
 		// this should never happen locally
 		return nil, fmt.Errorf("anchor origin is not a string in local result for suffix[%s]", op.UniqueSuffix)
 	}
 
+	localAnchorOrigin := localAnchorOrigins[0]
+
 	if localAnchorOrigin == d.domain {
 		// local domain is anchor origin - nothing else to check
 		return op, nil
@@ -112,7 +115,7 @@ func (d *OperationDecorator) Decorate(op *operation.Operation) (*operation.Opera
 
 	resolveFromAnchorOriginTime := time.Now()
 
-	anchorOriginResponse, err := d.resolveDocumentFromAnchorOrigin(canonicalID, localAnchorOrigin)
+	anchorOriginResponse, err := d.resolveDocumentFromAnchorOrigins(canonicalID, localAnchorOrigins)
 	if err != nil {
 		logger.Debugf("failed to resolve document from anchor origin for id[%s]: %s", canonicalID, err.Error())
 
@@ -164,6 +167,32 @@ func (d *OperationDecorator) Decorate(op *operation.Operation) (*operation.Opera
 	return op, nil
 }
 
+// resolveDocumentFromAnchorOrigins attempts to resolve the document from each of the given anchor origins
+// (primary first, followed by the fallbacks) and returns the first successful response.
+func (d *OperationDecorator) resolveDocumentFromAnchorOrigins(id string, anchorOrigins []string) (*document.ResolutionResult, error) { //nolint:lll
+	var errs []string
+
+	for _, anchorOrigin := range anchorOrigins {
+		if anchorOrigin == d.domain {
+			continue
+		}
+
+		anchorOriginResponse, err := d.resolveDocumentFromAnchorOrigin(id, anchorOrigin)
+		if err != nil {
+			logger.Debugf("failed to resolve id[%s] from anchor origin[%s]: %s", id, anchorOrigin, err)
+
+			errs = append(errs, err.Error())
+
+			continue
+		}
+
+		return anchorOriginResponse, nil
+	}
+
+	return nil, fmt.Errorf("unable to resolve id[%s] from anchor origins%s: %s", id, anchorOrigins,
+		strings.Join(errs, "; "))
+}
+
 func (d *OperationDecorator) resolveDocumentFromAnchorOrigin(id, anchorOrigin string) (*document.ResolutionResult, error) { //nolint:lll
 	endpoint, err := d.endpointClient.GetEndpoint(anchorOrigin)
 	if err != nil {
@@ -313,6 +313,54 @@ func TestOperationDecorator_Decorate(t *testing.T) {
 		require.NotNil(t, op)
 	})
 
+	t.Run("error - fallback anchor origin has additional operations", func(t *testing.T) {
+		const fallbackAnchorOrigin = "https://fallback.domain.com"
+
+		processor := &mocks.OperationProcessor{}
+		processor.ResolveReturns(&protocol.ResolutionModel{
+			AnchorOrigin: []interface{}{anchorOriginDomain, fallbackAnchorOrigin},
+			PublishedOperations: []*operation.AnchoredOperation{
+				{Type: operation.TypeCreate, UniqueSuffix: suffix, CanonicalReference: "abc"},
+			},
+		}, nil)
+
+		endpointClient := &mocks.EndpointClient{}
+		endpointClient.GetEndpointReturnsOnCall(0, nil, fmt.Errorf("primary anchor origin unavailable"))
+		endpointClient.GetEndpointReturnsOnCall(1,
+			&models.Endpoint{
+				ResolutionEndpoints: []string{fmt.Sprintf("%s/identifiers", fallbackAnchorOrigin)},
+			}, nil)
+
+		methodMetadata := make(map[string]interface{})
+		publishedOps := []metadata.PublishedOperation{
+			{Type: operation.TypeCreate, CanonicalReference: "abc"},
+			{Type: operation.TypeUpdate, CanonicalReference: "xyz"},
+		}
+		methodMetadata[document.PublishedOperationsProperty] = publishedOps
+		methodMetadata[document.AnchorOriginProperty] = []interface{}{anchorOriginDomain, fallbackAnchorOrigin}
+
+		docMetadata := make(document.Metadata)
+		docMetadata[document.MethodProperty] = methodMetadata
+
+		remoteResolver := &mocks.RemoteResolver{}
+		remoteResolver.ResolveDocumentFromResolutionEndpointsReturns(
+			&document.ResolutionResult{
+				Document:         make(document.Document),
+				DocumentMetadata: docMetadata,
+			}, nil)
+
+		handler := New(namespace, domain, processor, endpointClient, remoteResolver, &orbmocks.MetricsProvider{})
+		require.NotNil(t, handler)
+
+		op, err := handler.Decorate(&operation.Operation{Type: operation.TypeUpdate, UniqueSuffix: suffix})
+		require.Error(t, err)
+		require.Nil(t, op)
+		require.Contains(t, err.Error(), "anchor origin has additional published operations")
+
+		require.Equal(t, 2, endpointClient.GetEndpointCallCount())
+		require.Equal(t, fallbackAnchorOrigin, endpointClient.GetEndpointArgsForCall(1))
+	})
+
 	t.Run("error - operation processor error", func(t *testing.T) {
 		processor := &mocks.OperationProcessor{}
 		processor.ResolveReturns(nil, fmt.Errorf("operation processor error"))
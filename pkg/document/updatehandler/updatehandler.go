@@ -22,6 +22,7 @@ var logger = log.New("orb-update-handler")
 
 type metricsProvider interface {
 	DocumentCreateUpdateTime(duration time.Duration)
+	DocumentIncrementProcessedOperationCount()
 }
 
 // Option is an option for update handler.
@@ -77,6 +78,8 @@ func (r *UpdateHandler) ProcessOperation(operationBuffer []byte, protocolVersion
 		return nil, err
 	}
 
+	r.metrics.DocumentIncrementProcessedOperationCount()
+
 	if doc != nil && r.createDocumentStoreEnabled {
 		// document is returned only in 'create' case
 		r.storeResultToCreateDocumentStore(doc)
@@ -126,24 +126,71 @@ func GetMethodMetadata(metadata document.Metadata) (map[string]interface{}, erro
 	}
 }
 
-// GetAnchorOrigin returns anchor origin from document metadata.
+// GetAnchorOrigin returns the (primary) anchor origin from document metadata.
 func GetAnchorOrigin(metadata document.Metadata) (string, error) {
-	methodMeta, err := GetMethodMetadata(metadata)
+	anchorOrigins, err := GetAnchorOrigins(metadata)
 	if err != nil {
 		return "", err
 	}
 
+	return anchorOrigins[0], nil
+}
+
+// GetAnchorOrigins returns the anchor origins (the primary anchor origin followed by the fallback anchor
+// origins, if any) from document metadata.
+func GetAnchorOrigins(metadata document.Metadata) ([]string, error) {
+	methodMeta, err := GetMethodMetadata(metadata)
+	if err != nil {
+		return nil, err
+	}
+
 	anchorOriginObj, ok := methodMeta[document.AnchorOriginProperty]
 	if !ok {
-		return "", fmt.Errorf("missing anchor origin property in method metadata")
+		return nil, fmt.Errorf("missing anchor origin property in method metadata")
 	}
 
-	anchorOrigin, ok := anchorOriginObj.(string)
-	if !ok {
-		return "", fmt.Errorf("anchor origin property is not a string")
+	anchorOrigins, err := ParseAnchorOrigins(anchorOriginObj)
+	if err != nil {
+		return nil, fmt.Errorf("anchor origin property is not a string")
 	}
 
-	return anchorOrigin, nil
+	return anchorOrigins, nil
+}
+
+// ParseAnchorOrigins returns the anchor origins from the given anchor origin object. The anchor origin of
+// a create or recover operation is either a single origin (string) or an ordered list of origins, in which
+// case the first origin is the primary anchor origin and the rest are fallbacks that may be contacted
+// (in order) when the primary anchor origin is unavailable.
+func ParseAnchorOrigins(obj interface{}) ([]string, error) {
+	switch value := obj.(type) {
+	case string:
+		return []string{value}, nil
+	case []string:
+		return parseAnchorOriginList(len(value), func(i int) interface{} { return value[i] })
+	case []interface{}:
+		return parseAnchorOriginList(len(value), func(i int) interface{} { return value[i] })
+	default:
+		return nil, fmt.Errorf("anchor origin type not supported %T", obj)
+	}
+}
+
+func parseAnchorOriginList(n int, get func(i int) interface{}) ([]string, error) {
+	if n == 0 {
+		return nil, fmt.Errorf("anchor origin list is empty")
+	}
+
+	anchorOrigins := make([]string, n)
+
+	for i := 0; i < n; i++ {
+		anchorOrigin, ok := get(i).(string)
+		if !ok || anchorOrigin == "" {
+			return nil, fmt.Errorf("anchor origin at index %d is not a valid string", i)
+		}
+
+		anchorOrigins[i] = anchorOrigin
+	}
+
+	return anchorOrigins, nil
 }
 
 func getOperationsByKey(methodMetadata map[string]interface{}, key string) ([]*operation.AnchoredOperation, error) {
@@ -166,3 +213,17 @@ func getOperationsByKey(methodMetadata map[string]interface{}, key string) ([]*o
 
 	return ops, nil
 }
+
+// GetOperationType returns the operation type (create, update, recover or deactivate) of the given operation request.
+func GetOperationType(request []byte) (operation.Type, error) {
+	r := &struct {
+		Type operation.Type `json:"type"`
+	}{}
+
+	err := json.Unmarshal(request, r)
+	if err != nil {
+		return "", fmt.Errorf("unmarshal operation request: %w", err)
+	}
+
+	return r.Type, nil
+}
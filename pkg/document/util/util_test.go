@@ -105,6 +105,60 @@ func TestGetAnchorOrigin(t *testing.T) {
 		require.Empty(t, anchorOrigin)
 		require.Equal(t, "anchor origin property is not a string", err.Error())
 	})
+
+	t.Run("success - primary and fallback anchor origins", func(t *testing.T) {
+		methodMetadata := make(map[string]interface{})
+		methodMetadata[document.AnchorOriginProperty] = []interface{}{"domain1.com", "domain2.com"}
+
+		docMetadata := make(document.Metadata)
+		docMetadata[document.MethodProperty] = methodMetadata
+
+		anchorOrigin, err := GetAnchorOrigin(docMetadata)
+		require.NoError(t, err)
+		require.Equal(t, "domain1.com", anchorOrigin)
+
+		anchorOrigins, err := GetAnchorOrigins(docMetadata)
+		require.NoError(t, err)
+		require.Equal(t, []string{"domain1.com", "domain2.com"}, anchorOrigins)
+	})
+}
+
+func TestParseAnchorOrigins(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		anchorOrigins, err := ParseAnchorOrigins("domain1.com")
+		require.NoError(t, err)
+		require.Equal(t, []string{"domain1.com"}, anchorOrigins)
+
+		anchorOrigins, err = ParseAnchorOrigins([]string{"domain1.com", "domain2.com"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"domain1.com", "domain2.com"}, anchorOrigins)
+
+		anchorOrigins, err = ParseAnchorOrigins([]interface{}{"domain1.com", "domain2.com"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"domain1.com", "domain2.com"}, anchorOrigins)
+	})
+
+	t.Run("error - unsupported type", func(t *testing.T) {
+		_, err := ParseAnchorOrigins(123)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "anchor origin type not supported int")
+	})
+
+	t.Run("error - empty list", func(t *testing.T) {
+		_, err := ParseAnchorOrigins([]interface{}{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "anchor origin list is empty")
+	})
+
+	t.Run("error - invalid value in list", func(t *testing.T) {
+		_, err := ParseAnchorOrigins([]interface{}{"domain1.com", 123})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "anchor origin at index 1 is not a valid string")
+
+		_, err = ParseAnchorOrigins([]string{"domain1.com", ""})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "anchor origin at index 1 is not a valid string")
+	})
 }
 
 func TestGetOperations(t *testing.T) {
@@ -178,3 +232,17 @@ func TestGetOperationsAfterCanonicalReference(t *testing.T) {
 		require.Equal(t, "xyz", ops[0].CanonicalReference)
 	})
 }
+
+func TestGetOperationType(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		opType, err := GetOperationType([]byte(`{"type":"update","didSuffix":"suffix"}`))
+		require.NoError(t, err)
+		require.Equal(t, operation.TypeUpdate, opType)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		_, err := GetOperationType([]byte("invalid"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal operation request")
+	})
+}
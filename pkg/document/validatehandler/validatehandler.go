@@ -0,0 +1,123 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package validatehandler
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/trustbloc/edge-core/pkg/log"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+
+	"github.com/trustbloc/orb/pkg/document/opvalidator"
+)
+
+var logger = log.New("operation-validate-handler")
+
+const (
+	validatePath = "/validate"
+
+	badRequestResponse          = "Bad Request."
+	internalServerErrorResponse = "Internal Server Error."
+)
+
+// Response contains the result of validating an operation.
+type Response = opvalidator.Result
+
+// Handler validates a Sidetree operation with the current protocol version without adding it to the operation queue.
+// See opvalidator.Validate for the validations that are performed.
+type Handler struct {
+	basePath  string
+	namespace string
+	pc        protocol.Client
+	readAll   func(r io.Reader) ([]byte, error)
+	marshal   func(v interface{}) ([]byte, error)
+}
+
+// New returns a new operation validation handler. The endpoint of the handler is <basePath>/validate.
+func New(basePath, namespace string, pc protocol.Client) *Handler {
+	return &Handler{
+		basePath:  basePath,
+		namespace: namespace,
+		pc:        pc,
+		readAll:   ioutil.ReadAll,
+		marshal:   json.Marshal,
+	}
+}
+
+// Path returns the HTTP REST endpoint for the operation validation service.
+func (h *Handler) Path() string {
+	return h.basePath + validatePath
+}
+
+// Method returns the HTTP method, which is always POST.
+func (h *Handler) Method() string {
+	return http.MethodPost
+}
+
+// Handler returns the HTTP REST handle for the operation validation service.
+func (h *Handler) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Handler) handle(w http.ResponseWriter, req *http.Request) {
+	reqBytes, err := h.readAll(req.Body)
+	if err != nil {
+		logger.Errorf("[%s] Error reading request body: %s", h.Path(), err)
+
+		writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	pv, err := h.pc.Current()
+	if err != nil {
+		logger.Errorf("[%s] Error getting current protocol version: %s", h.Path(), err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	resp := opvalidator.Validate(h.namespace, reqBytes, pv)
+
+	respBytes, err := h.marshal(resp)
+	if err != nil {
+		logger.Errorf("[%s] Error marshalling response: %s", h.Path(), err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	if !resp.Valid {
+		logger.Debugf("[%s] Operation is not valid: %s", h.Path(), resp.Error)
+
+		writeJSONResponse(w, http.StatusBadRequest, respBytes)
+
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, respBytes)
+}
+
+func writeJSONResponse(w http.ResponseWriter, status int, body []byte) {
+	w.Header().Set("Content-Type", "application/json")
+
+	writeResponse(w, status, body)
+}
+
+func writeResponse(w http.ResponseWriter, status int, body []byte) {
+	w.WriteHeader(status)
+
+	if _, err := w.Write(body); err != nil {
+		logger.Warnf("Unable to write response: %s", err)
+	}
+}
@@ -0,0 +1,164 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package validatehandler
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+	"github.com/trustbloc/sidetree-core-go/pkg/commitment"
+	"github.com/trustbloc/sidetree-core-go/pkg/util/pubkey"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/client"
+
+	orbmocks "github.com/trustbloc/orb/pkg/mocks"
+)
+
+const (
+	namespace = "did:orb"
+	basePath  = "/sidetree/v1/operations"
+
+	sha2_256 = 18
+
+	validDoc = `{"service":[{"id":"svc1","type":"type","serviceEndpoint":"http://www.example.com"}]}`
+)
+
+func TestHandler(t *testing.T) {
+	pc, err := orbmocks.NewMockProtocolClientProvider().WithAllowedOrigins([]string{"*"}).ForNamespace(namespace)
+	require.NoError(t, err)
+
+	h := New(basePath, namespace, pc)
+	require.Equal(t, basePath+validatePath, h.Path())
+	require.Equal(t, http.MethodPost, h.Method())
+	require.NotNil(t, h.Handler())
+
+	t.Run("valid create operation", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+
+		h.Handler()(rw, httptest.NewRequest(http.MethodPost, h.Path(), bytes.NewReader(newCreateRequest(t))))
+
+		result := rw.Result()
+		defer result.Body.Close()
+
+		require.Equal(t, http.StatusOK, result.StatusCode)
+		require.Equal(t, "application/json", result.Header.Get("Content-Type"))
+
+		resp := &Response{}
+		require.NoError(t, json.NewDecoder(result.Body).Decode(resp))
+		require.True(t, resp.Valid)
+		require.Empty(t, resp.Error)
+		require.Equal(t, operation.TypeCreate, resp.Type)
+		require.NotEmpty(t, resp.DIDSuffix)
+		require.NotEmpty(t, resp.Document)
+	})
+
+	t.Run("invalid operation", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+
+		h.Handler()(rw, httptest.NewRequest(http.MethodPost, h.Path(), bytes.NewReader([]byte(`{"type":"create"}`))))
+
+		result := rw.Result()
+		defer result.Body.Close()
+
+		require.Equal(t, http.StatusBadRequest, result.StatusCode)
+
+		resp := &Response{}
+		require.NoError(t, json.NewDecoder(result.Body).Decode(resp))
+		require.False(t, resp.Valid)
+		require.Contains(t, resp.Error, "parse operation")
+	})
+
+	t.Run("read body error", func(t *testing.T) {
+		h := New(basePath, namespace, pc)
+		h.readAll = func(io.Reader) ([]byte, error) { return nil, errors.New("injected read error") }
+
+		rw := httptest.NewRecorder()
+
+		h.Handler()(rw, httptest.NewRequest(http.MethodPost, h.Path(), nil))
+
+		result := rw.Result()
+		require.NoError(t, result.Body.Close())
+		require.Equal(t, http.StatusBadRequest, result.StatusCode)
+	})
+
+	t.Run("protocol client error", func(t *testing.T) {
+		h := New(basePath, namespace, &mockProtocolClient{err: errors.New("injected protocol error")})
+
+		rw := httptest.NewRecorder()
+
+		h.Handler()(rw, httptest.NewRequest(http.MethodPost, h.Path(), bytes.NewReader(newCreateRequest(t))))
+
+		result := rw.Result()
+		require.NoError(t, result.Body.Close())
+		require.Equal(t, http.StatusInternalServerError, result.StatusCode)
+	})
+
+	t.Run("marshal error", func(t *testing.T) {
+		h := New(basePath, namespace, pc)
+		h.marshal = func(interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		rw := httptest.NewRecorder()
+
+		h.Handler()(rw, httptest.NewRequest(http.MethodPost, h.Path(), bytes.NewReader(newCreateRequest(t))))
+
+		result := rw.Result()
+		require.NoError(t, result.Body.Close())
+		require.Equal(t, http.StatusInternalServerError, result.StatusCode)
+	})
+}
+
+func newCreateRequest(t *testing.T) []byte {
+	t.Helper()
+
+	reqBytes, err := client.NewCreateRequest(&client.CreateRequestInfo{
+		OpaqueDocument:     validDoc,
+		RecoveryCommitment: newCommitment(t),
+		UpdateCommitment:   newCommitment(t),
+		MultihashCode:      sha2_256,
+		AnchorOrigin:       "https://orb.domain1.com",
+	})
+	require.NoError(t, err)
+
+	return reqBytes
+}
+
+func newCommitment(t *testing.T) string {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	pubKey, err := pubkey.GetPublicKeyJWK(&key.PublicKey)
+	require.NoError(t, err)
+
+	c, err := commitment.GetCommitment(pubKey, sha2_256)
+	require.NoError(t, err)
+
+	return c
+}
+
+type mockProtocolClient struct {
+	err error
+}
+
+func (m *mockProtocolClient) Current() (protocol.Version, error) {
+	return nil, m.err
+}
+
+func (m *mockProtocolClient) Get(uint64) (protocol.Version, error) {
+	return nil, m.err
+}
@@ -0,0 +1,68 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package faultinjection
+
+import (
+	"github.com/trustbloc/orb/pkg/cas/extendedcasclient"
+)
+
+const casName = "cas"
+
+// CASClient is a CAS client that injects faults into its operations. A partial failure of a write stores the
+// content and then returns an error. A partial failure of a read returns an error.
+type CASClient struct {
+	extendedcasclient.Client
+
+	injector *Injector
+}
+
+// NewCASClient returns a CAS client that injects faults into the operations of the given client.
+func NewCASClient(client extendedcasclient.Client, injector *Injector) *CASClient {
+	return &CASClient{
+		Client:   client,
+		injector: injector,
+	}
+}
+
+// Write writes the given content to CAS and returns its address.
+func (c *CASClient) Write(content []byte) (string, error) {
+	return c.write(func() (string, error) {
+		return c.Client.Write(content)
+	})
+}
+
+// WriteWithCIDFormat writes the given content to CAS using the given CID format and returns its address.
+func (c *CASClient) WriteWithCIDFormat(content []byte, opts ...extendedcasclient.CIDFormatOption) (string, error) {
+	return c.write(func() (string, error) {
+		return c.Client.WriteWithCIDFormat(content, opts...)
+	})
+}
+
+// Read reads the content at the given address.
+func (c *CASClient) Read(address string) ([]byte, error) {
+	if c.injector.inject(TargetCAS, casName) != faultNone {
+		return nil, newError(TargetCAS, address, "read")
+	}
+
+	return c.Client.Read(address)
+}
+
+func (c *CASClient) write(write func() (string, error)) (string, error) {
+	switch c.injector.inject(TargetCAS, casName) {
+	case faultError:
+		return "", newError(TargetCAS, casName, "write")
+	case faultPartial:
+		address, err := write()
+		if err != nil {
+			return "", err
+		}
+
+		return "", newError(TargetCAS, address, "write")
+	default:
+		return write()
+	}
+}
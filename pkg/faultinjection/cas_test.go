@@ -0,0 +1,83 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package faultinjection
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/cas/extendedcasclient"
+	"github.com/trustbloc/orb/pkg/cas/resolver/mocks"
+)
+
+func TestCASClient(t *testing.T) {
+	const address = "bafkreiatkubvbkdidscmqynkyls3iqawdqvthi7e6mbky2amuw3inxsi3y"
+
+	t.Run("No fault", func(t *testing.T) {
+		casClient := &mocks.CASClient{}
+		casClient.WriteReturns(address, nil)
+		casClient.WriteWithCIDFormatReturns(address, nil)
+		casClient.ReadReturns([]byte("content"), nil)
+		casClient.GetPrimaryWriterTypeReturns("local")
+
+		c := NewCASClient(casClient, newTestInjector(t, TargetCAS, faultNone))
+
+		a, err := c.Write([]byte("content"))
+		require.NoError(t, err)
+		require.Equal(t, address, a)
+
+		a, err = c.WriteWithCIDFormat([]byte("content"), extendedcasclient.WithCIDVersion(1))
+		require.NoError(t, err)
+		require.Equal(t, address, a)
+
+		content, err := c.Read(address)
+		require.NoError(t, err)
+		require.Equal(t, []byte("content"), content)
+
+		require.Equal(t, "local", c.GetPrimaryWriterType())
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		casClient := &mocks.CASClient{}
+
+		c := NewCASClient(casClient, newTestInjector(t, TargetCAS, faultError))
+
+		_, err := c.Write([]byte("content"))
+		requireInjected(t, err)
+
+		_, err = c.WriteWithCIDFormat([]byte("content"))
+		requireInjected(t, err)
+
+		_, err = c.Read(address)
+		requireInjected(t, err)
+
+		require.Zero(t, casClient.WriteCallCount())
+		require.Zero(t, casClient.WriteWithCIDFormatCallCount())
+		require.Zero(t, casClient.ReadCallCount())
+	})
+
+	t.Run("Partial failure", func(t *testing.T) {
+		casClient := &mocks.CASClient{}
+		casClient.WriteReturns(address, nil)
+
+		c := NewCASClient(casClient, newTestInjector(t, TargetCAS, faultPartial))
+
+		_, err := c.Write([]byte("content"))
+		requireInjected(t, err)
+		require.Contains(t, err.Error(), address)
+		require.Equal(t, 1, casClient.WriteCallCount())
+
+		errExpected := errors.New("injected write error")
+
+		casClient.WriteWithCIDFormatReturns("", errExpected)
+
+		_, err = c.WriteWithCIDFormat([]byte("content"))
+		require.ErrorIs(t, err, errExpected)
+	})
+}
@@ -0,0 +1,272 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package faultinjection
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+var logger = log.New("fault-injection")
+
+// ErrInjected is the error returned by an operation into which a fault was injected.
+var ErrInjected = errors.New("injected fault")
+
+// Target is the layer into which faults are injected.
+type Target = string
+
+const (
+	// TargetStore injects faults into the store SPI.
+	TargetStore Target = "store"
+	// TargetCAS injects faults into the CAS client.
+	TargetCAS Target = "cas"
+	// TargetTransport injects faults into outbound HTTP requests (ActivityPub, WebCAS and VCT).
+	TargetTransport Target = "transport"
+)
+
+// Rule specifies the faults that are injected into the operations of a target.
+type Rule struct {
+	// Latency is added to every operation.
+	Latency time.Duration
+	// ErrorRate is the probability (0 to 1) that an operation fails without being performed.
+	ErrorRate float64
+	// PartialFailureRate is the probability (0 to 1) that an operation is performed (or, in the case of a batch,
+	// partially performed) but an error is returned to the caller.
+	PartialFailureRate float64
+	// Match restricts the rule to the given names (store names for the store target and hosts for the
+	// transport target). If empty then the rule applies to all operations of the target.
+	Match []string
+}
+
+type rawRule struct {
+	Latency            string   `json:"latency,omitempty"`
+	ErrorRate          float64  `json:"errorRate,omitempty"`
+	PartialFailureRate float64  `json:"partialFailureRate,omitempty"`
+	Match              []string `json:"match,omitempty"`
+}
+
+// MarshalJSON marshals the rule. The latency is marshalled as a duration string, for example "100ms".
+func (r *Rule) MarshalJSON() ([]byte, error) {
+	raw := &rawRule{
+		ErrorRate:          r.ErrorRate,
+		PartialFailureRate: r.PartialFailureRate,
+		Match:              r.Match,
+	}
+
+	if r.Latency > 0 {
+		raw.Latency = r.Latency.String()
+	}
+
+	return json.Marshal(raw)
+}
+
+// UnmarshalJSON unmarshals the rule.
+func (r *Rule) UnmarshalJSON(b []byte) error {
+	raw := &rawRule{}
+
+	if err := json.Unmarshal(b, raw); err != nil {
+		return err
+	}
+
+	var latency time.Duration
+
+	if raw.Latency != "" {
+		var err error
+
+		latency, err = time.ParseDuration(raw.Latency)
+		if err != nil {
+			return fmt.Errorf("invalid latency: %w", err)
+		}
+	}
+
+	*r = Rule{
+		Latency:            latency,
+		ErrorRate:          raw.ErrorRate,
+		PartialFailureRate: raw.PartialFailureRate,
+		Match:              raw.Match,
+	}
+
+	return nil
+}
+
+func (r *Rule) matches(name string) bool {
+	if len(r.Match) == 0 {
+		return true
+	}
+
+	for _, m := range r.Match {
+		if m == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Config is the fault-injection configuration, which maps a target to the rule for the target, for example
+// {"store":{"latency":"50ms","errorRate":0.1},"transport":{"partialFailureRate":0.2,"match":["orb.domain2.com"]}}.
+type Config map[Target]*Rule
+
+// ParseConfig parses the given JSON fault-injection configuration.
+func ParseConfig(configBytes []byte) (Config, error) {
+	cfg := make(Config)
+
+	if err := json.Unmarshal(configBytes, &cfg); err != nil {
+		return nil, fmt.Errorf("unmarshal fault-injection config: %w", err)
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+func (c Config) validate() error {
+	for target, rule := range c {
+		switch target {
+		case TargetStore, TargetCAS, TargetTransport:
+		default:
+			return fmt.Errorf("invalid fault-injection target [%s]", target)
+		}
+
+		if rule == nil {
+			return fmt.Errorf("rule for fault-injection target [%s] is nil", target)
+		}
+
+		if rule.Latency < 0 {
+			return fmt.Errorf("latency for fault-injection target [%s] must not be negative", target)
+		}
+
+		if rule.ErrorRate < 0 || rule.PartialFailureRate < 0 || rule.ErrorRate+rule.PartialFailureRate > 1 {
+			return fmt.Errorf("error rates for fault-injection target [%s] must be between 0 and 1 "+
+				"and their sum must not exceed 1", target)
+		}
+	}
+
+	return nil
+}
+
+type fault int
+
+const (
+	faultNone fault = iota
+	faultError
+	faultPartial
+)
+
+// Injector injects faults into operations according to its configuration, which may be updated at runtime.
+type Injector struct {
+	mutex  sync.RWMutex
+	config Config
+	random func() float64
+	sleep  func(d time.Duration)
+}
+
+// New returns a new fault injector with the given configuration.
+func New(cfg Config) (*Injector, error) {
+	i := &Injector{
+		random: rand.Float64, //nolint:gosec
+		sleep:  time.Sleep,
+	}
+
+	if err := i.SetConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	return i, nil
+}
+
+// Config returns the current configuration.
+func (i *Injector) Config() Config {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	cfg := make(Config, len(i.config))
+
+	for target, rule := range i.config {
+		r := *rule
+		cfg[target] = &r
+	}
+
+	return cfg
+}
+
+// SetConfig replaces the current configuration. An empty configuration disables fault injection.
+func (i *Injector) SetConfig(cfg Config) error {
+	if err := cfg.validate(); err != nil {
+		return orberrors.NewBadRequest(err)
+	}
+
+	newCfg := make(Config, len(cfg))
+
+	for target, rule := range cfg {
+		r := *rule
+		newCfg[target] = &r
+	}
+
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	i.config = newCfg
+
+	logger.Infof("Fault-injection configuration updated: %s", newCfg)
+
+	return nil
+}
+
+// String returns a readable representation of the configuration.
+func (c Config) String() string {
+	cfgBytes, err := json.Marshal(c)
+	if err != nil {
+		return err.Error()
+	}
+
+	return string(cfgBytes)
+}
+
+// inject adds the configured latency for the given target and determines whether the operation should fail.
+func (i *Injector) inject(target Target, name string) fault {
+	i.mutex.RLock()
+	rule, ok := i.config[target]
+	i.mutex.RUnlock()
+
+	if !ok || !rule.matches(name) {
+		return faultNone
+	}
+
+	if rule.Latency > 0 {
+		i.sleep(rule.Latency)
+	}
+
+	r := i.random()
+
+	switch {
+	case r < rule.ErrorRate:
+		logger.Debugf("Injecting error into %s operation [%s]", target, name)
+
+		return faultError
+	case r < rule.ErrorRate+rule.PartialFailureRate:
+		logger.Debugf("Injecting partial failure into %s operation [%s]", target, name)
+
+		return faultPartial
+	default:
+		return faultNone
+	}
+}
+
+func newError(target Target, name, op string) error {
+	return orberrors.NewTransient(fmt.Errorf("%s %s [%s]: %w", target, op, name, ErrInjected))
+}
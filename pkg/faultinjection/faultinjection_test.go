@@ -0,0 +1,154 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package faultinjection
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+func TestParseConfig(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		cfg, err := ParseConfig([]byte(`{"store":{"latency":"50ms","errorRate":0.1},` +
+			`"transport":{"partialFailureRate":0.2,"match":["orb.domain2.com"]}}`))
+		require.NoError(t, err)
+		require.Len(t, cfg, 2)
+		require.Equal(t, 50*time.Millisecond, cfg[TargetStore].Latency)
+		require.Equal(t, 0.1, cfg[TargetStore].ErrorRate)
+		require.Equal(t, 0.2, cfg[TargetTransport].PartialFailureRate)
+		require.Equal(t, []string{"orb.domain2.com"}, cfg[TargetTransport].Match)
+
+		cfgBytes, err := json.Marshal(cfg)
+		require.NoError(t, err)
+
+		cfg2, err := ParseConfig(cfgBytes)
+		require.NoError(t, err)
+		require.Equal(t, cfg, cfg2)
+	})
+
+	t.Run("Invalid JSON", func(t *testing.T) {
+		_, err := ParseConfig([]byte("{"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal fault-injection config")
+	})
+
+	t.Run("Invalid latency", func(t *testing.T) {
+		_, err := ParseConfig([]byte(`{"store":{"latency":"xxx"}}`))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid latency")
+	})
+
+	t.Run("Invalid target", func(t *testing.T) {
+		_, err := ParseConfig([]byte(`{"xxx":{"errorRate":0.1}}`))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid fault-injection target [xxx]")
+	})
+
+	t.Run("Nil rule", func(t *testing.T) {
+		_, err := ParseConfig([]byte(`{"cas":null}`))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "rule for fault-injection target [cas] is nil")
+	})
+
+	t.Run("Invalid rates", func(t *testing.T) {
+		_, err := ParseConfig([]byte(`{"cas":{"errorRate":0.6,"partialFailureRate":0.6}}`))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "error rates for fault-injection target [cas] must be between 0 and 1")
+
+		_, err = ParseConfig([]byte(`{"cas":{"errorRate":-0.1}}`))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "error rates for fault-injection target [cas] must be between 0 and 1")
+	})
+
+	t.Run("Negative latency", func(t *testing.T) {
+		_, err := New(Config{TargetCAS: {Latency: -time.Second}})
+		require.Error(t, err)
+		require.True(t, orberrors.IsBadRequest(err))
+		require.Contains(t, err.Error(), "latency for fault-injection target [cas] must not be negative")
+	})
+}
+
+func TestInjector(t *testing.T) {
+	t.Run("Set config", func(t *testing.T) {
+		i, err := New(nil)
+		require.NoError(t, err)
+		require.Empty(t, i.Config())
+		require.Equal(t, faultNone, i.inject(TargetStore, "store1"))
+
+		require.NoError(t, i.SetConfig(Config{TargetStore: {ErrorRate: 1}}))
+		require.Equal(t, faultError, i.inject(TargetStore, "store1"))
+		require.Equal(t, faultNone, i.inject(TargetCAS, "cas"))
+
+		// Changes to the returned config don't affect the injector.
+		cfg := i.Config()
+		cfg[TargetStore].ErrorRate = 0
+		require.Equal(t, faultError, i.inject(TargetStore, "store1"))
+
+		err = i.SetConfig(Config{TargetStore: {ErrorRate: 2}})
+		require.Error(t, err)
+		require.True(t, orberrors.IsBadRequest(err))
+		require.Equal(t, faultError, i.inject(TargetStore, "store1"))
+	})
+
+	t.Run("Rates", func(t *testing.T) {
+		i, err := New(Config{TargetStore: {ErrorRate: 0.2, PartialFailureRate: 0.3}})
+		require.NoError(t, err)
+
+		i.random = func() float64 { return 0.1 }
+		require.Equal(t, faultError, i.inject(TargetStore, "store1"))
+
+		i.random = func() float64 { return 0.4 }
+		require.Equal(t, faultPartial, i.inject(TargetStore, "store1"))
+
+		i.random = func() float64 { return 0.5 }
+		require.Equal(t, faultNone, i.inject(TargetStore, "store1"))
+	})
+
+	t.Run("Match", func(t *testing.T) {
+		i, err := New(Config{TargetStore: {ErrorRate: 1, Match: []string{"store1"}}})
+		require.NoError(t, err)
+
+		require.Equal(t, faultError, i.inject(TargetStore, "store1"))
+		require.Equal(t, faultNone, i.inject(TargetStore, "store2"))
+	})
+
+	t.Run("Latency", func(t *testing.T) {
+		i, err := New(Config{TargetTransport: {Latency: time.Second}})
+		require.NoError(t, err)
+
+		var slept time.Duration
+
+		i.sleep = func(d time.Duration) { slept += d }
+
+		require.Equal(t, faultNone, i.inject(TargetTransport, "orb.domain1.com"))
+		require.Equal(t, time.Second, slept)
+	})
+}
+
+func newTestInjector(t *testing.T, target Target, f fault) *Injector {
+	t.Helper()
+
+	rule := &Rule{}
+
+	switch f {
+	case faultError:
+		rule.ErrorRate = 1
+	case faultPartial:
+		rule.PartialFailureRate = 1
+	case faultNone:
+	}
+
+	i, err := New(Config{target: rule})
+	require.NoError(t, err)
+
+	return i
+}
@@ -0,0 +1,161 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/trustbloc/edge-core/pkg/log"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/faultinjection"
+)
+
+const endpoint = "/faultinjection"
+
+const (
+	badRequestResponse          = "Bad Request."
+	internalServerErrorResponse = "Internal Server Error."
+)
+
+var logger = log.New("fault-injection-rest-handler")
+
+type injector interface {
+	Config() faultinjection.Config
+	SetConfig(cfg faultinjection.Config) error
+}
+
+// Reader implements a REST handler that returns the current fault-injection configuration.
+type Reader struct {
+	injector injector
+	marshal  func(v interface{}) ([]byte, error)
+}
+
+// NewReader returns a new fault-injection configuration reader.
+func NewReader(injector injector) *Reader {
+	return &Reader{
+		injector: injector,
+		marshal:  json.Marshal,
+	}
+}
+
+// Path returns the HTTP REST endpoint for the fault-injection service.
+func (h *Reader) Path() string {
+	return endpoint
+}
+
+// Method returns the HTTP method, which is always GET.
+func (h *Reader) Method() string {
+	return http.MethodGet
+}
+
+// Handler returns the HTTP REST handle for the fault-injection service.
+func (h *Reader) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Reader) handle(w http.ResponseWriter, _ *http.Request) {
+	cfgBytes, err := h.marshal(h.injector.Config())
+	if err != nil {
+		logger.Errorf("[%s] Error marshalling fault-injection configuration: %s", endpoint, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	writeResponse(w, http.StatusOK, cfgBytes)
+}
+
+// Writer implements a REST handler that replaces the fault-injection configuration. The request is a JSON object
+// of targets to rules, for example {"store":{"latency":"50ms","errorRate":0.1}}. An empty object ({}) disables
+// fault injection.
+type Writer struct {
+	injector injector
+	readAll  func(r io.Reader) ([]byte, error)
+}
+
+// NewWriter returns a new fault-injection configuration writer.
+func NewWriter(injector injector) *Writer {
+	return &Writer{
+		injector: injector,
+		readAll:  ioutil.ReadAll,
+	}
+}
+
+// Path returns the HTTP REST endpoint for the fault-injection service.
+func (h *Writer) Path() string {
+	return endpoint
+}
+
+// Method returns the HTTP method, which is always POST.
+func (h *Writer) Method() string {
+	return http.MethodPost
+}
+
+// Handler returns the HTTP REST handle for the fault-injection service.
+func (h *Writer) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Writer) handle(w http.ResponseWriter, req *http.Request) {
+	reqBytes, err := h.readAll(req.Body)
+	if err != nil {
+		logger.Errorf("[%s] Error reading request body: %s", endpoint, err)
+
+		writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	cfg, err := faultinjection.ParseConfig(reqBytes)
+	if err != nil {
+		logger.Infof("[%s] Invalid fault-injection request: %s", endpoint, err)
+
+		writeResponse(w, http.StatusBadRequest, []byte(err.Error()))
+
+		return
+	}
+
+	if err := h.injector.SetConfig(cfg); err != nil {
+		if orberrors.IsBadRequest(err) {
+			logger.Infof("[%s] Error updating fault-injection configuration: %s", endpoint, err)
+
+			writeResponse(w, http.StatusBadRequest, []byte(err.Error()))
+
+			return
+		}
+
+		logger.Errorf("[%s] Error updating fault-injection configuration: %s", endpoint, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	logger.Infof("[%s] Updated fault-injection configuration: %s", endpoint, reqBytes)
+
+	writeResponse(w, http.StatusOK, nil)
+}
+
+func writeResponse(w http.ResponseWriter, status int, body []byte) {
+	w.WriteHeader(status)
+
+	if len(body) > 0 {
+		if _, err := w.Write(body); err != nil {
+			logger.Warnf("[%s] Unable to write response: %s", endpoint, err)
+
+			return
+		}
+
+		logger.Debugf("[%s] Wrote response: %s", endpoint, body)
+	}
+}
@@ -0,0 +1,110 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/faultinjection"
+	"github.com/trustbloc/orb/pkg/internal/testutil/httptestutil"
+)
+
+func TestReader(t *testing.T) {
+	injector, err := faultinjection.New(faultinjection.Config{
+		faultinjection.TargetStore: {Latency: 50 * time.Millisecond, ErrorRate: 0.1},
+	})
+	require.NoError(t, err)
+
+	h := NewReader(injector)
+	require.Equal(t, endpoint, h.Path())
+	require.Equal(t, http.MethodGet, h.Method())
+	require.NotNil(t, h.Handler())
+
+	t.Run("success", func(t *testing.T) {
+		code, respBytes := httptestutil.Get(t, h.handle, endpoint)
+		require.Equal(t, http.StatusOK, code)
+
+		cfg, err := faultinjection.ParseConfig(respBytes)
+		require.NoError(t, err)
+		require.Equal(t, injector.Config(), cfg)
+	})
+
+	t.Run("marshal error", func(t *testing.T) {
+		h := NewReader(injector)
+		h.marshal = func(v interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		code, _ := httptestutil.Get(t, h.handle, endpoint)
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+}
+
+func TestWriter(t *testing.T) {
+	injector, err := faultinjection.New(nil)
+	require.NoError(t, err)
+
+	h := NewWriter(injector)
+	require.Equal(t, endpoint, h.Path())
+	require.Equal(t, http.MethodPost, h.Method())
+	require.NotNil(t, h.Handler())
+
+	t.Run("success", func(t *testing.T) {
+		code, _ := httptestutil.Serve(t, h.handle, http.MethodPost, endpoint,
+			[]byte(`{"transport":{"errorRate":0.5,"match":["orb.domain2.com"]}}`), nil)
+		require.Equal(t, http.StatusOK, code)
+
+		cfg := injector.Config()
+		require.Len(t, cfg, 1)
+		require.Equal(t, 0.5, cfg[faultinjection.TargetTransport].ErrorRate)
+
+		code, _ = httptestutil.Serve(t, h.handle, http.MethodPost, endpoint, []byte(`{}`), nil)
+		require.Equal(t, http.StatusOK, code)
+		require.Empty(t, injector.Config())
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		code, _ := httptestutil.Serve(t, h.handle, http.MethodPost, endpoint, []byte(`{`), nil)
+		require.Equal(t, http.StatusBadRequest, code)
+
+		code, respBytes := httptestutil.Serve(t, h.handle, http.MethodPost, endpoint,
+			[]byte(`{"cas":{"errorRate":2}}`), nil)
+		require.Equal(t, http.StatusBadRequest, code)
+		require.Contains(t, string(respBytes), "error rates for fault-injection target [cas]")
+	})
+
+	t.Run("read error", func(t *testing.T) {
+		h := NewWriter(injector)
+		h.readAll = func(r io.Reader) ([]byte, error) { return nil, errors.New("injected read error") }
+
+		code, _ := httptestutil.Serve(t, h.handle, http.MethodPost, endpoint, []byte(`{}`), nil)
+		require.Equal(t, http.StatusBadRequest, code)
+	})
+
+	t.Run("set config error", func(t *testing.T) {
+		h := NewWriter(&mockInjector{err: errors.New("injected set config error")})
+
+		code, _ := httptestutil.Serve(t, h.handle, http.MethodPost, endpoint, []byte(`{}`), nil)
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+}
+
+type mockInjector struct {
+	err error
+}
+
+func (m *mockInjector) Config() faultinjection.Config {
+	return nil
+}
+
+func (m *mockInjector) SetConfig(faultinjection.Config) error {
+	return m.err
+}
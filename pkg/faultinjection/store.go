@@ -0,0 +1,133 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package faultinjection
+
+import (
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+// Provider is a storage provider whose stores inject faults into their operations.
+type Provider struct {
+	storage.Provider
+
+	injector *Injector
+}
+
+// NewProvider returns a storage provider that injects faults into the operations of the stores of the
+// given provider.
+func NewProvider(p storage.Provider, injector *Injector) *Provider {
+	return &Provider{
+		Provider: p,
+		injector: injector,
+	}
+}
+
+// OpenStore opens the store with the given name.
+func (p *Provider) OpenStore(name string) (storage.Store, error) {
+	s, err := p.Provider.OpenStore(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Store{Store: s, name: name, injector: p.injector}, nil
+}
+
+// Store is a store that injects faults into its operations. A partial failure of a Put or Delete stores (or
+// deletes) the data and then returns an error. A partial failure of a Batch performs the first half of the
+// operations and then returns an error. A partial failure of a read operation returns an error.
+type Store struct {
+	storage.Store
+
+	name     string
+	injector *Injector
+}
+
+// Put stores the key + value pair along with the (optional) tags.
+func (s *Store) Put(key string, value []byte, tags ...storage.Tag) error {
+	switch s.injector.inject(TargetStore, s.name) {
+	case faultError:
+		return newError(TargetStore, s.name, "put")
+	case faultPartial:
+		if err := s.Store.Put(key, value, tags...); err != nil {
+			return err
+		}
+
+		return newError(TargetStore, s.name, "put")
+	default:
+		return s.Store.Put(key, value, tags...)
+	}
+}
+
+// Get fetches the value associated with the given key.
+func (s *Store) Get(key string) ([]byte, error) {
+	if s.injector.inject(TargetStore, s.name) != faultNone {
+		return nil, newError(TargetStore, s.name, "get")
+	}
+
+	return s.Store.Get(key)
+}
+
+// GetTags fetches all tags associated with the given key.
+func (s *Store) GetTags(key string) ([]storage.Tag, error) {
+	if s.injector.inject(TargetStore, s.name) != faultNone {
+		return nil, newError(TargetStore, s.name, "get tags")
+	}
+
+	return s.Store.GetTags(key)
+}
+
+// GetBulk fetches the values associated with the given keys.
+func (s *Store) GetBulk(keys ...string) ([][]byte, error) {
+	if s.injector.inject(TargetStore, s.name) != faultNone {
+		return nil, newError(TargetStore, s.name, "get bulk")
+	}
+
+	return s.Store.GetBulk(keys...)
+}
+
+// Query returns all data that satisfies the expression.
+func (s *Store) Query(expression string, options ...storage.QueryOption) (storage.Iterator, error) {
+	if s.injector.inject(TargetStore, s.name) != faultNone {
+		return nil, newError(TargetStore, s.name, "query")
+	}
+
+	return s.Store.Query(expression, options...)
+}
+
+// Delete deletes the value (and all tags) associated with the given key.
+func (s *Store) Delete(key string) error {
+	switch s.injector.inject(TargetStore, s.name) {
+	case faultError:
+		return newError(TargetStore, s.name, "delete")
+	case faultPartial:
+		if err := s.Store.Delete(key); err != nil {
+			return err
+		}
+
+		return newError(TargetStore, s.name, "delete")
+	default:
+		return s.Store.Delete(key)
+	}
+}
+
+// Batch performs multiple Put and/or Delete operations in order.
+func (s *Store) Batch(operations []storage.Operation) error {
+	switch s.injector.inject(TargetStore, s.name) {
+	case faultError:
+		return newError(TargetStore, s.name, "batch")
+	case faultPartial:
+		if n := len(operations) / 2; n > 0 { //nolint:gomnd
+			if err := s.Store.Batch(operations[:n]); err != nil {
+				return err
+			}
+		}
+
+		return newError(TargetStore, s.name, "batch")
+	default:
+		return s.Store.Batch(operations)
+	}
+}
@@ -0,0 +1,142 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package faultinjection
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/stretchr/testify/require"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+const storeName = "store1"
+
+func TestStore(t *testing.T) {
+	t.Run("No fault", func(t *testing.T) {
+		s := openStore(t, faultNone)
+
+		require.NoError(t, s.Put("k1", []byte("v1"), storage.Tag{Name: "tag1"}))
+
+		v, err := s.Get("k1")
+		require.NoError(t, err)
+		require.Equal(t, []byte("v1"), v)
+
+		tags, err := s.GetTags("k1")
+		require.NoError(t, err)
+		require.Len(t, tags, 1)
+
+		values, err := s.GetBulk("k1")
+		require.NoError(t, err)
+		require.Len(t, values, 1)
+
+		it, err := s.Query("tag1")
+		require.NoError(t, err)
+		require.NoError(t, it.Close())
+
+		require.NoError(t, s.Batch([]storage.Operation{{Key: "k2", Value: []byte("v2")}}))
+		require.NoError(t, s.Delete("k1"))
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		s := openStore(t, faultError)
+
+		requireInjected(t, s.Put("k1", []byte("v1")))
+		requireInjected(t, s.Batch([]storage.Operation{{Key: "k1", Value: []byte("v1")}}))
+		requireInjected(t, s.Delete("k1"))
+
+		_, err := s.Get("k1")
+		requireInjected(t, err)
+
+		_, err = s.GetTags("k1")
+		requireInjected(t, err)
+
+		_, err = s.GetBulk("k1")
+		requireInjected(t, err)
+
+		_, err = s.Query("tag1")
+		requireInjected(t, err)
+
+		_, err = s.Store.Get("k1")
+		require.ErrorIs(t, err, storage.ErrDataNotFound)
+	})
+
+	t.Run("Partial failure", func(t *testing.T) {
+		s := openStore(t, faultPartial)
+
+		requireInjected(t, s.Put("k1", []byte("v1")))
+
+		_, err := s.Store.Get("k1")
+		require.NoError(t, err)
+
+		requireInjected(t, s.Delete("k1"))
+
+		_, err = s.Store.Get("k1")
+		require.ErrorIs(t, err, storage.ErrDataNotFound)
+
+		requireInjected(t, s.Batch([]storage.Operation{
+			{Key: "k2", Value: []byte("v2")},
+			{Key: "k3", Value: []byte("v3")},
+		}))
+
+		_, err = s.Store.Get("k2")
+		require.NoError(t, err)
+
+		_, err = s.Store.Get("k3")
+		require.ErrorIs(t, err, storage.ErrDataNotFound)
+	})
+
+	t.Run("Partial failure - store error", func(t *testing.T) {
+		errExpected := errors.New("injected store error")
+
+		store := &mocks.Store{}
+		store.PutReturns(errExpected)
+		store.DeleteReturns(errExpected)
+		store.BatchReturns(errExpected)
+
+		p := &mocks.Provider{}
+		p.OpenStoreReturns(store, nil)
+
+		s, err := NewProvider(p, newTestInjector(t, TargetStore, faultPartial)).OpenStore(storeName)
+		require.NoError(t, err)
+
+		require.ErrorIs(t, s.Put("k1", []byte("v1")), errExpected)
+		require.ErrorIs(t, s.Delete("k1"), errExpected)
+		require.ErrorIs(t, s.Batch([]storage.Operation{{Key: "k1"}, {Key: "k2"}}), errExpected)
+	})
+
+	t.Run("Open store error", func(t *testing.T) {
+		errExpected := errors.New("injected open store error")
+
+		p := &mocks.Provider{}
+		p.OpenStoreReturns(nil, errExpected)
+
+		_, err := NewProvider(p, newTestInjector(t, TargetStore, faultNone)).OpenStore(storeName)
+		require.ErrorIs(t, err, errExpected)
+	})
+}
+
+func openStore(t *testing.T, f fault) *Store {
+	t.Helper()
+
+	s, err := NewProvider(mem.NewProvider(), newTestInjector(t, TargetStore, f)).OpenStore(storeName)
+	require.NoError(t, err)
+
+	return s.(*Store)
+}
+
+func requireInjected(t *testing.T, err error) {
+	t.Helper()
+
+	require.Error(t, err)
+	require.ErrorIs(t, err, ErrInjected)
+	require.True(t, orberrors.IsTransient(err))
+}
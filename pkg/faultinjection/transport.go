@@ -0,0 +1,67 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package faultinjection
+
+import (
+	"net/http"
+)
+
+// RoundTripper is an HTTP round tripper that injects faults into outbound requests. The rules of the transport
+// target are matched against the destination host. A partial failure sends the request (so the remote server
+// processes it) but discards the response and returns an error, which simulates a lost response.
+type RoundTripper struct {
+	next     http.RoundTripper
+	injector *Injector
+}
+
+// NewRoundTripper returns a round tripper that injects faults into the requests sent by the given round tripper.
+func NewRoundTripper(next http.RoundTripper, injector *Injector) *RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &RoundTripper{
+		next:     next,
+		injector: injector,
+	}
+}
+
+// RoundTrip sends the given request, injecting faults according to the configuration of the injector.
+func (rt *RoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Host
+
+	switch rt.injector.inject(TargetTransport, host) {
+	case faultError:
+		closeRequestBody(req)
+
+		return nil, newError(TargetTransport, host, req.Method)
+	case faultPartial:
+		resp, err := rt.next.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+
+		if e := resp.Body.Close(); e != nil {
+			logger.Warnf("Error closing response body from %s: %s", host, e)
+		}
+
+		return nil, newError(TargetTransport, host, req.Method)
+	default:
+		return rt.next.RoundTrip(req)
+	}
+}
+
+// closeRequestBody closes the request body since a round tripper must always close the body, even on errors.
+func closeRequestBody(req *http.Request) {
+	if req.Body == nil {
+		return
+	}
+
+	if err := req.Body.Close(); err != nil {
+		logger.Warnf("Error closing request body: %s", err)
+	}
+}
@@ -0,0 +1,96 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package faultinjection
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundTripper(t *testing.T) {
+	var count int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&count, 1)
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	t.Run("No fault", func(t *testing.T) {
+		atomic.StoreInt32(&count, 0)
+
+		client := &http.Client{Transport: NewRoundTripper(nil, newTestInjector(t, TargetTransport, faultNone))}
+
+		resp, err := client.Get(server.URL) //nolint:noctx
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, int32(1), atomic.LoadInt32(&count))
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		atomic.StoreInt32(&count, 0)
+
+		client := &http.Client{
+			Transport: NewRoundTripper(http.DefaultTransport, newTestInjector(t, TargetTransport, faultError)),
+		}
+
+		resp, err := client.Post(server.URL, "application/json", //nolint:noctx
+			ioutil.NopCloser(bytes.NewReader([]byte("{}"))))
+		if resp != nil {
+			require.NoError(t, resp.Body.Close())
+		}
+
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrInjected))
+		require.Zero(t, atomic.LoadInt32(&count))
+	})
+
+	t.Run("Partial failure", func(t *testing.T) {
+		atomic.StoreInt32(&count, 0)
+
+		client := &http.Client{
+			Transport: NewRoundTripper(http.DefaultTransport, newTestInjector(t, TargetTransport, faultPartial)),
+		}
+
+		resp, err := client.Get(server.URL) //nolint:noctx
+		if resp != nil {
+			require.NoError(t, resp.Body.Close())
+		}
+
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrInjected))
+		require.Equal(t, int32(1), atomic.LoadInt32(&count))
+	})
+
+	t.Run("Partial failure - transport error", func(t *testing.T) {
+		rt := NewRoundTripper(&mockRoundTripper{err: errors.New("injected transport error")},
+			newTestInjector(t, TargetTransport, faultPartial))
+
+		req := httptest.NewRequest(http.MethodGet, server.URL, nil)
+
+		_, err := rt.RoundTrip(req) //nolint:bodyclose
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected transport error")
+	})
+}
+
+type mockRoundTripper struct {
+	err error
+}
+
+func (m *mockRoundTripper) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, m.err
+}
@@ -0,0 +1,372 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package featureflag
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/lifecycle"
+)
+
+var logger = log.New("feature-flag")
+
+const (
+	keyPrefix = "feature-flag_"
+
+	defaultRefreshInterval = 10 * time.Second
+)
+
+// MultikeyPublicKey is the name of the feature flag that publishes the public key of the service actor in
+// Multikey (publicKeyMultibase) format in addition to the PEM format.
+const MultikeyPublicKey = "multikey-public-key"
+
+// ErrFlagNotFound is returned when the requested feature flag has not been registered.
+var ErrFlagNotFound = errors.New("feature flag not found")
+
+// Flag defines a feature that may be enabled or disabled while the server is running, either for the entire
+// deployment or for individual peers.
+type Flag struct {
+	// Name is the unique name of the flag.
+	Name string
+	// Description is a human readable description of the feature.
+	Description string
+	// Default indicates whether the feature is enabled if it hasn't been set in the config store.
+	Default bool
+}
+
+// Value contains the current state of a feature flag.
+type Value struct {
+	Name        string          `json:"name"`
+	Enabled     bool            `json:"enabled"`
+	Default     bool            `json:"default"`
+	Description string          `json:"description,omitempty"`
+	Peers       map[string]bool `json:"peers,omitempty"`
+}
+
+// Update contains an update to a feature flag. If Peer is specified then the flag is enabled or disabled for the
+// given peer (service IRI or host) only, otherwise the flag is enabled or disabled for the entire deployment.
+// If Enabled is nil then the override is removed, i.e. the flag reverts to the deployment value (for a peer)
+// or to the default value (for the deployment).
+type Update struct {
+	Name    string `json:"name"`
+	Peer    string `json:"peer,omitempty"`
+	Enabled *bool  `json:"enabled"`
+}
+
+// state is the state of a flag that's persisted in the config store.
+type state struct {
+	Enabled *bool           `json:"enabled,omitempty"`
+	Peers   map[string]bool `json:"peers,omitempty"`
+}
+
+// Manager manages feature flags that are consulted by handlers and services in order to incrementally roll out
+// new behaviors. The state of the flags is stored in the config store (which is shared by all server instances
+// in the domain). Flags that are updated by another server instance are picked up on the next refresh.
+type Manager struct {
+	*lifecycle.Lifecycle
+
+	store           storage.Store
+	refreshInterval time.Duration
+	flags           map[string]*flag
+	mutex           sync.RWMutex
+	done            chan struct{}
+}
+
+type flag struct {
+	*Flag
+
+	state *state
+}
+
+// Option is an option for the feature flag manager.
+type Option func(m *Manager)
+
+// WithRefreshInterval sets the interval at which the flags are reloaded from the config store.
+func WithRefreshInterval(interval time.Duration) Option {
+	return func(m *Manager) {
+		m.refreshInterval = interval
+	}
+}
+
+// New returns a new feature flag manager. Start must be called in order for flags updated by other server
+// instances to be picked up.
+func New(store storage.Store, opts ...Option) *Manager {
+	m := &Manager{
+		store:           store,
+		refreshInterval: defaultRefreshInterval,
+		flags:           make(map[string]*flag),
+		done:            make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	m.Lifecycle = lifecycle.New("feature-flag",
+		lifecycle.WithStart(m.start),
+		lifecycle.WithStop(m.stop),
+	)
+
+	return m
+}
+
+// Register registers a feature flag. The current state of the flag is loaded from the config store.
+func (m *Manager) Register(f *Flag) error {
+	if f.Name == "" {
+		return errors.New("feature flag name is required")
+	}
+
+	s, err := m.load(f.Name)
+	if err != nil {
+		return err
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if _, exists := m.flags[f.Name]; exists {
+		return fmt.Errorf("feature flag [%s] is already registered", f.Name)
+	}
+
+	m.flags[f.Name] = &flag{Flag: f, state: s}
+
+	logger.Debugf("Registered feature flag [%s] - enabled: %t", f.Name, isEnabled(f, s))
+
+	return nil
+}
+
+// Enabled returns true if the given feature is enabled for the deployment. False is returned if the
+// flag isn't registered.
+func (m *Manager) Enabled(name string) bool {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	f, ok := m.flags[name]
+	if !ok {
+		logger.Warnf("Feature flag [%s] is not registered", name)
+
+		return false
+	}
+
+	return isEnabled(f.Flag, f.state)
+}
+
+// EnabledFor returns true if the given feature is enabled for the given peer. A peer override may be
+// specified either for the peer's service IRI or for its host. If no override exists for the peer then
+// the deployment value is returned.
+func (m *Manager) EnabledFor(name string, peer *url.URL) bool {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	f, ok := m.flags[name]
+	if !ok {
+		logger.Warnf("Feature flag [%s] is not registered", name)
+
+		return false
+	}
+
+	if peer != nil {
+		if enabled, found := f.state.Peers[peer.String()]; found {
+			return enabled
+		}
+
+		if enabled, found := f.state.Peers[peer.Host]; found {
+			return enabled
+		}
+	}
+
+	return isEnabled(f.Flag, f.state)
+}
+
+// GetAll returns the current state of all registered flags, sorted by name.
+func (m *Manager) GetAll() []*Value {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	values := make([]*Value, 0, len(m.flags))
+
+	for _, f := range m.flags {
+		values = append(values, &Value{
+			Name:        f.Name,
+			Enabled:     isEnabled(f.Flag, f.state),
+			Default:     f.Default,
+			Description: f.Description,
+			Peers:       copyPeers(f.state.Peers),
+		})
+	}
+
+	sort.Slice(values, func(i, j int) bool {
+		return values[i].Name < values[j].Name
+	})
+
+	return values
+}
+
+// Update applies the given update to a feature flag and stores the new state of the flag.
+func (m *Manager) Update(u *Update) error {
+	if _, err := m.get(u.Name); err != nil {
+		return orberrors.NewBadRequest(fmt.Errorf("update [%s]: %w", u.Name, err))
+	}
+
+	// Load the state from the store (rather than using the cached state) so that a peer override that was
+	// added by another server instance isn't lost.
+	s, err := m.load(u.Name)
+	if err != nil {
+		return err
+	}
+
+	if u.Peer == "" {
+		s.Enabled = u.Enabled
+	} else {
+		if s.Peers == nil {
+			s.Peers = make(map[string]bool)
+		}
+
+		if u.Enabled == nil {
+			delete(s.Peers, u.Peer)
+		} else {
+			s.Peers[u.Peer] = *u.Enabled
+		}
+	}
+
+	stateBytes, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("marshal feature flag [%s]: %w", u.Name, err)
+	}
+
+	err = m.store.Put(keyPrefix+u.Name, stateBytes)
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("store feature flag [%s]: %w", u.Name, err))
+	}
+
+	m.set(u.Name, s)
+
+	return nil
+}
+
+func (m *Manager) get(name string) (*flag, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	f, ok := m.flags[name]
+	if !ok {
+		return nil, ErrFlagNotFound
+	}
+
+	return f, nil
+}
+
+func (m *Manager) set(name string, s *state) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	f, ok := m.flags[name]
+	if !ok {
+		return
+	}
+
+	if isEnabled(f.Flag, f.state) != isEnabled(f.Flag, s) {
+		logger.Infof("Feature flag [%s] changed - enabled: %t", name, isEnabled(f.Flag, s))
+	}
+
+	f.state = s
+}
+
+func (m *Manager) load(name string) (*state, error) {
+	stateBytes, err := m.store.Get(keyPrefix + name)
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return &state{}, nil
+		}
+
+		return nil, orberrors.NewTransient(fmt.Errorf("load feature flag [%s]: %w", name, err))
+	}
+
+	s := &state{}
+
+	err = json.Unmarshal(stateBytes, s)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal feature flag [%s]: %w", name, err)
+	}
+
+	return s, nil
+}
+
+func (m *Manager) refresh() {
+	m.mutex.RLock()
+
+	names := make([]string, 0, len(m.flags))
+	for name := range m.flags {
+		names = append(names, name)
+	}
+
+	m.mutex.RUnlock()
+
+	for _, name := range names {
+		s, err := m.load(name)
+		if err != nil {
+			logger.Warnf("Error refreshing feature flag [%s]: %s", name, err)
+
+			continue
+		}
+
+		m.set(name, s)
+	}
+}
+
+func (m *Manager) start() {
+	go func() {
+		logger.Infof("Started feature flag manager.")
+
+		for {
+			select {
+			case <-time.After(m.refreshInterval):
+				m.refresh()
+			case <-m.done:
+				logger.Debugf("Stopped feature flag manager.")
+
+				return
+			}
+		}
+	}()
+}
+
+func (m *Manager) stop() {
+	close(m.done)
+}
+
+func isEnabled(f *Flag, s *state) bool {
+	if s.Enabled != nil {
+		return *s.Enabled
+	}
+
+	return f.Default
+}
+
+func copyPeers(peers map[string]bool) map[string]bool {
+	if len(peers) == 0 {
+		return nil
+	}
+
+	c := make(map[string]bool, len(peers))
+
+	for peer, enabled := range peers {
+		c[peer] = enabled
+	}
+
+	return c
+}
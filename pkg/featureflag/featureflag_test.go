@@ -0,0 +1,193 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package featureflag
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/stretchr/testify/require"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+	storemocks "github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+const (
+	configStoreName = "orb-config"
+
+	flag1 = "feature-1"
+	flag2 = "feature-2"
+)
+
+var (
+	peer1 = testutil.MustParseURL("https://orb.domain1.com/services/orb")
+	peer2 = testutil.MustParseURL("https://orb.domain2.com/services/orb")
+)
+
+func TestManager(t *testing.T) {
+	configStore, err := mem.NewProvider().OpenStore(configStoreName)
+	require.NoError(t, err)
+
+	m := New(configStore)
+	require.NotNil(t, m)
+
+	require.NoError(t, m.Register(&Flag{Name: flag1, Description: "Feature 1"}))
+	require.NoError(t, m.Register(&Flag{Name: flag2, Default: true}))
+
+	t.Run("register errors", func(t *testing.T) {
+		require.EqualError(t, m.Register(&Flag{}), "feature flag name is required")
+		require.EqualError(t, m.Register(&Flag{Name: flag1}), "feature flag [feature-1] is already registered")
+	})
+
+	t.Run("defaults", func(t *testing.T) {
+		require.False(t, m.Enabled(flag1))
+		require.True(t, m.Enabled(flag2))
+		require.False(t, m.EnabledFor(flag1, peer1))
+		require.True(t, m.EnabledFor(flag2, peer1))
+		require.True(t, m.EnabledFor(flag2, nil))
+
+		require.False(t, m.Enabled("unknown"))
+		require.False(t, m.EnabledFor("unknown", peer1))
+
+		values := m.GetAll()
+		require.Len(t, values, 2)
+		require.Equal(t, flag1, values[0].Name)
+		require.Equal(t, "Feature 1", values[0].Description)
+		require.False(t, values[0].Enabled)
+		require.Equal(t, flag2, values[1].Name)
+		require.True(t, values[1].Enabled)
+		require.True(t, values[1].Default)
+	})
+
+	t.Run("deployment update", func(t *testing.T) {
+		require.NoError(t, m.Update(&Update{Name: flag1, Enabled: boolPtr(true)}))
+		require.True(t, m.Enabled(flag1))
+		require.True(t, m.EnabledFor(flag1, peer1))
+
+		// Remove the override so that the flag reverts to the default.
+		require.NoError(t, m.Update(&Update{Name: flag1}))
+		require.False(t, m.Enabled(flag1))
+	})
+
+	t.Run("peer update", func(t *testing.T) {
+		require.NoError(t, m.Update(&Update{Name: flag1, Peer: peer1.String(), Enabled: boolPtr(true)}))
+		require.NoError(t, m.Update(&Update{Name: flag2, Peer: peer2.Host, Enabled: boolPtr(false)}))
+
+		require.False(t, m.Enabled(flag1))
+		require.True(t, m.EnabledFor(flag1, peer1))
+		require.False(t, m.EnabledFor(flag1, peer2))
+
+		require.True(t, m.Enabled(flag2))
+		require.True(t, m.EnabledFor(flag2, peer1))
+		require.False(t, m.EnabledFor(flag2, peer2))
+
+		values := m.GetAll()
+		require.Len(t, values, 2)
+		require.Equal(t, map[string]bool{peer1.String(): true}, values[0].Peers)
+		require.Equal(t, map[string]bool{peer2.Host: false}, values[1].Peers)
+
+		// Remove the peer override.
+		require.NoError(t, m.Update(&Update{Name: flag1, Peer: peer1.String()}))
+		require.False(t, m.EnabledFor(flag1, peer1))
+	})
+
+	t.Run("update errors", func(t *testing.T) {
+		err := m.Update(&Update{Name: "unknown", Enabled: boolPtr(true)})
+		require.True(t, errors.Is(err, ErrFlagNotFound))
+		require.True(t, orberrors.IsBadRequest(err))
+	})
+
+	t.Run("state loaded from store", func(t *testing.T) {
+		m2 := New(configStore)
+
+		require.NoError(t, m2.Register(&Flag{Name: flag2, Default: true}))
+
+		require.True(t, m2.Enabled(flag2))
+		require.False(t, m2.EnabledFor(flag2, peer2))
+	})
+}
+
+func TestManager_Refresh(t *testing.T) {
+	configStore, err := mem.NewProvider().OpenStore(configStoreName)
+	require.NoError(t, err)
+
+	m1 := New(configStore, WithRefreshInterval(10*time.Millisecond))
+	m2 := New(configStore, WithRefreshInterval(10*time.Millisecond))
+
+	require.NoError(t, m1.Register(&Flag{Name: flag1}))
+	require.NoError(t, m2.Register(&Flag{Name: flag1}))
+
+	m2.Start()
+	defer m2.Stop()
+
+	require.NoError(t, m1.Update(&Update{Name: flag1, Enabled: boolPtr(true)}))
+
+	require.Eventually(t, func() bool { return m2.Enabled(flag1) }, time.Second, 10*time.Millisecond)
+}
+
+func TestManager_StoreError(t *testing.T) {
+	errExpected := errors.New("injected store error")
+
+	t.Run("get error", func(t *testing.T) {
+		s := &storemocks.Store{}
+		s.GetReturns(nil, errExpected)
+
+		m := New(s)
+
+		err := m.Register(&Flag{Name: flag1})
+		require.True(t, errors.Is(err, errExpected))
+		require.True(t, orberrors.IsTransient(err))
+	})
+
+	t.Run("unmarshal error", func(t *testing.T) {
+		s := &storemocks.Store{}
+		s.GetReturns([]byte("{"), nil)
+
+		m := New(s)
+
+		err := m.Register(&Flag{Name: flag1})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal feature flag")
+	})
+
+	t.Run("put error", func(t *testing.T) {
+		s := &storemocks.Store{}
+		s.GetReturns([]byte(`{"enabled":true}`), nil)
+		s.PutReturns(errExpected)
+
+		m := New(s)
+
+		require.NoError(t, m.Register(&Flag{Name: flag1}))
+		require.True(t, m.Enabled(flag1))
+
+		err := m.Update(&Update{Name: flag1, Enabled: boolPtr(false)})
+		require.True(t, errors.Is(err, errExpected))
+		require.True(t, orberrors.IsTransient(err))
+		require.True(t, m.Enabled(flag1))
+	})
+
+	t.Run("load error on update", func(t *testing.T) {
+		s := &storemocks.Store{}
+		s.GetReturnsOnCall(0, nil, storage.ErrDataNotFound)
+		s.GetReturnsOnCall(1, nil, errExpected)
+
+		m := New(s)
+
+		require.NoError(t, m.Register(&Flag{Name: flag1}))
+
+		err := m.Update(&Update{Name: flag1, Enabled: boolPtr(false)})
+		require.True(t, errors.Is(err, errExpected))
+	})
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
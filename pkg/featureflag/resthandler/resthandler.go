@@ -0,0 +1,166 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/trustbloc/edge-core/pkg/log"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+
+	"github.com/trustbloc/orb/pkg/featureflag"
+)
+
+// Path is the path of the feature flag endpoint.
+const Path = "/featureflags"
+
+const (
+	badRequestResponse          = "Bad Request."
+	notFoundResponse            = "Not Found."
+	internalServerErrorResponse = "Internal Server Error."
+)
+
+var logger = log.New("feature-flag-rest-handler")
+
+type flagManager interface {
+	GetAll() []*featureflag.Value
+	Update(u *featureflag.Update) error
+}
+
+// Reader implements a REST handler that returns the current state of the feature flags.
+type Reader struct {
+	mgr     flagManager
+	marshal func(v interface{}) ([]byte, error)
+}
+
+// NewReader returns a new feature flag reader.
+func NewReader(mgr flagManager) *Reader {
+	return &Reader{
+		mgr:     mgr,
+		marshal: json.Marshal,
+	}
+}
+
+// Path returns the HTTP REST endpoint for the feature flag service.
+func (h *Reader) Path() string {
+	return Path
+}
+
+// Method returns the HTTP method, which is always GET.
+func (h *Reader) Method() string {
+	return http.MethodGet
+}
+
+// Handler returns the HTTP REST handle for the feature flag service.
+func (h *Reader) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Reader) handle(w http.ResponseWriter, _ *http.Request) {
+	valuesBytes, err := h.marshal(h.mgr.GetAll())
+	if err != nil {
+		logger.Errorf("[%s] Error marshalling feature flags: %s", Path, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	writeResponse(w, http.StatusOK, valuesBytes)
+}
+
+// Writer implements a REST handler that enables or disables a feature, either for the entire deployment or
+// for a given peer. For example, {"name":"multikey-public-key","enabled":false} disables the feature for
+// the deployment, {"name":"multikey-public-key","peer":"orb.domain2.com","enabled":true} enables the feature
+// for the given peer and {"name":"multikey-public-key","peer":"orb.domain2.com"} removes the peer override.
+type Writer struct {
+	mgr     flagManager
+	readAll func(r io.Reader) ([]byte, error)
+}
+
+// NewWriter returns a new feature flag writer.
+func NewWriter(mgr flagManager) *Writer {
+	return &Writer{
+		mgr:     mgr,
+		readAll: ioutil.ReadAll,
+	}
+}
+
+// Path returns the HTTP REST endpoint for the feature flag service.
+func (h *Writer) Path() string {
+	return Path
+}
+
+// Method returns the HTTP method, which is always POST.
+func (h *Writer) Method() string {
+	return http.MethodPost
+}
+
+// Handler returns the HTTP REST handle for the feature flag service.
+func (h *Writer) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Writer) handle(w http.ResponseWriter, req *http.Request) {
+	reqBytes, err := h.readAll(req.Body)
+	if err != nil {
+		logger.Errorf("[%s] Error reading request body: %s", Path, err)
+
+		writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	update := &featureflag.Update{}
+
+	err = json.Unmarshal(reqBytes, update)
+	if err != nil || update.Name == "" {
+		logger.Infof("[%s] Invalid feature flag request: %s", Path, reqBytes)
+
+		writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	err = h.mgr.Update(update)
+	if err != nil {
+		switch {
+		case errors.Is(err, featureflag.ErrFlagNotFound):
+			logger.Infof("[%s] Error updating feature flag: %s", Path, err)
+
+			writeResponse(w, http.StatusNotFound, []byte(notFoundResponse))
+		default:
+			logger.Errorf("[%s] Error updating feature flag: %s", Path, err)
+
+			writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+		}
+
+		return
+	}
+
+	logger.Infof("[%s] Updated feature flag: %s", Path, reqBytes)
+
+	writeResponse(w, http.StatusOK, nil)
+}
+
+func writeResponse(w http.ResponseWriter, status int, body []byte) {
+	w.WriteHeader(status)
+
+	if len(body) > 0 {
+		if _, err := w.Write(body); err != nil {
+			logger.Warnf("[%s] Unable to write response: %s", Path, err)
+
+			return
+		}
+
+		logger.Debugf("[%s] Wrote response: %s", Path, body)
+	}
+}
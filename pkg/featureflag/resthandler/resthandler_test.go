@@ -0,0 +1,120 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/featureflag"
+	"github.com/trustbloc/orb/pkg/internal/testutil/httptestutil"
+	storemocks "github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+const flag1 = "feature-1"
+
+func TestReader(t *testing.T) {
+	mgr := newManager(t)
+
+	h := NewReader(mgr)
+	require.Equal(t, Path, h.Path())
+	require.Equal(t, http.MethodGet, h.Method())
+	require.NotNil(t, h.Handler())
+
+	t.Run("success", func(t *testing.T) {
+		status, respBytes := httptestutil.Get(t, h.Handler(), Path)
+		require.Equal(t, http.StatusOK, status)
+
+		var values []*featureflag.Value
+		require.NoError(t, json.Unmarshal(respBytes, &values))
+		require.Len(t, values, 1)
+		require.Equal(t, flag1, values[0].Name)
+		require.False(t, values[0].Enabled)
+	})
+
+	t.Run("marshal error", func(t *testing.T) {
+		h := NewReader(mgr)
+		h.marshal = func(v interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		status, _ := httptestutil.Get(t, h.Handler(), Path)
+		require.Equal(t, http.StatusInternalServerError, status)
+	})
+}
+
+func TestWriter(t *testing.T) {
+	h := NewWriter(newManager(t))
+	require.Equal(t, Path, h.Path())
+	require.Equal(t, http.MethodPost, h.Method())
+	require.NotNil(t, h.Handler())
+
+	t.Run("success", func(t *testing.T) {
+		mgr := newManager(t)
+
+		status, _ := httptestutil.Post(t, NewWriter(mgr).Handler(), Path, []byte(`{"name":"feature-1","enabled":true}`))
+		require.Equal(t, http.StatusOK, status)
+		require.True(t, mgr.Enabled(flag1))
+
+		status, _ = httptestutil.Post(t, NewWriter(mgr).Handler(), Path,
+			[]byte(`{"name":"feature-1","peer":"orb.domain2.com","enabled":false}`))
+		require.Equal(t, http.StatusOK, status)
+		require.True(t, mgr.Enabled(flag1))
+		require.False(t, mgr.EnabledFor(flag1, &url.URL{Scheme: "https", Host: "orb.domain2.com"}))
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		status, _ := httptestutil.Post(t, h.Handler(), Path, []byte(`{`))
+		require.Equal(t, http.StatusBadRequest, status)
+
+		status, _ = httptestutil.Post(t, h.Handler(), Path, []byte(`{"enabled":true}`))
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("unknown flag", func(t *testing.T) {
+		status, _ := httptestutil.Post(t, h.Handler(), Path, []byte(`{"name":"unknown","enabled":true}`))
+		require.Equal(t, http.StatusNotFound, status)
+	})
+
+	t.Run("read error", func(t *testing.T) {
+		h := NewWriter(newManager(t))
+		h.readAll = func(r io.Reader) ([]byte, error) { return nil, errors.New("injected read error") }
+
+		status, _ := httptestutil.Post(t, h.Handler(), Path, []byte(`{}`))
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		s := &storemocks.Store{}
+		s.GetReturns([]byte(`{}`), nil)
+		s.PutReturns(errors.New("injected put error"))
+
+		mgr := featureflag.New(s)
+		require.NoError(t, mgr.Register(&featureflag.Flag{Name: flag1}))
+
+		status, _ := httptestutil.Post(t, NewWriter(mgr).Handler(), Path, []byte(`{"name":"feature-1","enabled":true}`))
+		require.Equal(t, http.StatusInternalServerError, status)
+	})
+}
+
+func newManager(t *testing.T) *featureflag.Manager {
+	t.Helper()
+
+	configStore, err := mem.NewProvider().OpenStore("orb-config")
+	require.NoError(t, err)
+
+	mgr := featureflag.New(configStore)
+
+	require.NoError(t, mgr.Register(&featureflag.Flag{Name: flag1}))
+
+	return mgr
+}
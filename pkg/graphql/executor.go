@@ -0,0 +1,467 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+)
+
+const typeNameField = "__typename"
+
+// ArgType is the type of a field argument.
+type ArgType int
+
+const (
+	// String is a string argument. Enum literals are also accepted and are passed to the resolver as strings.
+	String ArgType = iota
+	// Int is an integer argument.
+	Int
+	// Boolean is a boolean argument.
+	Boolean
+)
+
+func (t ArgType) String() string {
+	switch t {
+	case String:
+		return "String"
+	case Int:
+		return "Int"
+	case Boolean:
+		return "Boolean"
+	default:
+		return "Unknown"
+	}
+}
+
+// Arg defines an argument of a field.
+type Arg struct {
+	Type     ArgType
+	Required bool
+	// Default is the value that's passed to the resolver if the argument isn't provided.
+	Default interface{}
+}
+
+// ResolveParams contains the parameters that are passed to a field resolver.
+type ResolveParams struct {
+	Context context.Context
+	// Source is the value of the parent object (nil for fields of the query type).
+	Source interface{}
+	// Args contains the coerced argument values of the field.
+	Args map[string]interface{}
+}
+
+// ResolveFunc resolves the value of a field.
+type ResolveFunc func(p *ResolveParams) (interface{}, error)
+
+// Field defines a field of an object type.
+type Field struct {
+	// Type is the object type of the field. If nil then the value returned by the resolver is a scalar and
+	// is marshalled to JSON as is.
+	Type *Object
+	// List indicates that the resolver returns a slice of values of the given type.
+	List    bool
+	Args    map[string]*Arg
+	Resolve ResolveFunc
+}
+
+// Object defines an object type.
+type Object struct {
+	Name   string
+	Fields map[string]*Field
+}
+
+// Schema is a read-only GraphQL schema, i.e. a schema that only has a query type.
+type Schema struct {
+	query *Object
+}
+
+// NewSchema returns a new schema with the given query type.
+func NewSchema(query *Object) *Schema {
+	return &Schema{query: query}
+}
+
+// Request is a GraphQL request.
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// Error is an error that's returned in a GraphQL response.
+type Error struct {
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path,omitempty"`
+}
+
+// Response is a GraphQL response. Data is not set if the request failed validation. If an error occurs while
+// resolving a field then the field is set to null and the error is added to Errors.
+type Response struct {
+	Data   interface{} `json:"data,omitempty"`
+	Errors []*Error    `json:"errors,omitempty"`
+}
+
+// Execute parses, validates and executes the given request.
+func (s *Schema) Execute(ctx context.Context, req *Request) *Response {
+	doc, err := parse(req.Query)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	op, err := getOperation(doc, req.OperationName)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	if op.opType != operationQuery {
+		return errorResponse(fmt.Errorf("%s operations are not supported", op.opType))
+	}
+
+	vars, err := coerceVariables(op, req.Variables)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	err = validateSelectionSet(s.query, op.selectionSet, vars)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	e := &execution{ctx: ctx, vars: vars}
+
+	data := e.executeSelectionSet(s.query, nil, op.selectionSet, nil)
+
+	return &Response{Data: data, Errors: e.errors}
+}
+
+func errorResponse(err error) *Response {
+	return &Response{Errors: []*Error{{Message: err.Error()}}}
+}
+
+func getOperation(doc *document, name string) (*operation, error) {
+	if name == "" {
+		if len(doc.operations) > 1 {
+			return nil, fmt.Errorf("operation name is required since the document contains multiple operations")
+		}
+
+		return doc.operations[0], nil
+	}
+
+	for _, op := range doc.operations {
+		if op.name == name {
+			return op, nil
+		}
+	}
+
+	return nil, fmt.Errorf("operation [%s] not found", name)
+}
+
+func coerceVariables(op *operation, values map[string]interface{}) (map[string]interface{}, error) {
+	vars := make(map[string]interface{})
+
+	for _, vd := range op.varDefs {
+		value, ok := values[vd.name]
+		if !ok {
+			value = vd.defaultValue
+		}
+
+		if value == nil && vd.required {
+			return nil, fmt.Errorf("variable $%s of type %s! is required", vd.name, vd.typ)
+		}
+
+		// A nil value is stored for variables that are defined but not provided.
+		vars[vd.name] = value
+	}
+
+	return vars, nil
+}
+
+// validateSelectionSet ensures that all of the selected fields and arguments are defined in the schema and that
+// selections are provided for object fields (and only for object fields).
+func validateSelectionSet(obj *Object, selectionSet []*field, vars map[string]interface{}) error {
+	for _, f := range selectionSet {
+		if f.name == typeNameField {
+			if len(f.args) > 0 || len(f.selectionSet) > 0 {
+				return fmt.Errorf("field [%s] does not accept arguments or a selection", typeNameField)
+			}
+
+			continue
+		}
+
+		fieldDef, ok := obj.Fields[f.name]
+		if !ok {
+			return fmt.Errorf("cannot query field [%s] on type [%s]", f.name, obj.Name)
+		}
+
+		if _, err := coerceArgs(fieldDef, f, vars); err != nil {
+			return fmt.Errorf("field [%s] on type [%s]: %w", f.name, obj.Name, err)
+		}
+
+		if fieldDef.Type == nil {
+			if len(f.selectionSet) > 0 {
+				return fmt.Errorf("field [%s] on type [%s] is a scalar and must not have a selection",
+					f.name, obj.Name)
+			}
+
+			continue
+		}
+
+		if len(f.selectionSet) == 0 {
+			return fmt.Errorf("field [%s] of type [%s] must have a selection of subfields",
+				f.name, fieldDef.Type.Name)
+		}
+
+		if err := validateSelectionSet(fieldDef.Type, f.selectionSet, vars); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func coerceArgs(fieldDef *Field, f *field, vars map[string]interface{}) (map[string]interface{}, error) {
+	args := make(map[string]interface{})
+
+	for _, a := range f.args {
+		argDef, ok := fieldDef.Args[a.name]
+		if !ok {
+			return nil, fmt.Errorf("unknown argument [%s]", a.name)
+		}
+
+		value := a.value
+
+		if v, isVar := value.(variable); isVar {
+			value, ok = vars[string(v)]
+			if !ok {
+				return nil, fmt.Errorf("variable $%s is not defined", v)
+			}
+		}
+
+		if value == nil {
+			continue
+		}
+
+		coerced, err := coerceArg(argDef.Type, value)
+		if err != nil {
+			return nil, fmt.Errorf("argument [%s]: %w", a.name, err)
+		}
+
+		args[a.name] = coerced
+	}
+
+	for name, argDef := range fieldDef.Args {
+		if _, ok := args[name]; ok {
+			continue
+		}
+
+		if argDef.Default != nil {
+			args[name] = argDef.Default
+
+			continue
+		}
+
+		if argDef.Required {
+			return nil, fmt.Errorf("argument [%s] of type %s is required", name, argDef.Type)
+		}
+	}
+
+	return args, nil
+}
+
+func coerceArg(argType ArgType, value interface{}) (interface{}, error) {
+	switch argType {
+	case String:
+		switch v := value.(type) {
+		case string:
+			return v, nil
+		case enumValue:
+			return string(v), nil
+		}
+
+	case Int:
+		switch v := value.(type) {
+		case int:
+			return v, nil
+		case float64:
+			// Numbers in JSON variables are unmarshalled as float64.
+			if v == math.Trunc(v) && v >= math.MinInt32 && v <= math.MaxInt32 {
+				return int(v), nil
+			}
+		}
+
+	case Boolean:
+		if v, ok := value.(bool); ok {
+			return v, nil
+		}
+	}
+
+	return nil, fmt.Errorf("expecting a value of type %s but got %v", argType, value)
+}
+
+type execution struct {
+	ctx    context.Context
+	vars   map[string]interface{}
+	errors []*Error
+}
+
+func (e *execution) executeSelectionSet(obj *Object, source interface{}, selectionSet []*field,
+	path []interface{}) *orderedMap {
+	result := newOrderedMap()
+
+	for _, f := range selectionSet {
+		key := f.responseKey()
+
+		if f.name == typeNameField {
+			result.set(key, obj.Name)
+
+			continue
+		}
+
+		// The selection set has already been validated so the field is defined.
+		fieldDef := obj.Fields[f.name]
+
+		result.set(key, e.executeField(fieldDef, f, source, appendPath(path, key)))
+	}
+
+	return result
+}
+
+func (e *execution) executeField(fieldDef *Field, f *field, source interface{}, path []interface{}) interface{} {
+	args, err := coerceArgs(fieldDef, f, e.vars)
+	if err != nil {
+		e.addError(err, path)
+
+		return nil
+	}
+
+	value, err := fieldDef.Resolve(&ResolveParams{Context: e.ctx, Source: source, Args: args})
+	if err != nil {
+		e.addError(err, path)
+
+		return nil
+	}
+
+	if isNil(value) {
+		return nil
+	}
+
+	if fieldDef.Type == nil {
+		return value
+	}
+
+	if !fieldDef.List {
+		return e.executeSelectionSet(fieldDef.Type, value, f.selectionSet, path)
+	}
+
+	rv := reflect.ValueOf(value)
+
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		e.addError(fmt.Errorf("expecting a list value for field [%s] but got %T", f.name, value), path)
+
+		return nil
+	}
+
+	items := make([]interface{}, rv.Len())
+
+	for i := 0; i < rv.Len(); i++ {
+		item := rv.Index(i).Interface()
+
+		if isNil(item) {
+			continue
+		}
+
+		items[i] = e.executeSelectionSet(fieldDef.Type, item, f.selectionSet, appendPath(path, i))
+	}
+
+	return items
+}
+
+func (e *execution) addError(err error, path []interface{}) {
+	e.errors = append(e.errors, &Error{Message: err.Error(), Path: path})
+}
+
+func appendPath(path []interface{}, elem interface{}) []interface{} {
+	p := make([]interface{}, len(path), len(path)+1)
+	copy(p, path)
+
+	return append(p, elem)
+}
+
+func isNil(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+
+	rv := reflect.ValueOf(value)
+
+	switch rv.Kind() { //nolint:exhaustive
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface, reflect.Func, reflect.Chan:
+		return rv.IsNil()
+	default:
+		return false
+	}
+}
+
+// orderedMap is marshalled to a JSON object with the fields in the order in which they were added, so that
+// the fields in the response are in the same order as the fields in the query.
+type orderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func newOrderedMap() *orderedMap {
+	return &orderedMap{values: make(map[string]interface{})}
+}
+
+func (m *orderedMap) set(key string, value interface{}) {
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+
+	m.values[key] = value
+}
+
+func (m *orderedMap) get(key string) (interface{}, bool) {
+	v, ok := m.values[key]
+
+	return v, ok
+}
+
+// MarshalJSON marshals the map to a JSON object with the fields in insertion order.
+func (m *orderedMap) MarshalJSON() ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	buf.WriteString("{")
+
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+
+		keyBytes, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+
+		valueBytes, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, fmt.Errorf("marshal field [%s]: %w", key, err)
+		}
+
+		buf.Write(keyBytes)
+		buf.WriteString(":")
+		buf.Write(valueBytes)
+	}
+
+	buf.WriteString("}")
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,312 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testItem struct {
+	name  string
+	parts []*testItem
+}
+
+func TestSchema_Execute(t *testing.T) {
+	s := newTestSchema()
+
+	t.Run("Success", func(t *testing.T) {
+		resp := s.Execute(context.Background(), &Request{
+			Query: `{
+				items(prefix: "item", count: 2) {
+					__typename
+					name
+					parts { name }
+				}
+				count
+			}`,
+		})
+		require.Empty(t, resp.Errors)
+
+		requireJSON(t, `{
+			"data": {
+				"items": [
+					{"__typename": "Item", "name": "item0", "parts": [{"name": "item0-part"}]},
+					{"__typename": "Item", "name": "item1", "parts": [{"name": "item1-part"}]}
+				],
+				"count": 10
+			}
+		}`, resp)
+	})
+
+	t.Run("Field order is preserved", func(t *testing.T) {
+		resp := s.Execute(context.Background(), &Request{
+			Query: `{ count z: count a: count item(name: "x") { name n: name } }`,
+		})
+		require.Empty(t, resp.Errors)
+
+		respBytes, err := json.Marshal(resp)
+		require.NoError(t, err)
+		require.Equal(t, `{"data":{"count":10,"z":10,"a":10,"item":{"name":"x","n":"x"}}}`, string(respBytes))
+	})
+
+	t.Run("Variables", func(t *testing.T) {
+		resp := s.Execute(context.Background(), &Request{
+			Query: `query Items($prefix: String = "default", $count: Int!, $enabled: Boolean) {
+				items(prefix: $prefix, count: $count, enabled: $enabled) { name }
+			}`,
+			// Numbers in JSON variables are unmarshalled as float64.
+			Variables: map[string]interface{}{"count": float64(1), "enabled": true},
+		})
+		require.Empty(t, resp.Errors)
+
+		requireJSON(t, `{"data": {"items": [{"name": "default0"}]}}`, resp)
+	})
+
+	t.Run("Enum value", func(t *testing.T) {
+		resp := s.Execute(context.Background(), &Request{Query: `{ item(name: ENUM) { name } }`})
+		require.Empty(t, resp.Errors)
+
+		requireJSON(t, `{"data": {"item": {"name": "ENUM"}}}`, resp)
+	})
+
+	t.Run("Operation name", func(t *testing.T) {
+		query := `query A { count } query B { item(name: "b") { name } }`
+
+		resp := s.Execute(context.Background(), &Request{Query: query, OperationName: "B"})
+		require.Empty(t, resp.Errors)
+
+		requireJSON(t, `{"data": {"item": {"name": "b"}}}`, resp)
+
+		resp = s.Execute(context.Background(), &Request{Query: query})
+		require.Nil(t, resp.Data)
+		requireError(t, resp, "operation name is required since the document contains multiple operations")
+
+		resp = s.Execute(context.Background(), &Request{Query: query, OperationName: "C"})
+		require.Nil(t, resp.Data)
+		requireError(t, resp, "operation [C] not found")
+	})
+
+	t.Run("Null values", func(t *testing.T) {
+		resp := s.Execute(context.Background(), &Request{
+			Query: `{ missing: item(name: "") { name } items(count: 3, withNil: true) { name } }`,
+		})
+		require.Empty(t, resp.Errors)
+
+		requireJSON(t, `{"data": {"missing": null, "items": [{"name": "0"}, null, {"name": "2"}]}}`, resp)
+	})
+
+	t.Run("Field error", func(t *testing.T) {
+		resp := s.Execute(context.Background(), &Request{
+			Query: `{ count items(count: 2) { name error } }`,
+		})
+		require.Len(t, resp.Errors, 2)
+		require.Equal(t, "injected error", resp.Errors[0].Message)
+		require.Equal(t, []interface{}{"items", 0, "error"}, resp.Errors[0].Path)
+		require.Equal(t, []interface{}{"items", 1, "error"}, resp.Errors[1].Path)
+
+		requireJSON(t, `{
+			"data": {"count": 10, "items": [{"name": "0", "error": null}, {"name": "1", "error": null}]},
+			"errors": [
+				{"message": "injected error", "path": ["items", 0, "error"]},
+				{"message": "injected error", "path": ["items", 1, "error"]}
+			]
+		}`, resp)
+	})
+
+	t.Run("Not a list", func(t *testing.T) {
+		resp := s.Execute(context.Background(), &Request{Query: `{ notAList { name } }`})
+		require.Len(t, resp.Errors, 1)
+		require.Equal(t, "expecting a list value for field [notAList] but got string", resp.Errors[0].Message)
+
+		requireJSON(t, `{"data": {"notAList": null}, "errors": [{"message": `+
+			`"expecting a list value for field [notAList] but got string", "path": ["notAList"]}]}`, resp)
+	})
+
+	t.Run("Request error", func(t *testing.T) {
+		for _, test := range []struct {
+			query string
+			vars  map[string]interface{}
+			err   string
+		}{
+			{query: "{", err: "syntax error: expected name but found end of query at position 1"},
+			{query: "mutation { count }", err: "mutation operations are not supported"},
+			{query: "subscription { count }", err: "subscription operations are not supported"},
+			{query: "query ($c: Int!) { count }", err: "variable $c of type Int! is required"},
+			{query: "{ unknown }", err: "cannot query field [unknown] on type [Query]"},
+			{query: "{ item(name: \"x\") { unknown } }", err: "cannot query field [unknown] on type [Item]"},
+			{query: "{ count { name } }", err: "field [count] on type [Query] is a scalar and must not have a " +
+				"selection"},
+			{query: "{ items }", err: "field [items] of type [Item] must have a selection of subfields"},
+			{query: "{ __typename(x: 1) }", err: "field [__typename] does not accept arguments or a selection"},
+			{query: "{ item { name } }", err: "field [item] on type [Query]: argument [name] of type String " +
+				"is required"},
+			{query: "{ count(x: 1) }", err: "field [count] on type [Query]: unknown argument [x]"},
+			{query: "{ items(count: $c) { name } }", err: "field [items] on type [Query]: variable $c is " +
+				"not defined"},
+			{query: "{ items(count: \"1\") { name } }", err: "field [items] on type [Query]: argument [count]: " +
+				"expecting a value of type Int but got 1"},
+			{
+				query: "query ($c: Int) { items(count: $c) { name } }",
+				vars:  map[string]interface{}{"c": 1.5},
+				err:   "field [items] on type [Query]: argument [count]: expecting a value of type Int but got 1.5",
+			},
+			{query: "{ items(prefix: 1) { name } }", err: "field [items] on type [Query]: argument [prefix]: " +
+				"expecting a value of type String but got 1"},
+			{query: "{ items(enabled: \"true\") { name } }", err: "field [items] on type [Query]: argument " +
+				"[enabled]: expecting a value of type Boolean but got true"},
+		} {
+			resp := s.Execute(context.Background(), &Request{Query: test.query, Variables: test.vars})
+			require.Nil(t, resp.Data, test.query)
+			requireError(t, resp, test.err)
+		}
+	})
+}
+
+func TestArgType_String(t *testing.T) {
+	require.Equal(t, "String", String.String())
+	require.Equal(t, "Int", Int.String())
+	require.Equal(t, "Boolean", Boolean.String())
+	require.Equal(t, "Unknown", ArgType(100).String())
+}
+
+func TestOrderedMap(t *testing.T) {
+	m := newOrderedMap()
+	m.set("b", 1)
+	m.set("a", "x")
+	m.set("b", 2)
+
+	v, ok := m.get("b")
+	require.True(t, ok)
+	require.Equal(t, 2, v)
+
+	_, ok = m.get("c")
+	require.False(t, ok)
+
+	mBytes, err := json.Marshal(m)
+	require.NoError(t, err)
+	require.Equal(t, `{"b":2,"a":"x"}`, string(mBytes))
+
+	m.set("c", func() {})
+
+	_, err = json.Marshal(m)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "marshal field [c]")
+}
+
+func newTestSchema() *Schema {
+	itemType := &Object{Name: "Item"}
+
+	itemType.Fields = map[string]*Field{
+		"name": {Resolve: itemField(func(item *testItem) interface{} { return item.name })},
+		"parts": {
+			Type:    itemType,
+			List:    true,
+			Resolve: itemField(func(item *testItem) interface{} { return item.parts }),
+		},
+		"error": {Resolve: func(*ResolveParams) (interface{}, error) { return nil, errors.New("injected error") }},
+	}
+
+	return NewSchema(&Object{
+		Name: "Query",
+		Fields: map[string]*Field{
+			"count": {Resolve: func(*ResolveParams) (interface{}, error) { return 10, nil }},
+			"item": {
+				Type: itemType,
+				Args: map[string]*Arg{"name": {Type: String, Required: true}},
+				Resolve: func(p *ResolveParams) (interface{}, error) {
+					name, ok := p.Args["name"].(string)
+					if !ok || name == "" {
+						var item *testItem
+
+						return item, nil
+					}
+
+					return &testItem{name: name}, nil
+				},
+			},
+			"items": {
+				Type: itemType,
+				List: true,
+				Args: map[string]*Arg{
+					"prefix":  {Type: String},
+					"count":   {Type: Int, Default: 1},
+					"enabled": {Type: Boolean},
+					"withNil": {Type: Boolean},
+				},
+				Resolve: resolveItems,
+			},
+			"notAList": {
+				Type:    itemType,
+				List:    true,
+				Resolve: func(*ResolveParams) (interface{}, error) { return "item", nil },
+			},
+		},
+	})
+}
+
+func resolveItems(p *ResolveParams) (interface{}, error) {
+	prefix, ok := p.Args["prefix"].(string)
+	if !ok {
+		prefix = ""
+	}
+
+	count, ok := p.Args["count"].(int)
+	if !ok {
+		return nil, errors.New("invalid count")
+	}
+
+	withNil, ok := p.Args["withNil"].(bool)
+	if !ok {
+		withNil = false
+	}
+
+	items := make([]*testItem, count)
+
+	for i := 0; i < count; i++ {
+		if withNil && i%2 == 1 {
+			continue
+		}
+
+		name := fmt.Sprintf("%s%d", prefix, i)
+
+		items[i] = &testItem{name: name, parts: []*testItem{{name: name + "-part"}}}
+	}
+
+	return items, nil
+}
+
+func itemField(fn func(item *testItem) interface{}) ResolveFunc {
+	return func(p *ResolveParams) (interface{}, error) {
+		item, ok := p.Source.(*testItem)
+		if !ok {
+			return nil, fmt.Errorf("unexpected source type %T", p.Source)
+		}
+
+		return fn(item), nil
+	}
+}
+
+func requireJSON(t *testing.T, expected string, resp *Response) {
+	t.Helper()
+
+	respBytes, err := json.Marshal(resp)
+	require.NoError(t, err)
+	require.JSONEq(t, expected, string(respBytes))
+}
+
+func requireError(t *testing.T, resp *Response, msg string) {
+	t.Helper()
+
+	require.Len(t, resp.Errors, 1)
+	require.Equal(t, msg, resp.Errors[0].Message)
+}
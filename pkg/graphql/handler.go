@@ -0,0 +1,150 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/trustbloc/edge-core/pkg/log"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+)
+
+// Path is the path of the GraphQL endpoint.
+const Path = "/graphql"
+
+const (
+	queryParam         = "query"
+	operationNameParam = "operationName"
+	variablesParam     = "variables"
+)
+
+var logger = log.New("graphql")
+
+type executor interface {
+	Execute(ctx context.Context, req *Request) *Response
+}
+
+// Handler implements a REST handler that executes GraphQL queries. A query may be submitted with GET (using the
+// query, operationName and variables URL parameters) or with POST (using a JSON request body).
+type Handler struct {
+	method   string
+	executor executor
+	readAll  func(r io.Reader) ([]byte, error)
+	marshal  func(v interface{}) ([]byte, error)
+}
+
+// NewGetHandler returns a new handler that executes queries submitted with GET.
+func NewGetHandler(executor executor) *Handler {
+	return newHandler(http.MethodGet, executor)
+}
+
+// NewPostHandler returns a new handler that executes queries submitted with POST.
+func NewPostHandler(executor executor) *Handler {
+	return newHandler(http.MethodPost, executor)
+}
+
+func newHandler(method string, executor executor) *Handler {
+	return &Handler{
+		method:   method,
+		executor: executor,
+		readAll:  ioutil.ReadAll,
+		marshal:  json.Marshal,
+	}
+}
+
+// Path returns the HTTP REST endpoint for the GraphQL service.
+func (h *Handler) Path() string {
+	return Path
+}
+
+// Method returns the HTTP method (GET or POST).
+func (h *Handler) Method() string {
+	return h.method
+}
+
+// Handler returns the HTTP REST handle for the GraphQL service.
+func (h *Handler) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Handler) handle(w http.ResponseWriter, req *http.Request) {
+	gqlReq, err := h.getRequest(req)
+	if err != nil {
+		logger.Debugf("[%s] Invalid request: %s", Path, err)
+
+		h.writeResponse(w, http.StatusBadRequest, errorResponse(err))
+
+		return
+	}
+
+	resp := h.executor.Execute(req.Context(), gqlReq)
+
+	if resp.Data == nil {
+		// The request failed to parse or validate.
+		h.writeResponse(w, http.StatusBadRequest, resp)
+
+		return
+	}
+
+	h.writeResponse(w, http.StatusOK, resp)
+}
+
+func (h *Handler) getRequest(req *http.Request) (*Request, error) {
+	if h.method == http.MethodGet {
+		params := req.URL.Query()
+
+		gqlReq := &Request{
+			Query:         params.Get(queryParam),
+			OperationName: params.Get(operationNameParam),
+		}
+
+		if vars := params.Get(variablesParam); vars != "" {
+			if err := json.Unmarshal([]byte(vars), &gqlReq.Variables); err != nil {
+				return nil, fmt.Errorf("invalid variables: %w", err)
+			}
+		}
+
+		return gqlReq, nil
+	}
+
+	reqBytes, err := h.readAll(req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read request body: %w", err)
+	}
+
+	gqlReq := &Request{}
+
+	err = json.Unmarshal(reqBytes, gqlReq)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request body: %w", err)
+	}
+
+	return gqlReq, nil
+}
+
+func (h *Handler) writeResponse(w http.ResponseWriter, status int, resp *Response) {
+	respBytes, err := h.marshal(resp)
+	if err != nil {
+		logger.Errorf("[%s] Error marshalling response: %s", Path, err)
+
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if _, e := w.Write(respBytes); e != nil {
+		logger.Warnf("[%s] Unable to write response: %s", Path, e)
+	}
+}
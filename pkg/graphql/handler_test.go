@@ -0,0 +1,173 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	s := newTestSchema()
+
+	t.Run("GET", func(t *testing.T) {
+		h := NewGetHandler(s)
+		require.Equal(t, Path, h.Path())
+		require.Equal(t, http.MethodGet, h.Method())
+		require.NotNil(t, h.Handler())
+
+		params := url.Values{}
+		params.Set(queryParam, `query Items($count: Int) { items(count: $count) { name } }`)
+		params.Set(operationNameParam, "Items")
+		params.Set(variablesParam, `{"count": 2}`)
+
+		rw := httptest.NewRecorder()
+
+		h.handle(rw, httptest.NewRequest(http.MethodGet, Path+"?"+params.Encode(), nil))
+
+		result := rw.Result()
+		require.NoError(t, result.Body.Close())
+
+		require.Equal(t, http.StatusOK, result.StatusCode)
+		require.Equal(t, "application/json", result.Header.Get("Content-Type"))
+		require.JSONEq(t, `{"data": {"items": [{"name": "0"}, {"name": "1"}]}}`, rw.Body.String())
+	})
+
+	t.Run("GET - invalid variables", func(t *testing.T) {
+		h := NewGetHandler(s)
+
+		params := url.Values{}
+		params.Set(queryParam, `{ count }`)
+		params.Set(variablesParam, `{`)
+
+		rw := httptest.NewRecorder()
+
+		h.handle(rw, httptest.NewRequest(http.MethodGet, Path+"?"+params.Encode(), nil))
+
+		result := rw.Result()
+		require.NoError(t, result.Body.Close())
+
+		require.Equal(t, http.StatusBadRequest, result.StatusCode)
+		require.Contains(t, rw.Body.String(), "invalid variables")
+	})
+
+	t.Run("POST", func(t *testing.T) {
+		h := NewPostHandler(s)
+		require.Equal(t, Path, h.Path())
+		require.Equal(t, http.MethodPost, h.Method())
+
+		reqBytes, err := json.Marshal(&Request{
+			Query:     `query ($name: String!) { item(name: $name) { name } }`,
+			Variables: map[string]interface{}{"name": "item1"},
+		})
+		require.NoError(t, err)
+
+		rw := httptest.NewRecorder()
+
+		h.handle(rw, httptest.NewRequest(http.MethodPost, Path, bytes.NewReader(reqBytes)))
+
+		result := rw.Result()
+		require.NoError(t, result.Body.Close())
+
+		require.Equal(t, http.StatusOK, result.StatusCode)
+		require.JSONEq(t, `{"data": {"item": {"name": "item1"}}}`, rw.Body.String())
+	})
+
+	t.Run("POST - field error", func(t *testing.T) {
+		h := NewPostHandler(s)
+
+		rw := httptest.NewRecorder()
+
+		h.handle(rw, httptest.NewRequest(http.MethodPost, Path, bytes.NewReader([]byte(
+			`{"query": "{ item(name: \"x\") { error } }"}`,
+		))))
+
+		result := rw.Result()
+		require.NoError(t, result.Body.Close())
+
+		require.Equal(t, http.StatusOK, result.StatusCode)
+		require.JSONEq(t, `{"data": {"item": {"error": null}}, `+
+			`"errors": [{"message": "injected error", "path": ["item", "error"]}]}`, rw.Body.String())
+	})
+
+	t.Run("POST - invalid query", func(t *testing.T) {
+		h := NewPostHandler(s)
+
+		rw := httptest.NewRecorder()
+
+		h.handle(rw, httptest.NewRequest(http.MethodPost, Path, bytes.NewReader([]byte(
+			`{"query": "{ unknown }"}`,
+		))))
+
+		result := rw.Result()
+		require.NoError(t, result.Body.Close())
+
+		require.Equal(t, http.StatusBadRequest, result.StatusCode)
+		require.JSONEq(t, `{"errors": [{"message": "cannot query field [unknown] on type [Query]"}]}`,
+			rw.Body.String())
+	})
+
+	t.Run("POST - invalid request body", func(t *testing.T) {
+		h := NewPostHandler(s)
+
+		rw := httptest.NewRecorder()
+
+		h.handle(rw, httptest.NewRequest(http.MethodPost, Path, bytes.NewReader([]byte("{"))))
+
+		result := rw.Result()
+		require.NoError(t, result.Body.Close())
+
+		require.Equal(t, http.StatusBadRequest, result.StatusCode)
+		require.Contains(t, rw.Body.String(), "invalid request body")
+	})
+
+	t.Run("POST - read request body error", func(t *testing.T) {
+		h := NewPostHandler(s)
+		h.readAll = func(r io.Reader) ([]byte, error) { return nil, errors.New("injected read error") }
+
+		rw := httptest.NewRecorder()
+
+		h.handle(rw, httptest.NewRequest(http.MethodPost, Path, nil))
+
+		result := rw.Result()
+		require.NoError(t, result.Body.Close())
+
+		require.Equal(t, http.StatusBadRequest, result.StatusCode)
+		require.Contains(t, rw.Body.String(), "injected read error")
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		h := NewGetHandler(&mockExecutor{resp: &Response{Data: "data"}})
+		h.marshal = func(v interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		rw := httptest.NewRecorder()
+
+		h.handle(rw, httptest.NewRequest(http.MethodGet, Path+"?query=%7Bcount%7D", nil))
+
+		result := rw.Result()
+		require.NoError(t, result.Body.Close())
+
+		require.Equal(t, http.StatusInternalServerError, result.StatusCode)
+	})
+}
+
+type mockExecutor struct {
+	resp *Response
+}
+
+func (m *mockExecutor) Execute(context.Context, *Request) *Response {
+	return m.resp
+}
@@ -0,0 +1,275 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const byteOrderMark = "\uFEFF"
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenPunctuator
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+func (k tokenKind) String() string {
+	switch k {
+	case tokenEOF:
+		return "end of query"
+	case tokenPunctuator:
+		return "punctuator"
+	case tokenName:
+		return "name"
+	case tokenInt:
+		return "integer"
+	case tokenFloat:
+		return "float"
+	case tokenString:
+		return "string"
+	default:
+		return "unknown"
+	}
+}
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return t.kind.String()
+	}
+
+	return fmt.Sprintf("%s %q", t.kind, t.value)
+}
+
+// lexer splits a GraphQL query document into tokens. Whitespace, commas and comments are ignored.
+type lexer struct {
+	src string
+	pos int
+}
+
+func newLexer(src string) *lexer {
+	return &lexer{src: src}
+}
+
+func (l *lexer) next() (token, error) { //nolint:gocyclo,cyclop
+	l.skipIgnored()
+
+	if l.pos >= len(l.src) {
+		return token{kind: tokenEOF, pos: l.pos}, nil
+	}
+
+	start := l.pos
+	c := l.src[l.pos]
+
+	switch {
+	case strings.IndexByte("!$():=@[]{}|&", c) >= 0:
+		l.pos++
+
+		return token{kind: tokenPunctuator, value: string(c), pos: start}, nil
+
+	case c == '.':
+		if strings.HasPrefix(l.src[l.pos:], "...") {
+			l.pos += 3
+
+			return token{kind: tokenPunctuator, value: "...", pos: start}, nil
+		}
+
+		return token{}, fmt.Errorf("unexpected character '.' at position %d", start)
+
+	case c == '"':
+		return l.readString()
+
+	case c == '-' || isDigit(c):
+		return l.readNumber()
+
+	case isNameStart(c):
+		for l.pos < len(l.src) && isNameContinue(l.src[l.pos]) {
+			l.pos++
+		}
+
+		return token{kind: tokenName, value: l.src[start:l.pos], pos: start}, nil
+
+	default:
+		r, _ := utf8.DecodeRuneInString(l.src[l.pos:])
+
+		return token{}, fmt.Errorf("unexpected character %q at position %d", r, start)
+	}
+}
+
+func (l *lexer) skipIgnored() {
+	for l.pos < len(l.src) {
+		switch c := l.src[l.pos]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			l.pos++
+		case c == '#':
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' && l.src[l.pos] != '\r' {
+				l.pos++
+			}
+		case strings.HasPrefix(l.src[l.pos:], byteOrderMark):
+			l.pos += len(byteOrderMark)
+		default:
+			return
+		}
+	}
+}
+
+func (l *lexer) readNumber() (token, error) {
+	start := l.pos
+
+	if l.src[l.pos] == '-' {
+		l.pos++
+	}
+
+	l.readDigits()
+
+	kind := tokenInt
+
+	if l.pos < len(l.src) && l.src[l.pos] == '.' {
+		kind = tokenFloat
+		l.pos++
+
+		l.readDigits()
+	}
+
+	if l.pos < len(l.src) && (l.src[l.pos] == 'e' || l.src[l.pos] == 'E') {
+		kind = tokenFloat
+		l.pos++
+
+		if l.pos < len(l.src) && (l.src[l.pos] == '+' || l.src[l.pos] == '-') {
+			l.pos++
+		}
+
+		l.readDigits()
+	}
+
+	value := l.src[start:l.pos]
+
+	var err error
+
+	if kind == tokenInt {
+		_, err = strconv.ParseInt(value, 10, 64)
+	} else {
+		_, err = strconv.ParseFloat(value, 64)
+	}
+
+	if err != nil {
+		return token{}, fmt.Errorf("invalid number %q at position %d", value, start)
+	}
+
+	return token{kind: kind, value: value, pos: start}, nil
+}
+
+func (l *lexer) readDigits() {
+	for l.pos < len(l.src) && isDigit(l.src[l.pos]) {
+		l.pos++
+	}
+}
+
+func (l *lexer) readString() (token, error) {
+	start := l.pos
+
+	if strings.HasPrefix(l.src[l.pos:], `"""`) {
+		return token{}, fmt.Errorf("block strings are not supported (position %d)", start)
+	}
+
+	l.pos++
+
+	var sb strings.Builder
+
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+
+		switch c {
+		case '"':
+			l.pos++
+
+			return token{kind: tokenString, value: sb.String(), pos: start}, nil
+
+		case '\n', '\r':
+			return token{}, fmt.Errorf("unterminated string at position %d", start)
+
+		case '\\':
+			if err := l.readEscape(&sb); err != nil {
+				return token{}, err
+			}
+
+		default:
+			sb.WriteByte(c)
+			l.pos++
+		}
+	}
+
+	return token{}, fmt.Errorf("unterminated string at position %d", start)
+}
+
+func (l *lexer) readEscape(sb *strings.Builder) error {
+	start := l.pos
+
+	if l.pos+1 >= len(l.src) {
+		return fmt.Errorf("invalid escape sequence at position %d", start)
+	}
+
+	c := l.src[l.pos+1]
+	l.pos += 2
+
+	switch c {
+	case '"', '\\', '/':
+		sb.WriteByte(c)
+	case 'b':
+		sb.WriteByte('\b')
+	case 'f':
+		sb.WriteByte('\f')
+	case 'n':
+		sb.WriteByte('\n')
+	case 'r':
+		sb.WriteByte('\r')
+	case 't':
+		sb.WriteByte('\t')
+	case 'u':
+		if l.pos+4 > len(l.src) {
+			return fmt.Errorf("invalid unicode escape sequence at position %d", start)
+		}
+
+		r, err := strconv.ParseUint(l.src[l.pos:l.pos+4], 16, 32)
+		if err != nil {
+			return fmt.Errorf("invalid unicode escape sequence at position %d", start)
+		}
+
+		sb.WriteRune(rune(r))
+		l.pos += 4
+	default:
+		return fmt.Errorf("invalid escape sequence at position %d", start)
+	}
+
+	return nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isNameContinue(c byte) bool {
+	return isNameStart(c) || isDigit(c)
+}
@@ -0,0 +1,82 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package graphql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLexer(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		l := newLexer(`query Q($first: Int = -10) { # comment
+			a: activities(since: "2021-09-01\nA", ratio: 1.5e3, list: [1, 2]) { ...F } }`)
+
+		var tokens []token
+
+		for {
+			tok, err := l.next()
+			require.NoError(t, err)
+
+			tokens = append(tokens, tok)
+
+			if tok.kind == tokenEOF {
+				break
+			}
+		}
+
+		values := make([]string, len(tokens))
+
+		for i, tok := range tokens {
+			values[i] = tok.value
+		}
+
+		require.Equal(t, []string{
+			"query", "Q", "(", "$", "first", ":", "Int", "=", "-10", ")", "{",
+			"a", ":", "activities", "(", "since", ":", "2021-09-01\nA", "ratio", ":", "1.5e3",
+			"list", ":", "[", "1", "2", "]", ")", "{", "...", "F", "}", "}", "",
+		}, values)
+
+		require.Equal(t, tokenName, tokens[0].kind)
+		require.Equal(t, tokenInt, tokens[8].kind)
+		require.Equal(t, tokenString, tokens[17].kind)
+		require.Equal(t, tokenFloat, tokens[20].kind)
+		require.Equal(t, tokenEOF, tokens[len(tokens)-1].kind)
+		require.Equal(t, `name "query"`, tokens[0].String())
+		require.Equal(t, "end of query", tokens[len(tokens)-1].String())
+	})
+
+	t.Run("Byte order mark", func(t *testing.T) {
+		tok, err := newLexer(byteOrderMark + "{").next()
+		require.NoError(t, err)
+		require.Equal(t, "{", tok.value)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		for _, query := range []struct {
+			src string
+			err string
+		}{
+			{src: "%", err: "unexpected character '%' at position 0"},
+			{src: "..", err: "unexpected character '.' at position 0"},
+			{src: `"abc`, err: "unterminated string at position 0"},
+			{src: "\"abc\n\"", err: "unterminated string at position 0"},
+			{src: `"\x"`, err: "invalid escape sequence at position 1"},
+			{src: `"\`, err: "invalid escape sequence at position 1"},
+			{src: `"\u00"`, err: "invalid unicode escape sequence at position 1"},
+			{src: `"\uXXXX"`, err: "invalid unicode escape sequence at position 1"},
+			{src: `"""abc"""`, err: "block strings are not supported (position 0)"},
+			{src: "-", err: `invalid number "-" at position 0`},
+			{src: "1e", err: `invalid number "1e" at position 0`},
+		} {
+			_, err := newLexer(query.src).next()
+			require.Error(t, err, query.src)
+			require.Equal(t, query.err, err.Error(), query.src)
+		}
+	})
+}
@@ -0,0 +1,516 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package orbschema
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/trustbloc/edge-core/pkg/log"
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
+
+	store "github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/anchor/anchorevent"
+	"github.com/trustbloc/orb/pkg/anchor/subject"
+	"github.com/trustbloc/orb/pkg/graphql"
+)
+
+var logger = log.New("graphql-orb-schema")
+
+const (
+	defaultFirst      = 25
+	defaultMaxResults = 100
+	dateLayout        = "2006-01-02"
+)
+
+type activityStore interface {
+	QueryReferences(refType store.ReferenceType, query *store.Criteria,
+		opts ...store.QueryOpt) (store.ReferenceIterator, error)
+	GetActivity(activityID *url.URL) (*vocab.ActivityType, error)
+}
+
+type anchorGraph interface {
+	Read(hl string) (*vocab.AnchorEventType, error)
+}
+
+type didResolver interface {
+	ResolveDocument(id string) (*document.ResolutionResult, error)
+}
+
+// Config contains the configuration for the Orb schema.
+type Config struct {
+	ServiceIRI *url.URL
+	// MaxResults is the maximum number of activities that may be returned by a single 'activities' query.
+	MaxResults int
+}
+
+// anchor is the source value of the Anchor type.
+type anchor struct {
+	hashlink string
+	event    *vocab.AnchorEventType
+	payload  *subject.Payload
+}
+
+type resolvers struct {
+	serviceIRI  *url.URL
+	maxResults  int
+	store       activityStore
+	graph       anchorGraph
+	didResolver didResolver
+}
+
+// New returns a read-only GraphQL schema that's backed by the ActivityPub store, the anchor graph and the DID
+// resolver. The schema is intended for explorer front-ends, for example:
+//
+//	{
+//	  activities(collection: INBOX, actor: "https://orb.domain1.com/services/orb", type: "Announce",
+//	             since: "2021-09-01") {
+//	    id
+//	    published
+//	    anchors { hashlink published operationCount coreIndex }
+//	  }
+//	}
+func New(cfg *Config, apStore activityStore, graph anchorGraph, resolver didResolver) *graphql.Schema {
+	maxResults := cfg.MaxResults
+
+	if maxResults <= 0 {
+		maxResults = defaultMaxResults
+	}
+
+	r := &resolvers{
+		serviceIRI:  cfg.ServiceIRI,
+		maxResults:  maxResults,
+		store:       apStore,
+		graph:       graph,
+		didResolver: resolver,
+	}
+
+	previousAnchorType := &graphql.Object{
+		Name: "PreviousAnchor",
+		Fields: map[string]*graphql.Field{
+			"suffix": {Resolve: previousAnchorField(func(a *subject.SuffixAnchor) interface{} { return a.Suffix })},
+			"anchor": {Resolve: previousAnchorField(func(a *subject.SuffixAnchor) interface{} { return a.Anchor })},
+		},
+	}
+
+	anchorType := newAnchorType(previousAnchorType)
+	activityType := newActivityType(anchorType, r.resolveActivityAnchors)
+
+	didType := &graphql.Object{
+		Name: "DIDResolution",
+		Fields: map[string]*graphql.Field{
+			"document": {Resolve: didField(func(rr *document.ResolutionResult) interface{} { return rr.Document })},
+			"metadata": {Resolve: didField(func(rr *document.ResolutionResult) interface{} {
+				return rr.DocumentMetadata
+			})},
+		},
+	}
+
+	return graphql.NewSchema(&graphql.Object{
+		Name: "Query",
+		Fields: map[string]*graphql.Field{
+			"activities": {
+				Type: activityType,
+				List: true,
+				Args: map[string]*graphql.Arg{
+					"collection": {Type: graphql.String, Default: string(store.Inbox)},
+					"actor":      {Type: graphql.String},
+					"type":       {Type: graphql.String},
+					"since":      {Type: graphql.String},
+					"until":      {Type: graphql.String},
+					"first":      {Type: graphql.Int, Default: defaultFirst},
+				},
+				Resolve: r.resolveActivities,
+			},
+			"activity": {
+				Type:    activityType,
+				Args:    map[string]*graphql.Arg{"id": {Type: graphql.String, Required: true}},
+				Resolve: r.resolveActivity,
+			},
+			"anchor": {
+				Type:    anchorType,
+				Args:    map[string]*graphql.Arg{"hashlink": {Type: graphql.String, Required: true}},
+				Resolve: r.resolveAnchor,
+			},
+			"did": {
+				Type:    didType,
+				Args:    map[string]*graphql.Arg{"id": {Type: graphql.String, Required: true}},
+				Resolve: r.resolveDID,
+			},
+		},
+	})
+}
+
+func newActivityType(anchorType *graphql.Object, resolveAnchors graphql.ResolveFunc) *graphql.Object {
+	return &graphql.Object{
+		Name: "Activity",
+		Fields: map[string]*graphql.Field{
+			"id":   {Resolve: activityField(func(a *vocab.ActivityType) interface{} { return a.ID().String() })},
+			"type": {Resolve: activityField(func(a *vocab.ActivityType) interface{} { return a.Type().String() })},
+			"actor": {Resolve: activityField(func(a *vocab.ActivityType) interface{} {
+				return urlString(a.Actor())
+			})},
+			"to": {Resolve: activityField(func(a *vocab.ActivityType) interface{} {
+				return urlStrings(a.To())
+			})},
+			"published": {Resolve: activityField(func(a *vocab.ActivityType) interface{} {
+				return a.Published()
+			})},
+			"anchorHashlinks": {Resolve: activityField(func(a *vocab.ActivityType) interface{} {
+				return anchorHashlinks(a)
+			})},
+			"anchors": {Type: anchorType, List: true, Resolve: resolveAnchors},
+			"activity": {Resolve: activityField(func(a *vocab.ActivityType) interface{} {
+				return a
+			})},
+		},
+	}
+}
+
+func newAnchorType(previousAnchorType *graphql.Object) *graphql.Object {
+	return &graphql.Object{
+		Name: "Anchor",
+		Fields: map[string]*graphql.Field{
+			"hashlink": {Resolve: anchorField(func(a *anchor) interface{} { return a.hashlink })},
+			"attributedTo": {Resolve: anchorField(func(a *anchor) interface{} {
+				if a.event.AttributedTo() == nil {
+					return nil
+				}
+
+				return a.event.AttributedTo().String()
+			})},
+			"published": {Resolve: anchorField(func(a *anchor) interface{} { return a.event.Published() })},
+			"index":     {Resolve: anchorField(func(a *anchor) interface{} { return urlString(a.event.Index()) })},
+			"parents": {Resolve: anchorField(func(a *anchor) interface{} {
+				return urlStrings(a.event.Parent())
+			})},
+			"operationCount": {Resolve: anchorField(func(a *anchor) interface{} { return a.payload.OperationCount })},
+			"coreIndex":      {Resolve: anchorField(func(a *anchor) interface{} { return a.payload.CoreIndex })},
+			"namespace":      {Resolve: anchorField(func(a *anchor) interface{} { return a.payload.Namespace })},
+			"version":        {Resolve: anchorField(func(a *anchor) interface{} { return a.payload.Version })},
+			"anchorOrigin":   {Resolve: anchorField(func(a *anchor) interface{} { return a.payload.AnchorOrigin })},
+			"previousAnchors": {
+				Type:    previousAnchorType,
+				List:    true,
+				Resolve: anchorField(func(a *anchor) interface{} { return a.payload.PreviousAnchors }),
+			},
+			"anchorEvent": {Resolve: anchorField(func(a *anchor) interface{} { return a.event })},
+		},
+	}
+}
+
+func (r *resolvers) resolveActivities(p *graphql.ResolveParams) (interface{}, error) {
+	q, err := newActivityQuery(p.Args, r.maxResults)
+	if err != nil {
+		return nil, err
+	}
+
+	// Activities are returned in reverse chronological order (i.e. the most recent first).
+	it, err := r.store.QueryReferences(q.collection, store.NewCriteria(store.WithObjectIRI(r.serviceIRI)),
+		store.WithSortOrder(store.SortDescending),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query %s: %w", q.collection, err)
+	}
+
+	defer func() {
+		if errClose := it.Close(); errClose != nil {
+			logger.Warnf("Error closing iterator: %s", errClose)
+		}
+	}()
+
+	var activities []*vocab.ActivityType
+
+	for len(activities) < q.first {
+		ref, e := it.Next()
+		if e != nil {
+			if errors.Is(e, store.ErrNotFound) {
+				break
+			}
+
+			return nil, fmt.Errorf("next %s reference: %w", q.collection, e)
+		}
+
+		a, e := r.store.GetActivity(ref)
+		if e != nil {
+			if errors.Is(e, store.ErrNotFound) {
+				logger.Debugf("Activity [%s] in %s not found. Skipping.", ref, q.collection)
+
+				continue
+			}
+
+			return nil, fmt.Errorf("get activity [%s]: %w", ref, e)
+		}
+
+		if q.isBefore(a) {
+			// The remaining activities are older so there's no need to continue.
+			break
+		}
+
+		if q.matches(a) {
+			activities = append(activities, a)
+		}
+	}
+
+	return activities, nil
+}
+
+func (r *resolvers) resolveActivity(p *graphql.ResolveParams) (interface{}, error) {
+	id, err := url.Parse(stringArg(p.Args, "id"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid activity ID: %w", err)
+	}
+
+	a, err := r.store.GetActivity(id)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("get activity [%s]: %w", id, err)
+	}
+
+	return a, nil
+}
+
+func (r *resolvers) resolveActivityAnchors(p *graphql.ResolveParams) (interface{}, error) {
+	a, ok := p.Source.(*vocab.ActivityType)
+	if !ok {
+		return nil, fmt.Errorf("unexpected source type %T", p.Source)
+	}
+
+	var anchors []*anchor
+
+	for _, hl := range anchorHashlinks(a) {
+		anchr, err := r.getAnchor(hl)
+		if err != nil {
+			return nil, err
+		}
+
+		anchors = append(anchors, anchr)
+	}
+
+	return anchors, nil
+}
+
+func (r *resolvers) resolveAnchor(p *graphql.ResolveParams) (interface{}, error) {
+	return r.getAnchor(stringArg(p.Args, "hashlink"))
+}
+
+func (r *resolvers) getAnchor(hl string) (*anchor, error) {
+	anchorEvent, err := r.graph.Read(hl)
+	if err != nil {
+		return nil, fmt.Errorf("read anchor [%s]: %w", hl, err)
+	}
+
+	payload, err := anchorevent.GetPayloadFromAnchorEvent(anchorEvent)
+	if err != nil {
+		return nil, fmt.Errorf("get payload from anchor [%s]: %w", hl, err)
+	}
+
+	return &anchor{hashlink: hl, event: anchorEvent, payload: payload}, nil
+}
+
+func (r *resolvers) resolveDID(p *graphql.ResolveParams) (interface{}, error) {
+	id := stringArg(p.Args, "id")
+
+	result, err := r.didResolver.ResolveDocument(id)
+	if err != nil {
+		return nil, fmt.Errorf("resolve DID [%s]: %w", id, err)
+	}
+
+	return result, nil
+}
+
+// activityQuery contains the criteria of an 'activities' query.
+type activityQuery struct {
+	collection   store.ReferenceType
+	actor        string
+	activityType string
+	since        *time.Time
+	until        *time.Time
+	first        int
+}
+
+func newActivityQuery(args map[string]interface{}, maxResults int) (*activityQuery, error) {
+	q := &activityQuery{
+		collection:   store.ReferenceType(stringArg(args, "collection")),
+		actor:        stringArg(args, "actor"),
+		activityType: stringArg(args, "type"),
+	}
+
+	if q.collection != store.Inbox && q.collection != store.Outbox {
+		return nil, fmt.Errorf("unsupported collection [%s] - expecting %s or %s", q.collection,
+			store.Inbox, store.Outbox)
+	}
+
+	var err error
+
+	q.since, err = parseTime(stringArg(args, "since"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for 'since': %w", err)
+	}
+
+	q.until, err = parseTime(stringArg(args, "until"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for 'until': %w", err)
+	}
+
+	first, ok := args["first"].(int)
+	if !ok || first <= 0 {
+		return nil, fmt.Errorf("'first' must be greater than 0")
+	}
+
+	if first > maxResults {
+		first = maxResults
+	}
+
+	q.first = first
+
+	return q, nil
+}
+
+// isBefore returns true if the given activity was published before the 'since' time.
+func (q *activityQuery) isBefore(a *vocab.ActivityType) bool {
+	return q.since != nil && a.Published() != nil && a.Published().Before(*q.since)
+}
+
+func (q *activityQuery) matches(a *vocab.ActivityType) bool {
+	if q.actor != "" && urlString(a.Actor()) != q.actor {
+		return false
+	}
+
+	if q.activityType != "" && !a.Type().Is(vocab.Type(q.activityType)) {
+		return false
+	}
+
+	if q.until != nil && a.Published() != nil && a.Published().After(*q.until) {
+		return false
+	}
+
+	return true
+}
+
+// anchorHashlinks returns the hashlinks of the anchors in a Create or Announce activity.
+func anchorHashlinks(a *vocab.ActivityType) []string {
+	obj := a.Object()
+
+	if obj == nil {
+		return nil
+	}
+
+	objects := []*vocab.ObjectProperty{obj}
+
+	if a.Type().Is(vocab.TypeAnnounce) {
+		if coll := obj.Collection(); coll != nil {
+			objects = coll.Items()
+		} else if ordered := obj.OrderedCollection(); ordered != nil {
+			objects = ordered.Items()
+		}
+	}
+
+	var hashlinks []string
+
+	for _, o := range objects {
+		if ae := o.AnchorEvent(); ae != nil && len(ae.URL()) > 0 {
+			hashlinks = append(hashlinks, ae.URL()[0].String())
+		}
+	}
+
+	return hashlinks
+}
+
+// parseTime parses a time in RFC3339 format or a date in the format YYYY-MM-DD.
+func parseTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t, err = time.Parse(dateLayout, value)
+		if err != nil {
+			return nil, fmt.Errorf("expecting RFC3339 time or date in the format YYYY-MM-DD: %s", value)
+		}
+	}
+
+	return &t, nil
+}
+
+func stringArg(args map[string]interface{}, name string) string {
+	s, ok := args[name].(string)
+	if !ok {
+		return ""
+	}
+
+	return s
+}
+
+func urlString(u *url.URL) interface{} {
+	if u == nil {
+		return nil
+	}
+
+	return u.String()
+}
+
+func urlStrings(urls []*url.URL) []string {
+	values := make([]string, len(urls))
+
+	for i, u := range urls {
+		values[i] = u.String()
+	}
+
+	return values
+}
+
+func activityField(fn func(a *vocab.ActivityType) interface{}) graphql.ResolveFunc {
+	return func(p *graphql.ResolveParams) (interface{}, error) {
+		a, ok := p.Source.(*vocab.ActivityType)
+		if !ok {
+			return nil, fmt.Errorf("unexpected source type %T", p.Source)
+		}
+
+		return fn(a), nil
+	}
+}
+
+func anchorField(fn func(a *anchor) interface{}) graphql.ResolveFunc {
+	return func(p *graphql.ResolveParams) (interface{}, error) {
+		a, ok := p.Source.(*anchor)
+		if !ok {
+			return nil, fmt.Errorf("unexpected source type %T", p.Source)
+		}
+
+		return fn(a), nil
+	}
+}
+
+func previousAnchorField(fn func(a *subject.SuffixAnchor) interface{}) graphql.ResolveFunc {
+	return func(p *graphql.ResolveParams) (interface{}, error) {
+		a, ok := p.Source.(*subject.SuffixAnchor)
+		if !ok {
+			return nil, fmt.Errorf("unexpected source type %T", p.Source)
+		}
+
+		return fn(a), nil
+	}
+}
+
+func didField(fn func(rr *document.ResolutionResult) interface{}) graphql.ResolveFunc {
+	return func(p *graphql.ResolveParams) (interface{}, error) {
+		rr, ok := p.Source.(*document.ResolutionResult)
+		if !ok {
+			return nil, fmt.Errorf("unexpected source type %T", p.Source)
+		}
+
+		return fn(rr), nil
+	}
+}
@@ -0,0 +1,481 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package orbschema
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
+
+	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
+	store "github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/anchor/anchorevent"
+	"github.com/trustbloc/orb/pkg/anchor/subject"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/graphql"
+	"github.com/trustbloc/orb/pkg/internal/aptestutil"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+)
+
+const (
+	namespace    = "did:orb"
+	anchorOrigin = "https://orb.domain2.com/services/orb"
+	coreIndex    = "hl:uEiD2k2kSGESB9e3UwwTOJ8WhqCeAT8fzKfQ9JzuGIYcHdg:uoQ-CeEdodHRwczovL2V4YW1wbGUuY29tL2Nhcy91RWlEMmsya1NHRVNCOWUzVXd3VE9KOFdocUNlQVQ4ZnpLZlE5Snp1R0lZY0hkZ3hCaXBmczovL2JhZmtyZWlod3NudXJlZ2NlcWgyNjN2Z2RhdGhjcHJuYnZhdHlhdDZoNm11N2lwamhob2RjZGJ5aG95" //nolint:lll
+	createSuffix = "uEiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A"
+	updateSuffix = "uEiA329wd6Aj36YRmp7NGkeB5ADnVt8ARdMZMPzfXsjwTJA"
+	prevAnchor   = "hl:uEiAsiwjaXOYDmOHxmvDl3Mx0TfJ0uCar5YXqumjFJUNIBg:uoQ-CeEdodHRwczovL2V4YW1wbGUuY29tL2Nhcy91RWlBc2l3amFYT1lEbU9IeG12RGwzTXgwVGZKMHVDYXI1WVhxdW1qRkpVTklCZ3hCaXBmczovL2JhZmtyZWlibXJtZW51eGhnYW9tb2Q0bTI2ZHM1enRkdWp4emhqb2JndnBzeWwydjJuZGNza3EyaWF5" //nolint:lll
+	hashlink1    = "hl:uEiAn3Y7USoP_lNVX-f0EEu1ajLymnqBJItiMARhKBzAKWg"
+	hashlink2    = "hl:uEiBBbcKBL-tC3fv8vdW-Gq2ZcTfXGHymaZLDRG8PDSOmaQ"
+)
+
+var (
+	serviceIRI = testutil.MustParseURL("https://orb.domain1.com/services/orb")
+	service2   = testutil.MustParseURL("https://orb.domain2.com/services/orb")
+	service3   = testutil.MustParseURL("https://orb.domain3.com/services/orb")
+)
+
+func TestSchema_Activities(t *testing.T) {
+	apStore := memstore.New("service1")
+
+	graph := newMockGraph()
+	graph.add(t, hashlink1)
+	graph.add(t, hashlink2)
+
+	addToInbox(t, apStore, newCreate(service2, "2021-09-01T10:00:00Z", hashlink1))
+	addToInbox(t, apStore, newAnnounce(service3, "2021-09-10T10:00:00Z", hashlink1, hashlink2))
+	addToInbox(t, apStore, newFollow(service2, "2021-09-15T10:00:00Z"))
+	addToInbox(t, apStore, newAnnounce(service3, "2021-09-20T10:00:00Z", hashlink2))
+
+	s := New(&Config{ServiceIRI: serviceIRI}, apStore, graph, &mockResolver{})
+
+	t.Run("All activities", func(t *testing.T) {
+		data := execute(t, s, `{ activities { type actor published } }`, nil)
+
+		require.Equal(t, []interface{}{
+			map[string]interface{}{
+				"type": "Announce", "actor": service3.String(), "published": "2021-09-20T10:00:00Z",
+			},
+			map[string]interface{}{
+				"type": "Follow", "actor": service2.String(), "published": "2021-09-15T10:00:00Z",
+			},
+			map[string]interface{}{
+				"type": "Announce", "actor": service3.String(), "published": "2021-09-10T10:00:00Z",
+			},
+			map[string]interface{}{
+				"type": "Create", "actor": service2.String(), "published": "2021-09-01T10:00:00Z",
+			},
+		}, data["activities"])
+	})
+
+	t.Run("Announce activities from actor since date joined with anchors", func(t *testing.T) {
+		data := execute(t, s, `query ($actor: String!, $since: String) {
+			activities(collection: INBOX, actor: $actor, type: "Announce", since: $since) {
+				to
+				anchorHashlinks
+				anchors { hashlink attributedTo operationCount namespace anchorOrigin parents
+					previousAnchors { suffix anchor } }
+			}
+		}`, map[string]interface{}{"actor": service3.String(), "since": "2021-09-15"})
+
+		activities, ok := data["activities"].([]interface{})
+		require.True(t, ok)
+		require.Len(t, activities, 1)
+
+		activity, ok := activities[0].(map[string]interface{})
+		require.True(t, ok)
+		require.Equal(t, []interface{}{serviceIRI.String()}, activity["to"])
+		require.Equal(t, []interface{}{hashlink2}, activity["anchorHashlinks"])
+		require.Equal(t, []interface{}{
+			map[string]interface{}{
+				"hashlink":       hashlink2,
+				"attributedTo":   anchorOrigin,
+				"operationCount": float64(2),
+				"namespace":      namespace,
+				"anchorOrigin":   anchorOrigin,
+				"parents":        []interface{}{prevAnchor},
+				"previousAnchors": []interface{}{
+					map[string]interface{}{"suffix": createSuffix, "anchor": ""},
+					map[string]interface{}{"suffix": updateSuffix, "anchor": prevAnchor},
+				},
+			},
+		}, activity["anchors"])
+	})
+
+	t.Run("Time range and limit", func(t *testing.T) {
+		data := execute(t, s, `{
+			activities(since: "2021-09-01T00:00:00Z", until: "2021-09-16", first: 2) { type published }
+		}`, nil)
+
+		require.Equal(t, []interface{}{
+			map[string]interface{}{"type": "Follow", "published": "2021-09-15T10:00:00Z"},
+			map[string]interface{}{"type": "Announce", "published": "2021-09-10T10:00:00Z"},
+		}, data["activities"])
+	})
+
+	t.Run("Create activity", func(t *testing.T) {
+		data := execute(t, s, `{ activities(type: "Create") { anchors { hashlink index } } }`, nil)
+
+		activities, ok := data["activities"].([]interface{})
+		require.True(t, ok)
+		require.Len(t, activities, 1)
+
+		activityBytes, err := json.Marshal(activities[0])
+		require.NoError(t, err)
+		require.Contains(t, string(activityBytes), hashlink1)
+	})
+
+	t.Run("Empty outbox", func(t *testing.T) {
+		data := execute(t, s, `{ activities(collection: OUTBOX) { id } }`, nil)
+		require.Nil(t, data["activities"])
+	})
+
+	t.Run("Invalid arguments", func(t *testing.T) {
+		for _, test := range []struct {
+			query string
+			err   string
+		}{
+			{query: `{ activities(collection: LIKED) { id } }`, err: "unsupported collection [LIKED]"},
+			{query: `{ activities(since: "yesterday") { id } }`, err: "invalid value for 'since'"},
+			{query: `{ activities(until: "2021-09") { id } }`, err: "invalid value for 'until'"},
+			{query: `{ activities(first: 0) { id } }`, err: "'first' must be greater than 0"},
+		} {
+			resp := s.Execute(context.Background(), &graphql.Request{Query: test.query})
+			require.Len(t, resp.Errors, 1, test.query)
+			require.Contains(t, resp.Errors[0].Message, test.err, test.query)
+		}
+	})
+
+	t.Run("Max results", func(t *testing.T) {
+		s := New(&Config{ServiceIRI: serviceIRI, MaxResults: 1}, apStore, graph, &mockResolver{})
+
+		data := execute(t, s, `{ activities(first: 100) { type } }`, nil)
+		require.Equal(t, []interface{}{map[string]interface{}{"type": "Announce"}}, data["activities"])
+	})
+
+	t.Run("Activity not found in store", func(t *testing.T) {
+		apStore := memstore.New("service1")
+
+		a := newFollow(service2, "2021-09-15T10:00:00Z")
+		require.NoError(t, apStore.AddReference(store.Inbox, serviceIRI, a.ID().URL()))
+
+		data := execute(t, New(&Config{ServiceIRI: serviceIRI}, apStore, graph, &mockResolver{}),
+			`{ activities { id } }`, nil)
+		require.Nil(t, data["activities"])
+	})
+
+	t.Run("Anchor not found", func(t *testing.T) {
+		apStore := memstore.New("service1")
+
+		addToInbox(t, apStore, newCreate(service2, "2021-09-01T10:00:00Z", "hl:unknown"))
+
+		resp := New(&Config{ServiceIRI: serviceIRI}, apStore, graph, &mockResolver{}).Execute(
+			context.Background(), &graphql.Request{Query: `{ activities { type anchors { hashlink } } }`},
+		)
+		require.Len(t, resp.Errors, 1)
+		require.Contains(t, resp.Errors[0].Message, "read anchor [hl:unknown]")
+		require.Equal(t, []interface{}{"activities", 0, "anchors"}, resp.Errors[0].Path)
+	})
+
+	t.Run("Store error", func(t *testing.T) {
+		errExpected := errors.New("injected query error")
+
+		resp := New(&Config{ServiceIRI: serviceIRI}, &mockActivityStore{queryErr: errExpected}, graph,
+			&mockResolver{}).Execute(context.Background(), &graphql.Request{Query: `{ activities { id } }`})
+		require.Len(t, resp.Errors, 1)
+		require.Contains(t, resp.Errors[0].Message, errExpected.Error())
+
+		resp = New(&Config{ServiceIRI: serviceIRI}, &mockActivityStore{Store: apStore, getErr: errExpected}, graph,
+			&mockResolver{}).Execute(context.Background(), &graphql.Request{Query: `{ activities { id } }`})
+		require.Len(t, resp.Errors, 1)
+		require.Contains(t, resp.Errors[0].Message, errExpected.Error())
+	})
+}
+
+func TestSchema_Activity(t *testing.T) {
+	apStore := memstore.New("service1")
+
+	a := newCreate(service2, "2021-09-01T10:00:00Z", hashlink1)
+	require.NoError(t, apStore.AddActivity(a))
+
+	s := New(&Config{ServiceIRI: serviceIRI}, apStore, newMockGraph(), &mockResolver{})
+
+	t.Run("Success", func(t *testing.T) {
+		data := execute(t, s, `query ($id: String!) { activity(id: $id) { id type activity } }`,
+			map[string]interface{}{"id": a.ID().String()})
+
+		activity, ok := data["activity"].(map[string]interface{})
+		require.True(t, ok)
+		require.Equal(t, a.ID().String(), activity["id"])
+		require.Equal(t, "Create", activity["type"])
+		require.NotNil(t, activity["activity"])
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		data := execute(t, s, `{ activity(id: "https://orb.domain2.com/activities/123") { id } }`, nil)
+		require.Nil(t, data["activity"])
+	})
+
+	t.Run("Invalid ID", func(t *testing.T) {
+		resp := s.Execute(context.Background(), &graphql.Request{Query: `{ activity(id: ":invalid") { id } }`})
+		require.Len(t, resp.Errors, 1)
+		require.Contains(t, resp.Errors[0].Message, "invalid activity ID")
+	})
+
+	t.Run("Store error", func(t *testing.T) {
+		errExpected := errors.New("injected get error")
+
+		resp := New(&Config{ServiceIRI: serviceIRI}, &mockActivityStore{Store: apStore, getErr: errExpected},
+			newMockGraph(), &mockResolver{}).Execute(context.Background(),
+			&graphql.Request{Query: `{ activity(id: "https://orb.domain2.com/activities/123") { id } }`},
+		)
+		require.Len(t, resp.Errors, 1)
+		require.Contains(t, resp.Errors[0].Message, errExpected.Error())
+	})
+}
+
+func TestSchema_Anchor(t *testing.T) {
+	graph := newMockGraph()
+	graph.add(t, hashlink1)
+	graph.graph["hl:invalid"] = vocab.NewAnchorEvent()
+
+	s := New(&Config{ServiceIRI: serviceIRI}, memstore.New("service1"), graph, &mockResolver{})
+
+	t.Run("Success", func(t *testing.T) {
+		data := execute(t, s, fmt.Sprintf(`{
+			anchor(hashlink: %q) { hashlink coreIndex version operationCount published anchorEvent }
+		}`, hashlink1), nil)
+
+		anchor, ok := data["anchor"].(map[string]interface{})
+		require.True(t, ok)
+		require.Equal(t, hashlink1, anchor["hashlink"])
+		require.Equal(t, coreIndex, anchor["coreIndex"])
+		require.Equal(t, float64(0), anchor["version"])
+		// The operation count is the number of DIDs in the anchor.
+		require.Equal(t, float64(2), anchor["operationCount"])
+		require.NotEmpty(t, anchor["published"])
+		require.NotNil(t, anchor["anchorEvent"])
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		resp := s.Execute(context.Background(), &graphql.Request{Query: `{ anchor(hashlink: "hl:xxx") { hashlink } }`})
+		require.Len(t, resp.Errors, 1)
+		require.Contains(t, resp.Errors[0].Message, orberrors.ErrContentNotFound.Error())
+	})
+
+	t.Run("Invalid anchor event", func(t *testing.T) {
+		resp := s.Execute(context.Background(),
+			&graphql.Request{Query: `{ anchor(hashlink: "hl:invalid") { hashlink } }`})
+		require.Len(t, resp.Errors, 1)
+		require.Contains(t, resp.Errors[0].Message, "get payload from anchor [hl:invalid]")
+	})
+}
+
+func TestSchema_DID(t *testing.T) {
+	const did = "did:orb:uAAA:EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A"
+
+	result := &document.ResolutionResult{
+		Document:         document.Document{"id": did},
+		DocumentMetadata: document.Metadata{"canonicalId": did},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		s := New(&Config{ServiceIRI: serviceIRI}, memstore.New("service1"), newMockGraph(),
+			&mockResolver{result: result})
+
+		data := execute(t, s, fmt.Sprintf(`{ did(id: %q) { document metadata } }`, did), nil)
+		require.Equal(t, map[string]interface{}{
+			"document": map[string]interface{}{"id": did},
+			"metadata": map[string]interface{}{"canonicalId": did},
+		}, data["did"])
+	})
+
+	t.Run("Resolve error", func(t *testing.T) {
+		s := New(&Config{ServiceIRI: serviceIRI}, memstore.New("service1"), newMockGraph(),
+			&mockResolver{err: errors.New("injected resolve error")})
+
+		resp := s.Execute(context.Background(),
+			&graphql.Request{Query: fmt.Sprintf(`{ did(id: %q) { document } }`, did)})
+		require.Len(t, resp.Errors, 1)
+		require.Contains(t, resp.Errors[0].Message, "injected resolve error")
+	})
+}
+
+func TestFieldResolvers_InvalidSource(t *testing.T) {
+	p := &graphql.ResolveParams{Source: "invalid"}
+
+	for _, resolve := range []graphql.ResolveFunc{
+		activityField(func(*vocab.ActivityType) interface{} { return nil }),
+		anchorField(func(*anchor) interface{} { return nil }),
+		previousAnchorField(func(*subject.SuffixAnchor) interface{} { return nil }),
+		didField(func(*document.ResolutionResult) interface{} { return nil }),
+		(&resolvers{}).resolveActivityAnchors,
+	} {
+		_, err := resolve(p)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unexpected source type string")
+	}
+}
+
+func execute(t *testing.T, s *graphql.Schema, query string, vars map[string]interface{}) map[string]interface{} {
+	t.Helper()
+
+	resp := s.Execute(context.Background(), &graphql.Request{Query: query, Variables: vars})
+	require.Empty(t, resp.Errors)
+
+	respBytes, err := json.Marshal(resp)
+	require.NoError(t, err)
+
+	t.Logf("Response: %s", respBytes)
+
+	result := &struct {
+		Data map[string]interface{} `json:"data"`
+	}{}
+
+	require.NoError(t, json.Unmarshal(respBytes, result))
+
+	return result.Data
+}
+
+func addToInbox(t *testing.T, apStore *memstore.Store, a *vocab.ActivityType) {
+	t.Helper()
+
+	require.NoError(t, apStore.AddActivity(a))
+	require.NoError(t, apStore.AddReference(store.Inbox, serviceIRI, a.ID().URL()))
+}
+
+func newCreate(actor *url.URL, published, hl string) *vocab.ActivityType {
+	return vocab.NewCreateActivity(
+		vocab.NewObjectProperty(vocab.WithAnchorEvent(newAnchorEventRef(hl))),
+		activityOpts(actor, published)...,
+	)
+}
+
+func newAnnounce(actor *url.URL, published string, hashlinks ...string) *vocab.ActivityType {
+	items := make([]*vocab.ObjectProperty, len(hashlinks))
+
+	for i, hl := range hashlinks {
+		items[i] = vocab.NewObjectProperty(vocab.WithAnchorEvent(newAnchorEventRef(hl)))
+	}
+
+	return vocab.NewAnnounceActivity(
+		vocab.NewObjectProperty(vocab.WithCollection(vocab.NewCollection(items))),
+		activityOpts(actor, published)...,
+	)
+}
+
+func newFollow(actor *url.URL, published string) *vocab.ActivityType {
+	return vocab.NewFollowActivity(vocab.NewObjectProperty(vocab.WithIRI(serviceIRI)),
+		activityOpts(actor, published)...,
+	)
+}
+
+func activityOpts(actor *url.URL, published string) []vocab.Opt {
+	publishedTime, err := time.Parse(time.RFC3339, published)
+	if err != nil {
+		panic(err)
+	}
+
+	return []vocab.Opt{
+		vocab.WithID(aptestutil.NewActivityID(actor)),
+		vocab.WithActor(actor),
+		vocab.WithTo(serviceIRI),
+		vocab.WithPublishedTime(&publishedTime),
+	}
+}
+
+func newAnchorEventRef(hl string) *vocab.AnchorEventType {
+	return vocab.NewAnchorEvent(vocab.WithURL(testutil.MustParseURL(hl)))
+}
+
+type mockGraph struct {
+	graph map[string]*vocab.AnchorEventType
+}
+
+func newMockGraph() *mockGraph {
+	return &mockGraph{graph: make(map[string]*vocab.AnchorEventType)}
+}
+
+func (m *mockGraph) add(t *testing.T, hl string) {
+	t.Helper()
+
+	published := time.Now()
+
+	payload := &subject.Payload{
+		CoreIndex:    coreIndex,
+		Namespace:    namespace,
+		AnchorOrigin: anchorOrigin,
+		Published:    &published,
+		PreviousAnchors: []*subject.SuffixAnchor{
+			{Suffix: createSuffix},
+			{Suffix: updateSuffix, Anchor: prevAnchor},
+		},
+	}
+
+	contentObj, err := anchorevent.BuildContentObject(payload)
+	require.NoError(t, err)
+
+	anchorEvent, err := anchorevent.BuildAnchorEvent(payload, contentObj.GeneratorID, contentObj.Payload,
+		vocab.MustMarshalToDoc(&verifiable.Credential{}))
+	require.NoError(t, err)
+
+	m.graph[hl] = anchorEvent
+}
+
+func (m *mockGraph) Read(hl string) (*vocab.AnchorEventType, error) {
+	anchorEvent, ok := m.graph[hl]
+	if !ok {
+		return nil, orberrors.ErrContentNotFound
+	}
+
+	return anchorEvent, nil
+}
+
+type mockActivityStore struct {
+	*memstore.Store
+
+	queryErr error
+	getErr   error
+}
+
+func (m *mockActivityStore) QueryReferences(refType store.ReferenceType, query *store.Criteria,
+	opts ...store.QueryOpt) (store.ReferenceIterator, error) {
+	if m.queryErr != nil {
+		return nil, m.queryErr
+	}
+
+	return m.Store.QueryReferences(refType, query, opts...)
+}
+
+func (m *mockActivityStore) GetActivity(activityID *url.URL) (*vocab.ActivityType, error) {
+	if m.getErr != nil {
+		return nil, m.getErr
+	}
+
+	return m.Store.GetActivity(activityID)
+}
+
+type mockResolver struct {
+	result *document.ResolutionResult
+	err    error
+}
+
+func (m *mockResolver) ResolveDocument(string) (*document.ResolutionResult, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	return m.result, nil
+}
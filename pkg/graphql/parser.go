@@ -0,0 +1,496 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+)
+
+const (
+	operationQuery        = "query"
+	operationMutation     = "mutation"
+	operationSubscription = "subscription"
+)
+
+// document is a parsed GraphQL query document.
+type document struct {
+	operations []*operation
+}
+
+// operation is a single operation (query, mutation or subscription) within a document.
+type operation struct {
+	opType       string
+	name         string
+	varDefs      []*varDef
+	selectionSet []*field
+}
+
+// varDef is a variable definition of an operation, e.g. "$first: Int = 10".
+type varDef struct {
+	name         string
+	typ          string
+	required     bool
+	defaultValue interface{}
+}
+
+// field is a field selection, e.g. "alias: name(arg: value) { ... }".
+type field struct {
+	alias        string
+	name         string
+	args         []*argument
+	selectionSet []*field
+}
+
+// responseKey returns the key of the field in the response, which is the alias if one was provided.
+func (f *field) responseKey() string {
+	if f.alias != "" {
+		return f.alias
+	}
+
+	return f.name
+}
+
+type argument struct {
+	name  string
+	value interface{}
+}
+
+// variable is a reference to a variable in an argument value.
+type variable string
+
+// enumValue is an enum literal in an argument value.
+type enumValue string
+
+// parser parses the subset of the GraphQL query language that's supported by the executor. Fragments and
+// directives are not supported.
+type parser struct {
+	lexer *lexer
+	tok   token
+}
+
+func parse(query string) (*document, error) {
+	p := &parser{lexer: newLexer(query)}
+
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	doc := &document{}
+
+	for p.tok.kind != tokenEOF {
+		op, err := p.parseOperation()
+		if err != nil {
+			return nil, err
+		}
+
+		doc.operations = append(doc.operations, op)
+	}
+
+	if len(doc.operations) == 0 {
+		return nil, fmt.Errorf("query document does not contain any operations")
+	}
+
+	return doc, nil
+}
+
+func (p *parser) advance() error {
+	tok, err := p.lexer.next()
+	if err != nil {
+		return err
+	}
+
+	p.tok = tok
+
+	return nil
+}
+
+func (p *parser) peek(value string) bool {
+	return p.tok.kind == tokenPunctuator && p.tok.value == value
+}
+
+func (p *parser) expect(value string) error {
+	if !p.peek(value) {
+		return p.unexpected(fmt.Sprintf("'%s'", value))
+	}
+
+	return p.advance()
+}
+
+func (p *parser) expectName() (string, error) {
+	if p.tok.kind != tokenName {
+		return "", p.unexpected("name")
+	}
+
+	name := p.tok.value
+
+	return name, p.advance()
+}
+
+func (p *parser) unexpected(expected string) error {
+	return fmt.Errorf("syntax error: expected %s but found %s at position %d", expected, p.tok, p.tok.pos)
+}
+
+func (p *parser) parseOperation() (*operation, error) {
+	op := &operation{opType: operationQuery}
+
+	// Shorthand query, e.g. "{ field }".
+	if p.peek("{") {
+		selectionSet, err := p.parseSelectionSet()
+		if err != nil {
+			return nil, err
+		}
+
+		op.selectionSet = selectionSet
+
+		return op, nil
+	}
+
+	if p.tok.kind != tokenName {
+		return nil, p.unexpected("operation")
+	}
+
+	switch p.tok.value {
+	case operationQuery, operationMutation, operationSubscription:
+		op.opType = p.tok.value
+	case "fragment":
+		return nil, fmt.Errorf("fragments are not supported")
+	default:
+		return nil, p.unexpected("operation")
+	}
+
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	if p.tok.kind == tokenName {
+		op.name = p.tok.value
+
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.peek("(") {
+		varDefs, err := p.parseVariableDefinitions()
+		if err != nil {
+			return nil, err
+		}
+
+		op.varDefs = varDefs
+	}
+
+	if p.peek("@") {
+		return nil, fmt.Errorf("directives are not supported")
+	}
+
+	selectionSet, err := p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+
+	op.selectionSet = selectionSet
+
+	return op, nil
+}
+
+func (p *parser) parseVariableDefinitions() ([]*varDef, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+
+	var varDefs []*varDef
+
+	for !p.peek(")") {
+		if err := p.expect("$"); err != nil {
+			return nil, err
+		}
+
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+
+		if err = p.expect(":"); err != nil {
+			return nil, err
+		}
+
+		vd := &varDef{name: name}
+
+		vd.typ, err = p.parseType()
+		if err != nil {
+			return nil, err
+		}
+
+		if p.peek("!") {
+			vd.required = true
+
+			if err = p.advance(); err != nil {
+				return nil, err
+			}
+		}
+
+		if p.peek("=") {
+			if err = p.advance(); err != nil {
+				return nil, err
+			}
+
+			vd.defaultValue, err = p.parseValue(true)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		varDefs = append(varDefs, vd)
+	}
+
+	return varDefs, p.advance()
+}
+
+func (p *parser) parseType() (string, error) {
+	if !p.peek("[") {
+		return p.expectName()
+	}
+
+	if err := p.advance(); err != nil {
+		return "", err
+	}
+
+	itemType, err := p.parseType()
+	if err != nil {
+		return "", err
+	}
+
+	if p.peek("!") {
+		itemType += "!"
+
+		if err = p.advance(); err != nil {
+			return "", err
+		}
+	}
+
+	if err = p.expect("]"); err != nil {
+		return "", err
+	}
+
+	return "[" + itemType + "]", nil
+}
+
+func (p *parser) parseSelectionSet() ([]*field, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+
+	var fields []*field
+
+	for !p.peek("}") {
+		if p.peek("...") {
+			return nil, fmt.Errorf("fragments are not supported")
+		}
+
+		f, err := p.parseField()
+		if err != nil {
+			return nil, err
+		}
+
+		fields = append(fields, f)
+	}
+
+	if len(fields) == 0 {
+		return nil, p.unexpected("field")
+	}
+
+	return fields, p.advance()
+}
+
+func (p *parser) parseField() (*field, error) {
+	name, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+
+	f := &field{name: name}
+
+	if p.peek(":") {
+		if err = p.advance(); err != nil {
+			return nil, err
+		}
+
+		f.alias = name
+
+		f.name, err = p.expectName()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if p.peek("(") {
+		f.args, err = p.parseArguments()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if p.peek("@") {
+		return nil, fmt.Errorf("directives are not supported")
+	}
+
+	if p.peek("{") {
+		f.selectionSet, err = p.parseSelectionSet()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return f, nil
+}
+
+func (p *parser) parseArguments() ([]*argument, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+
+	var args []*argument
+
+	for !p.peek(")") {
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+
+		if err = p.expect(":"); err != nil {
+			return nil, err
+		}
+
+		value, err := p.parseValue(false)
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, &argument{name: name, value: value})
+	}
+
+	return args, p.advance()
+}
+
+// parseValue parses an argument value. Variables are not allowed if constant is true (e.g. default values).
+func (p *parser) parseValue(constant bool) (interface{}, error) { //nolint:gocyclo,cyclop
+	tok := p.tok
+
+	switch tok.kind {
+	case tokenPunctuator:
+		switch tok.value {
+		case "$":
+			if constant {
+				return nil, p.unexpected("constant value")
+			}
+
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+
+			name, err := p.expectName()
+			if err != nil {
+				return nil, err
+			}
+
+			return variable(name), nil
+		case "[":
+			return p.parseList(constant)
+		case "{":
+			return p.parseObject(constant)
+		}
+
+	case tokenInt:
+		v, err := strconv.ParseInt(tok.value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer %q: %w", tok.value, err)
+		}
+
+		return int(v), p.advance()
+
+	case tokenFloat:
+		v, err := strconv.ParseFloat(tok.value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid float %q: %w", tok.value, err)
+		}
+
+		return v, p.advance()
+
+	case tokenString:
+		return tok.value, p.advance()
+
+	case tokenName:
+		var v interface{}
+
+		switch tok.value {
+		case "true":
+			v = true
+		case "false":
+			v = false
+		case "null":
+			v = nil
+		default:
+			v = enumValue(tok.value)
+		}
+
+		return v, p.advance()
+
+	case tokenEOF:
+	}
+
+	return nil, p.unexpected("value")
+}
+
+func (p *parser) parseList(constant bool) ([]interface{}, error) {
+	if err := p.expect("["); err != nil {
+		return nil, err
+	}
+
+	values := []interface{}{}
+
+	for !p.peek("]") {
+		if p.tok.kind == tokenEOF {
+			return nil, p.unexpected("']'")
+		}
+
+		v, err := p.parseValue(constant)
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, v)
+	}
+
+	return values, p.advance()
+}
+
+func (p *parser) parseObject(constant bool) (map[string]interface{}, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]interface{})
+
+	for !p.peek("}") {
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+
+		if err = p.expect(":"); err != nil {
+			return nil, err
+		}
+
+		values[name], err = p.parseValue(constant)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return values, p.advance()
+}
@@ -0,0 +1,116 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package graphql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("Shorthand query", func(t *testing.T) {
+		doc, err := parse(`{ activities { id } }`)
+		require.NoError(t, err)
+		require.Len(t, doc.operations, 1)
+
+		op := doc.operations[0]
+		require.Equal(t, operationQuery, op.opType)
+		require.Empty(t, op.name)
+		require.Len(t, op.selectionSet, 1)
+		require.Equal(t, "activities", op.selectionSet[0].name)
+		require.Equal(t, "activities", op.selectionSet[0].responseKey())
+		require.Len(t, op.selectionSet[0].selectionSet, 1)
+		require.Equal(t, "id", op.selectionSet[0].selectionSet[0].name)
+	})
+
+	t.Run("Named query with variables", func(t *testing.T) {
+		doc, err := parse(`
+			query Announcements($actor: String!, $first: Int = 10, $ids: [String!], $obj: Obj = {a: 1}) {
+				announcements: activities(actor: $actor, type: "Announce", collection: INBOX, first: $first,
+						recent: true, old: false, none: null, ratio: 0.5, list: [1, "two"], obj: {x: 1}) {
+					id
+					anchors { hashlink }
+				}
+			}`)
+		require.NoError(t, err)
+		require.Len(t, doc.operations, 1)
+
+		op := doc.operations[0]
+		require.Equal(t, operationQuery, op.opType)
+		require.Equal(t, "Announcements", op.name)
+		require.Len(t, op.varDefs, 4)
+		require.Equal(t, &varDef{name: "actor", typ: "String", required: true}, op.varDefs[0])
+		require.Equal(t, &varDef{name: "first", typ: "Int", defaultValue: 10}, op.varDefs[1])
+		require.Equal(t, &varDef{name: "ids", typ: "[String!]"}, op.varDefs[2])
+		require.Equal(t, &varDef{name: "obj", typ: "Obj", defaultValue: map[string]interface{}{"a": 1}}, op.varDefs[3])
+
+		require.Len(t, op.selectionSet, 1)
+
+		f := op.selectionSet[0]
+		require.Equal(t, "announcements", f.alias)
+		require.Equal(t, "activities", f.name)
+		require.Equal(t, "announcements", f.responseKey())
+		require.Equal(t, []*argument{
+			{name: "actor", value: variable("actor")},
+			{name: "type", value: "Announce"},
+			{name: "collection", value: enumValue("INBOX")},
+			{name: "first", value: variable("first")},
+			{name: "recent", value: true},
+			{name: "old", value: false},
+			{name: "none", value: nil},
+			{name: "ratio", value: 0.5},
+			{name: "list", value: []interface{}{1, "two"}},
+			{name: "obj", value: map[string]interface{}{"x": 1}},
+		}, f.args)
+		require.Len(t, f.selectionSet, 2)
+		require.Equal(t, "anchors", f.selectionSet[1].name)
+		require.Len(t, f.selectionSet[1].selectionSet, 1)
+	})
+
+	t.Run("Multiple operations", func(t *testing.T) {
+		doc, err := parse(`query A { a } mutation B { b } subscription { c }`)
+		require.NoError(t, err)
+		require.Len(t, doc.operations, 3)
+		require.Equal(t, operationQuery, doc.operations[0].opType)
+		require.Equal(t, operationMutation, doc.operations[1].opType)
+		require.Equal(t, operationSubscription, doc.operations[2].opType)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		for _, query := range []struct {
+			src string
+			err string
+		}{
+			{src: "", err: "query document does not contain any operations"},
+			{src: "%", err: "unexpected character '%' at position 0"},
+			{src: "{}", err: "syntax error: expected field but found punctuator \"}\" at position 1"},
+			{src: "{ a", err: "syntax error: expected name but found end of query at position 3"},
+			{src: "1", err: "syntax error: expected operation but found integer \"1\" at position 0"},
+			{src: "query2 { a }", err: "syntax error: expected operation but found name \"query2\" at position 0"},
+			{src: "fragment F on A { a }", err: "fragments are not supported"},
+			{src: "{ a { ...F } }", err: "fragments are not supported"},
+			{src: "query @dir { a }", err: "directives are not supported"},
+			{src: "{ a @include(if: true) }", err: "directives are not supported"},
+			{src: "query ($a Int) { a }", err: "syntax error: expected ':' but found name \"Int\" at position 10"},
+			{src: "query ($a: Int = $b) { a }", err: "syntax error: expected constant value but found punctuator " +
+				"\"$\" at position 17"},
+			{src: "query ($a: [Int) { a }", err: "syntax error: expected ']' but found punctuator \")\" at " +
+				"position 15"},
+			{src: "{ a(x: ) }", err: "syntax error: expected value but found punctuator \")\" at position 7"},
+			{src: "{ a(x: [1) }", err: "syntax error: expected value but found punctuator \")\" at position 9"},
+			{src: "{ a(x: [1", err: "syntax error: expected ']' but found end of query at position 9"},
+			{src: "{ a(x: {1}) }", err: "syntax error: expected name but found integer \"1\" at position 8"},
+			{src: "{ a(x 1) }", err: "syntax error: expected ':' but found integer \"1\" at position 6"},
+			{src: "{ a: 1 }", err: "syntax error: expected name but found integer \"1\" at position 5"},
+		} {
+			_, err := parse(query.src)
+			require.Error(t, err, query.src)
+			require.Equal(t, query.err, err.Error(), query.src)
+		}
+	})
+}
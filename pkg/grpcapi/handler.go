@@ -0,0 +1,249 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package grpcapi
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/trustbloc/edge-core/pkg/log"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/trustbloc/orb/pkg/document/opvalidator"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/httpserver/auth"
+)
+
+var logger = log.New("grpc-api")
+
+const (
+	authMetadataKey       = "authorization"
+	retryAfterMetadataKey = "retry-after"
+	validatePath          = "/validate"
+
+	// These substrings are used by the Sidetree document handlers to classify errors. They are mapped to
+	// the same HTTP status codes by the REST handlers.
+	badRequestError = "bad request"
+	notFoundError   = "not found"
+)
+
+type operationProcessor interface {
+	ProcessOperation(operationBuffer []byte, protocolVersion uint64) (*document.ResolutionResult, error)
+}
+
+type documentResolver interface {
+	ResolveDocument(id string) (*document.ResolutionResult, error)
+}
+
+type tokenManager interface {
+	RequiredAuthTokens(endpoint, method string) ([]string, error)
+}
+
+type maintenanceMode interface {
+	Enabled() bool
+	RetryAfter() time.Duration
+}
+
+// Option is a handler option.
+type Option func(h *Handler)
+
+// WithAuthTokens requires the same bearer tokens for each method as the corresponding REST endpoint, i.e. a
+// POST to updatePath for Submit, a POST to updatePath/validate for Validate and a GET to resolvePath for Resolve.
+func WithAuthTokens(tm tokenManager, updatePath, resolvePath string) Option {
+	return func(h *Handler) {
+		h.verifiers = map[string]*auth.TokenVerifier{
+			submitMethod:   auth.NewTokenVerifier(tm, updatePath, http.MethodPost),
+			validateMethod: auth.NewTokenVerifier(tm, updatePath+validatePath, http.MethodPost),
+			resolveMethod:  auth.NewTokenVerifier(tm, resolvePath, http.MethodGet),
+		}
+	}
+}
+
+// WithMaintenanceMode rejects operations that are submitted while the node is in maintenance mode.
+func WithMaintenanceMode(mode maintenanceMode) Option {
+	return func(h *Handler) {
+		h.maintenanceMode = mode
+	}
+}
+
+// Handler implements the Operations gRPC service. Operations are submitted to the same operation processor and
+// DIDs are resolved by the same resolver as the REST endpoints, so operations are validated in the same way
+// regardless of the API that's used.
+type Handler struct {
+	UnimplementedOperationsServer
+
+	namespace       string
+	pc              protocol.Client
+	processor       operationProcessor
+	resolver        documentResolver
+	verifiers       map[string]*auth.TokenVerifier
+	maintenanceMode maintenanceMode
+	marshal         func(v interface{}) ([]byte, error)
+}
+
+// NewHandler returns a new Operations gRPC service handler.
+func NewHandler(namespace string, pc protocol.Client, processor operationProcessor, resolver documentResolver,
+	opts ...Option) *Handler {
+	h := &Handler{
+		namespace: namespace,
+		pc:        pc,
+		processor: processor,
+		resolver:  resolver,
+		marshal:   json.Marshal,
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+// Register registers the handler with the given gRPC server.
+func (h *Handler) Register(s grpc.ServiceRegistrar) {
+	RegisterOperationsServer(s, h)
+}
+
+// Submit submits a Sidetree operation.
+func (h *Handler) Submit(ctx context.Context, req *wrapperspb.BytesValue) (*wrapperspb.BytesValue, error) {
+	if err := h.authorize(ctx, submitMethod); err != nil {
+		return nil, err
+	}
+
+	if h.maintenanceMode != nil && h.maintenanceMode.Enabled() {
+		logger.Debugf("Rejecting operation since the node is in maintenance mode")
+
+		retryAfter := strconv.Itoa(int(math.Ceil(h.maintenanceMode.RetryAfter().Seconds())))
+
+		if err := grpc.SetHeader(ctx, metadata.Pairs(retryAfterMetadataKey, retryAfter)); err != nil {
+			logger.Debugf("Unable to set %s header: %s", retryAfterMetadataKey, err)
+		}
+
+		return nil, status.Error(codes.Unavailable, "node is in maintenance mode")
+	}
+
+	pv, err := h.pc.Current()
+	if err != nil {
+		logger.Errorf("Error getting current protocol version: %s", err)
+
+		return nil, status.Error(codes.Internal, "get current protocol version")
+	}
+
+	result, err := h.processor.ProcessOperation(req.GetValue(), pv.Protocol().GenesisTime)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	if result == nil {
+		// A result is only returned for a create operation.
+		return &wrapperspb.BytesValue{}, nil
+	}
+
+	return h.toResponse(result)
+}
+
+// Validate validates a Sidetree operation without adding it to the operation queue.
+func (h *Handler) Validate(ctx context.Context, req *wrapperspb.BytesValue) (*wrapperspb.BytesValue, error) {
+	if err := h.authorize(ctx, validateMethod); err != nil {
+		return nil, err
+	}
+
+	pv, err := h.pc.Current()
+	if err != nil {
+		logger.Errorf("Error getting current protocol version: %s", err)
+
+		return nil, status.Error(codes.Internal, "get current protocol version")
+	}
+
+	result := opvalidator.Validate(h.namespace, req.GetValue(), pv)
+
+	if !result.Valid {
+		logger.Debugf("Operation is not valid: %s", result.Error)
+	}
+
+	return h.toResponse(result)
+}
+
+// Resolve resolves a DID.
+func (h *Handler) Resolve(ctx context.Context, req *wrapperspb.StringValue) (*wrapperspb.BytesValue, error) {
+	if err := h.authorize(ctx, resolveMethod); err != nil {
+		return nil, err
+	}
+
+	if req.GetValue() == "" {
+		return nil, status.Error(codes.InvalidArgument, "DID is required")
+	}
+
+	result, err := h.resolver.ResolveDocument(req.GetValue())
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return h.toResponse(result)
+}
+
+func (h *Handler) authorize(ctx context.Context, method string) error {
+	verifier, found := h.verifiers[method]
+	if !found {
+		return nil
+	}
+
+	var authorization string
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(authMetadataKey); len(values) > 0 {
+			authorization = values[0]
+		}
+	}
+
+	if !verifier.VerifyAuthorization(authorization) {
+		return status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	return nil
+}
+
+func (h *Handler) toResponse(v interface{}) (*wrapperspb.BytesValue, error) {
+	respBytes, err := h.marshal(v)
+	if err != nil {
+		logger.Errorf("Error marshalling response: %s", err)
+
+		return nil, status.Error(codes.Internal, "marshal response")
+	}
+
+	return &wrapperspb.BytesValue{Value: respBytes}, nil
+}
+
+// toStatusError maps the given error to a gRPC status in the same way that the REST handlers map errors
+// to HTTP status codes.
+func toStatusError(err error) error {
+	switch {
+	case strings.Contains(err.Error(), badRequestError):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case strings.Contains(err.Error(), notFoundError):
+		return status.Error(codes.NotFound, err.Error())
+	case orberrors.IsTransient(err):
+		logger.Warnf("Transient error: %s", err)
+
+		return status.Error(codes.Unavailable, err.Error())
+	default:
+		logger.Errorf("Internal server error: %s", err)
+
+		return status.Error(codes.Internal, err.Error())
+	}
+}
@@ -0,0 +1,280 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package grpcapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	apmocks "github.com/trustbloc/orb/pkg/activitypub/mocks"
+	"github.com/trustbloc/orb/pkg/document/opvalidator"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/maintenance"
+	orbmocks "github.com/trustbloc/orb/pkg/mocks"
+)
+
+const (
+	namespace   = "did:orb"
+	updatePath  = "/sidetree/v1/operations"
+	resolvePath = "/sidetree/v1/identifiers/{id}"
+	did         = "did:orb:uAAA:EiDJpL-xeSE4kVgoGjaQm_OisXYZLmgGd_LIaWbBQ2sUuw"
+)
+
+func TestHandler_Submit(t *testing.T) {
+	pc := newProtocolClient(t)
+
+	t.Run("Create operation", func(t *testing.T) {
+		processor := &mockProcessor{result: newResolutionResult()}
+
+		h := NewHandler(namespace, pc, processor, &mockResolver{})
+
+		resp, err := h.Submit(context.Background(), &wrapperspb.BytesValue{Value: []byte(`{}`)})
+		require.NoError(t, err)
+
+		result := &document.ResolutionResult{}
+		require.NoError(t, json.Unmarshal(resp.Value, result))
+		require.Equal(t, did, result.Document.ID())
+	})
+
+	t.Run("Update operation", func(t *testing.T) {
+		h := NewHandler(namespace, pc, &mockProcessor{}, &mockResolver{})
+
+		resp, err := h.Submit(context.Background(), &wrapperspb.BytesValue{Value: []byte(`{}`)})
+		require.NoError(t, err)
+		require.Empty(t, resp.Value)
+	})
+
+	t.Run("Bad request", func(t *testing.T) {
+		h := NewHandler(namespace, pc, &mockProcessor{err: errors.New("bad request: invalid operation")},
+			&mockResolver{})
+
+		_, err := h.Submit(context.Background(), &wrapperspb.BytesValue{Value: []byte(`{}`)})
+		requireCode(t, codes.InvalidArgument, err)
+	})
+
+	t.Run("Transient error", func(t *testing.T) {
+		h := NewHandler(namespace, pc,
+			&mockProcessor{err: orberrors.NewTransient(errors.New("injected transient error"))}, &mockResolver{})
+
+		_, err := h.Submit(context.Background(), &wrapperspb.BytesValue{Value: []byte(`{}`)})
+		requireCode(t, codes.Unavailable, err)
+	})
+
+	t.Run("Internal error", func(t *testing.T) {
+		h := NewHandler(namespace, pc, &mockProcessor{err: errors.New("injected processor error")},
+			&mockResolver{})
+
+		_, err := h.Submit(context.Background(), &wrapperspb.BytesValue{Value: []byte(`{}`)})
+		requireCode(t, codes.Internal, err)
+	})
+
+	t.Run("Protocol client error", func(t *testing.T) {
+		h := NewHandler(namespace, &mockProtocolClient{err: errors.New("injected protocol error")},
+			&mockProcessor{}, &mockResolver{})
+
+		_, err := h.Submit(context.Background(), &wrapperspb.BytesValue{Value: []byte(`{}`)})
+		requireCode(t, codes.Internal, err)
+	})
+
+	t.Run("Maintenance mode", func(t *testing.T) {
+		mode := maintenance.New(time.Minute)
+		mode.Enable()
+
+		processor := &mockProcessor{}
+
+		h := NewHandler(namespace, pc, processor, &mockResolver{}, WithMaintenanceMode(mode))
+
+		_, err := h.Submit(context.Background(), &wrapperspb.BytesValue{Value: []byte(`{}`)})
+		requireCode(t, codes.Unavailable, err)
+		require.Zero(t, processor.numCalls)
+
+		mode.Disable()
+
+		_, err = h.Submit(context.Background(), &wrapperspb.BytesValue{Value: []byte(`{}`)})
+		require.NoError(t, err)
+		require.Equal(t, 1, processor.numCalls)
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		h := NewHandler(namespace, pc, &mockProcessor{result: newResolutionResult()}, &mockResolver{})
+		h.marshal = func(interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		_, err := h.Submit(context.Background(), &wrapperspb.BytesValue{Value: []byte(`{}`)})
+		requireCode(t, codes.Internal, err)
+	})
+}
+
+func TestHandler_Validate(t *testing.T) {
+	pc := newProtocolClient(t)
+
+	t.Run("Invalid operation", func(t *testing.T) {
+		h := NewHandler(namespace, pc, &mockProcessor{}, &mockResolver{})
+
+		resp, err := h.Validate(context.Background(), &wrapperspb.BytesValue{Value: []byte(`{"type":"create"}`)})
+		require.NoError(t, err)
+
+		result := &opvalidator.Result{}
+		require.NoError(t, json.Unmarshal(resp.Value, result))
+		require.False(t, result.Valid)
+		require.Contains(t, result.Error, "parse operation")
+	})
+
+	t.Run("Protocol client error", func(t *testing.T) {
+		h := NewHandler(namespace, &mockProtocolClient{err: errors.New("injected protocol error")},
+			&mockProcessor{}, &mockResolver{})
+
+		_, err := h.Validate(context.Background(), &wrapperspb.BytesValue{Value: []byte(`{}`)})
+		requireCode(t, codes.Internal, err)
+	})
+}
+
+func TestHandler_Resolve(t *testing.T) {
+	pc := newProtocolClient(t)
+
+	t.Run("Success", func(t *testing.T) {
+		h := NewHandler(namespace, pc, &mockProcessor{}, &mockResolver{result: newResolutionResult()})
+
+		resp, err := h.Resolve(context.Background(), &wrapperspb.StringValue{Value: did})
+		require.NoError(t, err)
+
+		result := &document.ResolutionResult{}
+		require.NoError(t, json.Unmarshal(resp.Value, result))
+		require.Equal(t, did, result.Document.ID())
+	})
+
+	t.Run("No DID", func(t *testing.T) {
+		h := NewHandler(namespace, pc, &mockProcessor{}, &mockResolver{})
+
+		_, err := h.Resolve(context.Background(), &wrapperspb.StringValue{})
+		requireCode(t, codes.InvalidArgument, err)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		h := NewHandler(namespace, pc, &mockProcessor{}, &mockResolver{err: errors.New("document not found")})
+
+		_, err := h.Resolve(context.Background(), &wrapperspb.StringValue{Value: did})
+		requireCode(t, codes.NotFound, err)
+	})
+}
+
+func TestHandler_Authorization(t *testing.T) {
+	pc := newProtocolClient(t)
+
+	tm := &apmocks.AuthTokenMgr{}
+	tm.RequiredAuthTokensReturns([]string{"admin"}, nil)
+
+	h := NewHandler(namespace, pc, &mockProcessor{}, &mockResolver{result: newResolutionResult()},
+		WithAuthTokens(tm, updatePath, resolvePath))
+
+	t.Run("No token", func(t *testing.T) {
+		_, err := h.Submit(context.Background(), &wrapperspb.BytesValue{Value: []byte(`{}`)})
+		requireCode(t, codes.Unauthenticated, err)
+
+		_, err = h.Validate(context.Background(), &wrapperspb.BytesValue{Value: []byte(`{}`)})
+		requireCode(t, codes.Unauthenticated, err)
+
+		_, err = h.Resolve(context.Background(), &wrapperspb.StringValue{Value: did})
+		requireCode(t, codes.Unauthenticated, err)
+	})
+
+	t.Run("Invalid token", func(t *testing.T) {
+		ctx := metadata.NewIncomingContext(context.Background(),
+			metadata.Pairs(authMetadataKey, "Bearer invalid"))
+
+		_, err := h.Resolve(ctx, &wrapperspb.StringValue{Value: did})
+		requireCode(t, codes.Unauthenticated, err)
+	})
+
+	t.Run("Valid token", func(t *testing.T) {
+		ctx := metadata.NewIncomingContext(context.Background(),
+			metadata.Pairs(authMetadataKey, "Bearer admin"))
+
+		_, err := h.Submit(ctx, &wrapperspb.BytesValue{Value: []byte(`{}`)})
+		require.NoError(t, err)
+
+		_, err = h.Resolve(ctx, &wrapperspb.StringValue{Value: did})
+		require.NoError(t, err)
+	})
+}
+
+func requireCode(t *testing.T, code codes.Code, err error) {
+	t.Helper()
+
+	require.Error(t, err)
+
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, code, st.Code(), st.Message())
+}
+
+func newProtocolClient(t *testing.T) protocol.Client {
+	t.Helper()
+
+	pc, err := orbmocks.NewMockProtocolClientProvider().WithAllowedOrigins([]string{"*"}).ForNamespace(namespace)
+	require.NoError(t, err)
+
+	return pc
+}
+
+func newResolutionResult() *document.ResolutionResult {
+	return &document.ResolutionResult{
+		Document: document.Document{"id": did},
+	}
+}
+
+type mockProcessor struct {
+	result   *document.ResolutionResult
+	err      error
+	numCalls int
+}
+
+func (m *mockProcessor) ProcessOperation([]byte, uint64) (*document.ResolutionResult, error) {
+	m.numCalls++
+
+	return m.result, m.err
+}
+
+type mockResolver struct {
+	result *document.ResolutionResult
+	err    error
+}
+
+func (m *mockResolver) ResolveDocument(id string) (*document.ResolutionResult, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	if m.result == nil {
+		return nil, fmt.Errorf("%s not found", id)
+	}
+
+	return m.result, nil
+}
+
+type mockProtocolClient struct {
+	err error
+}
+
+func (m *mockProtocolClient) Current() (protocol.Version, error) {
+	return nil, m.err
+}
+
+func (m *mockProtocolClient) Get(uint64) (protocol.Version, error) {
+	return nil, m.err
+}
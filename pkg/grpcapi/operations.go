@@ -0,0 +1,195 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package grpcapi
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const (
+	serviceName = "orb.operations.v1.Operations"
+
+	submitMethod   = "Submit"
+	validateMethod = "Validate"
+	resolveMethod  = "Resolve"
+)
+
+// OperationsServer is the server API for the Operations service defined in operations.proto.
+type OperationsServer interface {
+	Submit(ctx context.Context, req *wrapperspb.BytesValue) (*wrapperspb.BytesValue, error)
+	Validate(ctx context.Context, req *wrapperspb.BytesValue) (*wrapperspb.BytesValue, error)
+	Resolve(ctx context.Context, req *wrapperspb.StringValue) (*wrapperspb.BytesValue, error)
+}
+
+// UnimplementedOperationsServer may be embedded in an implementation of OperationsServer for forward
+// compatibility.
+type UnimplementedOperationsServer struct{}
+
+// Submit returns an Unimplemented error.
+func (UnimplementedOperationsServer) Submit(context.Context, *wrapperspb.BytesValue) (*wrapperspb.BytesValue, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method %s not implemented", submitMethod)
+}
+
+// Validate returns an Unimplemented error.
+func (UnimplementedOperationsServer) Validate(context.Context,
+	*wrapperspb.BytesValue) (*wrapperspb.BytesValue, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method %s not implemented", validateMethod)
+}
+
+// Resolve returns an Unimplemented error.
+func (UnimplementedOperationsServer) Resolve(context.Context,
+	*wrapperspb.StringValue) (*wrapperspb.BytesValue, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method %s not implemented", resolveMethod)
+}
+
+// RegisterOperationsServer registers the Operations service with the given gRPC server.
+func RegisterOperationsServer(s grpc.ServiceRegistrar, srv OperationsServer) {
+	s.RegisterService(&operationsServiceDesc, srv)
+}
+
+// operationsServiceDesc is the gRPC service descriptor of the Operations service defined in operations.proto.
+var operationsServiceDesc = grpc.ServiceDesc{ //nolint:gochecknoglobals
+	ServiceName: serviceName,
+	HandlerType: (*OperationsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		newMethodDesc(submitMethod, newBytesValue,
+			func(ctx context.Context, srv OperationsServer, req interface{}) (interface{}, error) {
+				in, ok := req.(*wrapperspb.BytesValue)
+				if !ok {
+					return nil, unexpectedRequestError(req)
+				}
+
+				return srv.Submit(ctx, in)
+			},
+		),
+		newMethodDesc(validateMethod, newBytesValue,
+			func(ctx context.Context, srv OperationsServer, req interface{}) (interface{}, error) {
+				in, ok := req.(*wrapperspb.BytesValue)
+				if !ok {
+					return nil, unexpectedRequestError(req)
+				}
+
+				return srv.Validate(ctx, in)
+			},
+		),
+		newMethodDesc(resolveMethod, newStringValue,
+			func(ctx context.Context, srv OperationsServer, req interface{}) (interface{}, error) {
+				in, ok := req.(*wrapperspb.StringValue)
+				if !ok {
+					return nil, unexpectedRequestError(req)
+				}
+
+				return srv.Resolve(ctx, in)
+			},
+		),
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "operations.proto",
+}
+
+type invoker func(ctx context.Context, srv OperationsServer, req interface{}) (interface{}, error)
+
+// newMethodDesc returns the descriptor of a unary method. The request is decoded into the message returned
+// by newRequest and the method is invoked through the interceptor (if any).
+func newMethodDesc(method string, newRequest func() interface{}, invoke invoker) grpc.MethodDesc {
+	return grpc.MethodDesc{
+		MethodName: method,
+		Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, //nolint:golint,revive
+			interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+			s, ok := srv.(OperationsServer)
+			if !ok {
+				return nil, status.Errorf(codes.Internal, "unexpected server type %T", srv)
+			}
+
+			in := newRequest()
+
+			if err := dec(in); err != nil {
+				return nil, err
+			}
+
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				return invoke(ctx, s, req)
+			}
+
+			if interceptor == nil {
+				return handler(ctx, in)
+			}
+
+			return interceptor(ctx, in, &grpc.UnaryServerInfo{Server: srv, FullMethod: fullMethod(method)}, handler)
+		},
+	}
+}
+
+func newBytesValue() interface{} {
+	return &wrapperspb.BytesValue{}
+}
+
+func newStringValue() interface{} {
+	return &wrapperspb.StringValue{}
+}
+
+func unexpectedRequestError(req interface{}) error {
+	return status.Errorf(codes.InvalidArgument, "unexpected request type %T", req)
+}
+
+// OperationsClient is the client API for the Operations service defined in operations.proto.
+type OperationsClient interface {
+	Submit(ctx context.Context, in *wrapperspb.BytesValue, opts ...grpc.CallOption) (*wrapperspb.BytesValue, error)
+	Validate(ctx context.Context, in *wrapperspb.BytesValue, opts ...grpc.CallOption) (*wrapperspb.BytesValue, error)
+	Resolve(ctx context.Context, in *wrapperspb.StringValue, opts ...grpc.CallOption) (*wrapperspb.BytesValue, error)
+}
+
+type operationsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+// NewOperationsClient returns a new client for the Operations service.
+func NewOperationsClient(cc grpc.ClientConnInterface) OperationsClient {
+	return &operationsClient{cc: cc}
+}
+
+func (c *operationsClient) Submit(ctx context.Context, in *wrapperspb.BytesValue,
+	opts ...grpc.CallOption) (*wrapperspb.BytesValue, error) {
+	out := &wrapperspb.BytesValue{}
+
+	if err := c.cc.Invoke(ctx, fullMethod(submitMethod), in, out, opts...); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (c *operationsClient) Validate(ctx context.Context, in *wrapperspb.BytesValue,
+	opts ...grpc.CallOption) (*wrapperspb.BytesValue, error) {
+	out := &wrapperspb.BytesValue{}
+
+	if err := c.cc.Invoke(ctx, fullMethod(validateMethod), in, out, opts...); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (c *operationsClient) Resolve(ctx context.Context, in *wrapperspb.StringValue,
+	opts ...grpc.CallOption) (*wrapperspb.BytesValue, error) {
+	out := &wrapperspb.BytesValue{}
+
+	if err := c.cc.Invoke(ctx, fullMethod(resolveMethod), in, out, opts...); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func fullMethod(method string) string {
+	return "/" + serviceName + "/" + method
+}
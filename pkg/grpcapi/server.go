@@ -0,0 +1,133 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package grpcapi
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+type service interface {
+	Register(s grpc.ServiceRegistrar)
+}
+
+// Server implements a gRPC server. If a TLS certificate and key are provided then the server only accepts
+// TLS connections. The standard gRPC health service is also registered.
+type Server struct {
+	url      string
+	certFile string
+	keyFile  string
+	services []service
+	listen   func(network, address string) (net.Listener, error)
+
+	mutex      sync.Mutex
+	grpcServer *grpc.Server
+	health     *health.Server
+}
+
+// NewServer returns a new gRPC server.
+func NewServer(url, certFile, keyFile string, services ...service) *Server {
+	return &Server{
+		url:      url,
+		certFile: certFile,
+		keyFile:  keyFile,
+		services: services,
+		listen:   net.Listen,
+	}
+}
+
+// Start starts the gRPC server in a separate Go routine.
+func (s *Server) Start() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.grpcServer != nil {
+		return fmt.Errorf("server already started")
+	}
+
+	var opts []grpc.ServerOption
+
+	if s.certFile != "" && s.keyFile != "" {
+		creds, err := credentials.NewServerTLSFromFile(s.certFile, s.keyFile)
+		if err != nil {
+			return fmt.Errorf("load TLS credentials for gRPC server on [%s]: %w", s.url, err)
+		}
+
+		opts = append(opts, grpc.Creds(creds))
+	}
+
+	lis, err := s.listen("tcp", s.url)
+	if err != nil {
+		return fmt.Errorf("listen on [%s]: %w", s.url, err)
+	}
+
+	grpcServer := grpc.NewServer(opts...)
+
+	for _, svc := range s.services {
+		svc.Register(grpcServer)
+	}
+
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+
+	s.grpcServer = grpcServer
+	s.health = healthServer
+
+	go func() {
+		logger.Infof("listening for gRPC requests on [%s]", s.url)
+
+		if e := grpcServer.Serve(lis); e != nil {
+			logger.Errorf("gRPC server on [%s] stopped with error: %s", s.url, e)
+
+			return
+		}
+
+		logger.Infof("gRPC server has stopped")
+	}()
+
+	return nil
+}
+
+// Stop stops the gRPC server. In-flight requests are allowed to complete unless the context is done first,
+// in which case the server is stopped immediately.
+func (s *Server) Stop(ctx context.Context) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.grpcServer == nil {
+		return fmt.Errorf("cannot stop gRPC server since it hasn't been started")
+	}
+
+	s.health.Shutdown()
+
+	stopped := make(chan struct{})
+
+	go func() {
+		s.grpcServer.GracefulStop()
+
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		logger.Warnf("Timed out waiting for in-flight gRPC requests to complete. Stopping the server.")
+
+		s.grpcServer.Stop()
+	}
+
+	s.grpcServer = nil
+
+	return nil
+}
@@ -0,0 +1,146 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package grpcapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestServer(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		h := NewHandler(namespace, newProtocolClient(t), &mockProcessor{},
+			&mockResolver{result: newResolutionResult()})
+
+		lis := bufconn.Listen(1024 * 1024)
+
+		s := NewServer("bufconn", "", "", h)
+		s.listen = func(string, string) (net.Listener, error) { return lis, nil }
+
+		require.NoError(t, s.Start())
+
+		err := s.Start()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "server already started")
+
+		conn, err := grpc.DialContext(context.Background(), "bufconn",
+			grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+			grpc.WithInsecure(),
+		)
+		require.NoError(t, err)
+
+		defer func() {
+			require.NoError(t, conn.Close())
+		}()
+
+		client := NewOperationsClient(conn)
+
+		resp, err := client.Resolve(context.Background(), &wrapperspb.StringValue{Value: did})
+		require.NoError(t, err)
+
+		result := &document.ResolutionResult{}
+		require.NoError(t, json.Unmarshal(resp.Value, result))
+		require.Equal(t, did, result.Document.ID())
+
+		resp, err = client.Submit(context.Background(), &wrapperspb.BytesValue{Value: []byte(`{}`)})
+		require.NoError(t, err)
+		require.Empty(t, resp.Value)
+
+		resp, err = client.Validate(context.Background(), &wrapperspb.BytesValue{Value: []byte(`{}`)})
+		require.NoError(t, err)
+		require.NotEmpty(t, resp.Value)
+
+		_, err = client.Resolve(context.Background(), &wrapperspb.StringValue{})
+		requireCode(t, codes.InvalidArgument, err)
+
+		healthResp, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+		require.NoError(t, err)
+		require.Equal(t, healthpb.HealthCheckResponse_SERVING, healthResp.Status)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		require.NoError(t, s.Stop(ctx))
+
+		err = s.Stop(ctx)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "hasn't been started")
+	})
+
+	t.Run("Unimplemented", func(t *testing.T) {
+		lis := bufconn.Listen(1024 * 1024)
+
+		s := NewServer("bufconn", "", "", &unimplementedService{})
+		s.listen = func(string, string) (net.Listener, error) { return lis, nil }
+
+		require.NoError(t, s.Start())
+
+		defer func() {
+			require.NoError(t, s.Stop(context.Background()))
+		}()
+
+		conn, err := grpc.DialContext(context.Background(), "bufconn",
+			grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+			grpc.WithInsecure(),
+		)
+		require.NoError(t, err)
+
+		defer func() {
+			require.NoError(t, conn.Close())
+		}()
+
+		client := NewOperationsClient(conn)
+
+		_, err = client.Submit(context.Background(), &wrapperspb.BytesValue{})
+		requireCode(t, codes.Unimplemented, err)
+
+		_, err = client.Validate(context.Background(), &wrapperspb.BytesValue{})
+		requireCode(t, codes.Unimplemented, err)
+
+		_, err = client.Resolve(context.Background(), &wrapperspb.StringValue{})
+		requireCode(t, codes.Unimplemented, err)
+	})
+
+	t.Run("Listen error", func(t *testing.T) {
+		errExpected := errors.New("injected listen error")
+
+		s := NewServer("localhost:0", "", "")
+		s.listen = func(string, string) (net.Listener, error) { return nil, errExpected }
+
+		err := s.Start()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errExpected.Error())
+	})
+
+	t.Run("Invalid TLS files", func(t *testing.T) {
+		s := NewServer("localhost:0", "invalid-cert.pem", "invalid-key.pem")
+
+		err := s.Start()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "load TLS credentials")
+	})
+}
+
+type unimplementedService struct {
+	UnimplementedOperationsServer
+}
+
+func (s *unimplementedService) Register(r grpc.ServiceRegistrar) {
+	RegisterOperationsServer(r, s)
+}
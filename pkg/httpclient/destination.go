@@ -0,0 +1,249 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	"github.com/trustbloc/orb/pkg/circuitbreaker"
+)
+
+var logger = log.New("httpclient")
+
+// Settings contains the timeout and retry settings of outbound HTTP requests to a class of destinations.
+type Settings struct {
+	// Timeout is the timeout of a single attempt, including reading the response body. Zero means no timeout.
+	Timeout time.Duration
+	// MaxRetries is the maximum number of times that a failed request is retried. Zero means that failed
+	// requests are not retried.
+	MaxRetries uint
+	// RetryBackoff is the time to wait before the first retry. The wait time is doubled for each subsequent retry.
+	RetryBackoff time.Duration
+}
+
+// Destination holds the timeout and retry settings of a class of destinations (for example ActivityPub
+// deliveries or KMS calls). The settings may be updated at runtime, in which case the new settings apply
+// to subsequent requests sent by all clients of the destination.
+type Destination struct {
+	name     string
+	mutex    sync.RWMutex
+	settings Settings
+}
+
+// NewDestination returns a new destination class with the given name and initial settings.
+func NewDestination(name string, settings Settings) *Destination {
+	return &Destination{
+		name:     name,
+		settings: settings,
+	}
+}
+
+// Name returns the name of the destination class.
+func (d *Destination) Name() string {
+	return d.name
+}
+
+// Settings returns the current settings of the destination class.
+func (d *Destination) Settings() Settings {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	return d.settings
+}
+
+// SetTimeout sets the timeout of a single attempt.
+func (d *Destination) SetTimeout(timeout time.Duration) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	logger.Debugf("[%s] Setting timeout to %s", d.name, timeout)
+
+	d.settings.Timeout = timeout
+}
+
+// SetMaxRetries sets the maximum number of times that a failed request is retried.
+func (d *Destination) SetMaxRetries(maxRetries uint) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	logger.Debugf("[%s] Setting max retries to %d", d.name, maxRetries)
+
+	d.settings.MaxRetries = maxRetries
+}
+
+// SetRetryBackoff sets the time to wait before the first retry.
+func (d *Destination) SetRetryBackoff(backoff time.Duration) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	logger.Debugf("[%s] Setting retry backoff to %s", d.name, backoff)
+
+	d.settings.RetryBackoff = backoff
+}
+
+// Client returns an HTTP client which sends requests using the given round tripper and applies
+// the settings of the destination class. The timeout of the client itself is not set since
+// the timeout is applied to each attempt.
+func (d *Destination) Client(next http.RoundTripper) *http.Client {
+	return &http.Client{
+		Transport: d.RoundTripper(next),
+	}
+}
+
+// RoundTripper returns a round tripper which applies the settings of the destination class to requests
+// that are sent using the given round tripper.
+func (d *Destination) RoundTripper(next http.RoundTripper) *RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &RoundTripper{
+		destination: d,
+		next:        next,
+	}
+}
+
+// RoundTripper applies the timeout of the destination class to each attempt and retries requests that
+// failed with a transport error or with status 429, 502, 503 or 504. A request with a body is retried only
+// if the body may be obtained again (i.e. GetBody is set). Requests that were rejected by a circuit
+// breaker are not retried.
+type RoundTripper struct {
+	destination *Destination
+	next        http.RoundTripper
+}
+
+// RoundTrip sends the request and retries it according to the settings of the destination class.
+func (rt *RoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	settings := rt.destination.Settings()
+	backoff := settings.RetryBackoff
+
+	for attempt := uint(0); ; attempt++ {
+		resp, err := rt.roundTrip(req, settings.Timeout)
+
+		if attempt >= settings.MaxRetries || !isRetryable(req, resp, err) {
+			return resp, err
+		}
+
+		if resp != nil {
+			// Drain the body so that the connection may be reused.
+			_, _ = io.Copy(ioutil.Discard, resp.Body) //nolint:errcheck
+			_ = resp.Body.Close()                     //nolint:errcheck
+
+			logger.Debugf("[%s] Request to %s returned status %d. Retrying in %s (attempt %d of %d)",
+				rt.destination.name, req.URL, resp.StatusCode, backoff, attempt+1, settings.MaxRetries)
+		} else {
+			logger.Debugf("[%s] Request to %s failed. Retrying in %s (attempt %d of %d): %s",
+				rt.destination.name, req.URL, backoff, attempt+1, settings.MaxRetries, err)
+		}
+
+		if e := wait(req.Context(), backoff); e != nil {
+			return nil, e
+		}
+
+		backoff *= 2
+
+		req, err = rewind(req)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (rt *RoundTripper) roundTrip(req *http.Request, timeout time.Duration) (*http.Response, error) {
+	if timeout <= 0 {
+		return rt.next.RoundTrip(req)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+
+	resp, err := rt.next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+
+		return nil, err
+	}
+
+	// The context is cancelled when the response body is closed.
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+
+	return resp, nil
+}
+
+func isRetryable(req *http.Request, resp *http.Response, err error) bool {
+	if req.Context().Err() != nil {
+		// The caller cancelled the request or its deadline was exceeded.
+		return false
+	}
+
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+
+	if err != nil {
+		return !errors.Is(err, circuitbreaker.ErrOpen)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+func rewind(req *http.Request) (*http.Request, error) {
+	if req.GetBody == nil {
+		return req, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, fmt.Errorf("get request body for retry: %w", err)
+	}
+
+	r := req.Clone(req.Context())
+	r.Body = body
+
+	return r, nil
+}
+
+func wait(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+type cancelOnClose struct {
+	io.ReadCloser
+
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	defer c.cancel()
+
+	return c.ReadCloser.Close()
+}
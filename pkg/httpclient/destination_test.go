@@ -0,0 +1,205 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/circuitbreaker"
+)
+
+func TestDestination(t *testing.T) {
+	d := NewDestination("kms", Settings{Timeout: time.Second})
+	require.Equal(t, "kms", d.Name())
+	require.Equal(t, Settings{Timeout: time.Second}, d.Settings())
+
+	d.SetTimeout(2 * time.Second)
+	d.SetMaxRetries(3)
+	d.SetRetryBackoff(time.Millisecond)
+
+	require.Equal(t, Settings{
+		Timeout:      2 * time.Second,
+		MaxRetries:   3,
+		RetryBackoff: time.Millisecond,
+	}, d.Settings())
+
+	require.NotNil(t, d.RoundTripper(nil))
+}
+
+func TestRoundTripper_Timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(100 * time.Millisecond)
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	d := NewDestination("activitypub", Settings{Timeout: 50 * time.Millisecond})
+
+	client := d.Client(server.Client().Transport)
+
+	resp, err := client.Get(server.URL + "/fast")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NoError(t, resp.Body.Close())
+
+	_, err = client.Get(server.URL + "/slow") //nolint:bodyclose
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "context deadline exceeded")
+
+	// Increase the timeout at runtime.
+	d.SetTimeout(time.Second)
+
+	resp, err = client.Get(server.URL + "/slow")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NoError(t, resp.Body.Close())
+}
+
+func TestRoundTripper_Retry(t *testing.T) {
+	t.Run("Retry on status", func(t *testing.T) {
+		var count int32
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := ioutil.ReadAll(r.Body)
+			require.NoError(t, err)
+			require.Equal(t, "payload", string(body))
+
+			if atomic.AddInt32(&count, 1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+
+				return
+			}
+
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		d := NewDestination("vct", Settings{MaxRetries: 3, RetryBackoff: time.Millisecond})
+
+		resp, err := d.Client(server.Client().Transport).Post(server.URL, "text/plain",
+			bytes.NewBufferString("payload"))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, int32(3), atomic.LoadInt32(&count))
+	})
+
+	t.Run("Max retries exceeded", func(t *testing.T) {
+		var count int32
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&count, 1)
+
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer server.Close()
+
+		d := NewDestination("vct", Settings{MaxRetries: 2, RetryBackoff: time.Millisecond})
+
+		resp, err := d.Client(server.Client().Transport).Get(server.URL)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadGateway, resp.StatusCode)
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, int32(3), atomic.LoadInt32(&count))
+	})
+
+	t.Run("Not retryable status", func(t *testing.T) {
+		var count int32
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&count, 1)
+
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		d := NewDestination("vct", Settings{MaxRetries: 2, RetryBackoff: time.Millisecond})
+
+		resp, err := d.Client(server.Client().Transport).Get(server.URL)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, int32(1), atomic.LoadInt32(&count))
+	})
+
+	t.Run("Transport error", func(t *testing.T) {
+		rt := &mockRoundTripper{err: errors.New("injected transport error")}
+
+		d := NewDestination("kms", Settings{MaxRetries: 2})
+
+		_, err := d.Client(rt).Get("https://kms.example.com/keys") //nolint:bodyclose
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected transport error")
+		require.Equal(t, int32(3), atomic.LoadInt32(&rt.count))
+	})
+
+	t.Run("Circuit breaker open", func(t *testing.T) {
+		rt := &mockRoundTripper{err: circuitbreaker.ErrOpen}
+
+		d := NewDestination("activitypub", Settings{MaxRetries: 2})
+
+		_, err := d.Client(rt).Get("https://orb.domain1.com/services/orb/inbox") //nolint:bodyclose
+		require.Error(t, err)
+		require.Equal(t, int32(1), atomic.LoadInt32(&rt.count))
+	})
+
+	t.Run("Body may not be replayed", func(t *testing.T) {
+		rt := &mockRoundTripper{err: errors.New("injected transport error")}
+
+		d := NewDestination("kms", Settings{MaxRetries: 2})
+
+		req, err := http.NewRequest(http.MethodPost, "https://kms.example.com/keys",
+			ioutil.NopCloser(bytes.NewBufferString("payload")))
+		require.NoError(t, err)
+		require.Nil(t, req.GetBody)
+
+		_, err = d.Client(rt).Do(req) //nolint:bodyclose
+		require.Error(t, err)
+		require.Equal(t, int32(1), atomic.LoadInt32(&rt.count))
+	})
+
+	t.Run("Context cancelled during backoff", func(t *testing.T) {
+		rt := &mockRoundTripper{err: errors.New("injected transport error")}
+
+		d := NewDestination("kms", Settings{MaxRetries: 2, RetryBackoff: time.Minute})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://kms.example.com/keys", nil)
+		require.NoError(t, err)
+
+		_, err = d.Client(rt).Do(req) //nolint:bodyclose
+		require.Error(t, err)
+		require.True(t, errors.Is(err, context.DeadlineExceeded))
+		require.Equal(t, int32(1), atomic.LoadInt32(&rt.count))
+	})
+}
+
+type mockRoundTripper struct {
+	count int32
+	err   error
+}
+
+func (m *mockRoundTripper) RoundTrip(*http.Request) (*http.Response, error) {
+	atomic.AddInt32(&m.count, 1)
+
+	return nil, m.err
+}
@@ -59,8 +59,20 @@ func NewTokenVerifier(tm tokenManager, endpoint, method string) *TokenVerifier {
 	}
 }
 
+// TokenRequired returns true if a bearer token is required by the endpoint.
+func (h *TokenVerifier) TokenRequired() bool {
+	return len(h.authTokens) > 0
+}
+
 // Verify verifies that the request has the required bearer token. If not, false is returned.
 func (h *TokenVerifier) Verify(req *http.Request) bool {
+	return h.VerifyAuthorization(req.Header.Get(authHeader))
+}
+
+// VerifyAuthorization verifies that the given value of the authorization header (e.g. "Bearer <token>") contains
+// the required bearer token. If not, false is returned. This function allows for tokens to be verified for
+// requests that are not submitted over HTTP.
+func (h *TokenVerifier) VerifyAuthorization(actHdr string) bool {
 	if len(h.authTokens) == 0 {
 		// Open access.
 		logger.Debugf("[%s] No auth token required.", h.endpoint)
@@ -70,7 +82,6 @@ func (h *TokenVerifier) Verify(req *http.Request) bool {
 
 	logger.Debugf("[%s] Auth tokens required: %s", h.endpoint, h.authTokens)
 
-	actHdr := req.Header.Get(authHeader)
 	if actHdr == "" {
 		logger.Debugf("[%s] Bearer token not found in header", h.endpoint)
 
@@ -141,7 +152,7 @@ func (m *TokenManager) IsAuthRequired(endpoint, method string) (bool, error) {
 
 				return true, nil
 			}
-		case http.MethodPost:
+		case http.MethodPost, http.MethodDelete:
 			if len(def.writeTokens) > 0 {
 				logger.Debugf("[%s] Authorization token(s) required for %s: %s", endpoint, method, def.writeTokens)
 
@@ -172,7 +183,7 @@ func (m *TokenManager) RequiredAuthTokens(endpoint, method string) ([]string, er
 		switch method {
 		case http.MethodGet:
 			tokens = def.readTokens
-		case http.MethodPost:
+		case http.MethodPost, http.MethodDelete:
 			tokens = def.writeTokens
 		default:
 			return nil, fmt.Errorf("unsupported HTTP method [%s]", method)
@@ -55,6 +55,7 @@ func TestTokenVerifier(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, "/services/orb/outbox", nil)
 
 		require.False(t, v.Verify(req))
+		require.True(t, v.TokenRequired())
 	})
 
 	t.Run("GET with invalid auth token -> unauthorized", func(t *testing.T) {
@@ -77,6 +78,19 @@ func TestTokenVerifier(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, "/services/orb/outbox", nil)
 
 		require.True(t, v.Verify(req))
+		require.False(t, v.TokenRequired())
+	})
+
+	t.Run("VerifyAuthorization", func(t *testing.T) {
+		tm := &apmocks.AuthTokenMgr{}
+		tm.RequiredAuthTokensReturns([]string{"admin", "read"}, nil)
+
+		v := NewTokenVerifier(tm, http.MethodGet, "/services/orb/outbox")
+		require.NotNil(t, v)
+
+		require.True(t, v.VerifyAuthorization(tokenPrefix+"read"))
+		require.False(t, v.VerifyAuthorization(tokenPrefix+"INVALID_TOKEN"))
+		require.False(t, v.VerifyAuthorization(""))
 	})
 }
 
@@ -112,6 +126,10 @@ func TestTokenManager(t *testing.T) {
 		require.NoError(t, err)
 		require.True(t, authRequired)
 
+		authRequired, err = tm.IsAuthRequired("/services/orb/outbox", http.MethodDelete)
+		require.NoError(t, err)
+		require.True(t, authRequired)
+
 		authRequired, err = tm.IsAuthRequired("/services/orb/outbox", http.MethodGet)
 		require.NoError(t, err)
 		require.False(t, authRequired)
@@ -144,6 +162,10 @@ func TestTokenManager(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, []string{"ADMIN_TOKEN"}, requiredTokens)
 
+		requiredTokens, err = tm.RequiredAuthTokens("/services/orb/outbox", http.MethodDelete)
+		require.NoError(t, err)
+		require.Equal(t, []string{"ADMIN_TOKEN"}, requiredTokens)
+
 		requiredTokens, err = tm.RequiredAuthTokens("/services/orb/outbox", http.MethodGet)
 		require.NoError(t, err)
 		require.Empty(t, requiredTokens)
@@ -0,0 +1,129 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package httpserver
+
+import (
+	"crypto/tls"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+const defaultCertificateCheckInterval = 10 * time.Second
+
+// certificateReloader loads the TLS certificate and key from the given files and reloads them when either
+// of the files is modified, so that a rotated certificate is picked up without restarting the server.
+// The files are checked for modifications at most once per check interval during a TLS handshake.
+type certificateReloader struct {
+	certFile      string
+	keyFile       string
+	checkInterval time.Duration
+
+	mutex       sync.RWMutex
+	cert        *tls.Certificate
+	certModTime time.Time
+	keyModTime  time.Time
+	lastChecked time.Time
+}
+
+func newCertificateReloader(certFile, keyFile string, checkInterval time.Duration) (*certificateReloader, error) {
+	r := &certificateReloader{
+		certFile:      certFile,
+		keyFile:       keyFile,
+		checkInterval: checkInterval,
+	}
+
+	certModTime, keyModTime, err := r.modTimes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.load(certModTime, keyModTime); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// GetCertificate returns the current certificate. It is meant to be used as tls.Config.GetCertificate.
+func (r *certificateReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.reloadIfModified()
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return r.cert, nil
+}
+
+func (r *certificateReloader) reloadIfModified() {
+	r.mutex.Lock()
+
+	if time.Since(r.lastChecked) < r.checkInterval {
+		r.mutex.Unlock()
+
+		return
+	}
+
+	r.lastChecked = time.Now()
+
+	currentCertModTime := r.certModTime
+	currentKeyModTime := r.keyModTime
+
+	r.mutex.Unlock()
+
+	certModTime, keyModTime, err := r.modTimes()
+	if err != nil {
+		logger.Warnf("Error checking TLS certificate files for modifications: %s", err)
+
+		return
+	}
+
+	if certModTime.Equal(currentCertModTime) && keyModTime.Equal(currentKeyModTime) {
+		return
+	}
+
+	logger.Infof("TLS certificate files were modified. Reloading certificate from [%s] and key from [%s]",
+		r.certFile, r.keyFile)
+
+	if err := r.load(certModTime, keyModTime); err != nil {
+		// The certificate and key files may not have been updated at the same time. Keep using the
+		// previous certificate and try again on the next check.
+		logger.Warnf("Error reloading TLS certificate. The previous certificate will continue to be used: %s", err)
+	}
+}
+
+func (r *certificateReloader) load(certModTime, keyModTime time.Time) error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("load TLS certificate and key: %w", err)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.cert = &cert
+	r.certModTime = certModTime
+	r.keyModTime = keyModTime
+	r.lastChecked = time.Now()
+
+	return nil
+}
+
+func (r *certificateReloader) modTimes() (certModTime, keyModTime time.Time, err error) {
+	certInfo, err := os.Stat(r.certFile)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("stat TLS certificate file: %w", err)
+	}
+
+	keyInfo, err := os.Stat(r.keyFile)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("stat TLS key file: %w", err)
+	}
+
+	return certInfo.ModTime(), keyInfo.ModTime(), nil
+}
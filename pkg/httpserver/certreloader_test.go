@@ -0,0 +1,137 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package httpserver
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCertificateReloader(t *testing.T) {
+	dir := t.TempDir()
+
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+
+	writeCertificate(t, certFile, keyFile, "orb1.domain.com", time.Now().Add(-time.Minute))
+
+	t.Run("success", func(t *testing.T) {
+		r, err := newCertificateReloader(certFile, keyFile, 0)
+		require.NoError(t, err)
+
+		cert, err := r.GetCertificate(nil)
+		require.NoError(t, err)
+		require.Equal(t, "orb1.domain.com", parseCertificate(t, cert.Certificate[0]).Subject.CommonName)
+
+		// Files not modified.
+		cert2, err := r.GetCertificate(nil)
+		require.NoError(t, err)
+		require.True(t, cert == cert2)
+
+		writeCertificate(t, certFile, keyFile, "orb2.domain.com", time.Now())
+
+		cert, err = r.GetCertificate(nil)
+		require.NoError(t, err)
+		require.Equal(t, "orb2.domain.com", parseCertificate(t, cert.Certificate[0]).Subject.CommonName)
+	})
+
+	t.Run("invalid certificate -> previous certificate is used", func(t *testing.T) {
+		r, err := newCertificateReloader(certFile, keyFile, 0)
+		require.NoError(t, err)
+
+		require.NoError(t, ioutil.WriteFile(keyFile, []byte("invalid"), os.ModePerm))
+		require.NoError(t, os.Chtimes(keyFile, time.Now().Add(time.Minute), time.Now().Add(time.Minute)))
+
+		cert, err := r.GetCertificate(nil)
+		require.NoError(t, err)
+		require.NotNil(t, cert)
+	})
+
+	t.Run("check interval", func(t *testing.T) {
+		writeCertificate(t, certFile, keyFile, "orb1.domain.com", time.Now().Add(2*time.Minute))
+
+		r, err := newCertificateReloader(certFile, keyFile, time.Hour)
+		require.NoError(t, err)
+
+		writeCertificate(t, certFile, keyFile, "orb2.domain.com", time.Now().Add(3*time.Minute))
+
+		// The files are not checked again until the check interval has elapsed.
+		cert, err := r.GetCertificate(nil)
+		require.NoError(t, err)
+		require.Equal(t, "orb1.domain.com", parseCertificate(t, cert.Certificate[0]).Subject.CommonName)
+	})
+
+	t.Run("file not found", func(t *testing.T) {
+		_, err := newCertificateReloader(filepath.Join(dir, "missing.crt"), keyFile, 0)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "stat TLS certificate file")
+
+		_, err = newCertificateReloader(certFile, filepath.Join(dir, "missing.key"), 0)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "stat TLS key file")
+	})
+}
+
+func TestServer_StartTLSError(t *testing.T) {
+	dir := t.TempDir()
+
+	s := New(url, filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key"))
+
+	err := s.Start()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "stat TLS certificate file")
+}
+
+// writeCertificate writes a new self-signed certificate and key and sets the modification time of the files.
+func writeCertificate(t *testing.T, certFile, keyFile, commonName string, modTime time.Time) {
+	t.Helper()
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	certBytes, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	require.NoError(t, err)
+
+	keyBytes, err := x509.MarshalECPrivateKey(privateKey)
+	require.NoError(t, err)
+
+	require.NoError(t, ioutil.WriteFile(certFile,
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certBytes}), os.ModePerm))
+	require.NoError(t, ioutil.WriteFile(keyFile,
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyBytes}), os.ModePerm))
+
+	require.NoError(t, os.Chtimes(certFile, modTime, modTime))
+	require.NoError(t, os.Chtimes(keyFile, modTime, modTime))
+}
+
+func parseCertificate(t *testing.T, certBytes []byte) *x509.Certificate {
+	t.Helper()
+
+	cert, err := x509.ParseCertificate(certBytes)
+	require.NoError(t, err)
+
+	return cert
+}
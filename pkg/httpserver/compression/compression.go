@@ -0,0 +1,332 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package compression
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/trustbloc/edge-core/pkg/log"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+)
+
+var logger = log.New("httpserver")
+
+const (
+	// DefaultMinSize is the default minimum size (in bytes) of a response body that is compressed.
+	DefaultMinSize = 1024
+
+	encodingGzip    = "gzip"
+	encodingDeflate = "deflate"
+	encodingAny     = "*"
+	encodingIdent   = "identity"
+
+	acceptEncodingHeader  = "Accept-Encoding"
+	contentEncodingHeader = "Content-Encoding"
+	contentLengthHeader   = "Content-Length"
+	contentTypeHeader     = "Content-Type"
+	varyHeader            = "Vary"
+)
+
+// Config contains the response compression configuration.
+type Config struct {
+	// MinSize is the minimum size (in bytes) of a response body that is compressed. Smaller responses
+	// are sent uncompressed since the savings don't justify the overhead.
+	MinSize int
+	// Level is the compression level (see compress/flate). Defaults to flate.DefaultCompression.
+	Level int
+}
+
+// HandlerWrapper wraps an existing HTTP handler and compresses the response (using gzip or deflate)
+// if the client accepts a compressed response, the response has a compressible content type (JSON or text)
+// and the response body is at least the minimum size.
+type HandlerWrapper struct {
+	common.HTTPHandler
+
+	minSize       int
+	pools         map[string]*sync.Pool
+	handleRequest common.HTTPRequestHandler
+}
+
+// NewHandlerWrapper returns a handler that compresses the response of the wrapped handler.
+func NewHandlerWrapper(handler common.HTTPHandler, cfg Config) *HandlerWrapper {
+	if cfg.MinSize <= 0 {
+		cfg.MinSize = DefaultMinSize
+	}
+
+	if cfg.Level < flate.HuffmanOnly || cfg.Level > flate.BestCompression || cfg.Level == flate.NoCompression {
+		cfg.Level = flate.DefaultCompression
+	}
+
+	level := cfg.Level
+
+	return &HandlerWrapper{
+		HTTPHandler: handler,
+		minSize:     cfg.MinSize,
+		pools: map[string]*sync.Pool{
+			encodingGzip: {New: func() interface{} {
+				// The error is only returned for an invalid level, which was validated above.
+				w, _ := gzip.NewWriterLevel(nil, level) //nolint:errcheck
+
+				return w
+			}},
+			encodingDeflate: {New: func() interface{} {
+				w, _ := flate.NewWriter(nil, level) //nolint:errcheck
+
+				return w
+			}},
+		},
+		handleRequest: handler.Handler(),
+	}
+}
+
+// Handler returns the 'wrapper' handler.
+func (h *HandlerWrapper) Handler() common.HTTPRequestHandler {
+	return func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Add(varyHeader, acceptEncodingHeader)
+
+		encoding := negotiateEncoding(req.Header.Get(acceptEncodingHeader))
+
+		if encoding == "" || req.Method == http.MethodHead || req.Header.Get("Range") != "" {
+			h.handleRequest(w, req)
+
+			return
+		}
+
+		cw := &responseWriter{
+			ResponseWriter: w,
+			encoding:       encoding,
+			pool:           h.pools[encoding],
+			minSize:        h.minSize,
+		}
+
+		defer cw.close()
+
+		h.handleRequest(cw, req)
+	}
+}
+
+type resettableWriter interface {
+	io.WriteCloser
+	Reset(w io.Writer)
+	Flush() error
+}
+
+// responseWriter buffers the response body until either the minimum size is reached (in which case the
+// response is compressed) or the handler returns (in which case the response is sent uncompressed).
+type responseWriter struct {
+	http.ResponseWriter
+
+	encoding   string
+	pool       *sync.Pool
+	minSize    int
+	status     int
+	buf        bytes.Buffer
+	decided    bool
+	compressor resettableWriter
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *responseWriter) Write(p []byte) (int, error) {
+	if w.decided {
+		return w.write(p)
+	}
+
+	w.buf.Write(p)
+
+	if w.buf.Len() < w.minSize {
+		return len(p), nil
+	}
+
+	if err := w.decide(); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// Flush sends the buffered response.
+func (w *responseWriter) Flush() {
+	if !w.decided {
+		if err := w.decide(); err != nil {
+			logger.Debugf("Error writing response: %s", err)
+
+			return
+		}
+	}
+
+	if w.compressor != nil {
+		if err := w.compressor.Flush(); err != nil {
+			logger.Debugf("Error flushing compressed response: %s", err)
+		}
+	}
+
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// decide determines whether or not the response is compressed, writes the header and the buffered body.
+func (w *responseWriter) decide() error {
+	w.decided = true
+
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	if w.buf.Len() >= w.minSize && w.isCompressible() {
+		w.Header().Set(contentEncodingHeader, w.encoding)
+		w.Header().Del(contentLengthHeader)
+
+		w.compressor = w.pool.Get().(resettableWriter)
+		w.compressor.Reset(w.ResponseWriter)
+	}
+
+	w.ResponseWriter.WriteHeader(w.status)
+
+	if w.buf.Len() == 0 {
+		return nil
+	}
+
+	_, err := w.write(w.buf.Bytes())
+
+	w.buf.Reset()
+
+	return err
+}
+
+func (w *responseWriter) write(p []byte) (int, error) {
+	if w.compressor != nil {
+		return w.compressor.Write(p)
+	}
+
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *responseWriter) close() {
+	if !w.decided {
+		if w.status == 0 && w.buf.Len() == 0 {
+			// Nothing was written by the handler.
+			return
+		}
+
+		if err := w.decide(); err != nil {
+			logger.Debugf("Error writing response: %s", err)
+
+			return
+		}
+	}
+
+	if w.compressor == nil {
+		return
+	}
+
+	if err := w.compressor.Close(); err != nil {
+		logger.Debugf("Error closing compressed response: %s", err)
+	}
+
+	w.pool.Put(w.compressor)
+}
+
+func (w *responseWriter) isCompressible() bool {
+	if w.status < http.StatusOK || w.status == http.StatusNoContent || w.status == http.StatusNotModified {
+		return false
+	}
+
+	if w.Header().Get(contentEncodingHeader) != "" {
+		// The response is already encoded.
+		return false
+	}
+
+	contentType := w.Header().Get(contentTypeHeader)
+	if contentType == "" {
+		contentType = http.DetectContentType(w.buf.Bytes())
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	return strings.HasPrefix(mediaType, "text/") ||
+		mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// negotiateEncoding returns the preferred encoding (gzip or deflate) from the given Accept-Encoding header,
+// or an empty string if the response shouldn't be compressed.
+func negotiateEncoding(acceptEncoding string) string {
+	if acceptEncoding == "" {
+		return ""
+	}
+
+	qValues := make(map[string]float64)
+
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, q := parseCoding(part)
+		if name != "" {
+			qValues[name] = q
+		}
+	}
+
+	best := ""
+	bestQ := 0.0
+
+	// Gzip is preferred over deflate if both have the same quality.
+	for _, encoding := range []string{encodingGzip, encodingDeflate} {
+		q, ok := qValues[encoding]
+		if !ok {
+			q, ok = qValues[encodingAny]
+		}
+
+		if ok && q > bestQ {
+			best = encoding
+			bestQ = q
+		}
+	}
+
+	return best
+}
+
+func parseCoding(part string) (string, float64) {
+	params := strings.Split(part, ";")
+
+	name := strings.ToLower(strings.TrimSpace(params[0]))
+	if name == "" || name == encodingIdent {
+		return "", 0
+	}
+
+	q := 1.0
+
+	for _, param := range params[1:] {
+		param = strings.TrimSpace(param)
+
+		if !strings.HasPrefix(param, "q=") {
+			continue
+		}
+
+		value, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64)
+		if err != nil {
+			return "", 0
+		}
+
+		q = value
+	}
+
+	return name, q
+}
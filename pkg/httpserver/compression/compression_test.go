@@ -0,0 +1,291 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package compression
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+)
+
+const (
+	outboxPath      = "/services/orb/outbox"
+	jsonContentType = "application/activity+json"
+)
+
+var largeJSON = `{"items":["` + strings.Repeat("https://orb.domain1.com/obj", 100) + `"]}`
+
+func TestHandlerWrapper(t *testing.T) {
+	t.Run("gzip", func(t *testing.T) {
+		w := NewHandlerWrapper(&mockHTTPHandler{contentType: jsonContentType, body: largeJSON}, Config{})
+		require.Equal(t, outboxPath, w.Path())
+		require.Equal(t, http.MethodGet, w.Method())
+
+		result := serve(w, http.MethodGet, "gzip, deflate")
+		defer func() { require.NoError(t, result.Body.Close()) }()
+
+		require.Equal(t, http.StatusOK, result.StatusCode)
+		require.Equal(t, encodingGzip, result.Header.Get(contentEncodingHeader))
+		require.Equal(t, acceptEncodingHeader, result.Header.Get(varyHeader))
+		require.Empty(t, result.Header.Get(contentLengthHeader))
+
+		r, err := gzip.NewReader(result.Body)
+		require.NoError(t, err)
+
+		body, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+		require.Equal(t, largeJSON, string(body))
+	})
+
+	t.Run("deflate", func(t *testing.T) {
+		w := NewHandlerWrapper(&mockHTTPHandler{contentType: jsonContentType, body: largeJSON},
+			Config{Level: flate.BestSpeed})
+
+		result := serve(w, http.MethodGet, "gzip;q=0.5, deflate")
+		defer func() { require.NoError(t, result.Body.Close()) }()
+
+		require.Equal(t, http.StatusOK, result.StatusCode)
+		require.Equal(t, encodingDeflate, result.Header.Get(contentEncodingHeader))
+
+		body, err := ioutil.ReadAll(flate.NewReader(result.Body))
+		require.NoError(t, err)
+		require.Equal(t, largeJSON, string(body))
+	})
+
+	t.Run("multiple writes", func(t *testing.T) {
+		w := NewHandlerWrapper(&mockHTTPHandler{contentType: jsonContentType, body: largeJSON, chunks: 10},
+			Config{MinSize: 100})
+
+		result := serve(w, http.MethodGet, "gzip")
+		defer func() { require.NoError(t, result.Body.Close()) }()
+
+		require.Equal(t, encodingGzip, result.Header.Get(contentEncodingHeader))
+
+		r, err := gzip.NewReader(result.Body)
+		require.NoError(t, err)
+
+		body, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+		require.Equal(t, largeJSON, string(body))
+	})
+
+	t.Run("below minimum size", func(t *testing.T) {
+		w := NewHandlerWrapper(&mockHTTPHandler{contentType: jsonContentType, body: `{"a":"b"}`}, Config{})
+
+		result := serve(w, http.MethodGet, "gzip")
+		defer func() { require.NoError(t, result.Body.Close()) }()
+
+		require.Empty(t, result.Header.Get(contentEncodingHeader))
+		requireBody(t, result.Body, `{"a":"b"}`)
+	})
+
+	t.Run("not compressible", func(t *testing.T) {
+		body := strings.Repeat("x", 2*DefaultMinSize)
+
+		w := NewHandlerWrapper(&mockHTTPHandler{contentType: "image/png", body: body}, Config{})
+
+		result := serve(w, http.MethodGet, "gzip")
+		defer func() { require.NoError(t, result.Body.Close()) }()
+
+		require.Empty(t, result.Header.Get(contentEncodingHeader))
+		requireBody(t, result.Body, body)
+	})
+
+	t.Run("content type detected", func(t *testing.T) {
+		body := strings.Repeat("some text ", DefaultMinSize)
+
+		w := NewHandlerWrapper(&mockHTTPHandler{body: body}, Config{})
+
+		result := serve(w, http.MethodGet, "gzip")
+		defer func() { require.NoError(t, result.Body.Close()) }()
+
+		require.Equal(t, encodingGzip, result.Header.Get(contentEncodingHeader))
+	})
+
+	t.Run("already encoded", func(t *testing.T) {
+		w := NewHandlerWrapper(&mockHTTPHandler{
+			contentType: jsonContentType, contentEncoding: "br", body: largeJSON,
+		}, Config{})
+
+		result := serve(w, http.MethodGet, "gzip")
+		defer func() { require.NoError(t, result.Body.Close()) }()
+
+		require.Equal(t, "br", result.Header.Get(contentEncodingHeader))
+		requireBody(t, result.Body, largeJSON)
+	})
+
+	t.Run("error status", func(t *testing.T) {
+		w := NewHandlerWrapper(&mockHTTPHandler{
+			contentType: jsonContentType, status: http.StatusInternalServerError, body: largeJSON,
+		}, Config{})
+
+		result := serve(w, http.MethodGet, "gzip")
+		defer func() { require.NoError(t, result.Body.Close()) }()
+
+		require.Equal(t, http.StatusInternalServerError, result.StatusCode)
+		require.Equal(t, encodingGzip, result.Header.Get(contentEncodingHeader))
+	})
+
+	t.Run("no content", func(t *testing.T) {
+		w := NewHandlerWrapper(&mockHTTPHandler{status: http.StatusNoContent}, Config{})
+
+		result := serve(w, http.MethodGet, "gzip")
+		defer func() { require.NoError(t, result.Body.Close()) }()
+
+		require.Equal(t, http.StatusNoContent, result.StatusCode)
+		require.Empty(t, result.Header.Get(contentEncodingHeader))
+	})
+
+	t.Run("no Accept-Encoding", func(t *testing.T) {
+		w := NewHandlerWrapper(&mockHTTPHandler{contentType: jsonContentType, body: largeJSON}, Config{})
+
+		result := serve(w, http.MethodGet, "")
+		defer func() { require.NoError(t, result.Body.Close()) }()
+
+		require.Empty(t, result.Header.Get(contentEncodingHeader))
+		require.Equal(t, acceptEncodingHeader, result.Header.Get(varyHeader))
+		requireBody(t, result.Body, largeJSON)
+	})
+
+	t.Run("HEAD request", func(t *testing.T) {
+		w := NewHandlerWrapper(&mockHTTPHandler{contentType: jsonContentType, body: largeJSON}, Config{})
+
+		result := serve(w, http.MethodHead, "gzip")
+		defer func() { require.NoError(t, result.Body.Close()) }()
+
+		require.Empty(t, result.Header.Get(contentEncodingHeader))
+	})
+
+	t.Run("flush", func(t *testing.T) {
+		w := NewHandlerWrapper(&mockHTTPHandler{contentType: jsonContentType, body: largeJSON, flush: true},
+			Config{})
+
+		rw := httptest.NewRecorder()
+
+		req := httptest.NewRequest(http.MethodGet, outboxPath, nil)
+		req.Header.Set(acceptEncodingHeader, "gzip")
+
+		w.Handler()(rw, req)
+
+		result := rw.Result()
+		defer func() { require.NoError(t, result.Body.Close()) }()
+
+		require.True(t, rw.Flushed)
+		require.Equal(t, encodingGzip, result.Header.Get(contentEncodingHeader))
+
+		r, err := gzip.NewReader(result.Body)
+		require.NoError(t, err)
+
+		body, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+		require.Equal(t, largeJSON, string(body))
+	})
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	require.Empty(t, negotiateEncoding(""))
+	require.Empty(t, negotiateEncoding("identity"))
+	require.Empty(t, negotiateEncoding("br"))
+	require.Empty(t, negotiateEncoding("gzip;q=0, deflate;q=0"))
+	require.Empty(t, negotiateEncoding("gzip;q=x"))
+	require.Equal(t, encodingGzip, negotiateEncoding("gzip"))
+	require.Equal(t, encodingGzip, negotiateEncoding("GZIP"))
+	require.Equal(t, encodingGzip, negotiateEncoding("deflate, gzip"))
+	require.Equal(t, encodingGzip, negotiateEncoding("*"))
+	require.Equal(t, encodingDeflate, negotiateEncoding("deflate"))
+	require.Equal(t, encodingDeflate, negotiateEncoding("gzip;q=0.2, deflate;q=0.8"))
+	require.Equal(t, encodingDeflate, negotiateEncoding("gzip;q=0, *"))
+}
+
+func serve(h common.HTTPHandler, method, acceptEncoding string) *http.Response {
+	rw := httptest.NewRecorder()
+
+	req := httptest.NewRequest(method, h.Path(), nil)
+
+	if acceptEncoding != "" {
+		req.Header.Set(acceptEncodingHeader, acceptEncoding)
+	}
+
+	h.Handler()(rw, req)
+
+	return rw.Result()
+}
+
+func requireBody(t *testing.T, r io.Reader, expected string) {
+	t.Helper()
+
+	body, err := ioutil.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, expected, string(body))
+}
+
+type mockHTTPHandler struct {
+	contentType     string
+	contentEncoding string
+	status          int
+	body            string
+	chunks          int
+	flush           bool
+}
+
+func (m *mockHTTPHandler) Path() string {
+	return outboxPath
+}
+
+func (m *mockHTTPHandler) Method() string {
+	return http.MethodGet
+}
+
+func (m *mockHTTPHandler) Handler() common.HTTPRequestHandler {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if m.contentType != "" {
+			w.Header().Set(contentTypeHeader, m.contentType)
+		}
+
+		if m.contentEncoding != "" {
+			w.Header().Set(contentEncodingHeader, m.contentEncoding)
+		}
+
+		if m.status != 0 {
+			w.WriteHeader(m.status)
+		}
+
+		if m.body == "" {
+			return
+		}
+
+		chunks := m.chunks
+		if chunks <= 0 {
+			chunks = 1
+		}
+
+		size := len(m.body)/chunks + 1
+
+		for i := 0; i < len(m.body); i += size {
+			end := i + size
+			if end > len(m.body) {
+				end = len(m.body)
+			}
+
+			if _, err := w.Write([]byte(m.body[i:end])); err != nil {
+				panic(err)
+			}
+		}
+
+		if m.flush {
+			w.(http.Flusher).Flush()
+		}
+	}
+}
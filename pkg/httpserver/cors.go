@@ -0,0 +1,131 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package httpserver
+
+import (
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/rs/cors"
+)
+
+// AllowAllOrigins may be specified in CORSPolicy.AllowedOrigins in order to allow requests from any origin.
+const AllowAllOrigins = "*"
+
+// CORSPolicy defines the CORS (Cross-Origin Resource Sharing) policy of a group of endpoints.
+type CORSPolicy struct {
+	// Name is the name of the endpoint group (used for logging).
+	Name string
+	// PathExpressions contains the expressions that match the paths of the endpoints in the group.
+	PathExpressions []*regexp.Regexp
+	// AllowedOrigins contains the origins from which cross-origin requests are allowed. AllowAllOrigins
+	// allows all origins. If empty then cross-origin requests are not allowed.
+	AllowedOrigins []string
+	// AllowedHeaders contains the headers that may be used in cross-origin requests ("*" allows all headers).
+	AllowedHeaders []string
+	// MaxAge is the amount of time for which the results of a preflight request may be cached. If zero
+	// then the Access-Control-Max-Age header isn't sent.
+	MaxAge time.Duration
+}
+
+type corsGroup struct {
+	pathExpressions []*regexp.Regexp
+	handler         http.Handler
+}
+
+// corsHandler applies the CORS policy of the first endpoint group that matches the path of the request.
+// The default policy is applied to requests that don't match any of the groups.
+type corsHandler struct {
+	groups         []*corsGroup
+	defaultHandler http.Handler
+}
+
+func newCORSHandler(policies []*CORSPolicy, handler http.Handler) http.Handler {
+	defaultHandler := cors.New(
+		cors.Options{
+			AllowedMethods: allowedMethods(),
+			AllowedHeaders: []string{"*"},
+		},
+	).Handler(handler)
+
+	if len(policies) == 0 {
+		return defaultHandler
+	}
+
+	h := &corsHandler{defaultHandler: defaultHandler}
+
+	for _, policy := range policies {
+		logger.Infof("Registering CORS policy for endpoint group [%s] - Allowed origins: %s, Allowed headers: %s, "+
+			"Max age: %s", policy.Name, policy.AllowedOrigins, policy.AllowedHeaders, policy.MaxAge)
+
+		h.groups = append(h.groups, &corsGroup{
+			pathExpressions: policy.PathExpressions,
+			handler:         cors.New(corsOptions(policy)).Handler(handler),
+		})
+	}
+
+	return h
+}
+
+func (h *corsHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	for _, g := range h.groups {
+		if g.matches(req.URL.Path) {
+			g.handler.ServeHTTP(w, req)
+
+			return
+		}
+	}
+
+	h.defaultHandler.ServeHTTP(w, req)
+}
+
+func (g *corsGroup) matches(path string) bool {
+	for _, expr := range g.pathExpressions {
+		if expr.MatchString(path) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func corsOptions(policy *CORSPolicy) cors.Options {
+	opts := cors.Options{
+		AllowedMethods: allowedMethods(),
+		AllowedHeaders: policy.AllowedHeaders,
+		MaxAge:         int(policy.MaxAge.Seconds()),
+	}
+
+	switch {
+	case len(policy.AllowedOrigins) == 0:
+		// An empty list of origins allows all origins in the CORS library, so explicitly deny all origins.
+		opts.AllowOriginFunc = func(string) bool { return false }
+	case contains(policy.AllowedOrigins, AllowAllOrigins):
+		opts.AllowedOrigins = []string{AllowAllOrigins}
+	default:
+		opts.AllowedOrigins = policy.AllowedOrigins
+	}
+
+	return opts
+}
+
+func allowedMethods() []string {
+	return []string{
+		http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete, http.MethodOptions,
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,134 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package httpserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+)
+
+const (
+	explorerOrigin = "https://explorer.example.com"
+	otherOrigin    = "https://other.example.com"
+
+	allowOriginHeader = "Access-Control-Allow-Origin"
+	maxAgeHeader      = "Access-Control-Max-Age"
+)
+
+func TestServer_CORS(t *testing.T) {
+	s := NewWithOptions(url, "", "",
+		[]common.HTTPHandler{
+			&mockPathHandler{path: "/sidetree/v1/identifiers/{id}"},
+			&mockPathHandler{path: "/services/orb"},
+			&mockPathHandler{path: "/policy"},
+		},
+		WithCORSPolicies(
+			&CORSPolicy{
+				Name:            "resolution",
+				PathExpressions: []*regexp.Regexp{regexp.MustCompile("^/sidetree/")},
+				AllowedOrigins:  []string{explorerOrigin},
+				AllowedHeaders:  []string{"Accept"},
+				MaxAge:          10 * time.Minute,
+			},
+			&CORSPolicy{
+				Name:            "federation",
+				PathExpressions: []*regexp.Regexp{regexp.MustCompile("^/services/")},
+				AllowedOrigins:  []string{explorerOrigin, AllowAllOrigins},
+			},
+			&CORSPolicy{
+				Name:            "admin",
+				PathExpressions: []*regexp.Regexp{regexp.MustCompile("^/policy$")},
+			},
+		),
+	)
+
+	t.Run("Resolution - allowed origin", func(t *testing.T) {
+		rw := preflight(s, "/sidetree/v1/identifiers/did:orb:123", explorerOrigin)
+
+		require.Equal(t, explorerOrigin, rw.Header().Get(allowOriginHeader))
+		require.Equal(t, "600", rw.Header().Get(maxAgeHeader))
+
+		req := httptest.NewRequest(http.MethodGet, "/sidetree/v1/identifiers/did:orb:123", nil)
+		req.Header.Set("Origin", explorerOrigin)
+
+		rw = httptest.NewRecorder()
+
+		s.httpServer.Handler.ServeHTTP(rw, req)
+
+		require.Equal(t, http.StatusOK, rw.Code)
+		require.Equal(t, explorerOrigin, rw.Header().Get(allowOriginHeader))
+	})
+
+	t.Run("Resolution - origin not allowed", func(t *testing.T) {
+		rw := preflight(s, "/sidetree/v1/identifiers/did:orb:123", otherOrigin)
+
+		require.Empty(t, rw.Header().Get(allowOriginHeader))
+	})
+
+	t.Run("Federation - all origins allowed", func(t *testing.T) {
+		rw := preflight(s, "/services/orb", otherOrigin)
+
+		require.Equal(t, AllowAllOrigins, rw.Header().Get(allowOriginHeader))
+		require.Empty(t, rw.Header().Get(maxAgeHeader))
+	})
+
+	t.Run("Admin - no origins allowed", func(t *testing.T) {
+		rw := preflight(s, "/policy", explorerOrigin)
+
+		require.Empty(t, rw.Header().Get(allowOriginHeader))
+	})
+
+	t.Run("No matching group -> default policy", func(t *testing.T) {
+		rw := preflight(s, healthCheckEndpoint, otherOrigin)
+
+		require.Equal(t, AllowAllOrigins, rw.Header().Get(allowOriginHeader))
+	})
+}
+
+func TestServer_DefaultCORS(t *testing.T) {
+	s := New(url, "", "", &mockPathHandler{path: "/policy"})
+
+	rw := preflight(s, "/policy", otherOrigin)
+
+	require.Equal(t, AllowAllOrigins, rw.Header().Get(allowOriginHeader))
+}
+
+func preflight(s *Server, path, origin string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodOptions, path, nil)
+	req.Header.Set("Origin", origin)
+	req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+
+	rw := httptest.NewRecorder()
+
+	s.httpServer.Handler.ServeHTTP(rw, req)
+
+	return rw
+}
+
+type mockPathHandler struct {
+	path string
+}
+
+func (h *mockPathHandler) Path() string {
+	return h.path
+}
+
+func (h *mockPathHandler) Method() string {
+	return http.MethodGet
+}
+
+func (h *mockPathHandler) Handler() common.HTTPRequestHandler {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}
+}
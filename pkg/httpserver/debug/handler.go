@@ -0,0 +1,68 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package debug
+
+import (
+	"net/http"
+	"net/http/pprof"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+)
+
+const (
+	// BasePath is the base path of all debug endpoints.
+	BasePath = "/debug"
+
+	pprofPath = BasePath + "/pprof"
+)
+
+// Handler implements a debug REST handler.
+type Handler struct {
+	path    string
+	method  string
+	handler common.HTTPRequestHandler
+}
+
+// Path returns the HTTP REST endpoint of the handler.
+func (h *Handler) Path() string {
+	return h.path
+}
+
+// Method returns the HTTP method of the handler.
+func (h *Handler) Method() string {
+	return h.method
+}
+
+// Handler returns the HTTP REST handle.
+func (h *Handler) Handler() common.HTTPRequestHandler {
+	return h.handler
+}
+
+// NewHandlers returns the handlers that expose the runtime profiling data (as provided by net/http/pprof)
+// and the runtime execution trace under /debug/pprof. The index of the available profiles is served at
+// /debug/pprof/ and a named profile (e.g. heap, goroutine, allocs, block or mutex) is served at
+// /debug/pprof/{profile}. The execution trace is served at /debug/pprof/trace.
+func NewHandlers() []common.HTTPHandler {
+	return []common.HTTPHandler{
+		newHandler(pprofPath+"/", http.MethodGet, pprof.Index),
+		newHandler(pprofPath+"/cmdline", http.MethodGet, pprof.Cmdline),
+		newHandler(pprofPath+"/profile", http.MethodGet, pprof.Profile),
+		newHandler(pprofPath+"/symbol", http.MethodGet, pprof.Symbol),
+		newHandler(pprofPath+"/symbol", http.MethodPost, pprof.Symbol),
+		newHandler(pprofPath+"/trace", http.MethodGet, pprof.Trace),
+		// The index handler serves the named profile at /debug/pprof/{profile}.
+		newHandler(pprofPath+"/{profile}", http.MethodGet, pprof.Index),
+	}
+}
+
+func newHandler(path, method string, handler common.HTTPRequestHandler) *Handler {
+	return &Handler{
+		path:    path,
+		method:  method,
+		handler: handler,
+	}
+}
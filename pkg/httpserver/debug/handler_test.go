@@ -0,0 +1,70 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package debug
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/internal/testutil/httptestutil"
+)
+
+func TestNewHandlers(t *testing.T) {
+	handlers := NewHandlers()
+	require.Len(t, handlers, 7)
+
+	paths := make(map[string]*Handler)
+
+	for _, h := range handlers {
+		handler, ok := h.(*Handler)
+		require.True(t, ok)
+
+		paths[h.Path()+" "+h.Method()] = handler
+	}
+
+	t.Run("Index", func(t *testing.T) {
+		h := paths["/debug/pprof/ GET"]
+		require.NotNil(t, h)
+
+		code, body := httptestutil.Get(t, h.Handler(), "/debug/pprof/")
+		require.Equal(t, http.StatusOK, code)
+		require.Contains(t, string(body), "goroutine")
+	})
+
+	t.Run("Named profile", func(t *testing.T) {
+		h := paths["/debug/pprof/{profile} GET"]
+		require.NotNil(t, h)
+
+		code, body := httptestutil.Get(t, h.Handler(), "/debug/pprof/goroutine?debug=1")
+		require.Equal(t, http.StatusOK, code)
+		require.Contains(t, string(body), "goroutine profile")
+	})
+
+	t.Run("Command line", func(t *testing.T) {
+		h := paths["/debug/pprof/cmdline GET"]
+		require.NotNil(t, h)
+
+		code, body := httptestutil.Get(t, h.Handler(), "/debug/pprof/cmdline")
+		require.Equal(t, http.StatusOK, code)
+		require.NotEmpty(t, body)
+	})
+
+	t.Run("Trace", func(t *testing.T) {
+		h := paths["/debug/pprof/trace GET"]
+		require.NotNil(t, h)
+
+		code, body := httptestutil.Get(t, h.Handler(), "/debug/pprof/trace?seconds=0.1")
+		require.Equal(t, http.StatusOK, code)
+		require.NotEmpty(t, body)
+	})
+
+	require.NotNil(t, paths["/debug/pprof/profile GET"])
+	require.NotNil(t, paths["/debug/pprof/symbol GET"])
+	require.NotNil(t, paths["/debug/pprof/symbol POST"])
+}
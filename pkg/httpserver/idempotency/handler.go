@@ -0,0 +1,250 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package idempotency
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+
+	"github.com/trustbloc/edge-core/pkg/log"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+)
+
+var logger = log.New("idempotency")
+
+const (
+	// KeyHeader is the request header that holds the idempotency key.
+	KeyHeader = "Idempotency-Key"
+	// ReplayedHeader is set in the response if the response was returned from the store rather than
+	// by processing the request.
+	ReplayedHeader = "Idempotent-Replayed"
+
+	// MaxKeyLength is the maximum length of an idempotency key.
+	MaxKeyLength = 255
+)
+
+type responseStore interface {
+	Get(key string) (*Response, error)
+	Put(key string, response *Response) error
+}
+
+// HandlerWrapper wraps an HTTP handler and supports the Idempotency-Key request header. The response
+// of the first request with a given key is stored and is returned for subsequent requests with the same key
+// (within the idempotency window) without invoking the wrapped handler, so that a client may safely retry a
+// request if the response was lost. Requests without the header are passed through to the wrapped handler.
+type HandlerWrapper struct {
+	common.HTTPHandler
+
+	store         responseStore
+	handleRequest common.HTTPRequestHandler
+
+	mutex    sync.Mutex
+	inFlight map[string]struct{}
+}
+
+// NewHandlerWrapper returns a handler wrapper that supports idempotency keys.
+func NewHandlerWrapper(handler common.HTTPHandler, store responseStore) *HandlerWrapper {
+	return &HandlerWrapper{
+		HTTPHandler:   handler,
+		store:         store,
+		handleRequest: handler.Handler(),
+		inFlight:      make(map[string]struct{}),
+	}
+}
+
+// Handler returns the handler that should be invoked when an HTTP request is received.
+func (h *HandlerWrapper) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *HandlerWrapper) handle(w http.ResponseWriter, req *http.Request) {
+	key := req.Header.Get(KeyHeader)
+	if key == "" {
+		h.handleRequest(w, req)
+
+		return
+	}
+
+	if len(key) > MaxKeyLength {
+		writeResponse(w, http.StatusBadRequest,
+			fmt.Sprintf("%s header must not exceed %d characters", KeyHeader, MaxKeyLength))
+
+		return
+	}
+
+	body, err := readBody(req)
+	if err != nil {
+		logger.Debugf("[%s] Error reading request body: %s", h.Path(), err)
+
+		writeResponse(w, http.StatusBadRequest, http.StatusText(http.StatusBadRequest))
+
+		return
+	}
+
+	storeKey := hash([]byte(req.URL.Path + "\n" + key))
+
+	if !h.acquire(storeKey) {
+		logger.Debugf("[%s] A request with idempotency key [%s] is already being processed", h.Path(), key)
+
+		writeResponse(w, http.StatusConflict,
+			fmt.Sprintf("A request with the same %s is already being processed", KeyHeader))
+
+		return
+	}
+
+	defer h.release(storeKey)
+
+	response, err := h.store.Get(storeKey)
+	if err == nil {
+		h.replay(w, key, hash(body), response)
+
+		return
+	}
+
+	if !errors.Is(err, ErrNotFound) {
+		logger.Errorf("[%s] Error retrieving response for idempotency key [%s]: %s", h.Path(), key, err)
+
+		writeResponse(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+
+		return
+	}
+
+	rw := &responseRecorder{ResponseWriter: w}
+
+	h.handleRequest(rw, req)
+
+	// Server errors and rate limiting responses aren't stored so that the client may retry the request.
+	if rw.statusCode() >= http.StatusInternalServerError || rw.statusCode() == http.StatusTooManyRequests {
+		return
+	}
+
+	err = h.store.Put(storeKey, &Response{
+		RequestHash: hash(body),
+		StatusCode:  rw.statusCode(),
+		Header:      rw.header,
+		Body:        rw.body.Bytes(),
+	})
+	if err != nil {
+		logger.Warnf("[%s] Error storing response for idempotency key [%s]: %s", h.Path(), key, err)
+	}
+}
+
+func (h *HandlerWrapper) replay(w http.ResponseWriter, key, requestHash string, response *Response) {
+	if response.RequestHash != requestHash {
+		logger.Debugf("[%s] Idempotency key [%s] was already used for a different request", h.Path(), key)
+
+		writeResponse(w, http.StatusUnprocessableEntity,
+			fmt.Sprintf("The %s was already used for a different request", KeyHeader))
+
+		return
+	}
+
+	logger.Debugf("[%s] Returning stored response for idempotency key [%s]", h.Path(), key)
+
+	for name, values := range response.Header {
+		w.Header()[name] = values
+	}
+
+	w.Header().Set(ReplayedHeader, "true")
+	w.WriteHeader(response.StatusCode)
+
+	if _, err := w.Write(response.Body); err != nil {
+		logger.Warnf("[%s] Unable to write response: %s", h.Path(), err)
+	}
+}
+
+func (h *HandlerWrapper) acquire(key string) bool {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if _, ok := h.inFlight[key]; ok {
+		return false
+	}
+
+	h.inFlight[key] = struct{}{}
+
+	return true
+}
+
+func (h *HandlerWrapper) release(key string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	delete(h.inFlight, key)
+}
+
+// responseRecorder writes the response to the underlying writer and also records the response
+// so that it may be stored.
+type responseRecorder struct {
+	http.ResponseWriter
+
+	status int
+	header http.Header
+	body   bytes.Buffer
+}
+
+func (w *responseRecorder) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+		w.header = w.Header().Clone()
+	}
+
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseRecorder) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	w.body.Write(p)
+
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *responseRecorder) statusCode() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+
+	return w.status
+}
+
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	return body, nil
+}
+
+func hash(data []byte) string {
+	h := sha256.Sum256(data)
+
+	return hex.EncodeToString(h[:])
+}
+
+func writeResponse(w http.ResponseWriter, status int, msg string) {
+	w.WriteHeader(status)
+
+	if _, err := w.Write([]byte(msg)); err != nil {
+		logger.Warnf("Unable to write response: %s", err)
+	}
+}
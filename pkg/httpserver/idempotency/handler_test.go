@@ -0,0 +1,260 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package idempotency
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+)
+
+const (
+	operationsPath = "/sidetree/v1/operations"
+	request1       = `{"type":"create","suffixData":{"deltaHash":"abc"}}`
+	request2       = `{"type":"create","suffixData":{"deltaHash":"xyz"}}`
+)
+
+func TestHandlerWrapper(t *testing.T) {
+	t.Run("no idempotency key", func(t *testing.T) {
+		h := &mockHTTPHandler{status: http.StatusOK, response: "ok"}
+
+		w := newHandlerWrapper(t, h)
+		require.Equal(t, operationsPath, w.Path())
+		require.Equal(t, http.MethodPost, w.Method())
+
+		requireResponse(t, serve(w, "", request1), http.StatusOK, "ok", false)
+		requireResponse(t, serve(w, "", request1), http.StatusOK, "ok", false)
+		require.Equal(t, 2, h.invocations())
+	})
+
+	t.Run("duplicate request", func(t *testing.T) {
+		h := &mockHTTPHandler{status: http.StatusOK, response: `{"id":"did:orb:123"}`}
+
+		w := newHandlerWrapper(t, h)
+
+		result := serve(w, "key1", request1)
+		requireResponse(t, result, http.StatusOK, `{"id":"did:orb:123"}`, false)
+		require.Equal(t, 1, h.invocations())
+
+		result = serve(w, "key1", request1)
+		requireResponse(t, result, http.StatusOK, `{"id":"did:orb:123"}`, true)
+		require.Equal(t, "application/did+ld+json", result.Header.Get("Content-Type"))
+		require.Equal(t, 1, h.invocations(), "the handler shouldn't be invoked for a duplicate request")
+		require.Equal(t, request1, h.body)
+
+		// A different key results in the request being processed.
+		requireResponse(t, serve(w, "key2", request1), http.StatusOK, `{"id":"did:orb:123"}`, false)
+		require.Equal(t, 2, h.invocations())
+	})
+
+	t.Run("key reused for a different request", func(t *testing.T) {
+		h := &mockHTTPHandler{status: http.StatusOK, response: "ok"}
+
+		w := newHandlerWrapper(t, h)
+
+		requireResponse(t, serve(w, "key1", request1), http.StatusOK, "ok", false)
+
+		result := serve(w, "key1", request2)
+		require.Equal(t, http.StatusUnprocessableEntity, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+		require.Equal(t, 1, h.invocations())
+	})
+
+	t.Run("client error is stored", func(t *testing.T) {
+		h := &mockHTTPHandler{status: http.StatusBadRequest, response: "invalid operation"}
+
+		w := newHandlerWrapper(t, h)
+
+		requireResponse(t, serve(w, "key1", request1), http.StatusBadRequest, "invalid operation", false)
+		requireResponse(t, serve(w, "key1", request1), http.StatusBadRequest, "invalid operation", true)
+		require.Equal(t, 1, h.invocations())
+	})
+
+	t.Run("server error isn't stored", func(t *testing.T) {
+		h := &mockHTTPHandler{status: http.StatusInternalServerError, response: "error"}
+
+		w := newHandlerWrapper(t, h)
+
+		requireResponse(t, serve(w, "key1", request1), http.StatusInternalServerError, "error", false)
+		requireResponse(t, serve(w, "key1", request1), http.StatusInternalServerError, "error", false)
+		require.Equal(t, 2, h.invocations())
+	})
+
+	t.Run("too many requests isn't stored", func(t *testing.T) {
+		h := &mockHTTPHandler{status: http.StatusTooManyRequests, response: "quota exceeded"}
+
+		w := newHandlerWrapper(t, h)
+
+		requireResponse(t, serve(w, "key1", request1), http.StatusTooManyRequests, "quota exceeded", false)
+		requireResponse(t, serve(w, "key1", request1), http.StatusTooManyRequests, "quota exceeded", false)
+		require.Equal(t, 2, h.invocations())
+	})
+
+	t.Run("key too long", func(t *testing.T) {
+		h := &mockHTTPHandler{status: http.StatusOK}
+
+		result := serve(newHandlerWrapper(t, h), strings.Repeat("x", MaxKeyLength+1), request1)
+		require.Equal(t, http.StatusBadRequest, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+		require.Zero(t, h.invocations())
+	})
+
+	t.Run("request in progress", func(t *testing.T) {
+		h := &mockHTTPHandler{status: http.StatusOK, response: "ok", delay: 500 * time.Millisecond}
+
+		w := newHandlerWrapper(t, h)
+
+		var wg sync.WaitGroup
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			requireResponse(t, serve(w, "key1", request1), http.StatusOK, "ok", false)
+		}()
+
+		require.Eventually(t, func() bool { return h.invocations() == 1 }, time.Second, 10*time.Millisecond)
+
+		result := serve(w, "key1", request1)
+		require.Equal(t, http.StatusConflict, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+
+		wg.Wait()
+
+		requireResponse(t, serve(w, "key1", request1), http.StatusOK, "ok", true)
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		h := &mockHTTPHandler{status: http.StatusOK, response: "ok"}
+
+		s := &mockStore{getErr: errors.New("injected get error")}
+
+		result := serve(NewHandlerWrapper(h, s), "key1", request1)
+		require.Equal(t, http.StatusInternalServerError, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+		require.Zero(t, h.invocations())
+
+		s = &mockStore{getErr: ErrNotFound, putErr: errors.New("injected put error")}
+
+		requireResponse(t, serve(NewHandlerWrapper(h, s), "key1", request1), http.StatusOK, "ok", false)
+		require.Equal(t, 1, h.invocations())
+	})
+}
+
+func newHandlerWrapper(t *testing.T, h common.HTTPHandler) *HandlerWrapper {
+	t.Helper()
+
+	s, err := NewStore(mem.NewProvider(), &mockExpiryService{}, time.Minute)
+	require.NoError(t, err)
+
+	return NewHandlerWrapper(h, s)
+}
+
+func serve(h common.HTTPHandler, key, body string) *http.Response {
+	rw := httptest.NewRecorder()
+
+	req := httptest.NewRequest(h.Method(), h.Path(), strings.NewReader(body))
+
+	if key != "" {
+		req.Header.Set(KeyHeader, key)
+	}
+
+	h.Handler()(rw, req)
+
+	return rw.Result()
+}
+
+func requireResponse(t *testing.T, result *http.Response, status int, body string, replayed bool) {
+	t.Helper()
+
+	defer func() {
+		require.NoError(t, result.Body.Close())
+	}()
+
+	require.Equal(t, status, result.StatusCode)
+
+	respBody, err := ioutil.ReadAll(result.Body)
+	require.NoError(t, err)
+	require.Equal(t, body, string(respBody))
+
+	if replayed {
+		require.Equal(t, "true", result.Header.Get(ReplayedHeader))
+	} else {
+		require.Empty(t, result.Header.Get(ReplayedHeader))
+	}
+}
+
+type mockHTTPHandler struct {
+	status   int
+	response string
+	delay    time.Duration
+
+	mutex sync.Mutex
+	count int
+	body  string
+}
+
+func (m *mockHTTPHandler) Path() string {
+	return operationsPath
+}
+
+func (m *mockHTTPHandler) Method() string {
+	return http.MethodPost
+}
+
+func (m *mockHTTPHandler) Handler() common.HTTPRequestHandler {
+	return func(w http.ResponseWriter, req *http.Request) {
+		body, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			panic(err)
+		}
+
+		m.mutex.Lock()
+		m.count++
+		m.body = string(body)
+		m.mutex.Unlock()
+
+		time.Sleep(m.delay)
+
+		w.Header().Set("Content-Type", "application/did+ld+json")
+		w.WriteHeader(m.status)
+
+		if _, err := w.Write([]byte(m.response)); err != nil {
+			panic(err)
+		}
+	}
+}
+
+func (m *mockHTTPHandler) invocations() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.count
+}
+
+type mockStore struct {
+	getErr error
+	putErr error
+}
+
+func (m *mockStore) Get(string) (*Response, error) {
+	return nil, m.getErr
+}
+
+func (m *mockStore) Put(string, *Response) error {
+	return m.putErr
+}
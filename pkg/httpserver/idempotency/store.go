@@ -0,0 +1,118 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package idempotency
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/store/expiry"
+)
+
+const (
+	storeName = "idempotency-key"
+
+	// expiryTag holds the time (Unix time) after which the entry is deleted.
+	expiryTag = "expiry"
+)
+
+// ErrNotFound is returned when a response isn't found for the given idempotency key.
+var ErrNotFound = errors.New("response not found for idempotency key")
+
+// Response is the response that was returned for a request with an idempotency key.
+type Response struct {
+	// RequestHash is the hash of the request body. It is used to detect whether the idempotency key
+	// was reused for a different request.
+	RequestHash string      `json:"requestHash"`
+	StatusCode  int         `json:"statusCode"`
+	Header      http.Header `json:"header,omitempty"`
+	Body        []byte      `json:"body,omitempty"`
+	ExpiresAt   time.Time   `json:"expiresAt"`
+}
+
+type expiryService interface {
+	Register(store storage.Store, expiryTagName, storeName string, opts ...expiry.Option)
+}
+
+// Store stores the responses of requests with an idempotency key. Entries are deleted by the expiry
+// service after the idempotency window.
+type Store struct {
+	store  storage.Store
+	window time.Duration
+}
+
+// NewStore returns a new idempotency key store.
+func NewStore(provider storage.Provider, expiryService expiryService, window time.Duration) (*Store, error) {
+	s, err := provider.OpenStore(storeName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open idempotency key store: %w", err)
+	}
+
+	err = provider.SetStoreConfig(storeName, storage.StoreConfiguration{TagNames: []string{expiryTag}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set store configuration: %w", err)
+	}
+
+	expiryService.Register(s, expiryTag, storeName)
+
+	return &Store{
+		store:  s,
+		window: window,
+	}, nil
+}
+
+// Put stores the response for the given key.
+func (s *Store) Put(key string, response *Response) error {
+	response.ExpiresAt = time.Now().Add(s.window)
+
+	responseBytes, err := json.Marshal(response)
+	if err != nil {
+		return fmt.Errorf("marshal response for idempotency key [%s]: %w", key, err)
+	}
+
+	err = s.store.Put(key, responseBytes,
+		storage.Tag{Name: expiryTag, Value: strconv.FormatInt(response.ExpiresAt.Unix(), 10)},
+	)
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("store response for idempotency key [%s]: %w", key, err))
+	}
+
+	return nil
+}
+
+// Get returns the response for the given key or ErrNotFound if no response was stored.
+func (s *Store) Get(key string) (*Response, error) {
+	responseBytes, err := s.store.Get(key)
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return nil, ErrNotFound
+		}
+
+		return nil, orberrors.NewTransient(fmt.Errorf("get response for idempotency key [%s]: %w", key, err))
+	}
+
+	response := &Response{}
+
+	err = json.Unmarshal(responseBytes, response)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal response for idempotency key [%s]: %w", key, err)
+	}
+
+	// The expiry service deletes expired entries periodically, so an expired entry may still be in the database.
+	if time.Now().After(response.ExpiresAt) {
+		return nil, ErrNotFound
+	}
+
+	return response, nil
+}
@@ -0,0 +1,133 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package idempotency
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/stretchr/testify/require"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/store/expiry"
+	"github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+func TestNewStore(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		es := &mockExpiryService{}
+
+		s, err := NewStore(mem.NewProvider(), es, time.Minute)
+		require.NoError(t, err)
+		require.NotNil(t, s)
+		require.Equal(t, expiryTag, es.expiryTagName)
+		require.Equal(t, storeName, es.storeName)
+	})
+
+	t.Run("open store error", func(t *testing.T) {
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(nil, errors.New("injected open error"))
+
+		_, err := NewStore(provider, &mockExpiryService{}, time.Minute)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected open error")
+	})
+
+	t.Run("set store config error", func(t *testing.T) {
+		provider := &mocks.Provider{}
+		provider.SetStoreConfigReturns(errors.New("injected config error"))
+
+		_, err := NewStore(provider, &mockExpiryService{}, time.Minute)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected config error")
+	})
+}
+
+func TestStore(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		s, err := NewStore(mem.NewProvider(), &mockExpiryService{}, time.Minute)
+		require.NoError(t, err)
+
+		_, err = s.Get("key1")
+		require.ErrorIs(t, err, ErrNotFound)
+
+		require.NoError(t, s.Put("key1", &Response{
+			RequestHash: "hash1",
+			StatusCode:  http.StatusOK,
+			Header:      http.Header{"Content-Type": []string{"application/json"}},
+			Body:        []byte(`{"id":"did:orb:123"}`),
+		}))
+
+		response, err := s.Get("key1")
+		require.NoError(t, err)
+		require.Equal(t, "hash1", response.RequestHash)
+		require.Equal(t, http.StatusOK, response.StatusCode)
+		require.Equal(t, "application/json", response.Header.Get("Content-Type"))
+		require.Equal(t, `{"id":"did:orb:123"}`, string(response.Body))
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		s, err := NewStore(mem.NewProvider(), &mockExpiryService{}, -time.Minute)
+		require.NoError(t, err)
+
+		require.NoError(t, s.Put("key1", &Response{StatusCode: http.StatusOK}))
+
+		_, err = s.Get("key1")
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("storage error", func(t *testing.T) {
+		errExpected := errors.New("injected storage error")
+
+		store := &mocks.Store{}
+		store.PutReturns(errExpected)
+		store.GetReturns(nil, errExpected)
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := NewStore(provider, &mockExpiryService{}, time.Minute)
+		require.NoError(t, err)
+
+		err = s.Put("key1", &Response{StatusCode: http.StatusOK})
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+
+		_, err = s.Get("key1")
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+	})
+
+	t.Run("unmarshal error", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.GetReturns([]byte("{"), nil)
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := NewStore(provider, &mockExpiryService{}, time.Minute)
+		require.NoError(t, err)
+
+		_, err = s.Get("key1")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal response")
+	})
+}
+
+type mockExpiryService struct {
+	expiryTagName string
+	storeName     string
+}
+
+func (m *mockExpiryService) Register(_ storage.Store, expiryTagName, storeName string, _ ...expiry.Option) {
+	m.expiryTagName = expiryTagName
+	m.storeName = storeName
+}
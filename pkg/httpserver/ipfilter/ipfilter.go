@@ -0,0 +1,205 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ipfilter
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/trustbloc/edge-core/pkg/log"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+)
+
+var logger = log.New("ipfilter")
+
+const (
+	forwardedHeader     = "Forwarded"
+	xForwardedForHeader = "X-Forwarded-For"
+)
+
+// Config holds the IP filter configuration.
+type Config struct {
+	// Allow contains the networks from which requests are allowed. If empty then requests from any network
+	// (that is not denied) are allowed.
+	Allow []*net.IPNet
+	// Deny contains the networks from which requests are denied. Deny takes precedence over Allow.
+	Deny []*net.IPNet
+	// TrustedProxies contains the networks of the proxies (e.g. load balancers) whose Forwarded and
+	// X-Forwarded-For headers are trusted when determining the IP address of the client.
+	TrustedProxies []*net.IPNet
+}
+
+// Enabled returns true if either an allow list or a deny list is configured.
+func (c *Config) Enabled() bool {
+	return len(c.Allow) > 0 || len(c.Deny) > 0
+}
+
+// IsAllowed returns true if requests from the given IP address are allowed.
+func (c *Config) IsAllowed(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+
+	if contains(c.Deny, ip) {
+		return false
+	}
+
+	return len(c.Allow) == 0 || contains(c.Allow, ip)
+}
+
+// ParseCIDRs parses the given CIDRs (e.g. 10.0.0.0/8). A single IP address may also be specified, in which
+// case the network contains only that address.
+func ParseCIDRs(values []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(values))
+
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+
+		if !strings.Contains(value, "/") {
+			ip := net.ParseIP(value)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid IP address or CIDR [%s]", value)
+			}
+
+			if ip4 := ip.To4(); ip4 != nil {
+				ip = ip4
+			}
+
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(len(ip)*8, len(ip)*8)}) //nolint:gomnd
+
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR [%s]: %w", value, err)
+		}
+
+		networks = append(networks, network)
+	}
+
+	return networks, nil
+}
+
+// ClientIP returns the IP address of the client that sent the request. If the request was received from a trusted
+// proxy then the forwarding headers (Forwarded or, if not present, X-Forwarded-For) are traversed from the most
+// recent hop backwards and the first address that isn't a trusted proxy is returned. Nil is returned if the
+// address cannot be determined.
+func ClientIP(req *http.Request, trustedProxies []*net.IPNet) net.IP {
+	ip := parseIP(req.RemoteAddr)
+	if ip == nil || !contains(trustedProxies, ip) {
+		return ip
+	}
+
+	hops := forwardedFor(req)
+
+	for i := len(hops) - 1; i >= 0; i-- {
+		ip = parseIP(hops[i])
+		if ip == nil {
+			logger.Debugf("Invalid address [%s] in forwarding header", hops[i])
+
+			return nil
+		}
+
+		if !contains(trustedProxies, ip) {
+			return ip
+		}
+	}
+
+	// All hops are trusted proxies, so the client is the first hop (or the remote address if there are no hops).
+	return ip
+}
+
+// HandlerWrapper wraps an HTTP handler and rejects requests from clients that aren't allowed by the IP filter.
+type HandlerWrapper struct {
+	common.HTTPHandler
+
+	cfg Config
+}
+
+// NewHandlerWrapper returns a handler wrapper that applies the IP filter.
+func NewHandlerWrapper(handler common.HTTPHandler, cfg Config) *HandlerWrapper {
+	return &HandlerWrapper{
+		HTTPHandler: handler,
+		cfg:         cfg,
+	}
+}
+
+// Handler returns the handler that should be invoked when an HTTP request is received.
+func (h *HandlerWrapper) Handler() common.HTTPRequestHandler {
+	return h.filter
+}
+
+func (h *HandlerWrapper) filter(w http.ResponseWriter, req *http.Request) {
+	ip := ClientIP(req, h.cfg.TrustedProxies)
+
+	if !h.cfg.IsAllowed(ip) {
+		logger.Infof("[%s] Rejecting request from client IP [%s] (remote address [%s])",
+			h.Path(), ip, req.RemoteAddr)
+
+		w.WriteHeader(http.StatusForbidden)
+
+		if _, err := w.Write([]byte(http.StatusText(http.StatusForbidden))); err != nil {
+			logger.Warnf("[%s] Unable to write response: %s", h.Path(), err)
+		}
+
+		return
+	}
+
+	h.HTTPHandler.Handler()(w, req)
+}
+
+// forwardedFor returns the addresses of the hops in the order in which they were added, i.e. the last address
+// is the one that was added by the most recent proxy.
+func forwardedFor(req *http.Request) []string {
+	var hops []string
+
+	if values := req.Header.Values(forwardedHeader); len(values) > 0 {
+		for _, value := range values {
+			for _, element := range strings.Split(value, ",") {
+				for _, pair := range strings.Split(element, ";") {
+					pair = strings.TrimSpace(pair)
+
+					if len(pair) > 4 && strings.EqualFold(pair[:4], "for=") { //nolint:gomnd
+						hops = append(hops, strings.Trim(pair[4:], `"`))
+					}
+				}
+			}
+		}
+
+		return hops
+	}
+
+	for _, value := range req.Header.Values(xForwardedForHeader) {
+		for _, hop := range strings.Split(value, ",") {
+			hops = append(hops, strings.TrimSpace(hop))
+		}
+	}
+
+	return hops
+}
+
+// parseIP parses an address which may include a port and, for IPv6, may be enclosed in brackets.
+func parseIP(address string) net.IP {
+	if host, _, err := net.SplitHostPort(address); err == nil {
+		address = host
+	}
+
+	return net.ParseIP(strings.Trim(address, "[]"))
+}
+
+func contains(networks []*net.IPNet, ip net.IP) bool {
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
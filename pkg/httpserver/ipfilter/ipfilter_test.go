@@ -0,0 +1,202 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ipfilter
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+)
+
+func TestParseCIDRs(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		networks, err := ParseCIDRs([]string{"10.0.0.0/8", " 192.168.1.10 ", "2001:db8::/32", "2001:db8::1"})
+		require.NoError(t, err)
+		require.Len(t, networks, 4)
+
+		require.Equal(t, "10.0.0.0/8", networks[0].String())
+		require.Equal(t, "192.168.1.10/32", networks[1].String())
+		require.Equal(t, "2001:db8::/32", networks[2].String())
+		require.Equal(t, "2001:db8::1/128", networks[3].String())
+	})
+
+	t.Run("Invalid IP", func(t *testing.T) {
+		_, err := ParseCIDRs([]string{"10.0.0"})
+		require.EqualError(t, err, "invalid IP address or CIDR [10.0.0]")
+	})
+
+	t.Run("Invalid CIDR", func(t *testing.T) {
+		_, err := ParseCIDRs([]string{"10.0.0.0/33"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid CIDR [10.0.0.0/33]")
+	})
+}
+
+func TestConfig_IsAllowed(t *testing.T) {
+	t.Run("Allow list", func(t *testing.T) {
+		cfg := &Config{Allow: mustParseCIDRs(t, "10.0.0.0/8")}
+		require.True(t, cfg.Enabled())
+
+		require.True(t, cfg.IsAllowed(net.ParseIP("10.1.2.3")))
+		require.False(t, cfg.IsAllowed(net.ParseIP("192.168.1.10")))
+		require.False(t, cfg.IsAllowed(nil))
+	})
+
+	t.Run("Deny list", func(t *testing.T) {
+		cfg := &Config{Deny: mustParseCIDRs(t, "192.168.0.0/16")}
+		require.True(t, cfg.Enabled())
+
+		require.True(t, cfg.IsAllowed(net.ParseIP("10.1.2.3")))
+		require.False(t, cfg.IsAllowed(net.ParseIP("192.168.1.10")))
+	})
+
+	t.Run("Deny takes precedence", func(t *testing.T) {
+		cfg := &Config{
+			Allow: mustParseCIDRs(t, "10.0.0.0/8"),
+			Deny:  mustParseCIDRs(t, "10.0.0.5"),
+		}
+
+		require.True(t, cfg.IsAllowed(net.ParseIP("10.0.0.4")))
+		require.False(t, cfg.IsAllowed(net.ParseIP("10.0.0.5")))
+	})
+
+	t.Run("Not enabled", func(t *testing.T) {
+		cfg := &Config{TrustedProxies: mustParseCIDRs(t, "10.0.0.0/8")}
+		require.False(t, cfg.Enabled())
+		require.True(t, cfg.IsAllowed(net.ParseIP("192.168.1.10")))
+	})
+}
+
+func TestClientIP(t *testing.T) {
+	trustedProxies := mustParseCIDRs(t, "10.0.0.0/8", "2001:db8::/32")
+
+	t.Run("No proxy", func(t *testing.T) {
+		req := newRequest("192.168.1.10:4711")
+		req.Header.Set(xForwardedForHeader, "1.1.1.1")
+
+		require.Equal(t, "192.168.1.10", ClientIP(req, trustedProxies).String())
+	})
+
+	t.Run("X-Forwarded-For", func(t *testing.T) {
+		req := newRequest("10.0.0.1:4711")
+		req.Header.Set(xForwardedForHeader, "1.1.1.1, 192.168.1.10, 10.0.0.2")
+
+		require.Equal(t, "192.168.1.10", ClientIP(req, trustedProxies).String())
+	})
+
+	t.Run("Multiple X-Forwarded-For headers", func(t *testing.T) {
+		req := newRequest("10.0.0.1:4711")
+		req.Header.Add(xForwardedForHeader, "192.168.1.10")
+		req.Header.Add(xForwardedForHeader, "10.0.0.2")
+
+		require.Equal(t, "192.168.1.10", ClientIP(req, trustedProxies).String())
+	})
+
+	t.Run("Forwarded", func(t *testing.T) {
+		req := newRequest("[2001:db8::2]:4711")
+		req.Header.Set(forwardedHeader,
+			`for=1.1.1.1, for="[2001:db8:cafe::17]:4711";proto=https, For="10.0.0.2:8080";by=10.0.0.3`)
+		req.Header.Set(xForwardedForHeader, "192.168.1.10")
+
+		require.Equal(t, "2001:db8:cafe::17", ClientIP(req, mustParseCIDRs(t, "10.0.0.0/8", "2001:db8::2")).String())
+	})
+
+	t.Run("All hops trusted", func(t *testing.T) {
+		req := newRequest("10.0.0.1:4711")
+		req.Header.Set(xForwardedForHeader, "10.0.0.3, 10.0.0.2")
+
+		require.Equal(t, "10.0.0.3", ClientIP(req, trustedProxies).String())
+	})
+
+	t.Run("No forwarding headers", func(t *testing.T) {
+		require.Equal(t, "10.0.0.1", ClientIP(newRequest("10.0.0.1:4711"), trustedProxies).String())
+	})
+
+	t.Run("Invalid hop", func(t *testing.T) {
+		req := newRequest("10.0.0.1:4711")
+		req.Header.Set(forwardedHeader, "for=unknown")
+
+		require.Nil(t, ClientIP(req, trustedProxies))
+	})
+
+	t.Run("Remote address without port", func(t *testing.T) {
+		require.Equal(t, "192.168.1.10", ClientIP(newRequest("192.168.1.10"), trustedProxies).String())
+	})
+}
+
+func TestHandlerWrapper(t *testing.T) {
+	cfg := Config{
+		Allow:          mustParseCIDRs(t, "192.168.0.0/16"),
+		TrustedProxies: mustParseCIDRs(t, "10.0.0.0/8"),
+	}
+
+	h := NewHandlerWrapper(&mockHandler{}, cfg)
+	require.Equal(t, "/admin", h.Path())
+	require.Equal(t, http.MethodPost, h.Method())
+
+	t.Run("Allowed", func(t *testing.T) {
+		req := newRequest("10.0.0.1:4711")
+		req.Header.Set(xForwardedForHeader, "192.168.1.10")
+
+		rw := httptest.NewRecorder()
+
+		h.Handler()(rw, req)
+
+		result := rw.Result()
+		require.Equal(t, http.StatusOK, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+
+	t.Run("Forbidden", func(t *testing.T) {
+		req := newRequest("10.0.0.1:4711")
+		req.Header.Set(xForwardedForHeader, "1.1.1.1")
+
+		rw := httptest.NewRecorder()
+
+		h.Handler()(rw, req)
+
+		result := rw.Result()
+		require.Equal(t, http.StatusForbidden, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+}
+
+func newRequest(remoteAddr string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/admin", nil)
+	req.RemoteAddr = remoteAddr
+
+	return req
+}
+
+func mustParseCIDRs(t *testing.T, values ...string) []*net.IPNet {
+	t.Helper()
+
+	networks, err := ParseCIDRs(values)
+	require.NoError(t, err)
+
+	return networks
+}
+
+type mockHandler struct{}
+
+func (m *mockHandler) Path() string {
+	return "/admin"
+}
+
+func (m *mockHandler) Method() string {
+	return http.MethodPost
+}
+
+func (m *mockHandler) Handler() common.HTTPRequestHandler {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}
+}
@@ -0,0 +1,174 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package limits
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/trustbloc/edge-core/pkg/log"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+)
+
+var logger = log.New("httpserver")
+
+const (
+	// DefaultMaxBodySize is the default maximum size (in bytes) of a request body.
+	DefaultMaxBodySize = 2 * 1024 * 1024
+	// DefaultMaxJSONDepth is the default maximum nesting depth of a JSON request body.
+	DefaultMaxJSONDepth = 32
+	// DefaultMaxJSONElements is the default maximum number of elements (keys and values) in a JSON request body.
+	DefaultMaxJSONElements = 100000
+
+	requestEntityTooLargeResponse = "Request Entity Too Large.\n"
+	badRequestResponse            = "Bad Request.\n"
+)
+
+var (
+	errMaxDepthExceeded    = errors.New("maximum JSON depth exceeded")
+	errMaxElementsExceeded = errors.New("maximum number of JSON elements exceeded")
+)
+
+// Config contains the request limits.
+type Config struct {
+	// MaxBodySize is the maximum size (in bytes) of the request body. If the request body is larger
+	// then a 413 (Request Entity Too Large) response is returned.
+	MaxBodySize int64
+	// MaxJSONDepth is the maximum nesting depth of objects and arrays in a JSON request body. If the depth is
+	// exceeded then a 400 (Bad Request) response is returned.
+	MaxJSONDepth int
+	// MaxJSONElements is the maximum number of elements (object keys, values and array elements) in a JSON
+	// request body. If the number of elements is exceeded then a 400 (Bad Request) response is returned.
+	MaxJSONElements int
+}
+
+// HandlerWrapper wraps an existing HTTP handler and enforces the request limits before the wrapped handler
+// is invoked.
+type HandlerWrapper struct {
+	common.HTTPHandler
+
+	cfg           Config
+	handleRequest common.HTTPRequestHandler
+}
+
+// NewHandlerWrapper returns a handler that first enforces the request limits and, if the request is
+// within the limits, invokes the wrapped handler.
+func NewHandlerWrapper(handler common.HTTPHandler, cfg Config) *HandlerWrapper {
+	if cfg.MaxBodySize <= 0 {
+		cfg.MaxBodySize = DefaultMaxBodySize
+	}
+
+	if cfg.MaxJSONDepth <= 0 {
+		cfg.MaxJSONDepth = DefaultMaxJSONDepth
+	}
+
+	if cfg.MaxJSONElements <= 0 {
+		cfg.MaxJSONElements = DefaultMaxJSONElements
+	}
+
+	return &HandlerWrapper{
+		HTTPHandler:   handler,
+		cfg:           cfg,
+		handleRequest: handler.Handler(),
+	}
+}
+
+// Handler returns the 'wrapper' handler.
+func (h *HandlerWrapper) Handler() common.HTTPRequestHandler {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if req.Body == nil {
+			h.handleRequest(w, req)
+
+			return
+		}
+
+		body, err := ioutil.ReadAll(http.MaxBytesReader(w, req.Body, h.cfg.MaxBodySize))
+		if err != nil {
+			// MaxBytesReader returns an error after reading the maximum number of bytes.
+			if int64(len(body)) >= h.cfg.MaxBodySize {
+				logger.Infof("[%s] Request body exceeds the maximum size of %d bytes", h.Path(), h.cfg.MaxBodySize)
+
+				writeResponse(w, http.StatusRequestEntityTooLarge, requestEntityTooLargeResponse)
+
+				return
+			}
+
+			logger.Infof("[%s] Error reading request body: %s", h.Path(), err)
+
+			writeResponse(w, http.StatusBadRequest, badRequestResponse)
+
+			return
+		}
+
+		if err := checkJSON(body, h.cfg.MaxJSONDepth, h.cfg.MaxJSONElements); err != nil {
+			logger.Infof("[%s] Invalid request body: %s", h.Path(), err)
+
+			writeResponse(w, http.StatusBadRequest, badRequestResponse)
+
+			return
+		}
+
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		h.handleRequest(w, req)
+	}
+}
+
+// checkJSON ensures that the nesting depth and the number of elements of the given JSON document are within
+// the limits. Documents which are not JSON objects or arrays are ignored, as are JSON syntax errors since
+// these are reported by the wrapped handler when the document is unmarshalled.
+func checkJSON(doc []byte, maxDepth, maxElements int) error {
+	trimmed := bytes.TrimSpace(doc)
+	if len(trimmed) == 0 || (trimmed[0] != '{' && trimmed[0] != '[') {
+		return nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(trimmed))
+
+	depth := 0
+	elements := 0
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			// Either the end of the document was reached or the document is invalid, in which case the
+			// error is reported by the wrapped handler.
+			return nil //nolint:nilerr
+		}
+
+		switch token {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+
+			if depth > maxDepth {
+				return fmt.Errorf("%w: %d", errMaxDepthExceeded, maxDepth)
+			}
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+
+			continue
+		}
+
+		elements++
+
+		if elements > maxElements {
+			return fmt.Errorf("%w: %d", errMaxElementsExceeded, maxElements)
+		}
+	}
+}
+
+func writeResponse(w http.ResponseWriter, status int, body string) {
+	w.WriteHeader(status)
+
+	if _, err := w.Write([]byte(body)); err != nil {
+		logger.Warnf("Unable to write response: %s", err)
+	}
+}
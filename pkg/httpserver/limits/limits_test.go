@@ -0,0 +1,166 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package limits
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+)
+
+const inboxPath = "/services/orb/inbox"
+
+func TestHandlerWrapper(t *testing.T) {
+	cfg := Config{MaxBodySize: 100, MaxJSONDepth: 3, MaxJSONElements: 10}
+
+	t.Run("Success", func(t *testing.T) {
+		h := &mockHTTPHandler{}
+
+		w := NewHandlerWrapper(h, cfg)
+		require.Equal(t, inboxPath, w.Path())
+		require.Equal(t, http.MethodPost, w.Method())
+
+		result := serve(w, `{"type":"Create","object":{"id":"https://orb.domain1.com/obj"}}`)
+		require.Equal(t, http.StatusOK, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+		require.Equal(t, `{"type":"Create","object":{"id":"https://orb.domain1.com/obj"}}`, h.body)
+	})
+
+	t.Run("Non-JSON body", func(t *testing.T) {
+		h := &mockHTTPHandler{}
+
+		result := serve(NewHandlerWrapper(h, cfg), "some text")
+		require.Equal(t, http.StatusOK, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+		require.Equal(t, "some text", h.body)
+	})
+
+	t.Run("No body", func(t *testing.T) {
+		h := &mockHTTPHandler{}
+
+		rw := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, inboxPath, nil)
+		req.Body = nil
+
+		NewHandlerWrapper(h, cfg).Handler()(rw, req)
+
+		result := rw.Result()
+		require.Equal(t, http.StatusOK, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+
+	t.Run("Body too large", func(t *testing.T) {
+		h := &mockHTTPHandler{}
+
+		result := serve(NewHandlerWrapper(h, cfg), strings.Repeat("x", 101))
+		require.Equal(t, http.StatusRequestEntityTooLarge, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+		require.False(t, h.invoked)
+	})
+
+	t.Run("Body read error", func(t *testing.T) {
+		h := &mockHTTPHandler{}
+
+		rw := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, inboxPath, &errReader{})
+
+		NewHandlerWrapper(h, cfg).Handler()(rw, req)
+
+		result := rw.Result()
+		require.Equal(t, http.StatusBadRequest, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+		require.False(t, h.invoked)
+	})
+
+	t.Run("JSON too deep", func(t *testing.T) {
+		h := &mockHTTPHandler{}
+
+		result := serve(NewHandlerWrapper(h, cfg), `{"a":{"b":[{"c":1}]}}`)
+		require.Equal(t, http.StatusBadRequest, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+		require.False(t, h.invoked)
+	})
+
+	t.Run("Too many JSON elements", func(t *testing.T) {
+		h := &mockHTTPHandler{}
+
+		result := serve(NewHandlerWrapper(h, cfg), `[1,2,3,4,5,6,7,8,9,10]`)
+		require.Equal(t, http.StatusBadRequest, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+		require.False(t, h.invoked)
+	})
+
+	t.Run("Defaults", func(t *testing.T) {
+		w := NewHandlerWrapper(&mockHTTPHandler{}, Config{})
+		require.Equal(t, int64(DefaultMaxBodySize), w.cfg.MaxBodySize)
+		require.Equal(t, DefaultMaxJSONDepth, w.cfg.MaxJSONDepth)
+		require.Equal(t, DefaultMaxJSONElements, w.cfg.MaxJSONElements)
+	})
+}
+
+func TestCheckJSON(t *testing.T) {
+	require.NoError(t, checkJSON([]byte(`  {"a":[1,2,{"b":"c"}]}  `), 3, 10))
+	require.NoError(t, checkJSON([]byte(`{"a":`), 3, 10), "syntax errors should be ignored")
+	require.NoError(t, checkJSON([]byte(`"a string"`), 1, 1))
+	require.NoError(t, checkJSON(nil, 1, 1))
+
+	err := checkJSON([]byte(`[[[[]]]]`), 3, 10)
+	require.True(t, errors.Is(err, errMaxDepthExceeded))
+
+	err = checkJSON([]byte(`{"a":1,"b":2,"c":3}`), 3, 5)
+	require.True(t, errors.Is(err, errMaxElementsExceeded))
+}
+
+func serve(h common.HTTPHandler, body string) *http.Response {
+	rw := httptest.NewRecorder()
+
+	h.Handler()(rw, httptest.NewRequest(h.Method(), h.Path(), strings.NewReader(body)))
+
+	return rw.Result()
+}
+
+type mockHTTPHandler struct {
+	invoked bool
+	body    string
+}
+
+func (m *mockHTTPHandler) Path() string {
+	return inboxPath
+}
+
+func (m *mockHTTPHandler) Method() string {
+	return http.MethodPost
+}
+
+func (m *mockHTTPHandler) Handler() common.HTTPRequestHandler {
+	return func(w http.ResponseWriter, req *http.Request) {
+		m.invoked = true
+
+		if req.Body != nil {
+			body, err := ioutil.ReadAll(req.Body)
+			if err != nil {
+				panic(err)
+			}
+
+			m.body = string(body)
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+type errReader struct{}
+
+func (r *errReader) Read([]byte) (int, error) {
+	return 0, errors.New("read error")
+}
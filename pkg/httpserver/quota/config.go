@@ -0,0 +1,136 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package quota
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+
+	"gopkg.in/yaml.v2"
+)
+
+// DefaultName is the name under which the usage of requests without an API key is tracked.
+const DefaultName = "default"
+
+// namePattern restricts API key names to characters that are safe to use in store keys.
+var namePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`)
+
+// Limits contains the quotas of an API key. A value of zero means that the quota is unlimited.
+type Limits struct {
+	// OperationsPerHour is the maximum number of operation requests that may be submitted per hour
+	// (UTC clock hour), including requests that are rejected by the operations endpoint.
+	OperationsPerHour int `yaml:"operationsPerHour" json:"operationsPerHour"`
+	// AnchorsPerDay is the maximum number of operations that may be accepted for anchoring per day (UTC).
+	AnchorsPerDay int `yaml:"anchorsPerDay" json:"anchorsPerDay"`
+}
+
+// APIKey contains the quotas for the requests that carry the given key.
+type APIKey struct {
+	Limits `yaml:",inline"`
+
+	// Name identifies the API key in the usage records and in the usage endpoint so that the key itself
+	// isn't exposed.
+	Name string `yaml:"name" json:"name"`
+	// Key is the value of the X-API-Key request header.
+	Key string `yaml:"key" json:"-"`
+}
+
+// Config contains the quota configuration.
+type Config struct {
+	// Default contains the quotas for requests without an API key. If not set then these requests
+	// are unlimited.
+	Default *Limits `yaml:"default"`
+	// APIKeys contains the quotas per API key.
+	APIKeys []*APIKey `yaml:"apiKeys"`
+}
+
+// LoadConfig loads and validates the quota configuration from the given YAML file.
+func LoadConfig(path string) (*Config, error) {
+	configBytes, err := ioutil.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("read quotas file [%s]: %w", path, err)
+	}
+
+	return ParseConfig(configBytes)
+}
+
+// ParseConfig parses and validates the quota configuration in the given YAML document.
+func ParseConfig(configBytes []byte) (*Config, error) {
+	cfg := &Config{}
+
+	err := yaml.UnmarshalStrict(configBytes, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("parse quotas: %w", err)
+	}
+
+	if cfg.Default == nil && len(cfg.APIKeys) == 0 {
+		return nil, errors.New("no quotas are defined")
+	}
+
+	err = cfg.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+func (c *Config) validate() error {
+	if c.Default != nil {
+		err := c.Default.validate(DefaultName)
+		if err != nil {
+			return err
+		}
+	}
+
+	names := make(map[string]struct{})
+	keys := make(map[string]struct{})
+
+	for _, k := range c.APIKeys {
+		if k == nil {
+			return errors.New("nil API key")
+		}
+
+		if !namePattern.MatchString(k.Name) || k.Name == DefaultName {
+			return fmt.Errorf("invalid API key name [%s]: the name must contain only letters, digits, '-' "+
+				"and '_' and must not be '%s'", k.Name, DefaultName)
+		}
+
+		if k.Key == "" {
+			return fmt.Errorf("API key [%s]: key must be set", k.Name)
+		}
+
+		if _, exists := names[k.Name]; exists {
+			return fmt.Errorf("duplicate API key name [%s]", k.Name)
+		}
+
+		if _, exists := keys[k.Key]; exists {
+			return fmt.Errorf("API key [%s]: the key is already used by another API key", k.Name)
+		}
+
+		names[k.Name] = struct{}{}
+		keys[k.Key] = struct{}{}
+
+		err := k.Limits.validate(k.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (l *Limits) validate(name string) error {
+	if l.OperationsPerHour < 0 || l.AnchorsPerDay < 0 {
+		return fmt.Errorf("API key [%s]: quotas must not be negative", name)
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package quota
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const quotasYAML = `
+default:
+  operationsPerHour: 10
+  anchorsPerDay: 5
+apiKeys:
+  - name: tier1
+    key: key1
+    operationsPerHour: 1000
+    anchorsPerDay: 500
+  - name: tier2
+    key: key2
+    operationsPerHour: 100
+`
+
+func TestLoadConfig(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "quotas.yaml")
+
+		require.NoError(t, ioutil.WriteFile(path, []byte(quotasYAML), 0600))
+
+		cfg, err := LoadConfig(path)
+		require.NoError(t, err)
+		require.NotNil(t, cfg.Default)
+		require.Equal(t, 10, cfg.Default.OperationsPerHour)
+		require.Equal(t, 5, cfg.Default.AnchorsPerDay)
+		require.Len(t, cfg.APIKeys, 2)
+
+		require.Equal(t, "tier1", cfg.APIKeys[0].Name)
+		require.Equal(t, "key1", cfg.APIKeys[0].Key)
+		require.Equal(t, 1000, cfg.APIKeys[0].OperationsPerHour)
+		require.Equal(t, 500, cfg.APIKeys[0].AnchorsPerDay)
+
+		require.Equal(t, "tier2", cfg.APIKeys[1].Name)
+		require.Equal(t, 100, cfg.APIKeys[1].OperationsPerHour)
+		require.Zero(t, cfg.APIKeys[1].AnchorsPerDay)
+	})
+
+	t.Run("File not found", func(t *testing.T) {
+		_, err := LoadConfig("./invalid/quotas.yaml")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "read quotas file")
+	})
+}
+
+func TestParseConfig(t *testing.T) {
+	t.Run("Default only", func(t *testing.T) {
+		cfg, err := ParseConfig([]byte("default:\n  operationsPerHour: 10\n"))
+		require.NoError(t, err)
+		require.Equal(t, 10, cfg.Default.OperationsPerHour)
+		require.Empty(t, cfg.APIKeys)
+	})
+
+	t.Run("Invalid YAML", func(t *testing.T) {
+		_, err := ParseConfig([]byte("apiKeys: [{unknown: x}]"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "parse quotas")
+	})
+
+	t.Run("No quotas", func(t *testing.T) {
+		_, err := ParseConfig([]byte("apiKeys: []"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "no quotas are defined")
+	})
+
+	t.Run("Validation errors", func(t *testing.T) {
+		tests := []struct {
+			doc string
+			err string
+		}{
+			{doc: "default:\n  operationsPerHour: -1\n", err: "quotas must not be negative"},
+			{
+				doc: "apiKeys:\n  - name: tier1\n    key: key1\n    anchorsPerDay: -1\n",
+				err: "quotas must not be negative",
+			},
+			{doc: "apiKeys:\n  - name: tier 1\n    key: key1\n", err: "invalid API key name [tier 1]"},
+			{doc: "apiKeys:\n  - name: default\n    key: key1\n", err: "invalid API key name [default]"},
+			{doc: "apiKeys:\n  - name: tier1\n", err: "API key [tier1]: key must be set"},
+			{doc: "apiKeys:\n  - null\n", err: "nil API key"},
+			{
+				doc: "apiKeys:\n  - name: tier1\n    key: key1\n  - name: tier1\n    key: key2\n",
+				err: "duplicate API key name [tier1]",
+			},
+			{
+				doc: "apiKeys:\n  - name: tier1\n    key: key1\n  - name: tier2\n    key: key1\n",
+				err: "already used by another API key",
+			},
+		}
+
+		for _, test := range tests {
+			_, err := ParseConfig([]byte(test.doc))
+			require.Error(t, err)
+			require.Contains(t, err.Error(), test.err)
+		}
+	})
+}
@@ -0,0 +1,127 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package quota
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+)
+
+const (
+	// APIKeyHeader is the request header that holds the API key.
+	APIKeyHeader = "X-API-Key"
+
+	unauthorizedResponse        = "Unknown API key.\n"
+	tooManyRequestsResponse     = "Quota exceeded.\n"
+	internalServerErrorResponse = "Internal Server Error.\n"
+)
+
+// HandlerWrapper wraps the operations handler and enforces the quotas of the API key in the X-API-Key
+// request header. If a quota is exceeded then a 429 (Too Many Requests) response is returned along with
+// a Retry-After header. Requests with an unknown API key are rejected with a 401 (Unauthorized) response.
+type HandlerWrapper struct {
+	common.HTTPHandler
+
+	mgr           *Manager
+	handleRequest common.HTTPRequestHandler
+}
+
+// NewHandlerWrapper returns a handler wrapper that enforces quotas.
+func NewHandlerWrapper(handler common.HTTPHandler, mgr *Manager) *HandlerWrapper {
+	return &HandlerWrapper{
+		HTTPHandler:   handler,
+		mgr:           mgr,
+		handleRequest: handler.Handler(),
+	}
+}
+
+// Handler returns the handler that should be invoked when an HTTP request is received.
+func (h *HandlerWrapper) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *HandlerWrapper) handle(w http.ResponseWriter, req *http.Request) {
+	name, limits, err := h.mgr.resolve(req.Header.Get(APIKeyHeader))
+	if err != nil {
+		logger.Debugf("[%s] Rejecting request: %s", h.Path(), err)
+
+		writeResponse(w, http.StatusUnauthorized, unauthorizedResponse)
+
+		return
+	}
+
+	if limits == nil {
+		h.handleRequest(w, req)
+
+		return
+	}
+
+	retryAfter, err := h.mgr.admit(name, limits)
+	if err != nil {
+		logger.Errorf("[%s] Error checking quota for API key [%s]: %s", h.Path(), name, err)
+
+		writeResponse(w, http.StatusInternalServerError, internalServerErrorResponse)
+
+		return
+	}
+
+	if retryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+
+		writeResponse(w, http.StatusTooManyRequests, tooManyRequestsResponse)
+
+		return
+	}
+
+	rw := &statusRecorder{ResponseWriter: w}
+
+	h.handleRequest(rw, req)
+
+	if rw.status >= http.StatusMultipleChoices {
+		return
+	}
+
+	// The operation was accepted and will be anchored.
+	err = h.mgr.recordAnchor(name)
+	if err != nil {
+		logger.Warnf("[%s] Error recording anchor usage for API key [%s]: %s", h.Path(), name, err)
+	}
+}
+
+// statusRecorder records the status code of the response.
+type statusRecorder struct {
+	http.ResponseWriter
+
+	status int
+}
+
+func (w *statusRecorder) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusRecorder) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	return w.ResponseWriter.Write(p)
+}
+
+func writeResponse(w http.ResponseWriter, status int, msg string) {
+	w.WriteHeader(status)
+
+	if _, err := w.Write([]byte(msg)); err != nil {
+		logger.Warnf("Unable to write response: %s", err)
+	}
+}
@@ -0,0 +1,212 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package quota
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+)
+
+const (
+	operationsPath = "/sidetree/v1/operations"
+	request1       = `{"type":"create","suffixData":{"deltaHash":"abc"}}`
+)
+
+func TestHandlerWrapper(t *testing.T) {
+	now := time.Date(2022, 6, 1, 10, 59, 30, 0, time.UTC)
+
+	t.Run("Operations per hour", func(t *testing.T) {
+		h := &mockHTTPHandler{status: http.StatusOK}
+
+		w, mgr := newHandlerWrapper(t, h, &Config{
+			APIKeys: []*APIKey{{Name: "tier1", Key: "key1", Limits: Limits{OperationsPerHour: 2}}},
+		}, now)
+		require.Equal(t, operationsPath, w.Path())
+		require.Equal(t, http.MethodPost, w.Method())
+
+		require.Equal(t, http.StatusOK, serve(w, "key1").StatusCode)
+		require.Equal(t, http.StatusOK, serve(w, "key1").StatusCode)
+
+		result := serve(w, "key1")
+		require.Equal(t, http.StatusTooManyRequests, result.StatusCode)
+		require.Equal(t, "30", result.Header.Get("Retry-After"))
+		require.Equal(t, 2, h.invocations())
+
+		// Requests without an API key are unlimited since no default quota is configured.
+		for i := 0; i < 3; i++ {
+			require.Equal(t, http.StatusOK, serve(w, "").StatusCode)
+		}
+
+		require.Equal(t, 5, h.invocations())
+
+		// The next hour.
+		mgr.now = func() time.Time { return now.Add(time.Minute) }
+
+		require.Equal(t, http.StatusOK, serve(w, "key1").StatusCode)
+
+		usage, err := mgr.Usage("tier1")
+		require.NoError(t, err)
+		require.Equal(t, 1, usage.Operations.Used)
+		require.Equal(t, 2, usage.Operations.Limit)
+		require.Equal(t, 3, usage.Anchors.Used)
+		require.Zero(t, usage.Anchors.Limit)
+	})
+
+	t.Run("Anchors per day", func(t *testing.T) {
+		h := &mockHTTPHandler{status: http.StatusOK}
+
+		w, mgr := newHandlerWrapper(t, h, &Config{
+			Default: &Limits{AnchorsPerDay: 2},
+		}, now)
+
+		require.Equal(t, http.StatusOK, serve(w, "").StatusCode)
+
+		// Rejected operations aren't anchored.
+		h.setStatus(http.StatusBadRequest)
+
+		require.Equal(t, http.StatusBadRequest, serve(w, "").StatusCode)
+
+		h.setStatus(http.StatusOK)
+
+		require.Equal(t, http.StatusOK, serve(w, "").StatusCode)
+
+		result := serve(w, "")
+		require.Equal(t, http.StatusTooManyRequests, result.StatusCode)
+		require.Equal(t, "46830", result.Header.Get("Retry-After"))
+
+		usage, err := mgr.Usage(DefaultName)
+		require.NoError(t, err)
+		require.Equal(t, 3, usage.Operations.Used)
+		require.Equal(t, 2, usage.Anchors.Used)
+		require.Equal(t, 2, usage.Anchors.Limit)
+		require.Equal(t, time.Date(2022, 6, 2, 0, 0, 0, 0, time.UTC), usage.Anchors.ResetsAt)
+
+		// The next day.
+		mgr.now = func() time.Time { return now.Add(14 * time.Hour) }
+
+		require.Equal(t, http.StatusOK, serve(w, "").StatusCode)
+	})
+
+	t.Run("Unknown API key", func(t *testing.T) {
+		h := &mockHTTPHandler{status: http.StatusOK}
+
+		w, _ := newHandlerWrapper(t, h, &Config{
+			APIKeys: []*APIKey{{Name: "tier1", Key: "key1"}},
+		}, now)
+
+		require.Equal(t, http.StatusUnauthorized, serve(w, "key2").StatusCode)
+		require.Zero(t, h.invocations())
+	})
+
+	t.Run("Store error", func(t *testing.T) {
+		h := &mockHTTPHandler{status: http.StatusOK}
+
+		s := &mockUsageStore{getErr: errors.New("injected get error")}
+
+		w := NewHandlerWrapper(h, NewManager(&Config{Default: &Limits{OperationsPerHour: 10}}, s))
+
+		require.Equal(t, http.StatusInternalServerError, serve(w, "").StatusCode)
+
+		w = NewHandlerWrapper(h, NewManager(&Config{Default: &Limits{AnchorsPerDay: 10}}, s))
+
+		require.Equal(t, http.StatusInternalServerError, serve(w, "").StatusCode)
+
+		s = &mockUsageStore{incrementErr: errors.New("injected increment error")}
+
+		w = NewHandlerWrapper(h, NewManager(&Config{Default: &Limits{}}, s))
+
+		require.Equal(t, http.StatusInternalServerError, serve(w, "").StatusCode)
+		require.Zero(t, h.invocations())
+	})
+}
+
+func newHandlerWrapper(t *testing.T, h common.HTTPHandler, cfg *Config, now time.Time) (*HandlerWrapper, *Manager) {
+	t.Helper()
+
+	s, err := NewStore(mem.NewProvider(), &mockExpiryService{})
+	require.NoError(t, err)
+
+	mgr := NewManager(cfg, s)
+	mgr.now = func() time.Time { return now }
+
+	return NewHandlerWrapper(h, mgr), mgr
+}
+
+func serve(w *HandlerWrapper, apiKey string) *http.Response {
+	req := httptest.NewRequest(http.MethodPost, operationsPath, strings.NewReader(request1))
+
+	if apiKey != "" {
+		req.Header.Set(APIKeyHeader, apiKey)
+	}
+
+	rw := httptest.NewRecorder()
+
+	w.Handler()(rw, req)
+
+	return rw.Result()
+}
+
+type mockHTTPHandler struct {
+	mutex  sync.Mutex
+	status int
+	count  int
+}
+
+func (m *mockHTTPHandler) Path() string {
+	return operationsPath
+}
+
+func (m *mockHTTPHandler) Method() string {
+	return http.MethodPost
+}
+
+func (m *mockHTTPHandler) Handler() common.HTTPRequestHandler {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		m.mutex.Lock()
+		defer m.mutex.Unlock()
+
+		m.count++
+
+		w.WriteHeader(m.status)
+	}
+}
+
+func (m *mockHTTPHandler) setStatus(status int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.status = status
+}
+
+func (m *mockHTTPHandler) invocations() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.count
+}
+
+type mockUsageStore struct {
+	getErr       error
+	incrementErr error
+}
+
+func (m *mockUsageStore) Get(string, Counter, time.Time) (int, error) {
+	return 0, m.getErr
+}
+
+func (m *mockUsageStore) Increment(string, Counter, time.Time) (int, error) {
+	return 0, m.incrementErr
+}
@@ -0,0 +1,192 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package quota
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/trustbloc/edge-core/pkg/log"
+)
+
+var logger = log.New("quota")
+
+// ErrUnknownAPIKey is returned when the given API key isn't configured.
+var ErrUnknownAPIKey = errors.New("unknown API key")
+
+type usageStore interface {
+	Get(name string, counter Counter, t time.Time) (int, error)
+	Increment(name string, counter Counter, t time.Time) (int, error)
+}
+
+// CounterUsage contains the usage of a counter in the current window.
+type CounterUsage struct {
+	// Limit is the quota for the window. Zero means that the quota is unlimited.
+	Limit int `json:"limit"`
+	// Used is the usage in the current window.
+	Used int `json:"used"`
+	// ResetsAt is the time at which the current window ends.
+	ResetsAt time.Time `json:"resetsAt"`
+}
+
+// Usage contains the usage of an API key.
+type Usage struct {
+	Name       string        `json:"name"`
+	Operations *CounterUsage `json:"operations"`
+	Anchors    *CounterUsage `json:"anchors"`
+}
+
+// Manager enforces the quotas of the configured API keys and keeps track of their usage.
+type Manager struct {
+	defaultLimits *Limits
+	keys          map[string]*APIKey
+	names         []string
+	limits        map[string]*Limits
+	store         usageStore
+	now           func() time.Time
+	mutex         sync.Mutex
+}
+
+// NewManager returns a new quota manager.
+func NewManager(cfg *Config, store usageStore) *Manager {
+	m := &Manager{
+		defaultLimits: cfg.Default,
+		keys:          make(map[string]*APIKey),
+		limits:        make(map[string]*Limits),
+		store:         store,
+		now:           time.Now,
+	}
+
+	if cfg.Default != nil {
+		m.names = append(m.names, DefaultName)
+		m.limits[DefaultName] = cfg.Default
+	}
+
+	for _, k := range cfg.APIKeys {
+		m.keys[k.Key] = k
+		m.names = append(m.names, k.Name)
+		m.limits[k.Name] = &k.Limits
+	}
+
+	return m
+}
+
+// resolve returns the name and limits of the given API key. If no key is provided then the default limits
+// are returned, which are nil if no default limits were configured (i.e. requests without a key are unlimited).
+func (m *Manager) resolve(key string) (string, *Limits, error) {
+	if key == "" {
+		return DefaultName, m.defaultLimits, nil
+	}
+
+	k, ok := m.keys[key]
+	if !ok {
+		return "", nil, ErrUnknownAPIKey
+	}
+
+	return k.Name, &k.Limits, nil
+}
+
+// admit checks the quotas of the given API key and, if the quotas aren't exceeded, counts the operation
+// request. If a quota is exceeded then the duration after which the request may be retried is returned.
+func (m *Manager) admit(name string, limits *Limits) (time.Duration, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	now := m.now()
+
+	if limits.AnchorsPerDay > 0 {
+		anchors, err := m.store.Get(name, AnchorsCounter, now)
+		if err != nil {
+			return 0, err
+		}
+
+		if anchors >= limits.AnchorsPerDay {
+			logger.Debugf("Anchors per day quota [%d] exceeded for API key [%s]", limits.AnchorsPerDay, name)
+
+			return resetsAt(AnchorsCounter, now).Sub(now), nil
+		}
+	}
+
+	if limits.OperationsPerHour > 0 {
+		operations, err := m.store.Get(name, OperationsCounter, now)
+		if err != nil {
+			return 0, err
+		}
+
+		if operations >= limits.OperationsPerHour {
+			logger.Debugf("Operations per hour quota [%d] exceeded for API key [%s]", limits.OperationsPerHour, name)
+
+			return resetsAt(OperationsCounter, now).Sub(now), nil
+		}
+	}
+
+	_, err := m.store.Increment(name, OperationsCounter, now)
+	if err != nil {
+		return 0, err
+	}
+
+	return 0, nil
+}
+
+// recordAnchor counts an operation that was accepted for anchoring.
+func (m *Manager) recordAnchor(name string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	_, err := m.store.Increment(name, AnchorsCounter, m.now())
+
+	return err
+}
+
+// Names returns the names of the API keys (including the default name if default limits are configured).
+func (m *Manager) Names() []string {
+	return m.names
+}
+
+// Usage returns the usage of the API key with the given name.
+func (m *Manager) Usage(name string) (*Usage, error) {
+	limits, ok := m.limits[name]
+	if !ok {
+		return nil, ErrUnknownAPIKey
+	}
+
+	now := m.now()
+
+	operations, err := m.counterUsage(name, OperationsCounter, limits.OperationsPerHour, now)
+	if err != nil {
+		return nil, err
+	}
+
+	anchors, err := m.counterUsage(name, AnchorsCounter, limits.AnchorsPerDay, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Usage{
+		Name:       name,
+		Operations: operations,
+		Anchors:    anchors,
+	}, nil
+}
+
+func (m *Manager) counterUsage(name string, counter Counter, limit int, now time.Time) (*CounterUsage, error) {
+	used, err := m.store.Get(name, counter, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CounterUsage{
+		Limit:    limit,
+		Used:     used,
+		ResetsAt: resetsAt(counter, now),
+	}, nil
+}
+
+func resetsAt(counter Counter, now time.Time) time.Time {
+	return counter.windowStart(now).Add(counter.window())
+}
@@ -0,0 +1,139 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package quota
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/store/expiry"
+)
+
+const (
+	storeName = "quota-usage"
+
+	// expiryTag holds the time (Unix time) after which the entry is deleted.
+	expiryTag = "expiry"
+
+	hourWindow = time.Hour
+	dayWindow  = 24 * time.Hour
+)
+
+// Counter is the type of usage counter.
+type Counter string
+
+const (
+	// OperationsCounter counts the operation requests per hour.
+	OperationsCounter Counter = "operations"
+	// AnchorsCounter counts the operations that were accepted for anchoring per day.
+	AnchorsCounter Counter = "anchors"
+)
+
+// window returns the period of the counter.
+func (c Counter) window() time.Duration {
+	if c == AnchorsCounter {
+		return dayWindow
+	}
+
+	return hourWindow
+}
+
+// windowStart returns the start of the counter's window that contains the given time.
+func (c Counter) windowStart(t time.Time) time.Time {
+	return t.UTC().Truncate(c.window())
+}
+
+type usageRecord struct {
+	Count int `json:"count"`
+}
+
+type expiryService interface {
+	Register(store storage.Store, expiryTagName, storeName string, opts ...expiry.Option)
+}
+
+// Store stores the usage counters of the API keys. A counter is kept per API key and window, and
+// is deleted by the expiry service after the window ends.
+type Store struct {
+	store storage.Store
+}
+
+// NewStore returns a new usage store.
+func NewStore(provider storage.Provider, expiryService expiryService) (*Store, error) {
+	s, err := provider.OpenStore(storeName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open quota usage store: %w", err)
+	}
+
+	err = provider.SetStoreConfig(storeName, storage.StoreConfiguration{TagNames: []string{expiryTag}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set store configuration: %w", err)
+	}
+
+	expiryService.Register(s, expiryTag, storeName)
+
+	return &Store{store: s}, nil
+}
+
+// Get returns the value of the given counter for the window that contains the given time.
+func (s *Store) Get(name string, counter Counter, t time.Time) (int, error) {
+	key := usageKey(name, counter, t)
+
+	recordBytes, err := s.store.Get(key)
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return 0, nil
+		}
+
+		return 0, orberrors.NewTransient(fmt.Errorf("get usage [%s]: %w", key, err))
+	}
+
+	record := &usageRecord{}
+
+	err = json.Unmarshal(recordBytes, record)
+	if err != nil {
+		return 0, fmt.Errorf("unmarshal usage [%s]: %w", key, err)
+	}
+
+	return record.Count, nil
+}
+
+// Increment increments the given counter for the window that contains the given time and returns the new value.
+func (s *Store) Increment(name string, counter Counter, t time.Time) (int, error) {
+	count, err := s.Get(name, counter, t)
+	if err != nil {
+		return 0, err
+	}
+
+	count++
+
+	recordBytes, err := json.Marshal(&usageRecord{Count: count})
+	if err != nil {
+		return 0, fmt.Errorf("marshal usage: %w", err)
+	}
+
+	key := usageKey(name, counter, t)
+	expiresAt := counter.windowStart(t).Add(counter.window())
+
+	err = s.store.Put(key, recordBytes,
+		storage.Tag{Name: expiryTag, Value: strconv.FormatInt(expiresAt.Unix(), 10)},
+	)
+	if err != nil {
+		return 0, orberrors.NewTransient(fmt.Errorf("store usage [%s]: %w", key, err))
+	}
+
+	return count, nil
+}
+
+func usageKey(name string, counter Counter, t time.Time) string {
+	return fmt.Sprintf("%s:%s:%d", name, counter, counter.windowStart(t).Unix())
+}
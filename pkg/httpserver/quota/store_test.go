@@ -0,0 +1,147 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package quota
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/stretchr/testify/require"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/store/expiry"
+	"github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+func TestStore(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		es := &mockExpiryService{}
+
+		s, err := NewStore(mem.NewProvider(), es)
+		require.NoError(t, err)
+		require.Equal(t, storeName, es.storeName)
+		require.Equal(t, expiryTag, es.expiryTagName)
+
+		now := time.Date(2022, 6, 1, 10, 30, 0, 0, time.UTC)
+
+		count, err := s.Get("tier1", OperationsCounter, now)
+		require.NoError(t, err)
+		require.Zero(t, count)
+
+		for i := 1; i <= 3; i++ {
+			count, err = s.Increment("tier1", OperationsCounter, now)
+			require.NoError(t, err)
+			require.Equal(t, i, count)
+		}
+
+		// Same hour.
+		count, err = s.Get("tier1", OperationsCounter, now.Add(29*time.Minute))
+		require.NoError(t, err)
+		require.Equal(t, 3, count)
+
+		// Next hour.
+		count, err = s.Get("tier1", OperationsCounter, now.Add(30*time.Minute))
+		require.NoError(t, err)
+		require.Zero(t, count)
+
+		// Different counter.
+		count, err = s.Get("tier1", AnchorsCounter, now)
+		require.NoError(t, err)
+		require.Zero(t, count)
+
+		// Different API key.
+		count, err = s.Get("tier2", OperationsCounter, now)
+		require.NoError(t, err)
+		require.Zero(t, count)
+
+		_, err = s.Increment("tier1", AnchorsCounter, now)
+		require.NoError(t, err)
+
+		// Same day.
+		count, err = s.Get("tier1", AnchorsCounter, now.Add(13*time.Hour))
+		require.NoError(t, err)
+		require.Equal(t, 1, count)
+
+		// Next day.
+		count, err = s.Get("tier1", AnchorsCounter, now.Add(14*time.Hour))
+		require.NoError(t, err)
+		require.Zero(t, count)
+	})
+
+	t.Run("Open store error", func(t *testing.T) {
+		p := &mocks.Provider{}
+		p.OpenStoreReturns(nil, errors.New("injected open error"))
+
+		_, err := NewStore(p, &mockExpiryService{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected open error")
+	})
+
+	t.Run("Set store config error", func(t *testing.T) {
+		p := &mocks.Provider{}
+		p.SetStoreConfigReturns(errors.New("injected config error"))
+
+		_, err := NewStore(p, &mockExpiryService{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected config error")
+	})
+
+	t.Run("Store errors", func(t *testing.T) {
+		errExpected := errors.New("injected store error")
+
+		store := &mocks.Store{}
+		store.GetReturns(nil, errExpected)
+
+		p := &mocks.Provider{}
+		p.OpenStoreReturns(store, nil)
+
+		s, err := NewStore(p, &mockExpiryService{})
+		require.NoError(t, err)
+
+		_, err = s.Get("tier1", OperationsCounter, time.Now())
+		require.ErrorIs(t, err, errExpected)
+		require.True(t, orberrors.IsTransient(err))
+
+		_, err = s.Increment("tier1", OperationsCounter, time.Now())
+		require.ErrorIs(t, err, errExpected)
+
+		store.GetReturns(nil, storage.ErrDataNotFound)
+		store.PutReturns(errExpected)
+
+		_, err = s.Increment("tier1", OperationsCounter, time.Now())
+		require.ErrorIs(t, err, errExpected)
+		require.True(t, orberrors.IsTransient(err))
+	})
+
+	t.Run("Unmarshal error", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.GetReturns([]byte("xxx"), nil)
+
+		p := &mocks.Provider{}
+		p.OpenStoreReturns(store, nil)
+
+		s, err := NewStore(p, &mockExpiryService{})
+		require.NoError(t, err)
+
+		_, err = s.Get("tier1", OperationsCounter, time.Now())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal usage")
+	})
+}
+
+type mockExpiryService struct {
+	storeName     string
+	expiryTagName string
+}
+
+func (m *mockExpiryService) Register(_ storage.Store, expiryTagName, storeName string, _ ...expiry.Option) {
+	m.storeName = storeName
+	m.expiryTagName = expiryTagName
+}
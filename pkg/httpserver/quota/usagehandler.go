@@ -0,0 +1,118 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package quota
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+)
+
+const (
+	// UsagePath is the path of the quota usage endpoint.
+	UsagePath = "/quota/usage"
+
+	// nameParam is the query parameter that holds the name of the API key.
+	nameParam = "name"
+
+	notFoundResponse = "Not Found.\n"
+)
+
+type usageProvider interface {
+	Names() []string
+	Usage(name string) (*Usage, error)
+}
+
+// UsageHandler implements a REST handler that returns the usage of the API keys in the current windows.
+// The usage of a single API key is returned with GET /quota/usage?name={name}, otherwise the usage
+// of all API keys is returned.
+type UsageHandler struct {
+	usage   usageProvider
+	marshal func(v interface{}) ([]byte, error)
+}
+
+// NewUsageHandler returns a new quota usage handler.
+func NewUsageHandler(usage usageProvider) *UsageHandler {
+	return &UsageHandler{
+		usage:   usage,
+		marshal: json.Marshal,
+	}
+}
+
+// Path returns the HTTP REST endpoint of the handler.
+func (h *UsageHandler) Path() string {
+	return UsagePath
+}
+
+// Method returns the HTTP method, which is always GET.
+func (h *UsageHandler) Method() string {
+	return http.MethodGet
+}
+
+// Handler returns the HTTP REST handle.
+func (h *UsageHandler) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *UsageHandler) handle(w http.ResponseWriter, req *http.Request) {
+	var (
+		response interface{}
+		err      error
+	)
+
+	if name := req.URL.Query().Get(nameParam); name != "" {
+		response, err = h.usage.Usage(name)
+	} else {
+		response, err = h.getAll()
+	}
+
+	if err != nil {
+		if errors.Is(err, ErrUnknownAPIKey) {
+			writeResponse(w, http.StatusNotFound, notFoundResponse)
+
+			return
+		}
+
+		logger.Errorf("[%s] Error retrieving quota usage: %s", UsagePath, err)
+
+		writeResponse(w, http.StatusInternalServerError, internalServerErrorResponse)
+
+		return
+	}
+
+	respBytes, err := h.marshal(response)
+	if err != nil {
+		logger.Errorf("[%s] Error marshalling quota usage: %s", UsagePath, err)
+
+		writeResponse(w, http.StatusInternalServerError, internalServerErrorResponse)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	writeResponse(w, http.StatusOK, string(respBytes))
+}
+
+func (h *UsageHandler) getAll() ([]*Usage, error) {
+	names := h.usage.Names()
+
+	usage := make([]*Usage, len(names))
+
+	for i, name := range names {
+		u, err := h.usage.Usage(name)
+		if err != nil {
+			return nil, err
+		}
+
+		usage[i] = u
+	}
+
+	return usage, nil
+}
@@ -0,0 +1,101 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package quota
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/internal/testutil/httptestutil"
+)
+
+func TestUsageHandler(t *testing.T) {
+	now := time.Date(2022, 6, 1, 10, 30, 0, 0, time.UTC)
+
+	s, err := NewStore(mem.NewProvider(), &mockExpiryService{})
+	require.NoError(t, err)
+
+	mgr := NewManager(&Config{
+		Default: &Limits{OperationsPerHour: 10},
+		APIKeys: []*APIKey{{Name: "tier1", Key: "key1", Limits: Limits{OperationsPerHour: 100, AnchorsPerDay: 50}}},
+	}, s)
+	mgr.now = func() time.Time { return now }
+
+	_, err = s.Increment("tier1", OperationsCounter, now)
+	require.NoError(t, err)
+
+	_, err = s.Increment("tier1", AnchorsCounter, now)
+	require.NoError(t, err)
+
+	h := NewUsageHandler(mgr)
+	require.Equal(t, UsagePath, h.Path())
+	require.Equal(t, http.MethodGet, h.Method())
+	require.NotNil(t, h.Handler())
+
+	t.Run("All API keys", func(t *testing.T) {
+		code, body := httptestutil.Get(t, h.handle, UsagePath)
+		require.Equal(t, http.StatusOK, code)
+
+		var usage []*Usage
+		require.NoError(t, json.Unmarshal(body, &usage))
+		require.Len(t, usage, 2)
+
+		require.Equal(t, DefaultName, usage[0].Name)
+		require.Equal(t, 10, usage[0].Operations.Limit)
+		require.Zero(t, usage[0].Operations.Used)
+
+		require.Equal(t, "tier1", usage[1].Name)
+		require.Equal(t, 1, usage[1].Operations.Used)
+		require.Equal(t, 1, usage[1].Anchors.Used)
+	})
+
+	t.Run("Single API key", func(t *testing.T) {
+		code, body := httptestutil.Get(t, h.handle, UsagePath+"?name=tier1")
+		require.Equal(t, http.StatusOK, code)
+
+		usage := &Usage{}
+		require.NoError(t, json.Unmarshal(body, usage))
+		require.Equal(t, "tier1", usage.Name)
+		require.Equal(t, &CounterUsage{
+			Limit:    100,
+			Used:     1,
+			ResetsAt: time.Date(2022, 6, 1, 11, 0, 0, 0, time.UTC),
+		}, usage.Operations)
+		require.Equal(t, &CounterUsage{
+			Limit:    50,
+			Used:     1,
+			ResetsAt: time.Date(2022, 6, 2, 0, 0, 0, 0, time.UTC),
+		}, usage.Anchors)
+	})
+
+	t.Run("Unknown API key", func(t *testing.T) {
+		code, _ := httptestutil.Get(t, h.handle, UsagePath+"?name=tier2")
+		require.Equal(t, http.StatusNotFound, code)
+	})
+
+	t.Run("Store error", func(t *testing.T) {
+		h := NewUsageHandler(NewManager(&Config{Default: &Limits{}},
+			&mockUsageStore{getErr: errors.New("injected get error")}))
+
+		code, _ := httptestutil.Get(t, h.handle, UsagePath)
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		h := NewUsageHandler(mgr)
+		h.marshal = func(v interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		code, _ := httptestutil.Get(t, h.handle, UsagePath)
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+}
@@ -8,6 +8,7 @@ package httpserver
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -16,7 +17,6 @@ import (
 	"time"
 
 	"github.com/gorilla/mux"
-	"github.com/rs/cors"
 	"github.com/trustbloc/edge-core/pkg/log"
 	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
 )
@@ -25,44 +25,69 @@ var logger = log.New("httpserver")
 
 const healthCheckEndpoint = "/healthcheck"
 
-// Server implements an HTTP server.
+// Server implements an HTTP server. If a TLS certificate and key are provided then the files are
+// monitored for changes and the certificate is reloaded without restarting the server.
 type Server struct {
-	httpServer *http.Server
-	started    uint32
-	certFile   string
-	keyFile    string
+	httpServer               *http.Server
+	started                  uint32
+	certFile                 string
+	keyFile                  string
+	certificateCheckInterval time.Duration
+}
+
+type options struct {
+	corsPolicies []*CORSPolicy
+}
+
+// Option is an HTTP server option.
+type Option func(opts *options)
+
+// WithCORSPolicies sets the CORS policies of the endpoint groups. The policy of the first group that matches
+// the path of a request is applied. Requests that don't match any of the groups are allowed from all origins.
+func WithCORSPolicies(policies ...*CORSPolicy) Option {
+	return func(opts *options) {
+		opts.corsPolicies = policies
+	}
 }
 
 // New returns a new HTTP server.
 func New(url, certFile, keyFile string, handlers ...common.HTTPHandler) *Server {
+	return NewWithOptions(url, certFile, keyFile, handlers)
+}
+
+// NewWithOptions returns a new HTTP server with the given options.
+func NewWithOptions(url, certFile, keyFile string, handlers []common.HTTPHandler, opts ...Option) *Server {
+	options := &options{}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
 	router := mux.NewRouter()
 
 	for _, handler := range handlers {
 		logger.Infof("Registering handler for [%s]", handler.Path())
-		router.HandleFunc(handler.Path(), handler.Handler()).
+
+		route := router.HandleFunc(handler.Path(), handler.Handler()).
 			Methods(handler.Method()).
 			Queries(params(handler)...)
+
+		if h, ok := handler.(hostHolder); ok && h.Host() != "" {
+			route.Host(h.Host())
+		}
 	}
 
 	// add health check endpoint
 	router.HandleFunc(healthCheckEndpoint, healthCheckHandler).Methods(http.MethodGet)
 
-	handler := cors.New(
-		cors.Options{
-			AllowedMethods: []string{
-				http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete, http.MethodOptions,
-			},
-			AllowedHeaders: []string{"*"},
-		},
-	).Handler(router)
-
 	return &Server{
 		httpServer: &http.Server{
 			Addr:    url,
-			Handler: handler,
+			Handler: newCORSHandler(options.corsPolicies, router),
 		},
-		certFile: certFile,
-		keyFile:  keyFile,
+		certFile:                 certFile,
+		keyFile:                  keyFile,
+		certificateCheckInterval: defaultCertificateCheckInterval,
 	}
 }
 
@@ -72,12 +97,29 @@ func (s *Server) Start() error {
 		return fmt.Errorf("server already started")
 	}
 
+	useTLS := s.keyFile != "" && s.certFile != ""
+
+	if useTLS {
+		certReloader, err := newCertificateReloader(s.certFile, s.keyFile, s.certificateCheckInterval)
+		if err != nil {
+			atomic.StoreUint32(&s.started, 0)
+
+			return fmt.Errorf("start server on [%s]: %w", s.httpServer.Addr, err)
+		}
+
+		s.httpServer.TLSConfig = &tls.Config{
+			MinVersion:     tls.VersionTLS12,
+			GetCertificate: certReloader.GetCertificate,
+		}
+	}
+
 	go func() {
 		logger.Infof("listening for requests on [%s]", s.httpServer.Addr)
 
 		var err error
-		if s.keyFile != "" && s.certFile != "" {
-			err = s.httpServer.ListenAndServeTLS(s.certFile, s.keyFile)
+		if useTLS {
+			// The certificate is provided by the TLS config.
+			err = s.httpServer.ListenAndServeTLS("", "")
 		} else {
 			err = s.httpServer.ListenAndServe()
 		}
@@ -123,6 +165,11 @@ type paramHolder interface {
 	Params() map[string]string
 }
 
+// hostHolder is implemented by handlers that only handle requests for a specific host.
+type hostHolder interface {
+	Host() string
+}
+
 func params(handler common.HTTPHandler) []string {
 	var queries []string
 
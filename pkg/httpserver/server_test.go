@@ -65,6 +65,37 @@ func TestServer_Start(t *testing.T) {
 	})
 }
 
+func TestServer_Host(t *testing.T) {
+	s := New(url, "", "",
+		&mockHostHandler{host: "tenant1.example.com", status: http.StatusOK},
+		&mockHostHandler{host: "tenant2.example.com", status: http.StatusAccepted},
+	)
+
+	t.Run("Tenant 1", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+
+		s.httpServer.Handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://tenant1.example.com/host", nil))
+
+		require.Equal(t, http.StatusOK, rw.Code)
+	})
+
+	t.Run("Tenant 2", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+
+		s.httpServer.Handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://tenant2.example.com/host", nil))
+
+		require.Equal(t, http.StatusAccepted, rw.Code)
+	})
+
+	t.Run("Unknown host", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+
+		s.httpServer.Handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://other.example.com/host", nil))
+
+		require.Equal(t, http.StatusNotFound, rw.Code)
+	})
+}
+
 // httpPut sends a regular POST request to the sidetree-node
 // - If post request has operation "create" then return sidetree document else no response.
 func httpPut(t *testing.T, url string, req []byte) ([]byte, error) {
@@ -172,3 +203,26 @@ func (h *mockResolveHandler) Handler() common.HTTPRequestHandler {
 	return func(writer http.ResponseWriter, request *http.Request) {
 	}
 }
+
+type mockHostHandler struct {
+	host   string
+	status int
+}
+
+func (h *mockHostHandler) Path() string {
+	return "/host"
+}
+
+func (h *mockHostHandler) Method() string {
+	return http.MethodGet
+}
+
+func (h *mockHostHandler) Host() string {
+	return h.host
+}
+
+func (h *mockHostHandler) Handler() common.HTTPRequestHandler {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(h.status)
+	}
+}
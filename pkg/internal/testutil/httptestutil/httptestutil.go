@@ -0,0 +1,59 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package httptestutil
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+)
+
+// Get invokes the given handler with a GET request for the given target and returns the status code
+// and the body of the response.
+func Get(t *testing.T, handle func(http.ResponseWriter, *http.Request), target string) (int, []byte) {
+	t.Helper()
+
+	return Serve(t, handle, http.MethodGet, target, nil, nil)
+}
+
+// Post invokes the given handler with a POST request for the given target and body and returns the status code
+// and the body of the response.
+func Post(t *testing.T, handle func(http.ResponseWriter, *http.Request), target string, body []byte) (int, []byte) {
+	t.Helper()
+
+	return Serve(t, handle, http.MethodPost, target, body, nil)
+}
+
+// Serve invokes the given handler with a request for the given method and target and returns the status code
+// and the body of the response. The request body and the path variables (as set by the router) are optional.
+func Serve(t *testing.T, handle func(http.ResponseWriter, *http.Request), method, target string, body []byte,
+	vars map[string]string) (int, []byte) {
+	t.Helper()
+
+	req := httptest.NewRequest(method, target, bytes.NewReader(body))
+
+	if vars != nil {
+		req = mux.SetURLVars(req, vars)
+	}
+
+	rw := httptest.NewRecorder()
+
+	handle(rw, req)
+
+	result := rw.Result()
+
+	respBody, err := ioutil.ReadAll(result.Body)
+	require.NoError(t, err)
+	require.NoError(t, result.Body.Close())
+
+	return result.StatusCode, respBody
+}
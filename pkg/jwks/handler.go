@@ -0,0 +1,112 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwks
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+)
+
+// Path is the path of the JWKS endpoint.
+const Path = "/.well-known/jwks.json"
+
+const internalServerErrorResponse = "Internal Server Error."
+
+// Handler implements a REST handler that returns the public keys of the service (the HTTP signature key and
+// the key used to sign verifiable credentials) as a JSON Web Key Set.
+type Handler struct {
+	mutex            sync.RWMutex
+	httpSignatureKey *JWK
+	vcSigningKey     *JWK
+	marshal          func(v interface{}) ([]byte, error)
+}
+
+// New returns a new JWKS handler. The given HTTP signature key is the public key that is published by the
+// ActivityPub service and the VC signing key is the raw Ed25519 public key that is referenced by the given
+// verification method.
+func New(httpSignatureKey *vocab.PublicKeyType, vcVerificationMethod string, vcSigningKey []byte) (*Handler, error) {
+	httpSigJWK, err := FromPublicKey(httpSignatureKey)
+	if err != nil {
+		return nil, err
+	}
+
+	vcJWK, err := NewEd25519(vcVerificationMethod, vcSigningKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Handler{
+		httpSignatureKey: httpSigJWK,
+		vcSigningKey:     vcJWK,
+		marshal:          json.Marshal,
+	}, nil
+}
+
+// SetPublicKey replaces the HTTP signature key. This function is called when the HTTP signature key
+// is rotated. If the key can't be converted to a JWK then the previous key is retained.
+func (h *Handler) SetPublicKey(publicKey *vocab.PublicKeyType) {
+	jwk, err := FromPublicKey(publicKey)
+	if err != nil {
+		logger.Errorf("Unable to update the HTTP signature key in the JWKS: %s", err)
+
+		return
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.httpSignatureKey = jwk
+}
+
+// Path returns the HTTP REST endpoint of the handler.
+func (h *Handler) Path() string {
+	return Path
+}
+
+// Method returns the HTTP method, which is always GET.
+func (h *Handler) Method() string {
+	return http.MethodGet
+}
+
+// Handler returns the HTTP REST handle.
+func (h *Handler) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Handler) handle(w http.ResponseWriter, _ *http.Request) {
+	h.mutex.RLock()
+	set := &Set{Keys: []*JWK{h.httpSignatureKey, h.vcSigningKey}}
+	h.mutex.RUnlock()
+
+	setBytes, err := h.marshal(set)
+	if err != nil {
+		logger.Errorf("[%s] Error marshalling JWKS: %s", Path, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/jwk-set+json")
+
+	writeResponse(w, http.StatusOK, setBytes)
+}
+
+func writeResponse(w http.ResponseWriter, status int, body []byte) {
+	w.WriteHeader(status)
+
+	if len(body) > 0 {
+		if _, err := w.Write(body); err != nil {
+			logger.Warnf("[%s] Unable to write response: %s", Path, err)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwks
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+)
+
+const vcVerificationMethod = "did:web:orb.domain1.com#vc-key"
+
+func TestHandler(t *testing.T) {
+	httpSigKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	vcKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	h, err := New(newPublicKey(t, httpSigKey), vcVerificationMethod, vcKey)
+	require.NoError(t, err)
+	require.Equal(t, Path, h.Path())
+	require.Equal(t, http.MethodGet, h.Method())
+	require.NotNil(t, h.Handler())
+
+	t.Run("Success", func(t *testing.T) {
+		set := getSet(t, h)
+		require.Len(t, set.Keys, 2)
+		require.Equal(t, keyID, set.Keys[0].KeyID)
+		require.Equal(t, base64.RawURLEncoding.EncodeToString(httpSigKey), set.Keys[0].X)
+		require.Equal(t, vcVerificationMethod, set.Keys[1].KeyID)
+		require.Equal(t, base64.RawURLEncoding.EncodeToString(vcKey), set.Keys[1].X)
+	})
+
+	t.Run("Key rotation", func(t *testing.T) {
+		newKey, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		h.SetPublicKey(newPublicKey(t, newKey))
+
+		set := getSet(t, h)
+		require.Len(t, set.Keys, 2)
+		require.Equal(t, base64.RawURLEncoding.EncodeToString(newKey), set.Keys[0].X)
+
+		// An invalid key is ignored.
+		h.SetPublicKey(&vocab.PublicKeyType{})
+
+		set = getSet(t, h)
+		require.Equal(t, base64.RawURLEncoding.EncodeToString(newKey), set.Keys[0].X)
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		h, err := New(newPublicKey(t, httpSigKey), vcVerificationMethod, vcKey)
+		require.NoError(t, err)
+
+		h.marshal = func(v interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		rw := httptest.NewRecorder()
+
+		h.handle(rw, httptest.NewRequest(http.MethodGet, Path, nil))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusInternalServerError, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+}
+
+func TestNew_Error(t *testing.T) {
+	key, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	t.Run("Invalid HTTP signature key", func(t *testing.T) {
+		_, err := New(nil, vcVerificationMethod, key)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "public key ID is required")
+	})
+
+	t.Run("Invalid VC signing key", func(t *testing.T) {
+		_, err := New(newPublicKey(t, key), vcVerificationMethod, []byte("xxx"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid Ed25519 public key size")
+	})
+}
+
+func getSet(t *testing.T, h *Handler) *Set {
+	t.Helper()
+
+	rw := httptest.NewRecorder()
+
+	h.handle(rw, httptest.NewRequest(http.MethodGet, Path, nil))
+
+	result := rw.Result()
+	require.Equal(t, http.StatusOK, result.StatusCode)
+	require.Equal(t, "application/jwk-set+json", result.Header.Get("Content-Type"))
+
+	respBytes, err := ioutil.ReadAll(result.Body)
+	require.NoError(t, err)
+	require.NoError(t, result.Body.Close())
+
+	set := &Set{}
+	require.NoError(t, json.Unmarshal(respBytes, set))
+
+	return set
+}
@@ -0,0 +1,77 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwks
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	"github.com/trustbloc/orb/pkg/activitypub/multikey"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+)
+
+var logger = log.New("jwks")
+
+const (
+	keyTypeOKP     = "OKP"
+	curveEd25519   = "Ed25519"
+	algorithmEdDSA = "EdDSA"
+	useSignature   = "sig"
+)
+
+// JWK is a JSON Web Key (RFC 7517) that holds a public key.
+type JWK struct {
+	KeyType   string `json:"kty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	KeyID     string `json:"kid,omitempty"`
+	Use       string `json:"use,omitempty"`
+	Algorithm string `json:"alg,omitempty"`
+}
+
+// Set is a JSON Web Key Set.
+type Set struct {
+	Keys []*JWK `json:"keys"`
+}
+
+// NewEd25519 returns a JWK for the given raw Ed25519 public key.
+func NewEd25519(keyID string, pubKey []byte) (*JWK, error) {
+	if len(pubKey) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid Ed25519 public key size for key [%s]: %d", keyID, len(pubKey))
+	}
+
+	return &JWK{
+		KeyType:   keyTypeOKP,
+		Curve:     curveEd25519,
+		X:         base64.RawURLEncoding.EncodeToString(pubKey),
+		KeyID:     keyID,
+		Use:       useSignature,
+		Algorithm: algorithmEdDSA,
+	}, nil
+}
+
+// FromPublicKey returns a JWK for the given ActivityPub public key. The ID of the public key is used
+// as the key ID, so that the key may be matched with the keyId of an HTTP signature. The key may be
+// provided either in PEM ('publicKeyPem') or in Multikey ('publicKeyMultibase') format.
+func FromPublicKey(publicKey *vocab.PublicKeyType) (*JWK, error) {
+	if publicKey == nil || publicKey.ID == nil {
+		return nil, errors.New("public key ID is required")
+	}
+
+	keyID := publicKey.ID.String()
+
+	pubKey, err := multikey.Ed25519PublicKey(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("public key [%s]: %w", keyID, err)
+	}
+
+	return NewEd25519(keyID, pubKey)
+}
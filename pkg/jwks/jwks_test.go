@@ -0,0 +1,127 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jwks
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/multikey"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+)
+
+const keyID = "https://orb.domain1.com/services/orb/keys/main-key"
+
+func TestNewEd25519(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		pubKey, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		jwk, err := NewEd25519("key1", pubKey)
+		require.NoError(t, err)
+		require.Equal(t, "OKP", jwk.KeyType)
+		require.Equal(t, "Ed25519", jwk.Curve)
+		require.Equal(t, "EdDSA", jwk.Algorithm)
+		require.Equal(t, "sig", jwk.Use)
+		require.Equal(t, "key1", jwk.KeyID)
+
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		require.NoError(t, err)
+		require.Equal(t, []byte(pubKey), x)
+	})
+
+	t.Run("Invalid key size", func(t *testing.T) {
+		_, err := NewEd25519("key1", []byte("xxx"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid Ed25519 public key size for key [key1]")
+	})
+}
+
+func TestFromPublicKey(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		pubKey, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		jwk, err := FromPublicKey(newPublicKey(t, pubKey))
+		require.NoError(t, err)
+		require.Equal(t, keyID, jwk.KeyID)
+		require.Equal(t, base64.RawURLEncoding.EncodeToString(pubKey), jwk.X)
+	})
+
+	t.Run("Multibase key", func(t *testing.T) {
+		pubKey, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		multibaseKey, err := multikey.EncodeEd25519(pubKey)
+		require.NoError(t, err)
+
+		jwk, err := FromPublicKey(vocab.NewPublicKey(
+			vocab.WithID(testutil.MustParseURL(keyID)),
+			vocab.WithPublicKeyMultibase(multibaseKey),
+		))
+		require.NoError(t, err)
+		require.Equal(t, keyID, jwk.KeyID)
+		require.Equal(t, base64.RawURLEncoding.EncodeToString(pubKey), jwk.X)
+	})
+
+	t.Run("Nil key", func(t *testing.T) {
+		_, err := FromPublicKey(nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "public key ID is required")
+	})
+
+	t.Run("Invalid PEM", func(t *testing.T) {
+		_, err := FromPublicKey(vocab.NewPublicKey(
+			vocab.WithID(testutil.MustParseURL(keyID)),
+			vocab.WithPublicKeyPem("xxx"),
+		))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid PEM")
+	})
+
+	t.Run("Invalid key", func(t *testing.T) {
+		pemBytes := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: []byte("xxx")})
+
+		_, err := FromPublicKey(vocab.NewPublicKey(
+			vocab.WithID(testutil.MustParseURL(keyID)),
+			vocab.WithPublicKeyPem(string(pemBytes)),
+		))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "parse public key")
+	})
+
+	t.Run("Unsupported key type", func(t *testing.T) {
+		privKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		_, err = FromPublicKey(newPublicKey(t, &privKey.PublicKey))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unsupported type for public key")
+	})
+}
+
+func newPublicKey(t *testing.T, pubKey interface{}) *vocab.PublicKeyType {
+	t.Helper()
+
+	derBytes, err := x509.MarshalPKIXPublicKey(pubKey)
+	require.NoError(t, err)
+
+	return vocab.NewPublicKey(
+		vocab.WithID(testutil.MustParseURL(keyID)),
+		vocab.WithOwner(testutil.MustParseURL("https://orb.domain1.com/services/orb")),
+		vocab.WithPublicKeyPem(string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: derBytes}))),
+	)
+}
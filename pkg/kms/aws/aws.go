@@ -0,0 +1,218 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package aws
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+var logger = log.New("aws-kms")
+
+const (
+	service = "kms"
+
+	// DefaultSigningAlgorithm is the AWS KMS signing algorithm for Ed25519 keys (key spec ECC_NIST_EDWARDS25519).
+	DefaultSigningAlgorithm = "ED25519_SHA_512"
+
+	targetHeader = "X-Amz-Target"
+	contentType  = "application/x-amz-json-1.1"
+
+	getPublicKeyTarget = "TrentService.GetPublicKey"
+	signTarget         = "TrentService.Sign"
+)
+
+type httpClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Client signs data with asymmetric keys that are managed by AWS KMS. The private keys never leave AWS KMS.
+// Only Ed25519 keys are supported since the public key is published as an Ed25519 verification key.
+// Client implements the subset of the Aries KeyManager and Crypto interfaces that is required for signing.
+type Client struct {
+	endpoint         string
+	region           string
+	credentials      Credentials
+	signingAlgorithm string
+	httpClient       httpClient
+	now              func() time.Time
+}
+
+// Option is an AWS KMS client option.
+type Option func(c *Client)
+
+// WithEndpoint overrides the AWS KMS endpoint, which defaults to https://kms.<region>.amazonaws.com.
+func WithEndpoint(endpoint string) Option {
+	return func(c *Client) {
+		c.endpoint = endpoint
+	}
+}
+
+// WithSigningAlgorithm overrides the default signing algorithm (ED25519_SHA_512).
+func WithSigningAlgorithm(algorithm string) Option {
+	return func(c *Client) {
+		c.signingAlgorithm = algorithm
+	}
+}
+
+// New returns a new AWS KMS client for the given region.
+func New(region string, credentials Credentials, httpClient httpClient, opts ...Option) *Client {
+	c := &Client{
+		endpoint:         fmt.Sprintf("https://kms.%s.amazonaws.com", region),
+		region:           region,
+		credentials:      credentials,
+		signingAlgorithm: DefaultSigningAlgorithm,
+		httpClient:       httpClient,
+		now:              time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+type keyHandle struct {
+	keyID string
+}
+
+// Get returns a handle to the given key (a key ID, key ARN, alias name or alias ARN). The handle is used
+// with the Sign function.
+func (c *Client) Get(keyID string) (interface{}, error) {
+	if keyID == "" {
+		return nil, fmt.Errorf("key ID is required")
+	}
+
+	return &keyHandle{keyID: keyID}, nil
+}
+
+type getPublicKeyRequest struct {
+	KeyID string `json:"KeyId"`
+}
+
+type getPublicKeyResponse struct {
+	KeySpec   string `json:"KeySpec"`
+	PublicKey []byte `json:"PublicKey"`
+}
+
+// ExportPubKeyBytes returns the raw bytes of the Ed25519 public key for the given key.
+func (c *Client) ExportPubKeyBytes(keyID string) ([]byte, error) {
+	resp := &getPublicKeyResponse{}
+
+	if err := c.invoke(getPublicKeyTarget, &getPublicKeyRequest{KeyID: keyID}, resp); err != nil {
+		return nil, fmt.Errorf("get public key [%s]: %w", keyID, err)
+	}
+
+	pubKey, err := x509.ParsePKIXPublicKey(resp.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("parse public key [%s]: %w", keyID, err)
+	}
+
+	edPubKey, ok := pubKey.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported key spec [%s] for key [%s]", resp.KeySpec, keyID)
+	}
+
+	return edPubKey, nil
+}
+
+type signRequest struct {
+	KeyID            string `json:"KeyId"`
+	Message          []byte `json:"Message"`
+	MessageType      string `json:"MessageType"`
+	SigningAlgorithm string `json:"SigningAlgorithm"`
+}
+
+type signResponse struct {
+	Signature []byte `json:"Signature"`
+}
+
+// Sign signs the given message with the key referenced by the given key handle.
+func (c *Client) Sign(msg []byte, kh interface{}) ([]byte, error) {
+	handle, ok := kh.(*keyHandle)
+	if !ok {
+		return nil, fmt.Errorf("invalid key handle type: %T", kh)
+	}
+
+	resp := &signResponse{}
+
+	err := c.invoke(signTarget, &signRequest{
+		KeyID:            handle.keyID,
+		Message:          msg,
+		MessageType:      "RAW",
+		SigningAlgorithm: c.signingAlgorithm,
+	}, resp)
+	if err != nil {
+		return nil, fmt.Errorf("sign with key [%s]: %w", handle.keyID, err)
+	}
+
+	logger.Debugf("Signed message with key [%s]", handle.keyID)
+
+	return resp.Signature, nil
+}
+
+// invoke invokes the given AWS KMS action. Note that the JSON encoding of a byte slice is base64,
+// which is the encoding that AWS expects for binary values.
+func (c *Client) invoke(target string, request, response interface{}) error {
+	reqBytes, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("marshal request: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.endpoint+"/", bytes.NewReader(reqBytes))
+	if err != nil {
+		return fmt.Errorf("new request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set(targetHeader, target)
+
+	signV4(req, reqBytes, c.credentials, c.region, service, c.now())
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("%s: %w", target, err))
+	}
+
+	defer func() {
+		if e := resp.Body.Close(); e != nil {
+			logger.Warnf("Error closing response body: %s", e)
+		}
+	}()
+
+	respBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("read response: %w", err))
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		err = fmt.Errorf("%s returned status %d: %s", target, resp.StatusCode, respBytes)
+
+		if resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests {
+			return orberrors.NewTransient(err)
+		}
+
+		return err
+	}
+
+	if err := json.Unmarshal(respBytes, response); err != nil {
+		return fmt.Errorf("unmarshal response: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,219 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package aws
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+const keyID = "alias/orb-key"
+
+var creds = Credentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "secret"}
+
+func TestClient(t *testing.T) {
+	pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	pubKeyBytes, err := x509.MarshalPKIXPublicKey(pubKey)
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(newMockKMS(t, pubKeyBytes, privKey))
+	defer srv.Close()
+
+	c := New("us-east-1", creds, http.DefaultClient, WithEndpoint(srv.URL),
+		WithSigningAlgorithm(DefaultSigningAlgorithm))
+
+	t.Run("export public key", func(t *testing.T) {
+		keyBytes, err := c.ExportPubKeyBytes(keyID)
+		require.NoError(t, err)
+		require.Equal(t, []byte(pubKey), keyBytes)
+	})
+
+	t.Run("sign", func(t *testing.T) {
+		kh, err := c.Get(keyID)
+		require.NoError(t, err)
+
+		msg := []byte("message")
+
+		sig, err := c.Sign(msg, kh)
+		require.NoError(t, err)
+		require.True(t, ed25519.Verify(pubKey, msg, sig))
+	})
+
+	t.Run("get error", func(t *testing.T) {
+		_, err := c.Get("")
+		require.EqualError(t, err, "key ID is required")
+	})
+
+	t.Run("invalid key handle", func(t *testing.T) {
+		_, err := c.Sign([]byte("message"), "invalid")
+		require.EqualError(t, err, "invalid key handle type: string")
+	})
+
+	t.Run("key not found", func(t *testing.T) {
+		_, err := c.ExportPubKeyBytes("unknown")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "returned status 400")
+		require.False(t, orberrors.IsTransient(err))
+
+		kh, err := c.Get("unknown")
+		require.NoError(t, err)
+
+		_, err = c.Sign([]byte("message"), kh)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "returned status 400")
+	})
+
+	t.Run("connection error", func(t *testing.T) {
+		c := New("us-east-1", creds, http.DefaultClient, WithEndpoint("http://localhost:1"))
+
+		_, err := c.ExportPubKeyBytes(keyID)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+	})
+}
+
+func TestClient_InvalidResponse(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	ecPubKeyBytes, err := x509.MarshalPKIXPublicKey(&ecKey.PublicKey)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name     string
+		status   int
+		response interface{}
+		errMsg   string
+		sign     bool
+	}{
+		{name: "invalid JSON", status: http.StatusOK, response: "{", errMsg: "unmarshal response"},
+		{
+			name:     "invalid public key",
+			status:   http.StatusOK,
+			response: &getPublicKeyResponse{PublicKey: []byte("invalid")},
+			errMsg:   "parse public key",
+		},
+		{
+			name:     "unsupported key spec",
+			status:   http.StatusOK,
+			response: &getPublicKeyResponse{KeySpec: "ECC_NIST_P256", PublicKey: ecPubKeyBytes},
+			errMsg:   "unsupported key spec [ECC_NIST_P256]",
+		},
+		{
+			name:     "throttled",
+			status:   http.StatusTooManyRequests,
+			response: `{"__type":"ThrottlingException"}`,
+			errMsg:   "returned status 429",
+			sign:     true,
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+
+				var respBytes []byte
+
+				if s, ok := tc.response.(string); ok {
+					respBytes = []byte(s)
+				} else {
+					var err error
+
+					respBytes, err = json.Marshal(tc.response)
+					require.NoError(t, err)
+				}
+
+				_, err := w.Write(respBytes)
+				require.NoError(t, err)
+			}))
+			defer srv.Close()
+
+			c := New("us-east-1", creds, http.DefaultClient, WithEndpoint(srv.URL))
+
+			var err error
+
+			if tc.sign {
+				_, err = c.Sign([]byte("message"), &keyHandle{keyID: keyID})
+			} else {
+				_, err = c.ExportPubKeyBytes(keyID)
+			}
+
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.errMsg)
+		})
+	}
+}
+
+func newMockKMS(t *testing.T, pubKeyBytes []byte, privKey ed25519.PrivateKey) http.Handler {
+	t.Helper()
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, contentType, r.Header.Get("Content-Type"))
+		require.True(t, strings.HasPrefix(r.Header.Get(authorizationHeader),
+			"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/"))
+		require.NotEmpty(t, r.Header.Get(amzDateHeader))
+
+		reqBytes, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		var response interface{}
+
+		switch r.Header.Get(targetHeader) {
+		case getPublicKeyTarget:
+			req := &getPublicKeyRequest{}
+			require.NoError(t, json.Unmarshal(reqBytes, req))
+
+			if req.KeyID != keyID {
+				w.WriteHeader(http.StatusBadRequest)
+
+				return
+			}
+
+			response = &getPublicKeyResponse{KeySpec: "ECC_NIST_EDWARDS25519", PublicKey: pubKeyBytes}
+		case signTarget:
+			req := &signRequest{}
+			require.NoError(t, json.Unmarshal(reqBytes, req))
+
+			if req.KeyID != keyID {
+				w.WriteHeader(http.StatusBadRequest)
+
+				return
+			}
+
+			require.Equal(t, "RAW", req.MessageType)
+			require.Equal(t, DefaultSigningAlgorithm, req.SigningAlgorithm)
+
+			response = &signResponse{Signature: ed25519.Sign(privKey, req.Message)}
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		respBytes, err := json.Marshal(response)
+		require.NoError(t, err)
+
+		_, err = w.Write(respBytes)
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,221 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vault
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+var logger = log.New("vault-kms")
+
+const (
+	defaultMountPath = "transit"
+
+	tokenHeader = "X-Vault-Token"
+
+	keyTypeEd25519 = "ed25519"
+)
+
+type httpClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Client signs data with keys that are managed by the HashiCorp Vault Transit secrets engine. The private
+// keys never leave Vault. Only Ed25519 keys are supported. Client implements the subset of the Aries
+// KeyManager and Crypto interfaces that is required for signing.
+type Client struct {
+	url        string
+	token      string
+	mountPath  string
+	httpClient httpClient
+}
+
+// Option is a Vault client option.
+type Option func(c *Client)
+
+// WithMountPath sets the path at which the Transit secrets engine is mounted. The default is "transit".
+func WithMountPath(mountPath string) Option {
+	return func(c *Client) {
+		c.mountPath = strings.Trim(mountPath, "/")
+	}
+}
+
+// New returns a new Vault Transit client for the Vault server at the given URL.
+func New(url, token string, httpClient httpClient, opts ...Option) *Client {
+	c := &Client{
+		url:        strings.TrimSuffix(url, "/"),
+		token:      token,
+		mountPath:  defaultMountPath,
+		httpClient: httpClient,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+type keyHandle struct {
+	keyID string
+}
+
+// Get returns a handle to the given key. The handle is used with the Sign function.
+func (c *Client) Get(keyID string) (interface{}, error) {
+	if keyID == "" {
+		return nil, fmt.Errorf("key ID is required")
+	}
+
+	return &keyHandle{keyID: keyID}, nil
+}
+
+type keyResponse struct {
+	Data struct {
+		Type          string `json:"type"`
+		LatestVersion int    `json:"latest_version"`
+		Keys          map[string]struct {
+			PublicKey string `json:"public_key"`
+		} `json:"keys"`
+	} `json:"data"`
+}
+
+// ExportPubKeyBytes returns the raw bytes of the latest version of the public key for the given key.
+func (c *Client) ExportPubKeyBytes(keyID string) ([]byte, error) {
+	respBytes, err := c.do(http.MethodGet, fmt.Sprintf("%s/v1/%s/keys/%s", c.url, c.mountPath, keyID), nil)
+	if err != nil {
+		return nil, fmt.Errorf("read key [%s]: %w", keyID, err)
+	}
+
+	resp := &keyResponse{}
+
+	if err := json.Unmarshal(respBytes, resp); err != nil {
+		return nil, fmt.Errorf("unmarshal key [%s]: %w", keyID, err)
+	}
+
+	if resp.Data.Type != keyTypeEd25519 {
+		return nil, fmt.Errorf("unsupported type [%s] for key [%s]", resp.Data.Type, keyID)
+	}
+
+	key, ok := resp.Data.Keys[fmt.Sprint(resp.Data.LatestVersion)]
+	if !ok {
+		return nil, fmt.Errorf("version [%d] of key [%s] not found", resp.Data.LatestVersion, keyID)
+	}
+
+	pubKey, err := base64.StdEncoding.DecodeString(key.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("decode public key [%s]: %w", keyID, err)
+	}
+
+	return pubKey, nil
+}
+
+type signRequest struct {
+	Input string `json:"input"`
+}
+
+type signResponse struct {
+	Data struct {
+		Signature string `json:"signature"`
+	} `json:"data"`
+}
+
+// Sign signs the given message with the key referenced by the given key handle.
+func (c *Client) Sign(msg []byte, kh interface{}) ([]byte, error) {
+	handle, ok := kh.(*keyHandle)
+	if !ok {
+		return nil, fmt.Errorf("invalid key handle type: %T", kh)
+	}
+
+	reqBytes, err := json.Marshal(&signRequest{Input: base64.StdEncoding.EncodeToString(msg)})
+	if err != nil {
+		return nil, fmt.Errorf("marshal sign request: %w", err)
+	}
+
+	respBytes, err := c.do(http.MethodPost,
+		fmt.Sprintf("%s/v1/%s/sign/%s", c.url, c.mountPath, handle.keyID), reqBytes)
+	if err != nil {
+		return nil, fmt.Errorf("sign with key [%s]: %w", handle.keyID, err)
+	}
+
+	resp := &signResponse{}
+
+	if err := json.Unmarshal(respBytes, resp); err != nil {
+		return nil, fmt.Errorf("unmarshal sign response: %w", err)
+	}
+
+	// The signature has the format vault:v<version>:<base64 signature>.
+	parts := strings.Split(resp.Data.Signature, ":")
+	if len(parts) != 3 || parts[0] != "vault" {
+		return nil, fmt.Errorf("invalid signature format: %s", resp.Data.Signature)
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("decode signature: %w", err)
+	}
+
+	logger.Debugf("Signed message with key [%s], version [%s]", handle.keyID, parts[1])
+
+	return sig, nil
+}
+
+func (c *Client) do(method, url string, body []byte) ([]byte, error) {
+	var reqBody io.Reader
+	if body != nil {
+		reqBody = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequest(method, url, reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("new request: %w", err)
+	}
+
+	req.Header.Set(tokenHeader, c.token)
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, orberrors.NewTransient(fmt.Errorf("%s %s: %w", method, url, err))
+	}
+
+	defer func() {
+		if e := resp.Body.Close(); e != nil {
+			logger.Warnf("Error closing response body: %s", e)
+		}
+	}()
+
+	respBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, orberrors.NewTransient(fmt.Errorf("read response: %w", err))
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		err = fmt.Errorf("%s %s returned status %d: %s", method, url, resp.StatusCode, respBytes)
+
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return nil, orberrors.NewTransient(err)
+		}
+
+		return nil, err
+	}
+
+	return respBytes, nil
+}
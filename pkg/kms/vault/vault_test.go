@@ -0,0 +1,184 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vault
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+const (
+	token = "s.token"
+	keyID = "orb-key"
+)
+
+func TestClient(t *testing.T) {
+	pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(newMockVault(t, pubKey, privKey))
+	defer srv.Close()
+
+	c := New(srv.URL+"/", token, http.DefaultClient, WithMountPath("/orb-transit/"))
+
+	t.Run("export public key", func(t *testing.T) {
+		keyBytes, err := c.ExportPubKeyBytes(keyID)
+		require.NoError(t, err)
+		require.Equal(t, []byte(pubKey), keyBytes)
+	})
+
+	t.Run("sign", func(t *testing.T) {
+		kh, err := c.Get(keyID)
+		require.NoError(t, err)
+
+		msg := []byte("message")
+
+		sig, err := c.Sign(msg, kh)
+		require.NoError(t, err)
+		require.True(t, ed25519.Verify(pubKey, msg, sig))
+	})
+
+	t.Run("get error", func(t *testing.T) {
+		_, err := c.Get("")
+		require.EqualError(t, err, "key ID is required")
+	})
+
+	t.Run("invalid key handle", func(t *testing.T) {
+		_, err := c.Sign([]byte("message"), "invalid")
+		require.EqualError(t, err, "invalid key handle type: string")
+	})
+
+	t.Run("key not found", func(t *testing.T) {
+		_, err := c.ExportPubKeyBytes("unknown")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "returned status 404")
+		require.False(t, orberrors.IsTransient(err))
+
+		kh, err := c.Get("unknown")
+		require.NoError(t, err)
+
+		_, err = c.Sign([]byte("message"), kh)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "returned status 404")
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		c := New(srv.URL, "invalid", http.DefaultClient, WithMountPath("orb-transit"))
+
+		_, err := c.ExportPubKeyBytes(keyID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "returned status 403")
+	})
+
+	t.Run("connection error", func(t *testing.T) {
+		c := New("http://localhost:1", token, http.DefaultClient)
+
+		_, err := c.ExportPubKeyBytes(keyID)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+	})
+}
+
+func TestClient_InvalidResponse(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		response string
+		errMsg   string
+		sign     bool
+	}{
+		{name: "invalid key JSON", response: `{`, errMsg: "unmarshal key"},
+		{name: "unsupported key type", response: `{"data":{"type":"aes256-gcm96"}}`, errMsg: "unsupported type"},
+		{name: "version not found", response: `{"data":{"type":"ed25519","latest_version":2}}`, errMsg: "version [2]"},
+		{
+			name:     "invalid public key",
+			response: `{"data":{"type":"ed25519","latest_version":1,"keys":{"1":{"public_key":"%%%"}}}}`,
+			errMsg:   "decode public key",
+		},
+		{name: "invalid sign JSON", response: `{`, errMsg: "unmarshal sign response", sign: true},
+		{
+			name:     "invalid signature format",
+			response: `{"data":{"signature":"xxx"}}`,
+			errMsg:   "invalid signature format",
+			sign:     true,
+		},
+		{
+			name:     "invalid signature",
+			response: `{"data":{"signature":"vault:v1:%%%"}}`,
+			errMsg:   "decode signature",
+			sign:     true,
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, err := w.Write([]byte(tc.response))
+				require.NoError(t, err)
+			}))
+			defer srv.Close()
+
+			c := New(srv.URL, token, http.DefaultClient)
+
+			var err error
+
+			if tc.sign {
+				_, err = c.Sign([]byte("message"), &keyHandle{keyID: keyID})
+			} else {
+				_, err = c.ExportPubKeyBytes(keyID)
+			}
+
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.errMsg)
+		})
+	}
+}
+
+func newMockVault(t *testing.T, pubKey ed25519.PublicKey, privKey ed25519.PrivateKey) http.Handler {
+	t.Helper()
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/v1/orb-transit/keys/"+keyID, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(tokenHeader) != token {
+			w.WriteHeader(http.StatusForbidden)
+
+			return
+		}
+
+		_, err := fmt.Fprintf(w, `{"data":{"type":"ed25519","latest_version":1,"keys":{"1":{"public_key":"%s"}}}}`,
+			base64.StdEncoding.EncodeToString(pubKey))
+		require.NoError(t, err)
+	})
+
+	mux.HandleFunc("/v1/orb-transit/sign/"+keyID, func(w http.ResponseWriter, r *http.Request) {
+		reqBytes, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		req := &signRequest{}
+		require.NoError(t, json.Unmarshal(reqBytes, req))
+
+		msg, err := base64.StdEncoding.DecodeString(req.Input)
+		require.NoError(t, err)
+
+		_, err = fmt.Fprintf(w, `{"data":{"signature":"vault:v1:%s"}}`,
+			base64.StdEncoding.EncodeToString(ed25519.Sign(privKey, msg)))
+		require.NoError(t, err)
+	})
+
+	return mux
+}
@@ -0,0 +1,179 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ldcache
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+)
+
+// Path is the path of the endpoint that lists and pins JSON-LD contexts.
+const Path = "/contextpins"
+
+const (
+	badRequestResponse          = "Bad Request."
+	badGatewayResponse          = "Unable to retrieve remote context."
+	internalServerErrorResponse = "Internal Server Error."
+)
+
+type pinner interface {
+	Pin(u string, content []byte) (*Pin, error)
+	Pins() ([]*Pin, error)
+}
+
+// PinRequest is the request to pin a context. If Content isn't specified then the context is retrieved from URL.
+type PinRequest struct {
+	URL     string          `json:"url"`
+	Content json.RawMessage `json:"content,omitempty"`
+}
+
+// Pins implements a REST handler that returns the pinned contexts.
+type Pins struct {
+	pinner  pinner
+	marshal func(v interface{}) ([]byte, error)
+}
+
+// NewPins returns a new pinned contexts handler.
+func NewPins(p pinner) *Pins {
+	return &Pins{
+		pinner:  p,
+		marshal: json.Marshal,
+	}
+}
+
+// Path returns the HTTP REST endpoint of the handler.
+func (h *Pins) Path() string {
+	return Path
+}
+
+// Method returns the HTTP method, which is always GET.
+func (h *Pins) Method() string {
+	return http.MethodGet
+}
+
+// Handler returns the HTTP REST handle.
+func (h *Pins) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Pins) handle(w http.ResponseWriter, _ *http.Request) {
+	pins, err := h.pinner.Pins()
+	if err != nil {
+		logger.Errorf("[%s] Error retrieving pinned contexts: %s", Path, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	if pins == nil {
+		pins = []*Pin{}
+	}
+
+	writeJSON(w, h.marshal, pins)
+}
+
+// AddPin implements a REST handler that pins a context, i.e. permanently adds the context to the cache so that
+// it's never retrieved from the remote host at runtime.
+type AddPin struct {
+	pinner  pinner
+	marshal func(v interface{}) ([]byte, error)
+}
+
+// NewAddPin returns a new handler that pins a context.
+func NewAddPin(p pinner) *AddPin {
+	return &AddPin{
+		pinner:  p,
+		marshal: json.Marshal,
+	}
+}
+
+// Path returns the HTTP REST endpoint of the handler.
+func (h *AddPin) Path() string {
+	return Path
+}
+
+// Method returns the HTTP method, which is always POST.
+func (h *AddPin) Method() string {
+	return http.MethodPost
+}
+
+// Handler returns the HTTP REST handle.
+func (h *AddPin) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *AddPin) handle(w http.ResponseWriter, req *http.Request) {
+	reqBytes, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		logger.Errorf("[%s] Error reading request body: %s", Path, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	pinReq := &PinRequest{}
+
+	err = json.Unmarshal(reqBytes, pinReq)
+	if err != nil || pinReq.URL == "" {
+		logger.Debugf("[%s] Invalid request: %s", Path, reqBytes)
+
+		writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	pin, err := h.pinner.Pin(pinReq.URL, pinReq.Content)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidContext):
+			logger.Debugf("[%s] Invalid context [%s]: %s", Path, pinReq.URL, err)
+
+			writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+		case errors.Is(err, ErrRetrieveContext):
+			logger.Warnf("[%s] Error retrieving context [%s]: %s", Path, pinReq.URL, err)
+
+			writeResponse(w, http.StatusBadGateway, []byte(badGatewayResponse))
+		default:
+			logger.Errorf("[%s] Error pinning context [%s]: %s", Path, pinReq.URL, err)
+
+			writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+		}
+
+		return
+	}
+
+	writeJSON(w, h.marshal, pin)
+}
+
+func writeJSON(w http.ResponseWriter, marshal func(v interface{}) ([]byte, error), v interface{}) {
+	respBytes, err := marshal(v)
+	if err != nil {
+		logger.Errorf("[%s] Error marshalling response: %s", Path, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	writeResponse(w, http.StatusOK, respBytes)
+}
+
+func writeResponse(w http.ResponseWriter, status int, body []byte) {
+	w.WriteHeader(status)
+
+	if len(body) > 0 {
+		if _, err := w.Write(body); err != nil {
+			logger.Warnf("[%s] Unable to write response: %s", Path, err)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ldcache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/internal/testutil/httptestutil"
+)
+
+func TestPins(t *testing.T) {
+	l := newTestLoader(t, Config{}, http.DefaultClient)
+
+	h := NewPins(l)
+	require.Equal(t, Path, h.Path())
+	require.Equal(t, http.MethodGet, h.Method())
+	require.NotNil(t, h.Handler())
+
+	t.Run("Empty", func(t *testing.T) {
+		code, body := httptestutil.Get(t, h.handle, Path)
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, "[]", string(body))
+	})
+
+	_, err := l.Pin("https://example.com/contexts/v2", []byte(testContext))
+	require.NoError(t, err)
+
+	_, err = l.Pin("https://example.com/contexts/v1", []byte(testContext))
+	require.NoError(t, err)
+
+	t.Run("Success", func(t *testing.T) {
+		code, body := httptestutil.Get(t, h.handle, Path)
+		require.Equal(t, http.StatusOK, code)
+
+		var pins []*Pin
+		require.NoError(t, json.Unmarshal(body, &pins))
+		require.Len(t, pins, 2)
+		require.Equal(t, "https://example.com/contexts/v1", pins[0].URL)
+		require.Equal(t, "https://example.com/contexts/v2", pins[1].URL)
+		require.Equal(t, SourceProvided, pins[0].Source)
+	})
+
+	t.Run("Pinner error", func(t *testing.T) {
+		code, _ := httptestutil.Get(t, NewPins(&mockPinner{err: errors.New("injected error")}).handle, Path)
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		h := NewPins(l)
+		h.marshal = func(v interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		code, _ := httptestutil.Get(t, h.handle, Path)
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+}
+
+func TestAddPin(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/context1" {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		_, err := w.Write([]byte(testContext))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	l := newTestLoader(t, Config{}, server.Client())
+
+	h := NewAddPin(l)
+	require.Equal(t, Path, h.Path())
+	require.Equal(t, http.MethodPost, h.Method())
+	require.NotNil(t, h.Handler())
+
+	t.Run("Provided content", func(t *testing.T) {
+		code, body := httptestutil.Serve(t, h.handle, http.MethodPost, Path,
+			newPinRequest(t, "https://example.com/contexts/v1", testContext), nil)
+		require.Equal(t, http.StatusOK, code)
+
+		pin := &Pin{}
+		require.NoError(t, json.Unmarshal(body, pin))
+		require.Equal(t, "https://example.com/contexts/v1", pin.URL)
+		require.Equal(t, SourceProvided, pin.Source)
+	})
+
+	t.Run("Remote context", func(t *testing.T) {
+		code, body := httptestutil.Serve(t, h.handle, http.MethodPost, Path,
+			newPinRequest(t, server.URL+"/context1", ""), nil)
+		require.Equal(t, http.StatusOK, code)
+
+		pin := &Pin{}
+		require.NoError(t, json.Unmarshal(body, pin))
+		require.Equal(t, SourceRemote, pin.Source)
+	})
+
+	t.Run("Invalid request", func(t *testing.T) {
+		code, _ := httptestutil.Serve(t, h.handle, http.MethodPost, Path, []byte("invalid"), nil)
+		require.Equal(t, http.StatusBadRequest, code)
+
+		code, _ = httptestutil.Serve(t, h.handle, http.MethodPost, Path, newPinRequest(t, "", testContext), nil)
+		require.Equal(t, http.StatusBadRequest, code)
+	})
+
+	t.Run("Invalid context", func(t *testing.T) {
+		code, _ := httptestutil.Serve(t, h.handle, http.MethodPost, Path,
+			newPinRequest(t, "https://example.com/contexts/v1", `{"name":"value"}`), nil)
+		require.Equal(t, http.StatusBadRequest, code)
+	})
+
+	t.Run("Retrieve error", func(t *testing.T) {
+		code, _ := httptestutil.Serve(t, h.handle, http.MethodPost, Path,
+			newPinRequest(t, server.URL+"/unknown", ""), nil)
+		require.Equal(t, http.StatusBadGateway, code)
+	})
+
+	t.Run("Pinner error", func(t *testing.T) {
+		code, _ := httptestutil.Serve(t, NewAddPin(&mockPinner{err: errors.New("injected error")}).handle,
+			http.MethodPost, Path, newPinRequest(t, "https://example.com/contexts/v1", testContext), nil)
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+
+	t.Run("Read body error", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+
+		h.handle(rw, httptest.NewRequest(http.MethodPost, Path, &errReader{}))
+
+		result := rw.Result()
+		require.NoError(t, result.Body.Close())
+		require.Equal(t, http.StatusInternalServerError, result.StatusCode)
+	})
+}
+
+func newPinRequest(t *testing.T, u, content string) []byte {
+	t.Helper()
+
+	req := &PinRequest{URL: u}
+
+	if content != "" {
+		req.Content = json.RawMessage(content)
+	}
+
+	reqBytes, err := json.Marshal(req)
+	require.NoError(t, err)
+
+	return reqBytes
+}
+
+type mockPinner struct {
+	err error
+}
+
+func (m *mockPinner) Pin(string, []byte) (*Pin, error) {
+	return nil, m.err
+}
+
+func (m *mockPinner) Pins() ([]*Pin, error) {
+	return nil, m.err
+}
+
+type errReader struct{}
+
+func (r *errReader) Read([]byte) (int, error) {
+	return 0, fmt.Errorf("injected read error: %w", io.ErrUnexpectedEOF)
+}
@@ -0,0 +1,238 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ldcache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/ldcontext"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	jsonld "github.com/piprate/json-gold/ld"
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+var logger = log.New("ldcache")
+
+var (
+	// ErrInvalidContext is returned if the content of a context is not a valid JSON-LD context document.
+	ErrInvalidContext = errors.New("invalid JSON-LD context document")
+	// ErrRetrieveContext is returned from Pin if the context could not be retrieved from its URL.
+	ErrRetrieveContext = errors.New("unable to retrieve remote context")
+)
+
+const (
+	defaultFetchTimeout = 10 * time.Second
+	// maxContextSize is the maximum size of a remote context document.
+	maxContextSize = 1 << 20
+
+	contextProperty = "@context"
+)
+
+type contextStore interface {
+	Import(documents []ldcontext.Document) error
+}
+
+type httpClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Config contains the configuration of the document loader.
+type Config struct {
+	// FetchRemote indicates whether or not a context that isn't in the cache is retrieved from its URL (and then
+	// added to the cache). If false then an unknown context results in an error and the context must be pinned
+	// using the admin API.
+	FetchRemote bool
+	// FetchTimeout is the timeout for retrieving a remote context. Defaults to 10 seconds.
+	FetchTimeout time.Duration
+}
+
+// Loader is a JSON-LD document loader that loads contexts from a persistent context cache. The cache is preloaded
+// with the Orb, ActivityStreams and security contexts (by the underlying loader). Additional contexts may be pinned
+// (i.e. permanently added to the cache) using Pin. If remote fetching is enabled then a context that isn't in the
+// cache is retrieved once and added to the cache so that subsequent loads don't depend on the remote host.
+type Loader struct {
+	loader       jsonld.DocumentLoader
+	store        contextStore
+	pins         *pinStore
+	httpClient   httpClient
+	fetchRemote  bool
+	fetchTimeout time.Duration
+}
+
+// New returns a new caching document loader. The given loader must load contexts from the given context store.
+func New(cfg Config, loader jsonld.DocumentLoader, store contextStore, provider storage.Provider,
+	client httpClient) (*Loader, error) {
+	pins, err := newPinStore(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	fetchTimeout := cfg.FetchTimeout
+	if fetchTimeout == 0 {
+		fetchTimeout = defaultFetchTimeout
+	}
+
+	return &Loader{
+		loader:       loader,
+		store:        store,
+		pins:         pins,
+		httpClient:   client,
+		fetchRemote:  cfg.FetchRemote,
+		fetchTimeout: fetchTimeout,
+	}, nil
+}
+
+// LoadDocument returns the context document for the given URL from the cache. If the document isn't in the cache
+// and remote fetching is enabled then the document is retrieved and added to the cache.
+func (l *Loader) LoadDocument(u string) (*jsonld.RemoteDocument, error) {
+	doc, err := l.loader.LoadDocument(u)
+	if err == nil {
+		return doc, nil
+	}
+
+	if !l.fetchRemote {
+		return nil, err
+	}
+
+	logger.Debugf("Context [%s] not found in cache (%s). Retrieving remote context.", u, err)
+
+	content, e := l.fetch(u)
+	if e != nil {
+		return nil, fmt.Errorf("context [%s] not found in cache and remote retrieval failed: %w", u, e)
+	}
+
+	e = l.add(u, content)
+	if e != nil {
+		return nil, e
+	}
+
+	logger.Infof("Added remote context [%s] to the cache", u)
+
+	return l.loader.LoadDocument(u)
+}
+
+// Pin adds the context with the given URL to the cache. If content is empty then the context is retrieved from
+// the URL.
+func (l *Loader) Pin(u string, content []byte) (*Pin, error) {
+	if _, err := url.ParseRequestURI(u); err != nil {
+		return nil, fmt.Errorf("%w: invalid URL [%s]: %s", ErrInvalidContext, u, err)
+	}
+
+	source := SourceProvided
+
+	if len(content) == 0 {
+		fetched, err := l.fetch(u)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrRetrieveContext, err)
+		}
+
+		content = fetched
+		source = SourceRemote
+	}
+
+	err := l.add(u, content)
+	if err != nil {
+		return nil, err
+	}
+
+	pin := &Pin{
+		URL:    u,
+		Source: source,
+		Pinned: time.Now().UTC(),
+	}
+
+	err = l.pins.put(pin)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Infof("Pinned context [%s] (source: %s)", u, source)
+
+	return pin, nil
+}
+
+// Pins returns the contexts that were pinned using the admin API.
+func (l *Loader) Pins() ([]*Pin, error) {
+	return l.pins.getAll()
+}
+
+func (l *Loader) add(u string, content []byte) error {
+	err := validate(content)
+	if err != nil {
+		return fmt.Errorf("context [%s]: %w", u, err)
+	}
+
+	err = l.store.Import([]ldcontext.Document{{URL: u, Content: content}})
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("import context [%s]: %w", u, err))
+	}
+
+	return nil
+}
+
+func (l *Loader) fetch(u string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), l.fetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("new request for context [%s]: %w", u, err)
+	}
+
+	req.Header.Set("Accept", "application/ld+json, application/json")
+
+	resp, err := l.httpClient.Do(req)
+	if err != nil {
+		return nil, orberrors.NewTransient(fmt.Errorf("retrieve context [%s]: %w", u, err))
+	}
+
+	defer func() {
+		if e := resp.Body.Close(); e != nil {
+			logger.Warnf("Error closing response body: %s", e)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("retrieve context [%s]: unexpected status code %d", u, resp.StatusCode)
+	}
+
+	content, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxContextSize+1))
+	if err != nil {
+		return nil, orberrors.NewTransient(fmt.Errorf("read context [%s]: %w", u, err))
+	}
+
+	if len(content) > maxContextSize {
+		return nil, fmt.Errorf("context [%s] exceeds the maximum size of %d bytes", u, maxContextSize)
+	}
+
+	return content, nil
+}
+
+func validate(content []byte) error {
+	doc := make(map[string]interface{})
+
+	err := json.Unmarshal(content, &doc)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidContext, err)
+	}
+
+	if _, ok := doc[contextProperty]; !ok {
+		return fmt.Errorf("%w: missing %s property", ErrInvalidContext, contextProperty)
+	}
+
+	return nil
+}
@@ -0,0 +1,323 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ldcache
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/ld"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/ldcontext"
+	ldstore "github.com/hyperledger/aries-framework-go/pkg/store/ld"
+	"github.com/stretchr/testify/require"
+
+	orbldcontext "github.com/trustbloc/orb/internal/pkg/ldcontext"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+const (
+	activityStreamsContext = "https://www.w3.org/ns/activitystreams"
+	securityContext        = "https://w3id.org/security/v1"
+
+	testContext = `{"@context":{"name":"http://schema.org/name"}}`
+)
+
+func TestNew(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		l, err := New(Config{}, nil, nil, mem.NewProvider(), http.DefaultClient)
+		require.NoError(t, err)
+		require.NotNil(t, l)
+		require.Equal(t, defaultFetchTimeout, l.fetchTimeout)
+	})
+
+	t.Run("Open store error", func(t *testing.T) {
+		p := &mocks.Provider{}
+		p.OpenStoreReturns(nil, errors.New("injected open error"))
+
+		_, err := New(Config{}, nil, nil, p, http.DefaultClient)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected open error")
+	})
+
+	t.Run("Set store config error", func(t *testing.T) {
+		p := &mocks.Provider{}
+		p.SetStoreConfigReturns(errors.New("injected config error"))
+
+		_, err := New(Config{}, nil, nil, p, http.DefaultClient)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected config error")
+	})
+}
+
+func TestLoader_LoadDocument(t *testing.T) {
+	var requests int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&requests, 1)
+
+		switch req.URL.Path {
+		case "/context1":
+			_, err := w.Write([]byte(testContext))
+			require.NoError(t, err)
+		case "/invalid":
+			_, err := w.Write([]byte(`{"name":"value"}`))
+			require.NoError(t, err)
+		case "/large":
+			_, err := w.Write([]byte(`{"@context":"` + strings.Repeat("x", maxContextSize) + `"}`))
+			require.NoError(t, err)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Run("Preloaded contexts", func(t *testing.T) {
+		l := newTestLoader(t, Config{}, server.Client())
+
+		for _, u := range []string{activityStreamsContext, securityContext} {
+			doc, err := l.LoadDocument(u)
+			require.NoError(t, err, u)
+			require.NotNil(t, doc)
+		}
+
+		for _, c := range orbldcontext.MustGetAll() {
+			doc, err := l.LoadDocument(c.URL)
+			require.NoError(t, err, c.URL)
+			require.NotNil(t, doc)
+		}
+
+		require.Zero(t, atomic.LoadInt32(&requests))
+	})
+
+	t.Run("Remote fetch disabled -> error", func(t *testing.T) {
+		l := newTestLoader(t, Config{}, server.Client())
+
+		_, err := l.LoadDocument(server.URL + "/context1")
+		require.Error(t, err)
+	})
+
+	t.Run("Remote fetch enabled -> cached", func(t *testing.T) {
+		atomic.StoreInt32(&requests, 0)
+
+		l := newTestLoader(t, Config{FetchRemote: true}, server.Client())
+
+		for i := 0; i < 3; i++ {
+			doc, err := l.LoadDocument(server.URL + "/context1")
+			require.NoError(t, err)
+			require.NotNil(t, doc)
+		}
+
+		require.Equal(t, int32(1), atomic.LoadInt32(&requests), "the context should have been retrieved only once")
+	})
+
+	t.Run("Remote fetch errors", func(t *testing.T) {
+		l := newTestLoader(t, Config{FetchRemote: true}, server.Client())
+
+		_, err := l.LoadDocument(server.URL + "/unknown")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unexpected status code 404")
+
+		_, err = l.LoadDocument(server.URL + "/invalid")
+		require.ErrorIs(t, err, ErrInvalidContext)
+
+		_, err = l.LoadDocument(server.URL + "/large")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "exceeds the maximum size")
+	})
+
+	t.Run("HTTP client error", func(t *testing.T) {
+		l := newTestLoader(t, Config{FetchRemote: true}, &mockHTTPClient{err: errors.New("injected client error")})
+
+		_, err := l.LoadDocument(server.URL + "/context1")
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "injected client error")
+	})
+}
+
+func TestLoader_Pin(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/context1" {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		_, err := w.Write([]byte(testContext))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	t.Run("Remote context", func(t *testing.T) {
+		l := newTestLoader(t, Config{}, server.Client())
+
+		pin, err := l.Pin(server.URL+"/context1", nil)
+		require.NoError(t, err)
+		require.Equal(t, server.URL+"/context1", pin.URL)
+		require.Equal(t, SourceRemote, pin.Source)
+
+		// Remote fetching is disabled but the context is pinned.
+		doc, err := l.LoadDocument(server.URL + "/context1")
+		require.NoError(t, err)
+		require.NotNil(t, doc)
+
+		pins, err := l.Pins()
+		require.NoError(t, err)
+		require.Len(t, pins, 1)
+		require.Equal(t, server.URL+"/context1", pins[0].URL)
+	})
+
+	t.Run("Provided content", func(t *testing.T) {
+		l := newTestLoader(t, Config{}, server.Client())
+
+		const u = "https://example.com/contexts/v1"
+
+		pin, err := l.Pin(u, []byte(testContext))
+		require.NoError(t, err)
+		require.Equal(t, SourceProvided, pin.Source)
+
+		doc, err := l.LoadDocument(u)
+		require.NoError(t, err)
+		require.NotNil(t, doc)
+	})
+
+	t.Run("Invalid URL", func(t *testing.T) {
+		l := newTestLoader(t, Config{}, server.Client())
+
+		_, err := l.Pin("invalid", []byte(testContext))
+		require.ErrorIs(t, err, ErrInvalidContext)
+	})
+
+	t.Run("Invalid content", func(t *testing.T) {
+		l := newTestLoader(t, Config{}, server.Client())
+
+		_, err := l.Pin("https://example.com/contexts/v1", []byte(`{"name":"value"}`))
+		require.ErrorIs(t, err, ErrInvalidContext)
+
+		_, err = l.Pin("https://example.com/contexts/v1", []byte(`invalid`))
+		require.ErrorIs(t, err, ErrInvalidContext)
+	})
+
+	t.Run("Retrieve error", func(t *testing.T) {
+		l := newTestLoader(t, Config{}, server.Client())
+
+		_, err := l.Pin(server.URL+"/unknown", nil)
+		require.ErrorIs(t, err, ErrRetrieveContext)
+	})
+
+	t.Run("Import error", func(t *testing.T) {
+		l := newTestLoader(t, Config{}, server.Client())
+		l.store = &mockContextStore{err: errors.New("injected import error")}
+
+		_, err := l.Pin("https://example.com/contexts/v1", []byte(testContext))
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "injected import error")
+	})
+
+	t.Run("Pin store errors", func(t *testing.T) {
+		s := &mocks.Store{}
+		s.PutReturns(errors.New("injected put error"))
+		s.QueryReturns(nil, errors.New("injected query error"))
+
+		p := &mocks.Provider{}
+		p.OpenStoreReturns(s, nil)
+
+		l := newTestLoader(t, Config{}, server.Client())
+
+		pins, err := newPinStore(p)
+		require.NoError(t, err)
+
+		l.pins = pins
+
+		_, err = l.Pin("https://example.com/contexts/v1", []byte(testContext))
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "injected put error")
+
+		_, err = l.Pins()
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "injected query error")
+
+		it := &mocks.Iterator{}
+		it.NextReturns(false, errors.New("injected next error"))
+
+		s.QueryReturns(it, nil)
+
+		_, err = l.Pins()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected next error")
+
+		it = &mocks.Iterator{}
+		it.NextReturns(true, nil)
+		it.ValueReturns(nil, errors.New("injected value error"))
+
+		s.QueryReturns(it, nil)
+
+		_, err = l.Pins()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected value error")
+	})
+}
+
+type ldProvider struct {
+	contextStore        ldstore.ContextStore
+	remoteProviderStore ldstore.RemoteProviderStore
+}
+
+func (p *ldProvider) JSONLDContextStore() ldstore.ContextStore {
+	return p.contextStore
+}
+
+func (p *ldProvider) JSONLDRemoteProviderStore() ldstore.RemoteProviderStore {
+	return p.remoteProviderStore
+}
+
+func newTestLoader(t *testing.T, cfg Config, client httpClient) *Loader {
+	t.Helper()
+
+	contextStore, err := ldstore.NewContextStore(mem.NewProvider())
+	require.NoError(t, err)
+
+	remoteProviderStore, err := ldstore.NewRemoteProviderStore(mem.NewProvider())
+	require.NoError(t, err)
+
+	loader, err := ld.NewDocumentLoader(
+		&ldProvider{contextStore: contextStore, remoteProviderStore: remoteProviderStore},
+		ld.WithExtraContexts(orbldcontext.MustGetAll()...),
+	)
+	require.NoError(t, err)
+
+	l, err := New(cfg, loader, contextStore, mem.NewProvider(), client)
+	require.NoError(t, err)
+
+	return l
+}
+
+type mockContextStore struct {
+	err error
+}
+
+func (m *mockContextStore) Import([]ldcontext.Document) error {
+	return m.err
+}
+
+type mockHTTPClient struct {
+	err error
+}
+
+func (m *mockHTTPClient) Do(*http.Request) (*http.Response, error) {
+	return nil, fmt.Errorf("do request: %w", m.err)
+}
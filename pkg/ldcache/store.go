@@ -0,0 +1,120 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ldcache
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+const (
+	storeName = "jsonld-context-pin"
+	pinTag    = "pin"
+)
+
+// Source indicates where the content of a pinned context came from.
+type Source string
+
+const (
+	// SourceRemote indicates that the context was retrieved from its URL when it was pinned.
+	SourceRemote Source = "remote"
+	// SourceProvided indicates that the content of the context was provided when it was pinned.
+	SourceProvided Source = "provided"
+)
+
+// Pin contains information about a pinned context.
+type Pin struct {
+	URL    string    `json:"url"`
+	Source Source    `json:"source"`
+	Pinned time.Time `json:"pinned"`
+}
+
+// pinStore persists the list of pinned contexts. (The content of the contexts is held in the context store.)
+type pinStore struct {
+	store storage.Store
+}
+
+func newPinStore(provider storage.Provider) (*pinStore, error) {
+	s, err := provider.OpenStore(storeName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open context pin store: %w", err)
+	}
+
+	err = provider.SetStoreConfig(storeName, storage.StoreConfiguration{TagNames: []string{pinTag}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set store configuration: %w", err)
+	}
+
+	return &pinStore{store: s}, nil
+}
+
+func (s *pinStore) put(pin *Pin) error {
+	value, err := json.Marshal(pin)
+	if err != nil {
+		return fmt.Errorf("marshal context pin: %w", err)
+	}
+
+	err = s.store.Put(pin.URL, value, storage.Tag{Name: pinTag})
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("store context pin [%s]: %w", pin.URL, err))
+	}
+
+	return nil
+}
+
+// getAll returns all pins sorted by URL.
+func (s *pinStore) getAll() ([]*Pin, error) {
+	it, err := s.store.Query(pinTag)
+	if err != nil {
+		return nil, orberrors.NewTransient(fmt.Errorf("query context pins: %w", err))
+	}
+
+	defer func() {
+		if e := it.Close(); e != nil {
+			logger.Warnf("Error closing iterator: %s", e)
+		}
+	}()
+
+	var pins []*Pin
+
+	for {
+		ok, e := it.Next()
+		if e != nil {
+			return nil, orberrors.NewTransient(fmt.Errorf("next context pin: %w", e))
+		}
+
+		if !ok {
+			break
+		}
+
+		value, e := it.Value()
+		if e != nil {
+			return nil, orberrors.NewTransient(fmt.Errorf("get context pin from iterator: %w", e))
+		}
+
+		pin := &Pin{}
+
+		e = json.Unmarshal(value, pin)
+		if e != nil {
+			return nil, fmt.Errorf("unmarshal context pin: %w", e)
+		}
+
+		pins = append(pins, pin)
+	}
+
+	sort.Slice(pins, func(i, j int) bool {
+		return pins[i].URL < pins[j].URL
+	})
+
+	return pins, nil
+}
@@ -0,0 +1,372 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package leaderelection
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	"github.com/trustbloc/orb/pkg/lifecycle"
+)
+
+var logger = log.New("leader-election")
+
+const (
+	coordinationLeaseKey = "leader-lease"
+
+	// DefaultLeaseDuration is the default duration of the leader's lease. If the leader doesn't renew the lease
+	// within this duration then another instance takes over.
+	DefaultLeaseDuration = 30 * time.Second
+
+	// The lease is renewed a number of times within the lease duration so that a single missed
+	// renewal doesn't result in a change of leader.
+	renewalsPerLease = 3
+
+	// The maximum time to wait after claiming the lease before verifying that the claim was not
+	// overwritten by another instance.
+	maxSettleDelay = 2 * time.Second
+)
+
+// lease is stored within the coordination store and identifies the instance that is currently the leader.
+type lease struct {
+	// Holder is the ID of the instance that holds the lease.
+	Holder string `json:"holder"`
+	// Term is incremented each time that the lease is acquired by a different instance.
+	Term uint64 `json:"term"`
+	// AcquiredTime is when the holder acquired the lease (Unix time in milliseconds).
+	AcquiredTime int64 `json:"acquiredTime"`
+	// RenewedTime is when the holder last renewed the lease (Unix time in milliseconds).
+	RenewedTime int64 `json:"renewedTime"`
+}
+
+// Status contains the leader election status.
+type Status struct {
+	// Name is the name of the election.
+	Name string `json:"name"`
+	// InstanceID is the ID of this instance.
+	InstanceID string `json:"instanceId"`
+	// IsLeader indicates whether this instance is the leader.
+	IsLeader bool `json:"isLeader"`
+	// Leader is the ID of the instance that is currently the leader. This field is empty if there's no leader.
+	Leader string `json:"leader,omitempty"`
+	// Term is the current term of the leader.
+	Term uint64 `json:"term,omitempty"`
+	// Acquired is when the leader acquired the lease.
+	Acquired *time.Time `json:"acquired,omitempty"`
+	// Renewed is when the leader last renewed the lease.
+	Renewed *time.Time `json:"renewed,omitempty"`
+	// Expires is when the lease expires if it's not renewed.
+	Expires *time.Time `json:"expires,omitempty"`
+}
+
+type options struct {
+	leaseDuration time.Duration
+}
+
+// Opt sets a leader election option.
+type Opt func(opts *options)
+
+// WithLeaseDuration sets the duration of the leader's lease.
+func WithLeaseDuration(d time.Duration) Opt {
+	return func(opts *options) {
+		opts.leaseDuration = d
+	}
+}
+
+// Elector elects a single leader among the Orb instances in a cluster. Each instance periodically attempts
+// to acquire (or, if it's the leader, renew) a lease in the coordination store, which must be shared by all
+// instances. If the leader stops renewing its lease (e.g. the instance is down) then another instance acquires
+// the lease after it expires. Since the store doesn't support atomic compare-and-swap, an instance that claims
+// the lease waits a short time and then reads the lease back to verify that its claim wasn't overwritten by a
+// competing instance before assuming leadership. The leader steps down if it fails to renew its lease.
+type Elector struct {
+	*lifecycle.Lifecycle
+
+	name          string
+	instanceID    string
+	store         storage.Store
+	leaseDuration time.Duration
+	renewInterval time.Duration
+	settleDelay   time.Duration
+	done          chan struct{}
+
+	mutex    sync.RWMutex
+	isLeader bool
+}
+
+// New returns a new leader elector for the given election name.
+func New(name, instanceID string, coordinationStore storage.Store, opts ...Opt) *Elector {
+	options := &options{leaseDuration: DefaultLeaseDuration}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if options.leaseDuration <= 0 {
+		options.leaseDuration = DefaultLeaseDuration
+	}
+
+	settleDelay := options.leaseDuration / 10 //nolint:gomnd
+
+	if settleDelay > maxSettleDelay {
+		settleDelay = maxSettleDelay
+	}
+
+	e := &Elector{
+		name:          name,
+		instanceID:    instanceID,
+		store:         coordinationStore,
+		leaseDuration: options.leaseDuration,
+		renewInterval: options.leaseDuration / renewalsPerLease,
+		settleDelay:   settleDelay,
+		done:          make(chan struct{}),
+	}
+
+	e.Lifecycle = lifecycle.New("leader-election-"+name,
+		lifecycle.WithStart(e.start),
+		lifecycle.WithStop(e.stop),
+	)
+
+	return e
+}
+
+// IsLeader returns true if this instance is currently the leader.
+func (e *Elector) IsLeader() bool {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	return e.isLeader
+}
+
+// Status returns the leader election status.
+func (e *Elector) Status() (*Status, error) {
+	status := &Status{
+		Name:       e.name,
+		InstanceID: e.instanceID,
+		IsLeader:   e.IsLeader(),
+	}
+
+	l, err := e.getLease()
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return status, nil
+		}
+
+		return nil, err
+	}
+
+	renewed := time.Unix(0, l.RenewedTime*int64(time.Millisecond))
+
+	if e.isExpired(l, time.Now()) {
+		return status, nil
+	}
+
+	acquired := time.Unix(0, l.AcquiredTime*int64(time.Millisecond))
+	expires := renewed.Add(e.leaseDuration)
+
+	status.Leader = l.Holder
+	status.Term = l.Term
+	status.Acquired = &acquired
+	status.Renewed = &renewed
+	status.Expires = &expires
+
+	return status, nil
+}
+
+func (e *Elector) start() {
+	logger.Infof("[%s] Starting leader election [%s] - Lease duration: %s, Renew interval: %s",
+		e.instanceID, e.name, e.leaseDuration, e.renewInterval)
+
+	go func() {
+		e.check()
+
+		for {
+			select {
+			case <-time.After(e.renewInterval):
+				e.check()
+			case <-e.done:
+				logger.Debugf("[%s] Stopped leader election [%s]", e.instanceID, e.name)
+
+				return
+			}
+		}
+	}()
+}
+
+func (e *Elector) stop() {
+	close(e.done)
+
+	if !e.IsLeader() {
+		return
+	}
+
+	e.setLeader(false, 0)
+
+	// Resign so that another instance may take over immediately rather than waiting for the lease to expire.
+	l, err := e.getLease()
+	if err != nil || l.Holder != e.instanceID {
+		return
+	}
+
+	err = e.store.Delete(e.leaseKey())
+	if err != nil {
+		logger.Warnf("[%s] Error resigning leadership of [%s]: %s", e.instanceID, e.name, err)
+
+		return
+	}
+
+	logger.Infof("[%s] Resigned leadership of [%s]", e.instanceID, e.name)
+}
+
+func (e *Elector) check() {
+	isLeader, term, err := e.acquireOrRenew()
+	if err != nil {
+		logger.Warnf("[%s] Error acquiring or renewing the lease for [%s]: %s", e.instanceID, e.name, err)
+
+		// Step down to be safe since another instance may take over if the lease can't be renewed.
+		e.setLeader(false, 0)
+
+		return
+	}
+
+	e.setLeader(isLeader, term)
+}
+
+func (e *Elector) acquireOrRenew() (bool, uint64, error) {
+	now := time.Now()
+
+	current, err := e.getLease()
+	if err != nil && !errors.Is(err, storage.ErrDataNotFound) {
+		return false, 0, err
+	}
+
+	if current != nil && current.Holder == e.instanceID {
+		current.RenewedTime = toMillis(now)
+
+		err = e.putLease(current)
+		if err != nil {
+			return false, 0, fmt.Errorf("renew lease: %w", err)
+		}
+
+		logger.Debugf("[%s] Renewed the lease for [%s] (term %d)", e.instanceID, e.name, current.Term)
+
+		return true, current.Term, nil
+	}
+
+	if current != nil && !e.isExpired(current, now) {
+		logger.Debugf("[%s] Instance [%s] is the leader of [%s] (term %d)",
+			e.instanceID, current.Holder, e.name, current.Term)
+
+		return false, 0, nil
+	}
+
+	var term uint64 = 1
+
+	if current != nil {
+		logger.Infof("[%s] The lease of leader [%s] for [%s] has expired. Attempting to take over.",
+			e.instanceID, current.Holder, e.name)
+
+		term = current.Term + 1
+	}
+
+	claim := &lease{
+		Holder:       e.instanceID,
+		Term:         term,
+		AcquiredTime: toMillis(now),
+		RenewedTime:  toMillis(now),
+	}
+
+	err = e.putLease(claim)
+	if err != nil {
+		return false, 0, fmt.Errorf("claim lease: %w", err)
+	}
+
+	// Wait for competing claims (if any) to be written and then verify that this instance won.
+	time.Sleep(e.settleDelay)
+
+	current, err = e.getLease()
+	if err != nil {
+		return false, 0, fmt.Errorf("verify claim: %w", err)
+	}
+
+	if current.Holder != e.instanceID {
+		logger.Infof("[%s] Instance [%s] won the election for [%s]", e.instanceID, current.Holder, e.name)
+
+		return false, 0, nil
+	}
+
+	return true, current.Term, nil
+}
+
+func (e *Elector) setLeader(isLeader bool, term uint64) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if e.isLeader == isLeader {
+		return
+	}
+
+	e.isLeader = isLeader
+
+	if isLeader {
+		logger.Infof("[%s] I am now the leader of [%s] (term %d)", e.instanceID, e.name, term)
+	} else {
+		logger.Infof("[%s] I am no longer the leader of [%s]", e.instanceID, e.name)
+	}
+}
+
+func (e *Elector) isExpired(l *lease, now time.Time) bool {
+	return now.Sub(time.Unix(0, l.RenewedTime*int64(time.Millisecond))) > e.leaseDuration
+}
+
+func (e *Elector) getLease() (*lease, error) {
+	leaseBytes, err := e.store.Get(e.leaseKey())
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("get lease from DB: %w", err)
+	}
+
+	l := &lease{}
+
+	err = json.Unmarshal(leaseBytes, l)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal lease: %w", err)
+	}
+
+	return l, nil
+}
+
+func (e *Elector) putLease(l *lease) error {
+	leaseBytes, err := json.Marshal(l)
+	if err != nil {
+		return fmt.Errorf("marshal lease: %w", err)
+	}
+
+	err = e.store.Put(e.leaseKey(), leaseBytes)
+	if err != nil {
+		return fmt.Errorf("store lease: %w", err)
+	}
+
+	return nil
+}
+
+func (e *Elector) leaseKey() string {
+	return coordinationLeaseKey + "_" + e.name
+}
+
+func toMillis(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
@@ -0,0 +1,115 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package leaderelection
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	electionName  = "tasks"
+	leaseDuration = 300 * time.Millisecond
+)
+
+func TestElector(t *testing.T) {
+	coordinationStore, err := mem.NewProvider().OpenStore("orb-config")
+	require.NoError(t, err)
+
+	e1 := New(electionName, "instance1", coordinationStore, WithLeaseDuration(leaseDuration))
+	e2 := New(electionName, "instance2", coordinationStore, WithLeaseDuration(leaseDuration))
+
+	status, err := e1.Status()
+	require.NoError(t, err)
+	require.Empty(t, status.Leader)
+	require.False(t, status.IsLeader)
+
+	e1.Start()
+	e2.Start()
+
+	defer e2.Stop()
+
+	require.Eventually(t, func() bool {
+		return e1.IsLeader() != e2.IsLeader()
+	}, 2*time.Second, 10*time.Millisecond)
+
+	leader, follower := e1, e2
+
+	if e2.IsLeader() {
+		leader, follower = e2, e1
+	}
+
+	// The leadership should remain stable while the leader renews its lease.
+	time.Sleep(2 * leaseDuration)
+
+	require.True(t, leader.IsLeader())
+	require.False(t, follower.IsLeader())
+
+	status, err = follower.Status()
+	require.NoError(t, err)
+	require.False(t, status.IsLeader)
+	require.Equal(t, leader.instanceID, status.Leader)
+	require.Equal(t, uint64(1), status.Term)
+	require.NotNil(t, status.Acquired)
+	require.NotNil(t, status.Renewed)
+	require.NotNil(t, status.Expires)
+
+	// The follower should take over when the leader resigns.
+	leader.Stop()
+
+	require.False(t, leader.IsLeader())
+
+	require.Eventually(t, follower.IsLeader, 2*time.Second, 10*time.Millisecond)
+
+	status, err = follower.Status()
+	require.NoError(t, err)
+	require.Equal(t, follower.instanceID, status.Leader)
+}
+
+func TestElector_Failover(t *testing.T) {
+	coordinationStore, err := mem.NewProvider().OpenStore("orb-config")
+	require.NoError(t, err)
+
+	e1 := New(electionName, "instance1", coordinationStore, WithLeaseDuration(leaseDuration))
+
+	// Simulate a leader that went down without resigning.
+	require.NoError(t, e1.putLease(&lease{
+		Holder:       "instance2",
+		Term:         3,
+		AcquiredTime: toMillis(time.Now()),
+		RenewedTime:  toMillis(time.Now()),
+	}))
+
+	isLeader, _, err := e1.acquireOrRenew()
+	require.NoError(t, err)
+	require.False(t, isLeader)
+
+	time.Sleep(leaseDuration + 50*time.Millisecond)
+
+	isLeader, term, err := e1.acquireOrRenew()
+	require.NoError(t, err)
+	require.True(t, isLeader)
+	require.Equal(t, uint64(4), term)
+}
+
+func TestElector_Error(t *testing.T) {
+	errExpected := errors.New("injected get error")
+
+	e := New(electionName, "instance1", &mock.Store{ErrGet: errExpected}, WithLeaseDuration(leaseDuration))
+	e.setLeader(true, 1)
+
+	e.check()
+	require.False(t, e.IsLeader())
+
+	_, err := e.Status()
+	require.True(t, errors.Is(err, errExpected))
+}
@@ -0,0 +1,94 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/trustbloc/edge-core/pkg/log"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+
+	"github.com/trustbloc/orb/pkg/leaderelection"
+)
+
+// Path is the path of the leader election status endpoint.
+const Path = "/leader"
+
+const internalServerErrorResponse = "Internal Server Error."
+
+var logger = log.New("leader-election-rest-handler")
+
+type elector interface {
+	Status() (*leaderelection.Status, error)
+}
+
+// Reader implements a REST handler that returns the leader election status, i.e. the instance that currently
+// has the duty of running the singleton background tasks.
+type Reader struct {
+	elector elector
+	marshal func(v interface{}) ([]byte, error)
+}
+
+// NewReader returns a new leader election status reader.
+func NewReader(elector elector) *Reader {
+	return &Reader{
+		elector: elector,
+		marshal: json.Marshal,
+	}
+}
+
+// Path returns the HTTP REST endpoint for the leader election service.
+func (h *Reader) Path() string {
+	return Path
+}
+
+// Method returns the HTTP method, which is always GET.
+func (h *Reader) Method() string {
+	return http.MethodGet
+}
+
+// Handler returns the HTTP REST handle for the leader election service.
+func (h *Reader) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Reader) handle(w http.ResponseWriter, _ *http.Request) {
+	status, err := h.elector.Status()
+	if err != nil {
+		logger.Errorf("[%s] Error retrieving leader election status: %s", Path, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	statusBytes, err := h.marshal(status)
+	if err != nil {
+		logger.Errorf("[%s] Error marshalling leader election status: %s", Path, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	writeResponse(w, http.StatusOK, statusBytes)
+}
+
+func writeResponse(w http.ResponseWriter, status int, body []byte) {
+	w.WriteHeader(status)
+
+	if len(body) > 0 {
+		if _, err := w.Write(body); err != nil {
+			logger.Warnf("[%s] Unable to write response: %s", Path, err)
+
+			return
+		}
+
+		logger.Debugf("[%s] Wrote response: %s", Path, body)
+	}
+}
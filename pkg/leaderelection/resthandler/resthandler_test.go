@@ -0,0 +1,62 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/internal/testutil/httptestutil"
+	"github.com/trustbloc/orb/pkg/leaderelection"
+)
+
+func TestReader(t *testing.T) {
+	elector := &mockElector{
+		status: &leaderelection.Status{Name: "tasks", InstanceID: "instance1", IsLeader: true, Leader: "instance1"},
+	}
+
+	h := NewReader(elector)
+	require.Equal(t, Path, h.Path())
+	require.Equal(t, http.MethodGet, h.Method())
+	require.NotNil(t, h.Handler())
+
+	t.Run("success", func(t *testing.T) {
+		code, respBytes := httptestutil.Get(t, h.Handler(), Path)
+		require.Equal(t, http.StatusOK, code)
+
+		status := &leaderelection.Status{}
+		require.NoError(t, json.Unmarshal(respBytes, status))
+		require.Equal(t, "instance1", status.Leader)
+		require.True(t, status.IsLeader)
+	})
+
+	t.Run("elector error", func(t *testing.T) {
+		code, _ := httptestutil.Get(t, NewReader(&mockElector{err: errors.New("injected error")}).Handler(), Path)
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+
+	t.Run("marshal error", func(t *testing.T) {
+		h := NewReader(elector)
+		h.marshal = func(v interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		code, _ := httptestutil.Get(t, h.Handler(), Path)
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+}
+
+type mockElector struct {
+	status *leaderelection.Status
+	err    error
+}
+
+func (m *mockElector) Status() (*leaderelection.Status, error) {
+	return m.status, m.err
+}
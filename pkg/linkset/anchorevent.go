@@ -0,0 +1,43 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package linkset
+
+import (
+	"fmt"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+)
+
+// FromAnchorEvent returns the linkset representation of the given anchor event. The anchor of the link context
+// is the hashlink of the anchor (content) object, and the links contain the service that created the anchor
+// (author), the generator of the content (profile) and the parent anchors (predecessor-version). The content
+// object itself isn't included, so the linkset may be used by non-Orb consumers to follow the anchor graph
+// but not to verify the anchor.
+func FromAnchorEvent(anchorEvent *vocab.AnchorEventType) (*Linkset, error) {
+	if anchorEvent == nil || anchorEvent.Index() == nil {
+		return nil, fmt.Errorf("anchor event has no index")
+	}
+
+	anchor := anchorEvent.Index()
+
+	l := NewLink(anchor.String())
+
+	if anchorEvent.AttributedTo() != nil {
+		l.Add(RelationAuthor, &Target{Href: anchorEvent.AttributedTo().String()})
+	}
+
+	anchorObj, err := anchorEvent.AnchorObject(anchor)
+	if err == nil && anchorObj.Generator() != "" {
+		l.Add(RelationProfile, &Target{Href: anchorObj.Generator()})
+	}
+
+	for _, parent := range anchorEvent.Parent() {
+		l.Add(RelationPredecessor, &Target{Href: parent.String()})
+	}
+
+	return New(l), nil
+}
@@ -0,0 +1,59 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package linkset
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+)
+
+const generator = "https://w3id.org/orb#v0"
+
+func TestFromAnchorEvent(t *testing.T) {
+	anchorObj, err := vocab.NewAnchorObject(generator, vocab.Document{"field1": "value1"})
+	require.NoError(t, err)
+
+	t.Run("Success", func(t *testing.T) {
+		anchorEvent := vocab.NewAnchorEvent(
+			vocab.WithAttributedTo(testutil.MustParseURL(service)),
+			vocab.WithIndex(anchorObj.URL()[0]),
+			vocab.WithParent(testutil.MustParseURL(anchor2), testutil.MustParseURL(anchor3)),
+			vocab.WithAttachment(vocab.NewObjectProperty(vocab.WithAnchorObject(anchorObj))),
+		)
+
+		ls, err := FromAnchorEvent(anchorEvent)
+		require.NoError(t, err)
+		require.Len(t, ls.Linkset, 1)
+
+		l := ls.Linkset[0]
+		require.Equal(t, anchorObj.URL()[0].String(), l.Anchor)
+		require.Equal(t, []string{RelationAuthor, RelationPredecessor, RelationProfile}, l.RelationTypes())
+		require.Equal(t, service, l.Targets(RelationAuthor)[0].Href)
+		require.Equal(t, generator, l.Targets(RelationProfile)[0].Href)
+		require.Len(t, l.Targets(RelationPredecessor), 2)
+		require.Equal(t, anchor2, l.Targets(RelationPredecessor)[0].Href)
+	})
+
+	t.Run("No attachment", func(t *testing.T) {
+		ls, err := FromAnchorEvent(vocab.NewAnchorEvent(vocab.WithIndex(anchorObj.URL()[0])))
+		require.NoError(t, err)
+		require.Len(t, ls.Linkset, 1)
+		require.Empty(t, ls.Linkset[0].RelationTypes())
+	})
+
+	t.Run("No index", func(t *testing.T) {
+		_, err := FromAnchorEvent(vocab.NewAnchorEvent(vocab.WithURL(testutil.MustParseURL(anchor1))))
+		require.EqualError(t, err, "anchor event has no index")
+
+		_, err = FromAnchorEvent(nil)
+		require.EqualError(t, err, "anchor event has no index")
+	})
+}
@@ -0,0 +1,137 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package linkset
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+const (
+	// ContentTypeJSON is the media type of the JSON linkset format (RFC 9264).
+	ContentTypeJSON = "application/linkset+json"
+	// ContentTypeNative is the media type of the native linkset format (RFC 9264), which uses the
+	// serialization of the HTTP Link header (RFC 8288).
+	ContentTypeNative = "application/linkset"
+
+	anchorProperty = "anchor"
+)
+
+// Link relation types that are used to describe an anchor.
+const (
+	// RelationAuthor links to the service that created the anchor.
+	RelationAuthor = "author"
+	// RelationProfile links to the generator (e.g. https://w3id.org/orb#v0) of the anchor content.
+	RelationProfile = "profile"
+	// RelationPredecessor links to the previous (parent) anchors.
+	RelationPredecessor = "predecessor-version"
+)
+
+// Linkset contains a set of link contexts, as defined in RFC 9264.
+type Linkset struct {
+	Linkset []*Link `json:"linkset"`
+}
+
+// Link is a link context object, which contains the anchor (the context of the links) and the target links
+// keyed by relation type.
+type Link struct {
+	Anchor    string
+	Relations map[string][]*Target
+}
+
+// Target is a link target.
+type Target struct {
+	Href string `json:"href"`
+	Type string `json:"type,omitempty"`
+}
+
+// New returns a new linkset with the given link contexts.
+func New(links ...*Link) *Linkset {
+	return &Linkset{Linkset: links}
+}
+
+// NewLink returns a new link context with the given anchor.
+func NewLink(anchor string) *Link {
+	return &Link{
+		Anchor:    anchor,
+		Relations: make(map[string][]*Target),
+	}
+}
+
+// Add adds a target with the given relation type to the link context and returns the link context.
+func (l *Link) Add(rel string, targets ...*Target) *Link {
+	l.Relations[rel] = append(l.Relations[rel], targets...)
+
+	return l
+}
+
+// Targets returns the targets with the given relation type.
+func (l *Link) Targets(rel string) []*Target {
+	return l.Relations[rel]
+}
+
+// RelationTypes returns the relation types of the link context in sorted order.
+func (l *Link) RelationTypes() []string {
+	rels := make([]string, 0, len(l.Relations))
+
+	for rel := range l.Relations {
+		rels = append(rels, rel)
+	}
+
+	sort.Strings(rels)
+
+	return rels
+}
+
+// MarshalJSON marshals the link context to JSON. The anchor and relation types are members of the same object.
+func (l *Link) MarshalJSON() ([]byte, error) {
+	obj := make(map[string]interface{}, len(l.Relations)+1)
+
+	for rel, targets := range l.Relations {
+		obj[rel] = targets
+	}
+
+	if l.Anchor != "" {
+		obj[anchorProperty] = l.Anchor
+	}
+
+	return json.Marshal(obj)
+}
+
+// UnmarshalJSON unmarshals the link context from JSON.
+func (l *Link) UnmarshalJSON(bytes []byte) error {
+	obj := make(map[string]json.RawMessage)
+
+	err := json.Unmarshal(bytes, &obj)
+	if err != nil {
+		return fmt.Errorf("unmarshal link context: %w", err)
+	}
+
+	l.Anchor = ""
+	l.Relations = make(map[string][]*Target, len(obj))
+
+	for key, value := range obj {
+		if key == anchorProperty {
+			if e := json.Unmarshal(value, &l.Anchor); e != nil {
+				return fmt.Errorf("unmarshal anchor: %w", e)
+			}
+
+			continue
+		}
+
+		var targets []*Target
+
+		if e := json.Unmarshal(value, &targets); e != nil {
+			return fmt.Errorf("unmarshal targets of relation [%s]: %w", key, e)
+		}
+
+		l.Relations[key] = targets
+	}
+
+	return nil
+}
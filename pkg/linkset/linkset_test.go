@@ -0,0 +1,74 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package linkset
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	anchor1 = "hl:uEiDzUEQi2qRreCTfvp2AKmTaxuqUUZZNhbxe5RTBH59AWw"
+	anchor2 = "hl:uEiDYMTm9nJ5B0gwpNtflwrcZCT9uT6BFiEs5sYWB45piXg"
+	anchor3 = "hl:uEiDzOEQi2wRreCTfvp2AKmTaxuqUUZZNhbxe5RTBH59AWw"
+	service = "https://orb.domain1.com/services/orb"
+)
+
+func TestLinkset(t *testing.T) {
+	ls := New(
+		NewLink(anchor1).
+			Add(RelationAuthor, &Target{Href: service}).
+			Add(RelationPredecessor, &Target{Href: anchor2}, &Target{Href: anchor3}),
+	)
+
+	lsBytes, err := json.Marshal(ls)
+	require.NoError(t, err)
+	require.Equal(t, `{"linkset":[{"anchor":"`+anchor1+`","author":[{"href":"`+service+`"}],`+
+		`"predecessor-version":[{"href":"`+anchor2+`"},{"href":"`+anchor3+`"}]}]}`, string(lsBytes))
+
+	ls2 := &Linkset{}
+	require.NoError(t, json.Unmarshal(lsBytes, ls2))
+	require.Len(t, ls2.Linkset, 1)
+	require.Equal(t, anchor1, ls2.Linkset[0].Anchor)
+	require.Equal(t, []string{RelationAuthor, RelationPredecessor}, ls2.Linkset[0].RelationTypes())
+	require.Len(t, ls2.Linkset[0].Targets(RelationPredecessor), 2)
+	require.Equal(t, service, ls2.Linkset[0].Targets(RelationAuthor)[0].Href)
+	require.Empty(t, ls2.Linkset[0].Targets(RelationProfile))
+}
+
+func TestLink_UnmarshalJSON(t *testing.T) {
+	t.Run("No anchor", func(t *testing.T) {
+		l := &Link{}
+		require.NoError(t, json.Unmarshal([]byte(`{"author":[{"href":"`+service+`","type":"text/html"}]}`), l))
+		require.Empty(t, l.Anchor)
+		require.Equal(t, "text/html", l.Targets(RelationAuthor)[0].Type)
+
+		lBytes, err := json.Marshal(l)
+		require.NoError(t, err)
+		require.Equal(t, `{"author":[{"href":"`+service+`","type":"text/html"}]}`, string(lBytes))
+	})
+
+	t.Run("Invalid link context", func(t *testing.T) {
+		err := json.Unmarshal([]byte(`[]`), &Link{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal link context")
+	})
+
+	t.Run("Invalid anchor", func(t *testing.T) {
+		err := json.Unmarshal([]byte(`{"anchor":1}`), &Link{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal anchor")
+	})
+
+	t.Run("Invalid targets", func(t *testing.T) {
+		err := json.Unmarshal([]byte(`{"author":"`+service+`"}`), &Link{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal targets of relation [author]")
+	})
+}
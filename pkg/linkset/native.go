@@ -0,0 +1,219 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package linkset
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	relParam    = "rel"
+	anchorParam = "anchor"
+	typeParam   = "type"
+
+	linkSeparator = ",\n"
+)
+
+// MarshalNative serializes the linkset in the native linkset format (application/linkset), i.e. one link per
+// line using the syntax of the HTTP Link header:
+//
+//	<https://example.com/target>; rel="author"; anchor="hl:uEiD..."
+func (ls *Linkset) MarshalNative() []byte {
+	var links []string
+
+	for _, l := range ls.Linkset {
+		for _, rel := range l.RelationTypes() {
+			for _, target := range l.Relations[rel] {
+				link := fmt.Sprintf("<%s>; %s=%s", target.Href, relParam, quote(rel))
+
+				if l.Anchor != "" {
+					link += fmt.Sprintf("; %s=%s", anchorParam, quote(l.Anchor))
+				}
+
+				if target.Type != "" {
+					link += fmt.Sprintf("; %s=%s", typeParam, quote(target.Type))
+				}
+
+				links = append(links, link)
+			}
+		}
+	}
+
+	return []byte(strings.Join(links, linkSeparator))
+}
+
+// ParseNative parses a linkset in the native linkset format (application/linkset). Links with the same anchor
+// are grouped into a single link context (in the order in which the anchors first appear). A link with
+// multiple (space-separated) relation types is added to each of the relation types.
+func ParseNative(data []byte) (*Linkset, error) {
+	ls := &Linkset{}
+	contexts := make(map[string]*Link)
+
+	p := &parser{input: string(data)}
+
+	for {
+		p.skip(", \t\r\n")
+
+		if p.done() {
+			break
+		}
+
+		href, params, err := p.parseLink()
+		if err != nil {
+			return nil, err
+		}
+
+		rels := strings.Fields(params[relParam])
+		if len(rels) == 0 {
+			return nil, fmt.Errorf("missing relation type for link target [%s]", href)
+		}
+
+		anchor := params[anchorParam]
+
+		l, ok := contexts[anchor]
+		if !ok {
+			l = NewLink(anchor)
+			contexts[anchor] = l
+			ls.Linkset = append(ls.Linkset, l)
+		}
+
+		for _, rel := range rels {
+			l.Add(rel, &Target{Href: href, Type: params[typeParam]})
+		}
+	}
+
+	return ls, nil
+}
+
+// quote returns the given value as a quoted string (RFC 7230).
+func quote(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+type parser struct {
+	input string
+	pos   int
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *parser) skip(chars string) {
+	for !p.done() && strings.IndexByte(chars, p.input[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+func (p *parser) parseLink() (string, map[string]string, error) {
+	if p.input[p.pos] != '<' {
+		return "", nil, fmt.Errorf("expecting '<' at position %d", p.pos)
+	}
+
+	end := strings.IndexByte(p.input[p.pos:], '>')
+	if end < 0 {
+		return "", nil, fmt.Errorf("missing '>' for link target at position %d", p.pos)
+	}
+
+	href := p.input[p.pos+1 : p.pos+end]
+	p.pos += end + 1
+
+	params := make(map[string]string)
+
+	for {
+		p.skip(" \t\r\n")
+
+		if p.done() || p.input[p.pos] == ',' {
+			return href, params, nil
+		}
+
+		if p.input[p.pos] != ';' {
+			return "", nil, fmt.Errorf("expecting ';' at position %d", p.pos)
+		}
+
+		p.pos++
+		p.skip(" \t\r\n")
+
+		name, value, err := p.parseParam()
+		if err != nil {
+			return "", nil, err
+		}
+
+		// Only the first occurrence of a parameter is used, as per RFC 8288.
+		if _, exists := params[name]; !exists {
+			params[name] = value
+		}
+	}
+}
+
+func (p *parser) parseParam() (string, string, error) {
+	start := p.pos
+
+	for !p.done() && strings.IndexByte("=;, \t\r\n", p.input[p.pos]) < 0 {
+		p.pos++
+	}
+
+	name := strings.ToLower(p.input[start:p.pos])
+	if name == "" {
+		return "", "", fmt.Errorf("missing parameter name at position %d", start)
+	}
+
+	p.skip(" \t")
+
+	if p.done() || p.input[p.pos] != '=' {
+		// A parameter without a value.
+		return name, "", nil
+	}
+
+	p.pos++
+	p.skip(" \t")
+
+	if !p.done() && p.input[p.pos] == '"' {
+		value, err := p.parseQuoted()
+		if err != nil {
+			return "", "", err
+		}
+
+		return name, value, nil
+	}
+
+	start = p.pos
+
+	for !p.done() && strings.IndexByte(";, \t\r\n", p.input[p.pos]) < 0 {
+		p.pos++
+	}
+
+	return name, p.input[start:p.pos], nil
+}
+
+func (p *parser) parseQuoted() (string, error) {
+	start := p.pos
+
+	var value strings.Builder
+
+	for p.pos++; !p.done(); p.pos++ {
+		switch c := p.input[p.pos]; c {
+		case '"':
+			p.pos++
+
+			return value.String(), nil
+		case '\\':
+			p.pos++
+
+			if p.done() {
+				return "", fmt.Errorf("unterminated quoted string at position %d", start)
+			}
+
+			value.WriteByte(p.input[p.pos])
+		default:
+			value.WriteByte(c)
+		}
+	}
+
+	return "", fmt.Errorf("unterminated quoted string at position %d", start)
+}
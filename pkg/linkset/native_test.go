@@ -0,0 +1,85 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package linkset
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalNative(t *testing.T) {
+	ls := New(
+		NewLink(anchor1).
+			Add(RelationPredecessor, &Target{Href: anchor2}).
+			Add(RelationAuthor, &Target{Href: service, Type: `text/"html"`}),
+		NewLink("").Add(RelationProfile, &Target{Href: "https://w3id.org/orb#v0"}),
+	)
+
+	data := ls.MarshalNative()
+	require.Equal(t,
+		`<`+service+`>; rel="author"; anchor="`+anchor1+`"; type="text/\"html\"",`+"\n"+
+			`<`+anchor2+`>; rel="predecessor-version"; anchor="`+anchor1+`",`+"\n"+
+			`<https://w3id.org/orb#v0>; rel="profile"`,
+		string(data))
+
+	ls2, err := ParseNative(data)
+	require.NoError(t, err)
+	require.Len(t, ls2.Linkset, 2)
+	require.Equal(t, anchor1, ls2.Linkset[0].Anchor)
+	require.Equal(t, `text/"html"`, ls2.Linkset[0].Targets(RelationAuthor)[0].Type)
+	require.Equal(t, anchor2, ls2.Linkset[0].Targets(RelationPredecessor)[0].Href)
+	require.Empty(t, ls2.Linkset[1].Anchor)
+	require.Equal(t, "https://w3id.org/orb#v0", ls2.Linkset[1].Targets(RelationProfile)[0].Href)
+
+	require.Empty(t, New().MarshalNative())
+}
+
+func TestParseNative(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		ls, err := ParseNative([]byte(`
+			<` + service + `> ; REL="author predecessor-version" ; anchor=` + anchor1 + ` ; rel="ignored" ; x ,
+			<` + anchor3 + `>;rel=predecessor-version;anchor="` + anchor1 + `",
+			<` + anchor2 + `>; rel=author; anchor="` + anchor3 + `"; title="a\\b"
+		`))
+		require.NoError(t, err)
+		require.Len(t, ls.Linkset, 2)
+
+		l := ls.Linkset[0]
+		require.Equal(t, anchor1, l.Anchor)
+		require.Equal(t, service, l.Targets(RelationAuthor)[0].Href)
+		require.Len(t, l.Targets(RelationPredecessor), 2)
+		require.Equal(t, service, l.Targets(RelationPredecessor)[0].Href)
+		require.Equal(t, anchor3, l.Targets(RelationPredecessor)[1].Href)
+		require.Empty(t, l.Targets("ignored"))
+
+		require.Equal(t, anchor3, ls.Linkset[1].Anchor)
+		require.Equal(t, anchor2, ls.Linkset[1].Targets(RelationAuthor)[0].Href)
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		ls, err := ParseNative([]byte(" \n"))
+		require.NoError(t, err)
+		require.Empty(t, ls.Linkset)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		for input, errMsg := range map[string]string{
+			`https://example.com; rel="author"`:               "expecting '<' at position 0",
+			`<https://example.com; rel="author"`:              "missing '>' for link target",
+			`<https://example.com> rel="author"`:              "expecting ';' at position 22",
+			`<https://example.com>; ="author"`:                "missing parameter name",
+			`<https://example.com>; rel="author`:              "unterminated quoted string",
+			`<https://example.com>; rel="author\`:             "unterminated quoted string",
+			`<https://example.com>; anchor="` + anchor1 + `"`: "missing relation type for link target",
+		} {
+			_, err := ParseNative([]byte(input))
+			require.Error(t, err, input)
+			require.Contains(t, err.Error(), errMsg, input)
+		}
+	})
+}
@@ -0,0 +1,55 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package maintenance
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+)
+
+const retryAfterHeader = "Retry-After"
+
+// HandlerWrapper wraps an HTTP handler and rejects requests with status 503 (Service Unavailable)
+// while the node is in maintenance mode.
+type HandlerWrapper struct {
+	common.HTTPHandler
+
+	mode *Mode
+}
+
+// NewHandlerWrapper returns a handler wrapper that rejects requests while the node is in maintenance mode.
+func NewHandlerWrapper(handler common.HTTPHandler, mode *Mode) *HandlerWrapper {
+	return &HandlerWrapper{
+		HTTPHandler: handler,
+		mode:        mode,
+	}
+}
+
+// Handler returns the handler that should be invoked when an HTTP request is received.
+func (h *HandlerWrapper) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *HandlerWrapper) handle(w http.ResponseWriter, req *http.Request) {
+	if !h.mode.Enabled() {
+		h.HTTPHandler.Handler()(w, req)
+
+		return
+	}
+
+	logger.Debugf("[%s] Rejecting request since the node is in maintenance mode", h.Path())
+
+	w.Header().Set(retryAfterHeader, strconv.Itoa(int(math.Ceil(h.mode.RetryAfter().Seconds()))))
+	w.WriteHeader(http.StatusServiceUnavailable)
+
+	if _, err := w.Write([]byte(http.StatusText(http.StatusServiceUnavailable))); err != nil {
+		logger.Warnf("[%s] Unable to write response: %s", h.Path(), err)
+	}
+}
@@ -0,0 +1,66 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package maintenance
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+)
+
+func TestHandlerWrapper(t *testing.T) {
+	mode := New(90 * time.Second)
+
+	h := NewHandlerWrapper(&mockHandler{}, mode)
+	require.Equal(t, "/operations", h.Path())
+	require.Equal(t, http.MethodPost, h.Method())
+
+	t.Run("Not in maintenance mode", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+
+		h.Handler()(rw, httptest.NewRequest(http.MethodPost, "/operations", nil))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusOK, result.StatusCode)
+		require.Empty(t, result.Header.Get(retryAfterHeader))
+		require.NoError(t, result.Body.Close())
+	})
+
+	t.Run("In maintenance mode", func(t *testing.T) {
+		mode.Enable()
+		defer mode.Disable()
+
+		rw := httptest.NewRecorder()
+
+		h.Handler()(rw, httptest.NewRequest(http.MethodPost, "/operations", nil))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusServiceUnavailable, result.StatusCode)
+		require.Equal(t, "90", result.Header.Get(retryAfterHeader))
+		require.NoError(t, result.Body.Close())
+	})
+}
+
+type mockHandler struct{}
+
+func (m *mockHandler) Path() string {
+	return "/operations"
+}
+
+func (m *mockHandler) Method() string {
+	return http.MethodPost
+}
+
+func (m *mockHandler) Handler() common.HTTPRequestHandler {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package maintenance
+
+import (
+	"sync"
+	"time"
+
+	"github.com/trustbloc/edge-core/pkg/log"
+)
+
+var logger = log.New("maintenance")
+
+// DefaultRetryAfter is the default value of the Retry-After header that's returned to clients
+// while the node is in maintenance mode.
+const DefaultRetryAfter = time.Minute
+
+// Status contains the maintenance mode status.
+type Status struct {
+	Enabled    bool       `json:"enabled"`
+	Since      *time.Time `json:"since,omitempty"`
+	RetryAfter string     `json:"retryAfter"`
+}
+
+// Mode is the maintenance mode switch of the node. While in maintenance mode, read requests continue to be
+// served but operation submissions and inbox requests are rejected with status 503 (Service Unavailable) and
+// the delivery of messages to queue consumers is paused (see PubSub) so that the database may be safely
+// migrated. The mode applies only to this node.
+type Mode struct {
+	retryAfter time.Duration
+
+	mutex   sync.RWMutex
+	since   *time.Time
+	resumed chan struct{}
+}
+
+// New returns a new maintenance mode switch which is initially disabled.
+func New(retryAfter time.Duration) *Mode {
+	if retryAfter <= 0 {
+		retryAfter = DefaultRetryAfter
+	}
+
+	resumed := make(chan struct{})
+	close(resumed)
+
+	return &Mode{
+		retryAfter: retryAfter,
+		resumed:    resumed,
+	}
+}
+
+// Enable puts the node into maintenance mode. Enabling maintenance mode more than once has no effect.
+func (m *Mode) Enable() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.since != nil {
+		return
+	}
+
+	now := time.Now()
+
+	m.since = &now
+	m.resumed = make(chan struct{})
+
+	logger.Warnf("Maintenance mode enabled. Operation submissions and inbox requests will be rejected " +
+		"and queue consumers are paused.")
+}
+
+// Disable takes the node out of maintenance mode and resumes the queue consumers.
+func (m *Mode) Disable() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.since == nil {
+		return
+	}
+
+	logger.Warnf("Maintenance mode disabled after %s.", time.Since(*m.since))
+
+	m.since = nil
+
+	close(m.resumed)
+}
+
+// Enabled returns true if the node is in maintenance mode.
+func (m *Mode) Enabled() bool {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return m.since != nil
+}
+
+// Status returns the maintenance mode status.
+func (m *Mode) Status() *Status {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return &Status{
+		Enabled:    m.since != nil,
+		Since:      m.since,
+		RetryAfter: m.retryAfter.String(),
+	}
+}
+
+// RetryAfter returns the duration after which a rejected client should retry.
+func (m *Mode) RetryAfter() time.Duration {
+	return m.retryAfter
+}
+
+// Resumed returns a channel that is closed when the node isn't in maintenance mode. If the node is in
+// maintenance mode then the channel is closed when maintenance mode is disabled.
+func (m *Mode) Resumed() <-chan struct{} {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return m.resumed
+}
@@ -0,0 +1,59 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package maintenance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMode(t *testing.T) {
+	m := New(0)
+	require.Equal(t, DefaultRetryAfter, m.RetryAfter())
+	require.False(t, m.Enabled())
+	require.False(t, m.Status().Enabled)
+	require.Nil(t, m.Status().Since)
+
+	select {
+	case <-m.Resumed():
+	default:
+		t.Fatal("expecting resumed channel to be closed")
+	}
+
+	m.Enable()
+	require.True(t, m.Enabled())
+
+	status := m.Status()
+	require.True(t, status.Enabled)
+	require.NotNil(t, status.Since)
+	require.Equal(t, "1m0s", status.RetryAfter)
+
+	m.Enable()
+	require.Equal(t, status.Since, m.Status().Since)
+
+	resumed := m.Resumed()
+
+	select {
+	case <-resumed:
+		t.Fatal("expecting resumed channel to be open")
+	default:
+	}
+
+	m.Disable()
+	require.False(t, m.Enabled())
+
+	select {
+	case <-resumed:
+	case <-time.After(time.Second):
+		t.Fatal("expecting resumed channel to be closed")
+	}
+
+	m.Disable()
+	require.False(t, m.Enabled())
+}
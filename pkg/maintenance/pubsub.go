@@ -0,0 +1,121 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package maintenance
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+
+	"github.com/trustbloc/orb/pkg/pubsub/spi"
+)
+
+type pubSub interface {
+	Subscribe(ctx context.Context, topic string) (<-chan *message.Message, error)
+	SubscribeWithOpts(ctx context.Context, topic string, opts ...spi.Option) (<-chan *message.Message, error)
+	Publish(topic string, messages ...*message.Message) error
+	Close() error
+}
+
+// PubSub wraps a publisher/subscriber and pauses the delivery of messages to subscribers while the node
+// is in maintenance mode. Messages that are already being processed by a subscriber are allowed to complete
+// and the next message is held (without being acknowledged) until maintenance mode is disabled. Publishing
+// is not affected.
+type PubSub struct {
+	pubSub
+
+	mode      *Mode
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewPubSub returns a new maintenance-aware publisher/subscriber.
+func NewPubSub(ps pubSub, mode *Mode) *PubSub {
+	return &PubSub{
+		pubSub: ps,
+		mode:   mode,
+		done:   make(chan struct{}),
+	}
+}
+
+// Subscribe subscribes to the given topic.
+func (p *PubSub) Subscribe(ctx context.Context, topic string) (<-chan *message.Message, error) {
+	msgChan, err := p.pubSub.Subscribe(ctx, topic)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.forward(topic, msgChan), nil
+}
+
+// SubscribeWithOpts subscribes to the given topic using the provided options.
+func (p *PubSub) SubscribeWithOpts(ctx context.Context, topic string,
+	opts ...spi.Option) (<-chan *message.Message, error) {
+	msgChan, err := p.pubSub.SubscribeWithOpts(ctx, topic, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.forward(topic, msgChan), nil
+}
+
+// Close closes the publisher/subscriber. Any message that is held due to maintenance mode is nacked
+// so that it may be redelivered.
+func (p *PubSub) Close() error {
+	p.closeOnce.Do(func() {
+		close(p.done)
+	})
+
+	return p.pubSub.Close()
+}
+
+func (p *PubSub) forward(topic string, msgChan <-chan *message.Message) <-chan *message.Message {
+	out := make(chan *message.Message)
+
+	go func() {
+		defer close(out)
+
+		for msg := range msgChan {
+			if !p.wait(topic) {
+				logger.Debugf("Publisher/subscriber closed while in maintenance mode. Nacking message [%s] "+
+					"on topic [%s].", msg.UUID, topic)
+
+				msg.Nack()
+
+				continue
+			}
+
+			out <- msg
+		}
+	}()
+
+	return out
+}
+
+// wait blocks until the node is not in maintenance mode. False is returned if the publisher/subscriber
+// was closed while waiting.
+func (p *PubSub) wait(topic string) bool {
+	resumed := p.mode.Resumed()
+
+	select {
+	case <-resumed:
+		return true
+	default:
+	}
+
+	logger.Infof("Delivery of messages on topic [%s] is paused while in maintenance mode", topic)
+
+	select {
+	case <-resumed:
+		logger.Infof("Resuming delivery of messages on topic [%s]", topic)
+
+		return true
+	case <-p.done:
+		return false
+	}
+}
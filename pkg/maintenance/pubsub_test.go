@@ -0,0 +1,148 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/pubsub/spi"
+)
+
+const topic = "orb.test"
+
+func TestPubSub(t *testing.T) {
+	t.Run("Pause and resume", func(t *testing.T) {
+		mode := New(time.Minute)
+
+		ps := newMockPubSub()
+
+		p := NewPubSub(ps, mode)
+
+		msgChan, err := p.Subscribe(context.Background(), topic)
+		require.NoError(t, err)
+
+		require.NoError(t, p.Publish(topic, message.NewMessage(watermill.NewUUID(), []byte("msg1"))))
+		require.Equal(t, "msg1", string(receive(t, msgChan).Payload))
+
+		mode.Enable()
+
+		require.NoError(t, p.Publish(topic, message.NewMessage(watermill.NewUUID(), []byte("msg2"))))
+
+		select {
+		case <-msgChan:
+			t.Fatal("expecting message delivery to be paused")
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		mode.Disable()
+
+		require.Equal(t, "msg2", string(receive(t, msgChan).Payload))
+
+		require.NoError(t, p.Close())
+	})
+
+	t.Run("Close while paused", func(t *testing.T) {
+		mode := New(time.Minute)
+
+		ps := newMockPubSub()
+
+		p := NewPubSub(ps, mode)
+
+		msgChan, err := p.SubscribeWithOpts(context.Background(), topic, spi.WithPool(2))
+		require.NoError(t, err)
+
+		mode.Enable()
+
+		msg := message.NewMessage(watermill.NewUUID(), []byte("msg1"))
+
+		require.NoError(t, p.Publish(topic, msg))
+
+		time.Sleep(50 * time.Millisecond)
+
+		require.NoError(t, p.Close())
+
+		select {
+		case <-msg.Nacked():
+		case <-time.After(time.Second):
+			t.Fatal("expecting message to be nacked")
+		}
+
+		select {
+		case _, ok := <-msgChan:
+			require.False(t, ok)
+		case <-time.After(time.Second):
+			t.Fatal("expecting subscriber channel to be closed")
+		}
+	})
+
+	t.Run("Subscribe error", func(t *testing.T) {
+		errExpected := errors.New("injected subscribe error")
+
+		p := NewPubSub(&mockPubSub{err: errExpected}, New(time.Minute))
+
+		_, err := p.Subscribe(context.Background(), topic)
+		require.True(t, errors.Is(err, errExpected))
+
+		_, err = p.SubscribeWithOpts(context.Background(), topic)
+		require.True(t, errors.Is(err, errExpected))
+	})
+}
+
+func receive(t *testing.T, msgChan <-chan *message.Message) *message.Message {
+	t.Helper()
+
+	select {
+	case msg := <-msgChan:
+		return msg
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for message")
+	}
+
+	return nil
+}
+
+type mockPubSub struct {
+	msgChan chan *message.Message
+	err     error
+}
+
+func newMockPubSub() *mockPubSub {
+	return &mockPubSub{msgChan: make(chan *message.Message, 10)}
+}
+
+func (m *mockPubSub) Subscribe(ctx context.Context, topic string) (<-chan *message.Message, error) {
+	return m.SubscribeWithOpts(ctx, topic)
+}
+
+func (m *mockPubSub) SubscribeWithOpts(context.Context, string, ...spi.Option) (<-chan *message.Message, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	return m.msgChan, nil
+}
+
+func (m *mockPubSub) Publish(_ string, messages ...*message.Message) error {
+	for _, msg := range messages {
+		m.msgChan <- msg
+	}
+
+	return nil
+}
+
+func (m *mockPubSub) Close() error {
+	close(m.msgChan)
+
+	return nil
+}
@@ -0,0 +1,168 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/trustbloc/edge-core/pkg/log"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+
+	"github.com/trustbloc/orb/pkg/maintenance"
+)
+
+// Path is the path of the maintenance mode endpoint.
+const Path = "/maintenance"
+
+const (
+	badRequestResponse          = "Bad Request."
+	internalServerErrorResponse = "Internal Server Error."
+)
+
+var logger = log.New("maintenance-rest-handler")
+
+type maintenanceMode interface {
+	Enable()
+	Disable()
+	Status() *maintenance.Status
+}
+
+// Reader implements a REST handler that returns the maintenance mode status of the node.
+type Reader struct {
+	mode    maintenanceMode
+	marshal func(v interface{}) ([]byte, error)
+}
+
+// NewReader returns a new maintenance mode status reader.
+func NewReader(mode maintenanceMode) *Reader {
+	return &Reader{
+		mode:    mode,
+		marshal: json.Marshal,
+	}
+}
+
+// Path returns the HTTP REST endpoint for the maintenance mode service.
+func (h *Reader) Path() string {
+	return Path
+}
+
+// Method returns the HTTP method, which is always GET.
+func (h *Reader) Method() string {
+	return http.MethodGet
+}
+
+// Handler returns the HTTP REST handle for the maintenance mode service.
+func (h *Reader) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Reader) handle(w http.ResponseWriter, _ *http.Request) {
+	statusBytes, err := h.marshal(h.mode.Status())
+	if err != nil {
+		logger.Errorf("[%s] Error marshalling maintenance mode status: %s", Path, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	writeResponse(w, http.StatusOK, statusBytes)
+}
+
+// Writer implements a REST handler that puts the node into, or takes the node out of, maintenance mode.
+// The request is a JSON object, for example {"enabled":true}. The response contains the resulting status.
+type Writer struct {
+	mode    maintenanceMode
+	readAll func(r io.Reader) ([]byte, error)
+	marshal func(v interface{}) ([]byte, error)
+}
+
+// NewWriter returns a new maintenance mode writer.
+func NewWriter(mode maintenanceMode) *Writer {
+	return &Writer{
+		mode:    mode,
+		readAll: ioutil.ReadAll,
+		marshal: json.Marshal,
+	}
+}
+
+// Path returns the HTTP REST endpoint for the maintenance mode service.
+func (h *Writer) Path() string {
+	return Path
+}
+
+// Method returns the HTTP method, which is always POST.
+func (h *Writer) Method() string {
+	return http.MethodPost
+}
+
+// Handler returns the HTTP REST handle for the maintenance mode service.
+func (h *Writer) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+type maintenanceRequest struct {
+	Enabled *bool `json:"enabled"`
+}
+
+func (h *Writer) handle(w http.ResponseWriter, req *http.Request) {
+	reqBytes, err := h.readAll(req.Body)
+	if err != nil {
+		logger.Errorf("[%s] Error reading request body: %s", Path, err)
+
+		writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	r := &maintenanceRequest{}
+
+	err = json.Unmarshal(reqBytes, r)
+	if err != nil || r.Enabled == nil {
+		logger.Infof("[%s] Invalid maintenance mode request: %s", Path, reqBytes)
+
+		writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	if *r.Enabled {
+		h.mode.Enable()
+	} else {
+		h.mode.Disable()
+	}
+
+	logger.Infof("[%s] Maintenance mode enabled: %t", Path, *r.Enabled)
+
+	statusBytes, err := h.marshal(h.mode.Status())
+	if err != nil {
+		logger.Errorf("[%s] Error marshalling maintenance mode status: %s", Path, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	writeResponse(w, http.StatusOK, statusBytes)
+}
+
+func writeResponse(w http.ResponseWriter, status int, body []byte) {
+	w.WriteHeader(status)
+
+	if len(body) > 0 {
+		if _, err := w.Write(body); err != nil {
+			logger.Warnf("[%s] Unable to write response: %s", Path, err)
+
+			return
+		}
+
+		logger.Debugf("[%s] Wrote response: %s", Path, body)
+	}
+}
@@ -0,0 +1,107 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/internal/testutil/httptestutil"
+	"github.com/trustbloc/orb/pkg/maintenance"
+)
+
+func TestReader(t *testing.T) {
+	mode := maintenance.New(30 * time.Second)
+
+	h := NewReader(mode)
+	require.Equal(t, Path, h.Path())
+	require.Equal(t, http.MethodGet, h.Method())
+	require.NotNil(t, h.Handler())
+
+	t.Run("success", func(t *testing.T) {
+		code, respBytes := httptestutil.Get(t, h.Handler(), Path)
+
+		status := getStatus(t, code, respBytes)
+		require.False(t, status.Enabled)
+		require.Nil(t, status.Since)
+		require.Equal(t, "30s", status.RetryAfter)
+	})
+
+	t.Run("marshal error", func(t *testing.T) {
+		h := NewReader(mode)
+		h.marshal = func(v interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		code, _ := httptestutil.Get(t, h.Handler(), Path)
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+}
+
+func TestWriter(t *testing.T) {
+	mode := maintenance.New(time.Minute)
+
+	h := NewWriter(mode)
+	require.Equal(t, Path, h.Path())
+	require.Equal(t, http.MethodPost, h.Method())
+	require.NotNil(t, h.Handler())
+
+	t.Run("success", func(t *testing.T) {
+		code, respBytes := httptestutil.Post(t, h.Handler(), Path, []byte(`{"enabled":true}`))
+
+		status := getStatus(t, code, respBytes)
+		require.True(t, status.Enabled)
+		require.NotNil(t, status.Since)
+		require.True(t, mode.Enabled())
+
+		code, respBytes = httptestutil.Post(t, h.Handler(), Path, []byte(`{"enabled":false}`))
+
+		status = getStatus(t, code, respBytes)
+		require.False(t, status.Enabled)
+		require.False(t, mode.Enabled())
+	})
+
+	t.Run("bad request", func(t *testing.T) {
+		for _, body := range []string{`{`, `{}`, `{"enabled":"yes"}`} {
+			code, _ := httptestutil.Post(t, h.Handler(), Path, []byte(body))
+			require.Equal(t, http.StatusBadRequest, code)
+		}
+
+		require.False(t, mode.Enabled())
+	})
+
+	t.Run("read error", func(t *testing.T) {
+		h := NewWriter(mode)
+		h.readAll = func(r io.Reader) ([]byte, error) { return nil, errors.New("injected read error") }
+
+		code, _ := httptestutil.Post(t, h.Handler(), Path, nil)
+		require.Equal(t, http.StatusBadRequest, code)
+	})
+
+	t.Run("marshal error", func(t *testing.T) {
+		h := NewWriter(mode)
+		h.marshal = func(v interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		code, _ := httptestutil.Post(t, h.Handler(), Path, []byte(`{"enabled":false}`))
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+}
+
+func getStatus(t *testing.T, code int, respBytes []byte) *maintenance.Status {
+	t.Helper()
+
+	require.Equal(t, http.StatusOK, code)
+
+	status := &maintenance.Status{}
+	require.NoError(t, json.Unmarshal(respBytes, status))
+
+	return status
+}
@@ -0,0 +1,223 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package counterstore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/lifecycle"
+)
+
+var logger = log.New("metrics-counter-store")
+
+const (
+	storeName = "metrics-counters"
+
+	defaultFlushInterval = 30 * time.Second
+)
+
+type countersProvider interface {
+	PersistentCounts() map[string]uint64
+	RestoreCounts(counts map[string]uint64)
+}
+
+// record is the persisted document of an instance.
+type record struct {
+	Counts  map[string]uint64 `json:"counts"`
+	Updated time.Time         `json:"updated"`
+}
+
+type options struct {
+	flushInterval time.Duration
+}
+
+// Opt sets a persister option.
+type Opt func(opts *options)
+
+// WithFlushInterval sets the interval at which the counters are persisted.
+func WithFlushInterval(value time.Duration) Opt {
+	return func(opts *options) {
+		opts.flushInterval = value
+	}
+}
+
+// Persister persists the values of selected metrics counters (for example, the total number of anchors written)
+// so that the counters don't reset when the server is restarted. The counters are restored from the store
+// when the persister is created and they're periodically flushed to the store while the persister is running.
+// Each instance persists its counters under its own (stable) instance name since the counters of an instance
+// only reflect the work done by that instance.
+type Persister struct {
+	*lifecycle.Lifecycle
+
+	store         storage.Store
+	counters      countersProvider
+	instance      string
+	flushInterval time.Duration
+	done          chan struct{}
+	wg            sync.WaitGroup
+	now           func() time.Time
+
+	mutex   sync.Mutex
+	flushed map[string]uint64
+}
+
+// NewPersister returns a new metrics counter persister. The counters previously persisted by the given instance
+// are restored.
+func NewPersister(provider storage.Provider, counters countersProvider, instance string,
+	opts ...Opt) (*Persister, error) {
+	if instance == "" {
+		return nil, errors.New("instance name is required")
+	}
+
+	options := &options{
+		flushInterval: defaultFlushInterval,
+	}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	store, err := provider.OpenStore(storeName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open metrics counter store: %w", err)
+	}
+
+	p := &Persister{
+		store:         store,
+		counters:      counters,
+		instance:      instance,
+		flushInterval: options.flushInterval,
+		done:          make(chan struct{}),
+		now:           time.Now,
+	}
+
+	err = p.restore()
+	if err != nil {
+		return nil, err
+	}
+
+	p.Lifecycle = lifecycle.New("metrics-counter-store",
+		lifecycle.WithStart(p.start),
+		lifecycle.WithStop(p.stop),
+	)
+
+	return p, nil
+}
+
+func (p *Persister) restore() error {
+	recordBytes, err := p.store.Get(p.instance)
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			logger.Infof("No persisted metrics counters found for instance [%s]", p.instance)
+
+			return nil
+		}
+
+		return orberrors.NewTransient(fmt.Errorf("get metrics counters for instance [%s]: %w", p.instance, err))
+	}
+
+	r := &record{}
+
+	err = json.Unmarshal(recordBytes, r)
+	if err != nil {
+		return fmt.Errorf("unmarshal metrics counters for instance [%s]: %w", p.instance, err)
+	}
+
+	p.counters.RestoreCounts(r.Counts)
+
+	// The restored counts are already persisted.
+	p.flushed = p.counters.PersistentCounts()
+
+	logger.Infof("Restored metrics counters for instance [%s] (last updated %s): %v", p.instance, r.Updated, r.Counts)
+
+	return nil
+}
+
+func (p *Persister) start() {
+	p.wg.Add(1)
+
+	go p.run()
+
+	logger.Infof("Started metrics counter persister [%s]", p.instance)
+}
+
+func (p *Persister) stop() {
+	close(p.done)
+
+	p.wg.Wait()
+
+	// Persist the counts that were updated since the last flush.
+	p.flush()
+
+	logger.Infof("Stopped metrics counter persister [%s]", p.instance)
+}
+
+func (p *Persister) run() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.flush()
+		case <-p.done:
+			return
+		}
+	}
+}
+
+func (p *Persister) flush() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	counts := p.counters.PersistentCounts()
+
+	if equal(counts, p.flushed) {
+		return
+	}
+
+	recordBytes, err := json.Marshal(&record{Counts: counts, Updated: p.now().UTC()})
+	if err != nil {
+		logger.Errorf("Error marshalling metrics counters: %s", err)
+
+		return
+	}
+
+	err = p.store.Put(p.instance, recordBytes)
+	if err != nil {
+		// The counters are kept in memory, so they'll be persisted on the next flush.
+		logger.Warnf("Error persisting metrics counters: %s", err)
+
+		return
+	}
+
+	p.flushed = counts
+}
+
+func equal(counts1, counts2 map[string]uint64) bool {
+	if len(counts1) != len(counts2) {
+		return false
+	}
+
+	for name, value := range counts1 {
+		if v, ok := counts2[name]; !ok || v != value {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,213 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package counterstore
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/stretchr/testify/require"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+const (
+	instance1 = "orb-1"
+	instance2 = "orb-2"
+
+	anchorsCounter    = "orb_anchor_written_count"
+	operationsCounter = "orb_document_processed_operation_count"
+)
+
+func TestNewPersister(t *testing.T) {
+	t.Run("Success - nothing to restore", func(t *testing.T) {
+		counters := newMockCounters()
+
+		p, err := NewPersister(mem.NewProvider(), counters, instance1)
+		require.NoError(t, err)
+		require.NotNil(t, p)
+		require.Empty(t, counters.PersistentCounts())
+	})
+
+	t.Run("No instance name", func(t *testing.T) {
+		_, err := NewPersister(mem.NewProvider(), newMockCounters(), "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "instance name is required")
+	})
+
+	t.Run("Open store error", func(t *testing.T) {
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(nil, errors.New("injected open error"))
+
+		_, err := NewPersister(provider, newMockCounters(), instance1)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected open error")
+	})
+
+	t.Run("Get error", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.GetReturns(nil, errors.New("injected get error"))
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		_, err := NewPersister(provider, newMockCounters(), instance1)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected get error")
+		require.True(t, orberrors.IsTransient(err))
+	})
+
+	t.Run("Unmarshal error", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.GetReturns([]byte("{"), nil)
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		_, err := NewPersister(provider, newMockCounters(), instance1)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal metrics counters")
+	})
+}
+
+func TestPersister_Restore(t *testing.T) {
+	provider := mem.NewProvider()
+
+	counters1 := newMockCounters()
+
+	p1, err := NewPersister(provider, counters1, instance1)
+	require.NoError(t, err)
+
+	p1.Start()
+
+	counters1.inc(anchorsCounter, 3)
+	counters1.inc(operationsCounter, 10)
+
+	// The counts are flushed when the persister is stopped.
+	p1.Stop()
+
+	counters2 := newMockCounters()
+
+	p2, err := NewPersister(provider, counters2, instance2)
+	require.NoError(t, err)
+	require.NotNil(t, p2)
+
+	// The counts of another instance are not restored.
+	require.Empty(t, counters2.PersistentCounts())
+
+	// Simulate a restart of instance 1.
+	counters1 = newMockCounters()
+	counters1.inc(anchorsCounter, 1)
+
+	p1, err = NewPersister(provider, counters1, instance1)
+	require.NoError(t, err)
+	require.NotNil(t, p1)
+
+	counts := counters1.PersistentCounts()
+	require.Equal(t, uint64(4), counts[anchorsCounter])
+	require.Equal(t, uint64(10), counts[operationsCounter])
+}
+
+func TestPersister_Flush(t *testing.T) {
+	t.Run("Periodic flush", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.GetReturns(nil, storage.ErrDataNotFound)
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		counters := newMockCounters()
+
+		p, err := NewPersister(provider, counters, instance1, WithFlushInterval(10*time.Millisecond))
+		require.NoError(t, err)
+
+		p.Start()
+		defer p.Stop()
+
+		counters.inc(anchorsCounter, 1)
+
+		require.Eventually(t, func() bool { return store.PutCallCount() == 1 }, time.Second, 5*time.Millisecond)
+
+		// The counts haven't changed so they're not persisted again.
+		time.Sleep(50 * time.Millisecond)
+		require.Equal(t, 1, store.PutCallCount())
+
+		key, _, _ := store.PutArgsForCall(0)
+		require.Equal(t, instance1, key)
+	})
+
+	t.Run("Put error", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.GetReturns(nil, storage.ErrDataNotFound)
+		store.PutReturns(errors.New("injected put error"))
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		counters := newMockCounters()
+
+		p, err := NewPersister(provider, counters, instance1)
+		require.NoError(t, err)
+
+		counters.inc(anchorsCounter, 1)
+
+		p.flush()
+		require.Equal(t, 1, store.PutCallCount())
+
+		// The counts weren't persisted so they're persisted on the next flush.
+		store.PutReturns(nil)
+
+		p.flush()
+		require.Equal(t, 2, store.PutCallCount())
+
+		p.flush()
+		require.Equal(t, 2, store.PutCallCount())
+	})
+}
+
+type mockCounters struct {
+	mutex  sync.Mutex
+	counts map[string]uint64
+}
+
+func newMockCounters() *mockCounters {
+	return &mockCounters{counts: make(map[string]uint64)}
+}
+
+func (m *mockCounters) inc(name string, value uint64) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.counts[name] += value
+}
+
+func (m *mockCounters) PersistentCounts() map[string]uint64 {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	counts := make(map[string]uint64)
+
+	for name, value := range m.counts {
+		counts[name] = value
+	}
+
+	return counts
+}
+
+func (m *mockCounters) RestoreCounts(counts map[string]uint64) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for name, value := range counts {
+		m.counts[name] += value
+	}
+}
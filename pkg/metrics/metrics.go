@@ -8,6 +8,7 @@ package metrics
 
 import (
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -23,6 +24,10 @@ const (
 	apResolveInboxesTimeMetric    = "outbox_resolve_inboxes_seconds"
 	apInboxHandlerTimeMetric      = "inbox_handler_seconds"
 	apOutboxActivityCounterMetric = "outbox_count"
+	apDeliveryTimeMetric          = "delivery_seconds"
+	apDeliveryFailureCountMetric  = "delivery_failure_count"
+	apActorKeyChangeCountMetric   = "actor_key_change_count"
+	apInboxRejectionCountMetric   = "inbox_rejection_count"
 
 	// Anchor.
 	anchor                                         = "anchor"
@@ -41,6 +46,7 @@ const (
 	anchorWriteSignLocalStoreTimeMetric            = "write_sign_local_store_seconds"
 	anchorWriteSignLocalWatchTimeMetric            = "write_sign_local_watch_seconds"
 	anchorWriteResolveHostMetaLinkTimeMetric       = "write_resolve_host_meta_link_seconds"
+	anchorWrittenCountMetric                       = "written_count"
 
 	// Operation queue.
 	operationQueue                 = "opqueue"
@@ -48,11 +54,15 @@ const (
 	opQueueBatchCutTimeMetric      = "batch_cut_seconds"
 	opQueueBatchRollbackTimeMetric = "batch_rollback_seconds"
 	opQueueBatchSizeMetric         = "batch_size"
+	opQueueQueueSizeMetric         = "queue_size"
 
 	// Observer.
-	observer                        = "observer"
-	observerProcessAnchorTimeMetric = "process_anchor_seconds"
-	observerProcessDIDTimeMetric    = "process_did_seconds"
+	observer                             = "observer"
+	observerProcessAnchorTimeMetric      = "process_anchor_seconds"
+	observerProcessDIDTimeMetric         = "process_did_seconds"
+	observerProcessAnchorStageTimeMetric = "process_anchor_stage_seconds"
+	observerAnchorLagMetric              = "anchor_lag_seconds"
+	stageLabel                           = "stage"
 
 	// CAS.
 	cas                    = "cas"
@@ -66,6 +76,8 @@ const (
 	docCreateUpdateTimeMetric = "create_update_seconds"
 	docResolveTimeMetric      = "resolve_seconds"
 
+	docProcessedOperationCountMetric = "processed_operation_count"
+
 	// DB.
 	db                  = "db"
 	dbPutTimeMetric     = "put_seconds"
@@ -133,6 +145,13 @@ const (
 	coreGetCreateOperationResult          = "get_create_operation_result_seconds"
 	coreHTTPCreateUpdateTimeMetrics       = "http_create_update_seconds"
 	coreHTTPResolveTimeMetrics            = "http_resolve_seconds"
+
+	// Circuit breaker.
+	circuitBreaker              = "circuitbreaker"
+	circuitBreakerStateMetric   = "state"
+	circuitBreakerRejectedCount = "rejected_count"
+	hostLabel                   = "host"
+	reasonLabel                 = "reason"
 )
 
 var logger = log.New("metrics")
@@ -148,6 +167,10 @@ type Metrics struct {
 	apOutboxResolveInboxesTime prometheus.Histogram
 	apInboxHandlerTimes        map[string]prometheus.Histogram
 	apOutboxActivityCounts     map[string]prometheus.Counter
+	apDeliveryTimes            *prometheus.HistogramVec
+	apDeliveryFailureCounts    *prometheus.CounterVec
+	apActorKeyChangeCounts     *prometheus.CounterVec
+	apInboxRejectionCounts     *prometheus.CounterVec
 
 	anchorWriteTime                          prometheus.Histogram
 	anchorWitnessTime                        prometheus.Histogram
@@ -169,9 +192,12 @@ type Metrics struct {
 	opqueueBatchCutTime      prometheus.Histogram
 	opqueueBatchRollbackTime prometheus.Histogram
 	opqueueBatchSize         prometheus.Gauge
+	opqueueQueueSize         prometheus.Gauge
 
-	observerProcessAnchorTime prometheus.Histogram
-	observerProcessDIDTime    prometheus.Histogram
+	observerProcessAnchorTime       prometheus.Histogram
+	observerProcessDIDTime          prometheus.Histogram
+	observerProcessAnchorStageTimes *prometheus.HistogramVec
+	observerAnchorLag               prometheus.Gauge
 
 	casWriteTime     prometheus.Histogram
 	casResolveTime   prometheus.Histogram
@@ -228,6 +254,12 @@ type Metrics struct {
 	coreGetCreateOperationResultTime prometheus.Histogram
 	coreHTTPCreateUpdateTime         prometheus.Histogram
 	coreHTTPResolveTime              prometheus.Histogram
+
+	circuitBreakerStates         *prometheus.GaugeVec
+	circuitBreakerRejectedCounts *prometheus.CounterVec
+
+	anchorWrittenCount         *persistentCounter
+	docProcessedOperationCount *persistentCounter
 }
 
 // Get returns an Orb metrics provider.
@@ -265,8 +297,11 @@ func newMetrics() *Metrics { //nolint:funlen,gocyclo,cyclop
 		opqueueBatchCutTime:                          newOpQueueBatchCutTime(),
 		opqueueBatchRollbackTime:                     newOpQueueBatchRollbackTime(),
 		opqueueBatchSize:                             newOpQueueBatchSize(),
+		opqueueQueueSize:                             newOpQueueQueueSize(),
 		observerProcessAnchorTime:                    newObserverProcessAnchorTime(),
 		observerProcessDIDTime:                       newObserverProcessDIDTime(),
+		observerProcessAnchorStageTimes:              newObserverProcessAnchorStageTimes(),
+		observerAnchorLag:                            newObserverAnchorLag(),
 		casWriteTime:                                 newCASWriteTime(),
 		casResolveTime:                               newCASResolveTime(),
 		casReadTimes:                                 newCASReadTimes(),
@@ -317,6 +352,14 @@ func newMetrics() *Metrics { //nolint:funlen,gocyclo,cyclop
 		coreGetCreateOperationResultTime:             newCoreGetCreateOperationResultTime(),
 		coreHTTPCreateUpdateTime:                     newCoreHTTPCreateUpdateTime(),
 		coreHTTPResolveTime:                          newCoreHTTPResolveTime(),
+		circuitBreakerStates:                         newCircuitBreakerStates(),
+		circuitBreakerRejectedCounts:                 newCircuitBreakerRejectedCounts(),
+		apDeliveryTimes:                              newDeliveryTimes(),
+		apDeliveryFailureCounts:                      newDeliveryFailureCounts(),
+		apActorKeyChangeCounts:                       newActorKeyChangeCounts(),
+		apInboxRejectionCounts:                       newInboxRejectionCounts(),
+		anchorWrittenCount:                           newAnchorWrittenCount(),
+		docProcessedOperationCount:                   newDocProcessedOperationCount(),
 	}
 
 	prometheus.MustRegister(
@@ -327,7 +370,8 @@ func newMetrics() *Metrics { //nolint:funlen,gocyclo,cyclop
 		m.anchorWriteSignWithLocalWitnessTime, m.anchorWriteSignWithServerKeyTime, m.anchorWriteSignLocalWitnessLogTime,
 		m.anchorWriteSignLocalStoreTime, m.anchorWriteSignLocalWatchTime,
 		m.opqueueAddOperationTime, m.opqueueBatchCutTime, m.opqueueBatchRollbackTime,
-		m.opqueueBatchSize, m.observerProcessAnchorTime, m.observerProcessDIDTime,
+		m.opqueueBatchSize, m.opqueueQueueSize, m.observerProcessAnchorTime, m.observerProcessDIDTime,
+		m.observerProcessAnchorStageTimes, m.observerAnchorLag,
 		m.casWriteTime, m.casResolveTime, m.casCacheHitCount,
 		m.docCreateUpdateTime, m.docResolveTime,
 		m.vctWitnessAddProofVCTNilTimes, m.vctWitnessAddVCTimes, m.vctWitnessAddProofTimes,
@@ -345,6 +389,9 @@ func newMetrics() *Metrics { //nolint:funlen,gocyclo,cyclop
 		m.coreParseOperationTime, m.coreValidateOperationTime, m.coreDecorateOperationTime,
 		m.coreAddUnpublishedOperationTime, m.coreAddOperationToBatchTime, m.coreGetCreateOperationResultTime,
 		m.coreHTTPCreateUpdateTime, m.coreHTTPResolveTime,
+		m.circuitBreakerStates, m.circuitBreakerRejectedCounts,
+		m.apDeliveryTimes, m.apDeliveryFailureCounts, m.apActorKeyChangeCounts, m.apInboxRejectionCounts,
+		m.anchorWrittenCount.collector, m.docProcessedOperationCount.collector,
 	)
 
 	for _, c := range m.apInboxHandlerTimes {
@@ -559,6 +606,13 @@ func (m *Metrics) BatchSize(value float64) {
 	logger.Infof("BatchSize: %s", value)
 }
 
+// QueueSize records the number of operations that are pending in the operation queue.
+func (m *Metrics) QueueSize(value float64) {
+	m.opqueueQueueSize.Set(value)
+
+	logger.Debugf("QueueSize: %.0f", value)
+}
+
 // ProcessAnchorTime records the time it takes for the Observer to process an anchor credential.
 func (m *Metrics) ProcessAnchorTime(value time.Duration) {
 	m.observerProcessAnchorTime.Observe(value.Seconds())
@@ -573,6 +627,21 @@ func (m *Metrics) ProcessDIDTime(value time.Duration) {
 	logger.Infof("ProcessDID time: %s", value)
 }
 
+// ProcessAnchorStageTime records the time it takes for the Observer to complete the given stage
+// of processing an anchor (for example, retrieving the anchor from CAS).
+func (m *Metrics) ProcessAnchorStageTime(stage string, value time.Duration) {
+	m.observerProcessAnchorStageTimes.WithLabelValues(stage).Observe(value.Seconds())
+
+	logger.Debugf("ProcessAnchor stage [%s] time: %s", stage, value)
+}
+
+// AnchorLag records the time between the issuance of an anchor and the time that the Observer processed it.
+func (m *Metrics) AnchorLag(value time.Duration) {
+	m.observerAnchorLag.Set(value.Seconds())
+
+	logger.Debugf("Anchor lag: %s", value)
+}
+
 // CASWriteTime records the time it takes to write a document to CAS.
 func (m *Metrics) CASWriteTime(value time.Duration) {
 	m.casWriteTime.Observe(value.Seconds())
@@ -587,6 +656,38 @@ func (m *Metrics) CASResolveTime(value time.Duration) {
 	logger.Debugf("CASResolve time: %s", value)
 }
 
+// AnchorIncrementWrittenCount increments the number of anchors that were written. This counter is persistent
+// (see PersistentCounts).
+func (m *Metrics) AnchorIncrementWrittenCount() {
+	m.anchorWrittenCount.inc()
+}
+
+// PersistentCounts returns the current values of the persistent counters (i.e. the counters which are
+// persisted so that they survive a restart), keyed by counter name.
+func (m *Metrics) PersistentCounts() map[string]uint64 {
+	counts := make(map[string]uint64)
+
+	for _, c := range m.persistentCounters() {
+		counts[c.name] = c.get()
+	}
+
+	return counts
+}
+
+// RestoreCounts adds the given (previously persisted) counts to the persistent counters. Unknown counter
+// names are ignored.
+func (m *Metrics) RestoreCounts(counts map[string]uint64) {
+	for _, c := range m.persistentCounters() {
+		if value, ok := counts[c.name]; ok {
+			c.add(value)
+		}
+	}
+}
+
+func (m *Metrics) persistentCounters() []*persistentCounter {
+	return []*persistentCounter{m.anchorWrittenCount, m.docProcessedOperationCount}
+}
+
 // CASIncrementCacheHitCount increments the number of CAS cache hits.
 func (m *Metrics) CASIncrementCacheHitCount() {
 	m.casCacheHitCount.Inc()
@@ -606,6 +707,12 @@ func (m *Metrics) DocumentCreateUpdateTime(value time.Duration) {
 	logger.Debugf("DocumentCreateUpdate time: %s", value)
 }
 
+// DocumentIncrementProcessedOperationCount increments the number of create/update operations that were
+// successfully processed. This counter is persistent (see PersistentCounts).
+func (m *Metrics) DocumentIncrementProcessedOperationCount() {
+	m.docProcessedOperationCount.inc()
+}
+
 // DocumentResolveTime records the time it takes the REST handler to resolve a document.
 func (m *Metrics) DocumentResolveTime(value time.Duration) {
 	m.docResolveTime.Observe(value.Seconds())
@@ -909,6 +1016,50 @@ func (m *Metrics) SignerSign(value time.Duration) {
 	logger.Debugf("signer sign time: %s", value)
 }
 
+// CircuitBreakerState records the state of the circuit breaker for the given host
+// (0 - closed, 1 - open, 2 - half-open).
+func (m *Metrics) CircuitBreakerState(host string, state int) {
+	m.circuitBreakerStates.WithLabelValues(host).Set(float64(state))
+
+	logger.Debugf("Circuit breaker state for host [%s]: %d", host, state)
+}
+
+// CircuitBreakerRejected increments the number of requests to the given host that were rejected
+// because the circuit breaker was open.
+func (m *Metrics) CircuitBreakerRejected(host string) {
+	m.circuitBreakerRejectedCounts.WithLabelValues(host).Inc()
+
+	logger.Debugf("Circuit breaker rejected request to host [%s]", host)
+}
+
+// OutboxDeliveryTime records the time it takes to deliver an activity to an inbox on the given host.
+func (m *Metrics) OutboxDeliveryTime(host string, value time.Duration) {
+	m.apDeliveryTimes.WithLabelValues(host).Observe(value.Seconds())
+
+	logger.Debugf("Outbox delivery time for host [%s]: %s", host, value)
+}
+
+// OutboxDeliveryFailed increments the number of failed deliveries of activities to an inbox on the given host.
+func (m *Metrics) OutboxDeliveryFailed(host string) {
+	m.apDeliveryFailureCounts.WithLabelValues(host).Inc()
+
+	logger.Debugf("Outbox delivery to host [%s] failed", host)
+}
+
+// ActorKeyChanged increments the number of unexpected public key changes of actors on the given host.
+func (m *Metrics) ActorKeyChanged(host string) {
+	m.apActorKeyChangeCounts.WithLabelValues(host).Inc()
+
+	logger.Debugf("Public key of actor on host [%s] changed", host)
+}
+
+// InboxActivityRejected increments the number of activities rejected by the inbox for the given reason.
+func (m *Metrics) InboxActivityRejected(reason string) {
+	m.apInboxRejectionCounts.WithLabelValues(reason).Inc()
+
+	logger.Debugf("Inbox activity rejected: %s", reason)
+}
+
 func newCounter(subsystem, name, help string, labels prometheus.Labels) prometheus.Counter {
 	return prometheus.NewCounter(prometheus.CounterOpts{
 		Namespace:   namespace,
@@ -919,6 +1070,44 @@ func newCounter(subsystem, name, help string, labels prometheus.Labels) promethe
 	})
 }
 
+// persistentCounter is a counter whose value may be read and restored so that it may be persisted across
+// restarts. The value is exposed to Prometheus via a counter function.
+type persistentCounter struct {
+	name      string
+	value     uint64
+	collector prometheus.CounterFunc
+}
+
+func newPersistentCounter(subsystem, name, help string) *persistentCounter {
+	c := &persistentCounter{name: prometheus.BuildFQName(namespace, subsystem, name)}
+
+	c.collector = prometheus.NewCounterFunc(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      name,
+			Help:      help,
+		},
+		func() float64 {
+			return float64(c.get())
+		},
+	)
+
+	return c
+}
+
+func (c *persistentCounter) inc() {
+	atomic.AddUint64(&c.value, 1)
+}
+
+func (c *persistentCounter) add(value uint64) {
+	atomic.AddUint64(&c.value, value)
+}
+
+func (c *persistentCounter) get() uint64 {
+	return atomic.LoadUint64(&c.value)
+}
+
 func newGauge(subsystem, name, help string) prometheus.Gauge {
 	return prometheus.NewGauge(prometheus.GaugeOpts{
 		Namespace: namespace,
@@ -1138,6 +1327,21 @@ func newOpQueueBatchSize() prometheus.Gauge {
 	)
 }
 
+func newOpQueueQueueSize() prometheus.Gauge {
+	return newGauge(
+		operationQueue, opQueueQueueSizeMetric,
+		"The number of operations that are pending in the operation queue.",
+	)
+}
+
+func newObserverAnchorLag() prometheus.Gauge {
+	return newGauge(
+		observer, observerAnchorLagMetric,
+		"The time (in seconds) between the issuance of the most recently processed anchor and the time that "+
+			"it was processed by the Observer.",
+	)
+}
+
 func newObserverProcessAnchorTime() prometheus.Histogram {
 	return newHistogram(
 		observer, observerProcessAnchorTimeMetric,
@@ -1154,6 +1358,15 @@ func newObserverProcessDIDTime() prometheus.Histogram {
 	)
 }
 
+func newObserverProcessAnchorStageTimes() *prometheus.HistogramVec {
+	return prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: observer,
+		Name:      observerProcessAnchorStageTimeMetric,
+		Help:      "The time (in seconds) that it takes for the Observer to complete a stage of processing an anchor.",
+	}, []string{stageLabel})
+}
+
 func newCASWriteTime() prometheus.Histogram {
 	return newHistogram(
 		cas, casWriteTimeMetric,
@@ -1178,6 +1391,21 @@ func newCASCacheHitCount() prometheus.Counter {
 	)
 }
 
+func newAnchorWrittenCount() *persistentCounter {
+	return newPersistentCounter(
+		anchor, anchorWrittenCountMetric,
+		"The total number of anchors that were written (including anchors written before the last restart).",
+	)
+}
+
+func newDocProcessedOperationCount() *persistentCounter {
+	return newPersistentCounter(
+		document, docProcessedOperationCountMetric,
+		"The total number of create/update operations that were processed (including operations processed "+
+			"before the last restart).",
+	)
+}
+
 func newCASReadTimes() map[string]prometheus.Histogram {
 	times := make(map[string]prometheus.Histogram)
 
@@ -1585,3 +1813,57 @@ func newCoreHTTPResolveTime() prometheus.Histogram {
 		nil,
 	)
 }
+
+func newCircuitBreakerStates() *prometheus.GaugeVec {
+	return prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: circuitBreaker,
+		Name:      circuitBreakerStateMetric,
+		Help:      "The state of the circuit breaker for a host (0 - closed, 1 - open, 2 - half-open).",
+	}, []string{hostLabel})
+}
+
+func newCircuitBreakerRejectedCounts() *prometheus.CounterVec {
+	return prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: circuitBreaker,
+		Name:      circuitBreakerRejectedCount,
+		Help:      "The number of requests to a host that were rejected because the circuit breaker was open.",
+	}, []string{hostLabel})
+}
+
+func newDeliveryTimes() *prometheus.HistogramVec {
+	return prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: activityPub,
+		Name:      apDeliveryTimeMetric,
+		Help:      "The time (in seconds) that it takes to deliver an activity to the inbox of a follower/witness.",
+	}, []string{hostLabel})
+}
+
+func newDeliveryFailureCounts() *prometheus.CounterVec {
+	return prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: activityPub,
+		Name:      apDeliveryFailureCountMetric,
+		Help:      "The number of failed deliveries of activities to the inbox of a follower/witness.",
+	}, []string{hostLabel})
+}
+
+func newActorKeyChangeCounts() *prometheus.CounterVec {
+	return prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: activityPub,
+		Name:      apActorKeyChangeCountMetric,
+		Help:      "The number of times that the public key of a followed/witness actor changed from the pinned key.",
+	}, []string{hostLabel})
+}
+
+func newInboxRejectionCounts() *prometheus.CounterVec {
+	return prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: activityPub,
+		Name:      apInboxRejectionCountMetric,
+		Help:      "The number of activities rejected by the inbox, by reason.",
+	}, []string{reasonLabel})
+}
@@ -24,6 +24,7 @@ func TestMetrics(t *testing.T) {
 		require.NotPanics(t, func() { m.OutboxPostTime(time.Second) })
 		require.NotPanics(t, func() { m.OutboxResolveInboxesTime(time.Second) })
 		require.NotPanics(t, func() { m.WriteAnchorTime(time.Second) })
+		require.NotPanics(t, func() { m.AnchorIncrementWrittenCount() })
 		require.NotPanics(t, func() { m.WriteAnchorBuildCredentialTime(time.Second) })
 		require.NotPanics(t, func() { m.WriteAnchorSignCredentialTime(time.Second) })
 		require.NotPanics(t, func() { m.WriteAnchorPostOfferActivityTime(time.Second) })
@@ -42,13 +43,23 @@ func TestMetrics(t *testing.T) {
 		require.NotPanics(t, func() { m.BatchCutTime(time.Second) })
 		require.NotPanics(t, func() { m.BatchRollbackTime(time.Second) })
 		require.NotPanics(t, func() { m.BatchSize(float64(500)) })
+		require.NotPanics(t, func() { m.QueueSize(float64(10)) })
 		require.NotPanics(t, func() { m.ProcessAnchorTime(time.Second) })
 		require.NotPanics(t, func() { m.ProcessDIDTime(time.Second) })
+		require.NotPanics(t, func() { m.ProcessAnchorStageTime("cas_fetch", time.Second) })
+		require.NotPanics(t, func() { m.AnchorLag(time.Second) })
 		require.NotPanics(t, func() { m.CASWriteTime(time.Second) })
 		require.NotPanics(t, func() { m.CASResolveTime(time.Second) })
 		require.NotPanics(t, func() { m.CASIncrementCacheHitCount() })
 		require.NotPanics(t, func() { m.CASReadTime("local", time.Second) })
+		require.NotPanics(t, func() { m.CircuitBreakerState("orb.domain1.com", 1) })
+		require.NotPanics(t, func() { m.CircuitBreakerRejected("orb.domain1.com") })
+		require.NotPanics(t, func() { m.OutboxDeliveryTime("orb.domain1.com", time.Second) })
+		require.NotPanics(t, func() { m.OutboxDeliveryFailed("orb.domain1.com") })
+		require.NotPanics(t, func() { m.ActorKeyChanged("orb.domain1.com") })
+		require.NotPanics(t, func() { m.InboxActivityRejected("signature") })
 		require.NotPanics(t, func() { m.DocumentCreateUpdateTime(time.Second) })
+		require.NotPanics(t, func() { m.DocumentIncrementProcessedOperationCount() })
 		require.NotPanics(t, func() { m.DocumentResolveTime(time.Second) })
 		require.NotPanics(t, func() { m.OutboxIncrementActivityCount("Create") })
 		require.NotPanics(t, func() { m.DBPutTime("CouchDB", time.Second) })
@@ -96,6 +107,36 @@ func TestMetrics(t *testing.T) {
 	})
 }
 
+func TestMetrics_PersistentCounts(t *testing.T) {
+	m := Get()
+
+	before := m.PersistentCounts()
+	require.Contains(t, before, "orb_anchor_written_count")
+	require.Contains(t, before, "orb_document_processed_operation_count")
+
+	m.AnchorIncrementWrittenCount()
+	m.RestoreCounts(map[string]uint64{
+		"orb_document_processed_operation_count": 10,
+		"orb_unknown_count":                      5,
+	})
+
+	after := m.PersistentCounts()
+	require.Len(t, after, 2)
+	require.Equal(t, before["orb_anchor_written_count"]+1, after["orb_anchor_written_count"])
+	require.Equal(t, before["orb_document_processed_operation_count"]+10,
+		after["orb_document_processed_operation_count"])
+}
+
+func TestNewPersistentCounter(t *testing.T) {
+	c := newPersistentCounter("activityPub", "metric_name", "Some help")
+	require.NotNil(t, c.collector)
+	require.Equal(t, "orb_activityPub_metric_name", c.name)
+
+	c.inc()
+	c.add(5)
+	require.Equal(t, uint64(6), c.get())
+}
+
 func TestNewCounter(t *testing.T) {
 	labels := prometheus.Labels{"type": "create"}
 
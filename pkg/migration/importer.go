@@ -0,0 +1,374 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package migration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/trustbloc/edge-core/pkg/log"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/txn"
+
+	"github.com/trustbloc/orb/pkg/lifecycle"
+	"github.com/trustbloc/orb/pkg/observer"
+)
+
+var logger = log.New("migration")
+
+const defaultAnchorTimeout = 10 * time.Minute
+
+// ErrImportInProgress is returned when an import is requested while another import is in progress.
+var ErrImportInProgress = errors.New("an import is already in progress")
+
+type operationProvider interface {
+	GetTxnOperations(sidetreeTxn *txn.SidetreeTxn) ([]*operation.AnchoredOperation, error)
+}
+
+type operationWriter interface {
+	Add(op *operation.QueuedOperation, protocolVersion uint64) error
+}
+
+type protocolClient interface {
+	Current() (protocol.Version, error)
+}
+
+type provenanceStore interface {
+	Get(suffix string) (*Provenance, error)
+	Add(suffix string, op *ImportedOperation) error
+}
+
+// Config contains the configuration of the importer.
+type Config struct {
+	// Namespace is the Orb DID namespace into which operations are imported.
+	Namespace string
+	// AnchorOrigin is the service IRI of this server. The imported operations don't contain an anchor origin
+	// (since it's an Orb specific field) so this server is used as the anchor origin of the imported DIDs.
+	AnchorOrigin string
+}
+
+// Providers contains the dependencies of the importer.
+type Providers struct {
+	OperationProvider operationProvider
+	Writer            operationWriter
+	ProtocolClient    protocolClient
+	Store             provenanceStore
+	Tracker           *Tracker
+}
+
+// Opt sets an importer option.
+type Opt func(opts *options)
+
+type options struct {
+	anchorTimeout time.Duration
+}
+
+// WithAnchorTimeout sets the maximum time to wait for the operations of a source transaction to be anchored.
+func WithAnchorTimeout(value time.Duration) Opt {
+	return func(opts *options) {
+		opts.anchorTimeout = value
+	}
+}
+
+// Importer ports DIDs from another Sidetree network (such as did:ion) into Orb. The operations of each source
+// transaction are read from the batch files in CAS and are replayed through the batch writer, which creates Orb
+// anchor events for them. The importer waits for the operations of a transaction to be anchored before moving
+// on to the next transaction so that the operations of a DID are anchored in the same order as in the source
+// network. The provenance of each imported operation (source transaction and resulting Orb anchor) is recorded.
+//
+// The DIDs keep their unique suffix, so did:ion:<suffix> is resolvable as did:orb:<anchor>:<suffix> once imported.
+type Importer struct {
+	*lifecycle.Lifecycle
+	*options
+	*Providers
+
+	cfg    *Config
+	mutex  sync.RWMutex
+	status *Status
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	now    func() time.Time
+}
+
+// NewImporter returns a new importer.
+func NewImporter(cfg *Config, providers *Providers, opts ...Opt) *Importer {
+	options := &options{
+		anchorTimeout: defaultAnchorTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	i := &Importer{
+		options:   options,
+		Providers: providers,
+		cfg:       cfg,
+		status:    &Status{State: StateIdle},
+		ctx:       ctx,
+		cancel:    cancel,
+		now:       time.Now,
+	}
+
+	i.Lifecycle = lifecycle.New("migration-importer", lifecycle.WithStop(i.stop))
+
+	return i
+}
+
+// Import validates the given request and starts importing the transactions in the background. The progress
+// of the import may be retrieved using Status.
+func (i *Importer) Import(req *Request) error {
+	if err := validate(req); err != nil {
+		return err
+	}
+
+	if i.State() != lifecycle.StateStarted {
+		return errors.New("importer is not started")
+	}
+
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	if i.status.State == StateRunning {
+		return ErrImportInProgress
+	}
+
+	started := i.now()
+
+	i.status = &Status{
+		State:             StateRunning,
+		Namespace:         req.Namespace,
+		TotalTransactions: len(req.Transactions),
+		Started:           &started,
+	}
+
+	i.wg.Add(1)
+
+	go i.run(req)
+
+	return nil
+}
+
+// Status returns the status of the current (or last) import.
+func (i *Importer) Status() *Status {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	status := *i.status
+
+	return &status
+}
+
+func (i *Importer) stop() {
+	i.cancel()
+	i.wg.Wait()
+}
+
+func (i *Importer) run(req *Request) {
+	defer i.wg.Done()
+
+	logger.Infof("Importing %d transaction(s) from [%s]", len(req.Transactions), req.Namespace)
+
+	for _, t := range req.Transactions {
+		imported, skipped, err := i.importTransaction(req.Namespace, t)
+		if err != nil {
+			logger.Errorf("Error importing transaction [%d] from [%s]: %s", t.Number, req.Namespace, err)
+
+			i.complete(StateFailed, fmt.Errorf("transaction [%d]: %w", t.Number, err))
+
+			return
+		}
+
+		i.mutex.Lock()
+		i.status.ImportedTransactions++
+		i.status.ImportedOperations += imported
+		i.status.SkippedOperations += skipped
+		i.mutex.Unlock()
+	}
+
+	logger.Infof("Completed importing %d transaction(s) from [%s]", len(req.Transactions), req.Namespace)
+
+	i.complete(StateCompleted, nil)
+}
+
+func (i *Importer) complete(state State, err error) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	completed := i.now()
+
+	i.status.State = state
+	i.status.Completed = &completed
+
+	if err != nil {
+		i.status.Error = err.Error()
+	}
+}
+
+// importTransaction submits the operations of the given source transaction to the writer and waits for all of
+// them to be anchored. Operations that were imported previously (by an earlier run) are skipped. The number of
+// imported and skipped operations is returned.
+func (i *Importer) importTransaction(namespace string, t *Transaction) (int, int, error) {
+	ops, err := i.OperationProvider.GetTxnOperations(&txn.SidetreeTxn{
+		TransactionTime:   t.Time,
+		TransactionNumber: t.Number,
+		AnchorString:      t.AnchorString,
+		Namespace:         namespace,
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("get operations: %w", err)
+	}
+
+	total := len(ops)
+
+	ops, err = i.filterImported(ops, t)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	skipped := total - len(ops)
+
+	if len(ops) == 0 {
+		return 0, skipped, nil
+	}
+
+	pv, err := i.ProtocolClient.Current()
+	if err != nil {
+		return 0, 0, fmt.Errorf("get current protocol version: %w", err)
+	}
+
+	anchors := make(map[string]<-chan *observer.ProcessedAnchor)
+
+	defer func() {
+		for suffix := range anchors {
+			i.Tracker.unwatch(suffix)
+		}
+	}()
+
+	for _, op := range ops {
+		if _, exists := anchors[op.UniqueSuffix]; exists {
+			return 0, 0, fmt.Errorf("duplicate suffix in transaction: %s", op.UniqueSuffix)
+		}
+
+		anchors[op.UniqueSuffix] = i.Tracker.watch(op.UniqueSuffix)
+	}
+
+	for _, op := range ops {
+		err = i.Writer.Add(&operation.QueuedOperation{
+			Namespace:        i.cfg.Namespace,
+			UniqueSuffix:     op.UniqueSuffix,
+			OperationRequest: op.OperationRequest,
+			AnchorOrigin:     i.cfg.AnchorOrigin,
+		}, pv.Protocol().GenesisTime)
+		if err != nil {
+			return 0, 0, fmt.Errorf("add operation for suffix [%s] to writer: %w", op.UniqueSuffix, err)
+		}
+	}
+
+	err = i.awaitAnchors(namespace, t, ops, anchors)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return len(ops), skipped, nil
+}
+
+func (i *Importer) awaitAnchors(namespace string, t *Transaction, ops []*operation.AnchoredOperation,
+	anchors map[string]<-chan *observer.ProcessedAnchor) error {
+	timer := time.NewTimer(i.anchorTimeout)
+	defer timer.Stop()
+
+	for _, op := range ops {
+		var anchor *observer.ProcessedAnchor
+
+		select {
+		case anchor = <-anchors[op.UniqueSuffix]:
+		case <-timer.C:
+			return fmt.Errorf("timed out waiting for operation for suffix [%s] to be anchored", op.UniqueSuffix)
+		case <-i.ctx.Done():
+			return errors.New("importer stopped")
+		}
+
+		logger.Debugf("Operation [%s] for suffix [%s] from transaction [%d] was anchored in [%s]",
+			op.Type, op.UniqueSuffix, t.Number, anchor.Hashlink)
+
+		err := i.Store.Add(op.UniqueSuffix, &ImportedOperation{
+			Type:                    op.Type,
+			SourceNamespace:         namespace,
+			SourceAnchorString:      t.AnchorString,
+			SourceTransactionNumber: t.Number,
+			SourceTransactionTime:   t.Time,
+			CanonicalReference:      anchor.CanonicalReference,
+			Hashlink:                anchor.Hashlink,
+			Imported:                i.now(),
+		})
+		if err != nil {
+			return fmt.Errorf("store provenance for suffix [%s]: %w", op.UniqueSuffix, err)
+		}
+	}
+
+	return nil
+}
+
+// filterImported returns the operations that haven't already been imported from the given transaction.
+func (i *Importer) filterImported(ops []*operation.AnchoredOperation,
+	t *Transaction) ([]*operation.AnchoredOperation, error) {
+	var filtered []*operation.AnchoredOperation
+
+	for _, op := range ops {
+		prov, err := i.Store.Get(op.UniqueSuffix)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return nil, fmt.Errorf("get provenance for suffix [%s]: %w", op.UniqueSuffix, err)
+		}
+
+		if prov != nil && prov.contains(t) {
+			logger.Debugf("Operation for suffix [%s] from transaction [%d] was already imported",
+				op.UniqueSuffix, t.Number)
+
+			continue
+		}
+
+		filtered = append(filtered, op)
+	}
+
+	return filtered, nil
+}
+
+func (p *Provenance) contains(t *Transaction) bool {
+	for _, op := range p.Operations {
+		if op.SourceTransactionNumber == t.Number && op.SourceAnchorString == t.AnchorString {
+			return true
+		}
+	}
+
+	return false
+}
+
+func validate(req *Request) error {
+	if req.Namespace == "" {
+		return errors.New("namespace is required")
+	}
+
+	if len(req.Transactions) == 0 {
+		return errors.New("at least one transaction is required")
+	}
+
+	for _, t := range req.Transactions {
+		if t.AnchorString == "" {
+			return fmt.Errorf("anchor string is required for transaction [%d]", t.Number)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,350 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package migration
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/txn"
+	coremocks "github.com/trustbloc/sidetree-core-go/pkg/mocks"
+
+	"github.com/trustbloc/orb/pkg/observer"
+)
+
+const (
+	ionNamespace = "did:ion"
+	orbNamespace = "did:orb"
+	anchorOrigin = "https://orb.domain1.com/services/orb"
+)
+
+func TestImporter_Import(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		i, writer := newTestImporter(t, &mockOperationProvider{})
+
+		require.NoError(t, i.Import(&Request{
+			Namespace: ionNamespace,
+			Transactions: []*Transaction{
+				{Number: 1, Time: 100, AnchorString: "2.txn1"},
+				{Number: 2, Time: 200, AnchorString: "1.txn2"},
+			},
+		}))
+
+		status := waitForCompletion(t, i)
+		require.Equal(t, StateCompleted, status.State)
+		require.Equal(t, ionNamespace, status.Namespace)
+		require.Equal(t, 2, status.TotalTransactions)
+		require.Equal(t, 2, status.ImportedTransactions)
+		require.Equal(t, 3, status.ImportedOperations)
+		require.Zero(t, status.SkippedOperations)
+		require.NotNil(t, status.Started)
+		require.NotNil(t, status.Completed)
+
+		ops := writer.operations()
+		require.Len(t, ops, 3)
+
+		for _, op := range ops {
+			require.Equal(t, orbNamespace, op.Namespace)
+			require.Equal(t, anchorOrigin, op.AnchorOrigin)
+		}
+
+		require.Equal(t, "txn1-suffix0", ops[0].UniqueSuffix)
+		require.Equal(t, "txn2-suffix0", ops[2].UniqueSuffix)
+
+		prov, err := i.Store.Get("txn1-suffix1")
+		require.NoError(t, err)
+		require.Len(t, prov.Operations, 1)
+		require.Equal(t, ionNamespace, prov.Operations[0].SourceNamespace)
+		require.Equal(t, uint64(1), prov.Operations[0].SourceTransactionNumber)
+		require.Equal(t, uint64(100), prov.Operations[0].SourceTransactionTime)
+		require.Equal(t, "2.txn1", prov.Operations[0].SourceAnchorString)
+		require.Equal(t, "hl:anchor-txn1-suffix1", prov.Operations[0].Hashlink)
+		require.Equal(t, "anchor-txn1-suffix1", prov.Operations[0].CanonicalReference)
+
+		// Importing the same transactions again skips the operations that were already imported.
+		require.NoError(t, i.Import(&Request{
+			Namespace:    ionNamespace,
+			Transactions: []*Transaction{{Number: 1, Time: 100, AnchorString: "2.txn1"}},
+		}))
+
+		status = waitForCompletion(t, i)
+		require.Equal(t, StateCompleted, status.State)
+		require.Zero(t, status.ImportedOperations)
+		require.Equal(t, 2, status.SkippedOperations)
+		require.Len(t, writer.operations(), 3)
+	})
+
+	t.Run("Invalid request", func(t *testing.T) {
+		i, _ := newTestImporter(t, &mockOperationProvider{})
+
+		err := i.Import(&Request{Transactions: []*Transaction{{AnchorString: "1.txn1"}}})
+		require.EqualError(t, err, "namespace is required")
+
+		err = i.Import(&Request{Namespace: ionNamespace})
+		require.EqualError(t, err, "at least one transaction is required")
+
+		err = i.Import(&Request{Namespace: ionNamespace, Transactions: []*Transaction{{Number: 1}}})
+		require.EqualError(t, err, "anchor string is required for transaction [1]")
+	})
+
+	t.Run("Import in progress", func(t *testing.T) {
+		i, writer := newTestImporter(t, &mockOperationProvider{})
+		writer.anchor = false
+
+		req := &Request{
+			Namespace:    ionNamespace,
+			Transactions: []*Transaction{{Number: 1, AnchorString: "1.txn1"}},
+		}
+
+		require.NoError(t, i.Import(req))
+		require.True(t, errors.Is(i.Import(req), ErrImportInProgress))
+
+		i.Stop()
+
+		status := i.Status()
+		require.Equal(t, StateFailed, status.State)
+		require.Contains(t, status.Error, "importer stopped")
+
+		require.EqualError(t, i.Import(req), "importer is not started")
+	})
+
+	t.Run("Anchor timeout", func(t *testing.T) {
+		i, writer := newTestImporter(t, &mockOperationProvider{}, WithAnchorTimeout(10*time.Millisecond))
+		writer.anchor = false
+
+		require.NoError(t, i.Import(&Request{
+			Namespace:    ionNamespace,
+			Transactions: []*Transaction{{Number: 1, AnchorString: "1.txn1"}},
+		}))
+
+		status := waitForCompletion(t, i)
+		require.Equal(t, StateFailed, status.State)
+		require.Contains(t, status.Error, "timed out waiting for operation for suffix [txn1-suffix0] to be anchored")
+	})
+
+	t.Run("Operation provider error", func(t *testing.T) {
+		i, _ := newTestImporter(t, &mockOperationProvider{err: errors.New("injected CAS error")})
+
+		require.NoError(t, i.Import(&Request{
+			Namespace:    ionNamespace,
+			Transactions: []*Transaction{{Number: 1, AnchorString: "1.txn1"}},
+		}))
+
+		status := waitForCompletion(t, i)
+		require.Equal(t, StateFailed, status.State)
+		require.Contains(t, status.Error, "injected CAS error")
+	})
+
+	t.Run("Duplicate suffix", func(t *testing.T) {
+		i, _ := newTestImporter(t, &mockOperationProvider{duplicate: true})
+
+		require.NoError(t, i.Import(&Request{
+			Namespace:    ionNamespace,
+			Transactions: []*Transaction{{Number: 1, AnchorString: "2.txn1"}},
+		}))
+
+		status := waitForCompletion(t, i)
+		require.Equal(t, StateFailed, status.State)
+		require.Contains(t, status.Error, "duplicate suffix in transaction")
+	})
+
+	t.Run("Writer error", func(t *testing.T) {
+		i, writer := newTestImporter(t, &mockOperationProvider{})
+		writer.err = errors.New("injected writer error")
+
+		require.NoError(t, i.Import(&Request{
+			Namespace:    ionNamespace,
+			Transactions: []*Transaction{{Number: 1, AnchorString: "1.txn1"}},
+		}))
+
+		status := waitForCompletion(t, i)
+		require.Equal(t, StateFailed, status.State)
+		require.Contains(t, status.Error, "injected writer error")
+	})
+
+	t.Run("Protocol client error", func(t *testing.T) {
+		i, _ := newTestImporter(t, &mockOperationProvider{})
+		i.ProtocolClient = &mockProtocolClient{err: errors.New("injected protocol error")}
+
+		require.NoError(t, i.Import(&Request{
+			Namespace:    ionNamespace,
+			Transactions: []*Transaction{{Number: 1, AnchorString: "1.txn1"}},
+		}))
+
+		status := waitForCompletion(t, i)
+		require.Equal(t, StateFailed, status.State)
+		require.Contains(t, status.Error, "injected protocol error")
+	})
+
+	t.Run("Store error", func(t *testing.T) {
+		i, _ := newTestImporter(t, &mockOperationProvider{})
+		i.Store = &mockProvenanceStore{err: errors.New("injected store error")}
+
+		require.NoError(t, i.Import(&Request{
+			Namespace:    ionNamespace,
+			Transactions: []*Transaction{{Number: 1, AnchorString: "1.txn1"}},
+		}))
+
+		status := waitForCompletion(t, i)
+		require.Equal(t, StateFailed, status.State)
+		require.Contains(t, status.Error, "injected store error")
+	})
+}
+
+func newTestImporter(t *testing.T, opProvider *mockOperationProvider, opts ...Opt) (*Importer, *mockWriter) {
+	t.Helper()
+
+	store, err := NewStore(mem.NewProvider())
+	require.NoError(t, err)
+
+	tracker := NewTracker()
+
+	writer := &mockWriter{tracker: tracker, anchor: true}
+
+	i := NewImporter(
+		&Config{Namespace: orbNamespace, AnchorOrigin: anchorOrigin},
+		&Providers{
+			OperationProvider: opProvider,
+			Writer:            writer,
+			ProtocolClient:    &mockProtocolClient{},
+			Store:             store,
+			Tracker:           tracker,
+		},
+		opts...,
+	)
+
+	i.Start()
+
+	t.Cleanup(i.Stop)
+
+	return i, writer
+}
+
+func waitForCompletion(t *testing.T, i *Importer) *Status {
+	t.Helper()
+
+	for j := 0; j < 100; j++ {
+		status := i.Status()
+		if status.State != StateRunning {
+			return status
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	require.FailNow(t, "timed out waiting for import to complete")
+
+	return nil
+}
+
+// mockOperationProvider returns an operation for each operation in the anchor string ("<count>.<id>"). The suffix
+// of each operation is "<id>-suffix<n>".
+type mockOperationProvider struct {
+	err       error
+	duplicate bool
+}
+
+func (m *mockOperationProvider) GetTxnOperations(sidetreeTxn *txn.SidetreeTxn) ([]*operation.AnchoredOperation, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	var count int
+
+	var id string
+
+	if _, err := fmt.Sscanf(sidetreeTxn.AnchorString, "%d.%s", &count, &id); err != nil {
+		return nil, err
+	}
+
+	ops := make([]*operation.AnchoredOperation, count)
+
+	for j := 0; j < count; j++ {
+		suffix := fmt.Sprintf("%s-suffix%d", id, j)
+		if m.duplicate {
+			suffix = id + "-suffix"
+		}
+
+		ops[j] = &operation.AnchoredOperation{
+			Type:             operation.TypeCreate,
+			UniqueSuffix:     suffix,
+			OperationRequest: []byte(suffix),
+		}
+	}
+
+	return ops, nil
+}
+
+// mockWriter notifies the tracker that the operation was anchored (as the observer would).
+type mockWriter struct {
+	tracker *Tracker
+	anchor  bool
+	err     error
+	mutex   sync.Mutex
+	ops     []*operation.QueuedOperation
+}
+
+func (m *mockWriter) Add(op *operation.QueuedOperation, _ uint64) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	m.mutex.Lock()
+	m.ops = append(m.ops, op)
+	m.mutex.Unlock()
+
+	if m.anchor {
+		go m.tracker.AnchorProcessed(&observer.ProcessedAnchor{
+			Hashlink:           "hl:anchor-" + op.UniqueSuffix,
+			CanonicalReference: "anchor-" + op.UniqueSuffix,
+			Suffixes:           []string{op.UniqueSuffix},
+		})
+	}
+
+	return nil
+}
+
+func (m *mockWriter) operations() []*operation.QueuedOperation {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.ops
+}
+
+type mockProtocolClient struct {
+	err error
+}
+
+func (m *mockProtocolClient) Current() (protocol.Version, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	pv := &coremocks.ProtocolVersion{}
+	pv.ProtocolReturns(protocol.Protocol{GenesisTime: 0})
+
+	return pv, nil
+}
+
+type mockProvenanceStore struct {
+	err error
+}
+
+func (m *mockProvenanceStore) Get(string) (*Provenance, error) {
+	return nil, ErrNotFound
+}
+
+func (m *mockProvenanceStore) Add(string, *ImportedOperation) error {
+	return m.err
+}
@@ -0,0 +1,74 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package migration
+
+import (
+	"time"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+)
+
+// Transaction is a transaction of the source Sidetree network, for example an ION transaction that was anchored
+// in Bitcoin. The anchor string has the form "<operation count>.<core index file URI>".
+type Transaction struct {
+	Number       uint64 `json:"transactionNumber"`
+	Time         uint64 `json:"transactionTime"`
+	AnchorString string `json:"anchorString"`
+}
+
+// Request is a request to import the operations of the given source transactions. The transactions are
+// imported in the order given, so they must be in the order in which they were anchored in the source network.
+type Request struct {
+	// Namespace is the DID namespace of the source network (e.g. did:ion).
+	Namespace    string         `json:"namespace"`
+	Transactions []*Transaction `json:"transactions"`
+}
+
+// State is the state of an import.
+type State string
+
+const (
+	// StateIdle indicates that no import has been requested.
+	StateIdle State = "idle"
+	// StateRunning indicates that an import is in progress.
+	StateRunning State = "running"
+	// StateCompleted indicates that all of the transactions were imported.
+	StateCompleted State = "completed"
+	// StateFailed indicates that the import stopped due to an error.
+	StateFailed State = "failed"
+)
+
+// Status contains the progress of the current (or last) import.
+type Status struct {
+	State                State      `json:"state"`
+	Namespace            string     `json:"namespace,omitempty"`
+	TotalTransactions    int        `json:"totalTransactions"`
+	ImportedTransactions int        `json:"importedTransactions"`
+	ImportedOperations   int        `json:"importedOperations"`
+	SkippedOperations    int        `json:"skippedOperations"`
+	Error                string     `json:"error,omitempty"`
+	Started              *time.Time `json:"started,omitempty"`
+	Completed            *time.Time `json:"completed,omitempty"`
+}
+
+// Provenance contains the origin of the imported operations of a DID.
+type Provenance struct {
+	Suffix     string               `json:"suffix"`
+	Operations []*ImportedOperation `json:"operations"`
+}
+
+// ImportedOperation links an operation of the source network to the Orb anchor that now contains the operation.
+type ImportedOperation struct {
+	Type                    operation.Type `json:"type"`
+	SourceNamespace         string         `json:"sourceNamespace"`
+	SourceAnchorString      string         `json:"sourceAnchorString"`
+	SourceTransactionNumber uint64         `json:"sourceTransactionNumber"`
+	SourceTransactionTime   uint64         `json:"sourceTransactionTime"`
+	CanonicalReference      string         `json:"canonicalReference"`
+	Hashlink                string         `json:"hashlink"`
+	Imported                time.Time      `json:"imported"`
+}
@@ -0,0 +1,26 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package migration
+
+import (
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/operationparser"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/txnprovider"
+
+	"github.com/trustbloc/orb/pkg/compression"
+	"github.com/trustbloc/orb/pkg/context/common"
+	protocolcfg "github.com/trustbloc/orb/pkg/protocolversion/versions/v1_0/config"
+)
+
+// NewOperationProvider returns a provider that reads the operations of a source transaction from the batch files
+// in the given CAS (e.g. the IPFS network that is used by ION). The operations are parsed with the Sidetree 1.0
+// protocol parameters of Orb but without the Orb specific anchor origin and anchor time validators, since
+// operations of other Sidetree networks don't contain these fields.
+func NewOperationProvider(casReader common.CASReader) *txnprovider.OperationProvider {
+	p := protocolcfg.GetProtocolConfig()
+
+	return txnprovider.NewOperationProvider(p, operationparser.New(p), casReader, compression.New())
+}
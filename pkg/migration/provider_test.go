@@ -0,0 +1,41 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package migration
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/txn"
+)
+
+func TestNewOperationProvider(t *testing.T) {
+	p := NewOperationProvider(&mockCASReader{err: errors.New("injected CAS error")})
+	require.NotNil(t, p)
+
+	t.Run("Invalid anchor string", func(t *testing.T) {
+		_, err := p.GetTxnOperations(&txn.SidetreeTxn{Namespace: ionNamespace, AnchorString: "xxx"})
+		require.Error(t, err)
+	})
+
+	t.Run("CAS error", func(t *testing.T) {
+		_, err := p.GetTxnOperations(&txn.SidetreeTxn{
+			Namespace:    ionNamespace,
+			AnchorString: "1.QmWvQxTqbG2Z9HPJgG57jjwR154cKhbtJenbyYTWkjgF3e",
+		})
+		require.Error(t, err)
+	})
+}
+
+type mockCASReader struct {
+	err error
+}
+
+func (m *mockCASReader) Read(string) ([]byte, error) {
+	return nil, m.err
+}
@@ -0,0 +1,234 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/trustbloc/edge-core/pkg/log"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+
+	"github.com/trustbloc/orb/pkg/migration"
+)
+
+const (
+	// MigrationPath is the path of the migration endpoint.
+	MigrationPath = "/migration"
+
+	suffixPathVariable = "suffix"
+)
+
+const (
+	badRequestResponse          = "Bad Request."
+	notFoundResponse            = "Not Found."
+	internalServerErrorResponse = "Internal Server Error."
+)
+
+var logger = log.New("migration-rest-handler")
+
+type importer interface {
+	Import(req *migration.Request) error
+	Status() *migration.Status
+}
+
+type provenanceStore interface {
+	Get(suffix string) (*migration.Provenance, error)
+}
+
+type handler struct {
+	marshal func(v interface{}) ([]byte, error)
+}
+
+func newHandler() *handler {
+	return &handler{marshal: json.Marshal}
+}
+
+// Import starts an import of the operations of a source Sidetree network. The request contains the source
+// namespace and the source transactions (in the order in which they were anchored), for example:
+//
+//	POST /migration
+//	{"namespace":"did:ion","transactions":[{"transactionNumber":1,"transactionTime":1,"anchorString":"1.Qm..."}]}
+//
+// The import runs in the background and its status is returned.
+type Import struct {
+	*handler
+
+	importer importer
+}
+
+// NewImport returns a new import handler.
+func NewImport(i importer) *Import {
+	return &Import{handler: newHandler(), importer: i}
+}
+
+// Path returns the HTTP REST endpoint for the import handler.
+func (h *Import) Path() string {
+	return MigrationPath
+}
+
+// Method returns the HTTP REST method for the import handler.
+func (h *Import) Method() string {
+	return http.MethodPost
+}
+
+// Handler returns the HTTP REST handle for the import handler.
+func (h *Import) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Import) handle(w http.ResponseWriter, req *http.Request) {
+	reqBytes, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		logger.Errorf("[%s] Error reading request body: %s", MigrationPath, err)
+
+		writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	importReq := &migration.Request{}
+
+	err = json.Unmarshal(reqBytes, importReq)
+	if err != nil {
+		logger.Infof("[%s] Invalid import request: %s", MigrationPath, err)
+
+		writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	err = h.importer.Import(importReq)
+	if err != nil {
+		if errors.Is(err, migration.ErrImportInProgress) {
+			writeResponse(w, http.StatusConflict, []byte(err.Error()))
+
+			return
+		}
+
+		logger.Infof("[%s] Invalid import request: %s", MigrationPath, err)
+
+		writeResponse(w, http.StatusBadRequest, []byte(err.Error()))
+
+		return
+	}
+
+	h.writeJSON(w, http.StatusAccepted, h.importer.Status())
+}
+
+// Status returns the status of the current (or last) import.
+type Status struct {
+	*handler
+
+	importer importer
+}
+
+// NewStatus returns a new import status handler.
+func NewStatus(i importer) *Status {
+	return &Status{handler: newHandler(), importer: i}
+}
+
+// Path returns the HTTP REST endpoint for the import status handler.
+func (h *Status) Path() string {
+	return MigrationPath
+}
+
+// Method returns the HTTP REST method for the import status handler.
+func (h *Status) Method() string {
+	return http.MethodGet
+}
+
+// Handler returns the HTTP REST handle for the import status handler.
+func (h *Status) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Status) handle(w http.ResponseWriter, _ *http.Request) {
+	h.writeJSON(w, http.StatusOK, h.importer.Status())
+}
+
+// Provenance returns the provenance of the imported operations of a DID, i.e. the source transaction of each
+// operation and the Orb anchor that contains the operation.
+type Provenance struct {
+	*handler
+
+	store provenanceStore
+}
+
+// NewProvenance returns a new provenance handler.
+func NewProvenance(s provenanceStore) *Provenance {
+	return &Provenance{handler: newHandler(), store: s}
+}
+
+// Path returns the HTTP REST endpoint for the provenance handler.
+func (h *Provenance) Path() string {
+	return fmt.Sprintf("%s/dids/{%s}", MigrationPath, suffixPathVariable)
+}
+
+// Method returns the HTTP REST method for the provenance handler.
+func (h *Provenance) Method() string {
+	return http.MethodGet
+}
+
+// Handler returns the HTTP REST handle for the provenance handler.
+func (h *Provenance) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Provenance) handle(w http.ResponseWriter, req *http.Request) {
+	suffix := mux.Vars(req)[suffixPathVariable]
+
+	prov, err := h.store.Get(suffix)
+	if err != nil {
+		if errors.Is(err, migration.ErrNotFound) {
+			writeResponse(w, http.StatusNotFound, []byte(notFoundResponse))
+
+			return
+		}
+
+		logger.Errorf("[%s] Error retrieving provenance for suffix [%s]: %s", MigrationPath, suffix, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, prov)
+}
+
+func (h *handler) writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	respBytes, err := h.marshal(v)
+	if err != nil {
+		logger.Errorf("[%s] Error marshalling response: %s", MigrationPath, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	writeResponse(w, status, respBytes)
+}
+
+func writeResponse(w http.ResponseWriter, status int, body []byte) {
+	w.WriteHeader(status)
+
+	if len(body) > 0 {
+		if _, err := w.Write(body); err != nil {
+			logger.Warnf("[%s] Unable to write response: %s", MigrationPath, err)
+
+			return
+		}
+
+		logger.Debugf("[%s] Wrote response: %s", MigrationPath, body)
+	}
+}
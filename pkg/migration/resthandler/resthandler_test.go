@@ -0,0 +1,145 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/internal/testutil/httptestutil"
+	"github.com/trustbloc/orb/pkg/migration"
+)
+
+func TestImport(t *testing.T) {
+	i := &mockImporter{status: &migration.Status{State: migration.StateRunning, TotalTransactions: 1}}
+
+	h := NewImport(i)
+	require.Equal(t, MigrationPath, h.Path())
+	require.Equal(t, http.MethodPost, h.Method())
+	require.NotNil(t, h.Handler())
+
+	reqBytes, err := json.Marshal(&migration.Request{
+		Namespace:    "did:ion",
+		Transactions: []*migration.Transaction{{Number: 1, AnchorString: "1.Qm"}},
+	})
+	require.NoError(t, err)
+
+	t.Run("Success", func(t *testing.T) {
+		code, body := httptestutil.Serve(t, h.handle, http.MethodPost, MigrationPath, reqBytes, nil)
+		require.Equal(t, http.StatusAccepted, code)
+
+		status := &migration.Status{}
+		require.NoError(t, json.Unmarshal(body, status))
+		require.Equal(t, migration.StateRunning, status.State)
+		require.Equal(t, "did:ion", i.req.Namespace)
+	})
+
+	t.Run("Invalid request", func(t *testing.T) {
+		code, _ := httptestutil.Serve(t, h.handle, http.MethodPost, MigrationPath, []byte("{"), nil)
+		require.Equal(t, http.StatusBadRequest, code)
+
+		code, _ = httptestutil.Serve(t, NewImport(&mockImporter{err: errors.New("namespace is required")}).handle,
+			http.MethodPost, MigrationPath, reqBytes, nil)
+		require.Equal(t, http.StatusBadRequest, code)
+	})
+
+	t.Run("Import in progress", func(t *testing.T) {
+		code, _ := httptestutil.Serve(t, NewImport(&mockImporter{err: migration.ErrImportInProgress}).handle,
+			http.MethodPost, MigrationPath, reqBytes, nil)
+		require.Equal(t, http.StatusConflict, code)
+	})
+}
+
+func TestStatus(t *testing.T) {
+	h := NewStatus(&mockImporter{status: &migration.Status{State: migration.StateCompleted}})
+	require.Equal(t, MigrationPath, h.Path())
+	require.Equal(t, http.MethodGet, h.Method())
+	require.NotNil(t, h.Handler())
+
+	t.Run("Success", func(t *testing.T) {
+		code, body := httptestutil.Get(t, h.handle, MigrationPath)
+		require.Equal(t, http.StatusOK, code)
+
+		status := &migration.Status{}
+		require.NoError(t, json.Unmarshal(body, status))
+		require.Equal(t, migration.StateCompleted, status.State)
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		h := NewStatus(&mockImporter{status: &migration.Status{}})
+		h.marshal = func(v interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		code, _ := httptestutil.Get(t, h.handle, MigrationPath)
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+}
+
+func TestProvenance(t *testing.T) {
+	s := &mockStore{prov: &migration.Provenance{
+		Suffix:     "suffix1",
+		Operations: []*migration.ImportedOperation{{SourceNamespace: "did:ion", Hashlink: "hl:anchor1"}},
+	}}
+
+	h := NewProvenance(s)
+	require.Equal(t, MigrationPath+"/dids/{suffix}", h.Path())
+	require.Equal(t, http.MethodGet, h.Method())
+	require.NotNil(t, h.Handler())
+
+	vars := map[string]string{suffixPathVariable: "suffix1"}
+
+	t.Run("Success", func(t *testing.T) {
+		code, body := httptestutil.Serve(t, h.handle, http.MethodGet, MigrationPath+"/dids/suffix1", nil, vars)
+		require.Equal(t, http.StatusOK, code)
+
+		prov := &migration.Provenance{}
+		require.NoError(t, json.Unmarshal(body, prov))
+		require.Equal(t, "suffix1", prov.Suffix)
+		require.Len(t, prov.Operations, 1)
+		require.Equal(t, "hl:anchor1", prov.Operations[0].Hashlink)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		code, _ := httptestutil.Serve(t, NewProvenance(&mockStore{err: migration.ErrNotFound}).handle,
+			http.MethodGet, MigrationPath+"/dids/suffix1", nil, vars)
+		require.Equal(t, http.StatusNotFound, code)
+	})
+
+	t.Run("Store error", func(t *testing.T) {
+		code, _ := httptestutil.Serve(t, NewProvenance(&mockStore{err: errors.New("injected store error")}).handle,
+			http.MethodGet, MigrationPath+"/dids/suffix1", nil, vars)
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+}
+
+type mockImporter struct {
+	status *migration.Status
+	err    error
+	req    *migration.Request
+}
+
+func (m *mockImporter) Import(req *migration.Request) error {
+	m.req = req
+
+	return m.err
+}
+
+func (m *mockImporter) Status() *migration.Status {
+	return m.status
+}
+
+type mockStore struct {
+	prov *migration.Provenance
+	err  error
+}
+
+func (m *mockStore) Get(string) (*migration.Provenance, error) {
+	return m.prov, m.err
+}
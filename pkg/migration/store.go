@@ -0,0 +1,84 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package migration
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+const nameSpace = "migration"
+
+// ErrNotFound is returned when no operations were imported for a DID.
+var ErrNotFound = errors.New("provenance not found")
+
+// Store persists the provenance of imported operations, keyed by DID suffix.
+type Store struct {
+	store storage.Store
+}
+
+// NewStore returns a new provenance store.
+func NewStore(provider storage.Provider) (*Store, error) {
+	store, err := provider.OpenStore(nameSpace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open migration store: %w", err)
+	}
+
+	return &Store{store: store}, nil
+}
+
+// Get returns the provenance of the given DID suffix.
+func (s *Store) Get(suffix string) (*Provenance, error) {
+	provBytes, err := s.store.Get(suffix)
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return nil, ErrNotFound
+		}
+
+		return nil, orberrors.NewTransient(fmt.Errorf("get provenance [%s]: %w", suffix, err))
+	}
+
+	prov := &Provenance{}
+
+	err = json.Unmarshal(provBytes, prov)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal provenance [%s]: %w", suffix, err)
+	}
+
+	return prov, nil
+}
+
+// Add adds the given imported operation to the provenance of the given DID suffix.
+func (s *Store) Add(suffix string, op *ImportedOperation) error {
+	prov, err := s.Get(suffix)
+	if err != nil {
+		if !errors.Is(err, ErrNotFound) {
+			return err
+		}
+
+		prov = &Provenance{Suffix: suffix}
+	}
+
+	prov.Operations = append(prov.Operations, op)
+
+	provBytes, err := json.Marshal(prov)
+	if err != nil {
+		return fmt.Errorf("marshal provenance [%s]: %w", suffix, err)
+	}
+
+	err = s.store.Put(suffix, provBytes)
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("store provenance [%s]: %w", suffix, err))
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package migration
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+func TestStore(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		s, err := NewStore(mem.NewProvider())
+		require.NoError(t, err)
+
+		_, err = s.Get("suffix1")
+		require.True(t, errors.Is(err, ErrNotFound))
+
+		require.NoError(t, s.Add("suffix1", &ImportedOperation{Type: operation.TypeCreate, SourceTransactionNumber: 1}))
+		require.NoError(t, s.Add("suffix1", &ImportedOperation{Type: operation.TypeUpdate, SourceTransactionNumber: 2}))
+
+		prov, err := s.Get("suffix1")
+		require.NoError(t, err)
+		require.Equal(t, "suffix1", prov.Suffix)
+		require.Len(t, prov.Operations, 2)
+		require.Equal(t, operation.TypeCreate, prov.Operations[0].Type)
+		require.Equal(t, operation.TypeUpdate, prov.Operations[1].Type)
+	})
+
+	t.Run("Open store error", func(t *testing.T) {
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(nil, errors.New("injected open error"))
+
+		_, err := NewStore(provider)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected open error")
+	})
+
+	t.Run("Get error", func(t *testing.T) {
+		errExpected := errors.New("injected get error")
+
+		store := &mocks.Store{}
+		store.GetReturns(nil, errExpected)
+
+		s := newStoreWithMock(t, store)
+
+		_, err := s.Get("suffix1")
+		require.True(t, errors.Is(err, errExpected))
+		require.True(t, orberrors.IsTransient(err))
+
+		err = s.Add("suffix1", &ImportedOperation{})
+		require.True(t, errors.Is(err, errExpected))
+
+		store.GetReturns([]byte("{"), nil)
+
+		_, err = s.Get("suffix1")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal provenance")
+	})
+
+	t.Run("Put error", func(t *testing.T) {
+		errExpected := errors.New("injected put error")
+
+		store := &mocks.Store{}
+		store.GetReturns(nil, storage.ErrDataNotFound)
+		store.PutReturns(errExpected)
+
+		err := newStoreWithMock(t, store).Add("suffix1", &ImportedOperation{})
+		require.True(t, errors.Is(err, errExpected))
+		require.True(t, orberrors.IsTransient(err))
+	})
+}
+
+func newStoreWithMock(t *testing.T, store *mocks.Store) *Store {
+	t.Helper()
+
+	provider := &mocks.Provider{}
+	provider.OpenStoreReturns(store, nil)
+
+	s, err := NewStore(provider)
+	require.NoError(t, err)
+
+	return s
+}
@@ -0,0 +1,65 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package migration
+
+import (
+	"sync"
+
+	"github.com/trustbloc/orb/pkg/observer"
+)
+
+// Tracker is notified by the observer when an anchor is processed and, in turn, notifies the importer when
+// the operations that it submitted have been anchored.
+type Tracker struct {
+	mutex    sync.Mutex
+	watchers map[string]chan *observer.ProcessedAnchor
+}
+
+// NewTracker returns a new anchor tracker.
+func NewTracker() *Tracker {
+	return &Tracker{
+		watchers: make(map[string]chan *observer.ProcessedAnchor),
+	}
+}
+
+// AnchorProcessed is invoked by the observer after an anchor has been processed.
+func (t *Tracker) AnchorProcessed(anchor *observer.ProcessedAnchor) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	for _, suffix := range anchor.Suffixes {
+		ch, ok := t.watchers[suffix]
+		if !ok {
+			continue
+		}
+
+		delete(t.watchers, suffix)
+
+		// The channel is buffered so this never blocks.
+		ch <- anchor
+	}
+}
+
+// watch returns a channel that receives the anchor that contains an operation for the given suffix.
+func (t *Tracker) watch(suffix string) <-chan *observer.ProcessedAnchor {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	ch := make(chan *observer.ProcessedAnchor, 1)
+
+	t.watchers[suffix] = ch
+
+	return ch
+}
+
+// unwatch stops watching the given suffix.
+func (t *Tracker) unwatch(suffix string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	delete(t.watchers, suffix)
+}
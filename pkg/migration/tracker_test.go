@@ -0,0 +1,45 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package migration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/observer"
+)
+
+func TestTracker(t *testing.T) {
+	tracker := NewTracker()
+
+	ch1 := tracker.watch("suffix1")
+	ch2 := tracker.watch("suffix2")
+
+	tracker.unwatch("suffix2")
+
+	anchor := &observer.ProcessedAnchor{Hashlink: "hl:anchor1", Suffixes: []string{"suffix1", "suffix2", "suffix3"}}
+
+	tracker.AnchorProcessed(anchor)
+
+	select {
+	case a := <-ch1:
+		require.Equal(t, anchor, a)
+	default:
+		require.FailNow(t, "expecting anchor for suffix1")
+	}
+
+	select {
+	case <-ch2:
+		require.FailNow(t, "not expecting anchor for suffix2")
+	default:
+	}
+
+	// The watcher is removed after the anchor is received so a subsequent anchor doesn't block.
+	tracker.AnchorProcessed(anchor)
+	require.Empty(t, tracker.watchers)
+}
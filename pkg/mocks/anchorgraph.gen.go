@@ -36,6 +36,21 @@ type AnchorGraph struct {
 		result1 []graph.Anchor
 		result2 error
 	}
+	GetNewDidAnchorsStub        func(cid, suffix string, isKnown func(hl string) bool) ([]graph.Anchor, error)
+	getNewDidAnchorsMutex       sync.RWMutex
+	getNewDidAnchorsArgsForCall []struct {
+		cid     string
+		suffix  string
+		isKnown func(hl string) bool
+	}
+	getNewDidAnchorsReturns struct {
+		result1 []graph.Anchor
+		result2 error
+	}
+	getNewDidAnchorsReturnsOnCall map[int]struct {
+		result1 []graph.Anchor
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -143,6 +158,59 @@ func (fake *AnchorGraph) GetDidAnchorsReturnsOnCall(i int, result1 []graph.Ancho
 	}{result1, result2}
 }
 
+func (fake *AnchorGraph) GetNewDidAnchors(cid string, suffix string, isKnown func(hl string) bool) ([]graph.Anchor, error) {
+	fake.getNewDidAnchorsMutex.Lock()
+	ret, specificReturn := fake.getNewDidAnchorsReturnsOnCall[len(fake.getNewDidAnchorsArgsForCall)]
+	fake.getNewDidAnchorsArgsForCall = append(fake.getNewDidAnchorsArgsForCall, struct {
+		cid     string
+		suffix  string
+		isKnown func(hl string) bool
+	}{cid, suffix, isKnown})
+	fake.recordInvocation("GetNewDidAnchors", []interface{}{cid, suffix, isKnown})
+	fake.getNewDidAnchorsMutex.Unlock()
+	if fake.GetNewDidAnchorsStub != nil {
+		return fake.GetNewDidAnchorsStub(cid, suffix, isKnown)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getNewDidAnchorsReturns.result1, fake.getNewDidAnchorsReturns.result2
+}
+
+func (fake *AnchorGraph) GetNewDidAnchorsCallCount() int {
+	fake.getNewDidAnchorsMutex.RLock()
+	defer fake.getNewDidAnchorsMutex.RUnlock()
+	return len(fake.getNewDidAnchorsArgsForCall)
+}
+
+func (fake *AnchorGraph) GetNewDidAnchorsArgsForCall(i int) (string, string, func(hl string) bool) {
+	fake.getNewDidAnchorsMutex.RLock()
+	defer fake.getNewDidAnchorsMutex.RUnlock()
+	return fake.getNewDidAnchorsArgsForCall[i].cid, fake.getNewDidAnchorsArgsForCall[i].suffix, fake.getNewDidAnchorsArgsForCall[i].isKnown
+}
+
+func (fake *AnchorGraph) GetNewDidAnchorsReturns(result1 []graph.Anchor, result2 error) {
+	fake.GetNewDidAnchorsStub = nil
+	fake.getNewDidAnchorsReturns = struct {
+		result1 []graph.Anchor
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *AnchorGraph) GetNewDidAnchorsReturnsOnCall(i int, result1 []graph.Anchor, result2 error) {
+	fake.GetNewDidAnchorsStub = nil
+	if fake.getNewDidAnchorsReturnsOnCall == nil {
+		fake.getNewDidAnchorsReturnsOnCall = make(map[int]struct {
+			result1 []graph.Anchor
+			result2 error
+		})
+	}
+	fake.getNewDidAnchorsReturnsOnCall[i] = struct {
+		result1 []graph.Anchor
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *AnchorGraph) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -150,6 +218,8 @@ func (fake *AnchorGraph) Invocations() map[string][][]interface{} {
 	defer fake.readMutex.RUnlock()
 	fake.getDidAnchorsMutex.RLock()
 	defer fake.getDidAnchorsMutex.RUnlock()
+	fake.getNewDidAnchorsMutex.RLock()
+	defer fake.getNewDidAnchorsMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
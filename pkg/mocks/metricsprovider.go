@@ -100,6 +100,14 @@ func (m *MetricsProvider) ProcessAnchorTime(value time.Duration) {
 func (m *MetricsProvider) ProcessDIDTime(value time.Duration) {
 }
 
+// ProcessAnchorStageTime records the time it takes for the Observer to complete a stage of processing an anchor.
+func (m *MetricsProvider) ProcessAnchorStageTime(stage string, value time.Duration) {
+}
+
+// AnchorLag records the time between the issuance of an anchor and the time that the Observer processed it.
+func (m *MetricsProvider) AnchorLag(value time.Duration) {
+}
+
 // CASWriteTime records the time it takes to write a document to CAS.
 func (m *MetricsProvider) CASWriteTime(value time.Duration) {
 }
@@ -118,6 +126,10 @@ func (m *MetricsProvider) WitnessAnchorCredentialTime(value time.Duration) {
 func (m *MetricsProvider) DocumentCreateUpdateTime(value time.Duration) {
 }
 
+// DocumentIncrementProcessedOperationCount increments the number of processed create/update operations.
+func (m *MetricsProvider) DocumentIncrementProcessedOperationCount() {
+}
+
 // DocumentResolveTime records the time it takes the REST handler to resolve a document.
 func (m *MetricsProvider) DocumentResolveTime(value time.Duration) {
 }
@@ -126,6 +138,10 @@ func (m *MetricsProvider) DocumentResolveTime(value time.Duration) {
 func (m *MetricsProvider) OutboxIncrementActivityCount(activityType string) {
 }
 
+// AnchorIncrementWrittenCount increments the number of anchors that were written.
+func (m *MetricsProvider) AnchorIncrementWrittenCount() {
+}
+
 // CASIncrementCacheHitCount increments the number of CAS cache hits.
 func (m *MetricsProvider) CASIncrementCacheHitCount() {
 }
@@ -138,6 +154,10 @@ func (m *MetricsProvider) CASReadTime(casType string, value time.Duration) {
 func (m *MetricsProvider) BatchSize(float64) {
 }
 
+// QueueSize records the number of operations that are pending in the operation queue.
+func (m *MetricsProvider) QueueSize(float64) {
+}
+
 // WitnessAddProofVctNil records vct witness.
 func (m *MetricsProvider) WitnessAddProofVctNil(value time.Duration) {
 }
@@ -12,7 +12,6 @@ import (
 
 	"github.com/trustbloc/sidetree-core-go/pkg/api/cas"
 	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
-	"github.com/trustbloc/sidetree-core-go/pkg/compression"
 	"github.com/trustbloc/sidetree-core-go/pkg/mocks"
 	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/doccomposer"
 	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/doctransformer/didtransformer"
@@ -21,6 +20,7 @@ import (
 	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/operationparser"
 	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/txnprovider"
 
+	"github.com/trustbloc/orb/pkg/compression"
 	"github.com/trustbloc/orb/pkg/context/common"
 	orboperationparser "github.com/trustbloc/orb/pkg/versions/1_0/operationparser"
 	"github.com/trustbloc/orb/pkg/versions/1_0/operationparser/validators/anchororigin"
@@ -163,7 +163,7 @@ func (m *MockProtocolClientProvider) create() *MockProtocolClient {
 
 	orbParser := orboperationparser.New(parser)
 
-	cp := compression.New(compression.WithDefaultAlgorithms())
+	cp := compression.New()
 	op := txnprovider.NewOperationProvider(latest, parser, m.casClient, cp)
 	th := txnprovider.NewOperationHandler(latest, m.casClient, cp, parser)
 	dc := doccomposer.New()
@@ -28,13 +28,39 @@ type logger interface {
 	Errorf(msg string, args ...interface{})
 }
 
+const (
+	anchorCountKey = "anchorCount"
+	didCountKey    = "didCount"
+)
+
+type didCounter interface {
+	Count() (int, error)
+}
+
 type stats struct {
 	Posts    uint64
 	Comments uint64
+	Anchors  uint64
+	DIDs     uint64
 }
 
 func (s *stats) String() string {
-	return fmt.Sprintf("Posts: %d, Comments: %d", s.Posts, s.Comments)
+	return fmt.Sprintf("Posts: %d, Comments: %d, Anchors: %d, DIDs: %d", s.Posts, s.Comments, s.Anchors, s.DIDs)
+}
+
+type options struct {
+	didCounter didCounter
+}
+
+// Opt sets a NodeInfo service option.
+type Opt func(opts *options)
+
+// WithDIDCounter sets the counter that provides the total number of DIDs managed by this node. If not set then
+// the DID count is not included in the NodeInfo metadata.
+func WithDIDCounter(counter didCounter) Opt {
+	return func(opts *options) {
+		opts.didCounter = counter
+	}
 }
 
 // Service periodically polls various Orb services and produces NodeInfo data.
@@ -49,6 +75,7 @@ type Service struct {
 	mutex                   sync.RWMutex
 	multipleTagQueryCapable bool
 	logger                  logger
+	didCounter              didCounter
 }
 
 // NewService returns a new NodeInfo service.
@@ -56,11 +83,17 @@ type Service struct {
 // feature in the underlying Aries storage provider to update the stats more efficiently.
 // If logger is nil, then a default will be used.
 func NewService(serviceIRI *url.URL, refreshInterval time.Duration, apStore apstore.Store,
-	multipleTagQueryCapable bool, logger logger) *Service {
+	multipleTagQueryCapable bool, logger logger, opts ...Opt) *Service {
 	if logger == nil {
 		logger = log.New("nodeinfo")
 	}
 
+	options := &options{}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
 	r := &Service{
 		apStore:                 apStore,
 		serviceIRI:              serviceIRI,
@@ -69,6 +102,7 @@ func NewService(serviceIRI *url.URL, refreshInterval time.Duration, apStore apst
 		stats:                   &stats{},
 		multipleTagQueryCapable: multipleTagQueryCapable,
 		logger:                  logger,
+		didCounter:              options.didCounter,
 	}
 
 	r.Lifecycle = lifecycle.New("nodeinfo",
@@ -92,6 +126,14 @@ func (r *Service) GetNodeInfo(version Version) *NodeInfo {
 
 	r.mutex.RUnlock()
 
+	metadata := map[string]interface{}{
+		anchorCountKey: stats.Anchors,
+	}
+
+	if r.didCounter != nil {
+		metadata[didCountKey] = stats.DIDs
+	}
+
 	return &NodeInfo{
 		Version:   version,
 		Protocols: []string{activityPubProtocol},
@@ -112,6 +154,7 @@ func (r *Service) GetNodeInfo(version Version) *NodeInfo {
 			LocalPosts:    int(stats.Posts),
 			LocalComments: int(stats.Comments),
 		},
+		Metadata: metadata,
 	}
 }
 
@@ -145,11 +188,12 @@ func (r *Service) refresh() {
 func (r *Service) retrieve() {
 	if !r.multipleTagQueryCapable {
 		r.updateStatsUsingSingleTagQuery()
-
-		return
+	} else {
+		r.updateStatsUsingMultiTagQuery()
 	}
 
-	r.updateStatsUsingMultiTagQuery()
+	r.updateAnchorCount()
+	r.updateDIDCount()
 }
 
 func (r *Service) updateStatsUsingSingleTagQuery() {
@@ -194,13 +238,10 @@ func (r *Service) updateStatsUsingSingleTagQuery() {
 		}
 	}
 
-	r.logger.Debugf("Updated stats: %s", s)
-
-	r.mutex.Lock()
-
-	r.stats = s
-
-	r.mutex.Unlock()
+	r.updateStats(func(current *stats) {
+		current.Posts = s.Posts
+		current.Comments = s.Comments
+	})
 }
 
 func (r *Service) updateStatsUsingMultiTagQuery() {
@@ -249,12 +290,68 @@ func (r *Service) getTotalActivityCount(activityType vocab.Type) (int, error) {
 }
 
 func (r *Service) updateStatsStruct(totalCreateActivities, totalLikeActivities int) {
-	r.mutex.Lock()
+	r.updateStats(func(s *stats) {
+		s.Posts = uint64(totalCreateActivities)
+		s.Comments = uint64(totalLikeActivities)
+	})
+}
+
+func (r *Service) updateAnchorCount() {
+	it, err := r.apStore.QueryReferences(apstore.AnchorEvent,
+		apstore.NewCriteria(apstore.WithObjectIRI(r.serviceIRI)),
+	)
+	if err != nil {
+		r.logger.Errorf("query anchor events: %s", err.Error())
+
+		return
+	}
 
-	r.stats = &stats{
-		Posts:    uint64(totalCreateActivities),
-		Comments: uint64(totalLikeActivities),
+	defer func() {
+		err = it.Close()
+		if err != nil {
+			r.logger.Errorf("failed to close iterator: %s", err.Error())
+		}
+	}()
+
+	totalAnchors, err := it.TotalItems()
+	if err != nil {
+		r.logger.Errorf("get total items from reference iterator after querying anchor events: %s", err.Error())
+
+		return
 	}
 
-	r.mutex.Unlock()
+	r.updateStats(func(s *stats) {
+		s.Anchors = uint64(totalAnchors)
+	})
+}
+
+func (r *Service) updateDIDCount() {
+	if r.didCounter == nil {
+		return
+	}
+
+	totalDIDs, err := r.didCounter.Count()
+	if err != nil {
+		r.logger.Errorf("get DID count: %s", err.Error())
+
+		return
+	}
+
+	r.updateStats(func(s *stats) {
+		s.DIDs = uint64(totalDIDs)
+	})
+}
+
+// updateStats updates a copy of the current stats so that the stats returned to readers are never modified.
+func (r *Service) updateStats(update func(s *stats)) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	s := *r.stats
+
+	update(&s)
+
+	r.stats = &s
+
+	r.logger.Debugf("Updated stats: %s", &s)
 }
@@ -16,6 +16,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/trustbloc/edge-core/pkg/log"
 
+	"github.com/trustbloc/orb/pkg/activitypub/service/mocks"
 	"github.com/trustbloc/orb/pkg/activitypub/store/ariesstore"
 	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
 	"github.com/trustbloc/orb/pkg/activitypub/store/spi"
@@ -44,6 +45,15 @@ func (s *stringLogger) Errorf(msg string, args ...interface{}) {
 	s.log = fmt.Sprintf(msg, args...)
 }
 
+type mockDIDCounter struct {
+	count int
+	err   error
+}
+
+func (m *mockDIDCounter) Count() (int, error) {
+	return m.count, m.err
+}
+
 func TestService(t *testing.T) {
 	log.SetLevel("nodeinfo", log.DEBUG)
 
@@ -90,6 +100,64 @@ func TestUpdateStatsUsingMultiTagQuery(t *testing.T) {
 	})
 }
 
+func TestUpdateAnchorCount(t *testing.T) {
+	serviceIRI := testutil.MustParseURL("https://example.com/services/orb")
+
+	t.Run("Query error", func(t *testing.T) {
+		apStore := &mocks.ActivityStore{}
+		apStore.QueryReferencesReturns(nil, fmt.Errorf("injected query error"))
+
+		s := NewService(serviceIRI, 50*time.Millisecond, apStore, false, nil)
+		require.NotNil(t, s)
+
+		logger := &stringLogger{}
+
+		s.logger = logger
+
+		s.updateAnchorCount()
+		require.Contains(t, logger.log, "query anchor events: injected query error")
+		require.Equal(t, uint64(0), s.GetNodeInfo(V2_1).Metadata[anchorCountKey])
+	})
+}
+
+func TestUpdateDIDCount(t *testing.T) {
+	serviceIRI := testutil.MustParseURL("https://example.com/services/orb")
+
+	t.Run("No DID counter", func(t *testing.T) {
+		s := NewService(serviceIRI, 50*time.Millisecond, memstore.New(""), false, nil)
+		require.NotNil(t, s)
+
+		s.updateDIDCount()
+
+		nodeInfo := s.GetNodeInfo(V2_1)
+		require.NotContains(t, nodeInfo.Metadata, didCountKey)
+		require.Contains(t, nodeInfo.Metadata, anchorCountKey)
+	})
+
+	t.Run("Count error", func(t *testing.T) {
+		counter := &mockDIDCounter{count: 3}
+
+		s := NewService(serviceIRI, 50*time.Millisecond, memstore.New(""), false, nil,
+			WithDIDCounter(counter))
+		require.NotNil(t, s)
+
+		logger := &stringLogger{}
+
+		s.logger = logger
+
+		s.updateDIDCount()
+		require.Equal(t, uint64(3), s.GetNodeInfo(V2_1).Metadata[didCountKey])
+
+		counter.err = fmt.Errorf("injected count error")
+
+		s.updateDIDCount()
+		require.Contains(t, logger.log, "get DID count: injected count error")
+
+		// The previous count should be retained.
+		require.Equal(t, uint64(3), s.GetNodeInfo(V2_1).Metadata[didCountKey])
+	})
+}
+
 func runServiceTest(t *testing.T, apStore spi.Store, multipleTagQueryCapable bool) {
 	t.Helper()
 
@@ -98,6 +166,8 @@ func runServiceTest(t *testing.T, apStore spi.Store, multipleTagQueryCapable boo
 	const (
 		numCreates = 10
 		numLikes   = 5
+		numAnchors = 7
+		numDIDs    = 21
 	)
 
 	for _, a := range append(aptestutil.NewMockCreateActivities(numCreates),
@@ -107,7 +177,13 @@ func runServiceTest(t *testing.T, apStore spi.Store, multipleTagQueryCapable boo
 			spi.WithActivityType(a.Type().Types()[0])))
 	}
 
-	s := NewService(serviceIRI, 50*time.Millisecond, apStore, multipleTagQueryCapable, nil)
+	for i := 0; i < numAnchors; i++ {
+		require.NoError(t, apStore.AddReference(spi.AnchorEvent, serviceIRI,
+			testutil.MustParseURL(fmt.Sprintf("hl:uEiAsiwjaXOYDmOHxmvDl3Mx0TfJ0uCar5YXqumjFJUNIBg%d", i))))
+	}
+
+	s := NewService(serviceIRI, 50*time.Millisecond, apStore, multipleTagQueryCapable, nil,
+		WithDIDCounter(&mockDIDCounter{count: numDIDs}))
 	require.NotNil(t, s)
 
 	s.Start()
@@ -126,7 +202,8 @@ func runServiceTest(t *testing.T, apStore spi.Store, multipleTagQueryCapable boo
 	require.Empty(t, nodeInfo.Services.Outbound)
 	require.Len(t, nodeInfo.Protocols, 1)
 	require.Equal(t, activityPubProtocol, nodeInfo.Protocols[0])
-	require.Empty(t, nodeInfo.Metadata)
+	require.Equal(t, uint64(numAnchors), nodeInfo.Metadata[anchorCountKey])
+	require.Equal(t, uint64(numDIDs), nodeInfo.Metadata[didCountKey])
 	require.Equal(t, 1, nodeInfo.Usage.Users.Total)
 	require.Equal(t, numCreates, nodeInfo.Usage.LocalPosts)
 	require.Equal(t, numLikes, nodeInfo.Usage.LocalComments)
@@ -141,7 +218,8 @@ func runServiceTest(t *testing.T, apStore spi.Store, multipleTagQueryCapable boo
 	require.Empty(t, nodeInfo.Services.Outbound)
 	require.Len(t, nodeInfo.Protocols, 1)
 	require.Equal(t, activityPubProtocol, nodeInfo.Protocols[0])
-	require.Empty(t, nodeInfo.Metadata)
+	require.Equal(t, uint64(numAnchors), nodeInfo.Metadata[anchorCountKey])
+	require.Equal(t, uint64(numDIDs), nodeInfo.Metadata[didCountKey])
 	require.Equal(t, 1, nodeInfo.Usage.Users.Total)
 	require.Equal(t, numCreates, nodeInfo.Usage.LocalPosts)
 	require.Equal(t, numLikes, nodeInfo.Usage.LocalComments)
@@ -0,0 +1,312 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ipfspubsub
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	shell "github.com/ipfs/go-ipfs-api"
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	"github.com/trustbloc/orb/pkg/activitypub/service/spi"
+	store "github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/store/storeutil"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/lifecycle"
+)
+
+var logger = log.New("ipfs-pubsub")
+
+const defaultRetryInterval = 10 * time.Second
+
+type ipfsClient interface {
+	PubSubPublish(topic, data string) error
+	PubSubSubscribe(topic string) (*shell.PubSubSubscription, error)
+}
+
+type subscription interface {
+	Next() (*shell.Message, error)
+	Cancel() error
+}
+
+type activityStore interface {
+	GetActivity(activityID *url.URL) (*vocab.ActivityType, error)
+	AddActivity(activity *vocab.ActivityType) error
+	QueryReferences(refType store.ReferenceType, query *store.Criteria,
+		opts ...store.QueryOpt) (store.ReferenceIterator, error)
+}
+
+// Announcer publishes the 'Create' activities of locally written anchors to an IPFS pubsub topic.
+type Announcer struct {
+	topic  string
+	client ipfsClient
+}
+
+// NewAnnouncer returns a new anchor announcer which publishes to the given IPFS pubsub topic.
+func NewAnnouncer(topic string, client ipfsClient) *Announcer {
+	return &Announcer{
+		topic:  topic,
+		client: client,
+	}
+}
+
+// Announce publishes the given 'Create' activity to the IPFS pubsub topic.
+func (a *Announcer) Announce(create *vocab.ActivityType) error {
+	activityBytes, err := json.Marshal(create)
+	if err != nil {
+		return fmt.Errorf("marshal activity [%s]: %w", create.ID(), err)
+	}
+
+	err = a.client.PubSubPublish(a.topic, string(activityBytes))
+	if err != nil {
+		return fmt.Errorf("publish activity [%s] to IPFS topic [%s]: %w", create.ID(), a.topic, err)
+	}
+
+	logger.Debugf("Published activity [%s] to IPFS topic [%s]", create.ID(), a.topic)
+
+	return nil
+}
+
+// Config holds the configuration parameters for the subscriber.
+type Config struct {
+	// Topic is the IPFS pubsub topic on which anchors are announced.
+	Topic string
+
+	// ServiceIRI is the IRI of the local service.
+	ServiceIRI *url.URL
+
+	// RetryInterval (optional) is the time to wait before subscribing again after the subscription failed.
+	RetryInterval time.Duration
+}
+
+// Subscriber listens on an IPFS pubsub topic for the 'Create' activities of anchors that were written by the
+// services that we're following, so that an anchor may be observed even if the activity isn't delivered to our
+// inbox (for example, because of restrictive HTTP ingress rules). An activity that was already delivered to our
+// inbox (or by an earlier announcement) is ignored and vice versa. Pubsub messages aren't redelivered, so an anchor
+// that fails to be processed is left to the anchor synchronization task.
+type Subscriber struct {
+	*lifecycle.Lifecycle
+	*Config
+
+	subscribe  func(topic string) (subscription, error)
+	store      activityStore
+	getHandler func() spi.InboxHandler
+
+	mutex        sync.Mutex
+	subscription subscription
+	done         chan struct{}
+	stopped      chan struct{}
+}
+
+// NewSubscriber returns a new IPFS pubsub anchor subscriber.
+func NewSubscriber(cfg *Config, client ipfsClient, activityStore activityStore,
+	handlerFactory func() spi.InboxHandler) *Subscriber {
+	if cfg.RetryInterval == 0 {
+		cfg.RetryInterval = defaultRetryInterval
+	}
+
+	s := &Subscriber{
+		Config: cfg,
+		subscribe: func(topic string) (subscription, error) {
+			sub, err := client.PubSubSubscribe(topic)
+			if err != nil {
+				return nil, err
+			}
+
+			return sub, nil
+		},
+		store:      activityStore,
+		getHandler: handlerFactory,
+		done:       make(chan struct{}),
+		stopped:    make(chan struct{}),
+	}
+
+	s.Lifecycle = lifecycle.New("ipfs-pubsub-subscriber",
+		lifecycle.WithStart(s.start),
+		lifecycle.WithStop(s.stop),
+	)
+
+	return s
+}
+
+func (s *Subscriber) start() {
+	logger.Infof("Subscribing to anchor announcements on IPFS topic [%s]", s.Topic)
+
+	go s.listen()
+}
+
+func (s *Subscriber) stop() {
+	close(s.done)
+
+	s.mutex.Lock()
+
+	if s.subscription != nil {
+		if err := s.subscription.Cancel(); err != nil {
+			logger.Debugf("Error cancelling subscription to IPFS topic [%s]: %s", s.Topic, err)
+		}
+	}
+
+	s.mutex.Unlock()
+
+	<-s.stopped
+
+	logger.Infof("Unsubscribed from IPFS topic [%s]", s.Topic)
+}
+
+func (s *Subscriber) listen() {
+	defer close(s.stopped)
+
+	for {
+		sub, err := s.subscribe(s.Topic)
+		if err != nil {
+			logger.Warnf("Error subscribing to IPFS topic [%s]: %s. Retrying in %s", s.Topic, err, s.RetryInterval)
+		} else {
+			s.receive(sub)
+		}
+
+		select {
+		case <-s.done:
+			return
+		case <-time.After(s.RetryInterval):
+		}
+	}
+}
+
+func (s *Subscriber) receive(sub subscription) {
+	if !s.setSubscription(sub) {
+		return
+	}
+
+	defer s.setSubscription(nil)
+
+	for {
+		msg, err := sub.Next()
+		if err != nil {
+			select {
+			case <-s.done:
+			default:
+				logger.Warnf("Error receiving message from IPFS topic [%s]: %s. Retrying in %s",
+					s.Topic, err, s.RetryInterval)
+			}
+
+			return
+		}
+
+		if e := s.handle(msg.Data); e != nil {
+			logger.Warnf("Error handling anchor announcement from IPFS topic [%s]: %s", s.Topic, e)
+		}
+	}
+}
+
+// setSubscription sets the current subscription so that it may be cancelled when the subscriber is stopped.
+// False is returned (and the subscription is cancelled) if the subscriber was already stopped.
+func (s *Subscriber) setSubscription(sub subscription) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	select {
+	case <-s.done:
+		if sub != nil {
+			_ = sub.Cancel() //nolint:errcheck
+		}
+
+		return false
+	default:
+	}
+
+	s.subscription = sub
+
+	return true
+}
+
+func (s *Subscriber) handle(data []byte) error {
+	create := &vocab.ActivityType{}
+
+	if err := json.Unmarshal(data, create); err != nil {
+		return fmt.Errorf("unmarshal activity: %w", err)
+	}
+
+	if !create.Type().Is(vocab.TypeCreate) || create.ID() == nil || create.Actor() == nil {
+		return errors.New("expecting a 'Create' activity with an ID and an actor")
+	}
+
+	if create.Actor().String() == s.ServiceIRI.String() {
+		// Our own announcement.
+		return nil
+	}
+
+	following, err := s.isFollowing(create.Actor())
+	if err != nil {
+		return err
+	}
+
+	if !following {
+		logger.Debugf("Ignoring activity [%s] since we're not following actor [%s]", create.ID(), create.Actor())
+
+		return nil
+	}
+
+	_, err = s.store.GetActivity(create.ID().URL())
+	if err == nil {
+		logger.Debugf("Ignoring activity [%s] since it was already processed", create.ID())
+
+		return nil
+	}
+
+	if !errors.Is(err, store.ErrNotFound) {
+		return fmt.Errorf("get activity [%s]: %w", create.ID(), err)
+	}
+
+	logger.Debugf("Processing activity [%s] from IPFS topic [%s]", create.ID(), s.Topic)
+
+	err = s.getHandler().HandleCreateActivity(create, false)
+	if err != nil {
+		if errors.Is(err, spi.ErrDuplicateAnchorEvent) {
+			logger.Debugf("Ignoring activity [%s] since the anchor was already processed", create.ID())
+
+			return nil
+		}
+
+		return fmt.Errorf("handle activity [%s]: %w", create.ID(), err)
+	}
+
+	// Store the activity so that it isn't processed again when it's delivered to our inbox.
+	err = s.store.AddActivity(create)
+	if err != nil {
+		return fmt.Errorf("store activity [%s]: %w", create.ID(), err)
+	}
+
+	logger.Infof("Processed anchor announcement [%s] from actor [%s]", create.ID(), create.Actor())
+
+	return nil
+}
+
+func (s *Subscriber) isFollowing(actor *url.URL) (bool, error) {
+	it, err := s.store.QueryReferences(store.Following,
+		store.NewCriteria(store.WithObjectIRI(s.ServiceIRI), store.WithReferenceIRI(actor)))
+	if err != nil {
+		return false, fmt.Errorf("query following: %w", err)
+	}
+
+	defer func() {
+		if e := it.Close(); e != nil {
+			logger.Warnf("Error closing iterator: %s", e)
+		}
+	}()
+
+	refs, err := storeutil.ReadReferences(it, 1)
+	if err != nil {
+		return false, fmt.Errorf("read following: %w", err)
+	}
+
+	return len(refs) > 0, nil
+}
@@ -0,0 +1,294 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ipfspubsub
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	shell "github.com/ipfs/go-ipfs-api"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/service/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
+	store "github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+)
+
+const topic = "orb-anchors"
+
+var (
+	service1IRI = testutil.MustParseURL("https://orb.domain1.com/services/orb")
+	service2IRI = testutil.MustParseURL("https://orb.domain2.com/services/orb")
+	service3IRI = testutil.MustParseURL("https://orb.domain3.com/services/orb")
+)
+
+func TestAnnouncer(t *testing.T) {
+	create := newCreateActivity(t, service1IRI, "1")
+
+	t.Run("Success", func(t *testing.T) {
+		client := &mockIPFSClient{}
+
+		require.NoError(t, NewAnnouncer(topic, client).Announce(create))
+		require.Len(t, client.published, 1)
+		require.Equal(t, topic, client.published[0].topic)
+
+		a := &vocab.ActivityType{}
+		require.NoError(t, json.Unmarshal([]byte(client.published[0].data), a))
+		require.Equal(t, create.ID().String(), a.ID().String())
+	})
+
+	t.Run("Publish error", func(t *testing.T) {
+		client := &mockIPFSClient{publishErr: errors.New("injected publish error")}
+
+		err := NewAnnouncer(topic, client).Announce(create)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected publish error")
+	})
+}
+
+func TestSubscriber_Handle(t *testing.T) {
+	apStore := memstore.New("")
+	require.NoError(t, apStore.AddReference(store.Following, service1IRI, service2IRI))
+
+	handler := &mockHandler{}
+
+	s := NewSubscriber(&Config{Topic: topic, ServiceIRI: service1IRI}, &mockIPFSClient{}, apStore,
+		func() spi.InboxHandler { return handler })
+	require.Equal(t, defaultRetryInterval, s.RetryInterval)
+
+	t.Run("Success", func(t *testing.T) {
+		create := newCreateActivity(t, service2IRI, "1")
+
+		require.NoError(t, s.handle(marshal(t, create)))
+		require.Len(t, handler.get(), 1)
+
+		_, err := apStore.GetActivity(create.ID().URL())
+		require.NoError(t, err)
+
+		// The activity was already processed.
+		require.NoError(t, s.handle(marshal(t, create)))
+		require.Len(t, handler.get(), 1)
+	})
+
+	t.Run("Anchor already processed", func(t *testing.T) {
+		create := newCreateActivity(t, service2IRI, "2")
+
+		handler.setErr(spi.ErrDuplicateAnchorEvent)
+		defer handler.setErr(nil)
+
+		require.NoError(t, s.handle(marshal(t, create)))
+
+		_, err := apStore.GetActivity(create.ID().URL())
+		require.ErrorIs(t, err, store.ErrNotFound)
+	})
+
+	t.Run("Not following actor", func(t *testing.T) {
+		require.NoError(t, s.handle(marshal(t, newCreateActivity(t, service3IRI, "3"))))
+		require.Len(t, handler.get(), 1)
+	})
+
+	t.Run("Own announcement", func(t *testing.T) {
+		require.NoError(t, s.handle(marshal(t, newCreateActivity(t, service1IRI, "4"))))
+		require.Len(t, handler.get(), 1)
+	})
+
+	t.Run("Handler error", func(t *testing.T) {
+		handler.setErr(errors.New("injected handler error"))
+		defer handler.setErr(nil)
+
+		err := s.handle(marshal(t, newCreateActivity(t, service2IRI, "5")))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected handler error")
+	})
+
+	t.Run("Invalid message", func(t *testing.T) {
+		err := s.handle([]byte("{"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal activity")
+
+		follow := vocab.NewFollowActivity(vocab.NewObjectProperty(vocab.WithIRI(service1IRI)),
+			vocab.WithID(testutil.MustParseURL(service2IRI.String()+"/activities/6")),
+			vocab.WithActor(service2IRI),
+		)
+
+		err = s.handle(marshal(t, follow))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "expecting a 'Create' activity")
+	})
+}
+
+func TestSubscriber_StartStop(t *testing.T) {
+	apStore := memstore.New("")
+	require.NoError(t, apStore.AddReference(store.Following, service1IRI, service2IRI))
+
+	handler := &mockHandler{}
+
+	s := NewSubscriber(&Config{Topic: topic, ServiceIRI: service1IRI, RetryInterval: 10 * time.Millisecond},
+		&mockIPFSClient{}, apStore, func() spi.InboxHandler { return handler })
+
+	sub := newMockSubscription()
+
+	var (
+		mutex         sync.Mutex
+		subscriptions int
+	)
+
+	s.subscribe = func(string) (subscription, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		subscriptions++
+
+		if subscriptions == 1 {
+			return nil, errors.New("injected subscribe error")
+		}
+
+		return sub, nil
+	}
+
+	s.Start()
+
+	sub.messages <- &shell.Message{Data: []byte("{")}
+	sub.messages <- &shell.Message{Data: marshal(t, newCreateActivity(t, service2IRI, "1"))}
+
+	require.Eventually(t, func() bool { return len(handler.get()) == 1 }, time.Second, 10*time.Millisecond)
+
+	s.Stop()
+
+	require.True(t, sub.isCancelled())
+}
+
+func newCreateActivity(t *testing.T, actor *url.URL, id string) *vocab.ActivityType {
+	t.Helper()
+
+	hl := testutil.MustParseURL("hl:uEiDzUEQi2qRreCTfvp2AKmTaxuqUUZZNhbxe5RTBH59AWw")
+
+	return vocab.NewCreateActivity(
+		vocab.NewObjectProperty(vocab.WithAnchorEvent(vocab.NewAnchorEvent(vocab.WithURL(hl)))),
+		vocab.WithID(testutil.MustParseURL(actor.String()+"/activities/"+id)),
+		vocab.WithActor(actor),
+	)
+}
+
+func marshal(t *testing.T, a *vocab.ActivityType) []byte {
+	t.Helper()
+
+	b, err := json.Marshal(a)
+	require.NoError(t, err)
+
+	return b
+}
+
+type publishedMessage struct {
+	topic string
+	data  string
+}
+
+type mockIPFSClient struct {
+	published  []publishedMessage
+	publishErr error
+}
+
+func (m *mockIPFSClient) PubSubPublish(topic, data string) error {
+	if m.publishErr != nil {
+		return m.publishErr
+	}
+
+	m.published = append(m.published, publishedMessage{topic: topic, data: data})
+
+	return nil
+}
+
+func (m *mockIPFSClient) PubSubSubscribe(string) (*shell.PubSubSubscription, error) {
+	return nil, errors.New("not implemented")
+}
+
+type mockSubscription struct {
+	messages  chan *shell.Message
+	cancel    chan struct{}
+	mutex     sync.Mutex
+	cancelled bool
+}
+
+func newMockSubscription() *mockSubscription {
+	return &mockSubscription{
+		messages: make(chan *shell.Message, 10),
+		cancel:   make(chan struct{}),
+	}
+}
+
+func (m *mockSubscription) Next() (*shell.Message, error) {
+	select {
+	case msg := <-m.messages:
+		return msg, nil
+	case <-m.cancel:
+		return nil, errors.New("subscription cancelled")
+	}
+}
+
+func (m *mockSubscription) Cancel() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if !m.cancelled {
+		m.cancelled = true
+
+		close(m.cancel)
+	}
+
+	return nil
+}
+
+func (m *mockSubscription) isCancelled() bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.cancelled
+}
+
+type mockHandler struct {
+	mutex      sync.Mutex
+	activities []*vocab.ActivityType
+	err        error
+}
+
+func (m *mockHandler) HandleCreateActivity(a *vocab.ActivityType, _ bool) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.err != nil {
+		return m.err
+	}
+
+	m.activities = append(m.activities, a)
+
+	return nil
+}
+
+func (m *mockHandler) HandleAnnounceActivity(a *vocab.ActivityType) error {
+	return m.HandleCreateActivity(a, false)
+}
+
+func (m *mockHandler) get() []*vocab.ActivityType {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.activities
+}
+
+func (m *mockHandler) setErr(err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.err = err
+}
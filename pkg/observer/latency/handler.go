@@ -0,0 +1,114 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package latency
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+)
+
+const (
+	// Path is the path of the anchor latency endpoint.
+	Path = "/anchor-latency"
+
+	limitParam = "limit"
+
+	internalServerErrorResponse = "Internal Server Error.\n"
+)
+
+type entryRetriever interface {
+	Get(limit int) []*Entry
+	MaxEntries() int
+}
+
+// Handler implements the /anchor-latency REST endpoint which returns the slowest anchors that were recently
+// processed by the observer along with the time spent in each stage of processing. The optional 'limit'
+// query parameter specifies the maximum number of anchors to return.
+type Handler struct {
+	retriever entryRetriever
+	marshal   func(v interface{}) ([]byte, error)
+}
+
+// NewHandler returns the /anchor-latency REST handler.
+func NewHandler(retriever entryRetriever) *Handler {
+	return &Handler{
+		retriever: retriever,
+		marshal:   json.Marshal,
+	}
+}
+
+// Path returns the HTTP REST endpoint for the anchor latency handler.
+func (h *Handler) Path() string {
+	return Path
+}
+
+// Method returns the HTTP REST method for the anchor latency handler.
+func (h *Handler) Method() string {
+	return http.MethodGet
+}
+
+// Handler returns the HTTP REST handle for the anchor latency handler.
+func (h *Handler) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Handler) handle(w http.ResponseWriter, req *http.Request) {
+	limit, err := h.getLimit(req)
+	if err != nil {
+		h.writeResponse(w, http.StatusBadRequest, []byte(err.Error()))
+
+		return
+	}
+
+	respBytes, err := h.marshal(h.retriever.Get(limit))
+	if err != nil {
+		logger.Errorf("[%s] Error marshalling anchor latencies: %s", Path, err)
+
+		h.writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	h.writeResponse(w, http.StatusOK, respBytes)
+}
+
+func (h *Handler) writeResponse(w http.ResponseWriter, status int, body []byte) {
+	w.WriteHeader(status)
+
+	if len(body) > 0 {
+		if _, err := w.Write(body); err != nil {
+			logger.Warnf("[%s] Unable to write response: %s", Path, err)
+
+			return
+		}
+
+		logger.Debugf("[%s] Wrote response: %s", Path, body)
+	}
+}
+
+func (h *Handler) getLimit(req *http.Request) (int, error) {
+	value := req.URL.Query().Get(limitParam)
+	if value == "" {
+		return 0, nil
+	}
+
+	maxEntries := h.retriever.MaxEntries()
+
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 1 || limit > maxEntries {
+		return 0, fmt.Errorf("invalid value for parameter '%s': %s. It must be between 1 and %d",
+			limitParam, value, maxEntries)
+	}
+
+	return limit, nil
+}
@@ -0,0 +1,68 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package latency
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/internal/testutil/httptestutil"
+)
+
+func TestHandler(t *testing.T) {
+	tracker := New(10, time.Hour)
+
+	tracker.AnchorTimings(newTimings(hl1, time.Now(), 100*time.Millisecond))
+	tracker.AnchorTimings(newTimings(hl2, time.Now(), 200*time.Millisecond))
+
+	h := NewHandler(tracker)
+	require.Equal(t, Path, h.Path())
+	require.Equal(t, http.MethodGet, h.Method())
+	require.NotNil(t, h.Handler())
+
+	t.Run("All entries", func(t *testing.T) {
+		code, body := httptestutil.Get(t, h.handle, Path)
+		require.Equal(t, http.StatusOK, code)
+
+		var entries []*Entry
+		require.NoError(t, json.Unmarshal(body, &entries))
+		require.Len(t, entries, 2)
+		require.Equal(t, hl2, entries[0].Hashlink)
+		require.Len(t, entries[0].Stages, 2)
+	})
+
+	t.Run("Limit", func(t *testing.T) {
+		code, body := httptestutil.Get(t, h.handle, Path+"?limit=1")
+		require.Equal(t, http.StatusOK, code)
+
+		var entries []*Entry
+		require.NoError(t, json.Unmarshal(body, &entries))
+		require.Len(t, entries, 1)
+		require.Equal(t, hl2, entries[0].Hashlink)
+	})
+
+	t.Run("Invalid limit", func(t *testing.T) {
+		for _, limit := range []string{"xxx", "0", "11"} {
+			code, body := httptestutil.Get(t, h.handle, Path+"?limit="+limit)
+			require.Equal(t, http.StatusBadRequest, code)
+			require.Contains(t, string(body), "invalid value for parameter 'limit'")
+		}
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		h := NewHandler(tracker)
+		h.marshal = func(v interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		code, _ := httptestutil.Get(t, h.handle, Path)
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+}
@@ -0,0 +1,156 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package latency
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	"github.com/trustbloc/orb/pkg/observer"
+)
+
+var logger = log.New("anchor-latency")
+
+// Stage contains the time spent in a stage of processing an anchor.
+type Stage struct {
+	Stage      string  `json:"stage"`
+	DurationMS float64 `json:"durationMs"`
+}
+
+// Entry contains the processing times of an anchor.
+type Entry struct {
+	Hashlink string    `json:"hashlink"`
+	Time     time.Time `json:"time"`
+	TotalMS  float64   `json:"totalMs"`
+	Stages   []*Stage  `json:"stages"`
+	Error    string    `json:"error,omitempty"`
+
+	total time.Duration
+}
+
+// Tracker is notified by the observer of the time spent in each stage of processing an anchor
+// and keeps the slowest anchors that were processed within a recent period of time (the window).
+type Tracker struct {
+	maxEntries int
+	window     time.Duration
+	now        func() time.Time
+
+	mutex   sync.Mutex
+	entries []*Entry
+}
+
+// New returns a new tracker which keeps at most maxEntries of the slowest anchors that were processed within
+// the given window.
+func New(maxEntries int, window time.Duration) *Tracker {
+	return &Tracker{
+		maxEntries: maxEntries,
+		window:     window,
+		now:        time.Now,
+	}
+}
+
+// AnchorTimings is invoked by the observer after an anchor is processed.
+func (t *Tracker) AnchorTimings(timings *observer.AnchorTimings) {
+	entry := newEntry(timings)
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.prune()
+
+	if len(t.entries) < t.maxEntries {
+		t.entries = append(t.entries, entry)
+
+		return
+	}
+
+	// Replace the fastest entry if the new entry is slower.
+	fastest := 0
+
+	for i, e := range t.entries {
+		if e.total < t.entries[fastest].total {
+			fastest = i
+		}
+	}
+
+	if entry.total > t.entries[fastest].total {
+		logger.Debugf("Anchor [%s] replaces anchor [%s] in the list of slowest anchors",
+			entry.Hashlink, t.entries[fastest].Hashlink)
+
+		t.entries[fastest] = entry
+	}
+}
+
+// Get returns the slowest anchors that were processed within the window, sorted by total processing
+// time (slowest first). If limit is greater than zero then at most limit entries are returned.
+func (t *Tracker) Get(limit int) []*Entry {
+	t.mutex.Lock()
+
+	t.prune()
+
+	entries := make([]*Entry, len(t.entries))
+	copy(entries, t.entries)
+
+	t.mutex.Unlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].total > entries[j].total
+	})
+
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+
+	return entries
+}
+
+// MaxEntries returns the maximum number of entries that are kept by the tracker.
+func (t *Tracker) MaxEntries() int {
+	return t.maxEntries
+}
+
+// prune removes the entries that are older than the window. The mutex must be locked by the caller.
+func (t *Tracker) prune() {
+	cutoff := t.now().Add(-t.window)
+
+	entries := t.entries[:0]
+
+	for _, e := range t.entries {
+		if e.Time.After(cutoff) {
+			entries = append(entries, e)
+		}
+	}
+
+	t.entries = entries
+}
+
+func newEntry(timings *observer.AnchorTimings) *Entry {
+	stages := make([]*Stage, len(timings.Stages))
+
+	for i, s := range timings.Stages {
+		stages[i] = &Stage{
+			Stage:      s.Stage,
+			DurationMS: toMillis(s.Duration),
+		}
+	}
+
+	return &Entry{
+		Hashlink: timings.Hashlink,
+		Time:     timings.StartTime,
+		TotalMS:  toMillis(timings.Total),
+		Stages:   stages,
+		Error:    timings.Error,
+		total:    timings.Total,
+	}
+}
+
+func toMillis(d time.Duration) float64 {
+	return float64(d.Microseconds()) / float64(time.Millisecond/time.Microsecond)
+}
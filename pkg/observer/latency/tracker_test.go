@@ -0,0 +1,100 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package latency
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/observer"
+)
+
+const (
+	hl1 = "hl:uEiAsiwjaXOYDmOHxmvDl3Mx0TfJ0uCar5YXqumjFJUNIBg"
+	hl2 = "hl:uEiAn3Y7USoP_lNVX-f0EEu1ajLymnqBJItiMARhKBzAKWg"
+	hl3 = "hl:uEiBy5tCSsvB2QNqo5Dd3zg1Sjdg3_tSgjnnsNUBpdcDmvg"
+	hl4 = "hl:uEiDSRkm3hAkHuy3fGhpDBeJ5_c3-TLs0MD6CkGhcY7zZbQ"
+)
+
+func TestTracker(t *testing.T) {
+	t.Run("Slowest anchors", func(t *testing.T) {
+		tracker := New(3, time.Hour)
+		require.Equal(t, 3, tracker.MaxEntries())
+		require.Empty(t, tracker.Get(0))
+
+		tracker.AnchorTimings(newTimings(hl1, time.Now(), 200*time.Millisecond))
+		tracker.AnchorTimings(newTimings(hl2, time.Now(), 100*time.Millisecond))
+		tracker.AnchorTimings(newTimings(hl3, time.Now(), 300*time.Millisecond))
+
+		entries := tracker.Get(0)
+		require.Len(t, entries, 3)
+		require.Equal(t, hl3, entries[0].Hashlink)
+		require.Equal(t, hl1, entries[1].Hashlink)
+		require.Equal(t, hl2, entries[2].Hashlink)
+
+		require.Equal(t, float64(300), entries[0].TotalMS)
+		require.Len(t, entries[0].Stages, 2)
+		require.Equal(t, observer.StageCASFetch, entries[0].Stages[0].Stage)
+		require.Equal(t, float64(100), entries[0].Stages[0].DurationMS)
+
+		// Faster than all of the others - should be ignored.
+		tracker.AnchorTimings(newTimings(hl4, time.Now(), 50*time.Millisecond))
+
+		entries = tracker.Get(0)
+		require.Len(t, entries, 3)
+		require.Equal(t, hl2, entries[2].Hashlink)
+
+		// Slower than the fastest - should replace the fastest.
+		tracker.AnchorTimings(newTimings(hl4, time.Now(), 150*time.Millisecond))
+
+		entries = tracker.Get(0)
+		require.Len(t, entries, 3)
+		require.Equal(t, hl4, entries[2].Hashlink)
+
+		entries = tracker.Get(1)
+		require.Len(t, entries, 1)
+		require.Equal(t, hl3, entries[0].Hashlink)
+	})
+
+	t.Run("Expired entries", func(t *testing.T) {
+		tracker := New(3, time.Hour)
+
+		tracker.AnchorTimings(newTimings(hl1, time.Now().Add(-2*time.Hour), time.Second))
+		tracker.AnchorTimings(newTimings(hl2, time.Now(), 100*time.Millisecond))
+
+		entries := tracker.Get(0)
+		require.Len(t, entries, 1)
+		require.Equal(t, hl2, entries[0].Hashlink)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		tracker := New(3, time.Hour)
+
+		timings := newTimings(hl1, time.Now(), 100*time.Millisecond)
+		timings.Error = "injected error"
+
+		tracker.AnchorTimings(timings)
+
+		entries := tracker.Get(0)
+		require.Len(t, entries, 1)
+		require.Equal(t, "injected error", entries[0].Error)
+	})
+}
+
+func newTimings(hl string, startTime time.Time, total time.Duration) *observer.AnchorTimings {
+	return &observer.AnchorTimings{
+		Hashlink:  hl,
+		StartTime: startTime,
+		Total:     total,
+		Stages: []*observer.StageTime{
+			{Stage: observer.StageCASFetch, Duration: total / 3},
+			{Stage: observer.StageStoreOperations, Duration: total * 2 / 3},
+		},
+	}
+}
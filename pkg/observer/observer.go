@@ -42,6 +42,7 @@ const defaultSubscriberPoolSize = 5
 type AnchorGraph interface {
 	Read(hl string) (*vocab.AnchorEventType, error)
 	GetDidAnchors(cid, suffix string) ([]graph.Anchor, error)
+	GetNewDidAnchors(cid, suffix string, isKnown func(hl string) bool) ([]graph.Anchor, error)
 }
 
 // OperationStore interface to access operation store.
@@ -76,6 +77,8 @@ type pubSub interface {
 type metricsProvider interface {
 	ProcessAnchorTime(value time.Duration)
 	ProcessDIDTime(value time.Duration)
+	ProcessAnchorStageTime(stage string, value time.Duration)
+	AnchorLag(value time.Duration)
 }
 
 // Outbox defines an ActivityPub outbox.
@@ -99,11 +102,41 @@ type anchorLinkStore interface {
 	PutLinks(links []*url.URL) error
 }
 
+type processedAnchorStore interface {
+	IsProcessed(canonicalRef string) (bool, error)
+	PutProcessed(canonicalRef, hashlink, source string) error
+	AddSource(canonicalRef, source string) error
+}
+
 type outboxProvider func() Outbox
 
+// ProcessedAnchor contains information about an anchor that was successfully processed.
+type ProcessedAnchor struct {
+	Hashlink           string
+	AttributedTo       string
+	AnchorOrigin       string
+	Namespace          string
+	CanonicalReference string
+	CoreIndex          string
+	Published          time.Time
+	OperationCount     uint64
+	Suffixes           []string
+	// Witnesses contains the (unique) domains of the proofs on the anchor credential.
+	Witnesses []string
+}
+
+// AnchorProcessedListener is notified after an anchor has been successfully processed.
+type AnchorProcessedListener interface {
+	AnchorProcessed(anchor *ProcessedAnchor)
+}
+
 type options struct {
 	discoveryDomain    string
 	subscriberPoolSize uint
+	shardRouter        ShardRouter
+	listeners          []AnchorProcessedListener
+	timingsListeners   []AnchorTimingsListener
+	processedAnchors   processedAnchorStore
 }
 
 // Option is an option for observer.
@@ -123,6 +156,39 @@ func WithSubscriberPoolSize(value uint) Option {
 	}
 }
 
+// WithShardRouter sets the shard router which allows the processing of anchors to be distributed among
+// multiple observer instances according to the anchor's origin.
+func WithShardRouter(router ShardRouter) Option {
+	return func(opts *options) {
+		opts.shardRouter = router
+	}
+}
+
+// WithAnchorProcessedListener adds a listener that is notified after an anchor has been processed.
+func WithAnchorProcessedListener(listener AnchorProcessedListener) Option {
+	return func(opts *options) {
+		opts.listeners = append(opts.listeners, listener)
+	}
+}
+
+// WithAnchorTimingsListener adds a listener that is notified of the time spent in each stage
+// of processing an anchor.
+func WithAnchorTimingsListener(listener AnchorTimingsListener) Option {
+	return func(opts *options) {
+		opts.timingsListeners = append(opts.timingsListeners, listener)
+	}
+}
+
+// WithProcessedAnchorStore sets the store that keeps track of the anchors that were processed. If set then an
+// anchor that was already processed (for example, an anchor that is received from the origin and again from a
+// witness announcement or a resync) isn't read from CAS and verified again. The source of the anchor is just
+// added to the anchor's provenance.
+func WithProcessedAnchorStore(store processedAnchorStore) Option {
+	return func(opts *options) {
+		opts.processedAnchors = store
+	}
+}
+
 // Providers contains all of the providers required by the TxnProcessor.
 type Providers struct {
 	ProtocolClientProvider protocol.ClientProvider
@@ -142,9 +208,12 @@ type Providers struct {
 type Observer struct {
 	*Providers
 
-	serviceIRI      *url.URL
-	pubSub          *PubSub
-	discoveryDomain string
+	serviceIRI       *url.URL
+	pubSub           *PubSub
+	discoveryDomain  string
+	listeners        []AnchorProcessedListener
+	timingsListeners []AnchorTimingsListener
+	processedAnchors processedAnchorStore
 }
 
 // New returns a new observer.
@@ -156,9 +225,12 @@ func New(serviceIRI *url.URL, providers *Providers, opts ...Option) (*Observer,
 	}
 
 	o := &Observer{
-		serviceIRI:      serviceIRI,
-		Providers:       providers,
-		discoveryDomain: optns.discoveryDomain,
+		serviceIRI:       serviceIRI,
+		Providers:        providers,
+		discoveryDomain:  optns.discoveryDomain,
+		listeners:        optns.listeners,
+		timingsListeners: optns.timingsListeners,
+		processedAnchors: optns.processedAnchors,
 	}
 
 	subscriberPoolSize := optns.subscriberPoolSize
@@ -166,7 +238,13 @@ func New(serviceIRI *url.URL, providers *Providers, opts ...Option) (*Observer,
 		subscriberPoolSize = defaultSubscriberPoolSize
 	}
 
-	ps, err := NewPubSub(providers.PubSub, o.handleAnchor, o.processDID, subscriberPoolSize)
+	var pubSubOpts []PubSubOpt
+
+	if optns.shardRouter != nil {
+		pubSubOpts = append(pubSubOpts, WithPubSubShardRouter(optns.shardRouter))
+	}
+
+	ps, err := NewPubSub(providers.PubSub, o.handleAnchor, o.processDID, subscriberPoolSize, pubSubOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -196,10 +274,25 @@ func (o *Observer) handleAnchor(anchor *anchorinfo.AnchorInfo) error {
 		anchor.Hashlink, anchor.Hashlink, anchor.AttributedTo)
 
 	startTime := time.Now()
+	timer := newStageTimer(o.Metrics)
 
-	defer func() {
-		o.Metrics.ProcessAnchorTime(time.Since(startTime))
-	}()
+	err := o.readAndProcessAnchor(anchor, timer)
+
+	elapsed := time.Since(startTime)
+
+	o.Metrics.ProcessAnchorTime(elapsed)
+
+	o.notifyAnchorTimings(anchor.Hashlink, startTime, elapsed, timer, err)
+
+	return err
+}
+
+func (o *Observer) readAndProcessAnchor(anchor *anchorinfo.AnchorInfo, timer *stageTimer) error {
+	if o.isAlreadyProcessed(anchor) {
+		return nil
+	}
+
+	startTime := time.Now()
 
 	anchorEvent, err := o.AnchorGraph.Read(anchor.Hashlink)
 	if err != nil {
@@ -208,17 +301,107 @@ func (o *Observer) handleAnchor(anchor *anchorinfo.AnchorInfo) error {
 		return err
 	}
 
+	timer.record(StageCASFetch, startTime)
+
 	logger.Debugf("successfully read anchor event[%s] from anchor graph", anchor.Hashlink)
 
-	if err := o.processAnchor(anchor, anchorEvent); err != nil {
+	if err := o.processAnchor(anchor, anchorEvent, timer); err != nil {
 		logger.Warnf(err.Error())
 
 		return err
 	}
 
+	o.markProcessed(anchor)
+
 	return nil
 }
 
+// isAlreadyProcessed returns true if the given anchor was already processed, in which case the source of
+// the anchor is added to the anchor's provenance. Errors are logged and false is returned so that the
+// anchor is processed as usual.
+func (o *Observer) isAlreadyProcessed(anchor *anchorinfo.AnchorInfo) bool {
+	if o.processedAnchors == nil {
+		return false
+	}
+
+	canonicalID, err := hashlink.GetResourceHashFromHashLink(anchor.Hashlink)
+	if err != nil {
+		return false
+	}
+
+	processed, err := o.processedAnchors.IsProcessed(canonicalID)
+	if err != nil {
+		logger.Warnf("Error checking whether anchor [%s] was processed: %s", anchor.Hashlink, err)
+
+		return false
+	}
+
+	if !processed {
+		return false
+	}
+
+	source := o.sourceOf(anchor)
+
+	logger.Infof("Anchor [%s] was already processed. Skipping anchor from source [%s].", anchor.Hashlink, source)
+
+	err = o.processedAnchors.AddSource(canonicalID, source)
+	if err != nil {
+		// Not fatal. The anchor was processed, so only the provenance is incomplete.
+		logger.Warnf("Error adding source [%s] to processed anchor [%s]: %s", source, anchor.Hashlink, err)
+	}
+
+	return true
+}
+
+// isProcessed returns true if the anchor with the given hashlink was already processed. Errors are logged
+// and false is returned.
+func (o *Observer) isProcessed(hl string) bool {
+	if o.processedAnchors == nil {
+		return false
+	}
+
+	canonicalID, err := hashlink.GetResourceHashFromHashLink(hl)
+	if err != nil {
+		return false
+	}
+
+	processed, err := o.processedAnchors.IsProcessed(canonicalID)
+	if err != nil {
+		logger.Warnf("Error checking whether anchor [%s] was processed: %s", hl, err)
+
+		return false
+	}
+
+	return processed
+}
+
+func (o *Observer) markProcessed(anchor *anchorinfo.AnchorInfo) {
+	if o.processedAnchors == nil {
+		return
+	}
+
+	canonicalID, err := hashlink.GetResourceHashFromHashLink(anchor.Hashlink)
+	if err != nil {
+		return
+	}
+
+	err = o.processedAnchors.PutProcessed(canonicalID, anchor.Hashlink, o.sourceOf(anchor))
+	if err != nil {
+		// Not fatal. If the anchor is received again then it will just be processed again.
+		logger.Warnf("Error marking anchor [%s] as processed: %s", anchor.Hashlink, err)
+	}
+}
+
+// sourceOf returns the actor from which the anchor was received. Anchors that aren't attributed to an
+// actor were created by this service.
+func (o *Observer) sourceOf(anchor *anchorinfo.AnchorInfo) string {
+	if anchor.AttributedTo != "" || o.serviceIRI == nil {
+		return anchor.AttributedTo
+	}
+
+	return o.serviceIRI.String()
+}
+
 func (o *Observer) processDID(did string) error {
 	logger.Debugf("processing out-of-system did[%s]", did)
 
@@ -235,7 +418,9 @@ func (o *Observer) processDID(did string) error {
 		return err
 	}
 
-	anchors, err := o.AnchorGraph.GetDidAnchors(cidWithHint, suffix)
+	// The traversal stops at an anchor that was already processed since its operations (and those of its
+	// ancestors) are already in the operation store.
+	anchors, err := o.AnchorGraph.GetNewDidAnchors(cidWithHint, suffix, o.isProcessed)
 	if err != nil {
 		logger.Warnf("process did failed for did[%s]: %s", did, err.Error())
 
@@ -249,7 +434,7 @@ func (o *Observer) processDID(did string) error {
 
 		if err := o.processAnchor(
 			&anchorinfo.AnchorInfo{Hashlink: anchor.CID},
-			anchor.Info, suffix); err != nil {
+			anchor.Info, newStageTimer(o.Metrics), suffix); err != nil {
 			if errors.IsTransient(err) {
 				// Return an error so that the message is redelivered and retried.
 				return fmt.Errorf("process out-of-system anchor [%s]: %w", anchor.CID, err)
@@ -264,6 +449,22 @@ func (o *Observer) processDID(did string) error {
 	return nil
 }
 
+// validateCredentialSubject validates the credential subject of the anchor credential embedded in the given
+// anchor event against the Orb anchor credential subject schema.
+func validateCredentialSubject(anchorEvent *vocab.AnchorEventType) error {
+	witnessDoc, err := util.GetWitnessDoc(anchorEvent)
+	if err != nil {
+		return fmt.Errorf("get witness from anchor event: %w", err)
+	}
+
+	vcBytes, err := json.Marshal(witnessDoc)
+	if err != nil {
+		return fmt.Errorf("marshal witness: %w", err)
+	}
+
+	return subject.ValidateCredentialSubject(vcBytes)
+}
+
 func getDidParts(did string) (cid, suffix string, err error) {
 	const delimiter = ":"
 
@@ -277,14 +478,18 @@ func getDidParts(did string) (cid, suffix string, err error) {
 
 //nolint:funlen
 func (o *Observer) processAnchor(anchor *anchorinfo.AnchorInfo,
-	anchorEvent *vocab.AnchorEventType, suffixes ...string) error {
+	anchorEvent *vocab.AnchorEventType, timer *stageTimer, suffixes ...string) error {
 	logger.Debugf("processing anchor[%s] from [%s], suffixes: %s", anchor.Hashlink, anchor.AttributedTo, suffixes)
 
+	startTime := time.Now()
+
 	anchorPayload, err := anchorevent.GetPayloadFromAnchorEvent(anchorEvent)
 	if err != nil {
 		return fmt.Errorf("failed to extract anchor payload from anchor[%s]: %w", anchor.Hashlink, err)
 	}
 
+	timer.record(StagePayloadParse, startTime)
+
 	pc, err := o.ProtocolClientProvider.ForNamespace(anchorPayload.Namespace)
 	if err != nil {
 		return fmt.Errorf("failed to get protocol client for namespace [%s]: %w", anchorPayload.Namespace, err)
@@ -309,6 +514,13 @@ func (o *Observer) processAnchor(anchor *anchorinfo.AnchorInfo,
 		equivalentRefs = append(equivalentRefs, "https:"+o.discoveryDomain+":"+canonicalID)
 	}
 
+	startTime = time.Now()
+
+	err = validateCredentialSubject(anchorEvent)
+	if err != nil {
+		return fmt.Errorf("anchor [%s]: %w", anchor.Hashlink, err)
+	}
+
 	vc, err := util.VerifiableCredentialFromAnchorEvent(anchorEvent,
 		verifiable.WithPublicKeyFetcher(o.Pkf),
 		verifiable.WithJSONLDDocumentLoader(o.DocLoader),
@@ -317,6 +529,8 @@ func (o *Observer) processAnchor(anchor *anchorinfo.AnchorInfo,
 		return fmt.Errorf("get verifiable credential from anchor event: %w", err)
 	}
 
+	timer.record(StageCredentialVerification, startTime)
+
 	sidetreeTxn := txnapi.SidetreeTxn{
 		TransactionTime:      uint64(vc.Issued.Unix()),
 		AnchorString:         ad.GetAnchorString(),
@@ -328,7 +542,7 @@ func (o *Observer) processAnchor(anchor *anchorinfo.AnchorInfo,
 
 	logger.Debugf("processing anchor[%s], core index[%s]", anchor.Hashlink, anchorPayload.CoreIndex)
 
-	err = v.TransactionProcessor().Process(sidetreeTxn, suffixes...)
+	err = processTxn(v.TransactionProcessor(), sidetreeTxn, timer, suffixes...)
 	if err != nil {
 		return fmt.Errorf("failed to process anchor[%s] core index[%s]: %w",
 			anchor.Hashlink, anchorPayload.CoreIndex, err)
@@ -337,14 +551,35 @@ func (o *Observer) processAnchor(anchor *anchorinfo.AnchorInfo,
 	// update global did/anchor references
 	acSuffixes, areNewSuffixes := getSuffixes(anchorPayload.PreviousAnchors)
 
+	startTime = time.Now()
+
 	err = o.DidAnchors.PutBulk(acSuffixes, areNewSuffixes, anchor.Hashlink)
 	if err != nil {
 		return fmt.Errorf("failed updating did anchor references for anchor credential[%s]: %w", anchor.Hashlink, err)
 	}
 
+	timer.record(StageDIDAnchorStore, startTime)
+
 	logger.Infof("Successfully processed %d DIDs in anchor[%s], core index[%s]",
 		anchorPayload.OperationCount, anchor.Hashlink, anchorPayload.CoreIndex)
 
+	o.Metrics.AnchorLag(time.Since(vc.Issued.Time))
+
+	o.notifyAnchorProcessed(&ProcessedAnchor{
+		Hashlink:           anchor.Hashlink,
+		AttributedTo:       anchor.AttributedTo,
+		AnchorOrigin:       anchorPayload.AnchorOrigin,
+		Namespace:          anchorPayload.Namespace,
+		CanonicalReference: canonicalID,
+		CoreIndex:          anchorPayload.CoreIndex,
+		Published:          vc.Issued.Time,
+		OperationCount:     anchorPayload.OperationCount,
+		Suffixes:           acSuffixes,
+		Witnesses:          getProofDomains(vc.Proofs),
+	})
+
+	startTime = time.Now()
+
 	// Post a 'Like' activity to the originator of the anchor credential.
 	err = o.saveAnchorLinkAndPostLikeActivity(anchor)
 	if err != nil {
@@ -353,9 +588,51 @@ func (o *Observer) processAnchor(anchor *anchorinfo.AnchorInfo,
 		logger.Warnf("A 'Like' activity could not be posted to the outbox: %s", err)
 	}
 
+	timer.record(StageAnchorLinkStore, startTime)
+
 	return nil
 }
 
+// processTxn processes the given Sidetree transaction. If the transaction processor supports it then the
+// time spent parsing and storing the operations is recorded.
+func processTxn(tp protocol.TxnProcessor, sidetreeTxn txnapi.SidetreeTxn, //nolint:gocritic
+	timer *stageTimer, suffixes ...string) error {
+	stp, ok := tp.(stagedTxnProcessor)
+	if !ok {
+		return tp.Process(sidetreeTxn, suffixes...)
+	}
+
+	return stp.ProcessWithStages(sidetreeTxn, timer.observe, suffixes...)
+}
+
+func (o *Observer) notifyAnchorProcessed(anchor *ProcessedAnchor) {
+	for _, listener := range o.listeners {
+		listener.AnchorProcessed(anchor)
+	}
+}
+
+func (o *Observer) notifyAnchorTimings(hl string, startTime time.Time, elapsed time.Duration,
+	timer *stageTimer, err error) {
+	if len(o.timingsListeners) == 0 {
+		return
+	}
+
+	timings := &AnchorTimings{
+		Hashlink:  hl,
+		StartTime: startTime,
+		Total:     elapsed,
+		Stages:    timer.stages,
+	}
+
+	if err != nil {
+		timings.Error = err.Error()
+	}
+
+	for _, listener := range o.timingsListeners {
+		listener.AnchorTimings(timings)
+	}
+}
+
 func (o *Observer) saveAnchorLinkAndPostLikeActivity(anchor *anchorinfo.AnchorInfo) error {
 	refURL, err := url.Parse(anchor.Hashlink)
 	if err != nil {
@@ -492,6 +769,29 @@ func getSuffixes(m []*subject.SuffixAnchor) (suffixes []string, areNewSuffixes [
 	return suffixes, areNewSuffixes
 }
 
+func getProofDomains(proofs []verifiable.Proof) []string {
+	var domains []string
+
+	set := make(map[string]struct{})
+
+	for _, proof := range proofs {
+		domain, ok := proof["domain"].(string)
+		if !ok || domain == "" {
+			continue
+		}
+
+		if _, exists := set[domain]; exists {
+			continue
+		}
+
+		set[domain] = struct{}{}
+
+		domains = append(domains, domain)
+	}
+
+	return domains
+}
+
 func newLikeResult(hashLink string) (*vocab.ObjectProperty, error) {
 	if hashLink == "" {
 		return nil, nil
@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"sync"
 	"testing"
 	"time"
 
@@ -40,6 +41,7 @@ import (
 	"github.com/trustbloc/orb/pkg/pubsub/mempubsub"
 	"github.com/trustbloc/orb/pkg/pubsub/spi"
 	"github.com/trustbloc/orb/pkg/store/cas"
+	"github.com/trustbloc/orb/pkg/store/processedanchor"
 	webfingerclient "github.com/trustbloc/orb/pkg/webfinger/client"
 )
 
@@ -174,7 +176,11 @@ func TestStartObserver(t *testing.T) {
 			AnchorLinkStore:        &orbmocks.AnchorLinkStore{},
 		}
 
-		o, err := New(serviceIRI, providers, WithDiscoveryDomain("webcas:shared.domain.com"))
+		listener := &mockAnchorProcessedListener{}
+		timingsListener := &mockAnchorTimingsListener{}
+
+		o, err := New(serviceIRI, providers, WithDiscoveryDomain("webcas:shared.domain.com"),
+			WithAnchorProcessedListener(listener), WithAnchorTimingsListener(timingsListener))
 		require.NotNil(t, o)
 		require.NoError(t, err)
 
@@ -188,6 +194,26 @@ func TestStartObserver(t *testing.T) {
 		time.Sleep(200 * time.Millisecond)
 
 		require.Equal(t, 2, tp.ProcessCallCount())
+
+		processed := listener.Anchors()
+		require.Len(t, processed, 2)
+
+		for _, a := range processed {
+			require.Equal(t, namespace1, a.Namespace)
+			require.NotEmpty(t, a.CanonicalReference)
+			require.False(t, a.Published.IsZero())
+			require.Len(t, a.Suffixes, 1)
+		}
+
+		timings := timingsListener.Timings()
+		require.Len(t, timings, 3)
+
+		for _, at := range timings {
+			require.NotEmpty(t, at.Hashlink)
+			require.False(t, at.StartTime.IsZero())
+			require.NotEmpty(t, at.Stages)
+			require.Equal(t, StageCASFetch, at.Stages[0].Stage)
+		}
 	})
 
 	t.Run("success - process did (multiple, just create)", func(t *testing.T) {
@@ -601,7 +627,7 @@ func TestStartObserver(t *testing.T) {
 		pc.Versions[0].ProtocolReturns(pc.Protocol)
 
 		anchorGraph := &orbmocks.AnchorGraph{}
-		anchorGraph.GetDidAnchorsReturns([]graph.Anchor{{
+		anchorGraph.GetNewDidAnchorsReturns([]graph.Anchor{{
 			Info: &vocab.AnchorEventType{},
 		}}, nil)
 
@@ -698,6 +724,183 @@ func TestStartObserver(t *testing.T) {
 	})
 }
 
+func TestReplayProtection(t *testing.T) {
+	const (
+		namespace = "did:orb"
+		hl        = "hl:uEiBL1RVIr2DdyRE5h6b8bPys-PuVs5mMPPC778OtklPa-w"
+		hlAlt     = hl + ":uoQ-BeEJpcGZzOi8vYmFma3JlaWNuNGwzeGxmcWN3aDZrNjU0dzNqb3NnN210NWp6YW5meWY0ZXM0am1kbjR2YWlocTJteXk" //nolint:lll
+		canonical = "uEiBL1RVIr2DdyRE5h6b8bPys-PuVs5mMPPC778OtklPa-w"
+		origin    = "https://orb.domain2.com/services/orb"
+		witness   = "https://orb.domain3.com/services/orb"
+	)
+
+	newObserver := func(t *testing.T, anchorGraph AnchorGraph, tp *mocks.TxnProcessor, opts ...Option) *Observer {
+		t.Helper()
+
+		pc := mocks.NewMockProtocolClient()
+		pc.Versions[0].TransactionProcessorReturns(tp)
+		pc.Versions[0].ProtocolReturns(pc.Protocol)
+
+		casResolver := &protomocks.CASResolver{}
+		casResolver.ResolveReturns([]byte(anchorEvent), "", nil)
+
+		providers := &Providers{
+			ProtocolClientProvider: mocks.NewMockProtocolClientProvider().WithProtocolClient(namespace, pc),
+			AnchorGraph:            anchorGraph,
+			DidAnchors:             memdidanchor.New(),
+			PubSub:                 mempubsub.New(mempubsub.DefaultConfig()),
+			Metrics:                &orbmocks.MetricsProvider{},
+			Outbox:                 func() Outbox { return apmocks.NewOutbox() },
+			WebFingerResolver:      &apmocks.WebFingerResolver{},
+			CASResolver:            casResolver,
+			DocLoader:              testutil.GetLoader(t),
+			Pkf:                    pubKeyFetcherFnc,
+			AnchorLinkStore:        &orbmocks.AnchorLinkStore{},
+		}
+
+		o, err := New(serviceIRI, providers, opts...)
+		require.NoError(t, err)
+
+		return o
+	}
+
+	payload := &subject.Payload{
+		Namespace:       namespace,
+		CoreIndex:       "core1",
+		PreviousAnchors: []*subject.SuffixAnchor{{Suffix: "did1"}},
+	}
+
+	// The anchor must pass validation, otherwise it's rejected before the replay check is reached.
+	require.NoError(t, validateCredentialSubject(newMockAnchorEvent(t, payload)))
+
+	t.Run("Equivalent anchors from multiple sources", func(t *testing.T) {
+		anchorGraph := &orbmocks.AnchorGraph{}
+		anchorGraph.ReadReturns(newMockAnchorEvent(t, payload), nil)
+
+		tp := &mocks.TxnProcessor{}
+
+		store, err := processedanchor.New(mem.NewProvider())
+		require.NoError(t, err)
+
+		o := newObserver(t, anchorGraph, tp, WithProcessedAnchorStore(store))
+
+		require.NoError(t, o.handleAnchor(&anchorinfo.AnchorInfo{Hashlink: hl, AttributedTo: origin}))
+		require.Equal(t, 1, anchorGraph.ReadCallCount())
+		require.Equal(t, 1, tp.ProcessCallCount())
+
+		// The same anchor (with a different hashlink) from a witness announcement and again from the origin.
+		require.NoError(t, o.handleAnchor(&anchorinfo.AnchorInfo{Hashlink: hlAlt, AttributedTo: witness}))
+		require.NoError(t, o.handleAnchor(&anchorinfo.AnchorInfo{Hashlink: hl, AttributedTo: origin}))
+		require.Equal(t, 1, anchorGraph.ReadCallCount(), "anchor should not have been read again")
+		require.Equal(t, 1, tp.ProcessCallCount(), "anchor should not have been processed again")
+
+		entry, err := store.Get(canonical)
+		require.NoError(t, err)
+		require.Equal(t, hl, entry.Hashlink)
+		require.Equal(t, []string{origin, witness}, entry.Sources)
+
+		// A locally created anchor is attributed to this service.
+		require.NoError(t, o.handleAnchor(&anchorinfo.AnchorInfo{Hashlink: hl}))
+
+		entry, err = store.Get(canonical)
+		require.NoError(t, err)
+		require.Equal(t, []string{serviceIRI.String(), origin, witness}, entry.Sources)
+	})
+
+	t.Run("Processing error -> not marked as processed", func(t *testing.T) {
+		anchorGraph := &orbmocks.AnchorGraph{}
+		anchorGraph.ReadReturnsOnCall(0, nil, orberrors.NewTransient(errors.New("injected read error")))
+		anchorGraph.ReadReturns(newMockAnchorEvent(t, payload), nil)
+
+		tp := &mocks.TxnProcessor{}
+
+		store, err := processedanchor.New(mem.NewProvider())
+		require.NoError(t, err)
+
+		o := newObserver(t, anchorGraph, tp, WithProcessedAnchorStore(store))
+
+		require.Error(t, o.handleAnchor(&anchorinfo.AnchorInfo{Hashlink: hl, AttributedTo: origin}))
+		require.Equal(t, 0, tp.ProcessCallCount())
+
+		require.NoError(t, o.handleAnchor(&anchorinfo.AnchorInfo{Hashlink: hl, AttributedTo: origin}))
+		require.Equal(t, 1, tp.ProcessCallCount())
+	})
+
+	t.Run("Store errors -> anchor processed", func(t *testing.T) {
+		anchorGraph := &orbmocks.AnchorGraph{}
+		anchorGraph.ReadReturns(newMockAnchorEvent(t, payload), nil)
+
+		tp := &mocks.TxnProcessor{}
+
+		store := &mockProcessedAnchorStore{
+			isProcessedErr:  errors.New("injected get error"),
+			putProcessedErr: errors.New("injected put error"),
+		}
+
+		o := newObserver(t, anchorGraph, tp, WithProcessedAnchorStore(store))
+
+		require.NoError(t, o.handleAnchor(&anchorinfo.AnchorInfo{Hashlink: hl, AttributedTo: origin}))
+		require.NoError(t, o.handleAnchor(&anchorinfo.AnchorInfo{Hashlink: hl, AttributedTo: origin}))
+		require.Equal(t, 2, tp.ProcessCallCount())
+	})
+
+	t.Run("AddSource error -> anchor skipped", func(t *testing.T) {
+		anchorGraph := &orbmocks.AnchorGraph{}
+
+		tp := &mocks.TxnProcessor{}
+
+		store := &mockProcessedAnchorStore{
+			processed:    true,
+			addSourceErr: errors.New("injected add error"),
+		}
+
+		o := newObserver(t, anchorGraph, tp, WithProcessedAnchorStore(store))
+
+		require.NoError(t, o.handleAnchor(&anchorinfo.AnchorInfo{Hashlink: hl, AttributedTo: witness}))
+		require.Equal(t, 0, anchorGraph.ReadCallCount())
+		require.Equal(t, 0, tp.ProcessCallCount())
+	})
+
+	t.Run("Invalid hashlink -> not checked", func(t *testing.T) {
+		anchorGraph := &orbmocks.AnchorGraph{}
+		anchorGraph.ReadReturns(nil, errors.New("injected read error"))
+
+		store := &mockProcessedAnchorStore{processed: true}
+
+		o := newObserver(t, anchorGraph, &mocks.TxnProcessor{}, WithProcessedAnchorStore(store))
+
+		require.Error(t, o.handleAnchor(&anchorinfo.AnchorInfo{Hashlink: "invalid"}))
+		require.Equal(t, 1, anchorGraph.ReadCallCount())
+	})
+
+	t.Run("Out-of-system DID -> traversal stops at processed anchor", func(t *testing.T) {
+		anchorGraph := &orbmocks.AnchorGraph{}
+
+		store := &mockProcessedAnchorStore{processed: true}
+
+		o := newObserver(t, anchorGraph, &mocks.TxnProcessor{}, WithProcessedAnchorStore(store))
+
+		require.NoError(t, o.processDID(hl+":did1"))
+		require.Equal(t, 1, anchorGraph.GetNewDidAnchorsCallCount())
+
+		cid, suffix, isKnown := anchorGraph.GetNewDidAnchorsArgsForCall(0)
+		require.Equal(t, hl, cid)
+		require.Equal(t, "did1", suffix)
+		require.True(t, isKnown(hl))
+		require.False(t, isKnown("invalid"))
+
+		store.isProcessedErr = errors.New("injected get error")
+		require.False(t, isKnown(hl))
+
+		o = newObserver(t, anchorGraph, &mocks.TxnProcessor{})
+
+		require.NoError(t, o.processDID(hl+":did1"))
+
+		_, _, isKnown = anchorGraph.GetNewDidAnchorsArgsForCall(1)
+		require.False(t, isKnown(hl))
+	})
+}
+
 func TestResolveActorFromHashlink(t *testing.T) {
 	const hl = "hl:uEiAFwmZwzDoQ0XpnsKVHwwAjGCJ6g1prSDwUEMsDKv86NQ:uoQ-BeEJpcGZzOi8vYmFma3JlaWFmeWp0aGJ0YjJjZGl4" +
 		"dXo1cXV2ZDRnYWJkZGFyaHZhMjJubmVkeWZhcXptYnN2N3oyZ3U"
@@ -760,16 +963,51 @@ func TestResolveActorFromHashlink(t *testing.T) {
 	})
 }
 
+func TestValidateCredentialSubject(t *testing.T) {
+	const namespace = "did:orb"
+
+	payload := &subject.Payload{
+		Namespace:       namespace,
+		Version:         0,
+		CoreIndex:       "address",
+		PreviousAnchors: []*subject.SuffixAnchor{{Suffix: "did1"}},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		require.NoError(t, validateCredentialSubject(newMockAnchorEvent(t, payload)))
+	})
+
+	t.Run("Invalid subject", func(t *testing.T) {
+		err := validateCredentialSubject(newMockAnchorEventWithSubject(t, payload, "https://orb.domain1.com/xxx"))
+		require.ErrorIs(t, err, subject.ErrInvalidCredentialSubject)
+		require.Contains(t, err.Error(), "credentialSubject: Does not match pattern")
+	})
+
+	t.Run("No witness", func(t *testing.T) {
+		err := validateCredentialSubject(vocab.NewAnchorEvent())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get witness from anchor event")
+	})
+}
+
 func newMockAnchorEvent(t *testing.T, payload *subject.Payload) *vocab.AnchorEventType {
 	t.Helper()
 
+	return newMockAnchorEventWithSubject(t, payload,
+		"hl:uEiBN4vd1lgKx_K93ltpdI32T6nIGlwXhJcSwbeVAg8NMxg:uoQ-BeEJpcGZzOi8vYmFma3JlaWNuNGwzeGxmcWN3aDZrNjU0dzNqb3NnN210NWp6YW5meWY0ZXM0am1kbjR2YWlocTJteXk", //nolint:lll
+	)
+}
+
+func newMockAnchorEventWithSubject(t *testing.T, payload *subject.Payload, subjectID string) *vocab.AnchorEventType {
+	t.Helper()
+
 	const defVCContext = "https://www.w3.org/2018/credentials/v1"
 
 	vc := &verifiable.Credential{
 		Types:   []string{"VerifiableCredential"},
 		Context: []string{defVCContext},
 		Subject: &builder.CredentialSubject{
-			ID: "hl:uEiBN4vd1lgKx_K93ltpdI32T6nIGlwXhJcSwbeVAg8NMxg:uoQ-BeEJpcGZzOi8vYmFma3JlaWNuNGwzeGxmcWN3aDZrNjU0dzNqb3NnN210NWp6YW5meWY0ZXM0am1kbjR2YWlocTJteXk", //nolint:lll
+			ID: subjectID,
 		},
 		Issuer: verifiable.Issuer{
 			ID: "http://orb.domain.com",
@@ -864,3 +1102,73 @@ const anchorEvent = `{
 const anchorEventInvalid = `{
   "@context": [
 `
+
+func TestGetProofDomains(t *testing.T) {
+	require.Empty(t, getProofDomains(nil))
+
+	domains := getProofDomains([]verifiable.Proof{
+		{"domain": "https://orb.domain1.com"},
+		{"domain": "https://witness1.com/vct"},
+		{"domain": "https://orb.domain1.com"},
+		{"domain": 10},
+		{"created": "2021-09-01T10:00:00Z"},
+	})
+	require.Equal(t, []string{"https://orb.domain1.com", "https://witness1.com/vct"}, domains)
+}
+
+type mockAnchorProcessedListener struct {
+	mutex   sync.Mutex
+	anchors []*ProcessedAnchor
+}
+
+func (m *mockAnchorProcessedListener) AnchorProcessed(anchor *ProcessedAnchor) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.anchors = append(m.anchors, anchor)
+}
+
+func (m *mockAnchorProcessedListener) Anchors() []*ProcessedAnchor {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.anchors
+}
+
+type mockAnchorTimingsListener struct {
+	mutex   sync.Mutex
+	timings []*AnchorTimings
+}
+
+func (m *mockAnchorTimingsListener) AnchorTimings(timings *AnchorTimings) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.timings = append(m.timings, timings)
+}
+
+func (m *mockAnchorTimingsListener) Timings() []*AnchorTimings {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.timings
+}
+
+type mockProcessedAnchorStore struct {
+	processed       bool
+	isProcessedErr  error
+	putProcessedErr error
+	addSourceErr    error
+}
+
+func (m *mockProcessedAnchorStore) IsProcessed(string) (bool, error) {
+	return m.processed, m.isProcessedErr
+}
+
+func (m *mockProcessedAnchorStore) PutProcessed(string, string, string) error {
+	return m.putProcessedErr
+}
+
+func (m *mockProcessedAnchorStore) AddSource(string, string) error {
+	return m.addSourceErr
+}
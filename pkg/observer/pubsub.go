@@ -10,6 +10,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sync"
 
 	"github.com/ThreeDotsLabs/watermill"
 	"github.com/ThreeDotsLabs/watermill/message"
@@ -21,8 +22,9 @@ import (
 )
 
 const (
-	anchorTopic = "orb.anchor"
-	didTopic    = "orb.did"
+	anchorTopic      = "orb.anchor"
+	didTopic         = "orb.did"
+	shardTopicPrefix = anchorTopic + ".shard."
 )
 
 type (
@@ -30,6 +32,17 @@ type (
 	didProcessor    func(did string) error
 )
 
+// ShardRouter determines which instance owns the shard of a given anchor origin.
+type ShardRouter interface {
+	InstanceID() string
+	Owner(key string) string
+	OnMemberLeft(handler func(instanceID string))
+}
+
+type messageSubscriber interface {
+	SubscribeWithOpts(ctx context.Context, topic string, opts ...spi.Option) (<-chan *message.Message, error)
+}
+
 type messagePublisher interface {
 	Publish(topic string, messages ...*message.Message) error
 	Close() error
@@ -41,27 +54,59 @@ type PubSub struct {
 	*lifecycle.Lifecycle
 
 	publisher      messagePublisher
+	subscriber     messageSubscriber
+	poolSize       uint
 	anchorCredChan <-chan *message.Message
+	shardChan      <-chan *message.Message
 	didChan        <-chan *message.Message
+	shardRouter    ShardRouter
 	processAnchors anchorProcessor
 	processDID     didProcessor
 	jsonUnmarshal  func(data []byte, v interface{}) error
 	jsonMarshal    func(v interface{}) ([]byte, error)
+	done           chan struct{}
+	listenerDone   chan struct{}
+
+	drainMutex sync.Mutex
+	draining   map[string]struct{}
+	drainWG    sync.WaitGroup
+}
+
+// PubSubOpt sets a publisher/subscriber option.
+type PubSubOpt func(ps *PubSub)
+
+// WithPubSubShardRouter sets the shard router. If set then an anchor is processed only by the instance that owns
+// the shard of the anchor's origin. Anchors that are owned by another instance are forwarded to the owner's shard
+// topic. Anchors received on a shard topic are always processed, i.e. an anchor is forwarded at most once.
+func WithPubSubShardRouter(router ShardRouter) PubSubOpt {
+	return func(ps *PubSub) {
+		ps.shardRouter = router
+	}
 }
 
 // NewPubSub returns a new publisher/subscriber.
 func NewPubSub(pubSub pubSub, anchorProcessor anchorProcessor, didProcessor didProcessor,
-	poolSize uint) (*PubSub, error) {
+	poolSize uint, opts ...PubSubOpt) (*PubSub, error) {
 	h := &PubSub{
 		publisher:      pubSub,
+		subscriber:     pubSub,
+		poolSize:       poolSize,
 		processAnchors: anchorProcessor,
 		processDID:     didProcessor,
 		jsonUnmarshal:  json.Unmarshal,
 		jsonMarshal:    json.Marshal,
+		done:           make(chan struct{}),
+		listenerDone:   make(chan struct{}),
+		draining:       make(map[string]struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(h)
 	}
 
 	h.Lifecycle = lifecycle.New("observer-pubsub",
 		lifecycle.WithStart(h.start),
+		lifecycle.WithStop(h.stop),
 	)
 
 	logger.Infof("Subscribing to topic [%s]", anchorTopic)
@@ -73,6 +118,19 @@ func NewPubSub(pubSub pubSub, anchorProcessor anchorProcessor, didProcessor didP
 
 	h.anchorCredChan = anchorCredChan
 
+	if h.shardRouter != nil {
+		shardTopic := shardTopicPrefix + h.shardRouter.InstanceID()
+
+		logger.Infof("Subscribing to topic [%s]", shardTopic)
+
+		h.shardChan, err = pubSub.SubscribeWithOpts(context.Background(), shardTopic, spi.WithPool(poolSize))
+		if err != nil {
+			return nil, fmt.Errorf("subscribe to topic [%s]: %w", shardTopic, err)
+		}
+
+		h.shardRouter.OnMemberLeft(h.drainShard)
+	}
+
 	logger.Infof("Subscribing to topic [%s]", didTopic)
 
 	didChan, err := pubSub.SubscribeWithOpts(context.Background(), didTopic, spi.WithPool(poolSize))
@@ -136,11 +194,35 @@ func (h *PubSub) start() {
 	go h.listen()
 }
 
+// stop stops the message listener and waits for the message that is currently being processed
+// (if any) to complete. Messages that have not yet been processed are redelivered by the message
+// broker once the subscriber is closed.
+func (h *PubSub) stop() {
+	// The done channel is closed while holding the drain mutex so that no shard topics are drained after
+	// this point.
+	h.drainMutex.Lock()
+	close(h.done)
+	h.drainMutex.Unlock()
+
+	<-h.listenerDone
+
+	h.drainWG.Wait()
+
+	logger.Infof("Observer message listener stopped")
+}
+
 func (h *PubSub) listen() {
 	logger.Debugf("Starting message listener")
 
+	defer close(h.listenerDone)
+
 	for {
 		select {
+		case <-h.done:
+			logger.Debugf("Message listener stopped")
+
+			return
+
 		case msg, ok := <-h.anchorCredChan:
 			if !ok {
 				logger.Debugf("Message listener stopped")
@@ -151,7 +233,19 @@ func (h *PubSub) listen() {
 			logger.Debugf("Got new anchor credential message [%s], Metadata: %s, Payload %s",
 				msg.UUID, msg.Metadata, msg.Payload)
 
-			h.handleAnchorCredentialMessage(msg)
+			h.handleAnchorCredentialMessage(msg, true)
+
+		case msg, ok := <-h.shardChan:
+			if !ok {
+				logger.Debugf("Message listener stopped")
+
+				return
+			}
+
+			logger.Debugf("Got new anchor credential message [%s] on shard topic, Metadata: %s, Payload %s",
+				msg.UUID, msg.Metadata, msg.Payload)
+
+			h.handleAnchorCredentialMessage(msg, false)
 
 		case msg, ok := <-h.didChan:
 			if !ok {
@@ -167,7 +261,9 @@ func (h *PubSub) listen() {
 	}
 }
 
-func (h *PubSub) handleAnchorCredentialMessage(msg *message.Message) {
+// handleAnchorCredentialMessage processes the anchor in the given message. If forward is true and the anchor's
+// origin is owned by another instance then the anchor is forwarded to the owner's shard topic instead.
+func (h *PubSub) handleAnchorCredentialMessage(msg *message.Message, forward bool) {
 	logger.Debugf("Handling message [%s]: %s", msg.UUID, msg.Payload)
 
 	anchorInfo := &anchorinfo.AnchorInfo{}
@@ -182,9 +278,108 @@ func (h *PubSub) handleAnchorCredentialMessage(msg *message.Message) {
 		return
 	}
 
+	if owner, ok := h.shardOwner(anchorInfo); forward && !ok {
+		h.ackNackMessage(msg, newAnchorInfo(anchorInfo), h.forwardAnchor(msg, owner))
+
+		return
+	}
+
 	h.ackNackMessage(msg, newAnchorInfo(anchorInfo), h.processAnchors(anchorInfo))
 }
 
+// shardOwner returns the instance that owns the shard of the anchor's origin and true if the owner is
+// this instance. Anchors without an origin (i.e. local anchors) are always processed by this instance.
+func (h *PubSub) shardOwner(anchorInfo *anchorinfo.AnchorInfo) (string, bool) {
+	if h.shardRouter == nil || anchorInfo.AttributedTo == "" {
+		return "", true
+	}
+
+	owner := h.shardRouter.Owner(anchorInfo.AttributedTo)
+
+	return owner, owner == h.shardRouter.InstanceID()
+}
+
+// forwardAnchor publishes the anchor message to the shard topic of the given owner.
+func (h *PubSub) forwardAnchor(msg *message.Message, owner string) error {
+	shardTopic := shardTopicPrefix + owner
+
+	logger.Debugf("Forwarding anchors message [%s] to topic [%s] of shard owner", msg.UUID, shardTopic)
+
+	err := h.publisher.Publish(shardTopic, message.NewMessage(watermill.NewUUID(), msg.Payload))
+	if err != nil {
+		return errors.NewTransient(fmt.Errorf("forward anchor to topic [%s]: %w", shardTopic, err))
+	}
+
+	return nil
+}
+
+// drainShard subscribes to the shard topic of an instance that left the shard group so that the anchors that were
+// forwarded to the instance, and not processed before it left, are processed. To avoid every member subscribing
+// to the topic, the topic is drained only by the member that owns the departed instance's ID on the hash ring.
+// (If the instance rejoins the group then the anchors on its topic may be processed by either instance.)
+func (h *PubSub) drainShard(instanceID string) {
+	if instanceID == h.shardRouter.InstanceID() || h.shardRouter.Owner(instanceID) != h.shardRouter.InstanceID() {
+		return
+	}
+
+	if h.State() != lifecycle.StateStarted {
+		// The departed member is reported again on the next membership refresh.
+		return
+	}
+
+	h.drainMutex.Lock()
+	defer h.drainMutex.Unlock()
+
+	select {
+	case <-h.done:
+		return
+	default:
+	}
+
+	if _, ok := h.draining[instanceID]; ok {
+		return
+	}
+
+	shardTopic := shardTopicPrefix + instanceID
+
+	msgChan, err := h.subscriber.SubscribeWithOpts(context.Background(), shardTopic, spi.WithPool(h.poolSize))
+	if err != nil {
+		// The subscription is retried the next time that the departed member is reported.
+		logger.Warnf("Error subscribing to topic [%s] of departed shard member [%s]: %s", shardTopic, instanceID, err)
+
+		return
+	}
+
+	logger.Infof("Draining topic [%s] of departed shard member [%s]", shardTopic, instanceID)
+
+	h.draining[instanceID] = struct{}{}
+
+	h.drainWG.Add(1)
+
+	go h.drain(msgChan)
+}
+
+func (h *PubSub) drain(msgChan <-chan *message.Message) {
+	defer h.drainWG.Done()
+
+	for {
+		select {
+		case <-h.done:
+			return
+
+		case msg, ok := <-msgChan:
+			if !ok {
+				return
+			}
+
+			logger.Debugf("Got anchor credential message [%s] on the shard topic of a departed member: %s",
+				msg.UUID, msg.Payload)
+
+			h.handleAnchorCredentialMessage(msg, false)
+		}
+	}
+}
+
 func (h *PubSub) handleDIDMessage(msg *message.Message) {
 	logger.Debugf("Handling message [%s]: %s", msg.UUID, msg.Payload)
 
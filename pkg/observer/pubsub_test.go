@@ -7,11 +7,16 @@ SPDX-License-Identifier: Apache-2.0
 package observer
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
 	"github.com/stretchr/testify/require"
 
 	anchorinfo "github.com/trustbloc/orb/pkg/anchor/info"
@@ -75,6 +80,176 @@ func TestPubSub(t *testing.T) {
 	mutex.RUnlock()
 }
 
+func TestPubSub_Stop(t *testing.T) {
+	p := mempubsub.New(mempubsub.DefaultConfig())
+
+	processing := make(chan struct{})
+	release := make(chan struct{})
+
+	var processed uint32
+
+	ps, err := NewPubSub(p,
+		func(anchor *anchorinfo.AnchorInfo) error {
+			close(processing)
+
+			<-release
+
+			atomic.StoreUint32(&processed, 1)
+
+			return nil
+		},
+		func(did string) error { return nil },
+		5,
+	)
+	require.NoError(t, err)
+
+	ps.Start()
+
+	require.NoError(t, ps.PublishAnchor(&anchorinfo.AnchorInfo{Hashlink: "abcdefg"}))
+
+	<-processing
+
+	stopped := make(chan struct{})
+
+	go func() {
+		ps.Stop()
+
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		t.Fatal("stop should wait for the in-flight message to be processed")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(release)
+
+	select {
+	case <-stopped:
+		require.Equal(t, uint32(1), atomic.LoadUint32(&processed))
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for stop")
+	}
+}
+
+func TestPubSub_Sharding(t *testing.T) {
+	p := mempubsub.New(mempubsub.DefaultConfig())
+
+	var mutex sync.RWMutex
+
+	var gotAnchors []*anchorinfo.AnchorInfo
+
+	router := &mockShardRouter{
+		instanceID: "instance1",
+		owners: map[string]string{
+			"https://domain1.com/services/orb": "instance1",
+			"https://domain2.com/services/orb": "instance2",
+			"instance3":                        "instance1",
+			"instance4":                        "instance2",
+		},
+	}
+
+	forwardedChan, err := p.Subscribe(context.Background(), shardTopicPrefix+"instance2")
+	require.NoError(t, err)
+
+	ps, err := NewPubSub(p,
+		func(anchor *anchorinfo.AnchorInfo) error {
+			mutex.Lock()
+			gotAnchors = append(gotAnchors, anchor)
+			mutex.Unlock()
+
+			return nil
+		},
+		func(did string) error { return nil },
+		5,
+		WithPubSubShardRouter(router),
+	)
+	require.NoError(t, err)
+
+	ps.Start()
+	defer ps.Stop()
+
+	localAnchor := &anchorinfo.AnchorInfo{Hashlink: "hl1"}
+	ownedAnchor := &anchorinfo.AnchorInfo{Hashlink: "hl2", AttributedTo: "https://domain1.com/services/orb"}
+	notOwnedAnchor := &anchorinfo.AnchorInfo{Hashlink: "hl3", AttributedTo: "https://domain2.com/services/orb"}
+
+	require.NoError(t, ps.PublishAnchor(localAnchor))
+	require.NoError(t, ps.PublishAnchor(ownedAnchor))
+	require.NoError(t, ps.PublishAnchor(notOwnedAnchor))
+
+	select {
+	case msg := <-forwardedChan:
+		forwarded := &anchorinfo.AnchorInfo{}
+		require.NoError(t, json.Unmarshal(msg.Payload, forwarded))
+		require.Equal(t, notOwnedAnchor, forwarded)
+
+		msg.Ack()
+	case <-time.After(time.Second):
+		t.Fatal("expecting anchor to be forwarded to the shard owner")
+	}
+
+	require.Eventually(t, func() bool {
+		mutex.RLock()
+		defer mutex.RUnlock()
+
+		return len(gotAnchors) == 2
+	}, time.Second, 10*time.Millisecond)
+
+	mutex.RLock()
+	require.Contains(t, gotAnchors, localAnchor)
+	require.Contains(t, gotAnchors, ownedAnchor)
+	mutex.RUnlock()
+
+	// An anchor that's received on this instance's shard topic is processed, even if the hash ring of this
+	// instance says that another instance owns it (i.e. the anchor isn't forwarded again).
+	payload, err := json.Marshal(notOwnedAnchor)
+	require.NoError(t, err)
+
+	require.NoError(t, p.Publish(shardTopicPrefix+"instance1", message.NewMessage(watermill.NewUUID(), payload)))
+
+	require.Eventually(t, func() bool {
+		mutex.RLock()
+		defer mutex.RUnlock()
+
+		return len(gotAnchors) == 3
+	}, time.Second, 10*time.Millisecond)
+
+	select {
+	case <-forwardedChan:
+		t.Fatal("an anchor received on the shard topic must not be forwarded")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// The shard topic of a departed member is drained by the member that owns the departed member's ID.
+	router.memberLeft("instance1")
+	router.memberLeft("instance3")
+	router.memberLeft("instance3")
+	router.memberLeft("instance4")
+
+	drainedAnchor := &anchorinfo.AnchorInfo{Hashlink: "hl4", AttributedTo: "https://domain2.com/services/orb"}
+
+	payload, err = json.Marshal(drainedAnchor)
+	require.NoError(t, err)
+
+	require.NoError(t, p.Publish(shardTopicPrefix+"instance3", message.NewMessage(watermill.NewUUID(), payload)))
+
+	require.Eventually(t, func() bool {
+		mutex.RLock()
+		defer mutex.RUnlock()
+
+		return len(gotAnchors) == 4
+	}, time.Second, 10*time.Millisecond)
+
+	mutex.RLock()
+	require.Contains(t, gotAnchors, drainedAnchor)
+	mutex.RUnlock()
+
+	ps.drainMutex.Lock()
+	require.Len(t, ps.draining, 1)
+	ps.drainMutex.Unlock()
+}
+
 func TestPubSub_Error(t *testing.T) {
 	t.Run("Subscribe anchor error", func(t *testing.T) {
 		errExpected := errors.New("injected pub/sub error")
@@ -106,6 +281,63 @@ func TestPubSub_Error(t *testing.T) {
 		require.Nil(t, ps)
 	})
 
+	t.Run("Subscribe shard error", func(t *testing.T) {
+		errExpected := errors.New("injected pub/sub error")
+
+		p := &mocks.PubSub{}
+		p.SubscribeWithOptsReturnsOnCall(1, nil, errExpected)
+
+		ps, err := NewPubSub(p,
+			func(anchor *anchorinfo.AnchorInfo) error { return nil },
+			func(did string) error { return nil },
+			5,
+			WithPubSubShardRouter(&mockShardRouter{instanceID: "instance1"}),
+		)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), shardTopicPrefix+"instance1")
+		require.Nil(t, ps)
+	})
+
+	t.Run("Drain subscribe error", func(t *testing.T) {
+		p := &mocks.PubSub{}
+		p.SubscribeWithOptsReturnsOnCall(3, nil, errors.New("injected pub/sub error"))
+
+		router := &mockShardRouter{instanceID: "instance1", owners: map[string]string{"instance3": "instance1"}}
+
+		ps, err := NewPubSub(p,
+			func(anchor *anchorinfo.AnchorInfo) error { return nil },
+			func(did string) error { return nil },
+			5,
+			WithPubSubShardRouter(router),
+		)
+		require.NoError(t, err)
+
+		// The topic isn't drained before the pub/sub is started.
+		router.memberLeft("instance3")
+		require.Equal(t, 3, p.SubscribeWithOptsCallCount())
+
+		ps.Start()
+		defer ps.Stop()
+
+		router.memberLeft("instance3")
+		require.Equal(t, 4, p.SubscribeWithOptsCallCount())
+		require.Empty(t, ps.draining)
+	})
+
+	t.Run("Forward error", func(t *testing.T) {
+		errExpected := errors.New("injected publish error")
+
+		ps := &PubSub{
+			publisher:   &mocks.PubSub{PublishStub: func(string, ...*message.Message) error { return errExpected }},
+			shardRouter: &mockShardRouter{instanceID: "instance1"},
+		}
+
+		err := ps.forwardAnchor(message.NewMessage(watermill.NewUUID(), nil), "instance2")
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), errExpected.Error())
+	})
+
 	t.Run("Marshal error", func(t *testing.T) {
 		p := mempubsub.New(mempubsub.DefaultConfig())
 		require.NotNil(t, p)
@@ -219,3 +451,27 @@ func TestPubSub_Error(t *testing.T) {
 		require.EqualError(t, ps.PublishDID("123456"), lifecycle.ErrNotStarted.Error())
 	})
 }
+
+type mockShardRouter struct {
+	instanceID string
+	owners     map[string]string
+	handlers   []func(instanceID string)
+}
+
+func (m *mockShardRouter) InstanceID() string {
+	return m.instanceID
+}
+
+func (m *mockShardRouter) Owner(key string) string {
+	return m.owners[key]
+}
+
+func (m *mockShardRouter) OnMemberLeft(handler func(instanceID string)) {
+	m.handlers = append(m.handlers, handler)
+}
+
+func (m *mockShardRouter) memberLeft(instanceID string) {
+	for _, handle := range m.handlers {
+		handle(instanceID)
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shard
+
+import (
+	"hash/fnv"
+	"sort"
+	"strconv"
+)
+
+// DefaultVirtualNodes is the default number of points that each member occupies on the hash ring. A larger
+// number results in a more even distribution of keys among the members.
+const DefaultVirtualNodes = 100
+
+// Ring is a consistent hash ring that maps keys to members. When a member is added or removed, only the keys
+// that were owned by (or are to be owned by) that member are moved.
+type Ring struct {
+	members []string
+	hashes  []uint64
+	owners  map[uint64]string
+}
+
+// NewRing returns a new hash ring for the given members.
+func NewRing(members []string, virtualNodes int) *Ring {
+	if virtualNodes <= 0 {
+		virtualNodes = DefaultVirtualNodes
+	}
+
+	r := &Ring{
+		owners: make(map[uint64]string),
+	}
+
+	added := make(map[string]struct{})
+
+	for _, member := range members {
+		if _, exists := added[member]; exists {
+			continue
+		}
+
+		added[member] = struct{}{}
+
+		r.members = append(r.members, member)
+
+		for i := 0; i < virtualNodes; i++ {
+			h := hash(member + "#" + strconv.Itoa(i))
+
+			if _, exists := r.owners[h]; exists {
+				continue
+			}
+
+			r.owners[h] = member
+			r.hashes = append(r.hashes, h)
+		}
+	}
+
+	sort.Strings(r.members)
+	sort.Slice(r.hashes, func(i, j int) bool { return r.hashes[i] < r.hashes[j] })
+
+	return r
+}
+
+// Owner returns the member that owns the given key. An empty string is returned if the ring has no members.
+func (r *Ring) Owner(key string) string {
+	if len(r.hashes) == 0 {
+		return ""
+	}
+
+	h := hash(key)
+
+	i := sort.Search(len(r.hashes), func(i int) bool { return r.hashes[i] >= h })
+	if i == len(r.hashes) {
+		i = 0
+	}
+
+	return r.owners[r.hashes[i]]
+}
+
+// Members returns the sorted members of the ring.
+func (r *Ring) Members() []string {
+	return r.members
+}
+
+func hash(value string) uint64 {
+	h := fnv.New64a()
+
+	_, _ = h.Write([]byte(value)) //nolint:errcheck
+
+	return h.Sum64()
+}
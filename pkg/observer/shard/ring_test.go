@@ -0,0 +1,73 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shard
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRing(t *testing.T) {
+	t.Run("Empty ring", func(t *testing.T) {
+		r := NewRing(nil, 0)
+		require.Empty(t, r.Members())
+		require.Empty(t, r.Owner("https://domain1.com/services/orb"))
+	})
+
+	t.Run("Duplicate members", func(t *testing.T) {
+		r := NewRing([]string{"instance2", "instance1", "instance2"}, 10)
+		require.Equal(t, []string{"instance1", "instance2"}, r.Members())
+	})
+
+	t.Run("Distribution", func(t *testing.T) {
+		members := []string{"instance1", "instance2", "instance3"}
+
+		r := NewRing(members, DefaultVirtualNodes)
+
+		counts := make(map[string]int)
+
+		for i := 0; i < 3000; i++ {
+			key := fmt.Sprintf("https://domain%d.com/services/orb", i)
+
+			owner := r.Owner(key)
+			require.Contains(t, members, owner)
+			require.Equal(t, owner, r.Owner(key), "ownership must be deterministic")
+
+			counts[owner]++
+		}
+
+		for _, member := range members {
+			require.Greater(t, counts[member], 500, "expecting keys to be distributed among all members")
+		}
+	})
+
+	t.Run("Rebalance", func(t *testing.T) {
+		r1 := NewRing([]string{"instance1", "instance2", "instance3"}, DefaultVirtualNodes)
+		r2 := NewRing([]string{"instance1", "instance2", "instance3", "instance4"}, DefaultVirtualNodes)
+
+		moved := 0
+
+		for i := 0; i < 3000; i++ {
+			key := fmt.Sprintf("https://domain%d.com/services/orb", i)
+
+			owner1 := r1.Owner(key)
+			owner2 := r2.Owner(key)
+
+			if owner1 != owner2 {
+				// Keys may only move to the new member.
+				require.Equal(t, "instance4", owner2)
+
+				moved++
+			}
+		}
+
+		require.Greater(t, moved, 0)
+		require.Less(t, moved, 1500, "expecting only a fraction of the keys to move")
+	})
+}
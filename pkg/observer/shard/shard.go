@@ -0,0 +1,368 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shard
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	"github.com/trustbloc/orb/pkg/lifecycle"
+)
+
+var logger = log.New("observer-shard")
+
+const (
+	storeName = "observer-shard"
+	groupTag  = "shardGroup"
+
+	// DefaultHeartbeatInterval is the default interval at which an instance announces that it's a member
+	// of the shard group.
+	DefaultHeartbeatInterval = 10 * time.Second
+
+	// A member is removed from the hash ring if it hasn't sent a heartbeat within this number of
+	// heartbeat intervals.
+	missedHeartbeats = 3
+)
+
+// member is stored within the shard store and announces that an instance is a member of the shard group.
+type member struct {
+	// InstanceID is the ID of the instance.
+	InstanceID string `json:"instanceId"`
+	// HeartbeatTime is the time of the last heartbeat (Unix time in milliseconds).
+	HeartbeatTime int64 `json:"heartbeatTime"`
+	// Left indicates that the instance has left the group.
+	Left bool `json:"left,omitempty"`
+}
+
+// Status contains the shard membership status.
+type Status struct {
+	// Group is the name of the shard group.
+	Group string `json:"group"`
+	// InstanceID is the ID of this instance.
+	InstanceID string `json:"instanceId"`
+	// Members contains the IDs of the instances that are currently members of the group.
+	Members []string `json:"members"`
+}
+
+type options struct {
+	heartbeatInterval time.Duration
+	virtualNodes      int
+}
+
+// Opt sets a shard manager option.
+type Opt func(opts *options)
+
+// WithHeartbeatInterval sets the interval at which the instance announces its membership.
+func WithHeartbeatInterval(interval time.Duration) Opt {
+	return func(opts *options) {
+		opts.heartbeatInterval = interval
+	}
+}
+
+// WithVirtualNodes sets the number of points that each member occupies on the hash ring.
+func WithVirtualNodes(n int) Opt {
+	return func(opts *options) {
+		opts.virtualNodes = n
+	}
+}
+
+// Manager assigns shards of keys to the instances in a group using consistent hashing. Each instance periodically
+// records a heartbeat in a store that must be shared by all instances and then rebuilds its hash ring from the
+// set of members whose heartbeats haven't expired. When an instance joins or leaves the group, the keys are
+// rebalanced among the members with minimal movement. The handlers registered with OnMemberLeft are notified
+// of the instances that have left the group (gracefully or because their heartbeat expired) so that any work
+// that was assigned to them may be taken over.
+type Manager struct {
+	*lifecycle.Lifecycle
+
+	group             string
+	instanceID        string
+	store             storage.Store
+	heartbeatInterval time.Duration
+	memberTimeout     time.Duration
+	virtualNodes      int
+	done              chan struct{}
+	refresherDone     chan struct{}
+
+	mutex        sync.RWMutex
+	ring         *Ring
+	leftHandlers []func(instanceID string)
+}
+
+// New returns a new shard manager for the given group.
+func New(group, instanceID string, provider storage.Provider, opts ...Opt) (*Manager, error) {
+	options := &options{
+		heartbeatInterval: DefaultHeartbeatInterval,
+		virtualNodes:      DefaultVirtualNodes,
+	}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if options.heartbeatInterval <= 0 {
+		options.heartbeatInterval = DefaultHeartbeatInterval
+	}
+
+	store, err := provider.OpenStore(storeName)
+	if err != nil {
+		return nil, fmt.Errorf("open shard store: %w", err)
+	}
+
+	err = provider.SetStoreConfig(storeName, storage.StoreConfiguration{TagNames: []string{groupTag}})
+	if err != nil {
+		return nil, fmt.Errorf("set store configuration: %w", err)
+	}
+
+	m := &Manager{
+		group:             group,
+		instanceID:        instanceID,
+		store:             store,
+		heartbeatInterval: options.heartbeatInterval,
+		memberTimeout:     options.heartbeatInterval * missedHeartbeats,
+		virtualNodes:      options.virtualNodes,
+		done:              make(chan struct{}),
+		refresherDone:     make(chan struct{}),
+		ring:              NewRing([]string{instanceID}, options.virtualNodes),
+	}
+
+	m.Lifecycle = lifecycle.New("observer-shard-"+group,
+		lifecycle.WithStart(m.start),
+		lifecycle.WithStop(m.stop),
+	)
+
+	return m, nil
+}
+
+// InstanceID returns the ID of this instance.
+func (m *Manager) InstanceID() string {
+	return m.instanceID
+}
+
+// Owner returns the ID of the instance that owns the given key.
+func (m *Manager) Owner(key string) string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	owner := m.ring.Owner(key)
+	if owner == "" {
+		return m.instanceID
+	}
+
+	return owner
+}
+
+// OnMemberLeft registers a handler that's invoked with the ID of each instance that has left the group. Note that
+// the handler is invoked on every membership refresh for as long as the instance remains absent.
+func (m *Manager) OnMemberLeft(handler func(instanceID string)) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.leftHandlers = append(m.leftHandlers, handler)
+}
+
+// Status returns the shard membership status.
+func (m *Manager) Status() *Status {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return &Status{
+		Group:      m.group,
+		InstanceID: m.instanceID,
+		Members:    m.ring.Members(),
+	}
+}
+
+func (m *Manager) start() {
+	logger.Infof("[%s] Joining shard group [%s] - Heartbeat interval: %s",
+		m.instanceID, m.group, m.heartbeatInterval)
+
+	go func() {
+		defer close(m.refresherDone)
+
+		m.refresh()
+
+		for {
+			select {
+			case <-time.After(m.heartbeatInterval):
+				m.refresh()
+			case <-m.done:
+				logger.Debugf("[%s] Stopped shard manager for group [%s]", m.instanceID, m.group)
+
+				return
+			}
+		}
+	}()
+}
+
+func (m *Manager) stop() {
+	close(m.done)
+
+	// Wait for the refresher to exit so that a heartbeat doesn't overwrite the record of this instance leaving.
+	<-m.refresherDone
+
+	// Leave the group so that the other instances may rebalance immediately rather than waiting for the
+	// heartbeat to expire. The member record is kept (and marked as having left) so that the other instances
+	// may take over the work that was assigned to this instance.
+	err := m.putMember(true)
+	if err != nil {
+		logger.Warnf("[%s] Error leaving shard group [%s]: %s", m.instanceID, m.group, err)
+
+		return
+	}
+
+	logger.Infof("[%s] Left shard group [%s]", m.instanceID, m.group)
+}
+
+func (m *Manager) refresh() {
+	err := m.heartbeat()
+	if err != nil {
+		logger.Warnf("[%s] Error sending heartbeat for shard group [%s]: %s", m.instanceID, m.group, err)
+	}
+
+	members, departed, err := m.members()
+	if err != nil {
+		// Keep the current ring since the membership can't be determined.
+		logger.Warnf("[%s] Error retrieving members of shard group [%s]: %s", m.instanceID, m.group, err)
+
+		return
+	}
+
+	handlers := m.updateRing(members)
+
+	// The handlers are invoked after the ring is updated so that they see the current owners.
+	for _, instanceID := range departed {
+		for _, handle := range handlers {
+			handle(instanceID)
+		}
+	}
+}
+
+// updateRing rebuilds the hash ring if the membership has changed and returns the handlers of departed members.
+func (m *Manager) updateRing(members []string) []func(instanceID string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	ring := NewRing(members, m.virtualNodes)
+
+	if !reflect.DeepEqual(ring.Members(), m.ring.Members()) {
+		logger.Infof("[%s] Membership of shard group [%s] changed from %s to %s. Rebalancing shards.",
+			m.instanceID, m.group, m.ring.Members(), ring.Members())
+
+		m.ring = ring
+	}
+
+	return m.leftHandlers
+}
+
+func (m *Manager) heartbeat() error {
+	return m.putMember(false)
+}
+
+func (m *Manager) putMember(left bool) error {
+	memberBytes, err := json.Marshal(&member{
+		InstanceID:    m.instanceID,
+		HeartbeatTime: toMillis(time.Now()),
+		Left:          left,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal member: %w", err)
+	}
+
+	err = m.store.Put(m.memberKey(m.instanceID), memberBytes, storage.Tag{Name: groupTag, Value: m.group})
+	if err != nil {
+		return fmt.Errorf("store member: %w", err)
+	}
+
+	return nil
+}
+
+// members returns the current members of the group along with the instances that have left the group.
+func (m *Manager) members() (members, departed []string, err error) {
+	iter, err := m.store.Query(fmt.Sprintf("%s:%s", groupTag, m.group))
+	if err != nil {
+		return nil, nil, fmt.Errorf("query members: %w", err)
+	}
+
+	defer storage.Close(iter, logger)
+
+	now := time.Now()
+
+	// This instance is always a member, even if its own heartbeat couldn't be stored.
+	members = []string{m.instanceID}
+
+	for {
+		mbr, ok, e := m.nextMember(iter)
+		if e != nil {
+			return nil, nil, e
+		}
+
+		if !ok {
+			break
+		}
+
+		if mbr == nil || mbr.InstanceID == m.instanceID {
+			continue
+		}
+
+		if mbr.Left || now.Sub(time.Unix(0, mbr.HeartbeatTime*int64(time.Millisecond))) > m.memberTimeout {
+			logger.Debugf("[%s] Member [%s] has left shard group [%s] or its heartbeat has expired",
+				m.instanceID, mbr.InstanceID, m.group)
+
+			departed = append(departed, mbr.InstanceID)
+
+			continue
+		}
+
+		members = append(members, mbr.InstanceID)
+	}
+
+	return members, departed, nil
+}
+
+// nextMember returns the next member from the iterator. A nil member is returned if the member couldn't
+// be unmarshalled.
+func (m *Manager) nextMember(iter storage.Iterator) (*member, bool, error) {
+	ok, err := iter.Next()
+	if err != nil {
+		return nil, false, fmt.Errorf("next member: %w", err)
+	}
+
+	if !ok {
+		return nil, false, nil
+	}
+
+	memberBytes, err := iter.Value()
+	if err != nil {
+		return nil, false, fmt.Errorf("get member: %w", err)
+	}
+
+	mbr := &member{}
+
+	err = json.Unmarshal(memberBytes, mbr)
+	if err != nil {
+		logger.Warnf("[%s] Error unmarshalling member of shard group [%s]: %s", m.instanceID, m.group, err)
+
+		return nil, true, nil
+	}
+
+	return mbr, true, nil
+}
+
+func (m *Manager) memberKey(instanceID string) string {
+	return m.group + "_" + instanceID
+}
+
+func toMillis(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
@@ -0,0 +1,178 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shard
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	spi "github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/stretchr/testify/require"
+)
+
+const group = "orb-observer"
+
+func TestNew(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		m, err := New(group, "instance1", mem.NewProvider(), WithHeartbeatInterval(0), WithVirtualNodes(10))
+		require.NoError(t, err)
+		require.NotNil(t, m)
+		require.Equal(t, "instance1", m.InstanceID())
+		require.Equal(t, DefaultHeartbeatInterval, m.heartbeatInterval)
+		require.Equal(t, "instance1", m.Owner("https://domain1.com/services/orb"))
+
+		status := m.Status()
+		require.Equal(t, group, status.Group)
+		require.Equal(t, "instance1", status.InstanceID)
+		require.Equal(t, []string{"instance1"}, status.Members)
+	})
+
+	t.Run("Open store error", func(t *testing.T) {
+		errExpected := errors.New("injected open store error")
+
+		p := storage.NewMockStoreProvider()
+		p.ErrOpenStoreHandle = errExpected
+
+		_, err := New(group, "instance1", p)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errExpected.Error())
+	})
+}
+
+func TestManager(t *testing.T) {
+	provider := mem.NewProvider()
+
+	const heartbeatInterval = 50 * time.Millisecond
+
+	m1, err := New(group, "instance1", provider, WithHeartbeatInterval(heartbeatInterval))
+	require.NoError(t, err)
+
+	m2, err := New(group, "instance2", provider, WithHeartbeatInterval(heartbeatInterval))
+	require.NoError(t, err)
+
+	m1.Start()
+
+	var (
+		mutex sync.Mutex
+		left  []string
+	)
+
+	m2.OnMemberLeft(func(instanceID string) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		left = append(left, instanceID)
+	})
+
+	m2.Start()
+	defer m2.Stop()
+
+	require.Eventually(t, func() bool {
+		return len(m1.Status().Members) == 2 && len(m2.Status().Members) == 2
+	}, 2*time.Second, 10*time.Millisecond)
+
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("https://domain%d.com/services/orb", i)
+
+		require.Equal(t, m1.Owner(key), m2.Owner(key), "all members must agree on the owner of a key")
+	}
+
+	m1.Stop()
+
+	require.Eventually(t, func() bool {
+		return len(m2.Status().Members) == 1
+	}, 2*time.Second, 10*time.Millisecond)
+
+	require.Equal(t, "instance2", m2.Owner("https://domain1.com/services/orb"))
+
+	require.Eventually(t, func() bool {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		return len(left) > 0 && left[0] == "instance1"
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestManager_ExpiredMember(t *testing.T) {
+	provider := mem.NewProvider()
+
+	m, err := New(group, "instance1", provider, WithHeartbeatInterval(time.Second))
+	require.NoError(t, err)
+
+	store, err := provider.OpenStore(storeName)
+	require.NoError(t, err)
+
+	expiredBytes, err := json.Marshal(&member{
+		InstanceID:    "instance2",
+		HeartbeatTime: toMillis(time.Now().Add(-time.Hour)),
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, store.Put(m.memberKey("instance2"), expiredBytes, spi.Tag{Name: groupTag, Value: group}))
+	require.NoError(t, store.Put(m.memberKey("instance3"), []byte("{"), spi.Tag{Name: groupTag, Value: group}))
+
+	var left []string
+
+	m.OnMemberLeft(func(instanceID string) {
+		left = append(left, instanceID)
+	})
+
+	m.refresh()
+
+	require.Equal(t, []string{"instance1"}, m.Status().Members)
+	require.Equal(t, []string{"instance2"}, left)
+}
+
+func TestManager_Error(t *testing.T) {
+	t.Run("Query error", func(t *testing.T) {
+		errExpected := errors.New("injected query error")
+
+		p := storage.NewMockStoreProvider()
+		p.Store = &storage.MockStore{
+			ErrQuery: errExpected,
+			Store:    make(map[string]storage.DBEntry),
+		}
+
+		m, err := New(group, "instance1", p)
+		require.NoError(t, err)
+
+		_, _, err = m.members()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errExpected.Error())
+
+		m.refresh()
+
+		require.Equal(t, []string{"instance1"}, m.Status().Members)
+	})
+
+	t.Run("Put and delete error", func(t *testing.T) {
+		errExpected := errors.New("injected store error")
+
+		p := storage.NewMockStoreProvider()
+		p.Store = &storage.MockStore{
+			ErrPut:    errExpected,
+			ErrDelete: errExpected,
+			Store:     make(map[string]storage.DBEntry),
+		}
+
+		m, err := New(group, "instance1", p)
+		require.NoError(t, err)
+
+		err = m.heartbeat()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errExpected.Error())
+
+		m.Start()
+		m.Stop()
+	})
+}
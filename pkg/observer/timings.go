@@ -0,0 +1,80 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package observer
+
+import (
+	"time"
+
+	txnapi "github.com/trustbloc/sidetree-core-go/pkg/api/txn"
+
+	"github.com/trustbloc/orb/pkg/versions/1_0/txnprocessor"
+)
+
+// The stages of processing an anchor. The time spent in each stage is recorded in the
+// 'process_anchor_stage_seconds' metric and is reported to the AnchorTimingsListener.
+const (
+	// StageCASFetch is the stage in which the anchor event is retrieved from CAS.
+	StageCASFetch = "cas_fetch"
+	// StagePayloadParse is the stage in which the anchor payload is extracted from the anchor event.
+	StagePayloadParse = "payload_parse"
+	// StageCredentialVerification is the stage in which the anchor credential is parsed and its proofs are verified.
+	StageCredentialVerification = "credential_verification"
+	// StageParseOperations is the stage in which the Sidetree batch files are retrieved and the operations are parsed.
+	StageParseOperations = txnprocessor.StageParseOperations
+	// StageStoreOperations is the stage in which the operations are persisted to the operation store.
+	StageStoreOperations = txnprocessor.StageStoreOperations
+	// StageDIDAnchorStore is the stage in which the DID/anchor references are persisted.
+	StageDIDAnchorStore = "did_anchor_store"
+	// StageAnchorLinkStore is the stage in which the anchor link is persisted and the 'Like' activity is posted.
+	StageAnchorLinkStore = "anchor_link_store"
+)
+
+// StageTime contains the time spent in a stage of processing an anchor.
+type StageTime struct {
+	Stage    string
+	Duration time.Duration
+}
+
+// AnchorTimings contains the time spent in each stage of processing an anchor.
+type AnchorTimings struct {
+	Hashlink  string
+	StartTime time.Time
+	Total     time.Duration
+	Stages    []*StageTime
+	// Error is set if the anchor could not be processed.
+	Error string
+}
+
+// AnchorTimingsListener is notified of the time spent in each stage of processing an anchor.
+type AnchorTimingsListener interface {
+	AnchorTimings(timings *AnchorTimings)
+}
+
+type stagedTxnProcessor interface {
+	ProcessWithStages(sidetreeTxn txnapi.SidetreeTxn, observe txnprocessor.StageObserver, suffixes ...string) error
+}
+
+// stageTimer records the time spent in each stage of processing an anchor.
+type stageTimer struct {
+	metrics metricsProvider
+	stages  []*StageTime
+}
+
+func newStageTimer(metrics metricsProvider) *stageTimer {
+	return &stageTimer{metrics: metrics}
+}
+
+// record records the time elapsed since the given start time for the given stage.
+func (t *stageTimer) record(stage string, startTime time.Time) {
+	t.observe(stage, time.Since(startTime))
+}
+
+func (t *stageTimer) observe(stage string, value time.Duration) {
+	t.metrics.ProcessAnchorStageTime(stage, value)
+
+	t.stages = append(t.stages, &StageTime{Stage: stage, Duration: value})
+}
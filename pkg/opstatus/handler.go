@@ -0,0 +1,186 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package opstatus
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+)
+
+const (
+	// OperationIDHeader is the response header that holds the ID of a submitted operation. The ID may be
+	// used to query the anchoring status of the operation.
+	OperationIDHeader = "X-Orb-Operation-Id"
+
+	idParam = "id"
+
+	notFoundResponse            = "Not Found."
+	internalServerErrorResponse = "Internal Server Error."
+)
+
+type statusRetriever interface {
+	Get(id string) (*OperationStatus, error)
+}
+
+// Handler returns the anchoring status of an operation.
+type Handler struct {
+	basePath string
+	store    statusRetriever
+	marshal  func(v interface{}) ([]byte, error)
+}
+
+// NewHandler returns a new operation status handler. The endpoint of the handler is <basePath>/{id}/status.
+func NewHandler(basePath string, store statusRetriever) *Handler {
+	return &Handler{
+		basePath: basePath,
+		store:    store,
+		marshal:  json.Marshal,
+	}
+}
+
+// Path returns the HTTP REST endpoint for the operation status service.
+func (h *Handler) Path() string {
+	return fmt.Sprintf("%s/{%s}/status", h.basePath, idParam)
+}
+
+// Method returns the HTTP method, which is always GET.
+func (h *Handler) Method() string {
+	return http.MethodGet
+}
+
+// Handler returns the HTTP REST handle for the operation status service.
+func (h *Handler) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Handler) handle(w http.ResponseWriter, req *http.Request) {
+	id := mux.Vars(req)[idParam]
+
+	status, err := h.store.Get(id)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			logger.Debugf("[%s] Status not found for operation [%s]", h.Path(), id)
+
+			writeResponse(w, http.StatusNotFound, []byte(notFoundResponse))
+
+			return
+		}
+
+		logger.Errorf("[%s] Error retrieving status of operation [%s]: %s", h.Path(), id, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	statusBytes, err := h.marshal(status)
+	if err != nil {
+		logger.Errorf("[%s] Error marshalling status of operation [%s]: %s", h.Path(), id, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	writeResponse(w, http.StatusOK, statusBytes)
+}
+
+// HandlerWrapper wraps the operations handler and returns the ID of a successfully submitted operation in
+// the OperationIDHeader response header.
+type HandlerWrapper struct {
+	common.HTTPHandler
+
+	handleRequest common.HTTPRequestHandler
+}
+
+// NewHandlerWrapper returns a handler wrapper that returns the ID of a submitted operation.
+func NewHandlerWrapper(handler common.HTTPHandler) *HandlerWrapper {
+	return &HandlerWrapper{
+		HTTPHandler:   handler,
+		handleRequest: handler.Handler(),
+	}
+}
+
+// Handler returns the handler that should be invoked when an HTTP request is received.
+func (h *HandlerWrapper) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *HandlerWrapper) handle(w http.ResponseWriter, req *http.Request) {
+	if req.Body == nil {
+		h.handleRequest(w, req)
+
+		return
+	}
+
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		logger.Debugf("[%s] Error reading request body: %s", h.Path(), err)
+
+		writeResponse(w, http.StatusBadRequest, []byte(http.StatusText(http.StatusBadRequest)))
+
+		return
+	}
+
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	id, err := OperationID(body)
+	if err != nil {
+		logger.Warnf("[%s] Error calculating operation ID: %s", h.Path(), err)
+
+		h.handleRequest(w, req)
+
+		return
+	}
+
+	h.handleRequest(&idResponseWriter{ResponseWriter: w, id: id}, req)
+}
+
+// idResponseWriter sets the operation ID header if the request was successful.
+type idResponseWriter struct {
+	http.ResponseWriter
+
+	id          string
+	wroteHeader bool
+}
+
+func (w *idResponseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+
+		if status >= http.StatusOK && status < http.StatusMultipleChoices {
+			w.Header().Set(OperationIDHeader, w.id)
+		}
+	}
+
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *idResponseWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	return w.ResponseWriter.Write(p)
+}
+
+func writeResponse(w http.ResponseWriter, status int, body []byte) {
+	w.WriteHeader(status)
+
+	if _, err := w.Write(body); err != nil {
+		logger.Warnf("Unable to write response: %s", err)
+	}
+}
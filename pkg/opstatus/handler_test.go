@@ -0,0 +1,182 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package opstatus
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+)
+
+const basePath = "/sidetree/v1/operations"
+
+func TestHandler(t *testing.T) {
+	s := newTestStore(t)
+
+	require.NoError(t, s.Put(&OperationStatus{ID: "op1", Suffix: suffix1, Status: StatusQueued}))
+
+	h := NewHandler(basePath, s)
+	require.Equal(t, basePath+"/{id}/status", h.Path())
+	require.Equal(t, http.MethodGet, h.Method())
+	require.NotNil(t, h.Handler())
+
+	t.Run("success", func(t *testing.T) {
+		rw := getStatus(h, "op1")
+		require.Equal(t, http.StatusOK, rw.Code)
+		require.Equal(t, "application/json", rw.Header().Get("Content-Type"))
+
+		status := &OperationStatus{}
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), status))
+		require.Equal(t, "op1", status.ID)
+		require.Equal(t, StatusQueued, status.Status)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		require.Equal(t, http.StatusNotFound, getStatus(h, "op2").Code)
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		rw := getStatus(NewHandler(basePath, &mockStore{err: errors.New("injected store error")}), "op1")
+		require.Equal(t, http.StatusInternalServerError, rw.Code)
+	})
+
+	t.Run("marshal error", func(t *testing.T) {
+		h := NewHandler(basePath, s)
+		h.marshal = func(v interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		require.Equal(t, http.StatusInternalServerError, getStatus(h, "op1").Code)
+	})
+}
+
+func TestHandlerWrapper(t *testing.T) {
+	request := []byte(`{"type":"update"}`)
+
+	expectedID, err := OperationID(request)
+	require.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		var body []byte
+
+		h := NewHandlerWrapper(&mockHandler{status: http.StatusOK, handle: func(req *http.Request) {
+			body = readBody(t, req)
+		}})
+		require.Equal(t, basePath, h.Path())
+		require.Equal(t, http.MethodPost, h.Method())
+
+		rw := httptest.NewRecorder()
+
+		h.Handler()(rw, httptest.NewRequest(http.MethodPost, basePath, bytes.NewBuffer(request)))
+		require.Equal(t, http.StatusOK, rw.Code)
+		require.Equal(t, expectedID, rw.Header().Get(OperationIDHeader))
+		require.Equal(t, request, body)
+	})
+
+	t.Run("implicit status", func(t *testing.T) {
+		h := NewHandlerWrapper(&mockHandler{})
+
+		rw := httptest.NewRecorder()
+
+		h.Handler()(rw, httptest.NewRequest(http.MethodPost, basePath, bytes.NewBuffer(request)))
+		require.Equal(t, http.StatusOK, rw.Code)
+		require.Equal(t, expectedID, rw.Header().Get(OperationIDHeader))
+	})
+
+	t.Run("error response", func(t *testing.T) {
+		h := NewHandlerWrapper(&mockHandler{status: http.StatusBadRequest})
+
+		rw := httptest.NewRecorder()
+
+		h.Handler()(rw, httptest.NewRequest(http.MethodPost, basePath, bytes.NewBuffer(request)))
+		require.Equal(t, http.StatusBadRequest, rw.Code)
+		require.Empty(t, rw.Header().Get(OperationIDHeader))
+	})
+
+	t.Run("no body", func(t *testing.T) {
+		h := NewHandlerWrapper(&mockHandler{status: http.StatusOK})
+
+		req := httptest.NewRequest(http.MethodPost, basePath, nil)
+		req.Body = nil
+
+		rw := httptest.NewRecorder()
+
+		h.Handler()(rw, req)
+		require.Equal(t, http.StatusOK, rw.Code)
+		require.Empty(t, rw.Header().Get(OperationIDHeader))
+	})
+
+	t.Run("read error", func(t *testing.T) {
+		h := NewHandlerWrapper(&mockHandler{status: http.StatusOK})
+
+		req := httptest.NewRequest(http.MethodPost, basePath, &errReader{})
+
+		rw := httptest.NewRecorder()
+
+		h.Handler()(rw, req)
+		require.Equal(t, http.StatusBadRequest, rw.Code)
+	})
+}
+
+func getStatus(h *Handler, id string) *httptest.ResponseRecorder {
+	rw := httptest.NewRecorder()
+
+	req := httptest.NewRequest(http.MethodGet, basePath+"/"+id+"/status", nil)
+
+	h.Handler()(rw, mux.SetURLVars(req, map[string]string{idParam: id}))
+
+	return rw
+}
+
+func readBody(t *testing.T, req *http.Request) []byte {
+	t.Helper()
+
+	buf := &bytes.Buffer{}
+
+	_, err := buf.ReadFrom(req.Body)
+	require.NoError(t, err)
+
+	return buf.Bytes()
+}
+
+type mockHandler struct {
+	status int
+	handle func(req *http.Request)
+}
+
+func (m *mockHandler) Path() string {
+	return basePath
+}
+
+func (m *mockHandler) Method() string {
+	return http.MethodPost
+}
+
+func (m *mockHandler) Handler() common.HTTPRequestHandler {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if m.handle != nil {
+			m.handle(req)
+		}
+
+		if m.status != 0 {
+			w.WriteHeader(m.status)
+		}
+
+		_, _ = w.Write([]byte("response"))
+	}
+}
+
+type errReader struct{}
+
+func (r *errReader) Read([]byte) (int, error) {
+	return 0, errors.New("injected read error")
+}
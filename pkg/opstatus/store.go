@@ -0,0 +1,204 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package opstatus
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/edge-core/pkg/log"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/store/expiry"
+)
+
+var logger = log.New("operation-status")
+
+const (
+	storeName = "operation-status"
+
+	suffixTag    = "suffix"
+	coreIndexTag = "coreIndex"
+
+	// expiryTag holds the time (Unix time) after which the entry is deleted.
+	expiryTag = "expiry"
+)
+
+// ErrNotFound is returned when the status of an operation isn't found.
+var ErrNotFound = errors.New("operation status not found")
+
+// Status is the anchoring status of an operation.
+type Status string
+
+const (
+	// StatusQueued indicates that the operation is in the operation queue.
+	StatusQueued Status = "queued"
+	// StatusBatched indicates that the operation was removed from the queue and added to a batch.
+	StatusBatched Status = "batched"
+	// StatusAnchored indicates that the batch containing the operation was written by the anchor writer.
+	StatusAnchored Status = "anchored"
+	// StatusObserved indicates that the anchor containing the operation was processed by the observer.
+	StatusObserved Status = "observed"
+	// StatusRejected indicates that the operation was discarded from the queue.
+	StatusRejected Status = "rejected"
+)
+
+// Transition records the time at which an operation entered a status.
+type Transition struct {
+	Status    Status    `json:"status"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// OperationStatus contains the current anchoring status of an operation along with the history of
+// status transitions.
+type OperationStatus struct {
+	ID        string         `json:"id"`
+	Suffix    string         `json:"suffix"`
+	Type      operation.Type `json:"type"`
+	Status    Status         `json:"status"`
+	CoreIndex string         `json:"coreIndex,omitempty"`
+	Anchor    string         `json:"anchor,omitempty"`
+	Reason    string         `json:"reason,omitempty"`
+	History   []*Transition  `json:"history"`
+}
+
+type expiryService interface {
+	Register(store storage.Store, expiryTagName, storeName string, opts ...expiry.Option)
+}
+
+// Store stores the anchoring status of operations. Entries are deleted by the expiry service once the
+// status hasn't changed within the configured lifespan.
+type Store struct {
+	store    storage.Store
+	lifespan time.Duration
+}
+
+// NewStore returns a new operation status store.
+func NewStore(provider storage.Provider, expiryService expiryService, lifespan time.Duration) (*Store, error) {
+	s, err := provider.OpenStore(storeName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open operation status store: %w", err)
+	}
+
+	err = provider.SetStoreConfig(storeName,
+		storage.StoreConfiguration{TagNames: []string{suffixTag, coreIndexTag, expiryTag}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set store configuration: %w", err)
+	}
+
+	expiryService.Register(s, expiryTag, storeName)
+
+	return &Store{
+		store:    s,
+		lifespan: lifespan,
+	}, nil
+}
+
+// Put stores the given operation status.
+func (s *Store) Put(status *OperationStatus) error {
+	statusBytes, err := json.Marshal(status)
+	if err != nil {
+		return fmt.Errorf("marshal status of operation [%s]: %w", status.ID, err)
+	}
+
+	tags := []storage.Tag{
+		{Name: suffixTag, Value: status.Suffix},
+		{Name: expiryTag, Value: strconv.FormatInt(time.Now().Add(s.lifespan).Unix(), 10)},
+	}
+
+	if status.CoreIndex != "" {
+		tags = append(tags, storage.Tag{Name: coreIndexTag, Value: encode(status.CoreIndex)})
+	}
+
+	err = s.store.Put(status.ID, statusBytes, tags...)
+	if err != nil {
+		return orberrors.NewTransientf("store status of operation [%s]: %w", status.ID, err)
+	}
+
+	return nil
+}
+
+// Get returns the status of the operation with the given ID or ErrNotFound if no status was stored.
+func (s *Store) Get(id string) (*OperationStatus, error) {
+	statusBytes, err := s.store.Get(id)
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return nil, ErrNotFound
+		}
+
+		return nil, orberrors.NewTransientf("get status of operation [%s]: %w", id, err)
+	}
+
+	status := &OperationStatus{}
+
+	err = json.Unmarshal(statusBytes, status)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal status of operation [%s]: %w", id, err)
+	}
+
+	return status, nil
+}
+
+// GetBySuffix returns the status of all operations for the given DID suffix.
+func (s *Store) GetBySuffix(suffix string) ([]*OperationStatus, error) {
+	return s.query(suffixTag, suffix)
+}
+
+// GetByCoreIndex returns the status of all operations in the batch with the given core index.
+func (s *Store) GetByCoreIndex(coreIndex string) ([]*OperationStatus, error) {
+	return s.query(coreIndexTag, encode(coreIndex))
+}
+
+func (s *Store) query(tag, value string) ([]*OperationStatus, error) {
+	it, err := s.store.Query(fmt.Sprintf("%s:%s", tag, value))
+	if err != nil {
+		return nil, orberrors.NewTransientf("query operation status [%s:%s]: %w", tag, value, err)
+	}
+
+	defer storage.Close(it, logger)
+
+	var statuses []*OperationStatus
+
+	for {
+		ok, e := it.Next()
+		if e != nil {
+			return nil, orberrors.NewTransientf("query next operation status [%s:%s]: %w", tag, value, e)
+		}
+
+		if !ok {
+			break
+		}
+
+		statusBytes, e := it.Value()
+		if e != nil {
+			return nil, orberrors.NewTransientf("get operation status [%s:%s]: %w", tag, value, e)
+		}
+
+		status := &OperationStatus{}
+
+		e = json.Unmarshal(statusBytes, status)
+		if e != nil {
+			logger.Warnf("Error unmarshalling operation status: %s. The item will be ignored.", e)
+
+			continue
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+func encode(value string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(value))
+}
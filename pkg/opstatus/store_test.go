@@ -0,0 +1,183 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package opstatus
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/stretchr/testify/require"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/store/expiry"
+	"github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+const (
+	suffix1    = "EiDOQXC2GnoVyHwIRbjhLx_cNc6vmZaS04SZjZdlLLAPRg"
+	suffix2    = "EiA329wd6Aj36YRmp7NGkeB5ADnVt8ARdMZMPzfXsjwXYz"
+	coreIndex1 = "hl:uEiAFRFMd0Fbvu2u1E0l8Q4kAYKHRLoBpGvt0f6hGh5QFzA"
+)
+
+func TestNewStore(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		es := &mockExpiryService{}
+
+		s, err := NewStore(mem.NewProvider(), es, time.Minute)
+		require.NoError(t, err)
+		require.NotNil(t, s)
+		require.Equal(t, expiryTag, es.expiryTagName)
+		require.Equal(t, storeName, es.storeName)
+	})
+
+	t.Run("open store error", func(t *testing.T) {
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(nil, errors.New("injected open error"))
+
+		_, err := NewStore(provider, &mockExpiryService{}, time.Minute)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected open error")
+	})
+
+	t.Run("set store config error", func(t *testing.T) {
+		provider := &mocks.Provider{}
+		provider.SetStoreConfigReturns(errors.New("injected config error"))
+
+		_, err := NewStore(provider, &mockExpiryService{}, time.Minute)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected config error")
+	})
+}
+
+func TestStore(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		s, err := NewStore(mem.NewProvider(), &mockExpiryService{}, time.Minute)
+		require.NoError(t, err)
+
+		_, err = s.Get("op1")
+		require.ErrorIs(t, err, ErrNotFound)
+
+		require.NoError(t, s.Put(&OperationStatus{ID: "op1", Suffix: suffix1, Status: StatusQueued}))
+		require.NoError(t, s.Put(&OperationStatus{ID: "op2", Suffix: suffix1, Status: StatusBatched,
+			CoreIndex: coreIndex1}))
+		require.NoError(t, s.Put(&OperationStatus{ID: "op3", Suffix: suffix2, Status: StatusBatched,
+			CoreIndex: coreIndex1}))
+
+		status, err := s.Get("op1")
+		require.NoError(t, err)
+		require.Equal(t, suffix1, status.Suffix)
+		require.Equal(t, StatusQueued, status.Status)
+
+		statuses, err := s.GetBySuffix(suffix1)
+		require.NoError(t, err)
+		require.Len(t, statuses, 2)
+
+		statuses, err = s.GetByCoreIndex(coreIndex1)
+		require.NoError(t, err)
+		require.Len(t, statuses, 2)
+
+		// Clearing the core index removes the tag.
+		require.NoError(t, s.Put(&OperationStatus{ID: "op2", Suffix: suffix1, Status: StatusQueued}))
+
+		statuses, err = s.GetByCoreIndex(coreIndex1)
+		require.NoError(t, err)
+		require.Len(t, statuses, 1)
+		require.Equal(t, "op3", statuses[0].ID)
+	})
+
+	t.Run("store errors", func(t *testing.T) {
+		errExpected := errors.New("injected store error")
+
+		store := &mocks.Store{}
+		store.PutReturns(errExpected)
+		store.GetReturns(nil, errExpected)
+		store.QueryReturns(nil, errExpected)
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := NewStore(provider, &mockExpiryService{}, time.Minute)
+		require.NoError(t, err)
+
+		err = s.Put(&OperationStatus{ID: "op1"})
+		require.ErrorIs(t, err, errExpected)
+		require.True(t, orberrors.IsTransient(err))
+
+		_, err = s.Get("op1")
+		require.ErrorIs(t, err, errExpected)
+		require.True(t, orberrors.IsTransient(err))
+
+		_, err = s.GetBySuffix(suffix1)
+		require.ErrorIs(t, err, errExpected)
+		require.True(t, orberrors.IsTransient(err))
+	})
+
+	t.Run("iterator errors", func(t *testing.T) {
+		errExpected := errors.New("injected iterator error")
+
+		it := &mocks.Iterator{}
+		it.NextReturns(false, errExpected)
+
+		store := &mocks.Store{}
+		store.QueryReturns(it, nil)
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := NewStore(provider, &mockExpiryService{}, time.Minute)
+		require.NoError(t, err)
+
+		_, err = s.GetByCoreIndex(coreIndex1)
+		require.ErrorIs(t, err, errExpected)
+
+		it.NextReturns(true, nil)
+		it.ValueReturns(nil, errExpected)
+
+		_, err = s.GetByCoreIndex(coreIndex1)
+		require.ErrorIs(t, err, errExpected)
+	})
+
+	t.Run("unmarshal error", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.GetReturns([]byte("{"), nil)
+
+		it := &mocks.Iterator{}
+		it.NextReturnsOnCall(0, true, nil)
+		it.NextReturnsOnCall(1, false, nil)
+		it.ValueReturns([]byte("{"), nil)
+
+		store.QueryReturns(it, nil)
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := NewStore(provider, &mockExpiryService{}, time.Minute)
+		require.NoError(t, err)
+
+		_, err = s.Get("op1")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal status of operation")
+
+		// Invalid items are ignored.
+		statuses, err := s.GetBySuffix(suffix1)
+		require.NoError(t, err)
+		require.Empty(t, statuses)
+	})
+}
+
+type mockExpiryService struct {
+	expiryTagName string
+	storeName     string
+}
+
+func (m *mockExpiryService) Register(_ storage.Store, expiryTagName, storeName string, _ ...expiry.Option) {
+	m.expiryTagName = expiryTagName
+	m.storeName = storeName
+}
@@ -0,0 +1,311 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package opstatus
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+	"github.com/trustbloc/sidetree-core-go/pkg/batch"
+	"github.com/trustbloc/sidetree-core-go/pkg/batch/cutter"
+	"github.com/trustbloc/sidetree-core-go/pkg/hashing"
+
+	"github.com/trustbloc/orb/pkg/anchor/util"
+	documentutil "github.com/trustbloc/orb/pkg/document/util"
+	"github.com/trustbloc/orb/pkg/observer"
+)
+
+const sha2_256 = 18
+
+type statusStore interface {
+	Put(status *OperationStatus) error
+	Get(id string) (*OperationStatus, error)
+	GetBySuffix(suffix string) ([]*OperationStatus, error)
+	GetByCoreIndex(coreIndex string) ([]*OperationStatus, error)
+}
+
+// Tracker tracks the anchoring status of operations. The status is updated from events of the operation
+// queue (queued, batched), the anchor writer (anchored) and the observer (observed). An operation that is
+// discarded from the queue is marked as rejected.
+//
+// Errors are logged and don't affect the processing of the operation.
+type Tracker struct {
+	store statusStore
+	mutex sync.Mutex
+	now   func() time.Time
+}
+
+// NewTracker returns a new operation status tracker.
+func NewTracker(store statusStore) *Tracker {
+	return &Tracker{
+		store: store,
+		now:   time.Now,
+	}
+}
+
+// OperationID returns the ID of the given operation request, which is the multihash of the request.
+func OperationID(request []byte) (string, error) {
+	return hashing.CalculateModelMultihash(request, sha2_256)
+}
+
+// OperationQueue wraps the given operation queue so that operations are tracked as they're added to the queue
+// and removed from the queue in a batch.
+func (t *Tracker) OperationQueue(q cutter.OperationQueue) cutter.OperationQueue {
+	return &operationQueue{OperationQueue: q, tracker: t}
+}
+
+// AnchorWriter wraps the given anchor writer so that operations are tracked as their batch is anchored.
+func (t *Tracker) AnchorWriter(w batch.AnchorWriter) batch.AnchorWriter {
+	return &anchorWriter{AnchorWriter: w, tracker: t}
+}
+
+// AnchorProcessed marks the operations in the processed anchor as observed.
+func (t *Tracker) AnchorProcessed(anchor *observer.ProcessedAnchor) {
+	if anchor.CoreIndex == "" {
+		return
+	}
+
+	t.updateByCoreIndex(anchor.CoreIndex, StatusObserved, func(s *OperationStatus) {
+		s.Anchor = anchor.Hashlink
+	}, StatusBatched, StatusAnchored)
+}
+
+// OperationRejected marks the given operation as rejected.
+func (t *Tracker) OperationRejected(op *operation.QueuedOperation, reason string) {
+	t.updateOperation(op.OperationRequest, StatusRejected, func(s *OperationStatus) {
+		s.Reason = reason
+	}, StatusQueued, StatusBatched)
+}
+
+// operationQueued creates (or resets) the status of the given operation. An operation that was rejected
+// may be submitted again, in which case its history is retained.
+func (t *Tracker) operationQueued(op *operation.QueuedOperation) {
+	id, err := OperationID(op.OperationRequest)
+	if err != nil {
+		logger.Warnf("Error calculating ID of operation for suffix [%s]: %s", op.UniqueSuffix, err)
+
+		return
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	status, err := t.store.Get(id)
+	if err != nil {
+		if !errors.Is(err, ErrNotFound) {
+			logger.Warnf("Error retrieving status of operation [%s]: %s", id, err)
+
+			return
+		}
+
+		opType, e := documentutil.GetOperationType(op.OperationRequest)
+		if e != nil {
+			logger.Debugf("Unable to determine the type of operation [%s]: %s", id, e)
+		}
+
+		status = &OperationStatus{
+			ID:     id,
+			Suffix: op.UniqueSuffix,
+			Type:   opType,
+		}
+	}
+
+	t.transition(status, StatusQueued, func(s *OperationStatus) {
+		s.CoreIndex = ""
+		s.Anchor = ""
+		s.Reason = ""
+	})
+}
+
+func (t *Tracker) operationsBatched(ops operation.QueuedOperationsAtTime) {
+	for _, op := range ops {
+		t.updateOperation(op.OperationRequest, StatusBatched, nil, StatusQueued)
+	}
+}
+
+func (t *Tracker) operationsRequeued(ops operation.QueuedOperationsAtTime) {
+	for _, op := range ops {
+		t.updateOperation(op.OperationRequest, StatusQueued, func(s *OperationStatus) {
+			s.CoreIndex = ""
+		}, StatusBatched)
+	}
+}
+
+// batchAnchoring sets the core index on the batched operations of the given suffixes before the batch is
+// written so that the operations may be found by the observer, which may process the anchor before the
+// anchor writer returns.
+func (t *Tracker) batchAnchoring(coreIndex string, refs []*operation.Reference) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	for _, ref := range refs {
+		statuses, err := t.store.GetBySuffix(ref.UniqueSuffix)
+		if err != nil {
+			logger.Warnf("Error retrieving status of operations for suffix [%s]: %s", ref.UniqueSuffix, err)
+
+			continue
+		}
+
+		for _, s := range statuses {
+			if s.Status != StatusBatched || s.CoreIndex != "" {
+				continue
+			}
+
+			s.CoreIndex = coreIndex
+
+			if e := t.store.Put(s); e != nil {
+				logger.Warnf("Error storing status of operation [%s]: %s", s.ID, e)
+			}
+		}
+	}
+}
+
+func (t *Tracker) batchAnchored(coreIndex string) {
+	t.updateByCoreIndex(coreIndex, StatusAnchored, nil, StatusBatched)
+}
+
+func (t *Tracker) updateOperation(request []byte, status Status, update func(s *OperationStatus),
+	allowedFrom ...Status) {
+	id, err := OperationID(request)
+	if err != nil {
+		logger.Warnf("Error calculating ID of operation: %s", err)
+
+		return
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	s, err := t.store.Get(id)
+	if err != nil {
+		logger.Debugf("Status of operation [%s] not updated to [%s]: %s", id, status, err)
+
+		return
+	}
+
+	if contains(allowedFrom, s.Status) {
+		t.transition(s, status, update)
+	}
+}
+
+func (t *Tracker) updateByCoreIndex(coreIndex string, status Status, update func(s *OperationStatus),
+	allowedFrom ...Status) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	statuses, err := t.store.GetByCoreIndex(coreIndex)
+	if err != nil {
+		logger.Warnf("Error retrieving status of operations for core index [%s]: %s", coreIndex, err)
+
+		return
+	}
+
+	for _, s := range statuses {
+		if contains(allowedFrom, s.Status) {
+			t.transition(s, status, update)
+		}
+	}
+}
+
+// transition sets the status of the operation, records the transition in the history and stores the
+// operation status. The caller must hold the lock.
+func (t *Tracker) transition(s *OperationStatus, status Status, update func(s *OperationStatus)) {
+	s.Status = status
+	s.History = append(s.History, &Transition{Status: status, Timestamp: t.now()})
+
+	if update != nil {
+		update(s)
+	}
+
+	err := t.store.Put(s)
+	if err != nil {
+		logger.Warnf("Error storing status of operation [%s]: %s", s.ID, err)
+
+		return
+	}
+
+	logger.Debugf("Status of operation [%s] for suffix [%s] is [%s]", s.ID, s.Suffix, status)
+}
+
+func contains(statuses []Status, status Status) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+
+	return false
+}
+
+type operationQueue struct {
+	cutter.OperationQueue
+
+	tracker *Tracker
+}
+
+// Add adds the given operation to the queue and marks the operation as queued.
+func (q *operationQueue) Add(op *operation.QueuedOperation, protocolVersion uint64) (uint, error) {
+	q.tracker.operationQueued(op)
+
+	n, err := q.OperationQueue.Add(op, protocolVersion)
+	if err != nil {
+		q.tracker.OperationRejected(op, err.Error())
+
+		return 0, err
+	}
+
+	return n, nil
+}
+
+// Remove removes (up to) the given number of operations from the queue and marks them as batched. If
+// the batch fails (nack) then the operations are marked as queued again.
+func (q *operationQueue) Remove(num uint) (ops operation.QueuedOperationsAtTime, ack func() uint, nack func(),
+	err error) {
+	ops, ack, queueNack, err := q.OperationQueue.Remove(num)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	q.tracker.operationsBatched(ops)
+
+	return ops, ack, func() {
+		queueNack()
+
+		q.tracker.operationsRequeued(ops)
+	}, nil
+}
+
+type anchorWriter struct {
+	batch.AnchorWriter
+
+	tracker *Tracker
+}
+
+// WriteAnchor writes the anchor and marks the operations in the batch as anchored.
+func (w *anchorWriter) WriteAnchor(anchor string, attachments []*protocol.AnchorDocument,
+	refs []*operation.Reference, version uint64) error {
+	ad, err := util.ParseAnchorString(anchor)
+	if err != nil {
+		logger.Warnf("Error parsing anchor string [%s]: %s", anchor, err)
+
+		return w.AnchorWriter.WriteAnchor(anchor, attachments, refs, version)
+	}
+
+	w.tracker.batchAnchoring(ad.CoreIndexFileURI, refs)
+
+	err = w.AnchorWriter.WriteAnchor(anchor, attachments, refs, version)
+	if err != nil {
+		return err
+	}
+
+	w.tracker.batchAnchored(ad.CoreIndexFileURI)
+
+	return nil
+}
@@ -0,0 +1,297 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package opstatus
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+	txnapi "github.com/trustbloc/sidetree-core-go/pkg/api/txn"
+
+	"github.com/trustbloc/orb/pkg/observer"
+)
+
+const (
+	anchor1   = "2." + coreIndex1
+	hashlink1 = "hl:uEiBy2SOiDS8Z8gkZDt8cHbQGbvP9MQt2YqH1YjzW8Zv1KA"
+)
+
+func TestTracker(t *testing.T) {
+	op1 := newQueuedOperation(suffix1, "1")
+	op2 := newQueuedOperation(suffix2, "2")
+
+	t.Run("queued -> batched -> anchored -> observed", func(t *testing.T) {
+		s := newTestStore(t)
+		tracker := NewTracker(s)
+
+		q := tracker.OperationQueue(&mockQueue{})
+		w := tracker.AnchorWriter(&mockAnchorWriter{})
+
+		_, err := q.Add(op1, 1)
+		require.NoError(t, err)
+		_, err = q.Add(op2, 1)
+		require.NoError(t, err)
+
+		requireStatus(t, s, op1, StatusQueued)
+
+		ops, _, _, err := q.Remove(2)
+		require.NoError(t, err)
+		require.Len(t, ops, 2)
+
+		requireStatus(t, s, op1, StatusBatched)
+
+		require.NoError(t, w.WriteAnchor(anchor1, nil, refs(op1, op2), 1))
+
+		status := requireStatus(t, s, op2, StatusAnchored)
+		require.Equal(t, coreIndex1, status.CoreIndex)
+
+		tracker.AnchorProcessed(&observer.ProcessedAnchor{Hashlink: hashlink1, CoreIndex: coreIndex1})
+
+		status = requireStatus(t, s, op1, StatusObserved)
+		require.Equal(t, hashlink1, status.Anchor)
+		require.Len(t, status.History, 4)
+		require.Equal(t, StatusQueued, status.History[0].Status)
+		require.Equal(t, StatusBatched, status.History[1].Status)
+		require.Equal(t, StatusAnchored, status.History[2].Status)
+		require.Equal(t, StatusObserved, status.History[3].Status)
+
+		// Observed is a final status.
+		tracker.OperationRejected(op1, "rejected")
+		requireStatus(t, s, op1, StatusObserved)
+
+		// Anchors without a core index are ignored.
+		tracker.AnchorProcessed(&observer.ProcessedAnchor{Hashlink: hashlink1})
+	})
+
+	t.Run("observed before the anchor writer returns", func(t *testing.T) {
+		s := newTestStore(t)
+		tracker := NewTracker(s)
+
+		q := tracker.OperationQueue(&mockQueue{})
+
+		w := tracker.AnchorWriter(&mockAnchorWriter{
+			write: func() {
+				tracker.AnchorProcessed(&observer.ProcessedAnchor{Hashlink: hashlink1, CoreIndex: coreIndex1})
+			},
+		})
+
+		_, err := q.Add(op1, 1)
+		require.NoError(t, err)
+
+		_, _, _, err = q.Remove(1)
+		require.NoError(t, err)
+
+		require.NoError(t, w.WriteAnchor(anchor1, nil, refs(op1), 1))
+
+		requireStatus(t, s, op1, StatusObserved)
+	})
+
+	t.Run("nack -> queued", func(t *testing.T) {
+		s := newTestStore(t)
+		tracker := NewTracker(s)
+
+		q := tracker.OperationQueue(&mockQueue{})
+		w := tracker.AnchorWriter(&mockAnchorWriter{err: errors.New("injected write error")})
+
+		_, err := q.Add(op1, 1)
+		require.NoError(t, err)
+
+		_, _, nack, err := q.Remove(1)
+		require.NoError(t, err)
+
+		require.Error(t, w.WriteAnchor(anchor1, nil, refs(op1), 1))
+
+		nack()
+
+		status := requireStatus(t, s, op1, StatusQueued)
+		require.Empty(t, status.CoreIndex)
+		require.Len(t, status.History, 3)
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		s := newTestStore(t)
+		tracker := NewTracker(s)
+
+		_, err := tracker.OperationQueue(&mockQueue{}).Add(op1, 1)
+		require.NoError(t, err)
+
+		tracker.OperationRejected(op1, "max retries reached")
+
+		status := requireStatus(t, s, op1, StatusRejected)
+		require.Equal(t, "max retries reached", status.Reason)
+
+		// The operation may be submitted again.
+		_, err = tracker.OperationQueue(&mockQueue{}).Add(op1, 1)
+		require.NoError(t, err)
+
+		status = requireStatus(t, s, op1, StatusQueued)
+		require.Empty(t, status.Reason)
+		require.Len(t, status.History, 3)
+	})
+
+	t.Run("add error -> rejected", func(t *testing.T) {
+		s := newTestStore(t)
+		tracker := NewTracker(s)
+
+		_, err := tracker.OperationQueue(&mockQueue{err: errors.New("injected add error")}).Add(op1, 1)
+		require.Error(t, err)
+
+		status := requireStatus(t, s, op1, StatusRejected)
+		require.Contains(t, status.Reason, "injected add error")
+	})
+
+	t.Run("remove error", func(t *testing.T) {
+		_, _, _, err := NewTracker(newTestStore(t)).OperationQueue(&mockQueue{err: errors.New("injected error")}).
+			Remove(1)
+		require.Error(t, err)
+	})
+
+	t.Run("invalid anchor string", func(t *testing.T) {
+		s := newTestStore(t)
+
+		require.NoError(t, NewTracker(s).AnchorWriter(&mockAnchorWriter{}).WriteAnchor("invalid", nil, refs(op1), 1))
+	})
+
+	t.Run("store errors", func(t *testing.T) {
+		s := &mockStore{err: errors.New("injected store error")}
+		tracker := NewTracker(s)
+
+		q := tracker.OperationQueue(&mockQueue{})
+
+		_, err := q.Add(op1, 1)
+		require.NoError(t, err)
+
+		_, _, _, err = q.Remove(1)
+		require.NoError(t, err)
+
+		require.NoError(t, tracker.AnchorWriter(&mockAnchorWriter{}).WriteAnchor(anchor1, nil, refs(op1), 1))
+
+		tracker.AnchorProcessed(&observer.ProcessedAnchor{Hashlink: hashlink1, CoreIndex: coreIndex1})
+	})
+}
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+
+	s, err := NewStore(mem.NewProvider(), &mockExpiryService{}, time.Minute)
+	require.NoError(t, err)
+
+	return s
+}
+
+func newQueuedOperation(suffix, value string) *operation.QueuedOperation {
+	return &operation.QueuedOperation{
+		UniqueSuffix:     suffix,
+		OperationRequest: []byte(fmt.Sprintf(`{"type":"update","didSuffix":"%s","value":"%s"}`, suffix, value)),
+	}
+}
+
+func refs(ops ...*operation.QueuedOperation) []*operation.Reference {
+	var r []*operation.Reference
+
+	for _, op := range ops {
+		r = append(r, &operation.Reference{UniqueSuffix: op.UniqueSuffix, Type: operation.TypeUpdate})
+	}
+
+	return r
+}
+
+func requireStatus(t *testing.T, s *Store, op *operation.QueuedOperation, expected Status) *OperationStatus {
+	t.Helper()
+
+	id, err := OperationID(op.OperationRequest)
+	require.NoError(t, err)
+
+	status, err := s.Get(id)
+	require.NoError(t, err)
+	require.Equal(t, expected, status.Status)
+	require.Equal(t, op.UniqueSuffix, status.Suffix)
+
+	return status
+}
+
+type mockQueue struct {
+	ops operation.QueuedOperationsAtTime
+	err error
+}
+
+func (m *mockQueue) Add(op *operation.QueuedOperation, _ uint64) (uint, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+
+	m.ops = append(m.ops, &operation.QueuedOperationAtTime{QueuedOperation: *op})
+
+	return uint(len(m.ops)), nil
+}
+
+func (m *mockQueue) Peek(uint) (operation.QueuedOperationsAtTime, error) {
+	return m.ops, m.err
+}
+
+func (m *mockQueue) Remove(num uint) (operation.QueuedOperationsAtTime, func() uint, func(), error) {
+	if m.err != nil {
+		return nil, nil, nil, m.err
+	}
+
+	n := int(num)
+	if len(m.ops) < n {
+		n = len(m.ops)
+	}
+
+	ops := m.ops[0:n]
+	m.ops = m.ops[n:]
+
+	return ops, func() uint { return uint(len(ops)) }, func() {}, nil
+}
+
+func (m *mockQueue) Len() uint {
+	return uint(len(m.ops))
+}
+
+type mockAnchorWriter struct {
+	write func()
+	err   error
+}
+
+func (m *mockAnchorWriter) WriteAnchor(string, []*protocol.AnchorDocument, []*operation.Reference, uint64) error {
+	if m.write != nil {
+		m.write()
+	}
+
+	return m.err
+}
+
+func (m *mockAnchorWriter) Read(int) (bool, *txnapi.SidetreeTxn) {
+	return false, nil
+}
+
+type mockStore struct {
+	err error
+}
+
+func (m *mockStore) Put(*OperationStatus) error {
+	return m.err
+}
+
+func (m *mockStore) Get(string) (*OperationStatus, error) {
+	return nil, m.err
+}
+
+func (m *mockStore) GetBySuffix(string) ([]*OperationStatus, error) {
+	return nil, m.err
+}
+
+func (m *mockStore) GetByCoreIndex(string) ([]*OperationStatus, error) {
+	return nil, m.err
+}
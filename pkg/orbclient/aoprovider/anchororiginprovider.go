@@ -69,7 +69,7 @@ func WithDisableProofCheck(disableProofCheck bool) Option {
 
 // New creates new Orb client.
 func New(namespace string, cas common.CASReader, opts ...Option) (*OrbClient, error) {
-	versions := []string{"1.0"}
+	versions := []string{clientregistry.V1_0, clientregistry.V1_1}
 
 	registry := clientregistry.New()
 
@@ -0,0 +1,315 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package orbclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/trustbloc/edge-core/pkg/log"
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
+
+	"github.com/trustbloc/orb/pkg/document/protocolhandler"
+	"github.com/trustbloc/orb/pkg/orbclient/resolutionverifier"
+)
+
+var logger = log.New("orbclient")
+
+const (
+	operationsPath         = "/sidetree/v1/operations"
+	identifiersPath        = "/sidetree/v1/identifiers"
+	protocolParametersPath = "/sidetree/v1/protocol-parameters"
+
+	defaultNamespace       = "did:orb"
+	defaultServicePath     = "/services/orb"
+	defaultMaxRetries      = 3
+	defaultInitialInterval = 500 * time.Millisecond
+	defaultMaxInterval     = 5 * time.Second
+	defaultRequestTimeout  = 30 * time.Second
+
+	sha2_256 = 18
+
+	contentTypeJSON = "application/json"
+)
+
+type httpClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// HTTPError is returned when the Orb node responds with an unexpected status code.
+type HTTPError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("status code %d: %s", e.StatusCode, e.Body)
+}
+
+// Client is a client for an Orb node. It creates, updates, recovers, deactivates and resolves DIDs, verifies
+// resolution results and queries the federation (followers, following, witnesses, witnessing) of the node.
+//
+// Requests that fail with a network error or a 5xx/429 status code are retried with exponential backoff.
+type Client struct {
+	endpoint        string
+	httpClient      httpClient
+	authToken       string
+	namespace       string
+	servicePath     string
+	multihashCode   uint
+	maxRetries      int
+	initialInterval time.Duration
+	maxInterval     time.Duration
+	requestTimeout  time.Duration
+	verifierOpts    []resolutionverifier.Option
+	verifier        *resolutionverifier.ResolutionVerifier
+}
+
+// Option is a client option.
+type Option func(c *Client)
+
+// WithHTTPClient sets the HTTP client. The default is http.DefaultClient.
+func WithHTTPClient(client httpClient) Option {
+	return func(c *Client) {
+		c.httpClient = client
+	}
+}
+
+// WithAuthToken sets the bearer token that's sent in the Authorization header of each request.
+func WithAuthToken(token string) Option {
+	return func(c *Client) {
+		c.authToken = token
+	}
+}
+
+// WithNamespace sets the DID namespace. The default is did:orb.
+func WithNamespace(namespace string) Option {
+	return func(c *Client) {
+		c.namespace = namespace
+	}
+}
+
+// WithServicePath sets the path of the ActivityPub service of the node. The default is /services/orb.
+func WithServicePath(path string) Option {
+	return func(c *Client) {
+		c.servicePath = path
+	}
+}
+
+// WithMultihashCode sets the multihash code that's used to calculate commitments and reveal values. The
+// default is SHA2-256.
+func WithMultihashCode(code uint) Option {
+	return func(c *Client) {
+		c.multihashCode = code
+	}
+}
+
+// WithMaxRetries sets the maximum number of times that a failed request is retried. The default is 3.
+func WithMaxRetries(n int) Option {
+	return func(c *Client) {
+		c.maxRetries = n
+	}
+}
+
+// WithBackoff sets the initial and maximum interval between retries. The interval is doubled after each retry.
+func WithBackoff(initialInterval, maxInterval time.Duration) Option {
+	return func(c *Client) {
+		c.initialInterval = initialInterval
+		c.maxInterval = maxInterval
+	}
+}
+
+// WithRequestTimeout sets the timeout of each request (attempt). The default is 30 seconds.
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.requestTimeout = timeout
+	}
+}
+
+// WithResolutionVerifierOptions sets the options of the resolution verifier that's used by VerifyDID.
+func WithResolutionVerifierOptions(opts ...resolutionverifier.Option) Option {
+	return func(c *Client) {
+		c.verifierOpts = opts
+	}
+}
+
+// New returns a new client for the Orb node at the given endpoint (for example, https://orb.domain1.com).
+func New(endpoint string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid endpoint [%s]", endpoint)
+	}
+
+	c := &Client{
+		endpoint:        strings.TrimSuffix(endpoint, "/"),
+		httpClient:      http.DefaultClient,
+		namespace:       defaultNamespace,
+		servicePath:     defaultServicePath,
+		multihashCode:   sha2_256,
+		maxRetries:      defaultMaxRetries,
+		initialInterval: defaultInitialInterval,
+		maxInterval:     defaultMaxInterval,
+		requestTimeout:  defaultRequestTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	c.verifier, err = resolutionverifier.New(c.namespace, c.verifierOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("create resolution verifier: %w", err)
+	}
+
+	return c, nil
+}
+
+// ResolveDID resolves the given DID.
+func (c *Client) ResolveDID(did string) (*document.ResolutionResult, error) {
+	respBytes, err := c.get(c.endpoint + identifiersPath + "/" + did)
+	if err != nil {
+		return nil, fmt.Errorf("resolve DID [%s]: %w", did, err)
+	}
+
+	result := &document.ResolutionResult{}
+
+	err = json.Unmarshal(respBytes, result)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal resolution result for DID [%s]: %w", did, err)
+	}
+
+	return result, nil
+}
+
+// VerifyDID resolves the given DID and verifies that the resolved document is reproduced by the (anchored
+// and unanchored) operations in the document metadata. The node must be configured to include operations
+// in the resolution result.
+func (c *Client) VerifyDID(did string) (*document.ResolutionResult, error) {
+	result, err := c.ResolveDID(did)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.verifier.Verify(result)
+	if err != nil {
+		return nil, fmt.Errorf("verify DID [%s]: %w", did, err)
+	}
+
+	return result, nil
+}
+
+// ProtocolParameters contains the parameters of the node's active Sidetree protocol version.
+type ProtocolParameters = protocolhandler.ParametersResponse
+
+// ProtocolParameters returns the parameters (such as the maximum operation size) of the node's active
+// Sidetree protocol version.
+func (c *Client) ProtocolParameters() (*ProtocolParameters, error) {
+	respBytes, err := c.get(c.endpoint + protocolParametersPath)
+	if err != nil {
+		return nil, fmt.Errorf("get protocol parameters: %w", err)
+	}
+
+	params := &ProtocolParameters{}
+
+	err = json.Unmarshal(respBytes, params)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal protocol parameters: %w", err)
+	}
+
+	return params, nil
+}
+
+func (c *Client) get(u string) ([]byte, error) {
+	return c.do(http.MethodGet, u, nil)
+}
+
+func (c *Client) post(u string, body []byte) ([]byte, error) {
+	return c.do(http.MethodPost, u, body)
+}
+
+func (c *Client) do(method, u string, body []byte) ([]byte, error) {
+	b := backoff.NewExponentialBackOff()
+	b.InitialInterval = c.initialInterval
+	b.MaxInterval = c.maxInterval
+	b.Multiplier = 2
+	b.MaxElapsedTime = 0
+
+	var respBytes []byte
+
+	err := backoff.RetryNotify(
+		func() error {
+			var e error
+
+			respBytes, e = c.send(method, u, body)
+
+			return e
+		},
+		backoff.WithMaxRetries(b, uint64(c.maxRetries)),
+		func(err error, duration time.Duration) {
+			logger.Debugf("Request %s %s failed. Retrying in %s: %s", method, u, duration, err)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return respBytes, nil
+}
+
+func (c *Client) send(method, u string, body []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.requestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, backoff.Permanent(fmt.Errorf("create request: %w", err))
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", contentTypeJSON)
+	}
+
+	if c.authToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.authToken)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", method, u, err)
+	}
+
+	defer func() {
+		if e := resp.Body.Close(); e != nil {
+			logger.Warnf("Error closing response body: %s", e)
+		}
+	}()
+
+	respBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response of %s %s: %w", method, u, err)
+	}
+
+	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
+		return respBytes, nil
+	}
+
+	httpErr := &HTTPError{StatusCode: resp.StatusCode, Body: string(respBytes)}
+
+	if resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests {
+		return nil, httpErr
+	}
+
+	return nil, backoff.Permanent(httpErr)
+}
@@ -0,0 +1,233 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package orbclient
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/orbclient/resolutionverifier"
+)
+
+const (
+	testDID   = "did:orb:uAAA:EiDOQXC2GnoVyHwIRbjhLx_cNc6vmZaS04SZjZdlLLAPRg"
+	authToken = "TOKEN"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		c, err := New("https://orb.domain1.com/",
+			WithHTTPClient(http.DefaultClient),
+			WithAuthToken(authToken),
+			WithNamespace("did:orb"),
+			WithServicePath("/services/orb"),
+			WithMultihashCode(sha2_256),
+			WithMaxRetries(1),
+			WithBackoff(time.Millisecond, 10*time.Millisecond),
+			WithRequestTimeout(time.Second),
+			WithResolutionVerifierOptions(resolutionverifier.WithAnchorOrigins([]string{"*"})),
+		)
+		require.NoError(t, err)
+		require.Equal(t, "https://orb.domain1.com", c.endpoint)
+		require.Equal(t, authToken, c.authToken)
+		require.Equal(t, 1, c.maxRetries)
+	})
+
+	t.Run("invalid endpoint", func(t *testing.T) {
+		for _, endpoint := range []string{"", "orb.domain1.com", "https://", ":"} {
+			_, err := New(endpoint)
+			require.Errorf(t, err, endpoint)
+			require.Contains(t, err.Error(), "invalid endpoint")
+		}
+	})
+}
+
+func TestClient_ResolveDID(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		var authHeader string
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			require.Equal(t, http.MethodGet, req.Method)
+			require.Equal(t, identifiersPath+"/"+testDID, req.URL.Path)
+
+			authHeader = req.Header.Get("Authorization")
+
+			writeResponse(t, w, http.StatusOK, `{"didDocument":{"id":"`+testDID+`"}}`)
+		}))
+		defer srv.Close()
+
+		c := newTestClient(t, srv.URL, WithAuthToken(authToken))
+
+		result, err := c.ResolveDID(testDID)
+		require.NoError(t, err)
+		require.Equal(t, testDID, result.Document.ID())
+		require.Equal(t, "Bearer "+authToken, authHeader)
+	})
+
+	t.Run("retry -> success", func(t *testing.T) {
+		var attempts int32
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			switch atomic.AddInt32(&attempts, 1) {
+			case 1:
+				writeResponse(t, w, http.StatusInternalServerError, "Internal Server Error.")
+			case 2:
+				writeResponse(t, w, http.StatusTooManyRequests, "Too Many Requests.")
+			default:
+				writeResponse(t, w, http.StatusOK, `{"didDocument":{"id":"`+testDID+`"}}`)
+			}
+		}))
+		defer srv.Close()
+
+		result, err := newTestClient(t, srv.URL).ResolveDID(testDID)
+		require.NoError(t, err)
+		require.Equal(t, testDID, result.Document.ID())
+		require.Equal(t, int32(3), atomic.LoadInt32(&attempts))
+	})
+
+	t.Run("max retries reached", func(t *testing.T) {
+		var attempts int32
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			atomic.AddInt32(&attempts, 1)
+
+			writeResponse(t, w, http.StatusServiceUnavailable, "Service Unavailable.")
+		}))
+		defer srv.Close()
+
+		_, err := newTestClient(t, srv.URL, WithMaxRetries(2)).ResolveDID(testDID)
+		require.Error(t, err)
+
+		httpErr := &HTTPError{}
+		require.True(t, errors.As(err, &httpErr))
+		require.Equal(t, http.StatusServiceUnavailable, httpErr.StatusCode)
+		require.Equal(t, int32(3), atomic.LoadInt32(&attempts))
+	})
+
+	t.Run("not found -> no retry", func(t *testing.T) {
+		var attempts int32
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			atomic.AddInt32(&attempts, 1)
+
+			writeResponse(t, w, http.StatusNotFound, "DID not found")
+		}))
+		defer srv.Close()
+
+		_, err := newTestClient(t, srv.URL).ResolveDID(testDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "status code 404: DID not found")
+		require.Equal(t, int32(1), atomic.LoadInt32(&attempts))
+	})
+
+	t.Run("connection error", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {}))
+		srv.Close()
+
+		_, err := newTestClient(t, srv.URL, WithMaxRetries(0)).ResolveDID(testDID)
+		require.Error(t, err)
+	})
+
+	t.Run("invalid response", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			writeResponse(t, w, http.StatusOK, "{")
+		}))
+		defer srv.Close()
+
+		_, err := newTestClient(t, srv.URL).ResolveDID(testDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal resolution result")
+	})
+}
+
+func TestClient_ProtocolParameters(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			require.Equal(t, http.MethodGet, req.Method)
+			require.Equal(t, protocolParametersPath, req.URL.Path)
+
+			writeResponse(t, w, http.StatusOK, `{"protocolVersion":"1.0","maxOperationSize":2500}`)
+		}))
+		defer srv.Close()
+
+		params, err := newTestClient(t, srv.URL).ProtocolParameters()
+		require.NoError(t, err)
+		require.Equal(t, "1.0", params.ProtocolVersion)
+		require.Equal(t, uint(2500), params.MaxOperationSize)
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			writeResponse(t, w, http.StatusNotFound, "Not Found.")
+		}))
+		defer srv.Close()
+
+		_, err := newTestClient(t, srv.URL).ProtocolParameters()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get protocol parameters")
+	})
+
+	t.Run("invalid response", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			writeResponse(t, w, http.StatusOK, "{")
+		}))
+		defer srv.Close()
+
+		_, err := newTestClient(t, srv.URL).ProtocolParameters()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal protocol parameters")
+	})
+}
+
+func TestClient_VerifyDID(t *testing.T) {
+	t.Run("resolve error", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			writeResponse(t, w, http.StatusNotFound, "DID not found")
+		}))
+		defer srv.Close()
+
+		_, err := newTestClient(t, srv.URL).VerifyDID(testDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "DID not found")
+	})
+
+	t.Run("verify error", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			// The resolution result doesn't include the operations of the DID.
+			writeResponse(t, w, http.StatusOK, `{"didDocument":{"id":"`+testDID+`"}}`)
+		}))
+		defer srv.Close()
+
+		_, err := newTestClient(t, srv.URL).VerifyDID(testDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "verify DID")
+	})
+}
+
+func newTestClient(t *testing.T, endpoint string, opts ...Option) *Client {
+	t.Helper()
+
+	c, err := New(endpoint, append([]Option{WithBackoff(time.Millisecond, 5*time.Millisecond)}, opts...)...)
+	require.NoError(t, err)
+
+	return c
+}
+
+func writeResponse(t *testing.T, w http.ResponseWriter, status int, body string) {
+	t.Helper()
+
+	w.WriteHeader(status)
+
+	_, err := w.Write([]byte(body))
+	require.NoError(t, err)
+}
@@ -0,0 +1,264 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package orbclient
+
+import (
+	"crypto"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/commitment"
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
+	"github.com/trustbloc/sidetree-core-go/pkg/jws"
+	"github.com/trustbloc/sidetree-core-go/pkg/patch"
+	"github.com/trustbloc/sidetree-core-go/pkg/util/pubkey"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/client"
+
+	"github.com/trustbloc/orb/pkg/document/util"
+)
+
+// CreateDIDRequest contains the parameters for creating a DID.
+type CreateDIDRequest struct {
+	// Document is the initial DID document (public keys and services) in JSON format.
+	Document string
+	// RecoveryKey is the public key that's required to recover or deactivate the DID.
+	RecoveryKey crypto.PublicKey
+	// UpdateKey is the public key that's required for the first update of the DID.
+	UpdateKey crypto.PublicKey
+	// AnchorOrigin is the origin (for example, https://orb.domain1.com) that's allowed to anchor the DID.
+	AnchorOrigin string
+	// FallbackAnchorOrigins (optional) are the origins that are contacted (in order) for the latest operations
+	// of the DID when the primary anchor origin is unavailable.
+	FallbackAnchorOrigins []string
+}
+
+// UpdateDIDRequest contains the parameters for updating a DID.
+type UpdateDIDRequest struct {
+	DID     string
+	Patches []patch.Patch
+	// UpdateKey is the current update key, i.e. the key that was committed to by the previous
+	// create, update or recover operation.
+	UpdateKey crypto.PublicKey
+	// Signer signs the request with the private key of UpdateKey.
+	Signer client.Signer
+	// NextUpdateKey is the public key that's required for the next update of the DID.
+	NextUpdateKey crypto.PublicKey
+}
+
+// RecoverDIDRequest contains the parameters for recovering a DID.
+type RecoverDIDRequest struct {
+	DID string
+	// Document is the new DID document (public keys and services) in JSON format.
+	Document string
+	// RecoveryKey is the current recovery key.
+	RecoveryKey crypto.PublicKey
+	// Signer signs the request with the private key of RecoveryKey.
+	Signer          client.Signer
+	NextRecoveryKey crypto.PublicKey
+	NextUpdateKey   crypto.PublicKey
+	AnchorOrigin    string
+	// FallbackAnchorOrigins (optional) are the origins that are contacted (in order) for the latest operations
+	// of the DID when the primary anchor origin is unavailable.
+	FallbackAnchorOrigins []string
+}
+
+// DeactivateDIDRequest contains the parameters for deactivating a DID.
+type DeactivateDIDRequest struct {
+	DID string
+	// RecoveryKey is the current recovery key.
+	RecoveryKey crypto.PublicKey
+	// Signer signs the request with the private key of RecoveryKey.
+	Signer client.Signer
+}
+
+// CreateDID creates a DID and returns the (unpublished) resolution result of the new DID.
+func (c *Client) CreateDID(req *CreateDIDRequest) (*document.ResolutionResult, error) {
+	recoveryCommitment, err := c.commitment(req.RecoveryKey)
+	if err != nil {
+		return nil, fmt.Errorf("recovery key: %w", err)
+	}
+
+	updateCommitment, err := c.commitment(req.UpdateKey)
+	if err != nil {
+		return nil, fmt.Errorf("update key: %w", err)
+	}
+
+	reqBytes, err := client.NewCreateRequest(&client.CreateRequestInfo{
+		OpaqueDocument:     req.Document,
+		RecoveryCommitment: recoveryCommitment,
+		UpdateCommitment:   updateCommitment,
+		MultihashCode:      c.multihashCode,
+		AnchorOrigin:       anchorOriginObject(req.AnchorOrigin, req.FallbackAnchorOrigins),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	respBytes, err := c.post(c.endpoint+operationsPath, reqBytes)
+	if err != nil {
+		return nil, fmt.Errorf("create DID: %w", err)
+	}
+
+	result := &document.ResolutionResult{}
+
+	err = json.Unmarshal(respBytes, result)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal resolution result of created DID: %w", err)
+	}
+
+	return result, nil
+}
+
+// UpdateDID updates the given DID.
+func (c *Client) UpdateDID(req *UpdateDIDRequest) error {
+	suffix, err := util.GetSuffix(req.DID)
+	if err != nil {
+		return fmt.Errorf("invalid DID [%s]: %w", req.DID, err)
+	}
+
+	updateKey, revealValue, err := c.revealValue(req.UpdateKey)
+	if err != nil {
+		return fmt.Errorf("update key: %w", err)
+	}
+
+	nextUpdateCommitment, err := c.commitment(req.NextUpdateKey)
+	if err != nil {
+		return fmt.Errorf("next update key: %w", err)
+	}
+
+	reqBytes, err := client.NewUpdateRequest(&client.UpdateRequestInfo{
+		DidSuffix:        suffix,
+		RevealValue:      revealValue,
+		UpdateCommitment: nextUpdateCommitment,
+		UpdateKey:        updateKey,
+		Patches:          req.Patches,
+		MultihashCode:    c.multihashCode,
+		Signer:           req.Signer,
+		AnchorFrom:       time.Now().Unix(),
+	})
+	if err != nil {
+		return fmt.Errorf("update request: %w", err)
+	}
+
+	_, err = c.post(c.endpoint+operationsPath, reqBytes)
+	if err != nil {
+		return fmt.Errorf("update DID [%s]: %w", req.DID, err)
+	}
+
+	return nil
+}
+
+// RecoverDID recovers the given DID.
+func (c *Client) RecoverDID(req *RecoverDIDRequest) error {
+	suffix, err := util.GetSuffix(req.DID)
+	if err != nil {
+		return fmt.Errorf("invalid DID [%s]: %w", req.DID, err)
+	}
+
+	recoveryKey, revealValue, err := c.revealValue(req.RecoveryKey)
+	if err != nil {
+		return fmt.Errorf("recovery key: %w", err)
+	}
+
+	nextRecoveryCommitment, err := c.commitment(req.NextRecoveryKey)
+	if err != nil {
+		return fmt.Errorf("next recovery key: %w", err)
+	}
+
+	nextUpdateCommitment, err := c.commitment(req.NextUpdateKey)
+	if err != nil {
+		return fmt.Errorf("next update key: %w", err)
+	}
+
+	reqBytes, err := client.NewRecoverRequest(&client.RecoverRequestInfo{
+		DidSuffix:          suffix,
+		RevealValue:        revealValue,
+		OpaqueDocument:     req.Document,
+		RecoveryKey:        recoveryKey,
+		RecoveryCommitment: nextRecoveryCommitment,
+		UpdateCommitment:   nextUpdateCommitment,
+		MultihashCode:      c.multihashCode,
+		Signer:             req.Signer,
+		AnchorFrom:         time.Now().Unix(),
+		AnchorOrigin:       anchorOriginObject(req.AnchorOrigin, req.FallbackAnchorOrigins),
+	})
+	if err != nil {
+		return fmt.Errorf("recover request: %w", err)
+	}
+
+	_, err = c.post(c.endpoint+operationsPath, reqBytes)
+	if err != nil {
+		return fmt.Errorf("recover DID [%s]: %w", req.DID, err)
+	}
+
+	return nil
+}
+
+// DeactivateDID deactivates the given DID.
+func (c *Client) DeactivateDID(req *DeactivateDIDRequest) error {
+	suffix, err := util.GetSuffix(req.DID)
+	if err != nil {
+		return fmt.Errorf("invalid DID [%s]: %w", req.DID, err)
+	}
+
+	recoveryKey, revealValue, err := c.revealValue(req.RecoveryKey)
+	if err != nil {
+		return fmt.Errorf("recovery key: %w", err)
+	}
+
+	reqBytes, err := client.NewDeactivateRequest(&client.DeactivateRequestInfo{
+		DidSuffix:   suffix,
+		RevealValue: revealValue,
+		RecoveryKey: recoveryKey,
+		Signer:      req.Signer,
+		AnchorFrom:  time.Now().Unix(),
+	})
+	if err != nil {
+		return fmt.Errorf("deactivate request: %w", err)
+	}
+
+	_, err = c.post(c.endpoint+operationsPath, reqBytes)
+	if err != nil {
+		return fmt.Errorf("deactivate DID [%s]: %w", req.DID, err)
+	}
+
+	return nil
+}
+
+func (c *Client) commitment(key crypto.PublicKey) (string, error) {
+	jwk, err := pubkey.GetPublicKeyJWK(key)
+	if err != nil {
+		return "", err
+	}
+
+	return commitment.GetCommitment(jwk, c.multihashCode)
+}
+
+func (c *Client) revealValue(key crypto.PublicKey) (*jws.JWK, string, error) {
+	jwk, err := pubkey.GetPublicKeyJWK(key)
+	if err != nil {
+		return nil, "", err
+	}
+
+	revealValue, err := commitment.GetRevealValue(jwk, c.multihashCode)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return jwk, revealValue, nil
+}
+
+// anchorOriginObject returns the anchor origin object of a create or recover request. If fallback anchor origins are
+// provided then the anchor origin is a list containing the primary anchor origin followed by the fallbacks.
+func anchorOriginObject(primary string, fallbacks []string) interface{} {
+	if len(fallbacks) == 0 {
+		return primary
+	}
+
+	return append([]string{primary}, fallbacks...)
+}
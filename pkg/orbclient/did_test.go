@@ -0,0 +1,295 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package orbclient
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/patch"
+	"github.com/trustbloc/sidetree-core-go/pkg/util/ecsigner"
+)
+
+const anchorOrigin = "https://orb.domain1.com"
+
+func TestClient_CreateDID(t *testing.T) {
+	recoveryKey := newKey(t)
+	updateKey := newKey(t)
+
+	t.Run("success", func(t *testing.T) {
+		srv, requests := newOperationsServer(t, http.StatusOK, `{"didDocument":{"id":"`+testDID+`"}}`)
+		defer srv.Close()
+
+		result, err := newTestClient(t, srv.URL).CreateDID(&CreateDIDRequest{
+			Document:     `{}`,
+			RecoveryKey:  &recoveryKey.PublicKey,
+			UpdateKey:    &updateKey.PublicKey,
+			AnchorOrigin: anchorOrigin,
+		})
+		require.NoError(t, err)
+		require.Equal(t, testDID, result.Document.ID())
+		require.Len(t, *requests, 1)
+		require.Equal(t, "create", (*requests)[0]["type"])
+	})
+
+	t.Run("success - fallback anchor origins", func(t *testing.T) {
+		srv, requests := newOperationsServer(t, http.StatusOK, `{"didDocument":{"id":"`+testDID+`"}}`)
+		defer srv.Close()
+
+		_, err := newTestClient(t, srv.URL).CreateDID(&CreateDIDRequest{
+			Document:              `{}`,
+			RecoveryKey:           &recoveryKey.PublicKey,
+			UpdateKey:             &updateKey.PublicKey,
+			AnchorOrigin:          anchorOrigin,
+			FallbackAnchorOrigins: []string{"https://orb.domain2.com"},
+		})
+		require.NoError(t, err)
+		require.Len(t, *requests, 1)
+
+		suffixData, ok := (*requests)[0]["suffixData"].(map[string]interface{})
+		require.True(t, ok)
+		require.Equal(t, []interface{}{anchorOrigin, "https://orb.domain2.com"}, suffixData["anchorOrigin"])
+	})
+
+	t.Run("invalid keys", func(t *testing.T) {
+		c := newTestClient(t, anchorOrigin)
+
+		_, err := c.CreateDID(&CreateDIDRequest{UpdateKey: &updateKey.PublicKey})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "recovery key")
+
+		_, err = c.CreateDID(&CreateDIDRequest{RecoveryKey: &recoveryKey.PublicKey})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "update key")
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		srv, _ := newOperationsServer(t, http.StatusBadRequest, "bad request")
+		defer srv.Close()
+
+		_, err := newTestClient(t, srv.URL).CreateDID(&CreateDIDRequest{
+			Document:    `{}`,
+			RecoveryKey: &recoveryKey.PublicKey,
+			UpdateKey:   &updateKey.PublicKey,
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "status code 400")
+	})
+
+	t.Run("invalid response", func(t *testing.T) {
+		srv, _ := newOperationsServer(t, http.StatusOK, "{")
+		defer srv.Close()
+
+		_, err := newTestClient(t, srv.URL).CreateDID(&CreateDIDRequest{
+			Document:    `{}`,
+			RecoveryKey: &recoveryKey.PublicKey,
+			UpdateKey:   &updateKey.PublicKey,
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal resolution result")
+	})
+}
+
+func TestClient_UpdateDID(t *testing.T) {
+	updateKey := newKey(t)
+	nextUpdateKey := newKey(t)
+
+	p, err := patch.NewAddServiceEndpointsPatch(
+		`[{"id":"svc1","type":"type","serviceEndpoint":"http://www.example.com"}]`)
+	require.NoError(t, err)
+
+	newRequest := func() *UpdateDIDRequest {
+		return &UpdateDIDRequest{
+			DID:           testDID,
+			Patches:       []patch.Patch{p},
+			UpdateKey:     &updateKey.PublicKey,
+			Signer:        ecsigner.New(updateKey, "ES256", ""),
+			NextUpdateKey: &nextUpdateKey.PublicKey,
+		}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		srv, requests := newOperationsServer(t, http.StatusOK, "")
+		defer srv.Close()
+
+		require.NoError(t, newTestClient(t, srv.URL).UpdateDID(newRequest()))
+		require.Len(t, *requests, 1)
+		require.Equal(t, "update", (*requests)[0]["type"])
+		require.Equal(t, "EiDOQXC2GnoVyHwIRbjhLx_cNc6vmZaS04SZjZdlLLAPRg", (*requests)[0]["didSuffix"])
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		c := newTestClient(t, anchorOrigin)
+
+		req := newRequest()
+		req.DID = "did:orb"
+		require.Error(t, c.UpdateDID(req))
+
+		req = newRequest()
+		req.UpdateKey = nil
+		require.Error(t, c.UpdateDID(req))
+
+		req = newRequest()
+		req.NextUpdateKey = nil
+		require.Error(t, c.UpdateDID(req))
+
+		req = newRequest()
+		req.Patches = nil
+		require.Error(t, c.UpdateDID(req))
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		srv, _ := newOperationsServer(t, http.StatusBadRequest, "bad request")
+		defer srv.Close()
+
+		require.Error(t, newTestClient(t, srv.URL).UpdateDID(newRequest()))
+	})
+}
+
+func TestClient_RecoverDID(t *testing.T) {
+	recoveryKey := newKey(t)
+	nextRecoveryKey := newKey(t)
+	nextUpdateKey := newKey(t)
+
+	newRequest := func() *RecoverDIDRequest {
+		return &RecoverDIDRequest{
+			DID:             testDID,
+			Document:        `{}`,
+			RecoveryKey:     &recoveryKey.PublicKey,
+			Signer:          ecsigner.New(recoveryKey, "ES256", ""),
+			NextRecoveryKey: &nextRecoveryKey.PublicKey,
+			NextUpdateKey:   &nextUpdateKey.PublicKey,
+			AnchorOrigin:    anchorOrigin,
+		}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		srv, requests := newOperationsServer(t, http.StatusOK, "")
+		defer srv.Close()
+
+		require.NoError(t, newTestClient(t, srv.URL).RecoverDID(newRequest()))
+		require.Len(t, *requests, 1)
+		require.Equal(t, "recover", (*requests)[0]["type"])
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		c := newTestClient(t, anchorOrigin)
+
+		req := newRequest()
+		req.DID = "did:orb"
+		require.Error(t, c.RecoverDID(req))
+
+		req = newRequest()
+		req.RecoveryKey = nil
+		require.Error(t, c.RecoverDID(req))
+
+		req = newRequest()
+		req.NextRecoveryKey = nil
+		require.Error(t, c.RecoverDID(req))
+
+		req = newRequest()
+		req.NextUpdateKey = nil
+		require.Error(t, c.RecoverDID(req))
+
+		req = newRequest()
+		req.Signer = nil
+		require.Error(t, c.RecoverDID(req))
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		srv, _ := newOperationsServer(t, http.StatusBadRequest, "bad request")
+		defer srv.Close()
+
+		require.Error(t, newTestClient(t, srv.URL).RecoverDID(newRequest()))
+	})
+}
+
+func TestClient_DeactivateDID(t *testing.T) {
+	recoveryKey := newKey(t)
+
+	newRequest := func() *DeactivateDIDRequest {
+		return &DeactivateDIDRequest{
+			DID:         testDID,
+			RecoveryKey: &recoveryKey.PublicKey,
+			Signer:      ecsigner.New(recoveryKey, "ES256", ""),
+		}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		srv, requests := newOperationsServer(t, http.StatusOK, "")
+		defer srv.Close()
+
+		require.NoError(t, newTestClient(t, srv.URL).DeactivateDID(newRequest()))
+		require.Len(t, *requests, 1)
+		require.Equal(t, "deactivate", (*requests)[0]["type"])
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		c := newTestClient(t, anchorOrigin)
+
+		req := newRequest()
+		req.DID = "did:orb"
+		require.Error(t, c.DeactivateDID(req))
+
+		req = newRequest()
+		req.RecoveryKey = nil
+		require.Error(t, c.DeactivateDID(req))
+
+		req = newRequest()
+		req.Signer = nil
+		require.Error(t, c.DeactivateDID(req))
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		srv, _ := newOperationsServer(t, http.StatusBadRequest, "bad request")
+		defer srv.Close()
+
+		require.Error(t, newTestClient(t, srv.URL).DeactivateDID(newRequest()))
+	})
+}
+
+// newOperationsServer returns a server that responds to operation requests with the given status and body.
+// The posted requests are returned in the slice.
+func newOperationsServer(t *testing.T, status int, body string) (*httptest.Server, *[]map[string]interface{}) {
+	t.Helper()
+
+	var requests []map[string]interface{}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		require.Equal(t, http.MethodPost, req.Method)
+		require.Equal(t, operationsPath, req.URL.Path)
+		require.Equal(t, contentTypeJSON, req.Header.Get("Content-Type"))
+
+		reqBytes, err := ioutil.ReadAll(req.Body)
+		require.NoError(t, err)
+
+		opReq := make(map[string]interface{})
+		require.NoError(t, json.Unmarshal(reqBytes, &opReq))
+
+		requests = append(requests, opReq)
+
+		writeResponse(t, w, status, body)
+	}))
+
+	return srv, &requests
+}
+
+func newKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	return key
+}
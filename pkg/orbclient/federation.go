@@ -0,0 +1,87 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package orbclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	apclient "github.com/trustbloc/orb/pkg/activitypub/client"
+	"github.com/trustbloc/orb/pkg/activitypub/client/transport"
+	"github.com/trustbloc/orb/pkg/activitypub/resthandler"
+)
+
+// GetFollowers returns the services that follow the node.
+func (c *Client) GetFollowers() ([]*url.URL, error) {
+	return c.getReferences(resthandler.FollowersPath)
+}
+
+// GetFollowing returns the services that the node follows.
+func (c *Client) GetFollowing() ([]*url.URL, error) {
+	return c.getReferences(resthandler.FollowingPath)
+}
+
+// GetWitnesses returns the services that witness the anchors of the node.
+func (c *Client) GetWitnesses() ([]*url.URL, error) {
+	return c.getReferences(resthandler.WitnessesPath)
+}
+
+// GetWitnessing returns the services for which the node is a witness.
+func (c *Client) GetWitnessing() ([]*url.URL, error) {
+	return c.getReferences(resthandler.WitnessingPath)
+}
+
+func (c *Client) getReferences(path string) ([]*url.URL, error) {
+	iri, err := url.Parse(c.endpoint + c.servicePath + path)
+	if err != nil {
+		return nil, fmt.Errorf("parse collection IRI: %w", err)
+	}
+
+	it, err := apclient.New(apclient.Config{}, &apTransport{client: c}).GetReferences(iri)
+	if err != nil {
+		return nil, fmt.Errorf("get references from %s: %w", iri, err)
+	}
+
+	refs, err := apclient.ReadReferences(it, -1)
+	if err != nil {
+		return nil, fmt.Errorf("read references from %s: %w", iri, err)
+	}
+
+	return refs, nil
+}
+
+// apTransport adapts the client to the transport of the ActivityPub client so that the collections are
+// retrieved with the retries and authorization of the client.
+type apTransport struct {
+	client *Client
+}
+
+func (t *apTransport) Get(_ context.Context, req *transport.Request) (*http.Response, error) {
+	respBytes, err := t.client.get(req.URL.String())
+	if err != nil {
+		httpErr := &HTTPError{}
+		if errors.As(err, &httpErr) {
+			return newResponse(httpErr.StatusCode, []byte(httpErr.Body)), nil
+		}
+
+		return nil, err
+	}
+
+	return newResponse(http.StatusOK, respBytes), nil
+}
+
+func newResponse(status int, body []byte) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Body:       ioutil.NopCloser(bytes.NewReader(body)),
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package orbclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/resthandler"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/internal/aptestutil"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+)
+
+func TestClient_Federation(t *testing.T) {
+	services := []*url.URL{
+		testutil.MustParseURL("https://orb.domain2.com/services/orb"),
+		testutil.MustParseURL("https://orb.domain3.com/services/orb"),
+		testutil.MustParseURL("https://orb.domain4.com/services/orb"),
+	}
+
+	var srv *httptest.Server
+
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		collIRI := testutil.MustParseURL(srv.URL + req.URL.Path)
+		page0 := testutil.NewMockID(collIRI, "?page=0")
+		page1 := testutil.NewMockID(collIRI, "?page=1")
+
+		var obj interface{}
+
+		switch req.URL.Query().Get("page") {
+		case "0":
+			obj = aptestutil.NewMockCollectionPage(page0, page1, nil, collIRI, len(services),
+				vocab.NewObjectProperty(vocab.WithIRI(services[0])),
+				vocab.NewObjectProperty(vocab.WithIRI(services[1])),
+			)
+		case "1":
+			obj = aptestutil.NewMockCollectionPage(page1, nil, page0, collIRI, len(services),
+				vocab.NewObjectProperty(vocab.WithIRI(services[2])),
+			)
+		default:
+			if req.URL.Path == defaultServicePath+resthandler.WitnessingPath {
+				writeResponse(t, w, http.StatusUnauthorized, "Unauthorized.")
+
+				return
+			}
+
+			obj = aptestutil.NewMockCollection(collIRI, page0, page1, len(services))
+		}
+
+		objBytes, err := json.Marshal(obj)
+		require.NoError(t, err)
+
+		writeResponse(t, w, http.StatusOK, string(objBytes))
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+
+	for _, get := range []func() ([]*url.URL, error){c.GetFollowers, c.GetFollowing, c.GetWitnesses} {
+		refs, err := get()
+		require.NoError(t, err)
+		require.Len(t, refs, len(services))
+
+		for i, ref := range refs {
+			require.Equal(t, services[i].String(), ref.String())
+		}
+	}
+
+	_, err := c.GetWitnessing()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "status code 401")
+
+	t.Run("connection error", func(t *testing.T) {
+		srv2 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {}))
+		srv2.Close()
+
+		_, err := newTestClient(t, srv2.URL, WithMaxRetries(0)).GetFollowers()
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,79 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package clientversion
+
+import (
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/doccomposer"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/doctransformer/didtransformer"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/docvalidator/didvalidator"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/operationapplier"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/operationparser"
+
+	vcommon "github.com/trustbloc/orb/pkg/protocolversion/versions/common"
+	protocolcfgv1_0 "github.com/trustbloc/orb/pkg/protocolversion/versions/v1_0/config"
+	protocolcfgv1_1 "github.com/trustbloc/orb/pkg/protocolversion/versions/v1_1/config"
+	orboperationparser "github.com/trustbloc/orb/pkg/versions/1_0/operationparser"
+	"github.com/trustbloc/orb/pkg/versions/1_0/operationparser/validators/anchororigin"
+	"github.com/trustbloc/orb/pkg/versions/1_0/operationparser/validators/anchortime"
+)
+
+const (
+	// V1_0 is version 1.0 of the Sidetree protocol.
+	V1_0 = "1.0"
+	// V1_1 is version 1.1 of the Sidetree protocol.
+	V1_1 = "1.1"
+)
+
+// Config contains the settings of the client versions.
+type Config struct {
+	AnchorOrigins                []string
+	MethodContext                []string
+	EnableBase                   bool
+	IncludePublishedOperations   bool
+	IncludeUnpublishedOperations bool
+}
+
+// New returns the client versions of the Sidetree protocol that are required in order to parse operations and
+// resolve documents on the client side. Unlike the versions that are created by the client registry, these
+// versions have no operation provider (and therefore no CAS or storage dependencies), so this package may be
+// compiled to WASM.
+func New(cfg *Config) []protocol.Version {
+	return []protocol.Version{
+		newVersion(V1_0, protocolcfgv1_0.GetProtocolConfig(), cfg),
+		newVersion(V1_1, protocolcfgv1_1.GetProtocolConfig(), cfg),
+	}
+}
+
+func newVersion(version string, p protocol.Protocol, cfg *Config) protocol.Version {
+	parserOpts := []operationparser.Option{
+		operationparser.WithAnchorTimeValidator(anchortime.New(p.MaxOperationTimeDelta)),
+	}
+
+	if len(cfg.AnchorOrigins) > 0 {
+		parserOpts = append(parserOpts,
+			operationparser.WithAnchorOriginValidator(anchororigin.New(cfg.AnchorOrigins)))
+	}
+
+	opParser := operationparser.New(p, parserOpts...)
+
+	dc := doccomposer.New()
+
+	return &vcommon.ProtocolVersion{
+		VersionStr:   version,
+		P:            p,
+		OpParser:     orboperationparser.New(opParser),
+		OpApplier:    operationapplier.New(p, opParser, dc),
+		DocComposer:  dc,
+		DocValidator: didvalidator.New(),
+		DocTransformer: didtransformer.New(
+			didtransformer.WithMethodContext(cfg.MethodContext),
+			didtransformer.WithBase(cfg.EnableBase),
+			didtransformer.WithIncludePublishedOperations(cfg.IncludePublishedOperations),
+			didtransformer.WithIncludeUnpublishedOperations(cfg.IncludeUnpublishedOperations)),
+	}
+}
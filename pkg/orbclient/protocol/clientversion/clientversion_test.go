@@ -0,0 +1,43 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package clientversion
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		versions := New(&Config{})
+		require.Len(t, versions, 2)
+
+		require.Equal(t, V1_0, versions[0].Version())
+		require.Equal(t, uint64(0), versions[0].Protocol().GenesisTime)
+		require.Equal(t, V1_1, versions[1].Version())
+		require.Equal(t, uint64(1), versions[1].Protocol().GenesisTime)
+
+		for _, v := range versions {
+			require.NotNil(t, v.OperationParser())
+			require.NotNil(t, v.OperationApplier())
+			require.NotNil(t, v.DocumentTransformer())
+			require.Nil(t, v.OperationProvider())
+		}
+	})
+
+	t.Run("success - with anchor origins", func(t *testing.T) {
+		versions := New(&Config{
+			AnchorOrigins:                []string{"https://orb.domain1.com"},
+			MethodContext:                []string{"https://w3id.org/orb/v1"},
+			EnableBase:                   true,
+			IncludePublishedOperations:   true,
+			IncludeUnpublishedOperations: true,
+		})
+		require.Len(t, versions, 2)
+	})
+}
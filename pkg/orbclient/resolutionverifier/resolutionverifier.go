@@ -10,6 +10,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"reflect"
 
 	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
 	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
@@ -18,11 +19,10 @@ import (
 	"github.com/trustbloc/sidetree-core-go/pkg/document"
 	"github.com/trustbloc/sidetree-core-go/pkg/processor"
 
-	"github.com/trustbloc/orb/pkg/config"
 	"github.com/trustbloc/orb/pkg/document/util"
+	"github.com/trustbloc/orb/pkg/orbclient/protocol/clientversion"
 	"github.com/trustbloc/orb/pkg/orbclient/protocol/nsprovider"
 	"github.com/trustbloc/orb/pkg/orbclient/protocol/verprovider"
-	"github.com/trustbloc/orb/pkg/protocolversion/clientregistry"
 )
 
 // ResolutionVerifier verifies resolved documents.
@@ -92,26 +92,13 @@ func WithEnableBase(enabled bool) Option {
 }
 
 func getProtocolClient(namespace string, anchorOrigins, methodContexts []string, enableBase bool) (protocol.Client, error) { //nolint:lll
-	versions := []string{"1.0"}
-
-	registry := clientregistry.New()
-
-	var clientVersions []protocol.Version
-
-	for _, version := range versions {
-		cv, err := registry.CreateClientVersion(version, nil, &config.Sidetree{
-			IncludePublishedOperations:   true,
-			IncludeUnpublishedOperations: true,
-			AnchorOrigins:                anchorOrigins,
-			MethodContext:                methodContexts,
-			EnableBase:                   enableBase,
-		})
-		if err != nil {
-			return nil, fmt.Errorf("error creating client version [%s]: %w", version, err)
-		}
-
-		clientVersions = append(clientVersions, cv)
-	}
+	clientVersions := clientversion.New(&clientversion.Config{
+		IncludePublishedOperations:   true,
+		IncludeUnpublishedOperations: true,
+		AnchorOrigins:                anchorOrigins,
+		MethodContext:                methodContexts,
+		EnableBase:                   enableBase,
+	})
 
 	nsProvider := nsprovider.New()
 	nsProvider.Add(namespace, verprovider.New(clientVersions))
@@ -269,7 +256,8 @@ func equalMetadata(input, resolved document.Metadata) error {
 		return fmt.Errorf("input and resolved recovery commitments don't match: %w", err)
 	}
 
-	if inputMethodMetadata[document.AnchorOriginProperty] != resolvedMethodMetadata[document.AnchorOriginProperty] {
+	if !equalAnchorOrigins(inputMethodMetadata[document.AnchorOriginProperty],
+		resolvedMethodMetadata[document.AnchorOriginProperty]) {
 		return fmt.Errorf("input[%s] and resolved[%s] anchor origins don't match",
 			inputMethodMetadata[document.AnchorOriginProperty], resolvedMethodMetadata[document.AnchorOriginProperty])
 	}
@@ -282,6 +270,22 @@ func equalMetadata(input, resolved document.Metadata) error {
 	return nil
 }
 
+// equalAnchorOrigins returns true if the given anchor origin properties contain the same anchor origins
+// (the property may either be a single anchor origin or a list of anchor origins).
+func equalAnchorOrigins(input, resolved interface{}) bool {
+	inputAnchorOrigins, err := util.ParseAnchorOrigins(input)
+	if err != nil {
+		return reflect.DeepEqual(input, resolved)
+	}
+
+	resolvedAnchorOrigins, err := util.ParseAnchorOrigins(resolved)
+	if err != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(inputAnchorOrigins, resolvedAnchorOrigins)
+}
+
 func checkCommitment(input, resolved map[string]interface{}, commitmentType string) error {
 	ao, ok := input[commitmentType]
 	if !ok {
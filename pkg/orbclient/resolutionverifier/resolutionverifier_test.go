@@ -299,6 +299,20 @@ func TestEqualMetadata(t *testing.T) {
 			"input[https://anchor-origin.domain.com] and resolved[https://other.domain.com] anchor origins don't match")
 	})
 
+	t.Run("error - different fallback anchor origins", func(t *testing.T) {
+		md := make(map[string]interface{})
+		md[document.RecoveryCommitmentProperty] = recoveryCommitment
+		md[document.UpdateCommitmentProperty] = updateCommitment
+		md[document.AnchorOriginProperty] = []interface{}{anchorOriginDomain, "https://other.domain.com"}
+
+		docMD := make(document.Metadata)
+		docMD[document.MethodProperty] = md
+
+		err := equalMetadata(docMetadata, docMD)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "anchor origins don't match")
+	})
+
 	t.Run("error - different canonical ID", func(t *testing.T) {
 		md := make(map[string]interface{})
 		md[document.RecoveryCommitmentProperty] = recoveryCommitment
@@ -0,0 +1,255 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package verifier resolves long-form DIDs, verifies resolution results and verifies anchor credentials on the
+// client side. The package has no KMS, database or CAS dependencies so that it may be compiled to WASM
+// (see cmd/orb-wasm) and used by browser wallets.
+package verifier
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	"github.com/piprate/json-gold/ld"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+	"github.com/trustbloc/sidetree-core-go/pkg/dochandler"
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/anchor/anchorevent"
+	"github.com/trustbloc/orb/pkg/anchor/subject"
+	anchorutil "github.com/trustbloc/orb/pkg/anchor/util"
+	"github.com/trustbloc/orb/pkg/document/util"
+	"github.com/trustbloc/orb/pkg/orbclient/protocol/clientversion"
+	"github.com/trustbloc/orb/pkg/orbclient/protocol/verprovider"
+	"github.com/trustbloc/orb/pkg/orbclient/resolutionverifier"
+)
+
+const longFormSeparator = ":"
+
+// Verifier resolves long-form DIDs and verifies resolution results and anchor credentials.
+type Verifier struct {
+	namespace          string
+	protocol           protocol.Client
+	resolutionVerifier *resolutionverifier.ResolutionVerifier
+
+	anchorOrigins     []string
+	methodContexts    []string
+	enableBase        bool
+	publicKeyFetcher  verifiable.PublicKeyFetcher
+	docLoader         ld.DocumentLoader
+	disableProofCheck bool
+}
+
+// VerifiedAnchor contains the verified credential and the payload of an anchor event.
+type VerifiedAnchor struct {
+	Credential *verifiable.Credential
+	Payload    *subject.Payload
+}
+
+// Option is a verifier option.
+type Option func(v *Verifier)
+
+// WithAnchorOrigins sets the allowed anchor origins.
+func WithAnchorOrigins(anchorOrigins []string) Option {
+	return func(v *Verifier) {
+		v.anchorOrigins = anchorOrigins
+	}
+}
+
+// WithMethodContext sets the method contexts that are added to resolved documents.
+func WithMethodContext(methodContexts []string) Option {
+	return func(v *Verifier) {
+		v.methodContexts = methodContexts
+	}
+}
+
+// WithEnableBase enables the @base JSON-LD directive in resolved documents.
+func WithEnableBase(enabled bool) Option {
+	return func(v *Verifier) {
+		v.enableBase = enabled
+	}
+}
+
+// WithPublicKeyFetcher sets the public key fetcher that's used to verify the proofs of anchor credentials.
+func WithPublicKeyFetcher(pkf verifiable.PublicKeyFetcher) Option {
+	return func(v *Verifier) {
+		v.publicKeyFetcher = pkf
+	}
+}
+
+// WithJSONLDDocumentLoader sets the JSON-LD document loader that's used to parse anchor credentials.
+func WithJSONLDDocumentLoader(docLoader ld.DocumentLoader) Option {
+	return func(v *Verifier) {
+		v.docLoader = docLoader
+	}
+}
+
+// WithDisableProofCheck disables the proof check of anchor credentials.
+func WithDisableProofCheck(disable bool) Option {
+	return func(v *Verifier) {
+		v.disableProofCheck = disable
+	}
+}
+
+// New returns a new verifier for the given DID namespace.
+func New(namespace string, opts ...Option) (*Verifier, error) {
+	v := &Verifier{
+		namespace: namespace,
+	}
+
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	rv, err := resolutionverifier.New(namespace,
+		resolutionverifier.WithAnchorOrigins(v.anchorOrigins),
+		resolutionverifier.WithMethodContext(v.methodContexts),
+		resolutionverifier.WithEnableBase(v.enableBase),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create resolution verifier: %w", err)
+	}
+
+	v.resolutionVerifier = rv
+
+	v.protocol = verprovider.New(clientversion.New(&clientversion.Config{
+		AnchorOrigins:                v.anchorOrigins,
+		MethodContext:                v.methodContexts,
+		EnableBase:                   v.enableBase,
+		IncludeUnpublishedOperations: true,
+	}))
+
+	return v, nil
+}
+
+// ResolveLongFormDID resolves the given long-form DID (<namespace>:<suffix>:<encoded initial state>) from the
+// initial state that's embedded in the DID, i.e. without contacting an Orb node.
+func (v *Verifier) ResolveLongFormDID(did string) (*document.ResolutionResult, error) {
+	pv, err := v.protocol.Current()
+	if err != nil {
+		return nil, fmt.Errorf("get current protocol version: %w", err)
+	}
+
+	shortFormDID, initialState, err := pv.OperationParser().ParseDID(v.namespace, did)
+	if err != nil {
+		return nil, fmt.Errorf("parse DID [%s]: %w", did, err)
+	}
+
+	if len(initialState) == 0 {
+		return nil, fmt.Errorf("DID [%s] is not a long-form DID", did)
+	}
+
+	op, err := pv.OperationParser().Parse(v.namespace, initialState)
+	if err != nil {
+		return nil, fmt.Errorf("parse initial state of DID [%s]: %w", did, err)
+	}
+
+	suffix, err := util.GetSuffix(shortFormDID)
+	if err != nil {
+		return nil, fmt.Errorf("get suffix from DID [%s]: %w", shortFormDID, err)
+	}
+
+	if op.Type != operation.TypeCreate || op.UniqueSuffix != suffix {
+		return nil, fmt.Errorf("initial state doesn't match DID [%s]", shortFormDID)
+	}
+
+	rm, err := applyCreate(op, pv)
+	if err != nil {
+		return nil, fmt.Errorf("apply initial state of DID [%s]: %w", did, err)
+	}
+
+	hint, err := util.GetHint(shortFormDID, v.namespace, suffix)
+	if err != nil {
+		return nil, fmt.Errorf("get hint from DID [%s]: %w", shortFormDID, err)
+	}
+
+	ti := dochandler.GetTransformationInfoForUnpublished(v.namespace, "", hint, suffix,
+		did[strings.LastIndex(did, longFormSeparator)+1:])
+
+	return pv.DocumentTransformer().TransformDocument(rm, ti)
+}
+
+// VerifyResolutionResult verifies that the document in the given resolution result is reproduced by the
+// (published and unpublished) operations in the document metadata.
+func (v *Verifier) VerifyResolutionResult(result *document.ResolutionResult) error {
+	return v.resolutionVerifier.Verify(result)
+}
+
+// VerifyAnchorEvent verifies the proofs of the anchor credential in the given anchor event and returns the
+// credential along with the anchor payload (namespace, version, core index, etc.).
+func (v *Verifier) VerifyAnchorEvent(anchorEventBytes []byte) (*VerifiedAnchor, error) {
+	anchorEvent := &vocab.AnchorEventType{}
+
+	err := json.Unmarshal(anchorEventBytes, anchorEvent)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal anchor event: %w", err)
+	}
+
+	vc, err := anchorutil.VerifiableCredentialFromAnchorEvent(anchorEvent, v.getParseCredentialOpts()...)
+	if err != nil {
+		return nil, fmt.Errorf("verify anchor credential: %w", err)
+	}
+
+	payload, err := anchorevent.GetPayloadFromAnchorEvent(anchorEvent)
+	if err != nil {
+		return nil, fmt.Errorf("get payload from anchor event: %w", err)
+	}
+
+	return &VerifiedAnchor{
+		Credential: vc,
+		Payload:    payload,
+	}, nil
+}
+
+func (v *Verifier) getParseCredentialOpts() []verifiable.CredentialOpt {
+	var opts []verifiable.CredentialOpt
+
+	if v.publicKeyFetcher != nil {
+		opts = append(opts, verifiable.WithPublicKeyFetcher(v.publicKeyFetcher))
+	}
+
+	if v.docLoader != nil {
+		opts = append(opts, verifiable.WithJSONLDDocumentLoader(v.docLoader))
+	}
+
+	if v.disableProofCheck {
+		opts = append(opts, verifiable.WithDisabledProofCheck())
+	}
+
+	return opts
+}
+
+// applyCreate applies the create operation in the same way as the document handler does when it resolves
+// a long-form DID.
+func applyCreate(op *operation.Operation, pv protocol.Version) (*protocol.ResolutionModel, error) {
+	anchoredOp := &operation.AnchoredOperation{
+		Type:             op.Type,
+		UniqueSuffix:     op.UniqueSuffix,
+		OperationRequest: op.OperationRequest,
+		TransactionTime:  uint64(time.Now().Unix()),
+		ProtocolVersion:  pv.Protocol().GenesisTime,
+		AnchorOrigin:     op.AnchorOrigin,
+	}
+
+	rm, err := pv.OperationApplier().Apply(anchoredOp, &protocol.ResolutionModel{})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rm.Doc) == 0 {
+		return nil, errors.New("applying the delta resulted in an empty document (most likely due to an invalid patch)")
+	}
+
+	rm.UnpublishedOperations = []*operation.AnchoredOperation{anchoredOp}
+
+	return rm, nil
+}
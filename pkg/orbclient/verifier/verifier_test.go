@@ -0,0 +1,233 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifier
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"testing"
+	"time"
+
+	sigverifier "github.com/hyperledger/aries-framework-go/pkg/doc/signature/verifier"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/util"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/canonicalizer"
+	"github.com/trustbloc/sidetree-core-go/pkg/commitment"
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
+	"github.com/trustbloc/sidetree-core-go/pkg/encoder"
+	"github.com/trustbloc/sidetree-core-go/pkg/hashing"
+	"github.com/trustbloc/sidetree-core-go/pkg/patch"
+	"github.com/trustbloc/sidetree-core-go/pkg/util/pubkey"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/client"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/anchor/anchorevent"
+	"github.com/trustbloc/orb/pkg/anchor/builder"
+	"github.com/trustbloc/orb/pkg/anchor/subject"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+)
+
+const (
+	namespace = "did:orb"
+	sha2_256  = 18
+
+	anchorOrigin = "https://orb.domain1.com"
+
+	services = `[
+  {
+    "id": "svc1",
+    "type": "type",
+    "serviceEndpoint": "http://www.example.com"
+  }
+]`
+)
+
+func TestNew(t *testing.T) {
+	v, err := New(namespace,
+		WithAnchorOrigins([]string{anchorOrigin}),
+		WithMethodContext([]string{"https://w3id.org/orb/v1"}),
+		WithEnableBase(true),
+		WithPublicKeyFetcher(pubKeyFetcherFnc),
+		WithJSONLDDocumentLoader(testutil.GetLoader(t)),
+		WithDisableProofCheck(true),
+	)
+	require.NoError(t, err)
+	require.NotNil(t, v)
+	require.Len(t, v.getParseCredentialOpts(), 3)
+}
+
+func TestVerifier_ResolveLongFormDID(t *testing.T) {
+	v, err := New(namespace)
+	require.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		suffix, initialState := newInitialState(t)
+
+		did := namespace + ":uAAA:" + suffix + ":" + initialState
+
+		result, err := v.ResolveLongFormDID(did)
+		require.NoError(t, err)
+		require.Equal(t, did, result.Document.ID())
+		require.Len(t, result.Document[document.ServiceProperty], 1)
+		require.NotEmpty(t, result.DocumentMetadata[document.EquivalentIDProperty])
+	})
+
+	t.Run("short-form DID", func(t *testing.T) {
+		_, err := v.ResolveLongFormDID(namespace + ":uAAA:EiDOQXC2GnoVyHwIRbjhLx_cNc6vmZaS04SZjZdlLLAPRg")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "is not a long-form DID")
+	})
+
+	t.Run("suffix doesn't match initial state", func(t *testing.T) {
+		_, initialState := newInitialState(t)
+
+		_, err := v.ResolveLongFormDID(namespace + ":uAAA:EiDOQXC2GnoVyHwIRbjhLx_cNc6vmZaS04SZjZdlLLAPRg:" + initialState)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "initial state doesn't match DID")
+	})
+
+	t.Run("invalid initial state", func(t *testing.T) {
+		_, err := v.ResolveLongFormDID(namespace + ":uAAA:EiDOQXC2GnoVyHwIRbjhLx_cNc6vmZaS04SZjZdlLLAPRg:" +
+			encoder.EncodeToString([]byte(`{"delta":{}}`)))
+		require.Error(t, err)
+	})
+}
+
+func TestVerifier_VerifyResolutionResult(t *testing.T) {
+	v, err := New(namespace)
+	require.NoError(t, err)
+
+	rr := &document.ResolutionResult{
+		Document: document.Document{
+			"id": namespace + ":uAAA:EiDOQXC2GnoVyHwIRbjhLx_cNc6vmZaS04SZjZdlLLAPRg",
+		},
+	}
+
+	err = v.VerifyResolutionResult(rr)
+	require.Error(t, err)
+}
+
+func TestVerifier_VerifyAnchorEvent(t *testing.T) {
+	payload := &subject.Payload{
+		OperationCount: 1,
+		CoreIndex:      "hl:uEiAfDoaIG1rgG9-HRnRMveKAhR-5kjwZXOAQ1ABl1qBCWA",
+		Namespace:      namespace,
+		Version:        0,
+		AnchorOrigin:   anchorOrigin,
+		PreviousAnchors: []*subject.SuffixAnchor{
+			{Suffix: "EiDOQXC2GnoVyHwIRbjhLx_cNc6vmZaS04SZjZdlLLAPRg"},
+		},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		v, err := New(namespace,
+			WithPublicKeyFetcher(pubKeyFetcherFnc),
+			WithJSONLDDocumentLoader(testutil.GetLoader(t)))
+		require.NoError(t, err)
+
+		aeBytes, err := newMockAnchorEvent(t, payload).MarshalJSON()
+		require.NoError(t, err)
+
+		anchor, err := v.VerifyAnchorEvent(aeBytes)
+		require.NoError(t, err)
+		require.NotNil(t, anchor.Credential)
+		require.Equal(t, payload.CoreIndex, anchor.Payload.CoreIndex)
+		require.Equal(t, payload.Namespace, anchor.Payload.Namespace)
+	})
+
+	t.Run("invalid anchor event", func(t *testing.T) {
+		v, err := New(namespace, WithDisableProofCheck(true), WithJSONLDDocumentLoader(testutil.GetLoader(t)))
+		require.NoError(t, err)
+
+		_, err = v.VerifyAnchorEvent([]byte("{"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal anchor event")
+
+		_, err = v.VerifyAnchorEvent([]byte(`{"type":"AnchorEvent"}`))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "verify anchor credential")
+	})
+}
+
+// newInitialState returns the suffix and the encoded initial state of a long-form DID.
+func newInitialState(t *testing.T) (string, string) {
+	t.Helper()
+
+	recoveryCommitment := newCommitment(t)
+	updateCommitment := newCommitment(t)
+
+	p, err := patch.NewAddServiceEndpointsPatch(services)
+	require.NoError(t, err)
+
+	reqBytes, err := client.NewCreateRequest(&client.CreateRequestInfo{
+		Patches:            []patch.Patch{p},
+		RecoveryCommitment: recoveryCommitment,
+		UpdateCommitment:   updateCommitment,
+		MultihashCode:      sha2_256,
+		AnchorOrigin:       anchorOrigin,
+	})
+	require.NoError(t, err)
+
+	req := make(map[string]interface{})
+	require.NoError(t, json.Unmarshal(reqBytes, &req))
+
+	suffix, err := hashing.CalculateModelMultihash(req["suffixData"], sha2_256)
+	require.NoError(t, err)
+
+	initialStateBytes, err := canonicalizer.MarshalCanonical(map[string]interface{}{
+		"suffixData": req["suffixData"],
+		"delta":      req["delta"],
+	})
+	require.NoError(t, err)
+
+	return suffix, encoder.EncodeToString(initialStateBytes)
+}
+
+func newCommitment(t *testing.T) string {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	jwk, err := pubkey.GetPublicKeyJWK(&key.PublicKey)
+	require.NoError(t, err)
+
+	c, err := commitment.GetCommitment(jwk, sha2_256)
+	require.NoError(t, err)
+
+	return c
+}
+
+func newMockAnchorEvent(t *testing.T, payload *subject.Payload) *vocab.AnchorEventType {
+	t.Helper()
+
+	contentObj, err := anchorevent.BuildContentObject(payload)
+	require.NoError(t, err)
+
+	vc := &verifiable.Credential{
+		Types:   []string{"VerifiableCredential"},
+		Context: []string{"https://www.w3.org/2018/credentials/v1"},
+		Subject: &builder.CredentialSubject{},
+		Issuer: verifiable.Issuer{
+			ID: "http://orb.domain.com",
+		},
+		Issued: &util.TimeWrapper{Time: time.Now()},
+	}
+
+	act, err := anchorevent.BuildAnchorEvent(payload, contentObj.GeneratorID, contentObj.Payload,
+		vocab.MustMarshalToDoc(vc))
+	require.NoError(t, err)
+
+	return act
+}
+
+var pubKeyFetcherFnc = func(issuerID, keyID string) (*sigverifier.PublicKey, error) {
+	return nil, nil
+}
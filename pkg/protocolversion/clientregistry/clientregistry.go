@@ -17,6 +17,7 @@ import (
 	"github.com/trustbloc/orb/pkg/context/common"
 	versioncommon "github.com/trustbloc/orb/pkg/protocolversion/common"
 	v1_0 "github.com/trustbloc/orb/pkg/protocolversion/versions/v1_0/client"
+	v1_1 "github.com/trustbloc/orb/pkg/protocolversion/versions/v1_1/client"
 )
 
 var logger = log.New("client-factory-registry")
@@ -28,6 +29,8 @@ type factory interface {
 const (
 	// V1_0 ...
 	V1_0 = "1.0"
+	// V1_1 ...
+	V1_1 = "1.1"
 )
 
 // Registry implements a client version factory registry.
@@ -44,6 +47,7 @@ func New() *Registry {
 
 	// register supported versions
 	registry.Register(V1_0, v1_0.New())
+	registry.Register(V1_1, v1_1.New())
 
 	return registry
 }
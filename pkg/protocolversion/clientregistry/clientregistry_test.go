@@ -40,3 +40,13 @@ func TestRegistry(t *testing.T) {
 	require.EqualError(t, err, "client version factory for version [99] not found")
 	require.Nil(t, pv)
 }
+
+func TestRegistry_SupportedVersions(t *testing.T) {
+	r := New()
+
+	for _, version := range []string{V1_0, V1_1} {
+		pv, err := r.CreateClientVersion(version, &mocks.CasClient{}, &config.Sidetree{})
+		require.NoError(t, err)
+		require.Equal(t, version, pv.Version())
+	}
+}
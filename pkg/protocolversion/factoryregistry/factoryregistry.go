@@ -19,6 +19,7 @@ import (
 	ctxcommon "github.com/trustbloc/orb/pkg/context/common"
 	versioncommon "github.com/trustbloc/orb/pkg/protocolversion/common"
 	v1_0 "github.com/trustbloc/orb/pkg/protocolversion/versions/v1_0/factory"
+	v1_1 "github.com/trustbloc/orb/pkg/protocolversion/versions/v1_1/factory"
 )
 
 var logger = log.New("factory-registry")
@@ -30,6 +31,8 @@ type factory interface {
 const (
 	// V1_0 ...
 	V1_0 = "1.0"
+	// V1_1 ...
+	V1_1 = "1.1"
 )
 
 // Registry implements a protocol version factory registry.
@@ -46,6 +49,7 @@ func New() *Registry {
 
 	// register supported versions
 	registry.Register(V1_0, v1_0.New())
+	registry.Register(V1_1, v1_1.New())
 
 	return registry
 }
@@ -49,3 +49,14 @@ func TestRegistry(t *testing.T) {
 	require.EqualError(t, err, "protocol version factory for version [99] not found")
 	require.Nil(t, pv)
 }
+
+func TestRegistry_SupportedVersions(t *testing.T) {
+	r := New()
+
+	for _, version := range []string{V1_0, V1_1} {
+		pv, err := r.CreateProtocolVersion(version, &mocks.CasClient{}, &mocks.CASResolver{},
+			&mocks.OperationStore{}, &storemocks.Provider{}, &config.Sidetree{})
+		require.NoError(t, err)
+		require.Equal(t, version, pv.Version())
+	}
+}
@@ -8,7 +8,6 @@ package client
 
 import (
 	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
-	"github.com/trustbloc/sidetree-core-go/pkg/compression"
 	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/doccomposer"
 	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/doctransformer/didtransformer"
 	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/docvalidator/didvalidator"
@@ -16,6 +15,7 @@ import (
 	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/operationparser"
 	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/txnprovider"
 
+	"github.com/trustbloc/orb/pkg/compression"
 	"github.com/trustbloc/orb/pkg/config"
 	"github.com/trustbloc/orb/pkg/context/common"
 	vcommon "github.com/trustbloc/orb/pkg/protocolversion/versions/common"
@@ -49,7 +49,7 @@ func (v *Factory) Create(version string, casClient common.CASReader,
 
 	orbParser := orboperationparser.New(opParser)
 
-	cp := compression.New(compression.WithDefaultAlgorithms())
+	cp := compression.New()
 
 	dc := doccomposer.New()
 	oa := operationapplier.New(p, opParser, dc)
@@ -12,7 +12,6 @@ import (
 	"github.com/hyperledger/aries-framework-go/spi/storage"
 	"github.com/trustbloc/sidetree-core-go/pkg/api/cas"
 	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
-	"github.com/trustbloc/sidetree-core-go/pkg/compression"
 	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/doccomposer"
 	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/doctransformer/didtransformer"
 	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/docvalidator/didvalidator"
@@ -20,8 +19,10 @@ import (
 	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/operationparser"
 	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/txnprovider"
 
+	"github.com/trustbloc/orb/pkg/compression"
 	"github.com/trustbloc/orb/pkg/config"
 	ctxcommon "github.com/trustbloc/orb/pkg/context/common"
+	"github.com/trustbloc/orb/pkg/document/composition"
 	vcommon "github.com/trustbloc/orb/pkg/protocolversion/versions/common"
 	protocolcfg "github.com/trustbloc/orb/pkg/protocolversion/versions/v1_0/config"
 	orboperationparser "github.com/trustbloc/orb/pkg/versions/1_0/operationparser"
@@ -44,24 +45,30 @@ func (v *Factory) Create(version string, casClient cas.Client, casResolver ctxco
 	sidetreeCfg *config.Sidetree) (protocol.Version, error) {
 	p := protocolcfg.GetProtocolConfig()
 
+	if sidetreeCfg.CompressionAlgorithm != "" {
+		p.CompressionAlgorithm = sidetreeCfg.CompressionAlgorithm
+	}
+
 	opParser := operationparser.New(p,
 		operationparser.WithAnchorTimeValidator(anchortime.New(p.MaxOperationTimeDelta)),
 		operationparser.WithAnchorOriginValidator(anchororigin.New(sidetreeCfg.AnchorOrigins)))
 
 	orbParser := orboperationparser.New(opParser)
 
-	cp := compression.New(compression.WithDefaultAlgorithms())
+	cp := compression.New()
 	op := txnprovider.NewOperationProvider(p, opParser, &casReader{casResolver}, cp)
 	oh := txnprovider.NewOperationHandler(p, casClient, cp, opParser)
-	dc := doccomposer.New()
-	oa := operationapplier.New(p, opParser, dc)
+	dc := composition.NewComposer(doccomposer.New())
+	oa := composition.NewOperationApplier(operationapplier.New(p, opParser, dc), sidetreeCfg.CompositionErrors)
 
 	dv := didvalidator.New()
-	dt := didtransformer.New(
-		didtransformer.WithMethodContext(sidetreeCfg.MethodContext),
-		didtransformer.WithBase(sidetreeCfg.EnableBase),
-		didtransformer.WithIncludePublishedOperations(sidetreeCfg.IncludePublishedOperations),
-		didtransformer.WithIncludeUnpublishedOperations(sidetreeCfg.IncludeUnpublishedOperations))
+	dt := composition.NewTransformer(
+		didtransformer.New(
+			didtransformer.WithMethodContext(sidetreeCfg.MethodContext),
+			didtransformer.WithBase(sidetreeCfg.EnableBase),
+			didtransformer.WithIncludePublishedOperations(sidetreeCfg.IncludePublishedOperations),
+			didtransformer.WithIncludeUnpublishedOperations(sidetreeCfg.IncludeUnpublishedOperations)),
+		sidetreeCfg.CompositionErrors)
 
 	var orbTxnProcessorOpts []txnprocessor.Option
 
@@ -19,7 +19,9 @@ import (
 	"github.com/trustbloc/orb/pkg/activitypub/client/transport"
 	"github.com/trustbloc/orb/pkg/cas/extendedcasclient"
 	casresolver "github.com/trustbloc/orb/pkg/cas/resolver"
+	"github.com/trustbloc/orb/pkg/compression"
 	"github.com/trustbloc/orb/pkg/config"
+	"github.com/trustbloc/orb/pkg/document/composition"
 	"github.com/trustbloc/orb/pkg/internal/testutil"
 	orbmocks "github.com/trustbloc/orb/pkg/mocks"
 	"github.com/trustbloc/orb/pkg/protocolversion/mocks"
@@ -44,6 +46,22 @@ func TestFactory_Create(t *testing.T) {
 		require.NotNil(t, pv)
 	})
 
+	t.Run("compression algorithm", func(t *testing.T) {
+		pv, err := f.Create("1.0", casClient, casResolver, opStore, storeProvider,
+			&config.Sidetree{CompressionAlgorithm: compression.ZSTD})
+		require.NoError(t, err)
+		require.Equal(t, compression.ZSTD, pv.Protocol().CompressionAlgorithm)
+	})
+
+	t.Run("composition errors", func(t *testing.T) {
+		pv, err := f.Create("1.0", casClient, casResolver, opStore, storeProvider,
+			&config.Sidetree{CompositionErrors: composition.NewRecorder(0)})
+		require.NoError(t, err)
+		require.IsType(t, &composition.Composer{}, pv.DocumentComposer())
+		require.IsType(t, &composition.OperationApplier{}, pv.OperationApplier())
+		require.IsType(t, &composition.Transformer{}, pv.DocumentTransformer())
+	})
+
 	t.Run("success - with update store config", func(t *testing.T) {
 		updateDocumentStore, err := unpublishedopstore.New(storeProvider, time.Minute,
 			testutil.GetExpiryService(t), &orbmocks.MetricsProvider{})
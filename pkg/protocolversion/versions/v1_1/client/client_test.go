@@ -0,0 +1,37 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/config"
+	"github.com/trustbloc/orb/pkg/protocolversion/mocks"
+)
+
+func TestFactory_Create(t *testing.T) {
+	f := New()
+	require.NotNil(t, f)
+
+	casClient := &mocks.CasClient{}
+
+	t.Run("success", func(t *testing.T) {
+		pv, err := f.Create("1.1", casClient, &config.Sidetree{})
+		require.NoError(t, err)
+		require.NotNil(t, pv)
+		require.Equal(t, "1.1", pv.Version())
+		require.Equal(t, uint64(1), pv.Protocol().GenesisTime)
+	})
+
+	t.Run("success - with anchor origins", func(t *testing.T) {
+		pv, err := f.Create("1.1", casClient, &config.Sidetree{AnchorOrigins: []string{"https://orb.domain1.com"}})
+		require.NoError(t, err)
+		require.NotNil(t, pv)
+	})
+}
@@ -0,0 +1,44 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+)
+
+// GenesisTime is the genesis time of protocol version 1.1. The genesis time is the protocol version that is
+// recorded in an anchor, so anchors created before this version was activated (genesis time 0) continue to be
+// processed with version 1.0.
+const GenesisTime = 1
+
+// GetProtocolConfig returns protocol config for this version. In addition to the version 1.0 parameters, this
+// version allows larger operations and also accepts SHA2-512 multihashes. SHA2-256 remains the first (default)
+// hash algorithm so that unique suffixes are calculated the same way as in version 1.0.
+func GetProtocolConfig() protocol.Protocol {
+	//nolint:gomnd
+	p := protocol.Protocol{
+		GenesisTime:                  GenesisTime,
+		MultihashAlgorithms:          []uint{18, 19},
+		MaxOperationCount:            5000,
+		MaxOperationSize:             4000,
+		MaxOperationHashLength:       200,
+		MaxDeltaSize:                 3000,
+		MaxCasURILength:              500,
+		CompressionAlgorithm:         "GZIP",
+		MaxChunkFileSize:             20000000,
+		MaxProvisionalIndexFileSize:  1000000,
+		MaxCoreIndexFileSize:         1000000,
+		MaxProofFileSize:             4000000,
+		Patches:                      []string{"add-public-keys", "remove-public-keys", "add-services", "remove-services", "ietf-json-patch"}, //nolint:lll
+		SignatureAlgorithms:          []string{"EdDSA", "ES256", "ES256K"},
+		KeyAlgorithms:                []string{"Ed25519", "P-256", "secp256k1"},
+		MaxMemoryDecompressionFactor: 3,
+		NonceSize:                    16,
+	}
+
+	return p
+}
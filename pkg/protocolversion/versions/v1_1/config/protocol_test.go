@@ -0,0 +1,33 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	v1_0config "github.com/trustbloc/orb/pkg/protocolversion/versions/v1_0/config"
+)
+
+func TestGetProtocolConfig(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		cfg := GetProtocolConfig()
+		require.Equal(t, uint64(GenesisTime), cfg.GenesisTime)
+		require.Equal(t, uint(4000), cfg.MaxOperationSize)
+	})
+
+	t.Run("compatible with 1.0", func(t *testing.T) {
+		cfg := GetProtocolConfig()
+		cfg1_0 := v1_0config.GetProtocolConfig()
+
+		require.Greater(t, cfg.GenesisTime, cfg1_0.GenesisTime)
+		require.Equal(t, cfg1_0.MultihashAlgorithms[0], cfg.MultihashAlgorithms[0])
+		require.GreaterOrEqual(t, cfg.MaxOperationSize, cfg1_0.MaxOperationSize)
+		require.GreaterOrEqual(t, cfg.MaxDeltaSize, cfg1_0.MaxDeltaSize)
+	})
+}
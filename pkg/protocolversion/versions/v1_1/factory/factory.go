@@ -0,0 +1,114 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package factory
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/cas"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/doccomposer"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/doctransformer/didtransformer"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/docvalidator/didvalidator"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/operationapplier"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/operationparser"
+	"github.com/trustbloc/sidetree-core-go/pkg/versions/1_0/txnprovider"
+
+	"github.com/trustbloc/orb/pkg/compression"
+	"github.com/trustbloc/orb/pkg/config"
+	ctxcommon "github.com/trustbloc/orb/pkg/context/common"
+	"github.com/trustbloc/orb/pkg/document/composition"
+	vcommon "github.com/trustbloc/orb/pkg/protocolversion/versions/common"
+	protocolcfg "github.com/trustbloc/orb/pkg/protocolversion/versions/v1_1/config"
+	orboperationparser "github.com/trustbloc/orb/pkg/versions/1_0/operationparser"
+	"github.com/trustbloc/orb/pkg/versions/1_0/operationparser/validators/anchororigin"
+	"github.com/trustbloc/orb/pkg/versions/1_0/operationparser/validators/anchortime"
+	"github.com/trustbloc/orb/pkg/versions/1_0/txnprocessor"
+)
+
+// Factory implements version 1.1 of the Sidetree protocol.
+type Factory struct{}
+
+// New returns a version 1.1 implementation of the Sidetree protocol.
+func New() *Factory {
+	return &Factory{}
+}
+
+// Create creates a new protocol version.
+func (v *Factory) Create(version string, casClient cas.Client, casResolver ctxcommon.CASResolver,
+	opStore ctxcommon.OperationStore, provider storage.Provider,
+	sidetreeCfg *config.Sidetree) (protocol.Version, error) {
+	p := protocolcfg.GetProtocolConfig()
+
+	if sidetreeCfg.CompressionAlgorithm != "" {
+		p.CompressionAlgorithm = sidetreeCfg.CompressionAlgorithm
+	}
+
+	opParser := operationparser.New(p,
+		operationparser.WithAnchorTimeValidator(anchortime.New(p.MaxOperationTimeDelta)),
+		operationparser.WithAnchorOriginValidator(anchororigin.New(sidetreeCfg.AnchorOrigins)))
+
+	orbParser := orboperationparser.New(opParser)
+
+	cp := compression.New()
+	op := txnprovider.NewOperationProvider(p, opParser, &casReader{casResolver}, cp)
+	oh := txnprovider.NewOperationHandler(p, casClient, cp, opParser)
+	dc := composition.NewComposer(doccomposer.New())
+	oa := composition.NewOperationApplier(operationapplier.New(p, opParser, dc), sidetreeCfg.CompositionErrors)
+
+	dv := didvalidator.New()
+	dt := composition.NewTransformer(
+		didtransformer.New(
+			didtransformer.WithMethodContext(sidetreeCfg.MethodContext),
+			didtransformer.WithBase(sidetreeCfg.EnableBase),
+			didtransformer.WithIncludePublishedOperations(sidetreeCfg.IncludePublishedOperations),
+			didtransformer.WithIncludeUnpublishedOperations(sidetreeCfg.IncludeUnpublishedOperations)),
+		sidetreeCfg.CompositionErrors)
+
+	var orbTxnProcessorOpts []txnprocessor.Option
+
+	if sidetreeCfg.UnpublishedOpStore != nil {
+		orbTxnProcessorOpts = append(orbTxnProcessorOpts,
+			txnprocessor.WithUnpublishedOperationStore(sidetreeCfg.UnpublishedOpStore,
+				sidetreeCfg.UpdateDocumentStoreTypes))
+	}
+
+	orbTxnProcessor := txnprocessor.New(
+		&txnprocessor.Providers{
+			OpStore:                   opStore,
+			OperationProtocolProvider: op,
+		},
+		orbTxnProcessorOpts...,
+	)
+
+	return &vcommon.ProtocolVersion{
+		VersionStr:     version,
+		P:              p,
+		TxnProcessor:   orbTxnProcessor,
+		OpParser:       orbParser,
+		OpApplier:      oa,
+		DocComposer:    dc,
+		OpHandler:      oh,
+		OpProvider:     op,
+		DocValidator:   dv,
+		DocTransformer: dt,
+	}, nil
+}
+
+type casReader struct {
+	resolver ctxcommon.CASResolver
+}
+
+func (c *casReader) Read(cid string) ([]byte, error) {
+	data, _, err := c.resolver.Resolve(nil, cid, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve CID: %w", err)
+	}
+
+	return data, nil
+}
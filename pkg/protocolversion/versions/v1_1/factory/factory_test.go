@@ -0,0 +1,75 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package factory
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/compression"
+	"github.com/trustbloc/orb/pkg/config"
+	"github.com/trustbloc/orb/pkg/document/composition"
+	"github.com/trustbloc/orb/pkg/protocolversion/mocks"
+	storemocks "github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+func TestFactory_Create(t *testing.T) {
+	f := New()
+	require.NotNil(t, f)
+
+	casClient := &mocks.CasClient{}
+	opStore := &mocks.OperationStore{}
+	casResolver := &mocks.CASResolver{}
+	storeProvider := &storemocks.Provider{}
+
+	t.Run("success", func(t *testing.T) {
+		pv, err := f.Create("1.1", casClient, casResolver, opStore, storeProvider, &config.Sidetree{})
+		require.NoError(t, err)
+		require.NotNil(t, pv)
+		require.Equal(t, "1.1", pv.Version())
+		require.Equal(t, uint64(1), pv.Protocol().GenesisTime)
+		require.Equal(t, []uint{18, 19}, pv.Protocol().MultihashAlgorithms)
+	})
+
+	t.Run("compression algorithm", func(t *testing.T) {
+		pv, err := f.Create("1.1", casClient, casResolver, opStore, storeProvider,
+			&config.Sidetree{CompressionAlgorithm: compression.ZSTD})
+		require.NoError(t, err)
+		require.Equal(t, compression.ZSTD, pv.Protocol().CompressionAlgorithm)
+	})
+
+	t.Run("composition errors", func(t *testing.T) {
+		pv, err := f.Create("1.1", casClient, casResolver, opStore, storeProvider,
+			&config.Sidetree{CompositionErrors: composition.NewRecorder(0)})
+		require.NoError(t, err)
+		require.IsType(t, &composition.Composer{}, pv.DocumentComposer())
+		require.IsType(t, &composition.OperationApplier{}, pv.OperationApplier())
+		require.IsType(t, &composition.Transformer{}, pv.DocumentTransformer())
+	})
+}
+
+func TestCasReader_Read(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		casResolver := &mocks.CASResolver{}
+		casResolver.ResolveReturns([]byte("sample data"), "", nil)
+
+		data, err := (&casReader{resolver: casResolver}).Read("cid")
+		require.NoError(t, err)
+		require.Equal(t, "sample data", string(data))
+	})
+
+	t.Run("fail to resolve", func(t *testing.T) {
+		casResolver := &mocks.CASResolver{}
+		casResolver.ResolveReturns(nil, "", errors.New("injected resolve error"))
+
+		data, err := (&casReader{resolver: casResolver}).Read("cid")
+		require.EqualError(t, err, "failed to resolve CID: injected resolve error")
+		require.Nil(t, data)
+	})
+}
@@ -69,6 +69,20 @@ type Config struct {
 	RedeliveryMultiplier       float64
 	RedeliveryInitialInterval  time.Duration
 	MaxRedeliveryInterval      time.Duration
+
+	// FailoverURIs contains the URIs of the other nodes of the AMQP cluster. If the node at URI (or the node that
+	// is currently connected) is unavailable then a connection is made to the next available node. Only the host
+	// and port of a failover URI are used; the credentials and virtual host of URI apply to all nodes.
+	FailoverURIs []string
+
+	// PublisherConfirmsEnabled, if true, causes Publish to wait until the broker has confirmed each message. The
+	// messages are published with the mandatory flag so that an error is returned if a message can't be routed to
+	// a queue. A message that isn't confirmed (for example, because the node went down while publishing) results
+	// in a transient error so that the message may be published again.
+	PublisherConfirmsEnabled bool
+
+	// PublishConfirmTimeout is the maximum amount of time to wait for the broker to confirm a message.
+	PublishConfirmTimeout time.Duration
 }
 
 type closeable interface {
@@ -127,9 +141,20 @@ type PubSub struct {
 func New(cfg Config) *PubSub {
 	cfg = initConfig(cfg)
 
+	connectionConfig := amqp.ConnectionConfig{AmqpURI: cfg.URI}
+
+	if len(cfg.FailoverURIs) > 0 {
+		dialer, err := newFailoverDialer(append([]string{cfg.URI}, cfg.FailoverURIs...))
+		if err != nil {
+			panic(fmt.Sprintf("Invalid AMQP failover configuration: %s", err))
+		}
+
+		connectionConfig.AmqpConfig = dialer.amqpConfig()
+	}
+
 	p := &PubSub{
 		Config:               cfg,
-		connMgr:              newConnectionMgr(amqp.ConnectionConfig{AmqpURI: cfg.URI}, cfg.MaxConnectionSubscriptions),
+		connMgr:              newConnectionMgr(connectionConfig, cfg.MaxConnectionSubscriptions),
 		amqpConfig:           newQueueConfig(cfg.URI),
 		amqpRedeliveryConfig: newRedeliveryQueueConfig(cfg.URI),
 		amqpWaitConfig:       newWaitQueueConfig(cfg.URI),
@@ -159,6 +184,19 @@ func New(cfg Config) *PubSub {
 		return amqp.NewPublisherWithConnection(p.amqpWaitConfig, wmlogger.New(), conn.amqpConnection())
 	}
 
+	if cfg.PublisherConfirmsEnabled {
+		// The confirming publishers use their own connection since confirm mode applies to the whole channel.
+		dial := newAMQPDialer(cfg.URI, connectionConfig.AmqpConfig)
+
+		p.createPublisher = func(connection) (publisher, error) {
+			return newConfirmingPublisher(p.amqpConfig, dial, cfg.PublishConfirmTimeout), nil
+		}
+
+		p.createWaitPublisher = func(connection) (publisher, error) {
+			return newConfirmingPublisher(p.amqpWaitConfig, dial, cfg.PublishConfirmTimeout), nil
+		}
+	}
+
 	// Start the service immediately.
 	p.Start()
 
@@ -0,0 +1,270 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package amqp
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill-amqp/pkg/amqp"
+	"github.com/ThreeDotsLabs/watermill/message"
+	samqp "github.com/streadway/amqp"
+)
+
+const defaultPublishConfirmTimeout = 10 * time.Second
+
+var (
+	// ErrMessageNacked indicates that the broker rejected a published message.
+	ErrMessageNacked = errors.New("message was nacked by the broker")
+
+	// ErrMessageReturned indicates that a published message couldn't be routed to any queue.
+	ErrMessageReturned = errors.New("message was returned by the broker")
+
+	errPublisherClosed = errors.New("publisher is closed")
+	errChannelClosed   = errors.New("channel closed while waiting for publisher confirm")
+	errConfirmTimeout  = errors.New("timed out waiting for publisher confirm")
+)
+
+type amqpChannel interface {
+	Confirm(noWait bool) error
+	NotifyPublish(confirm chan samqp.Confirmation) chan samqp.Confirmation
+	NotifyReturn(ret chan samqp.Return) chan samqp.Return
+	ExchangeDeclare(name, kind string, durable, autoDelete, internal, noWait bool, args samqp.Table) error
+	Publish(exchange, key string, mandatory, immediate bool, msg samqp.Publishing) error
+	Close() error
+}
+
+type amqpConnection interface {
+	Channel() (amqpChannel, error)
+	Close() error
+}
+
+type amqpDialer = func() (amqpConnection, error)
+
+// confirmingPublisher publishes messages on a channel that's in confirm mode and waits for the broker to
+// confirm each message. Messages are published with the mandatory flag so that a message which can't be routed
+// to a queue is returned by the broker instead of being silently dropped. If the connection is lost while
+// publishing (for example, because a broker node went down) then an error is returned, so that the message may
+// be published again, and a new connection is established on the next publish.
+type confirmingPublisher struct {
+	config  amqp.Config
+	timeout time.Duration
+	dial    amqpDialer
+
+	mutex     sync.Mutex
+	conn      amqpConnection
+	channel   amqpChannel
+	confirms  chan samqp.Confirmation
+	returns   chan samqp.Return
+	exchanges map[string]struct{}
+	closed    bool
+}
+
+func newConfirmingPublisher(config amqp.Config, dial amqpDialer, timeout time.Duration) *confirmingPublisher {
+	if timeout == 0 {
+		timeout = defaultPublishConfirmTimeout
+	}
+
+	return &confirmingPublisher{
+		config:  config,
+		timeout: timeout,
+		dial:    dial,
+	}
+}
+
+// Publish publishes the given messages to the given topic. The function returns after all messages have been
+// confirmed by the broker or when the first message fails.
+func (p *confirmingPublisher) Publish(topic string, messages ...*message.Message) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.closed {
+		return errPublisherClosed
+	}
+
+	for _, msg := range messages {
+		if err := p.publish(topic, msg); err != nil {
+			if !errors.Is(err, ErrMessageNacked) && !errors.Is(err, ErrMessageReturned) {
+				// The channel may be unusable, so reconnect on the next publish.
+				p.reset()
+			}
+
+			return fmt.Errorf("publish message [%s] to topic [%s]: %w", msg.UUID, topic, err)
+		}
+
+		logger.Debugf("Message [%s] to topic [%s] was confirmed by the broker", msg.UUID, topic)
+	}
+
+	return nil
+}
+
+// Close closes the channel and connection.
+func (p *confirmingPublisher) Close() error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.closed = true
+	p.reset()
+
+	return nil
+}
+
+func (p *confirmingPublisher) publish(topic string, msg *message.Message) error {
+	if err := p.ensureChannel(); err != nil {
+		return err
+	}
+
+	exchangeName := p.config.Exchange.GenerateName(topic)
+
+	if err := p.declareExchange(exchangeName); err != nil {
+		return err
+	}
+
+	publishing, err := p.config.Marshaler.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("marshal message: %w", err)
+	}
+
+	err = p.channel.Publish(exchangeName, p.config.Publish.GenerateRoutingKey(topic), true, false, publishing)
+	if err != nil {
+		return fmt.Errorf("publish: %w", err)
+	}
+
+	return p.waitForConfirm()
+}
+
+func (p *confirmingPublisher) waitForConfirm() error {
+	timer := time.NewTimer(p.timeout)
+	defer timer.Stop()
+
+	select {
+	case confirm, ok := <-p.confirms:
+		if !ok {
+			return errChannelClosed
+		}
+
+		// A returned message is also acked by the broker. The return is always sent before the ack.
+		select {
+		case ret := <-p.returns:
+			return fmt.Errorf("%w: %d %s", ErrMessageReturned, ret.ReplyCode, ret.ReplyText)
+		default:
+		}
+
+		if !confirm.Ack {
+			return ErrMessageNacked
+		}
+
+		return nil
+	case <-timer.C:
+		return errConfirmTimeout
+	}
+}
+
+func (p *confirmingPublisher) ensureChannel() error {
+	if p.channel != nil {
+		return nil
+	}
+
+	conn, err := p.dial()
+	if err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+
+	channel, err := conn.Channel()
+	if err != nil {
+		_ = conn.Close() //nolint:errcheck
+
+		return fmt.Errorf("open channel: %w", err)
+	}
+
+	if e := channel.Confirm(false); e != nil {
+		_ = channel.Close() //nolint:errcheck
+		_ = conn.Close()    //nolint:errcheck
+
+		return fmt.Errorf("put channel into confirm mode: %w", e)
+	}
+
+	p.conn = conn
+	p.channel = channel
+	p.confirms = channel.NotifyPublish(make(chan samqp.Confirmation, 1))
+	p.returns = channel.NotifyReturn(make(chan samqp.Return, 1))
+	p.exchanges = make(map[string]struct{})
+
+	logger.Debugf("Opened publisher channel in confirm mode")
+
+	return nil
+}
+
+func (p *confirmingPublisher) declareExchange(name string) error {
+	if _, ok := p.exchanges[name]; ok {
+		return nil
+	}
+
+	err := p.channel.ExchangeDeclare(name, p.config.Exchange.Type, p.config.Exchange.Durable,
+		false, false, false, nil)
+	if err != nil {
+		return fmt.Errorf("declare exchange [%s]: %w", name, err)
+	}
+
+	p.exchanges[name] = struct{}{}
+
+	return nil
+}
+
+func (p *confirmingPublisher) reset() {
+	if p.channel != nil {
+		if err := p.channel.Close(); err != nil {
+			logger.Debugf("Error closing publisher channel: %s", err)
+		}
+	}
+
+	if p.conn != nil {
+		if err := p.conn.Close(); err != nil {
+			logger.Debugf("Error closing publisher connection: %s", err)
+		}
+	}
+
+	p.channel = nil
+	p.conn = nil
+}
+
+// connectionAdapter adapts the AMQP client connection to the amqpConnection interface.
+type connectionAdapter struct {
+	*samqp.Connection
+}
+
+func (c *connectionAdapter) Channel() (amqpChannel, error) {
+	channel, err := c.Connection.Channel()
+	if err != nil {
+		return nil, err
+	}
+
+	return channel, nil
+}
+
+func newAMQPDialer(uri string, config *samqp.Config) amqpDialer {
+	return func() (amqpConnection, error) {
+		var (
+			conn *samqp.Connection
+			err  error
+		)
+
+		if config != nil {
+			conn, err = samqp.DialConfig(uri, *config)
+		} else {
+			conn, err = samqp.Dial(uri)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		return &connectionAdapter{Connection: conn}, nil
+	}
+}
@@ -0,0 +1,113 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package amqp
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	samqp "github.com/streadway/amqp"
+)
+
+const (
+	defaultDialTimeout = 30 * time.Second
+	defaultHeartbeat   = 10 * time.Second
+	defaultLocale      = "en_US"
+)
+
+type dialFunc = func(network, addr string, timeout time.Duration) (net.Conn, error)
+
+// failoverDialer connects to the first available node of an AMQP cluster. The nodes are tried in order,
+// starting with the node to which the last successful connection was made, so that a connection that is
+// dropped because a node went down is re-established with one of the other nodes.
+type failoverDialer struct {
+	addresses []string
+	timeout   time.Duration
+	dial      dialFunc
+
+	mutex   sync.Mutex
+	current int
+}
+
+func newFailoverDialer(uris []string) (*failoverDialer, error) {
+	d := &failoverDialer{
+		timeout: defaultDialTimeout,
+		dial:    net.DialTimeout,
+	}
+
+	for _, uri := range uris {
+		u, err := samqp.ParseURI(uri)
+		if err != nil {
+			return nil, fmt.Errorf("parse AMQP URI [%s]: %w", extractEndpoint(uri), err)
+		}
+
+		d.addresses = append(d.addresses, net.JoinHostPort(u.Host, strconv.Itoa(u.Port)))
+	}
+
+	return d, nil
+}
+
+// Dial connects to the first available node. The given address (which is resolved from the primary URI)
+// is ignored.
+func (d *failoverDialer) Dial(network, _ string) (net.Conn, error) {
+	d.mutex.Lock()
+	start := d.current
+	d.mutex.Unlock()
+
+	var errs []string
+
+	for i := 0; i < len(d.addresses); i++ {
+		index := (start + i) % len(d.addresses)
+		addr := d.addresses[index]
+
+		conn, err := d.dial(network, addr, d.timeout)
+		if err != nil {
+			logger.Warnf("Error connecting to AMQP node [%s]: %s", addr, err)
+
+			errs = append(errs, fmt.Sprintf("%s: %s", addr, err))
+
+			continue
+		}
+
+		// Don't stall forever on a dead node during the TLS and AMQP handshake. The deadline is cleared
+		// by the AMQP client once the connection is established.
+		if e := conn.SetDeadline(time.Now().Add(d.timeout)); e != nil {
+			_ = conn.Close() //nolint:errcheck
+
+			errs = append(errs, fmt.Sprintf("%s: %s", addr, e))
+
+			continue
+		}
+
+		d.mutex.Lock()
+
+		if index != d.current {
+			logger.Warnf("Failing over from AMQP node [%s] to [%s]", d.addresses[d.current], addr)
+
+			d.current = index
+		}
+
+		d.mutex.Unlock()
+
+		return conn, nil
+	}
+
+	return nil, fmt.Errorf("unable to connect to any AMQP node: %s", strings.Join(errs, "; "))
+}
+
+// amqpConfig returns the AMQP client configuration which connects using the failover dialer.
+func (d *failoverDialer) amqpConfig() *samqp.Config {
+	return &samqp.Config{
+		Heartbeat: defaultHeartbeat,
+		Locale:    defaultLocale,
+		Dial:      d.Dial,
+	}
+}
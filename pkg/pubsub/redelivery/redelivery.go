@@ -52,6 +52,40 @@ type Config struct {
 
 	// MaxMessages is the maximum number of messages that can be concurrently managed by the redelivery service.
 	MaxMessages int
+
+	mutex sync.RWMutex
+}
+
+// SetMaxRetries updates the maximum number of retries. The new value applies to the messages that are
+// subsequently added for redelivery.
+func (c *Config) SetMaxRetries(maxRetries int) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.MaxRetries = maxRetries
+}
+
+// SetInitialBackoff updates the first interval between retries.
+func (c *Config) SetInitialBackoff(backoff time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.InitialBackoff = backoff
+}
+
+// SetMaxBackoff updates the limit for the exponential backoff of retries.
+func (c *Config) SetMaxBackoff(backoff time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.MaxBackoff = backoff
+}
+
+func (c *Config) maxRetries() int {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.MaxRetries
 }
 
 // DefaultConfig returns the default configuration parameters for the redelivery service.
@@ -122,7 +156,7 @@ func (m *Service) Add(msg *message.Message) (time.Time, error) {
 		redeliveryAttempts = ra
 	}
 
-	if redeliveryAttempts >= m.MaxRetries {
+	if redeliveryAttempts >= m.maxRetries() {
 		return time.Time{}, fmt.Errorf("unable to redeliver message after %d redelivery attempts", redeliveryAttempts)
 	}
 
@@ -198,10 +232,12 @@ func (m *Service) redeliver(entry *entry) {
 }
 
 func (m *Service) backoff(retries int) time.Duration {
-	backoff, max := float64(m.InitialBackoff), float64(m.MaxBackoff)
+	m.mutex.RLock()
+	backoff, max, factor := float64(m.InitialBackoff), float64(m.MaxBackoff), m.BackoffFactor
+	m.mutex.RUnlock()
 
 	for i := 0; i < retries && backoff < max; i++ {
-		backoff *= m.BackoffFactor
+		backoff *= factor
 	}
 
 	if backoff > max {
@@ -102,6 +102,29 @@ func TestBackoff(t *testing.T) {
 	require.Equal(t, cfg.MaxBackoff, s.backoff(10))
 }
 
+func TestConfig_Setters(t *testing.T) {
+	cfg := DefaultConfig()
+
+	s := NewService("service1", cfg, make(chan *message.Message, cfg.MaxMessages))
+
+	s.Start()
+	defer s.Stop()
+
+	cfg.SetMaxRetries(1)
+	cfg.SetInitialBackoff(10 * time.Millisecond)
+	cfg.SetMaxBackoff(20 * time.Millisecond)
+
+	require.Equal(t, 10*time.Millisecond, s.backoff(0))
+	require.Equal(t, 20*time.Millisecond, s.backoff(10))
+
+	msg := message.NewMessage(watermill.NewUUID(), []byte("payload"))
+	msg.Metadata[metadataRedeliveryAttempts] = "1"
+
+	_, err := s.Add(msg)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unable to redeliver message after 1 redelivery attempts")
+}
+
 func TestServiceStop(t *testing.T) {
 	cfg := &Config{
 		MaxRetries:     3,
@@ -17,6 +17,7 @@ import (
 	"github.com/trustbloc/sidetree-core-go/pkg/docutil"
 
 	"github.com/trustbloc/orb/pkg/didanchor"
+	"github.com/trustbloc/orb/pkg/document/util"
 	"github.com/trustbloc/orb/pkg/resolver/resource/registry"
 )
 
@@ -76,7 +77,7 @@ func (h *DidAnchorInfo) GetResourceInfo(did string) (registry.Metadata, error) {
 
 	info := make(registry.Metadata)
 	info[registry.AnchorURIProperty] = anchor
-	info[registry.AnchorOriginProperty] = resolutionResult.AnchorOrigin
+	info[registry.AnchorOriginProperty] = primaryAnchorOrigin(resolutionResult.AnchorOrigin)
 	info[registry.CanonicalReferenceProperty] = resolutionResult.CanonicalReference
 
 	logger.Debugf("latest anchor info for suffix[%s]: %+v", suffix, info)
@@ -104,3 +105,14 @@ func getSuffix(did string) (string, error) {
 
 	return did[adjustedPos:], nil
 }
+
+// primaryAnchorOrigin returns the primary anchor origin if the DID has fallback anchor origins (in which case the
+// anchor origin is a list). Otherwise the anchor origin is returned as is.
+func primaryAnchorOrigin(anchorOrigin interface{}) interface{} {
+	anchorOrigins, err := util.ParseAnchorOrigins(anchorOrigin)
+	if err != nil {
+		return anchorOrigin
+	}
+
+	return anchorOrigins[0]
+}
@@ -58,6 +58,28 @@ func TestDidAnchorInfo_GetResourceInfo(t *testing.T) {
 		require.Equal(t, testCID, info[registry.AnchorURIProperty])
 	})
 
+	t.Run("success - fallback anchor origins", func(t *testing.T) {
+		store, err := didanchorstore.New(mem.NewProvider())
+		require.NoError(t, err)
+
+		err = store.PutBulk([]string{testSuffix}, []bool{true}, testCID)
+		require.NoError(t, err)
+
+		operationProcessor := &mocks.OperationProcessor{}
+		operationProcessor.ResolveReturns(&protocol.ResolutionModel{
+			AnchorOrigin: []interface{}{testOrigin, "https://fallback.domain.com"},
+		}, nil)
+
+		didAnchoringProvider := New(testNS, store, operationProcessor)
+		require.NotNil(t, didAnchoringProvider)
+
+		info, err := didAnchoringProvider.GetResourceInfo(testID)
+		require.NoError(t, err)
+
+		// Only the primary anchor origin is returned.
+		require.Equal(t, testOrigin, info[registry.AnchorOriginProperty])
+	})
+
 	t.Run("error - suffix not provided", func(t *testing.T) {
 		didAnchoringProvider := New(testNS, nil, nil)
 		require.NotNil(t, didAnchoringProvider)
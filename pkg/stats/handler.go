@@ -0,0 +1,122 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package stats
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+)
+
+const (
+	// Path is the path of the stats endpoint.
+	Path = "/stats"
+
+	daysParam = "days"
+
+	// MaxDays is the maximum number of days that may be requested.
+	MaxDays = 366
+
+	internalServerErrorResponse = "Internal Server Error.\n"
+)
+
+type statsRetriever interface {
+	Get(days int) (*Stats, error)
+}
+
+// Handler implements the /stats REST endpoint. The optional 'days' query parameter specifies the number
+// of most recent days for which daily counts are returned.
+type Handler struct {
+	retriever statsRetriever
+	marshal   func(v interface{}) ([]byte, error)
+}
+
+// NewHandler returns the /stats REST handler.
+func NewHandler(retriever statsRetriever) *Handler {
+	return &Handler{
+		retriever: retriever,
+		marshal:   json.Marshal,
+	}
+}
+
+// Path returns the HTTP REST endpoint for the stats handler.
+func (h *Handler) Path() string {
+	return Path
+}
+
+// Method returns the HTTP REST method for the stats handler.
+func (h *Handler) Method() string {
+	return http.MethodGet
+}
+
+// Handler returns the HTTP REST handle for the stats handler.
+func (h *Handler) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Handler) handle(w http.ResponseWriter, req *http.Request) {
+	days, err := getDays(req)
+	if err != nil {
+		h.writeResponse(w, http.StatusBadRequest, []byte(err.Error()))
+
+		return
+	}
+
+	stats, err := h.retriever.Get(days)
+	if err != nil {
+		logger.Errorf("[%s] Error retrieving stats: %s", Path, err)
+
+		h.writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	statsBytes, err := h.marshal(stats)
+	if err != nil {
+		logger.Errorf("[%s] Error marshalling stats: %s", Path, err)
+
+		h.writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	h.writeResponse(w, http.StatusOK, statsBytes)
+}
+
+func (h *Handler) writeResponse(w http.ResponseWriter, status int, body []byte) {
+	w.WriteHeader(status)
+
+	if len(body) > 0 {
+		if _, err := w.Write(body); err != nil {
+			logger.Warnf("[%s] Unable to write response: %s", Path, err)
+
+			return
+		}
+
+		logger.Debugf("[%s] Wrote response: %s", Path, body)
+	}
+}
+
+func getDays(req *http.Request) (int, error) {
+	value := req.URL.Query().Get(daysParam)
+	if value == "" {
+		return 0, nil
+	}
+
+	days, err := strconv.Atoi(value)
+	if err != nil || days < 0 || days > MaxDays {
+		return 0, fmt.Errorf("invalid value for parameter '%s': %s. It must be between 0 and %d",
+			daysParam, value, MaxDays)
+	}
+
+	return days, nil
+}
@@ -0,0 +1,82 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package stats
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/internal/testutil/httptestutil"
+	"github.com/trustbloc/orb/pkg/observer"
+)
+
+func TestHandler(t *testing.T) {
+	a, err := NewAggregator(mem.NewProvider())
+	require.NoError(t, err)
+
+	a.AnchorProcessed(&observer.ProcessedAnchor{})
+
+	h := NewHandler(a)
+	require.Equal(t, Path, h.Path())
+	require.Equal(t, http.MethodGet, h.Method())
+	require.NotNil(t, h.Handler())
+
+	t.Run("Totals", func(t *testing.T) {
+		code, body := httptestutil.Get(t, h.handle, Path)
+		require.Equal(t, http.StatusOK, code)
+
+		stats := &Stats{}
+		require.NoError(t, json.Unmarshal(body, stats))
+		require.Equal(t, uint64(1), stats.Anchors)
+		require.Empty(t, stats.Days)
+	})
+
+	t.Run("Days", func(t *testing.T) {
+		code, body := httptestutil.Get(t, h.handle, Path+"?days=7")
+		require.Equal(t, http.StatusOK, code)
+
+		stats := &Stats{}
+		require.NoError(t, json.Unmarshal(body, stats))
+		require.Equal(t, uint64(1), stats.Anchors)
+		require.Len(t, stats.Days, 7)
+		require.Equal(t, uint64(1), stats.Days[0].Anchors)
+	})
+
+	t.Run("Invalid days", func(t *testing.T) {
+		for _, days := range []string{"xxx", "-1", "367"} {
+			code, body := httptestutil.Get(t, h.handle, Path+"?days="+days)
+			require.Equal(t, http.StatusBadRequest, code)
+			require.Contains(t, string(body), "invalid value for parameter 'days'")
+		}
+	})
+
+	t.Run("Retriever error", func(t *testing.T) {
+		code, _ := httptestutil.Get(t, NewHandler(&mockRetriever{err: errors.New("injected error")}).handle, Path)
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		h := NewHandler(a)
+		h.marshal = func(v interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		code, _ := httptestutil.Get(t, h.handle, Path)
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+}
+
+type mockRetriever struct {
+	err error
+}
+
+func (m *mockRetriever) Get(int) (*Stats, error) {
+	return nil, m.err
+}
@@ -0,0 +1,447 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package stats
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/edge-core/pkg/log"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+
+	apspi "github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/lifecycle"
+	"github.com/trustbloc/orb/pkg/observer"
+)
+
+var logger = log.New("stats")
+
+const (
+	storeName = "stats"
+
+	// totalTag is set on the document that holds the totals of an instance.
+	totalTag = "total"
+	// dateTag is set on the document that holds the daily counts of an instance. The value is the date.
+	dateTag = "date"
+
+	dateFormat = "2006-01-02"
+
+	defaultFlushInterval = 10 * time.Second
+)
+
+// activityCollections maps the reference types that are counted to the name of the collection in the stats.
+var activityCollections = map[apspi.ReferenceType]string{ //nolint:gochecknoglobals
+	apspi.Inbox:  "inbox",
+	apspi.Outbox: "outbox",
+	apspi.Liked:  "liked",
+	apspi.Like:   "likes",
+	apspi.Share:  "shares",
+}
+
+// Counts contains the counters that are maintained by the aggregator.
+type Counts struct {
+	Anchors    uint64            `json:"anchors"`
+	Operations map[string]uint64 `json:"operations"`
+	DIDs       uint64            `json:"dids"`
+	Activities map[string]uint64 `json:"activities"`
+}
+
+func newCounts() *Counts {
+	return &Counts{
+		Operations: make(map[string]uint64),
+		Activities: make(map[string]uint64),
+	}
+}
+
+func (c *Counts) add(other *Counts) {
+	c.Anchors += other.Anchors
+	c.DIDs += other.DIDs
+
+	for k, v := range other.Operations {
+		c.Operations[k] += v
+	}
+
+	for k, v := range other.Activities {
+		c.Activities[k] += v
+	}
+}
+
+func (c *Counts) copy() *Counts {
+	cpy := newCounts()
+	cpy.add(c)
+
+	return cpy
+}
+
+// DailyCounts contains the counts for a single day (UTC).
+type DailyCounts struct {
+	Date string `json:"date"`
+	*Counts
+}
+
+// Stats contains the totals of this Orb domain and, optionally, the counts of the most recent days.
+type Stats struct {
+	*Counts
+	Days []*DailyCounts `json:"days,omitempty"`
+}
+
+type options struct {
+	flushInterval time.Duration
+}
+
+// Opt sets an aggregator option.
+type Opt func(opts *options)
+
+// WithFlushInterval sets the interval at which the counters are persisted.
+func WithFlushInterval(value time.Duration) Opt {
+	return func(opts *options) {
+		opts.flushInterval = value
+	}
+}
+
+// Aggregator maintains counters of anchors, operations, DIDs and activities. The counters are updated
+// incrementally as events occur (rather than by scanning the underlying stores) and are periodically
+// persisted. Each instance persists its own counters under a unique ID so that multiple instances may
+// share the same database without overwriting each other's counts; the counters of all instances are
+// summed when the stats are retrieved.
+type Aggregator struct {
+	*lifecycle.Lifecycle
+
+	store         storage.Store
+	instanceID    string
+	flushInterval time.Duration
+	done          chan struct{}
+	wg            sync.WaitGroup
+	now           func() time.Time
+
+	mutex          sync.Mutex
+	total          *Counts
+	days           map[string]*Counts
+	version        uint64
+	flushedVersion uint64
+}
+
+// NewAggregator returns a new stats aggregator.
+func NewAggregator(provider storage.Provider, opts ...Opt) (*Aggregator, error) {
+	options := &options{
+		flushInterval: defaultFlushInterval,
+	}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	store, err := provider.OpenStore(storeName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open stats store: %w", err)
+	}
+
+	err = provider.SetStoreConfig(storeName, storage.StoreConfiguration{TagNames: []string{totalTag, dateTag}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set store configuration: %w", err)
+	}
+
+	a := &Aggregator{
+		store:         store,
+		instanceID:    uuid.New().String(),
+		flushInterval: options.flushInterval,
+		done:          make(chan struct{}),
+		now:           time.Now,
+		total:         newCounts(),
+		days:          make(map[string]*Counts),
+	}
+
+	a.Lifecycle = lifecycle.New("stats",
+		lifecycle.WithStart(a.start),
+		lifecycle.WithStop(a.stop),
+	)
+
+	return a, nil
+}
+
+// AnchorProcessed is invoked by the observer after an anchor has been processed.
+func (a *Aggregator) AnchorProcessed(*observer.ProcessedAnchor) {
+	a.update(func(c *Counts) {
+		c.Anchors++
+	})
+}
+
+// OperationsStored is invoked after the given operations have been added to the operation store.
+func (a *Aggregator) OperationsStored(ops []*operation.AnchoredOperation) {
+	if len(ops) == 0 {
+		return
+	}
+
+	a.update(func(c *Counts) {
+		for _, op := range ops {
+			c.Operations[string(op.Type)]++
+
+			if op.Type == operation.TypeCreate {
+				c.DIDs++
+			}
+		}
+	})
+}
+
+// ReferenceAdded is invoked after a reference has been added to the ActivityPub store. Only references to
+// activity collections are counted.
+func (a *Aggregator) ReferenceAdded(refType apspi.ReferenceType) {
+	collection, ok := activityCollections[refType]
+	if !ok {
+		return
+	}
+
+	a.update(func(c *Counts) {
+		c.Activities[collection]++
+	})
+}
+
+// Get returns the totals and, if days is greater than zero, the counts of the given number of most recent
+// days, starting with today.
+func (a *Aggregator) Get(days int) (*Stats, error) {
+	total, err := a.sum(totalTag, a.instanceID, a.localTotal())
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &Stats{Counts: total}
+
+	today := a.now().UTC()
+
+	for i := 0; i < days; i++ {
+		date := today.AddDate(0, 0, -i).Format(dateFormat)
+
+		counts, e := a.sum(fmt.Sprintf("%s:%s", dateTag, date), dayKey(a.instanceID, date), a.localDay(date))
+		if e != nil {
+			return nil, e
+		}
+
+		stats.Days = append(stats.Days, &DailyCounts{Date: date, Counts: counts})
+	}
+
+	return stats, nil
+}
+
+func (a *Aggregator) update(apply func(c *Counts)) {
+	date := a.now().UTC().Format(dateFormat)
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	day, ok := a.days[date]
+	if !ok {
+		day = newCounts()
+		a.days[date] = day
+	}
+
+	apply(a.total)
+	apply(day)
+
+	a.version++
+}
+
+func (a *Aggregator) localTotal() *Counts {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	return a.total.copy()
+}
+
+func (a *Aggregator) localDay(date string) *Counts {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	day, ok := a.days[date]
+	if !ok {
+		return nil
+	}
+
+	return day.copy()
+}
+
+// sum adds up the counts of all instances for the given query. The stored counts of this instance are
+// replaced with the local counts (if any) since the stored counts may not yet have been flushed.
+func (a *Aggregator) sum(query, localKey string, local *Counts) (*Counts, error) {
+	it, err := a.store.Query(query)
+	if err != nil {
+		return nil, orberrors.NewTransient(fmt.Errorf("query stats [%s]: %w", query, err))
+	}
+
+	defer func() {
+		if e := it.Close(); e != nil {
+			logger.Warnf("Error closing iterator: %s", e)
+		}
+	}()
+
+	total := newCounts()
+
+	for {
+		ok, e := it.Next()
+		if e != nil {
+			return nil, orberrors.NewTransient(fmt.Errorf("next stats [%s]: %w", query, e))
+		}
+
+		if !ok {
+			break
+		}
+
+		key, e := it.Key()
+		if e != nil {
+			return nil, orberrors.NewTransient(fmt.Errorf("get key from iterator: %w", e))
+		}
+
+		if key == localKey && local != nil {
+			continue
+		}
+
+		value, e := it.Value()
+		if e != nil {
+			return nil, orberrors.NewTransient(fmt.Errorf("get value from iterator: %w", e))
+		}
+
+		counts := newCounts()
+
+		e = json.Unmarshal(value, counts)
+		if e != nil {
+			return nil, fmt.Errorf("unmarshal stats [%s]: %w", key, e)
+		}
+
+		total.add(counts)
+	}
+
+	if local != nil {
+		total.add(local)
+	}
+
+	return total, nil
+}
+
+func (a *Aggregator) start() {
+	a.wg.Add(1)
+
+	go a.run()
+
+	logger.Infof("Started stats aggregator [%s]", a.instanceID)
+}
+
+func (a *Aggregator) stop() {
+	close(a.done)
+
+	a.wg.Wait()
+
+	// Persist the counts that were collected since the last flush.
+	a.flush()
+
+	logger.Infof("Stopped stats aggregator [%s]", a.instanceID)
+}
+
+func (a *Aggregator) run() {
+	defer a.wg.Done()
+
+	ticker := time.NewTicker(a.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			a.flush()
+		case <-a.done:
+			return
+		}
+	}
+}
+
+func (a *Aggregator) flush() {
+	version, ops, err := a.snapshot()
+	if err != nil {
+		logger.Errorf("Error marshalling stats: %s", err)
+
+		return
+	}
+
+	if len(ops) == 0 {
+		return
+	}
+
+	// The store is written outside of the lock so that updates aren't blocked.
+	err = a.store.Batch(ops)
+	if err != nil {
+		// The counts are kept in memory, so they'll be persisted on the next flush.
+		logger.Warnf("Error persisting stats: %s", err)
+
+		return
+	}
+
+	logger.Debugf("Persisted stats for instance [%s]", a.instanceID)
+
+	today := a.now().UTC().Format(dateFormat)
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.flushedVersion = version
+
+	if a.version != version {
+		// The counts were updated while flushing. Keep all days until the next flush.
+		return
+	}
+
+	// The counts of previous days won't change anymore and they've been persisted.
+	for date := range a.days {
+		if date != today {
+			delete(a.days, date)
+		}
+	}
+}
+
+// snapshot returns the store operations for the current counts along with the version of the counts.
+// No operations are returned if the counts haven't changed since the last flush.
+func (a *Aggregator) snapshot() (uint64, []storage.Operation, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if a.version == a.flushedVersion {
+		return a.version, nil, nil
+	}
+
+	totalBytes, err := json.Marshal(a.total)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	ops := []storage.Operation{
+		{
+			Key:   a.instanceID,
+			Value: totalBytes,
+			Tags:  []storage.Tag{{Name: totalTag}},
+		},
+	}
+
+	for date, counts := range a.days {
+		dayBytes, e := json.Marshal(counts)
+		if e != nil {
+			return 0, nil, e
+		}
+
+		ops = append(ops, storage.Operation{
+			Key:   dayKey(a.instanceID, date),
+			Value: dayBytes,
+			Tags:  []storage.Tag{{Name: dateTag, Value: date}},
+		})
+	}
+
+	return a.version, ops, nil
+}
+
+func dayKey(instanceID, date string) string {
+	return fmt.Sprintf("%s-%s", instanceID, date)
+}
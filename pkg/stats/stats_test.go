@@ -0,0 +1,290 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package stats
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+
+	apspi "github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/observer"
+	"github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+func TestNewAggregator(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		a, err := NewAggregator(mem.NewProvider(), WithFlushInterval(time.Second))
+		require.NoError(t, err)
+		require.NotNil(t, a)
+		require.Equal(t, time.Second, a.flushInterval)
+	})
+
+	t.Run("Open store error", func(t *testing.T) {
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(nil, errors.New("injected open error"))
+
+		_, err := NewAggregator(provider)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected open error")
+	})
+
+	t.Run("Set store config error", func(t *testing.T) {
+		provider := &mocks.Provider{}
+		provider.SetStoreConfigReturns(errors.New("injected config error"))
+
+		_, err := NewAggregator(provider)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected config error")
+	})
+}
+
+func TestAggregator_Get(t *testing.T) {
+	a, err := NewAggregator(mem.NewProvider())
+	require.NoError(t, err)
+
+	a.AnchorProcessed(&observer.ProcessedAnchor{})
+	a.AnchorProcessed(&observer.ProcessedAnchor{})
+
+	a.OperationsStored(nil)
+	a.OperationsStored([]*operation.AnchoredOperation{
+		{Type: operation.TypeCreate},
+		{Type: operation.TypeUpdate},
+		{Type: operation.TypeCreate},
+	})
+
+	a.ReferenceAdded(apspi.Inbox)
+	a.ReferenceAdded(apspi.Outbox)
+	a.ReferenceAdded(apspi.PublicOutbox)
+	a.ReferenceAdded(apspi.Follower)
+
+	stats, err := a.Get(0)
+	require.NoError(t, err)
+	require.Empty(t, stats.Days)
+	require.Equal(t, uint64(2), stats.Anchors)
+	require.Equal(t, uint64(2), stats.DIDs)
+	require.Equal(t, map[string]uint64{"create": 2, "update": 1}, stats.Operations)
+	require.Equal(t, map[string]uint64{"inbox": 1, "outbox": 1}, stats.Activities)
+
+	stats, err = a.Get(2)
+	require.NoError(t, err)
+	require.Len(t, stats.Days, 2)
+	require.Equal(t, time.Now().UTC().Format(dateFormat), stats.Days[0].Date)
+	require.Equal(t, uint64(2), stats.Days[0].Anchors)
+	require.Equal(t, time.Now().UTC().AddDate(0, 0, -1).Format(dateFormat), stats.Days[1].Date)
+	require.Zero(t, stats.Days[1].Anchors)
+}
+
+func TestAggregator_Flush(t *testing.T) {
+	t.Run("Multiple instances", func(t *testing.T) {
+		provider := mem.NewProvider()
+
+		a1, err := NewAggregator(provider)
+		require.NoError(t, err)
+
+		a2, err := NewAggregator(provider)
+		require.NoError(t, err)
+
+		a1.AnchorProcessed(&observer.ProcessedAnchor{})
+		a1.flush()
+
+		a2.AnchorProcessed(&observer.ProcessedAnchor{})
+
+		stats, err := a2.Get(1)
+		require.NoError(t, err)
+		require.Equal(t, uint64(2), stats.Anchors)
+		require.Equal(t, uint64(2), stats.Days[0].Anchors)
+
+		// Not yet flushed by a2.
+		stats, err = a1.Get(1)
+		require.NoError(t, err)
+		require.Equal(t, uint64(1), stats.Anchors)
+
+		a2.flush()
+
+		stats, err = a1.Get(1)
+		require.NoError(t, err)
+		require.Equal(t, uint64(2), stats.Anchors)
+		require.Equal(t, uint64(2), stats.Days[0].Anchors)
+	})
+
+	t.Run("Previous days", func(t *testing.T) {
+		a, err := NewAggregator(mem.NewProvider())
+		require.NoError(t, err)
+
+		now := time.Now()
+
+		a.now = func() time.Time { return now.AddDate(0, 0, -1) }
+
+		a.AnchorProcessed(&observer.ProcessedAnchor{})
+		a.AnchorProcessed(&observer.ProcessedAnchor{})
+
+		a.now = func() time.Time { return now }
+
+		a.AnchorProcessed(&observer.ProcessedAnchor{})
+
+		a.flush()
+
+		require.Len(t, a.days, 1)
+
+		stats, err := a.Get(3)
+		require.NoError(t, err)
+		require.Equal(t, uint64(3), stats.Anchors)
+		require.Len(t, stats.Days, 3)
+		require.Equal(t, uint64(1), stats.Days[0].Anchors)
+		require.Equal(t, uint64(2), stats.Days[1].Anchors)
+		require.Zero(t, stats.Days[2].Anchors)
+	})
+
+	t.Run("Batch error", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.BatchReturns(errors.New("injected batch error"))
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		a, err := NewAggregator(provider)
+		require.NoError(t, err)
+
+		a.flush()
+		require.Zero(t, store.BatchCallCount())
+
+		a.AnchorProcessed(&observer.ProcessedAnchor{})
+
+		a.flush()
+		require.Equal(t, 1, store.BatchCallCount())
+		require.NotEqual(t, a.version, a.flushedVersion)
+
+		store.BatchReturns(nil)
+
+		a.flush()
+		require.Equal(t, 2, store.BatchCallCount())
+		require.Equal(t, a.version, a.flushedVersion)
+	})
+}
+
+func TestAggregator_StartStop(t *testing.T) {
+	provider := mem.NewProvider()
+
+	a, err := NewAggregator(provider, WithFlushInterval(5*time.Millisecond))
+	require.NoError(t, err)
+
+	a2, err := NewAggregator(provider)
+	require.NoError(t, err)
+
+	a.Start()
+
+	a.AnchorProcessed(&observer.ProcessedAnchor{})
+
+	time.Sleep(50 * time.Millisecond)
+
+	stats, err := a2.Get(0)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), stats.Anchors)
+
+	a.AnchorProcessed(&observer.ProcessedAnchor{})
+
+	a.Stop()
+
+	stats, err = a2.Get(0)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), stats.Anchors)
+}
+
+func TestAggregator_GetError(t *testing.T) {
+	errExpected := errors.New("injected store error")
+
+	t.Run("Query error", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.QueryReturns(nil, errExpected)
+
+		a := newAggregatorWithStore(t, store)
+
+		_, err := a.Get(0)
+		require.True(t, errors.Is(err, errExpected))
+		require.True(t, orberrors.IsTransient(err))
+	})
+
+	t.Run("Day query error", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.QueryReturns(&mocks.Iterator{}, nil)
+		store.QueryReturnsOnCall(1, nil, errExpected)
+
+		a := newAggregatorWithStore(t, store)
+
+		_, err := a.Get(1)
+		require.True(t, errors.Is(err, errExpected))
+	})
+
+	t.Run("Iterator next error", func(t *testing.T) {
+		it := &mocks.Iterator{}
+		it.NextReturns(false, errExpected)
+		it.CloseReturns(errors.New("injected close error"))
+
+		store := &mocks.Store{}
+		store.QueryReturns(it, nil)
+
+		_, err := newAggregatorWithStore(t, store).Get(0)
+		require.True(t, errors.Is(err, errExpected))
+	})
+
+	t.Run("Iterator key error", func(t *testing.T) {
+		it := &mocks.Iterator{}
+		it.NextReturns(true, nil)
+		it.KeyReturns("", errExpected)
+
+		store := &mocks.Store{}
+		store.QueryReturns(it, nil)
+
+		_, err := newAggregatorWithStore(t, store).Get(0)
+		require.True(t, errors.Is(err, errExpected))
+	})
+
+	t.Run("Iterator value error", func(t *testing.T) {
+		it := &mocks.Iterator{}
+		it.NextReturns(true, nil)
+		it.KeyReturns("key1", nil)
+		it.ValueReturns(nil, errExpected)
+
+		store := &mocks.Store{}
+		store.QueryReturns(it, nil)
+
+		_, err := newAggregatorWithStore(t, store).Get(0)
+		require.True(t, errors.Is(err, errExpected))
+	})
+
+	t.Run("Unmarshal error", func(t *testing.T) {
+		it := &mocks.Iterator{}
+		it.NextReturns(true, nil)
+		it.KeyReturns("key1", nil)
+		it.ValueReturns([]byte("{"), nil)
+
+		store := &mocks.Store{}
+		store.QueryReturns(it, nil)
+
+		_, err := newAggregatorWithStore(t, store).Get(0)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal stats")
+	})
+}
+
+func newAggregatorWithStore(t *testing.T, store *mocks.Store) *Aggregator {
+	t.Helper()
+
+	provider := &mocks.Provider{}
+	provider.OpenStoreReturns(store, nil)
+
+	a, err := NewAggregator(provider)
+	require.NoError(t, err)
+
+	return a
+}
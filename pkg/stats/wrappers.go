@@ -0,0 +1,77 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package stats
+
+import (
+	"net/url"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+
+	apspi "github.com/trustbloc/orb/pkg/activitypub/store/spi"
+)
+
+type operationStore interface {
+	Get(suffix string) ([]*operation.AnchoredOperation, error)
+	Put(ops []*operation.AnchoredOperation) error
+}
+
+// OperationStore wraps an operation store and updates the operation and DID counters of the
+// aggregator when operations are stored.
+type OperationStore struct {
+	operationStore
+
+	aggregator *Aggregator
+}
+
+// NewOperationStore returns a new operation store wrapper.
+func NewOperationStore(s operationStore, aggregator *Aggregator) *OperationStore {
+	return &OperationStore{
+		operationStore: s,
+		aggregator:     aggregator,
+	}
+}
+
+// Put stores the given operations and updates the counters.
+func (s *OperationStore) Put(ops []*operation.AnchoredOperation) error {
+	err := s.operationStore.Put(ops)
+	if err != nil {
+		return err
+	}
+
+	s.aggregator.OperationsStored(ops)
+
+	return nil
+}
+
+// ActivityStore wraps an ActivityPub store and updates the activity counters of the aggregator
+// when activities are added to a collection.
+type ActivityStore struct {
+	apspi.Store
+
+	aggregator *Aggregator
+}
+
+// NewActivityStore returns a new ActivityPub store wrapper.
+func NewActivityStore(s apspi.Store, aggregator *Aggregator) *ActivityStore {
+	return &ActivityStore{
+		Store:      s,
+		aggregator: aggregator,
+	}
+}
+
+// AddReference adds the reference to the underlying store and updates the counters.
+func (s *ActivityStore) AddReference(refType apspi.ReferenceType, objectIRI *url.URL, referenceIRI *url.URL,
+	metaDataOpts ...apspi.RefMetadataOpt) error {
+	err := s.Store.AddReference(refType, objectIRI, referenceIRI, metaDataOpts...)
+	if err != nil {
+		return err
+	}
+
+	s.aggregator.ReferenceAdded(refType)
+
+	return nil
+}
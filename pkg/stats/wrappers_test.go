@@ -0,0 +1,111 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package stats
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+
+	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
+	apspi "github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+)
+
+func TestOperationStore(t *testing.T) {
+	a, err := NewAggregator(mem.NewProvider())
+	require.NoError(t, err)
+
+	t.Run("Success", func(t *testing.T) {
+		opStore := &mockOperationStore{}
+
+		s := NewOperationStore(opStore, a)
+
+		require.NoError(t, s.Put([]*operation.AnchoredOperation{
+			{Type: operation.TypeCreate, UniqueSuffix: "suffix1"},
+			{Type: operation.TypeDeactivate, UniqueSuffix: "suffix2"},
+		}))
+
+		ops, err := s.Get("suffix1")
+		require.NoError(t, err)
+		require.Len(t, ops, 2)
+
+		stats, err := a.Get(0)
+		require.NoError(t, err)
+		require.Equal(t, uint64(1), stats.DIDs)
+		require.Equal(t, map[string]uint64{"create": 1, "deactivate": 1}, stats.Operations)
+	})
+
+	t.Run("Put error", func(t *testing.T) {
+		errExpected := errors.New("injected put error")
+
+		s := NewOperationStore(&mockOperationStore{err: errExpected}, a)
+
+		err := s.Put([]*operation.AnchoredOperation{{Type: operation.TypeCreate}})
+		require.True(t, errors.Is(err, errExpected))
+
+		stats, err := a.Get(0)
+		require.NoError(t, err)
+		require.Equal(t, uint64(1), stats.DIDs)
+	})
+}
+
+func TestActivityStore(t *testing.T) {
+	a, err := NewAggregator(mem.NewProvider())
+	require.NoError(t, err)
+
+	serviceIRI := testutil.MustParseURL("https://example.com/services/orb")
+	activityIRI := testutil.MustParseURL("https://example.com/activities/123")
+
+	s := NewActivityStore(memstore.New("service1"), a)
+
+	require.NoError(t, s.AddReference(apspi.Inbox, serviceIRI, activityIRI))
+	require.NoError(t, s.AddReference(apspi.Outbox, serviceIRI, activityIRI))
+	require.NoError(t, s.AddReference(apspi.Follower, serviceIRI, serviceIRI))
+
+	it, err := s.QueryReferences(apspi.Inbox, apspi.NewCriteria(apspi.WithObjectIRI(serviceIRI)))
+	require.NoError(t, err)
+
+	refs, err := it.Next()
+	require.NoError(t, err)
+	require.Equal(t, activityIRI.String(), refs.String())
+
+	stats, err := a.Get(0)
+	require.NoError(t, err)
+	require.Equal(t, map[string]uint64{"inbox": 1, "outbox": 1}, stats.Activities)
+
+	t.Run("Add reference error", func(t *testing.T) {
+		err := s.AddReference(apspi.Inbox, nil, activityIRI)
+		require.Error(t, err)
+
+		stats, err := a.Get(0)
+		require.NoError(t, err)
+		require.Equal(t, uint64(1), stats.Activities["inbox"])
+	})
+}
+
+type mockOperationStore struct {
+	ops []*operation.AnchoredOperation
+	err error
+}
+
+func (m *mockOperationStore) Get(string) ([]*operation.AnchoredOperation, error) {
+	return m.ops, nil
+}
+
+func (m *mockOperationStore) Put(ops []*operation.AnchoredOperation) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	m.ops = append(m.ops, ops...)
+
+	return nil
+}
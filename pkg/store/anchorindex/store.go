@@ -0,0 +1,370 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package anchorindex
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/observer"
+)
+
+const (
+	nameSpace = "anchor-index"
+
+	// anchorTag is set on the entry of every anchor.
+	anchorTag = "anchor"
+	// publishedTag is set on every document and contains the time (Unix seconds) that the anchor was published.
+	publishedTag = "published"
+	// originTag, witnessTag and suffixTag are set on the reference documents that are used to search for anchors.
+	originTag  = "origin"
+	witnessTag = "witness"
+	suffixTag  = "suffix"
+)
+
+var logger = log.New("anchor-index-store")
+
+// ErrNotFound is returned when an anchor is not found in the index.
+var ErrNotFound = errors.New("anchor not found")
+
+// Entry is the indexed information of an anchor.
+type Entry struct {
+	CanonicalReference string    `json:"id"`
+	Hashlink           string    `json:"hashlink"`
+	Origin             string    `json:"origin,omitempty"`
+	AttributedTo       string    `json:"attributedTo,omitempty"`
+	Namespace          string    `json:"namespace"`
+	Published          time.Time `json:"published"`
+	OperationCount     uint64    `json:"operationCount"`
+	Witnesses          []string  `json:"witnesses,omitempty"`
+	Suffixes           []string  `json:"suffixes,omitempty"`
+}
+
+// Criteria contains the search criteria. At most one of Origin, Witness or Suffix may be specified. The time range
+// (From and To, inclusive) is optional and may be combined with any of the other criteria.
+type Criteria struct {
+	Origin  string
+	Witness string
+	Suffix  string
+	From    time.Time
+	To      time.Time
+}
+
+// Results contains a page of search results along with the total number of matching anchors.
+type Results struct {
+	Total   int      `json:"total"`
+	Anchors []*Entry `json:"anchors"`
+}
+
+// New returns a new anchor index store.
+func New(provider storage.Provider) (*Store, error) {
+	store, err := provider.OpenStore(nameSpace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open anchor index store: %w", err)
+	}
+
+	err = provider.SetStoreConfig(nameSpace, storage.StoreConfiguration{
+		TagNames: []string{anchorTag, publishedTag, originTag, witnessTag, suffixTag},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set store configuration: %w", err)
+	}
+
+	return &Store{
+		store: store,
+	}, nil
+}
+
+// Store indexes anchors by time, origin domain, witness domain and DID suffix.
+//
+// The index consists of an entry for each anchor and a reference document for each origin, witness and DID suffix
+// of the anchor. A search scans the reference documents (using the tags only) of the given origin, witness or
+// suffix, so only the entries in the requested page are loaded.
+type Store struct {
+	store storage.Store
+}
+
+// AnchorProcessed is invoked by the observer after an anchor has been processed. The anchor is added to the index.
+func (s *Store) AnchorProcessed(anchor *observer.ProcessedAnchor) {
+	entry := &Entry{
+		CanonicalReference: anchor.CanonicalReference,
+		Hashlink:           anchor.Hashlink,
+		Origin:             anchor.AnchorOrigin,
+		AttributedTo:       anchor.AttributedTo,
+		Namespace:          anchor.Namespace,
+		Published:          anchor.Published,
+		OperationCount:     anchor.OperationCount,
+		Witnesses:          anchor.Witnesses,
+		Suffixes:           anchor.Suffixes,
+	}
+
+	if err := s.Put(entry); err != nil {
+		// The anchor was processed so there's no point in failing. The anchor just won't be searchable.
+		logger.Warnf("Error adding anchor [%s] to the index: %s", anchor.Hashlink, err)
+	}
+}
+
+// Put adds the given entry to the index. If the entry already exists then it is overwritten.
+func (s *Store) Put(entry *Entry) error {
+	if entry.CanonicalReference == "" {
+		return errors.New("canonical reference is required")
+	}
+
+	entryBytes, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("marshal anchor entry: %w", err)
+	}
+
+	published := storage.Tag{Name: publishedTag, Value: strconv.FormatInt(entry.Published.Unix(), 10)}
+
+	ops := []storage.Operation{
+		{
+			Key:   entry.CanonicalReference,
+			Value: entryBytes,
+			Tags:  []storage.Tag{{Name: anchorTag}, published},
+		},
+	}
+
+	addRef := func(tagName, value string) {
+		ops = append(ops, storage.Operation{
+			Key:   fmt.Sprintf("%s-%s-%s", tagName, value, entry.CanonicalReference),
+			Value: []byte(entry.CanonicalReference),
+			Tags:  []storage.Tag{{Name: tagName, Value: encode(value)}, published},
+		})
+	}
+
+	if entry.Origin != "" {
+		addRef(originTag, Domain(entry.Origin))
+	}
+
+	for _, witness := range entry.Witnesses {
+		addRef(witnessTag, Domain(witness))
+	}
+
+	for _, suffix := range entry.Suffixes {
+		addRef(suffixTag, suffix)
+	}
+
+	err = s.store.Batch(ops)
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("store anchor entry [%s]: %w", entry.CanonicalReference, err))
+	}
+
+	logger.Debugf("Indexed anchor [%s] with %d reference(s)", entry.CanonicalReference, len(ops)-1)
+
+	return nil
+}
+
+// Get returns the entry for the given canonical reference.
+func (s *Store) Get(canonicalRef string) (*Entry, error) {
+	entryBytes, err := s.store.Get(canonicalRef)
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return nil, ErrNotFound
+		}
+
+		return nil, orberrors.NewTransient(fmt.Errorf("get anchor entry [%s]: %w", canonicalRef, err))
+	}
+
+	entry := &Entry{}
+
+	err = json.Unmarshal(entryBytes, entry)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal anchor entry [%s]: %w", canonicalRef, err)
+	}
+
+	return entry, nil
+}
+
+// Search returns the given page of anchors that match the given criteria, most recently published first.
+func (s *Store) Search(criteria *Criteria, pageNum, pageSize int) (*Results, error) {
+	query, err := getQuery(criteria)
+	if err != nil {
+		return nil, err
+	}
+
+	refs, err := s.queryRefs(query, criteria)
+	if err != nil {
+		return nil, err
+	}
+
+	results := &Results{
+		Total:   len(refs),
+		Anchors: []*Entry{},
+	}
+
+	start := pageNum * pageSize
+	if start >= len(refs) {
+		return results, nil
+	}
+
+	end := start + pageSize
+	if end > len(refs) {
+		end = len(refs)
+	}
+
+	for _, r := range refs[start:end] {
+		entry, e := s.Get(r.canonicalRef)
+		if e != nil {
+			return nil, e
+		}
+
+		results.Anchors = append(results.Anchors, entry)
+	}
+
+	return results, nil
+}
+
+type ref struct {
+	canonicalRef string
+	published    int64
+}
+
+// queryRefs returns the canonical references of the anchors that match the given query and time range,
+// sorted by published time (descending).
+func (s *Store) queryRefs(query string, criteria *Criteria) ([]*ref, error) {
+	it, err := s.store.Query(query)
+	if err != nil {
+		return nil, orberrors.NewTransient(fmt.Errorf("query anchor index [%s]: %w", query, err))
+	}
+
+	defer func() {
+		if e := it.Close(); e != nil {
+			logger.Warnf("Error closing iterator: %s", e)
+		}
+	}()
+
+	var refs []*ref
+
+	for {
+		ok, e := it.Next()
+		if e != nil {
+			return nil, orberrors.NewTransient(fmt.Errorf("next anchor index [%s]: %w", query, e))
+		}
+
+		if !ok {
+			break
+		}
+
+		r, e := getRef(it, query)
+		if e != nil {
+			return nil, e
+		}
+
+		if inRange(r.published, criteria) {
+			refs = append(refs, r)
+		}
+	}
+
+	sort.SliceStable(refs, func(i, j int) bool {
+		if refs[i].published == refs[j].published {
+			return refs[i].canonicalRef < refs[j].canonicalRef
+		}
+
+		return refs[i].published > refs[j].published
+	})
+
+	return refs, nil
+}
+
+func getRef(it storage.Iterator, query string) (*ref, error) {
+	tags, err := it.Tags()
+	if err != nil {
+		return nil, orberrors.NewTransient(fmt.Errorf("get tags from iterator: %w", err))
+	}
+
+	var published int64
+
+	for _, tag := range tags {
+		if tag.Name == publishedTag {
+			published, err = strconv.ParseInt(tag.Value, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value for tag [%s]: %w", publishedTag, err)
+			}
+		}
+	}
+
+	if query == anchorTag {
+		key, e := it.Key()
+		if e != nil {
+			return nil, orberrors.NewTransient(fmt.Errorf("get key from iterator: %w", e))
+		}
+
+		return &ref{canonicalRef: key, published: published}, nil
+	}
+
+	value, err := it.Value()
+	if err != nil {
+		return nil, orberrors.NewTransient(fmt.Errorf("get value from iterator: %w", err))
+	}
+
+	return &ref{canonicalRef: string(value), published: published}, nil
+}
+
+func getQuery(criteria *Criteria) (string, error) {
+	var queries []string
+
+	if criteria.Origin != "" {
+		queries = append(queries, fmt.Sprintf("%s:%s", originTag, encode(Domain(criteria.Origin))))
+	}
+
+	if criteria.Witness != "" {
+		queries = append(queries, fmt.Sprintf("%s:%s", witnessTag, encode(Domain(criteria.Witness))))
+	}
+
+	if criteria.Suffix != "" {
+		queries = append(queries, fmt.Sprintf("%s:%s", suffixTag, encode(criteria.Suffix)))
+	}
+
+	switch len(queries) {
+	case 0:
+		return anchorTag, nil
+	case 1:
+		return queries[0], nil
+	default:
+		return "", errors.New("only one of origin, witness or suffix may be specified")
+	}
+}
+
+func inRange(published int64, criteria *Criteria) bool {
+	if !criteria.From.IsZero() && published < criteria.From.Unix() {
+		return false
+	}
+
+	if !criteria.To.IsZero() && published > criteria.To.Unix() {
+		return false
+	}
+
+	return true
+}
+
+// Domain returns the (lower case) host of the given URL or, if the value isn't a URL with a host, the lower case
+// value.
+func Domain(value string) string {
+	u, err := url.Parse(value)
+	if err == nil && u.Host != "" {
+		return strings.ToLower(u.Host)
+	}
+
+	return strings.ToLower(value)
+}
+
+// encode encodes tag values since the query syntax doesn't allow certain characters (e.g. ':') in values.
+func encode(value string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(value))
+}
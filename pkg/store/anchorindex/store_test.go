@@ -0,0 +1,310 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package anchorindex
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/stretchr/testify/require"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/observer"
+	"github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+const (
+	origin1  = "https://orb.domain1.com/services/orb"
+	origin2  = "https://orb.domain2.com/services/orb"
+	witness1 = "https://witness1.com/ledgers/maple2021"
+	witness2 = "https://witness2.com:8443/ledgers/maple2021"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		s, err := New(mem.NewProvider())
+		require.NoError(t, err)
+		require.NotNil(t, s)
+	})
+
+	t.Run("Open store error", func(t *testing.T) {
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(nil, errors.New("injected open error"))
+
+		_, err := New(provider)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected open error")
+	})
+
+	t.Run("Set store config error", func(t *testing.T) {
+		provider := &mocks.Provider{}
+		provider.SetStoreConfigReturns(errors.New("injected config error"))
+
+		_, err := New(provider)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected config error")
+	})
+}
+
+func TestStore_Search(t *testing.T) {
+	s, err := New(mem.NewProvider())
+	require.NoError(t, err)
+
+	now := time.Now().Truncate(time.Second)
+
+	s.AnchorProcessed(&observer.ProcessedAnchor{
+		Hashlink:           "hl:anchor1",
+		CanonicalReference: "anchor1",
+		AnchorOrigin:       origin1,
+		Namespace:          "did:orb",
+		Published:          now.Add(-2 * time.Hour),
+		OperationCount:     2,
+		Suffixes:           []string{"suffix1", "suffix2"},
+		Witnesses:          []string{witness1},
+	})
+
+	s.AnchorProcessed(&observer.ProcessedAnchor{
+		Hashlink:           "hl:anchor2",
+		CanonicalReference: "anchor2",
+		AnchorOrigin:       origin2,
+		Published:          now.Add(-time.Hour),
+		OperationCount:     1,
+		Suffixes:           []string{"suffix1"},
+		Witnesses:          []string{witness1, witness2},
+	})
+
+	s.AnchorProcessed(&observer.ProcessedAnchor{
+		Hashlink:           "hl:anchor3",
+		CanonicalReference: "anchor3",
+		AnchorOrigin:       origin1,
+		Published:          now,
+		OperationCount:     1,
+		Suffixes:           []string{"suffix3"},
+		Witnesses:          []string{witness2},
+	})
+
+	t.Run("All", func(t *testing.T) {
+		results, err := s.Search(&Criteria{}, 0, 10)
+		require.NoError(t, err)
+		requireAnchors(t, results, 3, "anchor3", "anchor2", "anchor1")
+
+		entry := results.Anchors[2]
+		require.Equal(t, "hl:anchor1", entry.Hashlink)
+		require.Equal(t, origin1, entry.Origin)
+		require.Equal(t, "did:orb", entry.Namespace)
+		require.Equal(t, uint64(2), entry.OperationCount)
+		require.Equal(t, []string{witness1}, entry.Witnesses)
+		require.Equal(t, []string{"suffix1", "suffix2"}, entry.Suffixes)
+		require.True(t, now.Add(-2*time.Hour).Equal(entry.Published))
+	})
+
+	t.Run("Paging", func(t *testing.T) {
+		results, err := s.Search(&Criteria{}, 0, 2)
+		require.NoError(t, err)
+		requireAnchors(t, results, 3, "anchor3", "anchor2")
+
+		results, err = s.Search(&Criteria{}, 1, 2)
+		require.NoError(t, err)
+		requireAnchors(t, results, 3, "anchor1")
+
+		results, err = s.Search(&Criteria{}, 2, 2)
+		require.NoError(t, err)
+		requireAnchors(t, results, 3)
+	})
+
+	t.Run("Time range", func(t *testing.T) {
+		results, err := s.Search(&Criteria{From: now.Add(-time.Hour)}, 0, 10)
+		require.NoError(t, err)
+		requireAnchors(t, results, 2, "anchor3", "anchor2")
+
+		results, err = s.Search(&Criteria{To: now.Add(-time.Hour)}, 0, 10)
+		require.NoError(t, err)
+		requireAnchors(t, results, 2, "anchor2", "anchor1")
+
+		results, err = s.Search(&Criteria{From: now.Add(-90 * time.Minute), To: now.Add(-30 * time.Minute)}, 0, 10)
+		require.NoError(t, err)
+		requireAnchors(t, results, 1, "anchor2")
+	})
+
+	t.Run("Origin", func(t *testing.T) {
+		results, err := s.Search(&Criteria{Origin: "ORB.domain1.com"}, 0, 10)
+		require.NoError(t, err)
+		requireAnchors(t, results, 2, "anchor3", "anchor1")
+
+		results, err = s.Search(&Criteria{Origin: origin1, To: now.Add(-time.Minute)}, 0, 10)
+		require.NoError(t, err)
+		requireAnchors(t, results, 1, "anchor1")
+	})
+
+	t.Run("Witness", func(t *testing.T) {
+		results, err := s.Search(&Criteria{Witness: "witness1.com"}, 0, 10)
+		require.NoError(t, err)
+		requireAnchors(t, results, 2, "anchor2", "anchor1")
+
+		results, err = s.Search(&Criteria{Witness: "witness2.com:8443"}, 0, 10)
+		require.NoError(t, err)
+		requireAnchors(t, results, 2, "anchor3", "anchor2")
+	})
+
+	t.Run("Suffix", func(t *testing.T) {
+		results, err := s.Search(&Criteria{Suffix: "suffix1"}, 0, 10)
+		require.NoError(t, err)
+		requireAnchors(t, results, 2, "anchor2", "anchor1")
+
+		results, err = s.Search(&Criteria{Suffix: "suffix4"}, 0, 10)
+		require.NoError(t, err)
+		requireAnchors(t, results, 0)
+	})
+
+	t.Run("Multiple criteria -> error", func(t *testing.T) {
+		_, err := s.Search(&Criteria{Origin: origin1, Suffix: "suffix1"}, 0, 10)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "only one of origin, witness or suffix may be specified")
+	})
+}
+
+func TestStore_Get(t *testing.T) {
+	s, err := New(mem.NewProvider())
+	require.NoError(t, err)
+
+	require.NoError(t, s.Put(&Entry{CanonicalReference: "anchor1", Hashlink: "hl:anchor1"}))
+
+	entry, err := s.Get("anchor1")
+	require.NoError(t, err)
+	require.Equal(t, "hl:anchor1", entry.Hashlink)
+
+	_, err = s.Get("anchor2")
+	require.True(t, errors.Is(err, ErrNotFound))
+
+	err = s.Put(&Entry{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "canonical reference is required")
+}
+
+func TestStore_Errors(t *testing.T) {
+	errExpected := errors.New("injected store error")
+
+	t.Run("Put error", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.BatchReturns(errExpected)
+
+		s := newStore(t, store)
+
+		err := s.Put(&Entry{CanonicalReference: "anchor1"})
+		require.True(t, errors.Is(err, errExpected))
+		require.True(t, orberrors.IsTransient(err))
+
+		// The error is logged.
+		s.AnchorProcessed(&observer.ProcessedAnchor{CanonicalReference: "anchor1"})
+	})
+
+	t.Run("Get error", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.GetReturns(nil, errExpected)
+
+		_, err := newStore(t, store).Get("anchor1")
+		require.True(t, errors.Is(err, errExpected))
+
+		store.GetReturns([]byte("{"), nil)
+
+		_, err = newStore(t, store).Get("anchor1")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal anchor entry")
+	})
+
+	t.Run("Query error", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.QueryReturns(nil, errExpected)
+
+		_, err := newStore(t, store).Search(&Criteria{}, 0, 10)
+		require.True(t, errors.Is(err, errExpected))
+	})
+
+	t.Run("Iterator errors", func(t *testing.T) {
+		store := &mocks.Store{}
+
+		it := &mocks.Iterator{}
+		it.NextReturns(false, errExpected)
+		it.CloseReturns(errors.New("injected close error"))
+
+		store.QueryReturns(it, nil)
+
+		_, err := newStore(t, store).Search(&Criteria{}, 0, 10)
+		require.True(t, errors.Is(err, errExpected))
+
+		it.NextReturns(true, nil)
+		it.TagsReturns(nil, errExpected)
+
+		_, err = newStore(t, store).Search(&Criteria{}, 0, 10)
+		require.True(t, errors.Is(err, errExpected))
+
+		it.TagsReturns([]storage.Tag{{Name: publishedTag, Value: "xxx"}}, nil)
+
+		_, err = newStore(t, store).Search(&Criteria{}, 0, 10)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for tag [published]")
+
+		it.TagsReturns([]storage.Tag{{Name: publishedTag, Value: "1000"}}, nil)
+		it.KeyReturns("", errExpected)
+
+		_, err = newStore(t, store).Search(&Criteria{}, 0, 10)
+		require.True(t, errors.Is(err, errExpected))
+
+		it.ValueReturns(nil, errExpected)
+
+		_, err = newStore(t, store).Search(&Criteria{Suffix: "suffix1"}, 0, 10)
+		require.True(t, errors.Is(err, errExpected))
+	})
+
+	t.Run("Get entry error", func(t *testing.T) {
+		store := &mocks.Store{}
+
+		it := &mocks.Iterator{}
+		it.NextReturnsOnCall(0, true, nil)
+		it.KeyReturns("anchor1", nil)
+
+		store.QueryReturns(it, nil)
+		store.GetReturns(nil, storage.ErrDataNotFound)
+
+		_, err := newStore(t, store).Search(&Criteria{}, 0, 10)
+		require.True(t, errors.Is(err, ErrNotFound))
+	})
+}
+
+func TestDomain(t *testing.T) {
+	require.Equal(t, "orb.domain1.com", Domain("https://ORB.domain1.com/services/orb"))
+	require.Equal(t, "orb.domain1.com:8443", Domain("https://orb.domain1.com:8443"))
+	require.Equal(t, "orb.domain1.com", Domain("Orb.Domain1.com"))
+	require.Equal(t, "did:web:orb.domain1.com", Domain("did:web:orb.domain1.com"))
+}
+
+func requireAnchors(t *testing.T, results *Results, total int, ids ...string) {
+	t.Helper()
+
+	require.Equal(t, total, results.Total)
+	require.Len(t, results.Anchors, len(ids), fmt.Sprintf("expecting %s", ids))
+
+	for i, id := range ids {
+		require.Equal(t, id, results.Anchors[i].CanonicalReference)
+	}
+}
+
+func newStore(t *testing.T, store *mocks.Store) *Store {
+	t.Helper()
+
+	provider := &mocks.Provider{}
+	provider.OpenStoreReturns(store, nil)
+
+	s, err := New(provider)
+	require.NoError(t, err)
+
+	return s
+}
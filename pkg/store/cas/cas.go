@@ -114,6 +114,9 @@ func (p *CAS) WriteWithCIDFormat(content []byte, opts ...extendedcasclient.CIDFo
 			resourceHash, base64.RawStdEncoding.EncodeToString(content))
 	}
 
+	// The content is written to IPFS concurrently with the local store in order to reduce write latency.
+	ipfsResult := p.writeToIPFS(content, opts)
+
 	err = p.cas.Put(resourceHash, content)
 	if err != nil {
 		return "", orberrors.NewTransient(fmt.Errorf("failed to put content into underlying storage provider: %w", err))
@@ -122,15 +125,15 @@ func (p *CAS) WriteWithCIDFormat(content []byte, opts ...extendedcasclient.CIDFo
 	// add cas link
 	links := []string{p.casLink + "/" + resourceHash}
 
-	if p.ipfsClient != nil {
-		cid, writeErr := p.ipfsClient.WriteWithCIDFormat(content, opts...)
-		if writeErr != nil {
+	if ipfsResult != nil {
+		result := <-ipfsResult
+		if result.err != nil {
 			return "", orberrors.NewTransient(fmt.Errorf("failed to put content into IPFS (but it was "+
-				"successfully stored in the local storage provider): %w", writeErr))
+				"successfully stored in the local storage provider): %w", result.err))
 		}
 
 		// add ipfs link
-		links = append(links, "ipfs://"+cid)
+		links = append(links, "ipfs://"+result.cid)
 	}
 
 	if err = p.cache.Set(resourceHash, content); err != nil {
@@ -148,6 +151,30 @@ func (p *CAS) WriteWithCIDFormat(content []byte, opts ...extendedcasclient.CIDFo
 	return hashlink.GetHashLink(resourceHash, metadata), nil
 }
 
+type ipfsWriteResult struct {
+	cid string
+	err error
+}
+
+// writeToIPFS writes the content to IPFS in a separate goroutine and returns a channel that receives the result.
+// Nil is returned if IPFS is not configured.
+func (p *CAS) writeToIPFS(content []byte, opts []extendedcasclient.CIDFormatOption) <-chan *ipfsWriteResult {
+	if p.ipfsClient == nil {
+		return nil
+	}
+
+	// The channel is buffered so that the goroutine doesn't block if the local write fails.
+	result := make(chan *ipfsWriteResult, 1)
+
+	go func() {
+		cid, err := p.ipfsClient.WriteWithCIDFormat(content, opts...)
+
+		result <- &ipfsWriteResult{cid: cid, err: err}
+	}()
+
+	return result
+}
+
 // GetPrimaryWriterType returns primary writer type.
 func (p *CAS) GetPrimaryWriterType() string {
 	return "local"
@@ -17,7 +17,12 @@ import (
 	orberrors "github.com/trustbloc/orb/pkg/errors"
 )
 
-const nameSpace = "didanchor"
+const (
+	nameSpace = "didanchor"
+
+	// suffixTag is added to every entry so that the total number of DIDs may be queried.
+	suffixTag = "suffix"
+)
 
 var logger = log.New("didanchor-store")
 
@@ -28,6 +33,11 @@ func New(provider storage.Provider) (*Store, error) {
 		return nil, fmt.Errorf("failed to open did anchor store: %w", err)
 	}
 
+	err = provider.SetStoreConfig(nameSpace, storage.StoreConfiguration{TagNames: []string{suffixTag}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set store configuration: %w", err)
+	}
+
 	return &Store{
 		store: store,
 	}, nil
@@ -51,6 +61,7 @@ func (s *Store) PutBulk(suffixes []string, areNew []bool, cid string) error {
 			Key:        suffix,
 			Value:      []byte(cid),
 			PutOptions: &storage.PutOptions{IsNewKey: areNew[i]},
+			Tags:       []storage.Tag{{Name: suffixTag}},
 		}
 
 		operations[i] = op
@@ -67,6 +78,7 @@ func (s *Store) PutBulk(suffixes []string, areNew []bool, cid string) error {
 				op := storage.Operation{
 					Key:   suffix,
 					Value: []byte(cid),
+					Tags:  []storage.Tag{{Name: suffixTag}},
 				}
 
 				operations[i] = op
@@ -126,3 +138,24 @@ func (s *Store) Get(suffix string) (string, error) {
 
 	return anchor, nil
 }
+
+// Count returns the total number of DIDs (suffixes) in the store.
+func (s *Store) Count() (int, error) {
+	iter, err := s.store.Query(suffixTag)
+	if err != nil {
+		return 0, orberrors.NewTransient(fmt.Errorf("failed to query did anchor store: %w", err))
+	}
+
+	defer func() {
+		if e := iter.Close(); e != nil {
+			logger.Warnf("Failed to close iterator: %s", e)
+		}
+	}()
+
+	count, err := iter.TotalItems()
+	if err != nil {
+		return 0, orberrors.NewTransient(fmt.Errorf("failed to get total items from did anchor store: %w", err))
+	}
+
+	return count, nil
+}
@@ -41,6 +41,16 @@ func TestNew(t *testing.T) {
 		require.Contains(t, err.Error(), "failed to open did anchor store: open store error")
 		require.Nil(t, s)
 	})
+
+	t.Run("error - set store config fails", func(t *testing.T) {
+		provider := &mocks.Provider{}
+		provider.SetStoreConfigReturns(fmt.Errorf("set config error"))
+
+		s, err := New(provider)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to set store configuration: set config error")
+		require.Nil(t, s)
+	})
 }
 
 func TestStore_PutAll(t *testing.T) {
@@ -179,3 +189,59 @@ func TestStore_Get(t *testing.T) {
 		require.Contains(t, err.Error(), "store error")
 	})
 }
+
+func TestStore_Count(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		provider := mem.NewProvider()
+
+		s, err := New(provider)
+		require.NoError(t, err)
+
+		count, err := s.Count()
+		require.NoError(t, err)
+		require.Equal(t, 0, count)
+
+		require.NoError(t, s.PutBulk([]string{"suffix-1", "suffix-2"}, []bool{true, true}, "cid1"))
+		require.NoError(t, s.PutBulk([]string{"suffix-2", "suffix-3"}, []bool{false, true}, "cid2"))
+
+		count, err = s.Count()
+		require.NoError(t, err)
+		require.Equal(t, 3, count)
+	})
+
+	t.Run("error - query error", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.QueryReturns(nil, fmt.Errorf("query error"))
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := New(provider)
+		require.NoError(t, err)
+
+		count, err := s.Count()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "query error")
+		require.Equal(t, 0, count)
+	})
+
+	t.Run("error - total items error", func(t *testing.T) {
+		iter := &mocks.Iterator{}
+		iter.TotalItemsReturns(0, fmt.Errorf("total items error"))
+		iter.CloseReturns(fmt.Errorf("close error"))
+
+		store := &mocks.Store{}
+		store.QueryReturns(iter, nil)
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := New(provider)
+		require.NoError(t, err)
+
+		count, err := s.Count()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "total items error")
+		require.Equal(t, 0, count)
+	})
+}
@@ -0,0 +1,134 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dualwrite
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/edge-core/pkg/log"
+)
+
+var logger = log.New("dual-write-store")
+
+// Provider is a storage provider that is used while migrating from one database technology to another
+// (for example, from CouchDB to MongoDB) without downtime. All writes go to both the legacy and the new
+// database. Reads by key prefer the new database and fall back to the legacy database for data that hasn't
+// been copied yet. Queries are served by the legacy database since it's the only one that's guaranteed to
+// contain all of the data until the Verifier reports that the databases are in sync, at which point the
+// server may be switched to the new database only.
+type Provider struct {
+	primary   storage.Provider
+	secondary storage.Provider
+
+	mutex  sync.RWMutex
+	stores map[string]*Store
+}
+
+// NewProvider returns a new dual-write provider. The primary provider is the new database and the
+// secondary provider is the legacy database.
+func NewProvider(primary, secondary storage.Provider) *Provider {
+	return &Provider{
+		primary:   primary,
+		secondary: secondary,
+		stores:    make(map[string]*Store),
+	}
+}
+
+// OpenStore opens the store with the given name in both databases.
+func (p *Provider) OpenStore(name string) (storage.Store, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if s, ok := p.stores[name]; ok {
+		return s, nil
+	}
+
+	secondary, err := p.secondary.OpenStore(name)
+	if err != nil {
+		return nil, fmt.Errorf("open legacy store [%s]: %w", name, err)
+	}
+
+	primary, err := p.primary.OpenStore(name)
+	if err != nil {
+		return nil, fmt.Errorf("open store [%s]: %w", name, err)
+	}
+
+	s := &Store{name: name, primary: primary, secondary: secondary}
+
+	p.stores[name] = s
+
+	return s, nil
+}
+
+// SetStoreConfig sets the configuration of the store in both databases.
+func (p *Provider) SetStoreConfig(name string, config storage.StoreConfiguration) error {
+	if err := p.secondary.SetStoreConfig(name, config); err != nil {
+		return fmt.Errorf("set configuration of legacy store [%s]: %w", name, err)
+	}
+
+	return p.primary.SetStoreConfig(name, config)
+}
+
+// GetStoreConfig returns the configuration of the store from the new database or, if the store doesn't exist
+// in the new database, from the legacy database.
+func (p *Provider) GetStoreConfig(name string) (storage.StoreConfiguration, error) {
+	config, err := p.primary.GetStoreConfig(name)
+	if err != nil && errors.Is(err, storage.ErrStoreNotFound) {
+		return p.secondary.GetStoreConfig(name)
+	}
+
+	return config, err
+}
+
+// GetOpenStores returns the open stores.
+func (p *Provider) GetOpenStores() []storage.Store {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	stores := make([]storage.Store, 0, len(p.stores))
+
+	for _, s := range p.stores {
+		stores = append(stores, s)
+	}
+
+	return stores
+}
+
+// Close closes both providers.
+func (p *Provider) Close() error {
+	p.mutex.Lock()
+	p.stores = make(map[string]*Store)
+	p.mutex.Unlock()
+
+	secondaryErr := p.secondary.Close()
+
+	if err := p.primary.Close(); err != nil {
+		return err
+	}
+
+	return secondaryErr
+}
+
+// storeNames returns the names of the open stores, sorted.
+func (p *Provider) storeNames() []string {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	names := make([]string, 0, len(p.stores))
+
+	for name := range p.stores {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,99 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dualwrite
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+func TestProvider(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		primary := mem.NewProvider()
+		secondary := mem.NewProvider()
+
+		p := NewProvider(primary, secondary)
+
+		s1, err := p.OpenStore("store1")
+		require.NoError(t, err)
+
+		s2, err := p.OpenStore("store1")
+		require.NoError(t, err)
+		require.True(t, s1 == s2)
+
+		_, err = p.OpenStore("store2")
+		require.NoError(t, err)
+
+		require.Len(t, p.GetOpenStores(), 2)
+		require.Equal(t, []string{"store1", "store2"}, p.storeNames())
+
+		require.NoError(t, p.SetStoreConfig("store1", storage.StoreConfiguration{TagNames: []string{"tag1"}}))
+
+		config, err := secondary.GetStoreConfig("store1")
+		require.NoError(t, err)
+		require.Equal(t, []string{"tag1"}, config.TagNames)
+
+		config, err = p.GetStoreConfig("store1")
+		require.NoError(t, err)
+		require.Equal(t, []string{"tag1"}, config.TagNames)
+
+		require.NoError(t, p.Close())
+		require.Empty(t, p.GetOpenStores())
+	})
+
+	t.Run("Store config only in legacy database", func(t *testing.T) {
+		secondary := mem.NewProvider()
+
+		_, err := secondary.OpenStore("store1")
+		require.NoError(t, err)
+		require.NoError(t, secondary.SetStoreConfig("store1", storage.StoreConfiguration{TagNames: []string{"tag1"}}))
+
+		config, err := NewProvider(mem.NewProvider(), secondary).GetStoreConfig("store1")
+		require.NoError(t, err)
+		require.Equal(t, []string{"tag1"}, config.TagNames)
+	})
+
+	t.Run("Open store error", func(t *testing.T) {
+		errExpected := errors.New("injected open error")
+
+		failing := &mocks.Provider{}
+		failing.OpenStoreReturns(nil, errExpected)
+
+		_, err := NewProvider(mem.NewProvider(), failing).OpenStore("store1")
+		require.True(t, errors.Is(err, errExpected))
+		require.Contains(t, err.Error(), "open legacy store")
+
+		_, err = NewProvider(failing, mem.NewProvider()).OpenStore("store1")
+		require.True(t, errors.Is(err, errExpected))
+	})
+
+	t.Run("Set store config error", func(t *testing.T) {
+		errExpected := errors.New("injected config error")
+
+		failing := &mocks.Provider{}
+		failing.SetStoreConfigReturns(errExpected)
+
+		err := NewProvider(mem.NewProvider(), failing).SetStoreConfig("store1", storage.StoreConfiguration{})
+		require.True(t, errors.Is(err, errExpected))
+	})
+
+	t.Run("Close error", func(t *testing.T) {
+		errExpected := errors.New("injected close error")
+
+		failing := &mocks.Provider{}
+		failing.CloseReturns(errExpected)
+
+		require.True(t, errors.Is(NewProvider(failing, mem.NewProvider()).Close(), errExpected))
+		require.True(t, errors.Is(NewProvider(mem.NewProvider(), failing).Close(), errExpected))
+	})
+}
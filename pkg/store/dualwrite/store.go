@@ -0,0 +1,143 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dualwrite
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+// Store writes to both the legacy and the new store. Data is written to the legacy store first so that,
+// if the write to the new store fails, reads still return the latest data (via the fallback to the legacy store).
+type Store struct {
+	name      string
+	primary   storage.Store
+	secondary storage.Store
+}
+
+// Put stores the data in both stores.
+func (s *Store) Put(key string, value []byte, tags ...storage.Tag) error {
+	if err := s.secondary.Put(key, value, tags...); err != nil {
+		return err
+	}
+
+	if err := s.primary.Put(key, value, tags...); err != nil {
+		return fmt.Errorf("write to new store [%s]: %w", s.name, err)
+	}
+
+	return nil
+}
+
+// Get returns the data from the new store or, if not found, from the legacy store.
+func (s *Store) Get(key string) ([]byte, error) {
+	value, err := s.primary.Get(key)
+	if err == nil || !errors.Is(err, storage.ErrDataNotFound) {
+		return value, err
+	}
+
+	return s.secondary.Get(key)
+}
+
+// GetTags returns the tags from the new store or, if not found, from the legacy store.
+func (s *Store) GetTags(key string) ([]storage.Tag, error) {
+	tags, err := s.primary.GetTags(key)
+	if err == nil || !errors.Is(err, storage.ErrDataNotFound) {
+		return tags, err
+	}
+
+	return s.secondary.GetTags(key)
+}
+
+// GetBulk returns the values for the given keys from the new store. Any values that weren't found in the new store
+// are retrieved from the legacy store.
+func (s *Store) GetBulk(keys ...string) ([][]byte, error) {
+	values, err := s.primary.GetBulk(keys...)
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []string
+
+	for i, v := range values {
+		if v == nil {
+			missing = append(missing, keys[i])
+		}
+	}
+
+	if len(missing) == 0 {
+		return values, nil
+	}
+
+	legacyValues, err := s.secondary.GetBulk(missing...)
+	if err != nil {
+		return nil, err
+	}
+
+	j := 0
+
+	for i, v := range values {
+		if v == nil {
+			values[i] = legacyValues[j]
+			j++
+		}
+	}
+
+	return values, nil
+}
+
+// Query queries the legacy store, which contains all of the data while the migration is in progress.
+func (s *Store) Query(expression string, options ...storage.QueryOption) (storage.Iterator, error) {
+	return s.secondary.Query(expression, options...)
+}
+
+// Delete deletes the data from both stores.
+func (s *Store) Delete(key string) error {
+	if err := s.secondary.Delete(key); err != nil {
+		return err
+	}
+
+	if err := s.primary.Delete(key); err != nil {
+		return fmt.Errorf("delete from new store [%s]: %w", s.name, err)
+	}
+
+	return nil
+}
+
+// Batch performs the operations on both stores.
+func (s *Store) Batch(operations []storage.Operation) error {
+	if err := s.secondary.Batch(operations); err != nil {
+		return err
+	}
+
+	if err := s.primary.Batch(operations); err != nil {
+		return fmt.Errorf("batch write to new store [%s]: %w", s.name, err)
+	}
+
+	return nil
+}
+
+// Flush flushes both stores.
+func (s *Store) Flush() error {
+	if err := s.secondary.Flush(); err != nil {
+		return err
+	}
+
+	return s.primary.Flush()
+}
+
+// Close closes both stores.
+func (s *Store) Close() error {
+	secondaryErr := s.secondary.Close()
+
+	if err := s.primary.Close(); err != nil {
+		return err
+	}
+
+	return secondaryErr
+}
@@ -0,0 +1,147 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dualwrite
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+func TestStore(t *testing.T) {
+	primaryProvider := mem.NewProvider()
+	secondaryProvider := mem.NewProvider()
+
+	s, err := NewProvider(primaryProvider, secondaryProvider).OpenStore("store1")
+	require.NoError(t, err)
+
+	primary, err := primaryProvider.OpenStore("store1")
+	require.NoError(t, err)
+
+	secondary, err := secondaryProvider.OpenStore("store1")
+	require.NoError(t, err)
+
+	t.Run("Put and delete", func(t *testing.T) {
+		require.NoError(t, s.Put("key1", []byte("value1"), storage.Tag{Name: "tag1", Value: "v1"}))
+
+		for _, store := range []storage.Store{primary, secondary} {
+			value, e := store.Get("key1")
+			require.NoError(t, e)
+			require.Equal(t, "value1", string(value))
+		}
+
+		require.NoError(t, s.Delete("key1"))
+
+		for _, store := range []storage.Store{primary, secondary} {
+			_, e := store.Get("key1")
+			require.True(t, errors.Is(e, storage.ErrDataNotFound))
+		}
+	})
+
+	t.Run("Reads fall back to the legacy store", func(t *testing.T) {
+		require.NoError(t, secondary.Put("legacy1", []byte("legacy-value"), storage.Tag{Name: "tag1", Value: "v1"}))
+		require.NoError(t, s.Put("key2", []byte("value2")))
+
+		value, err := s.Get("legacy1")
+		require.NoError(t, err)
+		require.Equal(t, "legacy-value", string(value))
+
+		tags, err := s.GetTags("legacy1")
+		require.NoError(t, err)
+		require.Equal(t, []storage.Tag{{Name: "tag1", Value: "v1"}}, tags)
+
+		values, err := s.GetBulk("key2", "legacy1", "unknown")
+		require.NoError(t, err)
+		require.Equal(t, [][]byte{[]byte("value2"), []byte("legacy-value"), nil}, values)
+
+		values, err = s.GetBulk("key2")
+		require.NoError(t, err)
+		require.Equal(t, [][]byte{[]byte("value2")}, values)
+	})
+
+	t.Run("Reads prefer the new store", func(t *testing.T) {
+		require.NoError(t, secondary.Put("key3", []byte("old-value")))
+		require.NoError(t, primary.Put("key3", []byte("new-value")))
+
+		value, err := s.Get("key3")
+		require.NoError(t, err)
+		require.Equal(t, "new-value", string(value))
+	})
+
+	t.Run("Query uses the legacy store", func(t *testing.T) {
+		it, err := s.Query("tag1")
+		require.NoError(t, err)
+
+		more, err := it.Next()
+		require.NoError(t, err)
+		require.True(t, more)
+
+		key, err := it.Key()
+		require.NoError(t, err)
+		require.Equal(t, "legacy1", key)
+
+		require.NoError(t, it.Close())
+	})
+
+	t.Run("Batch", func(t *testing.T) {
+		require.NoError(t, s.Batch([]storage.Operation{{Key: "key4", Value: []byte("value4")}}))
+
+		for _, store := range []storage.Store{primary, secondary} {
+			_, e := store.Get("key4")
+			require.NoError(t, e)
+		}
+
+		require.NoError(t, s.Flush())
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		errExpected := errors.New("injected error")
+
+		failing := &mocks.Store{}
+		failing.PutReturns(errExpected)
+		failing.GetReturns(nil, errExpected)
+		failing.GetTagsReturns(nil, errExpected)
+		failing.GetBulkReturns(nil, errExpected)
+		failing.DeleteReturns(errExpected)
+		failing.BatchReturns(errExpected)
+		failing.FlushReturns(errExpected)
+		failing.CloseReturns(errExpected)
+
+		for _, ds := range []*Store{
+			{name: "store1", primary: failing, secondary: &mocks.Store{}},
+			{name: "store1", primary: &mocks.Store{}, secondary: failing},
+		} {
+			require.True(t, errors.Is(ds.Put("key1", nil), errExpected))
+			require.True(t, errors.Is(ds.Delete("key1"), errExpected))
+			require.True(t, errors.Is(ds.Batch(nil), errExpected))
+			require.True(t, errors.Is(ds.Flush(), errExpected))
+			require.True(t, errors.Is(ds.Close(), errExpected))
+		}
+
+		ds := &Store{name: "store1", primary: failing, secondary: &mocks.Store{}}
+
+		_, err := ds.Get("key1")
+		require.True(t, errors.Is(err, errExpected))
+
+		_, err = ds.GetTags("key1")
+		require.True(t, errors.Is(err, errExpected))
+
+		_, err = ds.GetBulk("key1")
+		require.True(t, errors.Is(err, errExpected))
+
+		primary := &mocks.Store{}
+		primary.GetBulkReturns([][]byte{nil}, nil)
+
+		_, err = (&Store{name: "store1", primary: primary, secondary: failing}).GetBulk("key1")
+		require.True(t, errors.Is(err, errExpected))
+	})
+}
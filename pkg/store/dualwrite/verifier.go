@@ -0,0 +1,297 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dualwrite
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+const defaultPageSize = 500
+
+// Report contains the results of verifying a store.
+type Report struct {
+	Store      string `json:"store"`
+	Checked    int    `json:"checked"`
+	Missing    int    `json:"missing"`
+	Mismatched int    `json:"mismatched"`
+	Copied     int    `json:"copied"`
+}
+
+// InSync returns true if all of the checked entries in the legacy store exist in the new store with the
+// same value and tags (taking into account the entries that were copied).
+func (r *Report) InSync() bool {
+	return r.Mismatched == 0 && r.Missing == r.Copied
+}
+
+// Verifier compares the contents of the legacy and new stores of a dual-write provider. The aries storage
+// interface doesn't support iterating over all of the entries of a store so the entries of a store are found by
+// querying the legacy store by each of the tag names in the store's configuration. (Entries without tags can't be
+// queried and are therefore not verified, although they are still retrieved by key from the legacy store.)
+//
+// If copyMissing is enabled then the entries that are missing from the new store are copied from the legacy store.
+// Entries with different values aren't overwritten since the difference may be due to a concurrent update;
+// they're reported and should converge as the entries are updated.
+type Verifier struct {
+	provider    *Provider
+	copyMissing bool
+	pageSize    int
+
+	running int32
+	mutex   sync.RWMutex
+	reports []*Report
+}
+
+// VerifierOpt is a verifier option.
+type VerifierOpt func(v *Verifier)
+
+// WithCopyMissing copies the entries that are missing from the new store.
+func WithCopyMissing(enable bool) VerifierOpt {
+	return func(v *Verifier) {
+		v.copyMissing = enable
+	}
+}
+
+// WithPageSize sets the page size of the queries.
+func WithPageSize(size int) VerifierOpt {
+	return func(v *Verifier) {
+		v.pageSize = size
+	}
+}
+
+// NewVerifier returns a new verifier for the stores of the given dual-write provider.
+func NewVerifier(provider *Provider, opts ...VerifierOpt) *Verifier {
+	v := &Verifier{
+		provider: provider,
+		pageSize: defaultPageSize,
+	}
+
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	return v
+}
+
+// Run verifies all open stores. It may be registered as a periodic task (by more than one service in
+// the case of multiple tenants), in which case the invocations that occur while a verification is in
+// progress are skipped.
+func (v *Verifier) Run() {
+	if !atomic.CompareAndSwapInt32(&v.running, 0, 1) {
+		logger.Debugf("Verification is already in progress")
+
+		return
+	}
+
+	defer atomic.StoreInt32(&v.running, 0)
+
+	reports, err := v.VerifyAll()
+	if err != nil {
+		logger.Errorf("Error verifying stores: %s", err)
+
+		return
+	}
+
+	inSync := true
+
+	for _, r := range reports {
+		if !r.InSync() {
+			inSync = false
+
+			logger.Warnf("Store [%s] isn't in sync - checked: %d, missing: %d, mismatched: %d, copied: %d",
+				r.Store, r.Checked, r.Missing, r.Mismatched, r.Copied)
+		}
+	}
+
+	if inSync {
+		logger.Infof("All %d stores are in sync with the legacy database", len(reports))
+	}
+}
+
+// Reports returns the reports of the last verification.
+func (v *Verifier) Reports() []*Report {
+	v.mutex.RLock()
+	defer v.mutex.RUnlock()
+
+	return v.reports
+}
+
+// VerifyAll verifies all open stores.
+func (v *Verifier) VerifyAll() ([]*Report, error) {
+	var reports []*Report
+
+	for _, name := range v.provider.storeNames() {
+		r, err := v.Verify(name)
+		if err != nil {
+			return nil, fmt.Errorf("verify store [%s]: %w", name, err)
+		}
+
+		reports = append(reports, r)
+	}
+
+	v.mutex.Lock()
+	v.reports = reports
+	v.mutex.Unlock()
+
+	return reports, nil
+}
+
+// Verify compares the given store in the legacy and new databases.
+func (v *Verifier) Verify(storeName string) (*Report, error) {
+	s, err := v.provider.OpenStore(storeName)
+	if err != nil {
+		return nil, err
+	}
+
+	ds, ok := s.(*Store)
+	if !ok {
+		return nil, fmt.Errorf("unexpected store type: %T", s)
+	}
+
+	config, err := v.provider.secondary.GetStoreConfig(storeName)
+	if err != nil {
+		if errors.Is(err, storage.ErrStoreNotFound) {
+			return &Report{Store: storeName}, nil
+		}
+
+		return nil, fmt.Errorf("get configuration of legacy store: %w", err)
+	}
+
+	report := &Report{Store: storeName}
+	checked := make(map[string]struct{})
+
+	for _, tagName := range config.TagNames {
+		err = v.verifyTag(ds, tagName, checked, report)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return report, nil
+}
+
+func (v *Verifier) verifyTag(s *Store, tagName string, checked map[string]struct{}, report *Report) error {
+	it, err := s.secondary.Query(tagName, storage.WithPageSize(v.pageSize))
+	if err != nil {
+		return fmt.Errorf("query legacy store by tag [%s]: %w", tagName, err)
+	}
+
+	defer func() {
+		if e := it.Close(); e != nil {
+			logger.Warnf("Error closing iterator: %s", e)
+		}
+	}()
+
+	for {
+		more, e := it.Next()
+		if e != nil {
+			return fmt.Errorf("iterate legacy store: %w", e)
+		}
+
+		if !more {
+			return nil
+		}
+
+		key, e := it.Key()
+		if e != nil {
+			return fmt.Errorf("get key: %w", e)
+		}
+
+		if _, ok := checked[key]; ok {
+			continue
+		}
+
+		checked[key] = struct{}{}
+
+		e = v.verifyEntry(s, key, it, report)
+		if e != nil {
+			return e
+		}
+	}
+}
+
+func (v *Verifier) verifyEntry(s *Store, key string, it storage.Iterator, report *Report) error {
+	report.Checked++
+
+	value, err := it.Value()
+	if err != nil {
+		return fmt.Errorf("get value: %w", err)
+	}
+
+	tags, err := it.Tags()
+	if err != nil {
+		return fmt.Errorf("get tags: %w", err)
+	}
+
+	newValue, err := s.primary.Get(key)
+	if err != nil {
+		if !errors.Is(err, storage.ErrDataNotFound) {
+			return fmt.Errorf("get [%s] from new store: %w", key, err)
+		}
+
+		report.Missing++
+
+		if !v.copyMissing {
+			return nil
+		}
+
+		err = s.primary.Put(key, value, tags...)
+		if err != nil {
+			return fmt.Errorf("copy [%s] to new store: %w", key, err)
+		}
+
+		report.Copied++
+
+		return nil
+	}
+
+	newTags, err := s.primary.GetTags(key)
+	if err != nil {
+		return fmt.Errorf("get tags of [%s] from new store: %w", key, err)
+	}
+
+	if !bytes.Equal(value, newValue) || !equalTags(tags, newTags) {
+		logger.Debugf("Entry [%s] in store [%s] differs from the legacy store", key, s.name)
+
+		report.Mismatched++
+	}
+
+	return nil
+}
+
+func equalTags(tags1, tags2 []storage.Tag) bool {
+	if len(tags1) != len(tags2) {
+		return false
+	}
+
+	sortTags(tags1)
+	sortTags(tags2)
+
+	for i := range tags1 {
+		if tags1[i] != tags2[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func sortTags(tags []storage.Tag) {
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].Name == tags[j].Name {
+			return tags[i].Value < tags[j].Value
+		}
+
+		return tags[i].Name < tags[j].Name
+	})
+}
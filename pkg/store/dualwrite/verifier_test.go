@@ -0,0 +1,176 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dualwrite
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+func TestVerifier(t *testing.T) {
+	const storeName = "store1"
+
+	newProviders := func(t *testing.T) (*Provider, storage.Store, storage.Store) {
+		t.Helper()
+
+		primaryProvider := mem.NewProvider()
+		secondaryProvider := mem.NewProvider()
+
+		p := NewProvider(primaryProvider, secondaryProvider)
+
+		_, err := p.OpenStore(storeName)
+		require.NoError(t, err)
+
+		require.NoError(t, p.SetStoreConfig(storeName,
+			storage.StoreConfiguration{TagNames: []string{"tag1", "tag2"}}))
+
+		primary, err := primaryProvider.OpenStore(storeName)
+		require.NoError(t, err)
+
+		secondary, err := secondaryProvider.OpenStore(storeName)
+		require.NoError(t, err)
+
+		// key1 is in both stores, key2 is only in the legacy store and key3 has a different value.
+		for _, s := range []storage.Store{primary, secondary} {
+			require.NoError(t, s.Put("key1", []byte("value1"),
+				storage.Tag{Name: "tag1", Value: "a"}, storage.Tag{Name: "tag2", Value: "b"}))
+		}
+
+		require.NoError(t, secondary.Put("key2", []byte("value2"), storage.Tag{Name: "tag2", Value: "b"}))
+		require.NoError(t, secondary.Put("key3", []byte("value3"), storage.Tag{Name: "tag1", Value: "a"}))
+		require.NoError(t, primary.Put("key3", []byte("other"), storage.Tag{Name: "tag1", Value: "a"}))
+
+		return p, primary, secondary
+	}
+
+	t.Run("Report only", func(t *testing.T) {
+		p, primary, _ := newProviders(t)
+
+		v := NewVerifier(p, WithPageSize(1))
+
+		reports, err := v.VerifyAll()
+		require.NoError(t, err)
+		require.Len(t, reports, 1)
+		require.Equal(t, &Report{Store: storeName, Checked: 3, Missing: 1, Mismatched: 1}, reports[0])
+		require.False(t, reports[0].InSync())
+		require.Equal(t, reports, v.Reports())
+
+		_, err = primary.Get("key2")
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+	})
+
+	t.Run("Copy missing", func(t *testing.T) {
+		p, primary, _ := newProviders(t)
+
+		v := NewVerifier(p, WithCopyMissing(true))
+
+		r, err := v.Verify(storeName)
+		require.NoError(t, err)
+		require.Equal(t, &Report{Store: storeName, Checked: 3, Missing: 1, Mismatched: 1, Copied: 1}, r)
+
+		value, err := primary.Get("key2")
+		require.NoError(t, err)
+		require.Equal(t, "value2", string(value))
+
+		require.NoError(t, primary.Put("key3", []byte("value3"), storage.Tag{Name: "tag1", Value: "a"}))
+
+		r, err = v.Verify(storeName)
+		require.NoError(t, err)
+		require.True(t, r.InSync())
+		require.Equal(t, 0, r.Missing)
+
+		v.Run()
+	})
+
+	t.Run("Verification in progress", func(t *testing.T) {
+		p, _, _ := newProviders(t)
+
+		v := NewVerifier(p)
+		v.running = 1
+
+		v.Run()
+		require.Nil(t, v.Reports())
+	})
+
+	t.Run("Legacy store not found", func(t *testing.T) {
+		p := NewProvider(mem.NewProvider(), mem.NewProvider())
+
+		_, err := p.OpenStore(storeName)
+		require.NoError(t, err)
+
+		r, err := NewVerifier(p).Verify(storeName)
+		require.NoError(t, err)
+		require.Equal(t, 0, r.Checked)
+	})
+
+	t.Run("Query error", func(t *testing.T) {
+		errExpected := errors.New("injected query error")
+
+		s := &mocks.Store{}
+		s.QueryReturns(nil, errExpected)
+
+		secondary := &mocks.Provider{}
+		secondary.OpenStoreReturns(s, nil)
+		secondary.GetStoreConfigReturns(storage.StoreConfiguration{TagNames: []string{"tag1"}}, nil)
+
+		p := NewProvider(mem.NewProvider(), secondary)
+
+		_, err := p.OpenStore(storeName)
+		require.NoError(t, err)
+
+		v := NewVerifier(p)
+
+		_, err = v.VerifyAll()
+		require.True(t, errors.Is(err, errExpected))
+
+		v.Run()
+		require.Nil(t, v.Reports())
+	})
+
+	t.Run("Get config error", func(t *testing.T) {
+		errExpected := errors.New("injected config error")
+
+		secondary := &mocks.Provider{}
+		secondary.OpenStoreReturns(&mocks.Store{}, nil)
+		secondary.GetStoreConfigReturns(storage.StoreConfiguration{}, errExpected)
+
+		_, err := NewVerifier(NewProvider(mem.NewProvider(), secondary)).Verify(storeName)
+		require.True(t, errors.Is(err, errExpected))
+	})
+
+	t.Run("Iterator error", func(t *testing.T) {
+		errExpected := errors.New("injected iterator error")
+
+		it := &mocks.Iterator{}
+		it.NextReturns(false, errExpected)
+
+		s := &mocks.Store{}
+		s.QueryReturns(it, nil)
+
+		secondary := &mocks.Provider{}
+		secondary.OpenStoreReturns(s, nil)
+		secondary.GetStoreConfigReturns(storage.StoreConfiguration{TagNames: []string{"tag1"}}, nil)
+
+		_, err := NewVerifier(NewProvider(mem.NewProvider(), secondary)).Verify(storeName)
+		require.True(t, errors.Is(err, errExpected))
+	})
+}
+
+func TestEqualTags(t *testing.T) {
+	require.True(t, equalTags(
+		[]storage.Tag{{Name: "b", Value: "1"}, {Name: "a", Value: "2"}},
+		[]storage.Tag{{Name: "a", Value: "2"}, {Name: "b", Value: "1"}},
+	))
+	require.False(t, equalTags([]storage.Tag{{Name: "a"}}, nil))
+	require.False(t, equalTags([]storage.Tag{{Name: "a", Value: "1"}}, []storage.Tag{{Name: "a", Value: "2"}}))
+}
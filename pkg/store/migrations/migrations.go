@@ -0,0 +1,232 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package migrations
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+var logger = log.New("store-migrations")
+
+const (
+	nameSpace  = "schema-version"
+	versionKey = "version"
+)
+
+// MigrateFunc applies a migration to the stores of the given provider. If dryRun is true then the function must
+// not make any changes; it should only log the changes that it would make.
+type MigrateFunc func(provider storage.Provider, dryRun bool) error
+
+// Migration is a versioned change to the store schema, for example a new index (tag) or a new type of
+// reference. Migrations should be idempotent since more than one server may apply the same migration
+// concurrently at startup.
+type Migration struct {
+	Version     uint
+	Description string
+	Migrate     MigrateFunc
+}
+
+// AppliedMigration records a migration that was applied.
+type AppliedMigration struct {
+	Version     uint      `json:"version"`
+	Description string    `json:"description"`
+	Applied     time.Time `json:"applied"`
+}
+
+// SchemaVersion is the schema version of the stores along with the history of applied migrations.
+type SchemaVersion struct {
+	Version uint                `json:"version"`
+	Applied []*AppliedMigration `json:"applied,omitempty"`
+}
+
+// Result contains the migrations that were (or, in the case of a dry run, would be) applied.
+type Result struct {
+	DryRun      bool
+	FromVersion uint
+	ToVersion   uint
+	Migrations  []*Migration
+}
+
+// Manager tracks the schema version of the stores and applies pending migrations in order.
+type Manager struct {
+	provider   storage.Provider
+	store      storage.Store
+	migrations []*Migration
+	now        func() time.Time
+}
+
+// New returns a new migration manager for the given migrations. The versions of the migrations must be unique
+// and greater than zero (zero is the version of a store to which no migration was applied).
+func New(provider storage.Provider, migrations ...*Migration) (*Manager, error) {
+	sorted, err := sortMigrations(migrations)
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := provider.OpenStore(nameSpace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open schema version store: %w", err)
+	}
+
+	return &Manager{
+		provider:   provider,
+		store:      store,
+		migrations: sorted,
+		now:        time.Now,
+	}, nil
+}
+
+// Version returns the current schema version of the stores.
+func (m *Manager) Version() (*SchemaVersion, error) {
+	versionBytes, err := m.store.Get(versionKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return &SchemaVersion{}, nil
+		}
+
+		return nil, orberrors.NewTransient(fmt.Errorf("get schema version: %w", err))
+	}
+
+	version := &SchemaVersion{}
+
+	err = json.Unmarshal(versionBytes, version)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal schema version: %w", err)
+	}
+
+	return version, nil
+}
+
+// Pending returns the migrations that haven't been applied, in the order in which they will be applied.
+func (m *Manager) Pending() ([]*Migration, error) {
+	version, err := m.Version()
+	if err != nil {
+		return nil, err
+	}
+
+	return m.pending(version.Version), nil
+}
+
+// Migrate applies the pending migrations in order. The schema version is updated after each migration so that,
+// if a migration fails, the migrations that were applied before it aren't applied again. If dryRun is true then
+// each pending migration is invoked in dry-run mode and the schema version isn't updated.
+func (m *Manager) Migrate(dryRun bool) (*Result, error) {
+	version, err := m.Version()
+	if err != nil {
+		return nil, err
+	}
+
+	pending := m.pending(version.Version)
+
+	result := &Result{
+		DryRun:      dryRun,
+		FromVersion: version.Version,
+		ToVersion:   version.Version,
+	}
+
+	if len(pending) == 0 {
+		logger.Debugf("Store schema is up to date at version %d", version.Version)
+
+		return result, nil
+	}
+
+	for _, migration := range pending {
+		logger.Infof("Applying store migration %d (%s) - dry run: %t", migration.Version, migration.Description, dryRun)
+
+		err = migration.Migrate(m.provider, dryRun)
+		if err != nil {
+			return result, fmt.Errorf("apply migration %d (%s): %w", migration.Version, migration.Description, err)
+		}
+
+		if !dryRun {
+			version.Version = migration.Version
+			version.Applied = append(version.Applied, &AppliedMigration{
+				Version:     migration.Version,
+				Description: migration.Description,
+				Applied:     m.now().UTC(),
+			})
+
+			err = m.putVersion(version)
+			if err != nil {
+				return result, err
+			}
+		}
+
+		result.ToVersion = migration.Version
+		result.Migrations = append(result.Migrations, migration)
+	}
+
+	logger.Infof("Store schema migrated from version %d to version %d - dry run: %t",
+		result.FromVersion, result.ToVersion, dryRun)
+
+	return result, nil
+}
+
+func (m *Manager) pending(version uint) []*Migration {
+	var pending []*Migration
+
+	for _, migration := range m.migrations {
+		if migration.Version > version {
+			pending = append(pending, migration)
+		}
+	}
+
+	return pending
+}
+
+func (m *Manager) putVersion(version *SchemaVersion) error {
+	versionBytes, err := json.Marshal(version)
+	if err != nil {
+		return fmt.Errorf("marshal schema version: %w", err)
+	}
+
+	err = m.store.Put(versionKey, versionBytes)
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("store schema version: %w", err))
+	}
+
+	return nil
+}
+
+func sortMigrations(migrations []*Migration) ([]*Migration, error) {
+	sorted := make([]*Migration, len(migrations))
+	copy(sorted, migrations)
+
+	versions := make(map[uint]struct{})
+
+	for _, migration := range sorted {
+		if migration.Version == 0 {
+			return nil, fmt.Errorf("invalid version for migration (%s): version must be greater than 0",
+				migration.Description)
+		}
+
+		if migration.Migrate == nil {
+			return nil, fmt.Errorf("migrate function is required for migration %d", migration.Version)
+		}
+
+		if _, exists := versions[migration.Version]; exists {
+			return nil, fmt.Errorf("duplicate migration version: %d", migration.Version)
+		}
+
+		versions[migration.Version] = struct{}{}
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Version < sorted[j].Version
+	})
+
+	return sorted, nil
+}
@@ -0,0 +1,174 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package migrations
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/stretchr/testify/require"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+func TestNew(t *testing.T) {
+	noop := func(storage.Provider, bool) error { return nil }
+
+	t.Run("Success", func(t *testing.T) {
+		m, err := New(mem.NewProvider(),
+			&Migration{Version: 2, Migrate: noop},
+			&Migration{Version: 1, Migrate: noop},
+		)
+		require.NoError(t, err)
+		require.Len(t, m.migrations, 2)
+		require.Equal(t, uint(1), m.migrations[0].Version)
+		require.Equal(t, uint(2), m.migrations[1].Version)
+	})
+
+	t.Run("Invalid migrations", func(t *testing.T) {
+		_, err := New(mem.NewProvider(), &Migration{Version: 0, Migrate: noop})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "version must be greater than 0")
+
+		_, err = New(mem.NewProvider(), &Migration{Version: 1})
+		require.EqualError(t, err, "migrate function is required for migration 1")
+
+		_, err = New(mem.NewProvider(), &Migration{Version: 1, Migrate: noop}, &Migration{Version: 1, Migrate: noop})
+		require.EqualError(t, err, "duplicate migration version: 1")
+	})
+
+	t.Run("Open store error", func(t *testing.T) {
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(nil, errors.New("injected open error"))
+
+		_, err := New(provider)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected open error")
+	})
+}
+
+func TestManager_Migrate(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		provider := mem.NewProvider()
+
+		var applied []uint
+
+		newMigration := func(version uint) *Migration {
+			return &Migration{
+				Version:     version,
+				Description: "test migration",
+				Migrate: func(_ storage.Provider, dryRun bool) error {
+					if !dryRun {
+						applied = append(applied, version)
+					}
+
+					return nil
+				},
+			}
+		}
+
+		m, err := New(provider, newMigration(1), newMigration(2))
+		require.NoError(t, err)
+
+		pending, err := m.Pending()
+		require.NoError(t, err)
+		require.Len(t, pending, 2)
+
+		// Dry run doesn't apply anything.
+		result, err := m.Migrate(true)
+		require.NoError(t, err)
+		require.True(t, result.DryRun)
+		require.Equal(t, uint(0), result.FromVersion)
+		require.Equal(t, uint(2), result.ToVersion)
+		require.Len(t, result.Migrations, 2)
+		require.Empty(t, applied)
+
+		version, err := m.Version()
+		require.NoError(t, err)
+		require.Equal(t, uint(0), version.Version)
+
+		result, err = m.Migrate(false)
+		require.NoError(t, err)
+		require.False(t, result.DryRun)
+		require.Equal(t, uint(2), result.ToVersion)
+		require.Equal(t, []uint{1, 2}, applied)
+
+		version, err = m.Version()
+		require.NoError(t, err)
+		require.Equal(t, uint(2), version.Version)
+		require.Len(t, version.Applied, 2)
+
+		// A new version of the server adds migration 3. Only migration 3 is applied.
+		m, err = New(provider, newMigration(1), newMigration(2), newMigration(3))
+		require.NoError(t, err)
+
+		result, err = m.Migrate(false)
+		require.NoError(t, err)
+		require.Equal(t, uint(2), result.FromVersion)
+		require.Equal(t, uint(3), result.ToVersion)
+		require.Len(t, result.Migrations, 1)
+		require.Equal(t, []uint{1, 2, 3}, applied)
+
+		// Up to date.
+		result, err = m.Migrate(false)
+		require.NoError(t, err)
+		require.Empty(t, result.Migrations)
+	})
+
+	t.Run("Migration error", func(t *testing.T) {
+		m, err := New(mem.NewProvider(),
+			&Migration{Version: 1, Migrate: func(storage.Provider, bool) error { return nil }},
+			&Migration{Version: 2, Migrate: func(storage.Provider, bool) error { return errors.New("injected error") }},
+		)
+		require.NoError(t, err)
+
+		result, err := m.Migrate(false)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "apply migration 2")
+		require.Equal(t, uint(1), result.ToVersion)
+
+		// Migration 1 isn't applied again.
+		version, err := m.Version()
+		require.NoError(t, err)
+		require.Equal(t, uint(1), version.Version)
+	})
+
+	t.Run("Store errors", func(t *testing.T) {
+		errExpected := errors.New("injected store error")
+
+		store := &mocks.Store{}
+		store.GetReturns(nil, errExpected)
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		m, err := New(provider, Orb()...)
+		require.NoError(t, err)
+
+		_, err = m.Migrate(false)
+		require.True(t, errors.Is(err, errExpected))
+		require.True(t, orberrors.IsTransient(err))
+
+		_, err = m.Pending()
+		require.True(t, errors.Is(err, errExpected))
+
+		store.GetReturns([]byte("{"), nil)
+
+		_, err = m.Version()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal schema version")
+
+		store.GetReturns(nil, storage.ErrDataNotFound)
+		store.PutReturns(errExpected)
+
+		_, err = m.Migrate(false)
+		require.True(t, errors.Is(err, errExpected))
+	})
+}
@@ -0,0 +1,79 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package migrations
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+// Orb returns the store migrations of Orb, in order. A new migration must be appended with the next version
+// number whenever a release changes the layout of a store (for example, a new tag that must be indexed in an
+// existing store). Migrations must never be removed or renumbered once released.
+func Orb() []*Migration {
+	return []*Migration{
+		{
+			Version:     1,
+			Description: "Initial schema version",
+			Migrate:     func(storage.Provider, bool) error { return nil },
+		},
+	}
+}
+
+// AddTags returns a migration function that adds the given tag names to the configuration of the given store,
+// so that the database creates indexes for them. Tags that are already configured are left as is.
+func AddTags(storeName string, tagNames ...string) MigrateFunc {
+	return func(provider storage.Provider, dryRun bool) error {
+		if _, err := provider.OpenStore(storeName); err != nil {
+			return fmt.Errorf("open store [%s]: %w", storeName, err)
+		}
+
+		config, err := provider.GetStoreConfig(storeName)
+		if err != nil && !errors.Is(err, storage.ErrStoreNotFound) {
+			return fmt.Errorf("get configuration of store [%s]: %w", storeName, err)
+		}
+
+		existing := make(map[string]struct{})
+
+		for _, name := range config.TagNames {
+			existing[name] = struct{}{}
+		}
+
+		var added []string
+
+		for _, name := range tagNames {
+			if _, ok := existing[name]; !ok {
+				added = append(added, name)
+			}
+		}
+
+		if len(added) == 0 {
+			logger.Infof("Store [%s] already has tags %s", storeName, tagNames)
+
+			return nil
+		}
+
+		if dryRun {
+			logger.Infof("[Dry run] Would add tags %s to store [%s]", added, storeName)
+
+			return nil
+		}
+
+		config.TagNames = append(config.TagNames, added...)
+
+		err = provider.SetStoreConfig(storeName, config)
+		if err != nil {
+			return fmt.Errorf("set configuration of store [%s]: %w", storeName, err)
+		}
+
+		logger.Infof("Added tags %s to store [%s]", added, storeName)
+
+		return nil
+	}
+}
@@ -0,0 +1,82 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package migrations
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+func TestOrb(t *testing.T) {
+	m, err := New(mem.NewProvider(), Orb()...)
+	require.NoError(t, err)
+
+	result, err := m.Migrate(false)
+	require.NoError(t, err)
+	require.Equal(t, uint(len(Orb())), result.ToVersion)
+}
+
+func TestAddTags(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		provider := mem.NewProvider()
+
+		_, err := provider.OpenStore("store1")
+		require.NoError(t, err)
+
+		require.NoError(t, provider.SetStoreConfig("store1", storage.StoreConfiguration{TagNames: []string{"tag1"}}))
+
+		migrate := AddTags("store1", "tag1", "tag2")
+
+		require.NoError(t, migrate(provider, true))
+
+		config, err := provider.GetStoreConfig("store1")
+		require.NoError(t, err)
+		require.Equal(t, []string{"tag1"}, config.TagNames)
+
+		require.NoError(t, migrate(provider, false))
+
+		config, err = provider.GetStoreConfig("store1")
+		require.NoError(t, err)
+		require.Equal(t, []string{"tag1", "tag2"}, config.TagNames)
+
+		// Already applied.
+		require.NoError(t, migrate(provider, false))
+	})
+
+	t.Run("Open store error", func(t *testing.T) {
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(nil, errors.New("injected open error"))
+
+		err := AddTags("store1", "tag1")(provider, false)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected open error")
+	})
+
+	t.Run("Get store config error", func(t *testing.T) {
+		provider := &mocks.Provider{}
+		provider.GetStoreConfigReturns(storage.StoreConfiguration{}, errors.New("injected config error"))
+
+		err := AddTags("store1", "tag1")(provider, false)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected config error")
+	})
+
+	t.Run("Set store config error", func(t *testing.T) {
+		provider := &mocks.Provider{}
+		provider.SetStoreConfigReturns(errors.New("injected config error"))
+
+		err := AddTags("store1", "tag1")(provider, false)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected config error")
+	})
+}
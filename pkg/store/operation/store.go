@@ -22,6 +22,8 @@ import (
 const (
 	namespace = "operation"
 	index     = "suffix"
+
+	suffixPageSize = 1000
 )
 
 var logger = log.New("operation-store")
@@ -158,3 +160,44 @@ func (s *Store) Get(suffix string) ([]*operation.AnchoredOperation, error) {
 
 	return ops, nil
 }
+
+// ForEachSuffix invokes the given function with the suffix of each stored operation, i.e. the function
+// is invoked once for each operation of a suffix. The iteration stops if the function returns an error.
+func (s *Store) ForEachSuffix(fn func(suffix string) error) error {
+	iter, err := s.store.Query(index, storage.WithPageSize(suffixPageSize))
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("failed to query operations: %w", err))
+	}
+
+	defer func() {
+		if e := iter.Close(); e != nil {
+			logger.Warnf("Error closing iterator: %s", e)
+		}
+	}()
+
+	for {
+		ok, e := iter.Next()
+		if e != nil {
+			return orberrors.NewTransient(fmt.Errorf("iterator error: %w", e))
+		}
+
+		if !ok {
+			return nil
+		}
+
+		tags, e := iter.Tags()
+		if e != nil {
+			return orberrors.NewTransient(fmt.Errorf("failed to get iterator tags: %w", e))
+		}
+
+		for _, tag := range tags {
+			if tag.Name != index {
+				continue
+			}
+
+			if e = fn(tag.Value); e != nil {
+				return e
+			}
+		}
+	}
+}
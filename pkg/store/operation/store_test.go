@@ -188,6 +188,88 @@ func TestStore_Get(t *testing.T) {
 	})
 }
 
+func TestStore_ForEachSuffix(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		s, err := New(mem.NewProvider(), &orbmocks.MetricsProvider{})
+		require.NoError(t, err)
+
+		require.NoError(t, s.Put([]*operation.AnchoredOperation{
+			getTestOperation(),
+			{Type: operation.TypeUpdate, UniqueSuffix: testSuffix},
+			{Type: operation.TypeCreate, UniqueSuffix: "suffix2"},
+		}))
+
+		suffixes := make(map[string]int)
+
+		require.NoError(t, s.ForEachSuffix(func(suffix string) error {
+			suffixes[suffix]++
+
+			return nil
+		}))
+
+		require.Equal(t, map[string]int{testSuffix: 2, "suffix2": 1}, suffixes)
+
+		errExpected := fmt.Errorf("stop")
+
+		err = s.ForEachSuffix(func(string) error {
+			return errExpected
+		})
+		require.Equal(t, errExpected, err)
+	})
+
+	t.Run("error - query error", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.QueryReturns(nil, fmt.Errorf("query error"))
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := New(provider, &orbmocks.MetricsProvider{})
+		require.NoError(t, err)
+
+		err = s.ForEachSuffix(func(string) error { return nil })
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "query error")
+	})
+
+	t.Run("error - iterator error", func(t *testing.T) {
+		iterator := &mocks.Iterator{}
+		iterator.NextReturns(false, fmt.Errorf("iterator next() error"))
+
+		store := &mocks.Store{}
+		store.QueryReturns(iterator, nil)
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := New(provider, &orbmocks.MetricsProvider{})
+		require.NoError(t, err)
+
+		err = s.ForEachSuffix(func(string) error { return nil })
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "iterator next() error")
+	})
+
+	t.Run("error - tags error", func(t *testing.T) {
+		iterator := &mocks.Iterator{}
+		iterator.NextReturns(true, nil)
+		iterator.TagsReturns(nil, fmt.Errorf("iterator tags() error"))
+
+		store := &mocks.Store{}
+		store.QueryReturns(iterator, nil)
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := New(provider, &orbmocks.MetricsProvider{})
+		require.NoError(t, err)
+
+		err = s.ForEachSuffix(func(string) error { return nil })
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "iterator tags() error")
+	})
+}
+
 func getTestOperation() *operation.AnchoredOperation {
 	return &operation.AnchoredOperation{
 		Type:         operation.TypeCreate,
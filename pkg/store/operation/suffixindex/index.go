@@ -0,0 +1,674 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package suffixindex
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/lifecycle"
+)
+
+var logger = log.New("suffix-index")
+
+const (
+	storeName  = "suffix-index"
+	chunkTag   = "chunk"
+	journalTag = "journal"
+
+	defaultSyncInterval      = 10 * time.Second
+	defaultFalsePositiveRate = 0.01
+
+	// Journal entries are kept for a few sync intervals so that they're merged into the persisted
+	// filter by at least one instance even if concurrent instances overwrite each other's chunks.
+	journalRetentionIntervals = 3
+
+	chunkWords   = 8192 // 64KB per chunk
+	wordBits     = 64
+	bytesPerWord = 8
+)
+
+var errStopped = errors.New("suffix index stopped")
+
+type suffixProvider interface {
+	ForEachSuffix(fn func(suffix string) error) error
+}
+
+type options struct {
+	syncInterval      time.Duration
+	falsePositiveRate float64
+}
+
+// Opt sets an index option.
+type Opt func(opts *options)
+
+// WithSyncInterval sets the interval at which the index is synchronized with the database.
+func WithSyncInterval(value time.Duration) Opt {
+	return func(opts *options) {
+		opts.syncInterval = value
+	}
+}
+
+// WithFalsePositiveRate sets the expected rate of false positives (i.e. the rate at which a suffix that
+// isn't in the index is reported as possibly being in the index).
+func WithFalsePositiveRate(value float64) Opt {
+	return func(opts *options) {
+		opts.falsePositiveRate = value
+	}
+}
+
+// Index is a persisted bloom filter over the DID suffixes that have operations in the operation store.
+// If the index reports that a suffix is not contained then the suffix is definitely not in the operation
+// store, so misses are answered without querying the operation store.
+//
+// The index is updated incrementally: the suffixes of new operations are added to the in-memory filter and
+// are also written to a journal (before the operations are stored) so that they aren't lost if the instance
+// terminates before the filter is persisted. The filter is persisted in chunks which are periodically
+// merged (bitwise OR) with the chunks in the database, so multiple instances that share the same database
+// converge on the same filter. Note that an instance learns about the suffixes that were added by other
+// instances only after the next synchronization.
+//
+// If the index doesn't yet exist in the database (or if its size changed) then it is rebuilt in the
+// background from the operation store. The index isn't consulted until the rebuild has completed.
+type Index struct {
+	*lifecycle.Lifecycle
+
+	store          storage.Store
+	suffixes       suffixProvider
+	numBits        uint64
+	numHashes      uint64
+	generation     string
+	syncInterval   time.Duration
+	journalExpiry  time.Duration
+	done           chan struct{}
+	wg             sync.WaitGroup
+	now            func() time.Time
+	syncMutex      sync.Mutex
+	mutex          sync.RWMutex
+	bits           []uint64
+	ready          bool
+	rebuildStarted bool
+}
+
+type journalEntry struct {
+	Time     int64    `json:"time"`
+	Suffixes []string `json:"suffixes"`
+}
+
+type metadata struct {
+	NumBits   uint64 `json:"numBits"`
+	NumHashes uint64 `json:"numHashes"`
+	Complete  bool   `json:"complete"`
+}
+
+// New returns a new suffix index that is sized for the given number of suffixes. The given suffix provider
+// is used to rebuild the index if it doesn't exist in the database.
+func New(provider storage.Provider, suffixes suffixProvider, expectedSuffixes uint, opts ...Opt) (*Index, error) {
+	options := &options{
+		syncInterval:      defaultSyncInterval,
+		falsePositiveRate: defaultFalsePositiveRate,
+	}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	store, err := provider.OpenStore(storeName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open suffix index store: %w", err)
+	}
+
+	err = provider.SetStoreConfig(storeName, storage.StoreConfiguration{TagNames: []string{chunkTag, journalTag}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set store configuration: %w", err)
+	}
+
+	numBits, numHashes := filterSize(expectedSuffixes, options.falsePositiveRate)
+
+	idx := &Index{
+		store:         store,
+		suffixes:      suffixes,
+		numBits:       numBits,
+		numHashes:     numHashes,
+		generation:    fmt.Sprintf("%d-%d", numBits, numHashes),
+		syncInterval:  options.syncInterval,
+		journalExpiry: journalRetentionIntervals * options.syncInterval,
+		done:          make(chan struct{}),
+		now:           time.Now,
+		bits:          make([]uint64, (numBits+wordBits-1)/wordBits),
+	}
+
+	idx.Lifecycle = lifecycle.New("suffix-index",
+		lifecycle.WithStart(idx.start),
+		lifecycle.WithStop(idx.stop),
+	)
+
+	logger.Debugf("Created suffix index with %d bits and %d hash functions", numBits, numHashes)
+
+	return idx, nil
+}
+
+// Add adds the given suffixes to the index. The suffixes are written to the journal before they're added
+// to the in-memory filter, so Add must be called before the operations of the suffixes are stored.
+func (idx *Index) Add(suffixes ...string) error {
+	if len(suffixes) == 0 {
+		return nil
+	}
+
+	entryBytes, err := json.Marshal(&journalEntry{
+		Time:     idx.now().Unix(),
+		Suffixes: suffixes,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal journal entry: %w", err)
+	}
+
+	err = idx.store.Put(uuid.New().String(), entryBytes, storage.Tag{Name: journalTag})
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("store journal entry: %w", err))
+	}
+
+	idx.mutex.Lock()
+	defer idx.mutex.Unlock()
+
+	for _, suffix := range suffixes {
+		idx.add(suffix)
+	}
+
+	return nil
+}
+
+// MayContain returns false if the given suffix is definitely not in the operation store. If true is returned
+// then the suffix may be in the operation store. True is always returned until the index is ready.
+func (idx *Index) MayContain(suffix string) bool {
+	idx.mutex.RLock()
+	defer idx.mutex.RUnlock()
+
+	if !idx.ready {
+		return true
+	}
+
+	h1, h2 := hash(suffix)
+
+	for i := uint64(0); i < idx.numHashes; i++ {
+		if !idx.isSet((h1 + i*h2) % idx.numBits) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IsReady returns true if the index has been fully built and is being consulted.
+func (idx *Index) IsReady() bool {
+	idx.mutex.RLock()
+	defer idx.mutex.RUnlock()
+
+	return idx.ready
+}
+
+func (idx *Index) start() {
+	idx.wg.Add(1)
+
+	go idx.run()
+
+	logger.Infof("Started suffix index")
+}
+
+func (idx *Index) stop() {
+	close(idx.done)
+
+	idx.wg.Wait()
+
+	// Persist the suffixes that were added since the last synchronization.
+	if err := idx.sync(); err != nil {
+		logger.Warnf("Error synchronizing suffix index: %s", err)
+	}
+
+	logger.Infof("Stopped suffix index")
+}
+
+func (idx *Index) run() {
+	defer idx.wg.Done()
+
+	idx.syncAndRebuild()
+
+	ticker := time.NewTicker(idx.syncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			idx.syncAndRebuild()
+		case <-idx.done:
+			return
+		}
+	}
+}
+
+func (idx *Index) syncAndRebuild() {
+	if err := idx.sync(); err != nil {
+		logger.Warnf("Error synchronizing suffix index: %s", err)
+
+		return
+	}
+
+	if idx.IsReady() {
+		return
+	}
+
+	md, err := idx.getMetadata()
+	if err != nil {
+		logger.Warnf("Error loading suffix index metadata: %s", err)
+
+		return
+	}
+
+	if md.Complete {
+		// The index was built by this instance or by another instance that shares the database. The
+		// persisted chunks have been merged into the local filter.
+		idx.setReady()
+
+		return
+	}
+
+	if !idx.rebuildStarted {
+		idx.rebuildStarted = true
+
+		idx.wg.Add(1)
+
+		go idx.rebuild()
+	}
+}
+
+// rebuild adds all of the suffixes in the operation store to the index.
+func (idx *Index) rebuild() {
+	defer idx.wg.Done()
+
+	logger.Infof("Rebuilding suffix index from the operation store ...")
+
+	n := 0
+
+	err := idx.suffixes.ForEachSuffix(func(suffix string) error {
+		select {
+		case <-idx.done:
+			return errStopped
+		default:
+		}
+
+		idx.mutex.Lock()
+		idx.add(suffix)
+		idx.mutex.Unlock()
+
+		n++
+
+		return nil
+	})
+	if err != nil {
+		logger.Errorf("Error rebuilding suffix index. The index will be rebuilt after the next restart: %s", err)
+
+		return
+	}
+
+	if err = idx.sync(); err != nil {
+		logger.Errorf("Error persisting rebuilt suffix index. The index will be rebuilt after the next restart: %s",
+			err)
+
+		return
+	}
+
+	if err = idx.putMetadata(&metadata{NumBits: idx.numBits, NumHashes: idx.numHashes, Complete: true}); err != nil {
+		logger.Errorf("Error storing suffix index metadata. The index will be rebuilt after the next restart: %s", err)
+
+		return
+	}
+
+	idx.setReady()
+
+	logger.Infof("... rebuilt suffix index from %d operations", n)
+}
+
+// sync merges the journal entries and the persisted chunks into the local filter and then persists
+// the chunks that are missing any bits of the local filter.
+func (idx *Index) sync() error {
+	idx.syncMutex.Lock()
+	defer idx.syncMutex.Unlock()
+
+	expiredEntries, err := idx.mergeJournal()
+	if err != nil {
+		return err
+	}
+
+	persisted, err := idx.loadChunks()
+	if err != nil {
+		return err
+	}
+
+	var ops []storage.Operation
+
+	for i := 0; i < idx.numChunks(); i++ {
+		if chunkBytes := idx.mergeChunk(i, persisted[i]); chunkBytes != nil {
+			ops = append(ops, storage.Operation{
+				Key:   idx.chunkKey(i),
+				Value: chunkBytes,
+				Tags:  []storage.Tag{{Name: chunkTag, Value: idx.generation}},
+			})
+		}
+	}
+
+	// The expired journal entries are deleted in the same batch as the chunks that contain them.
+	for _, key := range expiredEntries {
+		ops = append(ops, storage.Operation{Key: key})
+	}
+
+	if len(ops) == 0 {
+		return nil
+	}
+
+	err = idx.store.Batch(ops)
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("store suffix index chunks: %w", err))
+	}
+
+	logger.Debugf("Persisted %d suffix index chunks and deleted %d journal entries",
+		len(ops)-len(expiredEntries), len(expiredEntries))
+
+	return nil
+}
+
+// mergeJournal adds the suffixes in the journal to the local filter and returns the keys of the
+// expired journal entries.
+func (idx *Index) mergeJournal() ([]string, error) {
+	it, err := idx.store.Query(journalTag)
+	if err != nil {
+		return nil, orberrors.NewTransient(fmt.Errorf("query suffix index journal: %w", err))
+	}
+
+	defer func() {
+		if e := it.Close(); e != nil {
+			logger.Warnf("Error closing iterator: %s", e)
+		}
+	}()
+
+	expiryTime := idx.now().Add(-idx.journalExpiry).Unix()
+
+	var expired []string
+
+	for {
+		ok, e := it.Next()
+		if e != nil {
+			return nil, orberrors.NewTransient(fmt.Errorf("next journal entry: %w", e))
+		}
+
+		if !ok {
+			return expired, nil
+		}
+
+		key, entry, e := getJournalEntry(it)
+		if e != nil {
+			return nil, e
+		}
+
+		idx.mutex.Lock()
+
+		for _, suffix := range entry.Suffixes {
+			idx.add(suffix)
+		}
+
+		idx.mutex.Unlock()
+
+		if entry.Time < expiryTime {
+			expired = append(expired, key)
+		}
+	}
+}
+
+func (idx *Index) loadChunks() (map[int][]byte, error) {
+	it, err := idx.store.Query(fmt.Sprintf("%s:%s", chunkTag, idx.generation))
+	if err != nil {
+		return nil, orberrors.NewTransient(fmt.Errorf("query suffix index chunks: %w", err))
+	}
+
+	defer func() {
+		if e := it.Close(); e != nil {
+			logger.Warnf("Error closing iterator: %s", e)
+		}
+	}()
+
+	chunks := make(map[int][]byte)
+
+	for {
+		ok, e := it.Next()
+		if e != nil {
+			return nil, orberrors.NewTransient(fmt.Errorf("next suffix index chunk: %w", e))
+		}
+
+		if !ok {
+			return chunks, nil
+		}
+
+		key, e := it.Key()
+		if e != nil {
+			return nil, orberrors.NewTransient(fmt.Errorf("get key from iterator: %w", e))
+		}
+
+		i, ok := idx.chunkIndex(key)
+		if !ok {
+			logger.Warnf("Ignoring invalid suffix index chunk [%s]", key)
+
+			continue
+		}
+
+		value, e := it.Value()
+		if e != nil {
+			return nil, orberrors.NewTransient(fmt.Errorf("get value from iterator: %w", e))
+		}
+
+		chunks[i] = value
+	}
+}
+
+// mergeChunk merges the given persisted chunk into the local filter and returns the merged chunk if the
+// persisted chunk needs to be updated, otherwise nil is returned.
+func (idx *Index) mergeChunk(i int, persisted []byte) []byte {
+	idx.mutex.Lock()
+	defer idx.mutex.Unlock()
+
+	start := i * chunkWords
+
+	end := start + chunkWords
+	if end > len(idx.bits) {
+		end = len(idx.bits)
+	}
+
+	changed := len(persisted) != (end-start)*bytesPerWord
+
+	for w := start; w < end; w++ {
+		var p uint64
+
+		offset := (w - start) * bytesPerWord
+		if offset+bytesPerWord <= len(persisted) {
+			p = binary.LittleEndian.Uint64(persisted[offset:])
+		}
+
+		if idx.bits[w]|p != p {
+			changed = true
+		}
+
+		idx.bits[w] |= p
+	}
+
+	if !changed {
+		return nil
+	}
+
+	chunkBytes := make([]byte, (end-start)*bytesPerWord)
+
+	for w := start; w < end; w++ {
+		binary.LittleEndian.PutUint64(chunkBytes[(w-start)*bytesPerWord:], idx.bits[w])
+	}
+
+	return chunkBytes
+}
+
+func (idx *Index) getMetadata() (*metadata, error) {
+	mdBytes, err := idx.store.Get(idx.metadataKey())
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return &metadata{NumBits: idx.numBits, NumHashes: idx.numHashes}, nil
+		}
+
+		return nil, orberrors.NewTransient(fmt.Errorf("get suffix index metadata: %w", err))
+	}
+
+	md := &metadata{}
+
+	err = json.Unmarshal(mdBytes, md)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal suffix index metadata: %w", err)
+	}
+
+	return md, nil
+}
+
+func (idx *Index) putMetadata(md *metadata) error {
+	mdBytes, err := json.Marshal(md)
+	if err != nil {
+		return fmt.Errorf("marshal suffix index metadata: %w", err)
+	}
+
+	err = idx.store.Put(idx.metadataKey(), mdBytes)
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("store suffix index metadata: %w", err))
+	}
+
+	return nil
+}
+
+func (idx *Index) setReady() {
+	idx.mutex.Lock()
+	defer idx.mutex.Unlock()
+
+	if !idx.ready {
+		logger.Infof("Suffix index is ready")
+	}
+
+	idx.ready = true
+}
+
+// add adds the suffix to the local filter. The caller must hold the lock.
+func (idx *Index) add(suffix string) {
+	h1, h2 := hash(suffix)
+
+	for i := uint64(0); i < idx.numHashes; i++ {
+		bit := (h1 + i*h2) % idx.numBits
+
+		idx.bits[bit/wordBits] |= 1 << (bit % wordBits)
+	}
+}
+
+func (idx *Index) isSet(bit uint64) bool {
+	return idx.bits[bit/wordBits]&(1<<(bit%wordBits)) != 0
+}
+
+func (idx *Index) numChunks() int {
+	return (len(idx.bits) + chunkWords - 1) / chunkWords
+}
+
+func (idx *Index) chunkKey(i int) string {
+	return fmt.Sprintf("%s-%s-%d", chunkTag, idx.generation, i)
+}
+
+func (idx *Index) chunkIndex(key string) (int, bool) {
+	prefix := fmt.Sprintf("%s-%s-", chunkTag, idx.generation)
+
+	if !strings.HasPrefix(key, prefix) {
+		return 0, false
+	}
+
+	i, err := strconv.Atoi(strings.TrimPrefix(key, prefix))
+	if err != nil || i < 0 || i >= idx.numChunks() {
+		return 0, false
+	}
+
+	return i, true
+}
+
+func (idx *Index) metadataKey() string {
+	return fmt.Sprintf("metadata-%s", idx.generation)
+}
+
+func getJournalEntry(it storage.Iterator) (string, *journalEntry, error) {
+	key, err := it.Key()
+	if err != nil {
+		return "", nil, orberrors.NewTransient(fmt.Errorf("get key from iterator: %w", err))
+	}
+
+	value, err := it.Value()
+	if err != nil {
+		return "", nil, orberrors.NewTransient(fmt.Errorf("get value from iterator: %w", err))
+	}
+
+	entry := &journalEntry{}
+
+	err = json.Unmarshal(value, entry)
+	if err != nil {
+		return "", nil, fmt.Errorf("unmarshal journal entry [%s]: %w", key, err)
+	}
+
+	return key, entry, nil
+}
+
+// filterSize returns the optimal number of bits (m) and hash functions (k) for n items with a false
+// positive rate p: m = -n*ln(p)/(ln(2)^2) and k = (m/n)*ln(2).
+func filterSize(expectedItems uint, falsePositiveRate float64) (uint64, uint64) {
+	if expectedItems == 0 {
+		expectedItems = 1
+	}
+
+	n := float64(expectedItems)
+
+	numBits := uint64(math.Ceil(-n * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2)))
+	if numBits < wordBits {
+		numBits = wordBits
+	}
+
+	numHashes := uint64(math.Ceil(float64(numBits) / n * math.Ln2))
+	if numHashes == 0 {
+		numHashes = 1
+	}
+
+	return numBits, numHashes
+}
+
+// hash returns two hash values which are combined (double hashing) to simulate k hash functions.
+func hash(value string) (uint64, uint64) {
+	h := fnv.New64a()
+
+	// Write never returns an error.
+	_, _ = h.Write([]byte(value)) //nolint:errcheck
+
+	h1 := h.Sum64()
+
+	// Derive a second hash from the first one (and ensure that it's odd so that it's never zero).
+	h2 := (h1>>33 | h1<<31) | 1 //nolint:gomnd
+
+	return h1, h2
+}
@@ -0,0 +1,345 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package suffixindex
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/stretchr/testify/require"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+const (
+	suffix1 = "EiA329wd6Aj36YRmp7NGkeB5ADnVt8ARdMZMPzfXsjwTJA"
+	suffix2 = "EiBmPrHs7iR4Ecn6dzyBpF1yxsc0-b-9MmWzBV_Kp_2Mmw"
+	suffix3 = "EiDJpL-xeSE4kVUoGjB3l4CsJXW3h-9N5FIcOMqT5eyGxw"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		idx, err := New(mem.NewProvider(), &mockSuffixProvider{}, 1000)
+		require.NoError(t, err)
+		require.NotNil(t, idx)
+		require.False(t, idx.IsReady())
+		require.True(t, idx.MayContain(suffix1))
+	})
+
+	t.Run("open store error", func(t *testing.T) {
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(nil, errors.New("injected open error"))
+
+		_, err := New(provider, &mockSuffixProvider{}, 1000)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected open error")
+	})
+
+	t.Run("set store config error", func(t *testing.T) {
+		provider := &mocks.Provider{}
+		provider.SetStoreConfigReturns(errors.New("injected config error"))
+
+		_, err := New(provider, &mockSuffixProvider{}, 1000)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected config error")
+	})
+}
+
+func TestIndex(t *testing.T) {
+	t.Run("rebuild", func(t *testing.T) {
+		idx, err := New(mem.NewProvider(), &mockSuffixProvider{suffixes: []string{suffix1, suffix1}}, 1000,
+			WithSyncInterval(50*time.Millisecond))
+		require.NoError(t, err)
+
+		idx.Start()
+		defer idx.Stop()
+
+		require.Eventually(t, idx.IsReady, 5*time.Second, 10*time.Millisecond)
+
+		require.True(t, idx.MayContain(suffix1))
+		require.False(t, idx.MayContain(suffix2))
+
+		require.NoError(t, idx.Add(suffix2))
+		require.True(t, idx.MayContain(suffix2))
+	})
+
+	t.Run("rebuild error", func(t *testing.T) {
+		idx, err := New(mem.NewProvider(), &mockSuffixProvider{err: errors.New("injected iterator error")}, 1000,
+			WithSyncInterval(50*time.Millisecond))
+		require.NoError(t, err)
+
+		idx.Start()
+
+		time.Sleep(200 * time.Millisecond)
+
+		idx.Stop()
+
+		require.False(t, idx.IsReady())
+		require.True(t, idx.MayContain(suffix1))
+	})
+
+	t.Run("loaded from database", func(t *testing.T) {
+		provider := mem.NewProvider()
+
+		idx1, err := New(provider, &mockSuffixProvider{}, 1000, WithSyncInterval(50*time.Millisecond))
+		require.NoError(t, err)
+
+		idx1.Start()
+
+		require.Eventually(t, idx1.IsReady, 5*time.Second, 10*time.Millisecond)
+
+		require.NoError(t, idx1.Add(suffix1, suffix2))
+
+		idx1.Stop()
+
+		// The index shouldn't be rebuilt since it was persisted by the previous instance.
+		idx2, err := New(provider, &mockSuffixProvider{err: errors.New("unexpected rebuild")}, 1000,
+			WithSyncInterval(50*time.Millisecond))
+		require.NoError(t, err)
+
+		idx2.Start()
+		defer idx2.Stop()
+
+		require.Eventually(t, idx2.IsReady, 5*time.Second, 10*time.Millisecond)
+
+		require.True(t, idx2.MayContain(suffix1))
+		require.True(t, idx2.MayContain(suffix2))
+		require.False(t, idx2.MayContain(suffix3))
+
+		t.Run("different size -> rebuild", func(t *testing.T) {
+			idx3, err := New(provider, &mockSuffixProvider{suffixes: []string{suffix3}}, 5000,
+				WithSyncInterval(50*time.Millisecond))
+			require.NoError(t, err)
+
+			idx3.Start()
+			defer idx3.Stop()
+
+			require.Eventually(t, idx3.IsReady, 5*time.Second, 10*time.Millisecond)
+
+			require.True(t, idx3.MayContain(suffix3))
+		})
+	})
+
+	t.Run("multiple instances", func(t *testing.T) {
+		provider := mem.NewProvider()
+
+		idx1, err := New(provider, &mockSuffixProvider{}, 1000, WithSyncInterval(50*time.Millisecond))
+		require.NoError(t, err)
+
+		idx2, err := New(provider, &mockSuffixProvider{}, 1000, WithSyncInterval(50*time.Millisecond))
+		require.NoError(t, err)
+
+		idx1.Start()
+		defer idx1.Stop()
+
+		idx2.Start()
+		defer idx2.Stop()
+
+		require.Eventually(t, idx1.IsReady, 5*time.Second, 10*time.Millisecond)
+		require.Eventually(t, idx2.IsReady, 5*time.Second, 10*time.Millisecond)
+
+		require.NoError(t, idx1.Add(suffix1))
+		require.NoError(t, idx2.Add(suffix2))
+
+		require.Eventually(t, func() bool {
+			return idx1.MayContain(suffix2) && idx2.MayContain(suffix1)
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+}
+
+func TestIndex_Journal(t *testing.T) {
+	provider := mem.NewProvider()
+
+	idx, err := New(provider, &mockSuffixProvider{}, 1000)
+	require.NoError(t, err)
+
+	require.NoError(t, idx.Add())
+	require.NoError(t, idx.Add(suffix1))
+	require.NoError(t, idx.Add(suffix2))
+
+	require.Equal(t, 2, countEntries(t, idx.store, journalTag))
+
+	require.NoError(t, idx.sync())
+
+	// The journal entries haven't expired yet.
+	require.Equal(t, 2, countEntries(t, idx.store, journalTag))
+	require.Equal(t, idx.numChunks(), countEntries(t, idx.store, fmt.Sprintf("%s:%s", chunkTag, idx.generation)))
+
+	idx.now = func() time.Time { return time.Now().Add(time.Hour) }
+
+	require.NoError(t, idx.sync())
+	require.Zero(t, countEntries(t, idx.store, journalTag))
+
+	// The suffixes in the journal are merged by another instance.
+	require.NoError(t, idx.Add(suffix3))
+
+	idx2, err := New(provider, &mockSuffixProvider{}, 1000)
+	require.NoError(t, err)
+
+	require.NoError(t, idx2.sync())
+
+	idx2.setReady()
+
+	require.True(t, idx2.MayContain(suffix1))
+	require.True(t, idx2.MayContain(suffix2))
+	require.True(t, idx2.MayContain(suffix3))
+}
+
+func TestIndex_Errors(t *testing.T) {
+	errExpected := errors.New("injected storage error")
+
+	t.Run("add error", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.PutReturns(errExpected)
+
+		idx := newIndexWithStore(t, store)
+
+		err := idx.Add(suffix1)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.QueryReturns(nil, errExpected)
+
+		err := newIndexWithStore(t, store).sync()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errExpected.Error())
+	})
+
+	t.Run("iterator error", func(t *testing.T) {
+		it := &mocks.Iterator{}
+		it.NextReturns(false, errExpected)
+
+		store := &mocks.Store{}
+		store.QueryReturns(it, nil)
+
+		err := newIndexWithStore(t, store).sync()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errExpected.Error())
+	})
+
+	t.Run("invalid journal entry", func(t *testing.T) {
+		it := &mocks.Iterator{}
+		it.NextReturns(true, nil)
+		it.KeyReturns("key", nil)
+		it.ValueReturns([]byte("{"), nil)
+
+		store := &mocks.Store{}
+		store.QueryReturns(it, nil)
+
+		err := newIndexWithStore(t, store).sync()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal journal entry")
+	})
+
+	t.Run("batch error", func(t *testing.T) {
+		it := &mocks.Iterator{}
+
+		store := &mocks.Store{}
+		store.QueryReturns(it, nil)
+		store.BatchReturns(errExpected)
+
+		err := newIndexWithStore(t, store).sync()
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+	})
+
+	t.Run("metadata error", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.GetReturns(nil, errExpected)
+
+		_, err := newIndexWithStore(t, store).getMetadata()
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+
+		store.GetReturns([]byte("{"), nil)
+
+		_, err = newIndexWithStore(t, store).getMetadata()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal suffix index metadata")
+	})
+}
+
+func TestChunkIndex(t *testing.T) {
+	idx, err := New(mem.NewProvider(), &mockSuffixProvider{}, 1000)
+	require.NoError(t, err)
+
+	i, ok := idx.chunkIndex(idx.chunkKey(0))
+	require.True(t, ok)
+	require.Zero(t, i)
+
+	_, ok = idx.chunkIndex(idx.chunkKey(idx.numChunks()))
+	require.False(t, ok)
+
+	_, ok = idx.chunkIndex("chunk-1-1-0")
+	require.False(t, ok)
+
+	_, ok = idx.chunkIndex(idx.chunkKey(0) + "x")
+	require.False(t, ok)
+}
+
+func newIndexWithStore(t *testing.T, store storage.Store) *Index {
+	t.Helper()
+
+	provider := &mocks.Provider{}
+	provider.OpenStoreReturns(store, nil)
+
+	idx, err := New(provider, &mockSuffixProvider{}, 1000)
+	require.NoError(t, err)
+
+	return idx
+}
+
+func countEntries(t *testing.T, store storage.Store, query string) int {
+	t.Helper()
+
+	it, err := store.Query(query)
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, it.Close())
+	}()
+
+	n := 0
+
+	for {
+		ok, err := it.Next()
+		require.NoError(t, err)
+
+		if !ok {
+			return n
+		}
+
+		n++
+	}
+}
+
+type mockSuffixProvider struct {
+	suffixes []string
+	err      error
+}
+
+func (m *mockSuffixProvider) ForEachSuffix(fn func(suffix string) error) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	for _, suffix := range m.suffixes {
+		if err := fn(suffix); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package suffixindex
+
+import (
+	"fmt"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+)
+
+type operationStore interface {
+	Get(suffix string) ([]*operation.AnchoredOperation, error)
+	Put(ops []*operation.AnchoredOperation) error
+}
+
+type index interface {
+	Add(suffixes ...string) error
+	MayContain(suffix string) bool
+}
+
+// OperationStore wraps an operation store. The suffixes of stored operations are added to the suffix index and
+// the index is consulted before the operation store is queried, so that unknown suffixes are rejected without
+// querying the database.
+type OperationStore struct {
+	operationStore
+
+	index index
+}
+
+// NewOperationStore returns a new operation store wrapper.
+func NewOperationStore(s operationStore, idx index) *OperationStore {
+	return &OperationStore{
+		operationStore: s,
+		index:          idx,
+	}
+}
+
+// Put adds the suffixes of the given operations to the index and then stores the operations.
+func (s *OperationStore) Put(ops []*operation.AnchoredOperation) error {
+	// The suffixes are added to the index before the operations are stored so that the operations can't
+	// be stored without the suffixes being in the index.
+	if err := s.index.Add(uniqueSuffixes(ops)...); err != nil {
+		return fmt.Errorf("add suffixes to index: %w", err)
+	}
+
+	return s.operationStore.Put(ops)
+}
+
+// Get returns the operations of the given suffix. A 'not found' error is returned, without querying the
+// operation store, if the suffix is not in the index.
+func (s *OperationStore) Get(suffix string) ([]*operation.AnchoredOperation, error) {
+	if !s.index.MayContain(suffix) {
+		logger.Debugf("suffix[%s] not found in the suffix index", suffix)
+
+		return nil, fmt.Errorf("suffix[%s] not found in the store", suffix)
+	}
+
+	return s.operationStore.Get(suffix)
+}
+
+func uniqueSuffixes(ops []*operation.AnchoredOperation) []string {
+	var suffixes []string
+
+	added := make(map[string]bool)
+
+	for _, op := range ops {
+		if !added[op.UniqueSuffix] {
+			added[op.UniqueSuffix] = true
+
+			suffixes = append(suffixes, op.UniqueSuffix)
+		}
+	}
+
+	return suffixes
+}
@@ -0,0 +1,70 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package suffixindex
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+
+	orbmocks "github.com/trustbloc/orb/pkg/mocks"
+)
+
+func TestOperationStore(t *testing.T) {
+	idx, err := New(mem.NewProvider(), &mockSuffixProvider{}, 1000)
+	require.NoError(t, err)
+
+	idx.setReady()
+
+	opStore := orbmocks.NewMockOperationStore()
+
+	s := NewOperationStore(opStore, idx)
+
+	require.NoError(t, s.Put([]*operation.AnchoredOperation{
+		{Type: operation.TypeCreate, UniqueSuffix: suffix1},
+		{Type: operation.TypeUpdate, UniqueSuffix: suffix1},
+		{Type: operation.TypeCreate, UniqueSuffix: suffix2},
+	}))
+
+	ops, err := s.Get(suffix1)
+	require.NoError(t, err)
+	require.Len(t, ops, 2)
+
+	ops, err = s.Get(suffix2)
+	require.NoError(t, err)
+	require.Len(t, ops, 1)
+
+	_, err = s.Get(suffix3)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not found")
+
+	t.Run("index error", func(t *testing.T) {
+		s := NewOperationStore(opStore, &mockIndex{err: errors.New("injected index error")})
+
+		err := s.Put([]*operation.AnchoredOperation{{Type: operation.TypeCreate, UniqueSuffix: suffix3}})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected index error")
+
+		_, err = opStore.Get(suffix3)
+		require.Error(t, err)
+	})
+}
+
+type mockIndex struct {
+	err error
+}
+
+func (m *mockIndex) Add(...string) error {
+	return m.err
+}
+
+func (m *mockIndex) MayContain(string) bool {
+	return true
+}
@@ -0,0 +1,244 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package processedanchor
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/edge-core/pkg/log"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+const (
+	namespace = "processed-anchor"
+
+	// anchorTag is set on the source records of an anchor and contains the (encoded) canonical reference
+	// of the anchor.
+	anchorTag = "anchor"
+
+	sourceKeyPrefix = "source-"
+)
+
+var logger = log.New("processed-anchor-store")
+
+// Entry contains the provenance of an anchor that was processed by the observer.
+type Entry struct {
+	CanonicalReference string    `json:"id"`
+	Hashlink           string    `json:"hashlink"`
+	Processed          time.Time `json:"processed"`
+	// Sources contains the (unique) actors from which the anchor was received, sorted alphabetically.
+	Sources []string `json:"sources,omitempty"`
+}
+
+type sourceEntry struct {
+	Source   string    `json:"source"`
+	Received time.Time `json:"received"`
+}
+
+// Store keeps track of the anchors that were processed by the observer along with the sources from which
+// each anchor was received. An anchor is identified by its canonical reference (the hash of the anchor) so
+// that an anchor with a different hashlink (for example, with different CAS links) is treated as the same anchor.
+//
+// Each source is saved as a separate record (tagged with the anchor) so that sources may be added concurrently
+// without a read-modify-write.
+type Store struct {
+	store     storage.Store
+	unmarshal func(data []byte, v interface{}) error
+}
+
+// New returns a new processed anchor store.
+func New(provider storage.Provider) (*Store, error) {
+	store, err := provider.OpenStore(namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open processed anchor store: %w", err)
+	}
+
+	err = provider.SetStoreConfig(namespace, storage.StoreConfiguration{TagNames: []string{anchorTag}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set store configuration: %w", err)
+	}
+
+	return &Store{
+		store:     store,
+		unmarshal: json.Unmarshal,
+	}, nil
+}
+
+// IsProcessed returns true if the anchor with the given canonical reference was processed.
+func (s *Store) IsProcessed(canonicalRef string) (bool, error) {
+	_, err := s.store.Get(canonicalRef)
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return false, nil
+		}
+
+		return false, orberrors.NewTransientf("get processed anchor [%s]: %w", canonicalRef, err)
+	}
+
+	return true, nil
+}
+
+// PutProcessed marks the anchor with the given canonical reference as processed and records the source
+// from which the anchor was received.
+func (s *Store) PutProcessed(canonicalRef, hashlink, source string) error {
+	entryBytes, err := json.Marshal(&Entry{
+		CanonicalReference: canonicalRef,
+		Hashlink:           hashlink,
+		Processed:          time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("marshal processed anchor [%s]: %w", canonicalRef, err)
+	}
+
+	ops := []storage.Operation{
+		{
+			Key:   canonicalRef,
+			Value: entryBytes,
+		},
+	}
+
+	if source != "" {
+		op, e := newSourceOperation(canonicalRef, source)
+		if e != nil {
+			return e
+		}
+
+		ops = append(ops, op)
+	}
+
+	err = s.store.Batch(ops)
+	if err != nil {
+		return orberrors.NewTransientf("store processed anchor [%s]: %w", canonicalRef, err)
+	}
+
+	logger.Debugf("Stored processed anchor [%s] from source [%s]", canonicalRef, source)
+
+	return nil
+}
+
+// AddSource records an additional source from which the anchor with the given canonical reference was received.
+func (s *Store) AddSource(canonicalRef, source string) error {
+	if source == "" {
+		return nil
+	}
+
+	op, err := newSourceOperation(canonicalRef, source)
+	if err != nil {
+		return err
+	}
+
+	err = s.store.Put(op.Key, op.Value, op.Tags...)
+	if err != nil {
+		return orberrors.NewTransientf("store source [%s] of anchor [%s]: %w", source, canonicalRef, err)
+	}
+
+	logger.Debugf("Added source [%s] to processed anchor [%s]", source, canonicalRef)
+
+	return nil
+}
+
+// Get returns the processed anchor with the given canonical reference along with all of its sources. If the
+// anchor wasn't processed then orberrors.ErrContentNotFound is returned.
+func (s *Store) Get(canonicalRef string) (*Entry, error) {
+	entryBytes, err := s.store.Get(canonicalRef)
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return nil, orberrors.ErrContentNotFound
+		}
+
+		return nil, orberrors.NewTransientf("get processed anchor [%s]: %w", canonicalRef, err)
+	}
+
+	entry := &Entry{}
+
+	err = s.unmarshal(entryBytes, entry)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal processed anchor [%s]: %w", canonicalRef, err)
+	}
+
+	sources, err := s.getSources(canonicalRef)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, src := range sources {
+		entry.Sources = append(entry.Sources, src.Source)
+	}
+
+	return entry, nil
+}
+
+func (s *Store) getSources(canonicalRef string) ([]*sourceEntry, error) {
+	it, err := s.store.Query(fmt.Sprintf("%s:%s", anchorTag, encode(canonicalRef)))
+	if err != nil {
+		return nil, orberrors.NewTransientf("query sources of anchor [%s]: %w", canonicalRef, err)
+	}
+
+	defer storage.Close(it, logger)
+
+	var sources []*sourceEntry
+
+	for {
+		ok, e := it.Next()
+		if e != nil {
+			return nil, orberrors.NewTransientf("query next source of anchor [%s]: %w", canonicalRef, e)
+		}
+
+		if !ok {
+			break
+		}
+
+		value, e := it.Value()
+		if e != nil {
+			return nil, orberrors.NewTransientf("get source of anchor [%s]: %w", canonicalRef, e)
+		}
+
+		src := &sourceEntry{}
+
+		e = s.unmarshal(value, src)
+		if e != nil {
+			logger.Warnf("Error unmarshalling source of anchor [%s]: %s. The item will be ignored.", canonicalRef, e)
+
+			continue
+		}
+
+		sources = append(sources, src)
+	}
+
+	sort.Slice(sources, func(i, j int) bool {
+		return sources[i].Source < sources[j].Source
+	})
+
+	return sources, nil
+}
+
+func newSourceOperation(canonicalRef, source string) (storage.Operation, error) {
+	srcBytes, err := json.Marshal(&sourceEntry{
+		Source:   source,
+		Received: time.Now(),
+	})
+	if err != nil {
+		return storage.Operation{}, fmt.Errorf("marshal source [%s] of anchor [%s]: %w", source, canonicalRef, err)
+	}
+
+	return storage.Operation{
+		Key:   fmt.Sprintf("%s%s-%s", sourceKeyPrefix, canonicalRef, encode(source)),
+		Value: srcBytes,
+		Tags:  []storage.Tag{{Name: anchorTag, Value: encode(canonicalRef)}},
+	}, nil
+}
+
+func encode(value string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(value))
+}
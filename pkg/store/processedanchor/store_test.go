@@ -0,0 +1,204 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package processedanchor
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/stretchr/testify/require"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+const (
+	anchor1 = "uEiAFRFMd0Fbvu2u1E0l8Q4kAYKHRLoBpGvt0f6hGh5QFzA"
+	anchor2 = "uEiCVmZeFlbAQDqLu5DqdWvzL8kMqOuRGsGxIGyNE8h7M_w"
+	hl1     = "hl:" + anchor1 + ":uoQ-BeEJpcGZzOi8vYmFma3JlaWF"
+
+	source1 = "https://orb.domain1.com/services/orb"
+	source2 = "https://orb.domain2.com/services/orb"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		s, err := New(mem.NewProvider())
+		require.NoError(t, err)
+		require.NotNil(t, s)
+	})
+
+	t.Run("open store error", func(t *testing.T) {
+		p := &mocks.Provider{}
+		p.OpenStoreReturns(nil, errors.New("injected open error"))
+
+		s, err := New(p)
+		require.Error(t, err)
+		require.Nil(t, s)
+		require.Contains(t, err.Error(), "injected open error")
+	})
+
+	t.Run("set store config error", func(t *testing.T) {
+		p := &mocks.Provider{}
+		p.OpenStoreReturns(&mocks.Store{}, nil)
+		p.SetStoreConfigReturns(errors.New("injected config error"))
+
+		s, err := New(p)
+		require.Error(t, err)
+		require.Nil(t, s)
+		require.Contains(t, err.Error(), "injected config error")
+	})
+}
+
+func TestStore(t *testing.T) {
+	s, err := New(mem.NewProvider())
+	require.NoError(t, err)
+
+	processed, err := s.IsProcessed(anchor1)
+	require.NoError(t, err)
+	require.False(t, processed)
+
+	_, err = s.Get(anchor1)
+	require.True(t, errors.Is(err, orberrors.ErrContentNotFound))
+
+	require.NoError(t, s.PutProcessed(anchor1, hl1, source2))
+	require.NoError(t, s.PutProcessed(anchor2, "hl:"+anchor2, ""))
+
+	processed, err = s.IsProcessed(anchor1)
+	require.NoError(t, err)
+	require.True(t, processed)
+
+	require.NoError(t, s.AddSource(anchor1, source1))
+	require.NoError(t, s.AddSource(anchor1, source2)) // Duplicate sources should be ignored.
+	require.NoError(t, s.AddSource(anchor1, ""))
+
+	entry, err := s.Get(anchor1)
+	require.NoError(t, err)
+	require.Equal(t, anchor1, entry.CanonicalReference)
+	require.Equal(t, hl1, entry.Hashlink)
+	require.False(t, entry.Processed.IsZero())
+	require.Equal(t, []string{source1, source2}, entry.Sources)
+
+	entry, err = s.Get(anchor2)
+	require.NoError(t, err)
+	require.Empty(t, entry.Sources)
+}
+
+func TestStoreError(t *testing.T) {
+	errExpected := errors.New("injected storage error")
+
+	newStore := func(t *testing.T, st storage.Store) *Store {
+		t.Helper()
+
+		p := &mocks.Provider{}
+		p.OpenStoreReturns(st, nil)
+
+		s, err := New(p)
+		require.NoError(t, err)
+
+		return s
+	}
+
+	t.Run("IsProcessed error", func(t *testing.T) {
+		st := &mocks.Store{}
+		st.GetReturns(nil, errExpected)
+
+		_, err := newStore(t, st).IsProcessed(anchor1)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+	})
+
+	t.Run("PutProcessed error", func(t *testing.T) {
+		st := &mocks.Store{}
+		st.BatchReturns(errExpected)
+
+		err := newStore(t, st).PutProcessed(anchor1, hl1, source1)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+	})
+
+	t.Run("AddSource error", func(t *testing.T) {
+		st := &mocks.Store{}
+		st.PutReturns(errExpected)
+
+		err := newStore(t, st).AddSource(anchor1, source1)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+	})
+
+	t.Run("Get error", func(t *testing.T) {
+		st := &mocks.Store{}
+		st.GetReturns(nil, errExpected)
+
+		_, err := newStore(t, st).Get(anchor1)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+	})
+
+	t.Run("Get unmarshal error", func(t *testing.T) {
+		st := &mocks.Store{}
+		st.GetReturns([]byte("{"), nil)
+
+		_, err := newStore(t, st).Get(anchor1)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal processed anchor")
+	})
+
+	t.Run("Query error", func(t *testing.T) {
+		st := &mocks.Store{}
+		st.GetReturns([]byte(`{}`), nil)
+		st.QueryReturns(nil, errExpected)
+
+		_, err := newStore(t, st).Get(anchor1)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+	})
+
+	t.Run("Iterator.Next error", func(t *testing.T) {
+		it := &mocks.Iterator{}
+		it.NextReturns(false, errExpected)
+
+		st := &mocks.Store{}
+		st.GetReturns([]byte(`{}`), nil)
+		st.QueryReturns(it, nil)
+
+		_, err := newStore(t, st).Get(anchor1)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+	})
+
+	t.Run("Iterator.Value error", func(t *testing.T) {
+		it := &mocks.Iterator{}
+		it.NextReturns(true, nil)
+		it.ValueReturns(nil, errExpected)
+
+		st := &mocks.Store{}
+		st.GetReturns([]byte(`{}`), nil)
+		st.QueryReturns(it, nil)
+
+		_, err := newStore(t, st).Get(anchor1)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+	})
+
+	t.Run("Source unmarshal error -> ignore", func(t *testing.T) {
+		it := &mocks.Iterator{}
+		it.NextReturnsOnCall(0, true, nil)
+		it.NextReturnsOnCall(1, false, nil)
+		it.ValueReturns([]byte("{"), nil)
+
+		st := &mocks.Store{}
+		st.GetReturns([]byte(`{}`), nil)
+		st.QueryReturns(it, nil)
+
+		entry, err := newStore(t, st).Get(anchor1)
+		require.NoError(t, err)
+		require.Empty(t, entry.Sources)
+	})
+}
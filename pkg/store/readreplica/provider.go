@@ -0,0 +1,136 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package readreplica
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/edge-core/pkg/log"
+)
+
+var logger = log.New("read-replica-store")
+
+const defaultMaxStaleness = 30 * time.Second
+
+// Provider is a storage provider that routes queries to a read endpoint (for example, MongoDB secondaries or a
+// replicated CouchDB cluster) in order to increase read throughput. All writes and reads by key go to the
+// primary (write) endpoint. Since the replicas may lag behind the primary, queries on a store that was written
+// to (by this instance) within the maximum staleness are served by the primary so that recent writes are
+// always visible to subsequent queries.
+type Provider struct {
+	primary      storage.Provider
+	replica      storage.Provider
+	maxStaleness time.Duration
+	timeNow      func() time.Time
+
+	mutex  sync.RWMutex
+	stores map[string]*Store
+}
+
+// Opt sets a read replica provider option.
+type Opt func(p *Provider)
+
+// WithMaxStaleness sets the maximum time that a replica may lag behind the primary. Queries on a store
+// that was written to within this time are served by the primary. If zero then all queries go to the replica.
+func WithMaxStaleness(value time.Duration) Opt {
+	return func(p *Provider) {
+		p.maxStaleness = value
+	}
+}
+
+// NewProvider returns a new read replica provider. The primary provider is used for writes and reads by key
+// and the replica provider is used for queries.
+func NewProvider(primary, replica storage.Provider, opts ...Opt) *Provider {
+	p := &Provider{
+		primary:      primary,
+		replica:      replica,
+		maxStaleness: defaultMaxStaleness,
+		timeNow:      time.Now,
+		stores:       make(map[string]*Store),
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// OpenStore opens the store with the given name on both the primary and the read endpoint.
+func (p *Provider) OpenStore(name string) (storage.Store, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if s, ok := p.stores[name]; ok {
+		return s, nil
+	}
+
+	primary, err := p.primary.OpenStore(name)
+	if err != nil {
+		return nil, fmt.Errorf("open store [%s]: %w", name, err)
+	}
+
+	replica, err := p.replica.OpenStore(name)
+	if err != nil {
+		return nil, fmt.Errorf("open read replica store [%s]: %w", name, err)
+	}
+
+	s := &Store{
+		name:         name,
+		primary:      primary,
+		replica:      replica,
+		maxStaleness: p.maxStaleness,
+		timeNow:      p.timeNow,
+	}
+
+	p.stores[name] = s
+
+	return s, nil
+}
+
+// SetStoreConfig sets the configuration of the store on the primary. The configuration (indexes) is
+// replicated to the read endpoint by the database.
+func (p *Provider) SetStoreConfig(name string, config storage.StoreConfiguration) error {
+	return p.primary.SetStoreConfig(name, config)
+}
+
+// GetStoreConfig returns the configuration of the store from the primary.
+func (p *Provider) GetStoreConfig(name string) (storage.StoreConfiguration, error) {
+	return p.primary.GetStoreConfig(name)
+}
+
+// GetOpenStores returns the open stores.
+func (p *Provider) GetOpenStores() []storage.Store {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	stores := make([]storage.Store, 0, len(p.stores))
+
+	for _, s := range p.stores {
+		stores = append(stores, s)
+	}
+
+	return stores
+}
+
+// Close closes both providers.
+func (p *Provider) Close() error {
+	p.mutex.Lock()
+	p.stores = make(map[string]*Store)
+	p.mutex.Unlock()
+
+	replicaErr := p.replica.Close()
+
+	if err := p.primary.Close(); err != nil {
+		return err
+	}
+
+	return replicaErr
+}
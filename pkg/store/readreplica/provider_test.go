@@ -0,0 +1,82 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package readreplica
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+func TestProvider(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		primary := mem.NewProvider()
+		replica := mem.NewProvider()
+
+		p := NewProvider(primary, replica, WithMaxStaleness(time.Minute))
+		require.Equal(t, time.Minute, p.maxStaleness)
+
+		s1, err := p.OpenStore("store1")
+		require.NoError(t, err)
+
+		s2, err := p.OpenStore("store1")
+		require.NoError(t, err)
+		require.True(t, s1 == s2)
+
+		_, err = p.OpenStore("store2")
+		require.NoError(t, err)
+
+		require.Len(t, p.GetOpenStores(), 2)
+
+		require.NoError(t, p.SetStoreConfig("store1", storage.StoreConfiguration{TagNames: []string{"tag1"}}))
+
+		config, err := primary.GetStoreConfig("store1")
+		require.NoError(t, err)
+		require.Equal(t, []string{"tag1"}, config.TagNames)
+
+		config, err = p.GetStoreConfig("store1")
+		require.NoError(t, err)
+		require.Equal(t, []string{"tag1"}, config.TagNames)
+
+		require.NoError(t, p.Close())
+		require.Empty(t, p.GetOpenStores())
+	})
+
+	t.Run("Default max staleness", func(t *testing.T) {
+		require.Equal(t, defaultMaxStaleness, NewProvider(mem.NewProvider(), mem.NewProvider()).maxStaleness)
+	})
+
+	t.Run("Open store error", func(t *testing.T) {
+		errExpected := errors.New("injected open error")
+
+		failing := &mocks.Provider{}
+		failing.OpenStoreReturns(nil, errExpected)
+
+		_, err := NewProvider(mem.NewProvider(), failing).OpenStore("store1")
+		require.True(t, errors.Is(err, errExpected))
+		require.Contains(t, err.Error(), "open read replica store")
+
+		_, err = NewProvider(failing, mem.NewProvider()).OpenStore("store1")
+		require.True(t, errors.Is(err, errExpected))
+	})
+
+	t.Run("Close error", func(t *testing.T) {
+		errExpected := errors.New("injected close error")
+
+		failing := &mocks.Provider{}
+		failing.CloseReturns(errExpected)
+
+		require.True(t, errors.Is(NewProvider(failing, mem.NewProvider()).Close(), errExpected))
+		require.True(t, errors.Is(NewProvider(mem.NewProvider(), failing).Close(), errExpected))
+	})
+}
@@ -0,0 +1,110 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package readreplica
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+// Store sends writes and reads by key to the primary and queries to the read replica. Queries are served by
+// the primary if the store was written to within the maximum staleness or if the replica returns an error.
+type Store struct {
+	name         string
+	primary      storage.Store
+	replica      storage.Store
+	maxStaleness time.Duration
+	timeNow      func() time.Time
+
+	// lastWrite is the time (in Unix nanoseconds) of the most recent write to the store.
+	lastWrite int64
+}
+
+// Put stores the data in the primary.
+func (s *Store) Put(key string, value []byte, tags ...storage.Tag) error {
+	s.written()
+
+	return s.primary.Put(key, value, tags...)
+}
+
+// Get returns the data from the primary.
+func (s *Store) Get(key string) ([]byte, error) {
+	return s.primary.Get(key)
+}
+
+// GetTags returns the tags from the primary.
+func (s *Store) GetTags(key string) ([]storage.Tag, error) {
+	return s.primary.GetTags(key)
+}
+
+// GetBulk returns the values for the given keys from the primary.
+func (s *Store) GetBulk(keys ...string) ([][]byte, error) {
+	return s.primary.GetBulk(keys...)
+}
+
+// Query queries the read replica or, if the store was written to recently (i.e. the replica may not contain
+// the latest data), the primary.
+func (s *Store) Query(expression string, options ...storage.QueryOption) (storage.Iterator, error) {
+	if s.isStale() {
+		return s.primary.Query(expression, options...)
+	}
+
+	it, err := s.replica.Query(expression, options...)
+	if err != nil {
+		logger.Warnf("Error querying read replica of store [%s] - falling back to the primary: %s", s.name, err)
+
+		return s.primary.Query(expression, options...)
+	}
+
+	return it, nil
+}
+
+// Delete deletes the data from the primary.
+func (s *Store) Delete(key string) error {
+	s.written()
+
+	return s.primary.Delete(key)
+}
+
+// Batch performs the operations on the primary.
+func (s *Store) Batch(operations []storage.Operation) error {
+	s.written()
+
+	return s.primary.Batch(operations)
+}
+
+// Flush flushes the primary.
+func (s *Store) Flush() error {
+	return s.primary.Flush()
+}
+
+// Close closes both stores.
+func (s *Store) Close() error {
+	replicaErr := s.replica.Close()
+
+	if err := s.primary.Close(); err != nil {
+		return err
+	}
+
+	return replicaErr
+}
+
+func (s *Store) written() {
+	atomic.StoreInt64(&s.lastWrite, s.timeNow().UnixNano())
+}
+
+// isStale returns true if the replica may not yet contain the most recent write.
+func (s *Store) isStale() bool {
+	lastWrite := atomic.LoadInt64(&s.lastWrite)
+	if lastWrite == 0 || s.maxStaleness == 0 {
+		return false
+	}
+
+	return s.timeNow().Sub(time.Unix(0, lastWrite)) < s.maxStaleness
+}
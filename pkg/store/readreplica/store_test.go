@@ -0,0 +1,125 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package readreplica
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+func TestStore(t *testing.T) {
+	primaryProvider := mem.NewProvider()
+	replicaProvider := mem.NewProvider()
+
+	now := time.Now()
+
+	p := NewProvider(primaryProvider, replicaProvider, WithMaxStaleness(time.Minute))
+	p.timeNow = func() time.Time { return now }
+
+	s, err := p.OpenStore("store1")
+	require.NoError(t, err)
+
+	primary, err := primaryProvider.OpenStore("store1")
+	require.NoError(t, err)
+
+	replica, err := replicaProvider.OpenStore("store1")
+	require.NoError(t, err)
+
+	// Simulate data that was replicated to the read endpoint.
+	require.NoError(t, replica.Put("replicated1", []byte("value"), storage.Tag{Name: "tag1", Value: "v1"}))
+
+	t.Run("Query uses the replica", func(t *testing.T) {
+		require.Equal(t, "replicated1", firstKey(t, s, "tag1"))
+	})
+
+	t.Run("Writes and reads by key use the primary", func(t *testing.T) {
+		require.NoError(t, s.Put("key1", []byte("value1"), storage.Tag{Name: "tag1", Value: "v1"}))
+
+		value, e := primary.Get("key1")
+		require.NoError(t, e)
+		require.Equal(t, "value1", string(value))
+
+		_, e = replica.Get("key1")
+		require.True(t, errors.Is(e, storage.ErrDataNotFound))
+
+		value, e = s.Get("key1")
+		require.NoError(t, e)
+		require.Equal(t, "value1", string(value))
+
+		tags, e := s.GetTags("key1")
+		require.NoError(t, e)
+		require.Equal(t, []storage.Tag{{Name: "tag1", Value: "v1"}}, tags)
+
+		values, e := s.GetBulk("key1", "replicated1")
+		require.NoError(t, e)
+		require.Equal(t, [][]byte{[]byte("value1"), nil}, values)
+
+		require.NoError(t, s.Batch([]storage.Operation{{Key: "key2", Value: []byte("value2")}}))
+		require.NoError(t, s.Delete("key2"))
+		require.NoError(t, s.Flush())
+	})
+
+	t.Run("Query uses the primary after a recent write", func(t *testing.T) {
+		require.Equal(t, "key1", firstKey(t, s, "tag1"))
+
+		now = now.Add(time.Minute)
+
+		require.Equal(t, "replicated1", firstKey(t, s, "tag1"))
+	})
+
+	t.Run("No max staleness", func(t *testing.T) {
+		ps := &Store{name: "store1", primary: primary, replica: replica, timeNow: time.Now}
+
+		require.NoError(t, ps.Put("key3", []byte("value3")))
+		require.Equal(t, "replicated1", firstKey(t, ps, "tag1"))
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		errExpected := errors.New("injected error")
+
+		failing := &mocks.Store{}
+		failing.QueryReturns(nil, errExpected)
+		failing.CloseReturns(errExpected)
+
+		// The query falls back to the primary.
+		rs := &Store{name: "store1", primary: primary, replica: failing, timeNow: time.Now}
+		require.Equal(t, "key1", firstKey(t, rs, "tag1"))
+
+		_, e := (&Store{name: "store1", primary: failing, replica: failing, timeNow: time.Now}).Query("tag1")
+		require.True(t, errors.Is(e, errExpected))
+
+		require.True(t, errors.Is(rs.Close(), errExpected))
+		require.True(t, errors.Is((&Store{primary: failing, replica: &mocks.Store{}}).Close(), errExpected))
+	})
+}
+
+func firstKey(t *testing.T, s storage.Store, expression string) string {
+	t.Helper()
+
+	it, err := s.Query(expression)
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, it.Close())
+	}()
+
+	more, err := it.Next()
+	require.NoError(t, err)
+	require.True(t, more)
+
+	key, err := it.Key()
+	require.NoError(t, err)
+
+	return key
+}
@@ -0,0 +1,140 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package snapshot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/edge-core/pkg/log"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+const nameSpace = "did-snapshot"
+
+var logger = log.New("did-snapshot-store")
+
+// ErrNotFound is returned when a snapshot does not exist for a DID.
+var ErrNotFound = errors.New("snapshot not found")
+
+// Snapshot contains the composed state of a DID after applying all of the published operations up to
+// (and including) a checkpoint, along with the information that is required in order to continue applying
+// operations from this state in the same way as the Sidetree operation processor.
+type Snapshot struct {
+	// Suffix is the unique suffix of the DID.
+	Suffix string `json:"suffix"`
+
+	// Model is the resolution model at the checkpoint. The published and unpublished operations
+	// of the model are not stored.
+	Model *protocol.ResolutionModel `json:"model"`
+
+	// TransactionTime and TransactionNumber identify the checkpoint, i.e. the last operation that was
+	// included in the snapshot.
+	TransactionTime   uint64 `json:"transactionTime"`
+	TransactionNumber uint64 `json:"transactionNumber"`
+
+	// CanonicalReference is the hash link of the anchor of the last operation that was included in the snapshot.
+	CanonicalReference string `json:"canonicalReference"`
+
+	// OperationCount is the number of operations that were included in the snapshot.
+	OperationCount int `json:"operationCount"`
+
+	// OperationsHash is a hash of all of the operations (including their anchor hash links) that were included
+	// in the snapshot. The snapshot is only used if the same operations are found in the operation store.
+	OperationsHash string `json:"operationsHash"`
+
+	// FullTransactionTime and FullTransactionNumber identify the last create, recover or deactivate operation
+	// that was applied. Only update operations after this operation are applied.
+	FullTransactionTime   uint64 `json:"fullTransactionTime"`
+	FullTransactionNumber uint64 `json:"fullTransactionNumber"`
+
+	// RecoveryCommitments contains the recovery commitments that were consumed by the applied recover
+	// and deactivate operations.
+	RecoveryCommitments []string `json:"recoveryCommitments,omitempty"`
+
+	// UpdateCommitments contains the update commitments that were consumed by the update operations
+	// that were applied after the last create, recover or deactivate operation.
+	UpdateCommitments []string `json:"updateCommitments,omitempty"`
+
+	// PendingOperations contains the IDs of the operations that were included in the snapshot but were not
+	// applied (for example, an update that is not yet part of the commitment chain). These operations may
+	// still be applied once subsequent operations are applied.
+	PendingOperations []string `json:"pendingOperations,omitempty"`
+}
+
+// New returns a new DID snapshot store.
+func New(provider storage.Provider) (*Store, error) {
+	store, err := provider.OpenStore(nameSpace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open DID snapshot store: %w", err)
+	}
+
+	return &Store{
+		store: store,
+	}, nil
+}
+
+// Store persists the latest snapshot of each DID.
+type Store struct {
+	store storage.Store
+}
+
+// Put stores the given snapshot, replacing any existing snapshot for the DID.
+func (s *Store) Put(snapshot *Snapshot) error {
+	if snapshot.Suffix == "" {
+		return errors.New("suffix is required")
+	}
+
+	snapshotBytes, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("marshal snapshot: %w", err)
+	}
+
+	err = s.store.Put(snapshot.Suffix, snapshotBytes)
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("store snapshot for suffix [%s]: %w", snapshot.Suffix, err))
+	}
+
+	logger.Debugf("Stored snapshot of suffix [%s] at checkpoint [%s] with %d operations",
+		snapshot.Suffix, snapshot.CanonicalReference, snapshot.OperationCount)
+
+	return nil
+}
+
+// Get returns the snapshot of the given DID suffix. ErrNotFound is returned if no snapshot exists.
+func (s *Store) Get(suffix string) (*Snapshot, error) {
+	snapshotBytes, err := s.store.Get(suffix)
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return nil, ErrNotFound
+		}
+
+		return nil, orberrors.NewTransient(fmt.Errorf("get snapshot for suffix [%s]: %w", suffix, err))
+	}
+
+	snapshot := &Snapshot{}
+
+	err = json.Unmarshal(snapshotBytes, snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal snapshot for suffix [%s]: %w", suffix, err)
+	}
+
+	return snapshot, nil
+}
+
+// Delete deletes the snapshot of the given DID suffix.
+func (s *Store) Delete(suffix string) error {
+	if err := s.store.Delete(suffix); err != nil {
+		return orberrors.NewTransient(fmt.Errorf("delete snapshot for suffix [%s]: %w", suffix, err))
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package snapshot
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/protocol"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+const suffix = "EiA329wd6Aj36YRmp7NGkeB5ADnVt8ARdMZMPzfXsjwTJA"
+
+func TestNew(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		s, err := New(mem.NewProvider())
+		require.NoError(t, err)
+		require.NotNil(t, s)
+	})
+
+	t.Run("open store error", func(t *testing.T) {
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(nil, errors.New("injected open error"))
+
+		s, err := New(provider)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected open error")
+		require.Nil(t, s)
+	})
+}
+
+func TestStore(t *testing.T) {
+	s, err := New(mem.NewProvider())
+	require.NoError(t, err)
+
+	_, err = s.Get(suffix)
+	require.True(t, errors.Is(err, ErrNotFound))
+
+	snapshot := &Snapshot{
+		Suffix:              suffix,
+		Model:               &protocol.ResolutionModel{UpdateCommitment: "uc1", RecoveryCommitment: "rc1"},
+		TransactionTime:     1000,
+		TransactionNumber:   2,
+		CanonicalReference:  "hl:uEiA329wd6Aj36YRmp7NGkeB5ADnVt8ARdMZMPzfXsjwTJA",
+		OperationCount:      3,
+		OperationsHash:      "hash",
+		UpdateCommitments:   []string{"uc0"},
+		RecoveryCommitments: []string{"rc0"},
+		PendingOperations:   []string{"op1"},
+	}
+
+	require.NoError(t, s.Put(snapshot))
+
+	s2, err := s.Get(suffix)
+	require.NoError(t, err)
+	require.Equal(t, snapshot, s2)
+
+	require.NoError(t, s.Delete(suffix))
+
+	_, err = s.Get(suffix)
+	require.True(t, errors.Is(err, ErrNotFound))
+
+	t.Run("no suffix", func(t *testing.T) {
+		err := s.Put(&Snapshot{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "suffix is required")
+	})
+}
+
+func TestStore_Errors(t *testing.T) {
+	errExpected := errors.New("injected storage error")
+
+	store := &mocks.Store{}
+	store.PutReturns(errExpected)
+	store.GetReturns(nil, errExpected)
+	store.DeleteReturns(errExpected)
+
+	provider := &mocks.Provider{}
+	provider.OpenStoreReturns(store, nil)
+
+	s, err := New(provider)
+	require.NoError(t, err)
+
+	t.Run("put error", func(t *testing.T) {
+		err := s.Put(&Snapshot{Suffix: suffix})
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), errExpected.Error())
+	})
+
+	t.Run("get error", func(t *testing.T) {
+		_, err := s.Get(suffix)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), errExpected.Error())
+	})
+
+	t.Run("unmarshal error", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.GetReturns([]byte("{"), nil)
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := New(provider)
+		require.NoError(t, err)
+
+		_, err = s.Get(suffix)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal snapshot")
+	})
+
+	t.Run("delete error", func(t *testing.T) {
+		err := s.Delete(suffix)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+	})
+}
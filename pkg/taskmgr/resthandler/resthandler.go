@@ -0,0 +1,210 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/trustbloc/edge-core/pkg/log"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+
+	"github.com/trustbloc/orb/pkg/taskmgr"
+)
+
+// Path is the path of the task management endpoint.
+const Path = "/tasks"
+
+const (
+	actionTrigger = "trigger"
+	actionPause   = "pause"
+	actionResume  = "resume"
+)
+
+const (
+	badRequestResponse          = "Bad Request."
+	notFoundResponse            = "Not Found."
+	conflictResponse            = "Conflict."
+	internalServerErrorResponse = "Internal Server Error."
+)
+
+var logger = log.New("task-rest-handler")
+
+var errInvalidAction = errors.New("invalid action")
+
+type taskManager interface {
+	Tasks() ([]*taskmgr.TaskStatus, error)
+	TriggerTask(id string) error
+	PauseTask(id string) error
+	ResumeTask(id string) error
+}
+
+// Reader implements a REST handler that returns the status of the registered background tasks.
+type Reader struct {
+	mgr     taskManager
+	marshal func(v interface{}) ([]byte, error)
+}
+
+// NewReader returns a new task status reader.
+func NewReader(mgr taskManager) *Reader {
+	return &Reader{
+		mgr:     mgr,
+		marshal: json.Marshal,
+	}
+}
+
+// Path returns the HTTP REST endpoint for the task service.
+func (h *Reader) Path() string {
+	return Path
+}
+
+// Method returns the HTTP method, which is always GET.
+func (h *Reader) Method() string {
+	return http.MethodGet
+}
+
+// Handler returns the HTTP REST handle for the task service.
+func (h *Reader) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Reader) handle(w http.ResponseWriter, _ *http.Request) {
+	tasks, err := h.mgr.Tasks()
+	if err != nil {
+		logger.Errorf("[%s] Error retrieving task status: %s", Path, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	tasksBytes, err := h.marshal(tasks)
+	if err != nil {
+		logger.Errorf("[%s] Error marshalling task status: %s", Path, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	writeResponse(w, http.StatusOK, tasksBytes)
+}
+
+// Writer implements a REST handler that triggers, pauses or resumes a background task. The request is
+// a JSON object containing the task ID and the action, for example {"id":"anchor-status-monitor","action":"pause"}.
+// Valid actions are "trigger", "pause" and "resume".
+type Writer struct {
+	mgr     taskManager
+	readAll func(r io.Reader) ([]byte, error)
+}
+
+// NewWriter returns a new task writer.
+func NewWriter(mgr taskManager) *Writer {
+	return &Writer{
+		mgr:     mgr,
+		readAll: ioutil.ReadAll,
+	}
+}
+
+// Path returns the HTTP REST endpoint for the task service.
+func (h *Writer) Path() string {
+	return Path
+}
+
+// Method returns the HTTP method, which is always POST.
+func (h *Writer) Method() string {
+	return http.MethodPost
+}
+
+// Handler returns the HTTP REST handle for the task service.
+func (h *Writer) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+type taskRequest struct {
+	ID     string `json:"id"`
+	Action string `json:"action"`
+}
+
+func (h *Writer) handle(w http.ResponseWriter, req *http.Request) {
+	reqBytes, err := h.readAll(req.Body)
+	if err != nil {
+		logger.Errorf("[%s] Error reading request body: %s", Path, err)
+
+		writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	r := &taskRequest{}
+
+	if err := json.Unmarshal(reqBytes, r); err != nil || r.ID == "" {
+		logger.Infof("[%s] Invalid task request: %s", Path, reqBytes)
+
+		writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	if err := h.apply(r); err != nil {
+		switch {
+		case errors.Is(err, taskmgr.ErrTaskNotFound):
+			logger.Infof("[%s] Error applying task request: %s", Path, err)
+
+			writeResponse(w, http.StatusNotFound, []byte(notFoundResponse))
+		case errors.Is(err, taskmgr.ErrTaskRunning):
+			logger.Infof("[%s] Error applying task request: %s", Path, err)
+
+			writeResponse(w, http.StatusConflict, []byte(conflictResponse))
+		case errors.Is(err, errInvalidAction):
+			logger.Infof("[%s] Error applying task request: %s", Path, err)
+
+			writeResponse(w, http.StatusBadRequest, []byte(err.Error()))
+		default:
+			logger.Errorf("[%s] Error applying task request: %s", Path, err)
+
+			writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+		}
+
+		return
+	}
+
+	logger.Infof("[%s] Applied action [%s] to task [%s]", Path, r.Action, r.ID)
+
+	writeResponse(w, http.StatusOK, nil)
+}
+
+func (h *Writer) apply(r *taskRequest) error {
+	switch r.Action {
+	case actionTrigger:
+		return h.mgr.TriggerTask(r.ID)
+	case actionPause:
+		return h.mgr.PauseTask(r.ID)
+	case actionResume:
+		return h.mgr.ResumeTask(r.ID)
+	default:
+		return fmt.Errorf("%w [%s]", errInvalidAction, r.Action)
+	}
+}
+
+func writeResponse(w http.ResponseWriter, status int, body []byte) {
+	w.WriteHeader(status)
+
+	if len(body) > 0 {
+		if _, err := w.Write(body); err != nil {
+			logger.Warnf("[%s] Unable to write response: %s", Path, err)
+
+			return
+		}
+
+		logger.Debugf("[%s] Wrote response: %s", Path, body)
+	}
+}
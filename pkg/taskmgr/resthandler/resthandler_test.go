@@ -0,0 +1,138 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/internal/testutil/httptestutil"
+	"github.com/trustbloc/orb/pkg/taskmgr"
+)
+
+func TestReader(t *testing.T) {
+	mgr := &mockTaskManager{
+		tasks: []*taskmgr.TaskStatus{{ID: "task1", Status: "idle"}},
+	}
+
+	h := NewReader(mgr)
+	require.Equal(t, Path, h.Path())
+	require.Equal(t, http.MethodGet, h.Method())
+	require.NotNil(t, h.Handler())
+
+	t.Run("success", func(t *testing.T) {
+		code, respBytes := httptestutil.Get(t, h.Handler(), Path)
+		require.Equal(t, http.StatusOK, code)
+
+		var tasks []*taskmgr.TaskStatus
+		require.NoError(t, json.Unmarshal(respBytes, &tasks))
+		require.Len(t, tasks, 1)
+		require.Equal(t, "task1", tasks[0].ID)
+	})
+
+	t.Run("task manager error", func(t *testing.T) {
+		code, _ := httptestutil.Get(t, NewReader(&mockTaskManager{err: errors.New("injected error")}).Handler(), Path)
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+
+	t.Run("marshal error", func(t *testing.T) {
+		h := NewReader(mgr)
+		h.marshal = func(v interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		code, _ := httptestutil.Get(t, h.Handler(), Path)
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+}
+
+func TestWriter(t *testing.T) {
+	h := NewWriter(&mockTaskManager{})
+	require.Equal(t, Path, h.Path())
+	require.Equal(t, http.MethodPost, h.Method())
+	require.NotNil(t, h.Handler())
+
+	t.Run("success", func(t *testing.T) {
+		mgr := &mockTaskManager{}
+
+		h := NewWriter(mgr)
+
+		for _, action := range []string{actionTrigger, actionPause, actionResume} {
+			code, _ := httptestutil.Post(t, h.Handler(), Path,
+				[]byte(fmt.Sprintf(`{"id":"task1","action":"%s"}`, action)))
+			require.Equal(t, http.StatusOK, code)
+		}
+
+		require.Equal(t, []string{"trigger:task1", "pause:task1", "resume:task1"}, mgr.calls)
+	})
+
+	t.Run("bad request", func(t *testing.T) {
+		for _, body := range []string{`{`, `{"action":"pause"}`, `{"id":"task1","action":"stop"}`} {
+			code, _ := httptestutil.Post(t, h.Handler(), Path, []byte(body))
+			require.Equal(t, http.StatusBadRequest, code)
+		}
+	})
+
+	t.Run("read error", func(t *testing.T) {
+		h := NewWriter(&mockTaskManager{})
+		h.readAll = func(r io.Reader) ([]byte, error) { return nil, errors.New("injected read error") }
+
+		code, _ := httptestutil.Post(t, h.Handler(), Path, nil)
+		require.Equal(t, http.StatusBadRequest, code)
+	})
+
+	t.Run("task manager error", func(t *testing.T) {
+		for _, test := range []struct {
+			err    error
+			status int
+		}{
+			{err: fmt.Errorf("task [task1]: %w", taskmgr.ErrTaskNotFound), status: http.StatusNotFound},
+			{err: fmt.Errorf("task [task1]: %w", taskmgr.ErrTaskRunning), status: http.StatusConflict},
+			{err: errors.New("injected error"), status: http.StatusInternalServerError},
+		} {
+			code, _ := httptestutil.Post(t, NewWriter(&mockTaskManager{err: test.err}).Handler(), Path,
+				[]byte(`{"id":"task1","action":"trigger"}`))
+			require.Equal(t, test.status, code)
+		}
+	})
+}
+
+type mockTaskManager struct {
+	tasks []*taskmgr.TaskStatus
+	calls []string
+	err   error
+}
+
+func (m *mockTaskManager) Tasks() ([]*taskmgr.TaskStatus, error) {
+	return m.tasks, m.err
+}
+
+func (m *mockTaskManager) TriggerTask(id string) error {
+	return m.call("trigger", id)
+}
+
+func (m *mockTaskManager) PauseTask(id string) error {
+	return m.call("pause", id)
+}
+
+func (m *mockTaskManager) ResumeTask(id string) error {
+	return m.call("resume", id)
+}
+
+func (m *mockTaskManager) call(action, id string) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	m.calls = append(m.calls, action+":"+id)
+
+	return nil
+}
@@ -0,0 +1,270 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package taskmgr
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	everyPrefix = "@every "
+	cronFields  = 5
+
+	// maxYears is the number of years to search for the next time that matches a cron expression. An expression
+	// such as "0 0 30 2 *" never matches.
+	maxYears = 5
+
+	// sunday is the alternative value for Sunday in the day of week field (Sunday is also 0).
+	sunday = 7
+)
+
+var errInvalidSchedule = errors.New("invalid schedule")
+
+// Schedule determines when a task is run.
+type Schedule interface {
+	// Next returns the next time after the given time that the task should be run, or the zero time
+	// if the task should never be run.
+	Next(t time.Time) time.Time
+	// String returns the expression of the schedule.
+	String() string
+}
+
+var descriptors = map[string]string{ //nolint:gochecknoglobals
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ParseSchedule parses the given schedule expression. The expression may be a standard cron expression with five
+// fields (minute, hour, day of month, month and day of week) where each field contains "*", a value, a range
+// ("1-5"), a step ("*/15" or "0-30/10") or a comma-separated list of these, for example "30 2 * * 1-5" runs the
+// task at 2:30 (UTC) on weekdays. The descriptors @yearly, @annually, @monthly, @weekly, @daily, @midnight and
+// @hourly are also supported, as well as "@every <duration>" (for example "@every 90s").
+func ParseSchedule(expr string) (Schedule, error) {
+	expr = strings.TrimSpace(expr)
+
+	if strings.HasPrefix(expr, everyPrefix) {
+		interval, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(expr, everyPrefix)))
+		if err != nil || interval <= 0 {
+			return nil, fmt.Errorf("%w [%s]: a positive duration is expected", errInvalidSchedule, expr)
+		}
+
+		return &intervalSchedule{interval: interval}, nil
+	}
+
+	cronExpr := expr
+
+	if strings.HasPrefix(expr, "@") {
+		e, ok := descriptors[expr]
+		if !ok {
+			return nil, fmt.Errorf("%w [%s]: unsupported descriptor", errInvalidSchedule, expr)
+		}
+
+		cronExpr = e
+	}
+
+	s, err := parseCron(cronExpr)
+	if err != nil {
+		return nil, fmt.Errorf("%w [%s]: %s", errInvalidSchedule, expr, err)
+	}
+
+	s.expr = expr
+
+	return s, nil
+}
+
+type intervalSchedule struct {
+	interval time.Duration
+}
+
+func (s *intervalSchedule) Next(t time.Time) time.Time {
+	return t.Add(s.interval)
+}
+
+func (s *intervalSchedule) String() string {
+	return everyPrefix + s.interval.String()
+}
+
+type cronSchedule struct {
+	expr    string
+	minutes uint64
+	hours   uint64
+	days    uint64
+	months  uint64
+	weekday uint64
+
+	// anyDay and anyWeekday are true if the day of month or the day of week fields start with "*". If both fields are
+	// restricted then a day matches if either field matches (as in standard cron).
+	anyDay     bool
+	anyWeekday bool
+}
+
+func parseCron(expr string) (*cronSchedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != cronFields {
+		return nil, fmt.Errorf("expecting %d fields but got %d", cronFields, len(fields))
+	}
+
+	s := &cronSchedule{
+		anyDay:     strings.HasPrefix(fields[2], "*"),
+		anyWeekday: strings.HasPrefix(fields[4], "*"),
+	}
+
+	for _, f := range []struct {
+		value    string
+		min, max int
+		bits     *uint64
+	}{
+		{value: fields[0], min: 0, max: 59, bits: &s.minutes},
+		{value: fields[1], min: 0, max: 23, bits: &s.hours},
+		{value: fields[2], min: 1, max: 31, bits: &s.days},
+		{value: fields[3], min: 1, max: 12, bits: &s.months},
+		{value: fields[4], min: 0, max: sunday, bits: &s.weekday},
+	} {
+		bits, err := parseField(f.value, f.min, f.max)
+		if err != nil {
+			return nil, err
+		}
+
+		*f.bits = bits
+	}
+
+	if has(s.weekday, sunday) {
+		s.weekday |= 1
+	}
+
+	return s, nil
+}
+
+// Next returns the next time (in UTC and truncated to the minute) after the given time that matches the cron
+// expression.
+func (s *cronSchedule) Next(t time.Time) time.Time {
+	t = t.UTC().Truncate(time.Minute).Add(time.Minute)
+
+	maxYear := t.Year() + maxYears
+
+	for t.Year() <= maxYear {
+		switch {
+		case !has(s.months, int(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		case !s.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+		case !has(s.hours, t.Hour()):
+			t = t.Truncate(time.Hour).Add(time.Hour)
+		case !has(s.minutes, t.Minute()):
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+func (s *cronSchedule) String() string {
+	return s.expr
+}
+
+func (s *cronSchedule) matchesDay(t time.Time) bool {
+	dayMatches := has(s.days, t.Day())
+	weekdayMatches := has(s.weekday, int(t.Weekday()))
+
+	if s.anyDay || s.anyWeekday {
+		return dayMatches && weekdayMatches
+	}
+
+	return dayMatches || weekdayMatches
+}
+
+// parseField parses a comma-separated list of values, ranges and steps into a bit set.
+func parseField(field string, min, max int) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(field, ",") {
+		b, err := parseRange(part, min, max)
+		if err != nil {
+			return 0, err
+		}
+
+		bits |= b
+	}
+
+	return bits, nil
+}
+
+func parseRange(expr string, min, max int) (uint64, error) {
+	rangeExpr, step := expr, 1
+
+	i := strings.Index(expr, "/")
+	if i >= 0 {
+		s, err := strconv.Atoi(expr[i+1:])
+		if err != nil || s <= 0 {
+			return 0, fmt.Errorf("invalid step in [%s]", expr)
+		}
+
+		rangeExpr, step = expr[:i], s
+	}
+
+	start, end, err := parseBounds(rangeExpr, min, max)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value in [%s]", expr)
+	}
+
+	// A single value with a step (e.g. "5/15") applies the step from the value until the end of the range.
+	if i >= 0 && !strings.Contains(rangeExpr, "-") {
+		end = max
+	}
+
+	if start < min || end > max || start > end {
+		return 0, fmt.Errorf("[%s] is out of range [%d-%d]", expr, min, max)
+	}
+
+	var bits uint64
+
+	for v := start; v <= end; v += step {
+		bits |= 1 << uint(v)
+	}
+
+	return bits, nil
+}
+
+func parseBounds(expr string, min, max int) (int, int, error) {
+	if expr == "*" {
+		return min, max, nil
+	}
+
+	i := strings.Index(expr, "-")
+	if i < 0 {
+		v, err := strconv.Atoi(expr)
+
+		return v, v, err
+	}
+
+	start, err := strconv.Atoi(expr[:i])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	end, err := strconv.Atoi(expr[i+1:])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return start, end, nil
+}
+
+func has(bits uint64, v int) bool {
+	return bits&(1<<uint(v)) != 0
+}
@@ -0,0 +1,93 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package taskmgr
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSchedule(t *testing.T) {
+	t.Run("Cron expression", func(t *testing.T) {
+		for _, tc := range []struct {
+			expr     string
+			from     string
+			expected string
+		}{
+			{expr: "*/15 * * * *", from: "2021-03-04T10:07:30Z", expected: "2021-03-04T10:15:00Z"},
+			{expr: "*/15 * * * *", from: "2021-03-04T10:15:00Z", expected: "2021-03-04T10:30:00Z"},
+			{expr: "5/20 * * * *", from: "2021-03-04T10:26:00Z", expected: "2021-03-04T10:45:00Z"},
+			{expr: "0,30 8-9 * * *", from: "2021-03-04T09:45:00Z", expected: "2021-03-05T08:00:00Z"},
+			{expr: "30 2 * * 1-5", from: "2021-03-05T03:00:00Z", expected: "2021-03-08T02:30:00Z"},
+			{expr: "0 0 1 * *", from: "2021-12-15T00:00:00Z", expected: "2022-01-01T00:00:00Z"},
+			{expr: "0 12 13 * 5", from: "2021-03-06T00:00:00Z", expected: "2021-03-12T12:00:00Z"},
+			{expr: "0 0 29 2 *", from: "2021-03-01T00:00:00Z", expected: "2024-02-29T00:00:00Z"},
+			{expr: "0 0 * * 7", from: "2021-03-04T00:00:00Z", expected: "2021-03-07T00:00:00Z"},
+			{expr: "0 0 * * */2", from: "2021-03-04T00:00:00Z", expected: "2021-03-06T00:00:00Z"},
+			{expr: "@daily", from: "2021-03-04T10:00:00Z", expected: "2021-03-05T00:00:00Z"},
+			{expr: "@hourly", from: "2021-03-04T10:00:00Z", expected: "2021-03-04T11:00:00Z"},
+			{expr: "@weekly", from: "2021-03-04T10:00:00Z", expected: "2021-03-07T00:00:00Z"},
+			{expr: "@yearly", from: "2021-03-04T10:00:00Z", expected: "2022-01-01T00:00:00Z"},
+			{expr: "0 0 * * *", from: "2021-03-04T22:00:00-05:00", expected: "2021-03-06T00:00:00Z"},
+		} {
+			s, err := ParseSchedule(tc.expr)
+			require.NoError(t, err, tc.expr)
+			require.Equal(t, tc.expr, s.String())
+
+			from, err := time.Parse(time.RFC3339, tc.from)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.expected, s.Next(from).Format(time.RFC3339), tc.expr)
+		}
+	})
+
+	t.Run("Never matches", func(t *testing.T) {
+		s, err := ParseSchedule("0 0 30 2 *")
+		require.NoError(t, err)
+		require.True(t, s.Next(time.Now()).IsZero())
+	})
+
+	t.Run("Interval", func(t *testing.T) {
+		s, err := ParseSchedule("@every 90s")
+		require.NoError(t, err)
+		require.Equal(t, "@every 1m30s", s.String())
+
+		now := time.Now()
+
+		require.Equal(t, now.Add(90*time.Second), s.Next(now))
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for _, expr := range []string{
+			"",
+			"* * * *",
+			"* * * * * *",
+			"60 * * * *",
+			"* 24 * * *",
+			"* * 0 * *",
+			"* * * 13 *",
+			"* * * * 8",
+			"*/0 * * * *",
+			"*/x * * * *",
+			"a * * * *",
+			"1-x * * * *",
+			"x-1 * * * *",
+			"5-1 * * * *",
+			"@every",
+			"@every -1s",
+			"@every x",
+			"@fortnightly",
+		} {
+			_, err := ParseSchedule(expr)
+			require.Error(t, err, expr)
+			require.True(t, errors.Is(err, errInvalidSchedule), expr)
+		}
+	})
+}
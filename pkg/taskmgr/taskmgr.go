@@ -10,6 +10,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -24,9 +26,25 @@ import (
 const (
 	loggerModule          = "task-manager"
 	coordinationPermitKey = "task-permit"
+	coordinationStateKey  = "task-state"
+	coordinationRunKey    = "task-run"
 	defaultCheckInterval  = 10 * time.Second
+
+	// never is the period of a scheduled task whose schedule never matches.
+	never time.Duration = math.MaxInt64
+)
+
+var (
+	// ErrTaskNotFound is returned if the task with the given ID isn't registered.
+	ErrTaskNotFound = errors.New("task not found")
+	// ErrTaskRunning is returned if a task is triggered while it is already running.
+	ErrTaskRunning = errors.New("task is already running")
 )
 
+type leaderElector interface {
+	IsLeader() bool
+}
+
 type logger interface {
 	Debugf(msg string, args ...interface{})
 	Infof(msg string, args ...interface{})
@@ -54,19 +72,96 @@ type permit struct {
 	UpdatedTime int64 `json:"updateTime"` // This is a Unix timestamp.
 }
 
+// taskState is stored within the coordination store in order to pause/resume a task across all Orb
+// instances in a cluster.
+type taskState struct {
+	Paused bool `json:"paused"`
+}
+
+// RunState contains the details of the last completed run of a task. The state is persisted so that it's
+// available to all instances in the cluster and after a restart.
+type RunState struct {
+	// Instance is the ID of the Orb instance that ran the task.
+	Instance string `json:"instance"`
+	// Started is the time that the run started.
+	Started time.Time `json:"started"`
+	// Duration is the duration of the run.
+	Duration string `json:"duration"`
+	// Error contains the error that occurred during the run (if any).
+	Error string `json:"error,omitempty"`
+}
+
+// TaskStatus contains the status of a registered task.
+type TaskStatus struct {
+	// ID is the ID of the task.
+	ID string `json:"id"`
+	// Interval is the interval at which the task is run. It's empty if the task is run according to a schedule.
+	Interval string `json:"interval,omitempty"`
+	// Schedule is the schedule (e.g. a cron expression) according to which the task is run, if configured.
+	Schedule string `json:"schedule,omitempty"`
+	// NextRun is the next time that the task is due to be run, if known.
+	NextRun *time.Time `json:"nextRun,omitempty"`
+	// Status indicates whether the task is currently running (on any instance) or idle.
+	Status string `json:"status"`
+	// Paused indicates whether the task is paused.
+	Paused bool `json:"paused"`
+	// CurrentHolder is the ID of the Orb instance that currently has the duty of running the task.
+	CurrentHolder string `json:"currentHolder,omitempty"`
+	// LastUpdated is the time that the current holder last updated the status of the task.
+	LastUpdated *time.Time `json:"lastUpdated,omitempty"`
+	// LastRun is the time that the task was last run by this instance.
+	LastRun *time.Time `json:"lastRun,omitempty"`
+	// LastDuration is the duration of the last run by this instance.
+	LastDuration string `json:"lastDuration,omitempty"`
+	// RunCount is the number of times that the task was run by this instance.
+	RunCount uint64 `json:"runCount"`
+	// LastError contains the last error that occurred on this instance while running the task (if any).
+	LastError string `json:"lastError,omitempty"`
+	// LastCompletedRun contains the details of the last completed run of the task on any instance.
+	LastCompletedRun *RunState `json:"lastCompletedRun,omitempty"`
+}
+
 // Manager manages scheduled tasks which are run by exactly one server instance in an Orb domain.
 type Manager struct {
 	*lifecycle.Lifecycle
 
 	interval          time.Duration
 	tasks             map[string]*registration
+	schedules         map[string]Schedule
 	done              chan struct{}
 	logger            logger
 	coordinationStore storage.Store
 	instanceID        string
+	leaderElector     leaderElector
 	mutex             sync.RWMutex
 }
 
+// Opt sets a task manager option.
+type Opt func(s *Manager)
+
+// WithLeaderElector sets the leader elector. If set then tasks are only run (on schedule) by the instance that
+// is currently the leader, instead of by the instance that holds the permit for each task. This avoids the
+// brief period at startup, or after an instance goes down, in which multiple instances may run the same task.
+func WithLeaderElector(e leaderElector) Opt {
+	return func(s *Manager) {
+		s.leaderElector = e
+	}
+}
+
+// WithSchedule sets the schedule of the given task, which overrides the interval that the task is registered with.
+func WithSchedule(taskID string, schedule Schedule) Opt {
+	return func(s *Manager) {
+		s.schedules[taskID] = schedule
+	}
+}
+
+// WithInstanceID sets the unique ID of this server instance. If not set then a random ID is generated.
+func WithInstanceID(id string) Opt {
+	return func(s *Manager) {
+		s.instanceID = id
+	}
+}
+
 // New returns a new task manager.
 // coordinationStore is used for ensuring that only one Orb instance within a cluster has the duty of running scheduled
 // tasks (in order to avoid every instance doing the same work, which is wasteful). Every Orb instance
@@ -77,7 +172,7 @@ type Manager struct {
 // since a task should expect this situation.
 // You must register each task you want this service to run on using the Register method.
 // Start must be called to start the service and Stop should be called to stop it.
-func New(coordinationStore storage.Store, interval time.Duration) *Manager {
+func New(coordinationStore storage.Store, interval time.Duration, opts ...Opt) *Manager {
 	if interval <= 0 {
 		interval = defaultCheckInterval
 	}
@@ -89,6 +184,11 @@ func New(coordinationStore storage.Store, interval time.Duration) *Manager {
 		coordinationStore: coordinationStore,
 		instanceID:        uuid.New().String(),
 		tasks:             make(map[string]*registration),
+		schedules:         make(map[string]Schedule),
+	}
+
+	for _, opt := range opts {
+		opt(s)
 	}
 
 	s.Lifecycle = lifecycle.New("task-manager",
@@ -103,16 +203,200 @@ func (s *Manager) InstanceID() string {
 	return s.instanceID
 }
 
-// RegisterTask registers a task to be periodically run at the given interval.
+// RegisterTask registers a task to be periodically run at the given interval. If a schedule was configured for
+// the task (using the WithSchedule option) then the task is run according to the schedule instead.
 func (s *Manager) RegisterTask(id string, interval time.Duration, task func()) {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
+	schedule := s.schedules[id]
+	if schedule != nil {
+		s.logger.Infof("Task [%s] is run according to schedule [%s]", id, schedule)
+	}
+
 	s.tasks[id] = &registration{
-		handle:   task,
-		id:       id,
-		interval: interval,
+		handle:     task,
+		id:         id,
+		interval:   interval,
+		schedule:   schedule,
+		registered: time.Now(),
+	}
+}
+
+// Tasks returns the status of all registered tasks, sorted by ID.
+func (s *Manager) Tasks() ([]*TaskStatus, error) {
+	tasks := s.getTasks()
+
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].id < tasks[j].id
+	})
+
+	statuses := make([]*TaskStatus, len(tasks))
+
+	for i, t := range tasks {
+		ts, err := s.getStatus(t)
+		if err != nil {
+			return nil, err
+		}
+
+		statuses[i] = ts
+	}
+
+	return statuses, nil
+}
+
+// TriggerTask immediately runs the given task on this instance, regardless of the task's schedule
+// or whether it is paused.
+func (s *Manager) TriggerTask(id string) error {
+	t, err := s.getTask(id)
+	if err != nil {
+		return err
+	}
+
+	if t.isRunning() {
+		return fmt.Errorf("trigger task [%s]: %w", id, ErrTaskRunning)
+	}
+
+	err = s.updatePermit(t.id, statusRunning)
+	if err != nil {
+		return fmt.Errorf("trigger task [%s]: %w", id, err)
+	}
+
+	s.logger.Infof("[%s] Task [%s] was triggered", s.instanceID, t.id)
+
+	s.execute(t)
+
+	return nil
+}
+
+// PauseTask pauses the given task on all instances in the cluster. If the task is currently running
+// then the current run is allowed to complete, but the task won't be run again until it is resumed.
+func (s *Manager) PauseTask(id string) error {
+	return s.setPaused(id, true)
+}
+
+// ResumeTask resumes the given (paused) task.
+func (s *Manager) ResumeTask(id string) error {
+	return s.setPaused(id, false)
+}
+
+func (s *Manager) setPaused(id string, paused bool) error {
+	if _, err := s.getTask(id); err != nil {
+		return err
+	}
+
+	stateBytes, err := json.Marshal(&taskState{Paused: paused})
+	if err != nil {
+		return fmt.Errorf("marshal state for task [%s]: %w", id, err)
+	}
+
+	err = s.coordinationStore.Put(getStateKey(id), stateBytes)
+	if err != nil {
+		return fmt.Errorf("store state for task [%s]: %w", id, err)
+	}
+
+	s.logger.Infof("[%s] Task [%s] paused: %t", s.instanceID, id, paused)
+
+	return nil
+}
+
+func (s *Manager) isPaused(id string) (bool, error) {
+	stateBytes, err := s.coordinationStore.Get(getStateKey(id))
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("get state from DB for task [%s]: %w", id, err)
+	}
+
+	state := &taskState{}
+
+	err = json.Unmarshal(stateBytes, state)
+	if err != nil {
+		return false, fmt.Errorf("unmarshal state for task [%s]: %w", id, err)
+	}
+
+	return state.Paused, nil
+}
+
+func (s *Manager) getStatus(t *registration) (*TaskStatus, error) {
+	paused, err := s.isPaused(t.id)
+	if err != nil {
+		return nil, err
+	}
+
+	ts := &TaskStatus{
+		ID:     t.id,
+		Status: statusIdle,
+		Paused: paused,
+	}
+
+	if t.schedule != nil {
+		ts.Schedule = t.schedule.String()
+	} else {
+		ts.Interval = t.interval.String()
+	}
+
+	err = s.populatePermitStatus(t, ts)
+	if err != nil {
+		return nil, err
+	}
+
+	ts.LastCompletedRun, err = s.getRunState(t.id)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.isRunning() {
+		ts.Status = statusRunning
+	}
+
+	t.populateStatus(ts)
+
+	return ts, nil
+}
+
+func (s *Manager) populatePermitStatus(t *registration, ts *TaskStatus) error {
+	currentPermitBytes, err := s.coordinationStore.Get(getPermitKey(t.id))
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return nil
+		}
+
+		return fmt.Errorf("get permit from DB for task [%s]: %w", t.id, err)
+	}
+
+	var currentPermit permit
+
+	err = json.Unmarshal(currentPermitBytes, &currentPermit)
+	if err != nil {
+		return fmt.Errorf("unmarshal permit for task [%s]: %w", t.id, err)
+	}
+
+	lastUpdated := time.Unix(currentPermit.UpdatedTime, 0)
+
+	ts.Status = currentPermit.Status
+	ts.CurrentHolder = currentPermit.CurrentHolder
+	ts.LastUpdated = &lastUpdated
+
+	if currentPermit.Status == statusIdle {
+		ts.NextRun = t.nextRun(lastUpdated)
 	}
+
+	return nil
+}
+
+func (s *Manager) getTask(id string) (*registration, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	t, ok := s.tasks[id]
+	if !ok {
+		return nil, fmt.Errorf("task [%s]: %w", id, ErrTaskNotFound)
+	}
+
+	return t, nil
 }
 
 func (s *Manager) getTasks() []*registration {
@@ -163,11 +447,19 @@ func (s *Manager) run(t *registration) {
 		return
 	}
 
+	if s.leaderElector != nil && !s.leaderElector.IsLeader() {
+		s.logger.Debugf("[%s] Not running task [%s] since I'm not the leader", s.instanceID, t.id)
+
+		return
+	}
+
 	ok, err := s.shouldRun(t)
 	if err != nil {
 		s.logger.Warnf("[%s] An error occurred while checking if task [%s] should run: %s",
 			s.instanceID, t.id, err)
 
+		t.setError(err)
+
 		return
 	}
 
@@ -177,34 +469,69 @@ func (s *Manager) run(t *registration) {
 		return
 	}
 
+	paused, err := s.isPaused(t.id)
+	if err != nil {
+		s.logger.Warnf("[%s] An error occurred while checking if task [%s] is paused: %s",
+			s.instanceID, t.id, err)
+
+		t.setError(err)
+
+		return
+	}
+
+	if paused {
+		s.logger.Debugf("[%s] Not running task [%s] since it is paused", s.instanceID, t.id)
+
+		return
+	}
+
 	err = s.updatePermit(t.id, statusRunning)
 	if err != nil {
 		s.logger.Errorf("[%s] Failed to update permit for task [%s]: %s", s.instanceID, t.id, err.Error())
 
+		t.setError(err)
+
 		return
 	}
 
-	// Run the task in a new Go routine.
+	s.execute(t)
+}
 
+// execute runs the task in a new Go routine.
+func (s *Manager) execute(t *registration) {
 	go func(t *registration) {
 		s.logger.Debugf("[%s] Running task [%s]", s.instanceID, t.id)
 
-		t.run()
+		if err := t.run(); err != nil {
+			s.logger.Errorf("[%s] Error running task [%s]: %s", s.instanceID, t.id, err)
+		}
+
+		if err := s.saveRunState(t.id, t.runState(s.instanceID)); err != nil {
+			s.logger.Warnf("[%s] Failed to save the run state of task [%s]: %s", s.instanceID, t.id, err)
+		}
 
 		err := s.updatePermit(t.id, statusIdle)
 		if err != nil {
 			s.logger.Errorf("[%s] Failed to update permit: %s", s.instanceID, err.Error())
+
+			t.setError(err)
 		}
 
 		s.logger.Debugf("[%s] Finished running task [%s]", s.instanceID, t.id)
 	}(t)
 }
 
-//nolint:funlen
+//nolint:funlen,gocyclo
 func (s *Manager) shouldRun(t *registration) (bool, error) {
 	currentPermitBytes, err := s.coordinationStore.Get(getPermitKey(t.id))
 	if err != nil {
 		if errors.Is(err, storage.ErrDataNotFound) {
+			// A task with an interval is run immediately whereas a scheduled task is only run at its next
+			// scheduled time after it was registered.
+			if t.schedule != nil && time.Since(t.registered) < t.period(t.registered) {
+				return false, nil
+			}
+
 			s.logger.Infof("[%s] No existing permit found for task [%s]. I will take on "+
 				"the duty of running the task.", s.instanceID, t.id)
 
@@ -230,11 +557,18 @@ func (s *Manager) shouldRun(t *registration) (bool, error) {
 	// of (meaningless) precision.
 	timeSinceLastUpdate := time.Since(timeOfLastUpdate).Truncate(time.Second)
 
+	// The period is the time between the last run and the next run, which is the interval of the task unless the
+	// task has a schedule.
+	period := t.period(timeOfLastUpdate)
+	if period == never {
+		return false, nil
+	}
+
 	if currentPermit.CurrentHolder == s.instanceID {
-		if timeSinceLastUpdate < t.interval {
+		if timeSinceLastUpdate < period {
 			s.logger.Debugf("[%s] It's currently my duty to run task [%s] but it's not time to run the task "+
-				"since I last did this %s ago and the interval for this task is %s.",
-				s.instanceID, t.id, timeSinceLastUpdate, t.interval)
+				"since I last did this %s ago and the next run is due in %s.",
+				s.instanceID, t.id, timeSinceLastUpdate, period-timeSinceLastUpdate)
 
 			return false, nil
 		}
@@ -252,7 +586,27 @@ func (s *Manager) shouldRun(t *registration) (bool, error) {
 	// within the cluster have the same interval setting (which they should).
 	// So, "unusually long time" means that the 'last update' time is greater than the Task Manager check interval plus
 	// the task's run interval, in which case we'll assume that the other instance is dead and will take over.
-	maxTime := s.interval + t.interval
+	maxTime := s.interval + period
+
+	if s.leaderElector != nil {
+		// I'm the leader (this is checked by the caller) so I will take over from the previous holder unless the
+		// previous holder is still running the task or ran it too recently.
+		if currentPermit.Status == statusRunning && timeSinceLastUpdate <= maxTime {
+			s.logger.Debugf("[%s] I'm the leader but I will not run task [%s] since the previous holder [%s] "+
+				"is still running it.", s.instanceID, t.id, currentPermit.CurrentHolder)
+
+			return false, nil
+		}
+
+		if timeSinceLastUpdate < period {
+			return false, nil
+		}
+
+		s.logger.Infof("[%s] I'm the leader and will take over the duty of running task [%s] from [%s].",
+			s.instanceID, t.id, currentPermit.CurrentHolder)
+
+		return true, nil
+	}
 
 	if timeSinceLastUpdate > maxTime {
 		s.logger.Infof("[%s] The current permit holder [%s] for task [%s] has not updated the permit in an "+
@@ -296,26 +650,169 @@ func (s *Manager) updatePermit(taskID string, status status) error {
 	return nil
 }
 
+func (s *Manager) saveRunState(taskID string, state *RunState) error {
+	stateBytes, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("marshal run state: %w", err)
+	}
+
+	err = s.coordinationStore.Put(getRunKey(taskID), stateBytes)
+	if err != nil {
+		return fmt.Errorf("store run state: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Manager) getRunState(taskID string) (*RunState, error) {
+	stateBytes, err := s.coordinationStore.Get(getRunKey(taskID))
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("get run state from DB for task [%s]: %w", taskID, err)
+	}
+
+	state := &RunState{}
+
+	err = json.Unmarshal(stateBytes, state)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal run state for task [%s]: %w", taskID, err)
+	}
+
+	return state, nil
+}
+
 func getPermitKey(taskID string) string {
 	return coordinationPermitKey + "_" + taskID
 }
 
+func getStateKey(taskID string) string {
+	return coordinationStateKey + "_" + taskID
+}
+
+func getRunKey(taskID string) string {
+	return coordinationRunKey + "_" + taskID
+}
+
 type registration struct {
-	handle   func()
-	running  uint32
-	id       string
-	interval time.Duration
+	handle     func()
+	running    uint32
+	id         string
+	interval   time.Duration
+	schedule   Schedule
+	registered time.Time
+
+	mutex        sync.RWMutex
+	lastRun      time.Time
+	lastDuration time.Duration
+	runCount     uint64
+	lastErr      error
 }
 
-func (r *registration) run() {
+func (r *registration) run() error {
 	if !atomic.CompareAndSwapUint32(&r.running, 0, 1) {
 		// Already running.
-		return
+		return nil
 	}
 
+	defer atomic.StoreUint32(&r.running, 0)
+
+	start := time.Now()
+
+	err := r.invoke()
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.lastRun = start
+	r.lastDuration = time.Since(start)
+	r.runCount++
+	r.lastErr = err
+
+	return err
+}
+
+// invoke runs the task and returns an error if the task panics so that a failing task
+// doesn't bring down the server.
+func (r *registration) invoke() (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("task [%s] panicked: %v", r.id, p)
+		}
+	}()
+
 	r.handle()
 
-	atomic.StoreUint32(&r.running, 0)
+	return nil
+}
+
+// period returns the time between the given (last) run of the task and the next scheduled run.
+func (r *registration) period(last time.Time) time.Duration {
+	if r.schedule == nil {
+		return r.interval
+	}
+
+	next := r.schedule.Next(last)
+	if next.IsZero() {
+		return never
+	}
+
+	return next.Sub(last)
+}
+
+func (r *registration) nextRun(last time.Time) *time.Time {
+	period := r.period(last)
+	if period == never {
+		return nil
+	}
+
+	next := last.Add(period)
+
+	return &next
+}
+
+func (r *registration) runState(instanceID string) *RunState {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	state := &RunState{
+		Instance: instanceID,
+		Started:  r.lastRun,
+		Duration: r.lastDuration.String(),
+	}
+
+	if r.lastErr != nil {
+		state.Error = r.lastErr.Error()
+	}
+
+	return state
+}
+
+func (r *registration) setError(err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.lastErr = err
+}
+
+func (r *registration) populateStatus(ts *TaskStatus) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if !r.lastRun.IsZero() {
+		lastRun := r.lastRun
+
+		ts.LastRun = &lastRun
+		ts.LastDuration = r.lastDuration.String()
+	}
+
+	ts.RunCount = r.runCount
+
+	if r.lastErr != nil {
+		ts.LastError = r.lastErr.Error()
+	}
 }
 
 func (r *registration) isRunning() bool {
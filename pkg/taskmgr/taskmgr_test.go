@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package taskmgr
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
@@ -15,6 +16,7 @@ import (
 	"time"
 
 	"github.com/hyperledger/aries-framework-go-ext/component/storage/mongodb"
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
 	"github.com/hyperledger/aries-framework-go/component/storageutil/mock"
 	"github.com/hyperledger/aries-framework-go/spi/storage"
 	"github.com/stretchr/testify/require"
@@ -236,3 +238,375 @@ func ensureLogContainsMessage(t *testing.T, logger *stringLogger, expectedMessag
 Expected message: %s`, logContents, expectedMessage)
 	}
 }
+
+func TestManager_Tasks(t *testing.T) {
+	coordinationStore, err := mem.NewProvider().OpenStore("orb-config")
+	require.NoError(t, err)
+
+	taskMgr := New(coordinationStore, time.Hour)
+
+	var mutex sync.Mutex
+
+	runCount := 0
+
+	taskMgr.RegisterTask("task2", time.Hour, func() {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		runCount++
+	})
+
+	taskMgr.RegisterTask("task1", time.Hour, func() {
+		panic("injected panic")
+	})
+
+	t.Run("Initial status", func(t *testing.T) {
+		tasks, err := taskMgr.Tasks()
+		require.NoError(t, err)
+		require.Len(t, tasks, 2)
+
+		require.Equal(t, "task1", tasks[0].ID)
+		require.Equal(t, "task2", tasks[1].ID)
+		require.Equal(t, statusIdle, tasks[1].Status)
+		require.Equal(t, "1h0m0s", tasks[1].Interval)
+		require.False(t, tasks[1].Paused)
+		require.Nil(t, tasks[1].LastRun)
+		require.Empty(t, tasks[1].CurrentHolder)
+	})
+
+	t.Run("Trigger", func(t *testing.T) {
+		require.NoError(t, taskMgr.TriggerTask("task2"))
+
+		require.Eventually(t, func() bool {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			return runCount == 1
+		}, time.Second, 10*time.Millisecond)
+
+		require.Eventually(t, func() bool {
+			ts := getTaskStatus(t, taskMgr, "task2")
+
+			return ts.Status == statusIdle && ts.RunCount == 1
+		}, time.Second, 10*time.Millisecond)
+
+		ts := getTaskStatus(t, taskMgr, "task2")
+		require.NotNil(t, ts.LastRun)
+		require.NotEmpty(t, ts.LastDuration)
+		require.Equal(t, taskMgr.InstanceID(), ts.CurrentHolder)
+		require.NotNil(t, ts.LastUpdated)
+		require.Empty(t, ts.LastError)
+	})
+
+	t.Run("Trigger -> panic", func(t *testing.T) {
+		require.NoError(t, taskMgr.TriggerTask("task1"))
+
+		require.Eventually(t, func() bool {
+			return getTaskStatus(t, taskMgr, "task1").LastError != ""
+		}, time.Second, 10*time.Millisecond)
+
+		require.Contains(t, getTaskStatus(t, taskMgr, "task1").LastError, "injected panic")
+	})
+
+	t.Run("Pause/resume", func(t *testing.T) {
+		require.NoError(t, taskMgr.PauseTask("task2"))
+		require.True(t, getTaskStatus(t, taskMgr, "task2").Paused)
+
+		paused, err := taskMgr.isPaused("task2")
+		require.NoError(t, err)
+		require.True(t, paused)
+
+		require.NoError(t, taskMgr.ResumeTask("task2"))
+		require.False(t, getTaskStatus(t, taskMgr, "task2").Paused)
+	})
+
+	t.Run("Task not found", func(t *testing.T) {
+		require.True(t, errors.Is(taskMgr.TriggerTask("task3"), ErrTaskNotFound))
+		require.True(t, errors.Is(taskMgr.PauseTask("task3"), ErrTaskNotFound))
+		require.True(t, errors.Is(taskMgr.ResumeTask("task3"), ErrTaskNotFound))
+	})
+
+	t.Run("Task running", func(t *testing.T) {
+		mgr := New(coordinationStore, time.Hour)
+
+		release := make(chan struct{})
+
+		mgr.RegisterTask("blocking-task", time.Hour, func() {
+			<-release
+		})
+
+		require.NoError(t, mgr.TriggerTask("blocking-task"))
+
+		require.Eventually(t, func() bool {
+			return getTaskStatus(t, mgr, "blocking-task").Status == statusRunning
+		}, time.Second, 10*time.Millisecond)
+
+		require.True(t, errors.Is(mgr.TriggerTask("blocking-task"), ErrTaskRunning))
+
+		close(release)
+	})
+
+	t.Run("Store error", func(t *testing.T) {
+		errExpected := errors.New("injected store error")
+
+		mgr := New(&mock.Store{ErrGet: errExpected, ErrPut: errExpected}, time.Hour)
+		mgr.RegisterTask("task1", time.Hour, func() {})
+
+		_, err := mgr.Tasks()
+		require.True(t, errors.Is(err, errExpected))
+
+		require.True(t, errors.Is(mgr.TriggerTask("task1"), errExpected))
+		require.True(t, errors.Is(mgr.PauseTask("task1"), errExpected))
+	})
+}
+
+func TestManager_PausedTaskNotRun(t *testing.T) {
+	coordinationStore, err := mem.NewProvider().OpenStore("orb-config")
+	require.NoError(t, err)
+
+	taskMgr := New(coordinationStore, time.Millisecond)
+
+	logger := &stringLogger{}
+
+	taskMgr.logger = logger
+
+	taskMgr.RegisterTask("test-task", time.Millisecond, func() {
+		t.Logf("Running test-task")
+	})
+
+	require.NoError(t, taskMgr.PauseTask("test-task"))
+
+	taskMgr.Start()
+	defer taskMgr.Stop()
+
+	ensureLogContainsMessage(t, logger, "Not running task [test-task] since it is paused")
+}
+
+func TestManager_LeaderElection(t *testing.T) {
+	t.Run("Not leader", func(t *testing.T) {
+		coordinationStore, err := mem.NewProvider().OpenStore("orb-config")
+		require.NoError(t, err)
+
+		taskMgr := New(coordinationStore, time.Millisecond,
+			WithInstanceID("instance1"), WithLeaderElector(&mockLeaderElector{}))
+		require.Equal(t, "instance1", taskMgr.InstanceID())
+
+		logger := &stringLogger{}
+
+		taskMgr.logger = logger
+
+		taskMgr.RegisterTask("test-task", time.Millisecond, func() {
+			t.Logf("Running test-task")
+		})
+
+		taskMgr.Start()
+		defer taskMgr.Stop()
+
+		ensureLogContainsMessage(t, logger, "Not running task [test-task] since I'm not the leader")
+	})
+
+	t.Run("Leader takes over", func(t *testing.T) {
+		coordinationStore, err := mem.NewProvider().OpenStore("orb-config")
+		require.NoError(t, err)
+
+		// Another instance previously had the duty of running the task.
+		permitBytes, err := json.Marshal(&permit{
+			TaskID:        "test-task",
+			CurrentHolder: "instance2",
+			Status:        statusIdle,
+			UpdatedTime:   time.Now().Add(-time.Minute).Unix(),
+		})
+		require.NoError(t, err)
+		require.NoError(t, coordinationStore.Put(getPermitKey("test-task"), permitBytes))
+
+		taskMgr := New(coordinationStore, time.Millisecond, WithLeaderElector(&mockLeaderElector{isLeader: true}))
+
+		ran := make(chan struct{}, 1)
+
+		taskMgr.RegisterTask("test-task", time.Second, func() {
+			select {
+			case ran <- struct{}{}:
+			default:
+			}
+		})
+
+		taskMgr.Start()
+		defer taskMgr.Stop()
+
+		select {
+		case <-ran:
+		case <-time.After(time.Second):
+			require.FailNow(t, "expecting the leader to run the task")
+		}
+	})
+
+	t.Run("Previous holder still running", func(t *testing.T) {
+		coordinationStore, err := mem.NewProvider().OpenStore("orb-config")
+		require.NoError(t, err)
+
+		permitBytes, err := json.Marshal(&permit{
+			TaskID:        "test-task",
+			CurrentHolder: "instance2",
+			Status:        statusRunning,
+			UpdatedTime:   time.Now().Unix(),
+		})
+		require.NoError(t, err)
+		require.NoError(t, coordinationStore.Put(getPermitKey("test-task"), permitBytes))
+
+		taskMgr := New(coordinationStore, time.Hour, WithLeaderElector(&mockLeaderElector{isLeader: true}))
+
+		taskMgr.RegisterTask("test-task", time.Hour, func() {})
+
+		ok, err := taskMgr.shouldRun(taskMgr.tasks["test-task"])
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+}
+
+func TestManager_Schedule(t *testing.T) {
+	yearly, err := ParseSchedule("0 0 1 1 *")
+	require.NoError(t, err)
+
+	never, err := ParseSchedule("0 0 30 2 *")
+	require.NoError(t, err)
+
+	t.Run("Status and persisted run state", func(t *testing.T) {
+		coordinationStore, err := mem.NewProvider().OpenStore("orb-config")
+		require.NoError(t, err)
+
+		taskMgr := New(coordinationStore, time.Hour, WithSchedule("task1", yearly))
+
+		taskMgr.RegisterTask("task1", time.Hour, func() {})
+
+		ts := getTaskStatus(t, taskMgr, "task1")
+		require.Equal(t, "0 0 1 1 *", ts.Schedule)
+		require.Empty(t, ts.Interval)
+		require.Nil(t, ts.NextRun)
+		require.Nil(t, ts.LastCompletedRun)
+
+		// The task isn't run immediately since it's not yet time according to the schedule.
+		ok, err := taskMgr.shouldRun(taskMgr.tasks["task1"])
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		require.NoError(t, taskMgr.TriggerTask("task1"))
+
+		require.Eventually(t, func() bool {
+			ts := getTaskStatus(t, taskMgr, "task1")
+
+			return ts.Status == statusIdle && ts.LastCompletedRun != nil
+		}, time.Second, 10*time.Millisecond)
+
+		ts = getTaskStatus(t, taskMgr, "task1")
+		require.NotNil(t, ts.NextRun)
+		require.Equal(t, 1, ts.NextRun.UTC().YearDay())
+		require.Equal(t, taskMgr.InstanceID(), ts.LastCompletedRun.Instance)
+		require.Empty(t, ts.LastCompletedRun.Error)
+
+		ok, err = taskMgr.shouldRun(taskMgr.tasks["task1"])
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		// The run state is available to another instance (or after a restart).
+		taskMgr2 := New(coordinationStore, time.Hour)
+
+		taskMgr2.RegisterTask("task1", time.Hour, func() {})
+
+		ts = getTaskStatus(t, taskMgr2, "task1")
+		require.Equal(t, "1h0m0s", ts.Interval)
+		require.Empty(t, ts.Schedule)
+		require.NotNil(t, ts.LastCompletedRun)
+		require.Equal(t, taskMgr.InstanceID(), ts.LastCompletedRun.Instance)
+		require.Equal(t, 0, int(ts.RunCount))
+	})
+
+	t.Run("Schedule never matches", func(t *testing.T) {
+		coordinationStore, err := mem.NewProvider().OpenStore("orb-config")
+		require.NoError(t, err)
+
+		taskMgr := New(coordinationStore, time.Hour, WithSchedule("task1", never))
+
+		taskMgr.RegisterTask("task1", time.Hour, func() {})
+
+		require.NoError(t, taskMgr.updatePermit("task1", statusIdle))
+
+		ok, err := taskMgr.shouldRun(taskMgr.tasks["task1"])
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		require.Nil(t, getTaskStatus(t, taskMgr, "task1").NextRun)
+	})
+
+	t.Run("Scheduled task is run", func(t *testing.T) {
+		coordinationStore, err := mem.NewProvider().OpenStore("orb-config")
+		require.NoError(t, err)
+
+		every, err := ParseSchedule("@every 1ms")
+		require.NoError(t, err)
+
+		taskMgr := New(coordinationStore, time.Millisecond, WithSchedule("test-task", every))
+
+		ran := make(chan struct{}, 1)
+
+		taskMgr.RegisterTask("test-task", time.Hour, func() {
+			select {
+			case ran <- struct{}{}:
+			default:
+			}
+		})
+
+		taskMgr.Start()
+		defer taskMgr.Stop()
+
+		select {
+		case <-ran:
+		case <-time.After(time.Second):
+			require.FailNow(t, "expecting the task to be run")
+		}
+	})
+
+	t.Run("Run state store error", func(t *testing.T) {
+		errExpected := errors.New("injected store error")
+
+		coordinationStore, err := mem.NewProvider().OpenStore("orb-config")
+		require.NoError(t, err)
+
+		taskMgr := New(coordinationStore, time.Hour)
+
+		taskMgr.RegisterTask("task1", time.Hour, func() {})
+
+		taskMgr.coordinationStore = &mock.Store{ErrGet: errExpected}
+
+		_, err = taskMgr.getRunState("task1")
+		require.True(t, errors.Is(err, errExpected))
+
+		taskMgr.coordinationStore = &mock.Store{ErrPut: errExpected}
+
+		require.True(t, errors.Is(taskMgr.saveRunState("task1", &RunState{}), errExpected))
+	})
+}
+
+type mockLeaderElector struct {
+	isLeader bool
+}
+
+func (m *mockLeaderElector) IsLeader() bool {
+	return m.isLeader
+}
+
+func getTaskStatus(t *testing.T, taskMgr *Manager, id string) *TaskStatus {
+	t.Helper()
+
+	tasks, err := taskMgr.Tasks()
+	require.NoError(t, err)
+
+	for _, ts := range tasks {
+		if ts.ID == id {
+			return ts
+		}
+	}
+
+	require.FailNow(t, "task not found", id)
+
+	return nil
+}
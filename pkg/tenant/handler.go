@@ -0,0 +1,86 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tenant
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+)
+
+type paramHolder interface {
+	Params() map[string]string
+}
+
+// HandlerWrapper wraps an HTTP handler of a tenant so that the handler is only selected for requests with the
+// tenant's host and/or base path. The base path is removed from the request before the handler is invoked so
+// that the handler (including any authorization of the request path) is unaware of the tenant.
+type HandlerWrapper struct {
+	common.HTTPHandler
+
+	tenant *Config
+}
+
+// NewHandlerWrapper returns a new tenant handler wrapper.
+func NewHandlerWrapper(handler common.HTTPHandler, tenant *Config) *HandlerWrapper {
+	return &HandlerWrapper{
+		HTTPHandler: handler,
+		tenant:      tenant,
+	}
+}
+
+// WrapHandlers wraps each of the given handlers with a tenant handler wrapper.
+func WrapHandlers(handlers []common.HTTPHandler, tenant *Config) []common.HTTPHandler {
+	wrapped := make([]common.HTTPHandler, len(handlers))
+
+	for i, handler := range handlers {
+		wrapped[i] = NewHandlerWrapper(handler, tenant)
+	}
+
+	return wrapped
+}
+
+// Path returns the path of the handler prefixed with the tenant's base path.
+func (h *HandlerWrapper) Path() string {
+	return h.tenant.BasePath + h.HTTPHandler.Path()
+}
+
+// Host returns the host that selects the tenant or an empty string if the tenant is selected
+// by base path only.
+func (h *HandlerWrapper) Host() string {
+	return h.tenant.Host
+}
+
+// Params returns the query parameters of the wrapped handler (if any).
+func (h *HandlerWrapper) Params() map[string]string {
+	if p, ok := h.HTTPHandler.(paramHolder); ok {
+		return p.Params()
+	}
+
+	return nil
+}
+
+// Handler returns the handler that removes the tenant's base path from the request and then invokes
+// the wrapped handler.
+func (h *HandlerWrapper) Handler() common.HTTPRequestHandler {
+	handle := h.HTTPHandler.Handler()
+
+	if h.tenant.BasePath == "" {
+		return handle
+	}
+
+	return func(w http.ResponseWriter, req *http.Request) {
+		r := req.Clone(req.Context())
+
+		r.URL.Path = strings.TrimPrefix(req.URL.Path, h.tenant.BasePath)
+		r.URL.RawPath = strings.TrimPrefix(req.URL.RawPath, h.tenant.BasePath)
+		r.RequestURI = strings.TrimPrefix(req.RequestURI, h.tenant.BasePath)
+
+		handle(w, r)
+	}
+}
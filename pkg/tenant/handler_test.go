@@ -0,0 +1,85 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tenant
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+)
+
+func TestHandlerWrapper(t *testing.T) {
+	t.Run("Base path", func(t *testing.T) {
+		inner := &mockHandler{params: map[string]string{"page": "{page}"}}
+
+		handlers := WrapHandlers([]common.HTTPHandler{inner}, &Config{ID: "tenant1", BasePath: "/tenant1"})
+		require.Len(t, handlers, 1)
+
+		h, ok := handlers[0].(*HandlerWrapper)
+		require.True(t, ok)
+
+		require.Equal(t, "/tenant1/services/orb", h.Path())
+		require.Equal(t, http.MethodGet, h.Method())
+		require.Empty(t, h.Host())
+		require.Equal(t, inner.params, h.Params())
+
+		rw := httptest.NewRecorder()
+
+		h.Handler()(rw, httptest.NewRequest(http.MethodGet, "/tenant1/services/orb?page=1", nil))
+
+		require.Equal(t, http.StatusOK, rw.Code)
+		require.Equal(t, "/services/orb", inner.path)
+		require.Equal(t, "/services/orb?page=1", inner.requestURI)
+	})
+
+	t.Run("Host", func(t *testing.T) {
+		inner := &mockHandler{}
+
+		h := NewHandlerWrapper(inner, &Config{ID: "tenant1", Host: "tenant1.example.com"})
+
+		require.Equal(t, "/services/orb", h.Path())
+		require.Equal(t, "tenant1.example.com", h.Host())
+		require.Nil(t, h.Params())
+
+		rw := httptest.NewRecorder()
+
+		h.Handler()(rw, httptest.NewRequest(http.MethodGet, "http://tenant1.example.com/services/orb", nil))
+
+		require.Equal(t, http.StatusOK, rw.Code)
+		require.Equal(t, "/services/orb", inner.path)
+	})
+}
+
+type mockHandler struct {
+	params     map[string]string
+	path       string
+	requestURI string
+}
+
+func (m *mockHandler) Path() string {
+	return "/services/orb"
+}
+
+func (m *mockHandler) Method() string {
+	return http.MethodGet
+}
+
+func (m *mockHandler) Params() map[string]string {
+	return m.params
+}
+
+func (m *mockHandler) Handler() common.HTTPRequestHandler {
+	return func(w http.ResponseWriter, req *http.Request) {
+		m.path = req.URL.Path
+		m.requestURI = req.RequestURI
+
+		w.WriteHeader(http.StatusOK)
+	}
+}
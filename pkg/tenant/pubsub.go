@@ -0,0 +1,63 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tenant
+
+import (
+	"context"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+
+	"github.com/trustbloc/orb/pkg/pubsub/spi"
+)
+
+type pubSub interface {
+	Subscribe(ctx context.Context, topic string) (<-chan *message.Message, error)
+	SubscribeWithOpts(ctx context.Context, topic string, opts ...spi.Option) (<-chan *message.Message, error)
+	Publish(topic string, messages ...*message.Message) error
+	Close() error
+}
+
+// PubSub wraps a publisher/subscriber and isolates the topics of a tenant by prefixing each topic
+// with the tenant ID.
+type PubSub struct {
+	pubSub   pubSub
+	tenantID string
+}
+
+// NewPubSub returns a new publisher/subscriber for the given tenant.
+func NewPubSub(ps pubSub, tenantID string) *PubSub {
+	return &PubSub{
+		pubSub:   ps,
+		tenantID: tenantID,
+	}
+}
+
+// Subscribe subscribes to the tenant's topic.
+func (p *PubSub) Subscribe(ctx context.Context, topic string) (<-chan *message.Message, error) {
+	return p.pubSub.Subscribe(ctx, p.topic(topic))
+}
+
+// SubscribeWithOpts subscribes to the tenant's topic using the provided options.
+func (p *PubSub) SubscribeWithOpts(ctx context.Context, topic string,
+	opts ...spi.Option) (<-chan *message.Message, error) {
+	return p.pubSub.SubscribeWithOpts(ctx, p.topic(topic), opts...)
+}
+
+// Publish publishes the messages to the tenant's topic.
+func (p *PubSub) Publish(topic string, messages ...*message.Message) error {
+	return p.pubSub.Publish(p.topic(topic), messages...)
+}
+
+// Close does nothing since the underlying publisher/subscriber is shared by all tenants and is closed
+// by its owner.
+func (p *PubSub) Close() error {
+	return nil
+}
+
+func (p *PubSub) topic(topic string) string {
+	return p.tenantID + "." + topic
+}
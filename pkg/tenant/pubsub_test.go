@@ -0,0 +1,79 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tenant
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/pubsub/mempubsub"
+	"github.com/trustbloc/orb/pkg/pubsub/spi"
+)
+
+const topic = "orb.test"
+
+func TestPubSub(t *testing.T) {
+	ps := mempubsub.New(mempubsub.DefaultConfig())
+
+	defer func() {
+		require.NoError(t, ps.Close())
+	}()
+
+	p1 := NewPubSub(ps, "tenant1")
+	p2 := NewPubSub(ps, "tenant2")
+
+	msgChan1, err := p1.Subscribe(context.Background(), topic)
+	require.NoError(t, err)
+
+	msgChan2, err := p2.SubscribeWithOpts(context.Background(), topic, spi.WithPool(2))
+	require.NoError(t, err)
+
+	underlyingChan, err := ps.Subscribe(context.Background(), "tenant1."+topic)
+	require.NoError(t, err)
+
+	require.NoError(t, p1.Publish(topic, message.NewMessage(watermill.NewUUID(), []byte("msg1"))))
+
+	msg := receive(t, msgChan1)
+	require.Equal(t, "msg1", string(msg.Payload))
+	msg.Ack()
+
+	msg = receive(t, underlyingChan)
+	require.Equal(t, "msg1", string(msg.Payload))
+	msg.Ack()
+
+	select {
+	case <-msgChan2:
+		t.Fatal("tenant2 should not receive messages published by tenant1")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// Closing a tenant's publisher/subscriber doesn't close the shared publisher/subscriber.
+	require.NoError(t, p1.Close())
+	require.NoError(t, p2.Publish(topic, message.NewMessage(watermill.NewUUID(), []byte("msg2"))))
+
+	msg = receive(t, msgChan2)
+	require.Equal(t, "msg2", string(msg.Payload))
+	msg.Ack()
+}
+
+func receive(t *testing.T, msgChan <-chan *message.Message) *message.Message {
+	t.Helper()
+
+	select {
+	case msg := <-msgChan:
+		return msg
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for message")
+	}
+
+	return nil
+}
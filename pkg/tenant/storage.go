@@ -0,0 +1,79 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tenant
+
+import (
+	"sync"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+// StoreProvider wraps a storage provider and isolates the stores of a tenant by prefixing each store name
+// with the tenant ID.
+type StoreProvider struct {
+	provider storage.Provider
+	tenantID string
+
+	mutex  sync.RWMutex
+	stores map[string]storage.Store
+}
+
+// NewStoreProvider returns a new storage provider for the given tenant.
+func NewStoreProvider(provider storage.Provider, tenantID string) *StoreProvider {
+	return &StoreProvider{
+		provider: provider,
+		tenantID: tenantID,
+		stores:   make(map[string]storage.Store),
+	}
+}
+
+// OpenStore opens the tenant's store with the given name.
+func (p *StoreProvider) OpenStore(name string) (storage.Store, error) {
+	s, err := p.provider.OpenStore(p.storeName(name))
+	if err != nil {
+		return nil, err
+	}
+
+	p.mutex.Lock()
+	p.stores[name] = s
+	p.mutex.Unlock()
+
+	return s, nil
+}
+
+// SetStoreConfig sets the configuration of the tenant's store with the given name.
+func (p *StoreProvider) SetStoreConfig(name string, config storage.StoreConfiguration) error {
+	return p.provider.SetStoreConfig(p.storeName(name), config)
+}
+
+// GetStoreConfig returns the configuration of the tenant's store with the given name.
+func (p *StoreProvider) GetStoreConfig(name string) (storage.StoreConfiguration, error) {
+	return p.provider.GetStoreConfig(p.storeName(name))
+}
+
+// GetOpenStores returns the tenant's stores that are currently open.
+func (p *StoreProvider) GetOpenStores() []storage.Store {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	stores := make([]storage.Store, 0, len(p.stores))
+
+	for _, s := range p.stores {
+		stores = append(stores, s)
+	}
+
+	return stores
+}
+
+// Close does nothing since the underlying provider is shared by all tenants and is closed by its owner.
+func (p *StoreProvider) Close() error {
+	return nil
+}
+
+func (p *StoreProvider) storeName(name string) string {
+	return p.tenantID + "_" + name
+}
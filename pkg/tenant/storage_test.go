@@ -0,0 +1,86 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tenant
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	spi "github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreProvider(t *testing.T) {
+	t.Run("Isolation", func(t *testing.T) {
+		p := mem.NewProvider()
+
+		p1 := NewStoreProvider(p, "tenant1")
+		p2 := NewStoreProvider(p, "tenant2")
+
+		s1, err := p1.OpenStore("config")
+		require.NoError(t, err)
+
+		s2, err := p2.OpenStore("config")
+		require.NoError(t, err)
+
+		require.NoError(t, s1.Put("key", []byte("value1")))
+		require.NoError(t, s2.Put("key", []byte("value2")))
+
+		v, err := s1.Get("key")
+		require.NoError(t, err)
+		require.Equal(t, "value1", string(v))
+
+		v, err = s2.Get("key")
+		require.NoError(t, err)
+		require.Equal(t, "value2", string(v))
+
+		s, err := p.OpenStore("tenant1_config")
+		require.NoError(t, err)
+
+		v, err = s.Get("key")
+		require.NoError(t, err)
+		require.Equal(t, "value1", string(v))
+
+		require.Len(t, p1.GetOpenStores(), 1)
+		require.Len(t, p2.GetOpenStores(), 1)
+	})
+
+	t.Run("Store config", func(t *testing.T) {
+		p := mem.NewProvider()
+
+		p1 := NewStoreProvider(p, "tenant1")
+
+		_, err := p1.OpenStore("config")
+		require.NoError(t, err)
+
+		require.NoError(t, p1.SetStoreConfig("config", spi.StoreConfiguration{TagNames: []string{"tag1"}}))
+
+		cfg, err := p1.GetStoreConfig("config")
+		require.NoError(t, err)
+		require.Equal(t, []string{"tag1"}, cfg.TagNames)
+
+		cfg, err = p.GetStoreConfig("tenant1_config")
+		require.NoError(t, err)
+		require.Equal(t, []string{"tag1"}, cfg.TagNames)
+	})
+
+	t.Run("Open store error", func(t *testing.T) {
+		errExpected := errors.New("injected open store error")
+
+		p := storage.NewMockStoreProvider()
+		p.ErrOpenStoreHandle = errExpected
+
+		_, err := NewStoreProvider(p, "tenant1").OpenStore("config")
+		require.True(t, errors.Is(err, errExpected))
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		require.NoError(t, NewStoreProvider(mem.NewProvider(), "tenant1").Close())
+	})
+}
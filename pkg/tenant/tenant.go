@@ -0,0 +1,150 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tenant
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// idPattern restricts tenant IDs to characters that are safe to use in database and queue names.
+var idPattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`)
+
+// Config contains the configuration of a tenant (a logical Orb service hosted within the process).
+type Config struct {
+	// ID uniquely identifies the tenant. The ID is used to namespace the tenant's stores and message queue topics.
+	ID string `yaml:"id" json:"id"`
+	// Host is the host name (from the Host header) that selects the tenant. Either Host or BasePath
+	// (or both) must be set.
+	Host string `yaml:"host" json:"host,omitempty"`
+	// BasePath is the path prefix that selects the tenant. The prefix is removed from the request path before
+	// the request is handled so, for example, a request for [/tenant1/services/orb] is handled as
+	// [/services/orb] by tenant1.
+	BasePath string `yaml:"basePath" json:"basePath,omitempty"`
+	// ExternalEndpoint is the external URL of the tenant's service from which the service IRIs are derived.
+	ExternalEndpoint string `yaml:"externalEndpoint" json:"externalEndpoint"`
+	// KeyID is the ID of the tenant's signing key. If not set then a key is generated in the tenant's key store.
+	KeyID string `yaml:"keyId" json:"keyId,omitempty"`
+	// PrivateKey is the (base64-encoded) private key that is imported under KeyID.
+	PrivateKey string `yaml:"privateKey" json:"-"`
+	// FollowAuthPolicy overrides the server's follow authorization policy for the tenant.
+	FollowAuthPolicy string `yaml:"followAuthPolicy" json:"followAuthPolicy,omitempty"`
+	// InviteWitnessAuthPolicy overrides the server's invite witness authorization policy for the tenant.
+	InviteWitnessAuthPolicy string `yaml:"inviteWitnessAuthPolicy" json:"inviteWitnessAuthPolicy,omitempty"`
+	// FollowAcceptList contains the service IRIs that are added to the tenant's 'follow' accept list on startup.
+	FollowAcceptList []string `yaml:"followAcceptList" json:"followAcceptList,omitempty"`
+	// InviteWitnessAcceptList contains the service IRIs that are added to the tenant's 'invite-witness' accept
+	// list on startup.
+	InviteWitnessAcceptList []string `yaml:"inviteWitnessAcceptList" json:"inviteWitnessAcceptList,omitempty"`
+}
+
+type tenantsFile struct {
+	Tenants []*Config `yaml:"tenants"`
+}
+
+// LoadConfig loads and validates the tenant configurations from the given YAML file.
+func LoadConfig(path string) ([]*Config, error) {
+	configBytes, err := ioutil.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("read tenants file [%s]: %w", path, err)
+	}
+
+	return ParseConfig(configBytes)
+}
+
+// ParseConfig parses and validates the tenant configurations in the given YAML document.
+func ParseConfig(configBytes []byte) ([]*Config, error) {
+	f := &tenantsFile{}
+
+	err := yaml.UnmarshalStrict(configBytes, f)
+	if err != nil {
+		return nil, fmt.Errorf("parse tenants: %w", err)
+	}
+
+	if len(f.Tenants) == 0 {
+		return nil, errors.New("no tenants are defined")
+	}
+
+	err = validate(f.Tenants)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.Tenants, nil
+}
+
+func validate(tenants []*Config) error {
+	ids := make(map[string]struct{})
+	selectors := make(map[string]string)
+
+	for _, t := range tenants {
+		err := t.validate()
+		if err != nil {
+			return err
+		}
+
+		if _, exists := ids[t.ID]; exists {
+			return fmt.Errorf("duplicate tenant ID [%s]", t.ID)
+		}
+
+		ids[t.ID] = struct{}{}
+
+		selector := t.Host + t.BasePath
+
+		if other, exists := selectors[selector]; exists {
+			return fmt.Errorf("tenants [%s] and [%s] have the same host and base path", other, t.ID)
+		}
+
+		selectors[selector] = t.ID
+	}
+
+	return nil
+}
+
+func (t *Config) validate() error {
+	if !idPattern.MatchString(t.ID) {
+		return fmt.Errorf("invalid tenant ID [%s]: the ID must contain only letters, digits, '-' and '_'", t.ID)
+	}
+
+	if t.Host == "" && t.BasePath == "" {
+		return fmt.Errorf("tenant [%s]: either host or basePath must be set", t.ID)
+	}
+
+	if t.BasePath != "" && (!strings.HasPrefix(t.BasePath, "/") || strings.HasSuffix(t.BasePath, "/")) {
+		return fmt.Errorf("tenant [%s]: basePath [%s] must begin with '/' and must not end with '/'",
+			t.ID, t.BasePath)
+	}
+
+	if t.ExternalEndpoint == "" {
+		return fmt.Errorf("tenant [%s]: externalEndpoint must be set", t.ID)
+	}
+
+	_, err := url.ParseRequestURI(t.ExternalEndpoint)
+	if err != nil {
+		return fmt.Errorf("tenant [%s]: invalid externalEndpoint: %w", t.ID, err)
+	}
+
+	if t.PrivateKey != "" && t.KeyID == "" {
+		return fmt.Errorf("tenant [%s]: keyId must be set when privateKey is set", t.ID)
+	}
+
+	for _, uri := range append(append([]string{}, t.FollowAcceptList...), t.InviteWitnessAcceptList...) {
+		_, err = url.ParseRequestURI(uri)
+		if err != nil {
+			return fmt.Errorf("tenant [%s]: invalid accept list URI [%s]: %w", t.ID, uri, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tenant
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const tenantsYAML = `
+tenants:
+  - id: tenant1
+    host: tenant1.example.com
+    externalEndpoint: https://tenant1.example.com
+    followAuthPolicy: accept-list
+    followAcceptList:
+      - https://orb.domain1.com/services/orb
+  - id: tenant2
+    basePath: /tenant2
+    externalEndpoint: https://tenant2.example.com
+    keyId: key2
+    privateKey: cHJpdmF0ZS1rZXk
+`
+
+func TestLoadConfig(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "tenants.yaml")
+
+		require.NoError(t, ioutil.WriteFile(path, []byte(tenantsYAML), 0600))
+
+		tenants, err := LoadConfig(path)
+		require.NoError(t, err)
+		require.Len(t, tenants, 2)
+
+		require.Equal(t, "tenant1", tenants[0].ID)
+		require.Equal(t, "tenant1.example.com", tenants[0].Host)
+		require.Empty(t, tenants[0].BasePath)
+		require.Equal(t, "https://tenant1.example.com", tenants[0].ExternalEndpoint)
+		require.Equal(t, "accept-list", tenants[0].FollowAuthPolicy)
+		require.Equal(t, []string{"https://orb.domain1.com/services/orb"}, tenants[0].FollowAcceptList)
+
+		require.Equal(t, "tenant2", tenants[1].ID)
+		require.Equal(t, "/tenant2", tenants[1].BasePath)
+		require.Equal(t, "key2", tenants[1].KeyID)
+		require.Equal(t, "cHJpdmF0ZS1rZXk", tenants[1].PrivateKey)
+	})
+
+	t.Run("File not found", func(t *testing.T) {
+		_, err := LoadConfig("./invalid/tenants.yaml")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "read tenants file")
+	})
+}
+
+func TestParseConfig(t *testing.T) {
+	t.Run("Invalid YAML", func(t *testing.T) {
+		_, err := ParseConfig([]byte("tenants: {"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "parse tenants")
+	})
+
+	t.Run("Unknown field", func(t *testing.T) {
+		_, err := ParseConfig([]byte(`
+tenants:
+  - id: tenant1
+    hostname: tenant1.example.com
+`))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "parse tenants")
+	})
+
+	t.Run("No tenants", func(t *testing.T) {
+		_, err := ParseConfig([]byte("tenants: []"))
+		require.EqualError(t, err, "no tenants are defined")
+	})
+
+	t.Run("Validation errors", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			config string
+			err    string
+		}{
+			{
+				name:   "Invalid ID",
+				config: "tenants: [{id: 'tenant 1', host: h1, externalEndpoint: 'https://h1'}]",
+				err:    "invalid tenant ID [tenant 1]",
+			},
+			{
+				name:   "No host or base path",
+				config: "tenants: [{id: tenant1, externalEndpoint: 'https://h1'}]",
+				err:    "either host or basePath must be set",
+			},
+			{
+				name:   "Invalid base path",
+				config: "tenants: [{id: tenant1, basePath: 'tenant1/', externalEndpoint: 'https://h1'}]",
+				err:    "must begin with '/' and must not end with '/'",
+			},
+			{
+				name:   "No external endpoint",
+				config: "tenants: [{id: tenant1, host: h1}]",
+				err:    "externalEndpoint must be set",
+			},
+			{
+				name:   "Invalid external endpoint",
+				config: "tenants: [{id: tenant1, host: h1, externalEndpoint: 'h1'}]",
+				err:    "invalid externalEndpoint",
+			},
+			{
+				name:   "Private key without key ID",
+				config: "tenants: [{id: tenant1, host: h1, externalEndpoint: 'https://h1', privateKey: xxx}]",
+				err:    "keyId must be set when privateKey is set",
+			},
+			{
+				name: "Invalid accept list URI",
+				config: "tenants: [{id: tenant1, host: h1, externalEndpoint: 'https://h1', " +
+					"inviteWitnessAcceptList: [':invalid']}]",
+				err: "invalid accept list URI",
+			},
+			{
+				name: "Duplicate ID",
+				config: "tenants: [{id: tenant1, host: h1, externalEndpoint: 'https://h1'}, " +
+					"{id: tenant1, host: h2, externalEndpoint: 'https://h2'}]",
+				err: "duplicate tenant ID [tenant1]",
+			},
+			{
+				name: "Duplicate selector",
+				config: "tenants: [{id: tenant1, basePath: /t1, externalEndpoint: 'https://h1'}, " +
+					"{id: tenant2, basePath: /t1, externalEndpoint: 'https://h2'}]",
+				err: "tenants [tenant1] and [tenant2] have the same host and base path",
+			},
+		}
+
+		for _, test := range tests {
+			tc := test
+
+			t.Run(tc.name, func(t *testing.T) {
+				_, err := ParseConfig([]byte(tc.config))
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.err)
+			})
+		}
+	})
+}
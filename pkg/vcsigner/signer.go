@@ -12,14 +12,12 @@ import (
 	"strings"
 	"time"
 
-	ariescrypto "github.com/hyperledger/aries-framework-go/pkg/crypto"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/jsonld"
 	ariessigner "github.com/hyperledger/aries-framework-go/pkg/doc/signature/signer"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite/ed25519signature2018"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite/jsonwebsignature2020"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
-	"github.com/hyperledger/aries-framework-go/pkg/kms"
 	"github.com/piprate/json-gold/ld"
 )
 
@@ -39,6 +37,17 @@ type metricsProvider interface {
 	SignerAddLinkedDataProof(value time.Duration)
 }
 
+// keyManager returns a handle to a signing key. It is implemented by the Aries KMS as well as
+// by the external KMS backends (AWS KMS and Vault Transit).
+type keyManager interface {
+	Get(keyID string) (interface{}, error)
+}
+
+// signingCrypto signs data using a key handle returned by the keyManager.
+type signingCrypto interface {
+	Sign(msg []byte, kh interface{}) ([]byte, error)
+}
+
 // SigningParams contains required parameters for signing anchored credential.
 type SigningParams struct {
 	VerificationMethod string
@@ -49,8 +58,8 @@ type SigningParams struct {
 // Providers contains all of the providers required by verifiable credential signer.
 type Providers struct {
 	DocLoader  ld.DocumentLoader
-	KeyManager kms.KeyManager
-	Crypto     ariescrypto.Crypto
+	KeyManager keyManager
+	Crypto     signingCrypto
 	Metrics    metricsProvider
 }
 
@@ -197,11 +206,11 @@ type signer interface {
 
 type kmsSigner struct {
 	keyHandle interface{}
-	crypto    ariescrypto.Crypto
+	crypto    signingCrypto
 	metrics   metricsProvider
 }
 
-func newKMSSigner(keyManager kms.KeyManager, c ariescrypto.Crypto, verificationMethod string,
+func newKMSSigner(km keyManager, c signingCrypto, verificationMethod string,
 	metrics metricsProvider) (*kmsSigner, error) {
 	// verification will contain did key ID
 	keyID, err := getKeyIDFromVerificationMethod(verificationMethod)
@@ -211,7 +220,7 @@ func newKMSSigner(keyManager kms.KeyManager, c ariescrypto.Crypto, verificationM
 
 	getKeyStartTime := time.Now()
 
-	keyHandler, err := keyManager.Get(keyID)
+	keyHandler, err := km.Get(keyID)
 	if err != nil {
 		return nil, err
 	}
@@ -6,7 +6,11 @@ SPDX-License-Identifier: Apache-2.0
 
 package anchororigin
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/trustbloc/orb/pkg/document/util"
+)
 
 // New creates anchor origin validator.
 func New(allowed []string) *Validator {
@@ -24,24 +28,24 @@ func (v *Validator) Validate(obj interface{}) error {
 		return fmt.Errorf("anchor origin must be specified")
 	}
 
+	// The anchor origin may be a single origin or a list of origins (the primary followed by fallbacks),
+	// all of which must be allowed.
+	anchorOrigins, err := util.ParseAnchorOrigins(obj)
+	if err != nil {
+		return err
+	}
+
 	// if allowed origins contains wild-card '*' any origin is allowed
 	_, ok := v.allowed["*"]
 	if ok {
 		return nil
 	}
 
-	var val string
-
-	switch t := obj.(type) {
-	case string:
-		val, _ = obj.(string) // nolint: errcheck
-	default:
-		return fmt.Errorf("anchor origin type not supported %T", t)
-	}
-
-	_, ok = v.allowed[val]
-	if !ok {
-		return fmt.Errorf("origin %s is not supported", val)
+	for _, val := range anchorOrigins {
+		_, ok = v.allowed[val]
+		if !ok {
+			return fmt.Errorf("origin %s is not supported", val)
+		}
 	}
 
 	return nil
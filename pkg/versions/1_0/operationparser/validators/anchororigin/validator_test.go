@@ -38,4 +38,29 @@ func TestValidator_Validate(t *testing.T) {
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "origin not-allowed is not supported")
 	})
+
+	t.Run("success - primary and fallback origins allowed", func(t *testing.T) {
+		validator := New([]string{"allowed", "fallback"})
+
+		err := validator.Validate([]interface{}{"allowed", "fallback"})
+		require.NoError(t, err)
+	})
+
+	t.Run("error - fallback origin not in the allowed list", func(t *testing.T) {
+		validator := New([]string{"allowed"})
+
+		err := validator.Validate([]interface{}{"allowed", "not-allowed"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "origin not-allowed is not supported")
+	})
+
+	t.Run("error - anchor origin type not supported", func(t *testing.T) {
+		err := v.Validate(123)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "anchor origin type not supported int")
+
+		err = v.Validate([]interface{}{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "anchor origin list is empty")
+	})
 }
@@ -9,6 +9,7 @@ package txnprocessor
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/trustbloc/edge-core/pkg/log"
 	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
@@ -20,6 +21,18 @@ import (
 
 var logger = log.New("orb-txn-processor")
 
+const (
+	// StageParseOperations is the processing stage in which the Sidetree batch files of the transaction
+	// are retrieved and the operations are parsed.
+	StageParseOperations = "parse_operations"
+
+	// StageStoreOperations is the processing stage in which the operations are persisted to the operation store.
+	StageStoreOperations = "store_operations"
+)
+
+// StageObserver is notified of the time spent in a stage of processing a transaction.
+type StageObserver func(stage string, value time.Duration)
+
 // Providers contains the providers required by the TxnProcessor.
 type Providers struct {
 	OpStore                   common.OperationStore
@@ -69,18 +82,38 @@ func WithUnpublishedOperationStore(store unpublishedOperationStore, opTypes []op
 
 // Process persists all of the operations for the given anchor.
 func (p *TxnProcessor) Process(sidetreeTxn txn.SidetreeTxn, suffixes ...string) error { //nolint:gocritic
+	return p.ProcessWithStages(sidetreeTxn, func(string, time.Duration) {}, suffixes...)
+}
+
+// ProcessWithStages persists all of the operations for the given anchor and notifies the given observer
+// of the time spent in each stage of processing.
+func (p *TxnProcessor) ProcessWithStages(sidetreeTxn txn.SidetreeTxn, //nolint:gocritic
+	observe StageObserver, suffixes ...string) error {
 	logger.Debugf("processing sidetree txn:%+v", sidetreeTxn)
 
+	startTime := time.Now()
+
 	txnOps, err := p.OperationProtocolProvider.GetTxnOperations(&sidetreeTxn)
 	if err != nil {
 		return fmt.Errorf("failed to retrieve operations for anchor string[%s]: %w", sidetreeTxn.AnchorString, err)
 	}
 
+	observe(StageParseOperations, time.Since(startTime))
+
 	if len(suffixes) > 0 {
 		txnOps = filterOps(txnOps, suffixes)
 	}
 
-	return p.processTxnOperations(txnOps, &sidetreeTxn)
+	startTime = time.Now()
+
+	err = p.processTxnOperations(txnOps, &sidetreeTxn)
+	if err != nil {
+		return err
+	}
+
+	observe(StageStoreOperations, time.Since(startTime))
+
+	return nil
 }
 
 func filterOps(txnOps []*operation.AnchoredOperation, suffixes []string) []*operation.AnchoredOperation {
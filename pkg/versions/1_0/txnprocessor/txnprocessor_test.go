@@ -9,6 +9,7 @@ package txnprocessor
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
@@ -74,6 +75,44 @@ func TestTxnProcessor_Process(t *testing.T) {
 	})
 }
 
+func TestTxnProcessor_ProcessWithStages(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		providers := &Providers{
+			OpStore:                   &mockOperationStore{},
+			OperationProtocolProvider: &mockTxnOpsProvider{},
+		}
+
+		var stages []string
+
+		p := New(providers)
+		err := p.ProcessWithStages(txn.SidetreeTxn{AnchorString: anchorString},
+			func(stage string, value time.Duration) {
+				stages = append(stages, stage)
+			},
+		)
+		require.NoError(t, err)
+		require.Equal(t, []string{StageParseOperations, StageStoreOperations}, stages)
+	})
+
+	t.Run("error - error from txn operations provider", func(t *testing.T) {
+		providers := &Providers{
+			OpStore:                   &mockOperationStore{},
+			OperationProtocolProvider: &mockTxnOpsProvider{err: fmt.Errorf("txn operations provider error")},
+		}
+
+		var stages []string
+
+		p := New(providers)
+		err := p.ProcessWithStages(txn.SidetreeTxn{},
+			func(stage string, value time.Duration) {
+				stages = append(stages, stage)
+			},
+		)
+		require.Error(t, err)
+		require.Empty(t, stages)
+	})
+}
+
 func TestProcessTxnOperations(t *testing.T) {
 	t.Run("test error from operationStore Put", func(t *testing.T) {
 		providers := &Providers{
@@ -7,10 +7,13 @@ SPDX-License-Identifier: Apache-2.0
 package webcas
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 
 	"github.com/gorilla/mux"
 	"github.com/trustbloc/edge-core/pkg/log"
@@ -19,10 +22,16 @@ import (
 
 	"github.com/trustbloc/orb/pkg/activitypub/resthandler"
 	"github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/anchor/linksetsig"
 	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/linkset"
 )
 
-const cidPathVariable = "cid"
+const (
+	cidPathVariable = "cid"
+	linksetProperty = "linkset"
+)
 
 type logger interface {
 	Errorf(msg string, args ...interface{})
@@ -34,12 +43,28 @@ type signatureVerifier interface {
 	VerifyRequest(req *http.Request) (bool, *url.URL, error)
 }
 
+type linksetSignatureProvider interface {
+	Get(resourceHash string) (string, error)
+}
+
 // WebCAS represents a WebCAS handler + client for the backing CAS.
 type WebCAS struct {
 	*resthandler.AuthHandler
 
-	casClient casapi.Client
-	logger    logger
+	casClient  casapi.Client
+	logger     logger
+	signatures linksetSignatureProvider
+}
+
+// Option is a WebCAS option.
+type Option func(w *WebCAS)
+
+// WithLinksetSignatures sets the provider of the detached JWS signatures of the content. If a signature exists
+// for the requested content then it's returned in the linksetsig.HeaderName response header.
+func WithLinksetSignatures(provider linksetSignatureProvider) Option {
+	return func(w *WebCAS) {
+		w.signatures = provider
+	}
 }
 
 // Path returns the HTTP REST endpoint for the WebCAS service.
@@ -63,13 +88,22 @@ type authTokenManager interface {
 
 // New returns a new WebCAS, which contains a REST handler that implements WebCAS as defined in
 // https://trustbloc.github.io/did-method-orb/#webcas.
+//
+// By default, the content is returned exactly as it's stored in the CAS so that the client may verify its hash.
+// If the client prefers application/linkset+json or application/linkset (RFC 9264) and the content is an anchor
+// event (or a JSON linkset) then the linkset representation of the anchor is returned, which allows non-Orb
+// consumers to follow the anchor graph.
 func New(authCfg *resthandler.Config, s spi.Store, verifier signatureVerifier,
-	casClient casapi.Client, tm authTokenManager) *WebCAS {
+	casClient casapi.Client, tm authTokenManager, opts ...Option) *WebCAS {
 	h := &WebCAS{
 		casClient: casClient,
 		logger:    log.New("webcas"),
 	}
 
+	for _, opt := range opts {
+		opt(h)
+	}
+
 	h.AuthHandler = resthandler.NewAuthHandler(authCfg, "/cas/{%s}", http.MethodGet, s, verifier, tm,
 		func(actorIRI *url.URL) (bool, error) {
 			// TODO: Does the actor need to be authorized? If so, how? A witness needs access to the /cas endpoint
@@ -140,8 +174,139 @@ func (w *WebCAS) handler(rw http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	if contentType := getContentType(req.Header.Get("Accept")); contentType != "" {
+		content, err = toLinkset(content, contentType)
+		if err != nil {
+			w.logger.Debugf("Content at %s can't be represented as %s: %s", cid, contentType, err)
+
+			rw.WriteHeader(http.StatusNotAcceptable)
+
+			_, errWrite := rw.Write([]byte(fmt.Sprintf("content at %s can't be represented as %s", cid, contentType)))
+			if errWrite != nil {
+				w.logger.Errorf("failed to write error response: %s", errWrite.Error())
+			}
+
+			return
+		}
+
+		rw.Header().Set("Content-Type", contentType)
+	} else {
+		w.setSignatureHeader(rw, cid)
+	}
+
 	_, err = rw.Write(content)
 	if err != nil {
 		w.logger.Errorf("failed to write success response: %s", err.Error())
 	}
 }
+
+// setSignatureHeader sets the detached JWS of the content (if any) in the response. The signature is only
+// returned if the content is returned as is since it's computed over the stored bytes.
+func (w *WebCAS) setSignatureHeader(rw http.ResponseWriter, cid string) {
+	if w.signatures == nil {
+		return
+	}
+
+	jws, err := w.signatures.Get(cid)
+	if err != nil {
+		w.logger.Errorf("Error retrieving linkset signature of %s: %s", cid, err)
+
+		return
+	}
+
+	if jws != "" {
+		rw.Header().Set(linksetsig.HeaderName, jws)
+	}
+}
+
+// getContentType returns the linkset content type if the client prefers one of the linkset formats
+// (according to the order and quality values of the media ranges in the Accept header). An empty
+// string is returned if the content should be returned as is.
+func getContentType(accept string) string {
+	var (
+		contentType string
+		maxQuality  float64
+	)
+
+	for _, mediaRange := range strings.Split(accept, ",") {
+		parts := strings.Split(mediaRange, ";")
+
+		mediaType := strings.ToLower(strings.TrimSpace(parts[0]))
+		if mediaType == "" {
+			continue
+		}
+
+		quality := 1.0
+
+		for _, param := range parts[1:] {
+			kv := strings.SplitN(strings.TrimSpace(param), "=", 2) //nolint:gomnd
+
+			if len(kv) == 2 && strings.TrimSpace(kv[0]) == "q" {
+				if q, e := strconv.ParseFloat(strings.TrimSpace(kv[1]), 64); e == nil {
+					quality = q
+				}
+			}
+		}
+
+		if quality <= maxQuality {
+			continue
+		}
+
+		maxQuality = quality
+
+		switch mediaType {
+		case linkset.ContentTypeJSON, linkset.ContentTypeNative:
+			contentType = mediaType
+		default:
+			contentType = ""
+		}
+	}
+
+	return contentType
+}
+
+// toLinkset converts the given content (which must either be a JSON linkset or an anchor event) to a linkset
+// with the given content type.
+func toLinkset(content []byte, contentType string) ([]byte, error) {
+	obj := make(map[string]json.RawMessage)
+
+	err := json.Unmarshal(content, &obj)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal content: %w", err)
+	}
+
+	ls := &linkset.Linkset{}
+
+	if _, ok := obj[linksetProperty]; ok {
+		if contentType == linkset.ContentTypeJSON {
+			return content, nil
+		}
+
+		err = json.Unmarshal(content, ls)
+		if err != nil {
+			return nil, fmt.Errorf("unmarshal linkset: %w", err)
+		}
+	} else {
+		anchorEvent := &vocab.AnchorEventType{}
+
+		err = json.Unmarshal(content, anchorEvent)
+		if err != nil {
+			return nil, fmt.Errorf("unmarshal anchor event: %w", err)
+		}
+
+		if !anchorEvent.Type().Is(vocab.TypeAnchorEvent) {
+			return nil, fmt.Errorf("unsupported content type [%s]", anchorEvent.Type())
+		}
+
+		ls, err = linkset.FromAnchorEvent(anchorEvent)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if contentType == linkset.ContentTypeNative {
+		return ls.MarshalNative(), nil
+	}
+
+	return json.Marshal(ls)
+}
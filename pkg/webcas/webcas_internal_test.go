@@ -22,6 +22,7 @@ import (
 	"github.com/trustbloc/orb/pkg/activitypub/resthandler"
 	"github.com/trustbloc/orb/pkg/activitypub/service/mocks"
 	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
+	"github.com/trustbloc/orb/pkg/linkset"
 	orbmocks "github.com/trustbloc/orb/pkg/mocks"
 	"github.com/trustbloc/orb/pkg/store/cas"
 )
@@ -119,3 +120,45 @@ func TestWriteResponseFailures(t *testing.T) {
 		require.Equal(t, "failed to write success response: response write failure", testLogger.log)
 	})
 }
+
+func TestGetContentType(t *testing.T) {
+	for accept, contentType := range map[string]string{
+		"":                         "",
+		"*/*":                      "",
+		"application/ld+json":      "",
+		"application/linkset+json": "application/linkset+json",
+		"Application/Linkset":      "application/linkset",
+		"application/linkset;q=0":  "",
+		"text/plain, application/linkset+json;q=0.5":                     "",
+		"application/json;q=0.5, application/linkset+json;q=xxx":         "application/linkset+json",
+		", application/linkset; q = 0.7, application/linkset+json;q=0.7": "application/linkset",
+	} {
+		require.Equal(t, contentType, getContentType(accept), accept)
+	}
+}
+
+func TestToLinkset(t *testing.T) {
+	t.Run("Invalid content", func(t *testing.T) {
+		_, err := toLinkset([]byte("not JSON"), linkset.ContentTypeJSON)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal content")
+	})
+
+	t.Run("Invalid linkset", func(t *testing.T) {
+		_, err := toLinkset([]byte(`{"linkset":{}}`), linkset.ContentTypeNative)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal linkset")
+	})
+
+	t.Run("Invalid anchor event", func(t *testing.T) {
+		_, err := toLinkset([]byte(`{"type":1}`), linkset.ContentTypeJSON)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal anchor event")
+	})
+
+	t.Run("Anchor event without index", func(t *testing.T) {
+		_, err := toLinkset([]byte(`{"type":"AnchorEvent","url":"hl:uEiDzUEQi2qRreCTfvp2AKmTaxuqUUZZNhbxe5RTBH59AWw"}`),
+			linkset.ContentTypeJSON)
+		require.EqualError(t, err, "anchor event has no index")
+	})
+}
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package webcas_test
 
 import (
+	"encoding/json"
 	"errors"
 	"io/ioutil"
 	"net/http"
@@ -21,8 +22,11 @@ import (
 	"github.com/trustbloc/orb/pkg/activitypub/resthandler"
 	"github.com/trustbloc/orb/pkg/activitypub/service/mocks"
 	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/anchor/linksetsig"
 	"github.com/trustbloc/orb/pkg/hashlink"
 	"github.com/trustbloc/orb/pkg/internal/testutil"
+	"github.com/trustbloc/orb/pkg/linkset"
 	orbmocks "github.com/trustbloc/orb/pkg/mocks"
 	"github.com/trustbloc/orb/pkg/store/cas"
 	"github.com/trustbloc/orb/pkg/webcas"
@@ -117,6 +121,45 @@ func TestHandler(t *testing.T) {
 
 		require.Equal(t, http.StatusOK, response.StatusCode)
 		require.Equal(t, sampleAnchorCredential, string(responseBody))
+		require.Empty(t, response.Header.Get(linksetsig.HeaderName))
+	})
+	t.Run("Content found with linkset signature", func(t *testing.T) {
+		casClient, err := cas.New(mem.NewProvider(), casLink, nil, &orbmocks.MetricsProvider{}, 0)
+		require.NoError(t, err)
+
+		hl, err := casClient.Write([]byte(sampleAnchorCredential))
+		require.NoError(t, err)
+
+		rh, err := hashlink.GetResourceHashFromHashLink(hl)
+		require.NoError(t, err)
+
+		signatures := &mockSignatureProvider{signatures: map[string]string{rh: "eyJhbGciOiJFZERTQSJ9..c2ln"}}
+
+		webCAS := webcas.New(&resthandler.Config{}, memstore.New(""), &mocks.SignatureVerifier{}, casClient,
+			&apmocks.AuthTokenMgr{}, webcas.WithLinksetSignatures(signatures))
+
+		router := mux.NewRouter()
+
+		router.HandleFunc(webCAS.Path(), webCAS.Handler())
+
+		testServer := httptest.NewServer(router)
+		defer testServer.Close()
+
+		response, err := http.DefaultClient.Get(testServer.URL + "/cas/" + rh)
+		require.NoError(t, err)
+		require.NoError(t, response.Body.Close())
+
+		require.Equal(t, http.StatusOK, response.StatusCode)
+		require.Equal(t, "eyJhbGciOiJFZERTQSJ9..c2ln", response.Header.Get(linksetsig.HeaderName))
+
+		signatures.err = errors.New("injected signature error")
+
+		response, err = http.DefaultClient.Get(testServer.URL + "/cas/" + rh)
+		require.NoError(t, err)
+		require.NoError(t, response.Body.Close())
+
+		require.Equal(t, http.StatusOK, response.StatusCode)
+		require.Empty(t, response.Header.Get(linksetsig.HeaderName))
 	})
 	t.Run("Content not found", func(t *testing.T) {
 		casClient, err := cas.New(mem.NewProvider(), casLink, nil, &orbmocks.MetricsProvider{}, 0)
@@ -148,6 +191,116 @@ func TestHandler(t *testing.T) {
 			"content not found", string(responseBody))
 	})
 
+	t.Run("Linkset", func(t *testing.T) {
+		casClient, err := cas.New(mem.NewProvider(), casLink, nil, &orbmocks.MetricsProvider{}, 0)
+		require.NoError(t, err)
+
+		anchorObj, err := vocab.NewAnchorObject("https://w3id.org/orb#v0", vocab.Document{"field1": "value1"})
+		require.NoError(t, err)
+
+		anchorEventBytes, err := json.Marshal(vocab.NewAnchorEvent(
+			vocab.WithAttributedTo(testutil.MustParseURL("https://orb.domain1.com/services/orb")),
+			vocab.WithIndex(anchorObj.URL()[0]),
+			vocab.WithAttachment(vocab.NewObjectProperty(vocab.WithAnchorObject(anchorObj))),
+		))
+		require.NoError(t, err)
+
+		anchorEventHL, err := casClient.Write(anchorEventBytes)
+		require.NoError(t, err)
+
+		linksetBytes := []byte(`{"linkset":[{"anchor":"hl:uEiDzUEQi2qRreCTfvp2AKmTaxuqUUZZNhbxe5RTBH59AWw",` +
+			`"author":[{"href":"https://orb.domain1.com/services/orb"}]}]}`)
+
+		linksetHL, err := casClient.Write(linksetBytes)
+		require.NoError(t, err)
+
+		vcHL, err := casClient.Write([]byte(sampleAnchorCredential))
+		require.NoError(t, err)
+
+		webCAS := webcas.New(&resthandler.Config{}, memstore.New(""), &mocks.SignatureVerifier{}, casClient,
+			&apmocks.AuthTokenMgr{})
+		require.NotNil(t, webCAS)
+
+		router := mux.NewRouter()
+
+		router.HandleFunc(webCAS.Path(), webCAS.Handler())
+
+		testServer := httptest.NewServer(router)
+		defer testServer.Close()
+
+		get := func(hl, accept string) (int, string, string) {
+			rh, e := hashlink.GetResourceHashFromHashLink(hl)
+			require.NoError(t, e)
+
+			req, e := http.NewRequest(http.MethodGet, testServer.URL+"/cas/"+rh, nil)
+			require.NoError(t, e)
+
+			req.Header.Set("Accept", accept)
+
+			response, e := http.DefaultClient.Do(req)
+			require.NoError(t, e)
+
+			defer func() {
+				require.NoError(t, response.Body.Close())
+			}()
+
+			responseBody, e := ioutil.ReadAll(response.Body)
+			require.NoError(t, e)
+
+			return response.StatusCode, response.Header.Get("Content-Type"), string(responseBody)
+		}
+
+		t.Run("Anchor event as is", func(t *testing.T) {
+			status, _, body := get(anchorEventHL, "application/ld+json, application/linkset+json;q=0.9")
+			require.Equal(t, http.StatusOK, status)
+			require.Equal(t, string(anchorEventBytes), body)
+		})
+
+		t.Run("Anchor event as JSON linkset", func(t *testing.T) {
+			status, contentType, body := get(anchorEventHL, "application/linkset+json")
+			require.Equal(t, http.StatusOK, status)
+			require.Equal(t, linkset.ContentTypeJSON, contentType)
+
+			ls := &linkset.Linkset{}
+			require.NoError(t, json.Unmarshal([]byte(body), ls))
+			require.Len(t, ls.Linkset, 1)
+			require.Equal(t, anchorObj.URL()[0].String(), ls.Linkset[0].Anchor)
+			require.Equal(t, "https://w3id.org/orb#v0", ls.Linkset[0].Targets(linkset.RelationProfile)[0].Href)
+		})
+
+		t.Run("Anchor event as native linkset", func(t *testing.T) {
+			status, contentType, body := get(anchorEventHL,
+				"application/ld+json;q=0.5, application/linkset, application/linkset+json;q=0.9")
+			require.Equal(t, http.StatusOK, status)
+			require.Equal(t, linkset.ContentTypeNative, contentType)
+
+			ls, err := linkset.ParseNative([]byte(body))
+			require.NoError(t, err)
+			require.Len(t, ls.Linkset, 1)
+			require.Equal(t, anchorObj.URL()[0].String(), ls.Linkset[0].Anchor)
+			require.Equal(t, "https://orb.domain1.com/services/orb",
+				ls.Linkset[0].Targets(linkset.RelationAuthor)[0].Href)
+		})
+
+		t.Run("JSON linkset", func(t *testing.T) {
+			status, contentType, body := get(linksetHL, "application/linkset+json")
+			require.Equal(t, http.StatusOK, status)
+			require.Equal(t, linkset.ContentTypeJSON, contentType)
+			require.Equal(t, string(linksetBytes), body)
+
+			status, contentType, body = get(linksetHL, "application/linkset")
+			require.Equal(t, http.StatusOK, status)
+			require.Equal(t, linkset.ContentTypeNative, contentType)
+			require.Equal(t, `<https://orb.domain1.com/services/orb>; rel="author"; `+
+				`anchor="hl:uEiDzUEQi2qRreCTfvp2AKmTaxuqUUZZNhbxe5RTBH59AWw"`, body)
+		})
+
+		t.Run("Not an anchor -> not acceptable", func(t *testing.T) {
+			status, _, _ := get(vcHL, "application/linkset+json")
+			require.Equal(t, http.StatusNotAcceptable, status)
+		})
+	})
+
 	t.Run("Authorization", func(t *testing.T) {
 		casClient, err := cas.New(mem.NewProvider(), casLink, nil, &orbmocks.MetricsProvider{}, 0)
 		require.NoError(t, err)
@@ -224,3 +377,16 @@ func TestHandler(t *testing.T) {
 		})
 	})
 }
+
+type mockSignatureProvider struct {
+	signatures map[string]string
+	err        error
+}
+
+func (m *mockSignatureProvider) Get(resourceHash string) (string, error) {
+	if m.err != nil {
+		return "", m.err
+	}
+
+	return m.signatures[resourceHash], nil
+}
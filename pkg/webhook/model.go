@@ -0,0 +1,187 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webhook
+
+import (
+	"time"
+
+	"github.com/trustbloc/orb/pkg/document/util"
+)
+
+// EventType is the type of event that is posted to a webhook.
+type EventType string
+
+const (
+	// EventAnchorProcessed is posted after an anchor has been processed by the observer.
+	EventAnchorProcessed EventType = "anchor-processed"
+	// EventDIDUpdated is posted for each DID that was created or updated by a processed anchor.
+	EventDIDUpdated EventType = "did-updated"
+	// EventFollowAccepted is posted after a remote service accepts a 'Follow' request from this service.
+	EventFollowAccepted EventType = "follow-accepted"
+	// EventOperationRejected is posted when a Sidetree operation is discarded from the operation queue since
+	// it could not be anchored.
+	EventOperationRejected EventType = "operation-rejected"
+)
+
+// nolint: gochecknoglobals
+var (
+	// EventTypes contains all of the supported event types.
+	EventTypes = []EventType{EventAnchorProcessed, EventDIDUpdated, EventFollowAccepted, EventOperationRejected}
+
+	// DIDEventTypes contains the event types that pertain to a single DID. A subscription that's registered by
+	// a DID controller may only receive these events.
+	DIDEventTypes = []EventType{EventDIDUpdated, EventOperationRejected}
+)
+
+// IsValid returns true if the event type is supported.
+func (t EventType) IsValid() bool {
+	for _, et := range EventTypes {
+		if et == t {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsDIDEventType returns true if the event type pertains to a single DID.
+func (t EventType) IsDIDEventType() bool {
+	for _, et := range DIDEventTypes {
+		if et == t {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Event is the payload that is posted to a webhook.
+type Event struct {
+	ID        string      `json:"id"`
+	Type      EventType   `json:"type"`
+	Timestamp time.Time   `json:"timestamp"`
+	Data      interface{} `json:"data"`
+}
+
+// AnchorProcessedData is the data of an 'anchor-processed' event.
+type AnchorProcessedData struct {
+	Anchor         string   `json:"anchor"`
+	AttributedTo   string   `json:"attributedTo,omitempty"`
+	Namespace      string   `json:"namespace"`
+	OperationCount uint64   `json:"operationCount"`
+	DIDs           []string `json:"dids,omitempty"`
+}
+
+// DIDUpdatedData is the data of a 'did-updated' event.
+type DIDUpdatedData struct {
+	DID    string `json:"did"`
+	Anchor string `json:"anchor"`
+}
+
+// OperationRejectedData is the data of an 'operation-rejected' event.
+type OperationRejectedData struct {
+	Namespace     string `json:"namespace"`
+	Suffix        string `json:"suffix"`
+	OperationType string `json:"operationType"`
+	Reason        string `json:"reason"`
+}
+
+// FollowAcceptedData is the data of a 'follow-accepted' event.
+type FollowAcceptedData struct {
+	Actor    string `json:"actor"`
+	Follow   string `json:"follow"`
+	Activity string `json:"activity"`
+}
+
+// Subscription is a callback URL that is registered to receive events. If Events is empty then
+// all events are posted to the URL. If DID is set then the subscription was registered by the controller
+// of the DID and only events for that DID are posted to the URL.
+type Subscription struct {
+	ID      string      `json:"id"`
+	URL     string      `json:"url"`
+	Events  []EventType `json:"events,omitempty"`
+	DID     string      `json:"did,omitempty"`
+	Secret  string      `json:"secret,omitempty"`
+	Created time.Time   `json:"created"`
+}
+
+// Matches returns true if the subscription is registered for the given event type.
+func (s *Subscription) Matches(t EventType) bool {
+	if len(s.Events) == 0 {
+		return true
+	}
+
+	for _, et := range s.Events {
+		if et == t {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MatchesEvent returns true if the subscription is registered for the type of the given event and, if the
+// subscription is for a DID, the event pertains to the DID.
+func (s *Subscription) MatchesEvent(e *Event) bool {
+	if !s.Matches(e.Type) {
+		return false
+	}
+
+	if s.DID == "" {
+		return true
+	}
+
+	suffix := eventSuffix(e)
+
+	return suffix != "" && suffix == didSuffix(s.DID)
+}
+
+// eventSuffix returns the suffix of the DID to which the event pertains or an empty string if the event
+// doesn't pertain to a single DID.
+func eventSuffix(e *Event) string {
+	switch data := e.Data.(type) {
+	case *DIDUpdatedData:
+		return didSuffix(data.DID)
+	case *OperationRejectedData:
+		return data.Suffix
+	default:
+		return ""
+	}
+}
+
+func didSuffix(did string) string {
+	suffix, err := util.GetSuffix(did)
+	if err != nil {
+		return ""
+	}
+
+	return suffix
+}
+
+// DeliveryStatus is the status of an event delivery.
+type DeliveryStatus string
+
+const (
+	// DeliveryStatusSucceeded indicates that the event was delivered.
+	DeliveryStatusSucceeded DeliveryStatus = "succeeded"
+	// DeliveryStatusFailed indicates that the event could not be delivered after all retries.
+	DeliveryStatusFailed DeliveryStatus = "failed"
+)
+
+// Delivery is an entry in the delivery log of a subscription.
+type Delivery struct {
+	ID             string         `json:"id"`
+	SubscriptionID string         `json:"subscriptionId"`
+	EventID        string         `json:"eventId"`
+	EventType      EventType      `json:"eventType"`
+	URL            string         `json:"url"`
+	Status         DeliveryStatus `json:"status"`
+	Attempts       int            `json:"attempts"`
+	ResponseCode   int            `json:"responseCode,omitempty"`
+	Error          string         `json:"error,omitempty"`
+	Timestamp      time.Time      `json:"timestamp"`
+}
@@ -0,0 +1,465 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/google/uuid"
+	"github.com/trustbloc/edge-core/pkg/log"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/document/util"
+	"github.com/trustbloc/orb/pkg/lifecycle"
+	"github.com/trustbloc/orb/pkg/observer"
+)
+
+var logger = log.New("webhook")
+
+const (
+	// SignatureHeader contains the HMAC-SHA256 signature of the request body, computed with the
+	// subscription's secret, in the form "sha256=<hex>".
+	SignatureHeader = "X-Orb-Signature"
+	// EventTypeHeader contains the type of the event.
+	EventTypeHeader = "X-Orb-Event"
+	// DeliveryIDHeader contains the ID of the delivery, which is the same for all retries of a delivery.
+	DeliveryIDHeader = "X-Orb-Delivery"
+
+	signaturePrefix = "sha256="
+	contentTypeJSON = "application/json"
+
+	defaultMaxAttempts     = 5
+	defaultInitialInterval = time.Second
+	defaultMaxInterval     = time.Minute
+	defaultRequestTimeout  = 10 * time.Second
+	defaultWorkers         = 5
+	defaultQueueSize       = 1000
+)
+
+type subscriptionStore interface {
+	GetSubscriptions() ([]*Subscription, error)
+	AddDelivery(d *Delivery) error
+}
+
+type httpClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+type options struct {
+	maxAttempts     int
+	initialInterval time.Duration
+	maxInterval     time.Duration
+	requestTimeout  time.Duration
+	workers         int
+	queueSize       int
+}
+
+// Opt sets a notifier option.
+type Opt func(opts *options)
+
+// WithMaxAttempts sets the maximum number of attempts to deliver an event.
+func WithMaxAttempts(value int) Opt {
+	return func(opts *options) {
+		opts.maxAttempts = value
+	}
+}
+
+// WithRetryBackoff sets the initial and maximum intervals between delivery attempts. The interval is
+// doubled after every attempt.
+func WithRetryBackoff(initialInterval, maxInterval time.Duration) Opt {
+	return func(opts *options) {
+		opts.initialInterval = initialInterval
+		opts.maxInterval = maxInterval
+	}
+}
+
+// WithRequestTimeout sets the timeout of a single HTTP request to a webhook.
+func WithRequestTimeout(value time.Duration) Opt {
+	return func(opts *options) {
+		opts.requestTimeout = value
+	}
+}
+
+// WithWorkers sets the number of concurrent deliveries.
+func WithWorkers(value int) Opt {
+	return func(opts *options) {
+		opts.workers = value
+	}
+}
+
+// WithQueueSize sets the maximum number of deliveries that may be queued. Deliveries are dropped (and logged as
+// failed) if the queue is full.
+func WithQueueSize(value int) Opt {
+	return func(opts *options) {
+		opts.queueSize = value
+	}
+}
+
+type job struct {
+	sub     *Subscription
+	event   *Event
+	payload []byte
+}
+
+// Notifier posts events to the registered webhooks. Failed deliveries are retried with exponential backoff
+// and the outcome of every delivery is added to the delivery log.
+type Notifier struct {
+	*lifecycle.Lifecycle
+	*options
+
+	store  subscriptionStore
+	client httpClient
+	jobs   chan *job
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewNotifier returns a new webhook notifier.
+func NewNotifier(store subscriptionStore, client httpClient, opts ...Opt) *Notifier {
+	options := &options{
+		maxAttempts:     defaultMaxAttempts,
+		initialInterval: defaultInitialInterval,
+		maxInterval:     defaultMaxInterval,
+		requestTimeout:  defaultRequestTimeout,
+		workers:         defaultWorkers,
+		queueSize:       defaultQueueSize,
+	}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if options.maxAttempts < 1 {
+		options.maxAttempts = 1
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	n := &Notifier{
+		options: options,
+		store:   store,
+		client:  client,
+		jobs:    make(chan *job, options.queueSize),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+
+	n.Lifecycle = lifecycle.New("webhook-notifier",
+		lifecycle.WithStart(n.start),
+		lifecycle.WithStop(n.stop),
+	)
+
+	return n
+}
+
+// NewEvent returns a new event of the given type.
+func NewEvent(eventType EventType, data interface{}) *Event {
+	return &Event{
+		ID:        uuid.New().String(),
+		Type:      eventType,
+		Timestamp: time.Now().UTC(),
+		Data:      data,
+	}
+}
+
+// Notify posts the given events to all webhooks that are registered for the event types.
+func (n *Notifier) Notify(events ...*Event) {
+	if n.State() != lifecycle.StateStarted {
+		logger.Warnf("Webhook notifier is not started. Events will not be delivered.")
+
+		return
+	}
+
+	subs, err := n.store.GetSubscriptions()
+	if err != nil {
+		logger.Errorf("Unable to retrieve webhook subscriptions: %s", err)
+
+		return
+	}
+
+	if len(subs) == 0 {
+		return
+	}
+
+	for _, event := range events {
+		payload, e := json.Marshal(event)
+		if e != nil {
+			logger.Errorf("Unable to marshal event [%s]: %s", event.ID, e)
+
+			continue
+		}
+
+		for _, sub := range subs {
+			if sub.MatchesEvent(event) {
+				n.enqueue(&job{sub: sub, event: event, payload: payload})
+			}
+		}
+	}
+}
+
+// AnchorProcessed posts an 'anchor-processed' event along with a 'did-updated' event for each DID in the anchor.
+// This function implements the observer.AnchorProcessedListener interface.
+func (n *Notifier) AnchorProcessed(anchor *observer.ProcessedAnchor) {
+	dids := make([]string, len(anchor.Suffixes))
+
+	for i, suffix := range anchor.Suffixes {
+		dids[i] = fmt.Sprintf("%s:%s:%s", anchor.Namespace, anchor.CanonicalReference, suffix)
+	}
+
+	events := []*Event{
+		NewEvent(EventAnchorProcessed, &AnchorProcessedData{
+			Anchor:         anchor.Hashlink,
+			AttributedTo:   anchor.AttributedTo,
+			Namespace:      anchor.Namespace,
+			OperationCount: anchor.OperationCount,
+			DIDs:           dids,
+		}),
+	}
+
+	for _, did := range dids {
+		events = append(events, NewEvent(EventDIDUpdated, &DIDUpdatedData{
+			DID:    did,
+			Anchor: anchor.Hashlink,
+		}))
+	}
+
+	n.Notify(events...)
+}
+
+// OperationRejected posts an 'operation-rejected' event for the given operation. This function implements
+// the opqueue.RejectedOperationListener interface.
+func (n *Notifier) OperationRejected(op *operation.QueuedOperation, reason string) {
+	opType, err := util.GetOperationType(op.OperationRequest)
+	if err != nil {
+		logger.Debugf("Unable to determine the type of the rejected operation for suffix [%s]: %s",
+			op.UniqueSuffix, err)
+	}
+
+	n.Notify(NewEvent(EventOperationRejected, &OperationRejectedData{
+		Namespace:     op.Namespace,
+		Suffix:        op.UniqueSuffix,
+		OperationType: string(opType),
+		Reason:        reason,
+	}))
+}
+
+// ListenActivities posts a 'follow-accepted' event for each 'Accept' activity (of a 'Follow') that is received
+// on the given channel. The channel must be closed in order to stop listening.
+func (n *Notifier) ListenActivities(activities <-chan *vocab.ActivityType) {
+	go func() {
+		for activity := range activities {
+			n.handleActivity(activity)
+		}
+
+		logger.Debugf("Activity channel closed.")
+	}()
+}
+
+func (n *Notifier) handleActivity(activity *vocab.ActivityType) {
+	if !activity.Type().Is(vocab.TypeAccept) || activity.Object() == nil {
+		return
+	}
+
+	follow := activity.Object().Activity()
+	if follow == nil || !follow.Type().Is(vocab.TypeFollow) {
+		return
+	}
+
+	data := &FollowAcceptedData{
+		Follow:   follow.ID().String(),
+		Activity: activity.ID().String(),
+	}
+
+	if activity.Actor() != nil {
+		data.Actor = activity.Actor().String()
+	}
+
+	n.Notify(NewEvent(EventFollowAccepted, data))
+}
+
+func (n *Notifier) start() {
+	for i := 0; i < n.workers; i++ {
+		n.wg.Add(1)
+
+		go n.process()
+	}
+
+	logger.Infof("Started webhook notifier with %d workers", n.workers)
+}
+
+func (n *Notifier) stop() {
+	n.cancel()
+	n.wg.Wait()
+
+	logger.Infof("Stopped webhook notifier")
+}
+
+func (n *Notifier) enqueue(j *job) {
+	select {
+	case n.jobs <- j:
+	default:
+		logger.Warnf("Webhook delivery queue is full. Dropping event [%s] for subscription [%s]",
+			j.event.ID, j.sub.ID)
+
+		n.addDelivery(&Delivery{
+			ID:             uuid.New().String(),
+			SubscriptionID: j.sub.ID,
+			EventID:        j.event.ID,
+			EventType:      j.event.Type,
+			URL:            j.sub.URL,
+			Status:         DeliveryStatusFailed,
+			Error:          "delivery queue is full",
+			Timestamp:      time.Now().UTC(),
+		})
+	}
+}
+
+func (n *Notifier) process() {
+	defer n.wg.Done()
+
+	for {
+		select {
+		case j := <-n.jobs:
+			n.deliver(j)
+		case <-n.ctx.Done():
+			return
+		}
+	}
+}
+
+func (n *Notifier) deliver(j *job) {
+	d := &Delivery{
+		ID:             uuid.New().String(),
+		SubscriptionID: j.sub.ID,
+		EventID:        j.event.ID,
+		EventType:      j.event.Type,
+		URL:            j.sub.URL,
+	}
+
+	b := backoff.NewExponentialBackOff()
+	b.InitialInterval = n.initialInterval
+	b.MaxInterval = n.maxInterval
+	b.Multiplier = 2
+	b.MaxElapsedTime = 0
+
+	err := backoff.RetryNotify(
+		func() error {
+			d.Attempts++
+
+			code, e := n.post(j, d.ID)
+
+			d.ResponseCode = code
+
+			return e
+		},
+		backoff.WithContext(backoff.WithMaxRetries(b, uint64(n.maxAttempts-1)), n.ctx),
+		func(err error, duration time.Duration) {
+			logger.Debugf("Error delivering event [%s] to [%s]. Retrying in %s: %s",
+				j.event.ID, j.sub.URL, duration, err)
+		},
+	)
+
+	d.Timestamp = time.Now().UTC()
+
+	if err != nil {
+		logger.Warnf("Failed to deliver event [%s] to [%s] after %d attempt(s): %s",
+			j.event.ID, j.sub.URL, d.Attempts, err)
+
+		d.Status = DeliveryStatusFailed
+		d.Error = err.Error()
+	} else {
+		logger.Debugf("Delivered event [%s] to [%s]", j.event.ID, j.sub.URL)
+
+		d.Status = DeliveryStatusSucceeded
+	}
+
+	n.addDelivery(d)
+}
+
+func (n *Notifier) post(j *job, deliveryID string) (int, error) {
+	ctx, cancel := context.WithTimeout(n.ctx, n.requestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, j.sub.URL, bytes.NewReader(j.payload))
+	if err != nil {
+		return 0, backoff.Permanent(fmt.Errorf("create request: %w", err))
+	}
+
+	req.Header.Set("Content-Type", contentTypeJSON)
+	req.Header.Set(EventTypeHeader, string(j.event.Type))
+	req.Header.Set(DeliveryIDHeader, deliveryID)
+
+	if j.sub.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(j.sub.Secret, j.payload))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("post event: %w", err)
+	}
+
+	defer func() {
+		// Drain the body so that the connection may be reused.
+		if _, e := io.Copy(ioutil.Discard, resp.Body); e != nil {
+			logger.Debugf("Error reading response body: %s", e)
+		}
+
+		if e := resp.Body.Close(); e != nil {
+			logger.Warnf("Error closing response body: %s", e)
+		}
+	}()
+
+	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
+		return resp.StatusCode, nil
+	}
+
+	err = fmt.Errorf("webhook returned status %d", resp.StatusCode)
+
+	if isPermanent(resp.StatusCode) {
+		return resp.StatusCode, backoff.Permanent(err)
+	}
+
+	return resp.StatusCode, err
+}
+
+func (n *Notifier) addDelivery(d *Delivery) {
+	if err := n.store.AddDelivery(d); err != nil {
+		logger.Errorf("Unable to add delivery [%s] to the delivery log: %s", d.ID, err)
+	}
+}
+
+// Sign returns the value of the signature header for the given payload, which is the HMAC-SHA256 of the
+// payload computed with the given secret.
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+
+	// Write on a hash never returns an error.
+	_, _ = mac.Write(payload)
+
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// isPermanent returns true if the request should not be retried, i.e. a client error other than
+// 'Request Timeout' or 'Too Many Requests'.
+func isPermanent(statusCode int) bool {
+	return statusCode >= http.StatusBadRequest && statusCode < http.StatusInternalServerError &&
+		statusCode != http.StatusRequestTimeout && statusCode != http.StatusTooManyRequests
+}
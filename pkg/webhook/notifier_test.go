@@ -0,0 +1,418 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/api/operation"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+	"github.com/trustbloc/orb/pkg/observer"
+)
+
+func TestNotifier_AnchorProcessed(t *testing.T) {
+	var (
+		mutex  sync.Mutex
+		events []*Event
+	)
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, err := ioutil.ReadAll(req.Body)
+		require.NoError(t, err)
+
+		require.Equal(t, Sign("secret1", body), req.Header.Get(SignatureHeader))
+		require.NotEmpty(t, req.Header.Get(DeliveryIDHeader))
+
+		event := &Event{}
+		require.NoError(t, json.Unmarshal(body, event))
+		require.Equal(t, string(event.Type), req.Header.Get(EventTypeHeader))
+
+		mutex.Lock()
+		events = append(events, event)
+		mutex.Unlock()
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	store := newMockStore(
+		&Subscription{ID: "sub1", URL: server.URL + "/all", Secret: "secret1"},
+		&Subscription{ID: "sub2", URL: server.URL + "/follow", Secret: "secret1",
+			Events: []EventType{EventFollowAccepted}},
+	)
+
+	n := NewNotifier(store, server.Client())
+
+	n.Start()
+	defer n.Stop()
+
+	n.AnchorProcessed(&observer.ProcessedAnchor{
+		Hashlink:           "hl:uEiA1",
+		AttributedTo:       "https://orb.domain1.com/services/orb",
+		Namespace:          "did:orb",
+		CanonicalReference: "uEiA1",
+		OperationCount:     2,
+		Suffixes:           []string{"suffix1", "suffix2"},
+	})
+
+	require.Eventually(t, func() bool { return len(store.Deliveries()) == 3 }, time.Second, 10*time.Millisecond)
+
+	for _, d := range store.Deliveries() {
+		require.Equal(t, DeliveryStatusSucceeded, d.Status)
+		require.Equal(t, "sub1", d.SubscriptionID)
+		require.Equal(t, 1, d.Attempts)
+		require.Equal(t, http.StatusOK, d.ResponseCode)
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	var dids []string
+
+	for _, event := range events {
+		data, ok := event.Data.(map[string]interface{})
+		require.True(t, ok)
+
+		switch event.Type {
+		case EventAnchorProcessed:
+			require.Equal(t, "hl:uEiA1", data["anchor"])
+			require.Len(t, data["dids"], 2)
+		case EventDIDUpdated:
+			did, isString := data["did"].(string)
+			require.True(t, isString)
+
+			dids = append(dids, did)
+		default:
+			t.Fatalf("unexpected event type: %s", event.Type)
+		}
+	}
+
+	require.ElementsMatch(t, []string{"did:orb:uEiA1:suffix1", "did:orb:uEiA1:suffix2"}, dids)
+}
+
+func TestNotifier_DIDSubscription(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get(EventTypeHeader) == string(EventOperationRejected) {
+			event := &struct {
+				Data *OperationRejectedData `json:"data"`
+			}{}
+
+			require.NoError(t, json.NewDecoder(req.Body).Decode(event))
+			require.Equal(t, string(operation.TypeUpdate), event.Data.OperationType)
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	store := newMockStore(
+		&Subscription{ID: "sub1", URL: server.URL, DID: "did:orb:uAAA:suffix1"},
+		&Subscription{ID: "sub2", URL: server.URL, DID: "did:orb:uEiA1:suffix2",
+			Events: []EventType{EventOperationRejected}},
+	)
+
+	n := NewNotifier(store, server.Client())
+
+	n.Start()
+	defer n.Stop()
+
+	n.AnchorProcessed(&observer.ProcessedAnchor{
+		Hashlink:           "hl:uEiA1",
+		Namespace:          "did:orb",
+		CanonicalReference: "uEiA1",
+		OperationCount:     2,
+		Suffixes:           []string{"suffix1", "suffix2"},
+	})
+
+	n.OperationRejected(&operation.QueuedOperation{
+		Namespace:        "did:orb",
+		UniqueSuffix:     "suffix2",
+		OperationRequest: []byte(`{"type":"update"}`),
+	}, "injected rejection")
+
+	n.OperationRejected(&operation.QueuedOperation{
+		Namespace:        "did:orb",
+		UniqueSuffix:     "suffix3",
+		OperationRequest: []byte(`{"type":"update"}`),
+	}, "injected rejection")
+
+	require.Eventually(t, func() bool { return len(store.Deliveries()) == 2 }, time.Second, 10*time.Millisecond)
+
+	// Give the notifier a chance to (incorrectly) deliver more events.
+	time.Sleep(50 * time.Millisecond)
+
+	deliveries := store.Deliveries()
+	require.Len(t, deliveries, 2)
+
+	for _, d := range deliveries {
+		switch d.SubscriptionID {
+		case "sub1":
+			require.Equal(t, EventDIDUpdated, d.EventType)
+		case "sub2":
+			require.Equal(t, EventOperationRejected, d.EventType)
+		default:
+			t.Fatalf("unexpected subscription: %s", d.SubscriptionID)
+		}
+	}
+}
+
+func TestNotifier_ListenActivities(t *testing.T) {
+	var received int32
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		require.Empty(t, req.Header.Get(SignatureHeader))
+		require.Equal(t, string(EventFollowAccepted), req.Header.Get(EventTypeHeader))
+
+		atomic.AddInt32(&received, 1)
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	store := newMockStore(&Subscription{ID: "sub1", URL: server.URL, Events: []EventType{EventFollowAccepted}})
+
+	n := NewNotifier(store, server.Client())
+
+	n.Start()
+	defer n.Stop()
+
+	activities := make(chan *vocab.ActivityType)
+
+	n.ListenActivities(activities)
+
+	follow := vocab.NewFollowActivity(
+		vocab.NewObjectProperty(vocab.WithIRI(testutil.MustParseURL("https://orb.domain2.com/services/orb"))),
+		vocab.WithID(testutil.MustParseURL("https://orb.domain1.com/services/orb/activities/follow1")),
+	)
+
+	activities <- vocab.NewAcceptActivity(
+		vocab.NewObjectProperty(vocab.WithActivity(follow)),
+		vocab.WithID(testutil.MustParseURL("https://orb.domain2.com/services/orb/activities/accept1")),
+		vocab.WithActor(testutil.MustParseURL("https://orb.domain2.com/services/orb")),
+	)
+
+	// Activities other than 'Accept' of a 'Follow' are ignored.
+	activities <- follow
+	activities <- vocab.NewAcceptActivity(
+		vocab.NewObjectProperty(vocab.WithActivity(vocab.NewLikeActivity(
+			vocab.NewObjectProperty(vocab.WithIRI(testutil.MustParseURL("hl:uEiA1"))),
+		))),
+	)
+
+	close(activities)
+
+	require.Eventually(t, func() bool { return len(store.Deliveries()) == 1 }, time.Second, 10*time.Millisecond)
+	require.Equal(t, int32(1), atomic.LoadInt32(&received))
+	require.Equal(t, DeliveryStatusSucceeded, store.Deliveries()[0].Status)
+	require.Equal(t, EventFollowAccepted, store.Deliveries()[0].EventType)
+}
+
+func TestNotifier_Retry(t *testing.T) {
+	t.Run("Transient error", func(t *testing.T) {
+		var attempts int32
+
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if atomic.AddInt32(&attempts, 1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+
+				return
+			}
+
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		store := newMockStore(&Subscription{ID: "sub1", URL: server.URL})
+
+		n := NewNotifier(store, server.Client(), WithRetryBackoff(10*time.Millisecond, 50*time.Millisecond))
+
+		n.Start()
+		defer n.Stop()
+
+		n.Notify(NewEvent(EventDIDUpdated, &DIDUpdatedData{DID: "did:orb:uEiA1:suffix1"}))
+
+		require.Eventually(t, func() bool { return len(store.Deliveries()) == 1 }, time.Second, 10*time.Millisecond)
+
+		d := store.Deliveries()[0]
+		require.Equal(t, DeliveryStatusSucceeded, d.Status)
+		require.Equal(t, 3, d.Attempts)
+		require.Empty(t, d.Error)
+	})
+
+	t.Run("Max attempts reached", func(t *testing.T) {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+
+		store := newMockStore(&Subscription{ID: "sub1", URL: server.URL})
+
+		n := NewNotifier(store, server.Client(), WithMaxAttempts(2),
+			WithRetryBackoff(10*time.Millisecond, 50*time.Millisecond))
+
+		n.Start()
+		defer n.Stop()
+
+		n.Notify(NewEvent(EventDIDUpdated, &DIDUpdatedData{DID: "did:orb:uEiA1:suffix1"}))
+
+		require.Eventually(t, func() bool { return len(store.Deliveries()) == 1 }, time.Second, 10*time.Millisecond)
+
+		d := store.Deliveries()[0]
+		require.Equal(t, DeliveryStatusFailed, d.Status)
+		require.Equal(t, 2, d.Attempts)
+		require.Equal(t, http.StatusTooManyRequests, d.ResponseCode)
+		require.Contains(t, d.Error, "webhook returned status 429")
+	})
+
+	t.Run("Permanent error", func(t *testing.T) {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		defer server.Close()
+
+		store := newMockStore(&Subscription{ID: "sub1", URL: server.URL})
+
+		n := NewNotifier(store, server.Client(), WithRetryBackoff(10*time.Millisecond, 50*time.Millisecond))
+
+		n.Start()
+		defer n.Stop()
+
+		n.Notify(NewEvent(EventDIDUpdated, &DIDUpdatedData{DID: "did:orb:uEiA1:suffix1"}))
+
+		require.Eventually(t, func() bool { return len(store.Deliveries()) == 1 }, time.Second, 10*time.Millisecond)
+
+		d := store.Deliveries()[0]
+		require.Equal(t, DeliveryStatusFailed, d.Status)
+		require.Equal(t, 1, d.Attempts)
+		require.Equal(t, http.StatusBadRequest, d.ResponseCode)
+	})
+
+	t.Run("Connection error", func(t *testing.T) {
+		store := newMockStore(&Subscription{ID: "sub1", URL: "https://127.0.0.1:1/hook"})
+
+		n := NewNotifier(store, &http.Client{}, WithMaxAttempts(0),
+			WithRequestTimeout(time.Second))
+
+		n.Start()
+		defer n.Stop()
+
+		n.Notify(NewEvent(EventDIDUpdated, &DIDUpdatedData{DID: "did:orb:uEiA1:suffix1"}))
+
+		require.Eventually(t, func() bool { return len(store.Deliveries()) == 1 }, 2*time.Second, 10*time.Millisecond)
+
+		d := store.Deliveries()[0]
+		require.Equal(t, DeliveryStatusFailed, d.Status)
+		require.Equal(t, 1, d.Attempts)
+		require.Contains(t, d.Error, "post event")
+	})
+}
+
+func TestNotifier_Notify(t *testing.T) {
+	t.Run("Not started", func(t *testing.T) {
+		store := newMockStore(&Subscription{ID: "sub1", URL: "https://example.com/hook"})
+
+		n := NewNotifier(store, &http.Client{})
+
+		n.Notify(NewEvent(EventDIDUpdated, &DIDUpdatedData{}))
+
+		require.Empty(t, store.Deliveries())
+	})
+
+	t.Run("Store error", func(t *testing.T) {
+		store := newMockStore()
+		store.err = errors.New("injected store error")
+
+		n := NewNotifier(store, &http.Client{})
+
+		n.Start()
+		defer n.Stop()
+
+		n.Notify(NewEvent(EventDIDUpdated, &DIDUpdatedData{}))
+
+		require.Empty(t, store.Deliveries())
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		store := newMockStore(&Subscription{ID: "sub1", URL: "https://example.com/hook"})
+
+		n := NewNotifier(store, &http.Client{})
+
+		n.Start()
+		defer n.Stop()
+
+		n.Notify(NewEvent(EventDIDUpdated, make(chan int)))
+
+		require.Empty(t, store.Deliveries())
+	})
+
+	t.Run("Queue full", func(t *testing.T) {
+		store := newMockStore(&Subscription{ID: "sub1", URL: "https://example.com/hook"})
+
+		// With no workers, nothing is taken from the queue.
+		n := NewNotifier(store, &http.Client{}, WithWorkers(0), WithQueueSize(1))
+
+		n.Start()
+		defer n.Stop()
+
+		n.Notify(
+			NewEvent(EventDIDUpdated, &DIDUpdatedData{}),
+			NewEvent(EventDIDUpdated, &DIDUpdatedData{}),
+		)
+
+		deliveries := store.Deliveries()
+		require.Len(t, deliveries, 1)
+		require.Equal(t, DeliveryStatusFailed, deliveries[0].Status)
+		require.Equal(t, "delivery queue is full", deliveries[0].Error)
+	})
+}
+
+func TestSign(t *testing.T) {
+	require.Equal(t, "sha256=b613679a0814d9ec772f95d778c35fc5ff1697c493715653c6c712144292c5ad", Sign("", nil))
+	require.NotEqual(t, Sign("secret1", []byte("payload")), Sign("secret2", []byte("payload")))
+}
+
+type mockStore struct {
+	mutex      sync.Mutex
+	subs       []*Subscription
+	deliveries []*Delivery
+	err        error
+}
+
+func newMockStore(subs ...*Subscription) *mockStore {
+	return &mockStore{subs: subs}
+}
+
+func (m *mockStore) GetSubscriptions() ([]*Subscription, error) {
+	return m.subs, m.err
+}
+
+func (m *mockStore) AddDelivery(d *Delivery) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.deliveries = append(m.deliveries, d)
+
+	return nil
+}
+
+func (m *mockStore) Deliveries() []*Delivery {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.deliveries
+}
@@ -0,0 +1,269 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
+
+	"github.com/trustbloc/orb/pkg/document/util"
+)
+
+const (
+	algES256 = "ES256"
+	algEdDSA = "EdDSA"
+
+	ktyEC   = "EC"
+	ktyOKP  = "OKP"
+	crvP256 = "P-256"
+	crvEd25 = "Ed25519"
+
+	es256SignatureSize = 64
+	jwsParts           = 3
+)
+
+var errUnauthorized = errors.New("unauthorized")
+
+type jwsHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y,omitempty"`
+}
+
+type verificationMethod struct {
+	ID           string `json:"id"`
+	PublicKeyJwk *jwk   `json:"publicKeyJwk,omitempty"`
+}
+
+type didDocument struct {
+	VerificationMethod []*verificationMethod `json:"verificationMethod,omitempty"`
+	Authentication     []json.RawMessage     `json:"authentication,omitempty"`
+}
+
+// signedRequest is a request in JWS compact serialization.
+type signedRequest struct {
+	header       *jwsHeader
+	payload      []byte
+	signingInput []byte
+	signature    []byte
+}
+
+func parseSignedRequest(jws string) (*signedRequest, error) {
+	parts := strings.Split(strings.TrimSpace(jws), ".")
+	if len(parts) != jwsParts {
+		return nil, fmt.Errorf("request must be a JWS in compact serialization")
+	}
+
+	headerBytes, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("decode JWS header: %w", err)
+	}
+
+	header := &jwsHeader{}
+
+	err = json.Unmarshal(headerBytes, header)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal JWS header: %w", err)
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("decode JWS payload: %w", err)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("decode JWS signature: %w", err)
+	}
+
+	return &signedRequest{
+		header:       header,
+		payload:      payload,
+		signingInput: []byte(parts[0] + "." + parts[1]),
+		signature:    signature,
+	}, nil
+}
+
+// verifyDIDSignature verifies that the request was signed by an authentication key in the DID document (of the
+// DID with the given suffix). The key ID (kid) in the JWS header must be the DID URL of the key (or just the
+// fragment, e.g. #key1).
+func verifyDIDSignature(req *signedRequest, suffix string, doc document.Document) error {
+	fragment, err := keyFragment(req.header.Kid, suffix)
+	if err != nil {
+		return err
+	}
+
+	key, err := getAuthenticationKey(doc, fragment)
+	if err != nil {
+		return err
+	}
+
+	return verifySignature(key, req.header.Alg, req.signingInput, req.signature)
+}
+
+func keyFragment(kid, suffix string) (string, error) {
+	pos := strings.LastIndex(kid, "#")
+	if pos < 0 || pos == len(kid)-1 {
+		return "", fmt.Errorf("%w: kid [%s] must be a DID URL with a fragment", errUnauthorized, kid)
+	}
+
+	if pos > 0 {
+		kidSuffix, err := util.GetSuffix(kid[:pos])
+		if err != nil || kidSuffix != suffix {
+			return "", fmt.Errorf("%w: kid [%s] does not refer to the DID being registered", errUnauthorized, kid)
+		}
+	}
+
+	return kid[pos+1:], nil
+}
+
+// getAuthenticationKey returns the public key with the given fragment. The key must be referenced by (or
+// embedded in) the authentication verification relationship of the DID document.
+func getAuthenticationKey(doc document.Document, fragment string) (*jwk, error) {
+	docBytes, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("marshal DID document: %w", err)
+	}
+
+	d := &didDocument{}
+
+	err = json.Unmarshal(docBytes, d)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal DID document: %w", err)
+	}
+
+	for _, authBytes := range d.Authentication {
+		vm := d.authenticationMethod(authBytes)
+		if vm != nil && vm.PublicKeyJwk != nil && idFragment(vm.ID) == fragment {
+			return vm.PublicKeyJwk, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: authentication key [#%s] not found in DID document", errUnauthorized, fragment)
+}
+
+// authenticationMethod returns the verification method that's either referenced by or embedded in the given
+// authentication entry.
+func (d *didDocument) authenticationMethod(authBytes json.RawMessage) *verificationMethod {
+	var ref string
+
+	if json.Unmarshal(authBytes, &ref) == nil {
+		for _, vm := range d.VerificationMethod {
+			if idFragment(vm.ID) == idFragment(ref) {
+				return vm
+			}
+		}
+
+		return nil
+	}
+
+	vm := &verificationMethod{}
+
+	if json.Unmarshal(authBytes, vm) != nil {
+		return nil
+	}
+
+	return vm
+}
+
+func verifySignature(key *jwk, alg string, signingInput, signature []byte) error {
+	switch alg {
+	case algES256:
+		return verifyES256(key, signingInput, signature)
+	case algEdDSA:
+		return verifyEdDSA(key, signingInput, signature)
+	default:
+		return fmt.Errorf("%w: unsupported JWS algorithm [%s]", errUnauthorized, alg)
+	}
+}
+
+func verifyES256(key *jwk, signingInput, signature []byte) error {
+	if key.Kty != ktyEC || key.Crv != crvP256 {
+		return fmt.Errorf("%w: key type [%s/%s] is not supported for %s", errUnauthorized, key.Kty, key.Crv, algES256)
+	}
+
+	x, err := base64.RawURLEncoding.DecodeString(key.X)
+	if err != nil {
+		return fmt.Errorf("%w: decode x: %s", errUnauthorized, err)
+	}
+
+	y, err := base64.RawURLEncoding.DecodeString(key.Y)
+	if err != nil {
+		return fmt.Errorf("%w: decode y: %s", errUnauthorized, err)
+	}
+
+	pubKey := &ecdsa.PublicKey{
+		Curve: elliptic.P256(),
+		X:     new(big.Int).SetBytes(x),
+		Y:     new(big.Int).SetBytes(y),
+	}
+
+	if !pubKey.Curve.IsOnCurve(pubKey.X, pubKey.Y) {
+		return fmt.Errorf("%w: invalid public key", errUnauthorized)
+	}
+
+	if len(signature) != es256SignatureSize {
+		return fmt.Errorf("%w: invalid signature size", errUnauthorized)
+	}
+
+	digest := sha256.Sum256(signingInput)
+
+	r := new(big.Int).SetBytes(signature[:es256SignatureSize/2])
+	s := new(big.Int).SetBytes(signature[es256SignatureSize/2:])
+
+	if !ecdsa.Verify(pubKey, digest[:], r, s) {
+		return fmt.Errorf("%w: invalid signature", errUnauthorized)
+	}
+
+	return nil
+}
+
+func verifyEdDSA(key *jwk, signingInput, signature []byte) error {
+	if key.Kty != ktyOKP || key.Crv != crvEd25 {
+		return fmt.Errorf("%w: key type [%s/%s] is not supported for %s", errUnauthorized, key.Kty, key.Crv, algEdDSA)
+	}
+
+	x, err := base64.RawURLEncoding.DecodeString(key.X)
+	if err != nil {
+		return fmt.Errorf("%w: decode x: %s", errUnauthorized, err)
+	}
+
+	if len(x) != ed25519.PublicKeySize {
+		return fmt.Errorf("%w: invalid public key", errUnauthorized)
+	}
+
+	if !ed25519.Verify(x, signingInput, signature) {
+		return fmt.Errorf("%w: invalid signature", errUnauthorized)
+	}
+
+	return nil
+}
+
+func idFragment(id string) string {
+	pos := strings.LastIndex(id, "#")
+	if pos < 0 {
+		return ""
+	}
+
+	return id[pos+1:]
+}
@@ -0,0 +1,259 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+
+	"github.com/trustbloc/orb/pkg/document/util"
+	"github.com/trustbloc/orb/pkg/webhook"
+)
+
+const (
+	// DIDWebhooksPath is the path of the endpoint that's used by DID controllers to register webhooks.
+	DIDWebhooksPath = WebhooksPath + "/did"
+
+	// maxClockSkew is the maximum difference between the 'created' time of a signed request and the current time.
+	maxClockSkew = 5 * time.Minute
+)
+
+type didResolver interface {
+	ResolveDocument(id string) (*document.ResolutionResult, error)
+}
+
+// DIDSubscriptionRequest is the request of a DID controller to register a webhook that's notified of the
+// operations on the DID. If Events is empty then all DID events (see webhook.DIDEventTypes) are posted to the URL.
+// If Secret isn't provided then a random secret is generated and returned in the response.
+//
+// The request must be sent as a JWS (compact serialization) that's signed by one of the authentication keys
+// of the DID. The 'kid' header of the JWS is the DID URL of the key and 'created' must be within a few minutes
+// of the current time.
+type DIDSubscriptionRequest struct {
+	DID     string              `json:"did"`
+	URL     string              `json:"url"`
+	Events  []webhook.EventType `json:"events,omitempty"`
+	Secret  string              `json:"secret,omitempty"`
+	Created time.Time           `json:"created"`
+}
+
+// DIDRegistrar registers a webhook on behalf of the controller of a DID. If the DID already has a webhook with
+// the same URL then the webhook is updated.
+type DIDRegistrar struct {
+	*handler
+
+	resolver didResolver
+}
+
+// NewDIDRegistrar returns a new DID webhook registrar.
+func NewDIDRegistrar(s store, resolver didResolver) *DIDRegistrar {
+	return &DIDRegistrar{
+		handler:  newHandler(s),
+		resolver: resolver,
+	}
+}
+
+// Path returns the HTTP REST endpoint for registering DID webhooks.
+func (h *DIDRegistrar) Path() string {
+	return DIDWebhooksPath
+}
+
+// Method returns the HTTP REST method for registering DID webhooks.
+func (h *DIDRegistrar) Method() string {
+	return http.MethodPost
+}
+
+// Handler returns the HTTP REST handle for registering DID webhooks.
+func (h *DIDRegistrar) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *DIDRegistrar) handle(w http.ResponseWriter, req *http.Request) {
+	reqBytes, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		logger.Errorf("[%s] Error reading request body: %s", DIDWebhooksPath, err)
+
+		writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	signedReq, err := parseSignedRequest(string(reqBytes))
+	if err != nil {
+		logger.Infof("[%s] Invalid DID webhook request: %s", DIDWebhooksPath, err)
+
+		writeResponse(w, http.StatusBadRequest, []byte(err.Error()))
+
+		return
+	}
+
+	subReq := &DIDSubscriptionRequest{}
+
+	err = json.Unmarshal(signedReq.payload, subReq)
+	if err != nil {
+		logger.Infof("[%s] Invalid DID webhook request: %s", DIDWebhooksPath, err)
+
+		writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	suffix, err := validateDIDRequest(subReq)
+	if err != nil {
+		logger.Infof("[%s] Invalid DID webhook request: %s", DIDWebhooksPath, err)
+
+		writeResponse(w, http.StatusBadRequest, []byte(err.Error()))
+
+		return
+	}
+
+	status, err := h.authenticate(signedReq, subReq.DID, suffix)
+	if err != nil {
+		logger.Infof("[%s] Unable to authenticate DID webhook request for [%s]: %s", DIDWebhooksPath, subReq.DID, err)
+
+		writeResponse(w, status, []byte(http.StatusText(status)+"."))
+
+		return
+	}
+
+	h.register(w, subReq, suffix)
+}
+
+func (h *DIDRegistrar) authenticate(signedReq *signedRequest, did, suffix string) (int, error) {
+	result, err := h.resolver.ResolveDocument(did)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return http.StatusNotFound, err
+		}
+
+		return http.StatusInternalServerError, fmt.Errorf("resolve DID: %w", err)
+	}
+
+	err = verifyDIDSignature(signedReq, suffix, result.Document)
+	if err != nil {
+		if errors.Is(err, errUnauthorized) {
+			return http.StatusUnauthorized, err
+		}
+
+		return http.StatusInternalServerError, err
+	}
+
+	return http.StatusOK, nil
+}
+
+func (h *DIDRegistrar) register(w http.ResponseWriter, subReq *DIDSubscriptionRequest, suffix string) {
+	sub, err := h.getSubscription(suffix, subReq.URL)
+	if err != nil {
+		logger.Errorf("[%s] Error retrieving webhook subscriptions: %s", DIDWebhooksPath, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	status := http.StatusOK
+
+	if sub == nil {
+		status = http.StatusCreated
+
+		sub = &webhook.Subscription{
+			ID:      uuid.New().String(),
+			URL:     subReq.URL,
+			Created: time.Now().UTC(),
+		}
+	}
+
+	sub.DID = subReq.DID
+	sub.Events = subReq.Events
+	sub.Secret = subReq.Secret
+
+	if sub.Secret == "" {
+		sub.Secret, err = generateSecret()
+		if err != nil {
+			logger.Errorf("[%s] Error generating secret: %s", DIDWebhooksPath, err)
+
+			writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+			return
+		}
+	}
+
+	err = h.store.AddSubscription(sub)
+	if err != nil {
+		logger.Errorf("[%s] Error storing webhook subscription: %s", DIDWebhooksPath, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	logger.Infof("[%s] Registered webhook [%s] for DID [%s], URL [%s] and events %s",
+		DIDWebhooksPath, sub.ID, sub.DID, sub.URL, sub.Events)
+
+	// The secret is only returned in this response.
+	h.writeJSON(w, status, sub)
+}
+
+// getSubscription returns the subscription for the DID with the given suffix and the given URL or nil if
+// no such subscription exists.
+func (h *DIDRegistrar) getSubscription(suffix, u string) (*webhook.Subscription, error) {
+	subs, err := h.store.GetSubscriptions()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sub := range subs {
+		if sub.DID == "" || sub.URL != u {
+			continue
+		}
+
+		if subSuffix, e := util.GetSuffix(sub.DID); e == nil && subSuffix == suffix {
+			return sub, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func validateDIDRequest(req *DIDSubscriptionRequest) (string, error) {
+	suffix, err := util.GetSuffix(req.DID)
+	if err != nil {
+		return "", fmt.Errorf("invalid DID [%s]: %w", req.DID, err)
+	}
+
+	err = validateURL(req.URL)
+	if err != nil {
+		return "", err
+	}
+
+	for _, et := range req.Events {
+		if !et.IsDIDEventType() {
+			return "", fmt.Errorf("unsupported event type [%s]. Supported types: %s", et, webhook.DIDEventTypes)
+		}
+	}
+
+	skew := time.Since(req.Created)
+	if skew < 0 {
+		skew = -skew
+	}
+
+	if skew > maxClockSkew {
+		return "", fmt.Errorf("the created time of the request [%s] is not within %s of the current time",
+			req.Created, maxClockSkew)
+	}
+
+	return suffix, nil
+}
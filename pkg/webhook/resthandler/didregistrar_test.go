@@ -0,0 +1,276 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-core-go/pkg/document"
+
+	"github.com/trustbloc/orb/pkg/internal/testutil/httptestutil"
+	"github.com/trustbloc/orb/pkg/webhook"
+)
+
+const (
+	testDID  = "did:orb:uEiA1:EiDOQXC2GnoVyHwIRbjhLx_cNc6vmZaS04SZjZdlLLAPRg"
+	testDID2 = "did:orb:uEiA1:EiA329wd6Aj36YRmp7NGkeB5ADnVt8ARdMZMPzfXsjwXYz"
+)
+
+func TestDIDRegistrar(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	edPubKey, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	resolver := &mockResolver{doc: document.Document{
+		"id": testDID,
+		"verificationMethod": []interface{}{
+			map[string]interface{}{
+				"id": "#auth1", "type": "JsonWebKey2020", "controller": testDID,
+				"publicKeyJwk": ecJWK(&ecKey.PublicKey),
+			},
+			map[string]interface{}{
+				"id": testDID + "#assert1", "type": "JsonWebKey2020", "controller": testDID,
+				"publicKeyJwk": ecJWK(&ecKey.PublicKey),
+			},
+		},
+		"authentication": []interface{}{
+			"#auth1",
+			map[string]interface{}{
+				"id": testDID + "#auth2", "type": "JsonWebKey2020", "controller": testDID,
+				"publicKeyJwk": map[string]interface{}{
+					"kty": "OKP", "crv": "Ed25519", "x": base64.RawURLEncoding.EncodeToString(edPubKey),
+				},
+			},
+		},
+		"assertionMethod": []interface{}{testDID + "#assert1"},
+	}}
+
+	es256 := func(kid string) func(payload []byte) []byte {
+		return func(payload []byte) []byte { return signES256(t, ecKey, kid, payload) }
+	}
+
+	eddsa := func(kid string) func(payload []byte) []byte {
+		return func(payload []byte) []byte { return signEdDSA(t, edKey, kid, payload) }
+	}
+
+	h := NewDIDRegistrar(newMockStore(), resolver)
+	require.Equal(t, DIDWebhooksPath, h.Path())
+	require.Equal(t, http.MethodPost, h.Method())
+	require.NotNil(t, h.Handler())
+
+	t.Run("Success - ES256", func(t *testing.T) {
+		s := newMockStore()
+
+		result := postSigned(t, NewDIDRegistrar(s, resolver), newDIDRequest(testDID, testURL), es256(testDID+"#auth1"))
+		require.Equal(t, http.StatusCreated, result.code)
+
+		sub := &webhook.Subscription{}
+		require.NoError(t, json.Unmarshal(result.body, sub))
+		require.NotEmpty(t, sub.ID)
+		require.Equal(t, testDID, sub.DID)
+		require.Equal(t, testURL, sub.URL)
+		require.Len(t, sub.Secret, 2*secretSize)
+
+		// Registering the same URL again updates the subscription.
+		req := newDIDRequest(testDID, testURL)
+		req.Events = []webhook.EventType{webhook.EventOperationRejected}
+		req.Secret = "secret1"
+
+		result = postSigned(t, NewDIDRegistrar(s, resolver), req, es256("#auth1"))
+		require.Equal(t, http.StatusOK, result.code)
+
+		sub2 := &webhook.Subscription{}
+		require.NoError(t, json.Unmarshal(result.body, sub2))
+		require.Equal(t, sub.ID, sub2.ID)
+		require.Equal(t, "secret1", sub2.Secret)
+		require.Equal(t, []webhook.EventType{webhook.EventOperationRejected}, sub2.Events)
+
+		subs, e := s.GetSubscriptions()
+		require.NoError(t, e)
+		require.Len(t, subs, 1)
+	})
+
+	t.Run("Success - EdDSA", func(t *testing.T) {
+		result := postSigned(t, NewDIDRegistrar(newMockStore(), resolver), newDIDRequest(testDID, testURL),
+			eddsa(testDID+"#auth2"))
+		require.Equal(t, http.StatusCreated, result.code)
+	})
+
+	t.Run("Invalid JWS", func(t *testing.T) {
+		for _, body := range []string{
+			"{}", "a.b", "!.e30.e30", "e30.!.e30", "e30.e30.!", "eyJ.e30.e30", "e30.eyJ.e30",
+		} {
+			code, _ := httptestutil.Post(t, h.handle, DIDWebhooksPath, []byte(body))
+			require.Equal(t, http.StatusBadRequest, code, body)
+		}
+	})
+
+	t.Run("Invalid request", func(t *testing.T) {
+		req := newDIDRequest("did:orb", testURL)
+		require.Equal(t, http.StatusBadRequest, postSigned(t, h, req, es256("#auth1")).code)
+
+		req = newDIDRequest(testDID, "http://example.com/hook")
+		require.Equal(t, http.StatusBadRequest, postSigned(t, h, req, es256("#auth1")).code)
+
+		req = newDIDRequest(testDID, testURL)
+		req.Events = []webhook.EventType{webhook.EventAnchorProcessed}
+		require.Equal(t, http.StatusBadRequest, postSigned(t, h, req, es256("#auth1")).code)
+
+		req = newDIDRequest(testDID, testURL)
+		req.Created = time.Now().Add(-time.Hour)
+		require.Equal(t, http.StatusBadRequest, postSigned(t, h, req, es256("#auth1")).code)
+
+		req = newDIDRequest(testDID, testURL)
+		req.Created = time.Now().Add(time.Hour)
+		require.Equal(t, http.StatusBadRequest, postSigned(t, h, req, es256("#auth1")).code)
+	})
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		req := newDIDRequest(testDID, testURL)
+
+		// Key isn't an authentication key.
+		require.Equal(t, http.StatusUnauthorized, postSigned(t, h, req, es256("#assert1")).code)
+		// Key not found.
+		require.Equal(t, http.StatusUnauthorized, postSigned(t, h, req, es256("#auth3")).code)
+		// No fragment.
+		require.Equal(t, http.StatusUnauthorized, postSigned(t, h, req, es256(testDID)).code)
+		// Key of another DID.
+		require.Equal(t, http.StatusUnauthorized, postSigned(t, h, req, es256(testDID2+"#auth1")).code)
+		// Signed with the wrong key.
+		require.Equal(t, http.StatusUnauthorized, postSigned(t, h, req, eddsa("#auth1")).code)
+		require.Equal(t, http.StatusUnauthorized, postSigned(t, h, req, es256("#auth2")).code)
+
+		// Signed by the controller of another DID.
+		resolver2 := &mockResolver{doc: document.Document{"id": testDID2}}
+		require.Equal(t, http.StatusUnauthorized,
+			postSigned(t, NewDIDRegistrar(newMockStore(), resolver2), req, es256("#auth1")).code)
+
+		// Invalid signature.
+		require.Equal(t, http.StatusUnauthorized, postSigned(t, h, req, func(payload []byte) []byte {
+			jws := signES256(t, ecKey, "#auth1", payload)
+
+			return append(jws[:len(jws)-4], []byte("AAAA")...)
+		}).code)
+
+		// Unsupported algorithm.
+		require.Equal(t, http.StatusUnauthorized, postSigned(t, h, req, func(payload []byte) []byte {
+			return []byte(encode(t, &jwsHeader{Alg: "ES384", Kid: "#auth1"}) + "." +
+				base64.RawURLEncoding.EncodeToString(payload) + ".AAAA")
+		}).code)
+	})
+
+	t.Run("Resolve error", func(t *testing.T) {
+		req := newDIDRequest(testDID, testURL)
+
+		result := postSigned(t, NewDIDRegistrar(newMockStore(), &mockResolver{err: errors.New("DID not found")}),
+			req, es256("#auth1"))
+		require.Equal(t, http.StatusNotFound, result.code)
+
+		result = postSigned(t, NewDIDRegistrar(newMockStore(), &mockResolver{err: errors.New("injected error")}),
+			req, es256("#auth1"))
+		require.Equal(t, http.StatusInternalServerError, result.code)
+	})
+
+	t.Run("Store error", func(t *testing.T) {
+		s := newMockStore()
+		s.err = errors.New("injected store error")
+
+		result := postSigned(t, NewDIDRegistrar(s, resolver), newDIDRequest(testDID, testURL), es256("#auth1"))
+		require.Equal(t, http.StatusInternalServerError, result.code)
+	})
+}
+
+func newDIDRequest(did, u string) *DIDSubscriptionRequest {
+	return &DIDSubscriptionRequest{
+		DID:     did,
+		URL:     u,
+		Created: time.Now(),
+	}
+}
+
+func postSigned(t *testing.T, h *DIDRegistrar, req *DIDSubscriptionRequest, sign func([]byte) []byte) *response {
+	t.Helper()
+
+	payload, err := json.Marshal(req)
+	require.NoError(t, err)
+
+	code, body := httptestutil.Post(t, h.handle, DIDWebhooksPath, sign(payload))
+
+	return &response{code: code, body: body}
+}
+
+func signES256(t *testing.T, key *ecdsa.PrivateKey, kid string, payload []byte) []byte {
+	t.Helper()
+
+	signingInput := encode(t, &jwsHeader{Alg: algES256, Kid: kid}) + "." +
+		base64.RawURLEncoding.EncodeToString(payload)
+
+	digest := sha256.Sum256([]byte(signingInput))
+
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+
+	signature := make([]byte, es256SignatureSize)
+	r.FillBytes(signature[:es256SignatureSize/2])
+	s.FillBytes(signature[es256SignatureSize/2:])
+
+	return []byte(signingInput + "." + base64.RawURLEncoding.EncodeToString(signature))
+}
+
+func signEdDSA(t *testing.T, key ed25519.PrivateKey, kid string, payload []byte) []byte {
+	t.Helper()
+
+	signingInput := encode(t, &jwsHeader{Alg: algEdDSA, Kid: kid}) + "." +
+		base64.RawURLEncoding.EncodeToString(payload)
+
+	return []byte(signingInput + "." +
+		base64.RawURLEncoding.EncodeToString(ed25519.Sign(key, []byte(signingInput))))
+}
+
+func ecJWK(key *ecdsa.PublicKey) map[string]interface{} {
+	return map[string]interface{}{
+		"kty": "EC",
+		"crv": "P-256",
+		"x":   base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, 32))),
+		"y":   base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, 32))),
+	}
+}
+
+func encode(t *testing.T, v interface{}) string {
+	t.Helper()
+
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+type mockResolver struct {
+	doc document.Document
+	err error
+}
+
+func (m *mockResolver) ResolveDocument(string) (*document.ResolutionResult, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	return &document.ResolutionResult{Document: m.doc}, nil
+}
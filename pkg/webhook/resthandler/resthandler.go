@@ -0,0 +1,417 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/trustbloc/edge-core/pkg/log"
+	"github.com/trustbloc/sidetree-core-go/pkg/restapi/common"
+
+	"github.com/trustbloc/orb/pkg/webhook"
+)
+
+const (
+	// WebhooksPath is the path of the webhook subscriptions endpoint.
+	WebhooksPath = "/webhooks"
+
+	idPathVariable = "id"
+	limitParam     = "limit"
+	secretSize     = 32
+	httpsScheme    = "https"
+)
+
+const (
+	badRequestResponse          = "Bad Request."
+	notFoundResponse            = "Not Found."
+	internalServerErrorResponse = "Internal Server Error."
+)
+
+var logger = log.New("webhook-rest-handler")
+
+type store interface {
+	AddSubscription(sub *webhook.Subscription) error
+	GetSubscription(id string) (*webhook.Subscription, error)
+	GetSubscriptions() ([]*webhook.Subscription, error)
+	DeleteSubscription(id string) error
+	GetDeliveries(subscriptionID string) ([]*webhook.Delivery, error)
+}
+
+// SubscriptionRequest is the request to register a webhook. If Secret isn't provided then a random secret is
+// generated and returned in the response.
+type SubscriptionRequest struct {
+	URL    string              `json:"url"`
+	Events []webhook.EventType `json:"events,omitempty"`
+	Secret string              `json:"secret,omitempty"`
+}
+
+// DeliveryLog contains the delivery log of a subscription.
+type DeliveryLog struct {
+	SubscriptionID string              `json:"subscriptionId"`
+	Deliveries     []*webhook.Delivery `json:"deliveries"`
+}
+
+type handler struct {
+	store   store
+	marshal func(v interface{}) ([]byte, error)
+}
+
+func newHandler(s store) *handler {
+	return &handler{
+		store:   s,
+		marshal: json.Marshal,
+	}
+}
+
+// Registrar registers a webhook subscription.
+type Registrar struct {
+	*handler
+}
+
+// NewRegistrar returns a new webhook registrar.
+func NewRegistrar(s store) *Registrar {
+	return &Registrar{handler: newHandler(s)}
+}
+
+// Path returns the HTTP REST endpoint for registering webhooks.
+func (h *Registrar) Path() string {
+	return WebhooksPath
+}
+
+// Method returns the HTTP REST method for registering webhooks.
+func (h *Registrar) Method() string {
+	return http.MethodPost
+}
+
+// Handler returns the HTTP REST handle for registering webhooks.
+func (h *Registrar) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Registrar) handle(w http.ResponseWriter, req *http.Request) {
+	reqBytes, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		logger.Errorf("[%s] Error reading request body: %s", WebhooksPath, err)
+
+		writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	subReq := &SubscriptionRequest{}
+
+	err = json.Unmarshal(reqBytes, subReq)
+	if err != nil {
+		logger.Infof("[%s] Invalid webhook subscription request: %s", WebhooksPath, err)
+
+		writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	err = validate(subReq)
+	if err != nil {
+		logger.Infof("[%s] Invalid webhook subscription request: %s", WebhooksPath, err)
+
+		writeResponse(w, http.StatusBadRequest, []byte(err.Error()))
+
+		return
+	}
+
+	secret := subReq.Secret
+
+	if secret == "" {
+		secret, err = generateSecret()
+		if err != nil {
+			logger.Errorf("[%s] Error generating secret: %s", WebhooksPath, err)
+
+			writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+			return
+		}
+	}
+
+	sub := &webhook.Subscription{
+		ID:      uuid.New().String(),
+		URL:     subReq.URL,
+		Events:  subReq.Events,
+		Secret:  secret,
+		Created: time.Now().UTC(),
+	}
+
+	err = h.store.AddSubscription(sub)
+	if err != nil {
+		logger.Errorf("[%s] Error storing webhook subscription: %s", WebhooksPath, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	logger.Infof("[%s] Registered webhook [%s] for URL [%s] and events %s", WebhooksPath, sub.ID, sub.URL, sub.Events)
+
+	// The secret is only returned in this response.
+	h.writeJSON(w, http.StatusCreated, sub)
+}
+
+// Reader returns all webhook subscriptions. The secrets of the subscriptions are not included in the response.
+type Reader struct {
+	*handler
+}
+
+// NewReader returns a new webhook subscription reader.
+func NewReader(s store) *Reader {
+	return &Reader{handler: newHandler(s)}
+}
+
+// Path returns the HTTP REST endpoint for retrieving webhooks.
+func (h *Reader) Path() string {
+	return WebhooksPath
+}
+
+// Method returns the HTTP REST method for retrieving webhooks.
+func (h *Reader) Method() string {
+	return http.MethodGet
+}
+
+// Handler returns the HTTP REST handle for retrieving webhooks.
+func (h *Reader) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Reader) handle(w http.ResponseWriter, _ *http.Request) {
+	subs, err := h.store.GetSubscriptions()
+	if err != nil {
+		logger.Errorf("[%s] Error retrieving webhook subscriptions: %s", WebhooksPath, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	for _, sub := range subs {
+		sub.Secret = ""
+	}
+
+	if subs == nil {
+		subs = []*webhook.Subscription{}
+	}
+
+	h.writeJSON(w, http.StatusOK, subs)
+}
+
+// Remover deletes a webhook subscription.
+type Remover struct {
+	*handler
+}
+
+// NewRemover returns a new webhook subscription remover.
+func NewRemover(s store) *Remover {
+	return &Remover{handler: newHandler(s)}
+}
+
+// Path returns the HTTP REST endpoint for deleting a webhook.
+func (h *Remover) Path() string {
+	return fmt.Sprintf("%s/{%s}", WebhooksPath, idPathVariable)
+}
+
+// Method returns the HTTP REST method for deleting a webhook.
+func (h *Remover) Method() string {
+	return http.MethodDelete
+}
+
+// Handler returns the HTTP REST handle for deleting a webhook.
+func (h *Remover) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Remover) handle(w http.ResponseWriter, req *http.Request) {
+	id := mux.Vars(req)[idPathVariable]
+
+	err := h.store.DeleteSubscription(id)
+	if err != nil {
+		if errors.Is(err, webhook.ErrNotFound) {
+			writeResponse(w, http.StatusNotFound, []byte(notFoundResponse))
+
+			return
+		}
+
+		logger.Errorf("[%s] Error deleting webhook subscription [%s]: %s", WebhooksPath, id, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	logger.Infof("[%s] Deleted webhook [%s]", WebhooksPath, id)
+
+	writeResponse(w, http.StatusNoContent, nil)
+}
+
+// Deliveries returns the delivery log of a webhook subscription, most recent first. The optional 'limit'
+// query parameter limits the number of returned entries.
+type Deliveries struct {
+	*handler
+}
+
+// NewDeliveries returns a new delivery log handler.
+func NewDeliveries(s store) *Deliveries {
+	return &Deliveries{handler: newHandler(s)}
+}
+
+// Path returns the HTTP REST endpoint for retrieving the delivery log of a webhook.
+func (h *Deliveries) Path() string {
+	return fmt.Sprintf("%s/{%s}/deliveries", WebhooksPath, idPathVariable)
+}
+
+// Method returns the HTTP REST method for retrieving the delivery log of a webhook.
+func (h *Deliveries) Method() string {
+	return http.MethodGet
+}
+
+// Handler returns the HTTP REST handle for retrieving the delivery log of a webhook.
+func (h *Deliveries) Handler() common.HTTPRequestHandler {
+	return h.handle
+}
+
+func (h *Deliveries) handle(w http.ResponseWriter, req *http.Request) {
+	id := mux.Vars(req)[idPathVariable]
+
+	limit, err := getLimit(req)
+	if err != nil {
+		writeResponse(w, http.StatusBadRequest, []byte(err.Error()))
+
+		return
+	}
+
+	_, err = h.store.GetSubscription(id)
+	if err != nil {
+		if errors.Is(err, webhook.ErrNotFound) {
+			writeResponse(w, http.StatusNotFound, []byte(notFoundResponse))
+
+			return
+		}
+
+		logger.Errorf("[%s] Error retrieving webhook subscription [%s]: %s", WebhooksPath, id, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	deliveries, err := h.store.GetDeliveries(id)
+	if err != nil {
+		logger.Errorf("[%s] Error retrieving deliveries for webhook [%s]: %s", WebhooksPath, id, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	if limit > 0 && len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+
+	if deliveries == nil {
+		deliveries = []*webhook.Delivery{}
+	}
+
+	h.writeJSON(w, http.StatusOK, &DeliveryLog{SubscriptionID: id, Deliveries: deliveries})
+}
+
+func (h *handler) writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	respBytes, err := h.marshal(v)
+	if err != nil {
+		logger.Errorf("[%s] Error marshalling response: %s", WebhooksPath, err)
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	writeResponse(w, status, respBytes)
+}
+
+func validate(req *SubscriptionRequest) error {
+	err := validateURL(req.URL)
+	if err != nil {
+		return err
+	}
+
+	for _, et := range req.Events {
+		if !et.IsValid() {
+			return fmt.Errorf("unsupported event type [%s]. Supported types: %s", et, webhook.EventTypes)
+		}
+	}
+
+	return nil
+}
+
+func validateURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid URL: %w", err)
+	}
+
+	if u.Scheme != httpsScheme || u.Host == "" {
+		return fmt.Errorf("URL must be an absolute HTTPS URL: %s", rawURL)
+	}
+
+	return nil
+}
+
+func getLimit(req *http.Request) (int, error) {
+	value := req.URL.Query().Get(limitParam)
+	if value == "" {
+		return 0, nil
+	}
+
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 0 {
+		return 0, fmt.Errorf("invalid value for parameter '%s': %s", limitParam, value)
+	}
+
+	return limit, nil
+}
+
+func generateSecret() (string, error) {
+	b := make([]byte, secretSize)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+func writeResponse(w http.ResponseWriter, status int, body []byte) {
+	w.WriteHeader(status)
+
+	if len(body) > 0 {
+		if _, err := w.Write(body); err != nil {
+			logger.Warnf("[%s] Unable to write response: %s", WebhooksPath, err)
+
+			return
+		}
+
+		// The body isn't logged since it may contain the secret of a subscription.
+		logger.Debugf("[%s] Wrote response with status %d", WebhooksPath, status)
+	}
+}
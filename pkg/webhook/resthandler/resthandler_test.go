@@ -0,0 +1,334 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/internal/testutil/httptestutil"
+	"github.com/trustbloc/orb/pkg/webhook"
+)
+
+const testURL = "https://example.com/hooks/orb"
+
+func TestRegistrar(t *testing.T) {
+	h := NewRegistrar(newMockStore())
+	require.Equal(t, WebhooksPath, h.Path())
+	require.Equal(t, http.MethodPost, h.Method())
+	require.NotNil(t, h.Handler())
+
+	t.Run("Success - generated secret", func(t *testing.T) {
+		s := newMockStore()
+
+		result := post(t, NewRegistrar(s), &SubscriptionRequest{
+			URL:    testURL,
+			Events: []webhook.EventType{webhook.EventAnchorProcessed, webhook.EventDIDUpdated},
+		})
+		require.Equal(t, http.StatusCreated, result.code)
+
+		sub := &webhook.Subscription{}
+		require.NoError(t, json.Unmarshal(result.body, sub))
+		require.NotEmpty(t, sub.ID)
+		require.Equal(t, testURL, sub.URL)
+		require.Len(t, sub.Secret, 2*secretSize)
+		require.Len(t, sub.Events, 2)
+
+		stored, err := s.GetSubscription(sub.ID)
+		require.NoError(t, err)
+		require.Equal(t, sub.Secret, stored.Secret)
+	})
+
+	t.Run("Success - provided secret", func(t *testing.T) {
+		result := post(t, NewRegistrar(newMockStore()), &SubscriptionRequest{URL: testURL, Secret: "secret1"})
+		require.Equal(t, http.StatusCreated, result.code)
+
+		sub := &webhook.Subscription{}
+		require.NoError(t, json.Unmarshal(result.body, sub))
+		require.Equal(t, "secret1", sub.Secret)
+		require.Empty(t, sub.Events)
+	})
+
+	t.Run("Invalid request", func(t *testing.T) {
+		code, _ := httptestutil.Post(t, NewRegistrar(newMockStore()).handle, WebhooksPath, []byte("{"))
+		require.Equal(t, http.StatusBadRequest, code)
+	})
+
+	t.Run("Invalid URL", func(t *testing.T) {
+		for _, u := range []string{"http://example.com/hook", "https:///hook", "/hook", "https://example.com/%zz"} {
+			result := post(t, NewRegistrar(newMockStore()), &SubscriptionRequest{URL: u})
+			require.Equal(t, http.StatusBadRequest, result.code, u)
+		}
+	})
+
+	t.Run("Invalid event type", func(t *testing.T) {
+		result := post(t, NewRegistrar(newMockStore()), &SubscriptionRequest{
+			URL:    testURL,
+			Events: []webhook.EventType{"unknown"},
+		})
+		require.Equal(t, http.StatusBadRequest, result.code)
+		require.Contains(t, string(result.body), "unsupported event type [unknown]")
+	})
+
+	t.Run("Store error", func(t *testing.T) {
+		s := newMockStore()
+		s.err = errors.New("injected store error")
+
+		result := post(t, NewRegistrar(s), &SubscriptionRequest{URL: testURL})
+		require.Equal(t, http.StatusInternalServerError, result.code)
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		h := NewRegistrar(newMockStore())
+		h.marshal = func(v interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		result := post(t, h, &SubscriptionRequest{URL: testURL})
+		require.Equal(t, http.StatusInternalServerError, result.code)
+	})
+}
+
+func TestReader(t *testing.T) {
+	h := NewReader(newMockStore())
+	require.Equal(t, WebhooksPath, h.Path())
+	require.Equal(t, http.MethodGet, h.Method())
+	require.NotNil(t, h.Handler())
+
+	t.Run("Success", func(t *testing.T) {
+		s := newMockStore(&webhook.Subscription{ID: "sub1", URL: testURL, Secret: "secret1"})
+
+		code, body := httptestutil.Get(t, NewReader(s).handle, WebhooksPath)
+		require.Equal(t, http.StatusOK, code)
+
+		var subs []*webhook.Subscription
+		require.NoError(t, json.Unmarshal(body, &subs))
+		require.Len(t, subs, 1)
+		require.Equal(t, "sub1", subs[0].ID)
+		require.Empty(t, subs[0].Secret)
+	})
+
+	t.Run("No subscriptions", func(t *testing.T) {
+		code, body := httptestutil.Get(t, NewReader(newMockStore()).handle, WebhooksPath)
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, "[]", string(body))
+	})
+
+	t.Run("Store error", func(t *testing.T) {
+		s := newMockStore()
+		s.err = errors.New("injected store error")
+
+		code, _ := httptestutil.Get(t, NewReader(s).handle, WebhooksPath)
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+}
+
+func TestRemover(t *testing.T) {
+	h := NewRemover(newMockStore())
+	require.Equal(t, "/webhooks/{id}", h.Path())
+	require.Equal(t, http.MethodDelete, h.Method())
+	require.NotNil(t, h.Handler())
+
+	t.Run("Success", func(t *testing.T) {
+		s := newMockStore(&webhook.Subscription{ID: "sub1", URL: testURL})
+
+		code, _ := httptestutil.Serve(t, NewRemover(s).handle, http.MethodDelete, "/webhooks/sub1", nil,
+			map[string]string{"id": "sub1"})
+		require.Equal(t, http.StatusNoContent, code)
+
+		_, err := s.GetSubscription("sub1")
+		require.True(t, errors.Is(err, webhook.ErrNotFound))
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		code, _ := httptestutil.Serve(t, NewRemover(newMockStore()).handle, http.MethodDelete, "/webhooks/sub1", nil,
+			map[string]string{"id": "sub1"})
+		require.Equal(t, http.StatusNotFound, code)
+	})
+
+	t.Run("Store error", func(t *testing.T) {
+		s := newMockStore()
+		s.err = errors.New("injected store error")
+
+		code, _ := httptestutil.Serve(t, NewRemover(s).handle, http.MethodDelete, "/webhooks/sub1", nil,
+			map[string]string{"id": "sub1"})
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+}
+
+func TestDeliveries(t *testing.T) {
+	h := NewDeliveries(newMockStore())
+	require.Equal(t, "/webhooks/{id}/deliveries", h.Path())
+	require.Equal(t, http.MethodGet, h.Method())
+	require.NotNil(t, h.Handler())
+
+	vars := map[string]string{"id": "sub1"}
+
+	t.Run("Success", func(t *testing.T) {
+		s := newMockStore(&webhook.Subscription{ID: "sub1", URL: testURL})
+
+		for i := 0; i < 3; i++ {
+			s.deliveries = append(s.deliveries, &webhook.Delivery{
+				ID:             fmt.Sprintf("d%d", i),
+				SubscriptionID: "sub1",
+				Status:         webhook.DeliveryStatusSucceeded,
+				Timestamp:      time.Now(),
+			})
+		}
+
+		code, body := httptestutil.Serve(t, NewDeliveries(s).handle, http.MethodGet,
+			"/webhooks/sub1/deliveries", nil, vars)
+		require.Equal(t, http.StatusOK, code)
+
+		deliveryLog := &DeliveryLog{}
+		require.NoError(t, json.Unmarshal(body, deliveryLog))
+		require.Equal(t, "sub1", deliveryLog.SubscriptionID)
+		require.Len(t, deliveryLog.Deliveries, 3)
+
+		code, body = httptestutil.Serve(t, NewDeliveries(s).handle, http.MethodGet,
+			"/webhooks/sub1/deliveries?limit=2", nil, vars)
+		require.Equal(t, http.StatusOK, code)
+
+		deliveryLog = &DeliveryLog{}
+		require.NoError(t, json.Unmarshal(body, deliveryLog))
+		require.Len(t, deliveryLog.Deliveries, 2)
+	})
+
+	t.Run("No deliveries", func(t *testing.T) {
+		s := newMockStore(&webhook.Subscription{ID: "sub1", URL: testURL})
+
+		code, body := httptestutil.Serve(t, NewDeliveries(s).handle, http.MethodGet,
+			"/webhooks/sub1/deliveries", nil, vars)
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, `{"subscriptionId":"sub1","deliveries":[]}`, string(body))
+	})
+
+	t.Run("Invalid limit", func(t *testing.T) {
+		s := newMockStore(&webhook.Subscription{ID: "sub1", URL: testURL})
+
+		code, body := httptestutil.Serve(t, NewDeliveries(s).handle, http.MethodGet,
+			"/webhooks/sub1/deliveries?limit=-1", nil, vars)
+		require.Equal(t, http.StatusBadRequest, code)
+		require.Contains(t, string(body), "invalid value for parameter 'limit'")
+	})
+
+	t.Run("Subscription not found", func(t *testing.T) {
+		code, _ := httptestutil.Serve(t, NewDeliveries(newMockStore()).handle, http.MethodGet,
+			"/webhooks/sub1/deliveries", nil, vars)
+		require.Equal(t, http.StatusNotFound, code)
+	})
+
+	t.Run("Store error", func(t *testing.T) {
+		s := newMockStore()
+		s.err = errors.New("injected store error")
+
+		code, _ := httptestutil.Serve(t, NewDeliveries(s).handle, http.MethodGet,
+			"/webhooks/sub1/deliveries", nil, vars)
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+
+	t.Run("Get deliveries error", func(t *testing.T) {
+		s := newMockStore(&webhook.Subscription{ID: "sub1", URL: testURL})
+		s.deliveriesErr = errors.New("injected store error")
+
+		code, _ := httptestutil.Serve(t, NewDeliveries(s).handle, http.MethodGet,
+			"/webhooks/sub1/deliveries", nil, vars)
+		require.Equal(t, http.StatusInternalServerError, code)
+	})
+}
+
+type response struct {
+	code int
+	body []byte
+}
+
+func post(t *testing.T, h *Registrar, subReq *SubscriptionRequest) *response {
+	t.Helper()
+
+	reqBytes, err := json.Marshal(subReq)
+	require.NoError(t, err)
+
+	code, body := httptestutil.Post(t, h.handle, WebhooksPath, reqBytes)
+
+	return &response{code: code, body: body}
+}
+
+type mockStore struct {
+	subs          map[string]*webhook.Subscription
+	deliveries    []*webhook.Delivery
+	err           error
+	deliveriesErr error
+}
+
+func newMockStore(subs ...*webhook.Subscription) *mockStore {
+	s := &mockStore{subs: make(map[string]*webhook.Subscription)}
+
+	for _, sub := range subs {
+		s.subs[sub.ID] = sub
+	}
+
+	return s
+}
+
+func (m *mockStore) AddSubscription(sub *webhook.Subscription) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	m.subs[sub.ID] = sub
+
+	return nil
+}
+
+func (m *mockStore) GetSubscription(id string) (*webhook.Subscription, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	sub, ok := m.subs[id]
+	if !ok {
+		return nil, webhook.ErrNotFound
+	}
+
+	subCopy := *sub
+
+	return &subCopy, nil
+}
+
+func (m *mockStore) GetSubscriptions() ([]*webhook.Subscription, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	var subs []*webhook.Subscription
+
+	for _, sub := range m.subs {
+		subCopy := *sub
+
+		subs = append(subs, &subCopy)
+	}
+
+	return subs, nil
+}
+
+func (m *mockStore) DeleteSubscription(id string) error {
+	if _, err := m.GetSubscription(id); err != nil {
+		return err
+	}
+
+	delete(m.subs, id)
+
+	return nil
+}
+
+func (m *mockStore) GetDeliveries(string) ([]*webhook.Delivery, error) {
+	return m.deliveries, m.deliveriesErr
+}
@@ -0,0 +1,252 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/store/expiry"
+)
+
+const (
+	subscriptionStoreName = "webhook-subscription"
+	deliveryStoreName     = "webhook-delivery"
+
+	// subscriptionTag is added to every subscription so that all subscriptions may be queried.
+	subscriptionTag   = "subscription"
+	subscriptionIDTag = "subscriptionID"
+	expiryTimeTag     = "expiryTime"
+
+	defaultDeliveryLogLifespan = 7 * 24 * time.Hour
+)
+
+// ErrNotFound is returned when the requested subscription does not exist.
+var ErrNotFound = errors.New("subscription not found")
+
+// StoreOpt sets a webhook store option.
+type StoreOpt func(s *Store)
+
+// WithDeliveryLogLifespan sets the amount of time that entries are kept in the delivery log.
+func WithDeliveryLogLifespan(lifespan time.Duration) StoreOpt {
+	return func(s *Store) {
+		s.deliveryLogLifespan = lifespan
+	}
+}
+
+// Store persists webhook subscriptions and the delivery log.
+type Store struct {
+	subscriptions       storage.Store
+	deliveries          storage.Store
+	deliveryLogLifespan time.Duration
+	marshal             func(v interface{}) ([]byte, error)
+	unmarshal           func(data []byte, v interface{}) error
+}
+
+// NewStore returns a new webhook store. Entries in the delivery log are removed by the given expiry service
+// after the delivery log lifespan.
+func NewStore(provider storage.Provider, expiryService *expiry.Service, opts ...StoreOpt) (*Store, error) {
+	subscriptions, err := provider.OpenStore(subscriptionStoreName)
+	if err != nil {
+		return nil, fmt.Errorf("open webhook subscription store: %w", err)
+	}
+
+	err = provider.SetStoreConfig(subscriptionStoreName,
+		storage.StoreConfiguration{TagNames: []string{subscriptionTag}})
+	if err != nil {
+		return nil, fmt.Errorf("set webhook subscription store configuration: %w", err)
+	}
+
+	deliveries, err := provider.OpenStore(deliveryStoreName)
+	if err != nil {
+		return nil, fmt.Errorf("open webhook delivery store: %w", err)
+	}
+
+	err = provider.SetStoreConfig(deliveryStoreName,
+		storage.StoreConfiguration{TagNames: []string{subscriptionIDTag, expiryTimeTag}})
+	if err != nil {
+		return nil, fmt.Errorf("set webhook delivery store configuration: %w", err)
+	}
+
+	expiryService.Register(deliveries, expiryTimeTag, deliveryStoreName)
+
+	s := &Store{
+		subscriptions:       subscriptions,
+		deliveries:          deliveries,
+		deliveryLogLifespan: defaultDeliveryLogLifespan,
+		marshal:             json.Marshal,
+		unmarshal:           json.Unmarshal,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s, nil
+}
+
+// AddSubscription stores the given subscription.
+func (s *Store) AddSubscription(sub *Subscription) error {
+	subBytes, err := s.marshal(sub)
+	if err != nil {
+		return fmt.Errorf("marshal subscription: %w", err)
+	}
+
+	err = s.subscriptions.Put(sub.ID, subBytes, storage.Tag{Name: subscriptionTag})
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("store subscription [%s]: %w", sub.ID, err))
+	}
+
+	return nil
+}
+
+// GetSubscription returns the subscription for the given ID or ErrNotFound if the subscription doesn't exist.
+func (s *Store) GetSubscription(id string) (*Subscription, error) {
+	subBytes, err := s.subscriptions.Get(id)
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return nil, ErrNotFound
+		}
+
+		return nil, orberrors.NewTransient(fmt.Errorf("get subscription [%s]: %w", id, err))
+	}
+
+	sub := &Subscription{}
+
+	err = s.unmarshal(subBytes, sub)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal subscription [%s]: %w", id, err)
+	}
+
+	return sub, nil
+}
+
+// GetSubscriptions returns all subscriptions.
+func (s *Store) GetSubscriptions() ([]*Subscription, error) {
+	values, err := s.query(s.subscriptions, subscriptionTag)
+	if err != nil {
+		return nil, fmt.Errorf("query subscriptions: %w", err)
+	}
+
+	subs := make([]*Subscription, len(values))
+
+	for i, value := range values {
+		sub := &Subscription{}
+
+		if e := s.unmarshal(value, sub); e != nil {
+			return nil, fmt.Errorf("unmarshal subscription: %w", e)
+		}
+
+		subs[i] = sub
+	}
+
+	sort.Slice(subs, func(i, j int) bool {
+		return subs[i].Created.Before(subs[j].Created)
+	})
+
+	return subs, nil
+}
+
+// DeleteSubscription deletes the subscription with the given ID.
+func (s *Store) DeleteSubscription(id string) error {
+	if _, err := s.GetSubscription(id); err != nil {
+		return err
+	}
+
+	err := s.subscriptions.Delete(id)
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("delete subscription [%s]: %w", id, err))
+	}
+
+	return nil
+}
+
+// AddDelivery adds the given delivery to the delivery log.
+func (s *Store) AddDelivery(d *Delivery) error {
+	deliveryBytes, err := s.marshal(d)
+	if err != nil {
+		return fmt.Errorf("marshal delivery: %w", err)
+	}
+
+	err = s.deliveries.Put(d.ID, deliveryBytes,
+		storage.Tag{Name: subscriptionIDTag, Value: d.SubscriptionID},
+		storage.Tag{Name: expiryTimeTag, Value: fmt.Sprintf("%d", time.Now().Add(s.deliveryLogLifespan).Unix())},
+	)
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("store delivery [%s]: %w", d.ID, err))
+	}
+
+	return nil
+}
+
+// GetDeliveries returns the delivery log of the given subscription, most recent first.
+func (s *Store) GetDeliveries(subscriptionID string) ([]*Delivery, error) {
+	values, err := s.query(s.deliveries, fmt.Sprintf("%s:%s", subscriptionIDTag, subscriptionID))
+	if err != nil {
+		return nil, fmt.Errorf("query deliveries for subscription [%s]: %w", subscriptionID, err)
+	}
+
+	deliveries := make([]*Delivery, len(values))
+
+	for i, value := range values {
+		d := &Delivery{}
+
+		if e := s.unmarshal(value, d); e != nil {
+			return nil, fmt.Errorf("unmarshal delivery: %w", e)
+		}
+
+		deliveries[i] = d
+	}
+
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].Timestamp.After(deliveries[j].Timestamp)
+	})
+
+	return deliveries, nil
+}
+
+func (s *Store) query(store storage.Store, query string) ([][]byte, error) {
+	iter, err := store.Query(query)
+	if err != nil {
+		return nil, orberrors.NewTransient(err)
+	}
+
+	defer func() {
+		if e := iter.Close(); e != nil {
+			logger.Warnf("Failed to close iterator: %s", e)
+		}
+	}()
+
+	var values [][]byte
+
+	ok, err := iter.Next()
+	if err != nil {
+		return nil, orberrors.NewTransient(err)
+	}
+
+	for ok {
+		value, e := iter.Value()
+		if e != nil {
+			return nil, orberrors.NewTransient(e)
+		}
+
+		values = append(values, value)
+
+		ok, err = iter.Next()
+		if err != nil {
+			return nil, orberrors.NewTransient(err)
+		}
+	}
+
+	return values, nil
+}
@@ -0,0 +1,175 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package webhook
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/store/expiry"
+	"github.com/trustbloc/orb/pkg/store/mocks"
+	"github.com/trustbloc/orb/pkg/taskmgr"
+)
+
+func TestNewStore(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		s, err := NewStore(mem.NewProvider(), newExpiryService(t), WithDeliveryLogLifespan(time.Hour))
+		require.NoError(t, err)
+		require.NotNil(t, s)
+		require.Equal(t, time.Hour, s.deliveryLogLifespan)
+	})
+
+	t.Run("Open store error", func(t *testing.T) {
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(nil, errors.New("injected open error"))
+
+		_, err := NewStore(provider, newExpiryService(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected open error")
+	})
+
+	t.Run("Set store config error", func(t *testing.T) {
+		provider := &mocks.Provider{}
+		provider.SetStoreConfigReturns(errors.New("injected config error"))
+
+		_, err := NewStore(provider, newExpiryService(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected config error")
+	})
+}
+
+func TestStore_Subscriptions(t *testing.T) {
+	s, err := NewStore(mem.NewProvider(), newExpiryService(t))
+	require.NoError(t, err)
+
+	subs, err := s.GetSubscriptions()
+	require.NoError(t, err)
+	require.Empty(t, subs)
+
+	sub1 := &Subscription{
+		ID:      "sub1",
+		URL:     "https://example.com/hook1",
+		Events:  []EventType{EventAnchorProcessed},
+		Secret:  "secret1",
+		Created: time.Now().Add(-time.Minute),
+	}
+
+	sub2 := &Subscription{
+		ID:      "sub2",
+		URL:     "https://example.com/hook2",
+		Created: time.Now(),
+	}
+
+	require.NoError(t, s.AddSubscription(sub2))
+	require.NoError(t, s.AddSubscription(sub1))
+
+	sub, err := s.GetSubscription("sub1")
+	require.NoError(t, err)
+	require.Equal(t, sub1.URL, sub.URL)
+	require.Equal(t, sub1.Secret, sub.Secret)
+	require.Equal(t, sub1.Events, sub.Events)
+
+	subs, err = s.GetSubscriptions()
+	require.NoError(t, err)
+	require.Len(t, subs, 2)
+	require.Equal(t, "sub1", subs[0].ID)
+	require.Equal(t, "sub2", subs[1].ID)
+
+	require.NoError(t, s.DeleteSubscription("sub1"))
+
+	_, err = s.GetSubscription("sub1")
+	require.True(t, errors.Is(err, ErrNotFound))
+
+	err = s.DeleteSubscription("sub1")
+	require.True(t, errors.Is(err, ErrNotFound))
+
+	subs, err = s.GetSubscriptions()
+	require.NoError(t, err)
+	require.Len(t, subs, 1)
+}
+
+func TestStore_Deliveries(t *testing.T) {
+	s, err := NewStore(mem.NewProvider(), newExpiryService(t))
+	require.NoError(t, err)
+
+	now := time.Now()
+
+	require.NoError(t, s.AddDelivery(&Delivery{ID: "d1", SubscriptionID: "sub1", Timestamp: now.Add(-time.Minute)}))
+	require.NoError(t, s.AddDelivery(&Delivery{ID: "d2", SubscriptionID: "sub1", Timestamp: now}))
+	require.NoError(t, s.AddDelivery(&Delivery{ID: "d3", SubscriptionID: "sub2", Timestamp: now}))
+
+	deliveries, err := s.GetDeliveries("sub1")
+	require.NoError(t, err)
+	require.Len(t, deliveries, 2)
+	require.Equal(t, "d2", deliveries[0].ID)
+	require.Equal(t, "d1", deliveries[1].ID)
+
+	deliveries, err = s.GetDeliveries("sub3")
+	require.NoError(t, err)
+	require.Empty(t, deliveries)
+}
+
+func TestStore_Errors(t *testing.T) {
+	errExpected := errors.New("injected store error")
+
+	store := &mocks.Store{}
+	store.GetReturns(nil, errExpected)
+	store.PutReturns(errExpected)
+	store.QueryReturns(nil, errExpected)
+
+	provider := &mocks.Provider{}
+	provider.OpenStoreReturns(store, nil)
+
+	s, err := NewStore(provider, newExpiryService(t))
+	require.NoError(t, err)
+
+	err = s.AddSubscription(&Subscription{ID: "sub1"})
+	require.True(t, errors.Is(err, errExpected))
+
+	_, err = s.GetSubscription("sub1")
+	require.True(t, errors.Is(err, errExpected))
+
+	_, err = s.GetSubscriptions()
+	require.True(t, errors.Is(err, errExpected))
+
+	err = s.AddDelivery(&Delivery{ID: "d1"})
+	require.True(t, errors.Is(err, errExpected))
+
+	_, err = s.GetDeliveries("sub1")
+	require.True(t, errors.Is(err, errExpected))
+
+	t.Run("Iterator error", func(t *testing.T) {
+		iter := &mocks.Iterator{}
+		iter.NextReturns(false, errExpected)
+
+		store.QueryReturns(iter, nil)
+
+		_, err = s.GetSubscriptions()
+		require.True(t, errors.Is(err, errExpected))
+	})
+
+	t.Run("Unmarshal error", func(t *testing.T) {
+		store.GetReturns([]byte("{"), nil)
+
+		_, err = s.GetSubscription("sub1")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal subscription")
+	})
+}
+
+func newExpiryService(t *testing.T) *expiry.Service {
+	t.Helper()
+
+	coordinationStore, err := mem.NewProvider().OpenStore("coordination")
+	require.NoError(t, err)
+
+	return expiry.NewService(taskmgr.New(coordinationStore, time.Second), time.Second)
+}